@@ -1,9 +1,28 @@
 package config
 
+import (
+	"os"
+	"path/filepath"
+	"time"
+)
+
 // Config define the struct of global configuration
 type Config struct {
+	// Path is the data directory of the storage
+	Path string
+	// Lease is the longest time the DDL waits for the transactions to use a new schema.
+	Lease time.Duration
+	// GCLifeTime is how long the data of a dropped or truncated table is kept before GC cleans it up.
+	GCLifeTime time.Duration
+	// GCRunInterval is the interval GC runs.
+	GCRunInterval time.Duration
 }
 
 func InitConfig() *Config {
-	return &Config{}
+	return &Config{
+		Path:          filepath.Join(os.TempDir(), "grant-db"),
+		Lease:         time.Second,
+		GCLifeTime:    10 * time.Minute,
+		GCRunInterval: time.Minute,
+	}
 }
@@ -5,6 +5,7 @@ import (
 	"grant-db/config"
 	"grant-db/kv"
 	"grant-db/server"
+	"grant-db/session"
 	"log"
 )
 
@@ -30,6 +31,7 @@ func main() {
 	//TODO 加载配置，初始目录结构
 	cfg = config.InitConfig()
 	//TODO 设置全局参数
+	setGlobalVars()
 
 	//TODO 初始化日志模块
 
@@ -50,8 +52,20 @@ func main() {
 	runServer()
 }
 
+func setGlobalVars() {
+	session.SetSchemaLease(cfg.Lease)
+	session.SetGCLifeTime(cfg.GCLifeTime, cfg.GCRunInterval)
+}
+
 func createStore() {
-	storage = kv.NewStorage()
+	var err error
+	storage, err = kv.NewStorage(cfg.Path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if _, err = session.BootstrapSession(storage); err != nil {
+		log.Fatal(err)
+	}
 }
 
 func createServer() {
@@ -0,0 +1,1002 @@
+package ddl
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/charset"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/opcode"
+	"grant-db/infoschema"
+	"grant-db/kv"
+	"grant-db/meta"
+	"grant-db/table"
+	"grant-db/table/tables"
+	"grant-db/tablecodec"
+	"grant-db/types"
+)
+
+const (
+	maxCharLength      = 255
+	maxVarcharLength   = 65535
+	maxDecimalPrec     = 65
+	maxDecimalScale    = 30
+	maxFloatPrec       = 255
+	maxIntDisplayWidth = 255
+	maxBitLength       = 64
+)
+
+// buildColumnAndConstraint builds the ColumnInfo of colDef, the key options of the column
+// are returned as table constraints.
+func buildColumnAndConstraint(offset int, colDef *ast.ColumnDef, tbInfo *model.TableInfo) (*model.ColumnInfo, []*ast.Constraint, error) {
+	name := colDef.Name.Name
+	if err := checkIdentName(name.O, ErrWrongColumnName); err != nil {
+		return nil, nil, err
+	}
+	// The charset of the column is derived from its COLLATE option if it isn't specified.
+	explicitCharset := colDef.Tp.Charset != ""
+	if err := setCharsetCollationFlenDecimal(colDef.Tp, tbInfo.Charset, tbInfo.Collate); err != nil {
+		return nil, nil, err
+	}
+	if err := checkColumnType(name.O, colDef.Tp); err != nil {
+		return nil, nil, err
+	}
+	col := &model.ColumnInfo{
+		Name:      name,
+		Offset:    offset,
+		FieldType: *colDef.Tp,
+		Version:   model.CurrLatestColumnInfoVersion,
+	}
+
+	var (
+		constraints []*ast.Constraint
+		defaultExpr ast.ExprNode
+		hasDefault  bool
+	)
+	keys := []*ast.IndexPartSpecification{{Column: colDef.Name}}
+	for _, v := range colDef.Options {
+		switch v.Tp {
+		case ast.ColumnOptionNotNull:
+			col.Flag |= mysql.NotNullFlag
+		case ast.ColumnOptionNull:
+			col.Flag &= ^mysql.NotNullFlag
+		case ast.ColumnOptionAutoIncrement:
+			if !mysql.IsIntegerType(col.Tp) && col.Tp != mysql.TypeFloat && col.Tp != mysql.TypeDouble || col.Tp == mysql.TypeBit {
+				return nil, nil, ErrWrongFieldSpec.GenWithStackByArgs(name)
+			}
+			col.Flag |= mysql.AutoIncrementFlag | mysql.NotNullFlag
+		case ast.ColumnOptionPrimaryKey:
+			constraints = append(constraints, &ast.Constraint{Tp: ast.ConstraintPrimaryKey, Keys: keys})
+			col.Flag |= mysql.NotNullFlag
+		case ast.ColumnOptionUniqKey:
+			constraints = append(constraints, &ast.Constraint{Tp: ast.ConstraintUniqKey, Keys: keys})
+		case ast.ColumnOptionDefaultValue:
+			defaultExpr, hasDefault = v.Expr, true
+		case ast.ColumnOptionOnUpdate:
+			if col.Tp != mysql.TypeTimestamp && col.Tp != mysql.TypeDatetime {
+				return nil, nil, ErrInvalidOnUpdate.GenWithStackByArgs(name)
+			}
+			if !isCurrentTimestampExpr(v.Expr) {
+				return nil, nil, ErrInvalidOnUpdate.GenWithStackByArgs(name)
+			}
+			col.Flag |= mysql.OnUpdateNowFlag
+		case ast.ColumnOptionComment:
+			comment, _, err := evalConstExpr(v.Expr)
+			if err != nil {
+				return nil, nil, err
+			}
+			col.Comment = comment
+		case ast.ColumnOptionCollate:
+			if !types.HasCharset(&col.FieldType) {
+				continue
+			}
+			chs, coll, err := checkCharsetAndCollation("", v.StrValue)
+			if err != nil {
+				return nil, nil, err
+			}
+			if !explicitCharset {
+				col.Charset = chs
+			} else if chs != col.Charset {
+				return nil, nil, ErrCollationCharsetMismatch.GenWithStackByArgs(coll, col.Charset)
+			}
+			col.Collate = coll
+		case ast.ColumnOptionGenerated:
+			return nil, nil, ErrNotSupportedYet.GenWithStackByArgs("generated column")
+		case ast.ColumnOptionFulltext:
+			return nil, nil, ErrNotSupportedYet.GenWithStackByArgs("FULLTEXT index")
+		case ast.ColumnOptionAutoRandom:
+			// The shard bits are set by setAutoRandomBits when the table is created.
+		}
+	}
+
+	if hasDefault {
+		if err := setDefaultValue(col, defaultExpr); err != nil {
+			return nil, nil, err
+		}
+	} else if mysql.HasNotNullFlag(col.Flag) {
+		col.Flag |= mysql.NoDefaultValueFlag
+	}
+	col.OriginDefaultValue = col.DefaultValue
+	return col, constraints, nil
+}
+
+// setCharsetCollationFlenDecimal sets the default charset, collation, length and decimal of tp.
+func setCharsetCollationFlenDecimal(tp *types.FieldType, tblCharset, tblCollate string) error {
+	if tp.Flen == types.UnspecifiedLength || tp.Decimal == types.UnspecifiedLength {
+		defaultFlen, defaultDecimal := mysql.GetDefaultFieldLengthAndDecimal(tp.Tp)
+		if tp.Flen == types.UnspecifiedLength {
+			tp.Flen = defaultFlen
+		}
+		if tp.Decimal == types.UnspecifiedLength {
+			tp.Decimal = defaultDecimal
+		}
+	}
+	if !types.HasCharset(tp) || tp.Charset == charset.CharsetBin {
+		tp.Charset, tp.Collate = charset.CharsetBin, charset.CollationBin
+		if !types.HasCharset(tp) || types.IsTypeBlob(tp.Tp) || types.IsTypeChar(tp.Tp) || tp.Tp == mysql.TypeVarString {
+			tp.Flag |= mysql.BinaryFlag
+		}
+		return nil
+	}
+	var err error
+	switch {
+	case tp.Charset != "":
+		tp.Charset, tp.Collate, err = checkCharsetAndCollation(tp.Charset, tp.Collate)
+	case tp.Collate != "":
+		tp.Charset, tp.Collate, err = checkCharsetAndCollation("", tp.Collate)
+	default:
+		tp.Charset, tp.Collate = tblCharset, tblCollate
+	}
+	return err
+}
+
+// checkColumnType validates the length, precision and elements of the column type.
+func checkColumnType(colName string, tp *types.FieldType) error {
+	switch tp.Tp {
+	case mysql.TypeString:
+		if tp.Flen > maxCharLength {
+			return ErrTooBigFieldLength.GenWithStackByArgs(colName, maxCharLength)
+		}
+	case mysql.TypeVarchar, mysql.TypeVarString:
+		if tp.Flen > maxVarcharLength {
+			return ErrTooBigFieldLength.GenWithStackByArgs(colName, maxVarcharLength)
+		}
+	case mysql.TypeNewDecimal:
+		if tp.Decimal > maxDecimalScale {
+			return ErrTooBigScale.GenWithStackByArgs(tp.Decimal, colName, maxDecimalScale)
+		}
+		if tp.Flen > maxDecimalPrec {
+			return ErrTooBigPrecision.GenWithStackByArgs(tp.Flen, colName, maxDecimalPrec)
+		}
+		if tp.Decimal > tp.Flen {
+			return ErrMBiggerThanD.GenWithStackByArgs(colName)
+		}
+	case mysql.TypeFloat, mysql.TypeDouble:
+		if tp.Decimal == types.UnspecifiedLength {
+			break
+		}
+		if tp.Decimal > maxDecimalScale {
+			return ErrTooBigScale.GenWithStackByArgs(tp.Decimal, colName, maxDecimalScale)
+		}
+		if tp.Flen > maxFloatPrec {
+			return ErrTooBigPrecision.GenWithStackByArgs(tp.Flen, colName, maxFloatPrec)
+		}
+		if tp.Decimal > tp.Flen {
+			return ErrMBiggerThanD.GenWithStackByArgs(colName)
+		}
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
+		if tp.Flen > maxIntDisplayWidth {
+			return ErrTooBigDisplayWidth.GenWithStackByArgs(colName, maxIntDisplayWidth)
+		}
+	case mysql.TypeBit:
+		if tp.Flen > maxBitLength {
+			return ErrTooBigDisplayWidth.GenWithStackByArgs(colName, maxBitLength)
+		}
+	case mysql.TypeDatetime, mysql.TypeTimestamp, mysql.TypeDuration:
+		if tp.Decimal > int(types.MaxFsp) {
+			return ErrTooBigPrecision.GenWithStackByArgs(tp.Decimal, colName, types.MaxFsp)
+		}
+	case mysql.TypeEnum, mysql.TypeSet:
+		seen := make(map[string]struct{}, len(tp.Elems))
+		for _, elem := range tp.Elems {
+			key := strings.ToLower(strings.TrimRight(elem, " "))
+			if _, ok := seen[key]; ok {
+				return ErrDuplicatedValueInType.GenWithStackByArgs(colName, elem, strings.ToUpper(types.TypeStr(tp.Tp)))
+			}
+			seen[key] = struct{}{}
+		}
+	}
+	return nil
+}
+
+// isNullExpr checks whether the expression is the NULL literal.
+func isNullExpr(expr ast.ExprNode) bool {
+	v, ok := expr.(ast.ValueExpr)
+	return ok && v.GetValue() == nil
+}
+
+func isCurrentTimestampExpr(expr ast.ExprNode) bool {
+	fn, ok := expr.(*ast.FuncCallExpr)
+	if !ok {
+		return false
+	}
+	switch fn.FnName.L {
+	case ast.CurrentTimestamp, ast.Now, ast.LocalTime, ast.LocalTimestamp:
+		return true
+	}
+	return false
+}
+
+// evalConstExpr evaluates a literal or a signed numeric literal, isNull is true for the NULL literal.
+func evalConstExpr(expr ast.ExprNode) (s string, isNull bool, err error) {
+	switch x := expr.(type) {
+	case ast.ValueExpr:
+		switch v := x.GetValue().(type) {
+		case nil:
+			return "", true, nil
+		case int64:
+			return strconv.FormatInt(v, 10), false, nil
+		case uint64:
+			return strconv.FormatUint(v, 10), false, nil
+		case float64:
+			return strconv.FormatFloat(v, 'g', -1, 64), false, nil
+		case string:
+			return v, false, nil
+		default:
+			return fmt.Sprintf("%v", v), false, nil
+		}
+	case *ast.UnaryOperationExpr:
+		if x.Op != opcode.Minus && x.Op != opcode.Plus {
+			break
+		}
+		s, isNull, err = evalConstExpr(x.V)
+		if err != nil || isNull {
+			return s, isNull, err
+		}
+		if _, err := strconv.ParseFloat(s, 64); err != nil {
+			break
+		}
+		if x.Op == opcode.Minus {
+			if strings.HasPrefix(s, "-") {
+				return s[1:], false, nil
+			}
+			return "-" + s, false, nil
+		}
+		return s, false, nil
+	}
+	return "", false, ErrNotSupportedYet.GenWithStackByArgs("non-constant expression")
+}
+
+// setDefaultValue validates the DEFAULT expression against the column type and stores it in the column.
+func setDefaultValue(col *model.ColumnInfo, expr ast.ExprNode) error {
+	name := col.Name.O
+	if isCurrentTimestampExpr(expr) {
+		if col.Tp != mysql.TypeTimestamp && col.Tp != mysql.TypeDatetime {
+			return ErrInvalidDefaultValue.GenWithStackByArgs(name)
+		}
+		return col.SetDefaultValue(strings.ToUpper(ast.CurrentTimestamp))
+	}
+	value, isNull, err := evalConstExpr(expr)
+	if err != nil {
+		return ErrInvalidDefaultValue.GenWithStackByArgs(name)
+	}
+	if isNull {
+		if mysql.HasNotNullFlag(col.Flag) {
+			return ErrInvalidDefaultValue.GenWithStackByArgs(name)
+		}
+		return col.SetDefaultValue(nil)
+	}
+	if types.IsTypeBlob(col.Tp) || col.Tp == mysql.TypeJSON {
+		return ErrBlobCantHaveDefault.GenWithStackByArgs(name)
+	}
+	if mysql.HasAutoIncrementFlag(col.Flag) {
+		return ErrInvalidDefaultValue.GenWithStackByArgs(name)
+	}
+	value, err = convertDefaultValue(&col.FieldType, value)
+	if err != nil {
+		return ErrInvalidDefaultValue.GenWithStackByArgs(name)
+	}
+	return col.SetDefaultValue(value)
+}
+
+// convertDefaultValue converts the default value to the column type and returns its normalized string.
+func convertDefaultValue(tp *types.FieldType, value string) (string, error) {
+	switch tp.Tp {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong, mysql.TypeYear:
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return "", err
+		}
+		f = math.Round(f)
+		lower, upper := integerRange(tp)
+		if f < lower || f > upper {
+			return "", types.ErrOverflow
+		}
+		if tp.Tp == mysql.TypeYear && f != 0 && (f < 1901 || f > 2155) {
+			return "", types.ErrOverflow
+		}
+		return strconv.FormatFloat(f, 'f', 0, 64), nil
+	case mysql.TypeFloat, mysql.TypeDouble:
+		f, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return "", err
+		}
+		return strconv.FormatFloat(f, 'g', -1, 64), nil
+	case mysql.TypeNewDecimal:
+		dec := new(types.MyDecimal)
+		if err := dec.FromString([]byte(value)); err != nil && err != types.ErrTruncated {
+			return "", err
+		}
+		dec.Rescale(tp.Decimal)
+		if _, digits, exp := dec.Scientific(); digits != "" && exp > tp.Flen-tp.Decimal {
+			return "", types.ErrOverflow
+		}
+		return dec.String(), nil
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		t, err := types.ParseTime(value, tp.Tp, int8(tp.Decimal))
+		if err != nil {
+			return "", err
+		}
+		return t.String(), nil
+	case mysql.TypeDuration:
+		dur, err := types.ParseDuration(value, int8(tp.Decimal))
+		if err != nil {
+			return "", err
+		}
+		return dur.String(), nil
+	case mysql.TypeBit:
+		v, err := parseBitValue(value)
+		if err != nil {
+			return "", err
+		}
+		if tp.Flen < 64 && v >= 1<<uint64(tp.Flen) {
+			return "", types.ErrOverflow
+		}
+		return strconv.FormatUint(v, 10), nil
+	case mysql.TypeString, mysql.TypeVarchar, mysql.TypeVarString:
+		length := utf8.RuneCountInString(value)
+		if tp.Charset == charset.CharsetBin {
+			length = len(value)
+		}
+		if length > tp.Flen {
+			return "", types.ErrOverflow
+		}
+		return value, nil
+	case mysql.TypeEnum:
+		for _, elem := range tp.Elems {
+			if strings.EqualFold(elem, value) {
+				return elem, nil
+			}
+		}
+		return "", types.ErrOverflow
+	case mysql.TypeSet:
+		if value == "" {
+			return value, nil
+		}
+		parts := strings.Split(value, ",")
+		for i, part := range parts {
+			found := false
+			for _, elem := range tp.Elems {
+				if strings.EqualFold(elem, part) {
+					parts[i], found = elem, true
+					break
+				}
+			}
+			if !found {
+				return "", types.ErrOverflow
+			}
+		}
+		return strings.Join(parts, ","), nil
+	}
+	return value, nil
+}
+
+// parseBitValue parses the value of a BIT column, which is a number or a hex or bit literal in the form of 0x0a.
+func parseBitValue(value string) (uint64, error) {
+	if strings.HasPrefix(value, "0x") {
+		b, err := hex.DecodeString(value[2:])
+		if err != nil {
+			return 0, err
+		}
+		if len(b) > 8 {
+			return 0, types.ErrOverflow
+		}
+		var v uint64
+		for _, c := range b {
+			v = v<<8 | uint64(c)
+		}
+		return v, nil
+	}
+	return strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+}
+
+// integerRange returns the range of values the integer type can hold.
+func integerRange(tp *types.FieldType) (float64, float64) {
+	var bits uint
+	switch tp.Tp {
+	case mysql.TypeTiny:
+		bits = 8
+	case mysql.TypeShort:
+		bits = 16
+	case mysql.TypeInt24:
+		bits = 24
+	case mysql.TypeLong:
+		bits = 32
+	case mysql.TypeYear:
+		return 0, 2155
+	default:
+		bits = 64
+	}
+	if mysql.HasUnsignedFlag(tp.Flag) {
+		return 0, math.Pow(2, float64(bits)) - 1
+	}
+	return -math.Pow(2, float64(bits-1)), math.Pow(2, float64(bits-1)) - 1
+}
+
+// moveColumnInfo moves the column at offset from to offset to, the offsets of the columns and
+// the index columns are updated.
+func moveColumnInfo(tblInfo *model.TableInfo, from, to int) {
+	cols := tblInfo.Columns
+	col := cols[from]
+	if from < to {
+		copy(cols[from:to], cols[from+1:to+1])
+	} else {
+		copy(cols[to+1:from+1], cols[to:from])
+	}
+	cols[to] = col
+	for i, c := range cols {
+		c.Offset = i
+	}
+	for _, idx := range tblInfo.Indices {
+		for _, idxCol := range idx.Columns {
+			idxCol.Offset = model.FindColumnInfo(cols, idxCol.Name.L).Offset
+		}
+	}
+}
+
+// columnPositionOffset returns the offset the column at offset from moves to, the column
+// keeps its offset if no position is specified.
+func columnPositionOffset(tblInfo *model.TableInfo, from int, pos *ast.ColumnPosition) (int, error) {
+	if pos == nil || pos.Tp == ast.ColumnPositionNone {
+		return from, nil
+	}
+	if pos.Tp == ast.ColumnPositionFirst {
+		return 0, nil
+	}
+	after := model.FindColumnInfo(tblInfo.Columns, pos.RelativeColumn.Name.L)
+	if after == nil || after.State != model.StatePublic {
+		return 0, infoschema.ErrColumnNotExists.GenWithStackByArgs(pos.RelativeColumn, tblInfo.Name)
+	}
+	if after.Offset < from {
+		return after.Offset + 1, nil
+	}
+	return after.Offset, nil
+}
+
+// renameIndexColumns renames the column in the indices.
+func renameIndexColumns(tblInfo *model.TableInfo, from, to model.CIStr) {
+	for _, idx := range tblInfo.Indices {
+		for _, idxCol := range idx.Columns {
+			if idxCol.Name.L == from.L {
+				idxCol.Name = to
+			}
+		}
+	}
+}
+
+func isColumnWithIndex(colName string, indices []*model.IndexInfo) bool {
+	for _, idx := range indices {
+		for _, idxCol := range idx.Columns {
+			if idxCol.Name.L == colName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func onAddColumn(t *meta.Meta, job *model.Job) error {
+	// Handle the rolling back job.
+	if job.IsRollingback() {
+		return onDropColumn(t, job)
+	}
+
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return err
+	}
+	col := &model.ColumnInfo{}
+	pos := &ast.ColumnPosition{}
+	if err = job.DecodeArgs(col, pos); err != nil {
+		job.State = model.JobStateCancelled
+		return err
+	}
+
+	columnInfo := model.FindColumnInfo(tblInfo.Columns, col.Name.L)
+	if columnInfo != nil && columnInfo.State == model.StatePublic {
+		job.State = model.JobStateCancelled
+		return infoschema.ErrColumnExists.GenWithStackByArgs(col.Name)
+	}
+	if columnInfo == nil {
+		if _, err = columnPositionOffset(tblInfo, len(tblInfo.Columns), pos); err != nil {
+			job.State = model.JobStateCancelled
+			return err
+		}
+		// The column is appended and moved to its position when it becomes public.
+		tblInfo.MaxColumnID++
+		col.ID = tblInfo.MaxColumnID
+		col.Offset = len(tblInfo.Columns)
+		col.State = model.StateNone
+		tblInfo.Columns = append(tblInfo.Columns, col)
+		columnInfo = col
+	}
+
+	originalState := columnInfo.State
+	switch columnInfo.State {
+	case model.StateNone:
+		// none -> delete only
+		columnInfo.State = model.StateDeleteOnly
+		if _, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != columnInfo.State); err != nil {
+			return err
+		}
+		job.SchemaState = model.StateDeleteOnly
+	case model.StateDeleteOnly:
+		// delete only -> write only
+		columnInfo.State = model.StateWriteOnly
+		if _, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != columnInfo.State); err != nil {
+			return err
+		}
+		job.SchemaState = model.StateWriteOnly
+	case model.StateWriteOnly:
+		// write only -> reorganization
+		columnInfo.State = model.StateWriteReorganization
+		if _, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != columnInfo.State); err != nil {
+			return err
+		}
+		job.SchemaState = model.StateWriteReorganization
+	case model.StateWriteReorganization:
+		// reorganization -> public
+		// The existing rows aren't backfilled, they get the origin default value on read.
+		offset, err := columnPositionOffset(tblInfo, columnInfo.Offset, pos)
+		if err != nil {
+			return err
+		}
+		moveColumnInfo(tblInfo, columnInfo.Offset, offset)
+		columnInfo.State = model.StatePublic
+		ver, err := updateVersionAndTableInfo(t, job, tblInfo, originalState != columnInfo.State)
+		if err != nil {
+			return err
+		}
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	default:
+		err = errInvalidDDLState.GenWithStackByArgs("column", columnInfo.State)
+	}
+	return err
+}
+
+func checkDropColumn(t *meta.Meta, job *model.Job) (*model.TableInfo, *model.ColumnInfo, error) {
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return nil, nil, err
+	}
+	var colName model.CIStr
+	if err = job.DecodeArgs(&colName); err != nil {
+		job.State = model.JobStateCancelled
+		return nil, nil, err
+	}
+	colInfo := model.FindColumnInfo(tblInfo.Columns, colName.L)
+	if colInfo == nil || colInfo.Hidden {
+		job.State = model.JobStateCancelled
+		return nil, nil, ErrCantDropFieldOrKey.GenWithStackByArgs(colName)
+	}
+	if isColumnWithIndex(colName.L, tblInfo.Indices) {
+		job.State = model.JobStateCancelled
+		return nil, nil, ErrNotSupportedYet.GenWithStackByArgs("dropping a column with index")
+	}
+	return tblInfo, colInfo, nil
+}
+
+// onDropColumn drops the column, it also rolls back an ADD COLUMN job from delete only.
+func onDropColumn(t *meta.Meta, job *model.Job) error {
+	tblInfo, colInfo, err := checkDropColumn(t, job)
+	if err != nil {
+		return err
+	}
+
+	originalState := colInfo.State
+	switch colInfo.State {
+	case model.StatePublic:
+		// public -> write only
+		// The column is moved to the end so that the offsets of the public columns are continuous.
+		moveColumnInfo(tblInfo, colInfo.Offset, len(tblInfo.Columns)-1)
+		colInfo.State = model.StateWriteOnly
+		if _, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != colInfo.State); err != nil {
+			return err
+		}
+		job.SchemaState = model.StateWriteOnly
+	case model.StateWriteOnly:
+		// write only -> delete only
+		colInfo.State = model.StateDeleteOnly
+		if _, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != colInfo.State); err != nil {
+			return err
+		}
+		job.SchemaState = model.StateDeleteOnly
+	case model.StateDeleteOnly:
+		// delete only -> reorganization
+		colInfo.State = model.StateDeleteReorganization
+		if _, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != colInfo.State); err != nil {
+			return err
+		}
+		job.SchemaState = model.StateDeleteReorganization
+	case model.StateDeleteReorganization:
+		// reorganization -> absent
+		// The column data is left in the rows, it's ignored on read and removed when the row is updated.
+		tblInfo.Columns = tblInfo.Columns[:len(tblInfo.Columns)-1]
+		ver, err := updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return err
+		}
+		if job.IsRollingback() {
+			job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
+		} else {
+			job.FinishTableJob(model.JobStateDone, model.StateNone, ver, tblInfo)
+		}
+	default:
+		err = errInvalidDDLState.GenWithStackByArgs("column", colInfo.State)
+	}
+	return err
+}
+
+func (d *ddl) onModifyColumn(t *meta.Meta, job *model.Job) error {
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return err
+	}
+	newCol := &model.ColumnInfo{}
+	var oldColName model.CIStr
+	pos := &ast.ColumnPosition{}
+	if err = job.DecodeArgs(newCol, &oldColName, pos); err != nil {
+		job.State = model.JobStateCancelled
+		return err
+	}
+
+	oldCol := model.FindColumnInfo(tblInfo.Columns, oldColName.L)
+	if oldCol == nil || oldCol.State != model.StatePublic {
+		job.State = model.JobStateCancelled
+		return infoschema.ErrColumnNotExists.GenWithStackByArgs(oldColName, tblInfo.Name)
+	}
+	if newCol.Name.L != oldColName.L && model.FindColumnInfo(tblInfo.Columns, newCol.Name.L) != nil {
+		job.State = model.JobStateCancelled
+		return infoschema.ErrColumnExists.GenWithStackByArgs(newCol.Name)
+	}
+	if _, err = columnPositionOffset(tblInfo, oldCol.Offset, pos); err != nil {
+		job.State = model.JobStateCancelled
+		return err
+	}
+	needReorg, err := checkModifyTypes(&oldCol.FieldType, &newCol.FieldType, isColumnWithIndex(oldColName.L, tblInfo.Indices))
+	if err != nil {
+		job.State = model.JobStateCancelled
+		return err
+	}
+	if needReorg {
+		return d.doModifyColumnTypeWithData(t, job, tblInfo, oldCol, newCol, pos)
+	}
+
+	// The stored values fit the new type, only the column info is changed.
+	newCol.ID = oldCol.ID
+	newCol.Offset = oldCol.Offset
+	newCol.State = model.StatePublic
+	tblInfo.Columns[oldCol.Offset] = newCol
+	renameIndexColumns(tblInfo, oldCol.Name, newCol.Name)
+	offset, err := columnPositionOffset(tblInfo, newCol.Offset, pos)
+	if err != nil {
+		return err
+	}
+	moveColumnInfo(tblInfo, newCol.Offset, offset)
+	ver, err := updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err != nil {
+		return err
+	}
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	return nil
+}
+
+// doModifyColumnTypeWithData adds a hidden changing column of the new type, fills it with the
+// converted values of the old column and replaces the old column with it at last.
+func (d *ddl) doModifyColumnTypeWithData(t *meta.Meta, job *model.Job, tblInfo *model.TableInfo,
+	oldCol, newCol *model.ColumnInfo, pos *ast.ColumnPosition) error {
+	changingColName := model.NewCIStr(table.ChangingColumnPrefix + oldCol.Name.O)
+	changingCol := model.FindColumnInfo(tblInfo.Columns, changingColName.L)
+	if changingCol == nil {
+		changingCol = newCol.Clone()
+		changingCol.Name = changingColName
+		tblInfo.MaxColumnID++
+		changingCol.ID = tblInfo.MaxColumnID
+		changingCol.Offset = len(tblInfo.Columns)
+		changingCol.State = model.StateNone
+		changingCol.Hidden = true
+		tblInfo.Columns = append(tblInfo.Columns, changingCol)
+	}
+
+	var err error
+	originalState := changingCol.State
+	switch changingCol.State {
+	case model.StateNone:
+		// none -> delete only
+		changingCol.State = model.StateDeleteOnly
+		if _, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != changingCol.State); err != nil {
+			return err
+		}
+		job.SchemaState = model.StateDeleteOnly
+	case model.StateDeleteOnly:
+		// delete only -> write only
+		changingCol.State = model.StateWriteOnly
+		if _, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != changingCol.State); err != nil {
+			return err
+		}
+		job.SchemaState = model.StateWriteOnly
+	case model.StateWriteOnly:
+		// write only -> reorganization
+		changingCol.State = model.StateWriteReorganization
+		if _, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != changingCol.State); err != nil {
+			return err
+		}
+		job.SchemaState = model.StateWriteReorganization
+	case model.StateWriteReorganization:
+		// reorganization -> public
+		tbl, err := tables.TableFromMeta(nil, tblInfo)
+		if err != nil {
+			return err
+		}
+		err = d.updateColumnData(t, job, tbl, oldCol, changingCol, newCol.Name)
+		if err != nil {
+			if isColumnDataConversionErr(err) || errCancelledDDLJob.Equal(err) {
+				return rollbackModifyColumnJob(t, job, tblInfo, err)
+			}
+			return err
+		}
+
+		// The changing column takes the place and the name of the old column, the data of
+		// the old column is left in the rows and ignored on read.
+		tblInfo.Columns = tblInfo.Columns[:len(tblInfo.Columns)-1]
+		changingCol.Name = newCol.Name
+		changingCol.Offset = oldCol.Offset
+		changingCol.Hidden = false
+		changingCol.State = model.StatePublic
+		tblInfo.Columns[oldCol.Offset] = changingCol
+		renameIndexColumns(tblInfo, oldCol.Name, newCol.Name)
+		offset, err := columnPositionOffset(tblInfo, changingCol.Offset, pos)
+		if err != nil {
+			return err
+		}
+		moveColumnInfo(tblInfo, changingCol.Offset, offset)
+		ver, err := updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return err
+		}
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	default:
+		err = errInvalidDDLState.GenWithStackByArgs("column", changingCol.State)
+	}
+	return err
+}
+
+// rollbackModifyColumnJob removes the changing column and finishes the job as rolled back.
+func rollbackModifyColumnJob(t *meta.Meta, job *model.Job, tblInfo *model.TableInfo, err error) error {
+	tblInfo.Columns = tblInfo.Columns[:len(tblInfo.Columns)-1]
+	ver, err1 := updateVersionAndTableInfo(t, job, tblInfo, true)
+	if err1 != nil {
+		return err1
+	}
+	job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
+	return err
+}
+
+// updateColumnData rewrites the rows with the converted values of the old column in the changing column.
+func (d *ddl) updateColumnData(t *meta.Meta, job *model.Job, tbl table.Table,
+	oldCol, changingCol *model.ColumnInfo, colName model.CIStr) error {
+	return d.runReorgJob(t, job, tbl, func(startKey kv.Key) (nextKey kv.Key, count int64, err error) {
+		rowBase := job.GetRowCount()
+		err = kv.RunInNewTxn(d.store, true, func(txn kv.Transaction) error {
+			nextKey, count = nil, 0
+			if err := checkReorgJob(txn, job.ID); err != nil {
+				return err
+			}
+			return tbl.IterRecords(txn, startKey, tbl.DeletableCols(),
+				func(h int64, rec []types.Datum, cols []*table.Column) (bool, error) {
+					if count == reorgBatchSize {
+						nextKey = tbl.RecordKey(h)
+						return false, nil
+					}
+					val, err := convertColumnValue(rec[oldCol.Offset], changingCol, colName, rowBase+count+1)
+					if err != nil {
+						return false, err
+					}
+					rec[changingCol.Offset] = val
+					if err = writeRecord(txn, tbl, h, rec, cols); err != nil {
+						return false, err
+					}
+					count++
+					return true, nil
+				})
+		})
+		return nextKey, count, err
+	})
+}
+
+// writeRecord encodes the row of the columns and writes it, the handle column isn't stored in the value.
+func writeRecord(txn kv.Transaction, tbl table.Table, h int64, rec []types.Datum, cols []*table.Column) error {
+	colIDs := make([]int64, 0, len(cols))
+	row := make([]types.Datum, 0, len(cols))
+	for i, col := range cols {
+		if col.IsPKHandleColumn(tbl.Meta()) {
+			continue
+		}
+		colIDs = append(colIDs, col.ID)
+		row = append(row, rec[i])
+	}
+	value, err := tablecodec.EncodeRow(row, colIDs, nil)
+	if err != nil {
+		return err
+	}
+	return txn.Set(tbl.RecordKey(h), value)
+}
+
+// convertColumnValue converts the value of the row rowNum to the column type strictly.
+func convertColumnValue(val types.Datum, col *model.ColumnInfo, colName model.CIStr, rowNum int64) (types.Datum, error) {
+	if val.IsNull() {
+		if mysql.HasNotNullFlag(col.Flag) {
+			return val, ErrInvalidUseOfNull
+		}
+		return val, nil
+	}
+	s := datumToString(val)
+	v, err := convertDefaultValue(&col.FieldType, s)
+	if err == types.ErrOverflow {
+		if types.IsString(col.Tp) {
+			return val, types.ErrDataTooLong.GenWithStackByArgs(colName.O, rowNum)
+		}
+		return val, types.ErrWarnDataOutOfRange.GenWithStackByArgs(colName.O, rowNum)
+	}
+	if err != nil {
+		return val, types.ErrTruncatedWrongVal.GenWithStackByArgs(types.TypeStr(col.Tp), s)
+	}
+	return table.DatumFromString(&col.FieldType, v)
+}
+
+func isColumnDataConversionErr(err error) bool {
+	return types.ErrDataTooLong.Equal(err) || types.ErrWarnDataOutOfRange.Equal(err) ||
+		types.ErrTruncatedWrongVal.Equal(err) || ErrInvalidUseOfNull.Equal(err)
+}
+
+// datumToString returns the string form of a not null datum.
+func datumToString(d types.Datum) string {
+	switch d.Kind() {
+	case types.KindInt64:
+		return strconv.FormatInt(d.GetInt64(), 10)
+	case types.KindUint64:
+		return strconv.FormatUint(d.GetUint64(), 10)
+	case types.KindFloat64:
+		return strconv.FormatFloat(d.GetFloat64(), 'g', -1, 64)
+	case types.KindString:
+		return d.GetString()
+	case types.KindBytes:
+		return string(d.GetBytes())
+	case types.KindMysqlDecimal:
+		return d.GetMysqlDecimal().String()
+	case types.KindMysqlTime, types.KindMysqlDuration, types.KindMysqlEnum, types.KindMysqlSet:
+		s, _ := d.ToString()
+		return s
+	case types.KindMysqlBit:
+		v, _ := d.GetMysqlBit().ToInt(nil)
+		return strconv.FormatUint(v, 10)
+	}
+	return fmt.Sprintf("%v", d.GetValue())
+}
+
+// checkModifyTypes checks whether the column type can be changed from origin to to, needReorg
+// is true if the stored values have to be converted.
+func checkModifyTypes(origin, to *types.FieldType, hasIndex bool) (needReorg bool, err error) {
+	if !isConvertibleType(origin.Tp) || !isConvertibleType(to.Tp) {
+		if origin.Tp != to.Tp || !isLosslessChange(origin, to) {
+			return false, errUnsupportedModifyColumn.GenWithStackByArgs(
+				fmt.Sprintf("type %s not match origin %s", to.CompactStr(), origin.CompactStr()))
+		}
+	}
+	if types.HasCharset(origin) && types.HasCharset(to) && origin.Charset != to.Charset &&
+		origin.Charset != charset.CharsetBin && to.Charset != charset.CharsetBin {
+		return false, errUnsupportedModifyColumn.GenWithStackByArgs(
+			fmt.Sprintf("charset %s not match origin %s", to.Charset, origin.Charset))
+	}
+	// The index keys of the strings are encoded by the collation.
+	if hasIndex && types.HasCharset(origin) && types.HasCharset(to) && origin.Collate != to.Collate {
+		return false, errUnsupportedModifyColumn.GenWithStackByArgs(
+			fmt.Sprintf("collate %s not match origin %s of an indexed column", to.Collate, origin.Collate))
+	}
+	needReorg = !isLosslessChange(origin, to) ||
+		(!mysql.HasNotNullFlag(origin.Flag) && mysql.HasNotNullFlag(to.Flag))
+	if needReorg && hasIndex {
+		return false, errUnsupportedModifyColumn.GenWithStackByArgs("converting the data of an indexed column")
+	}
+	return needReorg, nil
+}
+
+// isConvertibleType checks whether the values of the type can be converted to the other convertible types.
+func isConvertibleType(tp byte) bool {
+	switch tp {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong,
+		mysql.TypeFloat, mysql.TypeDouble, mysql.TypeNewDecimal,
+		mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		return true
+	}
+	return types.IsString(tp)
+}
+
+var (
+	intTypeRank = map[byte]int{
+		mysql.TypeTiny: 1, mysql.TypeShort: 2, mysql.TypeInt24: 3, mysql.TypeLong: 4, mysql.TypeLonglong: 5,
+	}
+	blobTypeRank = map[byte]int{
+		mysql.TypeTinyBlob: 1, mysql.TypeBlob: 2, mysql.TypeMediumBlob: 3, mysql.TypeLongBlob: 4,
+	}
+)
+
+// isLosslessChange checks whether every value of origin is a valid value of to with the same
+// storage format, so that the column info can be changed without touching the rows.
+func isLosslessChange(origin, to *types.FieldType) bool {
+	if mysql.HasUnsignedFlag(origin.Flag) != mysql.HasUnsignedFlag(to.Flag) {
+		return false
+	}
+	switch {
+	case intTypeRank[origin.Tp] > 0 && intTypeRank[to.Tp] > 0:
+		return intTypeRank[to.Tp] >= intTypeRank[origin.Tp]
+	case (origin.Tp == mysql.TypeFloat || origin.Tp == mysql.TypeDouble) && (to.Tp == mysql.TypeFloat || to.Tp == mysql.TypeDouble):
+		if origin.Tp == mysql.TypeDouble && to.Tp == mysql.TypeFloat {
+			return false
+		}
+		return to.Decimal == types.UnspecifiedLength || to.Decimal == origin.Decimal
+	case origin.Tp == mysql.TypeNewDecimal && to.Tp == mysql.TypeNewDecimal:
+		return to.Decimal == origin.Decimal && to.Flen-to.Decimal >= origin.Flen-origin.Decimal
+	case types.IsString(origin.Tp) && types.IsString(to.Tp):
+		if (origin.Charset == charset.CharsetBin) != (to.Charset == charset.CharsetBin) {
+			return false
+		}
+		switch {
+		case origin.Tp == mysql.TypeString:
+			return to.Tp == mysql.TypeString && to.Flen >= origin.Flen
+		case types.IsTypeBlob(origin.Tp):
+			return blobTypeRank[to.Tp] >= blobTypeRank[origin.Tp]
+		case types.IsTypeBlob(to.Tp):
+			return true
+		default:
+			return to.Tp != mysql.TypeString && to.Flen >= origin.Flen
+		}
+	case origin.Tp == to.Tp:
+		switch origin.Tp {
+		case mysql.TypeEnum, mysql.TypeSet:
+			// Only appending members keeps the stored values.
+			if len(to.Elems) < len(origin.Elems) {
+				return false
+			}
+			for i, elem := range origin.Elems {
+				if to.Elems[i] != elem {
+					return false
+				}
+			}
+			return true
+		case mysql.TypeBit:
+			return to.Flen >= origin.Flen
+		case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp, mysql.TypeDuration:
+			return to.Decimal >= origin.Decimal
+		case mysql.TypeJSON, mysql.TypeYear:
+			return true
+		}
+	}
+	return false
+}
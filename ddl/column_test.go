@@ -0,0 +1,97 @@
+package ddl_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/util/testkit"
+)
+
+// errCodeUnsupportedModifyColumn is the error code of the unsupported column type change.
+const errCodeUnsupportedModifyColumn = 8200
+
+func TestAddDropColumn(t *testing.T) {
+	store, _ := testkit.NewMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create database test")
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int primary key, b int)")
+	tk.MustExec("insert into t values (1, 10), (2, 20)")
+
+	// The existing rows read the origin default of the added column.
+	tk.MustExec("alter table t add column c varchar(10) not null default 'x'")
+	checkLastJob(t, store, model.ActionAddColumn, model.StatePublic)
+	tk.MustExec("alter table t add column d int default 5 first")
+	tk.MustQuery("select * from t order by a").Check("5 1 10 x", "5 2 20 x")
+	// The later default doesn't change the values of the existing rows.
+	tk.MustExec("alter table t modify column c varchar(10) not null default 'y'")
+	tk.MustExec("insert into t (a, b) values (3, 30)")
+	tk.MustQuery("select a, c from t order by a").Check("1 x", "2 x", "3 y")
+	tk.MustGetErrCode("alter table t add column b int", mysql.ErrDupFieldName)
+
+	tk.MustExec("alter table t drop column b")
+	checkLastJob(t, store, model.ActionDropColumn, model.StateNone)
+	tk.MustQuery("select * from t order by a").Check("5 1 x", "5 2 x", "5 3 y")
+	tk.MustGetErrCode("select b from t", mysql.ErrBadField)
+	tk.MustGetErrCode("alter table t drop column b", mysql.ErrCantDropFieldOrKey)
+	// The column of the same name is a new one.
+	tk.MustExec("alter table t add column b int")
+	tk.MustQuery("select a, b from t order by a").Check("1 <nil>", "2 <nil>", "3 <nil>")
+}
+
+func TestModifyColumn(t *testing.T) {
+	store, _ := testkit.NewMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create database test")
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int primary key, b tinyint, c varchar(10), d int, key idx_d (d))")
+	tk.MustExec("insert into t values (1, 100, '12', 1), (2, -100, 'abcdef', 2)")
+
+	// Widening the type only changes the column info.
+	tk.MustExec("alter table t modify column b bigint")
+	checkLastJob(t, store, model.ActionModifyColumn, model.StatePublic)
+	tk.MustExec("insert into t values (3, 100000, 'x', 3)")
+	tk.MustQuery("select b from t order by a").Check("100", "-100", "100000")
+
+	// Narrowing the type rewrites the rows, the job fails if a value doesn't fit.
+	tk.MustGetErrCode("alter table t modify column c varchar(3)", mysql.ErrDataTooLong)
+	tk.MustQuery("select c from t order by a").Check("12", "abcdef", "x")
+	tk.MustExec("update t set c = 'abc' where a = 2")
+	tk.MustExec("alter table t modify column c varchar(3)")
+	tk.MustGetErrCode("insert into t values (4, 0, 'abcd', 4)", mysql.ErrDataTooLong)
+	tk.MustGetErrCode("alter table t modify column b tinyint", mysql.ErrWarnDataOutOfRange)
+
+	// Changing the type converts the values.
+	tk.MustExec("update t set c = '7' where a = 2")
+	tk.MustExec("update t set c = '8' where a = 3")
+	tk.MustExec("alter table t modify column c int")
+	tk.MustQuery("select c + 1 from t order by a").Check("13", "8", "9")
+
+	// The data of the indexed column isn't converted.
+	tk.MustGetErrCode("alter table t modify column d varchar(10)", errCodeUnsupportedModifyColumn)
+	tk.MustGetErrCode("alter table t modify column a json", errCodeUnsupportedModifyColumn)
+	tk.MustExec("alter table t modify column d bigint")
+	tk.MustQuery("select a from t where d = 2").Check("2")
+}
+
+func TestChangeRenameColumn(t *testing.T) {
+	store, _ := testkit.NewMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create database test")
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int primary key, b int, c int, key idx_b (b))")
+	tk.MustExec("insert into t values (1, 10, 100)")
+
+	tk.MustExec("alter table t change column b bb bigint")
+	tk.MustQuery("select bb from t where bb = 10").Check("10")
+	tk.MustGetErrCode("select b from t", mysql.ErrBadField)
+
+	tk.MustExec("alter table t rename column bb to b2")
+	tk.MustQuery("select b2 from t use index (idx_b) where b2 = 10").Check("10")
+	tk.MustGetErrCode("alter table t rename column b2 to c", mysql.ErrDupFieldName)
+	tk.MustGetErrCode("alter table t rename column x to y", mysql.ErrBadField)
+
+	tk.MustExec("alter table t change column c c int after a")
+	tk.MustQuery("select * from t").Check("1 100 10")
+}
@@ -0,0 +1,92 @@
+package ddl_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/kv"
+	"grant-db/meta"
+	"grant-db/util/testkit"
+)
+
+// lastHistoryJob returns the last finished DDL job.
+func lastHistoryJob(t *testing.T, store kv.Storage) *model.Job {
+	t.Helper()
+	var jobs []*model.Job
+	err := kv.RunInNewTxn(store, false, func(txn kv.Transaction) error {
+		var err error
+		jobs, err = meta.NewMeta(txn).GetLastNHistoryDDLJobs(1)
+		return err
+	})
+	if err != nil || len(jobs) != 1 {
+		t.Fatalf("get the last history job: %v, %v", jobs, err)
+	}
+	return jobs[0]
+}
+
+// checkLastJob checks the last finished DDL job is a successful one of tp, which ends in state.
+func checkLastJob(t *testing.T, store kv.Storage, tp model.ActionType, state model.SchemaState) {
+	t.Helper()
+	job := lastHistoryJob(t, store)
+	if job.Type != tp || !job.IsSynced() || job.SchemaState != state {
+		t.Fatalf("the last job is %s, expected a synced %s in state %s", job, tp, state)
+	}
+}
+
+func TestCreateDropDatabase(t *testing.T) {
+	store, _ := testkit.NewMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create database d1")
+	checkLastJob(t, store, model.ActionCreateSchema, model.StatePublic)
+	tk.MustGetErrCode("create database d1", mysql.ErrDBCreateExists)
+	tk.MustExec("create database if not exists d1")
+	tk.MustGetErrCode("use d2", mysql.ErrBadDB)
+
+	tk.MustExec("use d1")
+	tk.MustExec("create table t (a int primary key, b varchar(10))")
+	tk.MustExec("insert into t values (1, 'x')")
+	tk.MustQuery("select * from d1.t").Check("1 x")
+
+	tk.MustExec("drop database d1")
+	checkLastJob(t, store, model.ActionDropSchema, model.StateNone)
+	tk.MustGetErrCode("drop database d1", mysql.ErrDBDropExists)
+	tk.MustExec("drop database if exists d1")
+	tk.MustGetErrCode("select * from d1.t", mysql.ErrNoSuchTable)
+
+	// The database of the same name is a new one.
+	tk.MustExec("create database d1")
+	tk.MustExec("use d1")
+	tk.MustGetErrCode("select * from t", mysql.ErrNoSuchTable)
+}
+
+func TestCreateDropTable(t *testing.T) {
+	store, _ := testkit.NewMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustGetErrCode("create table t (a int)", mysql.ErrNoDB)
+	tk.MustExec("create database test")
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, b int, key idx_b (b))")
+	checkLastJob(t, store, model.ActionCreateTable, model.StatePublic)
+	tk.MustGetErrCode("create table t (a int)", mysql.ErrTableExists)
+	tk.MustExec("create table if not exists t (a int)")
+	tk.MustGetErrCode("create table t1 (a int, a int)", mysql.ErrDupFieldName)
+	tk.MustGetErrCode("create table t1 (a int, key idx (a), key idx (a))", mysql.ErrDupKeyName)
+
+	tk.MustExec("insert into t values (1, 2), (3, 4)")
+	tk.MustQuery("select a from t where b = 4").Check("3")
+
+	// Another session sees the table once it's created.
+	tk2 := testkit.NewTestKit(t, store)
+	tk2.MustQuery("select a from test.t order by a").Check("1", "3")
+
+	tk.MustExec("drop table t")
+	checkLastJob(t, store, model.ActionDropTable, model.StateNone)
+	tk.MustGetErrCode("drop table t", mysql.ErrBadTable)
+	tk.MustExec("drop table if exists t")
+	tk2.MustGetErrCode("select a from test.t", mysql.ErrNoSuchTable)
+
+	// The table of the same name is a new one.
+	tk.MustExec("create table t (a int)")
+	tk2.MustQuery("select count(*) from test.t").Check("0")
+}
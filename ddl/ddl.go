@@ -0,0 +1,203 @@
+package ddl
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
+	"grant-db/infoschema"
+	"grant-db/kv"
+	"grant-db/sessionctx"
+)
+
+const (
+	codeCancelledDDLJob      terror.ErrCode = 8214
+	codeDDLJobNotFound       terror.ErrCode = 8223
+	codeCancelFinishedDDLJob terror.ErrCode = 8225
+	codeCannotCancelDDLJob   terror.ErrCode = 8226
+
+	codeUnsupportedModifyColumn terror.ErrCode = 8200
+)
+
+var (
+	errWorkerClosed    = terror.ClassDDL.New(mysql.ErrUnknown, "DDL worker is closed")
+	errInvalidDDLState = terror.ClassDDL.New(mysql.ErrUnknown, "invalid %s state: %v")
+	errCancelledDDLJob = terror.ClassDDL.New(codeCancelledDDLJob, "Cancelled DDL job")
+	// errWaitReorg means the reorganization is not finished, the job keeps its
+	// progress and continues in the next round.
+	errWaitReorg               = terror.ClassDDL.New(mysql.ErrUnknown, "wait for reorganization timeout")
+	errUnsupportedModifyColumn = terror.ClassDDL.New(codeUnsupportedModifyColumn, "Unsupported modify column: %s")
+
+	// ErrDDLJobNotFound indicates the job id was not found.
+	ErrDDLJobNotFound = terror.ClassDDL.New(codeDDLJobNotFound, "DDL Job:%v not found")
+	// ErrCancelFinishedDDLJob returns when cancel a finished ddl job.
+	ErrCancelFinishedDDLJob = terror.ClassDDL.New(codeCancelFinishedDDLJob, "This job:%v is finished, so can't be cancelled")
+	// ErrCannotCancelDDLJob returns when cancel a almost finished ddl job, because cancel in now may cause data inconsistency.
+	ErrCannotCancelDDLJob = terror.ClassDDL.New(codeCannotCancelDDLJob, "This job:%v is almost finished, can't be cancelled now")
+	// ErrCantDropFieldOrKey returns for dropping a non-existent field or key.
+	ErrCantDropFieldOrKey = terror.ClassDDL.New(mysql.ErrCantDropFieldOrKey, mysql.MySQLErrName[mysql.ErrCantDropFieldOrKey])
+	// ErrErrorOnRename returns for renaming a table to a non-existent database.
+	ErrErrorOnRename = terror.ClassDDL.New(mysql.ErrErrorOnRename, mysql.MySQLErrName[mysql.ErrErrorOnRename])
+	// ErrNoDroppedTable returns when the dropped or truncated table to recover isn't found.
+	ErrNoDroppedTable = terror.ClassDDL.New(mysql.ErrUnknown, "Can't find dropped/truncated table '%s' in GC safe point %s")
+	// ErrNotDropOrTruncateJob returns when recovering a table by a job which doesn't drop or truncate a table.
+	ErrNotDropOrTruncateJob = terror.ClassDDL.New(mysql.ErrUnknown, "Job %v type is %v, not dropped/truncated table")
+	// ErrTableDataGCed returns when the data of the table to recover is cleaned up by GC.
+	ErrTableDataGCed = terror.ClassDDL.New(mysql.ErrUnknown, "Can't recover table '%s', its data is older than GC safe point")
+	// ErrCantRemoveAllFields returns for deleting all columns.
+	ErrCantRemoveAllFields = terror.ClassDDL.New(mysql.ErrCantRemoveAllFields, mysql.MySQLErrName[mysql.ErrCantRemoveAllFields])
+	// ErrInvalidUseOfNull is used when a NULL value is converted to a NOT NULL column.
+	ErrInvalidUseOfNull = terror.ClassDDL.New(mysql.ErrInvalidUseOfNull, mysql.MySQLErrName[mysql.ErrInvalidUseOfNull])
+
+	// ErrNoDB is returned when no database is selected.
+	ErrNoDB = terror.ClassDDL.New(mysql.ErrNoDB, mysql.MySQLErrName[mysql.ErrNoDB])
+	// ErrWrongDBName returns for wrong database name.
+	ErrWrongDBName = terror.ClassDDL.New(mysql.ErrWrongDBName, mysql.MySQLErrName[mysql.ErrWrongDBName])
+	// ErrWrongTableName returns for wrong table name.
+	ErrWrongTableName = terror.ClassDDL.New(mysql.ErrWrongTableName, mysql.MySQLErrName[mysql.ErrWrongTableName])
+	// ErrWrongColumnName returns for wrong column name.
+	ErrWrongColumnName = terror.ClassDDL.New(mysql.ErrWrongColumnName, mysql.MySQLErrName[mysql.ErrWrongColumnName])
+	// ErrWrongNameForIndex returns for wrong index name.
+	ErrWrongNameForIndex = terror.ClassDDL.New(mysql.ErrWrongNameForIndex, mysql.MySQLErrName[mysql.ErrWrongNameForIndex])
+	// ErrTooLongIdent returns for too long name of database/table/column/index.
+	ErrTooLongIdent = terror.ClassDDL.New(mysql.ErrTooLongIdent, mysql.MySQLErrName[mysql.ErrTooLongIdent])
+	// ErrTableMustHaveColumns returns for missing column when creating a table.
+	ErrTableMustHaveColumns = terror.ClassDDL.New(mysql.ErrTableMustHaveColumns, mysql.MySQLErrName[mysql.ErrTableMustHaveColumns])
+	// ErrInvalidDefaultValue returns for invalid default value for columns.
+	ErrInvalidDefaultValue = terror.ClassDDL.New(mysql.ErrInvalidDefault, mysql.MySQLErrName[mysql.ErrInvalidDefault])
+	// ErrBlobCantHaveDefault returns for blob can't have default value.
+	ErrBlobCantHaveDefault = terror.ClassDDL.New(mysql.ErrBlobCantHaveDefault, mysql.MySQLErrName[mysql.ErrBlobCantHaveDefault])
+	// ErrInvalidOnUpdate returns for invalid ON UPDATE clause.
+	ErrInvalidOnUpdate = terror.ClassDDL.New(mysql.ErrInvalidOnUpdate, mysql.MySQLErrName[mysql.ErrInvalidOnUpdate])
+	// ErrTooBigFieldLength returns for too big field length.
+	ErrTooBigFieldLength = terror.ClassDDL.New(mysql.ErrTooBigFieldlength, mysql.MySQLErrName[mysql.ErrTooBigFieldlength])
+	// ErrTooBigPrecision returns for too big precision.
+	ErrTooBigPrecision = terror.ClassDDL.New(mysql.ErrTooBigPrecision, mysql.MySQLErrName[mysql.ErrTooBigPrecision])
+	// ErrTooBigScale returns for too big scale.
+	ErrTooBigScale = terror.ClassDDL.New(mysql.ErrTooBigScale, mysql.MySQLErrName[mysql.ErrTooBigScale])
+	// ErrMBiggerThanD returns for M must be >= D.
+	ErrMBiggerThanD = terror.ClassDDL.New(mysql.ErrMBiggerThanD, mysql.MySQLErrName[mysql.ErrMBiggerThanD])
+	// ErrTooBigDisplayWidth returns for data display width exceed limit .
+	ErrTooBigDisplayWidth = terror.ClassDDL.New(mysql.ErrTooBigDisplaywidth, mysql.MySQLErrName[mysql.ErrTooBigDisplaywidth])
+	// ErrDuplicatedValueInType returns for duplicated values in ENUM/SET definitions.
+	ErrDuplicatedValueInType = terror.ClassDDL.New(mysql.ErrDuplicatedValueInType, mysql.MySQLErrName[mysql.ErrDuplicatedValueInType])
+	// ErrMultiplePriKey returns for multiple primary keys.
+	ErrMultiplePriKey = terror.ClassDDL.New(mysql.ErrMultiplePriKey, mysql.MySQLErrName[mysql.ErrMultiplePriKey])
+	// ErrPrimaryCantHaveNull returns All parts of a PRIMARY KEY must be NOT NULL; if you need NULL in a key, use UNIQUE instead
+	ErrPrimaryCantHaveNull = terror.ClassDDL.New(mysql.ErrPrimaryCantHaveNull, mysql.MySQLErrName[mysql.ErrPrimaryCantHaveNull])
+	// ErrKeyColumnDoesNotExits is used when the key column doesn't exist.
+	ErrKeyColumnDoesNotExits = terror.ClassDDL.New(mysql.ErrKeyColumnDoesNotExits, mysql.MySQLErrName[mysql.ErrKeyColumnDoesNotExits])
+	// ErrTooManyKeyParts returns for too many key parts.
+	ErrTooManyKeyParts = terror.ClassDDL.New(mysql.ErrTooManyKeyParts, mysql.MySQLErrName[mysql.ErrTooManyKeyParts])
+	// ErrTooLongKey is used when the column key is too long.
+	ErrTooLongKey = terror.ClassDDL.New(mysql.ErrTooLongKey, mysql.MySQLErrName[mysql.ErrTooLongKey])
+	// ErrIncorrectPrefixKey is used when the prefix length is incorrect for a string key.
+	ErrIncorrectPrefixKey = terror.ClassDDL.New(mysql.ErrWrongSubKey, mysql.MySQLErrName[mysql.ErrWrongSubKey])
+	// ErrBlobKeyWithoutLength is used when BLOB is used as key but without a length.
+	ErrBlobKeyWithoutLength = terror.ClassDDL.New(mysql.ErrBlobKeyWithoutLength, mysql.MySQLErrName[mysql.ErrBlobKeyWithoutLength])
+	// ErrJSONUsedAsKey forbids to use JSON as key or index.
+	ErrJSONUsedAsKey = terror.ClassDDL.New(mysql.ErrJSONUsedAsKey, mysql.MySQLErrName[mysql.ErrJSONUsedAsKey])
+	// ErrWrongAutoKey returns for wrong auto key.
+	ErrWrongAutoKey = terror.ClassDDL.New(mysql.ErrWrongAutoKey, mysql.MySQLErrName[mysql.ErrWrongAutoKey])
+	// ErrWrongFieldSpec returns for wrong column specifier, e.g. AUTO_INCREMENT on a string column.
+	ErrWrongFieldSpec = terror.ClassDDL.New(mysql.ErrWrongFieldSpec, mysql.MySQLErrName[mysql.ErrWrongFieldSpec])
+	// ErrDBDropExists returns for dropping a non-existent database.
+	ErrDBDropExists = terror.ClassDDL.New(mysql.ErrDBDropExists, mysql.MySQLErrName[mysql.ErrDBDropExists])
+	// ErrBadTable returns for dropping a non-existent table.
+	ErrBadTable = terror.ClassDDL.New(mysql.ErrBadTable, mysql.MySQLErrName[mysql.ErrBadTable])
+	// ErrCollationCharsetMismatch returns when collation not match the charset.
+	ErrCollationCharsetMismatch = terror.ClassDDL.New(mysql.ErrCollationCharsetMismatch, mysql.MySQLErrName[mysql.ErrCollationCharsetMismatch])
+	// ErrNotSupportedYet returns for unsupported features.
+	ErrNotSupportedYet = terror.ClassDDL.New(mysql.ErrNotSupportedYet, mysql.MySQLErrName[mysql.ErrNotSupportedYet])
+)
+
+// DDL is responsible for updating schema in data store and maintaining in-memory InfoSchema cache.
+type DDL interface {
+	CreateSchema(ctx sessionctx.Context, stmt *ast.CreateDatabaseStmt) error
+	DropSchema(ctx sessionctx.Context, stmt *ast.DropDatabaseStmt) error
+	CreateTable(ctx sessionctx.Context, stmt *ast.CreateTableStmt) error
+	DropTable(ctx sessionctx.Context, stmt *ast.DropTableStmt) error
+	CreateIndex(ctx sessionctx.Context, tableIdent ast.Ident, keyType ast.IndexKeyType, indexName model.CIStr,
+		columnNames []*ast.IndexPartSpecification, indexOption *ast.IndexOption, ifNotExists bool) error
+	DropIndex(ctx sessionctx.Context, tableIdent ast.Ident, indexName model.CIStr, ifExists bool) error
+	AlterTable(ctx sessionctx.Context, tableIdent ast.Ident, spec []*ast.AlterTableSpec) error
+	TruncateTable(ctx sessionctx.Context, tableIdent ast.Ident) error
+	RenameTables(ctx sessionctx.Context, oldTableIdents, newTableIdents []ast.Ident, isAlterTable bool) error
+	RecoverTable(ctx sessionctx.Context, recoverInfo *RecoverInfo) error
+	// Stop stops DDL worker.
+	Stop() error
+}
+
+// RecoverInfo describes the dropped or truncated table to recover.
+type RecoverInfo struct {
+	SchemaID  int64
+	TableInfo *model.TableInfo
+	// DropJobID is the ID of the job which drops or truncates the table.
+	DropJobID int64
+}
+
+// InfoSchemaLoader provides the latest InfoSchema and reloads it after a schema change.
+type InfoSchemaLoader interface {
+	InfoSchema() infoschema.InfoSchema
+	Reload() error
+	// OldestSchemaVersion returns the oldest schema version used by the transactions in progress, it's 0 if
+	// there is no transaction in progress.
+	OldestSchemaVersion() int64
+}
+
+// Options are the options of DDL.
+type Options struct {
+	// Lease is the longest time the worker waits for the transactions to use a new schema.
+	Lease time.Duration
+	// GCLifeTime is how long the data of a dropped or truncated table is kept, the table can be recovered
+	// in the time. The GC safe point is GCLifeTime before the current time.
+	GCLifeTime time.Duration
+	// GCRunInterval is the interval GC advances the safe point and cleans up the data before it.
+	GCRunInterval time.Duration
+}
+
+// DefaultOptions are the default options of DDL.
+var DefaultOptions = Options{
+	Lease:         time.Second,
+	GCLifeTime:    10 * time.Minute,
+	GCRunInterval: time.Minute,
+}
+
+type ddl struct {
+	store   kv.Storage
+	loader  InfoSchemaLoader
+	options Options
+	// ddlJobCh notifies the worker that a new job is queued.
+	ddlJobCh chan struct{}
+	quitCh   chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewDDL creates a new DDL and starts its worker.
+func NewDDL(store kv.Storage, loader InfoSchemaLoader, options Options) DDL {
+	d := &ddl{
+		store:    store,
+		loader:   loader,
+		options:  options,
+		ddlJobCh: make(chan struct{}, 1),
+		quitCh:   make(chan struct{}),
+	}
+	d.start()
+	d.startGCWorker()
+	return d
+}
+
+func (d *ddl) Stop() error {
+	if !d.isClosed() {
+		close(d.quitCh)
+	}
+	d.wg.Wait()
+	return nil
+}
+
+func (d *ddl) infoSchema() infoschema.InfoSchema {
+	return d.loader.InfoSchema()
+}
@@ -0,0 +1,1264 @@
+package ddl
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/format"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
+	"grant-db/infoschema"
+	"grant-db/kv"
+	"grant-db/meta"
+	"grant-db/meta/autoid"
+	"grant-db/sessionctx"
+	"grant-db/table"
+	"grant-db/types"
+	"grant-db/util/charset"
+)
+
+const (
+	// maxIdentLength is the max length of identifier names of databases, tables, columns and indices.
+	maxIdentLength = 64
+	// maxKeyParts is the max number of columns in one index.
+	maxKeyParts = 16
+	// maxKeyLength is the max length of an index key in bytes.
+	maxKeyLength = 3072
+)
+
+func (d *ddl) CreateSchema(ctx sessionctx.Context, stmt *ast.CreateDatabaseStmt) error {
+	schema := model.NewCIStr(stmt.Name)
+	if err := checkIdentName(schema.O, ErrWrongDBName); err != nil {
+		return err
+	}
+	if d.infoSchema().SchemaExists(schema) {
+		if stmt.IfNotExists {
+			return nil
+		}
+		return infoschema.ErrDatabaseExists.GenWithStackByArgs(schema)
+	}
+	chs, coll, err := resolveCharsetCollation(stmt.Options)
+	if err != nil {
+		return err
+	}
+	dbInfo := &model.DBInfo{Name: schema, Charset: chs, Collate: coll}
+	schemaID, err := d.genGlobalID()
+	if err != nil {
+		return err
+	}
+	job := &model.Job{
+		SchemaID:   schemaID,
+		SchemaName: dbInfo.Name.L,
+		Type:       model.ActionCreateSchema,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{dbInfo},
+	}
+	err = d.doDDLJob(job)
+	if infoschema.ErrDatabaseExists.Equal(err) && stmt.IfNotExists {
+		return nil
+	}
+	return err
+}
+
+// resolveCharsetCollation picks the charset and collation from the options of CREATE DATABASE,
+// a missing one is derived from the other.
+func resolveCharsetCollation(options []*ast.DatabaseOption) (string, string, error) {
+	var chs, coll string
+	for _, opt := range options {
+		switch opt.Tp {
+		case ast.DatabaseOptionCharset:
+			chs = opt.Value
+		case ast.DatabaseOptionCollate:
+			coll = opt.Value
+		}
+	}
+	return checkCharsetAndCollation(chs, coll)
+}
+
+// checkCharsetAndCollation checks the charset and the collation are supported and match, a missing one
+// is derived from the other.
+func checkCharsetAndCollation(chs, coll string) (string, string, error) {
+	return charset.ResolveCharsetCollation(chs, coll)
+}
+
+func (d *ddl) DropSchema(ctx sessionctx.Context, stmt *ast.DropDatabaseStmt) error {
+	schema := model.NewCIStr(stmt.Name)
+	old, ok := d.infoSchema().SchemaByName(schema)
+	if !ok {
+		if stmt.IfExists {
+			return nil
+		}
+		return ErrDBDropExists.GenWithStackByArgs(schema)
+	}
+	job := &model.Job{
+		SchemaID:   old.ID,
+		SchemaName: old.Name.L,
+		Type:       model.ActionDropSchema,
+		BinlogInfo: &model.HistoryInfo{},
+	}
+	err := d.doDDLJob(job)
+	if infoschema.ErrDatabaseNotExists.Equal(err) {
+		if stmt.IfExists {
+			return nil
+		}
+		return ErrDBDropExists.GenWithStackByArgs(schema)
+	}
+	if err != nil {
+		return err
+	}
+	if ctx.GetSessionVars().CurrentDB == schema.L {
+		ctx.GetSessionVars().CurrentDB = ""
+	}
+	return nil
+}
+
+// getSchemaName returns the schema of the table name, the current database is used if it is not specified.
+func getSchemaName(ctx sessionctx.Context, tn *ast.TableName) (model.CIStr, error) {
+	if tn.Schema.O != "" {
+		return tn.Schema, nil
+	}
+	if ctx.GetSessionVars().CurrentDB == "" {
+		return model.CIStr{}, ErrNoDB
+	}
+	return model.NewCIStr(ctx.GetSessionVars().CurrentDB), nil
+}
+
+func (d *ddl) CreateTable(ctx sessionctx.Context, s *ast.CreateTableStmt) error {
+	if s.ReferTable != nil || s.Select != nil {
+		return ErrNotSupportedYet.GenWithStackByArgs("CREATE TABLE ... LIKE/SELECT")
+	}
+	if s.IsTemporary {
+		return ErrNotSupportedYet.GenWithStackByArgs("CREATE TEMPORARY TABLE")
+	}
+	schemaName, err := getSchemaName(ctx, s.Table)
+	if err != nil {
+		return err
+	}
+	is := d.infoSchema()
+	schema, ok := is.SchemaByName(schemaName)
+	if !ok {
+		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(schemaName)
+	}
+	if is.TableExists(schemaName, s.Table.Name) {
+		if s.IfNotExists {
+			return nil
+		}
+		return infoschema.ErrTableExists.GenWithStackByArgs(s.Table.Name)
+	}
+
+	tbInfo, err := buildTableInfoWithCheck(s, schema)
+	if err != nil {
+		return err
+	}
+	if tbInfo.ID, err = d.genGlobalID(); err != nil {
+		return err
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tbInfo.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionCreateTable,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{tbInfo},
+	}
+	err = d.doDDLJob(job)
+	if infoschema.ErrTableExists.Equal(err) && s.IfNotExists {
+		return nil
+	}
+	return err
+}
+
+// buildTableInfoWithCheck builds the table info of CREATE TABLE and validates its columns, indices and options.
+func buildTableInfoWithCheck(s *ast.CreateTableStmt, dbInfo *model.DBInfo) (*model.TableInfo, error) {
+	if err := checkIdentName(s.Table.Name.O, ErrWrongTableName); err != nil {
+		return nil, err
+	}
+	if len(s.Cols) == 0 {
+		return nil, ErrTableMustHaveColumns
+	}
+	tbInfo := &model.TableInfo{
+		Name:    s.Table.Name,
+		Charset: dbInfo.Charset,
+		Collate: dbInfo.Collate,
+		Version: model.CurrLatestTableInfoVersion,
+	}
+	if err := handleTableOptions(s.Options, tbInfo); err != nil {
+		return nil, err
+	}
+	cols, constraints, err := buildColumnsAndConstraints(s.Cols, s.Constraints, tbInfo)
+	if err != nil {
+		return nil, err
+	}
+	tbInfo.Columns = cols
+	if err = buildIndices(tbInfo, constraints, explicitNullColumns(s.Cols)); err != nil {
+		return nil, err
+	}
+	if err = checkAutoIncrement(tbInfo); err != nil {
+		return nil, err
+	}
+	if err = setAutoRandomBits(tbInfo, s.Cols); err != nil {
+		return nil, err
+	}
+	return tbInfo, nil
+}
+
+// handleTableOptions updates tableInfo according to table options.
+func handleTableOptions(options []*ast.TableOption, tbInfo *model.TableInfo) error {
+	var chs, coll string
+	for _, op := range options {
+		switch op.Tp {
+		case ast.TableOptionAutoIncrement:
+			tbInfo.AutoIncID = int64(op.UintValue)
+		case ast.TableOptionComment:
+			tbInfo.Comment = op.StrValue
+		case ast.TableOptionCharset:
+			chs = op.StrValue
+		case ast.TableOptionCollate:
+			coll = op.StrValue
+		}
+	}
+	if chs != "" || coll != "" {
+		var err error
+		if tbInfo.Charset, tbInfo.Collate, err = checkCharsetAndCollation(chs, coll); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// buildColumnsAndConstraints builds the columns, the PRIMARY KEY and UNIQUE column options
+// are turned into table constraints.
+func buildColumnsAndConstraints(colDefs []*ast.ColumnDef, constraints []*ast.Constraint,
+	tbInfo *model.TableInfo) ([]*model.ColumnInfo, []*ast.Constraint, error) {
+	cols := make([]*model.ColumnInfo, 0, len(colDefs))
+	names := make(map[string]struct{}, len(colDefs))
+	for i, colDef := range colDefs {
+		if _, ok := names[colDef.Name.Name.L]; ok {
+			return nil, nil, infoschema.ErrColumnExists.GenWithStackByArgs(colDef.Name.Name)
+		}
+		names[colDef.Name.Name.L] = struct{}{}
+		col, cts, err := buildColumnAndConstraint(i, colDef, tbInfo)
+		if err != nil {
+			return nil, nil, err
+		}
+		tbInfo.MaxColumnID++
+		col.ID = tbInfo.MaxColumnID
+		col.State = model.StatePublic
+		cols = append(cols, col)
+		constraints = append(constraints, cts...)
+	}
+	return cols, constraints, nil
+}
+
+// buildIndices creates the indices of the table constraints, a single integer
+// primary key column becomes the row handle instead of an index.
+func buildIndices(tbInfo *model.TableInfo, constraints []*ast.Constraint, nullCols map[string]bool) error {
+	hasPrimaryKey := false
+	for _, constr := range constraints {
+		switch constr.Tp {
+		case ast.ConstraintPrimaryKey:
+			if hasPrimaryKey {
+				return ErrMultiplePriKey
+			}
+			hasPrimaryKey = true
+			if err := setPrimaryKeyColumns(tbInfo, constr.Keys, nullCols); err != nil {
+				return err
+			}
+			if col := singleIntPKColumn(tbInfo, constr.Keys); col != nil {
+				col.Flag |= mysql.PriKeyFlag
+				tbInfo.PKIsHandle = true
+				continue
+			}
+			if _, err := addIndexInfo(tbInfo, mysql.PrimaryKeyName, constr, true, true); err != nil {
+				return err
+			}
+		case ast.ConstraintKey, ast.ConstraintIndex:
+			if _, err := addIndexInfo(tbInfo, constr.Name, constr, false, false); err != nil {
+				return err
+			}
+		case ast.ConstraintUniq, ast.ConstraintUniqKey, ast.ConstraintUniqIndex:
+			if _, err := addIndexInfo(tbInfo, constr.Name, constr, true, false); err != nil {
+				return err
+			}
+		case ast.ConstraintFulltext:
+			return ErrNotSupportedYet.GenWithStackByArgs("FULLTEXT index")
+		case ast.ConstraintForeignKey, ast.ConstraintCheck:
+			// Foreign keys and check constraints are parsed but not enforced, the same as MySQL 5.7 does for checks.
+		}
+	}
+	return nil
+}
+
+// explicitNullColumns returns the names of the columns with an explicit NULL option or DEFAULT NULL.
+func explicitNullColumns(colDefs []*ast.ColumnDef) map[string]bool {
+	nullCols := make(map[string]bool)
+	for _, colDef := range colDefs {
+		for _, opt := range colDef.Options {
+			if opt.Tp == ast.ColumnOptionNull || (opt.Tp == ast.ColumnOptionDefaultValue && isNullExpr(opt.Expr)) {
+				nullCols[colDef.Name.Name.L] = true
+			}
+		}
+	}
+	return nullCols
+}
+
+// setPrimaryKeyColumns marks the key columns NOT NULL, a column explicitly declared NULL is an error.
+func setPrimaryKeyColumns(tbInfo *model.TableInfo, keys []*ast.IndexPartSpecification, nullCols map[string]bool) error {
+	for _, key := range keys {
+		col := model.FindColumnInfo(tbInfo.Columns, key.Column.Name.L)
+		if col == nil {
+			return ErrKeyColumnDoesNotExits.GenWithStackByArgs(key.Column.Name)
+		}
+		if nullCols[col.Name.L] {
+			return ErrPrimaryCantHaveNull
+		}
+		col.Flag |= mysql.NotNullFlag
+		if col.GetDefaultValue() == nil {
+			col.Flag |= mysql.NoDefaultValueFlag
+		}
+	}
+	return nil
+}
+
+func singleIntPKColumn(tbInfo *model.TableInfo, keys []*ast.IndexPartSpecification) *model.ColumnInfo {
+	if len(keys) != 1 || keys[0].Length > 0 {
+		return nil
+	}
+	col := model.FindColumnInfo(tbInfo.Columns, keys[0].Column.Name.L)
+	if col == nil || !mysql.IsIntegerType(col.Tp) || col.Tp == mysql.TypeBit {
+		return nil
+	}
+	return col
+}
+
+// addIndexInfo builds a public index of the constraint and appends it to the table.
+func addIndexInfo(tbInfo *model.TableInfo, name string, constr *ast.Constraint, unique, primary bool) (*model.IndexInfo, error) {
+	if name == "" {
+		name = genIndexName(tbInfo, constr.Keys[0].Column.Name.O)
+	}
+	if err := checkIndexName(tbInfo, name, primary); err != nil {
+		return nil, err
+	}
+	idx, err := buildIndexInfo(tbInfo, model.NewCIStr(name), constr.Keys, constr.Option, model.StatePublic)
+	if err != nil {
+		return nil, err
+	}
+	tbInfo.MaxIndexID++
+	idx.ID = tbInfo.MaxIndexID
+	idx.Unique = unique
+	idx.Primary = primary
+	setIndexColumnFlag(tbInfo, idx)
+	tbInfo.Indices = append(tbInfo.Indices, idx)
+	return idx, nil
+}
+
+// checkIndexName checks the name of a new index, only the primary key can be named PRIMARY.
+func checkIndexName(tbInfo *model.TableInfo, name string, primary bool) error {
+	if !primary && strings.EqualFold(name, mysql.PrimaryKeyName) {
+		return ErrWrongNameForIndex.GenWithStackByArgs(name)
+	}
+	if err := checkIdentName(name, ErrWrongNameForIndex); err != nil {
+		return err
+	}
+	if tbInfo.FindIndexByName(strings.ToLower(name)) != nil {
+		return infoschema.ErrIndexExists.GenWithStackByArgs(name)
+	}
+	return nil
+}
+
+// buildIndexInfo builds an index of the key parts in the state, the ID is left to the caller.
+func buildIndexInfo(tbInfo *model.TableInfo, indexName model.CIStr, keys []*ast.IndexPartSpecification,
+	option *ast.IndexOption, state model.SchemaState) (*model.IndexInfo, error) {
+	if len(keys) > maxKeyParts {
+		return nil, ErrTooManyKeyParts.GenWithStackByArgs(maxKeyParts)
+	}
+	idxCols, err := buildIndexColumns(tbInfo.Columns, keys)
+	if err != nil {
+		return nil, err
+	}
+	idx := &model.IndexInfo{
+		Name:    indexName,
+		Table:   tbInfo.Name,
+		Columns: idxCols,
+		State:   state,
+		Tp:      model.IndexTypeBtree,
+	}
+	if option != nil {
+		idx.Comment = option.Comment
+		if option.Tp != model.IndexTypeInvalid {
+			idx.Tp = option.Tp
+		}
+	}
+	return idx, nil
+}
+
+// genIndexName generates the name of an anonymous index from its first column, the same as MySQL.
+func genIndexName(tbInfo *model.TableInfo, colName string) string {
+	name := colName
+	for i := 2; tbInfo.FindIndexByName(strings.ToLower(name)) != nil || strings.EqualFold(name, mysql.PrimaryKeyName); i++ {
+		name = colName + "_" + strconv.Itoa(i)
+	}
+	return name
+}
+
+func buildIndexColumns(columns []*model.ColumnInfo, keys []*ast.IndexPartSpecification) ([]*model.IndexColumn, error) {
+	idxParts := make([]*model.IndexColumn, 0, len(keys))
+	sumLength := 0
+	for _, key := range keys {
+		if key.Expr != nil {
+			return nil, ErrNotSupportedYet.GenWithStackByArgs("expression index")
+		}
+		col := model.FindColumnInfo(columns, key.Column.Name.L)
+		if col == nil {
+			return nil, ErrKeyColumnDoesNotExits.GenWithStackByArgs(key.Column.Name)
+		}
+		// The length is -1 if the key part has no prefix length.
+		prefixLength := key.Length
+		if prefixLength < 0 {
+			prefixLength = 0
+		}
+		if err := checkIndexColumn(col, prefixLength); err != nil {
+			return nil, err
+		}
+		length := prefixLength
+		if length == 0 {
+			length = types.UnspecifiedLength
+		}
+		sumLength += indexColumnLength(col, prefixLength)
+		if sumLength > maxKeyLength {
+			return nil, ErrTooLongKey.GenWithStackByArgs(maxKeyLength)
+		}
+		idxParts = append(idxParts, &model.IndexColumn{
+			Name:   col.Name,
+			Offset: col.Offset,
+			Length: length,
+		})
+	}
+	return idxParts, nil
+}
+
+func checkIndexColumn(col *model.ColumnInfo, prefixLength int) error {
+	if col.Tp == mysql.TypeJSON {
+		return ErrJSONUsedAsKey.GenWithStackByArgs(col.Name.O)
+	}
+	if prefixLength == 0 {
+		if types.IsTypeBlob(col.Tp) {
+			return ErrBlobKeyWithoutLength.GenWithStackByArgs(col.Name.O)
+		}
+		return nil
+	}
+	if !types.IsTypeChar(col.Tp) && !types.IsTypeBlob(col.Tp) && col.Tp != mysql.TypeVarString {
+		return ErrIncorrectPrefixKey
+	}
+	if types.IsTypeChar(col.Tp) && col.Flen < prefixLength {
+		return ErrIncorrectPrefixKey
+	}
+	return nil
+}
+
+// indexColumnLength returns the max bytes of the column in an index key.
+func indexColumnLength(col *model.ColumnInfo, prefixLength int) int {
+	length := col.Flen
+	if prefixLength > 0 {
+		length = prefixLength
+	}
+	if types.IsTypeChar(col.Tp) || types.IsTypeBlob(col.Tp) || col.Tp == mysql.TypeVarString {
+		if desc, err := charset.GetCharsetInfo(col.Charset); err == nil {
+			length *= desc.Maxlen
+		}
+	} else {
+		length = 8
+	}
+	return length
+}
+
+// autoRandomOption returns the AUTO_RANDOM option of the column, or nil if it has none.
+func autoRandomOption(colDef *ast.ColumnDef) *ast.ColumnOption {
+	for _, opt := range colDef.Options {
+		if opt.Tp == ast.ColumnOptionAutoRandom {
+			return opt
+		}
+	}
+	return nil
+}
+
+// setAutoRandomBits sets the shard bits of the AUTO_RANDOM column, which must be the BIGINT primary key
+// handle without AUTO_INCREMENT or DEFAULT.
+func setAutoRandomBits(tbInfo *model.TableInfo, colDefs []*ast.ColumnDef) error {
+	for _, colDef := range colDefs {
+		opt := autoRandomOption(colDef)
+		if opt == nil {
+			continue
+		}
+		col := model.FindColumnInfo(tbInfo.Columns, colDef.Name.Name.L)
+		if col.Tp != mysql.TypeLonglong {
+			return autoid.ErrInvalidAutoRandom.GenWithStackByArgs(
+				fmt.Sprintf("auto_random option must be defined on `bigint` column, but not on `%s` column", col.Name))
+		}
+		if !tbInfo.PKIsHandle || !mysql.HasPriKeyFlag(col.Flag) {
+			return autoid.ErrInvalidAutoRandom.GenWithStackByArgs(
+				fmt.Sprintf("column %s is not the integer primary key", col.Name))
+		}
+		if mysql.HasAutoIncrementFlag(col.Flag) {
+			return autoid.ErrInvalidAutoRandom.GenWithStackByArgs("auto_random is incompatible with auto_increment")
+		}
+		if col.GetDefaultValue() != nil {
+			return autoid.ErrInvalidAutoRandom.GenWithStackByArgs("auto_random is incompatible with default")
+		}
+		bits := opt.AutoRandomBitLength
+		if bits == types.UnspecifiedLength {
+			bits = autoid.DefaultAutoRandomBits
+		}
+		if bits <= 0 {
+			return autoid.ErrInvalidAutoRandom.GenWithStackByArgs(
+				fmt.Sprintf("auto_random shard bits must be positive, but got %d on column `%s`", bits, col.Name))
+		}
+		if bits > autoid.MaxAutoRandomBits {
+			return autoid.ErrInvalidAutoRandom.GenWithStackByArgs(
+				fmt.Sprintf("max allowed auto_random shard bits is %d, but got %d on column `%s`", autoid.MaxAutoRandomBits, bits, col.Name))
+		}
+		tbInfo.AutoRandomBits = uint64(bits)
+	}
+	return nil
+}
+
+// checkAutoIncrement checks there is at most one AUTO_INCREMENT column and it is the first column of a key.
+func checkAutoIncrement(tbInfo *model.TableInfo) error {
+	var autoCol *model.ColumnInfo
+	for _, col := range tbInfo.Columns {
+		if mysql.HasAutoIncrementFlag(col.Flag) {
+			if autoCol != nil {
+				return ErrWrongAutoKey
+			}
+			autoCol = col
+		}
+	}
+	if autoCol == nil {
+		return nil
+	}
+	if tbInfo.PKIsHandle && mysql.HasPriKeyFlag(autoCol.Flag) {
+		return nil
+	}
+	for _, idx := range tbInfo.Indices {
+		if idx.Columns[0].Offset == autoCol.Offset {
+			return nil
+		}
+	}
+	return ErrWrongAutoKey
+}
+
+func (d *ddl) DropTable(ctx sessionctx.Context, stmt *ast.DropTableStmt) error {
+	if stmt.IsView {
+		return ErrNotSupportedYet.GenWithStackByArgs("DROP VIEW")
+	}
+	var notExists []string
+	for _, tn := range stmt.Tables {
+		schemaName, err := getSchemaName(ctx, tn)
+		if err != nil {
+			return err
+		}
+		is := d.infoSchema()
+		schema, ok := is.SchemaByName(schemaName)
+		if !ok {
+			notExists = append(notExists, schemaName.O+"."+tn.Name.O)
+			continue
+		}
+		tbl, err := is.TableByName(schemaName, tn.Name)
+		if err != nil {
+			notExists = append(notExists, schemaName.O+"."+tn.Name.O)
+			continue
+		}
+		job := &model.Job{
+			SchemaID:   schema.ID,
+			TableID:    tbl.Meta().ID,
+			SchemaName: schema.Name.L,
+			Type:       model.ActionDropTable,
+			BinlogInfo: &model.HistoryInfo{},
+		}
+		if err = d.doDDLJob(job); err != nil {
+			if infoschema.ErrTableNotExists.Equal(err) {
+				notExists = append(notExists, schemaName.O+"."+tn.Name.O)
+				continue
+			}
+			return err
+		}
+	}
+	if len(notExists) > 0 && !stmt.IfExists {
+		return ErrBadTable.GenWithStackByArgs(strings.Join(notExists, ","))
+	}
+	return nil
+}
+
+func (d *ddl) genGlobalID() (int64, error) {
+	var globalID int64
+	err := kv.RunInNewTxn(d.store, true, func(txn kv.Transaction) error {
+		var err error
+		globalID, err = meta.NewMeta(txn).GenGlobalID()
+		return err
+	})
+	return globalID, err
+}
+
+// checkIdentName checks the name of a database, table, column or index, errWrongName is returned for
+// an empty name or a name ending with space.
+func checkIdentName(name string, errWrongName *terror.Error) error {
+	if name == "" || name[len(name)-1] == ' ' {
+		return errWrongName.GenWithStackByArgs(name)
+	}
+	if utf8.RuneCountInString(name) > maxIdentLength {
+		return ErrTooLongIdent.GenWithStackByArgs(name)
+	}
+	return nil
+}
+
+// getSchemaAndTableByIdent gets the schema and the table of ident, the current database is used
+// if the schema isn't specified.
+func (d *ddl) getSchemaAndTableByIdent(ctx sessionctx.Context, ident ast.Ident) (*model.DBInfo, table.Table, error) {
+	schemaName := ident.Schema
+	if schemaName.L == "" {
+		if ctx.GetSessionVars().CurrentDB == "" {
+			return nil, nil, ErrNoDB
+		}
+		schemaName = model.NewCIStr(ctx.GetSessionVars().CurrentDB)
+	}
+	is := d.infoSchema()
+	schema, ok := is.SchemaByName(schemaName)
+	if !ok {
+		return nil, nil, infoschema.ErrDatabaseNotExists.GenWithStackByArgs(schemaName)
+	}
+	t, err := is.TableByName(schemaName, ident.Name)
+	if err != nil {
+		return nil, nil, infoschema.ErrTableNotExists.GenWithStackByArgs(schemaName, ident.Name)
+	}
+	return schema, t, nil
+}
+
+func (d *ddl) AlterTable(ctx sessionctx.Context, ident ast.Ident, specs []*ast.AlterTableSpec) error {
+	validSpecs := make([]*ast.AlterTableSpec, 0, len(specs))
+	for _, spec := range specs {
+		// The online schema change is always used, LOCK and ALGORITHM are ignored.
+		if spec.Tp != ast.AlterTableLock && spec.Tp != ast.AlterTableAlgorithm {
+			validSpecs = append(validSpecs, spec)
+		}
+	}
+	if len(validSpecs) != 1 {
+		return ErrNotSupportedYet.GenWithStackByArgs("multi schema change")
+	}
+
+	spec := validSpecs[0]
+	switch spec.Tp {
+	case ast.AlterTableAddConstraint:
+		constr := spec.Constraint
+		switch constr.Tp {
+		case ast.ConstraintKey, ast.ConstraintIndex:
+			return d.CreateIndex(ctx, ident, ast.IndexKeyTypeNone, model.NewCIStr(constr.Name),
+				constr.Keys, constr.Option, constr.IfNotExists)
+		case ast.ConstraintUniq, ast.ConstraintUniqIndex, ast.ConstraintUniqKey:
+			return d.CreateIndex(ctx, ident, ast.IndexKeyTypeUnique, model.NewCIStr(constr.Name),
+				constr.Keys, constr.Option, constr.IfNotExists)
+		case ast.ConstraintPrimaryKey:
+			return d.createPrimaryKey(ctx, ident, constr.Keys, constr.Option)
+		case ast.ConstraintFulltext:
+			return ErrNotSupportedYet.GenWithStackByArgs("FULLTEXT index")
+		case ast.ConstraintForeignKey, ast.ConstraintCheck:
+			// Foreign keys and check constraints are parsed but not enforced.
+			return nil
+		}
+	case ast.AlterTableAddColumns:
+		return d.AddColumn(ctx, ident, spec)
+	case ast.AlterTableDropColumn:
+		return d.DropColumn(ctx, ident, spec)
+	case ast.AlterTableModifyColumn:
+		return d.ModifyColumn(ctx, ident, spec)
+	case ast.AlterTableChangeColumn:
+		return d.ChangeColumn(ctx, ident, spec)
+	case ast.AlterTableRenameColumn:
+		return d.RenameColumn(ctx, ident, spec)
+	case ast.AlterTableRenameTable:
+		newIdent := ast.Ident{Schema: spec.NewTable.Schema, Name: spec.NewTable.Name}
+		return d.RenameTables(ctx, []ast.Ident{ident}, []ast.Ident{newIdent}, true)
+	case ast.AlterTableDropIndex:
+		return d.DropIndex(ctx, ident, model.NewCIStr(spec.Name), spec.IfExists)
+	case ast.AlterTableDropPrimaryKey:
+		return d.DropIndex(ctx, ident, model.NewCIStr(mysql.PrimaryKeyName), false)
+	}
+	return ErrNotSupportedYet.GenWithStackByArgs("ALTER TABLE " + alterTableSpecName(spec))
+}
+
+// alterTableSpecName returns the leading words of the restored spec to name it in errors.
+func alterTableSpecName(spec *ast.AlterTableSpec) string {
+	var sb strings.Builder
+	if err := spec.Restore(format.NewRestoreCtx(format.DefaultRestoreFlags, &sb)); err != nil {
+		return "clause"
+	}
+	words := strings.Fields(sb.String())
+	if len(words) > 2 {
+		words = words[:2]
+	}
+	return strings.Join(words, " ")
+}
+
+func (d *ddl) CreateIndex(ctx sessionctx.Context, ti ast.Ident, keyType ast.IndexKeyType, indexName model.CIStr,
+	indexPartSpecifications []*ast.IndexPartSpecification, indexOption *ast.IndexOption, ifNotExists bool) error {
+	switch keyType {
+	case ast.IndexKeyTypeFullText:
+		return ErrNotSupportedYet.GenWithStackByArgs("FULLTEXT index")
+	case ast.IndexKeyTypeSpatial:
+		return ErrNotSupportedYet.GenWithStackByArgs("SPATIAL index")
+	}
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return err
+	}
+	tblInfo := t.Meta()
+	// Deal with anonymous index.
+	if len(indexName.L) == 0 {
+		indexName = model.NewCIStr(genIndexName(tblInfo, indexPartSpecifications[0].Column.Name.O))
+	}
+	if indexInfo := tblInfo.FindIndexByName(indexName.L); indexInfo != nil && ifNotExists {
+		return nil
+	}
+	if err = checkIndexName(tblInfo, indexName.O, false); err != nil {
+		return err
+	}
+	// Check the index before queuing the job, the job builds it again in the worker.
+	if _, err = buildIndexInfo(tblInfo, indexName, indexPartSpecifications, indexOption, model.StateNone); err != nil {
+		return err
+	}
+
+	unique := keyType == ast.IndexKeyTypeUnique
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tblInfo.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionAddIndex,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{unique, indexName, indexPartSpecifications, indexOption},
+	}
+	err = d.doDDLJob(job)
+	if infoschema.ErrIndexExists.Equal(err) && ifNotExists {
+		return nil
+	}
+	return err
+}
+
+func (d *ddl) createPrimaryKey(ctx sessionctx.Context, ti ast.Ident,
+	indexPartSpecifications []*ast.IndexPartSpecification, indexOption *ast.IndexOption) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return err
+	}
+	tblInfo := t.Meta()
+	if tblInfo.PKIsHandle || tblInfo.FindIndexByName(strings.ToLower(mysql.PrimaryKeyName)) != nil {
+		return ErrMultiplePriKey
+	}
+	// The existing rows aren't checked for NULL values, so only NOT NULL columns are allowed.
+	for _, key := range indexPartSpecifications {
+		col := model.FindColumnInfo(tblInfo.Columns, key.Column.Name.L)
+		if col == nil {
+			return ErrKeyColumnDoesNotExits.GenWithStackByArgs(key.Column.Name)
+		}
+		if !mysql.HasNotNullFlag(col.Flag) {
+			return ErrPrimaryCantHaveNull
+		}
+	}
+	indexName := model.NewCIStr(mysql.PrimaryKeyName)
+	if _, err = buildIndexInfo(tblInfo, indexName, indexPartSpecifications, indexOption, model.StateNone); err != nil {
+		return err
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tblInfo.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionAddPrimaryKey,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{true, indexName, indexPartSpecifications, indexOption},
+	}
+	return d.doDDLJob(job)
+}
+
+func (d *ddl) DropIndex(ctx sessionctx.Context, ti ast.Ident, indexName model.CIStr, ifExists bool) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return err
+	}
+	tblInfo := t.Meta()
+	isPK := indexName.L == strings.ToLower(mysql.PrimaryKeyName)
+	if isPK && tblInfo.PKIsHandle {
+		return ErrNotSupportedYet.GenWithStackByArgs("dropping an integer primary key")
+	}
+	indexInfo := tblInfo.FindIndexByName(indexName.L)
+	if indexInfo == nil {
+		if ifExists {
+			return nil
+		}
+		return ErrCantDropFieldOrKey.GenWithStackByArgs(indexName)
+	}
+	if err = checkDropIndexOnAutoIncrementColumn(tblInfo, indexInfo); err != nil {
+		return err
+	}
+
+	jobTp := model.ActionDropIndex
+	if isPK {
+		jobTp = model.ActionDropPrimaryKey
+	}
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tblInfo.ID,
+		SchemaName: schema.Name.L,
+		Type:       jobTp,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{indexName},
+	}
+	err = d.doDDLJob(job)
+	if ErrCantDropFieldOrKey.Equal(err) && ifExists {
+		return nil
+	}
+	return err
+}
+
+// checkDropIndexOnAutoIncrementColumn checks the AUTO_INCREMENT column is still the first column of a key
+// after dropping the index.
+func checkDropIndexOnAutoIncrementColumn(tblInfo *model.TableInfo, indexInfo *model.IndexInfo) error {
+	autoCol := tblInfo.Columns[indexInfo.Columns[0].Offset]
+	if !mysql.HasAutoIncrementFlag(autoCol.Flag) || (tblInfo.PKIsHandle && mysql.HasPriKeyFlag(autoCol.Flag)) {
+		return nil
+	}
+	for _, idx := range tblInfo.Indices {
+		if idx.ID != indexInfo.ID && idx.Columns[0].Offset == autoCol.Offset {
+			return nil
+		}
+	}
+	return ErrWrongAutoKey
+}
+
+// AddColumn adds a column to the table, the existing rows aren't changed and read the origin
+// default value of the column.
+func (d *ddl) AddColumn(ctx sessionctx.Context, ti ast.Ident, spec *ast.AlterTableSpec) error {
+	if len(spec.NewColumns) != 1 {
+		return ErrNotSupportedYet.GenWithStackByArgs("adding multiple columns")
+	}
+	specNewColumn := spec.NewColumns[0]
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return err
+	}
+	tblInfo := t.Meta()
+	colName := specNewColumn.Name.Name
+	if model.FindColumnInfo(tblInfo.Columns, colName.L) != nil {
+		if spec.IfNotExists {
+			return nil
+		}
+		return infoschema.ErrColumnExists.GenWithStackByArgs(colName)
+	}
+	col, constraints, err := buildColumnAndConstraint(len(tblInfo.Columns), specNewColumn, tblInfo)
+	if err != nil {
+		return err
+	}
+	if len(constraints) > 0 {
+		return ErrNotSupportedYet.GenWithStackByArgs("adding a column with key")
+	}
+	if mysql.HasAutoIncrementFlag(col.Flag) {
+		return ErrNotSupportedYet.GenWithStackByArgs("adding an AUTO_INCREMENT column")
+	}
+	if autoRandomOption(specNewColumn) != nil {
+		return ErrNotSupportedYet.GenWithStackByArgs("adding an AUTO_RANDOM column")
+	}
+	if _, err = columnPositionOffset(tblInfo, len(tblInfo.Columns), spec.Position); err != nil {
+		return err
+	}
+	if err = setColumnOriginDefaultValue(col); err != nil {
+		return err
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tblInfo.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionAddColumn,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{col, spec.Position},
+	}
+	err = d.doDDLJob(job)
+	if infoschema.ErrColumnExists.Equal(err) && spec.IfNotExists {
+		return nil
+	}
+	return err
+}
+
+// setColumnOriginDefaultValue sets the value the existing rows get for the added column, it's
+// fixed when the column is added, so CURRENT_TIMESTAMP is evaluated now and a NOT NULL column
+// without default gets the zero value.
+func setColumnOriginDefaultValue(col *model.ColumnInfo) error {
+	switch {
+	case col.OriginDefaultValue == nil && mysql.HasNotNullFlag(col.Flag):
+		col.OriginDefaultValue = datumToString(table.GetZeroValue(col))
+	case col.OriginDefaultValue != nil:
+		d, err := table.GetColOriginDefaultValue(col)
+		if err != nil {
+			return err
+		}
+		col.OriginDefaultValue = datumToString(d)
+	}
+	return nil
+}
+
+// DropColumn drops a column of the table, the column data is left in the rows.
+func (d *ddl) DropColumn(ctx sessionctx.Context, ti ast.Ident, spec *ast.AlterTableSpec) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return err
+	}
+	tblInfo := t.Meta()
+	colName := spec.OldColumnName.Name
+	col := model.FindColumnInfo(tblInfo.Columns, colName.L)
+	if col == nil || col.Hidden {
+		if spec.IfExists {
+			return nil
+		}
+		return ErrCantDropFieldOrKey.GenWithStackByArgs(colName)
+	}
+	if len(tblInfo.Columns) == 1 {
+		return ErrCantRemoveAllFields
+	}
+	if isColumnWithIndex(colName.L, tblInfo.Indices) || (tblInfo.PKIsHandle && mysql.HasPriKeyFlag(col.Flag)) {
+		return ErrNotSupportedYet.GenWithStackByArgs("dropping a column with index")
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tblInfo.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionDropColumn,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{colName},
+	}
+	err = d.doDDLJob(job)
+	if ErrCantDropFieldOrKey.Equal(err) && spec.IfExists {
+		return nil
+	}
+	return err
+}
+
+// ModifyColumn changes the definition of a column, MODIFY COLUMN keeps the column name.
+func (d *ddl) ModifyColumn(ctx sessionctx.Context, ti ast.Ident, spec *ast.AlterTableSpec) error {
+	specNewColumn := spec.NewColumns[0]
+	if len(specNewColumn.Name.Schema.O) != 0 && ti.Schema.L != specNewColumn.Name.Schema.L {
+		return ErrWrongDBName.GenWithStackByArgs(specNewColumn.Name.Schema.O)
+	}
+	if len(specNewColumn.Name.Table.O) != 0 && ti.Name.L != specNewColumn.Name.Table.L {
+		return ErrWrongTableName.GenWithStackByArgs(specNewColumn.Name.Table.O)
+	}
+	return d.modifyColumn(ctx, ti, specNewColumn.Name.Name, specNewColumn, spec.Position)
+}
+
+// ChangeColumn changes the name and the definition of a column.
+func (d *ddl) ChangeColumn(ctx sessionctx.Context, ti ast.Ident, spec *ast.AlterTableSpec) error {
+	specNewColumn := spec.NewColumns[0]
+	if len(spec.OldColumnName.Schema.O) != 0 && ti.Schema.L != spec.OldColumnName.Schema.L {
+		return ErrWrongDBName.GenWithStackByArgs(spec.OldColumnName.Schema.O)
+	}
+	if len(spec.OldColumnName.Table.O) != 0 && ti.Name.L != spec.OldColumnName.Table.L {
+		return ErrWrongTableName.GenWithStackByArgs(spec.OldColumnName.Table.O)
+	}
+	return d.modifyColumn(ctx, ti, spec.OldColumnName.Name, specNewColumn, spec.Position)
+}
+
+// RenameColumn renames a column, its definition is kept.
+func (d *ddl) RenameColumn(ctx sessionctx.Context, ti ast.Ident, spec *ast.AlterTableSpec) error {
+	oldColName, newColName := spec.OldColumnName.Name, spec.NewColumnName.Name
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return err
+	}
+	tblInfo := t.Meta()
+	oldCol := model.FindColumnInfo(tblInfo.Columns, oldColName.L)
+	if oldCol == nil || oldCol.Hidden {
+		return infoschema.ErrColumnNotExists.GenWithStackByArgs(oldColName, ti.Name)
+	}
+	if oldColName.L == newColName.L {
+		return nil
+	}
+	if err = checkIdentName(newColName.O, ErrWrongColumnName); err != nil {
+		return err
+	}
+	if model.FindColumnInfo(tblInfo.Columns, newColName.L) != nil {
+		return infoschema.ErrColumnExists.GenWithStackByArgs(newColName)
+	}
+
+	newCol := oldCol.Clone()
+	newCol.Name = newColName
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tblInfo.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionModifyColumn,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{newCol, oldColName, &ast.ColumnPosition{Tp: ast.ColumnPositionNone}},
+	}
+	return d.doDDLJob(job)
+}
+
+func (d *ddl) modifyColumn(ctx sessionctx.Context, ti ast.Ident, oldColName model.CIStr,
+	specNewColumn *ast.ColumnDef, pos *ast.ColumnPosition) error {
+	schema, t, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return err
+	}
+	newCol, err := getModifiableColumn(t.Meta(), oldColName, specNewColumn, pos)
+	if err != nil {
+		return err
+	}
+	if pos == nil {
+		pos = &ast.ColumnPosition{Tp: ast.ColumnPositionNone}
+	}
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    t.Meta().ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionModifyColumn,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{newCol, oldColName, pos},
+	}
+	return d.doDDLJob(job)
+}
+
+// getModifiableColumn builds the new column info of MODIFY/CHANGE COLUMN and checks the change is supported.
+func getModifiableColumn(tblInfo *model.TableInfo, oldColName model.CIStr, specNewColumn *ast.ColumnDef,
+	pos *ast.ColumnPosition) (*model.ColumnInfo, error) {
+	oldCol := model.FindColumnInfo(tblInfo.Columns, oldColName.L)
+	if oldCol == nil || oldCol.Hidden {
+		return nil, infoschema.ErrColumnNotExists.GenWithStackByArgs(oldColName, tblInfo.Name)
+	}
+	newColName := specNewColumn.Name.Name
+	if newColName.L != oldColName.L && model.FindColumnInfo(tblInfo.Columns, newColName.L) != nil {
+		return nil, infoschema.ErrColumnExists.GenWithStackByArgs(newColName)
+	}
+	if pos != nil && pos.Tp == ast.ColumnPositionAfter && pos.RelativeColumn.Name.L == oldColName.L {
+		return nil, infoschema.ErrColumnNotExists.GenWithStackByArgs(pos.RelativeColumn, tblInfo.Name)
+	}
+	if _, err := columnPositionOffset(tblInfo, oldCol.Offset, pos); err != nil {
+		return nil, err
+	}
+
+	// The column charset is inherited from the old column if it's not specified.
+	if types.HasCharset(specNewColumn.Tp) && specNewColumn.Tp.Charset == "" && types.HasCharset(&oldCol.FieldType) {
+		specNewColumn.Tp.Charset, specNewColumn.Tp.Collate = oldCol.Charset, oldCol.Collate
+	}
+	newCol, constraints, err := buildColumnAndConstraint(oldCol.Offset, specNewColumn, tblInfo)
+	if err != nil {
+		return nil, err
+	}
+	if len(constraints) > 0 {
+		return nil, ErrNotSupportedYet.GenWithStackByArgs("modifying a column with key")
+	}
+	if mysql.HasAutoIncrementFlag(newCol.Flag) != mysql.HasAutoIncrementFlag(oldCol.Flag) {
+		return nil, errUnsupportedModifyColumn.GenWithStackByArgs("can't set or remove AUTO_INCREMENT")
+	}
+	// The AUTO_RANDOM column keeps its shard bits.
+	isAutoRandomCol := tblInfo.ContainsAutoRandomBits() && tblInfo.PKIsHandle && mysql.HasPriKeyFlag(oldCol.Flag)
+	if opt := autoRandomOption(specNewColumn); (opt != nil) != isAutoRandomCol ||
+		(opt != nil && opt.AutoRandomBitLength != types.UnspecifiedLength && uint64(opt.AutoRandomBitLength) != tblInfo.AutoRandomBits) {
+		return nil, errUnsupportedModifyColumn.GenWithStackByArgs("can't set, remove or change AUTO_RANDOM")
+	}
+	// The key flags belong to the indices of the column, the primary key columns are always NOT NULL.
+	newCol.Flag |= oldCol.Flag & (mysql.PriKeyFlag | mysql.UniqueKeyFlag | mysql.MultipleKeyFlag)
+	if mysql.HasPriKeyFlag(newCol.Flag) {
+		newCol.Flag |= mysql.NotNullFlag
+	}
+	hasIndex := isColumnWithIndex(oldColName.L, tblInfo.Indices) || (tblInfo.PKIsHandle && mysql.HasPriKeyFlag(oldCol.Flag))
+	if _, err = checkModifyTypes(&oldCol.FieldType, &newCol.FieldType, hasIndex); err != nil {
+		return nil, err
+	}
+	if hasIndex {
+		if err = checkModifiedIndexColumns(tblInfo, oldColName, newCol); err != nil {
+			return nil, err
+		}
+	}
+
+	// The rows without the column read the origin default value, it's converted to the new type.
+	newCol.OriginDefaultValue = oldCol.OriginDefaultValue
+	if s, ok := oldCol.OriginDefaultValue.(string); ok {
+		if newCol.OriginDefaultValue, err = convertDefaultValue(&newCol.FieldType, s); err != nil {
+			return nil, errUnsupportedModifyColumn.GenWithStackByArgs(
+				fmt.Sprintf("origin default value %s can't be converted to %s", s, newCol.CompactStr()))
+		}
+	}
+	return newCol, nil
+}
+
+// checkModifiedIndexColumns checks the indices of the column are still valid with the new column type.
+func checkModifiedIndexColumns(tblInfo *model.TableInfo, oldColName model.CIStr, newCol *model.ColumnInfo) error {
+	for _, idx := range tblInfo.Indices {
+		keyLength := 0
+		for _, idxCol := range idx.Columns {
+			prefixLength := idxCol.Length
+			if prefixLength == types.UnspecifiedLength {
+				prefixLength = 0
+			}
+			col := tblInfo.Columns[idxCol.Offset]
+			if idxCol.Name.L == oldColName.L {
+				col = newCol
+				if err := checkIndexColumn(col, prefixLength); err != nil {
+					return err
+				}
+			}
+			keyLength += indexColumnLength(col, prefixLength)
+		}
+		if keyLength > maxKeyLength {
+			return ErrTooLongKey.GenWithStackByArgs(maxKeyLength)
+		}
+	}
+	return nil
+}
+
+// TruncateTable replaces the table with an empty one with a new ID, the data of the old table
+// is deleted by GC later.
+func (d *ddl) TruncateTable(ctx sessionctx.Context, ti ast.Ident) error {
+	schema, tb, err := d.getSchemaAndTableByIdent(ctx, ti)
+	if err != nil {
+		return err
+	}
+	newTableID, err := d.genGlobalID()
+	if err != nil {
+		return err
+	}
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tb.Meta().ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionTruncateTable,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{newTableID},
+	}
+	return d.doDDLJob(job)
+}
+
+// RenameTables renames the tables in one job, so the renames are done atomically. The renames
+// are applied in order, e.g. a TO tmp, b TO a, tmp TO b swaps the tables a and b.
+func (d *ddl) RenameTables(ctx sessionctx.Context, oldIdents, newIdents []ast.Ident, isAlterTable bool) error {
+	is := d.infoSchema()
+	// tableIDs maps the schema ID and the table name to the table ID as the renames are done.
+	type tableName struct {
+		schemaID int64
+		name     string
+	}
+	tableIDs := make(map[tableName]int64)
+	tableExists := func(schemaID int64, name model.CIStr) (int64, bool) {
+		if id, ok := tableIDs[tableName{schemaID, name.L}]; ok {
+			return id, id != 0
+		}
+		schema, _ := is.SchemaByID(schemaID)
+		tb, err := is.TableByName(schema.Name, name)
+		if err != nil {
+			return 0, false
+		}
+		return tb.Meta().ID, true
+	}
+
+	var (
+		oldSchemaIDs  = make([]int64, 0, len(oldIdents))
+		newSchemaIDs  = make([]int64, 0, len(oldIdents))
+		newTableNames = make([]model.CIStr, 0, len(oldIdents))
+		ids           = make([]int64, 0, len(oldIdents))
+		schemaName    string
+	)
+	for i := range oldIdents {
+		oldIdent, newIdent := oldIdents[i], newIdents[i]
+		if oldIdent.Schema.L == "" || newIdent.Schema.L == "" {
+			if ctx.GetSessionVars().CurrentDB == "" {
+				return ErrNoDB
+			}
+			currentDB := model.NewCIStr(ctx.GetSessionVars().CurrentDB)
+			if oldIdent.Schema.L == "" {
+				oldIdent.Schema = currentDB
+			}
+			if newIdent.Schema.L == "" {
+				newIdent.Schema = currentDB
+			}
+		}
+		oldSchema, ok := is.SchemaByName(oldIdent.Schema)
+		if !ok {
+			return infoschema.ErrTableNotExists.GenWithStackByArgs(oldIdent.Schema, oldIdent.Name)
+		}
+		tableID, ok := tableExists(oldSchema.ID, oldIdent.Name)
+		if !ok {
+			return infoschema.ErrTableNotExists.GenWithStackByArgs(oldIdent.Schema, oldIdent.Name)
+		}
+		if isAlterTable && newIdent.Schema.L == oldIdent.Schema.L && newIdent.Name.L == oldIdent.Name.L {
+			// ALTER TABLE t RENAME TO t does nothing.
+			return nil
+		}
+		newSchema, ok := is.SchemaByName(newIdent.Schema)
+		if !ok {
+			return ErrErrorOnRename.GenWithStackByArgs(
+				fmt.Sprintf("%s.%s", oldIdent.Schema, oldIdent.Name),
+				fmt.Sprintf("%s.%s", newIdent.Schema, newIdent.Name),
+				168, "Database doesn't exist")
+		}
+		if _, ok = tableExists(newSchema.ID, newIdent.Name); ok {
+			return infoschema.ErrTableExists.GenWithStackByArgs(newIdent.Name)
+		}
+		if err := checkIdentName(newIdent.Name.O, ErrWrongTableName); err != nil {
+			return err
+		}
+		tableIDs[tableName{oldSchema.ID, oldIdent.Name.L}] = 0
+		tableIDs[tableName{newSchema.ID, newIdent.Name.L}] = tableID
+
+		oldSchemaIDs = append(oldSchemaIDs, oldSchema.ID)
+		newSchemaIDs = append(newSchemaIDs, newSchema.ID)
+		newTableNames = append(newTableNames, newIdent.Name)
+		ids = append(ids, tableID)
+		if i == 0 {
+			schemaName = newSchema.Name.L
+		}
+	}
+
+	job := &model.Job{
+		SchemaID:   newSchemaIDs[0],
+		TableID:    ids[0],
+		SchemaName: schemaName,
+		Type:       model.ActionRenameTable,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{oldSchemaIDs, newSchemaIDs, newTableNames, ids},
+	}
+	return d.doDDLJob(job)
+}
+
+// RecoverTable restores the dropped or truncated table with its old table ID, its data must
+// not be deleted by GC yet.
+func (d *ddl) RecoverTable(ctx sessionctx.Context, recoverInfo *RecoverInfo) error {
+	is := d.infoSchema()
+	tbInfo := recoverInfo.TableInfo
+	schema, ok := is.SchemaByID(recoverInfo.SchemaID)
+	if !ok {
+		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(
+			fmt.Sprintf("(Schema ID %d)", recoverInfo.SchemaID))
+	}
+	if tb, ok := is.TableByID(tbInfo.ID); ok {
+		return infoschema.ErrTableExists.GenWithStack("Table '%-.192s' already been recover to '%-.192s', can't be recover repeatedly",
+			tbInfo.Name.O, tb.Meta().Name.O)
+	}
+	if is.TableExists(schema.Name, tbInfo.Name) {
+		return infoschema.ErrTableExists.GenWithStackByArgs(tbInfo.Name)
+	}
+	if err := checkIdentName(tbInfo.Name.O, ErrWrongTableName); err != nil {
+		return err
+	}
+
+	job := &model.Job{
+		SchemaID:   schema.ID,
+		TableID:    tbInfo.ID,
+		SchemaName: schema.Name.L,
+		Type:       model.ActionRecoverTable,
+		BinlogInfo: &model.HistoryInfo{},
+		Args:       []interface{}{tbInfo, recoverInfo.DropJobID},
+	}
+	return d.doDDLJob(job)
+}
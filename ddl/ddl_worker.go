@@ -0,0 +1,306 @@
+package ddl
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/terror"
+	"grant-db/kv"
+	"grant-db/meta"
+)
+
+const (
+	// checkJobInterval is the interval to check the job queue when no job is notified,
+	// it also backs off a job which failed with a retryable error.
+	checkJobInterval = time.Second
+	// waitJobInterval is the interval a client checks whether its job is done.
+	waitJobInterval = 50 * time.Millisecond
+	// waitSchemaInterval is the interval the worker checks whether the transactions use the new schema.
+	waitSchemaInterval = 10 * time.Millisecond
+)
+
+// start starts the worker which runs the jobs of the queue in order.
+func (d *ddl) start() {
+	d.wg.Add(1)
+	go d.run()
+	d.notifyWorker()
+}
+
+func (d *ddl) run() {
+	defer d.wg.Done()
+	ticker := time.NewTicker(checkJobInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-d.ddlJobCh:
+		case <-d.quitCh:
+			return
+		}
+		if err := d.handleDDLJobQueue(); err != nil {
+			log.Printf("[ddl] handle DDL job failed: %v", err)
+		}
+	}
+}
+
+func (d *ddl) notifyWorker() {
+	select {
+	case d.ddlJobCh <- struct{}{}:
+	default:
+	}
+}
+
+func (d *ddl) isClosed() bool {
+	select {
+	case <-d.quitCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// doDDLJob puts the job into the queue and waits until the worker finishes it.
+func (d *ddl) doDDLJob(job *model.Job) error {
+	if d.isClosed() {
+		return errWorkerClosed
+	}
+	err := kv.RunInNewTxn(d.store, true, func(txn kv.Transaction) error {
+		t := meta.NewMeta(txn)
+		var err error
+		job.ID, err = t.GenGlobalID()
+		if err != nil {
+			return err
+		}
+		job.StartTS = txn.StartTS()
+		job.State = model.JobStateNone
+		return t.EnQueueDDLJob(job)
+	})
+	if err != nil {
+		return err
+	}
+	d.notifyWorker()
+
+	ticker := time.NewTicker(waitJobInterval)
+	defer ticker.Stop()
+	for {
+		historyJob, err := d.getHistoryDDLJob(job.ID)
+		if err != nil {
+			return err
+		}
+		if historyJob != nil {
+			if historyJob.Error != nil && (historyJob.IsCancelled() || historyJob.IsRollbackDone()) {
+				return historyJob.Error
+			}
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-d.quitCh:
+			return errWorkerClosed
+		}
+	}
+}
+
+func (d *ddl) getHistoryDDLJob(id int64) (*model.Job, error) {
+	ver, err := d.store.CurrentVersion()
+	if err != nil {
+		return nil, err
+	}
+	return meta.NewSnapshotMeta(d.store.GetSnapshot(ver)).GetHistoryDDLJob(id)
+}
+
+// handleDDLJobQueue runs the jobs in the queue step by step until the queue is empty.
+// Every step runs in its own transaction, the schema is reloaded once a step changes it, and the next step
+// isn't run until the transactions in progress use the new schema. So at most two adjacent schema versions
+// are used at the same time, which keeps the data consistent in the online schema change. A finished job
+// which changes the schema is removed from the queue after the wait too, so the client sees the new schema
+// in all the transactions begun after the job.
+func (d *ddl) handleDDLJobQueue() error {
+	for !d.isClosed() {
+		var (
+			job           *model.Job
+			schemaChanged bool
+			runJobErr     error
+		)
+		err := kv.RunInNewTxn(d.store, false, func(txn kv.Transaction) error {
+			t := meta.NewMeta(txn)
+			var err error
+			job, err = t.GetFirstDDLJob()
+			if job == nil || err != nil {
+				return err
+			}
+			if job.IsFinished() {
+				return d.finishDDLJob(t, job)
+			}
+			oldVer, err := t.GetSchemaVersion()
+			if err != nil {
+				return err
+			}
+			oldState := job.State
+			runJobErr = d.runDDLJob(t, job)
+			if isTransientJobErr(oldState, job, runJobErr) {
+				// Discard the step, the job will be run again.
+				return runJobErr
+			}
+			newVer, err := t.GetSchemaVersion()
+			if err != nil {
+				return err
+			}
+			schemaChanged = newVer != oldVer
+			if job.IsFinished() {
+				// The table info right before a dropping job is read at the finished TS.
+				job.BinlogInfo.FinishedTS = t.StartTS
+				if !schemaChanged {
+					return d.finishDDLJob(t, job)
+				}
+			}
+			return t.UpdateDDLJob(job, runJobErr != nil)
+		})
+		if kv.IsTxnRetryableError(err) {
+			// The job is updated by others, e.g. cancelled by the client, run it again.
+			continue
+		}
+		if err != nil {
+			if err == runJobErr {
+				log.Printf("[ddl] run DDL job %d failed, it will be retried: %v", job.ID, err)
+				return nil
+			}
+			return err
+		}
+		if job == nil {
+			return nil
+		}
+		if schemaChanged {
+			if err := d.loader.Reload(); err != nil {
+				return err
+			}
+			d.waitSchemaSynced(d.loader.InfoSchema().SchemaMetaVersion())
+		}
+	}
+	return nil
+}
+
+// waitSchemaSynced waits until the transactions in progress use the schema of ver or a later one, or until
+// the lease expires, after which the transactions still using an older schema fail to commit the changed
+// tables.
+func (d *ddl) waitSchemaSynced(ver int64) {
+	ticker := time.NewTicker(waitSchemaInterval)
+	defer ticker.Stop()
+	timeout := time.After(d.options.Lease)
+	for {
+		if oldest := d.loader.OldestSchemaVersion(); oldest == 0 || oldest >= ver {
+			return
+		}
+		select {
+		case <-ticker.C:
+		case <-timeout:
+			log.Printf("[ddl] wait for the transactions to use schema version %d timed out", ver)
+			return
+		case <-d.quitCh:
+			return
+		}
+	}
+}
+
+// finishDDLJob removes the finished job from the queue and adds it to the history, a done job is synced
+// since the transactions use its schema.
+func (d *ddl) finishDDLJob(t *meta.Meta, job *model.Job) error {
+	if job.IsDone() {
+		job.State = model.JobStateSynced
+	}
+	if job.BinlogInfo.FinishedTS == 0 {
+		job.BinlogInfo.FinishedTS = t.StartTS
+	}
+	if err := t.DeQueueDDLJob(job.ID); err != nil {
+		return err
+	}
+	if err := t.RemoveDDLReorgHandle(job); err != nil {
+		return err
+	}
+	return t.AddHistoryDDLJob(job)
+}
+
+// runDDLJob runs a step of the job in the transaction of t.
+func (d *ddl) runDDLJob(t *meta.Meta, job *model.Job) (err error) {
+	if job.IsCancelling() {
+		return convertJob2RollbackJob(t, job)
+	}
+	isRollingback := job.IsRollingback()
+	if !isRollingback {
+		job.State = model.JobStateRunning
+	}
+	switch job.Type {
+	case model.ActionCreateSchema:
+		err = onCreateSchema(t, job)
+	case model.ActionDropSchema:
+		err = onDropSchema(t, job)
+	case model.ActionCreateTable:
+		err = onCreateTable(t, job)
+	case model.ActionDropTable:
+		err = onDropTable(t, job)
+	case model.ActionTruncateTable:
+		err = onTruncateTable(t, job)
+	case model.ActionRenameTable:
+		err = onRenameTables(t, job)
+	case model.ActionRecoverTable:
+		err = onRecoverTable(t, job)
+	case model.ActionAddIndex, model.ActionAddPrimaryKey:
+		err = d.onCreateIndex(t, job)
+	case model.ActionDropIndex, model.ActionDropPrimaryKey:
+		err = d.onDropIndex(t, job)
+	case model.ActionAddColumn:
+		err = onAddColumn(t, job)
+	case model.ActionDropColumn:
+		err = onDropColumn(t, job)
+	case model.ActionModifyColumn:
+		err = d.onModifyColumn(t, job)
+	default:
+		// Invalid job, cancel it.
+		job.State = model.JobStateCancelled
+		err = ErrNotSupportedYet.GenWithStackByArgs(fmt.Sprintf("DDL job type %s", job.Type))
+	}
+	// Save the error which cancels the job or rolls back the job.
+	if err != nil && (job.IsCancelled() || job.IsRollbackDone() || (job.IsRollingback() && !isRollingback)) {
+		job.Error = toTError(err)
+	}
+	return err
+}
+
+// isTransientJobErr checks whether the step failed without changing the state of the job,
+// such a step is discarded and run again later.
+func isTransientJobErr(oldState model.JobState, job *model.Job, err error) bool {
+	if err == nil || errWaitReorg.Equal(err) {
+		return false
+	}
+	return job.IsRunning() || job.IsCancelling() || (oldState == model.JobStateRollingback && job.IsRollingback())
+}
+
+func toTError(err error) *terror.Error {
+	originErr := errors.Cause(err)
+	if tErr, ok := originErr.(*terror.Error); ok {
+		return tErr
+	}
+	// TODO: Add the error code.
+	return terror.ClassDDL.Synthesize(terror.CodeUnknown, err.Error())
+}
+
+// updateSchemaVersion increments the schema version by 1.
+func updateSchemaVersion(t *meta.Meta, job *model.Job) (int64, error) {
+	return t.GenSchemaVersion()
+}
+
+// updateVersionAndTableInfo updates the table info and bumps the schema version if shouldUpdateVer is true.
+func updateVersionAndTableInfo(t *meta.Meta, job *model.Job, tblInfo *model.TableInfo, shouldUpdateVer bool) (
+	ver int64, err error) {
+	if shouldUpdateVer {
+		ver, err = updateSchemaVersion(t, job)
+		if err != nil {
+			return 0, err
+		}
+	}
+	tblInfo.UpdateTS = t.StartTS
+	return ver, t.UpdateTable(job.SchemaID, tblInfo)
+}
@@ -0,0 +1,128 @@
+package ddl
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/pingcap/parser/model"
+	"grant-db/kv"
+	"grant-db/meta"
+	"grant-db/oracle"
+	"grant-db/tablecodec"
+)
+
+// addTableDelRange adds the range of the table data to be deleted by GC, the table can be
+// recovered before the range is deleted.
+func addTableDelRange(t *meta.Meta, job *model.Job, tableID int64) error {
+	prefix := tablecodec.EncodeTablePrefix(tableID)
+	return t.AddDelRange(&meta.DelRangeTask{
+		JobID:     job.ID,
+		ElementID: tableID,
+		StartKey:  prefix,
+		EndKey:    prefix.PrefixNext(),
+		TS:        t.StartTS,
+	})
+}
+
+// deleteKeysInRange deletes at most limit keys from startKey, it returns the next key to
+// delete or nil if the range is empty.
+func deleteKeysInRange(txn kv.Transaction, startKey, endKey kv.Key, limit int) (kv.Key, error) {
+	it, err := txn.Iter(startKey, endKey)
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	for count := 0; it.Valid(); count++ {
+		if count == limit {
+			return it.Key().Clone(), nil
+		}
+		if err = txn.Delete(it.Key()); err != nil {
+			return nil, err
+		}
+		if err = it.Next(); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// startGCWorker starts the worker which advances the GC safe point, deletes the ranges older than it
+// and cleans up the versions before it in the store.
+func (d *ddl) startGCWorker() {
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		ticker := time.NewTicker(d.options.GCRunInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-d.quitCh:
+				return
+			}
+			if err := d.runGC(oracle.GoTimeToTS(time.Now().Add(-d.options.GCLifeTime))); err != nil {
+				log.Printf("[ddl] run GC failed: %v", err)
+			}
+		}
+	}()
+}
+
+// runGC sets the GC safe point, deletes the ranges left before it and cleans up the versions before it.
+func (d *ddl) runGC(safePoint uint64) error {
+	var tasks []*meta.DelRangeTask
+	err := kv.RunInNewTxn(d.store, true, func(txn kv.Transaction) error {
+		t := meta.NewMeta(txn)
+		oldSafePoint, err := t.GetGCSafePoint()
+		if err != nil {
+			return err
+		}
+		if safePoint > oldSafePoint {
+			if err = t.SetGCSafePoint(safePoint); err != nil {
+				return err
+			}
+		}
+		tasks, err = t.ListDelRanges()
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	for _, task := range tasks {
+		if task.TS >= safePoint {
+			continue
+		}
+		if err = d.doDelRangeTask(task); err != nil {
+			return err
+		}
+	}
+	return d.store.GC(context.Background(), safePoint)
+}
+
+// doDelRangeTask removes the keys of the range from the store, then removes the task. The task is written
+// before, so a recovery of the table which is begun before the GC safe point is advanced conflicts with it,
+// and the later ones find the task older than the safe point.
+func (d *ddl) doDelRangeTask(task *meta.DelRangeTask) error {
+	err := kv.RunInNewTxn(d.store, false, func(txn kv.Transaction) error {
+		t := meta.NewMeta(txn)
+		var err error
+		if task, err = t.GetDelRange(task.JobID, task.ElementID); err != nil || task == nil {
+			return err
+		}
+		return t.AddDelRange(task)
+	})
+	if err != nil || task == nil {
+		return err
+	}
+	if err = d.store.DeleteRange(context.Background(), task.StartKey, task.EndKey); err != nil {
+		return err
+	}
+	return kv.RunInNewTxn(d.store, true, func(txn kv.Transaction) error {
+		t := meta.NewMeta(txn)
+		// The statistics of the table are useless once its data is gone.
+		if err := t.DropStats(task.ElementID); err != nil {
+			return err
+		}
+		return t.RemoveDelRange(task.JobID, task.ElementID)
+	})
+}
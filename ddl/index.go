@@ -0,0 +1,295 @@
+package ddl
+
+import (
+	"time"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/infoschema"
+	"grant-db/kv"
+	"grant-db/meta"
+	"grant-db/table"
+	"grant-db/table/tables"
+	"grant-db/tablecodec"
+	"grant-db/types"
+)
+
+// setIndexColumnFlag sets the key flags of the index columns, it's called when the index becomes public.
+func setIndexColumnFlag(tblInfo *model.TableInfo, indexInfo *model.IndexInfo) {
+	col := tblInfo.Columns[indexInfo.Columns[0].Offset]
+	switch {
+	case indexInfo.Primary:
+		for _, idxCol := range indexInfo.Columns {
+			tblInfo.Columns[idxCol.Offset].Flag |= mysql.PriKeyFlag
+		}
+	case indexInfo.Unique && len(indexInfo.Columns) == 1:
+		col.Flag |= mysql.UniqueKeyFlag
+	default:
+		col.Flag |= mysql.MultipleKeyFlag
+	}
+}
+
+// dropIndexColumnFlag clears the key flags of the dropped index columns, the flags of the other
+// public indices are set again.
+func dropIndexColumnFlag(tblInfo *model.TableInfo, indexInfo *model.IndexInfo) {
+	col := tblInfo.Columns[indexInfo.Columns[0].Offset]
+	switch {
+	case indexInfo.Primary:
+		for _, idxCol := range indexInfo.Columns {
+			tblInfo.Columns[idxCol.Offset].Flag &= ^mysql.PriKeyFlag
+		}
+	case indexInfo.Unique && len(indexInfo.Columns) == 1:
+		col.Flag &= ^mysql.UniqueKeyFlag
+	default:
+		col.Flag &= ^mysql.MultipleKeyFlag
+	}
+	for _, idx := range tblInfo.Indices {
+		if idx.State == model.StatePublic && idx.ID != indexInfo.ID {
+			setIndexColumnFlag(tblInfo, idx)
+		}
+	}
+}
+
+func getTableInfoAndCancelFaultJob(t *meta.Meta, job *model.Job, schemaID int64) (*model.TableInfo, error) {
+	tblInfo, err := getTableInfo(t, job, schemaID)
+	if err != nil {
+		job.State = model.JobStateCancelled
+		return nil, err
+	}
+	if tblInfo.State != model.StatePublic {
+		job.State = model.JobStateCancelled
+		return nil, errInvalidDDLState.GenWithStackByArgs("table", tblInfo.State)
+	}
+	return tblInfo, nil
+}
+
+func (d *ddl) onCreateIndex(t *meta.Meta, job *model.Job) error {
+	// Handle the rolling back job.
+	if job.IsRollingback() {
+		return d.onDropIndex(t, job)
+	}
+
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return err
+	}
+	var (
+		unique      bool
+		indexName   model.CIStr
+		idxParts    []*ast.IndexPartSpecification
+		indexOption *ast.IndexOption
+	)
+	if err = job.DecodeArgs(&unique, &indexName, &idxParts, &indexOption); err != nil {
+		job.State = model.JobStateCancelled
+		return err
+	}
+
+	indexInfo := tblInfo.FindIndexByName(indexName.L)
+	if indexInfo != nil && indexInfo.State == model.StatePublic {
+		job.State = model.JobStateCancelled
+		return infoschema.ErrIndexExists.GenWithStackByArgs(indexName)
+	}
+	if indexInfo == nil {
+		indexInfo, err = buildIndexInfo(tblInfo, indexName, idxParts, indexOption, model.StateNone)
+		if err != nil {
+			job.State = model.JobStateCancelled
+			return err
+		}
+		tblInfo.MaxIndexID++
+		indexInfo.ID = tblInfo.MaxIndexID
+		indexInfo.Unique = unique
+		indexInfo.Primary = job.Type == model.ActionAddPrimaryKey
+		tblInfo.Indices = append(tblInfo.Indices, indexInfo)
+	}
+
+	originalState := indexInfo.State
+	switch indexInfo.State {
+	case model.StateNone:
+		// none -> delete only
+		indexInfo.State = model.StateDeleteOnly
+		if _, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != indexInfo.State); err != nil {
+			return err
+		}
+		job.SchemaState = model.StateDeleteOnly
+	case model.StateDeleteOnly:
+		// delete only -> write only
+		indexInfo.State = model.StateWriteOnly
+		if _, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != indexInfo.State); err != nil {
+			return err
+		}
+		job.SchemaState = model.StateWriteOnly
+	case model.StateWriteOnly:
+		// write only -> reorganization
+		indexInfo.State = model.StateWriteReorganization
+		if _, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != indexInfo.State); err != nil {
+			return err
+		}
+		job.SchemaState = model.StateWriteReorganization
+	case model.StateWriteReorganization:
+		// reorganization -> public
+		tbl, err := tables.TableFromMeta(nil, tblInfo)
+		if err != nil {
+			return err
+		}
+		err = d.addTableIndex(t, job, tbl, indexInfo)
+		if err != nil {
+			if kv.ErrKeyExists.Equal(err) || errCancelledDDLJob.Equal(err) {
+				return convertAddIdxJob2RollbackJob(t, job, tblInfo, indexInfo, err)
+			}
+			return err
+		}
+
+		indexInfo.State = model.StatePublic
+		setIndexColumnFlag(tblInfo, indexInfo)
+		ver, err := updateVersionAndTableInfo(t, job, tblInfo, originalState != indexInfo.State)
+		if err != nil {
+			return err
+		}
+		job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	default:
+		err = errInvalidDDLState.GenWithStackByArgs("index", indexInfo.State)
+	}
+	return err
+}
+
+// addTableIndex backfills the index entries of the existing rows.
+func (d *ddl) addTableIndex(t *meta.Meta, job *model.Job, tbl table.Table, indexInfo *model.IndexInfo) error {
+	idx := tables.NewIndex(tbl.Meta(), indexInfo)
+	return d.runReorgJob(t, job, tbl, func(startKey kv.Key) (kv.Key, int64, error) {
+		return d.backfillIndexBatch(job.ID, tbl, idx, startKey)
+	})
+}
+
+// backfillIndexBatch adds the index entries of at most reorgBatchSize rows from startKey in a
+// transaction, it returns the key of the next row or nil if all the rows are done.
+func (d *ddl) backfillIndexBatch(jobID int64, tbl table.Table, idx table.Index, startKey kv.Key) (
+	nextKey kv.Key, count int64, err error) {
+	err = kv.RunInNewTxn(d.store, true, func(txn kv.Transaction) error {
+		nextKey, count = nil, 0
+		if err := checkReorgJob(txn, jobID); err != nil {
+			return err
+		}
+
+		var idxVals []types.Datum
+		return tbl.IterRecords(txn, startKey, tbl.DeletableCols(),
+			func(h int64, rec []types.Datum, cols []*table.Column) (bool, error) {
+				if count == reorgBatchSize {
+					nextKey = tbl.RecordKey(h)
+					return false, nil
+				}
+				idxVals, err = idx.FetchValues(rec, idxVals)
+				if err != nil {
+					return false, err
+				}
+				if err = idx.Create(txn, idxVals, h); err != nil {
+					return false, err
+				}
+				// Lock the row, the batch is retried if the row is changed concurrently.
+				if err = txn.LockKeys(tbl.RecordKey(h)); err != nil {
+					return false, err
+				}
+				count++
+				return true, nil
+			})
+	})
+	return nextKey, count, err
+}
+
+func checkDropIndex(t *meta.Meta, job *model.Job) (*model.TableInfo, *model.IndexInfo, error) {
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return nil, nil, err
+	}
+	var indexName model.CIStr
+	if err = job.DecodeArgs(&indexName); err != nil {
+		job.State = model.JobStateCancelled
+		return nil, nil, err
+	}
+	indexInfo := tblInfo.FindIndexByName(indexName.L)
+	if indexInfo == nil {
+		job.State = model.JobStateCancelled
+		return nil, nil, ErrCantDropFieldOrKey.GenWithStackByArgs(indexName)
+	}
+	return tblInfo, indexInfo, nil
+}
+
+// onDropIndex drops the index, it also rolls back an ADD INDEX job from delete only.
+func (d *ddl) onDropIndex(t *meta.Meta, job *model.Job) error {
+	tblInfo, indexInfo, err := checkDropIndex(t, job)
+	if err != nil {
+		return err
+	}
+
+	originalState := indexInfo.State
+	switch indexInfo.State {
+	case model.StatePublic:
+		// public -> write only
+		indexInfo.State = model.StateWriteOnly
+		if _, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != indexInfo.State); err != nil {
+			return err
+		}
+		job.SchemaState = model.StateWriteOnly
+	case model.StateWriteOnly:
+		// write only -> delete only
+		indexInfo.State = model.StateDeleteOnly
+		if _, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != indexInfo.State); err != nil {
+			return err
+		}
+		job.SchemaState = model.StateDeleteOnly
+	case model.StateDeleteOnly:
+		// delete only -> reorganization
+		indexInfo.State = model.StateDeleteReorganization
+		if _, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != indexInfo.State); err != nil {
+			return err
+		}
+		job.SchemaState = model.StateDeleteReorganization
+	case model.StateDeleteReorganization:
+		// reorganization -> absent
+		if err = d.dropTableIndex(tblInfo, indexInfo); err != nil {
+			return err
+		}
+		newIndices := make([]*model.IndexInfo, 0, len(tblInfo.Indices))
+		for _, idx := range tblInfo.Indices {
+			if idx.ID != indexInfo.ID {
+				newIndices = append(newIndices, idx)
+			}
+		}
+		tblInfo.Indices = newIndices
+		dropIndexColumnFlag(tblInfo, indexInfo)
+
+		ver, err := updateVersionAndTableInfo(t, job, tblInfo, true)
+		if err != nil {
+			return err
+		}
+		if job.IsRollingback() {
+			job.FinishTableJob(model.JobStateRollbackDone, model.StateNone, ver, tblInfo)
+		} else {
+			job.FinishTableJob(model.JobStateDone, model.StateNone, ver, tblInfo)
+		}
+	default:
+		err = errInvalidDDLState.GenWithStackByArgs("index", indexInfo.State)
+	}
+	return err
+}
+
+// dropTableIndex deletes the index entries in batches, errWaitReorg is returned if it doesn't
+// finish in reorgWaitTimeout.
+func (d *ddl) dropTableIndex(tblInfo *model.TableInfo, indexInfo *model.IndexInfo) error {
+	prefix := tablecodec.EncodeTableIndexPrefix(tblInfo.ID, indexInfo.ID)
+	deadline := time.Now().Add(reorgWaitTimeout)
+	for {
+		var nextKey kv.Key
+		err := kv.RunInNewTxn(d.store, true, func(txn kv.Transaction) error {
+			var err error
+			nextKey, err = deleteKeysInRange(txn, prefix, prefix.PrefixNext(), reorgBatchSize)
+			return err
+		})
+		if err != nil || nextKey == nil {
+			return err
+		}
+		if d.isClosed() || time.Now().After(deadline) {
+			return errWaitReorg
+		}
+	}
+}
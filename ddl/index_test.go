@@ -0,0 +1,107 @@
+package ddl_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/ddl"
+	"grant-db/domain"
+	"grant-db/kv"
+	"grant-db/session"
+	"grant-db/util/testkit"
+)
+
+// newStoreWithLease creates a store whose DDL waits at most lease for the transactions in progress.
+func newStoreWithLease(t *testing.T, lease time.Duration) kv.Storage {
+	t.Helper()
+	session.SetSchemaLease(lease)
+	defer session.SetSchemaLease(ddl.DefaultOptions.Lease)
+	store, _ := testkit.NewMockStore(t)
+	return store
+}
+
+func TestAddIndexWithConcurrentTxn(t *testing.T) {
+	store := newStoreWithLease(t, 50*time.Millisecond)
+	tk1 := testkit.NewTestKit(t, store)
+	tk2 := testkit.NewTestKit(t, store)
+	tk1.MustExec("create database test")
+	tk1.MustExec("use test")
+	tk2.MustExec("use test")
+	tk1.MustExec("create table t (a int, b int)")
+	tk1.MustExec("create table t1 (a int)")
+	tk1.MustExec("insert into t values (1, 1)")
+
+	// The transaction wrote the row without the index entry, it can't commit after the index is added.
+	tk1.MustExec("begin")
+	tk1.MustExec("insert into t values (2, 2)")
+	tk2.MustExec("alter table t add index ia (a)")
+	checkLastJob(t, store, model.ActionAddIndex, model.StatePublic)
+	tk1.MustGetErrCode("commit", int(domain.ErrInfoSchemaChanged.Code()))
+	tk1.MustQuery("select * from t use index (ia) where a = 2").Check()
+	tk1.MustQuery("select count(*) from t").Check("1")
+
+	// The transaction run again uses the new schema.
+	tk1.MustExec("insert into t values (2, 2)")
+	tk1.MustQuery("select * from t use index (ia) where a = 2").Check("2 2")
+
+	// The transaction writing the other tables commits.
+	tk1.MustExec("begin")
+	tk1.MustExec("insert into t1 values (1)")
+	tk2.MustExec("alter table t add index ib (b)")
+	tk1.MustExec("commit")
+	tk1.MustQuery("select a from t1").Check("1")
+}
+
+func TestAddUniqueIndexWithConcurrentTxn(t *testing.T) {
+	store := newStoreWithLease(t, 50*time.Millisecond)
+	tk1 := testkit.NewTestKit(t, store)
+	tk2 := testkit.NewTestKit(t, store)
+	tk1.MustExec("create database test")
+	tk1.MustExec("use test")
+	tk2.MustExec("use test")
+	tk1.MustExec("create table t (a int, b int)")
+	tk1.MustExec("insert into t values (10, 1)")
+
+	tk1.MustExec("begin")
+	tk1.MustExec("insert into t values (10, 2)")
+	tk2.MustExec("alter table t add unique index ua (a)")
+	tk1.MustGetErrCode("commit", int(domain.ErrInfoSchemaChanged.Code()))
+	tk1.MustQuery("select * from t where a = 10").Check("10 1")
+	tk1.MustGetErrCode("insert into t values (10, 3)", mysql.ErrDupEntry)
+}
+
+func TestDDLWaitsForTxn(t *testing.T) {
+	store := newStoreWithLease(t, time.Minute)
+	tk1 := testkit.NewTestKit(t, store)
+	tk2 := testkit.NewTestKit(t, store)
+	tk1.MustExec("create database test")
+	tk1.MustExec("use test")
+	tk2.MustExec("use test")
+	tk1.MustExec("create table t (a int, b int)")
+
+	tk1.MustExec("begin")
+	tk1.MustExec("insert into t values (1, 1)")
+	done := make(chan error, 1)
+	go func() {
+		done <- tk2.ExecToErr("alter table t add index ia (a)")
+	}()
+	select {
+	case err := <-done:
+		t.Fatalf("the DDL finished with the transaction in progress: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+	tk1.MustGetErrCode("commit", int(domain.ErrInfoSchemaChanged.Code()))
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("the DDL doesn't finish after the transaction")
+	}
+	checkLastJob(t, store, model.ActionAddIndex, model.StatePublic)
+	tk1.MustExec("insert into t values (1, 1)")
+	tk1.MustQuery("select * from t use index (ia) where a = 1").Check("1 1")
+}
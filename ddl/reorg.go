@@ -0,0 +1,72 @@
+package ddl
+
+import (
+	"time"
+
+	"github.com/pingcap/parser/model"
+	"grant-db/kv"
+	"grant-db/meta"
+	"grant-db/table"
+	"grant-db/tablecodec"
+)
+
+const (
+	// reorgBatchSize is the number of rows or index entries handled in one transaction of reorganization.
+	reorgBatchSize = 256
+	// reorgWaitTimeout is the max time a job step spends on reorganization, the progress is
+	// saved and the reorganization continues in the next step.
+	reorgWaitTimeout = 500 * time.Millisecond
+)
+
+// backfillBatchFunc handles a batch of rows from startKey in its own transaction, it returns the
+// key of the next row or nil if all the rows are done.
+type backfillBatchFunc func(startKey kv.Key) (nextKey kv.Key, count int64, err error)
+
+// runReorgJob runs the batches over the rows of the table, the handle to continue from is saved
+// so that the reorganization survives errWaitReorg and restarts.
+func (d *ddl) runReorgJob(t *meta.Meta, job *model.Job, tbl table.Table, fn backfillBatchFunc) error {
+	startKey := tbl.FirstKey()
+	handle, found, err := t.GetDDLReorgHandle(job)
+	if err != nil {
+		return err
+	}
+	if found {
+		startKey = tbl.RecordKey(handle)
+	}
+
+	deadline := time.Now().Add(reorgWaitTimeout)
+	for {
+		nextKey, count, err := fn(startKey)
+		if err != nil {
+			return err
+		}
+		job.SetRowCount(job.GetRowCount() + count)
+		if nextKey == nil {
+			return nil
+		}
+		handle, err := tablecodec.DecodeRowKey(nextKey)
+		if err != nil {
+			return err
+		}
+		if err = t.UpdateDDLReorgHandle(job, handle); err != nil {
+			return err
+		}
+		if d.isClosed() || time.Now().After(deadline) {
+			return errWaitReorg
+		}
+		startKey = nextKey
+	}
+}
+
+// checkReorgJob checks the job in the batch transaction, errCancelledDDLJob is returned if the
+// job is cancelled by the client.
+func checkReorgJob(txn kv.Transaction, jobID int64) error {
+	job, err := meta.NewMeta(txn).GetDDLJobByID(jobID)
+	if err != nil {
+		return err
+	}
+	if job == nil || job.IsCancelling() {
+		return errCancelledDDLJob
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package ddl
+
+import (
+	"strings"
+
+	"github.com/pingcap/parser/model"
+	"grant-db/meta"
+	"grant-db/table"
+)
+
+// convertAddIdxJob2RollbackJob makes the index delete only and turns the job into a rolling
+// back job, the index data is removed by onDropIndex.
+func convertAddIdxJob2RollbackJob(t *meta.Meta, job *model.Job, tblInfo *model.TableInfo,
+	indexInfo *model.IndexInfo, err error) error {
+	job.State = model.JobStateRollingback
+	originalState := indexInfo.State
+	indexInfo.State = model.StateDeleteOnly
+	job.SchemaState = model.StateDeleteOnly
+	job.Args = []interface{}{indexInfo.Name}
+	if _, err1 := updateVersionAndTableInfo(t, job, tblInfo, originalState != indexInfo.State); err1 != nil {
+		return err1
+	}
+	return err
+}
+
+func rollingbackAddIndex(t *meta.Meta, job *model.Job) error {
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return err
+	}
+	var (
+		unique    bool
+		indexName model.CIStr
+	)
+	if err = job.DecodeArgs(&unique, &indexName); err != nil {
+		job.State = model.JobStateCancelled
+		return err
+	}
+	indexInfo := tblInfo.FindIndexByName(indexName.L)
+	if indexInfo == nil {
+		// The job hasn't added the index yet.
+		job.State = model.JobStateCancelled
+		return errCancelledDDLJob
+	}
+	return convertAddIdxJob2RollbackJob(t, job, tblInfo, indexInfo, errCancelledDDLJob)
+}
+
+func rollingbackDropIndex(t *meta.Meta, job *model.Job) error {
+	tblInfo, indexInfo, err := checkDropIndex(t, job)
+	if err != nil {
+		return err
+	}
+	originalState := indexInfo.State
+	switch indexInfo.State {
+	case model.StatePublic:
+		// The job hasn't changed the index yet.
+		job.State = model.JobStateCancelled
+	case model.StateWriteOnly:
+		// The index is complete in write only, make it public again.
+		indexInfo.State = model.StatePublic
+		ver, err := updateVersionAndTableInfo(t, job, tblInfo, originalState != indexInfo.State)
+		if err != nil {
+			return err
+		}
+		job.FinishTableJob(model.JobStateRollbackDone, model.StatePublic, ver, tblInfo)
+	default:
+		// The index data may be deleted partly, the job can't be cancelled and goes on.
+		job.State = model.JobStateRunning
+		return nil
+	}
+	return errCancelledDDLJob
+}
+
+func rollingbackAddColumn(t *meta.Meta, job *model.Job) error {
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return err
+	}
+	col := &model.ColumnInfo{}
+	if err = job.DecodeArgs(col); err != nil {
+		job.State = model.JobStateCancelled
+		return err
+	}
+	columnInfo := model.FindColumnInfo(tblInfo.Columns, col.Name.L)
+	if columnInfo == nil {
+		// The job hasn't added the column yet.
+		job.State = model.JobStateCancelled
+		return errCancelledDDLJob
+	}
+	// The column is removed by onDropColumn from delete only.
+	job.State = model.JobStateRollingback
+	originalState := columnInfo.State
+	columnInfo.State = model.StateDeleteOnly
+	job.SchemaState = model.StateDeleteOnly
+	job.Args = []interface{}{columnInfo.Name}
+	if _, err = updateVersionAndTableInfo(t, job, tblInfo, originalState != columnInfo.State); err != nil {
+		return err
+	}
+	return errCancelledDDLJob
+}
+
+func rollingbackModifyColumn(t *meta.Meta, job *model.Job) error {
+	tblInfo, err := getTableInfoAndCancelFaultJob(t, job, job.SchemaID)
+	if err != nil {
+		return err
+	}
+	newCol := &model.ColumnInfo{}
+	var oldColName model.CIStr
+	if err = job.DecodeArgs(newCol, &oldColName); err != nil {
+		job.State = model.JobStateCancelled
+		return err
+	}
+	changingColName := table.ChangingColumnPrefix + oldColName.O
+	if model.FindColumnInfo(tblInfo.Columns, strings.ToLower(changingColName)) == nil {
+		// The job hasn't added the changing column yet.
+		job.State = model.JobStateCancelled
+		return errCancelledDDLJob
+	}
+	return rollbackModifyColumnJob(t, job, tblInfo, errCancelledDDLJob)
+}
+
+// convertJob2RollbackJob handles a job cancelled by the client, the job is either cancelled
+// directly or rolled back step by step.
+func convertJob2RollbackJob(t *meta.Meta, job *model.Job) (err error) {
+	switch job.Type {
+	case model.ActionAddIndex, model.ActionAddPrimaryKey:
+		err = rollingbackAddIndex(t, job)
+	case model.ActionDropIndex, model.ActionDropPrimaryKey:
+		err = rollingbackDropIndex(t, job)
+	case model.ActionAddColumn:
+		err = rollingbackAddColumn(t, job)
+	case model.ActionModifyColumn:
+		err = rollingbackModifyColumn(t, job)
+	default:
+		// The other jobs finish in one step, they are cancelled only before running.
+		job.State = model.JobStateCancelled
+		err = errCancelledDDLJob
+	}
+	if err != nil && (job.IsCancelled() || job.IsRollingback() || job.IsRollbackDone()) {
+		job.Error = toTError(err)
+	}
+	return err
+}
@@ -0,0 +1,87 @@
+package ddl
+
+import (
+	"github.com/pingcap/parser/model"
+	"grant-db/infoschema"
+	"grant-db/meta"
+)
+
+func onCreateSchema(t *meta.Meta, job *model.Job) error {
+	schemaID := job.SchemaID
+	dbInfo := &model.DBInfo{}
+	if err := job.DecodeArgs(dbInfo); err != nil {
+		return err
+	}
+	dbInfo.ID = schemaID
+	dbInfo.State = model.StateNone
+
+	if err := checkSchemaNotExists(t, dbInfo); err != nil {
+		job.State = model.JobStateCancelled
+		return err
+	}
+	ver, err := updateSchemaVersion(t, job)
+	if err != nil {
+		return err
+	}
+	// TODO: Support the online state of creating schema.
+	dbInfo.State = model.StatePublic
+	if err := t.CreateDatabase(dbInfo); err != nil {
+		return err
+	}
+	job.FinishDBJob(model.JobStateDone, model.StatePublic, ver, dbInfo)
+	return nil
+}
+
+func checkSchemaNotExists(t *meta.Meta, dbInfo *model.DBInfo) error {
+	dbs, err := t.ListDatabases()
+	if err != nil {
+		return err
+	}
+	for _, db := range dbs {
+		if db.Name.L == dbInfo.Name.L {
+			if db.ID != dbInfo.ID {
+				return infoschema.ErrDatabaseExists.GenWithStackByArgs(db.Name)
+			}
+			break
+		}
+	}
+	return nil
+}
+
+func getSchemaInfo(t *meta.Meta, job *model.Job) (*model.DBInfo, error) {
+	dbInfo, err := t.GetDatabase(job.SchemaID)
+	if err != nil {
+		return nil, err
+	}
+	if dbInfo == nil {
+		return nil, infoschema.ErrDatabaseNotExists.GenWithStackByArgs("")
+	}
+	return dbInfo, nil
+}
+
+func onDropSchema(t *meta.Meta, job *model.Job) error {
+	dbInfo, err := getSchemaInfo(t, job)
+	if err != nil {
+		job.State = model.JobStateCancelled
+		return err
+	}
+	ver, err := updateSchemaVersion(t, job)
+	if err != nil {
+		return err
+	}
+	tables, err := t.ListTables(dbInfo.ID)
+	if err != nil {
+		return err
+	}
+	// TODO: Support the online state of dropping schema.
+	if err = t.DropDatabase(dbInfo.ID); err != nil {
+		return err
+	}
+	for _, tblInfo := range tables {
+		if err = addTableDelRange(t, job, tblInfo.ID); err != nil {
+			return err
+		}
+	}
+	job.FinishDBJob(model.JobStateDone, model.StateNone, ver, dbInfo)
+	return nil
+}
@@ -0,0 +1,209 @@
+package ddl
+
+import (
+	"github.com/pingcap/parser/model"
+	"grant-db/infoschema"
+	"grant-db/meta"
+)
+
+func onCreateTable(t *meta.Meta, job *model.Job) error {
+	schemaID := job.SchemaID
+	tbInfo := &model.TableInfo{}
+	if err := job.DecodeArgs(tbInfo); err != nil {
+		return err
+	}
+
+	tbInfo.State = model.StateNone
+	if err := checkTableNotExists(t, schemaID, tbInfo.Name.L); err != nil {
+		job.State = model.JobStateCancelled
+		return err
+	}
+	ver, err := updateSchemaVersion(t, job)
+	if err != nil {
+		return err
+	}
+
+	// TODO: Support the online state of creating table.
+	tbInfo.State = model.StatePublic
+	if err := t.CreateTableOrView(schemaID, tbInfo); err != nil {
+		return err
+	}
+	// AUTO_INCREMENT=N makes the first allocated ID N.
+	if tbInfo.AutoIncID > 1 {
+		if _, err := t.GenAutoTableID(tbInfo.ID, tbInfo.AutoIncID-1); err != nil {
+			return err
+		}
+	}
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tbInfo)
+	return nil
+}
+
+func checkTableNotExists(t *meta.Meta, schemaID int64, tableName string) error {
+	tables, err := t.ListTables(schemaID)
+	if err != nil {
+		return err
+	}
+	for _, tbl := range tables {
+		if tbl.Name.L == tableName {
+			return infoschema.ErrTableExists.GenWithStackByArgs(tbl.Name)
+		}
+	}
+	return nil
+}
+
+func getTableInfo(t *meta.Meta, job *model.Job, schemaID int64) (*model.TableInfo, error) {
+	tableID := job.TableID
+	tblInfo, err := t.GetTable(schemaID, tableID)
+	if err != nil {
+		return nil, err
+	}
+	if tblInfo == nil {
+		return nil, infoschema.ErrTableNotExists.GenWithStackByArgs(
+			job.SchemaName, tableID)
+	}
+	return tblInfo, nil
+}
+
+func onDropTable(t *meta.Meta, job *model.Job) error {
+	tblInfo, err := getTableInfo(t, job, job.SchemaID)
+	if err != nil {
+		job.State = model.JobStateCancelled
+		return err
+	}
+	ver, err := updateSchemaVersion(t, job)
+	if err != nil {
+		return err
+	}
+	// TODO: Support the online state of dropping table.
+	if err = t.DropTableOrView(job.SchemaID, tblInfo.ID); err != nil {
+		return err
+	}
+	if err = addTableDelRange(t, job, tblInfo.ID); err != nil {
+		return err
+	}
+	job.FinishTableJob(model.JobStateDone, model.StateNone, ver, tblInfo)
+	return nil
+}
+
+func onTruncateTable(t *meta.Meta, job *model.Job) error {
+	tblInfo, err := getTableInfo(t, job, job.SchemaID)
+	if err != nil {
+		job.State = model.JobStateCancelled
+		return err
+	}
+	var newTableID int64
+	if err = job.DecodeArgs(&newTableID); err != nil {
+		job.State = model.JobStateCancelled
+		return err
+	}
+	if err = t.DropTableOrView(job.SchemaID, tblInfo.ID); err != nil {
+		return err
+	}
+	if err = addTableDelRange(t, job, tblInfo.ID); err != nil {
+		return err
+	}
+	tblInfo.ID = newTableID
+	if err = t.CreateTableOrView(job.SchemaID, tblInfo); err != nil {
+		return err
+	}
+	ver, err := updateSchemaVersion(t, job)
+	if err != nil {
+		return err
+	}
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	return nil
+}
+
+// onRenameTables renames all the tables of the job in order in one step.
+func onRenameTables(t *meta.Meta, job *model.Job) error {
+	var (
+		oldSchemaIDs  []int64
+		newSchemaIDs  []int64
+		newTableNames []model.CIStr
+		tableIDs      []int64
+	)
+	if err := job.DecodeArgs(&oldSchemaIDs, &newSchemaIDs, &newTableNames, &tableIDs); err != nil {
+		job.State = model.JobStateCancelled
+		return err
+	}
+	var tblInfo *model.TableInfo
+	for i, tableID := range tableIDs {
+		var err error
+		tblInfo, err = t.GetTable(oldSchemaIDs[i], tableID)
+		if err != nil || tblInfo == nil {
+			job.State = model.JobStateCancelled
+			if err == nil {
+				err = infoschema.ErrTableNotExists.GenWithStackByArgs(job.SchemaName, tableID)
+			}
+			return err
+		}
+		if err = checkTableNotExists(t, newSchemaIDs[i], newTableNames[i].L); err != nil {
+			job.State = model.JobStateCancelled
+			return err
+		}
+		if err = t.DropTableOrView(oldSchemaIDs[i], tableID); err != nil {
+			return err
+		}
+		tblInfo.Name = newTableNames[i]
+		if err = t.CreateTableOrView(newSchemaIDs[i], tblInfo); err != nil {
+			job.State = model.JobStateCancelled
+			return err
+		}
+	}
+	ver, err := updateSchemaVersion(t, job)
+	if err != nil {
+		return err
+	}
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	return nil
+}
+
+func onRecoverTable(t *meta.Meta, job *model.Job) error {
+	schemaID := job.SchemaID
+	tblInfo := &model.TableInfo{}
+	var dropJobID int64
+	if err := job.DecodeArgs(tblInfo, &dropJobID); err != nil {
+		job.State = model.JobStateCancelled
+		return err
+	}
+	if err := checkTableNotExists(t, schemaID, tblInfo.Name.L); err != nil {
+		job.State = model.JobStateCancelled
+		return err
+	}
+	oldTblInfo, err := t.GetTable(schemaID, tblInfo.ID)
+	if err != nil || oldTblInfo != nil {
+		job.State = model.JobStateCancelled
+		if err == nil {
+			err = infoschema.ErrTableExists.GenWithStackByArgs(oldTblInfo.Name)
+		}
+		return err
+	}
+	// The data is still there if the delete range task isn't done, the task is removed, so GC
+	// won't delete the data of the recovered table.
+	task, err := t.GetDelRange(dropJobID, tblInfo.ID)
+	if err != nil {
+		return err
+	}
+	safePoint, err := t.GetGCSafePoint()
+	if err != nil {
+		return err
+	}
+	if task == nil || task.TS < safePoint {
+		job.State = model.JobStateCancelled
+		return ErrTableDataGCed.GenWithStackByArgs(tblInfo.Name)
+	}
+	if err = t.RemoveDelRange(dropJobID, tblInfo.ID); err != nil {
+		return err
+	}
+
+	tblInfo.State = model.StatePublic
+	if err = t.CreateTableOrView(schemaID, tblInfo); err != nil {
+		return err
+	}
+	ver, err := updateSchemaVersion(t, job)
+	if err != nil {
+		return err
+	}
+	job.FinishTableJob(model.JobStateDone, model.StatePublic, ver, tblInfo)
+	return nil
+}
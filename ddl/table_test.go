@@ -0,0 +1,166 @@
+package ddl_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/ddl"
+	"grant-db/kv"
+	"grant-db/meta"
+	"grant-db/session"
+	"grant-db/tablecodec"
+	"grant-db/util/testkit"
+)
+
+func TestRenameTables(t *testing.T) {
+	store, _ := testkit.NewMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create database test")
+	tk.MustExec("create database test2")
+	tk.MustExec("use test")
+	tk.MustExec("create table a (x int)")
+	tk.MustExec("create table c (x int)")
+	tk.MustExec("insert into a values (1)")
+	tk.MustExec("insert into c values (3)")
+
+	tk.MustExec("rename table a to b, c to test2.d")
+	checkLastJob(t, store, model.ActionRenameTable, model.StatePublic)
+	tk.MustQuery("select x from b").Check("1")
+	tk.MustQuery("select x from test2.d").Check("3")
+	tk.MustGetErrCode("select x from a", mysql.ErrNoSuchTable)
+
+	// None of the tables is renamed if any of them fails.
+	tk.MustExec("create table e (x int)")
+	tk.MustGetErrCode("rename table b to f, e to test2.d", mysql.ErrTableExists)
+	tk.MustQuery("select x from b").Check("1")
+	tk.MustGetErrCode("select x from f", mysql.ErrNoSuchTable)
+	tk.MustGetErrCode("rename table x to y", mysql.ErrNoSuchTable)
+
+	tk.MustExec("alter table b rename to a")
+	tk.MustQuery("select x from a").Check("1")
+}
+
+func TestTruncateAndRecoverTable(t *testing.T) {
+	store, dom := testkit.NewMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create database test")
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int primary key auto_increment, b int)")
+	tk.MustExec("insert into t (b) values (1), (2)")
+	oldTbl, err := dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The truncated table is a new one with a new ID.
+	tk.MustExec("truncate table t")
+	checkLastJob(t, store, model.ActionTruncateTable, model.StatePublic)
+	tk.MustQuery("select count(*) from t").Check("0")
+	newTbl, err := dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if newTbl.Meta().ID == oldTbl.Meta().ID {
+		t.Fatalf("the truncated table keeps the ID %d", oldTbl.Meta().ID)
+	}
+	tk.MustExec("insert into t (b) values (3)")
+
+	// The truncated table is recovered with a new name, the name is taken by the new table.
+	tk.MustGetErrCode("flashback table t", mysql.ErrTableExists)
+	tk.MustExec("flashback table t to t_old")
+	checkLastJob(t, store, model.ActionRecoverTable, model.StatePublic)
+	tk.MustQuery("select b from t_old order by a").Check("1", "2")
+	tk.MustQuery("select b from t").Check("3")
+
+	tk.MustExec("drop table t")
+	tk.MustExec("recover table t")
+	tk.MustQuery("select b from t").Check("3")
+	if err = tk.ExecToErr("recover table t_none"); !ddl.ErrNoDroppedTable.Equal(err) {
+		t.Fatalf("the error of recovering the table never dropped is %v, expected %v", err, ddl.ErrNoDroppedTable)
+	}
+}
+
+func TestDropTableGC(t *testing.T) {
+	session.SetGCLifeTime(100*time.Millisecond, 50*time.Millisecond)
+	defer session.SetGCLifeTime(ddl.DefaultOptions.GCLifeTime, ddl.DefaultOptions.GCRunInterval)
+	store, dom := testkit.NewMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create database test")
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int primary key, b int, key idx_b (b))")
+	tk.MustExec("insert into t values (1, 1), (2, 2)")
+	tbl, err := dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefix := tablecodec.EncodeTablePrefix(tbl.Meta().ID)
+	ver, err := store.CurrentVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tk.MustExec("drop table t")
+
+	// GC removes the delete range task once the data is removed.
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		var tasks []*meta.DelRangeTask
+		err = kv.RunInNewTxn(store, false, func(txn kv.Transaction) error {
+			tasks, err = meta.NewMeta(txn).ListDelRanges()
+			return err
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(tasks) == 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("the delete range tasks are left: %v", tasks)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	// No version of the table data is left, even the ones before the drop.
+	it, err := store.GetSnapshot(ver).Iter(prefix, prefix.PrefixNext())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if it.Valid() {
+		t.Fatalf("the key %s of the dropped table is left", it.Key())
+	}
+	it.Close()
+	if err = tk.ExecToErr("recover table t"); !ddl.ErrTableDataGCed.Equal(err) {
+		t.Fatalf("the error of recovering the table cleaned up is %v, expected %v", err, ddl.ErrTableDataGCed)
+	}
+}
+
+func TestCreateTableWithCharset(t *testing.T) {
+	store, dom := testkit.NewMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create database test")
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a varchar(10) character set gbk, b varchar(10) charset latin1, c varchar(10) collate gbk_bin, d varchar(10)) charset=gbk")
+	tbl, err := dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr("t"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tbl.Meta().Charset != "gbk" || tbl.Meta().Collate != "gbk_chinese_ci" {
+		t.Fatalf("the table is in %s %s", tbl.Meta().Charset, tbl.Meta().Collate)
+	}
+	expected := []string{"gbk gbk_chinese_ci", "latin1 latin1_swedish_ci", "gbk gbk_bin", "gbk gbk_chinese_ci"}
+	for i, col := range tbl.Meta().Columns {
+		if got := col.Charset + " " + col.Collate; got != expected[i] {
+			t.Fatalf("the column %s is in %s, expected %s", col.Name, got, expected[i])
+		}
+	}
+	tk.MustGetErrCode("create table t1 (a varchar(10) charset foo)", mysql.ErrUnknownCharacterSet)
+	tk.MustGetErrCode("create table t1 (a varchar(10) charset gbk collate latin1_bin)", mysql.ErrCollationCharsetMismatch)
+
+	// The strings are compared by the collations of the columns, gbk_chinese_ci orders the Chinese by pinyin.
+	tk.MustExec("insert into t values ('中文', 'abc', 'A', 'x'), ('啊', 'ABC', 'a', 'X'), ('不', 'x', 'b', 'y')")
+	tk.MustQuery("select a from t order by a").Check("啊", "不", "中文")
+	tk.MustQuery("select b from t where b = 'abc'").Sort().Check("ABC", "abc")
+	tk.MustQuery("select c from t order by c").Check("A", "a", "b")
+	tk.MustQuery("select count(distinct d) from t").Check("2")
+}
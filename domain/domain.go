@@ -0,0 +1,212 @@
+package domain
+
+import (
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pingcap/parser/terror"
+
+	"grant-db/ddl"
+	"grant-db/infoschema"
+	"grant-db/kv"
+	"grant-db/meta"
+	"grant-db/sessionctx"
+	"grant-db/statistics/handle"
+)
+
+// statsLease is the interval the stats worker dumps the row count deltas, reloads the statistics and
+// analyzes the tables which are modified a lot.
+const statsLease = 3 * time.Second
+
+// Domain represents a storage space. Different domains can use the same database name.
+// Multiple domains can be used in parallel without synchronization.
+type Domain struct {
+	store kv.Storage
+	// infoSchema holds the latest loaded infoschema.InfoSchema.
+	infoSchema atomic.Value
+	// reloadMu serializes the reloads.
+	reloadMu    sync.Mutex
+	validator   *schemaValidator
+	ddlOptions  ddl.Options
+	ddl         ddl.DDL
+	statsHandle *handle.Handle
+	quitCh      chan struct{}
+	wg          sync.WaitGroup
+}
+
+// NewDomain creates a new domain. Should not create multiple domains for the same store.
+func NewDomain(store kv.Storage, ddlOptions ddl.Options) *Domain {
+	return &Domain{
+		store:      store,
+		validator:  newSchemaValidator(),
+		ddlOptions: ddlOptions,
+		quitCh:     make(chan struct{}),
+	}
+}
+
+// Init initializes a domain by loading the latest schema and statistics.
+func (do *Domain) Init() error {
+	do.ddl = ddl.NewDDL(do.store, do, do.ddlOptions)
+	if err := do.Reload(); err != nil {
+		return err
+	}
+	do.statsHandle = handle.NewHandle(do.store)
+	if err := do.statsHandle.Update(do.InfoSchema()); err != nil {
+		return err
+	}
+	do.startStatsWorker()
+	return nil
+}
+
+// StatsHandle returns the statistics handle.
+func (do *Domain) StatsHandle() *handle.Handle {
+	return do.statsHandle
+}
+
+// startStatsWorker starts the worker which keeps the statistics updated.
+func (do *Domain) startStatsWorker() {
+	do.wg.Add(1)
+	go func() {
+		defer do.wg.Done()
+		ticker := time.NewTicker(statsLease)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-do.quitCh:
+				return
+			}
+			if err := do.updateStats(); err != nil {
+				log.Printf("[stats] update statistics failed: %v", err)
+			}
+		}
+	}()
+}
+
+func (do *Domain) updateStats() error {
+	h := do.statsHandle
+	if err := h.DumpStatsDeltaToKV(); err != nil {
+		return err
+	}
+	if err := h.Update(do.InfoSchema()); err != nil {
+		return err
+	}
+	return h.HandleAutoAnalyze(do.InfoSchema())
+}
+
+// DDL gets DDL from domain.
+func (do *Domain) DDL() ddl.DDL {
+	return do.ddl
+}
+
+// Store gets KV store from domain.
+func (do *Domain) Store() kv.Storage {
+	return do.store
+}
+
+// InfoSchema gets information schema from domain.
+func (do *Domain) InfoSchema() infoschema.InfoSchema {
+	is, _ := do.infoSchema.Load().(infoschema.InfoSchema)
+	return is
+}
+
+// Reload reloads InfoSchema if the schema version in the store is different from the loaded one.
+// It's public in order to do the test.
+func (do *Domain) Reload() error {
+	do.reloadMu.Lock()
+	defer do.reloadMu.Unlock()
+
+	ver, err := do.store.CurrentVersion()
+	if err != nil {
+		return err
+	}
+	is, err := do.loadInfoSchema(ver.Ver)
+	if err != nil {
+		return err
+	}
+	if oldIS := do.InfoSchema(); oldIS != is {
+		do.validator.update(oldIS, is)
+		do.infoSchema.Store(is)
+	}
+	return nil
+}
+
+// RegisterTxn registers the transaction which uses the schema of schemaVer until it's unregistered by
+// UnregisterTxn, the DDL waits for it before the next schema change.
+func (do *Domain) RegisterTxn(schemaVer int64) {
+	do.validator.enter(schemaVer)
+}
+
+// UnregisterTxn unregisters the transaction once it's committed or rolled back.
+func (do *Domain) UnregisterTxn(schemaVer int64) {
+	do.validator.leave(schemaVer)
+}
+
+// OldestSchemaVersion returns the oldest schema version used by the registered transactions, it's 0 if no
+// transaction is registered.
+func (do *Domain) OldestSchemaVersion() int64 {
+	return do.validator.oldestActiveVersion()
+}
+
+// SchemaChecker returns the checker of the transaction which uses the schema of schemaVer and writes the
+// tables, the commit fails with ErrInfoSchemaChanged if any of the tables is changed after the schema.
+func (do *Domain) SchemaChecker(schemaVer int64, tableIDs []int64) kv.SchemaChecker {
+	return &schemaChecker{validator: do.validator, schemaVer: schemaVer, tableIDs: tableIDs}
+}
+
+// GetSnapshotInfoSchema gets a snapshot information schema at snapshotTS.
+func (do *Domain) GetSnapshotInfoSchema(snapshotTS uint64) (infoschema.InfoSchema, error) {
+	return do.loadInfoSchema(snapshotTS)
+}
+
+// loadInfoSchema loads the infoschema at startTS, the loaded one is reused if the version is unchanged.
+func (do *Domain) loadInfoSchema(startTS uint64) (infoschema.InfoSchema, error) {
+	m := meta.NewSnapshotMeta(do.store.GetSnapshot(kv.Version{Ver: startTS}))
+	neededSchemaVersion, err := m.GetSchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+	if is := do.InfoSchema(); is != nil && is.SchemaMetaVersion() == neededSchemaVersion {
+		return is, nil
+	}
+	b, err := infoschema.NewBuilder(do.store, do.InfoSchema()).InitWithMeta(m)
+	if err != nil {
+		return nil, err
+	}
+	return b.Build(), nil
+}
+
+// Close closes the Domain and release its resource.
+func (do *Domain) Close() {
+	close(do.quitCh)
+	do.wg.Wait()
+	if do.ddl != nil {
+		terror.Log(do.ddl.Stop())
+	}
+}
+
+// domainKeyType is a dummy type to avoid naming collision in context.
+type domainKeyType int
+
+// String defines a Stringer function for debugging and pretty printing.
+func (k domainKeyType) String() string {
+	return "domain"
+}
+
+const domainKey domainKeyType = 0
+
+// BindDomain binds domain to context.
+func BindDomain(ctx sessionctx.Context, domain *Domain) {
+	ctx.SetValue(domainKey, domain)
+}
+
+// GetDomain gets domain from context.
+func GetDomain(ctx sessionctx.Context) *Domain {
+	v, ok := ctx.Value(domainKey).(*Domain)
+	if ok {
+		return v
+	}
+	return nil
+}
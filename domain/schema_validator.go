@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"sync"
+
+	"github.com/pingcap/parser/terror"
+	"grant-db/infoschema"
+	"grant-db/kv"
+	"grant-db/meta"
+)
+
+// codeInfoSchemaChanged is the error code of ErrInfoSchemaChanged, which is the one of TiDB.
+const codeInfoSchemaChanged terror.ErrCode = 8028
+
+// ErrInfoSchemaChanged is returned when a transaction commits the tables changed by the DDL after the schema
+// it uses, the transaction can be run again with the new schema.
+var ErrInfoSchemaChanged = terror.ClassDomain.New(codeInfoSchemaChanged,
+	"Information schema is changed during the execution of the transaction, the table definition may be updated by other DDL ran in parallel, please try again")
+
+// maxSchemaDeltas is the number of the recent schema versions whose changed tables are kept, a transaction
+// using an older schema fails to commit its writes.
+const maxSchemaDeltas = 1024
+
+// schemaDelta is the tables changed by the schema versions after the previous delta up to ver.
+type schemaDelta struct {
+	ver      int64
+	tableIDs map[int64]struct{}
+}
+
+// schemaValidator keeps the tables changed by the recent schema versions, a transaction fails to commit if
+// it writes a table changed after the schema it uses. It also counts the transactions in progress by their
+// schema versions, the DDL waits for them to use the latest schema before the next schema change.
+type schemaValidator struct {
+	mu sync.Mutex
+	// latest is the latest loaded schema version.
+	latest int64
+	// base is the schema version before the first delta.
+	base int64
+	// deltas are ordered by the version ascending.
+	deltas []schemaDelta
+	// active counts the transactions in progress by their schema versions.
+	active map[int64]int
+}
+
+func newSchemaValidator() *schemaValidator {
+	return &schemaValidator{active: make(map[int64]int)}
+}
+
+// update records the tables changed from oldIS to newIS, they're the created, dropped and updated ones.
+func (v *schemaValidator) update(oldIS, newIS infoschema.InfoSchema) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	ver := newIS.SchemaMetaVersion()
+	if oldIS == nil {
+		v.latest, v.base, v.deltas = ver, ver, nil
+		return
+	}
+	oldTables, newTables := allTableUpdateTS(oldIS), allTableUpdateTS(newIS)
+	changed := make(map[int64]struct{})
+	for id, ts := range newTables {
+		if oldTS, ok := oldTables[id]; !ok || oldTS != ts {
+			changed[id] = struct{}{}
+		}
+	}
+	for id := range oldTables {
+		if _, ok := newTables[id]; !ok {
+			changed[id] = struct{}{}
+		}
+	}
+	v.deltas = append(v.deltas, schemaDelta{ver: ver, tableIDs: changed})
+	if len(v.deltas) > maxSchemaDeltas {
+		v.base = v.deltas[0].ver
+		v.deltas = v.deltas[1:]
+	}
+	v.latest = ver
+}
+
+// allTableUpdateTS returns the UpdateTS of the tables by their IDs.
+func allTableUpdateTS(is infoschema.InfoSchema) map[int64]uint64 {
+	tables := make(map[int64]uint64)
+	for _, db := range is.AllSchemas() {
+		for _, tbl := range is.SchemaTables(db.Name) {
+			tables[tbl.Meta().ID] = tbl.Meta().UpdateTS
+		}
+	}
+	return tables
+}
+
+// check checks the transaction which uses the schema of schemaVer and writes the tables can commit when the
+// latest schema version is latestVer.
+func (v *schemaValidator) check(schemaVer, latestVer int64, tableIDs []int64) error {
+	if schemaVer == latestVer {
+		return nil
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	// The changes are unknown if the latest schema isn't loaded or the schema of the transaction is too old.
+	if latestVer > v.latest || schemaVer < v.base {
+		return ErrInfoSchemaChanged
+	}
+	for _, delta := range v.deltas {
+		if delta.ver <= schemaVer || delta.ver > latestVer {
+			continue
+		}
+		for _, id := range tableIDs {
+			if _, ok := delta.tableIDs[id]; ok {
+				return ErrInfoSchemaChanged
+			}
+		}
+	}
+	return nil
+}
+
+func (v *schemaValidator) enter(schemaVer int64) {
+	v.mu.Lock()
+	v.active[schemaVer]++
+	v.mu.Unlock()
+}
+
+func (v *schemaValidator) leave(schemaVer int64) {
+	v.mu.Lock()
+	if v.active[schemaVer]--; v.active[schemaVer] <= 0 {
+		delete(v.active, schemaVer)
+	}
+	v.mu.Unlock()
+}
+
+// oldestActiveVersion returns the oldest schema version used by the transactions in progress, it's 0 if
+// there is no such transaction.
+func (v *schemaValidator) oldestActiveVersion() int64 {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	var oldest int64
+	for ver := range v.active {
+		if oldest == 0 || ver < oldest {
+			oldest = ver
+		}
+	}
+	return oldest
+}
+
+// schemaChecker checks the transaction which uses the schema of schemaVer and writes the tables on commit.
+type schemaChecker struct {
+	validator *schemaValidator
+	schemaVer int64
+	tableIDs  []int64
+}
+
+// Check implements kv.SchemaChecker interface.
+func (c *schemaChecker) Check(latest kv.Snapshot) error {
+	latestVer, err := meta.NewSnapshotMeta(latest).GetSchemaVersion()
+	if err != nil {
+		return err
+	}
+	return c.validator.check(c.schemaVer, latestVer, c.tableIDs)
+}
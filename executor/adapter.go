@@ -0,0 +1,117 @@
+package executor
+
+import (
+	"context"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"grant-db/planner"
+	"grant-db/sessionctx"
+	"grant-db/util/chunk"
+	"grant-db/util/memory"
+	"grant-db/util/sqlexec"
+)
+
+// ExecStmt is a physical plan to be executed, it builds the executors of the plan.
+type ExecStmt struct {
+	Plan planner.PhysicalPlan
+	Ctx  sessionctx.Context
+}
+
+// Exec builds and opens the executors of the plan, the rows are read from the returned record set.
+func (a *ExecStmt) Exec(ctx context.Context) (_ sqlexec.RecordSet, err error) {
+	defer recoverMemoryExceed(&err)
+	b := newExecutorBuilder(a.Ctx)
+	e := b.build(a.Plan)
+	if b.err != nil {
+		return nil, b.err
+	}
+	if err := e.Open(ctx); err != nil {
+		terminateErr := e.Close()
+		if terminateErr != nil {
+			return nil, terminateErr
+		}
+		return nil, err
+	}
+	switch a.Plan.(type) {
+	case *planner.Insert, *planner.Update, *planner.Delete:
+		return nil, a.handleNoResult(ctx, e)
+	}
+	return &recordSet{executor: e, fields: buildResultFields(a.Plan)}, nil
+}
+
+// handleNoResult runs the executor of the statement which returns no rows, the result is recorded in the
+// statement context.
+func (a *ExecStmt) handleNoResult(ctx context.Context, e Executor) (err error) {
+	defer func() {
+		if closeErr := e.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+	return Next(ctx, e, newFirstChunk(e))
+}
+
+// buildResultFields builds the fields of the output columns of the plan by their names.
+func buildResultFields(p planner.PhysicalPlan) []*ast.ResultField {
+	names := p.OutputNames()
+	fields := make([]*ast.ResultField, 0, p.Schema().Len())
+	for i, col := range p.Schema().Columns {
+		name := names[i]
+		origColName := name.OrigColName
+		if origColName.L == "" {
+			origColName = name.ColName
+		}
+		field := &ast.ResultField{
+			Column:       &model.ColumnInfo{Name: origColName, FieldType: *col.RetType},
+			ColumnAsName: name.ColName,
+			TableAsName:  name.TblName,
+			DBName:       name.DBName,
+		}
+		if name.OrigTblName.L != "" {
+			field.Table = &model.TableInfo{Name: name.OrigTblName}
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// recordSet reads the rows from the root executor.
+type recordSet struct {
+	executor Executor
+	fields   []*ast.ResultField
+}
+
+// Fields implements sqlexec.RecordSet Fields interface.
+func (a *recordSet) Fields() []*ast.ResultField {
+	return a.fields
+}
+
+// Next implements sqlexec.RecordSet Next interface.
+func (a *recordSet) Next(ctx context.Context, req *chunk.Chunk) (err error) {
+	defer recoverMemoryExceed(&err)
+	return Next(ctx, a.executor, req)
+}
+
+// recoverMemoryExceed recovers the panic of the exceeded memory quota of the query as the error, the other
+// panics are not recovered.
+func recoverMemoryExceed(err *error) {
+	r := recover()
+	if r == nil {
+		return
+	}
+	if recoveredErr, ok := r.(error); ok && memory.ErrMemoryExceedForQuery.Equal(recoveredErr) {
+		*err = recoveredErr
+		return
+	}
+	panic(r)
+}
+
+// NewChunk implements sqlexec.RecordSet NewChunk interface.
+func (a *recordSet) NewChunk() *chunk.Chunk {
+	return newFirstChunk(a.executor)
+}
+
+// Close implements sqlexec.RecordSet Close interface.
+func (a *recordSet) Close() error {
+	return a.executor.Close()
+}
@@ -0,0 +1,93 @@
+package aggfuncs
+
+import (
+	"unsafe"
+
+	"grant-db/expression"
+	"grant-db/sessionctx"
+	"grant-db/util/chunk"
+)
+
+// PartialResult represents data structure to store the partial result for the
+// aggregate functions. Here we use unsafe.Pointer to allow the partial result
+// to be any type.
+type PartialResult unsafe.Pointer
+
+// AggFunc is the interface to evaluate the aggregate functions.
+type AggFunc interface {
+	// AllocPartialResult allocates a specific data structure to store the
+	// partial result, initializes it, and converts it to PartialResult to
+	// return back. Aggregate operator implementation, no matter it's a hash
+	// or stream, should hold this allocated PartialResult for the further
+	// operations like: "ResetPartialResult", "UpdatePartialResult".
+	AllocPartialResult() PartialResult
+
+	// ResetPartialResult resets the partial result to the original state for a
+	// specific aggregate function. It converts the input PartialResult to the
+	// specific data structure which stores the partial result and then reset
+	// every field to the proper original state.
+	ResetPartialResult(pr PartialResult)
+
+	// UpdatePartialResult updates the specific partial result for an aggregate
+	// function using the input rows which all belonging to the same data group.
+	// It converts the PartialResult to the specific data structure which stores
+	// the partial result and then iterates on the input rows and update that
+	// partial result according to the functionality and the state of the
+	// aggregate function.
+	UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error
+
+	// MergePartialResult merges the partial result src into dst, both of
+	// which are the partial results of the same data group. It's used by the
+	// final workers of the hash aggregation, which merge the partial results
+	// of the partial workers. The functions with DISTINCT can't be merged.
+	MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) error
+
+	// AppendFinalResult2Chunk finalizes the partial result and append the
+	// final result to the input chunk. Like other operations, it converts the
+	// input PartialResult to the specific data structure which stores the
+	// partial result and then calculates the final result and append that
+	// final result to the chunk provided.
+	AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error
+}
+
+type baseAggFunc struct {
+	// args stores the input arguments for an aggregate function, we should
+	// call arg.EvalXXX to get the actual input data for this function.
+	args []expression.Expression
+
+	// ordinal stores the ordinal of the columns in the output chunk, which is
+	// used to append the final result of this function.
+	ordinal int
+}
+
+// hasNullArg evaluates the arguments on the row, it returns true if any of them is NULL.
+func hasNullArg(args []expression.Expression, row chunk.Row) (bool, error) {
+	for _, arg := range args {
+		d, err := arg.Eval(row)
+		if err != nil {
+			return false, err
+		}
+		if d.IsNull() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// SlidingWindowAggFunc is the aggregate function whose partial result can be slid over the frames of a
+// window, the rows leaving the frame are removed from the partial result and the ones entering it are
+// added, so the frame of every row isn't aggregated from scratch.
+type SlidingWindowAggFunc interface {
+	Slide(sctx sessionctx.Context, removed, added []chunk.Row, pr PartialResult) error
+}
+
+// baseWindowFunc is the base of the window functions which are evaluated on the whole partition, the rows
+// of the partition are updated at once and the result of every row is appended in order.
+type baseWindowFunc struct {
+	baseAggFunc
+}
+
+// MergePartialResult implements AggFunc interface, the window functions are never partially evaluated.
+func (e *baseWindowFunc) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) error {
+	return expression.ErrNotSupportedYet.GenWithStackByArgs("merging the partial results of window functions")
+}
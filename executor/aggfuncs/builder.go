@@ -0,0 +1,127 @@
+package aggfuncs
+
+import (
+	"github.com/pingcap/parser/ast"
+	"grant-db/expression"
+	"grant-db/expression/aggregation"
+	"grant-db/sessionctx"
+	"grant-db/types"
+	"grant-db/util/chunk"
+	"grant-db/util/collate"
+)
+
+// Build is used to build a specific AggFunc implementation according to the
+// input aggFuncDesc, ordinal is the column of the output chunk the result is
+// appended to.
+func Build(ctx sessionctx.Context, aggFuncDesc *aggregation.AggFuncDesc, ordinal int) (AggFunc, error) {
+	base := baseAggFunc{args: aggFuncDesc.Args, ordinal: ordinal}
+	var f AggFunc
+	switch aggFuncDesc.Name {
+	case ast.AggFuncCount:
+		f = &count{base}
+	case ast.AggFuncSum:
+		if aggFuncDesc.RetTp.EvalType() == types.ETDecimal {
+			f = &sum4Decimal{base}
+		} else {
+			f = &sum4Float64{base}
+		}
+	case ast.AggFuncAvg:
+		if aggFuncDesc.RetTp.EvalType() == types.ETDecimal {
+			f = &avg4Decimal{base, aggFuncDesc.RetTp.Decimal}
+		} else {
+			f = &avg4Float64{base}
+		}
+	case ast.AggFuncVarPop, ast.AggFuncVarSamp, ast.AggFuncStddevPop, ast.AggFuncStddevSamp:
+		f = &varPop{
+			baseAggFunc: base,
+			sample:      aggFuncDesc.Name == ast.AggFuncVarSamp || aggFuncDesc.Name == ast.AggFuncStddevSamp,
+			stddev:      aggFuncDesc.Name == ast.AggFuncStddevPop || aggFuncDesc.Name == ast.AggFuncStddevSamp,
+		}
+	case ast.AggFuncBitXor:
+		f = newBitXor(base)
+	case ast.AggFuncGroupConcat:
+		// The last argument is the separator, which is a constant.
+		sepArg := base.args[len(base.args)-1]
+		sep, _, err := sepArg.EvalString(ctx, chunk.Row{})
+		if err != nil {
+			return nil, err
+		}
+		base.args = base.args[:len(base.args)-1]
+		e := groupConcat{baseAggFunc: base, sep: sep, maxLen: ctx.GetSessionVars().GroupConcatMaxLen}
+		if len(aggFuncDesc.OrderByItems) > 0 {
+			f = newGroupConcatOrder(e, aggFuncDesc.OrderByItems)
+		} else {
+			f = &e
+		}
+	case aggregation.AggFuncJSONArrayAgg:
+		f = &jsonArrayagg{base}
+	case ast.AggFuncJsonObjectAgg:
+		f = &jsonObjectAgg{base}
+	// The results of the functions below are the same with or without DISTINCT.
+	case ast.AggFuncMax, ast.AggFuncMin:
+		return buildMaxMin(aggFuncDesc, base), nil
+	case ast.AggFuncFirstRow:
+		return &firstRow{base}, nil
+	case ast.AggFuncBitAnd:
+		return newBitAnd(base), nil
+	case ast.AggFuncBitOr:
+		return newBitOr(base), nil
+	default:
+		return nil, expression.ErrNotSupportedYet.GenWithStackByArgs(aggFuncDesc.Name)
+	}
+	if aggFuncDesc.HasDistinct {
+		f = &distinctAggFunc{AggFunc: f, args: base.args}
+	}
+	return f, nil
+}
+
+func buildMaxMin(aggFuncDesc *aggregation.AggFuncDesc, base baseAggFunc) AggFunc {
+	e := &maxMin{baseAggFunc: base, isMax: aggFuncDesc.Name == ast.AggFuncMax}
+	if argTp := base.args[0].GetType(); argTp.EvalType() == types.ETString {
+		e.collator = collate.GetCollator(argTp.Collate)
+	}
+	return e
+}
+
+// BuildWindowFunctions builds the AggFunc implementation of the window function, ordinal is the column of
+// the output chunk the result is appended to, and orderByCols are the ORDER BY columns of the window which
+// decide the peers of the rows.
+func BuildWindowFunctions(ctx sessionctx.Context, windowFuncDesc *aggregation.WindowFuncDesc, ordinal int,
+	orderByCols []*expression.Column) (AggFunc, error) {
+	base := baseAggFunc{args: windowFuncDesc.Args, ordinal: ordinal}
+	switch windowFuncDesc.Name {
+	case ast.WindowFuncRowNumber:
+		return &rowNumber{baseWindowFunc{base}}, nil
+	case ast.WindowFuncRank, ast.WindowFuncDenseRank:
+		return &rank{
+			baseWindowFunc: baseWindowFunc{base},
+			rowComparer:    buildRowComparer(orderByCols),
+			isDense:        windowFuncDesc.Name == ast.WindowFuncDenseRank,
+		}, nil
+	case ast.WindowFuncPercentRank:
+		return &percentRank{rank{baseWindowFunc: baseWindowFunc{base}, rowComparer: buildRowComparer(orderByCols)}}, nil
+	case ast.WindowFuncCumeDist:
+		return &cumeDist{baseWindowFunc: baseWindowFunc{base}, rowComparer: buildRowComparer(orderByCols)}, nil
+	case ast.WindowFuncNtile:
+		return &ntile{baseWindowFunc{base}, getConstantUint(ctx, base.args[0])}, nil
+	case ast.WindowFuncLead, ast.WindowFuncLag:
+		return &leadLag{
+			baseWindowFunc: baseWindowFunc{base},
+			offset:         getConstantUint(ctx, base.args[1]),
+			isLag:          windowFuncDesc.Name == ast.WindowFuncLag,
+		}, nil
+	case ast.WindowFuncFirstValue:
+		return &firstRow{base}, nil
+	case ast.WindowFuncLastValue:
+		return &lastValue{baseWindowFunc{base}}, nil
+	case ast.WindowFuncNthValue:
+		return &nthValue{baseWindowFunc{base}, getConstantUint(ctx, base.args[1])}, nil
+	}
+	return Build(ctx, &aggregation.AggFuncDesc{Name: windowFuncDesc.Name, Args: windowFuncDesc.Args, RetTp: windowFuncDesc.RetTp}, ordinal)
+}
+
+// getConstantUint returns the value of the constant argument, which is checked by the planner.
+func getConstantUint(ctx sessionctx.Context, arg expression.Expression) uint64 {
+	val, _, _ := arg.EvalInt(ctx, chunk.Row{})
+	return uint64(val)
+}
@@ -0,0 +1,165 @@
+package aggfuncs
+
+import (
+	"grant-db/sessionctx"
+	"grant-db/types"
+	"grant-db/util/chunk"
+)
+
+// avg4Decimal averages the exact numbers, the result is rounded to frac fraction digits.
+type avg4Decimal struct {
+	baseAggFunc
+	frac int
+}
+
+type partialResult4AvgDecimal struct {
+	sum   types.MyDecimal
+	count int64
+}
+
+func (e *avg4Decimal) AllocPartialResult() PartialResult {
+	return PartialResult(&partialResult4AvgDecimal{})
+}
+
+func (e *avg4Decimal) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4AvgDecimal)(pr)
+	p.sum, p.count = types.MyDecimal{}, 0
+}
+
+func (e *avg4Decimal) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4AvgDecimal)(pr)
+	for _, row := range rowsInGroup {
+		val, isNull, err := e.args[0].EvalDecimal(sctx, row)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			continue
+		}
+		if err = types.DecimalAdd(&p.sum, val, &p.sum); err != nil {
+			return err
+		}
+		p.count++
+	}
+	return nil
+}
+
+// Slide implements SlidingWindowAggFunc interface.
+func (e *avg4Decimal) Slide(sctx sessionctx.Context, removed, added []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4AvgDecimal)(pr)
+	for _, row := range removed {
+		val, isNull, err := e.args[0].EvalDecimal(sctx, row)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			continue
+		}
+		if err = types.DecimalSub(&p.sum, val, &p.sum); err != nil {
+			return err
+		}
+		p.count--
+	}
+	return e.UpdatePartialResult(sctx, added, pr)
+}
+
+func (e *avg4Decimal) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) error {
+	p1, p2 := (*partialResult4AvgDecimal)(src), (*partialResult4AvgDecimal)(dst)
+	if p1.count == 0 {
+		return nil
+	}
+	if err := types.DecimalAdd(&p1.sum, &p2.sum, &p2.sum); err != nil {
+		return err
+	}
+	p2.count += p1.count
+	return nil
+}
+
+func (e *avg4Decimal) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4AvgDecimal)(pr)
+	if p.count == 0 {
+		chk.AppendNull(e.ordinal)
+		return nil
+	}
+	result := new(types.MyDecimal)
+	if err := types.DecimalDiv(&p.sum, types.NewDecFromInt(p.count), result, types.DivFracIncr); err != nil {
+		return err
+	}
+	if e.frac != types.UnspecifiedLength {
+		result.Round(result, e.frac)
+	}
+	chk.AppendMyDecimal(e.ordinal, result)
+	return nil
+}
+
+// avg4Float64 averages the approximate numbers and the others.
+type avg4Float64 struct {
+	baseAggFunc
+}
+
+type partialResult4AvgFloat64 struct {
+	sum   float64
+	count int64
+}
+
+func (e *avg4Float64) AllocPartialResult() PartialResult {
+	return PartialResult(&partialResult4AvgFloat64{})
+}
+
+func (e *avg4Float64) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4AvgFloat64)(pr)
+	p.sum, p.count = 0, 0
+}
+
+func (e *avg4Float64) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4AvgFloat64)(pr)
+	for _, row := range rowsInGroup {
+		val, isNull, err := e.args[0].EvalReal(sctx, row)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			continue
+		}
+		p.sum += val
+		p.count++
+	}
+	return nil
+}
+
+// Slide implements SlidingWindowAggFunc interface.
+func (e *avg4Float64) Slide(sctx sessionctx.Context, removed, added []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4AvgFloat64)(pr)
+	for _, row := range removed {
+		val, isNull, err := e.args[0].EvalReal(sctx, row)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			continue
+		}
+		p.sum -= val
+		p.count--
+	}
+	if p.count == 0 {
+		p.sum = 0
+	}
+	return e.UpdatePartialResult(sctx, added, pr)
+}
+
+func (e *avg4Float64) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) error {
+	p1, p2 := (*partialResult4AvgFloat64)(src), (*partialResult4AvgFloat64)(dst)
+	p2.sum += p1.sum
+	p2.count += p1.count
+	return nil
+}
+
+func (e *avg4Float64) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4AvgFloat64)(pr)
+	if p.count == 0 {
+		chk.AppendNull(e.ordinal)
+		return nil
+	}
+	chk.AppendFloat64(e.ordinal, p.sum/float64(p.count))
+	return nil
+}
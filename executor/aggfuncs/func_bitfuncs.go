@@ -0,0 +1,65 @@
+package aggfuncs
+
+import (
+	"math"
+
+	"grant-db/sessionctx"
+	"grant-db/util/chunk"
+)
+
+type partialResult4BitFunc = uint64
+
+// baseBitAggFunc evaluates the argument as an integer, whose bits are taken as an unsigned one. The NULLs
+// are ignored, and the result of no values is the initial value.
+type baseBitAggFunc struct {
+	baseAggFunc
+	initVal uint64
+	op      func(a, b uint64) uint64
+}
+
+func (e *baseBitAggFunc) AllocPartialResult() PartialResult {
+	p := new(partialResult4BitFunc)
+	*p = e.initVal
+	return PartialResult(p)
+}
+
+func (e *baseBitAggFunc) ResetPartialResult(pr PartialResult) {
+	*(*partialResult4BitFunc)(pr) = e.initVal
+}
+
+func (e *baseBitAggFunc) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4BitFunc)(pr)
+	for _, row := range rowsInGroup {
+		val, isNull, err := e.args[0].EvalInt(sctx, row)
+		if err != nil {
+			return err
+		}
+		if !isNull {
+			*p = e.op(*p, uint64(val))
+		}
+	}
+	return nil
+}
+
+func (e *baseBitAggFunc) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) error {
+	p := (*partialResult4BitFunc)(dst)
+	*p = e.op(*p, *(*partialResult4BitFunc)(src))
+	return nil
+}
+
+func (e *baseBitAggFunc) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	chk.AppendUint64(e.ordinal, *(*partialResult4BitFunc)(pr))
+	return nil
+}
+
+func newBitAnd(base baseAggFunc) *baseBitAggFunc {
+	return &baseBitAggFunc{baseAggFunc: base, initVal: math.MaxUint64, op: func(a, b uint64) uint64 { return a & b }}
+}
+
+func newBitOr(base baseAggFunc) *baseBitAggFunc {
+	return &baseBitAggFunc{baseAggFunc: base, op: func(a, b uint64) uint64 { return a | b }}
+}
+
+func newBitXor(base baseAggFunc) *baseBitAggFunc {
+	return &baseBitAggFunc{baseAggFunc: base, op: func(a, b uint64) uint64 { return a ^ b }}
+}
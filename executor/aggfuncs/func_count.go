@@ -0,0 +1,60 @@
+package aggfuncs
+
+import (
+	"grant-db/sessionctx"
+	"grant-db/util/chunk"
+)
+
+type count struct {
+	baseAggFunc
+}
+
+type partialResult4Count = int64
+
+func (e *count) AllocPartialResult() PartialResult {
+	return PartialResult(new(partialResult4Count))
+}
+
+func (e *count) ResetPartialResult(pr PartialResult) {
+	*(*partialResult4Count)(pr) = 0
+}
+
+// UpdatePartialResult counts the rows whose arguments are all not NULL.
+func (e *count) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4Count)(pr)
+	for _, row := range rowsInGroup {
+		hasNull, err := hasNullArg(e.args, row)
+		if err != nil {
+			return err
+		}
+		if !hasNull {
+			*p++
+		}
+	}
+	return nil
+}
+
+// Slide implements SlidingWindowAggFunc interface.
+func (e *count) Slide(sctx sessionctx.Context, removed, added []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4Count)(pr)
+	for _, row := range removed {
+		hasNull, err := hasNullArg(e.args, row)
+		if err != nil {
+			return err
+		}
+		if !hasNull {
+			*p--
+		}
+	}
+	return e.UpdatePartialResult(sctx, added, pr)
+}
+
+func (e *count) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) error {
+	*(*partialResult4Count)(dst) += *(*partialResult4Count)(src)
+	return nil
+}
+
+func (e *count) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	chk.AppendInt64(e.ordinal, *(*partialResult4Count)(pr))
+	return nil
+}
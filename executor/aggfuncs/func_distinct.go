@@ -0,0 +1,83 @@
+package aggfuncs
+
+import (
+	"grant-db/expression"
+	"grant-db/sessionctx"
+	"grant-db/types"
+	"grant-db/util/chunk"
+	"grant-db/util/codec"
+	"grant-db/util/collate"
+)
+
+// distinctAggFunc evaluates the aggregate function on the rows of the distinct values of the arguments, the
+// rows of any NULL arguments are ignored. The strings are distinct by their collations.
+type distinctAggFunc struct {
+	AggFunc
+	args []expression.Expression
+}
+
+type partialResult4Distinct struct {
+	valSet map[string]struct{}
+	pr     PartialResult
+}
+
+func (e *distinctAggFunc) AllocPartialResult() PartialResult {
+	return PartialResult(&partialResult4Distinct{valSet: make(map[string]struct{}), pr: e.AggFunc.AllocPartialResult()})
+}
+
+func (e *distinctAggFunc) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4Distinct)(pr)
+	p.valSet = make(map[string]struct{})
+	e.AggFunc.ResetPartialResult(p.pr)
+}
+
+// encodeKey encodes the values of the arguments of the row, hasNull is true if any of them is NULL.
+func (e *distinctAggFunc) encodeKey(row chunk.Row) (key []byte, hasNull bool, err error) {
+	for _, arg := range e.args {
+		val, err := arg.Eval(row)
+		if err != nil {
+			return nil, false, err
+		}
+		if val.IsNull() {
+			return nil, true, nil
+		}
+		if tp := arg.GetType(); tp.EvalType() == types.ETString {
+			val.SetBytes(collate.GetCollator(tp.Collate).Key(val.GetString()))
+		}
+		if key, err = codec.EncodeValue(key, val); err != nil {
+			return nil, false, err
+		}
+	}
+	return key, false, nil
+}
+
+func (e *distinctAggFunc) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4Distinct)(pr)
+	for i, row := range rowsInGroup {
+		key, hasNull, err := e.encodeKey(row)
+		if err != nil {
+			return err
+		}
+		if hasNull {
+			continue
+		}
+		if _, ok := p.valSet[string(key)]; ok {
+			continue
+		}
+		p.valSet[string(key)] = struct{}{}
+		if err = e.AggFunc.UpdatePartialResult(sctx, rowsInGroup[i:i+1], p.pr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MergePartialResult implements AggFunc interface, the values of the partial results aren't kept so they
+// can't be merged.
+func (e *distinctAggFunc) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) error {
+	return expression.ErrNotSupportedYet.GenWithStackByArgs("merging the partial results of DISTINCT aggregate functions")
+}
+
+func (e *distinctAggFunc) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	return e.AggFunc.AppendFinalResult2Chunk(sctx, (*partialResult4Distinct)(pr).pr, chk)
+}
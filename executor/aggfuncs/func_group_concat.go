@@ -0,0 +1,239 @@
+package aggfuncs
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/pingcap/parser/mysql"
+	"grant-db/expression"
+	"grant-db/expression/aggregation"
+	"grant-db/sessionctx"
+	"grant-db/types"
+	"grant-db/util/chunk"
+)
+
+// groupConcat concatenates the values of the arguments of the rows by the separator, the rows of any NULL
+// arguments are ignored. The result is truncated to maxLen bytes with a warning.
+type groupConcat struct {
+	baseAggFunc
+	sep    string
+	maxLen int
+}
+
+type partialResult4GroupConcat struct {
+	// buffer is nil if there is no row yet.
+	buffer    *bytes.Buffer
+	truncated bool
+}
+
+func (e *groupConcat) AllocPartialResult() PartialResult {
+	return PartialResult(&partialResult4GroupConcat{})
+}
+
+func (e *groupConcat) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4GroupConcat)(pr)
+	p.buffer, p.truncated = nil, false
+}
+
+// UpdatePartialResult appends the values of the rows, the values of a row are removed from the buffer if
+// any of them is NULL.
+func (e *groupConcat) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4GroupConcat)(pr)
+	for _, row := range rowsInGroup {
+		if p.truncated {
+			return nil
+		}
+		isFirst := p.buffer == nil
+		if isFirst {
+			p.buffer = new(bytes.Buffer)
+		}
+		oldLen := p.buffer.Len()
+		if !isFirst {
+			p.buffer.WriteString(e.sep)
+		}
+		for _, arg := range e.args {
+			val, isNull, err := arg.EvalString(sctx, row)
+			if err != nil {
+				return err
+			}
+			if isNull {
+				if isFirst {
+					p.buffer = nil
+				} else {
+					p.buffer.Truncate(oldLen)
+				}
+				break
+			}
+			p.buffer.WriteString(val)
+		}
+		e.truncate(sctx, p)
+	}
+	return nil
+}
+
+func (e *groupConcat) truncate(sctx sessionctx.Context, p *partialResult4GroupConcat) {
+	if p.buffer == nil || p.buffer.Len() <= e.maxLen {
+		return
+	}
+	p.buffer.Truncate(e.maxLen)
+	// The merged partial result of a truncated one is warned already.
+	if !p.truncated {
+		sctx.GetSessionVars().StmtCtx.AppendWarning(expression.ErrCutValueGroupConcat.GenWithStackByArgs(e.args[0].String()))
+	}
+	p.truncated = true
+}
+
+func (e *groupConcat) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) error {
+	p1, p2 := (*partialResult4GroupConcat)(src), (*partialResult4GroupConcat)(dst)
+	if p1.buffer == nil || p2.truncated {
+		return nil
+	}
+	if p2.buffer == nil {
+		p2.buffer = new(bytes.Buffer)
+	} else {
+		p2.buffer.WriteString(e.sep)
+	}
+	p2.buffer.Write(p1.buffer.Bytes())
+	p2.truncated = p1.truncated
+	e.truncate(sctx, p2)
+	return nil
+}
+
+func (e *groupConcat) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4GroupConcat)(pr)
+	if p.buffer == nil {
+		chk.AppendNull(e.ordinal)
+		return nil
+	}
+	chk.AppendString(e.ordinal, p.buffer.String())
+	return nil
+}
+
+// groupConcatOrder is GROUP_CONCAT with ORDER BY, the values of the rows are kept with their sort keys, and
+// they're concatenated in order when the final result is appended.
+type groupConcatOrder struct {
+	groupConcat
+	byItems []*aggregation.ByItem
+	// keyTps are the types of the sort keys and the value, which is the last column of the rows.
+	keyTps   []*types.FieldType
+	cmpFuncs []chunk.CompareFunc
+}
+
+type partialResult4GroupConcatOrder struct {
+	// rows is nil if there is no row yet.
+	rows *chunk.Chunk
+}
+
+func newGroupConcatOrder(base groupConcat, byItems []*aggregation.ByItem) *groupConcatOrder {
+	e := &groupConcatOrder{groupConcat: base, byItems: byItems}
+	for _, item := range byItems {
+		tp := item.Expr.GetType()
+		e.keyTps = append(e.keyTps, tp)
+		e.cmpFuncs = append(e.cmpFuncs, chunk.GetCompareFunc(tp))
+	}
+	e.keyTps = append(e.keyTps, types.NewFieldType(mysql.TypeVarString))
+	return e
+}
+
+func (e *groupConcatOrder) AllocPartialResult() PartialResult {
+	return PartialResult(&partialResult4GroupConcatOrder{})
+}
+
+func (e *groupConcatOrder) ResetPartialResult(pr PartialResult) {
+	(*partialResult4GroupConcatOrder)(pr).rows = nil
+}
+
+// UpdatePartialResult keeps the sort keys and the value of the rows, the rows of any NULL arguments are
+// ignored.
+func (e *groupConcatOrder) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4GroupConcatOrder)(pr)
+	var buffer bytes.Buffer
+	for _, row := range rowsInGroup {
+		buffer.Reset()
+		isNull := false
+		for _, arg := range e.args {
+			var val string
+			var err error
+			val, isNull, err = arg.EvalString(sctx, row)
+			if err != nil {
+				return err
+			}
+			if isNull {
+				break
+			}
+			buffer.WriteString(val)
+		}
+		if isNull {
+			continue
+		}
+		if p.rows == nil {
+			p.rows = chunk.New(e.keyTps, 1, 1)
+		}
+		for i, item := range e.byItems {
+			key, err := item.Expr.Eval(row)
+			if err != nil {
+				return err
+			}
+			p.rows.AppendDatum(i, &key)
+		}
+		p.rows.AppendString(len(e.byItems), buffer.String())
+	}
+	return nil
+}
+
+func (e *groupConcatOrder) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) error {
+	p1, p2 := (*partialResult4GroupConcatOrder)(src), (*partialResult4GroupConcatOrder)(dst)
+	if p1.rows == nil {
+		return nil
+	}
+	if p2.rows == nil {
+		p2.rows = chunk.New(e.keyTps, 1, 1)
+	}
+	p2.rows.Append(p1.rows, 0, p1.rows.NumRows())
+	return nil
+}
+
+// AppendFinalResult2Chunk sorts the rows stably by the keys and concatenates their values, the result is
+// truncated to maxLen bytes.
+func (e *groupConcatOrder) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4GroupConcatOrder)(pr)
+	if p.rows == nil {
+		chk.AppendNull(e.ordinal)
+		return nil
+	}
+	idx := make([]int, p.rows.NumRows())
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		return e.less(p.rows.GetRow(idx[i]), p.rows.GetRow(idx[j]))
+	})
+	result := &partialResult4GroupConcat{buffer: new(bytes.Buffer)}
+	valCol := len(e.byItems)
+	for i, rowIdx := range idx {
+		if i > 0 {
+			result.buffer.WriteString(e.sep)
+		}
+		result.buffer.WriteString(p.rows.GetRow(rowIdx).GetString(valCol))
+		if result.buffer.Len() > e.maxLen {
+			break
+		}
+	}
+	e.truncate(sctx, result)
+	chk.AppendString(e.ordinal, result.buffer.String())
+	return nil
+}
+
+func (e *groupConcatOrder) less(lhs, rhs chunk.Row) bool {
+	for i, cmpFunc := range e.cmpFuncs {
+		cmp := cmpFunc(lhs, i, rhs, i)
+		if cmp == 0 {
+			continue
+		}
+		if e.byItems[i].Desc {
+			return cmp > 0
+		}
+		return cmp < 0
+	}
+	return false
+}
@@ -0,0 +1,59 @@
+package aggfuncs
+
+import (
+	"grant-db/sessionctx"
+	"grant-db/types/json"
+	"grant-db/util/chunk"
+)
+
+type jsonArrayagg struct {
+	baseAggFunc
+}
+
+type partialResult4JSONArrayagg struct {
+	entries []json.BinaryJSON
+}
+
+func (e *jsonArrayagg) AllocPartialResult() PartialResult {
+	return PartialResult(&partialResult4JSONArrayagg{})
+}
+
+func (e *jsonArrayagg) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4JSONArrayagg)(pr)
+	p.entries = p.entries[:0]
+}
+
+// UpdatePartialResult appends the values of the rows, a NULL is the JSON null. The values are copied
+// since they reference the memory of the chunk.
+func (e *jsonArrayagg) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4JSONArrayagg)(pr)
+	for _, row := range rowsInGroup {
+		val, isNull, err := e.args[0].EvalJSON(sctx, row)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			p.entries = append(p.entries, json.CreateBinary(nil))
+			continue
+		}
+		p.entries = append(p.entries, val.Copy())
+	}
+	return nil
+}
+
+func (e *jsonArrayagg) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) error {
+	p1, p2 := (*partialResult4JSONArrayagg)(src), (*partialResult4JSONArrayagg)(dst)
+	p2.entries = append(p2.entries, p1.entries...)
+	return nil
+}
+
+// AppendFinalResult2Chunk appends the array of the values, it's NULL if the group has no rows.
+func (e *jsonArrayagg) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4JSONArrayagg)(pr)
+	if len(p.entries) == 0 {
+		chk.AppendNull(e.ordinal)
+		return nil
+	}
+	chk.AppendJSON(e.ordinal, json.CreateBinary(p.entries))
+	return nil
+}
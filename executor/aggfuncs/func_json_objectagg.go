@@ -0,0 +1,71 @@
+package aggfuncs
+
+import (
+	"grant-db/sessionctx"
+	"grant-db/types/json"
+	"grant-db/util/chunk"
+)
+
+type jsonObjectAgg struct {
+	baseAggFunc
+}
+
+type partialResult4JSONObjectAgg struct {
+	entries map[string]interface{}
+}
+
+func (e *jsonObjectAgg) AllocPartialResult() PartialResult {
+	return PartialResult(&partialResult4JSONObjectAgg{entries: make(map[string]interface{})})
+}
+
+func (e *jsonObjectAgg) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4JSONObjectAgg)(pr)
+	p.entries = make(map[string]interface{})
+}
+
+// UpdatePartialResult puts the key-value pairs of the rows, a key can't be NULL, a NULL value is the
+// JSON null, and the last value of a duplicated key wins.
+func (e *jsonObjectAgg) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4JSONObjectAgg)(pr)
+	for _, row := range rowsInGroup {
+		key, isNull, err := e.args[0].EvalString(sctx, row)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			return json.ErrJSONDocumentNULLKey
+		}
+		val, isNull, err := e.args[1].EvalJSON(sctx, row)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			p.entries[key] = json.CreateBinary(nil)
+			continue
+		}
+		// The value references the memory of the chunk.
+		p.entries[key] = val.Copy()
+	}
+	return nil
+}
+
+// MergePartialResult puts the pairs of src into dst, the values of src win since the order of the rows of
+// the partial results is unknown.
+func (e *jsonObjectAgg) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) error {
+	p1, p2 := (*partialResult4JSONObjectAgg)(src), (*partialResult4JSONObjectAgg)(dst)
+	for key, val := range p1.entries {
+		p2.entries[key] = val
+	}
+	return nil
+}
+
+// AppendFinalResult2Chunk appends the object of the pairs, it's NULL if the group has no rows.
+func (e *jsonObjectAgg) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4JSONObjectAgg)(pr)
+	if len(p.entries) == 0 {
+		chk.AppendNull(e.ordinal)
+		return nil
+	}
+	chk.AppendJSON(e.ordinal, json.CreateBinary(p.entries))
+	return nil
+}
@@ -0,0 +1,55 @@
+package aggfuncs
+
+import (
+	"grant-db/sessionctx"
+	"grant-db/util/chunk"
+)
+
+// leadLag returns the value of the row which is offset rows after (LEAD) or before (LAG) the row in the
+// partition, the default value evaluated on the row is returned if there is no such row.
+type leadLag struct {
+	baseWindowFunc
+	offset uint64
+	isLag  bool
+}
+
+type partialResult4LeadLag struct {
+	curIdx uint64
+	rows   []chunk.Row
+}
+
+func (e *leadLag) AllocPartialResult() PartialResult {
+	return PartialResult(&partialResult4LeadLag{})
+}
+
+func (e *leadLag) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4LeadLag)(pr)
+	p.curIdx, p.rows = 0, p.rows[:0]
+}
+
+func (e *leadLag) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4LeadLag)(pr)
+	p.rows = append(p.rows, rowsInGroup...)
+	return nil
+}
+
+func (e *leadLag) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4LeadLag)(pr)
+	cur := p.rows[p.curIdx]
+	p.curIdx++
+	// The offset is checked before it's added to avoid overflows.
+	expr, row := e.args[2], cur
+	if e.isLag {
+		if e.offset < p.curIdx {
+			expr, row = e.args[0], p.rows[p.curIdx-1-e.offset]
+		}
+	} else if e.offset < uint64(len(p.rows))-p.curIdx+1 {
+		expr, row = e.args[0], p.rows[p.curIdx-1+e.offset]
+	}
+	val, err := expr.Eval(row)
+	if err != nil {
+		return err
+	}
+	chk.AppendDatum(e.ordinal, &val)
+	return nil
+}
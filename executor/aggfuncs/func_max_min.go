@@ -0,0 +1,137 @@
+package aggfuncs
+
+import (
+	"grant-db/sessionctx"
+	"grant-db/types"
+	"grant-db/util/chunk"
+	"grant-db/util/collate"
+)
+
+// maxMin keeps the max or the min value of the argument, the strings are compared by the collation of the
+// argument, and the enums and the sets are compared by their names like MySQL does.
+type maxMin struct {
+	baseAggFunc
+	isMax bool
+	// collator is nil if the argument isn't a string.
+	collator collate.Collator
+}
+
+type partialResult4MaxMin struct {
+	// val is NULL if there is no value yet.
+	val types.Datum
+}
+
+func (e *maxMin) AllocPartialResult() PartialResult {
+	return PartialResult(&partialResult4MaxMin{})
+}
+
+func (e *maxMin) ResetPartialResult(pr PartialResult) {
+	(*partialResult4MaxMin)(pr).val.SetNull()
+}
+
+// better returns whether the value a should replace the value b.
+func (e *maxMin) better(sctx sessionctx.Context, a, b *types.Datum) (bool, error) {
+	if b.IsNull() {
+		return true, nil
+	}
+	var cmp int
+	if e.collator != nil {
+		cmp = e.collator.Compare(a.GetString(), b.GetString())
+	} else {
+		var err error
+		if cmp, err = a.CompareDatum(sctx.GetSessionVars().StmtCtx, b); err != nil {
+			return false, err
+		}
+	}
+	if e.isMax {
+		return cmp > 0, nil
+	}
+	return cmp < 0, nil
+}
+
+// UpdatePartialResult keeps the max or the min value of the rows, the value is copied since it references
+// the memory of the chunk.
+func (e *maxMin) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4MaxMin)(pr)
+	for _, row := range rowsInGroup {
+		val, err := e.args[0].Eval(row)
+		if err != nil {
+			return err
+		}
+		if val.IsNull() {
+			continue
+		}
+		better, err := e.better(sctx, &val, &p.val)
+		if err != nil {
+			return err
+		}
+		if better {
+			val.Copy(&p.val)
+		}
+	}
+	return nil
+}
+
+func (e *maxMin) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) error {
+	p1, p2 := (*partialResult4MaxMin)(src), (*partialResult4MaxMin)(dst)
+	if p1.val.IsNull() {
+		return nil
+	}
+	better, err := e.better(sctx, &p1.val, &p2.val)
+	if better {
+		p2.val = p1.val
+	}
+	return err
+}
+
+func (e *maxMin) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	chk.AppendDatum(e.ordinal, &(*partialResult4MaxMin)(pr).val)
+	return nil
+}
+
+// firstRow keeps the value of the first row of the group, which may be NULL.
+type firstRow struct {
+	baseAggFunc
+}
+
+type partialResult4FirstRow struct {
+	val         types.Datum
+	gotFirstRow bool
+}
+
+func (e *firstRow) AllocPartialResult() PartialResult {
+	return PartialResult(&partialResult4FirstRow{})
+}
+
+func (e *firstRow) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4FirstRow)(pr)
+	p.val.SetNull()
+	p.gotFirstRow = false
+}
+
+func (e *firstRow) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4FirstRow)(pr)
+	if p.gotFirstRow || len(rowsInGroup) == 0 {
+		return nil
+	}
+	val, err := e.args[0].Eval(rowsInGroup[0])
+	if err != nil {
+		return err
+	}
+	val.Copy(&p.val)
+	p.gotFirstRow = true
+	return nil
+}
+
+func (e *firstRow) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) error {
+	p1, p2 := (*partialResult4FirstRow)(src), (*partialResult4FirstRow)(dst)
+	if !p2.gotFirstRow {
+		*p2 = *p1
+	}
+	return nil
+}
+
+func (e *firstRow) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	chk.AppendDatum(e.ordinal, &(*partialResult4FirstRow)(pr).val)
+	return nil
+}
@@ -0,0 +1,47 @@
+package aggfuncs
+
+import (
+	"grant-db/sessionctx"
+	"grant-db/util/chunk"
+)
+
+// ntile divides the rows of the partition into n buckets as evenly as possible, the first buckets have one
+// more row than the others if the rows can't be divided evenly.
+type ntile struct {
+	baseWindowFunc
+	n uint64
+}
+
+type partialResult4Ntile struct {
+	curIdx  uint64
+	numRows uint64
+}
+
+func (e *ntile) AllocPartialResult() PartialResult {
+	return PartialResult(&partialResult4Ntile{})
+}
+
+func (e *ntile) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4Ntile)(pr)
+	p.curIdx, p.numRows = 0, 0
+}
+
+func (e *ntile) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	(*partialResult4Ntile)(pr).numRows += uint64(len(rowsInGroup))
+	return nil
+}
+
+func (e *ntile) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4Ntile)(pr)
+	size, remainder := p.numRows/e.n, p.numRows%e.n
+	// The rows of the first remainder buckets are size+1 each.
+	var bucket uint64
+	if bigRows := remainder * (size + 1); p.curIdx < bigRows {
+		bucket = p.curIdx/(size+1) + 1
+	} else {
+		bucket = remainder + (p.curIdx-bigRows)/size + 1
+	}
+	p.curIdx++
+	chk.AppendInt64(e.ordinal, int64(bucket))
+	return nil
+}
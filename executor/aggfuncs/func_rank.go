@@ -0,0 +1,171 @@
+package aggfuncs
+
+import (
+	"grant-db/expression"
+	"grant-db/sessionctx"
+	"grant-db/util/chunk"
+)
+
+// rowComparer compares the rows by the ORDER BY columns of the window, the rows are peers if they're
+// equal. All the rows are peers if there is no column.
+type rowComparer struct {
+	cmpFuncs []chunk.CompareFunc
+	colIdx   []int
+}
+
+func buildRowComparer(cols []*expression.Column) rowComparer {
+	c := rowComparer{cmpFuncs: make([]chunk.CompareFunc, 0, len(cols)), colIdx: make([]int, 0, len(cols))}
+	for _, col := range cols {
+		c.cmpFuncs = append(c.cmpFuncs, chunk.GetCompareFunc(col.RetType))
+		c.colIdx = append(c.colIdx, col.Index)
+	}
+	return c
+}
+
+func (c *rowComparer) isPeer(lhs, rhs chunk.Row) bool {
+	for i, cmpFunc := range c.cmpFuncs {
+		if cmpFunc(lhs, c.colIdx[i], rhs, c.colIdx[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// rowNumber numbers the rows of the partition from 1.
+type rowNumber struct {
+	baseWindowFunc
+}
+
+type partialResult4RowNumber struct {
+	curIdx int64
+}
+
+func (e *rowNumber) AllocPartialResult() PartialResult {
+	return PartialResult(&partialResult4RowNumber{})
+}
+
+func (e *rowNumber) ResetPartialResult(pr PartialResult) {
+	(*partialResult4RowNumber)(pr).curIdx = 0
+}
+
+func (e *rowNumber) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	return nil
+}
+
+func (e *rowNumber) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4RowNumber)(pr)
+	p.curIdx++
+	chk.AppendInt64(e.ordinal, p.curIdx)
+	return nil
+}
+
+// rank ranks the rows of the partition by the ORDER BY columns, the peers have the same rank. The ranks of
+// RANK have gaps after the peers and the ones of DENSE_RANK don't.
+type rank struct {
+	baseWindowFunc
+	rowComparer
+	isDense bool
+}
+
+// partialResult4Rank is shared by the functions which rank the rows, curIdx is the count of the appended
+// rows and lastRank is the rank of the last one.
+type partialResult4Rank struct {
+	curIdx   int64
+	lastRank int64
+	rows     []chunk.Row
+}
+
+func (p *partialResult4Rank) reset() {
+	p.curIdx, p.lastRank, p.rows = 0, 0, p.rows[:0]
+}
+
+// nextRank advances to the next row and returns its rank.
+func (p *partialResult4Rank) nextRank(c *rowComparer, isDense bool) int64 {
+	p.curIdx++
+	switch {
+	case p.curIdx == 1:
+		p.lastRank = 1
+	case c.isPeer(p.rows[p.curIdx-2], p.rows[p.curIdx-1]):
+	case isDense:
+		p.lastRank++
+	default:
+		p.lastRank = p.curIdx
+	}
+	return p.lastRank
+}
+
+func (e *rank) AllocPartialResult() PartialResult {
+	return PartialResult(&partialResult4Rank{})
+}
+
+func (e *rank) ResetPartialResult(pr PartialResult) {
+	(*partialResult4Rank)(pr).reset()
+}
+
+func (e *rank) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4Rank)(pr)
+	p.rows = append(p.rows, rowsInGroup...)
+	return nil
+}
+
+func (e *rank) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	chk.AppendInt64(e.ordinal, (*partialResult4Rank)(pr).nextRank(&e.rowComparer, e.isDense))
+	return nil
+}
+
+// percentRank is (rank - 1) / (rows of the partition - 1), it's 0 if the partition has one row.
+type percentRank struct {
+	rank
+}
+
+func (e *percentRank) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4Rank)(pr)
+	r := p.nextRank(&e.rowComparer, false)
+	if len(p.rows) <= 1 {
+		chk.AppendFloat64(e.ordinal, 0)
+		return nil
+	}
+	chk.AppendFloat64(e.ordinal, float64(r-1)/float64(len(p.rows)-1))
+	return nil
+}
+
+// cumeDist is the count of the rows before the row and its peers divided by the rows of the partition.
+type cumeDist struct {
+	baseWindowFunc
+	rowComparer
+}
+
+type partialResult4CumeDist struct {
+	curIdx int
+	// peerEnd is the offset behind the last peer of the current row.
+	peerEnd int
+	rows    []chunk.Row
+}
+
+func (e *cumeDist) AllocPartialResult() PartialResult {
+	return PartialResult(&partialResult4CumeDist{})
+}
+
+func (e *cumeDist) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4CumeDist)(pr)
+	p.curIdx, p.peerEnd, p.rows = 0, 0, p.rows[:0]
+}
+
+func (e *cumeDist) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4CumeDist)(pr)
+	p.rows = append(p.rows, rowsInGroup...)
+	return nil
+}
+
+func (e *cumeDist) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4CumeDist)(pr)
+	if p.curIdx >= p.peerEnd {
+		p.peerEnd = p.curIdx + 1
+		for p.peerEnd < len(p.rows) && e.isPeer(p.rows[p.curIdx], p.rows[p.peerEnd]) {
+			p.peerEnd++
+		}
+	}
+	p.curIdx++
+	chk.AppendFloat64(e.ordinal, float64(p.peerEnd)/float64(len(p.rows)))
+	return nil
+}
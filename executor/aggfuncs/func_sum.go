@@ -0,0 +1,159 @@
+package aggfuncs
+
+import (
+	"grant-db/sessionctx"
+	"grant-db/types"
+	"grant-db/util/chunk"
+)
+
+// sum4Decimal sums the exact numbers, whose arguments are casted to decimals.
+type sum4Decimal struct {
+	baseAggFunc
+}
+
+type partialResult4SumDecimal struct {
+	val types.MyDecimal
+	// notNullRowCount is the number of the values, the sum is NULL if there is none.
+	notNullRowCount int64
+}
+
+func (e *sum4Decimal) AllocPartialResult() PartialResult {
+	return PartialResult(&partialResult4SumDecimal{})
+}
+
+func (e *sum4Decimal) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4SumDecimal)(pr)
+	p.val, p.notNullRowCount = types.MyDecimal{}, 0
+}
+
+func (e *sum4Decimal) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4SumDecimal)(pr)
+	for _, row := range rowsInGroup {
+		val, isNull, err := e.args[0].EvalDecimal(sctx, row)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			continue
+		}
+		if err = types.DecimalAdd(&p.val, val, &p.val); err != nil {
+			return err
+		}
+		p.notNullRowCount++
+	}
+	return nil
+}
+
+// Slide implements SlidingWindowAggFunc interface.
+func (e *sum4Decimal) Slide(sctx sessionctx.Context, removed, added []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4SumDecimal)(pr)
+	for _, row := range removed {
+		val, isNull, err := e.args[0].EvalDecimal(sctx, row)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			continue
+		}
+		if err = types.DecimalSub(&p.val, val, &p.val); err != nil {
+			return err
+		}
+		p.notNullRowCount--
+	}
+	return e.UpdatePartialResult(sctx, added, pr)
+}
+
+func (e *sum4Decimal) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) error {
+	p1, p2 := (*partialResult4SumDecimal)(src), (*partialResult4SumDecimal)(dst)
+	if p1.notNullRowCount == 0 {
+		return nil
+	}
+	if err := types.DecimalAdd(&p1.val, &p2.val, &p2.val); err != nil {
+		return err
+	}
+	p2.notNullRowCount += p1.notNullRowCount
+	return nil
+}
+
+func (e *sum4Decimal) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4SumDecimal)(pr)
+	if p.notNullRowCount == 0 {
+		chk.AppendNull(e.ordinal)
+		return nil
+	}
+	chk.AppendMyDecimal(e.ordinal, &p.val)
+	return nil
+}
+
+// sum4Float64 sums the approximate numbers and the others, whose arguments are casted to doubles.
+type sum4Float64 struct {
+	baseAggFunc
+}
+
+type partialResult4SumFloat64 struct {
+	val             float64
+	notNullRowCount int64
+}
+
+func (e *sum4Float64) AllocPartialResult() PartialResult {
+	return PartialResult(&partialResult4SumFloat64{})
+}
+
+func (e *sum4Float64) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4SumFloat64)(pr)
+	p.val, p.notNullRowCount = 0, 0
+}
+
+func (e *sum4Float64) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4SumFloat64)(pr)
+	for _, row := range rowsInGroup {
+		val, isNull, err := e.args[0].EvalReal(sctx, row)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			continue
+		}
+		p.val += val
+		p.notNullRowCount++
+	}
+	return nil
+}
+
+// Slide implements SlidingWindowAggFunc interface, the sum is reset once there is no value to drop the
+// rounding errors of the removed values.
+func (e *sum4Float64) Slide(sctx sessionctx.Context, removed, added []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4SumFloat64)(pr)
+	for _, row := range removed {
+		val, isNull, err := e.args[0].EvalReal(sctx, row)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			continue
+		}
+		p.val -= val
+		p.notNullRowCount--
+	}
+	if p.notNullRowCount == 0 {
+		p.val = 0
+	}
+	return e.UpdatePartialResult(sctx, added, pr)
+}
+
+func (e *sum4Float64) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) error {
+	p1, p2 := (*partialResult4SumFloat64)(src), (*partialResult4SumFloat64)(dst)
+	p2.val += p1.val
+	p2.notNullRowCount += p1.notNullRowCount
+	return nil
+}
+
+func (e *sum4Float64) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4SumFloat64)(pr)
+	if p.notNullRowCount == 0 {
+		chk.AppendNull(e.ordinal)
+		return nil
+	}
+	chk.AppendFloat64(e.ordinal, p.val)
+	return nil
+}
@@ -0,0 +1,81 @@
+package aggfuncs
+
+import (
+	"grant-db/sessionctx"
+	"grant-db/types"
+	"grant-db/util/chunk"
+)
+
+// lastValue keeps the value of the last row of the frame, it's NULL if the frame is empty.
+type lastValue struct {
+	baseWindowFunc
+}
+
+type partialResult4LastValue struct {
+	val types.Datum
+}
+
+func (e *lastValue) AllocPartialResult() PartialResult {
+	return PartialResult(&partialResult4LastValue{})
+}
+
+func (e *lastValue) ResetPartialResult(pr PartialResult) {
+	(*partialResult4LastValue)(pr).val.SetNull()
+}
+
+func (e *lastValue) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	if len(rowsInGroup) == 0 {
+		return nil
+	}
+	val, err := e.args[0].Eval(rowsInGroup[len(rowsInGroup)-1])
+	if err != nil {
+		return err
+	}
+	val.Copy(&(*partialResult4LastValue)(pr).val)
+	return nil
+}
+
+func (e *lastValue) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	chk.AppendDatum(e.ordinal, &(*partialResult4LastValue)(pr).val)
+	return nil
+}
+
+// nthValue keeps the value of the nth row of the frame, it's NULL if the frame has less than n rows.
+type nthValue struct {
+	baseWindowFunc
+	n uint64
+}
+
+type partialResult4NthValue struct {
+	numRows uint64
+	val     types.Datum
+}
+
+func (e *nthValue) AllocPartialResult() PartialResult {
+	return PartialResult(&partialResult4NthValue{})
+}
+
+func (e *nthValue) ResetPartialResult(pr PartialResult) {
+	p := (*partialResult4NthValue)(pr)
+	p.numRows = 0
+	p.val.SetNull()
+}
+
+func (e *nthValue) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4NthValue)(pr)
+	numRows := uint64(len(rowsInGroup))
+	if p.numRows < e.n && p.numRows+numRows >= e.n {
+		val, err := e.args[0].Eval(rowsInGroup[e.n-p.numRows-1])
+		if err != nil {
+			return err
+		}
+		val.Copy(&p.val)
+	}
+	p.numRows += numRows
+	return nil
+}
+
+func (e *nthValue) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	chk.AppendDatum(e.ordinal, &(*partialResult4NthValue)(pr).val)
+	return nil
+}
@@ -0,0 +1,85 @@
+package aggfuncs
+
+import (
+	"math"
+
+	"grant-db/sessionctx"
+	"grant-db/util/chunk"
+)
+
+// varPop computes the variances and the standard deviations of the argument by Welford's algorithm, the
+// partial results are merged by the algorithm of Chan et al.
+type varPop struct {
+	baseAggFunc
+	// sample is true for VAR_SAMP and STDDEV_SAMP, whose results are NULL if there are less than two values.
+	sample bool
+	stddev bool
+}
+
+type partialResult4VarPop struct {
+	count int64
+	mean  float64
+	// m2 is the sum of the squares of the differences from the mean.
+	m2 float64
+}
+
+func (e *varPop) AllocPartialResult() PartialResult {
+	return PartialResult(&partialResult4VarPop{})
+}
+
+func (e *varPop) ResetPartialResult(pr PartialResult) {
+	*(*partialResult4VarPop)(pr) = partialResult4VarPop{}
+}
+
+func (e *varPop) UpdatePartialResult(sctx sessionctx.Context, rowsInGroup []chunk.Row, pr PartialResult) error {
+	p := (*partialResult4VarPop)(pr)
+	for _, row := range rowsInGroup {
+		val, isNull, err := e.args[0].EvalReal(sctx, row)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			continue
+		}
+		p.count++
+		delta := val - p.mean
+		p.mean += delta / float64(p.count)
+		p.m2 += delta * (val - p.mean)
+	}
+	return nil
+}
+
+func (e *varPop) MergePartialResult(sctx sessionctx.Context, src, dst PartialResult) error {
+	p1, p2 := (*partialResult4VarPop)(src), (*partialResult4VarPop)(dst)
+	if p1.count == 0 {
+		return nil
+	}
+	if p2.count == 0 {
+		*p2 = *p1
+		return nil
+	}
+	count := p1.count + p2.count
+	delta := p1.mean - p2.mean
+	p2.mean += delta * float64(p1.count) / float64(count)
+	p2.m2 += p1.m2 + delta*delta*float64(p1.count)*float64(p2.count)/float64(count)
+	p2.count = count
+	return nil
+}
+
+func (e *varPop) AppendFinalResult2Chunk(sctx sessionctx.Context, pr PartialResult, chk *chunk.Chunk) error {
+	p := (*partialResult4VarPop)(pr)
+	n := p.count
+	if e.sample {
+		n--
+	}
+	if n <= 0 {
+		chk.AppendNull(e.ordinal)
+		return nil
+	}
+	variance := p.m2 / float64(n)
+	if e.stddev {
+		variance = math.Sqrt(variance)
+	}
+	chk.AppendFloat64(e.ordinal, variance)
+	return nil
+}
@@ -0,0 +1,878 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"sync/atomic"
+
+	"grant-db/executor/aggfuncs"
+	"grant-db/expression"
+	"grant-db/sessionctx"
+	"grant-db/types"
+	"grant-db/util/chunk"
+	"grant-db/util/codec"
+	"grant-db/util/memory"
+)
+
+// groupKeyEvaluator evaluates the group by items on the chunks and encodes the group keys of the rows, the
+// key of every row is empty if there are no items.
+type groupKeyEvaluator struct {
+	items  []expression.Expression
+	tps    []*types.FieldType
+	colIdx []int
+	keyChk *chunk.Chunk
+	keyBuf []byte
+}
+
+func newGroupKeyEvaluator(items []expression.Expression, maxChunkSize int) *groupKeyEvaluator {
+	g := &groupKeyEvaluator{items: items}
+	for i, item := range items {
+		g.tps = append(g.tps, item.GetType())
+		g.colIdx = append(g.colIdx, i)
+	}
+	g.keyChk = chunk.New(g.tps, maxChunkSize, maxChunkSize)
+	return g
+}
+
+// evalChunk evaluates the group by items on the rows of the chunk.
+func (g *groupKeyEvaluator) evalChunk(sctx sessionctx.Context, chk *chunk.Chunk) error {
+	if len(g.items) == 0 {
+		return nil
+	}
+	return expression.VectorizedExecute(sctx, g.items, chk, g.keyChk)
+}
+
+// key returns the group key of the row of the last evaluated chunk, it's valid until the next call.
+func (g *groupKeyEvaluator) key(rowIdx int) []byte {
+	if len(g.items) == 0 {
+		return nil
+	}
+	g.keyBuf = codec.HashGroupKey(g.keyBuf[:0], g.keyChk.GetRow(rowIdx), g.tps, g.colIdx)
+	return g.keyBuf
+}
+
+func allocPartialResults(aggFuncs []aggfuncs.AggFunc) []aggfuncs.PartialResult {
+	prs := make([]aggfuncs.PartialResult, 0, len(aggFuncs))
+	for _, f := range aggFuncs {
+		prs = append(prs, f.AllocPartialResult())
+	}
+	return prs
+}
+
+func updatePartialResults(sctx sessionctx.Context, aggFuncs []aggfuncs.AggFunc, rows []chunk.Row, prs []aggfuncs.PartialResult) error {
+	for i, f := range aggFuncs {
+		if err := f.UpdatePartialResult(sctx, rows, prs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func appendFinalResults(sctx sessionctx.Context, aggFuncs []aggfuncs.AggFunc, prs []aggfuncs.PartialResult, chk *chunk.Chunk) error {
+	for i, f := range aggFuncs {
+		if err := f.AppendFinalResult2Chunk(sctx, prs[i], chk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// The approximate sizes of a group besides its key, and the partial result of a function.
+const (
+	groupEntrySize    = hashEntrySize + 16
+	partialResultSize = 32
+)
+
+func groupMemUsage(key string, numFuncs int) int64 {
+	return int64(len(key)) + groupEntrySize + int64(numFuncs)*partialResultSize
+}
+
+// StreamAggExec aggregates the rows of the child which are ordered by the group by items, the results of
+// a group are returned when the next group begins.
+type StreamAggExec struct {
+	baseExecutor
+
+	aggFuncs     []aggfuncs.AggFunc
+	groupByItems []expression.Expression
+
+	keyEval        *groupKeyEvaluator
+	partialResults []aggfuncs.PartialResult
+	childResult    *chunk.Chunk
+	// rowIdx is the next row of childResult to be aggregated.
+	rowIdx int
+	// curGroupKey is the key of the current group, hasGroup is false if there is no row yet.
+	curGroupKey []byte
+	hasGroup    bool
+	executed    bool
+	rowBuf      []chunk.Row
+}
+
+// Open implements the Executor Open interface.
+func (e *StreamAggExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.keyEval = newGroupKeyEvaluator(e.groupByItems, e.maxChunkSize)
+	e.partialResults = allocPartialResults(e.aggFuncs)
+	e.childResult = newFirstChunk(e.children[0])
+	e.rowIdx = 0
+	e.curGroupKey = e.curGroupKey[:0]
+	e.hasGroup, e.executed = false, false
+	return nil
+}
+
+// Next implements the Executor Next interface.
+func (e *StreamAggExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	for !e.executed && !req.IsFull() {
+		if e.rowIdx < e.childResult.NumRows() {
+			if err := e.consumeGroupRows(req); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := e.fetchChildChunk(ctx); err != nil {
+			return err
+		}
+		if e.childResult.NumRows() == 0 {
+			e.executed = true
+			// The scalar aggregation returns a row even if there are no rows.
+			if e.hasGroup || len(e.groupByItems) == 0 {
+				return appendFinalResults(e.ctx, e.aggFuncs, e.partialResults, req)
+			}
+		}
+	}
+	return nil
+}
+
+func (e *StreamAggExec) fetchChildChunk(ctx context.Context) error {
+	e.rowIdx = 0
+	if err := Next(ctx, e.children[0], e.childResult); err != nil {
+		return err
+	}
+	return e.keyEval.evalChunk(e.ctx, e.childResult)
+}
+
+// consumeGroupRows aggregates the rows of the current group from rowIdx, the results of the group are
+// appended to req if the next group begins in the chunk.
+func (e *StreamAggExec) consumeGroupRows(req *chunk.Chunk) error {
+	begin, numRows := e.rowIdx, e.childResult.NumRows()
+	newGroup := false
+	for ; e.rowIdx < numRows; e.rowIdx++ {
+		key := e.keyEval.key(e.rowIdx)
+		if !e.hasGroup {
+			e.curGroupKey = append(e.curGroupKey[:0], key...)
+			e.hasGroup = true
+		} else if !bytes.Equal(key, e.curGroupKey) {
+			newGroup = true
+			break
+		}
+	}
+	e.rowBuf = e.rowBuf[:0]
+	for i := begin; i < e.rowIdx; i++ {
+		e.rowBuf = append(e.rowBuf, e.childResult.GetRow(i))
+	}
+	if err := updatePartialResults(e.ctx, e.aggFuncs, e.rowBuf, e.partialResults); err != nil {
+		return err
+	}
+	if !newGroup {
+		return nil
+	}
+	if err := appendFinalResults(e.ctx, e.aggFuncs, e.partialResults, req); err != nil {
+		return err
+	}
+	for i, f := range e.aggFuncs {
+		f.ResetPartialResult(e.partialResults[i])
+	}
+	e.curGroupKey = append(e.curGroupKey[:0], e.keyEval.key(e.rowIdx)...)
+	return nil
+}
+
+// Close implements the Executor Close interface.
+func (e *StreamAggExec) Close() error {
+	e.childResult = nil
+	e.partialResults = nil
+	return e.baseExecutor.Close()
+}
+
+// aggPartialResultMapper maps the group keys to the partial results of the groups.
+type aggPartialResultMapper = map[string][]aggfuncs.PartialResult
+
+// HashAggExec groups the rows by a hash table.
+//
+// The rows are aggregated in parallel unless any function has DISTINCT, whose partial results can't be
+// merged, or both the concurrencies are 1. The partial workers aggregate the chunks of the child, and then
+// send the groups to the final workers by the hashes of the keys, which merge the partial results of the
+// same groups and return the final results.
+//
+// Otherwise the rows are aggregated by the main goroutine. When the memory quota is exceeded, the rows of
+// the new groups are spilled to the disk, they're aggregated in the next round after the groups in the
+// memory are returned.
+//
+// The parallel execution falls back to the unparallel one if the memory quota is exceeded before all the
+// rows of the child are read. The partial workers stop at the first rows of their new groups, and hand over
+// their groups and the rows which aren't aggregated to the main goroutine, which merges the groups and
+// aggregates the rest of the rows by spilling.
+type HashAggExec struct {
+	baseExecutor
+
+	groupByItems       []expression.Expression
+	isUnparallel       bool
+	partialConcurrency int
+	finalConcurrency   int
+	// aggFuncs are the functions of the unparallel execution, partialAggFuncs and finalAggFuncs are the
+	// ones of the workers, the arguments of the partial ones are cloned for every worker.
+	aggFuncs        []aggfuncs.AggFunc
+	partialAggFuncs [][]aggfuncs.AggFunc
+	finalAggFuncs   [][]aggfuncs.AggFunc
+
+	prepared    bool
+	memTracker  *memory.Tracker
+	diskTracker *memory.Tracker
+
+	// The fields of the unparallel execution.
+	keyEval     *groupKeyEvaluator
+	childResult *chunk.Chunk
+	groupSet    aggPartialResultMapper
+	groupKeys   []string
+	// cursor is the next group of groupKeys to be returned.
+	cursor   int
+	executed bool
+	rowBuf   []chunk.Row
+	// spillState is set by the spill action, the rows of the new groups are written to listInDisk in the
+	// spilling state. spilledRows are the rows spilled in the last round, spilledChkIdx is the next chunk
+	// of them to be aggregated.
+	spillState    uint32
+	spillAction   *aggSpillDiskAction
+	spillChk      *chunk.Chunk
+	listInDisk    *chunk.ListInDisk
+	spilledRows   *chunk.ListInDisk
+	spilledChkIdx int
+
+	// The fields of the parallel execution.
+	closeCh chan struct{}
+	// parallelState is whether the parallel execution is completed or handed over, handOverCh is closed when
+	// it's handed over. partialWorking is the number of the running partial workers.
+	parallelState  uint32
+	handOverCh     chan struct{}
+	partialWorking int32
+	// handOverMu protects handOverGroups and pendingInputs, which are the groups of the partial workers and
+	// the rows they haven't aggregated. childDrained is true if all the chunks of the child are read.
+	handOverMu     sync.Mutex
+	handOverGroups []aggPartialResultMapper
+	pendingInputs  []pendingInput
+	childDrained   bool
+	fellBack       bool
+	// wg waits for all the goroutines and partialWg waits for the partial workers.
+	wg        sync.WaitGroup
+	partialWg sync.WaitGroup
+	// inputCh sends the chunks of the child to the partial workers, and inputResourceCh recycles them.
+	inputCh         chan *chunk.Chunk
+	inputResourceCh chan *chunk.Chunk
+	// partialOutputChs send the groups of the partial workers to the final workers.
+	partialOutputChs []chan aggPartialResultMapper
+	finalResultCh    chan *aggWorkerResult
+}
+
+// aggWorkerResult is a chunk of the final results or an error, the chunk is sent back to src after it's used.
+type aggWorkerResult struct {
+	chk *chunk.Chunk
+	err error
+	src chan<- *chunk.Chunk
+}
+
+// pendingInput is a chunk of the child whose rows from begin aren't aggregated.
+type pendingInput struct {
+	chk   *chunk.Chunk
+	begin int
+}
+
+// The states of the parallel execution of a HashAggExec.
+const (
+	aggRunning uint32 = iota
+	aggCompleted
+	aggHandedOver
+)
+
+// The states of the spilling of an unparallel HashAggExec.
+const (
+	aggNotSpilled uint32 = iota
+	aggSpilling
+	aggClosed
+)
+
+// aggSpillDiskAction requests the spilling of the rows of the new groups of a HashAggExec, the fallback action
+// is taken if they're being spilled already since the groups in the memory can't be spilled. The partial
+// workers of the parallel execution stop creating groups soon after the spilling is requested, so the
+// action isn't taken until they exit.
+type aggSpillDiskAction struct {
+	e        *HashAggExec
+	fallback memory.ActionOnExceed
+}
+
+// Action implements memory.ActionOnExceed Action interface.
+func (a *aggSpillDiskAction) Action(t *memory.Tracker) {
+	if atomic.CompareAndSwapUint32(&a.e.spillState, aggNotSpilled, aggSpilling) {
+		return
+	}
+	if atomic.LoadInt32(&a.e.partialWorking) > 0 {
+		return
+	}
+	if a.fallback != nil {
+		a.fallback.Action(t)
+	}
+}
+
+// SetFallback implements memory.ActionOnExceed SetFallback interface.
+func (a *aggSpillDiskAction) SetFallback(fallback memory.ActionOnExceed) {
+	a.fallback = fallback
+}
+
+// Open implements the Executor Open interface.
+func (e *HashAggExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.prepared, e.fellBack = false, false
+	sc := e.ctx.GetSessionVars().StmtCtx
+	e.memTracker = memory.NewTracker(e.id)
+	e.memTracker.AttachTo(sc.MemTracker)
+	e.diskTracker = memory.NewTracker(e.id)
+	e.diskTracker.AttachTo(sc.DiskTracker)
+	return nil
+}
+
+// Next implements the Executor Next interface.
+func (e *HashAggExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.isUnparallel {
+		if !e.prepared {
+			e.initUnparallel()
+			e.prepared = true
+		}
+		return e.unparallelNext(ctx, req)
+	}
+	if e.fellBack {
+		return e.unparallelNext(ctx, req)
+	}
+	if !e.prepared {
+		e.startWorkers(ctx)
+		e.prepared = true
+	}
+	result, ok := <-e.finalResultCh
+	if !ok {
+		if atomic.LoadUint32(&e.parallelState) != aggHandedOver {
+			return nil
+		}
+		if err := e.fallBackToUnparallel(); err != nil {
+			return err
+		}
+		return e.unparallelNext(ctx, req)
+	}
+	if result.err != nil {
+		return result.err
+	}
+	req.SwapColumns(result.chk)
+	result.src <- result.chk
+	return nil
+}
+
+func (e *HashAggExec) initUnparallel() {
+	e.keyEval = newGroupKeyEvaluator(e.groupByItems, e.maxChunkSize)
+	e.childResult = newFirstChunk(e.children[0])
+	e.rowBuf = make([]chunk.Row, 1)
+	e.resetGroups()
+	e.setSpillAction()
+}
+
+func (e *HashAggExec) setSpillAction() {
+	atomic.StoreUint32(&e.spillState, aggNotSpilled)
+	e.spillAction = &aggSpillDiskAction{e: e}
+	e.ctx.GetSessionVars().StmtCtx.MemTracker.FallbackOldAndSetNewAction(e.spillAction)
+}
+
+// fallBackToUnparallel merges the groups handed over by the partial workers by the functions of the first
+// final worker, which aggregate the rest of the rows in the unparallel execution. The goroutines have exited.
+func (e *HashAggExec) fallBackToUnparallel() error {
+	e.fellBack = true
+	e.aggFuncs = e.finalAggFuncs[0]
+	e.keyEval = newGroupKeyEvaluator(e.groupByItems, e.maxChunkSize)
+	e.childResult = newFirstChunk(e.children[0])
+	e.rowBuf = make([]chunk.Row, 1)
+	e.groupSet = make(aggPartialResultMapper)
+	e.groupKeys = e.groupKeys[:0]
+	e.cursor, e.executed = 0, false
+	for _, groups := range e.handOverGroups {
+		for key, src := range groups {
+			dst, ok := e.groupSet[key]
+			if !ok {
+				e.groupSet[key] = src
+				e.groupKeys = append(e.groupKeys, key)
+				continue
+			}
+			for i, f := range e.aggFuncs {
+				if err := f.MergePartialResult(e.ctx, src[i], dst[i]); err != nil {
+					return err
+				}
+			}
+			e.memTracker.Consume(-groupMemUsage(key, len(e.aggFuncs)))
+		}
+	}
+	e.handOverGroups = nil
+	return nil
+}
+
+// resetGroups releases the groups of the last round. The scalar aggregation has the group of the empty key
+// even if there are no rows.
+func (e *HashAggExec) resetGroups() {
+	e.groupSet = make(aggPartialResultMapper)
+	e.groupKeys = e.groupKeys[:0]
+	e.cursor, e.executed = 0, false
+	e.memTracker.Consume(-e.memTracker.BytesConsumed())
+	if len(e.groupByItems) == 0 {
+		e.groupSet[""] = allocPartialResults(e.aggFuncs)
+		e.groupKeys = append(e.groupKeys, "")
+	}
+}
+
+func (e *HashAggExec) unparallelNext(ctx context.Context, req *chunk.Chunk) error {
+	for {
+		if !e.executed {
+			if err := e.execute(ctx); err != nil {
+				return err
+			}
+			e.executed = true
+		}
+		for ; e.cursor < len(e.groupKeys) && !req.IsFull(); e.cursor++ {
+			if err := appendFinalResults(e.ctx, e.aggFuncs, e.groupSet[e.groupKeys[e.cursor]], req); err != nil {
+				return err
+			}
+		}
+		if req.IsFull() || e.listInDisk == nil {
+			return nil
+		}
+		// All the groups of this round are returned, the spilled rows are aggregated in the next round.
+		if err := e.closeSpilledRows(); err != nil {
+			return err
+		}
+		e.spilledRows, e.listInDisk, e.spilledChkIdx = e.listInDisk, nil, 0
+		e.resetGroups()
+		atomic.CompareAndSwapUint32(&e.spillState, aggSpilling, aggNotSpilled)
+	}
+}
+
+// execute aggregates all the rows of this round, the rows handed over by the partial workers are aggregated
+// first.
+func (e *HashAggExec) execute(ctx context.Context) error {
+	for _, input := range e.pendingInputs {
+		if err := e.aggregateChunk(input.chk, input.begin); err != nil {
+			return err
+		}
+	}
+	e.pendingInputs = nil
+	for {
+		if err := e.fetchChildChunk(ctx, e.childResult); err != nil {
+			return err
+		}
+		if e.childResult.NumRows() == 0 {
+			return e.flushSpillChk()
+		}
+		if err := e.aggregateChunk(e.childResult, 0); err != nil {
+			return err
+		}
+	}
+}
+
+// aggregateChunk aggregates the rows of the chunk from begin, the rows of the new groups are spilled in the
+// spilling state.
+func (e *HashAggExec) aggregateChunk(chk *chunk.Chunk, begin int) error {
+	if err := e.keyEval.evalChunk(e.ctx, chk); err != nil {
+		return err
+	}
+	for i := begin; i < chk.NumRows(); i++ {
+		row := chk.GetRow(i)
+		key := e.keyEval.key(i)
+		prs, ok := e.groupSet[string(key)]
+		if !ok {
+			if atomic.LoadUint32(&e.spillState) == aggSpilling {
+				if err := e.spillRow(row); err != nil {
+					return err
+				}
+				continue
+			}
+			groupKey := string(key)
+			prs = allocPartialResults(e.aggFuncs)
+			e.groupSet[groupKey] = prs
+			e.groupKeys = append(e.groupKeys, groupKey)
+			e.memTracker.Consume(groupMemUsage(groupKey, len(e.aggFuncs)))
+		}
+		e.rowBuf[0] = row
+		if err := updatePartialResults(e.ctx, e.aggFuncs, e.rowBuf, prs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchChildChunk reads the next chunk of the rows of this round, which are the rows of the child in the
+// first round, or the ones spilled in the last round.
+func (e *HashAggExec) fetchChildChunk(ctx context.Context, chk *chunk.Chunk) error {
+	if e.spilledRows == nil {
+		if e.childDrained {
+			chk.Reset()
+			return nil
+		}
+		return Next(ctx, e.children[0], chk)
+	}
+	chk.Reset()
+	if e.spilledChkIdx >= e.spilledRows.NumChunks() {
+		return nil
+	}
+	for i := 0; i < e.spilledRows.NumRowsOfChunk(e.spilledChkIdx); i++ {
+		row, err := e.spilledRows.GetRow(chunk.RowPtr{ChkIdx: uint32(e.spilledChkIdx), RowIdx: uint32(i)})
+		if err != nil {
+			return err
+		}
+		chk.AppendRow(row)
+	}
+	e.spilledChkIdx++
+	return nil
+}
+
+func (e *HashAggExec) spillRow(row chunk.Row) error {
+	if e.listInDisk == nil {
+		e.listInDisk = chunk.NewListInDisk(retTypes(e.children[0]))
+		e.listInDisk.GetDiskTracker().AttachTo(e.diskTracker)
+	}
+	if e.spillChk == nil {
+		e.spillChk = newFirstChunk(e.children[0])
+	}
+	e.spillChk.AppendRow(row)
+	if e.spillChk.NumRows() < e.maxChunkSize {
+		return nil
+	}
+	return e.flushSpillChk()
+}
+
+func (e *HashAggExec) flushSpillChk() error {
+	if e.spillChk == nil || e.spillChk.NumRows() == 0 {
+		return nil
+	}
+	err := e.listInDisk.Add(e.spillChk)
+	e.spillChk.Reset()
+	return err
+}
+
+func (e *HashAggExec) closeSpilledRows() error {
+	if e.spilledRows == nil {
+		return nil
+	}
+	err := e.spilledRows.Close()
+	e.spilledRows = nil
+	return err
+}
+
+// startWorkers starts the goroutine which reads the chunks of the child, the partial workers and the final
+// workers, finalResultCh is closed after they all exit.
+func (e *HashAggExec) startWorkers(ctx context.Context) {
+	e.closeCh = make(chan struct{})
+	e.parallelState = aggRunning
+	e.handOverCh = make(chan struct{})
+	e.handOverGroups = make([]aggPartialResultMapper, e.partialConcurrency)
+	e.pendingInputs, e.childDrained = nil, false
+	e.partialWorking = int32(e.partialConcurrency)
+	e.setSpillAction()
+	e.inputCh = make(chan *chunk.Chunk, e.partialConcurrency)
+	e.inputResourceCh = make(chan *chunk.Chunk, e.partialConcurrency)
+	for i := 0; i < e.partialConcurrency; i++ {
+		e.inputResourceCh <- newFirstChunk(e.children[0])
+	}
+	e.partialOutputChs = make([]chan aggPartialResultMapper, e.finalConcurrency)
+	for i := range e.partialOutputChs {
+		e.partialOutputChs[i] = make(chan aggPartialResultMapper, e.partialConcurrency)
+	}
+	e.finalResultCh = make(chan *aggWorkerResult, e.finalConcurrency+1)
+	e.wg.Add(1)
+	go e.fetchChildChunks(ctx)
+	for i := 0; i < e.partialConcurrency; i++ {
+		e.wg.Add(1)
+		e.partialWg.Add(1)
+		go e.runPartialWorker(i)
+	}
+	for i := 0; i < e.finalConcurrency; i++ {
+		e.wg.Add(1)
+		go e.runFinalWorker(i)
+	}
+	go func() {
+		e.partialWg.Wait()
+		for _, ch := range e.partialOutputChs {
+			close(ch)
+		}
+	}()
+	go func() {
+		e.wg.Wait()
+		close(e.finalResultCh)
+	}()
+}
+
+// sendResult sends the result to the main goroutine, it returns false if the executor is closed.
+func (e *HashAggExec) sendResult(result *aggWorkerResult) bool {
+	select {
+	case <-e.closeCh:
+		return false
+	case e.finalResultCh <- result:
+		return true
+	}
+}
+
+// recoverWorker sends the panic of a goroutine as an error, like the one of the exceeded memory quota.
+func (e *HashAggExec) recoverWorker() {
+	if r := recover(); r != nil {
+		err, ok := r.(error)
+		if !ok {
+			err = fmt.Errorf("%v", r)
+		}
+		e.sendResult(&aggWorkerResult{err: err})
+	}
+}
+
+func (e *HashAggExec) fetchChildChunks(ctx context.Context) {
+	defer func() {
+		close(e.inputCh)
+		e.wg.Done()
+	}()
+	defer e.recoverWorker()
+	for {
+		var chk *chunk.Chunk
+		select {
+		case <-e.closeCh:
+			return
+		case <-e.handOverCh:
+			return
+		case chk = <-e.inputResourceCh:
+		}
+		if atomic.LoadUint32(&e.parallelState) == aggHandedOver {
+			return
+		}
+		if err := Next(ctx, e.children[0], chk); err != nil {
+			e.sendResult(&aggWorkerResult{err: err})
+			return
+		}
+		if chk.NumRows() == 0 {
+			e.childDrained = true
+			atomic.CompareAndSwapUint32(&e.parallelState, aggRunning, aggCompleted)
+			return
+		}
+		e.inputCh <- chk
+	}
+}
+
+// runPartialWorker aggregates the chunks of the child, the groups are sent to the final workers after all
+// the chunks are read, or handed over to the main goroutine.
+func (e *HashAggExec) runPartialWorker(workerID int) {
+	defer func() {
+		atomic.AddInt32(&e.partialWorking, -1)
+		e.partialWg.Done()
+		e.wg.Done()
+	}()
+	defer e.recoverWorker()
+	aggFuncs := e.partialAggFuncs[workerID]
+	keyEval := newGroupKeyEvaluator(expression.CNFExprs(e.groupByItems).Clone(), e.maxChunkSize)
+	groups := make(aggPartialResultMapper)
+	if len(e.groupByItems) == 0 && workerID == 0 {
+		groups[""] = allocPartialResults(aggFuncs)
+	}
+	rowBuf := make([]chunk.Row, 1)
+	handedOver := false
+	for {
+		var chk *chunk.Chunk
+		select {
+		case <-e.closeCh:
+			return
+		case input, ok := <-e.inputCh:
+			if !ok {
+				if atomic.LoadUint32(&e.parallelState) == aggHandedOver {
+					e.handOverMu.Lock()
+					e.handOverGroups[workerID] = groups
+					e.handOverMu.Unlock()
+					return
+				}
+				e.shuffleGroups(groups)
+				return
+			}
+			chk = input
+		}
+		if handedOver {
+			e.stashInput(chk, 0)
+			continue
+		}
+		if err := keyEval.evalChunk(e.ctx, chk); err != nil {
+			e.sendResult(&aggWorkerResult{err: err})
+			return
+		}
+		for i := 0; i < chk.NumRows(); i++ {
+			key := keyEval.key(i)
+			prs, ok := groups[string(key)]
+			if !ok {
+				if handedOver = e.handOver(); handedOver {
+					e.stashInput(chk, i)
+					break
+				}
+				groupKey := string(key)
+				prs = allocPartialResults(aggFuncs)
+				groups[groupKey] = prs
+				e.memTracker.Consume(groupMemUsage(groupKey, len(aggFuncs)))
+			}
+			rowBuf[0] = chk.GetRow(i)
+			if err := updatePartialResults(e.ctx, aggFuncs, rowBuf, prs); err != nil {
+				e.sendResult(&aggWorkerResult{err: err})
+				return
+			}
+		}
+		if !handedOver {
+			e.inputResourceCh <- chk
+		}
+	}
+}
+
+// handOver reports whether the parallel execution is handed over to the main goroutine, it's handed over
+// when a partial worker meets a new group in the spilling state, unless all the chunks of the child are read.
+func (e *HashAggExec) handOver() bool {
+	if atomic.LoadUint32(&e.spillState) != aggSpilling {
+		return false
+	}
+	if atomic.CompareAndSwapUint32(&e.parallelState, aggRunning, aggHandedOver) {
+		close(e.handOverCh)
+		return true
+	}
+	return atomic.LoadUint32(&e.parallelState) == aggHandedOver
+}
+
+// stashInput keeps the rows of the chunk from begin for the main goroutine.
+func (e *HashAggExec) stashInput(chk *chunk.Chunk, begin int) {
+	e.handOverMu.Lock()
+	e.pendingInputs = append(e.pendingInputs, pendingInput{chk: chk, begin: begin})
+	e.handOverMu.Unlock()
+}
+
+// shuffleGroups sends the groups to the final workers by the hashes of the keys, so the partial results of
+// a group are merged by the same worker.
+func (e *HashAggExec) shuffleGroups(groups aggPartialResultMapper) {
+	outputs := make([]aggPartialResultMapper, e.finalConcurrency)
+	for i := range outputs {
+		outputs[i] = make(aggPartialResultMapper)
+	}
+	h := fnv.New64()
+	for key, prs := range groups {
+		h.Reset()
+		_, _ = h.Write([]byte(key))
+		outputs[h.Sum64()%uint64(e.finalConcurrency)][key] = prs
+	}
+	for i, output := range outputs {
+		if len(output) == 0 {
+			continue
+		}
+		select {
+		case <-e.closeCh:
+			return
+		case e.partialOutputChs[i] <- output:
+		}
+	}
+}
+
+// runFinalWorker merges the partial results of the groups, the final results are returned in the chunks of
+// its own resource channel after all the groups are merged.
+func (e *HashAggExec) runFinalWorker(workerID int) {
+	defer e.wg.Done()
+	defer e.recoverWorker()
+	aggFuncs := e.finalAggFuncs[workerID]
+	groups := make(aggPartialResultMapper)
+	var groupKeys []string
+	for {
+		var input aggPartialResultMapper
+		select {
+		case <-e.closeCh:
+			return
+		case output, ok := <-e.partialOutputChs[workerID]:
+			if !ok {
+				e.returnFinalResults(aggFuncs, groups, groupKeys)
+				return
+			}
+			input = output
+		}
+		for key, src := range input {
+			dst, ok := groups[key]
+			if !ok {
+				groups[key] = src
+				groupKeys = append(groupKeys, key)
+				continue
+			}
+			for i, f := range aggFuncs {
+				if err := f.MergePartialResult(e.ctx, src[i], dst[i]); err != nil {
+					e.sendResult(&aggWorkerResult{err: err})
+					return
+				}
+			}
+		}
+	}
+}
+
+func (e *HashAggExec) returnFinalResults(aggFuncs []aggfuncs.AggFunc, groups aggPartialResultMapper, groupKeys []string) {
+	resourceCh := make(chan *chunk.Chunk, 1)
+	result := &aggWorkerResult{chk: newFirstChunk(e), src: resourceCh}
+	for _, key := range groupKeys {
+		if err := appendFinalResults(e.ctx, aggFuncs, groups[key], result.chk); err != nil {
+			e.sendResult(&aggWorkerResult{err: err})
+			return
+		}
+		if !result.chk.IsFull() {
+			continue
+		}
+		if !e.sendResult(result) {
+			return
+		}
+		select {
+		case <-e.closeCh:
+			return
+		case chk := <-resourceCh:
+			chk.Reset()
+			result = &aggWorkerResult{chk: chk, src: resourceCh}
+		}
+	}
+	if result.chk.NumRows() > 0 {
+		e.sendResult(result)
+	}
+}
+
+// Close implements the Executor Close interface, it waits for the goroutines to exit before the child is
+// closed.
+func (e *HashAggExec) Close() error {
+	if e.prepared && !e.isUnparallel {
+		close(e.closeCh)
+		for range e.finalResultCh {
+		}
+	}
+	e.prepared = false
+	atomic.StoreUint32(&e.spillState, aggClosed)
+	e.handOverGroups, e.pendingInputs = nil, nil
+	if e.isUnparallel || e.fellBack {
+		e.groupSet, e.groupKeys = nil, nil
+		e.childResult, e.spillChk = nil, nil
+		if e.listInDisk != nil {
+			if err := e.listInDisk.Close(); err != nil {
+				return err
+			}
+			e.listInDisk = nil
+		}
+		if err := e.closeSpilledRows(); err != nil {
+			return err
+		}
+	}
+	if e.memTracker != nil {
+		e.memTracker.Consume(-e.memTracker.BytesConsumed())
+	}
+	return e.baseExecutor.Close()
+}
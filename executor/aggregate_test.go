@@ -0,0 +1,77 @@
+package executor_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestAggregation(t *testing.T) {
+	tk := newExecTestKit(t)
+	tk.MustExec("create table t (a int primary key, b int, c varchar(10), key idx_b (b))")
+	tk.MustExec("insert into t values (1, 1, 'x'), (2, 1, 'y'), (3, null, 'x'), (4, 2, null), (5, 2, 'z'), (6, 2, 'x')")
+	tests := []struct {
+		sql      string
+		expected []string
+	}{
+		{"select %s b, count(*), count(c), sum(a), avg(a), min(c), max(c) from t group by b",
+			[]string{"1 2 2 3 1.5000 x y", "2 3 2 15 5.0000 x z", "<nil> 1 1 3 3.0000 x x"}},
+		{"select %s c, count(*), sum(b) from t group by c", []string{"<nil> 1 2", "x 3 3", "y 1 1", "z 1 2"}},
+		{"select %s b from t group by b having count(*) > 1", []string{"1", "2"}},
+		{"select %s count(*), sum(a), min(a) from t where a > 100", []string{"0 <nil> <nil>"}},
+		{"select %s b, count(*) from t where a > 100 group by b", nil},
+	}
+	// The stream and the hash aggregations return the same results.
+	for _, hint := range []string{"STREAM_AGG", "HASH_AGG"} {
+		operator := map[string]string{"STREAM_AGG": "StreamAgg", "HASH_AGG": "HashAgg"}[hint]
+		for _, tt := range tests {
+			sql := fmt.Sprintf(tt.sql, "/*+ "+hint+"() */")
+			if plan := strings.Join(tk.MustQuery("explain "+sql).Rows(), "\n"); !strings.Contains(plan, operator) {
+				t.Fatalf("%s isn't executed by %s:\n%s", sql, operator, plan)
+			}
+			tk.MustQuery(sql).Sort().Check(tt.expected...)
+		}
+	}
+
+	// The NULLs are ignored by DISTINCT.
+	tk.MustQuery("select count(distinct b), count(distinct c), sum(distinct b), count(distinct b, c) from t").Check("2 3 3 4")
+	tk.MustQuery("select c, group_concat(a), group_concat(distinct b order by b desc separator ';') from t group by c").Sort().Check(
+		"<nil> 4 2", "x 1,3,6 2;1", "y 2 1", "z 5 2")
+	tk.MustQuery("select group_concat(c order by a desc) from t").Check("x,z,x,y,x")
+	tk.MustQuery("select group_concat(c) from t where a > 100").Check("<nil>")
+}
+
+func TestHashAggSpill(t *testing.T) {
+	tk := newExecTestKit(t)
+	tk.MustExec("create table t (a int primary key, b int)")
+	for i := 0; i < 10; i++ {
+		insertRows(tk, "t", i*2000, (i+1)*2000, func(i int) string { return fmt.Sprint(i % 5000) })
+	}
+	// The small chunks keep the child from being drained before the memory quota is exceeded.
+	tk.MustExec("set tidb_max_chunk_size = 32")
+	tests := []struct {
+		sql      string
+		expected string
+	}{
+		{"select /*+ HASH_AGG() */ count(*), sum(cnt), sum(a), max(cnt) from (select /*+ HASH_AGG() */ a, count(*) cnt from t group by a) x",
+			"20000 20000 199990000 1"},
+		{"select /*+ HASH_AGG() */ count(*), sum(cnt), sum(b), min(cnt), max(cnt) from (select /*+ HASH_AGG() */ b, count(*) cnt from t group by b) x",
+			"5000 20000 12497500 4 4"},
+	}
+	// The rows of the new groups are spilled to the disk when the memory quota is exceeded, by both the parallel
+	// and the unparallel executions, the results are the same.
+	for _, concurrency := range []int{4, 1} {
+		tk.MustExec(fmt.Sprintf("set tidb_hashagg_partial_concurrency = %d", concurrency))
+		tk.MustExec(fmt.Sprintf("set tidb_hashagg_final_concurrency = %d", concurrency))
+		for _, quota := range []int{100000, 1 << 30} {
+			tk.MustExec(fmt.Sprintf("set tidb_mem_quota_query = %d", quota))
+			for _, tt := range tests {
+				tk.MustQuery(tt.sql).Check(tt.expected)
+			}
+			rows := tk.MustQuery("explain analyze select /*+ HASH_AGG() */ b, count(*) from t group by b").Rows()
+			if spilled := !strings.HasSuffix(rows[1], " 0 Bytes"); spilled != (quota < 1<<30) {
+				t.Fatalf("the hash aggregation of concurrency %d under the memory quota %d: %s", concurrency, quota, rows[1])
+			}
+		}
+	}
+}
@@ -0,0 +1,623 @@
+package executor
+
+import (
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/executor/aggfuncs"
+	"grant-db/expression"
+	"grant-db/expression/aggregation"
+	"grant-db/planner"
+	"grant-db/sessionctx"
+	"grant-db/table"
+)
+
+// executorBuilder builds the executors of the physical plans, the first error is kept in err.
+type executorBuilder struct {
+	ctx sessionctx.Context
+	err error
+}
+
+func newExecutorBuilder(ctx sessionctx.Context) *executorBuilder {
+	return &executorBuilder{ctx: ctx}
+}
+
+func (b *executorBuilder) build(p planner.PhysicalPlan) Executor {
+	switch v := p.(type) {
+	case *planner.PhysicalTableScan:
+		return b.buildTableReader(v, false)
+	case *planner.PhysicalIndexScan:
+		return b.buildIndexReader(v, false)
+	case *planner.PhysicalIndexLookUp:
+		return b.buildIndexLookUp(v)
+	case *planner.PointGetPlan:
+		return b.buildPointGet(v)
+	case *planner.BatchPointGetPlan:
+		return b.buildBatchPointGet(v)
+	case *planner.PhysicalTableDual:
+		return b.buildTableDual(v)
+	case *planner.PhysicalSelection:
+		return b.buildSelection(v, b.build(v.Children()[0]))
+	case *planner.PhysicalProjection:
+		return b.buildProjection(v)
+	case *planner.PhysicalLimit:
+		return b.buildLimit(v)
+	case *planner.PhysicalSort:
+		return b.buildSort(v)
+	case *planner.PhysicalTopN:
+		return b.buildTopN(v)
+	case *planner.PhysicalUnionAll:
+		return b.buildUnionAll(v)
+	case *planner.PhysicalMaxOneRow:
+		return b.buildMaxOneRow(v)
+	case *planner.PhysicalHashJoin:
+		return b.buildHashJoin(v)
+	case *planner.PhysicalMergeJoin:
+		return b.buildMergeJoin(v)
+	case *planner.PhysicalIndexJoin:
+		return b.buildIndexJoin(v)
+	case *planner.PhysicalApply:
+		return b.buildApply(v)
+	case *planner.PhysicalHashAgg:
+		return b.buildHashAgg(v)
+	case *planner.PhysicalStreamAgg:
+		return b.buildStreamAgg(v)
+	case *planner.PhysicalWindow:
+		return b.buildWindow(v)
+	case *planner.Insert:
+		return b.buildInsert(v)
+	case *planner.Update:
+		return b.buildUpdate(v)
+	case *planner.Delete:
+		return b.buildDelete(v)
+	}
+	b.err = ErrNotSupportedYet.GenWithStackByArgs(p.TP())
+	return nil
+}
+
+// buildChildren builds the executors of the children of the plan, nil is returned if any of them fails.
+func (b *executorBuilder) buildChildren(p planner.PhysicalPlan) []Executor {
+	children := make([]Executor, 0, len(p.Children()))
+	for _, child := range p.Children() {
+		childExec := b.build(child)
+		if b.err != nil {
+			return nil
+		}
+		children = append(children, childExec)
+	}
+	return children
+}
+
+func (b *executorBuilder) buildTableReader(v *planner.PhysicalTableScan, byHandles bool) *TableReaderExecutor {
+	decoder, err := newRowDecoder(v.TableInfo, v.Columns)
+	if err != nil {
+		b.err = err
+		return nil
+	}
+	e := &TableReaderExecutor{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
+		table:        v.Table,
+		desc:         v.Desc,
+		decoder:      decoder,
+		byHandles:    byHandles,
+	}
+	if !byHandles {
+		unsigned := v.HandleCol != nil && mysql.HasUnsignedFlag(v.HandleCol.RetType.Flag)
+		e.ranges = tableRangesToKVRanges(v.Table, v.Ranges, unsigned)
+	}
+	return e
+}
+
+// findIndex returns the index of the table by the ID.
+func findIndex(tbl table.Table, id int64) table.Index {
+	for _, idx := range tbl.Indices() {
+		if idx.Meta().ID == id {
+			return idx
+		}
+	}
+	return nil
+}
+
+func (b *executorBuilder) buildIndexReader(v *planner.PhysicalIndexScan, outputHandle bool) *IndexReaderExecutor {
+	idx := findIndex(v.Table, v.Index.ID)
+	ranges, err := indexRangesToKVRanges(v.TableInfo, idx, v.Ranges)
+	if err != nil {
+		b.err = err
+		return nil
+	}
+	e := &IndexReaderExecutor{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
+		index:        idx,
+		tblInfo:      v.TableInfo,
+		ranges:       ranges,
+		desc:         v.Desc,
+		cols:         v.Columns,
+		outputHandle: outputHandle,
+	}
+	e.initColOffsets()
+	return e
+}
+
+func (b *executorBuilder) buildIndexLookUp(v *planner.PhysicalIndexLookUp) Executor {
+	var indexExec Executor
+	var is *planner.PhysicalIndexScan
+	switch x := v.IndexPlan.(type) {
+	case *planner.PhysicalIndexScan:
+		is = x
+		indexExec = b.buildIndexReader(x, true)
+	case *planner.PhysicalSelection:
+		is = x.Children()[0].(*planner.PhysicalIndexScan)
+		indexExec = b.buildSelection(x, b.buildIndexReader(is, true))
+	}
+	if b.err != nil {
+		return nil
+	}
+	var tableExec Executor
+	var tableReader *TableReaderExecutor
+	switch x := v.TablePlan.(type) {
+	case *planner.PhysicalTableScan:
+		tableReader = b.buildTableReader(x, true)
+		tableExec = tableReader
+	case *planner.PhysicalSelection:
+		tableReader = b.buildTableReader(x.Children()[0].(*planner.PhysicalTableScan), true)
+		tableExec = b.buildSelection(x, tableReader)
+	}
+	if b.err != nil {
+		return nil
+	}
+	return &IndexLookUpExecutor{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
+		indexExec:    indexExec,
+		handleIdx:    is.Schema().Len(),
+		tableReader:  tableReader,
+		tableExec:    tableExec,
+	}
+}
+
+// newPointGetter creates the point getter of the table, the index is nil if the rows are read by the handles.
+func (b *executorBuilder) newPointGetter(tbl table.Table, tblInfo *model.TableInfo, cols []*model.ColumnInfo, idxInfo *model.IndexInfo) pointGetter {
+	decoder, err := newRowDecoder(tblInfo, cols)
+	if err != nil {
+		b.err = err
+	}
+	g := pointGetter{table: tbl, decoder: decoder}
+	if idxInfo != nil {
+		g.index = findIndex(tbl, idxInfo.ID)
+	}
+	return g
+}
+
+func (b *executorBuilder) buildPointGet(v *planner.PointGetPlan) Executor {
+	e := &PointGetExecutor{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
+		pointGetter:  b.newPointGetter(v.Table, v.TableInfo, v.Columns, v.IndexInfo),
+		handle:       v.Handle,
+		idxVals:      v.IndexValues,
+	}
+	if b.err != nil {
+		return nil
+	}
+	return e
+}
+
+func (b *executorBuilder) buildBatchPointGet(v *planner.BatchPointGetPlan) Executor {
+	e := &BatchPointGetExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
+		pointGetter:  b.newPointGetter(v.Table, v.TableInfo, v.Columns, v.IndexInfo),
+		handles:      v.Handles,
+		idxVals:      v.IndexValues,
+	}
+	if b.err != nil {
+		return nil
+	}
+	return e
+}
+
+func (b *executorBuilder) buildTableDual(v *planner.PhysicalTableDual) Executor {
+	return &TableDualExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID()),
+		numDualRows:  v.RowCount,
+	}
+}
+
+// buildSelection builds the selection on the child executor, the rows are in the types of the child, which
+// may have the extra handle column of an index lookup.
+func (b *executorBuilder) buildSelection(v *planner.PhysicalSelection, childExec Executor) Executor {
+	if b.err != nil {
+		return nil
+	}
+	e := &SelectionExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID(), childExec),
+		filters:      v.Conditions,
+	}
+	e.retFieldTypes = retTypes(childExec)
+	return e
+}
+
+func (b *executorBuilder) buildProjection(v *planner.PhysicalProjection) Executor {
+	children := b.buildChildren(v)
+	if b.err != nil {
+		return nil
+	}
+	return &ProjectionExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID(), children...),
+		exprs:        v.Exprs,
+	}
+}
+
+func (b *executorBuilder) buildLimit(v *planner.PhysicalLimit) Executor {
+	children := b.buildChildren(v)
+	if b.err != nil {
+		return nil
+	}
+	return &LimitExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID(), children...),
+		begin:        v.Offset,
+		end:          v.Offset + v.Count,
+	}
+}
+
+func (b *executorBuilder) buildSort(v *planner.PhysicalSort) Executor {
+	children := b.buildChildren(v)
+	if b.err != nil {
+		return nil
+	}
+	return &SortExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID(), children...),
+		ByItems:      v.ByItems,
+	}
+}
+
+func (b *executorBuilder) buildTopN(v *planner.PhysicalTopN) Executor {
+	children := b.buildChildren(v)
+	if b.err != nil {
+		return nil
+	}
+	return &TopNExec{
+		SortExec: SortExec{
+			baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID(), children...),
+			ByItems:      v.ByItems,
+		},
+		limitBegin: v.Offset,
+		limitEnd:   v.Offset + v.Count,
+	}
+}
+
+func (b *executorBuilder) buildUnionAll(v *planner.PhysicalUnionAll) Executor {
+	children := b.buildChildren(v)
+	if b.err != nil {
+		return nil
+	}
+	return &UnionExec{baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID(), children...)}
+}
+
+func (b *executorBuilder) buildMaxOneRow(v *planner.PhysicalMaxOneRow) Executor {
+	children := b.buildChildren(v)
+	if b.err != nil {
+		return nil
+	}
+	return &MaxOneRowExec{baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID(), children...)}
+}
+
+// joinConditions are the conditions of a join which are split by the rows they're evaluated on.
+type joinConditions struct {
+	// outerFilter is evaluated on the outer rows, the ones which don't satisfy it match no inner rows.
+	outerFilter expression.CNFExprs
+	// innerFilter is evaluated on the inner rows, the ones which don't satisfy it are filtered out.
+	innerFilter expression.CNFExprs
+	// conditions are evaluated on the joined rows.
+	conditions expression.CNFExprs
+}
+
+// splitJoinConditions splits the conditions of the join, the equal conditions are evaluated on the joined rows
+// if they're given, or else the keys are matched by the executor. All the conditions of a NULL aware join are
+// evaluated on the joined rows since their NULL results are different from the mismatches.
+func splitJoinConditions(innerIdx int, nullAware bool, left, right, other expression.CNFExprs, eq []*expression.ScalarFunction) joinConditions {
+	var conds joinConditions
+	switch {
+	case nullAware:
+		conds.conditions = append(append(conds.conditions, left...), right...)
+	case innerIdx == 0:
+		conds.outerFilter, conds.innerFilter = right, left
+	default:
+		conds.outerFilter, conds.innerFilter = left, right
+	}
+	conds.conditions = append(conds.conditions, expression.ScalarFuncs2Exprs(eq)...)
+	conds.conditions = append(conds.conditions, other...)
+	return conds
+}
+
+// buildInnerFilter builds the selection of the inner filter on the inner executor.
+func (b *executorBuilder) buildInnerFilter(innerExec Executor, filter expression.CNFExprs) Executor {
+	if len(filter) == 0 {
+		return innerExec
+	}
+	e := &SelectionExec{
+		baseExecutor: newBaseExecutor(b.ctx, innerExec.Schema(), innerExec.base().id, innerExec),
+		filters:      filter,
+	}
+	// The rows are recorded by the runtime statistics of the inner executor, whose ID is shared.
+	e.runtimeStats = nil
+	e.retFieldTypes = retTypes(innerExec)
+	return e
+}
+
+// keyColIdx returns the offsets of the key columns, which are resolved by the schema of the child.
+func keyColIdx(keys []*expression.Column) []int {
+	idx := make([]int, 0, len(keys))
+	for _, key := range keys {
+		idx = append(idx, key.Index)
+	}
+	return idx
+}
+
+func (b *executorBuilder) buildHashJoin(v *planner.PhysicalHashJoin) Executor {
+	children := b.buildChildren(v)
+	if b.err != nil {
+		return nil
+	}
+	var eq []*expression.ScalarFunction
+	if v.NullAware {
+		eq = v.EqualConditions
+	}
+	conds := splitJoinConditions(v.InnerChildIdx, v.NullAware, v.LeftConditions, v.RightConditions, v.OtherConditions, eq)
+	children[v.InnerChildIdx] = b.buildInnerFilter(children[v.InnerChildIdx], conds.innerFilter)
+	e := &HashJoinExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID(), children...),
+		outerExec:    children[1-v.InnerChildIdx],
+		innerExec:    children[v.InnerChildIdx],
+		outerFilter:  conds.outerFilter,
+		joinType:     v.JoinType,
+		nullAware:    v.NullAware,
+		concurrency:  b.ctx.GetSessionVars().HashJoinConcurrency,
+	}
+	if v.InnerChildIdx == 0 {
+		e.outerKeyColIdx, e.innerKeyColIdx = keyColIdx(v.RightJoinKeys), keyColIdx(v.LeftJoinKeys)
+	} else {
+		e.outerKeyColIdx, e.innerKeyColIdx = keyColIdx(v.LeftJoinKeys), keyColIdx(v.RightJoinKeys)
+	}
+	j := newJoiner(b.ctx, v.JoinType, v.InnerChildIdx == 0, v.DefaultValues, conds.conditions, v.NullAware,
+		retTypes(children[0]), retTypes(children[1]))
+	e.joiners = make([]joiner, 0, e.concurrency)
+	for i := 0; i < e.concurrency; i++ {
+		e.joiners = append(e.joiners, j.clone())
+	}
+	return e
+}
+
+func (b *executorBuilder) buildMergeJoin(v *planner.PhysicalMergeJoin) Executor {
+	children := b.buildChildren(v)
+	if b.err != nil {
+		return nil
+	}
+	conds := splitJoinConditions(v.InnerChildIdx, false, v.LeftConditions, v.RightConditions, v.OtherConditions, nil)
+	children[v.InnerChildIdx] = b.buildInnerFilter(children[v.InnerChildIdx], conds.innerFilter)
+	e := &MergeJoinExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID(), children...),
+		outerExec:    children[1-v.InnerChildIdx],
+		innerExec:    children[v.InnerChildIdx],
+		outerFilter:  conds.outerFilter,
+		joiner: newJoiner(b.ctx, v.JoinType, v.InnerChildIdx == 0, v.DefaultValues, conds.conditions, false,
+			retTypes(children[0]), retTypes(children[1])),
+		desc: v.Desc,
+	}
+	if v.InnerChildIdx == 0 {
+		e.outerKeyColIdx, e.innerKeyColIdx = keyColIdx(v.RightJoinKeys), keyColIdx(v.LeftJoinKeys)
+	} else {
+		e.outerKeyColIdx, e.innerKeyColIdx = keyColIdx(v.LeftJoinKeys), keyColIdx(v.RightJoinKeys)
+	}
+	return e
+}
+
+func (b *executorBuilder) buildIndexJoin(v *planner.PhysicalIndexJoin) Executor {
+	outerExec := b.build(v.Children()[1-v.InnerChildIdx])
+	if b.err != nil {
+		return nil
+	}
+	innerExec, tableReader, indexReader := b.buildIndexJoinInner(v.Children()[v.InnerChildIdx])
+	if b.err != nil {
+		return nil
+	}
+	conds := splitJoinConditions(v.InnerChildIdx, false, v.LeftConditions, v.RightConditions, v.OtherConditions, nil)
+	innerExec = b.buildInnerFilter(innerExec, conds.innerFilter)
+	children := []Executor{outerExec, innerExec}
+	if v.InnerChildIdx == 0 {
+		children[0], children[1] = innerExec, outerExec
+	}
+	return &IndexLookUpJoin{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID(), children...),
+		outerExec:    outerExec,
+		innerExec:    innerExec,
+		outerFilter:  conds.outerFilter,
+		joiner: newJoiner(b.ctx, v.JoinType, v.InnerChildIdx == 0, v.DefaultValues, conds.conditions, false,
+			retTypes(children[0]), retTypes(children[1])),
+		batchSize:      b.ctx.GetSessionVars().IndexJoinBatchSize,
+		outerKeyColIdx: keyColIdx(v.OuterJoinKeys),
+		innerKeyColIdx: keyColIdx(v.InnerJoinKeys),
+		keyOff2IdxOff:  v.KeyOff2IdxOff,
+		idxColLens:     v.IdxColLens,
+		tableReader:    tableReader,
+		indexReader:    indexReader,
+	}
+}
+
+// buildIndexJoinInner builds the inner executor of an index join, whose rows are read by the handles of the
+// table reader or the ranges of the index reader.
+func (b *executorBuilder) buildIndexJoinInner(p planner.PhysicalPlan) (Executor, *TableReaderExecutor, *IndexReaderExecutor) {
+	switch v := p.(type) {
+	case *planner.PhysicalTableScan:
+		e := b.buildTableReader(v, true)
+		return e, e, nil
+	case *planner.PhysicalIndexScan:
+		e := b.buildIndexReader(v, false)
+		return e, nil, e
+	case *planner.PhysicalIndexLookUp:
+		e := b.buildIndexLookUp(v)
+		if b.err != nil {
+			return nil, nil, nil
+		}
+		indexExec := e.(*IndexLookUpExecutor).indexExec
+		if sel, ok := indexExec.(*SelectionExec); ok {
+			indexExec = sel.children[0]
+		}
+		return e, nil, indexExec.(*IndexReaderExecutor)
+	case *planner.PhysicalSelection:
+		childExec, tableReader, indexReader := b.buildIndexJoinInner(v.Children()[0])
+		return b.buildSelection(v, childExec), tableReader, indexReader
+	}
+	b.err = ErrNotSupportedYet.GenWithStackByArgs(p.TP())
+	return nil, nil, nil
+}
+
+func (b *executorBuilder) buildApply(v *planner.PhysicalApply) Executor {
+	children := b.buildChildren(v)
+	if b.err != nil {
+		return nil
+	}
+	conds := splitJoinConditions(1, v.NullAware, v.LeftConditions, v.RightConditions, v.OtherConditions, v.EqualConditions)
+	children[1] = b.buildInnerFilter(children[1], conds.innerFilter)
+	return &NestedLoopApplyExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID(), children...),
+		outerExec:    children[0],
+		innerExec:    children[1],
+		outerFilter:  conds.outerFilter,
+		joiner: newJoiner(b.ctx, v.JoinType, false, v.DefaultValues, conds.conditions, v.NullAware,
+			retTypes(children[0]), retTypes(children[1])),
+		corCols: v.CorCols,
+	}
+}
+
+// buildAggFuncs builds the aggregate functions, whose arguments are cloned if clone is true so they can be
+// evaluated concurrently with the other ones.
+func (b *executorBuilder) buildAggFuncs(descs []*aggregation.AggFuncDesc, clone bool) []aggfuncs.AggFunc {
+	aggFuncs := make([]aggfuncs.AggFunc, 0, len(descs))
+	for i, desc := range descs {
+		if clone {
+			desc = desc.Clone()
+		}
+		f, err := aggfuncs.Build(b.ctx, desc, i)
+		if err != nil {
+			b.err = err
+			return nil
+		}
+		aggFuncs = append(aggFuncs, f)
+	}
+	return aggFuncs
+}
+
+func (b *executorBuilder) buildHashAgg(v *planner.PhysicalHashAgg) Executor {
+	children := b.buildChildren(v)
+	if b.err != nil {
+		return nil
+	}
+	sessionVars := b.ctx.GetSessionVars()
+	e := &HashAggExec{
+		baseExecutor:       newBaseExecutor(b.ctx, v.Schema(), v.ID(), children...),
+		groupByItems:       v.GroupByItems,
+		partialConcurrency: sessionVars.HashAggPartialConcurrency,
+		finalConcurrency:   sessionVars.HashAggFinalConcurrency,
+	}
+	e.isUnparallel = e.partialConcurrency == 1 && e.finalConcurrency == 1
+	for _, desc := range v.AggFuncs {
+		e.isUnparallel = e.isUnparallel || desc.HasDistinct
+	}
+	if e.isUnparallel {
+		e.aggFuncs = b.buildAggFuncs(v.AggFuncs, false)
+		return e
+	}
+	for i := 0; i < e.partialConcurrency && b.err == nil; i++ {
+		e.partialAggFuncs = append(e.partialAggFuncs, b.buildAggFuncs(v.AggFuncs, true))
+	}
+	for i := 0; i < e.finalConcurrency && b.err == nil; i++ {
+		e.finalAggFuncs = append(e.finalAggFuncs, b.buildAggFuncs(v.AggFuncs, false))
+	}
+	return e
+}
+
+func (b *executorBuilder) buildStreamAgg(v *planner.PhysicalStreamAgg) Executor {
+	children := b.buildChildren(v)
+	if b.err != nil {
+		return nil
+	}
+	return &StreamAggExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID(), children...),
+		aggFuncs:     b.buildAggFuncs(v.AggFuncs, false),
+		groupByItems: v.GroupByItems,
+	}
+}
+
+func (b *executorBuilder) buildWindow(v *planner.PhysicalWindow) Executor {
+	children := b.buildChildren(v)
+	if b.err != nil {
+		return nil
+	}
+	e := &WindowExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID(), children...),
+		orderBy:      v.OrderBy,
+		frame:        v.Frame,
+	}
+	for _, item := range v.PartitionBy {
+		e.partitionBy = append(e.partitionBy, item.Col)
+	}
+	orderByCols := make([]*expression.Column, 0, len(v.OrderBy))
+	for _, item := range v.OrderBy {
+		orderByCols = append(orderByCols, item.Col)
+	}
+	childLen := children[0].Schema().Len()
+	for i, desc := range v.WindowFuncDescs {
+		f, err := aggfuncs.BuildWindowFunctions(b.ctx, desc, childLen+i, orderByCols)
+		if err != nil {
+			b.err = err
+			return nil
+		}
+		e.windowFuncs = append(e.windowFuncs, f)
+		e.needFrame = append(e.needFrame, v.Frame != nil && aggregation.NeedFrame(desc.Name))
+	}
+	return e
+}
+
+func (b *executorBuilder) buildInsert(v *planner.Insert) Executor {
+	children := b.buildChildren(v)
+	if b.err != nil {
+		return nil
+	}
+	e := &InsertExec{
+		baseExecutor: newBaseExecutor(b.ctx, v.Schema(), v.ID(), children...),
+		table:        v.Table,
+		lists:        v.Lists,
+		onDuplicate:  v.OnDuplicate,
+		isReplace:    v.IsReplace,
+		ignoreErr:    v.IgnoreErr,
+	}
+	for _, col := range v.Columns {
+		for i, publicCol := range v.Table.Cols() {
+			if publicCol == col {
+				e.colOffsets = append(e.colOffsets, i)
+			}
+		}
+	}
+	return e
+}
+
+func (b *executorBuilder) buildUpdate(v *planner.Update) Executor {
+	children := b.buildChildren(v)
+	if b.err != nil {
+		return nil
+	}
+	return &UpdateExec{
+		baseExecutor:   newBaseExecutor(b.ctx, v.Schema(), v.ID(), children...),
+		orderedList:    v.OrderedList,
+		tblColPosInfos: v.TblColPosInfos,
+		ignoreErr:      v.IgnoreErr,
+	}
+}
+
+func (b *executorBuilder) buildDelete(v *planner.Delete) Executor {
+	children := b.buildChildren(v)
+	if b.err != nil {
+		return nil
+	}
+	return &DeleteExec{
+		baseExecutor:   newBaseExecutor(b.ctx, v.Schema(), v.ID(), children...),
+		tblColPosInfos: v.TblColPosInfos,
+	}
+}
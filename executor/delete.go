@@ -0,0 +1,65 @@
+package executor
+
+import (
+	"context"
+
+	"grant-db/planner"
+	"grant-db/types"
+	"grant-db/util/chunk"
+)
+
+// DeleteExec deletes the rows of the tables read by the child. All the rows are read before any of them is
+// deleted, and a row which is read more than once by a join is only deleted once.
+type DeleteExec struct {
+	baseExecutor
+
+	tblColPosInfos []planner.TblColPosInfo
+	done           bool
+}
+
+// Open implements the Executor Open interface.
+func (e *DeleteExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.done = false
+	return nil
+}
+
+// Next implements the Executor Next interface, all the rows are deleted by the first call.
+func (e *DeleteExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.done {
+		return nil
+	}
+	e.done = true
+	rows, err := readAllRows(ctx, e.children[0])
+	if err != nil {
+		return err
+	}
+	vars := e.ctx.GetSessionVars()
+	for _, info := range e.tblColPosInfos {
+		deleted := make(map[int64]struct{})
+		for _, row := range rows {
+			// The table isn't matched by the outer join.
+			if row[info.HandleOrdinal].IsNull() {
+				continue
+			}
+			h := row[info.HandleOrdinal].GetInt64()
+			if _, ok := deleted[h]; ok {
+				continue
+			}
+			deleted[h] = struct{}{}
+			data := make([]types.Datum, 0, len(info.ColOrdinals))
+			for _, ordinal := range info.ColOrdinals {
+				data = append(data, row[ordinal])
+			}
+			if err = info.Table.RemoveRecord(e.ctx, h, data); err != nil {
+				return err
+			}
+			vars.StmtCtx.AddAffectedRows(1)
+			vars.TxnCtx.UpdateDeltaForTable(info.Table.Meta().ID, -1, 1)
+		}
+	}
+	return nil
+}
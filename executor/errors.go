@@ -0,0 +1,13 @@
+package executor
+
+import (
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
+)
+
+var (
+	// ErrNotSupportedYet is returned when a plan can't be executed yet.
+	ErrNotSupportedYet = terror.ClassExecutor.New(mysql.ErrNotSupportedYet, mysql.MySQLErrName[mysql.ErrNotSupportedYet])
+	// ErrSubqueryMoreThan1Row is returned when a subquery which is used as a value returns more than one row.
+	ErrSubqueryMoreThan1Row = terror.ClassExecutor.New(mysql.ErrSubqueryNo1Row, mysql.MySQLErrName[mysql.ErrSubqueryNo1Row])
+)
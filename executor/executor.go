@@ -0,0 +1,379 @@
+package executor
+
+import (
+	"context"
+	"time"
+
+	"grant-db/expression"
+	"grant-db/sessionctx"
+	"grant-db/types"
+	"grant-db/util/chunk"
+	"grant-db/util/execdetails"
+)
+
+// Executor is the physical implementation of an operator of a plan, the rows are pulled from the root
+// executor by the chunks in the Volcano model.
+type Executor interface {
+	base() *baseExecutor
+	// Open initializes the executor and its children before the rows are read.
+	Open(context.Context) error
+	// Next fills the chunk with the next rows, there are no more rows if the chunk is empty.
+	Next(ctx context.Context, req *chunk.Chunk) error
+	// Close releases the resources of the executor and its children.
+	Close() error
+	// Schema returns the schema of the output rows.
+	Schema() *expression.Schema
+}
+
+// baseExecutor is the base of the executors, the executors of the plans are identified by the IDs of the plans.
+type baseExecutor struct {
+	ctx           sessionctx.Context
+	id            int
+	schema        *expression.Schema
+	initCap       int
+	maxChunkSize  int
+	children      []Executor
+	retFieldTypes []*types.FieldType
+	runtimeStats  *execdetails.RuntimeStats
+}
+
+func newBaseExecutor(ctx sessionctx.Context, schema *expression.Schema, id int, children ...Executor) baseExecutor {
+	vars := ctx.GetSessionVars()
+	e := baseExecutor{
+		ctx:          ctx,
+		id:           id,
+		schema:       schema,
+		initCap:      vars.InitChunkSize,
+		maxChunkSize: vars.MaxChunkSize,
+		children:     children,
+	}
+	if coll := vars.StmtCtx.RuntimeStatsColl; coll != nil {
+		e.runtimeStats = coll.GetRootStats(id)
+	}
+	if schema != nil {
+		e.retFieldTypes = make([]*types.FieldType, 0, schema.Len())
+		for _, col := range schema.Columns {
+			e.retFieldTypes = append(e.retFieldTypes, col.RetType)
+		}
+	}
+	return e
+}
+
+func (e *baseExecutor) base() *baseExecutor {
+	return e
+}
+
+// Open initializes the children.
+func (e *baseExecutor) Open(ctx context.Context) error {
+	for _, child := range e.children {
+		if err := child.Open(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes all the children, the first error is returned.
+func (e *baseExecutor) Close() error {
+	var firstErr error
+	for _, child := range e.children {
+		if err := child.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Schema returns the schema of the output rows.
+func (e *baseExecutor) Schema() *expression.Schema {
+	if e.schema == nil {
+		return expression.NewSchema()
+	}
+	return e.schema
+}
+
+// Next fills the chunk with the next rows, it's implemented by the executors.
+func (e *baseExecutor) Next(ctx context.Context, req *chunk.Chunk) error {
+	return nil
+}
+
+// Next calls the Next of the executor, the runtime statistics are recorded if they are collected.
+func Next(ctx context.Context, e Executor, req *chunk.Chunk) error {
+	stats := e.base().runtimeStats
+	if stats == nil {
+		return e.Next(ctx, req)
+	}
+	start := time.Now()
+	err := e.Next(ctx, req)
+	stats.Record(time.Since(start), req.NumRows())
+	return err
+}
+
+// retTypes returns the field types of the output rows of the executor.
+func retTypes(e Executor) []*types.FieldType {
+	return e.base().retFieldTypes
+}
+
+// newFirstChunk creates the chunk of the first call of Next of the executor, the capacity of the later
+// chunks grows up to the max chunk size.
+func newFirstChunk(e Executor) *chunk.Chunk {
+	base := e.base()
+	return chunk.New(base.retFieldTypes, base.initCap, base.maxChunkSize)
+}
+
+// SelectionExec filters the rows of the child by the conditions.
+type SelectionExec struct {
+	baseExecutor
+
+	filters     []expression.Expression
+	selected    []bool
+	childResult *chunk.Chunk
+	// inputRow is the next row of childResult to be filtered.
+	inputRow int
+}
+
+// Open implements the Executor Open interface.
+func (e *SelectionExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.childResult = newFirstChunk(e.children[0])
+	e.inputRow = 0
+	return nil
+}
+
+// Next implements the Executor Next interface.
+func (e *SelectionExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	for {
+		for ; e.inputRow < e.childResult.NumRows(); e.inputRow++ {
+			if req.IsFull() {
+				return nil
+			}
+			if e.selected[e.inputRow] {
+				req.AppendRow(e.childResult.GetRow(e.inputRow))
+			}
+		}
+		if err := Next(ctx, e.children[0], e.childResult); err != nil {
+			return err
+		}
+		if e.childResult.NumRows() == 0 {
+			return nil
+		}
+		var err error
+		e.selected, err = expression.VectorizedFilter(e.ctx, e.filters, e.childResult, e.selected)
+		if err != nil {
+			return err
+		}
+		e.inputRow = 0
+	}
+}
+
+// Close implements the Executor Close interface.
+func (e *SelectionExec) Close() error {
+	e.childResult = nil
+	e.selected = nil
+	return e.baseExecutor.Close()
+}
+
+// ProjectionExec evaluates the expressions on the rows of the child.
+type ProjectionExec struct {
+	baseExecutor
+
+	exprs       []expression.Expression
+	childResult *chunk.Chunk
+}
+
+// Open implements the Executor Open interface.
+func (e *ProjectionExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.childResult = newFirstChunk(e.children[0])
+	return nil
+}
+
+// Next implements the Executor Next interface.
+func (e *ProjectionExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if err := Next(ctx, e.children[0], e.childResult); err != nil {
+		return err
+	}
+	if len(e.exprs) == 0 {
+		// The rows of no columns are counted only.
+		req.SetNumVirtualRows(e.childResult.NumRows())
+		return nil
+	}
+	return expression.VectorizedExecute(e.ctx, e.exprs, e.childResult, req)
+}
+
+// Close implements the Executor Close interface.
+func (e *ProjectionExec) Close() error {
+	e.childResult = nil
+	return e.baseExecutor.Close()
+}
+
+// LimitExec skips the first offset rows of the child and returns the next count rows at most.
+type LimitExec struct {
+	baseExecutor
+
+	begin  uint64
+	end    uint64
+	cursor uint64
+
+	childResult *chunk.Chunk
+}
+
+// Open implements the Executor Open interface.
+func (e *LimitExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.childResult = newFirstChunk(e.children[0])
+	e.cursor = 0
+	return nil
+}
+
+// Next implements the Executor Next interface.
+func (e *LimitExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	for e.cursor < e.end {
+		if err := Next(ctx, e.children[0], e.childResult); err != nil {
+			return err
+		}
+		numRows := uint64(e.childResult.NumRows())
+		if numRows == 0 {
+			return nil
+		}
+		// The rows of the chunk are in [cursor, cursor+numRows), the ones in [begin, end) are returned.
+		begin, end := e.cursor, e.cursor+numRows
+		e.cursor = end
+		if end <= e.begin {
+			continue
+		}
+		if begin < e.begin {
+			begin = e.begin
+		}
+		if end > e.end {
+			end = e.end
+		}
+		for i := begin; i < end; i++ {
+			req.AppendRow(e.childResult.GetRow(int(i - (e.cursor - numRows))))
+		}
+		return nil
+	}
+	return nil
+}
+
+// Close implements the Executor Close interface.
+func (e *LimitExec) Close() error {
+	e.childResult = nil
+	return e.baseExecutor.Close()
+}
+
+// TableDualExec returns the rows of NULLs, which is a row of no columns for SELECT without FROM.
+type TableDualExec struct {
+	baseExecutor
+
+	numDualRows int
+	numReturned int
+}
+
+// Open implements the Executor Open interface.
+func (e *TableDualExec) Open(ctx context.Context) error {
+	e.numReturned = 0
+	return nil
+}
+
+// Next implements the Executor Next interface.
+func (e *TableDualExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.numReturned >= e.numDualRows {
+		return nil
+	}
+	if e.Schema().Len() == 0 {
+		req.SetNumVirtualRows(e.numDualRows)
+	} else {
+		for i := range e.Schema().Columns {
+			req.AppendNull(i)
+		}
+	}
+	e.numReturned = e.numDualRows
+	return nil
+}
+
+// MaxOneRowExec checks that the child returns one row at most, a row of NULLs is returned if the child
+// returns none.
+type MaxOneRowExec struct {
+	baseExecutor
+
+	evaluated bool
+}
+
+// Open implements the Executor Open interface.
+func (e *MaxOneRowExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.evaluated = false
+	return nil
+}
+
+// Next implements the Executor Next interface.
+func (e *MaxOneRowExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.evaluated {
+		return nil
+	}
+	e.evaluated = true
+	if err := Next(ctx, e.children[0], req); err != nil {
+		return err
+	}
+	if num := req.NumRows(); num == 0 {
+		for i := range e.Schema().Columns {
+			req.AppendNull(i)
+		}
+		return nil
+	} else if num != 1 {
+		return ErrSubqueryMoreThan1Row
+	}
+	childChunk := newFirstChunk(e.children[0])
+	if err := Next(ctx, e.children[0], childChunk); err != nil {
+		return err
+	}
+	if childChunk.NumRows() != 0 {
+		return ErrSubqueryMoreThan1Row
+	}
+	return nil
+}
+
+// UnionExec returns the rows of the children one by one, the rows of the children are in the types of the
+// union already.
+type UnionExec struct {
+	baseExecutor
+
+	childIdx int
+}
+
+// Open implements the Executor Open interface.
+func (e *UnionExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.childIdx = 0
+	return nil
+}
+
+// Next implements the Executor Next interface.
+func (e *UnionExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	for ; e.childIdx < len(e.children); e.childIdx++ {
+		if err := Next(ctx, e.children[e.childIdx], req); err != nil {
+			return err
+		}
+		if req.NumRows() > 0 {
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package executor_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"grant-db/util/testkit"
+)
+
+// newExecTestKit returns a test kit in the database test.
+func newExecTestKit(t *testing.T) *testkit.TestKit {
+	store, _ := testkit.NewMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create database test")
+	tk.MustExec("use test")
+	return tk
+}
+
+// insertRows inserts the rows (i, f(i)) for i in [from, to) into the table.
+func insertRows(tk *testkit.TestKit, table string, from, to int, f func(int) string) {
+	values := make([]string, 0, to-from)
+	for i := from; i < to; i++ {
+		values = append(values, fmt.Sprintf("(%d, %s)", i, f(i)))
+	}
+	tk.MustExec(fmt.Sprintf("insert into %s values %s", table, strings.Join(values, ", ")))
+}
+
+func TestScanSelectionProjection(t *testing.T) {
+	tk := newExecTestKit(t)
+	tk.MustExec("create table t (a int primary key, b int, c varchar(10), key idx_b (b))")
+	tk.MustExec("insert into t values (1, 10, 'x'), (2, null, 'y'), (3, 30, null), (4, 10, 'z')")
+	tk.MustQuery("select * from t").Check("1 10 x", "2 <nil> y", "3 30 <nil>", "4 10 z")
+	tk.MustQuery("select a, b * 2 + a, concat(c, '!') from t where b > 10 or c = 'y'").Check("2 <nil> y!", "3 63 <nil>")
+	tk.MustQuery("select a from t where b = 10").Check("1", "4")
+	tk.MustQuery("select c from t where b = 10 and a > 1").Check("z")
+	tk.MustQuery("select a from t where b is null").Check("2")
+	tk.MustQuery("select a from t where a between 2 and 3").Check("2", "3")
+	tk.MustQuery("select a from t where a > 10").Check()
+	tk.MustQuery("select 1 + 1, 'a'").Check("2 a")
+
+	// The rows are returned across the chunks.
+	tk.MustExec("create table big (a int primary key, b int)")
+	insertRows(tk, "big", 0, 3000, func(i int) string { return fmt.Sprint(i % 7) })
+	tk.MustExec("set tidb_max_chunk_size = 32")
+	tk.MustQuery("select count(*), sum(a), sum(b) from big").Check("3000 4498500 8994")
+	tk.MustQuery("select count(*) from big where b = 3").Check("429")
+}
+
+func TestLimitTopNSort(t *testing.T) {
+	tk := newExecTestKit(t)
+	tk.MustExec("create table t (a int primary key, b int, c varchar(10))")
+	tk.MustExec("insert into t values (1, 3, 'b'), (2, null, 'a'), (3, 1, 'c'), (4, 3, 'a'), (5, 2, null)")
+	tk.MustQuery("select a from t limit 2").Check("1", "2")
+	tk.MustQuery("select a from t limit 1, 2").Check("2", "3")
+	tk.MustQuery("select a from t limit 10 offset 4").Check("5")
+	tk.MustQuery("select a from t limit 0").Check()
+
+	// The NULLs are the smallest.
+	tk.MustQuery("select a from t order by b, a").Check("2", "3", "5", "1", "4")
+	tk.MustQuery("select a from t order by b desc, a desc").Check("4", "1", "5", "3", "2")
+	tk.MustQuery("select a from t order by c, b desc").Check("5", "4", "2", "1", "3")
+	tk.MustQuery("select a, b + a as x from t order by x desc limit 2").Check("4 7", "5 7")
+	tk.MustQuery("select a from t order by b desc, a limit 1, 2").Check("4", "5")
+	tk.MustQuery("select a from t where b is not null order by c desc limit 10").Check("3", "1", "4", "5")
+
+	tk.MustExec("create table big (a int primary key, b int)")
+	insertRows(tk, "big", 0, 3000, func(i int) string { return fmt.Sprint((i * 7919) % 3000) })
+	tk.MustExec("set tidb_max_chunk_size = 32")
+	tk.MustQuery("select b from big order by b desc limit 3").Check("2999", "2998", "2997")
+	// 1000 * 7919 % 3000 = 2000.
+	tk.MustQuery("select a from big order by b limit 2000, 1").Check("1000")
+}
@@ -0,0 +1,122 @@
+package executor
+
+import (
+	"grant-db/types"
+	"grant-db/util/chunk"
+	"grant-db/util/codec"
+	"grant-db/util/memory"
+)
+
+// hashRowContainer holds the rows of the build side of a hash join and the hash table from the join keys to
+// the rows, the rows are spilled to the disk when the memory quota is exceeded but the hash table is kept in
+// the memory. The rows of NULL keys match no rows, they're kept aside for the NULL aware joins.
+type hashRowContainer struct {
+	allTypes  []*types.FieldType
+	keyColIdx []int
+
+	rowContainer *chunk.RowContainer
+	hashTable    map[string][]chunk.RowPtr
+	nullKeyRows  []chunk.RowPtr
+	keyBuf       []byte
+
+	// memTracker tracks the memory of the hash table, the rows are tracked by the tracker of the rowContainer.
+	memTracker *memory.Tracker
+}
+
+func newHashRowContainer(allTypes []*types.FieldType, keyColIdx []int, chunkSize int) *hashRowContainer {
+	return &hashRowContainer{
+		allTypes:     allTypes,
+		keyColIdx:    keyColIdx,
+		rowContainer: chunk.NewRowContainer(allTypes, chunkSize),
+		hashTable:    make(map[string][]chunk.RowPtr),
+		memTracker:   memory.NewTracker(memory.LabelForSQLText),
+	}
+}
+
+// The approximate sizes of the entries of the hash table.
+const (
+	rowPtrSize    = 8
+	hashEntrySize = 40
+)
+
+// PutChunk puts the rows of the chunk into the hash table, the chunk is kept by the container.
+func (c *hashRowContainer) PutChunk(chk *chunk.Chunk) error {
+	if chk.NumRows() == 0 {
+		return nil
+	}
+	chkIdx := uint32(c.rowContainer.NumChunks())
+	var consumed int64
+	for i := 0; i < chk.NumRows(); i++ {
+		ptr := chunk.RowPtr{ChkIdx: chkIdx, RowIdx: uint32(i)}
+		var hasNull bool
+		c.keyBuf, hasNull = codec.HashChunkRow(c.keyBuf[:0], chk.GetRow(i), c.allTypes, c.keyColIdx)
+		if hasNull {
+			c.nullKeyRows = append(c.nullKeyRows, ptr)
+			consumed += rowPtrSize
+			continue
+		}
+		ptrs, ok := c.hashTable[string(c.keyBuf)]
+		if !ok {
+			consumed += int64(len(c.keyBuf)) + hashEntrySize
+		}
+		c.hashTable[string(c.keyBuf)] = append(ptrs, ptr)
+		consumed += rowPtrSize
+	}
+	c.memTracker.Consume(consumed)
+	return c.rowContainer.Add(chk)
+}
+
+// getRows returns the rows of the pointers.
+func (c *hashRowContainer) getRows(ptrs []chunk.RowPtr, rows []chunk.Row) ([]chunk.Row, error) {
+	rows = rows[:0]
+	for _, ptr := range ptrs {
+		row, err := c.rowContainer.GetRow(ptr)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// GetMatchedRows returns the rows whose join keys are encoded to the key.
+func (c *hashRowContainer) GetMatchedRows(key []byte, rows []chunk.Row) ([]chunk.Row, error) {
+	return c.getRows(c.hashTable[string(key)], rows)
+}
+
+// GetNullKeyRows returns the rows of NULL keys.
+func (c *hashRowContainer) GetNullKeyRows(rows []chunk.Row) ([]chunk.Row, error) {
+	return c.getRows(c.nullKeyRows, rows)
+}
+
+// WalkChunks calls walkFn on the rows of the chunks one by one, the walk stops if stop is true or an error
+// is returned.
+func (c *hashRowContainer) WalkChunks(rows []chunk.Row, walkFn func(rows []chunk.Row) (stop bool, err error)) error {
+	for chkIdx := 0; chkIdx < c.rowContainer.NumChunks(); chkIdx++ {
+		rows = rows[:0]
+		for rowIdx := 0; rowIdx < c.rowContainer.NumRowsOfChunk(chkIdx); rowIdx++ {
+			row, err := c.rowContainer.GetRow(chunk.RowPtr{ChkIdx: uint32(chkIdx), RowIdx: uint32(rowIdx)})
+			if err != nil {
+				return err
+			}
+			rows = append(rows, row)
+		}
+		if stop, err := walkFn(rows); stop || err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Len returns the number of rows.
+func (c *hashRowContainer) Len() int {
+	return c.rowContainer.Len()
+}
+
+// Close releases the hash table and the rows.
+func (c *hashRowContainer) Close() error {
+	c.memTracker.Consume(-c.memTracker.BytesConsumed())
+	c.hashTable = nil
+	c.nullKeyRows = nil
+	return c.rowContainer.Close()
+}
@@ -0,0 +1,279 @@
+package executor
+
+import (
+	"context"
+
+	"grant-db/expression"
+	"grant-db/types"
+	"grant-db/util/chunk"
+	"grant-db/util/codec"
+	"grant-db/util/memory"
+	"grant-db/util/ranger"
+)
+
+// IndexLookUpJoin reads the outer rows batch by batch, and the inner rows of the join keys of a batch by the
+// handles or the index of the inner table. The rows are returned in the order of the outer rows.
+type IndexLookUpJoin struct {
+	baseExecutor
+
+	outerExec   Executor
+	innerExec   Executor
+	outerFilter expression.CNFExprs
+	joiner      joiner
+	batchSize   int
+	// outerKeyColIdx and innerKeyColIdx are the offsets of the join keys in the rows of the children.
+	outerKeyColIdx []int
+	innerKeyColIdx []int
+	// keyOff2IdxOff maps the offsets of the join keys to the offsets of the index columns, the keys of -1 are
+	// only checked by the inner rows. idxColLens are the prefix lengths of the index columns.
+	keyOff2IdxOff []int
+	idxColLens    []int
+	// The inner rows are read by the handles of tableReader, or the ranges of indexReader.
+	tableReader *TableReaderExecutor
+	indexReader *IndexReaderExecutor
+
+	outerDone  bool
+	outerChk   *chunk.Chunk
+	outerBatch *chunk.List
+	outerRows  []chunk.Row
+	selected   []bool
+	// cursor is the next row of outerRows to be joined.
+	cursor int
+
+	innerList  *chunk.List
+	innerChk   *chunk.Chunk
+	lookupMap  map[string][]chunk.Row
+	keyBuf     []byte
+	memTracker *memory.Tracker
+}
+
+// Open implements the Executor Open interface, the inner child is opened by every batch.
+func (e *IndexLookUpJoin) Open(ctx context.Context) error {
+	if err := e.outerExec.Open(ctx); err != nil {
+		return err
+	}
+	e.memTracker = memory.NewTracker(e.id)
+	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	e.outerChk = newFirstChunk(e.outerExec)
+	e.outerBatch = chunk.NewList(retTypes(e.outerExec), e.initCap, e.maxChunkSize)
+	e.outerBatch.GetMemTracker().AttachTo(e.memTracker)
+	e.innerChk = newFirstChunk(e.innerExec)
+	e.innerList = chunk.NewList(retTypes(e.innerExec), e.initCap, e.maxChunkSize)
+	e.innerList.GetMemTracker().AttachTo(e.memTracker)
+	e.outerDone = false
+	e.outerRows = e.outerRows[:0]
+	e.cursor = 0
+	return nil
+}
+
+// Next implements the Executor Next interface.
+func (e *IndexLookUpJoin) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	for !req.IsFull() {
+		if e.cursor >= len(e.outerRows) {
+			if e.outerDone {
+				return nil
+			}
+			if err := e.fetchOuterBatch(ctx); err != nil {
+				return err
+			}
+			if len(e.outerRows) == 0 {
+				return nil
+			}
+			if err := e.fetchInnerRows(ctx); err != nil {
+				return err
+			}
+			continue
+		}
+		outer := e.outerRows[e.cursor]
+		selected := e.selected[e.cursor]
+		e.cursor++
+		if !selected {
+			e.joiner.onMissMatch(false, outer, req)
+			continue
+		}
+		var hasNull bool
+		e.keyBuf, hasNull = codec.HashChunkRow(e.keyBuf[:0], outer, retTypes(e.outerExec), e.outerKeyColIdx)
+		if hasNull {
+			e.joiner.onMissMatch(false, outer, req)
+			continue
+		}
+		matched, _, err := e.joiner.tryToMatchInners(outer, e.lookupMap[string(e.keyBuf)], req)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			e.joiner.onMissMatch(false, outer, req)
+		}
+	}
+	return nil
+}
+
+// fetchOuterBatch reads the next batch of the outer rows, and evaluates the outer filter on them.
+func (e *IndexLookUpJoin) fetchOuterBatch(ctx context.Context) error {
+	e.outerBatch.Reset()
+	e.outerRows = e.outerRows[:0]
+	e.selected = e.selected[:0]
+	e.cursor = 0
+	for e.outerBatch.Len() < e.batchSize {
+		if err := Next(ctx, e.outerExec, e.outerChk); err != nil {
+			return err
+		}
+		if e.outerChk.NumRows() == 0 {
+			e.outerDone = true
+			break
+		}
+		var selected []bool
+		if len(e.outerFilter) > 0 {
+			var err error
+			if selected, err = expression.VectorizedFilter(e.ctx, e.outerFilter, e.outerChk, nil); err != nil {
+				return err
+			}
+		}
+		for i := 0; i < e.outerChk.NumRows(); i++ {
+			e.outerRows = append(e.outerRows, e.outerBatch.GetRow(e.outerBatch.AppendRow(e.outerChk.GetRow(i))))
+			e.selected = append(e.selected, selected == nil || selected[i])
+		}
+	}
+	return nil
+}
+
+// fetchInnerRows reads the inner rows of the keys of the outer batch, which are put into the lookup map by
+// all the join keys since some of the keys may not be used to read the rows.
+func (e *IndexLookUpJoin) fetchInnerRows(ctx context.Context) error {
+	e.innerList.Reset()
+	e.lookupMap = make(map[string][]chunk.Row)
+	var err error
+	if e.tableReader != nil {
+		e.tableReader.resetHandles(e.buildLookUpHandles())
+	} else {
+		var ranges []kvRange
+		if ranges, err = e.buildLookUpRanges(); err != nil {
+			return err
+		}
+		e.indexReader.resetRanges(ranges)
+	}
+	if err = e.innerExec.Open(ctx); err != nil {
+		return err
+	}
+	for {
+		if err = Next(ctx, e.innerExec, e.innerChk); err != nil || e.innerChk.NumRows() == 0 {
+			break
+		}
+		e.innerList.Add(e.innerChk)
+		e.innerChk = chunk.Renew(e.innerChk, e.maxChunkSize)
+	}
+	if err1 := e.innerExec.Close(); err == nil {
+		err = err1
+	}
+	if err != nil {
+		return err
+	}
+	innerTypes := retTypes(e.innerExec)
+	return e.innerList.Walk(func(row chunk.Row) error {
+		var hasNull bool
+		e.keyBuf, hasNull = codec.HashChunkRow(e.keyBuf[:0], row, innerTypes, e.innerKeyColIdx)
+		if !hasNull {
+			e.lookupMap[string(e.keyBuf)] = append(e.lookupMap[string(e.keyBuf)], row)
+		}
+		return nil
+	})
+}
+
+// buildLookUpHandles returns the distinct handles of the outer rows, the key of the handle is the only one
+// which is used to read the rows.
+func (e *IndexLookUpJoin) buildLookUpHandles() []int64 {
+	var keyOff int
+	for i, idxOff := range e.keyOff2IdxOff {
+		if idxOff == 0 {
+			keyOff = i
+		}
+	}
+	colIdx := e.outerKeyColIdx[keyOff]
+	handles := make([]int64, 0, len(e.outerRows))
+	seen := make(map[int64]struct{}, len(e.outerRows))
+	for i, row := range e.outerRows {
+		if !e.selected[i] || row.IsNull(colIdx) {
+			continue
+		}
+		handle := row.GetInt64(colIdx)
+		if _, ok := seen[handle]; !ok {
+			seen[handle] = struct{}{}
+			handles = append(handles, handle)
+		}
+	}
+	return handles
+}
+
+// buildLookUpRanges returns the distinct point ranges of the index columns of the keys of the outer rows.
+// The values are converted to the types of the index columns, the ones which can't be converted are equal
+// to no values of the columns.
+func (e *IndexLookUpJoin) buildLookUpRanges() ([]kvRange, error) {
+	idxCols := e.indexReader.index.Meta().Columns
+	numCols := 0
+	for _, idxOff := range e.keyOff2IdxOff {
+		if idxOff >= 0 {
+			numCols++
+		}
+	}
+	sc := e.ctx.GetSessionVars().StmtCtx
+	ranges := make([]*ranger.Range, 0, len(e.outerRows))
+	seen := make(map[string]struct{}, len(e.outerRows))
+	outerTypes := retTypes(e.outerExec)
+	var keyBuf []byte
+	for i, row := range e.outerRows {
+		if !e.selected[i] {
+			continue
+		}
+		vals, ok := make([]types.Datum, numCols), true
+		for keyOff, idxOff := range e.keyOff2IdxOff {
+			if idxOff < 0 {
+				continue
+			}
+			colIdx := e.outerKeyColIdx[keyOff]
+			if row.IsNull(colIdx) {
+				ok = false
+				break
+			}
+			colInfo := e.indexReader.tblInfo.Columns[idxCols[idxOff].Offset]
+			d := row.GetDatum(colIdx, outerTypes[colIdx])
+			val, err := d.ConvertTo(sc, &colInfo.FieldType)
+			if err != nil {
+				ok = false
+				break
+			}
+			ranger.CutDatumByPrefixLen(&val, e.idxColLens[idxOff], &colInfo.FieldType)
+			vals[idxOff] = val
+		}
+		if !ok {
+			continue
+		}
+		var err error
+		if keyBuf, err = codec.EncodeKey(keyBuf[:0], vals...); err != nil {
+			return nil, err
+		}
+		if _, ok := seen[string(keyBuf)]; ok {
+			continue
+		}
+		seen[string(keyBuf)] = struct{}{}
+		ranges = append(ranges, &ranger.Range{LowVal: vals, HighVal: vals})
+	}
+	return indexRangesToKVRanges(e.indexReader.tblInfo, e.indexReader.index, ranges)
+}
+
+// Close implements the Executor Close interface.
+func (e *IndexLookUpJoin) Close() error {
+	if e.outerBatch != nil {
+		e.outerBatch.Clear()
+		e.outerBatch = nil
+	}
+	if e.innerList != nil {
+		e.innerList.Clear()
+		e.innerList = nil
+	}
+	if e.memTracker != nil {
+		e.memTracker.Consume(-e.memTracker.BytesConsumed())
+	}
+	e.outerRows, e.lookupMap = nil, nil
+	return e.outerExec.Close()
+}
@@ -0,0 +1,224 @@
+package executor
+
+import (
+	"context"
+
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/kv"
+	"grant-db/statistics"
+	"grant-db/table"
+	"grant-db/tablecodec"
+	"grant-db/types"
+	"grant-db/util/chunk"
+	"grant-db/util/codec"
+	"grant-db/util/ranger"
+)
+
+// indexRangesToKVRanges converts the ranges of the index columns to the key ranges of the index. The keys
+// have the values as the prefixes, so [low, high] is [low, high.PrefixNext()).
+func indexRangesToKVRanges(tblInfo *model.TableInfo, idx table.Index, ranges []*ranger.Range) ([]kvRange, error) {
+	colInfos := make([]*model.ColumnInfo, 0, len(idx.Meta().Columns))
+	for _, idxCol := range idx.Meta().Columns {
+		colInfos = append(colInfos, tblInfo.Columns[idxCol.Offset])
+	}
+	krs := make([]kvRange, 0, len(ranges))
+	for _, ran := range ranges {
+		low, err := statistics.EncodeValues(ran.LowVal, colInfos)
+		if err != nil {
+			return nil, err
+		}
+		high, err := statistics.EncodeValues(ran.HighVal, colInfos)
+		if err != nil {
+			return nil, err
+		}
+		startKey := append(append(kv.Key(nil), idx.Prefix()...), low...)
+		endKey := append(append(kv.Key(nil), idx.Prefix()...), high...)
+		if ran.LowExclude {
+			startKey = startKey.PrefixNext()
+		}
+		if !ran.HighExclude {
+			endKey = endKey.PrefixNext()
+		}
+		krs = append(krs, kvRange{startKey: startKey, endKey: endKey})
+	}
+	return krs, nil
+}
+
+// decodeIndexHandle returns the handle of the index entry, which is appended to the key if the entry isn't
+// distinct, or else it's the value.
+func decodeIndexHandle(rest []byte, value []byte) (int64, error) {
+	if len(rest) > 0 {
+		_, d, err := codec.DecodeOne(rest)
+		if err != nil {
+			return 0, err
+		}
+		return d.GetInt64(), nil
+	}
+	_, handle, err := codec.DecodeInt(value)
+	return handle, err
+}
+
+// IndexReaderExecutor reads the values of the columns covered by the index in the ranges of the index.
+type IndexReaderExecutor struct {
+	baseExecutor
+
+	index   table.Index
+	tblInfo *model.TableInfo
+	ranges  []kvRange
+	desc    bool
+	cols    []*model.ColumnInfo
+	// colOffsets are the offsets of the columns in the index columns, it's -1 for the integer primary key,
+	// which is the handle.
+	colOffsets []int
+	// outputHandle is true if the handle is appended as the last column, which is read by the index lookup.
+	outputHandle bool
+
+	scanner *kvScanner
+}
+
+func (e *IndexReaderExecutor) initColOffsets() {
+	e.colOffsets = make([]int, 0, len(e.cols))
+	for _, col := range e.cols {
+		offset := -1
+		if !e.tblInfo.PKIsHandle || !mysql.HasPriKeyFlag(col.Flag) {
+			for i, idxCol := range e.index.Meta().Columns {
+				if idxCol.Name.L == col.Name.L {
+					offset = i
+					break
+				}
+			}
+		}
+		e.colOffsets = append(e.colOffsets, offset)
+	}
+	if e.outputHandle {
+		e.retFieldTypes = append(e.retFieldTypes, types.NewFieldType(mysql.TypeLonglong))
+	}
+}
+
+// Open implements the Executor Open interface.
+func (e *IndexReaderExecutor) Open(ctx context.Context) error {
+	txn, err := e.ctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	e.scanner = &kvScanner{txn: txn, ranges: e.ranges, desc: e.desc}
+	return nil
+}
+
+// resetRanges sets the ranges of the index to be read, which are read after the executor is opened.
+func (e *IndexReaderExecutor) resetRanges(ranges []kvRange) {
+	e.ranges = ranges
+}
+
+// Next implements the Executor Next interface.
+func (e *IndexReaderExecutor) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	numIdxCols := len(e.index.Meta().Columns)
+	for !req.IsFull() {
+		key, value, ok, err := e.scanner.next()
+		if err != nil || !ok {
+			return err
+		}
+		values, rest, err := tablecodec.CutIndexKey(key, numIdxCols)
+		if err != nil {
+			return err
+		}
+		handle, err := decodeIndexHandle(rest, value)
+		if err != nil {
+			return err
+		}
+		for i, offset := range e.colOffsets {
+			if offset == -1 {
+				if mysql.HasUnsignedFlag(e.cols[i].Flag) {
+					req.AppendUint64(i, uint64(handle))
+				} else {
+					req.AppendInt64(i, handle)
+				}
+				continue
+			}
+			_, d, err := codec.DecodeOne(values[offset])
+			if err != nil {
+				return err
+			}
+			if d, err = codec.Unflatten(d, &e.cols[i].FieldType); err != nil {
+				return err
+			}
+			req.AppendDatum(i, &d)
+		}
+		if e.outputHandle {
+			req.AppendInt64(len(e.colOffsets), handle)
+		}
+	}
+	return nil
+}
+
+// Close implements the Executor Close interface.
+func (e *IndexReaderExecutor) Close() error {
+	if e.scanner != nil {
+		e.scanner.close()
+		e.scanner = nil
+	}
+	return nil
+}
+
+// IndexLookUpExecutor reads the handles from the index side, and then the rows of the handles from the
+// table side batch by batch, the rows are in the order of the index.
+type IndexLookUpExecutor struct {
+	baseExecutor
+
+	indexExec Executor
+	// handleIdx is the offset of the handle column in the rows of the index side.
+	handleIdx   int
+	tableReader *TableReaderExecutor
+	// tableExec is the table reader or the selection of the table filters on it.
+	tableExec Executor
+
+	indexResult *chunk.Chunk
+	handles     []int64
+}
+
+// Open implements the Executor Open interface.
+func (e *IndexLookUpExecutor) Open(ctx context.Context) error {
+	if err := e.indexExec.Open(ctx); err != nil {
+		return err
+	}
+	if err := e.tableExec.Open(ctx); err != nil {
+		return err
+	}
+	e.indexResult = newFirstChunk(e.indexExec)
+	return nil
+}
+
+// Next implements the Executor Next interface.
+func (e *IndexLookUpExecutor) Next(ctx context.Context, req *chunk.Chunk) error {
+	for {
+		if err := Next(ctx, e.tableExec, req); err != nil {
+			return err
+		}
+		if req.NumRows() > 0 {
+			return nil
+		}
+		if err := Next(ctx, e.indexExec, e.indexResult); err != nil {
+			return err
+		}
+		if e.indexResult.NumRows() == 0 {
+			return nil
+		}
+		e.handles = e.handles[:0]
+		for i := 0; i < e.indexResult.NumRows(); i++ {
+			e.handles = append(e.handles, e.indexResult.GetRow(i).GetInt64(e.handleIdx))
+		}
+		e.tableReader.resetHandles(e.handles)
+	}
+}
+
+// Close implements the Executor Close interface.
+func (e *IndexLookUpExecutor) Close() error {
+	err := e.indexExec.Close()
+	if err1 := e.tableExec.Close(); err == nil {
+		err = err1
+	}
+	e.indexResult = nil
+	return err
+}
@@ -0,0 +1,409 @@
+package executor
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/expression"
+	"grant-db/kv"
+	"grant-db/meta/autoid"
+	"grant-db/table"
+	"grant-db/types"
+	"grant-db/util/chunk"
+)
+
+// InsertExec writes the rows of VALUES or of the child to the table, it's also the executor of REPLACE.
+// The rows of the child are read before any of them is written since the child may read the table.
+type InsertExec struct {
+	baseExecutor
+
+	table table.Table
+	// colOffsets are the offsets of the columns the values are written to in the public columns.
+	colOffsets  []int
+	lists       [][]expression.Expression
+	onDuplicate []*expression.Assignment
+	isReplace   bool
+	ignoreErr   bool
+
+	// tps are the types of the public columns, and dupTps are the ones of the rows the assignments of ON
+	// DUPLICATE KEY UPDATE are evaluated on.
+	tps    []*types.FieldType
+	dupTps []*types.FieldType
+	// badNullAsWarning turns the error of a NULL value written to a NOT NULL column into a warning.
+	badNullAsWarning bool
+	// autoColOffset is the offset of the AUTO_INCREMENT or AUTO_RANDOM column in the public columns, or -1.
+	autoColOffset int
+	records       uint64
+	duplicates    uint64
+	done          bool
+}
+
+// Open implements the Executor Open interface.
+func (e *InsertExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.tps = fieldTypes(e.table.Cols())
+	e.dupTps = append(append(make([]*types.FieldType, 0, 2*len(e.tps)), e.tps...), e.tps...)
+	// A NULL value is only written as the zero value by the statements of multiple rows, like MySQL.
+	sc := e.ctx.GetSessionVars().StmtCtx
+	e.badNullAsWarning = e.ignoreErr || (sc.BadNullAsWarning && (len(e.children) > 0 || len(e.lists) > 1))
+	e.autoColOffset = -1
+	tblInfo := e.table.Meta()
+	for i, col := range e.table.Cols() {
+		if mysql.HasAutoIncrementFlag(col.Flag) || (tblInfo.ContainsAutoRandomBits() && col.IsPKHandleColumn(tblInfo)) {
+			e.autoColOffset = i
+		}
+	}
+	e.records, e.duplicates = 0, 0
+	e.done = false
+	return nil
+}
+
+// Next implements the Executor Next interface, all the rows are written by the first call.
+func (e *InsertExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.done {
+		return nil
+	}
+	e.done = true
+	if len(e.children) > 0 {
+		if err := e.insertSelectRows(ctx); err != nil {
+			return err
+		}
+	} else {
+		for i, list := range e.lists {
+			row, err := e.evalRow(list, int64(i+1))
+			if err != nil {
+				return err
+			}
+			if err = e.addRow(row); err != nil {
+				return err
+			}
+		}
+	}
+	if len(e.children) > 0 || len(e.lists) > 1 {
+		sc := e.ctx.GetSessionVars().StmtCtx
+		sc.SetMessage(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrInsertInfo], e.records, e.duplicates, sc.WarningCount()))
+	}
+	return nil
+}
+
+// evalRow evaluates the row of VALUES, the values are evaluated in order on the row being built, whose
+// columns which are not written yet are the default values.
+func (e *InsertExec) evalRow(list []expression.Expression, rowNum int64) ([]types.Datum, error) {
+	row, err := e.defaultRow(rowNum)
+	if err != nil {
+		return nil, err
+	}
+	for i, offset := range e.colOffsets {
+		if list[i] == nil {
+			if row[offset], err = e.defaultValue(offset, rowNum); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		val, err := list[i].Eval(datumsToRow(e.tps, row))
+		if err != nil {
+			return nil, err
+		}
+		if row[offset], err = e.castValue(val, offset, rowNum); err != nil {
+			return nil, err
+		}
+	}
+	return row, nil
+}
+
+// insertSelectRows reads all the rows of the child, and writes them.
+func (e *InsertExec) insertSelectRows(ctx context.Context) error {
+	childRows, err := readAllRows(ctx, e.children[0])
+	if err != nil {
+		return err
+	}
+	for i, childRow := range childRows {
+		rowNum := int64(i + 1)
+		row, err := e.defaultRow(rowNum)
+		if err != nil {
+			return err
+		}
+		for j, offset := range e.colOffsets {
+			if row[offset], err = e.castValue(childRow[j], offset, rowNum); err != nil {
+				return err
+			}
+		}
+		if err = e.addRow(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// defaultRow returns the row whose columns which aren't written are the default values.
+func (e *InsertExec) defaultRow(rowNum int64) ([]types.Datum, error) {
+	cols := e.table.Cols()
+	row := make([]types.Datum, len(cols))
+	written := make([]bool, len(cols))
+	for _, offset := range e.colOffsets {
+		written[offset] = true
+	}
+	for i := range cols {
+		if written[i] {
+			continue
+		}
+		var err error
+		if row[i], err = e.defaultValue(i, rowNum); err != nil {
+			return nil, err
+		}
+	}
+	return row, nil
+}
+
+// defaultValue returns the default value of the column of offset, the column which has no default value gets
+// its zero value with a warning if the errors are turned into warnings. The value of the auto ID column is
+// NULL, which is replaced by an allocated ID.
+func (e *InsertExec) defaultValue(offset int, rowNum int64) (types.Datum, error) {
+	if offset == e.autoColOffset {
+		return types.Datum{}, nil
+	}
+	sc := e.ctx.GetSessionVars().StmtCtx
+	col := e.table.Cols()[offset]
+	val, err := table.GetColDefaultValue(col.ColumnInfo)
+	if err != nil {
+		if !table.ErrNoDefaultValue.Equal(err) || !sc.BadNullAsWarning {
+			return val, err
+		}
+		sc.AppendWarning(err)
+		return table.GetZeroValue(col.ColumnInfo), nil
+	}
+	return castValue(sc, val, col, rowNum, e.badNullAsWarning)
+}
+
+// castValue converts the value written to the column of offset, a NULL written to the auto ID column is kept.
+func (e *InsertExec) castValue(val types.Datum, offset int, rowNum int64) (types.Datum, error) {
+	if offset == e.autoColOffset && val.IsNull() {
+		return val, nil
+	}
+	return castValue(e.ctx.GetSessionVars().StmtCtx, val, e.table.Cols()[offset], rowNum, e.badNullAsWarning)
+}
+
+// fillAutoID writes an allocated ID to the auto ID column if its value is NULL, or 0 unless the sql_mode is
+// NO_AUTO_VALUE_ON_ZERO. The allocator is rebased by the value written explicitly.
+func (e *InsertExec) fillAutoID(row []types.Datum) error {
+	if e.autoColOffset == -1 {
+		return nil
+	}
+	vars := e.ctx.GetSessionVars()
+	col := e.table.Cols()[e.autoColOffset]
+	if val := row[e.autoColOffset]; !val.IsNull() {
+		id, err := val.ToInt64(vars.StmtCtx)
+		if err != nil {
+			return err
+		}
+		if id != 0 || vars.SQLMode&mysql.ModeNoAutoValueOnZero != 0 {
+			if vars.StmtCtx.InsertID == 0 {
+				vars.StmtCtx.InsertID = uint64(id)
+			}
+			return e.rebaseAutoID(id)
+		}
+	}
+	alloc := e.table.Allocator()
+	if alloc == nil {
+		return errors.Errorf("table %s can't allocate IDs", e.table.Meta().Name)
+	}
+	var id int64
+	var err error
+	if e.table.Meta().ContainsAutoRandomBits() {
+		id, err = e.allocAutoRandomID(alloc)
+	} else {
+		increment, offset := int64(vars.AutoIncrementIncrement), int64(vars.AutoIncrementOffset)
+		// The offset is ignored if it's greater than the increment like MySQL.
+		if offset > increment {
+			offset = 1
+		}
+		var min int64
+		min, _, err = alloc.Alloc(1, increment, offset)
+		id = autoid.SeekToFirstAutoID(min, increment, offset)
+	}
+	if err != nil {
+		return err
+	}
+	d := types.NewIntDatum(id)
+	if row[e.autoColOffset], err = d.ConvertTo(vars.StmtCtx, &col.FieldType); err != nil {
+		return err
+	}
+	if vars.StmtCtx.LastInsertID == 0 {
+		vars.StmtCtx.LastInsertID = uint64(id)
+	}
+	return nil
+}
+
+// autoRandomIncrementalBits returns the number of the low bits of the AUTO_RANDOM IDs which are allocated
+// incrementally, the shard bits are above them and below the sign bit of the signed column.
+func (e *InsertExec) autoRandomIncrementalBits() uint64 {
+	tblInfo := e.table.Meta()
+	bits := 64 - tblInfo.AutoRandomBits
+	if !tblInfo.IsAutoRandomBitColUnsigned() {
+		bits--
+	}
+	return bits
+}
+
+// allocAutoRandomID allocates the low bits of the AUTO_RANDOM ID, whose shard bits are the hash of the start
+// timestamp of the transaction, so the rows written by different transactions are scattered.
+func (e *InsertExec) allocAutoRandomID(alloc autoid.Allocator) (int64, error) {
+	_, low, err := alloc.Alloc(1, 1, 1)
+	if err != nil {
+		return 0, err
+	}
+	incrementalBits := e.autoRandomIncrementalBits()
+	if uint64(low) >= 1<<incrementalBits {
+		return 0, autoid.ErrAutoincReadFailed
+	}
+	txn, err := e.ctx.Txn(true)
+	if err != nil {
+		return 0, err
+	}
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], txn.StartTS())
+	h := fnv.New64a()
+	_, _ = h.Write(buf[:])
+	shard := h.Sum64() & (1<<e.table.Meta().AutoRandomBits - 1)
+	return int64(shard<<incrementalBits | uint64(low)), nil
+}
+
+// rebaseAutoID rebases the allocator by the ID written explicitly, only the low bits of an AUTO_RANDOM ID
+// are allocated.
+func (e *InsertExec) rebaseAutoID(id int64) error {
+	if e.table.Meta().ContainsAutoRandomBits() {
+		id &= 1<<e.autoRandomIncrementalBits() - 1
+	}
+	if id <= 0 || e.table.Allocator() == nil {
+		return nil
+	}
+	return e.table.Allocator().Rebase(id)
+}
+
+// addRow writes the row, the row which conflicts with an existing one replaces it, updates it by ON
+// DUPLICATE KEY UPDATE, or is skipped with a warning by IGNORE.
+func (e *InsertExec) addRow(row []types.Datum) error {
+	e.records++
+	if err := e.fillAutoID(row); err != nil {
+		return err
+	}
+	if e.isReplace {
+		return e.replaceRow(row)
+	}
+	err := e.addRecord(row)
+	if err == nil || !kv.ErrKeyExists.Equal(err) {
+		return err
+	}
+	if len(e.onDuplicate) > 0 {
+		return e.updateDupRow(row, err)
+	}
+	if e.ignoreErr {
+		e.duplicates++
+		e.ctx.GetSessionVars().StmtCtx.AppendWarning(err)
+		return nil
+	}
+	return err
+}
+
+func (e *InsertExec) addRecord(row []types.Datum) error {
+	if _, err := e.table.AddRecord(e.ctx, row); err != nil {
+		return err
+	}
+	e.ctx.GetSessionVars().StmtCtx.AddAffectedRows(1)
+	e.ctx.GetSessionVars().TxnCtx.UpdateDeltaForTable(e.table.Meta().ID, 1, 1)
+	return nil
+}
+
+// replaceRow removes the rows which conflict with the row before it's written. Nothing is written if a row
+// which is the same as it exists, which is counted as an affected row like MySQL.
+func (e *InsertExec) replaceRow(row []types.Datum) error {
+	handles, err := dupHandles(e.ctx, e.table, row)
+	if err != nil {
+		return err
+	}
+	txn, err := e.ctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	sc := e.ctx.GetSessionVars().StmtCtx
+	for _, h := range handles {
+		oldRow, err := e.table.RowWithCols(txn, h, e.table.Cols())
+		if err != nil {
+			return err
+		}
+		same, err := equalDatums(sc, oldRow, row)
+		if err != nil {
+			return err
+		}
+		if same {
+			sc.AddAffectedRows(1)
+			return nil
+		}
+		if err = e.table.RemoveRecord(e.ctx, h, oldRow); err != nil {
+			return err
+		}
+		e.duplicates++
+		sc.AddAffectedRows(1)
+		e.ctx.GetSessionVars().TxnCtx.UpdateDeltaForTable(e.table.Meta().ID, -1, 1)
+	}
+	return e.addRecord(row)
+}
+
+// updateDupRow updates the row which conflicts with the row by the assignments of ON DUPLICATE KEY UPDATE,
+// which are evaluated in order on the row being updated followed by the row being inserted. The changed row
+// is counted as 2 affected rows like MySQL.
+func (e *InsertExec) updateDupRow(row []types.Datum, dupErr error) error {
+	handles, err := dupHandles(e.ctx, e.table, row)
+	if err != nil {
+		return err
+	}
+	if len(handles) == 0 {
+		return dupErr
+	}
+	txn, err := e.ctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	cols := e.table.Cols()
+	oldRow, err := e.table.RowWithCols(txn, handles[0], cols)
+	if err != nil {
+		return err
+	}
+	sc := e.ctx.GetSessionVars().StmtCtx
+	newRow := append(make([]types.Datum, 0, 2*len(cols)), oldRow...)
+	newRow = append(newRow, row...)
+	assigned := make([]bool, len(cols))
+	for _, assign := range e.onDuplicate {
+		val, err := assign.Expr.Eval(datumsToRow(e.dupTps, newRow))
+		if err != nil {
+			return err
+		}
+		idx := assign.Col.Index
+		if newRow[idx], err = castValue(sc, val, cols[idx], int64(e.records), sc.BadNullAsWarning); err != nil {
+			return err
+		}
+		assigned[idx] = true
+	}
+	e.duplicates++
+	changed, err := updateRecord(e.ctx, e.table, handles[0], oldRow, newRow[:len(cols)], assigned)
+	if err != nil {
+		if e.ignoreErr && kv.ErrKeyExists.Equal(err) {
+			sc.AppendWarning(err)
+			return nil
+		}
+		return err
+	}
+	if changed {
+		sc.AddAffectedRows(2)
+	} else {
+		sc.AddAffectedRows(affectedRowsOfFound(e.ctx, 1))
+	}
+	return nil
+}
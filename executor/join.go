@@ -0,0 +1,447 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"grant-db/expression"
+	"grant-db/planner"
+	"grant-db/types"
+	"grant-db/util/chunk"
+	"grant-db/util/codec"
+	"grant-db/util/memory"
+)
+
+// HashJoinExec builds the hash table by the rows of the inner child, and probes it by the rows of the outer
+// child concurrently. The rows are returned out of the order of the outer rows.
+type HashJoinExec struct {
+	baseExecutor
+
+	outerExec   Executor
+	innerExec   Executor
+	outerFilter expression.CNFExprs
+	// outerKeyColIdx and innerKeyColIdx are the offsets of the join keys in the rows of the children.
+	outerKeyColIdx []int
+	innerKeyColIdx []int
+	joinType       planner.JoinType
+	nullAware      bool
+	concurrency    int
+	// joiners are the joiners of the probe workers.
+	joiners []joiner
+
+	prepared     bool
+	rowContainer *hashRowContainer
+	memTracker   *memory.Tracker
+	diskTracker  *memory.Tracker
+
+	closeCh chan struct{}
+	wg      sync.WaitGroup
+	// outerChkCh sends the chunks of the outer rows to the probe workers, and outerChkResourceCh recycles them.
+	outerChkCh         chan *chunk.Chunk
+	outerChkResourceCh chan *chunk.Chunk
+	joinResultCh       chan *hashjoinWorkerResult
+}
+
+// hashjoinWorkerResult is a chunk of the joined rows or an error, the chunk is sent back to src after it's used.
+type hashjoinWorkerResult struct {
+	chk *chunk.Chunk
+	err error
+	src chan<- *chunk.Chunk
+}
+
+// Open implements the Executor Open interface.
+func (e *HashJoinExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.prepared = false
+	sc := e.ctx.GetSessionVars().StmtCtx
+	e.memTracker = memory.NewTracker(e.id)
+	e.memTracker.AttachTo(sc.MemTracker)
+	e.diskTracker = memory.NewTracker(e.id)
+	e.diskTracker.AttachTo(sc.DiskTracker)
+	return nil
+}
+
+// Next implements the Executor Next interface.
+func (e *HashJoinExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	if !e.prepared {
+		if err := e.buildHashTable(ctx); err != nil {
+			return err
+		}
+		e.startWorkers(ctx)
+		e.prepared = true
+	}
+	req.Reset()
+	result, ok := <-e.joinResultCh
+	if !ok {
+		return nil
+	}
+	if result.err != nil {
+		return result.err
+	}
+	req.SwapColumns(result.chk)
+	result.src <- result.chk
+	return nil
+}
+
+// buildHashTable reads all the rows of the inner child into the hash table, the rows are spilled to the disk
+// if the memory quota of the query is exceeded.
+func (e *HashJoinExec) buildHashTable(ctx context.Context) error {
+	e.rowContainer = newHashRowContainer(retTypes(e.innerExec), e.innerKeyColIdx, e.maxChunkSize)
+	e.rowContainer.memTracker.AttachTo(e.memTracker)
+	e.rowContainer.rowContainer.GetMemTracker().AttachTo(e.memTracker)
+	e.rowContainer.rowContainer.GetDiskTracker().AttachTo(e.diskTracker)
+	e.ctx.GetSessionVars().StmtCtx.MemTracker.FallbackOldAndSetNewAction(e.rowContainer.rowContainer.ActionSpill())
+	for {
+		chk := newFirstChunk(e.innerExec)
+		if err := Next(ctx, e.innerExec, chk); err != nil {
+			return err
+		}
+		if chk.NumRows() == 0 {
+			return nil
+		}
+		if err := e.rowContainer.PutChunk(chk); err != nil {
+			return err
+		}
+	}
+}
+
+// startWorkers starts the goroutine which reads the outer rows and the workers which probe the hash table by
+// them, joinResultCh is closed after they all exit.
+func (e *HashJoinExec) startWorkers(ctx context.Context) {
+	e.closeCh = make(chan struct{})
+	e.outerChkCh = make(chan *chunk.Chunk, e.concurrency)
+	e.outerChkResourceCh = make(chan *chunk.Chunk, e.concurrency)
+	for i := 0; i < e.concurrency; i++ {
+		e.outerChkResourceCh <- newFirstChunk(e.outerExec)
+	}
+	e.joinResultCh = make(chan *hashjoinWorkerResult, e.concurrency+1)
+	e.wg.Add(1)
+	go e.fetchOuterChunks(ctx)
+	for i := 0; i < e.concurrency; i++ {
+		e.wg.Add(1)
+		go e.runJoinWorker(i)
+	}
+	go func() {
+		e.wg.Wait()
+		close(e.joinResultCh)
+	}()
+}
+
+// sendResult sends the result to the main goroutine, it returns false if the executor is closed.
+func (e *HashJoinExec) sendResult(result *hashjoinWorkerResult) bool {
+	select {
+	case <-e.closeCh:
+		return false
+	case e.joinResultCh <- result:
+		return true
+	}
+}
+
+// recoverWorker sends the panic of a goroutine as an error, like the one of the exceeded memory quota.
+func (e *HashJoinExec) recoverWorker() {
+	if r := recover(); r != nil {
+		err, ok := r.(error)
+		if !ok {
+			err = fmt.Errorf("%v", r)
+		}
+		e.sendResult(&hashjoinWorkerResult{err: err})
+	}
+}
+
+func (e *HashJoinExec) fetchOuterChunks(ctx context.Context) {
+	defer func() {
+		close(e.outerChkCh)
+		e.wg.Done()
+	}()
+	defer e.recoverWorker()
+	if e.rowContainer.Len() == 0 && (e.joinType == planner.InnerJoin || e.joinType == planner.SemiJoin) {
+		return
+	}
+	for {
+		var chk *chunk.Chunk
+		select {
+		case <-e.closeCh:
+			return
+		case chk = <-e.outerChkResourceCh:
+		}
+		if err := Next(ctx, e.outerExec, chk); err != nil {
+			e.sendResult(&hashjoinWorkerResult{err: err})
+			return
+		}
+		if chk.NumRows() == 0 {
+			return
+		}
+		e.outerChkCh <- chk
+	}
+}
+
+// hashJoinWorker probes the hash table by the outer rows, the joined rows are returned in the chunks of
+// joinChkResourceCh.
+type hashJoinWorker struct {
+	*HashJoinExec
+	joiner            joiner
+	outerFilter       expression.CNFExprs
+	joinChkResourceCh chan *chunk.Chunk
+	selected          []bool
+	keyBuf            []byte
+	rows              []chunk.Row
+}
+
+func (e *HashJoinExec) runJoinWorker(workerID int) {
+	defer e.wg.Done()
+	defer e.recoverWorker()
+	w := &hashJoinWorker{
+		HashJoinExec:      e,
+		joiner:            e.joiners[workerID],
+		outerFilter:       e.outerFilter.Clone(),
+		joinChkResourceCh: make(chan *chunk.Chunk, 1),
+	}
+	w.joinChkResourceCh <- newFirstChunk(e)
+	result := &hashjoinWorkerResult{chk: <-w.joinChkResourceCh, src: w.joinChkResourceCh}
+	for {
+		var outerChk *chunk.Chunk
+		select {
+		case <-e.closeCh:
+			return
+		case chk, ok := <-e.outerChkCh:
+			if !ok {
+				if result.chk.NumRows() > 0 {
+					e.sendResult(result)
+				}
+				return
+			}
+			outerChk = chk
+		}
+		var ok bool
+		if result, ok = w.join2Chunk(outerChk, result); !ok {
+			return
+		}
+		e.outerChkResourceCh <- outerChk
+	}
+}
+
+// join2Chunk joins the rows of the outer chunk, the result is sent when it's full and a new one is returned.
+func (w *hashJoinWorker) join2Chunk(outerChk *chunk.Chunk, result *hashjoinWorkerResult) (_ *hashjoinWorkerResult, ok bool) {
+	var err error
+	if len(w.outerFilter) > 0 {
+		w.selected, err = expression.VectorizedFilter(w.ctx, w.outerFilter, outerChk, w.selected)
+		if err != nil {
+			w.sendResult(&hashjoinWorkerResult{err: err})
+			return nil, false
+		}
+	}
+	outerTypes := retTypes(w.outerExec)
+	for i := 0; i < outerChk.NumRows(); i++ {
+		outer := outerChk.GetRow(i)
+		if len(w.outerFilter) > 0 && !w.selected[i] {
+			w.joiner.onMissMatch(false, outer, result.chk)
+		} else if err = w.joinOuterRow(outer, outerTypes, result.chk); err != nil {
+			w.sendResult(&hashjoinWorkerResult{err: err})
+			return nil, false
+		}
+		if result.chk.IsFull() {
+			if !w.sendResult(result) {
+				return nil, false
+			}
+			select {
+			case <-w.closeCh:
+				return nil, false
+			case chk := <-w.joinChkResourceCh:
+				chk.Reset()
+				result = &hashjoinWorkerResult{chk: chk, src: w.joinChkResourceCh}
+			}
+		}
+	}
+	return result, true
+}
+
+// joinOuterRow matches the outer row with the inner rows of the same keys. The outer row of a NULL key matches
+// no rows, but the ones of a NULL aware join are matched with all the inner rows since the conditions may be
+// NULL, and the inner rows of NULL keys are matched by every outer row as well.
+func (w *hashJoinWorker) joinOuterRow(outer chunk.Row, outerTypes []*types.FieldType, chk *chunk.Chunk) error {
+	var hasNull bool
+	w.keyBuf, hasNull = codec.HashChunkRow(w.keyBuf[:0], outer, outerTypes, w.outerKeyColIdx)
+	if hasNull && !w.nullAware {
+		w.joiner.onMissMatch(false, outer, chk)
+		return nil
+	}
+	var matched, condNull bool
+	var err error
+	if hasNull {
+		err = w.rowContainer.WalkChunks(w.rows, func(inners []chunk.Row) (bool, error) {
+			ok, isNull, err := w.joiner.tryToMatchInners(outer, inners, chk)
+			matched, condNull = ok, condNull || isNull
+			return matched, err
+		})
+	} else {
+		if w.rows, err = w.rowContainer.GetMatchedRows(w.keyBuf, w.rows); err != nil {
+			return err
+		}
+		matched, condNull, err = w.joiner.tryToMatchInners(outer, w.rows, chk)
+		if err == nil && !matched && w.nullAware {
+			if w.rows, err = w.rowContainer.GetNullKeyRows(w.rows); err != nil {
+				return err
+			}
+			var isNull bool
+			matched, isNull, err = w.joiner.tryToMatchInners(outer, w.rows, chk)
+			condNull = condNull || isNull
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if !matched {
+		w.joiner.onMissMatch(condNull, outer, chk)
+	}
+	return nil
+}
+
+// Close implements the Executor Close interface, it waits for the goroutines to exit before the children
+// are closed.
+func (e *HashJoinExec) Close() error {
+	if e.prepared {
+		close(e.closeCh)
+		for range e.joinResultCh {
+		}
+		e.prepared = false
+	}
+	if e.rowContainer != nil {
+		if err := e.rowContainer.Close(); err != nil {
+			return err
+		}
+		e.rowContainer = nil
+	}
+	return e.baseExecutor.Close()
+}
+
+// NestedLoopApplyExec evaluates the inner child by every outer row, whose values are set to the correlated
+// columns of the inner child. The rows are returned in the order of the outer rows.
+type NestedLoopApplyExec struct {
+	baseExecutor
+
+	outerExec   Executor
+	innerExec   Executor
+	outerFilter expression.CNFExprs
+	joiner      joiner
+	corCols     []*expression.CorrelatedColumn
+
+	outerChk *chunk.Chunk
+	// outerIdx is the next row of outerChk to be joined.
+	outerIdx   int
+	outerDone  bool
+	selected   []bool
+	innerList  *chunk.List
+	innerChk   *chunk.Chunk
+	innerRows  []chunk.Row
+	memTracker *memory.Tracker
+}
+
+// Open implements the Executor Open interface, the inner child is opened by every outer row.
+func (e *NestedLoopApplyExec) Open(ctx context.Context) error {
+	if err := e.outerExec.Open(ctx); err != nil {
+		return err
+	}
+	e.outerChk = newFirstChunk(e.outerExec)
+	e.outerIdx, e.outerDone = 0, false
+	e.innerChk = newFirstChunk(e.innerExec)
+	e.memTracker = memory.NewTracker(e.id)
+	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	e.innerList = chunk.NewList(retTypes(e.innerExec), e.initCap, e.maxChunkSize)
+	e.innerList.GetMemTracker().AttachTo(e.memTracker)
+	return nil
+}
+
+// Next implements the Executor Next interface.
+func (e *NestedLoopApplyExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	for !req.IsFull() {
+		if e.outerIdx >= e.outerChk.NumRows() {
+			if e.outerDone {
+				return nil
+			}
+			if err := e.fetchOuterChunk(ctx); err != nil {
+				return err
+			}
+			continue
+		}
+		outer := e.outerChk.GetRow(e.outerIdx)
+		selected := len(e.outerFilter) == 0 || e.selected[e.outerIdx]
+		e.outerIdx++
+		if !selected {
+			e.joiner.onMissMatch(false, outer, req)
+			continue
+		}
+		if err := e.fetchInnerRows(ctx, outer); err != nil {
+			return err
+		}
+		matched, hasNull, err := e.joiner.tryToMatchInners(outer, e.innerRows, req)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			e.joiner.onMissMatch(hasNull, outer, req)
+		}
+	}
+	return nil
+}
+
+func (e *NestedLoopApplyExec) fetchOuterChunk(ctx context.Context) (err error) {
+	e.outerIdx = 0
+	if err = Next(ctx, e.outerExec, e.outerChk); err != nil {
+		return err
+	}
+	if e.outerChk.NumRows() == 0 {
+		e.outerDone = true
+		return nil
+	}
+	if len(e.outerFilter) > 0 {
+		e.selected, err = expression.VectorizedFilter(e.ctx, e.outerFilter, e.outerChk, e.selected)
+	}
+	return err
+}
+
+// fetchInnerRows evaluates the inner child by the outer row, the inner rows are kept in innerList.
+func (e *NestedLoopApplyExec) fetchInnerRows(ctx context.Context, outer chunk.Row) error {
+	for _, corCol := range e.corCols {
+		*corCol.Data = outer.GetDatum(corCol.Index, corCol.RetType)
+	}
+	e.innerList.Reset()
+	e.innerRows = e.innerRows[:0]
+	if err := e.innerExec.Open(ctx); err != nil {
+		return err
+	}
+	for {
+		if err := Next(ctx, e.innerExec, e.innerChk); err != nil {
+			_ = e.innerExec.Close()
+			return err
+		}
+		if e.innerChk.NumRows() == 0 {
+			break
+		}
+		e.innerList.Add(e.innerChk)
+		e.innerChk = chunk.Renew(e.innerChk, e.maxChunkSize)
+	}
+	if err := e.innerExec.Close(); err != nil {
+		return err
+	}
+	return e.innerList.Walk(func(row chunk.Row) error {
+		e.innerRows = append(e.innerRows, row)
+		return nil
+	})
+}
+
+// Close implements the Executor Close interface.
+func (e *NestedLoopApplyExec) Close() error {
+	if e.innerList != nil {
+		e.innerList.Clear()
+		e.innerList = nil
+	}
+	if e.memTracker != nil {
+		e.memTracker.Consume(-e.memTracker.BytesConsumed())
+	}
+	e.innerRows = nil
+	return e.outerExec.Close()
+}
@@ -0,0 +1,97 @@
+package executor_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestJoin(t *testing.T) {
+	tk := newExecTestKit(t)
+	tk.MustExec("create table t (a int primary key, b int, key idx_b (b))")
+	tk.MustExec("create table s (a int primary key, b int, key idx_b (b))")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, null), (4, 4)")
+	tk.MustExec("insert into s values (1, 1), (2, 2), (5, null), (6, 6)")
+	tests := []struct {
+		hint     string
+		sql      string
+		operator string
+		expected []string
+	}{
+		{"HASH_JOIN(t, s)", "select %s t.a, s.a from t join s on t.b = s.b", "HashJoin", []string{"1 1", "2 2"}},
+		{"HASH_JOIN(t, s)", "select %s t.a, s.a from t left join s on t.a = s.a", "HashJoin", []string{"1 1", "2 2", "3 <nil>", "4 <nil>"}},
+		{"HASH_JOIN(t, s)", "select %s t.a, s.a from t right join s on t.a = s.a", "HashJoin", []string{"1 1", "2 2", "<nil> 5", "<nil> 6"}},
+		{"MERGE_JOIN(t, s)", "select %s t.a, s.a from t join s on t.b = s.b", "MergeJoin", []string{"1 1", "2 2"}},
+		{"MERGE_JOIN(t, s)", "select %s t.a, s.a from t left join s on t.a = s.a", "MergeJoin", []string{"1 1", "2 2", "3 <nil>", "4 <nil>"}},
+		{"MERGE_JOIN(t, s)", "select %s t.a, s.a from t right join s on t.a = s.a", "MergeJoin", []string{"1 1", "2 2", "<nil> 5", "<nil> 6"}},
+		{"MERGE_JOIN(t, s)", "select %s t.a from t where exists (select 1 from s where s.a = t.a)", "MergeJoin", []string{"1", "2"}},
+		{"MERGE_JOIN(t, s)", "select %s t.a from t where not exists (select 1 from s where s.a = t.a)", "MergeJoin", []string{"3", "4"}},
+		{"INL_JOIN(s)", "select %s t.a, s.a from t join s on t.b = s.b", "IndexJoin", []string{"1 1", "2 2"}},
+		{"INL_JOIN(s)", "select %s t.a, s.a from t left join s on t.a = s.a", "IndexJoin", []string{"1 1", "2 2", "3 <nil>", "4 <nil>"}},
+		{"INL_JOIN(t)", "select %s t.a, s.a from t right join s on t.a = s.a", "IndexJoin", []string{"1 1", "2 2", "<nil> 5", "<nil> 6"}},
+	}
+	for _, tt := range tests {
+		sql := fmt.Sprintf(tt.sql, "/*+ "+tt.hint+" */")
+		if op := strings.Fields(tk.MustQuery("explain " + sql).Rows()[1])[0]; !strings.Contains(op, tt.operator) {
+			t.Fatalf("%s is executed by %s, expected %s", sql, op, tt.operator)
+		}
+		tk.MustQuery(sql).Sort().Check(tt.expected...)
+	}
+}
+
+func TestNullAwareAntiJoin(t *testing.T) {
+	tk := newExecTestKit(t)
+	tk.MustExec("create table t (a int primary key, b int)")
+	tk.MustExec("create table s (a int primary key, b int)")
+	tk.MustExec("insert into t values (1, 1), (2, 2), (3, null), (4, 4)")
+	tk.MustExec("insert into s values (1, 1), (2, 2), (5, null), (6, 6)")
+	sql := "select a from t where b not in (select b from s)"
+	if row := tk.MustQuery("explain " + sql).Rows()[1]; !strings.Contains(row, "null-aware anti semi join") {
+		t.Fatalf("%s is executed by %s", sql, row)
+	}
+	// x NOT IN (...) is NULL rather than true if x or any of the values is NULL.
+	tk.MustQuery(sql).Check()
+	tk.MustQuery("select a from t where b not in (select b from s where b is not null)").Check("4")
+	tk.MustQuery("select a from t where b not in (select b from s where a > 10)").Sort().Check("1", "2", "3", "4")
+	tk.MustQuery("select a, b not in (select b from s) from t order by a").Check("1 0", "2 0", "3 <nil>", "4 <nil>")
+	tk.MustQuery("select a, b not in (select b from s where b is not null) from t order by a").Check("1 0", "2 0", "3 <nil>", "4 1")
+	tk.MustQuery("select a, b in (select b from s) from t order by a").Check("1 1", "2 1", "3 <nil>", "4 <nil>")
+}
+
+func TestHashJoinSpill(t *testing.T) {
+	tk := newExecTestKit(t)
+	tk.MustExec("create table t (a int primary key, b int, c varchar(200))")
+	tk.MustExec("create table s (a int primary key, b int, c varchar(200))")
+	x, y := strings.Repeat("x", 200), strings.Repeat("y", 200)
+	insertRows(tk, "t", 0, 2000, func(i int) string { return fmt.Sprintf("%d, '%s'", i%100, x) })
+	insertRows(tk, "s", 0, 2000, func(i int) string { return fmt.Sprintf("%d, '%s'", i, y) })
+	tk.MustExec("insert into s values (2000, null, '')")
+	// The tables are analyzed, so the plans don't change if they're analyzed automatically.
+	tk.MustExec("analyze table t, s")
+	tests := []struct {
+		sql      string
+		expected string
+	}{
+		{"select /*+ HASH_JOIN(t, s) */ count(*), sum(s.a), max(t.c) = '" + x + "', max(s.c) = '" + y + "' from t join s on t.b = s.b", "2000 99000 1 1"},
+		{"select /*+ HASH_JOIN(t, s) */ count(*), sum(t.b), max(t.c) = '" + x + "' from t left join s on t.a = s.a + 1", "2000 99000 1"},
+		{"select count(*) from t where b not in (select b from s)", "0"},
+		{"select count(*) from t where b not in (select b from s where b is not null and b > 50)", "1020"},
+	}
+	// The rows of the build side are spilled to the disk if they exceed the memory quota, the results are the
+	// same.
+	for _, quota := range []int{300000, 1 << 30} {
+		tk.MustExec(fmt.Sprintf("set tidb_mem_quota_query = %d", quota))
+		for _, tt := range tests {
+			tk.MustQuery(tt.sql).Check(tt.expected)
+		}
+		var joinRow string
+		for _, row := range tk.MustQuery("explain analyze " + tests[0].sql).Rows() {
+			if strings.Contains(row, "HashJoin") {
+				joinRow = row
+			}
+		}
+		if spilled := !strings.HasSuffix(joinRow, " 0 Bytes"); spilled != (quota < 1<<30) {
+			t.Fatalf("the hash join under the memory quota %d: %s", quota, joinRow)
+		}
+	}
+}
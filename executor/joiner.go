@@ -0,0 +1,305 @@
+package executor
+
+import (
+	"grant-db/expression"
+	"grant-db/planner"
+	"grant-db/sessionctx"
+	"grant-db/types"
+	"grant-db/util/chunk"
+)
+
+// joiner joins an outer row with the inner rows by the type of the join, the rows of the semi joins are the
+// outer rows, the outer semi joins append the results of the matching, and the other joins return the joined
+// rows. The rows of an outer row are appended together, so the chunk may hold more rows than its capacity.
+type joiner interface {
+	// tryToMatchInners appends the rows of the outer row and the inner rows which satisfy the conditions to
+	// chk. matched is true if any inner row does, and hasNull is true if the conditions are NULL on any inner
+	// row, which is only reported by the NULL aware joins. The semi joins stop at the first matched inner row.
+	tryToMatchInners(outer chunk.Row, inners []chunk.Row, chk *chunk.Chunk) (matched bool, hasNull bool, err error)
+	// onMissMatch appends the row of the outer row which matches none of the inner rows to chk, hasNull is
+	// true if the conditions are NULL on any of them.
+	onMissMatch(hasNull bool, outer chunk.Row, chk *chunk.Chunk)
+	// clone returns a copy of the joiner which can be used by another goroutine.
+	clone() joiner
+}
+
+// newJoiner creates the joiner of the join type. The conditions are evaluated on the joined rows, whose columns
+// are the ones of the left child and the right child. defaultInner is the inner row of the unmatched outer rows
+// of an outer join, which is NULLs if it's nil.
+func newJoiner(ctx sessionctx.Context, joinType planner.JoinType, outerIsRight bool, defaultInner []types.Datum,
+	conditions expression.CNFExprs, nullAware bool, lhsTypes, rhsTypes []*types.FieldType) joiner {
+	base := baseJoiner{
+		ctx:          ctx,
+		conditions:   conditions,
+		nullAware:    nullAware,
+		outerIsRight: outerIsRight,
+		batchSize:    ctx.GetSessionVars().MaxChunkSize,
+	}
+	if len(conditions) > 0 {
+		base.joinedChk = chunk.NewChunkWithCapacity(append(append(lhsTypes[:0:0], lhsTypes...), rhsTypes...), base.batchSize)
+	}
+	switch joinType {
+	case planner.SemiJoin:
+		return &semiJoiner{base}
+	case planner.AntiSemiJoin:
+		return &antiSemiJoiner{base}
+	case planner.LeftOuterSemiJoin:
+		return &leftOuterSemiJoiner{base}
+	case planner.AntiLeftOuterSemiJoin:
+		return &antiLeftOuterSemiJoiner{base}
+	case planner.LeftOuterJoin, planner.RightOuterJoin:
+		innerTypes := rhsTypes
+		if outerIsRight {
+			innerTypes = lhsTypes
+		}
+		base.initDefaultInner(innerTypes, defaultInner)
+		return &outerJoiner{base}
+	}
+	return &innerJoiner{base}
+}
+
+type baseJoiner struct {
+	ctx          sessionctx.Context
+	conditions   expression.CNFExprs
+	nullAware    bool
+	outerIsRight bool
+	defaultInner chunk.Row
+	// joinedChk holds the joined rows of a batch of the inner rows, on which the conditions are evaluated.
+	joinedChk *chunk.Chunk
+	batchSize int
+	selected  []bool
+}
+
+func (j *baseJoiner) initDefaultInner(innerTypes []*types.FieldType, defaultInner []types.Datum) {
+	chk := chunk.NewChunkWithCapacity(innerTypes, 1)
+	for i := range innerTypes {
+		if defaultInner == nil {
+			chk.AppendNull(i)
+		} else {
+			chk.AppendDatum(i, &defaultInner[i])
+		}
+	}
+	j.defaultInner = chk.GetRow(0)
+}
+
+// appendJoinedRow appends the joined row of the outer row and the inner row to chk.
+func (j *baseJoiner) appendJoinedRow(chk *chunk.Chunk, outer, inner chunk.Row) {
+	lhs, rhs := outer, inner
+	if j.outerIsRight {
+		lhs, rhs = inner, outer
+	}
+	chk.AppendRow(lhs)
+	chk.AppendPartialRow(lhs.Len(), rhs)
+}
+
+// filter evaluates the conditions on the joined rows of the outer row and the inners, which are in
+// joinedChk then. The conditions are evaluated in the three-valued logic for the NULL aware joins.
+func (j *baseJoiner) filter(outer chunk.Row, inners []chunk.Row) (selected []bool, hasNull bool, err error) {
+	j.joinedChk.Reset()
+	for _, inner := range inners {
+		j.appendJoinedRow(j.joinedChk, outer, inner)
+	}
+	if !j.nullAware {
+		j.selected, err = expression.VectorizedFilter(j.ctx, j.conditions, j.joinedChk, j.selected)
+		return j.selected, false, err
+	}
+	j.selected = j.selected[:0]
+	for i := 0; i < j.joinedChk.NumRows(); i++ {
+		matched, isNull, err := expression.EvalBool(j.ctx, j.conditions, j.joinedChk.GetRow(i))
+		if err != nil {
+			return nil, false, err
+		}
+		j.selected = append(j.selected, matched)
+		hasNull = hasNull || isNull
+	}
+	return j.selected, hasNull, nil
+}
+
+// matchFirst returns whether any inner row satisfies the conditions, the inner rows are checked batch by
+// batch until the first one is found.
+func (j *baseJoiner) matchFirst(outer chunk.Row, inners []chunk.Row) (matched bool, hasNull bool, err error) {
+	if len(j.conditions) == 0 {
+		return len(inners) > 0, false, nil
+	}
+	for begin := 0; begin < len(inners); begin += j.batchSize {
+		end := mathMin(begin+j.batchSize, len(inners))
+		selected, isNull, err := j.filter(outer, inners[begin:end])
+		if err != nil {
+			return false, false, err
+		}
+		hasNull = hasNull || isNull
+		for _, ok := range selected {
+			if ok {
+				return true, hasNull, nil
+			}
+		}
+	}
+	return false, hasNull, nil
+}
+
+// appendMatched appends the joined rows of the inner rows which satisfy the conditions to chk.
+func (j *baseJoiner) appendMatched(outer chunk.Row, inners []chunk.Row, chk *chunk.Chunk) (matched bool, err error) {
+	if len(j.conditions) == 0 {
+		for _, inner := range inners {
+			j.appendJoinedRow(chk, outer, inner)
+		}
+		return len(inners) > 0, nil
+	}
+	for begin := 0; begin < len(inners); begin += j.batchSize {
+		end := mathMin(begin+j.batchSize, len(inners))
+		selected, _, err := j.filter(outer, inners[begin:end])
+		if err != nil {
+			return false, err
+		}
+		for i, ok := range selected {
+			if ok {
+				chk.AppendRow(j.joinedChk.GetRow(i))
+				matched = true
+			}
+		}
+	}
+	return matched, nil
+}
+
+func (j baseJoiner) cloneBase() baseJoiner {
+	j.conditions = j.conditions.Clone()
+	if j.joinedChk != nil {
+		j.joinedChk = j.joinedChk.CopyConstruct()
+	}
+	j.selected = nil
+	return j
+}
+
+type innerJoiner struct {
+	baseJoiner
+}
+
+func (j *innerJoiner) tryToMatchInners(outer chunk.Row, inners []chunk.Row, chk *chunk.Chunk) (bool, bool, error) {
+	matched, err := j.appendMatched(outer, inners, chk)
+	return matched, false, err
+}
+
+func (j *innerJoiner) onMissMatch(bool, chunk.Row, *chunk.Chunk) {}
+
+func (j *innerJoiner) clone() joiner {
+	return &innerJoiner{j.cloneBase()}
+}
+
+// outerJoiner is the joiner of the left and the right outer joins, the unmatched outer rows are joined with
+// the default inner row.
+type outerJoiner struct {
+	baseJoiner
+}
+
+func (j *outerJoiner) tryToMatchInners(outer chunk.Row, inners []chunk.Row, chk *chunk.Chunk) (bool, bool, error) {
+	matched, err := j.appendMatched(outer, inners, chk)
+	return matched, false, err
+}
+
+func (j *outerJoiner) onMissMatch(_ bool, outer chunk.Row, chk *chunk.Chunk) {
+	j.appendJoinedRow(chk, outer, j.defaultInner)
+}
+
+func (j *outerJoiner) clone() joiner {
+	return &outerJoiner{j.cloneBase()}
+}
+
+type semiJoiner struct {
+	baseJoiner
+}
+
+func (j *semiJoiner) tryToMatchInners(outer chunk.Row, inners []chunk.Row, chk *chunk.Chunk) (bool, bool, error) {
+	matched, hasNull, err := j.matchFirst(outer, inners)
+	if matched {
+		chk.AppendRow(outer)
+	}
+	return matched, hasNull, err
+}
+
+func (j *semiJoiner) onMissMatch(bool, chunk.Row, *chunk.Chunk) {}
+
+func (j *semiJoiner) clone() joiner {
+	return &semiJoiner{j.cloneBase()}
+}
+
+// antiSemiJoiner returns the outer rows which match no inner rows, the ones of NULL results of a NULL aware
+// join, like `1 NOT IN (NULL)`, are not returned either.
+type antiSemiJoiner struct {
+	baseJoiner
+}
+
+func (j *antiSemiJoiner) tryToMatchInners(outer chunk.Row, inners []chunk.Row, _ *chunk.Chunk) (bool, bool, error) {
+	return j.matchFirst(outer, inners)
+}
+
+func (j *antiSemiJoiner) onMissMatch(hasNull bool, outer chunk.Row, chk *chunk.Chunk) {
+	if !hasNull {
+		chk.AppendRow(outer)
+	}
+}
+
+func (j *antiSemiJoiner) clone() joiner {
+	return &antiSemiJoiner{j.cloneBase()}
+}
+
+// leftOuterSemiJoiner appends whether the outer row matches any inner row to the outer row, it's NULL if the
+// row of a NULL aware join matches none but the conditions are NULL on some.
+type leftOuterSemiJoiner struct {
+	baseJoiner
+}
+
+func (j *leftOuterSemiJoiner) tryToMatchInners(outer chunk.Row, inners []chunk.Row, chk *chunk.Chunk) (bool, bool, error) {
+	matched, hasNull, err := j.matchFirst(outer, inners)
+	if matched {
+		chk.AppendRow(outer)
+		chk.AppendInt64(outer.Len(), 1)
+	}
+	return matched, hasNull, err
+}
+
+func (j *leftOuterSemiJoiner) onMissMatch(hasNull bool, outer chunk.Row, chk *chunk.Chunk) {
+	chk.AppendRow(outer)
+	if hasNull {
+		chk.AppendNull(outer.Len())
+	} else {
+		chk.AppendInt64(outer.Len(), 0)
+	}
+}
+
+func (j *leftOuterSemiJoiner) clone() joiner {
+	return &leftOuterSemiJoiner{j.cloneBase()}
+}
+
+// antiLeftOuterSemiJoiner appends whether the outer row matches no inner rows to the outer row.
+type antiLeftOuterSemiJoiner struct {
+	baseJoiner
+}
+
+func (j *antiLeftOuterSemiJoiner) tryToMatchInners(outer chunk.Row, inners []chunk.Row, chk *chunk.Chunk) (bool, bool, error) {
+	matched, hasNull, err := j.matchFirst(outer, inners)
+	if matched {
+		chk.AppendRow(outer)
+		chk.AppendInt64(outer.Len(), 0)
+	}
+	return matched, hasNull, err
+}
+
+func (j *antiLeftOuterSemiJoiner) onMissMatch(hasNull bool, outer chunk.Row, chk *chunk.Chunk) {
+	chk.AppendRow(outer)
+	if hasNull {
+		chk.AppendNull(outer.Len())
+	} else {
+		chk.AppendInt64(outer.Len(), 1)
+	}
+}
+
+func (j *antiLeftOuterSemiJoiner) clone() joiner {
+	return &antiLeftOuterSemiJoiner{j.cloneBase()}
+}
+
+func mathMin(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,253 @@
+package executor
+
+import (
+	"context"
+
+	"github.com/pingcap/parser/mysql"
+	"grant-db/expression"
+	"grant-db/types"
+	"grant-db/util/chunk"
+	"grant-db/util/memory"
+)
+
+// MergeJoinExec merges the rows of the children which are ordered by the join keys. The inner rows of the
+// same keys are read into a group, which is joined with the outer rows of the keys. The rows are returned in
+// the order of the outer rows.
+type MergeJoinExec struct {
+	baseExecutor
+
+	outerExec   Executor
+	innerExec   Executor
+	outerFilter expression.CNFExprs
+	joiner      joiner
+	desc        bool
+	// outerKeyColIdx and innerKeyColIdx are the offsets of the join keys in the rows of the children.
+	outerKeyColIdx []int
+	innerKeyColIdx []int
+	// compareFuncs compare the outer keys with the inner keys, and innerCmpFuncs compare the inner keys.
+	compareFuncs  []chunk.CompareFunc
+	innerCmpFuncs []chunk.CompareFunc
+
+	outerChk  *chunk.Chunk
+	outerIdx  int
+	outerDone bool
+	selected  []bool
+
+	innerChk  *chunk.Chunk
+	innerIdx  int
+	innerDone bool
+	// innerGroup holds the inner rows of the current keys, the rows are copied since innerChk is reused.
+	innerGroup *chunk.List
+	groupRows  []chunk.Row
+	memTracker *memory.Tracker
+}
+
+// Open implements the Executor Open interface.
+func (e *MergeJoinExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.outerChk = newFirstChunk(e.outerExec)
+	e.outerIdx, e.outerDone = 0, false
+	e.innerChk = newFirstChunk(e.innerExec)
+	e.innerIdx, e.innerDone = 0, false
+	e.memTracker = memory.NewTracker(e.id)
+	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	e.innerGroup = chunk.NewList(retTypes(e.innerExec), e.initCap, e.maxChunkSize)
+	e.innerGroup.GetMemTracker().AttachTo(e.memTracker)
+	e.groupRows = e.groupRows[:0]
+	e.initCompareFuncs()
+	return nil
+}
+
+func (e *MergeJoinExec) initCompareFuncs() {
+	outerTypes, innerTypes := retTypes(e.outerExec), retTypes(e.innerExec)
+	e.compareFuncs = make([]chunk.CompareFunc, 0, len(e.outerKeyColIdx))
+	e.innerCmpFuncs = make([]chunk.CompareFunc, 0, len(e.innerKeyColIdx))
+	for i := range e.outerKeyColIdx {
+		outerTp, innerTp := outerTypes[e.outerKeyColIdx[i]], innerTypes[e.innerKeyColIdx[i]]
+		e.compareFuncs = append(e.compareFuncs, getJoinKeyCompareFunc(outerTp, innerTp))
+		e.innerCmpFuncs = append(e.innerCmpFuncs, chunk.GetCompareFunc(innerTp))
+	}
+}
+
+// getJoinKeyCompareFunc returns the function which compares the keys of the types, which are of the same
+// evaluation type. A float is compared with a double as a double.
+func getJoinKeyCompareFunc(lTp, rTp *types.FieldType) chunk.CompareFunc {
+	if lTp.EvalType() != types.ETReal || lTp.Tp == rTp.Tp {
+		return chunk.GetCompareFunc(lTp)
+	}
+	lFloat, rFloat := lTp.Tp == mysql.TypeFloat, rTp.Tp == mysql.TypeFloat
+	getFloat64 := func(row chunk.Row, colIdx int, isFloat bool) float64 {
+		if isFloat {
+			return float64(row.GetFloat32(colIdx))
+		}
+		return row.GetFloat64(colIdx)
+	}
+	return func(l chunk.Row, lCol int, r chunk.Row, rCol int) int {
+		lVal, rVal := getFloat64(l, lCol, lFloat), getFloat64(r, rCol, rFloat)
+		switch {
+		case lVal < rVal:
+			return -1
+		case lVal > rVal:
+			return 1
+		}
+		return 0
+	}
+}
+
+// compare compares the keys of the outer row with the ones of the current inner group in the order of the rows.
+func (e *MergeJoinExec) compare(outer chunk.Row) int {
+	inner := e.groupRows[0]
+	for i, cmpFunc := range e.compareFuncs {
+		cmp := cmpFunc(outer, e.outerKeyColIdx[i], inner, e.innerKeyColIdx[i])
+		if cmp != 0 {
+			if e.desc {
+				return -cmp
+			}
+			return cmp
+		}
+	}
+	return 0
+}
+
+// hasNullKey checks whether any of the keys of the row is NULL, which matches no rows.
+func hasNullKey(row chunk.Row, keyColIdx []int) bool {
+	for _, idx := range keyColIdx {
+		if row.IsNull(idx) {
+			return true
+		}
+	}
+	return false
+}
+
+// Next implements the Executor Next interface.
+func (e *MergeJoinExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	for !req.IsFull() {
+		if e.outerIdx >= e.outerChk.NumRows() {
+			if e.outerDone {
+				return nil
+			}
+			if err := e.fetchOuterChunk(ctx); err != nil {
+				return err
+			}
+			continue
+		}
+		outer := e.outerChk.GetRow(e.outerIdx)
+		selected := len(e.outerFilter) == 0 || e.selected[e.outerIdx]
+		e.outerIdx++
+		if !selected || hasNullKey(outer, e.outerKeyColIdx) {
+			e.joiner.onMissMatch(false, outer, req)
+			continue
+		}
+		cmp, err := e.seekInnerGroup(ctx, outer)
+		if err != nil {
+			return err
+		}
+		if cmp != 0 {
+			e.joiner.onMissMatch(false, outer, req)
+			continue
+		}
+		matched, _, err := e.joiner.tryToMatchInners(outer, e.groupRows, req)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			e.joiner.onMissMatch(false, outer, req)
+		}
+	}
+	return nil
+}
+
+func (e *MergeJoinExec) fetchOuterChunk(ctx context.Context) (err error) {
+	e.outerIdx = 0
+	if err = Next(ctx, e.outerExec, e.outerChk); err != nil {
+		return err
+	}
+	if e.outerChk.NumRows() == 0 {
+		e.outerDone = true
+		return nil
+	}
+	if len(e.outerFilter) > 0 {
+		e.selected, err = expression.VectorizedFilter(e.ctx, e.outerFilter, e.outerChk, e.selected)
+	}
+	return err
+}
+
+// seekInnerGroup moves the inner group forward until its keys are not less than the ones of the outer row, the
+// result of the comparison is returned, which is negative if there are no more inner rows.
+func (e *MergeJoinExec) seekInnerGroup(ctx context.Context, outer chunk.Row) (int, error) {
+	for {
+		if len(e.groupRows) == 0 {
+			if err := e.fetchNextInnerGroup(ctx); err != nil {
+				return 0, err
+			}
+			if len(e.groupRows) == 0 {
+				return -1, nil
+			}
+		}
+		if cmp := e.compare(outer); cmp <= 0 {
+			return cmp, nil
+		}
+		e.groupRows = e.groupRows[:0]
+	}
+}
+
+// fetchNextInnerGroup reads the next inner rows of the same keys into the group, the rows of NULL keys are
+// skipped.
+func (e *MergeJoinExec) fetchNextInnerGroup(ctx context.Context) error {
+	e.innerGroup.Reset()
+	e.groupRows = e.groupRows[:0]
+	var first chunk.Row
+	for {
+		if e.innerIdx >= e.innerChk.NumRows() {
+			if e.innerDone {
+				return nil
+			}
+			e.innerIdx = 0
+			if err := Next(ctx, e.innerExec, e.innerChk); err != nil {
+				return err
+			}
+			if e.innerChk.NumRows() == 0 {
+				e.innerDone = true
+				return nil
+			}
+		}
+		inner := e.innerChk.GetRow(e.innerIdx)
+		if hasNullKey(inner, e.innerKeyColIdx) {
+			e.innerIdx++
+			continue
+		}
+		if len(e.groupRows) > 0 && !e.sameInnerKeys(first, inner) {
+			return nil
+		}
+		e.innerIdx++
+		e.groupRows = append(e.groupRows, e.innerGroup.GetRow(e.innerGroup.AppendRow(inner)))
+		if len(e.groupRows) == 1 {
+			first = e.groupRows[0]
+		}
+	}
+}
+
+func (e *MergeJoinExec) sameInnerKeys(lhs, rhs chunk.Row) bool {
+	for i, cmpFunc := range e.innerCmpFuncs {
+		if cmpFunc(lhs, e.innerKeyColIdx[i], rhs, e.innerKeyColIdx[i]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Close implements the Executor Close interface.
+func (e *MergeJoinExec) Close() error {
+	if e.innerGroup != nil {
+		e.innerGroup.Clear()
+		e.innerGroup = nil
+	}
+	if e.memTracker != nil {
+		e.memTracker.Consume(-e.memTracker.BytesConsumed())
+	}
+	e.groupRows = nil
+	return e.baseExecutor.Close()
+}
@@ -0,0 +1,140 @@
+package executor
+
+import (
+	"context"
+
+	"grant-db/kv"
+	"grant-db/table"
+	"grant-db/types"
+	"grant-db/util/chunk"
+	"grant-db/util/codec"
+)
+
+// pointGetter reads the rows by the handles or the values of the unique index.
+type pointGetter struct {
+	table   table.Table
+	index   table.Index
+	decoder *rowDecoder
+	txn     kv.Transaction
+}
+
+// getHandle returns the handle of the index values, ok is false if there is no such row. A NULL value
+// matches no rows.
+func (g *pointGetter) getHandle(ctx context.Context, vals []types.Datum) (handle int64, ok bool, err error) {
+	key, distinct, err := g.index.GenIndexKey(vals, 0, nil)
+	if err != nil || !distinct {
+		return 0, false, err
+	}
+	value, err := g.txn.Get(ctx, key)
+	if kv.IsErrNotFound(err) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	_, handle, err = codec.DecodeInt(value)
+	return handle, err == nil, err
+}
+
+// getRow appends the row of the handle to the chunk if it exists.
+func (g *pointGetter) getRow(ctx context.Context, handle int64, chk *chunk.Chunk) error {
+	value, err := g.txn.Get(ctx, g.table.RecordKey(handle))
+	if kv.IsErrNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return g.decoder.decodeToChunk(handle, value, chk)
+}
+
+// PointGetExecutor reads one row by the handle or the values of the unique index.
+type PointGetExecutor struct {
+	baseExecutor
+	pointGetter
+
+	handle  int64
+	idxVals []types.Datum
+	done    bool
+}
+
+// Open implements the Executor Open interface.
+func (e *PointGetExecutor) Open(ctx context.Context) (err error) {
+	e.txn, err = e.ctx.Txn(true)
+	e.done = false
+	return err
+}
+
+// Next implements the Executor Next interface.
+func (e *PointGetExecutor) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.done {
+		return nil
+	}
+	e.done = true
+	handle := e.handle
+	if e.index != nil {
+		var ok bool
+		var err error
+		if handle, ok, err = e.getHandle(ctx, e.idxVals); err != nil || !ok {
+			return err
+		}
+	}
+	return e.getRow(ctx, handle, req)
+}
+
+// Close implements the Executor Close interface.
+func (e *PointGetExecutor) Close() error {
+	e.txn = nil
+	return nil
+}
+
+// BatchPointGetExec reads the rows by the handles or the values of the unique index in order.
+type BatchPointGetExec struct {
+	baseExecutor
+	pointGetter
+
+	handles []int64
+	idxVals [][]types.Datum
+	// idx is the next handle or index values to read.
+	idx int
+}
+
+// Open implements the Executor Open interface.
+func (e *BatchPointGetExec) Open(ctx context.Context) (err error) {
+	e.txn, err = e.ctx.Txn(true)
+	e.idx = 0
+	return err
+}
+
+// Next implements the Executor Next interface.
+func (e *BatchPointGetExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.index == nil {
+		for ; e.idx < len(e.handles) && !req.IsFull(); e.idx++ {
+			if err := e.getRow(ctx, e.handles[e.idx], req); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for ; e.idx < len(e.idxVals) && !req.IsFull(); e.idx++ {
+		handle, ok, err := e.getHandle(ctx, e.idxVals[e.idx])
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		if err = e.getRow(ctx, handle, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close implements the Executor Close interface.
+func (e *BatchPointGetExec) Close() error {
+	e.txn = nil
+	return nil
+}
@@ -0,0 +1,263 @@
+package executor
+
+import (
+	"container/heap"
+	"context"
+	"sort"
+
+	"grant-db/expression"
+	"grant-db/planner"
+	"grant-db/util/chunk"
+	"grant-db/util/memory"
+)
+
+// SortExec sorts the rows of the child in the memory. The items are the columns of the child, the
+// expressions are evaluated by the projection below it.
+type SortExec struct {
+	baseExecutor
+
+	ByItems []*planner.ByItems
+	// keyColumns are the offsets of the columns of the items in the rows of the child.
+	keyColumns  []int
+	keyCmpFuncs []chunk.CompareFunc
+
+	fetched bool
+	rowList *chunk.List
+	rowPtrs []chunk.RowPtr
+	// idx is the next row of rowPtrs to be returned.
+	idx int
+
+	memTracker *memory.Tracker
+}
+
+// Open implements the Executor Open interface.
+func (e *SortExec) Open(ctx context.Context) error {
+	e.fetched = false
+	e.idx = 0
+	e.memTracker = memory.NewTracker(e.id)
+	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	e.initKeyColumns()
+	return e.baseExecutor.Open(ctx)
+}
+
+func (e *SortExec) initKeyColumns() {
+	e.keyColumns = make([]int, 0, len(e.ByItems))
+	e.keyCmpFuncs = make([]chunk.CompareFunc, 0, len(e.ByItems))
+	for _, item := range e.ByItems {
+		col := item.Expr.(*expression.Column)
+		e.keyColumns = append(e.keyColumns, col.Index)
+		e.keyCmpFuncs = append(e.keyCmpFuncs, chunk.GetCompareFunc(col.RetType))
+	}
+}
+
+// Next implements the Executor Next interface.
+func (e *SortExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if !e.fetched {
+		if err := e.fetchRowChunks(ctx); err != nil {
+			return err
+		}
+		e.initPointers()
+		sort.Slice(e.rowPtrs, e.keyColumnsLess)
+		e.fetched = true
+	}
+	for !req.IsFull() && e.idx < len(e.rowPtrs) {
+		req.AppendRow(e.rowList.GetRow(e.rowPtrs[e.idx]))
+		e.idx++
+	}
+	return nil
+}
+
+// fetchRowChunks reads all the rows of the child into the row list.
+func (e *SortExec) fetchRowChunks(ctx context.Context) error {
+	e.rowList = chunk.NewList(retTypes(e), e.initCap, e.maxChunkSize)
+	e.rowList.GetMemTracker().AttachTo(e.memTracker)
+	chk := newFirstChunk(e.children[0])
+	for {
+		if err := Next(ctx, e.children[0], chk); err != nil {
+			return err
+		}
+		if chk.NumRows() == 0 {
+			return nil
+		}
+		e.rowList.Add(chk)
+		chk = chunk.Renew(chk, e.maxChunkSize)
+	}
+}
+
+func (e *SortExec) initPointers() {
+	e.rowPtrs = make([]chunk.RowPtr, 0, e.rowList.Len())
+	e.memTracker.Consume(int64(8 * e.rowList.Len()))
+	for chkIdx := 0; chkIdx < e.rowList.NumChunks(); chkIdx++ {
+		rowChk := e.rowList.GetChunk(chkIdx)
+		for rowIdx := 0; rowIdx < rowChk.NumRows(); rowIdx++ {
+			e.rowPtrs = append(e.rowPtrs, chunk.RowPtr{ChkIdx: uint32(chkIdx), RowIdx: uint32(rowIdx)})
+		}
+	}
+}
+
+func (e *SortExec) lessRow(rowI, rowJ chunk.Row) bool {
+	for i, colIdx := range e.keyColumns {
+		cmp := e.keyCmpFuncs[i](rowI, colIdx, rowJ, colIdx)
+		if e.ByItems[i].Desc {
+			cmp = -cmp
+		}
+		if cmp != 0 {
+			return cmp < 0
+		}
+	}
+	return false
+}
+
+// keyColumnsLess is the less function of sort.Slice on rowPtrs.
+func (e *SortExec) keyColumnsLess(i, j int) bool {
+	return e.lessRow(e.rowList.GetRow(e.rowPtrs[i]), e.rowList.GetRow(e.rowPtrs[j]))
+}
+
+// Close implements the Executor Close interface.
+func (e *SortExec) Close() error {
+	if e.rowList != nil {
+		e.rowList.Clear()
+		e.rowList = nil
+	}
+	if e.memTracker != nil {
+		// The pointers are released as well.
+		e.memTracker.Consume(-e.memTracker.BytesConsumed())
+	}
+	e.rowPtrs = nil
+	return e.baseExecutor.Close()
+}
+
+// TopNExec returns the first count rows after the offset in the order of the items. It keeps the
+// offset+count smallest rows in a heap.
+type TopNExec struct {
+	SortExec
+
+	limitBegin uint64
+	limitEnd   uint64
+	// chkHeap is the max heap of the kept rows, the top is the largest one.
+	chkHeap *topNChunkHeap
+}
+
+// topNChunkHeap implements heap.Interface.
+type topNChunkHeap struct {
+	*TopNExec
+}
+
+// Less implements heap.Interface Less interface, the order is reversed so the top is the largest row.
+func (h *topNChunkHeap) Less(i, j int) bool {
+	return h.keyColumnsLess(j, i)
+}
+
+func (h *topNChunkHeap) Len() int {
+	return len(h.rowPtrs)
+}
+
+func (h *topNChunkHeap) Push(x interface{}) {
+	// Rows are never pushed, the top is replaced instead.
+}
+
+func (h *topNChunkHeap) Pop() interface{} {
+	h.rowPtrs = h.rowPtrs[:len(h.rowPtrs)-1]
+	// The returned value is not used.
+	return nil
+}
+
+func (h *topNChunkHeap) Swap(i, j int) {
+	h.rowPtrs[i], h.rowPtrs[j] = h.rowPtrs[j], h.rowPtrs[i]
+}
+
+// Next implements the Executor Next interface.
+func (e *TopNExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if !e.fetched {
+		e.idx = int(e.limitBegin)
+		if err := e.loadChunksUntilTotalLimit(ctx); err != nil {
+			return err
+		}
+		if err := e.executeTopN(ctx); err != nil {
+			return err
+		}
+		e.fetched = true
+	}
+	for !req.IsFull() && e.idx < len(e.rowPtrs) {
+		req.AppendRow(e.rowList.GetRow(e.rowPtrs[e.idx]))
+		e.idx++
+	}
+	return nil
+}
+
+// loadChunksUntilTotalLimit reads the rows of the child until there are limitEnd rows, which are heapified.
+func (e *TopNExec) loadChunksUntilTotalLimit(ctx context.Context) error {
+	e.chkHeap = &topNChunkHeap{e}
+	e.rowList = chunk.NewList(retTypes(e), e.initCap, e.maxChunkSize)
+	e.rowList.GetMemTracker().AttachTo(e.memTracker)
+	for uint64(e.rowList.Len()) < e.limitEnd {
+		srcChk := newFirstChunk(e.children[0])
+		// The chunks are full sized, the rows beyond the limit are handled by executeTopN.
+		if err := Next(ctx, e.children[0], srcChk); err != nil {
+			return err
+		}
+		if srcChk.NumRows() == 0 {
+			break
+		}
+		e.rowList.Add(srcChk)
+	}
+	e.initPointers()
+	return nil
+}
+
+const topNCompactionFactor = 4
+
+// executeTopN reads the rest rows of the child, a row replaces the top of the heap if it's smaller. The rows
+// list is compacted if there are too many rows which are out of the heap.
+func (e *TopNExec) executeTopN(ctx context.Context) error {
+	// The rows loaded beyond the limit are in the heap at first, they're popped out after heapified.
+	heap.Init(e.chkHeap)
+	for uint64(len(e.rowPtrs)) > e.limitEnd {
+		heap.Pop(e.chkHeap)
+	}
+	childRowChk := newFirstChunk(e.children[0])
+	for {
+		if err := Next(ctx, e.children[0], childRowChk); err != nil {
+			return err
+		}
+		if childRowChk.NumRows() == 0 {
+			break
+		}
+		e.processChildChk(childRowChk)
+		if e.rowList.Len() > len(e.rowPtrs)*topNCompactionFactor && e.rowList.Len() > e.maxChunkSize {
+			e.doCompaction()
+		}
+	}
+	sort.Slice(e.rowPtrs, e.keyColumnsLess)
+	return nil
+}
+
+func (e *TopNExec) processChildChk(childRowChk *chunk.Chunk) {
+	for i := 0; i < childRowChk.NumRows(); i++ {
+		if len(e.rowPtrs) == 0 {
+			return
+		}
+		heapMax := e.rowPtrs[0]
+		row := childRowChk.GetRow(i)
+		if e.lessRow(row, e.rowList.GetRow(heapMax)) {
+			e.rowPtrs[0] = e.rowList.AppendRow(row)
+			heap.Fix(e.chkHeap, 0)
+		}
+	}
+}
+
+// doCompaction rebuilds the row list by the rows in the heap, the other rows are released.
+func (e *TopNExec) doCompaction() {
+	newRowList := chunk.NewList(retTypes(e), e.initCap, e.maxChunkSize)
+	newRowPtrs := make([]chunk.RowPtr, 0, len(e.rowPtrs))
+	for _, rowPtr := range e.rowPtrs {
+		newRowPtrs = append(newRowPtrs, newRowList.AppendRow(e.rowList.GetRow(rowPtr)))
+	}
+	newRowList.GetMemTracker().AttachTo(e.memTracker)
+	e.rowList.Clear()
+	e.rowList.GetMemTracker().Detach()
+	e.rowList = newRowList
+	e.rowPtrs = newRowPtrs
+}
@@ -0,0 +1,309 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"math"
+
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/kv"
+	"grant-db/table"
+	"grant-db/tablecodec"
+	"grant-db/types"
+	"grant-db/util/chunk"
+	"grant-db/util/ranger"
+)
+
+// kvRange is the key range [startKey, endKey).
+type kvRange struct {
+	startKey kv.Key
+	endKey   kv.Key
+}
+
+// tableRangesToKVRanges converts the ranges of the handle to the key ranges of the rows. The keys are in
+// the order of the int64 handles, so a range of the unsigned handles across math.MaxInt64 is split into two
+// key ranges, which are kept in the order of the unsigned handles.
+func tableRangesToKVRanges(tbl table.Table, ranges []*ranger.Range, unsigned bool) []kvRange {
+	krs := make([]kvRange, 0, len(ranges))
+	appendRange := func(low, high int64) {
+		krs = append(krs, kvRange{startKey: tbl.RecordKey(low), endKey: tbl.RecordKey(high).PrefixNext()})
+	}
+	for _, ran := range ranges {
+		if unsigned {
+			low, high, ok := uintHandleRange(ran)
+			if !ok {
+				continue
+			}
+			if low <= math.MaxInt64 && high > math.MaxInt64 {
+				appendRange(int64(low), math.MaxInt64)
+				appendRange(math.MinInt64, int64(high))
+			} else {
+				appendRange(int64(low), int64(high))
+			}
+			continue
+		}
+		low, high, ok := intHandleRange(ran)
+		if ok {
+			appendRange(low, high)
+		}
+	}
+	return krs
+}
+
+// intHandleRange returns the closed range of the signed handle, ok is false if the range is empty.
+func intHandleRange(ran *ranger.Range) (low, high int64, ok bool) {
+	low, high = math.MinInt64, math.MaxInt64
+	if d := ran.LowVal[0]; d.Kind() == types.KindInt64 || d.Kind() == types.KindUint64 {
+		low = d.GetInt64()
+	}
+	if d := ran.HighVal[0]; d.Kind() == types.KindInt64 || d.Kind() == types.KindUint64 {
+		high = d.GetInt64()
+	}
+	if ran.LowExclude {
+		if low == math.MaxInt64 {
+			return 0, 0, false
+		}
+		low++
+	}
+	if ran.HighExclude {
+		if high == math.MinInt64 {
+			return 0, 0, false
+		}
+		high--
+	}
+	return low, high, low <= high
+}
+
+// uintHandleRange returns the closed range of the unsigned handle, ok is false if the range is empty.
+func uintHandleRange(ran *ranger.Range) (low, high uint64, ok bool) {
+	low, high = 0, math.MaxUint64
+	if d := ran.LowVal[0]; d.Kind() == types.KindInt64 || d.Kind() == types.KindUint64 {
+		low = d.GetUint64()
+	}
+	if d := ran.HighVal[0]; d.Kind() == types.KindInt64 || d.Kind() == types.KindUint64 {
+		high = d.GetUint64()
+	}
+	if ran.LowExclude {
+		if low == math.MaxUint64 {
+			return 0, 0, false
+		}
+		low++
+	}
+	if ran.HighExclude {
+		if high == 0 {
+			return 0, 0, false
+		}
+		high--
+	}
+	return low, high, low <= high
+}
+
+// kvScanner iterates the key-value pairs of the ranges in order, or in the reverse order if desc is true.
+type kvScanner struct {
+	txn    kv.Transaction
+	ranges []kvRange
+	desc   bool
+
+	rangeIdx int
+	iter     kv.Iterator
+	// advance is true if the iterator is on the pair returned last time.
+	advance bool
+}
+
+// next returns the next key-value pair, ok is false if there are no more pairs. The pair is valid until the
+// next call.
+func (s *kvScanner) next() (key kv.Key, value []byte, ok bool, err error) {
+	for {
+		if s.iter == nil {
+			if s.rangeIdx >= len(s.ranges) {
+				return nil, nil, false, nil
+			}
+			if s.iter, err = s.openIter(); err != nil {
+				return nil, nil, false, err
+			}
+			s.advance = false
+		}
+		if s.advance {
+			if err = s.iter.Next(); err != nil {
+				return nil, nil, false, err
+			}
+			s.advance = false
+		}
+		if s.iter.Valid() && s.inRange(s.iter.Key()) {
+			s.advance = true
+			return s.iter.Key(), s.iter.Value(), true, nil
+		}
+		s.iter.Close()
+		s.iter = nil
+		s.rangeIdx++
+	}
+}
+
+func (s *kvScanner) openIter() (kv.Iterator, error) {
+	if s.desc {
+		return s.txn.IterReverse(s.ranges[len(s.ranges)-1-s.rangeIdx].endKey)
+	}
+	r := s.ranges[s.rangeIdx]
+	return s.txn.Iter(r.startKey, r.endKey)
+}
+
+func (s *kvScanner) inRange(key kv.Key) bool {
+	if s.desc {
+		return bytes.Compare(key, s.ranges[len(s.ranges)-1-s.rangeIdx].startKey) >= 0
+	}
+	return bytes.Compare(key, s.ranges[s.rangeIdx].endKey) < 0
+}
+
+func (s *kvScanner) close() {
+	if s.iter != nil {
+		s.iter.Close()
+		s.iter = nil
+	}
+}
+
+// rowDecoder decodes the values of the rows to the columns of the chunks, the columns absent from a row are
+// filled by their original default values.
+type rowDecoder struct {
+	tblInfo  *model.TableInfo
+	cols     []*model.ColumnInfo
+	colTps   map[int64]*types.FieldType
+	defaults []types.Datum
+}
+
+func newRowDecoder(tblInfo *model.TableInfo, cols []*model.ColumnInfo) (*rowDecoder, error) {
+	d := &rowDecoder{
+		tblInfo:  tblInfo,
+		cols:     cols,
+		colTps:   make(map[int64]*types.FieldType, len(cols)),
+		defaults: make([]types.Datum, len(cols)),
+	}
+	for i, col := range cols {
+		d.colTps[col.ID] = &col.FieldType
+		if d.isHandleCol(col) {
+			continue
+		}
+		val, err := table.GetColOriginDefaultValue(col)
+		if err != nil {
+			return nil, err
+		}
+		d.defaults[i] = val
+	}
+	return d, nil
+}
+
+func (d *rowDecoder) isHandleCol(col *model.ColumnInfo) bool {
+	return (d.tblInfo.PKIsHandle && mysql.HasPriKeyFlag(col.Flag)) || col.ID == model.ExtraHandleID
+}
+
+// decodeToChunk decodes the row of the handle to the columns of the chunk.
+func (d *rowDecoder) decodeToChunk(handle int64, value []byte, chk *chunk.Chunk) error {
+	row, err := tablecodec.DecodeRowToDatumMap(value, d.colTps)
+	if err != nil {
+		return err
+	}
+	for i, col := range d.cols {
+		if d.isHandleCol(col) {
+			if mysql.HasUnsignedFlag(col.Flag) {
+				chk.AppendUint64(i, uint64(handle))
+			} else {
+				chk.AppendInt64(i, handle)
+			}
+			continue
+		}
+		if val, ok := row[col.ID]; ok {
+			chk.AppendDatum(i, &val)
+		} else {
+			chk.AppendDatum(i, &d.defaults[i])
+		}
+	}
+	return nil
+}
+
+// TableReaderExecutor reads the rows of the table in the ranges of the handle, or the rows of the handles
+// if it's the table side of an index lookup.
+type TableReaderExecutor struct {
+	baseExecutor
+
+	table   table.Table
+	ranges  []kvRange
+	desc    bool
+	decoder *rowDecoder
+
+	txn     kv.Transaction
+	scanner *kvScanner
+
+	// byHandles is true if the rows are read by the handles, which are reset by the index lookup.
+	byHandles bool
+	handles   []int64
+	handleIdx int
+}
+
+// Open implements the Executor Open interface.
+func (e *TableReaderExecutor) Open(ctx context.Context) error {
+	txn, err := e.ctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	e.txn = txn
+	if !e.byHandles {
+		e.scanner = &kvScanner{txn: txn, ranges: e.ranges, desc: e.desc}
+	}
+	return nil
+}
+
+// resetHandles sets the handles of the rows to be read.
+func (e *TableReaderExecutor) resetHandles(handles []int64) {
+	e.handles = handles
+	e.handleIdx = 0
+}
+
+// Next implements the Executor Next interface.
+func (e *TableReaderExecutor) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.byHandles {
+		return e.nextByHandles(ctx, req)
+	}
+	for !req.IsFull() {
+		key, value, ok, err := e.scanner.next()
+		if err != nil || !ok {
+			return err
+		}
+		handle, err := tablecodec.DecodeRowKey(key)
+		if err != nil {
+			return err
+		}
+		if err = e.decoder.decodeToChunk(handle, value, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// nextByHandles reads the rows in the order of the handles, the handles whose rows don't exist are skipped.
+func (e *TableReaderExecutor) nextByHandles(ctx context.Context, req *chunk.Chunk) error {
+	for ; e.handleIdx < len(e.handles) && !req.IsFull(); e.handleIdx++ {
+		handle := e.handles[e.handleIdx]
+		value, err := e.txn.Get(ctx, e.table.RecordKey(handle))
+		if kv.IsErrNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if err = e.decoder.decodeToChunk(handle, value, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close implements the Executor Close interface.
+func (e *TableReaderExecutor) Close() error {
+	if e.scanner != nil {
+		e.scanner.close()
+		e.scanner = nil
+	}
+	e.txn = nil
+	return nil
+}
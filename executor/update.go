@@ -0,0 +1,146 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/expression"
+	"grant-db/kv"
+	"grant-db/planner"
+	"grant-db/types"
+	"grant-db/util/chunk"
+)
+
+// UpdateExec updates the rows of the tables read by the child. All the rows are read before any of them is
+// updated, and a row which is read more than once by a join is only updated by the first one.
+type UpdateExec struct {
+	baseExecutor
+
+	orderedList    []*expression.Assignment
+	tblColPosInfos []planner.TblColPosInfo
+	ignoreErr      bool
+
+	// assignTbls and assignCols are the tables in tblColPosInfos and the offsets in their public columns of
+	// the columns of the assignments.
+	assignTbls []int
+	assignCols []int
+	done       bool
+}
+
+// Open implements the Executor Open interface.
+func (e *UpdateExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.assignTbls = make([]int, len(e.orderedList))
+	e.assignCols = make([]int, len(e.orderedList))
+	for i, assign := range e.orderedList {
+		e.assignTbls[i], e.assignCols[i] = -1, -1
+		for j, info := range e.tblColPosInfos {
+			for k, ordinal := range info.ColOrdinals {
+				if ordinal == assign.Col.Index {
+					e.assignTbls[i], e.assignCols[i] = j, k
+				}
+			}
+		}
+		if e.assignTbls[i] == -1 {
+			return errors.Errorf("the column %s of the assignment is not in the updated tables", assign.ColName)
+		}
+	}
+	e.done = false
+	return nil
+}
+
+// Next implements the Executor Next interface, all the rows are updated by the first call.
+func (e *UpdateExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	if e.done {
+		return nil
+	}
+	e.done = true
+	rows, err := readAllRows(ctx, e.children[0])
+	if err != nil {
+		return err
+	}
+	sc := e.ctx.GetSessionVars().StmtCtx
+	tps := retTypes(e.children[0])
+	updated := make([]map[int64]struct{}, len(e.tblColPosInfos))
+	for i := range updated {
+		updated[i] = make(map[int64]struct{})
+	}
+	var matched, changed uint64
+	for rowIdx, row := range rows {
+		newRow := append(make([]types.Datum, 0, len(row)), row...)
+		assigned := make([][]bool, len(e.tblColPosInfos))
+		for i, assign := range e.orderedList {
+			info := e.tblColPosInfos[e.assignTbls[i]]
+			// The table isn't matched by the outer join.
+			if row[info.HandleOrdinal].IsNull() {
+				continue
+			}
+			val, err := assign.Expr.Eval(datumsToRow(tps, newRow))
+			if err != nil {
+				return err
+			}
+			col := info.Table.Cols()[e.assignCols[i]]
+			if newRow[assign.Col.Index], err = castValue(sc, val, col, int64(rowIdx+1), sc.BadNullAsWarning); err != nil {
+				return err
+			}
+			if assigned[e.assignTbls[i]] == nil {
+				assigned[e.assignTbls[i]] = make([]bool, len(info.ColOrdinals))
+			}
+			assigned[e.assignTbls[i]][e.assignCols[i]] = true
+		}
+		for i, info := range e.tblColPosInfos {
+			if assigned[i] == nil {
+				continue
+			}
+			h := row[info.HandleOrdinal].GetInt64()
+			if _, ok := updated[i][h]; ok {
+				continue
+			}
+			updated[i][h] = struct{}{}
+			matched++
+			oldData := make([]types.Datum, 0, len(info.ColOrdinals))
+			newData := make([]types.Datum, 0, len(info.ColOrdinals))
+			for _, ordinal := range info.ColOrdinals {
+				oldData = append(oldData, row[ordinal])
+				newData = append(newData, newRow[ordinal])
+			}
+			rowChanged, err := updateRecord(e.ctx, info.Table, h, oldData, newData, assigned[i])
+			if err != nil {
+				if e.ignoreErr && kv.ErrKeyExists.Equal(err) {
+					sc.AppendWarning(err)
+					continue
+				}
+				return err
+			}
+			if rowChanged {
+				changed++
+			}
+		}
+	}
+	sc.AddAffectedRows(changed + affectedRowsOfFound(e.ctx, matched-changed))
+	sc.SetMessage(fmt.Sprintf(mysql.MySQLErrName[mysql.ErrUpdateInfo], matched, changed, sc.WarningCount()))
+	return nil
+}
+
+// readAllRows reads all the rows of the executor.
+func readAllRows(ctx context.Context, e Executor) ([][]types.Datum, error) {
+	tps := retTypes(e)
+	var rows [][]types.Datum
+	chk := newFirstChunk(e)
+	for {
+		if err := Next(ctx, e, chk); err != nil {
+			return nil, err
+		}
+		if chk.NumRows() == 0 {
+			return rows, nil
+		}
+		for i := 0; i < chk.NumRows(); i++ {
+			rows = append(rows, chk.GetRow(i).GetDatumRow(tps))
+		}
+	}
+}
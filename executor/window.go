@@ -0,0 +1,336 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+
+	"github.com/pingcap/parser/ast"
+	"grant-db/executor/aggfuncs"
+	"grant-db/expression"
+	"grant-db/planner"
+	"grant-db/planner/property"
+	"grant-db/util/chunk"
+	"grant-db/util/memory"
+)
+
+// WindowExec evaluates the window functions on the rows of the child, which are ordered by the PARTITION BY
+// and ORDER BY items. The rows of a partition are kept in the memory until all their results are returned.
+//
+// The functions which ignore the frame, and all the functions if the frame is the whole partition, are
+// updated by the partition at once. The others are evaluated on the frame of every row, the partial result
+// of the last frame is extended if the frame only grows, or slid if the function supports it.
+type WindowExec struct {
+	baseExecutor
+
+	windowFuncs []aggfuncs.AggFunc
+	// needFrame marks the functions which are evaluated on the frames.
+	needFrame   []bool
+	partitionBy []expression.Expression
+	orderBy     []property.SortItem
+	frame       *planner.WindowFrame
+
+	keyEval        *groupKeyEvaluator
+	partialResults []aggfuncs.PartialResult
+	// childResult is the last chunk of the child, rowIdx is its next row which is not in any partition.
+	childResult *chunk.Chunk
+	rowIdx      int
+	// chunks are the chunks of the child which hold the rows of the partition.
+	chunks   []*chunk.Chunk
+	rows     []chunk.Row
+	curKey   []byte
+	executed bool
+	// curIdx is the next row of the partition whose results are appended.
+	curIdx int
+
+	// The fields of the frames of the partition. frameStart and frameEnd are the last frame, peerStart and
+	// peerEnd are the peers of the current row, rangeStart and rangeEnd are the bounds of RANGE N.
+	orderByCmp             []chunk.CompareFunc
+	frameStart, frameEnd   int
+	peerStart, peerEnd     int
+	rangeStart, rangeEnd   int
+	removedRows, addedRows []chunk.Row
+
+	memTracker *memory.Tracker
+}
+
+// Open implements the Executor Open interface.
+func (e *WindowExec) Open(ctx context.Context) error {
+	if err := e.baseExecutor.Open(ctx); err != nil {
+		return err
+	}
+	e.memTracker = memory.NewTracker(e.id)
+	e.memTracker.AttachTo(e.ctx.GetSessionVars().StmtCtx.MemTracker)
+	e.keyEval = newGroupKeyEvaluator(e.partitionBy, e.maxChunkSize)
+	e.partialResults = allocPartialResults(e.windowFuncs)
+	e.orderByCmp = make([]chunk.CompareFunc, 0, len(e.orderBy))
+	for _, item := range e.orderBy {
+		e.orderByCmp = append(e.orderByCmp, chunk.GetCompareFunc(item.Col.RetType))
+	}
+	e.childResult, e.rowIdx = nil, 0
+	e.rows, e.curIdx = e.rows[:0], 0
+	e.executed = false
+	return nil
+}
+
+// Next implements the Executor Next interface.
+func (e *WindowExec) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	for !req.IsFull() && !e.executed {
+		if e.curIdx < len(e.rows) {
+			if err := e.appendResult(req); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := e.fetchPartition(ctx); err != nil {
+			return err
+		}
+		if len(e.rows) == 0 {
+			e.executed = true
+			return nil
+		}
+		if err := e.initPartition(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchPartition reads the rows of the next partition, the chunks of the last partition are released.
+func (e *WindowExec) fetchPartition(ctx context.Context) error {
+	e.rows = e.rows[:0]
+	e.releaseChunks()
+	for {
+		if e.childResult == nil || e.rowIdx >= e.childResult.NumRows() {
+			chk := newFirstChunk(e.children[0])
+			if err := Next(ctx, e.children[0], chk); err != nil {
+				return err
+			}
+			if chk.NumRows() == 0 {
+				return nil
+			}
+			if err := e.keyEval.evalChunk(e.ctx, chk); err != nil {
+				return err
+			}
+			e.childResult, e.rowIdx = chk, 0
+			e.chunks = append(e.chunks, chk)
+			e.memTracker.Consume(chk.MemoryUsage())
+		}
+		for ; e.rowIdx < e.childResult.NumRows(); e.rowIdx++ {
+			key := e.keyEval.key(e.rowIdx)
+			if len(e.rows) == 0 {
+				e.curKey = append(e.curKey[:0], key...)
+			} else if !bytes.Equal(key, e.curKey) {
+				return nil
+			}
+			e.rows = append(e.rows, e.childResult.GetRow(e.rowIdx))
+		}
+	}
+}
+
+// releaseChunks releases the chunks of the rows which have been returned, the last chunk is kept if the
+// next partition begins in it.
+func (e *WindowExec) releaseChunks() {
+	for _, chk := range e.chunks {
+		if chk == e.childResult && e.rowIdx < chk.NumRows() {
+			continue
+		}
+		e.memTracker.Consume(-chk.MemoryUsage())
+	}
+	e.chunks = e.chunks[:0]
+	if e.childResult != nil && e.rowIdx < e.childResult.NumRows() {
+		e.chunks = append(e.chunks, e.childResult)
+	}
+}
+
+// initPartition resets the functions for the new partition, the ones which aren't evaluated on the frames
+// are updated by all the rows.
+func (e *WindowExec) initPartition() error {
+	e.curIdx = 0
+	e.frameStart, e.frameEnd = 0, 0
+	e.peerStart, e.peerEnd = 0, 0
+	e.rangeStart, e.rangeEnd = 0, 0
+	for i, f := range e.windowFuncs {
+		f.ResetPartialResult(e.partialResults[i])
+		if e.needFrame[i] {
+			continue
+		}
+		if err := f.UpdatePartialResult(e.ctx, e.rows, e.partialResults[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// appendResult appends the row of curIdx and the results of the functions to req.
+func (e *WindowExec) appendResult(req *chunk.Chunk) error {
+	row := e.rows[e.curIdx]
+	if e.frame != nil {
+		if err := e.moveFrame(); err != nil {
+			return err
+		}
+	}
+	req.AppendPartialRow(0, row)
+	for i, f := range e.windowFuncs {
+		if err := f.AppendFinalResult2Chunk(e.ctx, e.partialResults[i], req); err != nil {
+			return err
+		}
+	}
+	e.curIdx++
+	return nil
+}
+
+// moveFrame computes the frame of the current row, and updates the partial results of the functions
+// evaluated on the frames from the last frame.
+func (e *WindowExec) moveFrame() error {
+	e.updatePeers()
+	start, err := e.boundOffset(e.frame.Start, true)
+	if err != nil {
+		return err
+	}
+	end, err := e.boundOffset(e.frame.End, false)
+	if err != nil {
+		return err
+	}
+	if start > end {
+		start = end
+	}
+	lastStart, lastEnd := e.frameStart, e.frameEnd
+	e.frameStart, e.frameEnd = start, end
+	canSlide := start >= lastStart && end >= lastEnd && start <= lastEnd
+	for i, f := range e.windowFuncs {
+		if !e.needFrame[i] {
+			continue
+		}
+		pr := e.partialResults[i]
+		if start == lastStart && end >= lastEnd {
+			if err := f.UpdatePartialResult(e.ctx, e.rows[lastEnd:end], pr); err != nil {
+				return err
+			}
+			continue
+		}
+		if slidingFunc, ok := f.(aggfuncs.SlidingWindowAggFunc); ok && canSlide {
+			if err := slidingFunc.Slide(e.ctx, e.rows[lastStart:start], e.rows[lastEnd:end], pr); err != nil {
+				return err
+			}
+			continue
+		}
+		f.ResetPartialResult(pr)
+		if err := f.UpdatePartialResult(e.ctx, e.rows[start:end], pr); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updatePeers finds the peers of the current row, the rows are peers if their ORDER BY items are equal.
+func (e *WindowExec) updatePeers() {
+	if e.curIdx < e.peerEnd {
+		return
+	}
+	e.peerStart, e.peerEnd = e.curIdx, e.curIdx+1
+	for e.peerEnd < len(e.rows) && e.isPeer(e.rows[e.curIdx], e.rows[e.peerEnd]) {
+		e.peerEnd++
+	}
+}
+
+func (e *WindowExec) isPeer(lhs, rhs chunk.Row) bool {
+	for i, item := range e.orderBy {
+		if e.orderByCmp[i](lhs, item.Col.Index, rhs, item.Col.Index) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// boundOffset returns the offset of the bound of the current row in the partition, the frame ends before
+// the offset of the end.
+func (e *WindowExec) boundOffset(bound *planner.FrameBound, isStart bool) (int, error) {
+	numRows := len(e.rows)
+	switch {
+	case bound.UnBounded:
+		if bound.Type == ast.Preceding {
+			return 0, nil
+		}
+		return numRows, nil
+	case e.frame.Type == ast.Rows:
+		offset := e.curIdx
+		if bound.Type == ast.Preceding {
+			if bound.Num > uint64(offset) {
+				offset = -1
+			} else {
+				offset -= int(bound.Num)
+			}
+		} else if bound.Type == ast.Following {
+			if bound.Num >= uint64(numRows-offset) {
+				offset = numRows
+			} else {
+				offset += int(bound.Num)
+			}
+		}
+		if !isStart {
+			offset++
+		}
+		if offset < 0 {
+			return 0, nil
+		}
+		if offset > numRows {
+			return numRows, nil
+		}
+		return offset, nil
+	case bound.Type == ast.CurrentRow:
+		if isStart {
+			return e.peerStart, nil
+		}
+		return e.peerEnd, nil
+	}
+	return e.rangeBoundOffset(bound, isStart)
+}
+
+// rangeBoundOffset returns the offset of the bound N PRECEDING or N FOLLOWING of RANGE, the start is the
+// first row whose ORDER BY item reaches the value of CalcFunc, and the end is the first one beyond it. The
+// offsets only move forward since the values of CalcFunc are in the order of the rows. The bound of the row
+// whose ORDER BY item is NULL is its peers.
+func (e *WindowExec) rangeBoundOffset(bound *planner.FrameBound, isStart bool) (int, error) {
+	val, err := bound.CalcFunc.Eval(e.rows[e.curIdx])
+	if err != nil {
+		return 0, err
+	}
+	if val.IsNull() {
+		if isStart {
+			return e.peerStart, nil
+		}
+		return e.peerEnd, nil
+	}
+	offset := &e.rangeEnd
+	if isStart {
+		offset = &e.rangeStart
+	}
+	sc := e.ctx.GetSessionVars().StmtCtx
+	item := e.orderBy[0]
+	for ; *offset < len(e.rows); *offset++ {
+		d := e.rows[*offset].GetDatum(item.Col.Index, item.Col.RetType)
+		cmp, err := d.CompareDatum(sc, &val)
+		if err != nil {
+			return 0, err
+		}
+		if item.Desc {
+			cmp = -cmp
+		}
+		if cmp > 0 || (isStart && cmp == 0) {
+			break
+		}
+	}
+	return *offset, nil
+}
+
+// Close implements the Executor Close interface.
+func (e *WindowExec) Close() error {
+	e.childResult = nil
+	e.chunks, e.rows = nil, nil
+	e.partialResults = nil
+	if e.memTracker != nil {
+		e.memTracker.Consume(-e.memTracker.BytesConsumed())
+	}
+	return e.baseExecutor.Close()
+}
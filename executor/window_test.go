@@ -0,0 +1,44 @@
+package executor_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pingcap/parser/mysql"
+)
+
+func TestWindowFunctions(t *testing.T) {
+	tk := newExecTestKit(t)
+	tk.MustExec("create table t (a int primary key, b int, c int)")
+	tk.MustExec("insert into t values (1, 1, 10), (2, 1, 20), (3, 1, 20), (4, 2, 5), (5, 2, null), (6, null, 7)")
+	tk.MustQuery("select a, row_number() over (order by a desc), rank() over (order by c), dense_rank() over (order by c) from t order by a").Check(
+		"1 6 4 4", "2 5 5 5", "3 4 5 5", "4 3 2 2", "5 2 1 1", "6 1 3 3")
+	tk.MustQuery("select a, rank() over w, percent_rank() over w, cume_dist() over w from t window w as (partition by b order by c) order by a").Check(
+		"1 1 0 0.3333333333333333", "2 2 0.5 1", "3 2 0.5 1", "4 2 1 1", "5 1 0 0.5", "6 1 0 1")
+	tk.MustQuery("select a, ntile(2) over (partition by b order by a), lead(c) over (order by a), lag(c, 2, 0) over (order by a) from t order by a").Check(
+		"1 1 20 0", "2 1 20 0", "3 2 5 10", "4 1 <nil> 20", "5 2 7 20", "6 1 <nil> 5")
+	// The default frame ends at the last peer of the current row.
+	tk.MustQuery("select a, first_value(c) over w, last_value(c) over w, nth_value(c, 2) over w from t window w as (partition by b order by a) order by a").Check(
+		"1 10 10 <nil>", "2 10 20 20", "3 10 20 20", "4 5 5 <nil>", "5 5 <nil> <nil>", "6 7 7 <nil>")
+	tk.MustQuery("select a, sum(c) over (partition by b), count(*) over (), avg(c) over (order by a rows between 1 preceding and 1 following) from t order by a").Check(
+		"1 50 6 15.0000", "2 50 6 16.6667", "3 50 6 15.0000", "4 5 6 12.5000", "5 5 6 6.0000", "6 7 6 7.0000")
+	tk.MustQuery("select a, sum(c) over (order by c range between 5 preceding and current row) from t order by a").Check(
+		"1 22", "2 40", "3 40", "4 5", "5 <nil>", "6 12")
+	tk.MustQuery("select a, max(c) over (order by a rows unbounded preceding), min(c) over (order by a rows between current row and unbounded following) from t order by a").Check(
+		"1 10 5", "2 20 5", "3 20 5", "4 20 5", "5 20 7", "6 20 7")
+	tk.MustQuery("select b, sum(sum(c)) over (order by b) from t group by b order by b").Check("<nil> 7", "1 57", "2 62")
+	tk.MustQuery("select a, row_number() over () from t where a > 100").Check()
+
+	tk.MustGetErrCode("select a from t where row_number() over () > 1", mysql.ErrWindowInvalidWindowFuncUse)
+	tk.MustGetErrCode("select a, ntile(0) over () from t", mysql.ErrWrongArguments)
+	tk.MustGetErrCode("select a, rank() over w from t", mysql.ErrWindowNoSuchWindow)
+	tk.MustGetErrCode("select a, sum(c) over (order by a, b range 1 preceding) from t", mysql.ErrWindowRangeFrameOrderType)
+	tk.MustGetErrCode("select a, sum(c) over w from t window w as (order by a), w as (order by b)", mysql.ErrWindowDuplicateName)
+
+	// The partitions span the chunks.
+	tk.MustExec("create table big (a int primary key, b int)")
+	insertRows(tk, "big", 0, 1000, func(i int) string { return fmt.Sprint(i % 3) })
+	tk.MustExec("set tidb_max_chunk_size = 32")
+	tk.MustQuery("select b, a, row_number() over w, sum(a) over w from big window w as (partition by b order by a) order by a desc limit 2").Check(
+		"0 999 334 166833", "2 998 333 166500")
+}
@@ -0,0 +1,162 @@
+package executor
+
+import (
+	"context"
+
+	"github.com/pingcap/parser/mysql"
+	"grant-db/kv"
+	"grant-db/sessionctx"
+	"grant-db/sessionctx/stmtctx"
+	"grant-db/table"
+	"grant-db/types"
+	"grant-db/util/chunk"
+	"grant-db/util/codec"
+)
+
+// datumsToRow makes a row of the datums, the expressions of the rows being written are evaluated on it.
+func datumsToRow(tps []*types.FieldType, datums []types.Datum) chunk.Row {
+	chk := chunk.NewChunkWithCapacity(tps, 1)
+	for i := range datums {
+		chk.AppendDatum(i, &datums[i])
+	}
+	return chk.GetRow(0)
+}
+
+// fieldTypes returns the types of the columns.
+func fieldTypes(cols []*table.Column) []*types.FieldType {
+	tps := make([]*types.FieldType, 0, len(cols))
+	for _, col := range cols {
+		tps = append(tps, &col.FieldType)
+	}
+	return tps
+}
+
+// castValue converts the value written to the column of the row rowNum. A NULL written to a NOT NULL
+// column is an error unless badNullAsWarning is true, in which case the zero value of the column is written.
+func castValue(sc *stmtctx.StatementContext, val types.Datum, col *table.Column, rowNum int64, badNullAsWarning bool) (types.Datum, error) {
+	if val.IsNull() {
+		err := col.CheckNotNull(val)
+		if err == nil {
+			return val, nil
+		}
+		if !badNullAsWarning {
+			return val, err
+		}
+		sc.AppendWarning(err)
+		return table.GetZeroValue(col.ColumnInfo), nil
+	}
+	return table.CastValue(sc, val, col.ColumnInfo, rowNum)
+}
+
+// updateRecord writes newData to the row of the handle if it differs from oldData, the columns with ON
+// UPDATE CURRENT_TIMESTAMP which aren't assigned get the current time then. It returns whether the row
+// changed.
+func updateRecord(ctx sessionctx.Context, t table.Table, h int64, oldData, newData []types.Datum, assigned []bool) (bool, error) {
+	sc := ctx.GetSessionVars().StmtCtx
+	changed := false
+	for i := range oldData {
+		cmp, err := newData[i].CompareDatum(sc, &oldData[i])
+		if err != nil {
+			return false, err
+		}
+		if cmp != 0 {
+			changed = true
+			break
+		}
+	}
+	if !changed {
+		return false, nil
+	}
+	for i, col := range t.Cols() {
+		if mysql.HasOnUpdateNowFlag(col.Flag) && !assigned[i] {
+			newData[i] = types.NewTimeDatum(types.FromGoTime(sc.GetNowTsCached(), col.Tp, int8(col.Decimal)))
+		}
+	}
+	if err := t.UpdateRecord(ctx, h, oldData, newData); err != nil {
+		return false, err
+	}
+	ctx.GetSessionVars().TxnCtx.UpdateDeltaForTable(t.Meta().ID, 0, 1)
+	return true, nil
+}
+
+// dupHandles returns the handles of the rows which conflict with the row of the public columns on the
+// handle or the unique keys, every handle is returned once.
+func dupHandles(ctx sessionctx.Context, t table.Table, row []types.Datum) ([]int64, error) {
+	txn, err := ctx.Txn(true)
+	if err != nil {
+		return nil, err
+	}
+	var handles []int64
+	appendHandle := func(h int64) {
+		for _, handle := range handles {
+			if handle == h {
+				return
+			}
+		}
+		handles = append(handles, h)
+	}
+	// The index values are fetched by the offsets of all the columns.
+	fullRow := make([]types.Datum, len(t.Meta().Columns))
+	for i, col := range t.Cols() {
+		fullRow[col.Offset] = row[i]
+		if !col.IsPKHandleColumn(t.Meta()) {
+			continue
+		}
+		h := row[i].GetInt64()
+		_, err = txn.Get(context.Background(), t.RecordKey(h))
+		if err == nil {
+			appendHandle(h)
+		} else if !kv.IsErrNotFound(err) {
+			return nil, err
+		}
+	}
+	for _, idx := range t.WritableIndices() {
+		if !idx.Meta().Unique {
+			continue
+		}
+		vals, err := idx.FetchValues(fullRow, nil)
+		if err != nil {
+			return nil, err
+		}
+		key, distinct, err := idx.GenIndexKey(vals, 0, nil)
+		if err != nil {
+			return nil, err
+		}
+		if !distinct {
+			continue
+		}
+		value, err := txn.Get(context.Background(), key)
+		if kv.IsErrNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		_, h, err := codec.DecodeInt(value)
+		if err != nil {
+			return nil, err
+		}
+		appendHandle(h)
+	}
+	return handles, nil
+}
+
+// equalDatums checks whether the rows have the same values.
+func equalDatums(sc *stmtctx.StatementContext, a, b []types.Datum) (bool, error) {
+	for i := range a {
+		cmp, err := a[i].CompareDatum(sc, &b[i])
+		if err != nil || cmp != 0 {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// affectedRowsOfFound returns the affected rows of the rows which are found but unchanged, which are counted
+// if the client has the CLIENT_FOUND_ROWS capability.
+func affectedRowsOfFound(ctx sessionctx.Context, found uint64) uint64 {
+	if ctx.GetSessionVars().ClientCapability&mysql.ClientFoundRows > 0 {
+		return found
+	}
+	return 0
+}
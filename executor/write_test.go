@@ -0,0 +1,138 @@
+package executor_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/parser/mysql"
+	"grant-db/meta/autoid"
+	"grant-db/util/testkit"
+)
+
+// checkExec executes the statement and checks its affected rows and info message.
+func checkExec(t *testing.T, tk *testkit.TestKit, sql string, affected uint64, message string) {
+	t.Helper()
+	tk.MustExec(sql)
+	if got := tk.Se.AffectedRows(); got != affected {
+		t.Fatalf("%s affects %d rows, expected %d", sql, got, affected)
+	}
+	if got := tk.Se.LastMessage(); got != message {
+		t.Fatalf("the message of %s is %q, expected %q", sql, got, message)
+	}
+}
+
+func TestInsertAndReplace(t *testing.T) {
+	tk := newExecTestKit(t)
+	tk.MustExec("create table t (a int primary key, b int, c int, unique key uk_b (b))")
+	// The message is only set for the statements of multiple rows.
+	checkExec(t, tk, "insert into t values (1, 1, 1), (2, 2, 2)", 2, "Records: 2  Duplicates: 0  Warnings: 0")
+	checkExec(t, tk, "insert into t values (3, 3, 3)", 1, "")
+	tk.MustGetErrCode("insert into t values (4, 1, 4)", mysql.ErrDupEntry)
+	tk.MustGetErrCode("insert into t values (4, 4, 4), (1, 5, 5)", mysql.ErrDupEntry)
+	tk.MustQuery("select count(*) from t").Check("3")
+
+	// A row updated by ON DUPLICATE KEY UPDATE is counted twice, and not counted if it's unchanged.
+	checkExec(t, tk, "insert into t values (1, 5, 5) on duplicate key update c = c + 10", 2, "")
+	checkExec(t, tk, "insert into t values (1, 5, 5) on duplicate key update c = c", 0, "")
+	checkExec(t, tk, "insert into t values (4, 2, 5), (5, 5, 5) on duplicate key update c = values(c) + 100", 3,
+		"Records: 2  Duplicates: 1  Warnings: 0")
+	tk.MustQuery("select * from t order by a").Check("1 1 11", "2 2 105", "3 3 3", "5 5 5")
+	checkExec(t, tk, "insert ignore into t values (1, 9, 9), (6, 6, 6)", 1, "Records: 2  Duplicates: 1  Warnings: 1")
+	tk.MustQuery("show warnings").Check("Warning 1062 Duplicate entry '1' for key 'PRIMARY'")
+
+	// REPLACE deletes all the rows of the conflicting keys.
+	checkExec(t, tk, "replace into t values (2, 3, 30)", 3, "")
+	checkExec(t, tk, "replace into t values (7, 7, 7)", 1, "")
+	tk.MustQuery("select * from t order by a").Check("1 1 11", "2 3 30", "5 5 5", "6 6 6", "7 7 7")
+	checkExec(t, tk, "insert into t select a + 10, b + 10, c from t", 5, "Records: 5  Duplicates: 0  Warnings: 0")
+	tk.MustQuery("select count(*), sum(c) from t").Check("10 118")
+}
+
+func TestUpdateAndDelete(t *testing.T) {
+	tk := newExecTestKit(t)
+	tk.MustExec("create table t (a int primary key, b int, c int)")
+	tk.MustExec("create table s (a int primary key, b int)")
+	tk.MustExec("insert into t values (1, 1, 1), (2, 2, 2), (3, 3, 3), (4, 4, 4)")
+	tk.MustExec("insert into s values (1, 0), (2, 0), (5, 0)")
+
+	// The rows matched but unchanged aren't affected.
+	checkExec(t, tk, "update t set c = c + 1 where a > 2", 2, "Rows matched: 2  Changed: 2  Warnings: 0")
+	checkExec(t, tk, "update t set c = 4 where a > 2", 1, "Rows matched: 2  Changed: 1  Warnings: 0")
+	checkExec(t, tk, "update t set c = c where a > 10", 0, "Rows matched: 0  Changed: 0  Warnings: 0")
+	checkExec(t, tk, "update t set a = a + 10 where a = 4", 1, "Rows matched: 1  Changed: 1  Warnings: 0")
+	tk.MustGetErrCode("update t set a = 1 where a = 2", mysql.ErrDupEntry)
+	tk.MustQuery("select * from t order by a").Check("1 1 1", "2 2 2", "3 3 4", "14 4 4")
+
+	checkExec(t, tk, "update t, s set t.c = 0, s.b = t.b where t.a = s.a", 4, "Rows matched: 4  Changed: 4  Warnings: 0")
+	tk.MustQuery("select * from t order by a").Check("1 1 0", "2 2 0", "3 3 4", "14 4 4")
+	tk.MustQuery("select * from s order by a").Check("1 1", "2 2", "5 0")
+
+	checkExec(t, tk, "delete t, s from t join s on t.a = s.a where t.a = 1", 2, "")
+	checkExec(t, tk, "delete from t where a > 10", 1, "")
+	checkExec(t, tk, "delete from t where a > 10", 0, "")
+	tk.MustQuery("select a from t order by a").Check("2", "3")
+	tk.MustQuery("select a from s order by a").Check("2", "5")
+	checkExec(t, tk, "delete s from s left join t on s.a = t.a where t.a is null", 1, "")
+	checkExec(t, tk, "delete from t order by a desc limit 1", 1, "")
+	tk.MustQuery("select a from t").Check("2")
+	tk.MustGetErrCode("delete x from t", mysql.ErrUnknownTable)
+}
+
+func TestAutoIncrement(t *testing.T) {
+	tk := newExecTestKit(t)
+	tk.MustExec("create table t (a int primary key auto_increment, b int)")
+	checkLastInsertID := func(sql string, id uint64, lastInsertID string) {
+		t.Helper()
+		tk.MustExec(sql)
+		if got := tk.Se.LastInsertID(); got != id {
+			t.Fatalf("the insert ID of %s is %d, expected %d", sql, got, id)
+		}
+		tk.MustQuery("select last_insert_id()").Check(lastInsertID)
+	}
+	// LAST_INSERT_ID() is the first ID allocated by the last statement, it's kept by the explicit IDs.
+	checkLastInsertID("insert into t (b) values (1)", 1, "1")
+	checkLastInsertID("insert into t (b) values (2), (3)", 2, "2")
+	checkLastInsertID("insert into t values (10, 4)", 10, "2")
+	checkLastInsertID("insert into t (b) values (5)", 11, "11")
+	checkLastInsertID("insert into t values (null, 6), (0, 7)", 12, "12")
+	tk.MustQuery("select last_insert_id(100), last_insert_id()").Check("100 100")
+
+	// The IDs are the next ones in the sequence of auto_increment_offset stepped by auto_increment_increment.
+	tk.MustExec("set auto_increment_increment = 5")
+	tk.MustExec("set auto_increment_offset = 3")
+	checkLastInsertID("insert into t (b) values (8), (9)", 18, "18")
+	tk.MustGetErrCode("set auto_increment_increment = 70000", mysql.ErrWrongValueForVar)
+	tk.MustExec("set auto_increment_increment = 1")
+	tk.MustExec("set auto_increment_offset = 1")
+	tk.MustQuery("select a from t order by a").Check("1", "2", "3", "10", "11", "12", "13", "18", "23")
+
+	// The IDs are rebased by the explicit ones.
+	tk.MustExec("replace into t values (2000, 1)")
+	checkLastInsertID("insert into t (b) values (11)", 2001, "2001")
+}
+
+func TestAutoRandom(t *testing.T) {
+	tk := newExecTestKit(t)
+	tk.MustExec("create table t (a bigint primary key auto_random(3), b int)")
+	// The shard bits are the 3 bits below the sign bit, they're the same for the rows of a transaction.
+	tk.MustExec("insert into t (b) values (1), (2)")
+	tk.MustQuery("select a & ((1 << 60) - 1), a >> 60 < 8, a > 0 from t order by b").Check("1 1 1", "2 1 1")
+	tk.MustQuery("select count(distinct a >> 60) from t").Check("1")
+	tk.MustQuery("select last_insert_id() = min(a) from t").Check("1")
+	tk.MustExec("insert into t values (5, 3)")
+	tk.MustExec("insert into t (b) values (4)")
+	tk.MustQuery("select a & ((1 << 60) - 1) from t order by b").Check("1", "2", "5", "6")
+
+	tests := []string{
+		"create table t1 (a int primary key auto_random, b int)",
+		"create table t1 (a bigint primary key auto_random(16), b int)",
+		"create table t1 (a bigint primary key auto_random(0), b int)",
+		"create table t1 (a bigint auto_random, b int)",
+		"create table t1 (a bigint primary key auto_random auto_increment, b int)",
+		"create table t1 (a bigint primary key auto_random default 1, b int)",
+	}
+	for _, sql := range tests {
+		if err := tk.ExecToErr(sql); !autoid.ErrInvalidAutoRandom.Equal(err) {
+			t.Fatalf("the error of %s is %v, expected %v", sql, err, autoid.ErrInvalidAutoRandom)
+		}
+	}
+}
@@ -0,0 +1,263 @@
+package aggregation
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/expression"
+	"grant-db/sessionctx"
+	"grant-db/types"
+)
+
+// AggFuncJSONArrayAgg is the name of json_arrayagg function, it's parsed as a function call by the parser.
+const AggFuncJSONArrayAgg = "json_arrayagg"
+
+// divFracIncr is the increment of the scale of AVG, like div_precision_increment of MySQL.
+const divFracIncr = 4
+
+// AggFuncDesc describes an aggregate function signature, only used in planner.
+type AggFuncDesc struct {
+	// Name represents the aggregate function name.
+	Name string
+	// Args represents the arguments of the aggregate function.
+	Args []expression.Expression
+	// RetTp represents the return type of the aggregate function.
+	RetTp *types.FieldType
+	// HasDistinct represents whether the aggregate function contains a distinct attribute.
+	HasDistinct bool
+	// OrderByItems are the items of the ORDER BY clause of GROUP_CONCAT.
+	OrderByItems []*ByItem
+}
+
+// ByItem is an item of the ORDER BY clause of an aggregate function.
+type ByItem struct {
+	Expr expression.Expression
+	Desc bool
+}
+
+// String implements fmt.Stringer interface.
+func (by *ByItem) String() string {
+	if by.Desc {
+		return by.Expr.String() + " desc"
+	}
+	return by.Expr.String()
+}
+
+// Clone copies the item totally.
+func (by *ByItem) Clone() *ByItem {
+	return &ByItem{Expr: by.Expr.Clone(), Desc: by.Desc}
+}
+
+// The names of STDDEV_POP which are parsed as they're written.
+const (
+	aggFuncStd    = "std"
+	aggFuncStddev = "stddev"
+)
+
+// NewAggFuncDesc creates an aggregate function signature descriptor, the arguments are wrapped with
+// casts to the types the aggregate function evaluates.
+func NewAggFuncDesc(ctx sessionctx.Context, name string, args []expression.Expression, hasDistinct bool) (*AggFuncDesc, error) {
+	name = strings.ToLower(name)
+	if name == aggFuncStd || name == aggFuncStddev {
+		name = ast.AggFuncStddevPop
+	}
+	a := &AggFuncDesc{Name: name, Args: args, HasDistinct: hasDistinct}
+	switch name {
+	case ast.AggFuncCount:
+		if len(a.Args) == 0 {
+			return nil, expression.ErrIncorrectParameterCount.GenWithStackByArgs(a.Name)
+		}
+		a.typeInfer4Count()
+	case ast.AggFuncSum, ast.AggFuncAvg:
+		if err := a.checkArgsLen(1); err != nil {
+			return nil, err
+		}
+		a.typeInfer4SumAvg()
+		if a.RetTp.EvalType() == types.ETDecimal {
+			a.Args[0] = expression.WrapWithCastAsDecimal(ctx, a.Args[0])
+		} else {
+			a.Args[0] = expression.WrapWithCastAsReal(ctx, a.Args[0])
+		}
+	case ast.AggFuncBitAnd, ast.AggFuncBitOr, ast.AggFuncBitXor:
+		if err := a.checkArgsLen(1); err != nil {
+			return nil, err
+		}
+		a.Args[0] = expression.WrapWithCastAsInt(ctx, a.Args[0])
+		a.typeInfer4BitFuncs()
+	case ast.AggFuncVarPop, ast.AggFuncVarSamp, ast.AggFuncStddevPop, ast.AggFuncStddevSamp:
+		if err := a.checkArgsLen(1); err != nil {
+			return nil, err
+		}
+		a.Args[0] = expression.WrapWithCastAsReal(ctx, a.Args[0])
+		a.RetTp = types.NewFieldType(mysql.TypeDouble)
+		a.RetTp.Flen, a.RetTp.Decimal = mysql.MaxRealWidth, types.UnspecifiedLength
+		types.SetBinChsClnFlag(a.RetTp)
+	case ast.AggFuncGroupConcat:
+		// The last argument is the separator.
+		if len(a.Args) < 2 {
+			return nil, expression.ErrIncorrectParameterCount.GenWithStackByArgs(a.Name)
+		}
+		for i := 0; i < len(a.Args)-1; i++ {
+			a.Args[i] = expression.WrapWithCastAsString(ctx, a.Args[i])
+		}
+		a.typeInfer4GroupConcat()
+	case ast.AggFuncMax, ast.AggFuncMin, ast.AggFuncFirstRow:
+		if err := a.checkArgsLen(1); err != nil {
+			return nil, err
+		}
+		a.RetTp = a.Args[0].GetType().Clone()
+		if name != ast.AggFuncFirstRow {
+			// MAX and MIN are NULL for an empty group.
+			a.RetTp.Flag &^= mysql.NotNullFlag
+		}
+	case AggFuncJSONArrayAgg:
+		if err := a.checkArgsLen(1); err != nil {
+			return nil, err
+		}
+		a.Args[0] = expression.WrapWithCastAsJSONValue(ctx, a.Args[0])
+		a.typeInfer4JSON()
+	case ast.AggFuncJsonObjectAgg:
+		if err := a.checkArgsLen(2); err != nil {
+			return nil, err
+		}
+		a.Args[0] = expression.WrapWithCastAsString(ctx, a.Args[0])
+		a.Args[1] = expression.WrapWithCastAsJSONValue(ctx, a.Args[1])
+		a.typeInfer4JSON()
+	default:
+		return nil, expression.ErrFunctionNotExists.GenWithStackByArgs("FUNCTION", name)
+	}
+	return a, nil
+}
+
+func (a *AggFuncDesc) checkArgsLen(n int) error {
+	if len(a.Args) != n {
+		return expression.ErrIncorrectParameterCount.GenWithStackByArgs(a.Name)
+	}
+	return nil
+}
+
+func (a *AggFuncDesc) typeInfer4Count() {
+	a.RetTp = types.NewFieldType(mysql.TypeLonglong)
+	a.RetTp.Flen, a.RetTp.Decimal = mysql.MaxIntWidth, 0
+	a.RetTp.Flag |= mysql.NotNullFlag
+	types.SetBinChsClnFlag(a.RetTp)
+}
+
+// typeInfer4SumAvg infers the type of SUM and AVG, which are DECIMAL for the exact numbers and DOUBLE for
+// the others, the scale of AVG is 4 more than the argument's.
+func (a *AggFuncDesc) typeInfer4SumAvg() {
+	argTp := a.Args[0].GetType()
+	switch argTp.EvalType() {
+	case types.ETInt, types.ETDecimal:
+		a.RetTp = types.NewFieldType(mysql.TypeNewDecimal)
+		a.RetTp.Flen, a.RetTp.Decimal = mysql.MaxDecimalWidth, 0
+		if argTp.Decimal > 0 {
+			a.RetTp.Decimal = argTp.Decimal
+		}
+		if a.Name == ast.AggFuncAvg {
+			a.RetTp.Decimal += divFracIncr
+		}
+		if a.RetTp.Decimal > mysql.MaxDecimalScale {
+			a.RetTp.Decimal = mysql.MaxDecimalScale
+		}
+	default:
+		a.RetTp = types.NewFieldType(mysql.TypeDouble)
+		a.RetTp.Flen, a.RetTp.Decimal = mysql.MaxRealWidth, types.UnspecifiedLength
+	}
+	types.SetBinChsClnFlag(a.RetTp)
+}
+
+// typeInfer4BitFuncs infers the type of BIT_AND, BIT_OR and BIT_XOR, which are never NULL.
+func (a *AggFuncDesc) typeInfer4BitFuncs() {
+	a.RetTp = types.NewFieldType(mysql.TypeLonglong)
+	a.RetTp.Flen, a.RetTp.Decimal = mysql.MaxIntWidth, 0
+	a.RetTp.Flag |= mysql.UnsignedFlag | mysql.NotNullFlag
+	types.SetBinChsClnFlag(a.RetTp)
+}
+
+// typeInfer4GroupConcat infers the type of GROUP_CONCAT, whose collation is the one of the first argument.
+func (a *AggFuncDesc) typeInfer4GroupConcat() {
+	argTp := a.Args[0].GetType()
+	a.RetTp = types.NewFieldType(mysql.TypeVarString)
+	a.RetTp.Flen, a.RetTp.Decimal = mysql.MaxBlobWidth, 0
+	a.RetTp.Charset, a.RetTp.Collate = argTp.Charset, argTp.Collate
+	if mysql.HasBinaryFlag(argTp.Flag) {
+		a.RetTp.Flag |= mysql.BinaryFlag
+	}
+}
+
+func (a *AggFuncDesc) typeInfer4JSON() {
+	a.RetTp = types.NewFieldType(mysql.TypeJSON)
+	a.RetTp.Flen, a.RetTp.Decimal = mysql.MaxBlobWidth, 0
+	types.SetBinChsClnFlag(a.RetTp)
+}
+
+// String implements the fmt.Stringer interface.
+func (a *AggFuncDesc) String() string {
+	var buffer bytes.Buffer
+	buffer.WriteString(a.Name + "(")
+	if a.HasDistinct {
+		buffer.WriteString("distinct ")
+	}
+	args := a.Args
+	if a.Name == ast.AggFuncGroupConcat {
+		args = args[:len(args)-1]
+	}
+	for i, arg := range args {
+		if i > 0 {
+			buffer.WriteString(", ")
+		}
+		buffer.WriteString(arg.String())
+	}
+	for i, item := range a.OrderByItems {
+		if i == 0 {
+			buffer.WriteString(" order by ")
+		} else {
+			buffer.WriteString(", ")
+		}
+		buffer.WriteString(item.String())
+	}
+	if a.Name == ast.AggFuncGroupConcat {
+		buffer.WriteString(" separator " + a.Args[len(a.Args)-1].String())
+	}
+	buffer.WriteString(")")
+	return buffer.String()
+}
+
+// Equal checks whether two aggregate function signatures are equal.
+func (a *AggFuncDesc) Equal(ctx sessionctx.Context, other *AggFuncDesc) bool {
+	if a.Name != other.Name || a.HasDistinct != other.HasDistinct || len(a.Args) != len(other.Args) ||
+		len(a.OrderByItems) != len(other.OrderByItems) {
+		return false
+	}
+	for i := range a.Args {
+		if !a.Args[i].Equal(ctx, other.Args[i]) {
+			return false
+		}
+	}
+	for i, item := range a.OrderByItems {
+		if item.Desc != other.OrderByItems[i].Desc || !item.Expr.Equal(ctx, other.OrderByItems[i].Expr) {
+			return false
+		}
+	}
+	return true
+}
+
+// Clone copies an aggregate function signature totally.
+func (a *AggFuncDesc) Clone() *AggFuncDesc {
+	clone := *a
+	clone.Args = make([]expression.Expression, len(a.Args))
+	for i := range a.Args {
+		clone.Args[i] = a.Args[i].Clone()
+	}
+	if a.OrderByItems != nil {
+		clone.OrderByItems = make([]*ByItem, len(a.OrderByItems))
+		for i, item := range a.OrderByItems {
+			clone.OrderByItems[i] = item.Clone()
+		}
+	}
+	clone.RetTp = a.RetTp.Clone()
+	return &clone
+}
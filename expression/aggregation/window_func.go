@@ -0,0 +1,144 @@
+package aggregation
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/expression"
+	"grant-db/sessionctx"
+	"grant-db/types"
+	"grant-db/util/chunk"
+)
+
+// WindowFuncDesc describes a window function signature, only used in planner.
+type WindowFuncDesc struct {
+	// Name represents the window function name.
+	Name string
+	// Args represents the arguments of the window function, the offset and the default value of LEAD and
+	// LAG are always filled.
+	Args []expression.Expression
+	// RetTp represents the return type of the window function.
+	RetTp *types.FieldType
+}
+
+// noFrameWindowFuncs are the window functions which evaluate the whole partition, the frames of the
+// windows are ignored.
+var noFrameWindowFuncs = map[string]struct{}{
+	ast.WindowFuncRowNumber:   {},
+	ast.WindowFuncRank:        {},
+	ast.WindowFuncDenseRank:   {},
+	ast.WindowFuncCumeDist:    {},
+	ast.WindowFuncPercentRank: {},
+	ast.WindowFuncNtile:       {},
+	ast.WindowFuncLead:        {},
+	ast.WindowFuncLag:         {},
+}
+
+// NeedFrame checks whether the window function is evaluated on the frame of the row.
+func NeedFrame(name string) bool {
+	_, ok := noFrameWindowFuncs[strings.ToLower(name)]
+	return !ok
+}
+
+// NewWindowFuncDesc creates a window function signature descriptor, the aggregate functions are typed as
+// they're in the aggregations.
+func NewWindowFuncDesc(ctx sessionctx.Context, name string, args []expression.Expression, hasDistinct bool) (*WindowFuncDesc, error) {
+	name = strings.ToLower(name)
+	w := &WindowFuncDesc{Name: name, Args: args}
+	switch name {
+	case ast.WindowFuncRowNumber, ast.WindowFuncRank, ast.WindowFuncDenseRank:
+		w.typeInfer4Int()
+	case ast.WindowFuncCumeDist, ast.WindowFuncPercentRank:
+		w.RetTp = types.NewFieldType(mysql.TypeDouble)
+		w.RetTp.Flen, w.RetTp.Decimal = mysql.MaxRealWidth, types.UnspecifiedLength
+		w.RetTp.Flag |= mysql.NotNullFlag
+		types.SetBinChsClnFlag(w.RetTp)
+	case ast.WindowFuncNtile:
+		if err := w.checkNumArg(ctx, 0, 1); err != nil {
+			return nil, err
+		}
+		w.typeInfer4Int()
+	case ast.WindowFuncLead, ast.WindowFuncLag:
+		if len(w.Args) == 1 {
+			w.Args = append(w.Args, expression.NewOne())
+		}
+		if err := w.checkNumArg(ctx, 1, 0); err != nil {
+			return nil, err
+		}
+		if len(w.Args) == 2 {
+			w.Args = append(w.Args, expression.NewNull())
+		}
+		w.RetTp = expression.InferType4ControlFuncs(w.Args[0], w.Args[2])
+		w.RetTp.Flag &^= mysql.NotNullFlag
+		for _, i := range []int{0, 2} {
+			if !w.Args[i].GetType().Equal(w.RetTp) {
+				w.Args[i] = expression.BuildCastFunction(ctx, w.Args[i], w.RetTp)
+			}
+		}
+	case ast.WindowFuncFirstValue, ast.WindowFuncLastValue, ast.WindowFuncNthValue:
+		if name == ast.WindowFuncNthValue {
+			if err := w.checkNumArg(ctx, 1, 1); err != nil {
+				return nil, err
+			}
+		}
+		// The value is NULL if the frame is empty.
+		w.RetTp = w.Args[0].GetType().Clone()
+		w.RetTp.Flag &^= mysql.NotNullFlag
+	case ast.AggFuncGroupConcat:
+		return nil, expression.ErrNotSupportedYet.GenWithStackByArgs("GROUP_CONCAT as window function")
+	default:
+		if hasDistinct {
+			return nil, expression.ErrNotSupportedYet.GenWithStackByArgs("<window function>(DISTINCT ..)")
+		}
+		aggDesc, err := NewAggFuncDesc(ctx, name, args, false)
+		if err != nil {
+			return nil, err
+		}
+		w.Name, w.Args, w.RetTp = aggDesc.Name, aggDesc.Args, aggDesc.RetTp
+	}
+	return w, nil
+}
+
+func (w *WindowFuncDesc) typeInfer4Int() {
+	w.RetTp = types.NewFieldType(mysql.TypeLonglong)
+	w.RetTp.Flen, w.RetTp.Decimal = mysql.MaxIntWidth, 0
+	w.RetTp.Flag |= mysql.NotNullFlag
+	types.SetBinChsClnFlag(w.RetTp)
+}
+
+// checkNumArg checks the argument of the position is a constant integer which is not less than min, like
+// the N of NTILE.
+func (w *WindowFuncDesc) checkNumArg(ctx sessionctx.Context, pos int, min int64) error {
+	if len(w.Args) <= pos {
+		return expression.ErrIncorrectParameterCount.GenWithStackByArgs(w.Name)
+	}
+	con, ok := expression.FoldConstant(w.Args[pos]).(*expression.Constant)
+	if !ok || con.GetType().EvalType() != types.ETInt {
+		return expression.ErrIncorrectArguments.GenWithStackByArgs(w.Name)
+	}
+	n, isNull, err := con.EvalInt(ctx, chunk.Row{})
+	if err != nil {
+		return err
+	}
+	if isNull || n < min || (mysql.HasUnsignedFlag(con.GetType().Flag) && n < 0) {
+		return expression.ErrIncorrectArguments.GenWithStackByArgs(w.Name)
+	}
+	w.Args[pos] = con
+	return nil
+}
+
+// String implements the fmt.Stringer interface.
+func (w *WindowFuncDesc) String() string {
+	var buffer bytes.Buffer
+	buffer.WriteString(w.Name + "(")
+	for i, arg := range w.Args {
+		if i > 0 {
+			buffer.WriteString(", ")
+		}
+		buffer.WriteString(arg.String())
+	}
+	buffer.WriteString(")")
+	return buffer.String()
+}
@@ -0,0 +1,377 @@
+package expression
+
+import (
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/charset"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/sessionctx"
+	"grant-db/types"
+	"grant-db/types/json"
+	"grant-db/util/chunk"
+	"grant-db/util/collate"
+)
+
+// baseBuiltinFunc will be contained in every struct that implement builtinFunc interface.
+type baseBuiltinFunc struct {
+	bufAllocator columnBufferAllocator
+	args         []Expression
+	ctx          sessionctx.Context
+	tp           *types.FieldType
+	// collation is derived from the arguments, by which the strings are compared.
+	collation string
+}
+
+func newBaseBuiltinFunc(ctx sessionctx.Context, args []Expression) baseBuiltinFunc {
+	if ctx == nil {
+		panic("ctx should not be nil")
+	}
+	_, collation := DeriveCollationFromExprs(ctx, args...)
+	return baseBuiltinFunc{
+		bufAllocator: newLocalSliceBuffer(),
+		args:         args,
+		ctx:          ctx,
+		tp:           types.NewFieldType(mysql.TypeUnspecified),
+		collation:    collation,
+	}
+}
+
+// newBaseBuiltinFuncWithTp creates a built-in function signature with specified types of arguments and the return type of the function.
+// argTps indicates the types of the args, retType indicates the return type of the built-in function.
+// Every built-in function needs determined argTps and retType when we create it, the arguments of
+// other types are wrapped with a cast.
+func newBaseBuiltinFuncWithTp(ctx sessionctx.Context, args []Expression, retType types.EvalType, argTps ...types.EvalType) baseBuiltinFunc {
+	if len(args) != len(argTps) {
+		panic("unexpected length of args and argTps")
+	}
+	if ctx == nil {
+		panic("ctx should not be nil")
+	}
+	// The collation is derived before the arguments are wrapped with casts.
+	derivedCharset, derivedCollation := DeriveCollationFromExprs(ctx, args...)
+	for i := range args {
+		switch argTps[i] {
+		case types.ETInt:
+			args[i] = WrapWithCastAsInt(ctx, args[i])
+		case types.ETReal:
+			args[i] = WrapWithCastAsReal(ctx, args[i])
+		case types.ETDecimal:
+			args[i] = WrapWithCastAsDecimal(ctx, args[i])
+		case types.ETString:
+			args[i] = WrapWithCastAsString(ctx, args[i])
+		case types.ETDatetime:
+			args[i] = WrapWithCastAsTime(ctx, args[i], types.NewFieldType(mysql.TypeDatetime))
+		case types.ETTimestamp:
+			args[i] = WrapWithCastAsTime(ctx, args[i], types.NewFieldType(mysql.TypeTimestamp))
+		case types.ETJson:
+			args[i] = WrapWithCastAsJSON(ctx, args[i])
+		}
+	}
+	var fieldType *types.FieldType
+	switch retType {
+	case types.ETInt:
+		fieldType = &types.FieldType{
+			Tp:      mysql.TypeLonglong,
+			Flen:    mysql.MaxIntWidth,
+			Decimal: 0,
+			Flag:    mysql.BinaryFlag,
+		}
+	case types.ETReal:
+		fieldType = &types.FieldType{
+			Tp:      mysql.TypeDouble,
+			Flen:    mysql.MaxRealWidth,
+			Decimal: types.UnspecifiedLength,
+			Flag:    mysql.BinaryFlag,
+		}
+	case types.ETDecimal:
+		fieldType = &types.FieldType{
+			Tp:      mysql.TypeNewDecimal,
+			Flen:    11,
+			Decimal: 0,
+			Flag:    mysql.BinaryFlag,
+		}
+	case types.ETString:
+		fieldType = &types.FieldType{
+			Tp:      mysql.TypeVarString,
+			Decimal: types.UnspecifiedLength,
+			Charset: derivedCharset,
+			Collate: derivedCollation,
+			Flen:    types.UnspecifiedLength,
+		}
+		if derivedCollation == charset.CollationBin {
+			fieldType.Flag |= mysql.BinaryFlag
+		}
+	case types.ETDatetime:
+		fieldType = &types.FieldType{
+			Tp:      mysql.TypeDatetime,
+			Flen:    mysql.MaxDatetimeWidthWithFsp,
+			Decimal: int(types.MaxFsp),
+			Flag:    mysql.BinaryFlag,
+		}
+	case types.ETTimestamp:
+		fieldType = &types.FieldType{
+			Tp:      mysql.TypeTimestamp,
+			Flen:    mysql.MaxDatetimeWidthWithFsp,
+			Decimal: int(types.MaxFsp),
+			Flag:    mysql.BinaryFlag,
+		}
+	case types.ETJson:
+		fieldType = &types.FieldType{
+			Tp:      mysql.TypeJSON,
+			Flen:    mysql.MaxBlobWidth,
+			Decimal: 0,
+			Flag:    mysql.BinaryFlag,
+		}
+	}
+	if mysql.HasBinaryFlag(fieldType.Flag) {
+		fieldType.Charset, fieldType.Collate = charset.CharsetBin, charset.CollationBin
+	}
+	return baseBuiltinFunc{
+		bufAllocator: newLocalSliceBuffer(),
+		args:         args,
+		ctx:          ctx,
+		tp:           fieldType,
+		collation:    derivedCollation,
+	}
+}
+
+// collator returns the collator of the derived collation of the arguments.
+func (b *baseBuiltinFunc) collator() collate.Collator {
+	return collate.GetCollator(b.collation)
+}
+
+func (b *baseBuiltinFunc) getArgs() []Expression {
+	return b.args
+}
+
+func (b *baseBuiltinFunc) evalInt(row chunk.Row) (int64, bool, error) {
+	return 0, false, errors.Errorf("baseBuiltinFunc.evalInt() should never be called")
+}
+
+func (b *baseBuiltinFunc) evalReal(row chunk.Row) (float64, bool, error) {
+	return 0, false, errors.Errorf("baseBuiltinFunc.evalReal() should never be called")
+}
+
+func (b *baseBuiltinFunc) evalString(row chunk.Row) (string, bool, error) {
+	return "", false, errors.Errorf("baseBuiltinFunc.evalString() should never be called")
+}
+
+func (b *baseBuiltinFunc) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	return nil, false, errors.Errorf("baseBuiltinFunc.evalDecimal() should never be called")
+}
+
+func (b *baseBuiltinFunc) evalTime(row chunk.Row) (types.Time, bool, error) {
+	return types.ZeroDatetime, false, errors.Errorf("baseBuiltinFunc.evalTime() should never be called")
+}
+
+func (b *baseBuiltinFunc) evalJSON(row chunk.Row) (json.BinaryJSON, bool, error) {
+	return json.BinaryJSON{}, false, errors.Errorf("baseBuiltinFunc.evalJSON() should never be called")
+}
+
+func (b *baseBuiltinFunc) getRetTp() *types.FieldType {
+	if b.tp.EvalType() == types.ETString {
+		if b.tp.Flen >= mysql.MaxBlobWidth {
+			b.tp.Tp = mysql.TypeLongBlob
+		} else if b.tp.Flen >= 65536 {
+			b.tp.Tp = mysql.TypeMediumBlob
+		}
+		if len(b.tp.Charset) <= 0 {
+			b.tp.Charset, b.tp.Collate = charset.GetDefaultCharsetAndCollate()
+		}
+	}
+	return b.tp
+}
+
+func (b *baseBuiltinFunc) equal(fun builtinFunc) bool {
+	funArgs := fun.getArgs()
+	if len(funArgs) != len(b.args) {
+		return false
+	}
+	for i := range b.args {
+		if !b.args[i].Equal(b.ctx, funArgs[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func (b *baseBuiltinFunc) getCtx() sessionctx.Context {
+	return b.ctx
+}
+
+func (b *baseBuiltinFunc) cloneFrom(from *baseBuiltinFunc) {
+	b.args = make([]Expression, 0, len(from.args))
+	for _, arg := range from.args {
+		b.args = append(b.args, arg.Clone())
+	}
+	b.ctx = from.ctx
+	b.tp = from.tp
+	b.collation = from.collation
+	b.bufAllocator = newLocalSliceBuffer()
+}
+
+// vecBuiltinFunc contains all vectorized methods for a builtin function.
+type vecBuiltinFunc interface {
+	// vectorized returns if this builtin function itself supports vectorized evaluation.
+	vectorized() bool
+	// vecEvalInt evaluates this builtin function in a vectorized manner.
+	vecEvalInt(input *chunk.Chunk, result *chunk.Column) error
+	// vecEvalReal evaluates this builtin function in a vectorized manner.
+	vecEvalReal(input *chunk.Chunk, result *chunk.Column) error
+	// vecEvalString evaluates this builtin function in a vectorized manner.
+	vecEvalString(input *chunk.Chunk, result *chunk.Column) error
+	// vecEvalDecimal evaluates this builtin function in a vectorized manner.
+	vecEvalDecimal(input *chunk.Chunk, result *chunk.Column) error
+	// vecEvalTime evaluates this builtin function in a vectorized manner.
+	vecEvalTime(input *chunk.Chunk, result *chunk.Column) error
+	// vecEvalJSON evaluates this builtin function in a vectorized manner.
+	vecEvalJSON(input *chunk.Chunk, result *chunk.Column) error
+}
+
+// builtinFunc stands for a particular function signature.
+type builtinFunc interface {
+	vecBuiltinFunc
+
+	// evalInt evaluates int result of builtinFunc by given row.
+	evalInt(row chunk.Row) (val int64, isNull bool, err error)
+	// evalReal evaluates real representation of builtinFunc by given row.
+	evalReal(row chunk.Row) (val float64, isNull bool, err error)
+	// evalString evaluates string representation of builtinFunc by given row.
+	evalString(row chunk.Row) (val string, isNull bool, err error)
+	// evalDecimal evaluates decimal representation of builtinFunc by given row.
+	evalDecimal(row chunk.Row) (val *types.MyDecimal, isNull bool, err error)
+	// evalTime evaluates DATE/DATETIME/TIMESTAMP representation of builtinFunc by given row.
+	evalTime(row chunk.Row) (val types.Time, isNull bool, err error)
+	// evalJSON evaluates JSON representation of builtinFunc by given row.
+	evalJSON(row chunk.Row) (val json.BinaryJSON, isNull bool, err error)
+	// getArgs returns the arguments expressions.
+	getArgs() []Expression
+	// equal check if this function equals to another function.
+	equal(builtinFunc) bool
+	// getCtx returns this function's context.
+	getCtx() sessionctx.Context
+	// getRetTp returns the return type of the built-in function.
+	getRetTp() *types.FieldType
+	// Clone returns a copy of itself.
+	Clone() builtinFunc
+}
+
+// baseFunctionClass will be contained in every struct that implement functionClass interface.
+type baseFunctionClass struct {
+	funcName string
+	minArgs  int
+	maxArgs  int
+}
+
+func (b *baseFunctionClass) verifyArgs(args []Expression) error {
+	l := len(args)
+	if l < b.minArgs || (b.maxArgs != -1 && l > b.maxArgs) {
+		return ErrIncorrectParameterCount.GenWithStackByArgs(b.funcName)
+	}
+	return nil
+}
+
+// functionClass is the interface for a function which may contains multiple functions.
+type functionClass interface {
+	// getFunction gets a function signature by the types and the counts of given arguments.
+	getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error)
+}
+
+// funcs holds all registered builtin functions.
+var funcs = map[string]functionClass{
+	// common functions
+	ast.IsNull: &isNullFunctionClass{baseFunctionClass{ast.IsNull, 1, 1}},
+
+	// op functions
+	ast.LogicAnd:   &logicAndFunctionClass{baseFunctionClass{ast.LogicAnd, 2, 2}},
+	ast.LogicOr:    &logicOrFunctionClass{baseFunctionClass{ast.LogicOr, 2, 2}},
+	ast.LogicXor:   &logicXorFunctionClass{baseFunctionClass{ast.LogicXor, 2, 2}},
+	ast.UnaryNot:   &unaryNotFunctionClass{baseFunctionClass{ast.UnaryNot, 1, 1}},
+	ast.UnaryMinus: &unaryMinusFunctionClass{baseFunctionClass{ast.UnaryMinus, 1, 1}},
+	ast.IsTruth:    &isTrueOrFalseFunctionClass{baseFunctionClass{ast.IsTruth, 1, 1}, true},
+	ast.IsFalsity:  &isTrueOrFalseFunctionClass{baseFunctionClass{ast.IsFalsity, 1, 1}, false},
+	ast.And:        &bitAndFunctionClass{baseFunctionClass{ast.And, 2, 2}},
+	ast.Or:         &bitOrFunctionClass{baseFunctionClass{ast.Or, 2, 2}},
+	ast.Xor:        &bitXorFunctionClass{baseFunctionClass{ast.Xor, 2, 2}},
+	ast.BitNeg:     &bitNegFunctionClass{baseFunctionClass{ast.BitNeg, 1, 1}},
+	ast.LeftShift:  &leftShiftFunctionClass{baseFunctionClass{ast.LeftShift, 2, 2}},
+	ast.RightShift: &rightShiftFunctionClass{baseFunctionClass{ast.RightShift, 2, 2}},
+
+	// arithmetic functions
+	ast.Plus:   &arithmeticPlusFunctionClass{baseFunctionClass{ast.Plus, 2, 2}},
+	ast.Minus:  &arithmeticMinusFunctionClass{baseFunctionClass{ast.Minus, 2, 2}},
+	ast.Mul:    &arithmeticMultiplyFunctionClass{baseFunctionClass{ast.Mul, 2, 2}},
+	ast.Div:    &arithmeticDivideFunctionClass{baseFunctionClass{ast.Div, 2, 2}},
+	ast.IntDiv: &arithmeticIntDivideFunctionClass{baseFunctionClass{ast.IntDiv, 2, 2}},
+	ast.Mod:    &arithmeticModFunctionClass{baseFunctionClass{ast.Mod, 2, 2}},
+
+	// compare functions
+	ast.LT:     &compareFunctionClass{baseFunctionClass{ast.LT, 2, 2}},
+	ast.LE:     &compareFunctionClass{baseFunctionClass{ast.LE, 2, 2}},
+	ast.GT:     &compareFunctionClass{baseFunctionClass{ast.GT, 2, 2}},
+	ast.GE:     &compareFunctionClass{baseFunctionClass{ast.GE, 2, 2}},
+	ast.EQ:     &compareFunctionClass{baseFunctionClass{ast.EQ, 2, 2}},
+	ast.NE:     &compareFunctionClass{baseFunctionClass{ast.NE, 2, 2}},
+	ast.NullEQ: &compareFunctionClass{baseFunctionClass{ast.NullEQ, 2, 2}},
+	ast.In:     &inFunctionClass{baseFunctionClass{ast.In, 1, -1}},
+
+	// like functions
+	ast.Like: &likeFunctionClass{baseFunctionClass{ast.Like, 3, 3}},
+
+	// control functions
+	ast.Case:     &caseWhenFunctionClass{baseFunctionClass{ast.Case, 1, -1}},
+	ast.If:       &ifFunctionClass{baseFunctionClass{ast.If, 3, 3}},
+	ast.Ifnull:   &coalesceFunctionClass{baseFunctionClass{ast.Ifnull, 2, 2}},
+	ast.Coalesce: &coalesceFunctionClass{baseFunctionClass{ast.Coalesce, 1, -1}},
+
+	// string functions
+	ast.Concat:    &concatFunctionClass{baseFunctionClass{ast.Concat, 1, -1}},
+	ast.Substring: &substringFunctionClass{baseFunctionClass{ast.Substring, 2, 3}},
+	ast.Substr:    &substringFunctionClass{baseFunctionClass{ast.Substr, 2, 3}},
+	ast.Replace:   &replaceFunctionClass{baseFunctionClass{ast.Replace, 3, 3}},
+	ast.Lpad:      &padFunctionClass{baseFunctionClass{ast.Lpad, 3, 3}, true},
+	ast.Rpad:      &padFunctionClass{baseFunctionClass{ast.Rpad, 3, 3}, false},
+	ast.Lower:     &changeCaseFunctionClass{baseFunctionClass{ast.Lower, 1, 1}, false},
+	ast.Lcase:     &changeCaseFunctionClass{baseFunctionClass{ast.Lcase, 1, 1}, false},
+	ast.Upper:     &changeCaseFunctionClass{baseFunctionClass{ast.Upper, 1, 1}, true},
+	ast.Ucase:     &changeCaseFunctionClass{baseFunctionClass{ast.Ucase, 1, 1}, true},
+
+	// math functions
+	ast.Abs:     &absFunctionClass{baseFunctionClass{ast.Abs, 1, 1}},
+	ast.Round:   &roundFunctionClass{baseFunctionClass{ast.Round, 1, 2}},
+	ast.Floor:   &floorCeilFunctionClass{baseFunctionClass{ast.Floor, 1, 1}, false},
+	ast.Ceil:    &floorCeilFunctionClass{baseFunctionClass{ast.Ceil, 1, 1}, true},
+	ast.Ceiling: &floorCeilFunctionClass{baseFunctionClass{ast.Ceiling, 1, 1}, true},
+
+	// time functions
+	ast.Now:              &nowFunctionClass{baseFunctionClass{ast.Now, 0, 1}},
+	ast.CurrentTimestamp: &nowFunctionClass{baseFunctionClass{ast.CurrentTimestamp, 0, 1}},
+	ast.DateFormat:       &dateFormatFunctionClass{baseFunctionClass{ast.DateFormat, 2, 2}},
+	ast.DateAdd:          &addSubDateFunctionClass{baseFunctionClass{ast.DateAdd, 3, 3}, false},
+	ast.AddDate:          &addSubDateFunctionClass{baseFunctionClass{ast.AddDate, 3, 3}, false},
+	ast.DateSub:          &addSubDateFunctionClass{baseFunctionClass{ast.DateSub, 3, 3}, true},
+	ast.SubDate:          &addSubDateFunctionClass{baseFunctionClass{ast.SubDate, 3, 3}, true},
+	ast.DateDiff:         &dateDiffFunctionClass{baseFunctionClass{ast.DateDiff, 2, 2}},
+	ast.UnixTimestamp:    &unixTimestampFunctionClass{baseFunctionClass{ast.UnixTimestamp, 0, 1}},
+
+	// json functions
+	ast.JSONType:     &jsonTypeFunctionClass{baseFunctionClass{ast.JSONType, 1, 1}},
+	ast.JSONExtract:  &jsonExtractFunctionClass{baseFunctionClass{ast.JSONExtract, 2, -1}},
+	ast.JSONUnquote:  &jsonUnquoteFunctionClass{baseFunctionClass{ast.JSONUnquote, 1, 1}},
+	ast.JSONSet:      &jsonModifyFunctionClass{baseFunctionClass{ast.JSONSet, 3, -1}, json.ModifySet},
+	ast.JSONInsert:   &jsonModifyFunctionClass{baseFunctionClass{ast.JSONInsert, 3, -1}, json.ModifyInsert},
+	ast.JSONReplace:  &jsonModifyFunctionClass{baseFunctionClass{ast.JSONReplace, 3, -1}, json.ModifyReplace},
+	ast.JSONRemove:   &jsonRemoveFunctionClass{baseFunctionClass{ast.JSONRemove, 2, -1}},
+	ast.JSONContains: &jsonContainsFunctionClass{baseFunctionClass{ast.JSONContains, 2, 3}},
+	ast.JSONArray:    &jsonArrayFunctionClass{baseFunctionClass{ast.JSONArray, 0, -1}},
+	ast.JSONObject:   &jsonObjectFunctionClass{baseFunctionClass{ast.JSONObject, 0, -1}},
+
+	// information functions
+	ast.LastInsertId: &lastInsertIDFunctionClass{baseFunctionClass{ast.LastInsertId, 0, 1}},
+}
+
+// IsFunctionSupported check if given function name is a builtin sql function.
+func IsFunctionSupported(name string) bool {
+	_, ok := funcs[name]
+	return ok
+}
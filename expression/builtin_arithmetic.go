@@ -0,0 +1,857 @@
+package expression
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+
+	"github.com/pingcap/parser/mysql"
+	"grant-db/sessionctx"
+	"grant-db/types"
+	"grant-db/util/chunk"
+)
+
+// numericContextResultType returns types.EvalType for numeric function's parameter.
+// the returned types.EvalType should be one of: types.ETInt, types.ETDecimal, types.ETReal
+func numericContextResultType(ft *types.FieldType) types.EvalType {
+	if types.IsTypeTemporal(ft.Tp) {
+		if ft.Decimal > 0 {
+			return types.ETDecimal
+		}
+		return types.ETInt
+	}
+	evalTp4Ft := ft.EvalType()
+	if evalTp4Ft != types.ETDecimal && evalTp4Ft != types.ETInt {
+		evalTp4Ft = types.ETReal
+	}
+	return evalTp4Ft
+}
+
+// setFlenDecimal4Int is called to set proper `Flen` and `Decimal` of return
+// type according to the two input parameter's types.
+func setFlenDecimal4Int(retTp, a, b *types.FieldType) {
+	retTp.Decimal = 0
+	retTp.Flen = mysql.MaxIntWidth
+}
+
+// setFlenDecimal4RealOrDecimal is called to set proper `Flen` and `Decimal` of return
+// type according to the two input parameter's types.
+func setFlenDecimal4RealOrDecimal(retTp, a, b *types.FieldType, isReal bool, isMultiply bool) {
+	if a.Decimal != types.UnspecifiedLength && b.Decimal != types.UnspecifiedLength {
+		retTp.Decimal = a.Decimal + b.Decimal
+		if !isMultiply {
+			retTp.Decimal = maxInt(a.Decimal, b.Decimal)
+		}
+		if !isReal && retTp.Decimal > types.MaxDecimalScale {
+			retTp.Decimal = types.MaxDecimalScale
+		}
+		if a.Flen == types.UnspecifiedLength || b.Flen == types.UnspecifiedLength {
+			retTp.Flen = types.UnspecifiedLength
+			return
+		}
+		digitsInt := maxInt(a.Flen-a.Decimal, b.Flen-b.Decimal)
+		if isMultiply {
+			digitsInt = a.Flen - a.Decimal + b.Flen - b.Decimal
+		}
+		retTp.Flen = digitsInt + retTp.Decimal + 3
+		if isReal {
+			retTp.Flen = minInt(retTp.Flen, mysql.MaxRealWidth)
+			return
+		}
+		retTp.Flen = minInt(retTp.Flen, mysql.MaxDecimalWidth)
+		return
+	}
+	if isReal {
+		retTp.Flen, retTp.Decimal = types.UnspecifiedLength, types.UnspecifiedLength
+	} else {
+		retTp.Flen, retTp.Decimal = mysql.MaxDecimalWidth, mysql.MaxDecimalScale
+	}
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// setUnsignedFlag4Int sets the unsigned flag of the int result if any of its arguments is unsigned.
+func setUnsignedFlag4Int(retTp, a, b *types.FieldType) {
+	if mysql.HasUnsignedFlag(a.Flag) || mysql.HasUnsignedFlag(b.Flag) {
+		retTp.Flag |= mysql.UnsignedFlag
+	}
+}
+
+// outOfRangeErr returns the error of a result out of the range of tp, like "BIGINT value is out of range in '(a + b)'".
+func outOfRangeErr(tp string, op string, args []Expression) error {
+	return types.ErrDataOutOfRange.GenWithStackByArgs(tp, fmt.Sprintf("(%s %s %s)", args[0].String(), op, args[1].String()))
+}
+
+func intOutOfRangeErr(unsigned bool, op string, args []Expression) error {
+	if unsigned {
+		return outOfRangeErr("BIGINT UNSIGNED", op, args)
+	}
+	return outOfRangeErr("BIGINT", op, args)
+}
+
+// handleDivisionByZeroError handles the division by zero, whose result is NULL. It's silent unless
+// ERROR_FOR_DIVISION_BY_ZERO is in the sql_mode, then it's a warning when the statement allows it
+// or an error otherwise.
+func handleDivisionByZeroError(ctx sessionctx.Context) error {
+	vars := ctx.GetSessionVars()
+	if !vars.SQLMode.HasErrorForDivisionByZeroMode() {
+		return nil
+	}
+	sc := vars.StmtCtx
+	if !sc.DividedByZeroAsWarning {
+		return ErrDivisionByZero
+	}
+	sc.AppendWarning(ErrDivisionByZero)
+	return nil
+}
+
+// evalDecimalArgs evaluates the two decimal arguments of b, the second one is only evaluated when the first one is not NULL.
+func (b *baseBuiltinFunc) evalDecimalArgs(row chunk.Row) (arg0, arg1 *types.MyDecimal, isNull bool, err error) {
+	arg0, isNull, err = b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return nil, nil, true, err
+	}
+	arg1, isNull, err = b.args[1].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return nil, nil, true, err
+	}
+	return arg0, arg1, false, nil
+}
+
+type arithmeticPlusFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *arithmeticPlusFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	lhsTp, rhsTp := args[0].GetType(), args[1].GetType()
+	lhsEvalTp, rhsEvalTp := numericContextResultType(lhsTp), numericContextResultType(rhsTp)
+	if lhsEvalTp == types.ETReal || rhsEvalTp == types.ETReal {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETReal, types.ETReal, types.ETReal)
+		setFlenDecimal4RealOrDecimal(bf.tp, args[0].GetType(), args[1].GetType(), true, false)
+		return &builtinArithmeticPlusRealSig{bf}, nil
+	} else if lhsEvalTp == types.ETDecimal || rhsEvalTp == types.ETDecimal {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDecimal, types.ETDecimal, types.ETDecimal)
+		setFlenDecimal4RealOrDecimal(bf.tp, args[0].GetType(), args[1].GetType(), false, false)
+		return &builtinArithmeticPlusDecimalSig{bf}, nil
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETInt, types.ETInt)
+	setUnsignedFlag4Int(bf.tp, args[0].GetType(), args[1].GetType())
+	setFlenDecimal4Int(bf.tp, args[0].GetType(), args[1].GetType())
+	return &builtinArithmeticPlusIntSig{bf}, nil
+}
+
+type builtinArithmeticPlusIntSig struct {
+	baseBuiltinFunc
+}
+
+func (s *builtinArithmeticPlusIntSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticPlusIntSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticPlusIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	a, b, isNull, err := s.evalIntArgs(row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	isLHSUnsigned := mysql.HasUnsignedFlag(s.args[0].GetType().Flag)
+	isRHSUnsigned := mysql.HasUnsignedFlag(s.args[1].GetType().Flag)
+	res, overflow := plusInt(a, b, isLHSUnsigned, isRHSUnsigned)
+	if overflow {
+		return 0, true, intOutOfRangeErr(isLHSUnsigned || isRHSUnsigned, "+", s.args)
+	}
+	return res, false, nil
+}
+
+// plusInt returns a + b of the integers with the unsigned flags, the second returned value reports
+// whether the result overflows.
+func plusInt(a, b int64, isLHSUnsigned, isRHSUnsigned bool) (int64, bool) {
+	switch {
+	case isLHSUnsigned && isRHSUnsigned:
+		if uint64(a) > math.MaxUint64-uint64(b) {
+			return 0, true
+		}
+	case isLHSUnsigned && !isRHSUnsigned:
+		if b < 0 && uint64(-b) > uint64(a) {
+			return 0, true
+		}
+		if b > 0 && uint64(a) > math.MaxUint64-uint64(b) {
+			return 0, true
+		}
+	case !isLHSUnsigned && isRHSUnsigned:
+		if a < 0 && uint64(-a) > uint64(b) {
+			return 0, true
+		}
+		if a > 0 && uint64(b) > math.MaxUint64-uint64(a) {
+			return 0, true
+		}
+	default:
+		if (a > 0 && b > math.MaxInt64-a) || (a < 0 && b < math.MinInt64-a) {
+			return 0, true
+		}
+	}
+	return a + b, false
+}
+
+type builtinArithmeticPlusDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (s *builtinArithmeticPlusDecimalSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticPlusDecimalSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticPlusDecimalSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	a, b, isNull, err := s.evalDecimalArgs(row)
+	if isNull || err != nil {
+		return nil, true, err
+	}
+	return s.plus(a, b)
+}
+
+func (s *builtinArithmeticPlusDecimalSig) plus(a, b *types.MyDecimal) (*types.MyDecimal, bool, error) {
+	c := &types.MyDecimal{}
+	if err := types.DecimalAdd(a, b, c); err != nil {
+		if err == types.ErrOverflow {
+			err = outOfRangeErr("DECIMAL", "+", s.args)
+		}
+		return nil, true, err
+	}
+	return c, false, nil
+}
+
+type builtinArithmeticPlusRealSig struct {
+	baseBuiltinFunc
+}
+
+func (s *builtinArithmeticPlusRealSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticPlusRealSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticPlusRealSig) evalReal(row chunk.Row) (float64, bool, error) {
+	a, isNull, err := s.args[0].EvalReal(s.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	b, isNull, err := s.args[1].EvalReal(s.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if math.IsInf(a+b, 0) {
+		return 0, true, outOfRangeErr("DOUBLE", "+", s.args)
+	}
+	return a + b, false, nil
+}
+
+type arithmeticMinusFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *arithmeticMinusFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	lhsTp, rhsTp := args[0].GetType(), args[1].GetType()
+	lhsEvalTp, rhsEvalTp := numericContextResultType(lhsTp), numericContextResultType(rhsTp)
+	if lhsEvalTp == types.ETReal || rhsEvalTp == types.ETReal {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETReal, types.ETReal, types.ETReal)
+		setFlenDecimal4RealOrDecimal(bf.tp, args[0].GetType(), args[1].GetType(), true, false)
+		return &builtinArithmeticMinusRealSig{bf}, nil
+	} else if lhsEvalTp == types.ETDecimal || rhsEvalTp == types.ETDecimal {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDecimal, types.ETDecimal, types.ETDecimal)
+		setFlenDecimal4RealOrDecimal(bf.tp, args[0].GetType(), args[1].GetType(), false, false)
+		return &builtinArithmeticMinusDecimalSig{bf}, nil
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETInt, types.ETInt)
+	setUnsignedFlag4Int(bf.tp, args[0].GetType(), args[1].GetType())
+	setFlenDecimal4Int(bf.tp, args[0].GetType(), args[1].GetType())
+	return &builtinArithmeticMinusIntSig{bf}, nil
+}
+
+type builtinArithmeticMinusRealSig struct {
+	baseBuiltinFunc
+}
+
+func (s *builtinArithmeticMinusRealSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticMinusRealSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticMinusRealSig) evalReal(row chunk.Row) (float64, bool, error) {
+	a, isNull, err := s.args[0].EvalReal(s.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	b, isNull, err := s.args[1].EvalReal(s.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if math.IsInf(a-b, 0) {
+		return 0, true, outOfRangeErr("DOUBLE", "-", s.args)
+	}
+	return a - b, false, nil
+}
+
+type builtinArithmeticMinusDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (s *builtinArithmeticMinusDecimalSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticMinusDecimalSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticMinusDecimalSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	a, b, isNull, err := s.evalDecimalArgs(row)
+	if isNull || err != nil {
+		return nil, true, err
+	}
+	return s.minus(a, b)
+}
+
+func (s *builtinArithmeticMinusDecimalSig) minus(a, b *types.MyDecimal) (*types.MyDecimal, bool, error) {
+	c := &types.MyDecimal{}
+	if err := types.DecimalSub(a, b, c); err != nil {
+		if err == types.ErrOverflow {
+			err = outOfRangeErr("DECIMAL", "-", s.args)
+		}
+		return nil, true, err
+	}
+	return c, false, nil
+}
+
+type builtinArithmeticMinusIntSig struct {
+	baseBuiltinFunc
+}
+
+func (s *builtinArithmeticMinusIntSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticMinusIntSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticMinusIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	a, b, isNull, err := s.evalIntArgs(row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	isLHSUnsigned := mysql.HasUnsignedFlag(s.args[0].GetType().Flag)
+	isRHSUnsigned := mysql.HasUnsignedFlag(s.args[1].GetType().Flag)
+	res, overflow := minusInt(a, b, isLHSUnsigned, isRHSUnsigned)
+	if overflow {
+		return 0, true, intOutOfRangeErr(isLHSUnsigned || isRHSUnsigned, "-", s.args)
+	}
+	return res, false, nil
+}
+
+// minusInt returns a - b of the integers with the unsigned flags, the second returned value reports
+// whether the result overflows.
+func minusInt(a, b int64, isLHSUnsigned, isRHSUnsigned bool) (int64, bool) {
+	switch {
+	case isLHSUnsigned && isRHSUnsigned:
+		if uint64(a) < uint64(b) {
+			return 0, true
+		}
+	case isLHSUnsigned && !isRHSUnsigned:
+		if b >= 0 && uint64(a) < uint64(b) {
+			return 0, true
+		}
+		if b < 0 && uint64(a) > math.MaxUint64-uint64(-b) {
+			return 0, true
+		}
+	case !isLHSUnsigned && isRHSUnsigned:
+		if a < 0 || uint64(a) < uint64(b) {
+			return 0, true
+		}
+	default:
+		if (a >= 0 && b < 0 && a > math.MaxInt64+b) || (a < 0 && b > 0 && a < math.MinInt64+b) {
+			return 0, true
+		}
+	}
+	return a - b, false
+}
+
+type arithmeticMultiplyFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *arithmeticMultiplyFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	lhsTp, rhsTp := args[0].GetType(), args[1].GetType()
+	lhsEvalTp, rhsEvalTp := numericContextResultType(lhsTp), numericContextResultType(rhsTp)
+	if lhsEvalTp == types.ETReal || rhsEvalTp == types.ETReal {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETReal, types.ETReal, types.ETReal)
+		setFlenDecimal4RealOrDecimal(bf.tp, args[0].GetType(), args[1].GetType(), true, true)
+		return &builtinArithmeticMultiplyRealSig{bf}, nil
+	} else if lhsEvalTp == types.ETDecimal || rhsEvalTp == types.ETDecimal {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDecimal, types.ETDecimal, types.ETDecimal)
+		setFlenDecimal4RealOrDecimal(bf.tp, args[0].GetType(), args[1].GetType(), false, true)
+		return &builtinArithmeticMultiplyDecimalSig{bf}, nil
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETInt, types.ETInt)
+	setUnsignedFlag4Int(bf.tp, args[0].GetType(), args[1].GetType())
+	setFlenDecimal4Int(bf.tp, args[0].GetType(), args[1].GetType())
+	return &builtinArithmeticMultiplyIntSig{bf}, nil
+}
+
+type builtinArithmeticMultiplyRealSig struct{ baseBuiltinFunc }
+
+func (s *builtinArithmeticMultiplyRealSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticMultiplyRealSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticMultiplyRealSig) evalReal(row chunk.Row) (float64, bool, error) {
+	a, isNull, err := s.args[0].EvalReal(s.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	b, isNull, err := s.args[1].EvalReal(s.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	result := a * b
+	if math.IsInf(result, 0) {
+		return 0, true, outOfRangeErr("DOUBLE", "*", s.args)
+	}
+	return result, false, nil
+}
+
+type builtinArithmeticMultiplyDecimalSig struct{ baseBuiltinFunc }
+
+func (s *builtinArithmeticMultiplyDecimalSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticMultiplyDecimalSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticMultiplyDecimalSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	a, b, isNull, err := s.evalDecimalArgs(row)
+	if isNull || err != nil {
+		return nil, true, err
+	}
+	return s.mul(a, b)
+}
+
+func (s *builtinArithmeticMultiplyDecimalSig) mul(a, b *types.MyDecimal) (*types.MyDecimal, bool, error) {
+	c := &types.MyDecimal{}
+	if err := types.DecimalMul(a, b, c); err != nil {
+		if err == types.ErrOverflow {
+			err = outOfRangeErr("DECIMAL", "*", s.args)
+		}
+		return nil, true, err
+	}
+	return c, false, nil
+}
+
+type builtinArithmeticMultiplyIntSig struct{ baseBuiltinFunc }
+
+func (s *builtinArithmeticMultiplyIntSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticMultiplyIntSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticMultiplyIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	a, b, isNull, err := s.evalIntArgs(row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	isLHSUnsigned := mysql.HasUnsignedFlag(s.args[0].GetType().Flag)
+	isRHSUnsigned := mysql.HasUnsignedFlag(s.args[1].GetType().Flag)
+	res, overflow := mulInt(a, b, isLHSUnsigned, isRHSUnsigned)
+	if overflow {
+		return 0, true, intOutOfRangeErr(isLHSUnsigned || isRHSUnsigned, "*", s.args)
+	}
+	return res, false, nil
+}
+
+// mulInt returns a * b of the integers with the unsigned flags, the second returned value reports
+// whether the result overflows.
+func mulInt(a, b int64, isLHSUnsigned, isRHSUnsigned bool) (int64, bool) {
+	if isLHSUnsigned || isRHSUnsigned {
+		// The result is unsigned, a negative operand only works with a zero.
+		if (!isLHSUnsigned && a < 0 && b != 0) || (!isRHSUnsigned && b < 0 && a != 0) {
+			return 0, true
+		}
+		hi, lo := bits.Mul64(uint64(a), uint64(b))
+		return int64(lo), hi != 0
+	}
+	result := a * b
+	if (a != 0 && result/a != b) || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, true
+	}
+	return result, false
+}
+
+type arithmeticDivideFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *arithmeticDivideFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	lhsTp, rhsTp := args[0].GetType(), args[1].GetType()
+	lhsEvalTp, rhsEvalTp := numericContextResultType(lhsTp), numericContextResultType(rhsTp)
+	if lhsEvalTp == types.ETReal || rhsEvalTp == types.ETReal {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETReal, types.ETReal, types.ETReal)
+		c.setType4DivReal(bf.tp)
+		return &builtinArithmeticDivideRealSig{bf}, nil
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDecimal, types.ETDecimal, types.ETDecimal)
+	c.setType4DivDecimal(bf.tp, lhsTp, rhsTp)
+	return &builtinArithmeticDivideDecimalSig{bf}, nil
+}
+
+func (c *arithmeticDivideFunctionClass) setType4DivDecimal(retTp, a, b *types.FieldType) {
+	var deca, decb = a.Decimal, b.Decimal
+	if deca == types.UnspecifiedLength {
+		deca = 0
+	}
+	if decb == types.UnspecifiedLength {
+		decb = 0
+	}
+	retTp.Decimal = deca + types.DivFracIncr
+	if retTp.Decimal > types.MaxDecimalScale {
+		retTp.Decimal = types.MaxDecimalScale
+	}
+	if a.Flen == types.UnspecifiedLength {
+		retTp.Flen = mysql.MaxDecimalWidth
+		return
+	}
+	retTp.Flen = a.Flen + decb + types.DivFracIncr
+	if retTp.Flen > mysql.MaxDecimalWidth {
+		retTp.Flen = mysql.MaxDecimalWidth
+	}
+}
+
+func (c *arithmeticDivideFunctionClass) setType4DivReal(retTp *types.FieldType) {
+	retTp.Decimal = types.UnspecifiedLength
+	retTp.Flen = mysql.MaxRealWidth
+}
+
+type builtinArithmeticDivideRealSig struct{ baseBuiltinFunc }
+
+func (s *builtinArithmeticDivideRealSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticDivideRealSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticDivideRealSig) evalReal(row chunk.Row) (float64, bool, error) {
+	a, isNull, err := s.args[0].EvalReal(s.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	b, isNull, err := s.args[1].EvalReal(s.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if b == 0 {
+		return 0, true, handleDivisionByZeroError(s.ctx)
+	}
+	result := a / b
+	if math.IsInf(result, 0) {
+		return 0, true, outOfRangeErr("DOUBLE", "/", s.args)
+	}
+	return result, false, nil
+}
+
+type builtinArithmeticDivideDecimalSig struct{ baseBuiltinFunc }
+
+func (s *builtinArithmeticDivideDecimalSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticDivideDecimalSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticDivideDecimalSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	a, b, isNull, err := s.evalDecimalArgs(row)
+	if isNull || err != nil {
+		return nil, true, err
+	}
+	return s.div(a, b)
+}
+
+func (s *builtinArithmeticDivideDecimalSig) div(a, b *types.MyDecimal) (*types.MyDecimal, bool, error) {
+	c := &types.MyDecimal{}
+	err := types.DecimalDiv(a, b, c, types.DivFracIncr)
+	if err == types.ErrDivByZero {
+		return c, true, handleDivisionByZeroError(s.ctx)
+	} else if err == types.ErrOverflow {
+		return c, true, outOfRangeErr("DECIMAL", "/", s.args)
+	}
+	return c, false, err
+}
+
+type arithmeticIntDivideFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *arithmeticIntDivideFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	lhsTp, rhsTp := args[0].GetType(), args[1].GetType()
+	lhsEvalTp, rhsEvalTp := numericContextResultType(lhsTp), numericContextResultType(rhsTp)
+	isLHSUnsigned, isRHSUnsigned := mysql.HasUnsignedFlag(lhsTp.Flag), mysql.HasUnsignedFlag(rhsTp.Flag)
+	if lhsEvalTp == types.ETInt && rhsEvalTp == types.ETInt && isLHSUnsigned == isRHSUnsigned {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETInt, types.ETInt)
+		if isLHSUnsigned {
+			bf.tp.Flag |= mysql.UnsignedFlag
+		}
+		return &builtinArithmeticIntDivideIntSig{bf}, nil
+	}
+	// The mixed signed and unsigned integers are divided as decimals.
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETDecimal, types.ETDecimal)
+	if isLHSUnsigned || isRHSUnsigned {
+		bf.tp.Flag |= mysql.UnsignedFlag
+	}
+	return &builtinArithmeticIntDivideDecimalSig{bf}, nil
+}
+
+type builtinArithmeticIntDivideIntSig struct{ baseBuiltinFunc }
+
+func (s *builtinArithmeticIntDivideIntSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticIntDivideIntSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticIntDivideIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	a, isNull, err := s.args[0].EvalInt(s.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	b, isNull, err := s.args[1].EvalInt(s.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if b == 0 {
+		return 0, true, handleDivisionByZeroError(s.ctx)
+	}
+	if mysql.HasUnsignedFlag(s.args[0].GetType().Flag) {
+		return int64(uint64(a) / uint64(b)), false, nil
+	}
+	if a == math.MinInt64 && b == -1 {
+		return 0, true, intOutOfRangeErr(false, "DIV", s.args)
+	}
+	return a / b, false, nil
+}
+
+type builtinArithmeticIntDivideDecimalSig struct{ baseBuiltinFunc }
+
+func (s *builtinArithmeticIntDivideDecimalSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticIntDivideDecimalSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticIntDivideDecimalSig) evalInt(row chunk.Row) (ret int64, isNull bool, err error) {
+	a, b, isNull, err := s.evalDecimalArgs(row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	return s.intDiv(a, b)
+}
+
+func (s *builtinArithmeticIntDivideDecimalSig) intDiv(a, b *types.MyDecimal) (ret int64, isNull bool, err error) {
+	c := &types.MyDecimal{}
+	err = types.DecimalIntDiv(a, b, c)
+	if err == types.ErrDivByZero {
+		return 0, true, handleDivisionByZeroError(s.ctx)
+	}
+	if err != nil {
+		return 0, true, outOfRangeErr("DECIMAL", "DIV", s.args)
+	}
+	if mysql.HasUnsignedFlag(s.tp.Flag) {
+		if c.IsNegative() {
+			return 0, true, intOutOfRangeErr(true, "DIV", s.args)
+		}
+		uVal, err := c.ToUint()
+		if err != nil {
+			return 0, true, intOutOfRangeErr(true, "DIV", s.args)
+		}
+		return int64(uVal), false, nil
+	}
+	ret, err = c.ToInt()
+	if err != nil {
+		return 0, true, intOutOfRangeErr(false, "DIV", s.args)
+	}
+	return ret, false, nil
+}
+
+type arithmeticModFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *arithmeticModFunctionClass) setType4ModRealOrDecimal(retTp, a, b *types.FieldType, isDecimal bool) {
+	if a.Decimal == types.UnspecifiedLength || b.Decimal == types.UnspecifiedLength {
+		retTp.Decimal = types.UnspecifiedLength
+	} else {
+		retTp.Decimal = maxInt(a.Decimal, b.Decimal)
+		if isDecimal && retTp.Decimal > types.MaxDecimalScale {
+			retTp.Decimal = types.MaxDecimalScale
+		}
+	}
+	if a.Flen == types.UnspecifiedLength || b.Flen == types.UnspecifiedLength {
+		retTp.Flen = types.UnspecifiedLength
+	} else {
+		retTp.Flen = maxInt(a.Flen, b.Flen)
+		if isDecimal {
+			retTp.Flen = minInt(retTp.Flen, mysql.MaxDecimalWidth)
+			return
+		}
+		retTp.Flen = minInt(retTp.Flen, mysql.MaxRealWidth)
+	}
+}
+
+func (c *arithmeticModFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	lhsTp, rhsTp := args[0].GetType(), args[1].GetType()
+	lhsEvalTp, rhsEvalTp := numericContextResultType(lhsTp), numericContextResultType(rhsTp)
+	if lhsEvalTp == types.ETReal || rhsEvalTp == types.ETReal {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETReal, types.ETReal, types.ETReal)
+		c.setType4ModRealOrDecimal(bf.tp, lhsTp, rhsTp, false)
+		if mysql.HasUnsignedFlag(lhsTp.Flag) {
+			bf.tp.Flag |= mysql.UnsignedFlag
+		}
+		return &builtinArithmeticModRealSig{bf}, nil
+	} else if lhsEvalTp == types.ETDecimal || rhsEvalTp == types.ETDecimal {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDecimal, types.ETDecimal, types.ETDecimal)
+		c.setType4ModRealOrDecimal(bf.tp, lhsTp, rhsTp, true)
+		if mysql.HasUnsignedFlag(lhsTp.Flag) {
+			bf.tp.Flag |= mysql.UnsignedFlag
+		}
+		return &builtinArithmeticModDecimalSig{bf}, nil
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETInt, types.ETInt)
+	if mysql.HasUnsignedFlag(lhsTp.Flag) {
+		bf.tp.Flag |= mysql.UnsignedFlag
+	}
+	return &builtinArithmeticModIntSig{bf}, nil
+}
+
+type builtinArithmeticModRealSig struct {
+	baseBuiltinFunc
+}
+
+func (s *builtinArithmeticModRealSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticModRealSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticModRealSig) evalReal(row chunk.Row) (float64, bool, error) {
+	a, isNull, err := s.args[0].EvalReal(s.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	b, isNull, err := s.args[1].EvalReal(s.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if b == 0 {
+		return 0, true, handleDivisionByZeroError(s.ctx)
+	}
+	return math.Mod(a, b), false, nil
+}
+
+type builtinArithmeticModDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (s *builtinArithmeticModDecimalSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticModDecimalSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticModDecimalSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	a, b, isNull, err := s.evalDecimalArgs(row)
+	if isNull || err != nil {
+		return nil, true, err
+	}
+	return s.mod(a, b)
+}
+
+func (s *builtinArithmeticModDecimalSig) mod(a, b *types.MyDecimal) (*types.MyDecimal, bool, error) {
+	c := &types.MyDecimal{}
+	err := types.DecimalMod(a, b, c)
+	if err == types.ErrDivByZero {
+		return c, true, handleDivisionByZeroError(s.ctx)
+	}
+	return c, err != nil, err
+}
+
+type builtinArithmeticModIntSig struct {
+	baseBuiltinFunc
+}
+
+func (s *builtinArithmeticModIntSig) Clone() builtinFunc {
+	newSig := &builtinArithmeticModIntSig{}
+	newSig.cloneFrom(&s.baseBuiltinFunc)
+	return newSig
+}
+
+func (s *builtinArithmeticModIntSig) evalInt(row chunk.Row) (val int64, isNull bool, err error) {
+	a, isNull, err := s.args[0].EvalInt(s.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	b, isNull, err := s.args[1].EvalInt(s.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if b == 0 {
+		return 0, true, handleDivisionByZeroError(s.ctx)
+	}
+	isLHSUnsigned := mysql.HasUnsignedFlag(s.args[0].GetType().Flag)
+	isRHSUnsigned := mysql.HasUnsignedFlag(s.args[1].GetType().Flag)
+	return modInt(a, b, isLHSUnsigned, isRHSUnsigned), false, nil
+}
+
+// modInt returns a % b of the integers with the unsigned flags, b must not be zero.
+func modInt(a, b int64, isLHSUnsigned, isRHSUnsigned bool) int64 {
+	// The sign of the result is the sign of the dividend.
+	absB := uint64(b)
+	if !isRHSUnsigned && b < 0 {
+		absB = uint64(-b)
+	}
+	switch {
+	case isLHSUnsigned:
+		return int64(uint64(a) % absB)
+	case a < 0:
+		return -int64(uint64(-a) % absB)
+	default:
+		return int64(uint64(a) % absB)
+	}
+}
@@ -0,0 +1,413 @@
+package expression
+
+import (
+	"math"
+
+	"github.com/pingcap/parser/mysql"
+	"grant-db/types"
+	"grant-db/util/chunk"
+)
+
+// vecEvalIntArgs evaluates the two integer arguments of b by vectors, the first one is evaluated into
+// result and the second one into the returned buffer, which should be put back after using.
+func (b *baseBuiltinFunc) vecEvalIntArgs(input *chunk.Chunk, result *chunk.Column) (*chunk.Column, error) {
+	if err := b.args[0].VecEvalInt(b.ctx, input, result); err != nil {
+		return nil, err
+	}
+	buf := b.bufAllocator.get(types.ETInt, input.NumRows())
+	if err := b.args[1].VecEvalInt(b.ctx, input, buf); err != nil {
+		b.bufAllocator.put(types.ETInt, buf)
+		return nil, err
+	}
+	result.MergeNulls(buf)
+	return buf, nil
+}
+
+// vecEvalRealArgs is like vecEvalIntArgs but evaluates the real arguments.
+func (b *baseBuiltinFunc) vecEvalRealArgs(input *chunk.Chunk, result *chunk.Column) (*chunk.Column, error) {
+	if err := b.args[0].VecEvalReal(b.ctx, input, result); err != nil {
+		return nil, err
+	}
+	buf := b.bufAllocator.get(types.ETReal, input.NumRows())
+	if err := b.args[1].VecEvalReal(b.ctx, input, buf); err != nil {
+		b.bufAllocator.put(types.ETReal, buf)
+		return nil, err
+	}
+	result.MergeNulls(buf)
+	return buf, nil
+}
+
+// vecEvalDecimalOp evaluates the two decimal arguments of b by vectors and appends op of every row to result,
+// a row with any NULL argument is NULL.
+func (b *baseBuiltinFunc) vecEvalDecimalOp(input *chunk.Chunk, result *chunk.Column, op func(a, b *types.MyDecimal) (*types.MyDecimal, bool, error)) error {
+	n := input.NumRows()
+	buf0 := b.bufAllocator.get(types.ETDecimal, n)
+	defer b.bufAllocator.put(types.ETDecimal, buf0)
+	if err := b.args[0].VecEvalDecimal(b.ctx, input, buf0); err != nil {
+		return err
+	}
+	buf1 := b.bufAllocator.get(types.ETDecimal, n)
+	defer b.bufAllocator.put(types.ETDecimal, buf1)
+	if err := b.args[1].VecEvalDecimal(b.ctx, input, buf1); err != nil {
+		return err
+	}
+	result.ReserveDecimal(n)
+	for i := 0; i < n; i++ {
+		if buf0.IsNull(i) || buf1.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		res, isNull, err := op(buf0.GetDecimal(i), buf1.GetDecimal(i))
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		result.AppendMyDecimal(res)
+	}
+	return nil
+}
+
+func (s *builtinArithmeticPlusIntSig) vectorized() bool {
+	return true
+}
+
+func (s *builtinArithmeticPlusIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	buf, err := s.vecEvalIntArgs(input, result)
+	if err != nil {
+		return err
+	}
+	defer s.bufAllocator.put(types.ETInt, buf)
+	isLHSUnsigned := mysql.HasUnsignedFlag(s.args[0].GetType().Flag)
+	isRHSUnsigned := mysql.HasUnsignedFlag(s.args[1].GetType().Flag)
+	as, bs := result.Int64s(), buf.Int64s()
+	for i := range as {
+		if result.IsNull(i) {
+			continue
+		}
+		res, overflow := plusInt(as[i], bs[i], isLHSUnsigned, isRHSUnsigned)
+		if overflow {
+			return intOutOfRangeErr(isLHSUnsigned || isRHSUnsigned, "+", s.args)
+		}
+		as[i] = res
+	}
+	return nil
+}
+
+func (s *builtinArithmeticPlusRealSig) vectorized() bool {
+	return true
+}
+
+func (s *builtinArithmeticPlusRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	buf, err := s.vecEvalRealArgs(input, result)
+	if err != nil {
+		return err
+	}
+	defer s.bufAllocator.put(types.ETReal, buf)
+	as, bs := result.Float64s(), buf.Float64s()
+	for i := range as {
+		if result.IsNull(i) {
+			continue
+		}
+		as[i] += bs[i]
+		if math.IsInf(as[i], 0) {
+			return outOfRangeErr("DOUBLE", "+", s.args)
+		}
+	}
+	return nil
+}
+
+func (s *builtinArithmeticPlusDecimalSig) vectorized() bool {
+	return true
+}
+
+func (s *builtinArithmeticPlusDecimalSig) vecEvalDecimal(input *chunk.Chunk, result *chunk.Column) error {
+	return s.vecEvalDecimalOp(input, result, s.plus)
+}
+
+func (s *builtinArithmeticMinusIntSig) vectorized() bool {
+	return true
+}
+
+func (s *builtinArithmeticMinusIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	buf, err := s.vecEvalIntArgs(input, result)
+	if err != nil {
+		return err
+	}
+	defer s.bufAllocator.put(types.ETInt, buf)
+	isLHSUnsigned := mysql.HasUnsignedFlag(s.args[0].GetType().Flag)
+	isRHSUnsigned := mysql.HasUnsignedFlag(s.args[1].GetType().Flag)
+	as, bs := result.Int64s(), buf.Int64s()
+	for i := range as {
+		if result.IsNull(i) {
+			continue
+		}
+		res, overflow := minusInt(as[i], bs[i], isLHSUnsigned, isRHSUnsigned)
+		if overflow {
+			return intOutOfRangeErr(isLHSUnsigned || isRHSUnsigned, "-", s.args)
+		}
+		as[i] = res
+	}
+	return nil
+}
+
+func (s *builtinArithmeticMinusRealSig) vectorized() bool {
+	return true
+}
+
+func (s *builtinArithmeticMinusRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	buf, err := s.vecEvalRealArgs(input, result)
+	if err != nil {
+		return err
+	}
+	defer s.bufAllocator.put(types.ETReal, buf)
+	as, bs := result.Float64s(), buf.Float64s()
+	for i := range as {
+		if result.IsNull(i) {
+			continue
+		}
+		as[i] -= bs[i]
+		if math.IsInf(as[i], 0) {
+			return outOfRangeErr("DOUBLE", "-", s.args)
+		}
+	}
+	return nil
+}
+
+func (s *builtinArithmeticMinusDecimalSig) vectorized() bool {
+	return true
+}
+
+func (s *builtinArithmeticMinusDecimalSig) vecEvalDecimal(input *chunk.Chunk, result *chunk.Column) error {
+	return s.vecEvalDecimalOp(input, result, s.minus)
+}
+
+func (s *builtinArithmeticMultiplyIntSig) vectorized() bool {
+	return true
+}
+
+func (s *builtinArithmeticMultiplyIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	buf, err := s.vecEvalIntArgs(input, result)
+	if err != nil {
+		return err
+	}
+	defer s.bufAllocator.put(types.ETInt, buf)
+	isLHSUnsigned := mysql.HasUnsignedFlag(s.args[0].GetType().Flag)
+	isRHSUnsigned := mysql.HasUnsignedFlag(s.args[1].GetType().Flag)
+	as, bs := result.Int64s(), buf.Int64s()
+	for i := range as {
+		if result.IsNull(i) {
+			continue
+		}
+		res, overflow := mulInt(as[i], bs[i], isLHSUnsigned, isRHSUnsigned)
+		if overflow {
+			return intOutOfRangeErr(isLHSUnsigned || isRHSUnsigned, "*", s.args)
+		}
+		as[i] = res
+	}
+	return nil
+}
+
+func (s *builtinArithmeticMultiplyRealSig) vectorized() bool {
+	return true
+}
+
+func (s *builtinArithmeticMultiplyRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	buf, err := s.vecEvalRealArgs(input, result)
+	if err != nil {
+		return err
+	}
+	defer s.bufAllocator.put(types.ETReal, buf)
+	as, bs := result.Float64s(), buf.Float64s()
+	for i := range as {
+		if result.IsNull(i) {
+			continue
+		}
+		as[i] *= bs[i]
+		if math.IsInf(as[i], 0) {
+			return outOfRangeErr("DOUBLE", "*", s.args)
+		}
+	}
+	return nil
+}
+
+func (s *builtinArithmeticMultiplyDecimalSig) vectorized() bool {
+	return true
+}
+
+func (s *builtinArithmeticMultiplyDecimalSig) vecEvalDecimal(input *chunk.Chunk, result *chunk.Column) error {
+	return s.vecEvalDecimalOp(input, result, s.mul)
+}
+
+func (s *builtinArithmeticDivideRealSig) vectorized() bool {
+	return true
+}
+
+func (s *builtinArithmeticDivideRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	buf, err := s.vecEvalRealArgs(input, result)
+	if err != nil {
+		return err
+	}
+	defer s.bufAllocator.put(types.ETReal, buf)
+	as, bs := result.Float64s(), buf.Float64s()
+	for i := range as {
+		if result.IsNull(i) {
+			continue
+		}
+		if bs[i] == 0 {
+			if err := handleDivisionByZeroError(s.ctx); err != nil {
+				return err
+			}
+			result.SetNull(i, true)
+			continue
+		}
+		as[i] /= bs[i]
+		if math.IsInf(as[i], 0) {
+			return outOfRangeErr("DOUBLE", "/", s.args)
+		}
+	}
+	return nil
+}
+
+func (s *builtinArithmeticDivideDecimalSig) vectorized() bool {
+	return true
+}
+
+func (s *builtinArithmeticDivideDecimalSig) vecEvalDecimal(input *chunk.Chunk, result *chunk.Column) error {
+	return s.vecEvalDecimalOp(input, result, s.div)
+}
+
+func (s *builtinArithmeticIntDivideIntSig) vectorized() bool {
+	return true
+}
+
+func (s *builtinArithmeticIntDivideIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	buf, err := s.vecEvalIntArgs(input, result)
+	if err != nil {
+		return err
+	}
+	defer s.bufAllocator.put(types.ETInt, buf)
+	isUnsigned := mysql.HasUnsignedFlag(s.args[0].GetType().Flag)
+	as, bs := result.Int64s(), buf.Int64s()
+	for i := range as {
+		if result.IsNull(i) {
+			continue
+		}
+		switch {
+		case bs[i] == 0:
+			if err := handleDivisionByZeroError(s.ctx); err != nil {
+				return err
+			}
+			result.SetNull(i, true)
+		case isUnsigned:
+			as[i] = int64(uint64(as[i]) / uint64(bs[i]))
+		case as[i] == math.MinInt64 && bs[i] == -1:
+			return intOutOfRangeErr(false, "DIV", s.args)
+		default:
+			as[i] /= bs[i]
+		}
+	}
+	return nil
+}
+
+func (s *builtinArithmeticIntDivideDecimalSig) vectorized() bool {
+	return true
+}
+
+func (s *builtinArithmeticIntDivideDecimalSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf0 := s.bufAllocator.get(types.ETDecimal, n)
+	defer s.bufAllocator.put(types.ETDecimal, buf0)
+	if err := s.args[0].VecEvalDecimal(s.ctx, input, buf0); err != nil {
+		return err
+	}
+	buf1 := s.bufAllocator.get(types.ETDecimal, n)
+	defer s.bufAllocator.put(types.ETDecimal, buf1)
+	if err := s.args[1].VecEvalDecimal(s.ctx, input, buf1); err != nil {
+		return err
+	}
+	result.ResizeInt64(n, false)
+	result.MergeNulls(buf0, buf1)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		res, isNull, err := s.intDiv(buf0.GetDecimal(i), buf1.GetDecimal(i))
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.SetNull(i, true)
+			continue
+		}
+		i64s[i] = res
+	}
+	return nil
+}
+
+func (s *builtinArithmeticModIntSig) vectorized() bool {
+	return true
+}
+
+func (s *builtinArithmeticModIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	buf, err := s.vecEvalIntArgs(input, result)
+	if err != nil {
+		return err
+	}
+	defer s.bufAllocator.put(types.ETInt, buf)
+	isLHSUnsigned := mysql.HasUnsignedFlag(s.args[0].GetType().Flag)
+	isRHSUnsigned := mysql.HasUnsignedFlag(s.args[1].GetType().Flag)
+	as, bs := result.Int64s(), buf.Int64s()
+	for i := range as {
+		if result.IsNull(i) {
+			continue
+		}
+		if bs[i] == 0 {
+			if err := handleDivisionByZeroError(s.ctx); err != nil {
+				return err
+			}
+			result.SetNull(i, true)
+			continue
+		}
+		as[i] = modInt(as[i], bs[i], isLHSUnsigned, isRHSUnsigned)
+	}
+	return nil
+}
+
+func (s *builtinArithmeticModRealSig) vectorized() bool {
+	return true
+}
+
+func (s *builtinArithmeticModRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	buf, err := s.vecEvalRealArgs(input, result)
+	if err != nil {
+		return err
+	}
+	defer s.bufAllocator.put(types.ETReal, buf)
+	as, bs := result.Float64s(), buf.Float64s()
+	for i := range as {
+		if result.IsNull(i) {
+			continue
+		}
+		if bs[i] == 0 {
+			if err := handleDivisionByZeroError(s.ctx); err != nil {
+				return err
+			}
+			result.SetNull(i, true)
+			continue
+		}
+		as[i] = math.Mod(as[i], bs[i])
+	}
+	return nil
+}
+
+func (s *builtinArithmeticModDecimalSig) vectorized() bool {
+	return true
+}
+
+func (s *builtinArithmeticModDecimalSig) vecEvalDecimal(input *chunk.Chunk, result *chunk.Column) error {
+	return s.vecEvalDecimalOp(input, result, s.mod)
+}
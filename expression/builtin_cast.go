@@ -0,0 +1,791 @@
+// We implement 6 CastAsXXFunctionClass for `cast` built-in functions.
+// XX means the return type of the `cast` built-in functions.
+// XX contains the following 6 types:
+// Int, Decimal, Real, String, Time, JSON.
+
+// We implement one signature for each return type, the signature converts its argument
+// according to the evaluation type of the argument.
+
+package expression
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/charset"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
+	"grant-db/sessionctx"
+	"grant-db/types"
+	"grant-db/types/json"
+	"grant-db/util/chunk"
+)
+
+type castAsIntFunctionClass struct {
+	baseFunctionClass
+
+	tp *types.FieldType
+}
+
+func (c *castAsIntFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	b := newBaseBuiltinFunc(ctx, args)
+	b.tp = c.tp
+	return &builtinCastAsIntSig{b}, nil
+}
+
+type castAsRealFunctionClass struct {
+	baseFunctionClass
+
+	tp *types.FieldType
+}
+
+func (c *castAsRealFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	b := newBaseBuiltinFunc(ctx, args)
+	b.tp = c.tp
+	return &builtinCastAsRealSig{b}, nil
+}
+
+type castAsDecimalFunctionClass struct {
+	baseFunctionClass
+
+	tp *types.FieldType
+}
+
+func (c *castAsDecimalFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	b := newBaseBuiltinFunc(ctx, args)
+	b.tp = c.tp
+	return &builtinCastAsDecimalSig{b}, nil
+}
+
+type castAsStringFunctionClass struct {
+	baseFunctionClass
+
+	tp *types.FieldType
+}
+
+func (c *castAsStringFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	b := newBaseBuiltinFunc(ctx, args)
+	b.tp = c.tp
+	return &builtinCastAsStringSig{b}, nil
+}
+
+type castAsTimeFunctionClass struct {
+	baseFunctionClass
+
+	tp *types.FieldType
+}
+
+func (c *castAsTimeFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	b := newBaseBuiltinFunc(ctx, args)
+	b.tp = c.tp
+	return &builtinCastAsTimeSig{b}, nil
+}
+
+type castAsJSONFunctionClass struct {
+	baseFunctionClass
+
+	tp *types.FieldType
+}
+
+func (c *castAsJSONFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	b := newBaseBuiltinFunc(ctx, args)
+	b.tp = c.tp
+	return &builtinCastAsJSONSig{b}, nil
+}
+
+type builtinCastAsIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastAsIntSig) Clone() builtinFunc {
+	newSig := &builtinCastAsIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// isHybridArg checks whether the argument is an ENUM, SET or BIT column or a hex or bit literal, whose
+// numeric value is its index, bits or bytes in big endian rather than the number parsed from its string.
+func isHybridArg(arg Expression) bool {
+	if arg.GetType().Hybrid() {
+		return true
+	}
+	c, ok := arg.(*Constant)
+	return ok && c.Value.Kind() == types.KindBinaryLiteral
+}
+
+// evalDurationArg evaluates the TIME argument, whose numeric value is in the format of HHMMSS[.fraction].
+func evalDurationArg(ctx sessionctx.Context, arg Expression, row chunk.Row) (types.Duration, bool, error) {
+	val, isNull, err := arg.EvalString(ctx, row)
+	if isNull || err != nil {
+		return types.ZeroDuration, isNull, err
+	}
+	dur, err := types.ParseDuration(val, int8(arg.GetType().Decimal))
+	return dur, false, err
+}
+
+func (b *builtinCastAsIntSig) evalInt(row chunk.Row) (res int64, isNull bool, err error) {
+	arg := b.args[0]
+	if isHybridArg(arg) {
+		return arg.EvalInt(b.ctx, row)
+	}
+	switch arg.GetType().EvalType() {
+	case types.ETInt:
+		return arg.EvalInt(b.ctx, row)
+	case types.ETReal:
+		val, isNull, err := arg.EvalReal(b.ctx, row)
+		if isNull || err != nil {
+			return 0, isNull, err
+		}
+		res, err = b.realToInt(val)
+		return res, false, err
+	case types.ETDecimal:
+		val, isNull, err := arg.EvalDecimal(b.ctx, row)
+		if isNull || err != nil {
+			return 0, isNull, err
+		}
+		res, err = b.decimalToInt(val)
+		return res, false, err
+	case types.ETDatetime, types.ETTimestamp:
+		val, isNull, err := arg.EvalTime(b.ctx, row)
+		if isNull || err != nil {
+			return 0, isNull, err
+		}
+		res, err = b.decimalToInt(val.ToNumber())
+		return res, false, err
+	case types.ETDuration:
+		val, isNull, err := evalDurationArg(b.ctx, arg, row)
+		if isNull || err != nil {
+			return 0, isNull, err
+		}
+		res, err = b.decimalToInt(val.ToNumber())
+		return res, false, err
+	case types.ETJson:
+		val, isNull, err := arg.EvalJSON(b.ctx, row)
+		if isNull || err != nil {
+			return 0, isNull, err
+		}
+		res, err = types.ConvertJSONToInt(b.ctx.GetSessionVars().StmtCtx, val, mysql.HasUnsignedFlag(b.tp.Flag))
+		return res, false, err
+	default:
+		val, isNull, err := arg.EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return 0, isNull, err
+		}
+		res, err = b.stringToInt(val)
+		return res, false, err
+	}
+}
+
+func (b *builtinCastAsIntSig) realToInt(val float64) (res int64, err error) {
+	if mysql.HasUnsignedFlag(b.tp.Flag) && val >= 0 {
+		var uVal uint64
+		uVal, err = types.ConvertFloatToUint(val, math.MaxUint64, mysql.TypeLonglong)
+		res = int64(uVal)
+	} else {
+		res, err = types.ConvertFloatToInt(val, math.MinInt64, math.MaxInt64, mysql.TypeLonglong)
+	}
+	if err != nil {
+		return res, b.handleOverflow(types.StrictFormatFloat(val, 64))
+	}
+	return res, nil
+}
+
+func (b *builtinCastAsIntSig) decimalToInt(val *types.MyDecimal) (res int64, err error) {
+	val = val.Copy().Rescale(0)
+	if mysql.HasUnsignedFlag(b.tp.Flag) && !val.IsNegative() {
+		var uVal uint64
+		uVal, err = val.ToUint()
+		res = int64(uVal)
+	} else {
+		res, err = val.ToInt()
+	}
+	if err != nil {
+		return res, b.handleOverflow(val.String())
+	}
+	return res, nil
+}
+
+func (b *builtinCastAsIntSig) stringToInt(val string) (res int64, err error) {
+	sc := b.ctx.GetSessionVars().StmtCtx
+	if mysql.HasUnsignedFlag(b.tp.Flag) && (len(val) == 0 || val[0] != '-') {
+		var uVal uint64
+		uVal, err = types.StrToUint(sc, val)
+		res = int64(uVal)
+	} else {
+		res, err = types.StrToInt(sc, val)
+	}
+	if types.ErrOverflow == err {
+		return res, b.handleOverflow(val)
+	}
+	return res, err
+}
+
+// handleOverflow handles the value which is out of the BIGINT range, the value is clipped with a warning
+// when overflow is allowed by the statement context.
+func (b *builtinCastAsIntSig) handleOverflow(origin string) error {
+	err := types.ErrTruncatedWrongVal.GenWithStackByArgs("INTEGER", origin)
+	return b.ctx.GetSessionVars().StmtCtx.HandleOverflow(err, err)
+}
+
+type builtinCastAsRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastAsRealSig) Clone() builtinFunc {
+	newSig := &builtinCastAsRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastAsRealSig) evalReal(row chunk.Row) (res float64, isNull bool, err error) {
+	arg := b.args[0]
+	if isHybridArg(arg) {
+		val, isNull, err := arg.EvalInt(b.ctx, row)
+		return float64(uint64(val)), isNull, err
+	}
+	switch arg.GetType().EvalType() {
+	case types.ETInt:
+		val, isNull, err := arg.EvalInt(b.ctx, row)
+		if isNull || err != nil {
+			return 0, isNull, err
+		}
+		return b.intToReal(val), false, nil
+	case types.ETReal:
+		return arg.EvalReal(b.ctx, row)
+	case types.ETDecimal:
+		val, isNull, err := arg.EvalDecimal(b.ctx, row)
+		if isNull || err != nil {
+			return 0, isNull, err
+		}
+		res, err = val.ToFloat64()
+		return res, false, err
+	case types.ETDatetime, types.ETTimestamp:
+		val, isNull, err := arg.EvalTime(b.ctx, row)
+		if isNull || err != nil {
+			return 0, isNull, err
+		}
+		res, err = val.ToNumber().ToFloat64()
+		return res, false, err
+	case types.ETDuration:
+		val, isNull, err := evalDurationArg(b.ctx, arg, row)
+		if isNull || err != nil {
+			return 0, isNull, err
+		}
+		res, err = val.ToNumber().ToFloat64()
+		return res, false, err
+	case types.ETJson:
+		val, isNull, err := arg.EvalJSON(b.ctx, row)
+		if isNull || err != nil {
+			return 0, isNull, err
+		}
+		res, err = types.ConvertJSONToFloat(b.ctx.GetSessionVars().StmtCtx, val)
+		return res, false, err
+	default:
+		val, isNull, err := arg.EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return 0, isNull, err
+		}
+		res, err = b.stringToReal(val)
+		return res, false, err
+	}
+}
+
+func (b *builtinCastAsRealSig) intToReal(val int64) float64 {
+	if mysql.HasUnsignedFlag(b.args[0].GetType().Flag) {
+		return float64(uint64(val))
+	}
+	return float64(val)
+}
+
+func (b *builtinCastAsRealSig) stringToReal(val string) (float64, error) {
+	sc := b.ctx.GetSessionVars().StmtCtx
+	res, err := types.StrToFloat(sc, val)
+	if types.ErrOverflow == err {
+		warnErr := types.ErrTruncatedWrongVal.GenWithStackByArgs("DOUBLE", val)
+		return res, sc.HandleOverflow(warnErr, warnErr)
+	}
+	return res, err
+}
+
+type builtinCastAsDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastAsDecimalSig) Clone() builtinFunc {
+	newSig := &builtinCastAsDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastAsDecimalSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	res, isNull, err := b.convertArg(row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	res, err = types.ProduceDecWithSpecifiedTp(res, b.tp, b.ctx.GetSessionVars().StmtCtx)
+	return res, false, err
+}
+
+// convertArg converts the argument to a decimal, which isn't rounded to the field type of the cast yet.
+func (b *builtinCastAsDecimalSig) convertArg(row chunk.Row) (res *types.MyDecimal, isNull bool, err error) {
+	arg := b.args[0]
+	if isHybridArg(arg) {
+		val, isNull, err := arg.EvalInt(b.ctx, row)
+		if isNull || err != nil {
+			return nil, isNull, err
+		}
+		return types.NewDecFromUint(uint64(val)), false, nil
+	}
+	switch arg.GetType().EvalType() {
+	case types.ETInt:
+		val, isNull, err := arg.EvalInt(b.ctx, row)
+		if isNull || err != nil {
+			return nil, isNull, err
+		}
+		return b.intToDecimal(val), false, nil
+	case types.ETReal:
+		val, isNull, err := arg.EvalReal(b.ctx, row)
+		if isNull || err != nil {
+			return nil, isNull, err
+		}
+		res = new(types.MyDecimal)
+		err = res.FromFloat64(val)
+		return res, false, err
+	case types.ETDecimal:
+		return arg.EvalDecimal(b.ctx, row)
+	case types.ETDatetime, types.ETTimestamp:
+		val, isNull, err := arg.EvalTime(b.ctx, row)
+		if isNull || err != nil {
+			return nil, isNull, err
+		}
+		return val.ToNumber(), false, nil
+	case types.ETDuration:
+		val, isNull, err := evalDurationArg(b.ctx, arg, row)
+		if isNull || err != nil {
+			return nil, isNull, err
+		}
+		return val.ToNumber(), false, nil
+	case types.ETJson:
+		val, isNull, err := arg.EvalJSON(b.ctx, row)
+		if isNull || err != nil {
+			return nil, isNull, err
+		}
+		res, err = types.ConvertJSONToDecimal(b.ctx.GetSessionVars().StmtCtx, val)
+		return res, false, err
+	default:
+		val, isNull, err := arg.EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return nil, isNull, err
+		}
+		res, err = b.stringToDecimal(val)
+		return res, false, err
+	}
+}
+
+func (b *builtinCastAsDecimalSig) intToDecimal(val int64) *types.MyDecimal {
+	if mysql.HasUnsignedFlag(b.args[0].GetType().Flag) {
+		return types.NewDecFromUint(uint64(val))
+	}
+	return types.NewDecFromInt(val)
+}
+
+func (b *builtinCastAsDecimalSig) stringToDecimal(val string) (*types.MyDecimal, error) {
+	sc := b.ctx.GetSessionVars().StmtCtx
+	res, err := types.StrToDecimal(sc, val)
+	if types.ErrOverflow == err {
+		warnErr := types.ErrTruncatedWrongVal.GenWithStackByArgs("DECIMAL", val)
+		return res, sc.HandleOverflow(warnErr, warnErr)
+	}
+	return res, err
+}
+
+type builtinCastAsStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastAsStringSig) Clone() builtinFunc {
+	newSig := &builtinCastAsStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastAsStringSig) evalString(row chunk.Row) (string, bool, error) {
+	res, isNull, err := b.convertArg(row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	res, err = types.ProduceStrWithSpecifiedTp(res, b.tp, b.ctx.GetSessionVars().StmtCtx)
+	return res, false, err
+}
+
+// convertArg converts the argument to a string, which isn't truncated to the field type of the cast yet.
+func (b *builtinCastAsStringSig) convertArg(row chunk.Row) (res string, isNull bool, err error) {
+	arg := b.args[0]
+	if isHybridArg(arg) {
+		return arg.EvalString(b.ctx, row)
+	}
+	switch arg.GetType().EvalType() {
+	case types.ETInt:
+		val, isNull, err := arg.EvalInt(b.ctx, row)
+		if isNull || err != nil {
+			return "", isNull, err
+		}
+		return b.intToString(val), false, nil
+	case types.ETReal:
+		val, isNull, err := arg.EvalReal(b.ctx, row)
+		if isNull || err != nil {
+			return "", isNull, err
+		}
+		return b.realToString(val), false, nil
+	case types.ETDecimal:
+		val, isNull, err := arg.EvalDecimal(b.ctx, row)
+		if isNull || err != nil {
+			return "", isNull, err
+		}
+		return val.String(), false, nil
+	case types.ETDatetime, types.ETTimestamp:
+		val, isNull, err := arg.EvalTime(b.ctx, row)
+		if isNull || err != nil {
+			return "", isNull, err
+		}
+		return val.String(), false, nil
+	case types.ETJson:
+		val, isNull, err := arg.EvalJSON(b.ctx, row)
+		if isNull || err != nil {
+			return "", isNull, err
+		}
+		return val.String(), false, nil
+	default:
+		return arg.EvalString(b.ctx, row)
+	}
+}
+
+func (b *builtinCastAsStringSig) intToString(val int64) string {
+	if mysql.HasUnsignedFlag(b.args[0].GetType().Flag) {
+		return strconv.FormatUint(uint64(val), 10)
+	}
+	return strconv.FormatInt(val, 10)
+}
+
+func (b *builtinCastAsStringSig) realToString(val float64) string {
+	if b.args[0].GetType().Tp == mysql.TypeFloat {
+		return types.StrictFormatFloat(val, 32)
+	}
+	return types.StrictFormatFloat(val, 64)
+}
+
+type builtinCastAsTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastAsTimeSig) Clone() builtinFunc {
+	newSig := &builtinCastAsTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCastAsTimeSig) evalTime(row chunk.Row) (res types.Time, isNull bool, err error) {
+	arg := b.args[0]
+	switch arg.GetType().EvalType() {
+	case types.ETDatetime, types.ETTimestamp:
+		val, isNull, err := arg.EvalTime(b.ctx, row)
+		if isNull || err != nil {
+			return res, isNull, err
+		}
+		return b.convertTime(val), false, nil
+	case types.ETString:
+		val, isNull, err := arg.EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return res, isNull, err
+		}
+		return b.parseTime(val)
+	case types.ETJson:
+		// A JSON string is parsed without the quotes.
+		val, isNull, err := arg.EvalJSON(b.ctx, row)
+		if isNull || err != nil {
+			return res, isNull, err
+		}
+		return b.parseTime(val.Unquote())
+	default:
+		// A number is parsed in the format of YYYYMMDD or YYYYMMDDHHMMSS.
+		val, isNull, err := b.castArgAsString(row)
+		if isNull || err != nil {
+			return res, isNull, err
+		}
+		return b.parseTime(val)
+	}
+}
+
+// convertTime converts the time to the type of the cast.
+func (b *builtinCastAsTimeSig) convertTime(val types.Time) types.Time {
+	if b.tp.Tp == mysql.TypeDate {
+		return types.NewTime(val.Year(), val.Month(), val.Day(), 0, 0, 0, 0, mysql.TypeDate, 0)
+	}
+	val.SetType(b.tp.Tp)
+	return val
+}
+
+func (b *builtinCastAsTimeSig) castArgAsString(row chunk.Row) (string, bool, error) {
+	sig := &builtinCastAsStringSig{b.baseBuiltinFunc}
+	return sig.convertArg(row)
+}
+
+// parseTime parses the string into a time, an invalid time is NULL with a warning when the truncation is allowed.
+// The date is checked by the sql_mode, like '2020-02-30' is invalid unless ALLOW_INVALID_DATES.
+func (b *builtinCastAsTimeSig) parseTime(s string) (res types.Time, isNull bool, err error) {
+	sc := b.ctx.GetSessionVars().StmtCtx
+	res, err = types.ParseTime(s, b.tp.Tp, getFsp(b.tp))
+	if err != nil {
+		err = types.ErrTruncatedWrongVal.GenWithStackByArgs(types.TypeStr(b.tp.Tp), s)
+		return res, true, sc.HandleTruncate(err)
+	}
+	if err = res.Check(sc); err != nil {
+		return res, true, sc.HandleTruncate(err)
+	}
+	return res, false, nil
+}
+
+// getFsp returns the fractional seconds precision of the time field type.
+func getFsp(tp *types.FieldType) int8 {
+	if tp.Decimal < int(types.MinFsp) {
+		return types.DefaultFsp
+	}
+	if tp.Decimal > int(types.MaxFsp) {
+		return types.MaxFsp
+	}
+	return int8(tp.Decimal)
+}
+
+type builtinCastAsJSONSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCastAsJSONSig) Clone() builtinFunc {
+	newSig := &builtinCastAsJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON converts the argument to a JSON value, a boolean is a JSON boolean, a number is a JSON number and
+// a string is parsed as the JSON text if the cast has the ParseToJSONFlag, other values are JSON strings of their string forms.
+func (b *builtinCastAsJSONSig) evalJSON(row chunk.Row) (res json.BinaryJSON, isNull bool, err error) {
+	arg := b.args[0]
+	if isHybridArg(arg) {
+		val, isNull, err := arg.EvalString(b.ctx, row)
+		return json.CreateBinary(val), isNull, err
+	}
+	switch arg.GetType().EvalType() {
+	case types.ETJson:
+		return arg.EvalJSON(b.ctx, row)
+	case types.ETInt:
+		val, isNull, err := arg.EvalInt(b.ctx, row)
+		if isNull || err != nil {
+			return res, isNull, err
+		}
+		// TRUE and FALSE are JSON booleans.
+		if mysql.HasIsBooleanFlag(arg.GetType().Flag) {
+			return json.CreateBinary(val != 0), false, nil
+		}
+		if mysql.HasUnsignedFlag(arg.GetType().Flag) {
+			return json.CreateBinary(uint64(val)), false, nil
+		}
+		return json.CreateBinary(val), false, nil
+	case types.ETReal:
+		val, isNull, err := arg.EvalReal(b.ctx, row)
+		if isNull || err != nil {
+			return res, isNull, err
+		}
+		return json.CreateBinary(val), false, nil
+	case types.ETDecimal:
+		val, isNull, err := arg.EvalDecimal(b.ctx, row)
+		if isNull || err != nil {
+			return res, isNull, err
+		}
+		f, err := val.ToFloat64()
+		return json.CreateBinary(f), false, err
+	case types.ETString:
+		val, isNull, err := arg.EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return res, isNull, err
+		}
+		if mysql.HasParseToJSONFlag(b.tp.Flag) {
+			res, err = json.ParseBinaryFromString(val)
+			return res, err != nil, err
+		}
+		return json.CreateBinary(val), false, nil
+	default:
+		sig := &builtinCastAsStringSig{b.baseBuiltinFunc}
+		val, isNull, err := sig.convertArg(row)
+		if isNull || err != nil {
+			return res, isNull, err
+		}
+		return json.CreateBinary(val), false, nil
+	}
+}
+
+// BuildCastFunction builds a CAST ScalarFunction from the Expression.
+func BuildCastFunction(ctx sessionctx.Context, expr Expression, tp *types.FieldType) (res Expression) {
+	var fc functionClass
+	switch tp.EvalType() {
+	case types.ETInt:
+		fc = &castAsIntFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
+	case types.ETDecimal:
+		fc = &castAsDecimalFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
+	case types.ETReal:
+		fc = &castAsRealFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
+	case types.ETDatetime, types.ETTimestamp:
+		fc = &castAsTimeFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
+	case types.ETJson:
+		fc = &castAsJSONFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
+	default:
+		fc = &castAsStringFunctionClass{baseFunctionClass{ast.Cast, 1, 1}, tp}
+	}
+	f, err := fc.getFunction(ctx, []Expression{expr})
+	terror.Log(err)
+	res = &ScalarFunction{
+		FuncName: model.NewCIStr(ast.Cast),
+		RetType:  tp,
+		Function: f,
+	}
+	return FoldConstant(res)
+}
+
+// WrapWithCastAsInt wraps `expr` with `cast` if the return type of expr is not
+// type int, otherwise, returns `expr` directly.
+func WrapWithCastAsInt(ctx sessionctx.Context, expr Expression) Expression {
+	if expr.GetType().EvalType() == types.ETInt {
+		return expr
+	}
+	tp := types.NewFieldType(mysql.TypeLonglong)
+	tp.Flen, tp.Decimal = expr.GetType().Flen, 0
+	types.SetBinChsClnFlag(tp)
+	tp.Flag |= expr.GetType().Flag & mysql.UnsignedFlag
+	return BuildCastFunction(ctx, expr, tp)
+}
+
+// WrapWithCastAsReal wraps `expr` with `cast` if the return type of expr is not
+// type real, otherwise, returns `expr` directly.
+func WrapWithCastAsReal(ctx sessionctx.Context, expr Expression) Expression {
+	if expr.GetType().EvalType() == types.ETReal {
+		return expr
+	}
+	tp := types.NewFieldType(mysql.TypeDouble)
+	tp.Flen, tp.Decimal = mysql.MaxRealWidth, types.UnspecifiedLength
+	types.SetBinChsClnFlag(tp)
+	tp.Flag |= expr.GetType().Flag & mysql.UnsignedFlag
+	return BuildCastFunction(ctx, expr, tp)
+}
+
+// WrapWithCastAsDecimal wraps `expr` with `cast` if the return type of expr is
+// not type decimal, otherwise, returns `expr` directly.
+func WrapWithCastAsDecimal(ctx sessionctx.Context, expr Expression) Expression {
+	if expr.GetType().EvalType() == types.ETDecimal {
+		return expr
+	}
+	tp := types.NewFieldType(mysql.TypeNewDecimal)
+	tp.Flen, tp.Decimal = expr.GetType().Flen, expr.GetType().Decimal
+	if expr.GetType().EvalType() == types.ETInt {
+		tp.Flen = mysql.MaxIntWidth
+	}
+	types.SetBinChsClnFlag(tp)
+	tp.Flag |= expr.GetType().Flag & mysql.UnsignedFlag
+	return BuildCastFunction(ctx, expr, tp)
+}
+
+// WrapWithCastAsString wraps `expr` with `cast` if the return type of expr is
+// not type string, otherwise, returns `expr` directly.
+func WrapWithCastAsString(ctx sessionctx.Context, expr Expression) Expression {
+	exprTp := expr.GetType()
+	if exprTp.EvalType() == types.ETString {
+		return expr
+	}
+	argLen := exprTp.Flen
+	// If expr is decimal, we should take the decimal point and negative sign
+	// into consideration, so we set `expr.GetType().Flen + 2` as the `argLen`.
+	// Since the length of float and double is not accurate, the length is
+	// unspecified for them, or the string may be truncated.
+	if exprTp.Tp == mysql.TypeNewDecimal && argLen != types.UnspecifiedLength {
+		argLen += 2
+	}
+	if exprTp.EvalType() == types.ETReal {
+		argLen = types.UnspecifiedLength
+	}
+	if exprTp.EvalType() == types.ETInt {
+		argLen = mysql.MaxIntWidth
+	}
+	tp := types.NewFieldType(mysql.TypeVarString)
+	tp.Charset, tp.Collate = charset.CharsetUTF8MB4, charset.CollationUTF8MB4
+	tp.Flen, tp.Decimal = argLen, types.UnspecifiedLength
+	return BuildCastFunction(ctx, expr, tp)
+}
+
+// WrapWithCastAsTime wraps `expr` with `cast` if the return type of expr is not
+// same as type of the specified `tp` , otherwise, returns `expr` directly.
+func WrapWithCastAsTime(ctx sessionctx.Context, expr Expression, tp *types.FieldType) Expression {
+	exprTp := expr.GetType().Tp
+	if tp.Tp == exprTp {
+		return expr
+	} else if (exprTp == mysql.TypeDate || exprTp == mysql.TypeTimestamp) && tp.Tp == mysql.TypeDatetime {
+		return expr
+	}
+	switch x := expr.GetType(); x.Tp {
+	case mysql.TypeDatetime, mysql.TypeTimestamp, mysql.TypeDate:
+		tp.Decimal = x.Decimal
+	default:
+		tp.Decimal = int(types.MaxFsp)
+	}
+	switch tp.Tp {
+	case mysql.TypeDate:
+		tp.Flen = mysql.MaxDateWidth
+	case mysql.TypeDatetime, mysql.TypeTimestamp:
+		tp.Flen = mysql.MaxDatetimeWidthNoFsp
+		if tp.Decimal > 0 {
+			tp.Flen = tp.Flen + 1 + tp.Decimal
+		}
+	}
+	types.SetBinChsClnFlag(tp)
+	return BuildCastFunction(ctx, expr, tp)
+}
+
+// WrapWithCastAsJSON wraps `expr` with `cast` if the return type of expr is not
+// type json, otherwise, returns `expr` directly. A string is parsed as the JSON text.
+func WrapWithCastAsJSON(ctx sessionctx.Context, expr Expression) Expression {
+	return wrapWithCastAsJSON(ctx, expr, mysql.ParseToJSONFlag)
+}
+
+// WrapWithCastAsJSONValue wraps `expr` with `cast` as WrapWithCastAsJSON does except that a string is a JSON
+// string, it's used by the values to be put into a JSON and the values compared with a JSON.
+func WrapWithCastAsJSONValue(ctx sessionctx.Context, expr Expression) Expression {
+	return wrapWithCastAsJSON(ctx, expr, 0)
+}
+
+func wrapWithCastAsJSON(ctx sessionctx.Context, expr Expression, flag uint) Expression {
+	if expr.GetType().EvalType() == types.ETJson {
+		return expr
+	}
+	tp := types.NewFieldType(mysql.TypeJSON)
+	tp.Flen, tp.Decimal = mysql.MaxBlobWidth, 0
+	types.SetBinChsClnFlag(tp)
+	tp.Flag |= flag
+	return BuildCastFunction(ctx, expr, tp)
+}
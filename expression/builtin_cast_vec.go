@@ -0,0 +1,224 @@
+package expression
+
+import (
+	"grant-db/types"
+	"grant-db/util/chunk"
+)
+
+// vecEvalArg evaluates the only argument of the cast by vectors into a buffer of its evaluation type,
+// which should be put back after using.
+func (b *baseBuiltinFunc) vecEvalArg(input *chunk.Chunk) (*chunk.Column, types.EvalType, error) {
+	evalType := b.args[0].GetType().EvalType()
+	buf := b.bufAllocator.get(evalType, input.NumRows())
+	if err := vecEval(b.ctx, b.args[0], input, buf); err != nil {
+		b.bufAllocator.put(evalType, buf)
+		return nil, evalType, err
+	}
+	return buf, evalType, nil
+}
+
+// isVecCastArg checks whether the argument is converted by vectors, the hybrid, TIME and JSON values are
+// converted row by row.
+func isVecCastArg(arg Expression) bool {
+	evalType := arg.GetType().EvalType()
+	return !isHybridArg(arg) && evalType != types.ETDuration && evalType != types.ETJson
+}
+
+func (b *builtinCastAsIntSig) vectorized() bool {
+	return isVecCastArg(b.args[0])
+}
+
+func (b *builtinCastAsIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	if b.args[0].GetType().EvalType() == types.ETInt {
+		return b.args[0].VecEvalInt(b.ctx, input, result)
+	}
+	buf, evalType, err := b.vecEvalArg(input)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(evalType, buf)
+	n := input.NumRows()
+	result.ResizeInt64(n, false)
+	result.MergeNulls(buf)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		switch evalType {
+		case types.ETReal:
+			i64s[i], err = b.realToInt(buf.GetFloat64(i))
+		case types.ETDecimal:
+			i64s[i], err = b.decimalToInt(buf.GetDecimal(i))
+		case types.ETDatetime, types.ETTimestamp:
+			i64s[i], err = b.decimalToInt(buf.GetTime(i).ToNumber())
+		default:
+			i64s[i], err = b.stringToInt(buf.GetString(i))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *builtinCastAsRealSig) vectorized() bool {
+	return isVecCastArg(b.args[0])
+}
+
+func (b *builtinCastAsRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	if b.args[0].GetType().EvalType() == types.ETReal {
+		return b.args[0].VecEvalReal(b.ctx, input, result)
+	}
+	buf, evalType, err := b.vecEvalArg(input)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(evalType, buf)
+	n := input.NumRows()
+	result.ResizeFloat64(n, false)
+	result.MergeNulls(buf)
+	f64s := result.Float64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		switch evalType {
+		case types.ETInt:
+			f64s[i] = b.intToReal(buf.GetInt64(i))
+		case types.ETDecimal:
+			f64s[i], err = buf.GetDecimal(i).ToFloat64()
+		case types.ETDatetime, types.ETTimestamp:
+			f64s[i], err = buf.GetTime(i).ToNumber().ToFloat64()
+		default:
+			f64s[i], err = b.stringToReal(buf.GetString(i))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *builtinCastAsDecimalSig) vectorized() bool {
+	return isVecCastArg(b.args[0])
+}
+
+func (b *builtinCastAsDecimalSig) vecEvalDecimal(input *chunk.Chunk, result *chunk.Column) error {
+	buf, evalType, err := b.vecEvalArg(input)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(evalType, buf)
+	sc := b.ctx.GetSessionVars().StmtCtx
+	n := input.NumRows()
+	result.ReserveDecimal(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		var res *types.MyDecimal
+		switch evalType {
+		case types.ETInt:
+			res = b.intToDecimal(buf.GetInt64(i))
+		case types.ETReal:
+			res = new(types.MyDecimal)
+			err = res.FromFloat64(buf.GetFloat64(i))
+		case types.ETDecimal:
+			res = buf.GetDecimal(i)
+		case types.ETDatetime, types.ETTimestamp:
+			res = buf.GetTime(i).ToNumber()
+		default:
+			res, err = b.stringToDecimal(buf.GetString(i))
+		}
+		if err != nil {
+			return err
+		}
+		if res, err = types.ProduceDecWithSpecifiedTp(res, b.tp, sc); err != nil {
+			return err
+		}
+		result.AppendMyDecimal(res)
+	}
+	return nil
+}
+
+func (b *builtinCastAsStringSig) vectorized() bool {
+	return isVecCastArg(b.args[0])
+}
+
+func (b *builtinCastAsStringSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	buf, evalType, err := b.vecEvalArg(input)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(evalType, buf)
+	sc := b.ctx.GetSessionVars().StmtCtx
+	n := input.NumRows()
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		var res string
+		switch evalType {
+		case types.ETInt:
+			res = b.intToString(buf.GetInt64(i))
+		case types.ETReal:
+			res = b.realToString(buf.GetFloat64(i))
+		case types.ETDecimal:
+			res = buf.GetDecimal(i).String()
+		case types.ETDatetime, types.ETTimestamp:
+			res = buf.GetTime(i).String()
+		default:
+			res = buf.GetString(i)
+		}
+		if res, err = types.ProduceStrWithSpecifiedTp(res, b.tp, sc); err != nil {
+			return err
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
+
+// vectorized reports whether the cast is vectorized, a number is cast through its string form
+// row by row.
+func (b *builtinCastAsTimeSig) vectorized() bool {
+	switch b.args[0].GetType().EvalType() {
+	case types.ETDatetime, types.ETTimestamp, types.ETString:
+		return true
+	}
+	return false
+}
+
+func (b *builtinCastAsTimeSig) vecEvalTime(input *chunk.Chunk, result *chunk.Column) error {
+	buf, evalType, err := b.vecEvalArg(input)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(evalType, buf)
+	n := input.NumRows()
+	result.ResizeTime(n, false)
+	result.MergeNulls(buf)
+	times := result.Times()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		if evalType == types.ETString {
+			res, isNull, err := b.parseTime(buf.GetString(i))
+			if err != nil {
+				return err
+			}
+			if isNull {
+				result.SetNull(i, true)
+				continue
+			}
+			times[i] = res
+			continue
+		}
+		times[i] = b.convertTime(buf.GetTime(i))
+	}
+	return nil
+}
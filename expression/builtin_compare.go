@@ -0,0 +1,461 @@
+package expression
+
+import (
+	"math"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/opcode"
+	"grant-db/sessionctx"
+	"grant-db/types"
+	"grant-db/types/json"
+	"grant-db/util/chunk"
+	"grant-db/util/collate"
+)
+
+// isStringKind returns whether the values of the eval type are compared as strings by default.
+func isStringKind(et types.EvalType) bool {
+	return et == types.ETString || et == types.ETDatetime || et == types.ETTimestamp || et == types.ETDuration || et == types.ETJson
+}
+
+func isTemporalKind(et types.EvalType) bool {
+	return et == types.ETDatetime || et == types.ETTimestamp
+}
+
+// getBaseCmpType gets the EvalType that the two args will be treated as when comparing.
+func getBaseCmpType(lhs, rhs types.EvalType) types.EvalType {
+	if isStringKind(lhs) && isStringKind(rhs) {
+		return types.ETString
+	} else if lhs == types.ETInt && rhs == types.ETInt {
+		return types.ETInt
+	} else if (lhs == types.ETInt || lhs == types.ETDecimal) && (rhs == types.ETInt || rhs == types.ETDecimal) {
+		return types.ETDecimal
+	}
+	return types.ETReal
+}
+
+// GetAccurateCmpType uses a more complex logic to decide the EvalType of the two args when compare with each other than
+// getBaseCmpType does, a temporal value compared with a string or another temporal value is compared as a datetime,
+// and any value compared with a JSON is compared as a JSON.
+func GetAccurateCmpType(lhs, rhs Expression) types.EvalType {
+	lhsEvalType, rhsEvalType := lhs.GetType().EvalType(), rhs.GetType().EvalType()
+	// A NULL literal is compared as the type of the other side.
+	if lhs.GetType().Tp == mysql.TypeNull {
+		lhsEvalType = rhsEvalType
+	} else if rhs.GetType().Tp == mysql.TypeNull {
+		rhsEvalType = lhsEvalType
+	}
+	if lhsEvalType == types.ETJson || rhsEvalType == types.ETJson {
+		return types.ETJson
+	}
+	cmpType := getBaseCmpType(lhsEvalType, rhsEvalType)
+	if cmpType == types.ETString && (isTemporalKind(lhsEvalType) || isTemporalKind(rhsEvalType)) {
+		cmpType = types.ETDatetime
+	}
+	return cmpType
+}
+
+// compareInt compares two integers with their unsigned flags.
+func compareInt(a int64, isUnsignedA bool, b int64, isUnsignedB bool) int {
+	switch {
+	case isUnsignedA && isUnsignedB:
+		return compareUint(uint64(a), uint64(b))
+	case isUnsignedA && !isUnsignedB:
+		if b < 0 || uint64(a) > math.MaxInt64 {
+			return 1
+		}
+	case !isUnsignedA && isUnsignedB:
+		if a < 0 || uint64(b) > math.MaxInt64 {
+			return -1
+		}
+	}
+	return compareSigned(a, b)
+}
+
+func compareSigned(a, b int64) int {
+	if a < b {
+		return -1
+	} else if a == b {
+		return 0
+	}
+	return 1
+}
+
+func compareUint(a, b uint64) int {
+	if a < b {
+		return -1
+	} else if a == b {
+		return 0
+	}
+	return 1
+}
+
+func compareFloat64(a, b float64) int {
+	if a < b {
+		return -1
+	} else if a == b {
+		return 0
+	}
+	return 1
+}
+
+// resOfCmp returns the result of the comparison op for the compared result res.
+func resOfCmp(op opcode.Op, res int) int64 {
+	var ok bool
+	switch op {
+	case opcode.LT:
+		ok = res < 0
+	case opcode.LE:
+		ok = res <= 0
+	case opcode.GT:
+		ok = res > 0
+	case opcode.GE:
+		ok = res >= 0
+	case opcode.EQ, opcode.NullEQ:
+		ok = res == 0
+	case opcode.NE:
+		ok = res != 0
+	}
+	if ok {
+		return 1
+	}
+	return 0
+}
+
+// cmpOps maps the comparison function name to its opcode.
+var cmpOps = map[string]opcode.Op{
+	ast.LT:     opcode.LT,
+	ast.LE:     opcode.LE,
+	ast.GT:     opcode.GT,
+	ast.GE:     opcode.GE,
+	ast.EQ:     opcode.EQ,
+	ast.NE:     opcode.NE,
+	ast.NullEQ: opcode.NullEQ,
+}
+
+type compareFunctionClass struct {
+	baseFunctionClass
+}
+
+// getFunction sets compare built-in function signatures for various types.
+func (c *compareFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	cmpType := GetAccurateCmpType(args[0], args[1])
+	return newCmpSig(ctx, cmpOps[c.funcName], args, cmpType), nil
+}
+
+func newCmpSig(ctx sessionctx.Context, op opcode.Op, args []Expression, tp types.EvalType) builtinFunc {
+	argTps := make([]types.EvalType, len(args))
+	for i := range argTps {
+		argTps[i] = tp
+	}
+	wrapJSONCmpArgs(ctx, args, tp)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, argTps...)
+	bf.tp.Flen = 1
+	cmp := baseBuiltinCompareFunc{bf, op}
+	switch tp {
+	case types.ETInt:
+		return &builtinCompareIntSig{cmp}
+	case types.ETReal:
+		return &builtinCompareRealSig{cmp}
+	case types.ETDecimal:
+		return &builtinCompareDecimalSig{cmp}
+	case types.ETDatetime, types.ETTimestamp:
+		return &builtinCompareTimeSig{cmp}
+	case types.ETJson:
+		return &builtinCompareJSONSig{cmp}
+	default:
+		return &builtinCompareStringSig{cmp}
+	}
+}
+
+// wrapJSONCmpArgs wraps the arguments compared as JSON with casts, a string compared with a JSON is
+// a JSON string rather than a JSON text.
+func wrapJSONCmpArgs(ctx sessionctx.Context, args []Expression, tp types.EvalType) {
+	if tp != types.ETJson {
+		return
+	}
+	for i := range args {
+		args[i] = WrapWithCastAsJSONValue(ctx, args[i])
+	}
+}
+
+// baseBuiltinCompareFunc is the base of the comparison signatures, every signature compares
+// its two arguments of one evaluation type.
+type baseBuiltinCompareFunc struct {
+	baseBuiltinFunc
+
+	op opcode.Op
+}
+
+// cmpResult turns the compared result into the result of the comparison, a NULL argument results
+// in NULL, except that `<=>` is true when both arguments are NULL.
+func (b *baseBuiltinCompareFunc) cmpResult(res int, isNull0, isNull1 bool, err error) (int64, bool, error) {
+	if err != nil {
+		return 0, true, err
+	}
+	if b.op == opcode.NullEQ {
+		if isNull0 || isNull1 {
+			if isNull0 && isNull1 {
+				return 1, false, nil
+			}
+			return 0, false, nil
+		}
+		return resOfCmp(b.op, res), false, nil
+	}
+	if isNull0 || isNull1 {
+		return 0, true, nil
+	}
+	return resOfCmp(b.op, res), false, nil
+}
+
+func (b *baseBuiltinCompareFunc) cloneFrom(from *baseBuiltinCompareFunc) {
+	b.baseBuiltinFunc.cloneFrom(&from.baseBuiltinFunc)
+	b.op = from.op
+}
+
+type builtinCompareIntSig struct {
+	baseBuiltinCompareFunc
+}
+
+func (b *builtinCompareIntSig) Clone() builtinFunc {
+	newSig := &builtinCompareIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinCompareFunc)
+	return newSig
+}
+
+func (b *builtinCompareIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	return b.cmpResult(compareIntValues(b.ctx, row, b.args[0], b.args[1]))
+}
+
+type builtinCompareRealSig struct {
+	baseBuiltinCompareFunc
+}
+
+func (b *builtinCompareRealSig) Clone() builtinFunc {
+	newSig := &builtinCompareRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinCompareFunc)
+	return newSig
+}
+
+func (b *builtinCompareRealSig) evalInt(row chunk.Row) (int64, bool, error) {
+	return b.cmpResult(compareRealValues(b.ctx, row, b.args[0], b.args[1]))
+}
+
+type builtinCompareDecimalSig struct {
+	baseBuiltinCompareFunc
+}
+
+func (b *builtinCompareDecimalSig) Clone() builtinFunc {
+	newSig := &builtinCompareDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinCompareFunc)
+	return newSig
+}
+
+func (b *builtinCompareDecimalSig) evalInt(row chunk.Row) (int64, bool, error) {
+	return b.cmpResult(compareDecimalValues(b.ctx, row, b.args[0], b.args[1]))
+}
+
+type builtinCompareStringSig struct {
+	baseBuiltinCompareFunc
+}
+
+func (b *builtinCompareStringSig) Clone() builtinFunc {
+	newSig := &builtinCompareStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinCompareFunc)
+	return newSig
+}
+
+func (b *builtinCompareStringSig) evalInt(row chunk.Row) (int64, bool, error) {
+	return b.cmpResult(compareStringValues(b.ctx, row, b.args[0], b.args[1], b.collator()))
+}
+
+type builtinCompareTimeSig struct {
+	baseBuiltinCompareFunc
+}
+
+func (b *builtinCompareTimeSig) Clone() builtinFunc {
+	newSig := &builtinCompareTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinCompareFunc)
+	return newSig
+}
+
+func (b *builtinCompareTimeSig) evalInt(row chunk.Row) (int64, bool, error) {
+	return b.cmpResult(compareTimeValues(b.ctx, row, b.args[0], b.args[1]))
+}
+
+type builtinCompareJSONSig struct {
+	baseBuiltinCompareFunc
+}
+
+func (b *builtinCompareJSONSig) Clone() builtinFunc {
+	newSig := &builtinCompareJSONSig{}
+	newSig.cloneFrom(&b.baseBuiltinCompareFunc)
+	return newSig
+}
+
+func (b *builtinCompareJSONSig) evalInt(row chunk.Row) (int64, bool, error) {
+	return b.cmpResult(compareJSONValues(b.ctx, row, b.args[0], b.args[1]))
+}
+
+// valuesComparer evaluates lhs and rhs as one evaluation type on the row and compares them,
+// the compared result is meaningful only if neither of them is NULL.
+type valuesComparer func(ctx sessionctx.Context, row chunk.Row, lhs, rhs Expression) (res int, isNull0, isNull1 bool, err error)
+
+// getValuesComparer returns the valuesComparer of the evaluation type, the strings are compared by the collator.
+func getValuesComparer(tp types.EvalType, collator collate.Collator) valuesComparer {
+	switch tp {
+	case types.ETInt:
+		return compareIntValues
+	case types.ETReal:
+		return compareRealValues
+	case types.ETDecimal:
+		return compareDecimalValues
+	case types.ETDatetime, types.ETTimestamp:
+		return compareTimeValues
+	case types.ETJson:
+		return compareJSONValues
+	default:
+		return func(ctx sessionctx.Context, row chunk.Row, lhs, rhs Expression) (int, bool, bool, error) {
+			return compareStringValues(ctx, row, lhs, rhs, collator)
+		}
+	}
+}
+
+func compareIntValues(ctx sessionctx.Context, row chunk.Row, lhs, rhs Expression) (res int, isNull0, isNull1 bool, err error) {
+	arg0, isNull0, err := lhs.EvalInt(ctx, row)
+	if err != nil {
+		return 0, true, true, err
+	}
+	arg1, isNull1, err := rhs.EvalInt(ctx, row)
+	if err != nil || isNull0 || isNull1 {
+		return 0, isNull0, isNull1, err
+	}
+	isUnsigned0, isUnsigned1 := mysql.HasUnsignedFlag(lhs.GetType().Flag), mysql.HasUnsignedFlag(rhs.GetType().Flag)
+	return compareInt(arg0, isUnsigned0, arg1, isUnsigned1), false, false, nil
+}
+
+func compareRealValues(ctx sessionctx.Context, row chunk.Row, lhs, rhs Expression) (res int, isNull0, isNull1 bool, err error) {
+	arg0, isNull0, err := lhs.EvalReal(ctx, row)
+	if err != nil {
+		return 0, true, true, err
+	}
+	arg1, isNull1, err := rhs.EvalReal(ctx, row)
+	if err != nil || isNull0 || isNull1 {
+		return 0, isNull0, isNull1, err
+	}
+	return compareFloat64(arg0, arg1), false, false, nil
+}
+
+func compareDecimalValues(ctx sessionctx.Context, row chunk.Row, lhs, rhs Expression) (res int, isNull0, isNull1 bool, err error) {
+	arg0, isNull0, err := lhs.EvalDecimal(ctx, row)
+	if err != nil {
+		return 0, true, true, err
+	}
+	arg1, isNull1, err := rhs.EvalDecimal(ctx, row)
+	if err != nil || isNull0 || isNull1 {
+		return 0, isNull0, isNull1, err
+	}
+	return arg0.Compare(arg1), false, false, nil
+}
+
+func compareStringValues(ctx sessionctx.Context, row chunk.Row, lhs, rhs Expression, collator collate.Collator) (res int, isNull0, isNull1 bool, err error) {
+	arg0, isNull0, err := lhs.EvalString(ctx, row)
+	if err != nil {
+		return 0, true, true, err
+	}
+	arg1, isNull1, err := rhs.EvalString(ctx, row)
+	if err != nil || isNull0 || isNull1 {
+		return 0, isNull0, isNull1, err
+	}
+	return collator.Compare(arg0, arg1), false, false, nil
+}
+
+func compareTimeValues(ctx sessionctx.Context, row chunk.Row, lhs, rhs Expression) (res int, isNull0, isNull1 bool, err error) {
+	arg0, isNull0, err := lhs.EvalTime(ctx, row)
+	if err != nil {
+		return 0, true, true, err
+	}
+	arg1, isNull1, err := rhs.EvalTime(ctx, row)
+	if err != nil || isNull0 || isNull1 {
+		return 0, isNull0, isNull1, err
+	}
+	return arg0.Compare(arg1), false, false, nil
+}
+
+func compareJSONValues(ctx sessionctx.Context, row chunk.Row, lhs, rhs Expression) (res int, isNull0, isNull1 bool, err error) {
+	arg0, isNull0, err := lhs.EvalJSON(ctx, row)
+	if err != nil {
+		return 0, true, true, err
+	}
+	arg1, isNull1, err := rhs.EvalJSON(ctx, row)
+	if err != nil || isNull0 || isNull1 {
+		return 0, isNull0, isNull1, err
+	}
+	return json.CompareBinary(arg0, arg1), false, false, nil
+}
+
+// inFunctionClass is the function class of `expr IN (v1, v2, ...)`.
+type inFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *inFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	// All the values are compared with the same type, the values of different types are compared as reals.
+	cmpType := GetAccurateCmpType(args[0], args[1])
+	for _, arg := range args[2:] {
+		if GetAccurateCmpType(args[0], arg) != cmpType {
+			cmpType = types.ETReal
+			break
+		}
+	}
+	argTps := make([]types.EvalType, len(args))
+	for i := range argTps {
+		argTps[i] = cmpType
+	}
+	wrapJSONCmpArgs(ctx, args, cmpType)
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, argTps...)
+	bf.tp.Flen = 1
+	return &builtinInSig{bf, cmpType}, nil
+}
+
+// builtinInSig evaluates `expr IN (v1, v2, ...)` by comparing expr with every value in order,
+// the result is NULL if no value matches and any of them is NULL.
+type builtinInSig struct {
+	baseBuiltinFunc
+
+	cmpType types.EvalType
+}
+
+func (b *builtinInSig) Clone() builtinFunc {
+	newSig := &builtinInSig{cmpType: b.cmpType}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinInSig) evalInt(row chunk.Row) (int64, bool, error) {
+	compare := getValuesComparer(b.cmpType, b.collator())
+	hasNull := false
+	for _, arg := range b.args[1:] {
+		res, isNull0, isNull1, err := compare(b.ctx, row, b.args[0], arg)
+		if err != nil {
+			return 0, true, err
+		}
+		if isNull0 {
+			return 0, true, nil
+		}
+		if isNull1 {
+			hasNull = true
+			continue
+		}
+		if res == 0 {
+			return 1, false, nil
+		}
+	}
+	return 0, hasNull, nil
+}
@@ -0,0 +1,209 @@
+package expression
+
+import (
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/opcode"
+	"grant-db/types"
+	"grant-db/types/json"
+	"grant-db/util/chunk"
+	"grant-db/util/collate"
+)
+
+// vecEvalCmpArgs evaluates the two arguments of b as the evaluation type by vectors into two buffers,
+// which should be put back after using.
+func (b *baseBuiltinCompareFunc) vecEvalCmpArgs(input *chunk.Chunk, tp types.EvalType) (buf0, buf1 *chunk.Column, err error) {
+	n := input.NumRows()
+	buf0 = b.bufAllocator.get(tp, n)
+	if err = vecEval(b.ctx, b.args[0], input, buf0); err != nil {
+		b.bufAllocator.put(tp, buf0)
+		return nil, nil, err
+	}
+	buf1 = b.bufAllocator.get(tp, n)
+	if err = vecEval(b.ctx, b.args[1], input, buf1); err != nil {
+		b.bufAllocator.put(tp, buf0)
+		b.bufAllocator.put(tp, buf1)
+		return nil, nil, err
+	}
+	return buf0, buf1, nil
+}
+
+// vecCmpResult sets the results of the comparison of n rows into result, cmp compares the two
+// arguments of the i-th row, which are both not NULL.
+func (b *baseBuiltinCompareFunc) vecCmpResult(n int, buf0, buf1, result *chunk.Column, cmp func(i int) int) {
+	result.ResizeInt64(n, false)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		isNull0, isNull1 := buf0.IsNull(i), buf1.IsNull(i)
+		if isNull0 || isNull1 {
+			if b.op != opcode.NullEQ {
+				result.SetNull(i, true)
+			} else if isNull0 && isNull1 {
+				i64s[i] = 1
+			} else {
+				i64s[i] = 0
+			}
+			continue
+		}
+		i64s[i] = resOfCmp(b.op, cmp(i))
+	}
+}
+
+func (b *builtinCompareIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCompareIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	buf0, buf1, err := b.vecEvalCmpArgs(input, types.ETInt)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(types.ETInt, buf0)
+	defer b.bufAllocator.put(types.ETInt, buf1)
+	isUnsigned0, isUnsigned1 := mysql.HasUnsignedFlag(b.args[0].GetType().Flag), mysql.HasUnsignedFlag(b.args[1].GetType().Flag)
+	arg0s, arg1s := buf0.Int64s(), buf1.Int64s()
+	b.vecCmpResult(input.NumRows(), buf0, buf1, result, func(i int) int {
+		return compareInt(arg0s[i], isUnsigned0, arg1s[i], isUnsigned1)
+	})
+	return nil
+}
+
+func (b *builtinCompareRealSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCompareRealSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	buf0, buf1, err := b.vecEvalCmpArgs(input, types.ETReal)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(types.ETReal, buf0)
+	defer b.bufAllocator.put(types.ETReal, buf1)
+	arg0s, arg1s := buf0.Float64s(), buf1.Float64s()
+	b.vecCmpResult(input.NumRows(), buf0, buf1, result, func(i int) int {
+		return compareFloat64(arg0s[i], arg1s[i])
+	})
+	return nil
+}
+
+func (b *builtinCompareDecimalSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCompareDecimalSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	buf0, buf1, err := b.vecEvalCmpArgs(input, types.ETDecimal)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(types.ETDecimal, buf0)
+	defer b.bufAllocator.put(types.ETDecimal, buf1)
+	b.vecCmpResult(input.NumRows(), buf0, buf1, result, func(i int) int {
+		return buf0.GetDecimal(i).Compare(buf1.GetDecimal(i))
+	})
+	return nil
+}
+
+func (b *builtinCompareStringSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCompareStringSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	buf0, buf1, err := b.vecEvalCmpArgs(input, types.ETString)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(types.ETString, buf0)
+	defer b.bufAllocator.put(types.ETString, buf1)
+	collator := b.collator()
+	b.vecCmpResult(input.NumRows(), buf0, buf1, result, func(i int) int {
+		return collator.Compare(buf0.GetString(i), buf1.GetString(i))
+	})
+	return nil
+}
+
+func (b *builtinCompareTimeSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinCompareTimeSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	buf0, buf1, err := b.vecEvalCmpArgs(input, types.ETDatetime)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(types.ETDatetime, buf0)
+	defer b.bufAllocator.put(types.ETDatetime, buf1)
+	arg0s, arg1s := buf0.Times(), buf1.Times()
+	b.vecCmpResult(input.NumRows(), buf0, buf1, result, func(i int) int {
+		return arg0s[i].Compare(arg1s[i])
+	})
+	return nil
+}
+
+// compareBufRow compares the i-th values of the two not NULL buffers of the evaluation type, the strings
+// are compared by the collator.
+func compareBufRow(tp types.EvalType, buf0, buf1 *chunk.Column, i int, isUnsigned0, isUnsigned1 bool, collator collate.Collator) int {
+	switch tp {
+	case types.ETInt:
+		return compareInt(buf0.GetInt64(i), isUnsigned0, buf1.GetInt64(i), isUnsigned1)
+	case types.ETReal:
+		return compareFloat64(buf0.GetFloat64(i), buf1.GetFloat64(i))
+	case types.ETDecimal:
+		return buf0.GetDecimal(i).Compare(buf1.GetDecimal(i))
+	case types.ETDatetime, types.ETTimestamp:
+		return buf0.GetTime(i).Compare(buf1.GetTime(i))
+	case types.ETJson:
+		return json.CompareBinary(buf0.GetJSON(i), buf1.GetJSON(i))
+	default:
+		return collator.Compare(buf0.GetString(i), buf1.GetString(i))
+	}
+}
+
+func (b *builtinInSig) vectorized() bool {
+	return true
+}
+
+// vecEvalInt evaluates the IN by vectors, the values are evaluated one by one and compared with the
+// rows that are not matched yet.
+func (b *builtinInSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf0 := b.bufAllocator.get(b.cmpType, n)
+	defer b.bufAllocator.put(b.cmpType, buf0)
+	if err := vecEval(b.ctx, b.args[0], input, buf0); err != nil {
+		return err
+	}
+	buf1 := b.bufAllocator.get(b.cmpType, n)
+	defer b.bufAllocator.put(b.cmpType, buf1)
+	result.ResizeInt64(n, false)
+	result.MergeNulls(buf0)
+	i64s := result.Int64s()
+	for i := range i64s {
+		i64s[i] = 0
+	}
+	// hasNull marks the rows which meet a NULL value.
+	hasNull := make([]bool, n)
+	isUnsigned0 := mysql.HasUnsignedFlag(b.args[0].GetType().Flag)
+	collator := b.collator()
+	for _, arg := range b.args[1:] {
+		if err := vecEval(b.ctx, arg, input, buf1); err != nil {
+			return err
+		}
+		isUnsigned1 := mysql.HasUnsignedFlag(arg.GetType().Flag)
+		for i := 0; i < n; i++ {
+			if result.IsNull(i) || i64s[i] == 1 {
+				continue
+			}
+			if buf1.IsNull(i) {
+				hasNull[i] = true
+				continue
+			}
+			if compareBufRow(b.cmpType, buf0, buf1, i, isUnsigned0, isUnsigned1, collator) == 0 {
+				i64s[i] = 1
+			}
+		}
+	}
+	for i := 0; i < n; i++ {
+		if hasNull[i] && !result.IsNull(i) && i64s[i] == 0 {
+			result.SetNull(i, true)
+		}
+	}
+	return nil
+}
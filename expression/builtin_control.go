@@ -0,0 +1,432 @@
+package expression
+
+import (
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/charset"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/sessionctx"
+	"grant-db/types"
+	"grant-db/util/chunk"
+)
+
+// IF and CASE share the signatures of CASE WHEN, whose arguments are pairs of a condition and a result
+// followed by an optional ELSE result, IF(c, a, b) is CASE WHEN c THEN a ELSE b END.
+// IFNULL shares the signatures of COALESCE, IFNULL(a, b) is COALESCE(a, b).
+
+// InferType4ControlFuncs infers the result type of the control functions from the types of the result
+// arguments, the NULL literals are ignored.
+func InferType4ControlFuncs(args ...Expression) *types.FieldType {
+	var (
+		evalType    types.EvalType
+		found       bool
+		allUnsigned = true
+		allDate     = true
+		hasBinary   bool
+		intDigits   int
+		frac        int
+		flen        int
+	)
+	for _, arg := range args {
+		ft := arg.GetType()
+		if ft.Tp == mysql.TypeNull {
+			continue
+		}
+		argType := ft.EvalType()
+		if argType == types.ETTimestamp {
+			argType = types.ETDatetime
+		}
+		if !found {
+			evalType, found = argType, true
+		} else if evalType != argType {
+			evalType = mergeControlEvalType(evalType, argType)
+		}
+		allUnsigned = allUnsigned && mysql.HasUnsignedFlag(ft.Flag)
+		allDate = allDate && ft.Tp == mysql.TypeDate
+		hasBinary = hasBinary || (types.IsString(ft.Tp) && ft.Charset == charset.CharsetBin)
+		if ft.Decimal > 0 && ft.Decimal != types.UnspecifiedLength {
+			frac = maxInt(frac, ft.Decimal)
+			intDigits = maxInt(intDigits, ft.Flen-ft.Decimal)
+		} else {
+			intDigits = maxInt(intDigits, ft.Flen)
+		}
+		flen = maxInt(flen, ft.Flen)
+	}
+	if !found {
+		evalType = types.ETString
+	}
+	var tp *types.FieldType
+	switch evalType {
+	case types.ETInt:
+		tp = types.NewFieldType(mysql.TypeLonglong)
+		tp.Flen, tp.Decimal = mysql.MaxIntWidth, 0
+		if allUnsigned {
+			tp.Flag |= mysql.UnsignedFlag
+		}
+	case types.ETReal:
+		tp = types.NewFieldType(mysql.TypeDouble)
+		tp.Flen, tp.Decimal = mysql.MaxRealWidth, types.UnspecifiedLength
+	case types.ETDecimal:
+		tp = types.NewFieldType(mysql.TypeNewDecimal)
+		tp.Decimal = minInt(frac, mysql.MaxDecimalScale)
+		tp.Flen = minInt(intDigits+tp.Decimal, mysql.MaxDecimalWidth)
+	case types.ETDatetime:
+		tp = types.NewFieldType(mysql.TypeDatetime)
+		if allDate {
+			tp.Tp = mysql.TypeDate
+		}
+		tp.Decimal = frac
+		tp.Flen = mysql.MaxDatetimeWidthNoFsp
+		if allDate {
+			tp.Flen = mysql.MaxDateWidth
+		} else if frac > 0 {
+			tp.Flen += 1 + frac
+		}
+	default:
+		tp = types.NewFieldType(mysql.TypeVarString)
+		tp.Flen, tp.Decimal = flen, types.UnspecifiedLength
+		if flen <= 0 {
+			tp.Flen = types.UnspecifiedLength
+		}
+		tp.Charset, tp.Collate = charset.CharsetUTF8MB4, charset.CollationUTF8MB4
+		if hasBinary {
+			tp.Charset, tp.Collate = charset.CharsetBin, charset.CollationBin
+		}
+		return tp
+	}
+	types.SetBinChsClnFlag(tp)
+	return tp
+}
+
+// mergeControlEvalType merges two different evaluation types of the results, the numbers are merged into the
+// most accurate numeric type, and a number or string mixed with a temporal value is a string.
+func mergeControlEvalType(a, b types.EvalType) types.EvalType {
+	isNumber := func(tp types.EvalType) bool {
+		return tp == types.ETInt || tp == types.ETReal || tp == types.ETDecimal
+	}
+	switch {
+	case isNumber(a) && isNumber(b):
+		if a == types.ETReal || b == types.ETReal {
+			return types.ETReal
+		}
+		return types.ETDecimal
+	}
+	return types.ETString
+}
+
+// wrapWithIsTrue wraps the condition with IS TRUE unless it's an integer, which is the truth value itself.
+func wrapWithIsTrue(ctx sessionctx.Context, arg Expression) (Expression, error) {
+	if arg.GetType().EvalType() == types.ETInt {
+		return arg, nil
+	}
+	return NewFunction(ctx, ast.IsTruth, types.NewFieldType(mysql.TypeTiny), arg)
+}
+
+type caseWhenFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *caseWhenFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	return newCaseWhenSig(ctx, args)
+}
+
+// newCaseWhenSig creates the CASE WHEN signature of the result type, the conditions are wrapped with IS TRUE
+// and the results are cast to the result type.
+func newCaseWhenSig(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	l := len(args)
+	results := make([]Expression, 0, l/2+1)
+	for i := 1; i < l; i += 2 {
+		results = append(results, args[i])
+	}
+	if l%2 == 1 {
+		results = append(results, args[l-1])
+	}
+	retTp := InferType4ControlFuncs(results...)
+	evalType := retTp.EvalType()
+	argTps := make([]types.EvalType, l)
+	for i := range args {
+		argTps[i] = evalType
+		if i%2 == 0 && i != l-1 {
+			cond, err := wrapWithIsTrue(ctx, args[i])
+			if err != nil {
+				return nil, err
+			}
+			args[i], argTps[i] = cond, types.ETInt
+		}
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, evalType, argTps...)
+	bf.tp = retTp
+	switch evalType {
+	case types.ETInt:
+		return &builtinCaseWhenIntSig{bf}, nil
+	case types.ETReal:
+		return &builtinCaseWhenRealSig{bf}, nil
+	case types.ETDecimal:
+		return &builtinCaseWhenDecimalSig{bf}, nil
+	case types.ETDatetime:
+		return &builtinCaseWhenTimeSig{bf}, nil
+	default:
+		return &builtinCaseWhenStringSig{bf}, nil
+	}
+}
+
+type ifFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *ifFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	return newCaseWhenSig(ctx, args)
+}
+
+// caseWhenBranch returns the index of the result argument of the first true condition, or the ELSE result,
+// -1 is returned if there is no such result, then the result is NULL.
+func (b *baseBuiltinFunc) caseWhenBranch(row chunk.Row) (int, error) {
+	l := len(b.args)
+	for i := 0; i < l-1; i += 2 {
+		cond, isNull, err := b.args[i].EvalInt(b.ctx, row)
+		if err != nil {
+			return -1, err
+		}
+		if !isNull && cond != 0 {
+			return i + 1, nil
+		}
+	}
+	if l%2 == 1 {
+		return l - 1, nil
+	}
+	return -1, nil
+}
+
+type builtinCaseWhenIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCaseWhenIntSig) Clone() builtinFunc {
+	newSig := &builtinCaseWhenIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCaseWhenIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	idx, err := b.caseWhenBranch(row)
+	if idx < 0 || err != nil {
+		return 0, err == nil, err
+	}
+	return b.args[idx].EvalInt(b.ctx, row)
+}
+
+type builtinCaseWhenRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCaseWhenRealSig) Clone() builtinFunc {
+	newSig := &builtinCaseWhenRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCaseWhenRealSig) evalReal(row chunk.Row) (float64, bool, error) {
+	idx, err := b.caseWhenBranch(row)
+	if idx < 0 || err != nil {
+		return 0, err == nil, err
+	}
+	return b.args[idx].EvalReal(b.ctx, row)
+}
+
+type builtinCaseWhenDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCaseWhenDecimalSig) Clone() builtinFunc {
+	newSig := &builtinCaseWhenDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCaseWhenDecimalSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	idx, err := b.caseWhenBranch(row)
+	if idx < 0 || err != nil {
+		return nil, err == nil, err
+	}
+	return b.args[idx].EvalDecimal(b.ctx, row)
+}
+
+type builtinCaseWhenStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCaseWhenStringSig) Clone() builtinFunc {
+	newSig := &builtinCaseWhenStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCaseWhenStringSig) evalString(row chunk.Row) (string, bool, error) {
+	idx, err := b.caseWhenBranch(row)
+	if idx < 0 || err != nil {
+		return "", err == nil, err
+	}
+	return b.args[idx].EvalString(b.ctx, row)
+}
+
+type builtinCaseWhenTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCaseWhenTimeSig) Clone() builtinFunc {
+	newSig := &builtinCaseWhenTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCaseWhenTimeSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	idx, err := b.caseWhenBranch(row)
+	if idx < 0 || err != nil {
+		return types.ZeroDatetime, err == nil, err
+	}
+	res, isNull, err := b.args[idx].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res.SetType(b.tp.Tp)
+	return res, false, nil
+}
+
+type coalesceFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *coalesceFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	retTp := InferType4ControlFuncs(args...)
+	evalType := retTp.EvalType()
+	argTps := make([]types.EvalType, len(args))
+	for i := range args {
+		argTps[i] = evalType
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, evalType, argTps...)
+	bf.tp = retTp
+	switch evalType {
+	case types.ETInt:
+		return &builtinCoalesceIntSig{bf}, nil
+	case types.ETReal:
+		return &builtinCoalesceRealSig{bf}, nil
+	case types.ETDecimal:
+		return &builtinCoalesceDecimalSig{bf}, nil
+	case types.ETDatetime:
+		return &builtinCoalesceTimeSig{bf}, nil
+	default:
+		return &builtinCoalesceStringSig{bf}, nil
+	}
+}
+
+// builtinCoalesceIntSig returns the first argument which isn't NULL, the remaining arguments aren't evaluated.
+type builtinCoalesceIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCoalesceIntSig) Clone() builtinFunc {
+	newSig := &builtinCoalesceIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCoalesceIntSig) evalInt(row chunk.Row) (res int64, isNull bool, err error) {
+	for _, a := range b.getArgs() {
+		res, isNull, err = a.EvalInt(b.ctx, row)
+		if err != nil || !isNull {
+			break
+		}
+	}
+	return res, isNull, err
+}
+
+type builtinCoalesceRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCoalesceRealSig) Clone() builtinFunc {
+	newSig := &builtinCoalesceRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCoalesceRealSig) evalReal(row chunk.Row) (res float64, isNull bool, err error) {
+	for _, a := range b.getArgs() {
+		res, isNull, err = a.EvalReal(b.ctx, row)
+		if err != nil || !isNull {
+			break
+		}
+	}
+	return res, isNull, err
+}
+
+type builtinCoalesceDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCoalesceDecimalSig) Clone() builtinFunc {
+	newSig := &builtinCoalesceDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCoalesceDecimalSig) evalDecimal(row chunk.Row) (res *types.MyDecimal, isNull bool, err error) {
+	for _, a := range b.getArgs() {
+		res, isNull, err = a.EvalDecimal(b.ctx, row)
+		if err != nil || !isNull {
+			break
+		}
+	}
+	return res, isNull, err
+}
+
+type builtinCoalesceStringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCoalesceStringSig) Clone() builtinFunc {
+	newSig := &builtinCoalesceStringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCoalesceStringSig) evalString(row chunk.Row) (res string, isNull bool, err error) {
+	for _, a := range b.getArgs() {
+		res, isNull, err = a.EvalString(b.ctx, row)
+		if err != nil || !isNull {
+			break
+		}
+	}
+	return res, isNull, err
+}
+
+type builtinCoalesceTimeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinCoalesceTimeSig) Clone() builtinFunc {
+	newSig := &builtinCoalesceTimeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinCoalesceTimeSig) evalTime(row chunk.Row) (res types.Time, isNull bool, err error) {
+	for _, a := range b.getArgs() {
+		res, isNull, err = a.EvalTime(b.ctx, row)
+		if err != nil || !isNull {
+			break
+		}
+	}
+	if !isNull && err == nil {
+		res.SetType(b.tp.Tp)
+	}
+	return res, isNull, err
+}
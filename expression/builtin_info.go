@@ -0,0 +1,67 @@
+package expression
+
+import (
+	"github.com/pingcap/parser/mysql"
+	"grant-db/sessionctx"
+	"grant-db/types"
+	"grant-db/util/chunk"
+)
+
+type lastInsertIDFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *lastInsertIDFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	var argsTp []types.EvalType
+	if len(args) == 1 {
+		argsTp = append(argsTp, types.ETInt)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, argsTp...)
+	bf.tp.Flag |= mysql.UnsignedFlag
+	if len(args) == 1 {
+		return &builtinLastInsertIDWithIDSig{bf}, nil
+	}
+	return &builtinLastInsertIDSig{bf}, nil
+}
+
+type builtinLastInsertIDSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLastInsertIDSig) Clone() builtinFunc {
+	newSig := &builtinLastInsertIDSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals LAST_INSERT_ID(), which is the first ID allocated for the AUTO_INCREMENT column by the last
+// statement which allocates it.
+// See https://dev.mysql.com/doc/refman/5.7/en/information-functions.html#function_last-insert-id
+func (b *builtinLastInsertIDSig) evalInt(row chunk.Row) (int64, bool, error) {
+	return int64(b.ctx.GetSessionVars().StmtCtx.PrevLastInsertID), false, nil
+}
+
+type builtinLastInsertIDWithIDSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLastInsertIDWithIDSig) Clone() builtinFunc {
+	newSig := &builtinLastInsertIDWithIDSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals LAST_INSERT_ID(expr), which returns expr, and makes LAST_INSERT_ID() return it.
+// See https://dev.mysql.com/doc/refman/5.7/en/information-functions.html#function_last-insert-id
+func (b *builtinLastInsertIDWithIDSig) evalInt(row chunk.Row) (int64, bool, error) {
+	id, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return id, isNull, err
+	}
+	sc := b.ctx.GetSessionVars().StmtCtx
+	sc.LastInsertID, sc.PrevLastInsertID = uint64(id), uint64(id)
+	return id, false, nil
+}
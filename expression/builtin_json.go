@@ -0,0 +1,407 @@
+package expression
+
+import (
+	"github.com/pingcap/parser/mysql"
+	"grant-db/sessionctx"
+	"grant-db/types"
+	"grant-db/types/json"
+	"grant-db/util/chunk"
+)
+
+// The JSON documents in the arguments are parsed from the strings, while the values to be put into a
+// JSON, like the values of JSON_SET and JSON_ARRAY, are JSON strings if they are strings.
+
+// evalPathExprs evaluates the arguments as JSON path expressions, it's NULL if any path is NULL.
+func evalPathExprs(ctx sessionctx.Context, args []Expression, row chunk.Row) ([]json.PathExpression, bool, error) {
+	pathExprs := make([]json.PathExpression, 0, len(args))
+	for _, arg := range args {
+		s, isNull, err := arg.EvalString(ctx, row)
+		if isNull || err != nil {
+			return nil, isNull, err
+		}
+		pathExpr, err := json.ParseJSONPathExpr(s)
+		if err != nil {
+			return nil, true, err
+		}
+		pathExprs = append(pathExprs, pathExpr)
+	}
+	return pathExprs, false, nil
+}
+
+// evalJSONValue evaluates the value to be put into a JSON, a NULL is the JSON null.
+func evalJSONValue(ctx sessionctx.Context, arg Expression, row chunk.Row) (json.BinaryJSON, error) {
+	val, isNull, err := arg.EvalJSON(ctx, row)
+	if err != nil {
+		return val, err
+	}
+	if isNull {
+		return json.CreateBinary(nil), nil
+	}
+	return val, nil
+}
+
+type jsonTypeFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonTypeFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETJson)
+	bf.tp.Flen = 51 // Flen of JSON_TYPE is length of UNSIGNED INTEGER.
+	return &builtinJSONTypeSig{bf}, nil
+}
+
+type builtinJSONTypeSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONTypeSig) Clone() builtinFunc {
+	newSig := &builtinJSONTypeSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals JSON_TYPE(json_val).
+// See https://dev.mysql.com/doc/refman/5.7/en/json-attribute-functions.html#function_json-type
+func (b *builtinJSONTypeSig) evalString(row chunk.Row) (string, bool, error) {
+	j, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return j.Type(), false, nil
+}
+
+type jsonExtractFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonExtractFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	argTps = append(argTps, types.ETJson)
+	for range args[1:] {
+		argTps = append(argTps, types.ETString)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETJson, argTps...)
+	return &builtinJSONExtractSig{bf}, nil
+}
+
+type builtinJSONExtractSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONExtractSig) Clone() builtinFunc {
+	newSig := &builtinJSONExtractSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals JSON_EXTRACT(json_doc, path[, path] ...), which is also `column->path`, it's NULL if no
+// path matches.
+// See https://dev.mysql.com/doc/refman/5.7/en/json-search-functions.html#function_json-extract
+func (b *builtinJSONExtractSig) evalJSON(row chunk.Row) (res json.BinaryJSON, isNull bool, err error) {
+	res, isNull, err = b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	pathExprs, isNull, err := evalPathExprs(b.ctx, b.args[1:], row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, found := res.Extract(pathExprs)
+	return res, !found, nil
+}
+
+type jsonUnquoteFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonUnquoteFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString)
+	bf.tp.Flen = mysql.MaxFieldVarCharLength
+	return &builtinJSONUnquoteSig{bf}, nil
+}
+
+type builtinJSONUnquoteSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONUnquoteSig) Clone() builtinFunc {
+	newSig := &builtinJSONUnquoteSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals JSON_UNQUOTE(json_val), which is also `column->>path` with JSON_EXTRACT, a JSON value is
+// unquoted from its JSON text.
+// See https://dev.mysql.com/doc/refman/5.7/en/json-modification-functions.html#function_json-unquote
+func (b *builtinJSONUnquoteSig) evalString(row chunk.Row) (string, bool, error) {
+	s, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	s, err = json.UnquoteString(s)
+	return s, err != nil, err
+}
+
+type jsonModifyFunctionClass struct {
+	baseFunctionClass
+
+	mt json.ModifyType
+}
+
+func (c *jsonModifyFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	if len(args)&1 != 1 {
+		return nil, ErrIncorrectParameterCount.GenWithStackByArgs(c.funcName)
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	argTps = append(argTps, types.ETJson)
+	for i := 1; i < len(args); i += 2 {
+		args[i+1] = WrapWithCastAsJSONValue(ctx, args[i+1])
+		argTps = append(argTps, types.ETString, types.ETJson)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETJson, argTps...)
+	return &builtinJSONModifySig{bf, c.mt}, nil
+}
+
+type builtinJSONModifySig struct {
+	baseBuiltinFunc
+
+	mt json.ModifyType
+}
+
+func (b *builtinJSONModifySig) Clone() builtinFunc {
+	newSig := &builtinJSONModifySig{mt: b.mt}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals JSON_SET, JSON_INSERT and JSON_REPLACE(json_doc, path, val[, path, val] ...), the pairs are
+// applied from left to right.
+// See https://dev.mysql.com/doc/refman/5.7/en/json-modification-functions.html#function_json-set
+func (b *builtinJSONModifySig) evalJSON(row chunk.Row) (res json.BinaryJSON, isNull bool, err error) {
+	res, isNull, err = b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	n := (len(b.args) - 1) / 2
+	pathArgs := make([]Expression, 0, n)
+	values := make([]json.BinaryJSON, 0, n)
+	for i := 1; i < len(b.args); i += 2 {
+		pathArgs = append(pathArgs, b.args[i])
+		val, err := evalJSONValue(b.ctx, b.args[i+1], row)
+		if err != nil {
+			return res, true, err
+		}
+		values = append(values, val)
+	}
+	pathExprs, isNull, err := evalPathExprs(b.ctx, pathArgs, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = res.Modify(pathExprs, values, b.mt)
+	return res, err != nil, err
+}
+
+type jsonRemoveFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonRemoveFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	argTps = append(argTps, types.ETJson)
+	for range args[1:] {
+		argTps = append(argTps, types.ETString)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETJson, argTps...)
+	return &builtinJSONRemoveSig{bf}, nil
+}
+
+type builtinJSONRemoveSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONRemoveSig) Clone() builtinFunc {
+	newSig := &builtinJSONRemoveSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals JSON_REMOVE(json_doc, path[, path] ...).
+// See https://dev.mysql.com/doc/refman/5.7/en/json-modification-functions.html#function_json-remove
+func (b *builtinJSONRemoveSig) evalJSON(row chunk.Row) (res json.BinaryJSON, isNull bool, err error) {
+	res, isNull, err = b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	pathExprs, isNull, err := evalPathExprs(b.ctx, b.args[1:], row)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	res, err = res.Remove(pathExprs)
+	return res, err != nil, err
+}
+
+type jsonContainsFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonContainsFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETJson, types.ETJson}
+	if len(args) == 3 {
+		argTps = append(argTps, types.ETString)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, argTps...)
+	bf.tp.Flen = 1
+	return &builtinJSONContainsSig{bf}, nil
+}
+
+type builtinJSONContainsSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONContainsSig) Clone() builtinFunc {
+	newSig := &builtinJSONContainsSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals JSON_CONTAINS(target, candidate[, path]), it's NULL if the path doesn't match, and the path
+// can't contain any wildcard.
+// See https://dev.mysql.com/doc/refman/5.7/en/json-search-functions.html#function_json-contains
+func (b *builtinJSONContainsSig) evalInt(row chunk.Row) (int64, bool, error) {
+	target, isNull, err := b.args[0].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	candidate, isNull, err := b.args[1].EvalJSON(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if len(b.args) == 3 {
+		pathExprs, isNull, err := evalPathExprs(b.ctx, b.args[2:], row)
+		if isNull || err != nil {
+			return 0, isNull, err
+		}
+		if pathExprs[0].ContainsAnyAsterisk() {
+			return 0, true, json.ErrInvalidJSONPathWildcard
+		}
+		var found bool
+		if target, found = target.Extract(pathExprs); !found {
+			return 0, true, nil
+		}
+	}
+	if json.ContainsBinary(target, candidate) {
+		return 1, false, nil
+	}
+	return 0, false, nil
+}
+
+type jsonArrayFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonArrayFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	for i := range args {
+		args[i] = WrapWithCastAsJSONValue(ctx, args[i])
+		argTps = append(argTps, types.ETJson)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETJson, argTps...)
+	return &builtinJSONArraySig{bf}, nil
+}
+
+type builtinJSONArraySig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONArraySig) Clone() builtinFunc {
+	newSig := &builtinJSONArraySig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals JSON_ARRAY([val[, val] ...]).
+// See https://dev.mysql.com/doc/refman/5.7/en/json-creation-functions.html#function_json-array
+func (b *builtinJSONArraySig) evalJSON(row chunk.Row) (res json.BinaryJSON, isNull bool, err error) {
+	elems := make([]json.BinaryJSON, 0, len(b.args))
+	for _, arg := range b.args {
+		val, err := evalJSONValue(b.ctx, arg, row)
+		if err != nil {
+			return res, true, err
+		}
+		elems = append(elems, val)
+	}
+	return json.CreateBinary(elems), false, nil
+}
+
+type jsonObjectFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *jsonObjectFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	if len(args)&1 != 0 {
+		return nil, ErrIncorrectParameterCount.GenWithStackByArgs(c.funcName)
+	}
+	argTps := make([]types.EvalType, 0, len(args))
+	for i := 0; i < len(args); i += 2 {
+		args[i+1] = WrapWithCastAsJSONValue(ctx, args[i+1])
+		argTps = append(argTps, types.ETString, types.ETJson)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETJson, argTps...)
+	return &builtinJSONObjectSig{bf}, nil
+}
+
+type builtinJSONObjectSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinJSONObjectSig) Clone() builtinFunc {
+	newSig := &builtinJSONObjectSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalJSON evals JSON_OBJECT([key, val[, key, val] ...]), a key can't be NULL and the last value of a
+// duplicated key wins.
+// See https://dev.mysql.com/doc/refman/5.7/en/json-creation-functions.html#function_json-object
+func (b *builtinJSONObjectSig) evalJSON(row chunk.Row) (res json.BinaryJSON, isNull bool, err error) {
+	obj := make(map[string]interface{}, len(b.args)/2)
+	for i := 0; i < len(b.args); i += 2 {
+		key, isNull, err := b.args[i].EvalString(b.ctx, row)
+		if err != nil {
+			return res, true, err
+		}
+		if isNull {
+			return res, true, json.ErrJSONDocumentNULLKey
+		}
+		val, err := evalJSONValue(b.ctx, b.args[i+1], row)
+		if err != nil {
+			return res, true, err
+		}
+		obj[key] = val
+	}
+	return json.CreateBinary(obj), false, nil
+}
@@ -0,0 +1,47 @@
+package expression_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/parser/mysql"
+	"grant-db/util/testkit"
+)
+
+func TestJSONFuncs(t *testing.T) {
+	store, _ := testkit.NewMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create database test")
+	tk.MustExec("use test")
+	tk.MustExec("create table t (id int primary key, j json)")
+	tk.MustExec(`insert into t values (1, '{"b": [1, 2], "a": 1}'), (2, '[3, "x"]'), (3, 'null'), (4, null), (5, '"str"'), (6, '2.5')`)
+	tk.MustGetErrCode(`insert into t values (7, '{bad')`, mysql.ErrInvalidJSONText)
+	tk.MustGetErrCode("select json_extract(j, 'a') from t", mysql.ErrInvalidJSONPath)
+
+	// The values are formatted like MySQL, the keys of an object are sorted.
+	tk.MustQuery("select j, json_type(j), json_unquote(j) from t order by id").Check(
+		`{"a": 1, "b": [1, 2]} OBJECT {"a": 1, "b": [1, 2]}`,
+		`[3, "x"] ARRAY [3, "x"]`,
+		"null NULL null",
+		"<nil> <nil> <nil>",
+		`"str" STRING str`,
+		"2.5 DOUBLE 2.5",
+	)
+	tk.MustQuery("select id, j->'$.a', j->>'$.b[1]', json_extract(j, '$[1]'), j->>'$' from t where id in (1, 2, 5) order by id").Check(
+		`1 1 2 <nil> {"a": 1, "b": [1, 2]}`,
+		`2 <nil> <nil> "x" [3, "x"]`,
+		"5 <nil> <nil> <nil> str",
+	)
+	tk.MustQuery(`select json_set(j, '$.c', 'x'), json_insert(j, '$.a', 5), json_replace(j, '$.a', true), json_remove(j, '$.b[0]') from t where id = 1`).
+		Check(`{"a": 1, "b": [1, 2], "c": "x"} {"a": 1, "b": [1, 2]} {"a": true, "b": [1, 2]} {"a": 1, "b": [2]}`)
+	tk.MustQuery(`select json_contains(j, '1', '$.a'), json_contains(j, '[2]', '$.b'), json_contains(j, '{"a": 2}') from t where id = 1`).
+		Check("1 1 0")
+	tk.MustQuery("select json_array(1, 'a', null, true, 1.5), json_object('k', 1, 'v', json_array())").
+		Check(`[1, "a", null, true, 1.5] {"k": 1, "v": []}`)
+	tk.MustQuery("select json_arrayagg(id), json_objectagg(id, j) from t where id < 4").
+		Check(`[1, 2, 3] {"1": {"a": 1, "b": [1, 2]}, "2": [3, "x"], "3": null}`)
+
+	// JSON values are ordered by their types first: null < numbers < strings < objects < arrays.
+	tk.MustQuery("select id from t where j is not null order by j, id").Check("3", "6", "5", "1", "2")
+	tk.MustQuery("select j = cast('[3, \"x\"]' as json), j > cast('[1]' as json), j < cast('{}' as json) from t where id = 2").
+		Check("1 1 0")
+}
@@ -0,0 +1,53 @@
+package expression
+
+import (
+	"grant-db/types"
+	"grant-db/util/chunk"
+)
+
+func (b *builtinLikeSig) vectorized() bool {
+	return true
+}
+
+// vecEvalInt evaluates the LIKE by vectors, the pattern is compiled only when it differs from the
+// one of the previous row, which makes a constant pattern compiled once.
+func (b *builtinLikeSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufVal := b.bufAllocator.get(types.ETString, n)
+	defer b.bufAllocator.put(types.ETString, bufVal)
+	if err := b.args[0].VecEvalString(b.ctx, input, bufVal); err != nil {
+		return err
+	}
+	bufPattern := b.bufAllocator.get(types.ETString, n)
+	defer b.bufAllocator.put(types.ETString, bufPattern)
+	if err := b.args[1].VecEvalString(b.ctx, input, bufPattern); err != nil {
+		return err
+	}
+	if err := b.args[2].VecEvalInt(b.ctx, input, result); err != nil {
+		return err
+	}
+	result.MergeNulls(bufVal, bufPattern)
+	escapes := result.Int64s()
+	var (
+		lastPattern string
+		lastEscape  int64
+		compiled    bool
+	)
+	matcher := b.collator().Pattern()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		pattern := bufPattern.GetString(i)
+		if !compiled || pattern != lastPattern || escapes[i] != lastEscape {
+			matcher.Compile(pattern, byte(escapes[i]))
+			lastPattern, lastEscape, compiled = pattern, escapes[i], true
+		}
+		if matcher.DoMatch(bufVal.GetString(i)) {
+			escapes[i] = 1
+		} else {
+			escapes[i] = 0
+		}
+	}
+	return nil
+}
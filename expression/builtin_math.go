@@ -0,0 +1,437 @@
+package expression
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/pingcap/parser/mysql"
+	"grant-db/sessionctx"
+	"grant-db/types"
+	"grant-db/util/chunk"
+)
+
+type absFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *absFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTp := numericContextResultType(args[0].GetType())
+	bf := newBaseBuiltinFuncWithTp(ctx, args, argTp, argTp)
+	argFieldTp := bf.args[0].GetType()
+	if argFieldTp.Flen != types.UnspecifiedLength {
+		bf.tp.Flen = argFieldTp.Flen
+	}
+	bf.tp.Decimal = argFieldTp.Decimal
+	if mysql.HasUnsignedFlag(argFieldTp.Flag) {
+		bf.tp.Flag |= mysql.UnsignedFlag
+	}
+	switch argTp {
+	case types.ETInt:
+		if mysql.HasUnsignedFlag(argFieldTp.Flag) {
+			return &builtinAbsUIntSig{bf}, nil
+		}
+		return &builtinAbsIntSig{bf}, nil
+	case types.ETDecimal:
+		return &builtinAbsDecSig{bf}, nil
+	default:
+		return &builtinAbsRealSig{bf}, nil
+	}
+}
+
+type builtinAbsIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinAbsIntSig) Clone() builtinFunc {
+	newSig := &builtinAbsIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals ABS(value).
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_abs
+func (b *builtinAbsIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	val, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if val >= 0 {
+		return val, false, nil
+	}
+	if val == math.MinInt64 {
+		return 0, true, types.ErrDataOutOfRange.GenWithStackByArgs("BIGINT", fmt.Sprintf("abs(%s)", b.args[0].String()))
+	}
+	return -val, false, nil
+}
+
+type builtinAbsUIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinAbsUIntSig) Clone() builtinFunc {
+	newSig := &builtinAbsUIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinAbsUIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	return b.args[0].EvalInt(b.ctx, row)
+}
+
+type builtinAbsDecSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinAbsDecSig) Clone() builtinFunc {
+	newSig := &builtinAbsDecSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinAbsDecSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	val, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	if val.IsNegative() {
+		return val.Neg(), false, nil
+	}
+	return val, false, nil
+}
+
+type builtinAbsRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinAbsRealSig) Clone() builtinFunc {
+	newSig := &builtinAbsRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinAbsRealSig) evalReal(row chunk.Row) (float64, bool, error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return math.Abs(val), false, nil
+}
+
+type roundFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *roundFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTp := numericContextResultType(args[0].GetType())
+	argTps := []types.EvalType{argTp}
+	if len(args) > 1 {
+		argTps = append(argTps, types.ETInt)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, argTp, argTps...)
+	argFieldTp := bf.args[0].GetType()
+	if mysql.HasUnsignedFlag(argFieldTp.Flag) {
+		bf.tp.Flag |= mysql.UnsignedFlag
+	}
+	// The fraction digits of the result are known only when D is a constant.
+	frac := 0
+	if len(bf.args) > 1 {
+		frac = argFieldTp.Decimal
+		if constFrac, ok := bf.args[1].(*Constant); ok {
+			if d, err := constFrac.Eval(chunk.Row{}); err == nil && !d.IsNull() {
+				frac = int(mathClamp(d.GetInt64(), 0, mysql.MaxDecimalScale))
+			}
+		}
+	}
+	switch argTp {
+	case types.ETInt:
+		bf.tp.Flen, bf.tp.Decimal = argFieldTp.Flen, 0
+		return &builtinRoundIntSig{bf}, nil
+	case types.ETDecimal:
+		bf.tp.Decimal = minInt(frac, mysql.MaxDecimalScale)
+		bf.tp.Flen = mysql.MaxDecimalWidth
+		if argFieldTp.Flen != types.UnspecifiedLength && argFieldTp.Decimal != types.UnspecifiedLength {
+			// One more digit for the carry.
+			bf.tp.Flen = minInt(argFieldTp.Flen-argFieldTp.Decimal+1+bf.tp.Decimal, mysql.MaxDecimalWidth)
+		}
+		return &builtinRoundDecSig{bf}, nil
+	default:
+		bf.tp.Flen, bf.tp.Decimal = mysql.MaxRealWidth, types.UnspecifiedLength
+		return &builtinRoundRealSig{bf}, nil
+	}
+}
+
+// mathClamp returns v limited to [min, max].
+func mathClamp(v, min, max int64) int64 {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
+
+// evalRoundFrac evaluates the fraction digits D of ROUND(X,D), it's 0 for ROUND(X).
+func (b *baseBuiltinFunc) evalRoundFrac(row chunk.Row) (int64, bool, error) {
+	if len(b.args) == 1 {
+		return 0, false, nil
+	}
+	return b.args[1].EvalInt(b.ctx, row)
+}
+
+type builtinRoundIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRoundIntSig) Clone() builtinFunc {
+	newSig := &builtinRoundIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals ROUND(X) and ROUND(X,D) of an integer, which is changed only by a negative D.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_round
+func (b *builtinRoundIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	val, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	frac, isNull, err := b.evalRoundFrac(row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if frac >= 0 {
+		return val, false, nil
+	}
+	frac = mathClamp(frac, -mysql.MaxDecimalWidth, 0)
+	isUnsigned := mysql.HasUnsignedFlag(b.tp.Flag)
+	dec := types.NewDecFromInt(val)
+	if isUnsigned {
+		dec = types.NewDecFromUint(uint64(val))
+	}
+	dec.Round(dec, int(frac))
+	if isUnsigned {
+		res, err := dec.ToUint()
+		if err != nil {
+			return 0, true, types.ErrDataOutOfRange.GenWithStackByArgs("BIGINT UNSIGNED", fmt.Sprintf("round(%s)", b.args[0].String()))
+		}
+		return int64(res), false, nil
+	}
+	res, err := dec.ToInt()
+	if err != nil {
+		return 0, true, types.ErrDataOutOfRange.GenWithStackByArgs("BIGINT", fmt.Sprintf("round(%s)", b.args[0].String()))
+	}
+	return res, false, nil
+}
+
+type builtinRoundDecSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRoundDecSig) Clone() builtinFunc {
+	newSig := &builtinRoundDecSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDecimal evals ROUND(X) and ROUND(X,D) of a decimal, which is rounded half away from zero.
+func (b *builtinRoundDecSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	val, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	frac, isNull, err := b.evalRoundFrac(row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	to := new(types.MyDecimal)
+	val.Round(to, int(mathClamp(frac, -mysql.MaxDecimalWidth, mysql.MaxDecimalScale)))
+	return to, false, nil
+}
+
+type builtinRoundRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRoundRealSig) Clone() builtinFunc {
+	newSig := &builtinRoundRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalReal evals ROUND(X) and ROUND(X,D) of a double, which is rounded half to even like MySQL.
+func (b *builtinRoundRealSig) evalReal(row chunk.Row) (float64, bool, error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	frac, isNull, err := b.evalRoundFrac(row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return roundFloat(val, frac), false, nil
+}
+
+// roundFloat rounds f to frac fraction digits half to even, frac may be negative.
+func roundFloat(f float64, frac int64) float64 {
+	if frac < 0 {
+		shift := math.Pow10(int(mathClamp(-frac, 0, 400)))
+		if math.IsInf(shift, 0) {
+			return 0
+		}
+		return math.RoundToEven(f/shift) * shift
+	}
+	shift := math.Pow10(int(mathClamp(frac, 0, 400)))
+	tmp := f * shift
+	if math.IsInf(tmp, 0) {
+		// f has no more fraction digits than frac.
+		return f
+	}
+	return math.RoundToEven(tmp) / shift
+}
+
+type floorCeilFunctionClass struct {
+	baseFunctionClass
+	isCeil bool
+}
+
+func (c *floorCeilFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argFieldTp := args[0].GetType()
+	argTp := numericContextResultType(argFieldTp)
+	retTp := argTp
+	// The decimal which fits into BIGINT is floored to an integer.
+	if argTp == types.ETDecimal {
+		intDigits := argFieldTp.Flen - argFieldTp.Decimal
+		if argFieldTp.Flen == types.UnspecifiedLength || argFieldTp.Decimal == types.UnspecifiedLength || intDigits >= mysql.MaxIntWidth-1 {
+			retTp = types.ETDecimal
+		} else {
+			retTp = types.ETInt
+		}
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, retTp, argTp)
+	argFieldTp = bf.args[0].GetType()
+	if mysql.HasUnsignedFlag(argFieldTp.Flag) {
+		bf.tp.Flag |= mysql.UnsignedFlag
+	}
+	switch {
+	case argTp == types.ETInt:
+		bf.tp.Flen = argFieldTp.Flen
+		return &builtinFloorCeilIntSig{bf}, nil
+	case argTp == types.ETDecimal && retTp == types.ETInt:
+		bf.tp.Flen = argFieldTp.Flen - argFieldTp.Decimal + 1
+		return &builtinFloorCeilDecToIntSig{bf, c.isCeil}, nil
+	case argTp == types.ETDecimal:
+		bf.tp.Decimal = 0
+		bf.tp.Flen = mysql.MaxDecimalWidth
+		if argFieldTp.Flen != types.UnspecifiedLength && argFieldTp.Decimal != types.UnspecifiedLength {
+			bf.tp.Flen = minInt(argFieldTp.Flen-argFieldTp.Decimal+1, mysql.MaxDecimalWidth)
+		}
+		return &builtinFloorCeilDecSig{bf, c.isCeil}, nil
+	default:
+		bf.tp.Flen, bf.tp.Decimal = mysql.MaxRealWidth, 0
+		return &builtinFloorCeilRealSig{bf, c.isCeil}, nil
+	}
+}
+
+// builtinFloorCeilIntSig evals FLOOR(X) and CEIL(X) of an integer, which is X itself.
+type builtinFloorCeilIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinFloorCeilIntSig) Clone() builtinFunc {
+	newSig := &builtinFloorCeilIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinFloorCeilIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	return b.args[0].EvalInt(b.ctx, row)
+}
+
+type builtinFloorCeilDecToIntSig struct {
+	baseBuiltinFunc
+	isCeil bool
+}
+
+func (b *builtinFloorCeilDecToIntSig) Clone() builtinFunc {
+	newSig := &builtinFloorCeilDecToIntSig{isCeil: b.isCeil}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals FLOOR(X) and CEIL(X) of a decimal whose integer part fits into BIGINT.
+// See https://dev.mysql.com/doc/refman/5.7/en/mathematical-functions.html#function_floor
+func (b *builtinFloorCeilDecToIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	val, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	to := new(types.MyDecimal)
+	floorOrCeil(val, to, b.isCeil)
+	if mysql.HasUnsignedFlag(b.tp.Flag) {
+		res, err := to.ToUint()
+		return int64(res), err != nil, err
+	}
+	res, err := to.ToInt()
+	return res, err != nil, err
+}
+
+type builtinFloorCeilDecSig struct {
+	baseBuiltinFunc
+	isCeil bool
+}
+
+func (b *builtinFloorCeilDecSig) Clone() builtinFunc {
+	newSig := &builtinFloorCeilDecSig{isCeil: b.isCeil}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinFloorCeilDecSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	val, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	to := new(types.MyDecimal)
+	floorOrCeil(val, to, b.isCeil)
+	return to, false, nil
+}
+
+func floorOrCeil(val, to *types.MyDecimal, isCeil bool) {
+	if isCeil {
+		val.Ceil(to)
+	} else {
+		val.Floor(to)
+	}
+}
+
+type builtinFloorCeilRealSig struct {
+	baseBuiltinFunc
+	isCeil bool
+}
+
+func (b *builtinFloorCeilRealSig) Clone() builtinFunc {
+	newSig := &builtinFloorCeilRealSig{isCeil: b.isCeil}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinFloorCeilRealSig) evalReal(row chunk.Row) (float64, bool, error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	if b.isCeil {
+		return math.Ceil(val), false, nil
+	}
+	return math.Floor(val), false, nil
+}
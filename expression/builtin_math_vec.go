@@ -0,0 +1,111 @@
+package expression
+
+import (
+	"fmt"
+	"math"
+
+	"grant-db/types"
+	"grant-db/util/chunk"
+)
+
+func (b *builtinAbsIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinAbsIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	if err := b.args[0].VecEvalInt(b.ctx, input, result); err != nil {
+		return err
+	}
+	i64s := result.Int64s()
+	for i := range i64s {
+		if result.IsNull(i) || i64s[i] >= 0 {
+			continue
+		}
+		if i64s[i] == math.MinInt64 {
+			return types.ErrDataOutOfRange.GenWithStackByArgs("BIGINT", fmt.Sprintf("abs(%s)", b.args[0].String()))
+		}
+		i64s[i] = -i64s[i]
+	}
+	return nil
+}
+
+func (b *builtinAbsUIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinAbsUIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return b.args[0].VecEvalInt(b.ctx, input, result)
+}
+
+func (b *builtinAbsRealSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinAbsRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	if err := b.args[0].VecEvalReal(b.ctx, input, result); err != nil {
+		return err
+	}
+	f64s := result.Float64s()
+	for i := range f64s {
+		f64s[i] = math.Abs(f64s[i])
+	}
+	return nil
+}
+
+func (b *builtinRoundRealSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRoundRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	if err := b.args[0].VecEvalReal(b.ctx, input, result); err != nil {
+		return err
+	}
+	f64s := result.Float64s()
+	if len(b.args) == 1 {
+		for i := range f64s {
+			f64s[i] = math.RoundToEven(f64s[i])
+		}
+		return nil
+	}
+	buf := b.bufAllocator.get(types.ETInt, input.NumRows())
+	defer b.bufAllocator.put(types.ETInt, buf)
+	if err := b.args[1].VecEvalInt(b.ctx, input, buf); err != nil {
+		return err
+	}
+	result.MergeNulls(buf)
+	fracs := buf.Int64s()
+	for i := range f64s {
+		if result.IsNull(i) {
+			continue
+		}
+		f64s[i] = roundFloat(f64s[i], fracs[i])
+	}
+	return nil
+}
+
+func (b *builtinFloorCeilIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinFloorCeilIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return b.args[0].VecEvalInt(b.ctx, input, result)
+}
+
+func (b *builtinFloorCeilRealSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinFloorCeilRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	if err := b.args[0].VecEvalReal(b.ctx, input, result); err != nil {
+		return err
+	}
+	f64s := result.Float64s()
+	for i := range f64s {
+		if b.isCeil {
+			f64s[i] = math.Ceil(f64s[i])
+		} else {
+			f64s[i] = math.Floor(f64s[i])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,537 @@
+package expression
+
+import (
+	"math"
+
+	"github.com/pingcap/parser/mysql"
+	"grant-db/sessionctx"
+	"grant-db/types"
+	"grant-db/util/chunk"
+)
+
+// logicArgTps returns the evaluation types of the logic function arguments, every argument is
+// evaluated as its own type and then converted to a boolean.
+func logicArgTps(args []Expression) []types.EvalType {
+	argTps := make([]types.EvalType, len(args))
+	for i, arg := range args {
+		argTps[i] = arg.GetType().EvalType()
+	}
+	return argTps
+}
+
+func newLogicBaseFunc(ctx sessionctx.Context, args []Expression) baseBuiltinFunc {
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, logicArgTps(args)...)
+	bf.tp.Flen = 1
+	return bf
+}
+
+type logicAndFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *logicAndFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	return &builtinLogicAndSig{newLogicBaseFunc(ctx, args)}, nil
+}
+
+type builtinLogicAndSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLogicAndSig) Clone() builtinFunc {
+	newSig := &builtinLogicAndSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evaluates the AND, it's false if either argument is false even if the other one is NULL.
+func (b *builtinLogicAndSig) evalInt(row chunk.Row) (int64, bool, error) {
+	arg0, isNull0, err := evalBool(b.ctx, b.args[0], row)
+	if err != nil || (!isNull0 && !arg0) {
+		return 0, err != nil, err
+	}
+	arg1, isNull1, err := evalBool(b.ctx, b.args[1], row)
+	if err != nil || (!isNull1 && !arg1) {
+		return 0, err != nil, err
+	}
+	if isNull0 || isNull1 {
+		return 0, true, nil
+	}
+	return 1, false, nil
+}
+
+type logicOrFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *logicOrFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	return &builtinLogicOrSig{newLogicBaseFunc(ctx, args)}, nil
+}
+
+type builtinLogicOrSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLogicOrSig) Clone() builtinFunc {
+	newSig := &builtinLogicOrSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evaluates the OR, it's true if either argument is true even if the other one is NULL.
+func (b *builtinLogicOrSig) evalInt(row chunk.Row) (int64, bool, error) {
+	arg0, isNull0, err := evalBool(b.ctx, b.args[0], row)
+	if err != nil {
+		return 0, true, err
+	}
+	if !isNull0 && arg0 {
+		return 1, false, nil
+	}
+	arg1, isNull1, err := evalBool(b.ctx, b.args[1], row)
+	if err != nil {
+		return 0, true, err
+	}
+	if !isNull1 && arg1 {
+		return 1, false, nil
+	}
+	if isNull0 || isNull1 {
+		return 0, true, nil
+	}
+	return 0, false, nil
+}
+
+type logicXorFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *logicXorFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	return &builtinLogicXorSig{newLogicBaseFunc(ctx, args)}, nil
+}
+
+type builtinLogicXorSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLogicXorSig) Clone() builtinFunc {
+	newSig := &builtinLogicXorSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLogicXorSig) evalInt(row chunk.Row) (int64, bool, error) {
+	arg0, isNull, err := evalBool(b.ctx, b.args[0], row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	arg1, isNull, err := evalBool(b.ctx, b.args[1], row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	if arg0 != arg1 {
+		return 1, false, nil
+	}
+	return 0, false, nil
+}
+
+type unaryNotFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *unaryNotFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	return &builtinUnaryNotSig{newLogicBaseFunc(ctx, args)}, nil
+}
+
+type builtinUnaryNotSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUnaryNotSig) Clone() builtinFunc {
+	newSig := &builtinUnaryNotSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinUnaryNotSig) evalInt(row chunk.Row) (int64, bool, error) {
+	arg, isNull, err := evalBool(b.ctx, b.args[0], row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	if arg {
+		return 0, false, nil
+	}
+	return 1, false, nil
+}
+
+type isTrueOrFalseFunctionClass struct {
+	baseFunctionClass
+
+	isTrue bool
+}
+
+func (c *isTrueOrFalseFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	return &builtinIsTrueOrFalseSig{newLogicBaseFunc(ctx, args), c.isTrue}, nil
+}
+
+// builtinIsTrueOrFalseSig evaluates `expr IS TRUE` and `expr IS FALSE`, they are never NULL.
+type builtinIsTrueOrFalseSig struct {
+	baseBuiltinFunc
+
+	isTrue bool
+}
+
+func (b *builtinIsTrueOrFalseSig) Clone() builtinFunc {
+	newSig := &builtinIsTrueOrFalseSig{isTrue: b.isTrue}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinIsTrueOrFalseSig) equal(fun builtinFunc) bool {
+	sig, ok := fun.(*builtinIsTrueOrFalseSig)
+	return ok && sig.isTrue == b.isTrue && b.baseBuiltinFunc.equal(fun)
+}
+
+func (b *builtinIsTrueOrFalseSig) evalInt(row chunk.Row) (int64, bool, error) {
+	arg, isNull, err := evalBool(b.ctx, b.args[0], row)
+	if err != nil {
+		return 0, true, err
+	}
+	if !isNull && arg == b.isTrue {
+		return 1, false, nil
+	}
+	return 0, false, nil
+}
+
+type isNullFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *isNullFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTp := args[0].GetType().EvalType()
+	if argTp == types.ETTimestamp {
+		argTp = types.ETDatetime
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, argTp)
+	bf.tp.Flen = 1
+	return &builtinIsNullSig{bf}, nil
+}
+
+type builtinIsNullSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinIsNullSig) Clone() builtinFunc {
+	newSig := &builtinIsNullSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinIsNullSig) evalInt(row chunk.Row) (int64, bool, error) {
+	d, err := evalDatum(b.ctx, b.args[0], row)
+	if err != nil {
+		return 0, true, err
+	}
+	if d.IsNull() {
+		return 1, false, nil
+	}
+	return 0, false, nil
+}
+
+type unaryMinusFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *unaryMinusFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argExprTp := args[0].GetType()
+	switch numericContextResultType(argExprTp) {
+	case types.ETInt:
+		if mysql.HasUnsignedFlag(argExprTp.Flag) {
+			// The negative of a BIGINT UNSIGNED may be out of the range of BIGINT.
+			bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDecimal, types.ETDecimal)
+			bf.tp.Flen = argExprTp.Flen + 1
+			return &builtinUnaryMinusDecimalSig{bf}, nil
+		}
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETInt)
+		bf.tp.Flen = argExprTp.Flen + 1
+		return &builtinUnaryMinusIntSig{bf}, nil
+	case types.ETDecimal:
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDecimal, types.ETDecimal)
+		bf.tp.Flen, bf.tp.Decimal = argExprTp.Flen+1, argExprTp.Decimal
+		return &builtinUnaryMinusDecimalSig{bf}, nil
+	default:
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETReal, types.ETReal)
+		return &builtinUnaryMinusRealSig{bf}, nil
+	}
+}
+
+type builtinUnaryMinusIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUnaryMinusIntSig) Clone() builtinFunc {
+	newSig := &builtinUnaryMinusIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinUnaryMinusIntSig) evalInt(row chunk.Row) (res int64, isNull bool, err error) {
+	val, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if err != nil || isNull {
+		return val, isNull, err
+	}
+	if val == math.MinInt64 {
+		return 0, true, types.ErrDataOutOfRange.GenWithStackByArgs("BIGINT", "-"+b.args[0].String())
+	}
+	return -val, false, nil
+}
+
+type builtinUnaryMinusDecimalSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUnaryMinusDecimalSig) Clone() builtinFunc {
+	newSig := &builtinUnaryMinusDecimalSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinUnaryMinusDecimalSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	dec, isNull, err := b.args[0].EvalDecimal(b.ctx, row)
+	if err != nil || isNull {
+		return dec, isNull, err
+	}
+	return dec.Neg(), false, nil
+}
+
+type builtinUnaryMinusRealSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUnaryMinusRealSig) Clone() builtinFunc {
+	newSig := &builtinUnaryMinusRealSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinUnaryMinusRealSig) evalReal(row chunk.Row) (float64, bool, error) {
+	val, isNull, err := b.args[0].EvalReal(b.ctx, row)
+	return -val, isNull, err
+}
+
+// newBitBaseFunc creates the base of the bit functions, the arguments are converted to integers
+// and the result is a BIGINT UNSIGNED.
+func newBitBaseFunc(ctx sessionctx.Context, args []Expression) baseBuiltinFunc {
+	argTps := make([]types.EvalType, len(args))
+	for i := range argTps {
+		argTps[i] = types.ETInt
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, argTps...)
+	bf.tp.Flag |= mysql.UnsignedFlag
+	return bf
+}
+
+// evalIntArgs evaluates the two integer arguments of b, the second one is only evaluated when the first one is not NULL.
+func (b *baseBuiltinFunc) evalIntArgs(row chunk.Row) (arg0, arg1 int64, isNull bool, err error) {
+	arg0, isNull, err = b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, 0, true, err
+	}
+	arg1, isNull, err = b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, 0, true, err
+	}
+	return arg0, arg1, false, nil
+}
+
+type bitAndFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *bitAndFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	return &builtinBitAndSig{newBitBaseFunc(ctx, args)}, nil
+}
+
+type builtinBitAndSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinBitAndSig) Clone() builtinFunc {
+	newSig := &builtinBitAndSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinBitAndSig) evalInt(row chunk.Row) (int64, bool, error) {
+	arg0, arg1, isNull, err := b.evalIntArgs(row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	return arg0 & arg1, false, nil
+}
+
+type bitOrFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *bitOrFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	return &builtinBitOrSig{newBitBaseFunc(ctx, args)}, nil
+}
+
+type builtinBitOrSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinBitOrSig) Clone() builtinFunc {
+	newSig := &builtinBitOrSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinBitOrSig) evalInt(row chunk.Row) (int64, bool, error) {
+	arg0, arg1, isNull, err := b.evalIntArgs(row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	return arg0 | arg1, false, nil
+}
+
+type bitXorFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *bitXorFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	return &builtinBitXorSig{newBitBaseFunc(ctx, args)}, nil
+}
+
+type builtinBitXorSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinBitXorSig) Clone() builtinFunc {
+	newSig := &builtinBitXorSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinBitXorSig) evalInt(row chunk.Row) (int64, bool, error) {
+	arg0, arg1, isNull, err := b.evalIntArgs(row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	return arg0 ^ arg1, false, nil
+}
+
+type leftShiftFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *leftShiftFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	return &builtinLeftShiftSig{newBitBaseFunc(ctx, args)}, nil
+}
+
+type builtinLeftShiftSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinLeftShiftSig) Clone() builtinFunc {
+	newSig := &builtinLeftShiftSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinLeftShiftSig) evalInt(row chunk.Row) (int64, bool, error) {
+	arg0, arg1, isNull, err := b.evalIntArgs(row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	return int64(uint64(arg0) << uint64(arg1)), false, nil
+}
+
+type rightShiftFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *rightShiftFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	return &builtinRightShiftSig{newBitBaseFunc(ctx, args)}, nil
+}
+
+type builtinRightShiftSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinRightShiftSig) Clone() builtinFunc {
+	newSig := &builtinRightShiftSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinRightShiftSig) evalInt(row chunk.Row) (int64, bool, error) {
+	arg0, arg1, isNull, err := b.evalIntArgs(row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	return int64(uint64(arg0) >> uint64(arg1)), false, nil
+}
+
+type bitNegFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *bitNegFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	return &builtinBitNegSig{newBitBaseFunc(ctx, args)}, nil
+}
+
+type builtinBitNegSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinBitNegSig) Clone() builtinFunc {
+	newSig := &builtinBitNegSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+func (b *builtinBitNegSig) evalInt(row chunk.Row) (int64, bool, error) {
+	arg, isNull, err := b.args[0].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return 0, true, err
+	}
+	return ^arg, false, nil
+}
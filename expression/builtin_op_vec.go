@@ -0,0 +1,311 @@
+package expression
+
+import (
+	"math"
+
+	"grant-db/types"
+	"grant-db/util/chunk"
+)
+
+// vecEvalBoolArgs evaluates the truth values of the two arguments of b by vectors, the first one is
+// evaluated into result and the second one into the returned buffer, which should be put back after using.
+func (b *baseBuiltinFunc) vecEvalBoolArgs(input *chunk.Chunk, result *chunk.Column) (*chunk.Column, error) {
+	if err := vecEvalBool(b.ctx, b.args[0], input, result); err != nil {
+		return nil, err
+	}
+	buf := b.bufAllocator.get(types.ETInt, input.NumRows())
+	if err := vecEvalBool(b.ctx, b.args[1], input, buf); err != nil {
+		b.bufAllocator.put(types.ETInt, buf)
+		return nil, err
+	}
+	return buf, nil
+}
+
+func (b *builtinLogicAndSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLogicAndSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	buf, err := b.vecEvalBoolArgs(input, result)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(types.ETInt, buf)
+	i64s, arg1s := result.Int64s(), buf.Int64s()
+	for i := range i64s {
+		isNull0, isNull1 := result.IsNull(i), buf.IsNull(i)
+		if (!isNull0 && i64s[i] == 0) || (!isNull1 && arg1s[i] == 0) {
+			i64s[i] = 0
+			result.SetNull(i, false)
+		} else if isNull1 {
+			result.SetNull(i, true)
+		}
+	}
+	return nil
+}
+
+func (b *builtinLogicOrSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLogicOrSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	buf, err := b.vecEvalBoolArgs(input, result)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(types.ETInt, buf)
+	i64s, arg1s := result.Int64s(), buf.Int64s()
+	for i := range i64s {
+		isNull0, isNull1 := result.IsNull(i), buf.IsNull(i)
+		if (!isNull0 && i64s[i] != 0) || (!isNull1 && arg1s[i] != 0) {
+			i64s[i] = 1
+			result.SetNull(i, false)
+		} else if isNull1 {
+			result.SetNull(i, true)
+		}
+	}
+	return nil
+}
+
+func (b *builtinLogicXorSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLogicXorSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	buf, err := b.vecEvalBoolArgs(input, result)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(types.ETInt, buf)
+	result.MergeNulls(buf)
+	i64s, arg1s := result.Int64s(), buf.Int64s()
+	for i := range i64s {
+		if i64s[i] != arg1s[i] {
+			i64s[i] = 1
+		} else {
+			i64s[i] = 0
+		}
+	}
+	return nil
+}
+
+func (b *builtinUnaryNotSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUnaryNotSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	if err := vecEvalBool(b.ctx, b.args[0], input, result); err != nil {
+		return err
+	}
+	i64s := result.Int64s()
+	for i := range i64s {
+		i64s[i] = 1 - i64s[i]
+	}
+	return nil
+}
+
+func (b *builtinIsTrueOrFalseSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinIsTrueOrFalseSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	if err := vecEvalBool(b.ctx, b.args[0], input, result); err != nil {
+		return err
+	}
+	expected := int64(0)
+	if b.isTrue {
+		expected = 1
+	}
+	i64s := result.Int64s()
+	for i := range i64s {
+		if !result.IsNull(i) && i64s[i] == expected {
+			i64s[i] = 1
+		} else {
+			i64s[i] = 0
+		}
+	}
+	result.SetNulls(0, len(i64s), false)
+	return nil
+}
+
+func (b *builtinIsNullSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinIsNullSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	evalType := b.args[0].GetType().EvalType()
+	buf := b.bufAllocator.get(evalType, n)
+	defer b.bufAllocator.put(evalType, buf)
+	if err := vecEval(b.ctx, b.args[0], input, buf); err != nil {
+		return err
+	}
+	result.ResizeInt64(n, false)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			i64s[i] = 1
+		} else {
+			i64s[i] = 0
+		}
+	}
+	return nil
+}
+
+func (b *builtinUnaryMinusIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUnaryMinusIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	if err := b.args[0].VecEvalInt(b.ctx, input, result); err != nil {
+		return err
+	}
+	i64s := result.Int64s()
+	for i := range i64s {
+		if result.IsNull(i) {
+			continue
+		}
+		if i64s[i] == math.MinInt64 {
+			return types.ErrDataOutOfRange.GenWithStackByArgs("BIGINT", "-"+b.args[0].String())
+		}
+		i64s[i] = -i64s[i]
+	}
+	return nil
+}
+
+func (b *builtinUnaryMinusDecimalSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUnaryMinusDecimalSig) vecEvalDecimal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf := b.bufAllocator.get(types.ETDecimal, n)
+	defer b.bufAllocator.put(types.ETDecimal, buf)
+	if err := b.args[0].VecEvalDecimal(b.ctx, input, buf); err != nil {
+		return err
+	}
+	result.ReserveDecimal(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		result.AppendMyDecimal(buf.GetDecimal(i).Neg())
+	}
+	return nil
+}
+
+func (b *builtinUnaryMinusRealSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUnaryMinusRealSig) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	if err := b.args[0].VecEvalReal(b.ctx, input, result); err != nil {
+		return err
+	}
+	f64s := result.Float64s()
+	for i := range f64s {
+		f64s[i] = -f64s[i]
+	}
+	return nil
+}
+
+func (b *builtinBitAndSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinBitAndSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	buf, err := b.vecEvalIntArgs(input, result)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(types.ETInt, buf)
+	arg0s, arg1s := result.Int64s(), buf.Int64s()
+	for i := range arg0s {
+		arg0s[i] &= arg1s[i]
+	}
+	return nil
+}
+
+func (b *builtinBitOrSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinBitOrSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	buf, err := b.vecEvalIntArgs(input, result)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(types.ETInt, buf)
+	arg0s, arg1s := result.Int64s(), buf.Int64s()
+	for i := range arg0s {
+		arg0s[i] |= arg1s[i]
+	}
+	return nil
+}
+
+func (b *builtinBitXorSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinBitXorSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	buf, err := b.vecEvalIntArgs(input, result)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(types.ETInt, buf)
+	arg0s, arg1s := result.Int64s(), buf.Int64s()
+	for i := range arg0s {
+		arg0s[i] ^= arg1s[i]
+	}
+	return nil
+}
+
+func (b *builtinLeftShiftSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinLeftShiftSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	buf, err := b.vecEvalIntArgs(input, result)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(types.ETInt, buf)
+	arg0s, arg1s := result.Int64s(), buf.Int64s()
+	for i := range arg0s {
+		arg0s[i] = int64(uint64(arg0s[i]) << uint64(arg1s[i]))
+	}
+	return nil
+}
+
+func (b *builtinRightShiftSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinRightShiftSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	buf, err := b.vecEvalIntArgs(input, result)
+	if err != nil {
+		return err
+	}
+	defer b.bufAllocator.put(types.ETInt, buf)
+	arg0s, arg1s := result.Int64s(), buf.Int64s()
+	for i := range arg0s {
+		arg0s[i] = int64(uint64(arg0s[i]) >> uint64(arg1s[i]))
+	}
+	return nil
+}
+
+func (b *builtinBitNegSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinBitNegSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	if err := b.args[0].VecEvalInt(b.ctx, input, result); err != nil {
+		return err
+	}
+	i64s := result.Int64s()
+	for i := range i64s {
+		i64s[i] = ^i64s[i]
+	}
+	return nil
+}
@@ -0,0 +1,381 @@
+package expression
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pingcap/parser/charset"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/sessionctx"
+	"grant-db/types"
+	"grant-db/util/chunk"
+)
+
+// maxAllowedPacket is the max_allowed_packet, the functions producing a longer string return NULL.
+const maxAllowedPacket = 64 << 20
+
+// isBinaryStr returns whether the string type is a binary string, whose length is counted by bytes.
+func isBinaryStr(tp *types.FieldType) bool {
+	return tp.Charset == charset.CharsetBin
+}
+
+// setBinStrIfAnyBinary sets the charset of the string result to binary if any argument is a binary string.
+func setBinStrIfAnyBinary(tp *types.FieldType, args ...Expression) {
+	for _, arg := range args {
+		if isBinaryStr(arg.GetType()) {
+			tp.Charset, tp.Collate = charset.CharsetBin, charset.CollationBin
+			types.SetBinChsClnFlag(tp)
+			return
+		}
+	}
+}
+
+type concatFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *concatFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := make([]types.EvalType, len(args))
+	for i := range args {
+		argTps[i] = types.ETString
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, argTps...)
+	bf.tp.Flen = 0
+	for _, arg := range bf.args {
+		argFlen := arg.GetType().Flen
+		if argFlen == types.UnspecifiedLength {
+			bf.tp.Flen = types.UnspecifiedLength
+			break
+		}
+		bf.tp.Flen += argFlen
+	}
+	if bf.tp.Flen > mysql.MaxBlobWidth {
+		bf.tp.Flen = mysql.MaxBlobWidth
+	}
+	setBinStrIfAnyBinary(bf.tp, bf.args...)
+	return &builtinConcatSig{bf}, nil
+}
+
+type builtinConcatSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinConcatSig) Clone() builtinFunc {
+	newSig := &builtinConcatSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals CONCAT(str1,str2,...), the result is NULL if any argument is NULL.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_concat
+func (b *builtinConcatSig) evalString(row chunk.Row) (string, bool, error) {
+	var s []byte
+	for _, a := range b.getArgs() {
+		d, isNull, err := a.EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return "", isNull, err
+		}
+		if len(s)+len(d) > maxAllowedPacket {
+			appendAllowedPacketWarning(b.ctx, "concat")
+			return "", true, nil
+		}
+		s = append(s, d...)
+	}
+	return string(s), false, nil
+}
+
+// appendAllowedPacketWarning appends the warning of a result larger than max_allowed_packet, the result is NULL.
+func appendAllowedPacketWarning(ctx sessionctx.Context, funcName string) {
+	ctx.GetSessionVars().StmtCtx.AppendWarning(errWarnAllowedPacketOverflowed.GenWithStackByArgs(funcName, maxAllowedPacket))
+}
+
+type substringFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *substringFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	argTps := []types.EvalType{types.ETString, types.ETInt}
+	if len(args) == 3 {
+		argTps = append(argTps, types.ETInt)
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, argTps...)
+	argTp := bf.args[0].GetType()
+	bf.tp.Flen = argTp.Flen
+	setBinStrIfAnyBinary(bf.tp, bf.args[0])
+	return &builtinSubstringSig{bf}, nil
+}
+
+type builtinSubstringSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinSubstringSig) Clone() builtinFunc {
+	newSig := &builtinSubstringSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals SUBSTRING(str,pos) and SUBSTRING(str,pos,len), the position starts from 1 and a negative
+// position counts from the end of str.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_substring
+func (b *builtinSubstringSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	pos, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	length := int64(-1)
+	if len(b.args) == 3 {
+		length, isNull, err = b.args[2].EvalInt(b.ctx, row)
+		if isNull || err != nil {
+			return "", isNull, err
+		}
+		if length <= 0 {
+			return "", false, nil
+		}
+	}
+	return substring(str, pos, length, isBinaryStr(b.tp)), false, nil
+}
+
+// substring returns the substring of str, a negative length means the rest of str.
+func substring(str string, pos, length int64, isBinary bool) string {
+	var runes []rune
+	strLength := int64(len(str))
+	if !isBinary {
+		runes = []rune(str)
+		strLength = int64(len(runes))
+	}
+	if pos < 0 {
+		pos += strLength
+	} else {
+		pos--
+	}
+	if pos < 0 || pos >= strLength {
+		return ""
+	}
+	end := strLength
+	if length >= 0 && length < strLength-pos {
+		end = pos + length
+	}
+	if isBinary {
+		return str[pos:end]
+	}
+	return string(runes[pos:end])
+}
+
+type replaceFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *replaceFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString, types.ETString, types.ETString)
+	bf.tp.Flen = c.fixLength(bf.args)
+	setBinStrIfAnyBinary(bf.tp, bf.args...)
+	return &builtinReplaceSig{bf}, nil
+}
+
+// fixLength returns the max length of the result, which is reached when each character of str is replaced.
+func (c *replaceFunctionClass) fixLength(args []Expression) int {
+	charLen := args[0].GetType().Flen
+	oldStrLen, newStrLen := args[1].GetType().Flen, args[2].GetType().Flen
+	if charLen == types.UnspecifiedLength || oldStrLen == types.UnspecifiedLength || newStrLen == types.UnspecifiedLength {
+		return types.UnspecifiedLength
+	}
+	if oldStrLen > 0 && newStrLen > oldStrLen {
+		charLen = charLen / oldStrLen * newStrLen
+	}
+	if charLen > mysql.MaxBlobWidth {
+		charLen = mysql.MaxBlobWidth
+	}
+	return charLen
+}
+
+type builtinReplaceSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinReplaceSig) Clone() builtinFunc {
+	newSig := &builtinReplaceSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals REPLACE(str,from_str,to_str), the matching is case sensitive.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_replace
+func (b *builtinReplaceSig) evalString(row chunk.Row) (string, bool, error) {
+	var str, oldStr, newStr string
+	for i, s := range []*string{&str, &oldStr, &newStr} {
+		v, isNull, err := b.args[i].EvalString(b.ctx, row)
+		if isNull || err != nil {
+			return "", isNull, err
+		}
+		*s = v
+	}
+	if oldStr == "" {
+		return str, false, nil
+	}
+	return strings.Replace(str, oldStr, newStr, -1), false, nil
+}
+
+type padFunctionClass struct {
+	baseFunctionClass
+	isLeft bool
+}
+
+func (c *padFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString, types.ETInt, types.ETString)
+	bf.tp.Flen = types.UnspecifiedLength
+	if constLen, ok := bf.args[1].(*Constant); ok {
+		// The length is known when it's a constant.
+		if d, err := constLen.Eval(chunk.Row{}); err == nil && !d.IsNull() {
+			if l := d.GetInt64(); l >= 0 && l <= mysql.MaxBlobWidth {
+				bf.tp.Flen = int(l)
+			}
+		}
+	}
+	setBinStrIfAnyBinary(bf.tp, bf.args[0], bf.args[2])
+	return &builtinPadSig{bf, c.isLeft}, nil
+}
+
+type builtinPadSig struct {
+	baseBuiltinFunc
+	isLeft bool
+}
+
+func (b *builtinPadSig) Clone() builtinFunc {
+	newSig := &builtinPadSig{isLeft: b.isLeft}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals LPAD(str,len,padstr) and RPAD(str,len,padstr), str is truncated to len characters if it's
+// longer than len.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_lpad
+func (b *builtinPadSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	length, isNull, err := b.args[1].EvalInt(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	padStr, isNull, err := b.args[2].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return b.pad(str, length, padStr)
+}
+
+func (b *builtinPadSig) pad(str string, length int64, padStr string) (string, bool, error) {
+	if length < 0 {
+		return "", true, nil
+	}
+	isBinary := isBinaryStr(b.tp)
+	// The result is checked by the max byte length like MySQL, which is len characters of the widest encoding.
+	byteLen := length
+	if !isBinary {
+		byteLen *= utf8.UTFMax
+	}
+	if byteLen > maxAllowedPacket {
+		appendAllowedPacketWarning(b.ctx, b.funcName())
+		return "", true, nil
+	}
+	if isBinary {
+		if int64(len(str)) >= length {
+			return str[:length], false, nil
+		}
+		if padStr == "" {
+			return "", true, nil
+		}
+		padLen := int(length) - len(str)
+		fill := strings.Repeat(padStr, padLen/len(padStr)+1)[:padLen]
+		if b.isLeft {
+			return fill + str, false, nil
+		}
+		return str + fill, false, nil
+	}
+	runes := []rune(str)
+	if int64(len(runes)) >= length {
+		return string(runes[:length]), false, nil
+	}
+	padRunes := []rune(padStr)
+	if len(padRunes) == 0 {
+		return "", true, nil
+	}
+	padLen := int(length) - len(runes)
+	fill := []rune(strings.Repeat(padStr, padLen/len(padRunes)+1))[:padLen]
+	if b.isLeft {
+		return string(fill) + str, false, nil
+	}
+	return str + string(fill), false, nil
+}
+
+func (b *builtinPadSig) funcName() string {
+	if b.isLeft {
+		return "lpad"
+	}
+	return "rpad"
+}
+
+type changeCaseFunctionClass struct {
+	baseFunctionClass
+	isUpper bool
+}
+
+func (c *changeCaseFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString)
+	bf.tp.Flen = bf.args[0].GetType().Flen
+	setBinStrIfAnyBinary(bf.tp, bf.args[0])
+	return &builtinChangeCaseSig{bf, c.isUpper}, nil
+}
+
+type builtinChangeCaseSig struct {
+	baseBuiltinFunc
+	isUpper bool
+}
+
+func (b *builtinChangeCaseSig) Clone() builtinFunc {
+	newSig := &builtinChangeCaseSig{isUpper: b.isUpper}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals LOWER(str) and UPPER(str), the binary strings are returned unchanged.
+// See https://dev.mysql.com/doc/refman/5.7/en/string-functions.html#function_lower
+func (b *builtinChangeCaseSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return b.changeCase(str), false, nil
+}
+
+func (b *builtinChangeCaseSig) changeCase(str string) string {
+	switch {
+	case isBinaryStr(b.tp):
+		return str
+	case b.isUpper:
+		return strings.ToUpper(str)
+	default:
+		return strings.ToLower(str)
+	}
+}
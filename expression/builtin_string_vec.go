@@ -0,0 +1,201 @@
+package expression
+
+import (
+	"strings"
+
+	"grant-db/types"
+	"grant-db/util/chunk"
+)
+
+func (b *builtinConcatSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinConcatSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs := make([]*chunk.Column, 0, len(b.args))
+	defer func() {
+		for _, buf := range bufs {
+			b.bufAllocator.put(types.ETString, buf)
+		}
+	}()
+	for _, arg := range b.args {
+		buf := b.bufAllocator.get(types.ETString, n)
+		bufs = append(bufs, buf)
+		if err := arg.VecEvalString(b.ctx, input, buf); err != nil {
+			return err
+		}
+	}
+	result.ReserveString(n)
+	var s []byte
+	for i := 0; i < n; i++ {
+		s = s[:0]
+		isNull := false
+		for _, buf := range bufs {
+			if buf.IsNull(i) {
+				isNull = true
+				break
+			}
+			d := buf.GetString(i)
+			if len(s)+len(d) > maxAllowedPacket {
+				appendAllowedPacketWarning(b.ctx, "concat")
+				isNull = true
+				break
+			}
+			s = append(s, d...)
+		}
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		result.AppendBytes(s)
+	}
+	return nil
+}
+
+func (b *builtinChangeCaseSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinChangeCaseSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf := b.bufAllocator.get(types.ETString, n)
+	defer b.bufAllocator.put(types.ETString, buf)
+	if err := b.args[0].VecEvalString(b.ctx, input, buf); err != nil {
+		return err
+	}
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(b.changeCase(buf.GetString(i)))
+	}
+	return nil
+}
+
+func (b *builtinSubstringSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinSubstringSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	strBuf := b.bufAllocator.get(types.ETString, n)
+	defer b.bufAllocator.put(types.ETString, strBuf)
+	if err := b.args[0].VecEvalString(b.ctx, input, strBuf); err != nil {
+		return err
+	}
+	posBuf := b.bufAllocator.get(types.ETInt, n)
+	defer b.bufAllocator.put(types.ETInt, posBuf)
+	if err := b.args[1].VecEvalInt(b.ctx, input, posBuf); err != nil {
+		return err
+	}
+	var lenBuf *chunk.Column
+	if len(b.args) == 3 {
+		lenBuf = b.bufAllocator.get(types.ETInt, n)
+		defer b.bufAllocator.put(types.ETInt, lenBuf)
+		if err := b.args[2].VecEvalInt(b.ctx, input, lenBuf); err != nil {
+			return err
+		}
+	}
+	isBinary := isBinaryStr(b.tp)
+	positions := posBuf.Int64s()
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if strBuf.IsNull(i) || posBuf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		length := int64(-1)
+		if lenBuf != nil {
+			if lenBuf.IsNull(i) {
+				result.AppendNull()
+				continue
+			}
+			if length = lenBuf.Int64s()[i]; length <= 0 {
+				result.AppendString("")
+				continue
+			}
+		}
+		result.AppendString(substring(strBuf.GetString(i), positions[i], length, isBinary))
+	}
+	return nil
+}
+
+func (b *builtinReplaceSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinReplaceSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	bufs := make([]*chunk.Column, 0, len(b.args))
+	defer func() {
+		for _, buf := range bufs {
+			b.bufAllocator.put(types.ETString, buf)
+		}
+	}()
+	for _, arg := range b.args {
+		buf := b.bufAllocator.get(types.ETString, n)
+		bufs = append(bufs, buf)
+		if err := arg.VecEvalString(b.ctx, input, buf); err != nil {
+			return err
+		}
+	}
+	strBuf, oldBuf, newBuf := bufs[0], bufs[1], bufs[2]
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if strBuf.IsNull(i) || oldBuf.IsNull(i) || newBuf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		str, oldStr := strBuf.GetString(i), oldBuf.GetString(i)
+		if oldStr == "" {
+			result.AppendString(str)
+			continue
+		}
+		result.AppendString(strings.Replace(str, oldStr, newBuf.GetString(i), -1))
+	}
+	return nil
+}
+
+func (b *builtinPadSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinPadSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	strBuf := b.bufAllocator.get(types.ETString, n)
+	defer b.bufAllocator.put(types.ETString, strBuf)
+	if err := b.args[0].VecEvalString(b.ctx, input, strBuf); err != nil {
+		return err
+	}
+	lenBuf := b.bufAllocator.get(types.ETInt, n)
+	defer b.bufAllocator.put(types.ETInt, lenBuf)
+	if err := b.args[1].VecEvalInt(b.ctx, input, lenBuf); err != nil {
+		return err
+	}
+	padBuf := b.bufAllocator.get(types.ETString, n)
+	defer b.bufAllocator.put(types.ETString, padBuf)
+	if err := b.args[2].VecEvalString(b.ctx, input, padBuf); err != nil {
+		return err
+	}
+	lengths := lenBuf.Int64s()
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if strBuf.IsNull(i) || lenBuf.IsNull(i) || padBuf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		res, isNull, err := b.pad(strBuf.GetString(i), lengths[i], padBuf.GetString(i))
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package expression_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pingcap/parser/mysql"
+	"grant-db/util/testkit"
+)
+
+// checkWarnings checks the codes of the warnings of the last statement.
+func checkWarnings(t *testing.T, tk *testkit.TestKit, codes ...int) {
+	t.Helper()
+	rows := tk.MustQuery("show warnings").Rows()
+	got := make([]string, 0, len(rows))
+	for _, row := range rows {
+		got = append(got, strings.Fields(row)[1])
+	}
+	if fmt.Sprint(got) != fmt.Sprint(codes) {
+		t.Fatalf("the warnings are %v, expected the codes %v", rows, codes)
+	}
+}
+
+func TestBuiltinFuncs(t *testing.T) {
+	store, _ := testkit.NewMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustQuery("select concat('a', 1, 2.5), concat('a', null), substring('hello', 2, 3), substring('hello', -3)").
+		Check("a12.5 <nil> ell llo")
+	tk.MustQuery("select replace('aXbXc', 'X', '-'), lpad('7', 3, '0'), lpad('hello', 3, '0'), lower('AbC'), upper('AbC')").
+		Check("a-b-c 007 hel abc ABC")
+	tk.MustQuery("select round(2.5), round(-2.5), round(1.2345, 2), round(1234, -2), floor(-1.5), abs(-3), mod(7, -3), mod(-7, 3)").
+		Check("3 -3 1.23 1200 -2 3 1 -1")
+	tk.MustQuery("select date_format('2020-01-02 03:04:05', '%Y/%m/%d %H:%i:%s %W'), date_add('2020-01-31', interval 1 month), datediff('2020-03-01', '2020-02-01')").
+		Check("2020/01/02 03:04:05 Thursday 2020-02-29 29")
+	tk.MustQuery("select if(1 > 2, 'a', 'b'), ifnull(null, 3), coalesce(null, null, 'c'), case 2 when 1 then 'one' when 2 then 'two' end").
+		Check("b 3 c two")
+	tk.MustQuery("select cast('12abc' as signed), cast(1.5 as char), cast('2020-01-02' as date)").
+		Check("12 1.5 2020-01-02")
+	checkWarnings(t, tk, mysql.ErrTruncatedWrongValue)
+	tk.MustQuery("select unix_timestamp('1970-01-01 00:00:01') > 0, now() = current_timestamp()").Check("1 1")
+}
+
+func TestBuiltinSQLMode(t *testing.T) {
+	store, _ := testkit.NewMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create database test")
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int, d date)")
+
+	// SELECT warns of the division by zero and the invalid date.
+	tk.MustQuery("select 1 / 0, 5 mod 0").Check("<nil> <nil>")
+	checkWarnings(t, tk, mysql.ErrDivisionByZero, mysql.ErrDivisionByZero)
+	tk.MustQuery("select date_add('2020-02-30', interval 1 day)").Check("<nil>")
+	checkWarnings(t, tk, mysql.ErrTruncatedWrongValue)
+
+	// The strict mode makes them errors on write.
+	tk.MustExec("set sql_mode = 'STRICT_TRANS_TABLES,ERROR_FOR_DIVISION_BY_ZERO'")
+	tk.MustGetErrCode("insert into t (a) values (1 / 0)", mysql.ErrDivisionByZero)
+	tk.MustGetErrCode("insert into t (d) values ('2020-02-30')", mysql.ErrTruncatedWrongValue)
+	tk.MustExec("insert ignore into t (a) values (1 / 0)")
+	checkWarnings(t, tk, mysql.ErrDivisionByZero)
+
+	// The division by zero is NULL without a warning if ERROR_FOR_DIVISION_BY_ZERO is off.
+	tk.MustExec("set sql_mode = 'STRICT_TRANS_TABLES'")
+	tk.MustExec("insert into t (a) values (1 / 0)")
+	checkWarnings(t, tk)
+	tk.MustExec("set sql_mode = 'ALLOW_INVALID_DATES'")
+	tk.MustExec("insert into t (d) values ('2020-02-30')")
+	tk.MustQuery("select a, d from t").Check("<nil> <nil>", "<nil> <nil>", "<nil> 2020-02-30")
+}
@@ -0,0 +1,507 @@
+package expression
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/pingcap/parser/charset"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/sessionctx"
+	"grant-db/types"
+	"grant-db/util/chunk"
+)
+
+// getConstFsp returns the fsp argument of a function like NOW(fsp), which must be a constant in 0~6.
+func getConstFsp(ctx sessionctx.Context, funcName string, args []Expression) (int8, error) {
+	if len(args) == 0 {
+		return types.DefaultFsp, nil
+	}
+	con, ok := args[0].(*Constant)
+	if !ok || args[0].GetType().EvalType() != types.ETInt {
+		return 0, ErrNotSupportedYet.GenWithStackByArgs(fmt.Sprintf("non-constant fsp of %s", funcName))
+	}
+	fsp, isNull, err := con.EvalInt(ctx, chunk.Row{})
+	if err != nil {
+		return 0, err
+	}
+	if isNull || fsp < int64(types.MinFsp) {
+		return 0, ErrNotSupportedYet.GenWithStackByArgs(fmt.Sprintf("invalid fsp of %s", funcName))
+	}
+	if fsp > int64(types.MaxFsp) {
+		return 0, types.ErrTooBigPrecision.GenWithStackByArgs(fsp, funcName, types.MaxFsp)
+	}
+	return int8(fsp), nil
+}
+
+// newDatetimeFieldType returns the field type of a datetime or date with the fsp.
+func newDatetimeFieldType(tp byte, fsp int8) *types.FieldType {
+	ft := types.NewFieldType(tp)
+	switch tp {
+	case mysql.TypeDate:
+		ft.Flen, ft.Decimal = mysql.MaxDateWidth, 0
+	default:
+		ft.Flen, ft.Decimal = mysql.MaxDatetimeWidthNoFsp, int(fsp)
+		if fsp > 0 {
+			ft.Flen += 1 + int(fsp)
+		}
+	}
+	types.SetBinChsClnFlag(ft)
+	return ft
+}
+
+// truncateGoTime truncates the fraction of the Go time to fsp digits.
+func truncateGoTime(tm time.Time, fsp int8) time.Time {
+	return tm.Truncate(time.Duration(math.Pow10(int(types.MaxFsp-fsp))) * time.Microsecond)
+}
+
+type nowFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *nowFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	fsp, err := getConstFsp(ctx, c.funcName, args)
+	if err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, nil, types.ETDatetime)
+	bf.tp = newDatetimeFieldType(mysql.TypeDatetime, fsp)
+	return &builtinNowSig{bf, fsp}, nil
+}
+
+type builtinNowSig struct {
+	baseBuiltinFunc
+	fsp int8
+}
+
+func (b *builtinNowSig) Clone() builtinFunc {
+	newSig := &builtinNowSig{fsp: b.fsp}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals NOW() and NOW(fsp), which is the start time of the statement, so it's the same in a statement.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_now
+func (b *builtinNowSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	nowTs := b.ctx.GetSessionVars().StmtCtx.GetNowTsCached()
+	tm := truncateGoTime(nowTs.In(time.Local), b.fsp)
+	return types.FromGoTime(tm, mysql.TypeDatetime, b.fsp), false, nil
+}
+
+type dateFormatFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *dateFormatFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETDatetime, types.ETString)
+	bf.tp.Charset, bf.tp.Collate = charset.CharsetUTF8MB4, charset.CollationUTF8MB4
+	bf.tp.Flag &^= mysql.BinaryFlag
+	// Each specifier has 2 characters and is formatted into at most 9, like %W of "Wednesday".
+	if formatLen := bf.args[1].GetType().Flen; formatLen != types.UnspecifiedLength {
+		bf.tp.Flen = minInt(formatLen*5, mysql.MaxBlobWidth)
+	}
+	return &builtinDateFormatSig{bf}, nil
+}
+
+type builtinDateFormatSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinDateFormatSig) Clone() builtinFunc {
+	newSig := &builtinDateFormatSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals DATE_FORMAT(date,format).
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_date-format
+func (b *builtinDateFormatSig) evalString(row chunk.Row) (string, bool, error) {
+	t, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	layout, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	res, err := t.DateFormat(layout)
+	if err != nil {
+		return "", true, b.ctx.GetSessionVars().StmtCtx.HandleTruncate(err)
+	}
+	return res, false, nil
+}
+
+type addSubDateFunctionClass struct {
+	baseFunctionClass
+	isSub bool
+}
+
+func (c *addSubDateFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	unitCon, ok := args[2].(*Constant)
+	if !ok {
+		return nil, ErrNotSupportedYet.GenWithStackByArgs("non-constant interval unit")
+	}
+	unit, _, err := unitCon.EvalString(ctx, chunk.Row{})
+	if err != nil {
+		return nil, err
+	}
+	unit = strings.ToUpper(unit)
+	intervalFsp := types.DefaultFsp
+	if types.IsMicrosecondUnit(unit) {
+		intervalFsp = types.MaxFsp
+	} else if unit == "SECOND" {
+		if frac := args[1].GetType().Decimal; frac > 0 {
+			intervalFsp = int8(minInt(frac, int(types.MaxFsp)))
+		}
+	}
+	dateTp := args[0].GetType()
+	switch dateTp.Tp {
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETDatetime, types.ETDatetime, types.ETString, types.ETString)
+		if dateTp.Tp == mysql.TypeDate && !types.IsClockUnit(unit) {
+			bf.tp = newDatetimeFieldType(mysql.TypeDate, 0)
+		} else {
+			fsp := intervalFsp
+			if dateTp.Decimal > int(fsp) && dateTp.Decimal != types.UnspecifiedLength {
+				fsp = int8(dateTp.Decimal)
+			}
+			bf.tp = newDatetimeFieldType(mysql.TypeDatetime, fsp)
+		}
+		return &builtinAddSubDateDatetimeSig{bf, unit, c.isSub}, nil
+	}
+	// The string and number are parsed as a date or datetime, and the result is formatted back to a string.
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETString, types.ETString, types.ETString, types.ETString)
+	bf.tp.Flen = mysql.MaxDatetimeWidthWithFsp
+	return &builtinAddSubDateStringSig{bf, unit, c.isSub}, nil
+}
+
+// addSubDate adds the interval of the unit to t or subtracts it from t, it's NULL if t is an invalid date
+// or the result overflows.
+func (b *baseBuiltinFunc) addSubDate(t types.Time, row chunk.Row, unit string, isSub bool) (types.Time, bool, error) {
+	sc := b.ctx.GetSessionVars().StmtCtx
+	if t.InvalidZero() {
+		return t, true, sc.HandleTruncate(types.ErrTruncatedWrongVal.GenWithStackByArgs(types.TypeStr(mysql.TypeDatetime), t.String()))
+	}
+	interval, isNull, err := b.args[1].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return t, true, err
+	}
+	return b.addSubInterval(t, interval, unit, isSub)
+}
+
+// addSubInterval adds the interval string of the unit to t or subtracts it from t, t must be a valid date.
+func (b *baseBuiltinFunc) addSubInterval(t types.Time, interval, unit string, isSub bool) (types.Time, bool, error) {
+	sc := b.ctx.GetSessionVars().StmtCtx
+	years, months, days, nanos, err := types.ParseDurationValue(sc, unit, interval)
+	if err != nil {
+		if types.ErrDatetimeFunctionOverflow.Equal(err) {
+			sc.AppendWarning(err)
+			return t, true, nil
+		}
+		return t, true, sc.HandleTruncate(err)
+	}
+	if isSub {
+		years, months, days, nanos = -years, -months, -days, -nanos
+	}
+	res, err := t.AddInterval(years, months, days, nanos)
+	if err != nil {
+		sc.AppendWarning(err)
+		return t, true, nil
+	}
+	return res, false, nil
+}
+
+type builtinAddSubDateDatetimeSig struct {
+	baseBuiltinFunc
+	unit  string
+	isSub bool
+}
+
+func (b *builtinAddSubDateDatetimeSig) Clone() builtinFunc {
+	newSig := &builtinAddSubDateDatetimeSig{unit: b.unit, isSub: b.isSub}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalTime evals DATE_ADD(date,INTERVAL expr unit) and DATE_SUB(date,INTERVAL expr unit) of a date or datetime.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_date-add
+func (b *builtinAddSubDateDatetimeSig) evalTime(row chunk.Row) (types.Time, bool, error) {
+	t, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return t, isNull, err
+	}
+	res, isNull, err := b.addSubDate(t, row, b.unit, b.isSub)
+	if isNull || err != nil {
+		return res, isNull, err
+	}
+	return b.convertResult(res), false, nil
+}
+
+// convertResult converts the result to the date or datetime type of the function.
+func (b *builtinAddSubDateDatetimeSig) convertResult(res types.Time) types.Time {
+	if b.tp.Tp == mysql.TypeDate {
+		return types.NewTime(res.Year(), res.Month(), res.Day(), 0, 0, 0, 0, mysql.TypeDate, 0)
+	}
+	res.SetType(b.tp.Tp)
+	res.SetFsp(int8(b.tp.Decimal))
+	return res
+}
+
+type builtinAddSubDateStringSig struct {
+	baseBuiltinFunc
+	unit  string
+	isSub bool
+}
+
+func (b *builtinAddSubDateStringSig) Clone() builtinFunc {
+	newSig := &builtinAddSubDateStringSig{unit: b.unit, isSub: b.isSub}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalString evals DATE_ADD and DATE_SUB of a string or number, the result is a date if the argument is a date
+// and the unit has no time part, or a datetime otherwise.
+func (b *builtinAddSubDateStringSig) evalString(row chunk.Row) (string, bool, error) {
+	str, isNull, err := b.args[0].EvalString(b.ctx, row)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	t, isDate, isNull, err := b.parseDate(str)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	res, isNull, err := b.addSubDate(t, row, b.unit, b.isSub)
+	if isNull || err != nil {
+		return "", isNull, err
+	}
+	return b.formatResult(res, isDate), false, nil
+}
+
+// parseDate parses str as a datetime, isDate is true if str has no time part.
+func (b *builtinAddSubDateStringSig) parseDate(str string) (t types.Time, isDate bool, isNull bool, err error) {
+	sc := b.ctx.GetSessionVars().StmtCtx
+	t, err = types.ParseDateOrDatetime(str)
+	if err == nil {
+		err = t.Check(sc)
+	}
+	if err != nil {
+		return t, false, true, sc.HandleTruncate(types.ErrTruncatedWrongVal.GenWithStackByArgs(types.TypeStr(mysql.TypeDatetime), str))
+	}
+	isDate = t.Type() == mysql.TypeDate
+	t.SetType(mysql.TypeDatetime)
+	return t, isDate, false, nil
+}
+
+// formatResult formats the result as a date if the argument is a date and the unit has no time part.
+func (b *builtinAddSubDateStringSig) formatResult(res types.Time, isDate bool) string {
+	switch {
+	case isDate && !types.IsClockUnit(b.unit):
+		res.SetType(mysql.TypeDate)
+	case res.Microsecond() != 0:
+		res.SetFsp(types.MaxFsp)
+	default:
+		res.SetFsp(types.DefaultFsp)
+	}
+	return res.String()
+}
+
+type dateDiffFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *dateDiffFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt, types.ETDatetime, types.ETDatetime)
+	return &builtinDateDiffSig{bf}, nil
+}
+
+type builtinDateDiffSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinDateDiffSig) Clone() builtinFunc {
+	newSig := &builtinDateDiffSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals DATEDIFF(expr1,expr2), which is the number of days from expr2 to expr1.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_datediff
+func (b *builtinDateDiffSig) evalInt(row chunk.Row) (int64, bool, error) {
+	t1, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	t2, isNull, err := b.args[1].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	return b.dateDiff(t1, t2)
+}
+
+// dateDiff returns the number of days from t2 to t1, it's NULL with a warning if either of them is invalid.
+func (b *builtinDateDiffSig) dateDiff(t1, t2 types.Time) (int64, bool, error) {
+	for _, t := range []types.Time{t1, t2} {
+		if t.InvalidZero() {
+			sc := b.ctx.GetSessionVars().StmtCtx
+			sc.AppendWarning(types.ErrTruncatedWrongVal.GenWithStackByArgs(types.TypeStr(mysql.TypeDatetime), t.String()))
+			return 0, true, nil
+		}
+	}
+	return int64(types.DateDiff(t1, t2)), false, nil
+}
+
+type unixTimestampFunctionClass struct {
+	baseFunctionClass
+}
+
+func (c *unixTimestampFunctionClass) getFunction(ctx sessionctx.Context, args []Expression) (builtinFunc, error) {
+	if err := c.verifyArgs(args); err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		bf := newBaseBuiltinFuncWithTp(ctx, args, types.ETInt)
+		bf.tp.Flen = 11
+		return &builtinUnixTimestampCurrentSig{bf}, nil
+	}
+	fsp := unixTimestampArgFsp(ctx, args[0])
+	retTp := types.ETInt
+	if fsp > 0 {
+		retTp = types.ETDecimal
+	}
+	bf := newBaseBuiltinFuncWithTp(ctx, args, retTp, types.ETDatetime)
+	bf.tp.Flen, bf.tp.Decimal = 11, int(fsp)
+	if fsp > 0 {
+		bf.tp.Flen += 1 + int(fsp)
+	}
+	if retTp == types.ETInt {
+		return &builtinUnixTimestampIntSig{bf}, nil
+	}
+	return &builtinUnixTimestampDecSig{bf}, nil
+}
+
+// unixTimestampArgFsp returns the fsp of the argument of UNIX_TIMESTAMP, the result is a decimal if it's positive.
+// The fsp of a string constant is the number of its fraction digits, and it's 6 for other strings.
+func unixTimestampArgFsp(ctx sessionctx.Context, arg Expression) int8 {
+	ft := arg.GetType()
+	switch ft.EvalType() {
+	case types.ETInt:
+		return types.DefaultFsp
+	case types.ETString:
+		con, ok := arg.(*Constant)
+		if !ok {
+			return types.MaxFsp
+		}
+		str, isNull, err := con.EvalString(ctx, chunk.Row{})
+		if isNull || err != nil {
+			return types.DefaultFsp
+		}
+		dot := strings.LastIndexByte(str, '.')
+		if dot < 0 {
+			return types.DefaultFsp
+		}
+		return int8(minInt(len(strings.TrimSpace(str[dot+1:])), int(types.MaxFsp)))
+	}
+	if ft.Decimal == types.UnspecifiedLength {
+		return types.MaxFsp
+	}
+	return int8(minInt(ft.Decimal, int(types.MaxFsp)))
+}
+
+// unixTimestamp returns the seconds and microseconds since the Unix epoch of t in the local time zone, ok is false
+// if t is invalid or out of the range of TIMESTAMP, then the result is 0.
+func unixTimestamp(t types.Time) (secs int64, micros int64, ok bool) {
+	tm, err := t.GoTime(time.Local)
+	if err != nil || t.InvalidZero() {
+		return 0, 0, false
+	}
+	secs = tm.Unix()
+	if secs < 0 || secs > math.MaxInt32 {
+		return 0, 0, false
+	}
+	return secs, int64(tm.Nanosecond() / 1000), true
+}
+
+type builtinUnixTimestampCurrentSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUnixTimestampCurrentSig) Clone() builtinFunc {
+	newSig := &builtinUnixTimestampCurrentSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals UNIX_TIMESTAMP(), which is the seconds of the start time of the statement.
+// See https://dev.mysql.com/doc/refman/5.7/en/date-and-time-functions.html#function_unix-timestamp
+func (b *builtinUnixTimestampCurrentSig) evalInt(row chunk.Row) (int64, bool, error) {
+	return b.ctx.GetSessionVars().StmtCtx.GetNowTsCached().Unix(), false, nil
+}
+
+type builtinUnixTimestampIntSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUnixTimestampIntSig) Clone() builtinFunc {
+	newSig := &builtinUnixTimestampIntSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalInt evals UNIX_TIMESTAMP(date) of a date without the fraction.
+func (b *builtinUnixTimestampIntSig) evalInt(row chunk.Row) (int64, bool, error) {
+	t, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return 0, isNull, err
+	}
+	secs, _, _ := unixTimestamp(t)
+	return secs, false, nil
+}
+
+type builtinUnixTimestampDecSig struct {
+	baseBuiltinFunc
+}
+
+func (b *builtinUnixTimestampDecSig) Clone() builtinFunc {
+	newSig := &builtinUnixTimestampDecSig{}
+	newSig.cloneFrom(&b.baseBuiltinFunc)
+	return newSig
+}
+
+// evalDecimal evals UNIX_TIMESTAMP(date) of a date with the fraction, which is kept in the result.
+func (b *builtinUnixTimestampDecSig) evalDecimal(row chunk.Row) (*types.MyDecimal, bool, error) {
+	t, isNull, err := b.args[0].EvalTime(b.ctx, row)
+	if isNull || err != nil {
+		return nil, isNull, err
+	}
+	res, err := b.unixTimestampDecimal(t)
+	if err != nil {
+		return nil, true, err
+	}
+	return res, false, nil
+}
+
+// unixTimestampDecimal returns the seconds since the Unix epoch of t with the fraction of the result type.
+func (b *builtinUnixTimestampDecSig) unixTimestampDecimal(t types.Time) (*types.MyDecimal, error) {
+	res := new(types.MyDecimal)
+	secs, micros, ok := unixTimestamp(t)
+	if !ok {
+		return res.FromInt(0), nil
+	}
+	if err := res.FromString([]byte(fmt.Sprintf("%d.%06d", secs, micros))); err != nil {
+		return nil, err
+	}
+	return res.Rescale(b.tp.Decimal), nil
+}
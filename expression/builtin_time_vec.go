@@ -0,0 +1,249 @@
+package expression
+
+import (
+	"github.com/pingcap/parser/mysql"
+	"grant-db/types"
+	"grant-db/util/chunk"
+)
+
+func (b *builtinDateFormatSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinDateFormatSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	timeBuf := b.bufAllocator.get(types.ETDatetime, n)
+	defer b.bufAllocator.put(types.ETDatetime, timeBuf)
+	if err := b.args[0].VecEvalTime(b.ctx, input, timeBuf); err != nil {
+		return err
+	}
+	layoutBuf := b.bufAllocator.get(types.ETString, n)
+	defer b.bufAllocator.put(types.ETString, layoutBuf)
+	if err := b.args[1].VecEvalString(b.ctx, input, layoutBuf); err != nil {
+		return err
+	}
+	sc := b.ctx.GetSessionVars().StmtCtx
+	times := timeBuf.Times()
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if timeBuf.IsNull(i) || layoutBuf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		res, err := times[i].DateFormat(layoutBuf.GetString(i))
+		if err != nil {
+			if err = sc.HandleTruncate(err); err != nil {
+				return err
+			}
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
+
+// vecAddSubDate adds the intervals to the valid dates of times or subtracts them, the rows are set to NULL in
+// result if the date is invalid or the result overflows.
+func (b *baseBuiltinFunc) vecAddSubDate(input *chunk.Chunk, times []types.Time, result *chunk.Column, unit string, isSub bool) error {
+	n := input.NumRows()
+	intervalBuf := b.bufAllocator.get(types.ETString, n)
+	defer b.bufAllocator.put(types.ETString, intervalBuf)
+	if err := b.args[1].VecEvalString(b.ctx, input, intervalBuf); err != nil {
+		return err
+	}
+	sc := b.ctx.GetSessionVars().StmtCtx
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		if times[i].InvalidZero() {
+			err := types.ErrTruncatedWrongVal.GenWithStackByArgs(types.TypeStr(mysql.TypeDatetime), times[i].String())
+			if err = sc.HandleTruncate(err); err != nil {
+				return err
+			}
+			result.SetNull(i, true)
+			continue
+		}
+		if intervalBuf.IsNull(i) {
+			result.SetNull(i, true)
+			continue
+		}
+		res, isNull, err := b.addSubInterval(times[i], intervalBuf.GetString(i), unit, isSub)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.SetNull(i, true)
+			continue
+		}
+		times[i] = res
+	}
+	return nil
+}
+
+func (b *builtinAddSubDateDatetimeSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinAddSubDateDatetimeSig) vecEvalTime(input *chunk.Chunk, result *chunk.Column) error {
+	if err := b.args[0].VecEvalTime(b.ctx, input, result); err != nil {
+		return err
+	}
+	times := result.Times()
+	if err := b.vecAddSubDate(input, times, result, b.unit, b.isSub); err != nil {
+		return err
+	}
+	for i := range times {
+		if !result.IsNull(i) {
+			times[i] = b.convertResult(times[i])
+		}
+	}
+	return nil
+}
+
+func (b *builtinAddSubDateStringSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinAddSubDateStringSig) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	strBuf := b.bufAllocator.get(types.ETString, n)
+	defer b.bufAllocator.put(types.ETString, strBuf)
+	if err := b.args[0].VecEvalString(b.ctx, input, strBuf); err != nil {
+		return err
+	}
+	timeBuf := b.bufAllocator.get(types.ETDatetime, n)
+	defer b.bufAllocator.put(types.ETDatetime, timeBuf)
+	timeBuf.ResizeTime(n, false)
+	times := timeBuf.Times()
+	isDates := make([]bool, n)
+	for i := 0; i < n; i++ {
+		if strBuf.IsNull(i) {
+			timeBuf.SetNull(i, true)
+			continue
+		}
+		t, isDate, isNull, err := b.parseDate(strBuf.GetString(i))
+		if err != nil {
+			return err
+		}
+		if isNull {
+			timeBuf.SetNull(i, true)
+			continue
+		}
+		times[i], isDates[i] = t, isDate
+	}
+	if err := b.vecAddSubDate(input, times, timeBuf, b.unit, b.isSub); err != nil {
+		return err
+	}
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if timeBuf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(b.formatResult(times[i], isDates[i]))
+	}
+	return nil
+}
+
+func (b *builtinDateDiffSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinDateDiffSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf1 := b.bufAllocator.get(types.ETDatetime, n)
+	defer b.bufAllocator.put(types.ETDatetime, buf1)
+	if err := b.args[0].VecEvalTime(b.ctx, input, buf1); err != nil {
+		return err
+	}
+	buf2 := b.bufAllocator.get(types.ETDatetime, n)
+	defer b.bufAllocator.put(types.ETDatetime, buf2)
+	if err := b.args[1].VecEvalTime(b.ctx, input, buf2); err != nil {
+		return err
+	}
+	result.ResizeInt64(n, false)
+	result.MergeNulls(buf1, buf2)
+	t1s, t2s, i64s := buf1.Times(), buf2.Times(), result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		res, isNull, err := b.dateDiff(t1s[i], t2s[i])
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.SetNull(i, true)
+			continue
+		}
+		i64s[i] = res
+	}
+	return nil
+}
+
+func (b *builtinUnixTimestampCurrentSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUnixTimestampCurrentSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	result.ResizeInt64(n, false)
+	now := b.ctx.GetSessionVars().StmtCtx.GetNowTsCached().Unix()
+	i64s := result.Int64s()
+	for i := range i64s {
+		i64s[i] = now
+	}
+	return nil
+}
+
+func (b *builtinUnixTimestampIntSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUnixTimestampIntSig) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf := b.bufAllocator.get(types.ETDatetime, n)
+	defer b.bufAllocator.put(types.ETDatetime, buf)
+	if err := b.args[0].VecEvalTime(b.ctx, input, buf); err != nil {
+		return err
+	}
+	result.ResizeInt64(n, false)
+	result.MergeNulls(buf)
+	times, i64s := buf.Times(), result.Int64s()
+	for i := 0; i < n; i++ {
+		if result.IsNull(i) {
+			continue
+		}
+		i64s[i], _, _ = unixTimestamp(times[i])
+	}
+	return nil
+}
+
+func (b *builtinUnixTimestampDecSig) vectorized() bool {
+	return true
+}
+
+func (b *builtinUnixTimestampDecSig) vecEvalDecimal(input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	buf := b.bufAllocator.get(types.ETDatetime, n)
+	defer b.bufAllocator.put(types.ETDatetime, buf)
+	if err := b.args[0].VecEvalTime(b.ctx, input, buf); err != nil {
+		return err
+	}
+	times := buf.Times()
+	result.ReserveDecimal(n)
+	for i := 0; i < n; i++ {
+		if buf.IsNull(i) {
+			result.AppendNull()
+			continue
+		}
+		res, err := b.unixTimestampDecimal(times[i])
+		if err != nil {
+			return err
+		}
+		result.AppendMyDecimal(res)
+	}
+	return nil
+}
@@ -0,0 +1,313 @@
+package expression
+
+import (
+	"sync"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/sessionctx"
+	"grant-db/types"
+	"grant-db/util/chunk"
+)
+
+// columnBufferAllocator allocates and recycles the column buffers used by the vectorized evaluation.
+type columnBufferAllocator interface {
+	// get allocates a column buffer for the values of the evaluation type.
+	get(evalType types.EvalType, capacity int) *chunk.Column
+	// put releases the column buffer allocated for the evaluation type.
+	put(evalType types.EvalType, buf *chunk.Column)
+}
+
+// localSliceBuffer keeps the released column buffers of every evaluation type for reusing, the
+// buffers of different types are kept apart since a fixed-length column can't hold var-length values.
+type localSliceBuffer struct {
+	sync.Mutex
+	buffers map[types.EvalType][]*chunk.Column
+}
+
+func newLocalSliceBuffer() *localSliceBuffer {
+	return &localSliceBuffer{buffers: make(map[types.EvalType][]*chunk.Column)}
+}
+
+// globalColumnAllocator is the allocator of the buffers used outside the built-in functions.
+var globalColumnAllocator = newLocalSliceBuffer()
+
+func bufferType(evalType types.EvalType) types.EvalType {
+	if evalType == types.ETTimestamp {
+		return types.ETDatetime
+	}
+	return evalType
+}
+
+func newBuffer(evalType types.EvalType, capacity int) *chunk.Column {
+	switch evalType {
+	case types.ETInt:
+		return chunk.NewColumn(types.NewFieldType(mysql.TypeLonglong), capacity)
+	case types.ETReal:
+		return chunk.NewColumn(types.NewFieldType(mysql.TypeDouble), capacity)
+	case types.ETDatetime, types.ETTimestamp:
+		return chunk.NewColumn(types.NewFieldType(mysql.TypeDatetime), capacity)
+	case types.ETDecimal:
+		return chunk.NewColumn(types.NewFieldType(mysql.TypeNewDecimal), capacity)
+	case types.ETJson:
+		return chunk.NewColumn(types.NewFieldType(mysql.TypeJSON), capacity)
+	default:
+		return chunk.NewColumn(types.NewFieldType(mysql.TypeVarString), capacity)
+	}
+}
+
+func (r *localSliceBuffer) get(evalType types.EvalType, capacity int) *chunk.Column {
+	evalType = bufferType(evalType)
+	r.Lock()
+	if bufs := r.buffers[evalType]; len(bufs) > 0 {
+		buf := bufs[len(bufs)-1]
+		r.buffers[evalType] = bufs[:len(bufs)-1]
+		r.Unlock()
+		return buf
+	}
+	r.Unlock()
+	return newBuffer(evalType, capacity)
+}
+
+func (r *localSliceBuffer) put(evalType types.EvalType, buf *chunk.Column) {
+	evalType = bufferType(evalType)
+	r.Lock()
+	r.buffers[evalType] = append(r.buffers[evalType], buf)
+	r.Unlock()
+}
+
+func (b *baseBuiltinFunc) vectorized() bool {
+	return false
+}
+
+func (b *baseBuiltinFunc) vecEvalInt(input *chunk.Chunk, result *chunk.Column) error {
+	return errors.Errorf("baseBuiltinFunc.vecEvalInt() should never be called")
+}
+
+func (b *baseBuiltinFunc) vecEvalReal(input *chunk.Chunk, result *chunk.Column) error {
+	return errors.Errorf("baseBuiltinFunc.vecEvalReal() should never be called")
+}
+
+func (b *baseBuiltinFunc) vecEvalString(input *chunk.Chunk, result *chunk.Column) error {
+	return errors.Errorf("baseBuiltinFunc.vecEvalString() should never be called")
+}
+
+func (b *baseBuiltinFunc) vecEvalDecimal(input *chunk.Chunk, result *chunk.Column) error {
+	return errors.Errorf("baseBuiltinFunc.vecEvalDecimal() should never be called")
+}
+
+func (b *baseBuiltinFunc) vecEvalTime(input *chunk.Chunk, result *chunk.Column) error {
+	return errors.Errorf("baseBuiltinFunc.vecEvalTime() should never be called")
+}
+
+func (b *baseBuiltinFunc) vecEvalJSON(input *chunk.Chunk, result *chunk.Column) error {
+	return errors.Errorf("baseBuiltinFunc.vecEvalJSON() should never be called")
+}
+
+// vecEval evaluates expr by vectors according to its evaluation type, result must be a buffer of the type.
+func vecEval(ctx sessionctx.Context, expr Expression, input *chunk.Chunk, result *chunk.Column) error {
+	switch expr.GetType().EvalType() {
+	case types.ETInt:
+		return expr.VecEvalInt(ctx, input, result)
+	case types.ETReal:
+		return expr.VecEvalReal(ctx, input, result)
+	case types.ETDecimal:
+		return expr.VecEvalDecimal(ctx, input, result)
+	case types.ETDatetime, types.ETTimestamp:
+		return expr.VecEvalTime(ctx, input, result)
+	case types.ETJson:
+		return expr.VecEvalJSON(ctx, input, result)
+	default:
+		return expr.VecEvalString(ctx, input, result)
+	}
+}
+
+// vecEvalBool evaluates the truth values of expr by vectors, result is resized to hold 1 or 0 for the rows.
+func vecEvalBool(ctx sessionctx.Context, expr Expression, input *chunk.Chunk, result *chunk.Column) error {
+	evalType := expr.GetType().EvalType()
+	if evalType == types.ETInt {
+		if err := expr.VecEvalInt(ctx, input, result); err != nil {
+			return err
+		}
+		i64s := result.Int64s()
+		for i := range i64s {
+			if i64s[i] != 0 {
+				i64s[i] = 1
+			}
+		}
+		return nil
+	}
+	n := input.NumRows()
+	buf := globalColumnAllocator.get(evalType, n)
+	defer globalColumnAllocator.put(evalType, buf)
+	if err := vecEval(ctx, expr, input, buf); err != nil {
+		return err
+	}
+	result.ResizeInt64(n, false)
+	result.MergeNulls(buf)
+	i64s := result.Int64s()
+	switch evalType {
+	case types.ETReal:
+		f64s := buf.Float64s()
+		for i := 0; i < n; i++ {
+			if f64s[i] != 0 {
+				i64s[i] = 1
+			} else {
+				i64s[i] = 0
+			}
+		}
+	case types.ETDecimal:
+		for i := 0; i < n; i++ {
+			i64s[i] = 0
+			if !result.IsNull(i) && !buf.GetDecimal(i).IsZero() {
+				i64s[i] = 1
+			}
+		}
+	case types.ETDatetime, types.ETTimestamp:
+		times := buf.Times()
+		for i := 0; i < n; i++ {
+			i64s[i] = 0
+			if !times[i].IsZero() {
+				i64s[i] = 1
+			}
+		}
+	case types.ETJson:
+		sc := ctx.GetSessionVars().StmtCtx
+		for i := 0; i < n; i++ {
+			i64s[i] = 0
+			if result.IsNull(i) {
+				continue
+			}
+			f, err := types.ConvertJSONToFloat(sc, buf.GetJSON(i))
+			if err != nil {
+				return err
+			}
+			if f != 0 {
+				i64s[i] = 1
+			}
+		}
+	default:
+		sc := ctx.GetSessionVars().StmtCtx
+		for i := 0; i < n; i++ {
+			i64s[i] = 0
+			if result.IsNull(i) {
+				continue
+			}
+			f, err := types.StrToFloat(sc, buf.GetString(i))
+			if err != nil {
+				return err
+			}
+			if f != 0 {
+				i64s[i] = 1
+			}
+		}
+	}
+	return nil
+}
+
+// vecEvalIntByRows evaluates expr row by row into result, it's the fallback of the functions
+// without a vectorized version.
+func vecEvalIntByRows(ctx sessionctx.Context, expr Expression, input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	result.ResizeInt64(n, false)
+	i64s := result.Int64s()
+	for i := 0; i < n; i++ {
+		res, isNull, err := expr.EvalInt(ctx, input.GetRow(i))
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.SetNull(i, true)
+			continue
+		}
+		i64s[i] = res
+	}
+	return nil
+}
+
+func vecEvalRealByRows(ctx sessionctx.Context, expr Expression, input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	result.ResizeFloat64(n, false)
+	f64s := result.Float64s()
+	for i := 0; i < n; i++ {
+		res, isNull, err := expr.EvalReal(ctx, input.GetRow(i))
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.SetNull(i, true)
+			continue
+		}
+		f64s[i] = res
+	}
+	return nil
+}
+
+func vecEvalDecimalByRows(ctx sessionctx.Context, expr Expression, input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	result.ReserveDecimal(n)
+	for i := 0; i < n; i++ {
+		res, isNull, err := expr.EvalDecimal(ctx, input.GetRow(i))
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		result.AppendMyDecimal(res)
+	}
+	return nil
+}
+
+func vecEvalStringByRows(ctx sessionctx.Context, expr Expression, input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		res, isNull, err := expr.EvalString(ctx, input.GetRow(i))
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		result.AppendString(res)
+	}
+	return nil
+}
+
+func vecEvalTimeByRows(ctx sessionctx.Context, expr Expression, input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	result.ResizeTime(n, false)
+	times := result.Times()
+	for i := 0; i < n; i++ {
+		res, isNull, err := expr.EvalTime(ctx, input.GetRow(i))
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.SetNull(i, true)
+			continue
+		}
+		times[i] = res
+	}
+	return nil
+}
+
+func vecEvalJSONByRows(ctx sessionctx.Context, expr Expression, input *chunk.Chunk, result *chunk.Column) error {
+	n := input.NumRows()
+	result.ReserveJSON(n)
+	for i := 0; i < n; i++ {
+		res, isNull, err := expr.EvalJSON(ctx, input.GetRow(i))
+		if err != nil {
+			return err
+		}
+		if isNull {
+			result.AppendNull()
+			continue
+		}
+		result.AppendJSON(res)
+	}
+	return nil
+}
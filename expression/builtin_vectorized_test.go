@@ -0,0 +1,465 @@
+package expression
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/charset"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/kv"
+	"grant-db/sessionctx"
+	"grant-db/sessionctx/variable"
+	"grant-db/types"
+	"grant-db/util/chunk"
+)
+
+// mockContext is the session context of the expressions evaluated without a store.
+type mockContext struct {
+	values map[fmt.Stringer]interface{}
+	vars   *variable.SessionVars
+}
+
+func newMockContext() sessionctx.Context {
+	vars := variable.NewSessionVars()
+	// The statement is like a SELECT, which warns of the truncations and the divisions by zero.
+	vars.StmtCtx.TruncateAsWarning = true
+	vars.StmtCtx.DividedByZeroAsWarning = true
+	return &mockContext{values: make(map[fmt.Stringer]interface{}), vars: vars}
+}
+
+func (c *mockContext) SetValue(key fmt.Stringer, value interface{}) { c.values[key] = value }
+
+func (c *mockContext) Value(key fmt.Stringer) interface{} { return c.values[key] }
+
+func (c *mockContext) GetSessionVars() *variable.SessionVars { return c.vars }
+
+func (c *mockContext) GetStore() kv.Storage { return nil }
+
+func (c *mockContext) Txn(bool) (kv.Transaction, error) { return nil, nil }
+
+// dataGen generates the value of a row of a column, nil is NULL.
+type dataGen func(r *rand.Rand) interface{}
+
+// vecExprCase is a function whose vectorized evaluation is checked against the row-based one, the arguments
+// are the columns of the input followed by the constants.
+type vecExprCase struct {
+	name     string
+	funcName string
+	retTp    byte
+	cols     []*types.FieldType
+	gens     []dataGen
+	consts   []*Constant
+}
+
+func stringType() *types.FieldType {
+	ft := types.NewFieldType(mysql.TypeVarString)
+	ft.Charset, ft.Collate = charset.CharsetUTF8MB4, charset.CollationUTF8MB4
+	ft.Flen = types.UnspecifiedLength
+	return ft
+}
+
+func intType() *types.FieldType {
+	return types.NewFieldType(mysql.TypeLonglong)
+}
+
+func realType() *types.FieldType {
+	return types.NewFieldType(mysql.TypeDouble)
+}
+
+func decimalType() *types.FieldType {
+	ft := types.NewFieldType(mysql.TypeNewDecimal)
+	ft.Flen, ft.Decimal = 20, 2
+	return ft
+}
+
+func datetimeType(fsp int) *types.FieldType {
+	ft := types.NewFieldType(mysql.TypeDatetime)
+	ft.Decimal = fsp
+	return ft
+}
+
+func stringConst(s string) *Constant {
+	return &Constant{Value: types.NewStringDatum(s), RetType: stringType()}
+}
+
+func intConst(i int64) *Constant {
+	return &Constant{Value: types.NewIntDatum(i), RetType: intType()}
+}
+
+func withNulls(gen dataGen) dataGen {
+	return func(r *rand.Rand) interface{} {
+		if r.Intn(10) == 0 {
+			return nil
+		}
+		return gen(r)
+	}
+}
+
+func genString(chars string, maxLen int) dataGen {
+	runes := []rune(chars)
+	return withNulls(func(r *rand.Rand) interface{} {
+		s := make([]rune, r.Intn(maxLen+1))
+		for i := range s {
+			s[i] = runes[r.Intn(len(runes))]
+		}
+		return string(s)
+	})
+}
+
+func genInt(min, max int64) dataGen {
+	return withNulls(func(r *rand.Rand) interface{} {
+		return min + r.Int63n(max-min+1)
+	})
+}
+
+// genReal generates the reals in [-max, max) with some zeros.
+func genReal(max float64) dataGen {
+	return withNulls(func(r *rand.Rand) interface{} {
+		if r.Intn(10) == 0 {
+			return 0.0
+		}
+		return (r.Float64()*2 - 1) * max
+	})
+}
+
+// genDecimal generates the decimals of 2 fraction digits with some zeros.
+func genDecimal() dataGen {
+	return withNulls(func(r *rand.Rand) interface{} {
+		d := new(types.MyDecimal)
+		if r.Intn(10) == 0 {
+			return d
+		}
+		if err := d.FromString([]byte(fmt.Sprintf("%d.%02d", r.Int63n(2000000)-1000000, r.Intn(100)))); err != nil {
+			panic(err)
+		}
+		return d
+	})
+}
+
+func randDatetime(r *rand.Rand, fsp int8) types.Time {
+	micro := 0
+	if fsp > 0 {
+		micro = r.Intn(1000000)
+	}
+	return types.NewTime(1960+r.Intn(90), 1+r.Intn(12), 1+r.Intn(28), r.Intn(24), r.Intn(60), r.Intn(60),
+		micro, mysql.TypeDatetime, fsp)
+}
+
+// genDatetime generates the datetimes with some zero dates, which are invalid for the date functions.
+func genDatetime(fsp int8) dataGen {
+	return withNulls(func(r *rand.Rand) interface{} {
+		if r.Intn(20) == 0 {
+			return types.NewTime(0, 0, 0, 0, 0, 0, 0, mysql.TypeDatetime, fsp)
+		}
+		return randDatetime(r, fsp)
+	})
+}
+
+// genDateString generates the dates and datetimes as strings with some malformed ones.
+func genDateString() dataGen {
+	return withNulls(func(r *rand.Rand) interface{} {
+		t := randDatetime(r, 0)
+		switch r.Intn(4) {
+		case 0:
+			return "not a date"
+		case 1:
+			return fmt.Sprintf("%04d-%02d-%02d", t.Year(), t.Month(), t.Day())
+		}
+		return t.String()
+	})
+}
+
+var vecExprCases = []vecExprCase{
+	{name: "plus_int", funcName: ast.Plus, retTp: mysql.TypeLonglong,
+		cols: []*types.FieldType{intType(), intType()},
+		gens: []dataGen{genInt(-1000, 1000), genInt(-1000, 1000)}},
+	{name: "mul_real", funcName: ast.Mul, retTp: mysql.TypeDouble,
+		cols: []*types.FieldType{realType(), realType()},
+		gens: []dataGen{genReal(1000), genReal(1000)}},
+	{name: "div_decimal", funcName: ast.Div, retTp: mysql.TypeNewDecimal,
+		cols: []*types.FieldType{decimalType(), decimalType()},
+		gens: []dataGen{genDecimal(), genDecimal()}},
+	{name: "intdiv_int", funcName: ast.IntDiv, retTp: mysql.TypeLonglong,
+		cols: []*types.FieldType{intType(), intType()},
+		gens: []dataGen{genInt(-1000, 1000), genInt(-3, 3)}},
+	{name: "mod_int", funcName: ast.Mod, retTp: mysql.TypeLonglong,
+		cols: []*types.FieldType{intType(), intType()},
+		gens: []dataGen{genInt(-1000, 1000), genInt(-3, 3)}},
+	{name: "lt_int", funcName: ast.LT, retTp: mysql.TypeLonglong,
+		cols: []*types.FieldType{intType(), intType()},
+		gens: []dataGen{genInt(-3, 3), genInt(-3, 3)}},
+	{name: "eq_string", funcName: ast.EQ, retTp: mysql.TypeLonglong,
+		cols: []*types.FieldType{stringType(), stringType()},
+		gens: []dataGen{genString("ab", 2), genString("ab", 2)}},
+	{name: "in_int", funcName: ast.In, retTp: mysql.TypeLonglong,
+		cols:   []*types.FieldType{intType(), intType()},
+		gens:   []dataGen{genInt(-3, 3), genInt(-3, 3)},
+		consts: []*Constant{intConst(1), intConst(2)}},
+	{name: "like", funcName: ast.Like, retTp: mysql.TypeLonglong,
+		cols:   []*types.FieldType{stringType(), stringType()},
+		gens:   []dataGen{genString("ab%_\\", 4), genString("ab%_\\", 3)},
+		consts: []*Constant{intConst('\\')}},
+	{name: "and", funcName: ast.LogicAnd, retTp: mysql.TypeLonglong,
+		cols: []*types.FieldType{intType(), intType()},
+		gens: []dataGen{genInt(0, 1), genInt(0, 1)}},
+	{name: "xor", funcName: ast.LogicXor, retTp: mysql.TypeLonglong,
+		cols: []*types.FieldType{intType(), intType()},
+		gens: []dataGen{genInt(0, 1), genInt(0, 1)}},
+	{name: "left_shift", funcName: ast.LeftShift, retTp: mysql.TypeLonglong,
+		cols: []*types.FieldType{intType(), intType()},
+		gens: []dataGen{genInt(-1000, 1000), genInt(0, 70)}},
+	{name: "is_null", funcName: ast.IsNull, retTp: mysql.TypeLonglong,
+		cols: []*types.FieldType{realType()},
+		gens: []dataGen{genReal(10)}},
+	{name: "unary_minus_decimal", funcName: ast.UnaryMinus, retTp: mysql.TypeNewDecimal,
+		cols: []*types.FieldType{decimalType()},
+		gens: []dataGen{genDecimal()}},
+	{name: "substring2", funcName: ast.Substring, retTp: mysql.TypeVarString,
+		cols: []*types.FieldType{stringType(), intType()},
+		gens: []dataGen{genString("abc中文", 10), genInt(-12, 12)}},
+	{name: "substring3", funcName: ast.Substring, retTp: mysql.TypeVarString,
+		cols: []*types.FieldType{stringType(), intType(), intType()},
+		gens: []dataGen{genString("abc中文", 10), genInt(-12, 12), genInt(-2, 12)}},
+	{name: "replace", funcName: ast.Replace, retTp: mysql.TypeVarString,
+		cols: []*types.FieldType{stringType(), stringType(), stringType()},
+		gens: []dataGen{genString("ab中", 12), genString("ab", 2), genString("xy", 3)}},
+	{name: "lpad", funcName: ast.Lpad, retTp: mysql.TypeVarString,
+		cols: []*types.FieldType{stringType(), intType(), stringType()},
+		gens: []dataGen{genString("abc中文", 8), genInt(-2, 16), genString("xy文", 3)}},
+	{name: "date_format", funcName: ast.DateFormat, retTp: mysql.TypeVarString,
+		cols:   []*types.FieldType{datetimeType(0)},
+		gens:   []dataGen{genDatetime(0)},
+		consts: []*Constant{stringConst("%Y-%m-%d %H:%i:%s %W %j")}},
+	{name: "date_add_datetime", funcName: ast.DateAdd, retTp: mysql.TypeDatetime,
+		cols:   []*types.FieldType{datetimeType(0), stringType()},
+		gens:   []dataGen{genDatetime(0), genString("0123456789-", 4)},
+		consts: []*Constant{stringConst("DAY")}},
+	{name: "date_add_string", funcName: ast.DateAdd, retTp: mysql.TypeVarString,
+		cols:   []*types.FieldType{stringType(), stringType()},
+		gens:   []dataGen{genDateString(), genString("0123456789", 3)},
+		consts: []*Constant{stringConst("HOUR")}},
+	{name: "datediff", funcName: ast.DateDiff, retTp: mysql.TypeLonglong,
+		cols: []*types.FieldType{datetimeType(0), datetimeType(0)},
+		gens: []dataGen{genDatetime(0), genDatetime(0)}},
+	{name: "unix_timestamp_int", funcName: ast.UnixTimestamp, retTp: mysql.TypeLonglong,
+		cols: []*types.FieldType{datetimeType(0)},
+		gens: []dataGen{genDatetime(0)}},
+	{name: "unix_timestamp_dec", funcName: ast.UnixTimestamp, retTp: mysql.TypeNewDecimal,
+		cols: []*types.FieldType{datetimeType(3)},
+		gens: []dataGen{genDatetime(3)}},
+}
+
+// build returns the function of the case and an input of n random rows.
+func (c *vecExprCase) build(ctx sessionctx.Context, r *rand.Rand, n int) (Expression, *chunk.Chunk, error) {
+	args := make([]Expression, 0, len(c.cols)+len(c.consts))
+	for i, ft := range c.cols {
+		args = append(args, &Column{RetType: ft, Index: i, UniqueID: int64(i + 1)})
+	}
+	for _, con := range c.consts {
+		args = append(args, con)
+	}
+	expr, err := NewFunctionBase(ctx, c.funcName, types.NewFieldType(c.retTp), args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	input := chunk.NewChunkWithCapacity(c.cols, n)
+	for i := 0; i < n; i++ {
+		for j, gen := range c.gens {
+			d := types.NewDatum(gen(r))
+			input.AppendDatum(j, &d)
+		}
+	}
+	return expr, input, nil
+}
+
+// evalByRows evaluates expr row by row and formats the results, the error is formatted as the result of the row.
+func evalByRows(ctx sessionctx.Context, expr Expression, input *chunk.Chunk) []string {
+	res := make([]string, input.NumRows())
+	for i := range res {
+		row := input.GetRow(i)
+		var (
+			val    interface{}
+			isNull bool
+			err    error
+		)
+		switch expr.GetType().EvalType() {
+		case types.ETInt:
+			val, isNull, err = expr.EvalInt(ctx, row)
+		case types.ETReal:
+			val, isNull, err = expr.EvalReal(ctx, row)
+		case types.ETDecimal:
+			val, isNull, err = expr.EvalDecimal(ctx, row)
+		case types.ETDatetime, types.ETTimestamp:
+			val, isNull, err = expr.EvalTime(ctx, row)
+		default:
+			val, isNull, err = expr.EvalString(ctx, row)
+		}
+		switch {
+		case err != nil:
+			res[i] = "error: " + err.Error()
+		case isNull:
+			res[i] = "<nil>"
+		default:
+			res[i] = fmt.Sprint(val)
+		}
+	}
+	return res
+}
+
+// evalByVec evaluates expr by vectors and formats the results.
+func evalByVec(ctx sessionctx.Context, expr Expression, input *chunk.Chunk) ([]string, error) {
+	evalType := expr.GetType().EvalType()
+	buf := newBuffer(evalType, input.NumRows())
+	if err := vecEval(ctx, expr, input, buf); err != nil {
+		return nil, err
+	}
+	res := make([]string, input.NumRows())
+	for i := range res {
+		if buf.IsNull(i) {
+			res[i] = "<nil>"
+			continue
+		}
+		switch evalType {
+		case types.ETInt:
+			res[i] = fmt.Sprint(buf.GetInt64(i))
+		case types.ETReal:
+			res[i] = fmt.Sprint(buf.GetFloat64(i))
+		case types.ETDecimal:
+			res[i] = fmt.Sprint(buf.GetDecimal(i))
+		case types.ETDatetime, types.ETTimestamp:
+			res[i] = fmt.Sprint(buf.GetTime(i))
+		default:
+			res[i] = buf.GetString(i)
+		}
+	}
+	return res, nil
+}
+
+// randSel selects about half of the n rows in order.
+func randSel(r *rand.Rand, n int) []int {
+	sel := make([]int, 0, n)
+	for i := 0; i < n; i++ {
+		if r.Intn(2) == 0 {
+			sel = append(sel, i)
+		}
+	}
+	return sel
+}
+
+func TestVectorizedBuiltinFunc(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for _, c := range vecExprCases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			ctx := newMockContext()
+			sc := ctx.GetSessionVars().StmtCtx
+			expr, input, err := c.build(ctx, r, 1024)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !expr.Vectorized() {
+				t.Fatalf("%s isn't vectorized", expr)
+			}
+			for _, sel := range [][]int{nil, randSel(r, input.NumRows()), {}} {
+				input.SetSel(sel)
+				sc.SetWarnings(nil)
+				expected := evalByRows(ctx, expr, input)
+				rowWarnings := sc.WarningCount()
+				sc.SetWarnings(nil)
+				got, err := evalByVec(ctx, expr, input)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(got) != len(expected) {
+					t.Fatalf("got %d rows, expected %d", len(got), len(expected))
+				}
+				for i := range expected {
+					if got[i] != expected[i] {
+						t.Fatalf("row %d of %v: got %q, expected %q", i, input.GetRow(i).GetDatumRow(c.cols), got[i], expected[i])
+					}
+				}
+				if vecWarnings := sc.WarningCount(); vecWarnings != rowWarnings {
+					t.Fatalf("got %d warnings, expected %d", vecWarnings, rowWarnings)
+				}
+			}
+		})
+	}
+}
+
+func TestVectorizedUnixTimestampCurrent(t *testing.T) {
+	ctx := newMockContext()
+	expr, err := NewFunctionBase(ctx, ast.UnixTimestamp, types.NewFieldType(mysql.TypeLonglong))
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := chunk.NewChunkWithCapacity([]*types.FieldType{intType()}, 8)
+	for i := 0; i < 8; i++ {
+		input.AppendInt64(0, int64(i))
+	}
+	input.SetSel([]int{1, 4, 6})
+	buf := newBuffer(types.ETInt, input.NumRows())
+	if err := expr.VecEvalInt(ctx, input, buf); err != nil {
+		t.Fatal(err)
+	}
+	now := ctx.GetSessionVars().StmtCtx.GetNowTsCached().Unix()
+	i64s := buf.Int64s()
+	if len(i64s) != 3 {
+		t.Fatalf("got %d rows, expected 3", len(i64s))
+	}
+	for i := range i64s {
+		if buf.IsNull(i) || i64s[i] != now {
+			t.Fatalf("row %d: got %d, expected %d", i, i64s[i], now)
+		}
+	}
+}
+
+func BenchmarkVectorizedBuiltinFunc(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	for _, c := range vecExprCases {
+		ctx := newMockContext()
+		expr, input, err := c.build(ctx, r, 1024)
+		if err != nil {
+			b.Fatal(err)
+		}
+		sc := ctx.GetSessionVars().StmtCtx
+		evalType := expr.GetType().EvalType()
+		b.Run(c.name+"/vec", func(b *testing.B) {
+			buf := newBuffer(evalType, input.NumRows())
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				sc.SetWarnings(nil)
+				if err := vecEval(ctx, expr, input, buf); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(c.name+"/row", func(b *testing.B) {
+			buf := newBuffer(evalType, input.NumRows())
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				sc.SetWarnings(nil)
+				if err := evalRows(ctx, expr, evalType, input, buf); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// evalRows evaluates expr by the row-based fallbacks of the functions without a vectorized version.
+func evalRows(ctx sessionctx.Context, expr Expression, evalType types.EvalType, input *chunk.Chunk, result *chunk.Column) error {
+	switch evalType {
+	case types.ETInt:
+		return vecEvalIntByRows(ctx, expr, input, result)
+	case types.ETReal:
+		return vecEvalRealByRows(ctx, expr, input, result)
+	case types.ETDecimal:
+		return vecEvalDecimalByRows(ctx, expr, input, result)
+	case types.ETDatetime, types.ETTimestamp:
+		return vecEvalTimeByRows(ctx, expr, input, result)
+	default:
+		return vecEvalStringByRows(ctx, expr, input, result)
+	}
+}
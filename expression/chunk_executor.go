@@ -0,0 +1,124 @@
+package expression
+
+import (
+	"github.com/pingcap/parser/mysql"
+	"grant-db/sessionctx"
+	"grant-db/types"
+	"grant-db/util/chunk"
+)
+
+// Vectorizable checks whether a list of expressions can employ vectorized execution.
+func Vectorizable(exprs []Expression) bool {
+	for _, expr := range exprs {
+		if !expr.Vectorized() {
+			return false
+		}
+	}
+	return true
+}
+
+// VectorizedExecute evaluates a list of expressions column by column and writes their results to the
+// columns of "output" Chunk, the columns are overwritten rather than appended. An expression is evaluated
+// by vectors if it's vectorized, or row by row otherwise, the selected rows of "input" are evaluated only.
+func VectorizedExecute(ctx sessionctx.Context, exprs []Expression, input, output *chunk.Chunk) error {
+	for colIdx, expr := range exprs {
+		if err := evalOneColumn(ctx, expr, input, output, colIdx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func evalOneColumn(ctx sessionctx.Context, expr Expression, input, output *chunk.Chunk, colIdx int) error {
+	if expr.Vectorized() && ctx.GetSessionVars().EnableVectorizedExpression {
+		return evalOneVec(ctx, expr, input, output, colIdx)
+	}
+	output.Column(colIdx).Reset()
+	for i := 0; i < input.NumRows(); i++ {
+		if err := evalOneCell(ctx, expr, input.GetRow(i), output, colIdx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// evalOneVec evaluates expr by vectors into the colIdx column of output.
+func evalOneVec(ctx sessionctx.Context, expr Expression, input, output *chunk.Chunk, colIdx int) error {
+	ft := expr.GetType()
+	result := output.Column(colIdx)
+	switch ft.EvalType() {
+	case types.ETInt:
+		return expr.VecEvalInt(ctx, input, result)
+	case types.ETReal:
+		if ft.Tp != mysql.TypeFloat {
+			return expr.VecEvalReal(ctx, input, result)
+		}
+		// A FLOAT column holds float32 values.
+		n := input.NumRows()
+		buf := globalColumnAllocator.get(types.ETReal, n)
+		defer globalColumnAllocator.put(types.ETReal, buf)
+		if err := expr.VecEvalReal(ctx, input, buf); err != nil {
+			return err
+		}
+		result.ResizeFloat32(n, false)
+		result.MergeNulls(buf)
+		f32s, f64s := result.Float32s(), buf.Float64s()
+		for i := range f32s {
+			f32s[i] = float32(f64s[i])
+		}
+		return nil
+	case types.ETDecimal:
+		return expr.VecEvalDecimal(ctx, input, result)
+	case types.ETDatetime, types.ETTimestamp:
+		return expr.VecEvalTime(ctx, input, result)
+	case types.ETJson:
+		return expr.VecEvalJSON(ctx, input, result)
+	default:
+		return expr.VecEvalString(ctx, input, result)
+	}
+}
+
+// VectorizedFilter applies a list of filters to a Chunk and returns a bool slice, which indicates
+// whether a selected row of the Chunk passes the filters. A row is filtered out if any filter is
+// false or NULL on it.
+func VectorizedFilter(ctx sessionctx.Context, filters CNFExprs, input *chunk.Chunk, selected []bool) ([]bool, error) {
+	n := input.NumRows()
+	selected = selected[:0]
+	for i := 0; i < n; i++ {
+		selected = append(selected, true)
+	}
+	for _, filter := range filters {
+		if filter.Vectorized() && ctx.GetSessionVars().EnableVectorizedExpression {
+			if err := vecFilter(ctx, filter, input, selected); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		for i := 0; i < n; i++ {
+			if !selected[i] {
+				continue
+			}
+			val, isNull, err := evalBool(ctx, filter, input.GetRow(i))
+			if err != nil {
+				return nil, err
+			}
+			selected[i] = val && !isNull
+		}
+	}
+	return selected, nil
+}
+
+func vecFilter(ctx sessionctx.Context, filter Expression, input *chunk.Chunk, selected []bool) error {
+	buf := globalColumnAllocator.get(types.ETInt, input.NumRows())
+	defer globalColumnAllocator.put(types.ETInt, buf)
+	if err := vecEvalBool(ctx, filter, input, buf); err != nil {
+		return err
+	}
+	i64s := buf.Int64s()
+	for i := range selected {
+		if selected[i] && (buf.IsNull(i) || i64s[i] == 0) {
+			selected[i] = false
+		}
+	}
+	return nil
+}
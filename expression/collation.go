@@ -0,0 +1,101 @@
+package expression
+
+import (
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/charset"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/sessionctx"
+	"grant-db/types"
+)
+
+// Coercibility is the coercibility of the collation of an expression, the collation of the lowest coercibility
+// is used when the arguments of a function have different collations.
+// See https://dev.mysql.com/doc/refman/5.7/en/charset-collation-coercibility.html.
+type Coercibility int
+
+const (
+	// CoercibilityExplicit is the coercibility of an expression with a COLLATE clause.
+	CoercibilityExplicit Coercibility = 0
+	// CoercibilityNone is the coercibility of the concatenation of strings of different collations.
+	CoercibilityNone Coercibility = 1
+	// CoercibilityImplicit is the coercibility of a column.
+	CoercibilityImplicit Coercibility = 2
+	// CoercibilitySysconst is the coercibility of a system constant, like USER().
+	CoercibilitySysconst Coercibility = 3
+	// CoercibilityCoercible is the coercibility of a literal.
+	CoercibilityCoercible Coercibility = 4
+	// CoercibilityNumeric is the coercibility of a numeric or temporal value.
+	CoercibilityNumeric Coercibility = 5
+	// CoercibilityIgnorable is the coercibility of NULL.
+	CoercibilityIgnorable Coercibility = 6
+)
+
+// collationInfo keeps the coercibility of an expression, which is derived when it's asked for the first time.
+type collationInfo struct {
+	coer     Coercibility
+	coerInit bool
+}
+
+// SetCoercibility sets the coercibility of the expression.
+func (c *collationInfo) SetCoercibility(coer Coercibility) {
+	c.coer, c.coerInit = coer, true
+}
+
+func deriveCoercibilityForColumn(col *Column) Coercibility {
+	if col.RetType.EvalType() != types.ETString {
+		return CoercibilityNumeric
+	}
+	return CoercibilityImplicit
+}
+
+func deriveCoercibilityForConstant(c *Constant) Coercibility {
+	switch {
+	case c.Value.IsNull():
+		return CoercibilityIgnorable
+	case c.RetType.EvalType() != types.ETString:
+		return CoercibilityNumeric
+	}
+	return CoercibilityCoercible
+}
+
+// deriveCoercibilityForScalarFunc derives the coercibility of a function from its arguments, a CAST
+// is like a column.
+func deriveCoercibilityForScalarFunc(sf *ScalarFunction) Coercibility {
+	if sf.RetType.EvalType() != types.ETString {
+		return CoercibilityNumeric
+	}
+	if sf.FuncName.L == ast.Cast {
+		return CoercibilityImplicit
+	}
+	coer := CoercibilityCoercible
+	for _, arg := range sf.GetArgs() {
+		if argCoer := arg.Coercibility(); argCoer < coer {
+			coer = argCoer
+		}
+	}
+	return coer
+}
+
+// DeriveCollationFromExprs derives the charset and the collation of the string arguments of a function,
+// which are the ones of the argument of the lowest coercibility, and the binary one wins the tie.
+// The charset and the collation of the connection are used if there is no string argument.
+func DeriveCollationFromExprs(ctx sessionctx.Context, exprs ...Expression) (dstCharset, dstCollation string) {
+	curCoer := CoercibilityNumeric
+	for _, e := range exprs {
+		coer := e.Coercibility()
+		if coer >= CoercibilityNumeric {
+			continue
+		}
+		tp := e.GetType()
+		if coer < curCoer || (coer == curCoer && tp.Collate == charset.CollationBin) {
+			curCoer, dstCharset, dstCollation = coer, tp.Charset, tp.Collate
+		}
+	}
+	if dstCollation == "" {
+		dstCharset, dstCollation = ctx.GetSessionVars().GetCharsetInfo()
+	}
+	if dstCollation == "" {
+		dstCharset, dstCollation = mysql.DefaultCharset, mysql.DefaultCollationName
+	}
+	return dstCharset, dstCollation
+}
@@ -0,0 +1,273 @@
+package expression
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/sessionctx"
+	"grant-db/types"
+	"grant-db/types/json"
+	"grant-db/util/chunk"
+)
+
+// Column represents a column.
+type Column struct {
+	collationInfo
+
+	RetType *types.FieldType
+	// ID is used to specify whether this column is ExtraHandleColumn or to access histogram.
+	// We'll try to remove it in the future.
+	ID int64
+	// UniqueID is the unique id of this column.
+	UniqueID int64
+
+	// Index is used for execution, to tell the column's position in the given row.
+	Index int
+
+	// OrigName is the name of the column for explaining, like "test.t.a".
+	OrigName string
+}
+
+// Equal implements Expression interface.
+func (col *Column) Equal(_ sessionctx.Context, expr Expression) bool {
+	if newCol, ok := expr.(*Column); ok {
+		return newCol.UniqueID == col.UniqueID
+	}
+	return false
+}
+
+// String implements Stringer interface.
+func (col *Column) String() string {
+	if col.OrigName != "" {
+		return col.OrigName
+	}
+	return fmt.Sprintf("Column#%d", col.UniqueID)
+}
+
+// GetType implements Expression interface.
+func (col *Column) GetType() *types.FieldType {
+	return col.RetType
+}
+
+// Eval implements Expression interface.
+func (col *Column) Eval(row chunk.Row) (types.Datum, error) {
+	return row.GetDatum(col.Index, col.RetType), nil
+}
+
+// evalByDatum checks whether the column holds ENUM, SET, BIT, TIME or JSON values, which are kept in their
+// own forms in the chunk and are evaluated through the datum.
+func (col *Column) evalByDatum() bool {
+	return col.RetType.Hybrid() || col.RetType.Tp == mysql.TypeDuration || col.RetType.Tp == mysql.TypeJSON
+}
+
+// EvalInt returns int representation of Column.
+func (col *Column) EvalInt(ctx sessionctx.Context, row chunk.Row) (int64, bool, error) {
+	if row.IsNull(col.Index) {
+		return 0, true, nil
+	}
+	if col.evalByDatum() {
+		d := row.GetDatum(col.Index, col.RetType)
+		val, err := d.ToInt64(ctx.GetSessionVars().StmtCtx)
+		return val, err != nil, err
+	}
+	return row.GetInt64(col.Index), false, nil
+}
+
+// EvalReal returns real representation of Column.
+func (col *Column) EvalReal(ctx sessionctx.Context, row chunk.Row) (float64, bool, error) {
+	if row.IsNull(col.Index) {
+		return 0, true, nil
+	}
+	if col.evalByDatum() {
+		d := row.GetDatum(col.Index, col.RetType)
+		val, err := d.ToFloat64(ctx.GetSessionVars().StmtCtx)
+		return val, err != nil, err
+	}
+	if col.GetType().Tp == mysql.TypeFloat {
+		return float64(row.GetFloat32(col.Index)), false, nil
+	}
+	return row.GetFloat64(col.Index), false, nil
+}
+
+// EvalString returns string representation of Column.
+func (col *Column) EvalString(ctx sessionctx.Context, row chunk.Row) (string, bool, error) {
+	if row.IsNull(col.Index) {
+		return "", true, nil
+	}
+	if col.evalByDatum() {
+		d := row.GetDatum(col.Index, col.RetType)
+		val, err := d.ToString()
+		return val, err != nil, err
+	}
+	return row.GetString(col.Index), false, nil
+}
+
+// EvalDecimal returns decimal representation of Column.
+func (col *Column) EvalDecimal(ctx sessionctx.Context, row chunk.Row) (*types.MyDecimal, bool, error) {
+	if row.IsNull(col.Index) {
+		return nil, true, nil
+	}
+	if col.evalByDatum() {
+		d := row.GetDatum(col.Index, col.RetType)
+		val, err := d.ToDecimal(ctx.GetSessionVars().StmtCtx)
+		return val, err != nil, err
+	}
+	return row.GetMyDecimal(col.Index), false, nil
+}
+
+// EvalTime returns DATE/DATETIME/TIMESTAMP representation of Column.
+func (col *Column) EvalTime(ctx sessionctx.Context, row chunk.Row) (types.Time, bool, error) {
+	if row.IsNull(col.Index) {
+		return types.ZeroDatetime, true, nil
+	}
+	return row.GetTime(col.Index), false, nil
+}
+
+// EvalJSON returns JSON representation of Column.
+func (col *Column) EvalJSON(ctx sessionctx.Context, row chunk.Row) (json.BinaryJSON, bool, error) {
+	if row.IsNull(col.Index) {
+		return json.BinaryJSON{}, true, nil
+	}
+	return row.GetJSON(col.Index), false, nil
+}
+
+// Vectorized returns if this expression supports vectorized evaluation.
+func (col *Column) Vectorized() bool {
+	return !col.evalByDatum()
+}
+
+// VecEvalInt evaluates this expression in a vectorized manner.
+func (col *Column) VecEvalInt(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	input.Column(col.Index).CopyReconstruct(input.Sel(), result)
+	return nil
+}
+
+// VecEvalReal evaluates this expression in a vectorized manner, a FLOAT column is widened to float64.
+func (col *Column) VecEvalReal(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	src := input.Column(col.Index)
+	if col.GetType().Tp != mysql.TypeFloat {
+		src.CopyReconstruct(input.Sel(), result)
+		return nil
+	}
+	n, sel := input.NumRows(), input.Sel()
+	result.ResizeFloat64(n, false)
+	f32s, f64s := src.Float32s(), result.Float64s()
+	for i := 0; i < n; i++ {
+		j := i
+		if sel != nil {
+			j = sel[i]
+		}
+		if src.IsNull(j) {
+			result.SetNull(i, true)
+			continue
+		}
+		f64s[i] = float64(f32s[j])
+	}
+	return nil
+}
+
+// VecEvalString evaluates this expression in a vectorized manner.
+func (col *Column) VecEvalString(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	input.Column(col.Index).CopyReconstruct(input.Sel(), result)
+	return nil
+}
+
+// VecEvalDecimal evaluates this expression in a vectorized manner.
+func (col *Column) VecEvalDecimal(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	input.Column(col.Index).CopyReconstruct(input.Sel(), result)
+	return nil
+}
+
+// VecEvalTime evaluates this expression in a vectorized manner.
+func (col *Column) VecEvalTime(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	input.Column(col.Index).CopyReconstruct(input.Sel(), result)
+	return nil
+}
+
+// VecEvalJSON evaluates this expression in a vectorized manner.
+func (col *Column) VecEvalJSON(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	input.Column(col.Index).CopyReconstruct(input.Sel(), result)
+	return nil
+}
+
+// Clone implements Expression interface.
+func (col *Column) Clone() Expression {
+	newCol := *col
+	return &newCol
+}
+
+// Coercibility implements Expression interface.
+func (col *Column) Coercibility() Coercibility {
+	if !col.coerInit {
+		col.SetCoercibility(deriveCoercibilityForColumn(col))
+	}
+	return col.coer
+}
+
+// ResolveIndices implements Expression interface.
+func (col *Column) ResolveIndices(schema *Schema) (Expression, error) {
+	newCol := col.Clone()
+	err := newCol.resolveIndices(schema)
+	return newCol, err
+}
+
+func (col *Column) resolveIndices(schema *Schema) error {
+	col.Index = schema.ColumnIndex(col)
+	if col.Index == -1 {
+		return ErrUnknownColumn.GenWithStackByArgs(col.String(), "field list")
+	}
+	return nil
+}
+
+// Column2Exprs converts []*Column to []Expression.
+func Column2Exprs(cols []*Column) []Expression {
+	result := make([]Expression, 0, len(cols))
+	for _, col := range cols {
+		result = append(result, col)
+	}
+	return result
+}
+
+// ColInfo2Col finds the corresponding column of the ColumnInfo in a column slice.
+func ColInfo2Col(cols []*Column, col *model.ColumnInfo) *Column {
+	for _, c := range cols {
+		if c.ID == col.ID {
+			return c
+		}
+	}
+	return nil
+}
+
+// ColumnInfos2ColumnsAndNames converts the ColumnInfo to the *Column and NameSlice.
+func ColumnInfos2ColumnsAndNames(ctx sessionctx.Context, dbName, tblName model.CIStr, colInfos []*model.ColumnInfo) ([]*Column, types.NameSlice) {
+	columns := make([]*Column, 0, len(colInfos))
+	names := make([]*types.FieldName, 0, len(colInfos))
+	for i, col := range colInfos {
+		names = append(names, &types.FieldName{
+			OrigTblName: tblName,
+			OrigColName: col.Name,
+			DBName:      dbName,
+			TblName:     tblName,
+			ColName:     col.Name,
+		})
+		newCol := &Column{
+			RetType:  &col.FieldType,
+			ID:       col.ID,
+			UniqueID: ctx.GetSessionVars().AllocPlanColumnID(),
+			Index:    i,
+			OrigName: origColName(dbName, tblName, col.Name),
+		}
+		columns = append(columns, newCol)
+	}
+	return columns, names
+}
+
+// origColName returns the name of the column for explaining, the database name is omitted if it's empty.
+func origColName(dbName, tblName, colName model.CIStr) string {
+	if dbName.L == "" {
+		return strings.Join([]string{tblName.L, colName.L}, ".")
+	}
+	return strings.Join([]string{dbName.L, tblName.L, colName.L}, ".")
+}
@@ -0,0 +1,286 @@
+package expression
+
+import (
+	"fmt"
+
+	"github.com/pingcap/parser/mysql"
+	"grant-db/sessionctx"
+	"grant-db/types"
+	"grant-db/types/json"
+	"grant-db/util/chunk"
+)
+
+// NewOne stands for a number 1.
+func NewOne() *Constant {
+	retT := types.NewFieldType(mysql.TypeTiny)
+	retT.Flag |= mysql.UnsignedFlag // shrink range to avoid integral promotion
+	retT.Flen = 1
+	retT.Decimal = 0
+	return &Constant{
+		Value:   types.NewDatum(1),
+		RetType: retT,
+	}
+}
+
+// NewZero stands for a number 0.
+func NewZero() *Constant {
+	retT := types.NewFieldType(mysql.TypeTiny)
+	retT.Flag |= mysql.UnsignedFlag // shrink range to avoid integral promotion
+	retT.Flen = 1
+	retT.Decimal = 0
+	return &Constant{
+		Value:   types.NewDatum(0),
+		RetType: retT,
+	}
+}
+
+// NewNull stands for null constant.
+func NewNull() *Constant {
+	return &Constant{
+		Value:   types.NewDatum(nil),
+		RetType: types.NewFieldType(mysql.TypeNull),
+	}
+}
+
+// Constant stands for a constant value.
+type Constant struct {
+	collationInfo
+
+	Value   types.Datum
+	RetType *types.FieldType
+}
+
+// String implements fmt.Stringer interface.
+func (c *Constant) String() string {
+	return fmt.Sprintf("%v", c.Value.GetValue())
+}
+
+// Clone implements Expression interface.
+func (c *Constant) Clone() Expression {
+	con := *c
+	return &con
+}
+
+// Coercibility implements Expression interface.
+func (c *Constant) Coercibility() Coercibility {
+	if !c.coerInit {
+		c.SetCoercibility(deriveCoercibilityForConstant(c))
+	}
+	return c.coer
+}
+
+// GetType implements Expression interface.
+func (c *Constant) GetType() *types.FieldType {
+	return c.RetType
+}
+
+// Eval implements Expression interface.
+func (c *Constant) Eval(_ chunk.Row) (types.Datum, error) {
+	return c.Value, nil
+}
+
+// EvalInt returns int representation of Constant.
+func (c *Constant) EvalInt(ctx sessionctx.Context, _ chunk.Row) (int64, bool, error) {
+	if c.Value.IsNull() {
+		return 0, true, nil
+	}
+	val, err := c.Value.ToInt64(ctx.GetSessionVars().StmtCtx)
+	return val, err != nil, err
+}
+
+// EvalReal returns real representation of Constant.
+func (c *Constant) EvalReal(ctx sessionctx.Context, _ chunk.Row) (float64, bool, error) {
+	if c.Value.IsNull() {
+		return 0, true, nil
+	}
+	val, err := c.Value.ToFloat64(ctx.GetSessionVars().StmtCtx)
+	return val, err != nil, err
+}
+
+// EvalString returns string representation of Constant.
+func (c *Constant) EvalString(ctx sessionctx.Context, _ chunk.Row) (string, bool, error) {
+	if c.Value.IsNull() {
+		return "", true, nil
+	}
+	val, err := c.Value.ToString()
+	return val, err != nil, err
+}
+
+// EvalDecimal returns decimal representation of Constant.
+func (c *Constant) EvalDecimal(ctx sessionctx.Context, _ chunk.Row) (*types.MyDecimal, bool, error) {
+	if c.Value.IsNull() {
+		return nil, true, nil
+	}
+	res, err := c.Value.ToDecimal(ctx.GetSessionVars().StmtCtx)
+	return res, err != nil, err
+}
+
+// EvalTime returns DATE/DATETIME/TIMESTAMP representation of Constant.
+func (c *Constant) EvalTime(ctx sessionctx.Context, _ chunk.Row) (val types.Time, isNull bool, err error) {
+	if c.Value.IsNull() {
+		return types.ZeroDatetime, true, nil
+	}
+	if c.Value.Kind() == types.KindMysqlTime {
+		return c.Value.GetMysqlTime(), false, nil
+	}
+	s, err := c.Value.ToString()
+	if err != nil {
+		return types.ZeroDatetime, true, err
+	}
+	val, err = types.ParseTime(s, c.RetType.Tp, int8(c.RetType.Decimal))
+	return val, err != nil, err
+}
+
+// EvalJSON returns JSON representation of Constant.
+func (c *Constant) EvalJSON(ctx sessionctx.Context, _ chunk.Row) (json.BinaryJSON, bool, error) {
+	if c.Value.IsNull() {
+		return json.BinaryJSON{}, true, nil
+	}
+	if c.Value.Kind() == types.KindMysqlJSON {
+		return c.Value.GetMysqlJSON(), false, nil
+	}
+	d, err := c.Value.ConvertTo(ctx.GetSessionVars().StmtCtx, types.NewFieldType(mysql.TypeJSON))
+	if err != nil {
+		return json.BinaryJSON{}, true, err
+	}
+	return d.GetMysqlJSON(), false, nil
+}
+
+// Vectorized returns if this expression supports vectorized evaluation.
+func (c *Constant) Vectorized() bool {
+	return true
+}
+
+// numRows returns the number of rows the constant is evaluated for, a nil input stands for a single row.
+func (c *Constant) numRows(input *chunk.Chunk) int {
+	if input == nil {
+		return 1
+	}
+	return input.NumRows()
+}
+
+// VecEvalInt evaluates this expression in a vectorized manner.
+func (c *Constant) VecEvalInt(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	res, isNull, err := c.EvalInt(ctx, chunk.Row{})
+	if err != nil {
+		return err
+	}
+	result.ResizeInt64(c.numRows(input), isNull)
+	if !isNull {
+		i64s := result.Int64s()
+		for i := range i64s {
+			i64s[i] = res
+		}
+	}
+	return nil
+}
+
+// VecEvalReal evaluates this expression in a vectorized manner.
+func (c *Constant) VecEvalReal(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	res, isNull, err := c.EvalReal(ctx, chunk.Row{})
+	if err != nil {
+		return err
+	}
+	result.ResizeFloat64(c.numRows(input), isNull)
+	if !isNull {
+		f64s := result.Float64s()
+		for i := range f64s {
+			f64s[i] = res
+		}
+	}
+	return nil
+}
+
+// VecEvalString evaluates this expression in a vectorized manner.
+func (c *Constant) VecEvalString(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	res, isNull, err := c.EvalString(ctx, chunk.Row{})
+	if err != nil {
+		return err
+	}
+	n := c.numRows(input)
+	result.ReserveString(n)
+	for i := 0; i < n; i++ {
+		if isNull {
+			result.AppendNull()
+		} else {
+			result.AppendString(res)
+		}
+	}
+	return nil
+}
+
+// VecEvalDecimal evaluates this expression in a vectorized manner.
+func (c *Constant) VecEvalDecimal(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	res, isNull, err := c.EvalDecimal(ctx, chunk.Row{})
+	if err != nil {
+		return err
+	}
+	n := c.numRows(input)
+	result.ReserveDecimal(n)
+	for i := 0; i < n; i++ {
+		if isNull {
+			result.AppendNull()
+		} else {
+			result.AppendMyDecimal(res)
+		}
+	}
+	return nil
+}
+
+// VecEvalTime evaluates this expression in a vectorized manner.
+func (c *Constant) VecEvalTime(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	res, isNull, err := c.EvalTime(ctx, chunk.Row{})
+	if err != nil {
+		return err
+	}
+	result.ResizeTime(c.numRows(input), isNull)
+	if !isNull {
+		times := result.Times()
+		for i := range times {
+			times[i] = res
+		}
+	}
+	return nil
+}
+
+// VecEvalJSON evaluates this expression in a vectorized manner.
+func (c *Constant) VecEvalJSON(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	res, isNull, err := c.EvalJSON(ctx, chunk.Row{})
+	if err != nil {
+		return err
+	}
+	n := c.numRows(input)
+	result.ReserveJSON(n)
+	for i := 0; i < n; i++ {
+		if isNull {
+			result.AppendNull()
+		} else {
+			result.AppendJSON(res)
+		}
+	}
+	return nil
+}
+
+// Equal implements Expression interface.
+func (c *Constant) Equal(ctx sessionctx.Context, b Expression) bool {
+	y, ok := b.(*Constant)
+	if !ok {
+		return false
+	}
+	if c.Value.IsNull() || y.Value.IsNull() {
+		return c.Value.IsNull() && y.Value.IsNull()
+	}
+	if c.Value.Kind() != y.Value.Kind() {
+		return false
+	}
+	return c.Value.String() == y.Value.String()
+}
+
+// ResolveIndices implements Expression interface.
+func (c *Constant) ResolveIndices(_ *Schema) (Expression, error) {
+	return c, nil
+}
+
+func (c *Constant) resolveIndices(_ *Schema) error {
+	return nil
+}
@@ -0,0 +1,39 @@
+package expression
+
+import (
+	"grant-db/util/chunk"
+)
+
+// FoldConstant does constant folding optimization on an expression excluding deferred ones.
+func FoldConstant(expr Expression) Expression {
+	e, _ := foldConstant(expr)
+	return e
+}
+
+// foldConstant folds a scalar function whose arguments are all constants, the second returned
+// value reports whether expr is a constant after folding.
+func foldConstant(expr Expression) (Expression, bool) {
+	switch x := expr.(type) {
+	case *ScalarFunction:
+		args := x.GetArgs()
+		allConstArg := true
+		for i := 0; i < len(args); i++ {
+			foldedArg, isConst := foldConstant(args[i])
+			args[i] = foldedArg
+			allConstArg = allConstArg && isConst
+		}
+		if _, ok := unFoldableFunctions[x.FuncName.L]; ok || !allConstArg {
+			return expr, false
+		}
+		value, err := x.Eval(chunk.Row{})
+		if err != nil {
+			// The error is raised again when the function is evaluated, like the division by zero
+			// in a statement where it is an error.
+			return expr, false
+		}
+		return &Constant{Value: value, RetType: x.RetType}, true
+	case *Constant:
+		return expr, true
+	}
+	return expr, false
+}
@@ -0,0 +1,27 @@
+package expression
+
+import (
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
+)
+
+var (
+	// ErrIncorrectParameterCount is returned when a function is called with a wrong number of arguments.
+	ErrIncorrectParameterCount = terror.ClassExpression.New(mysql.ErrWrongParamcountToNativeFct, mysql.MySQLErrName[mysql.ErrWrongParamcountToNativeFct])
+	// ErrDivisionByZero is returned when a value is divided by zero.
+	ErrDivisionByZero = terror.ClassExpression.New(mysql.ErrDivisionByZero, mysql.MySQLErrName[mysql.ErrDivisionByZero])
+	// ErrFunctionNotExists is returned when a function is unknown.
+	ErrFunctionNotExists = terror.ClassExpression.New(mysql.ErrSpDoesNotExist, mysql.MySQLErrName[mysql.ErrSpDoesNotExist])
+	// ErrUnknownColumn is returned when a column can not be found.
+	ErrUnknownColumn = terror.ClassExpression.New(mysql.ErrBadField, mysql.MySQLErrName[mysql.ErrBadField])
+	// ErrNonUniq is returned when a column name is ambiguous.
+	ErrNonUniq = terror.ClassExpression.New(mysql.ErrNonUniq, mysql.MySQLErrName[mysql.ErrNonUniq])
+	// ErrIncorrectArguments is returned when the arguments of a function are invalid, like NTILE(0).
+	ErrIncorrectArguments = terror.ClassExpression.New(mysql.ErrWrongArguments, mysql.MySQLErrName[mysql.ErrWrongArguments])
+	// ErrNotSupportedYet is returned when an expression is not supported.
+	ErrNotSupportedYet = terror.ClassExpression.New(mysql.ErrNotSupportedYet, mysql.MySQLErrName[mysql.ErrNotSupportedYet])
+	// ErrCutValueGroupConcat is appended when the result of GROUP_CONCAT is truncated by group_concat_max_len.
+	ErrCutValueGroupConcat = terror.ClassExpression.New(mysql.ErrCutValueGroupConcat, mysql.MySQLErrName[mysql.ErrCutValueGroupConcat])
+	// errWarnAllowedPacketOverflowed is appended when the result of a function is larger than max_allowed_packet.
+	errWarnAllowedPacketOverflowed = terror.ClassExpression.New(mysql.ErrWarnAllowedPacketOverflowed, mysql.MySQLErrName[mysql.ErrWarnAllowedPacketOverflowed])
+)
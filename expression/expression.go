@@ -0,0 +1,316 @@
+package expression
+
+import (
+	"fmt"
+
+	"github.com/pingcap/parser/charset"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/sessionctx"
+	"grant-db/types"
+	"grant-db/types/json"
+	"grant-db/util/chunk"
+)
+
+// Expression represents all scalar expression in SQL.
+type Expression interface {
+	fmt.Stringer
+
+	// Eval evaluates an expression through a row.
+	Eval(row chunk.Row) (types.Datum, error)
+
+	// EvalInt returns the int64 representation of expression.
+	EvalInt(ctx sessionctx.Context, row chunk.Row) (val int64, isNull bool, err error)
+
+	// EvalReal returns the float64 representation of expression.
+	EvalReal(ctx sessionctx.Context, row chunk.Row) (val float64, isNull bool, err error)
+
+	// EvalString returns the string representation of expression.
+	EvalString(ctx sessionctx.Context, row chunk.Row) (val string, isNull bool, err error)
+
+	// EvalDecimal returns the decimal representation of expression.
+	EvalDecimal(ctx sessionctx.Context, row chunk.Row) (val *types.MyDecimal, isNull bool, err error)
+
+	// EvalTime returns the DATE/DATETIME/TIMESTAMP representation of expression.
+	EvalTime(ctx sessionctx.Context, row chunk.Row) (val types.Time, isNull bool, err error)
+
+	// EvalJSON returns the JSON representation of expression.
+	EvalJSON(ctx sessionctx.Context, row chunk.Row) (val json.BinaryJSON, isNull bool, err error)
+
+	// Vectorized returns if this expression supports vectorized evaluation.
+	Vectorized() bool
+
+	// VecEvalInt evaluates this expression in a vectorized manner.
+	VecEvalInt(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error
+
+	// VecEvalReal evaluates this expression in a vectorized manner.
+	VecEvalReal(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error
+
+	// VecEvalString evaluates this expression in a vectorized manner.
+	VecEvalString(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error
+
+	// VecEvalDecimal evaluates this expression in a vectorized manner.
+	VecEvalDecimal(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error
+
+	// VecEvalTime evaluates this expression in a vectorized manner.
+	VecEvalTime(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error
+
+	// VecEvalJSON evaluates this expression in a vectorized manner.
+	VecEvalJSON(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error
+
+	// GetType gets the type that the expression returns.
+	GetType() *types.FieldType
+
+	// Clone copies an expression totally.
+	Clone() Expression
+
+	// Coercibility returns the coercibility of the collation of the expression.
+	Coercibility() Coercibility
+
+	// SetCoercibility sets the coercibility of the collation of the expression.
+	SetCoercibility(coer Coercibility)
+
+	// Equal checks whether two expressions are equal.
+	Equal(ctx sessionctx.Context, e Expression) bool
+
+	// ResolveIndices resolves indices by the given schema. It will copy the original expression and return the copied one.
+	ResolveIndices(schema *Schema) (Expression, error)
+
+	// resolveIndices is called inside the `ResolveIndices` It will perform on the expression itself.
+	resolveIndices(schema *Schema) error
+}
+
+// CNFExprs stands for a CNF expression.
+type CNFExprs []Expression
+
+// Clone clones itself.
+func (e CNFExprs) Clone() CNFExprs {
+	cnf := make(CNFExprs, 0, len(e))
+	for _, expr := range e {
+		cnf = append(cnf, expr.Clone())
+	}
+	return cnf
+}
+
+// EvalBool evaluates expression list to a boolean value. The first returned value
+// indicates bool result of the expression list, the second returned value indicates
+// whether the result of the expression list is null, it can only be true when the
+// first returned values is false.
+func EvalBool(ctx sessionctx.Context, exprList CNFExprs, row chunk.Row) (bool, bool, error) {
+	hasNull := false
+	for _, expr := range exprList {
+		val, isNull, err := evalBool(ctx, expr, row)
+		if err != nil {
+			return false, false, err
+		}
+		if isNull {
+			hasNull = true
+			continue
+		}
+		if !val {
+			return false, false, nil
+		}
+	}
+	if hasNull {
+		return false, true, nil
+	}
+	return true, false, nil
+}
+
+// evalBool evaluates the truth value of expr according to its evaluation type, a string is
+// converted to a number first.
+func evalBool(ctx sessionctx.Context, expr Expression, row chunk.Row) (val bool, isNull bool, err error) {
+	switch expr.GetType().EvalType() {
+	case types.ETInt:
+		i, isNull, err := expr.EvalInt(ctx, row)
+		return i != 0, isNull, err
+	case types.ETReal:
+		f, isNull, err := expr.EvalReal(ctx, row)
+		return f != 0, isNull, err
+	case types.ETDecimal:
+		d, isNull, err := expr.EvalDecimal(ctx, row)
+		if isNull || err != nil {
+			return false, isNull, err
+		}
+		return !d.IsZero(), false, nil
+	case types.ETDatetime, types.ETTimestamp:
+		t, isNull, err := expr.EvalTime(ctx, row)
+		return !t.IsZero(), isNull, err
+	case types.ETJson:
+		j, isNull, err := expr.EvalJSON(ctx, row)
+		if isNull || err != nil {
+			return false, isNull, err
+		}
+		f, err := types.ConvertJSONToFloat(ctx.GetSessionVars().StmtCtx, j)
+		return f != 0, false, err
+	default:
+		s, isNull, err := expr.EvalString(ctx, row)
+		if isNull || err != nil {
+			return false, isNull, err
+		}
+		f, err := types.StrToFloat(ctx.GetSessionVars().StmtCtx, s)
+		return f != 0, false, err
+	}
+}
+
+// EvalExprsToChunk evaluates a list of expressions and appends their results to "output" Chunk.
+func EvalExprsToChunk(ctx sessionctx.Context, exprs []Expression, input *chunk.Chunk, output *chunk.Chunk) error {
+	for i := 0; i < input.NumRows(); i++ {
+		row := input.GetRow(i)
+		for colID, expr := range exprs {
+			if err := evalOneCell(ctx, expr, row, output, colID); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// evalOneCell evaluates expr on the row and appends the result to the colIdx column of output.
+func evalOneCell(ctx sessionctx.Context, expr Expression, row chunk.Row, output *chunk.Chunk, colIdx int) error {
+	ft := expr.GetType()
+	if ft.Hybrid() || ft.Tp == mysql.TypeDuration {
+		// The values of these types are kept in their own forms in the chunk.
+		d, err := evalDatum(ctx, expr, row)
+		if err != nil {
+			return err
+		}
+		output.AppendDatum(colIdx, &d)
+		return nil
+	}
+	switch ft.EvalType() {
+	case types.ETInt:
+		res, isNull, err := expr.EvalInt(ctx, row)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			output.AppendNull(colIdx)
+		} else {
+			output.AppendInt64(colIdx, res)
+		}
+	case types.ETReal:
+		res, isNull, err := expr.EvalReal(ctx, row)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			output.AppendNull(colIdx)
+		} else if ft.Tp == mysql.TypeFloat {
+			output.AppendFloat32(colIdx, float32(res))
+		} else {
+			output.AppendFloat64(colIdx, res)
+		}
+	case types.ETDecimal:
+		res, isNull, err := expr.EvalDecimal(ctx, row)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			output.AppendNull(colIdx)
+		} else {
+			output.AppendMyDecimal(colIdx, res)
+		}
+	case types.ETDatetime, types.ETTimestamp:
+		res, isNull, err := expr.EvalTime(ctx, row)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			output.AppendNull(colIdx)
+		} else {
+			output.AppendTime(colIdx, res)
+		}
+	case types.ETJson:
+		res, isNull, err := expr.EvalJSON(ctx, row)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			output.AppendNull(colIdx)
+		} else {
+			output.AppendJSON(colIdx, res)
+		}
+	default:
+		res, isNull, err := expr.EvalString(ctx, row)
+		if err != nil {
+			return err
+		}
+		if isNull {
+			output.AppendNull(colIdx)
+		} else {
+			output.AppendString(colIdx, res)
+		}
+	}
+	return nil
+}
+
+// evalDatum evaluates expr on the row into a datum according to the return type of expr.
+func evalDatum(ctx sessionctx.Context, expr Expression, row chunk.Row) (d types.Datum, err error) {
+	if col, ok := expr.(*Column); ok {
+		return col.Eval(row)
+	}
+	ft := expr.GetType()
+	var isNull bool
+	switch ft.EvalType() {
+	case types.ETInt:
+		var i int64
+		i, isNull, err = expr.EvalInt(ctx, row)
+		if mysql.HasUnsignedFlag(ft.Flag) {
+			d.SetUint64(uint64(i))
+		} else {
+			d.SetInt64(i)
+		}
+	case types.ETReal:
+		var f float64
+		f, isNull, err = expr.EvalReal(ctx, row)
+		d.SetFloat64(f)
+	case types.ETDecimal:
+		var dec *types.MyDecimal
+		dec, isNull, err = expr.EvalDecimal(ctx, row)
+		d.SetMysqlDecimal(dec)
+	case types.ETDatetime, types.ETTimestamp:
+		var t types.Time
+		t, isNull, err = expr.EvalTime(ctx, row)
+		d.SetMysqlTime(t)
+	case types.ETJson:
+		var j json.BinaryJSON
+		j, isNull, err = expr.EvalJSON(ctx, row)
+		d.SetMysqlJSON(j)
+	default:
+		var s string
+		s, isNull, err = expr.EvalString(ctx, row)
+		if ft.Charset == charset.CharsetBin {
+			d.SetBytes([]byte(s))
+		} else {
+			d.SetString(s)
+		}
+	}
+	if err != nil || isNull {
+		d.SetNull()
+	}
+	return d, err
+}
+
+// ExprsToStrings converts a list of expressions to their string forms.
+func ExprsToStrings(exprs []Expression) []string {
+	strs := make([]string, 0, len(exprs))
+	for _, expr := range exprs {
+		strs = append(strs, expr.String())
+	}
+	return strs
+}
+
+// Assignment is an assignment of the SET of UPDATE or ON DUPLICATE KEY UPDATE, the value of Expr is assigned
+// to the column Col.
+type Assignment struct {
+	Col *Column
+	// ColName is the name of the column, which is shown by EXPLAIN.
+	ColName model.CIStr
+	Expr    Expression
+}
+
+// String implements fmt.Stringer interface.
+func (a *Assignment) String() string {
+	return a.ColName.O + "=" + a.Expr.String()
+}
@@ -0,0 +1,117 @@
+package expression
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	_ "github.com/pingcap/tidb/types/parser_driver"
+	"grant-db/types"
+	"grant-db/util/chunk"
+)
+
+// testTableInfo is the table of the columns a INT and b VARCHAR(10).
+func testTableInfo() *model.TableInfo {
+	a := &model.ColumnInfo{Name: model.NewCIStr("a"), Offset: 0, FieldType: *types.NewFieldType(mysql.TypeLong)}
+	b := &model.ColumnInfo{Name: model.NewCIStr("b"), Offset: 1, FieldType: *stringType()}
+	return &model.TableInfo{Name: model.NewCIStr("t"), Columns: []*model.ColumnInfo{a, b}}
+}
+
+// testRows returns the rows (NULL, 'x'), (0, NULL), (2, NULL) and (2, 'x') of the test table.
+func testRows(tbl *model.TableInfo) *chunk.Chunk {
+	fieldTypes := []*types.FieldType{&tbl.Columns[0].FieldType, &tbl.Columns[1].FieldType}
+	input := chunk.NewChunkWithCapacity(fieldTypes, 4)
+	for _, row := range [][]interface{}{{nil, "x"}, {int64(0), nil}, {int64(2), nil}, {int64(2), "x"}} {
+		if row[0] == nil {
+			input.AppendNull(0)
+		} else {
+			input.AppendInt64(0, row[0].(int64))
+		}
+		if row[1] == nil {
+			input.AppendNull(1)
+		} else {
+			input.AppendString(1, row[1].(string))
+		}
+	}
+	return input
+}
+
+func TestEvalWithNull(t *testing.T) {
+	ctx := newMockContext()
+	tbl := testTableInfo()
+	input := testRows(tbl)
+	tests := []struct {
+		expr     string
+		expected string
+	}{
+		{"a > 1 and b = 'x'", "[<nil> 0 <nil> 1]"},
+		{"a > 1 or b = 'x'", "[1 <nil> 1 1]"},
+		{"a > 1 xor b = 'x'", "[<nil> <nil> <nil> 0]"},
+		{"not (a > 1)", "[<nil> 1 0 0]"},
+		{"a is null", "[1 0 0 0]"},
+		{"b is not null and a is not true", "[1 0 0 0]"},
+		{"a in (0, null)", "[<nil> 1 <nil> <nil>]"},
+		{"a not in (0, 1)", "[<nil> 0 1 1]"},
+		{"a <=> null", "[1 0 0 0]"},
+		{"a = null", "[<nil> <nil> <nil> <nil>]"},
+		{"ifnull(a, -1) + 1", "[0 1 3 3]"},
+		{"coalesce(b, a, 'y')", "[x 0 2 x]"},
+		{"case when a > 1 then 'big' when a = 0 then 'zero' end", "[<nil> zero big big]"},
+		{"if(a, b, 'no')", "[no no <nil> x]"},
+		{"a between 0 and 1", "[<nil> 1 0 0]"},
+		{"b like 'x%'", "[1 <nil> <nil> 1]"},
+	}
+	for _, tt := range tests {
+		expr, err := ParseSimpleExprWithTableInfo(ctx, tt.expr, tbl)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.expr, err)
+		}
+		if got := fmt.Sprint(evalByRows(ctx, expr, input)); got != tt.expected {
+			t.Fatalf("%s = %s, expected %s", tt.expr, got, tt.expected)
+		}
+	}
+}
+
+func TestTypeInference(t *testing.T) {
+	ctx := newMockContext()
+	tbl := testTableInfo()
+	// The last row is (2, 'x').
+	input := testRows(tbl)
+	tests := []struct {
+		expr     string
+		evalType types.EvalType
+		expected string
+	}{
+		{"a + 1", types.ETInt, "3"},
+		{"a + 1.5", types.ETDecimal, "3.5"},
+		{"a + 1e0", types.ETReal, "3"},
+		{"a / 4", types.ETDecimal, "0.5000"},
+		{"a + '1.5'", types.ETReal, "3.5"},
+		{"a = '2'", types.ETInt, "1"},
+		{"concat(a, b)", types.ETString, "2x"},
+		{"cast(a as char)", types.ETString, "2"},
+		{"cast('2020-01-02' as datetime)", types.ETDatetime, "2020-01-02 00:00:00"},
+		{"b > 1", types.ETInt, "0"},
+	}
+	for _, tt := range tests {
+		expr, err := ParseSimpleExprWithTableInfo(ctx, tt.expr, tbl)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.expr, err)
+		}
+		if tp := expr.GetType().EvalType(); tp != tt.evalType {
+			t.Fatalf("the type of %s is %v, expected %v", tt.expr, tp, tt.evalType)
+		}
+		d, err := expr.Eval(input.GetRow(3))
+		if err != nil {
+			t.Fatalf("%s: %v", tt.expr, err)
+		}
+		if s, err := d.ToString(); err != nil || s != tt.expected {
+			t.Fatalf("%s = %s, %v, expected %s", tt.expr, s, err, tt.expected)
+		}
+	}
+	// Comparing the string with the number casts it to a number, which warns of the truncation.
+	if n := len(ctx.GetSessionVars().StmtCtx.GetWarnings()); n == 0 {
+		t.Fatal("comparing 'x' with 1 has no warning")
+	}
+}
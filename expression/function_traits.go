@@ -0,0 +1,11 @@
+package expression
+
+import "github.com/pingcap/parser/ast"
+
+// unFoldableFunctions stores functions which can not be folded during constant folding stage.
+var unFoldableFunctions = map[string]struct{}{
+	ast.Now:              {},
+	ast.CurrentTimestamp: {},
+	ast.UnixTimestamp:    {},
+	ast.LastInsertId:     {},
+}
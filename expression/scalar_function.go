@@ -0,0 +1,253 @@
+package expression
+
+import (
+	"bytes"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/model"
+	"grant-db/sessionctx"
+	"grant-db/types"
+	"grant-db/types/json"
+	"grant-db/util/chunk"
+)
+
+// ScalarFunction is the function that returns a value.
+type ScalarFunction struct {
+	collationInfo
+
+	FuncName model.CIStr
+	// RetType is the type that ScalarFunction returns.
+	// TODO: Implement type inference here, now we use ast's return type temporarily.
+	RetType  *types.FieldType
+	Function builtinFunc
+}
+
+// GetArgs gets arguments of function.
+func (sf *ScalarFunction) GetArgs() []Expression {
+	return sf.Function.getArgs()
+}
+
+// GetCtx gets the context of function.
+func (sf *ScalarFunction) GetCtx() sessionctx.Context {
+	return sf.Function.getCtx()
+}
+
+// String implements fmt.Stringer interface.
+func (sf *ScalarFunction) String() string {
+	var buffer bytes.Buffer
+	buffer.WriteString(sf.FuncName.L + "(")
+	for i, arg := range sf.GetArgs() {
+		buffer.WriteString(arg.String())
+		if i+1 != len(sf.GetArgs()) {
+			buffer.WriteString(", ")
+		}
+	}
+	buffer.WriteString(")")
+	return buffer.String()
+}
+
+// newFunctionImpl creates a new scalar function or constant by InferType and args.
+func newFunctionImpl(ctx sessionctx.Context, fold bool, funcName string, retType *types.FieldType, args ...Expression) (Expression, error) {
+	if retType == nil {
+		return nil, errors.Errorf("RetType cannot be nil for ScalarFunction.")
+	}
+	fc, ok := funcs[funcName]
+	if !ok {
+		return nil, ErrFunctionNotExists.GenWithStackByArgs("FUNCTION", funcName)
+	}
+	funcArgs := make([]Expression, len(args))
+	copy(funcArgs, args)
+	f, err := fc.getFunction(ctx, funcArgs)
+	if err != nil {
+		return nil, err
+	}
+	if builtinRetTp := f.getRetTp(); builtinRetTp.Tp != 0 || types.IsString(retType.Tp) {
+		retType = builtinRetTp
+	}
+	sf := &ScalarFunction{
+		FuncName: model.NewCIStr(funcName),
+		RetType:  retType,
+		Function: f,
+	}
+	if fold {
+		return FoldConstant(sf), nil
+	}
+	return sf, nil
+}
+
+// NewFunction creates a new scalar function or constant via a constant folding.
+func NewFunction(ctx sessionctx.Context, funcName string, retType *types.FieldType, args ...Expression) (Expression, error) {
+	return newFunctionImpl(ctx, true, funcName, retType, args...)
+}
+
+// NewFunctionBase creates a new scalar function with no constant folding.
+func NewFunctionBase(ctx sessionctx.Context, funcName string, retType *types.FieldType, args ...Expression) (Expression, error) {
+	return newFunctionImpl(ctx, false, funcName, retType, args...)
+}
+
+// NewFunctionInternal is similar to NewFunction, but do not returns error, should only be used internally.
+func NewFunctionInternal(ctx sessionctx.Context, funcName string, retType *types.FieldType, args ...Expression) Expression {
+	expr, err := NewFunction(ctx, funcName, retType, args...)
+	if err != nil {
+		panic(err)
+	}
+	return expr
+}
+
+// ScalarFuncs2Exprs converts []*ScalarFunction to []Expression.
+func ScalarFuncs2Exprs(funcs []*ScalarFunction) []Expression {
+	result := make([]Expression, 0, len(funcs))
+	for _, col := range funcs {
+		result = append(result, col)
+	}
+	return result
+}
+
+// Clone implements Expression interface.
+func (sf *ScalarFunction) Clone() Expression {
+	return &ScalarFunction{
+		collationInfo: sf.collationInfo,
+		FuncName:      sf.FuncName,
+		RetType:       sf.RetType,
+		Function:      sf.Function.Clone(),
+	}
+}
+
+// Coercibility implements Expression interface.
+func (sf *ScalarFunction) Coercibility() Coercibility {
+	if !sf.coerInit {
+		sf.SetCoercibility(deriveCoercibilityForScalarFunc(sf))
+	}
+	return sf.coer
+}
+
+// GetType implements Expression interface.
+func (sf *ScalarFunction) GetType() *types.FieldType {
+	return sf.RetType
+}
+
+// Equal implements Expression interface.
+func (sf *ScalarFunction) Equal(ctx sessionctx.Context, e Expression) bool {
+	fun, ok := e.(*ScalarFunction)
+	if !ok {
+		return false
+	}
+	if sf.FuncName.L != fun.FuncName.L {
+		return false
+	}
+	return sf.Function.equal(fun.Function)
+}
+
+// Eval implements Expression interface.
+func (sf *ScalarFunction) Eval(row chunk.Row) (types.Datum, error) {
+	return evalDatum(sf.GetCtx(), sf, row)
+}
+
+// EvalInt implements Expression interface.
+func (sf *ScalarFunction) EvalInt(ctx sessionctx.Context, row chunk.Row) (int64, bool, error) {
+	return sf.Function.evalInt(row)
+}
+
+// EvalReal implements Expression interface.
+func (sf *ScalarFunction) EvalReal(ctx sessionctx.Context, row chunk.Row) (float64, bool, error) {
+	return sf.Function.evalReal(row)
+}
+
+// EvalDecimal implements Expression interface.
+func (sf *ScalarFunction) EvalDecimal(ctx sessionctx.Context, row chunk.Row) (*types.MyDecimal, bool, error) {
+	return sf.Function.evalDecimal(row)
+}
+
+// EvalString implements Expression interface.
+func (sf *ScalarFunction) EvalString(ctx sessionctx.Context, row chunk.Row) (string, bool, error) {
+	return sf.Function.evalString(row)
+}
+
+// EvalTime implements Expression interface.
+func (sf *ScalarFunction) EvalTime(ctx sessionctx.Context, row chunk.Row) (types.Time, bool, error) {
+	return sf.Function.evalTime(row)
+}
+
+// EvalJSON implements Expression interface.
+func (sf *ScalarFunction) EvalJSON(ctx sessionctx.Context, row chunk.Row) (json.BinaryJSON, bool, error) {
+	return sf.Function.evalJSON(row)
+}
+
+// Vectorized returns if this expression supports vectorized evaluation.
+func (sf *ScalarFunction) Vectorized() bool {
+	if !sf.Function.vectorized() {
+		return false
+	}
+	for _, arg := range sf.GetArgs() {
+		if !arg.Vectorized() {
+			return false
+		}
+	}
+	return true
+}
+
+// VecEvalInt evaluates this expression in a vectorized manner, a function without a vectorized
+// version is evaluated row by row.
+func (sf *ScalarFunction) VecEvalInt(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	if !sf.Function.vectorized() {
+		return vecEvalIntByRows(ctx, sf, input, result)
+	}
+	return sf.Function.vecEvalInt(input, result)
+}
+
+// VecEvalReal evaluates this expression in a vectorized manner.
+func (sf *ScalarFunction) VecEvalReal(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	if !sf.Function.vectorized() {
+		return vecEvalRealByRows(ctx, sf, input, result)
+	}
+	return sf.Function.vecEvalReal(input, result)
+}
+
+// VecEvalString evaluates this expression in a vectorized manner.
+func (sf *ScalarFunction) VecEvalString(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	if !sf.Function.vectorized() {
+		return vecEvalStringByRows(ctx, sf, input, result)
+	}
+	return sf.Function.vecEvalString(input, result)
+}
+
+// VecEvalDecimal evaluates this expression in a vectorized manner.
+func (sf *ScalarFunction) VecEvalDecimal(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	if !sf.Function.vectorized() {
+		return vecEvalDecimalByRows(ctx, sf, input, result)
+	}
+	return sf.Function.vecEvalDecimal(input, result)
+}
+
+// VecEvalTime evaluates this expression in a vectorized manner.
+func (sf *ScalarFunction) VecEvalTime(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	if !sf.Function.vectorized() {
+		return vecEvalTimeByRows(ctx, sf, input, result)
+	}
+	return sf.Function.vecEvalTime(input, result)
+}
+
+// VecEvalJSON evaluates this expression in a vectorized manner.
+func (sf *ScalarFunction) VecEvalJSON(ctx sessionctx.Context, input *chunk.Chunk, result *chunk.Column) error {
+	if !sf.Function.vectorized() {
+		return vecEvalJSONByRows(ctx, sf, input, result)
+	}
+	return sf.Function.vecEvalJSON(input, result)
+}
+
+// ResolveIndices implements Expression interface.
+func (sf *ScalarFunction) ResolveIndices(schema *Schema) (Expression, error) {
+	newSf := sf.Clone()
+	err := newSf.resolveIndices(schema)
+	return newSf, err
+}
+
+func (sf *ScalarFunction) resolveIndices(schema *Schema) error {
+	for _, arg := range sf.GetArgs() {
+		err := arg.resolveIndices(schema)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
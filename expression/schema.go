@@ -0,0 +1,136 @@
+package expression
+
+import (
+	"strings"
+)
+
+// KeyInfo stores the columns of one unique key or primary key.
+type KeyInfo []*Column
+
+// Clone copies the entire UniqueKey.
+func (ki KeyInfo) Clone() KeyInfo {
+	result := make([]*Column, 0, len(ki))
+	for _, col := range ki {
+		result = append(result, col.Clone().(*Column))
+	}
+	return result
+}
+
+// Schema stands for the row schema and unique key information get from input.
+type Schema struct {
+	Columns []*Column
+	Keys    []KeyInfo
+}
+
+// NewSchema returns a schema made by its parameter.
+func NewSchema(cols ...*Column) *Schema {
+	return &Schema{Columns: cols}
+}
+
+// String implements fmt.Stringer interface.
+func (s *Schema) String() string {
+	colStrs := make([]string, 0, len(s.Columns))
+	for _, col := range s.Columns {
+		colStrs = append(colStrs, col.String())
+	}
+	return "Column: [" + strings.Join(colStrs, ",") + "]"
+}
+
+// Clone copies the total schema.
+func (s *Schema) Clone() *Schema {
+	cols := make([]*Column, 0, s.Len())
+	for _, col := range s.Columns {
+		cols = append(cols, col.Clone().(*Column))
+	}
+	schema := NewSchema(cols...)
+	for _, key := range s.Keys {
+		schema.Keys = append(schema.Keys, key.Clone())
+	}
+	return schema
+}
+
+// ColumnIndex finds the index for a column.
+func (s *Schema) ColumnIndex(col *Column) int {
+	for i, c := range s.Columns {
+		if c.UniqueID == col.UniqueID {
+			return i
+		}
+	}
+	return -1
+}
+
+// Contains checks if the schema contains the column.
+func (s *Schema) Contains(col *Column) bool {
+	return s.ColumnIndex(col) != -1
+}
+
+// ColumnsIndices finds the indices of the columns, nil is returned if any of them is not in the schema.
+func (s *Schema) ColumnsIndices(cols []*Column) []int {
+	ret := make([]int, 0, len(cols))
+	for _, col := range cols {
+		idx := s.ColumnIndex(col)
+		if idx == -1 {
+			return nil
+		}
+		ret = append(ret, idx)
+	}
+	return ret
+}
+
+// ColumnsByIndices returns the columns of the indices.
+func (s *Schema) ColumnsByIndices(offsets []int) []*Column {
+	cols := make([]*Column, 0, len(offsets))
+	for _, offset := range offsets {
+		cols = append(cols, s.Columns[offset])
+	}
+	return cols
+}
+
+// IsUniqueKey checks whether the columns contain a unique key of the schema.
+func (s *Schema) IsUniqueKey(cols ...*Column) bool {
+	for _, key := range s.Keys {
+		found := true
+		for _, keyCol := range key {
+			if !NewSchema(cols...).Contains(keyCol) {
+				found = false
+				break
+			}
+		}
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+// Len returns the number of columns in schema.
+func (s *Schema) Len() int {
+	return len(s.Columns)
+}
+
+// Append append new column to the columns stored in schema.
+func (s *Schema) Append(col ...*Column) {
+	s.Columns = append(s.Columns, col...)
+}
+
+// SetColumns sets columns for schema.
+func (s *Schema) SetColumns(cols []*Column) {
+	s.Columns = cols
+}
+
+// MergeSchema will merge two schema into one schema.
+func MergeSchema(lSchema, rSchema *Schema) *Schema {
+	if lSchema == nil && rSchema == nil {
+		return nil
+	}
+	if lSchema == nil {
+		return rSchema.Clone()
+	}
+	if rSchema == nil {
+		return lSchema.Clone()
+	}
+	tmpL := lSchema.Clone()
+	tmpR := rSchema.Clone()
+	ret := NewSchema(append(tmpL.Columns, tmpR.Columns...)...)
+	return ret
+}
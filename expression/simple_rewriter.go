@@ -0,0 +1,493 @@
+package expression
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/pingcap/parser"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/opcode"
+	"grant-db/sessionctx"
+	"grant-db/sessionctx/variable"
+	"grant-db/types"
+	"grant-db/util/charset"
+)
+
+type simpleRewriter struct {
+	exprStack
+
+	schema *Schema
+	err    error
+	ctx    sessionctx.Context
+	names  []*types.FieldName
+
+	hook RewriteHook
+	// hooked is the node rewritten by the hook, it's skipped when it's left.
+	hooked ast.Node
+}
+
+// RewriteHook rewrites the nodes which need more than the simple rewriter knows, like the columns of a plan,
+// the aggregate functions and the sub queries. The node is rewritten to expr if ok is true, and its children
+// are not visited.
+type RewriteHook func(node ast.Node) (expr Expression, ok bool, err error)
+
+// RewriteExprWithHook rewrites ast.ExprNode to expression.Expression, every node is offered to the hook first,
+// and the columns must be rewritten by the hook.
+func RewriteExprWithHook(ctx sessionctx.Context, expr ast.ExprNode, hook RewriteHook) (Expression, error) {
+	rewriter := &simpleRewriter{ctx: ctx, schema: NewSchema(), hook: hook}
+	expr.Accept(rewriter)
+	if rewriter.err != nil {
+		return nil, rewriter.err
+	}
+	return rewriter.pop(), nil
+}
+
+// ParseSimpleExprWithTableInfo parses simple expression string to Expression.
+// The expression string must only reference the column in table Info.
+func ParseSimpleExprWithTableInfo(ctx sessionctx.Context, exprStr string, tableInfo *model.TableInfo) (Expression, error) {
+	exprStr = "select " + exprStr
+	stmts, _, err := parser.New().Parse(exprStr, "", "")
+	if err != nil {
+		return nil, err
+	}
+	expr := stmts[0].(*ast.SelectStmt).Fields.Fields[0].Expr
+	return RewriteSimpleExprWithTableInfo(ctx, tableInfo, expr)
+}
+
+// RewriteSimpleExprWithTableInfo rewrites simple ast.ExprNode to expression.Expression.
+func RewriteSimpleExprWithTableInfo(ctx sessionctx.Context, tbl *model.TableInfo, expr ast.ExprNode) (Expression, error) {
+	dbName := model.NewCIStr(ctx.GetSessionVars().CurrentDB)
+	columns, names := ColumnInfos2ColumnsAndNames(ctx, dbName, tbl.Name, tbl.Columns)
+	return RewriteSimpleExprWithNames(ctx, expr, NewSchema(columns...), names)
+}
+
+// RewriteSimpleExprWithNames rewrites simple ast.ExprNode to expression.Expression,
+// the columns are resolved by names, which are the names of the schema columns in order.
+func RewriteSimpleExprWithNames(ctx sessionctx.Context, expr ast.ExprNode, schema *Schema, names []*types.FieldName) (Expression, error) {
+	rewriter := &simpleRewriter{ctx: ctx, schema: schema, names: names}
+	expr.Accept(rewriter)
+	if rewriter.err != nil {
+		return nil, rewriter.err
+	}
+	return rewriter.pop(), nil
+}
+
+// FindFieldName finds the column name from NameSlice, -1 is returned if it's not found.
+func FindFieldName(names types.NameSlice, astCol *ast.ColumnName) (int, error) {
+	dbName, tblName, colName := astCol.Schema, astCol.Table, astCol.Name
+	idx := -1
+	for i, name := range names {
+		if name.Redundant && tblName.L == "" {
+			continue
+		}
+		if (dbName.L == "" || dbName.L == name.DBName.L) &&
+			(tblName.L == "" || tblName.L == name.TblName.L) &&
+			(colName.L == name.ColName.L) {
+			if idx == -1 {
+				idx = i
+			} else {
+				return -1, ErrNonUniq.GenWithStackByArgs(astCol.String(), "field list")
+			}
+		}
+	}
+	return idx, nil
+}
+
+func (sr *simpleRewriter) rewriteColumn(nodeColName *ast.ColumnNameExpr) (*Column, error) {
+	idx, err := FindFieldName(sr.names, nodeColName.Name)
+	if idx >= 0 && err == nil {
+		return sr.schema.Columns[idx], nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return nil, ErrUnknownColumn.GenWithStackByArgs(nodeColName.Name.String(), "field list")
+}
+
+func (sr *simpleRewriter) Enter(inNode ast.Node) (ast.Node, bool) {
+	if sr.hook != nil {
+		expr, ok, err := sr.hook(inNode)
+		if err != nil {
+			sr.err = err
+			return inNode, true
+		}
+		if ok {
+			sr.push(expr)
+			sr.hooked = inNode
+			return inNode, true
+		}
+	}
+	switch inNode.(type) {
+	case *ast.ColumnNameExpr:
+		// The column name is resolved as a whole when the node is left.
+		return inNode, true
+	}
+	return inNode, false
+}
+
+func (sr *simpleRewriter) Leave(originInNode ast.Node) (retNode ast.Node, ok bool) {
+	if sr.err != nil {
+		return originInNode, false
+	}
+	if sr.hooked != nil && sr.hooked == originInNode {
+		sr.hooked = nil
+		return originInNode, true
+	}
+	switch v := originInNode.(type) {
+	case *ast.ColumnNameExpr:
+		column, err := sr.rewriteColumn(v)
+		if err != nil {
+			sr.err = err
+			return originInNode, false
+		}
+		sr.push(column)
+	case ast.ValueExpr:
+		value, err := valueExprToDatum(v)
+		if err != nil {
+			sr.err = err
+			return originInNode, false
+		}
+		retType := *v.GetType()
+		sr.push(&Constant{Value: value, RetType: &retType})
+	case *ast.FuncCallExpr:
+		sr.funcCallToExpression(v)
+	case *ast.BinaryOperationExpr:
+		sr.binaryOpToExpression(v)
+	case *ast.UnaryOperationExpr:
+		sr.unaryOpToExpression(v)
+	case *ast.ParenthesesExpr:
+	case *ast.IsNullExpr:
+		sr.isNullToExpression(v)
+	case *ast.IsTruthExpr:
+		sr.isTrueToScalarFunc(v)
+	case *ast.BetweenExpr:
+		sr.betweenToExpression(v)
+	case *ast.PatternInExpr:
+		if v.Sel != nil {
+			sr.err = ErrNotSupportedYet.GenWithStackByArgs("subquery in simple expression")
+			return originInNode, false
+		}
+		sr.inToExpression(len(v.List), v.Not)
+	case *ast.PatternLikeExpr:
+		sr.likeToScalarFunc(v)
+	case *ast.FuncCastExpr:
+		sr.castToExpression(v)
+	case *ast.CaseExpr:
+		sr.caseWhenToExpression(v)
+	case *ast.SetCollationExpr:
+		sr.setCollation(v)
+	case *ast.WhenClause:
+		// The condition and result are popped by the CASE expression.
+	case *ast.VariableExpr:
+		sr.variableToConstant(v)
+	case *ast.TimeUnitExpr:
+		unitTp := types.NewFieldType(mysql.TypeVarString)
+		unitTp.Charset, unitTp.Collate = mysql.DefaultCharset, mysql.DefaultCollationName
+		sr.push(&Constant{Value: types.NewStringDatum(v.Unit.String()), RetType: unitTp})
+	default:
+		sr.err = ErrNotSupportedYet.GenWithStackByArgs(fmt.Sprintf("expression %T", originInNode))
+	}
+	if sr.err != nil {
+		return retNode, false
+	}
+	return originInNode, true
+}
+
+// variableToConstant reads the value of the system variable, which is a string like the one shown by SHOW VARIABLES.
+func (sr *simpleRewriter) variableToConstant(v *ast.VariableExpr) {
+	if !v.IsSystem {
+		sr.err = ErrNotSupportedYet.GenWithStackByArgs("user variable")
+		return
+	}
+	vars := sr.ctx.GetSessionVars()
+	var val string
+	if v.IsGlobal {
+		val, sr.err = variable.GetGlobalSystemVar(vars, v.Name)
+	} else {
+		val, sr.err = variable.GetSessionSystemVar(vars, v.Name)
+	}
+	if sr.err != nil {
+		return
+	}
+	tp := types.NewFieldType(mysql.TypeVarString)
+	tp.Charset, tp.Collate = vars.GetCharsetInfo()
+	tp.Flen = len(val)
+	sr.push(&Constant{Value: types.NewStringDatum(val), RetType: tp})
+}
+
+// valueExprToDatum converts the value of a literal, which is made by the parser driver, to a datum.
+func valueExprToDatum(v ast.ValueExpr) (d types.Datum, err error) {
+	switch x := v.GetValue().(type) {
+	case nil:
+		d.SetNull()
+	case int64:
+		d.SetInt64(x)
+	case uint64:
+		d.SetUint64(x)
+	case float64:
+		d.SetFloat64(x)
+	case string:
+		d.SetString(x)
+	case []byte:
+		d.SetBytes(x)
+	default:
+		// A hex or bit literal is a byte slice.
+		if rv := reflect.ValueOf(x); rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+			d.SetBinaryLiteral(types.BinaryLiteral(rv.Bytes()))
+			break
+		}
+		s := fmt.Sprintf("%v", x)
+		if v.GetType().Tp == mysql.TypeNewDecimal {
+			dec := new(types.MyDecimal)
+			if err = dec.FromString([]byte(s)); err != nil && err != types.ErrTruncated {
+				return d, err
+			}
+			d.SetMysqlDecimal(dec)
+			break
+		}
+		d.SetString(s)
+	}
+	return d, nil
+}
+
+func (sr *simpleRewriter) newFunction(funcName string, retType *types.FieldType, args ...Expression) Expression {
+	if sr.err != nil {
+		return nil
+	}
+	expr, err := NewFunction(sr.ctx, funcName, retType, args...)
+	if err != nil {
+		sr.err = err
+	}
+	return expr
+}
+
+func (sr *simpleRewriter) funcCallToExpression(v *ast.FuncCallExpr) {
+	args := sr.popN(len(v.Args))
+	sr.push(sr.newFunction(v.FnName.L, &v.Type, args...))
+}
+
+// castToExpression converts CAST(expr AS type) to a cast function, the unspecified length of the type
+// is set to its default.
+func (sr *simpleRewriter) castToExpression(v *ast.FuncCastExpr) {
+	arg := sr.pop()
+	tp := v.Tp.Clone()
+	switch tp.Tp {
+	case mysql.TypeDuration:
+		sr.err = ErrNotSupportedYet.GenWithStackByArgs(fmt.Sprintf("CAST AS %s", types.TypeStr(tp.Tp)))
+		return
+	case mysql.TypeJSON:
+		// The string is parsed as the JSON text by the ParseToJSONFlag set by the parser.
+		tp.Flen, tp.Decimal = mysql.MaxBlobWidth, 0
+		types.SetBinChsClnFlag(tp)
+	case mysql.TypeLonglong:
+		tp.Flen, tp.Decimal = mysql.MaxIntWidth, 0
+	case mysql.TypeNewDecimal:
+		if tp.Flen == types.UnspecifiedLength {
+			tp.Flen = mysql.MaxDecimalWidth
+			if tp.Decimal == types.UnspecifiedLength {
+				tp.Flen = 10
+			}
+		}
+		if tp.Decimal == types.UnspecifiedLength {
+			tp.Decimal = 0
+		}
+		if tp.Flen > mysql.MaxDecimalWidth || tp.Decimal > mysql.MaxDecimalScale || tp.Decimal > tp.Flen {
+			sr.err = ErrNotSupportedYet.GenWithStackByArgs(fmt.Sprintf("CAST AS DECIMAL(%d,%d)", tp.Flen, tp.Decimal))
+			return
+		}
+	case mysql.TypeDate:
+		tp.Flen, tp.Decimal = mysql.MaxDateWidth, 0
+	case mysql.TypeDatetime:
+		if tp.Decimal == types.UnspecifiedLength {
+			tp.Decimal = 0
+		}
+		if tp.Decimal > int(types.MaxFsp) {
+			sr.err = types.ErrTooBigPrecision.GenWithStackByArgs(tp.Decimal, "CAST", types.MaxFsp)
+			return
+		}
+		tp.Flen = mysql.MaxDatetimeWidthNoFsp
+		if tp.Decimal > 0 {
+			tp.Flen += 1 + tp.Decimal
+		}
+	case mysql.TypeString, mysql.TypeVarString:
+		if tp.Charset == "" {
+			tp.Charset, tp.Collate = mysql.DefaultCharset, mysql.DefaultCollationName
+		}
+		tp.Decimal = types.UnspecifiedLength
+	}
+	if sr.err == nil {
+		sr.push(BuildCastFunction(sr.ctx, arg, tp))
+	}
+}
+
+// setCollation sets the collation of `expr COLLATE collation`, which has the explicit coercibility.
+func (sr *simpleRewriter) setCollation(v *ast.SetCollationExpr) {
+	arg := sr.pop()
+	coll, err := charset.GetCollationByName(v.Collate)
+	if err != nil {
+		sr.err = err
+		return
+	}
+	tp := arg.GetType().Clone()
+	if tp.EvalType() != types.ETString || tp.Charset != coll.CharsetName {
+		sr.err = charset.ErrCollationCharsetMismatch.GenWithStackByArgs(coll.Name, tp.Charset)
+		return
+	}
+	tp.Collate = coll.Name
+	arg = arg.Clone()
+	switch x := arg.(type) {
+	case *Column:
+		x.RetType = tp
+	case *Constant:
+		x.RetType = tp
+	case *ScalarFunction:
+		x.RetType = tp
+	}
+	arg.SetCoercibility(CoercibilityExplicit)
+	sr.push(arg)
+}
+
+// caseWhenToExpression converts the CASE expression to the CASE WHEN function, CASE value WHEN compare_value
+// THEN result is converted to CASE WHEN value = compare_value THEN result.
+func (sr *simpleRewriter) caseWhenToExpression(v *ast.CaseExpr) {
+	argsLen := 2 * len(v.WhenClauses)
+	if v.ElseClause != nil {
+		argsLen++
+	}
+	args := sr.popN(argsLen)
+	if v.Value != nil {
+		value := sr.pop()
+		for i := 0; i < len(v.WhenClauses); i++ {
+			args[2*i] = sr.newFunction(ast.EQ, types.NewFieldType(mysql.TypeTiny), value.Clone(), args[2*i])
+		}
+	}
+	sr.push(sr.newFunction(ast.Case, &v.Type, args...))
+}
+
+func (sr *simpleRewriter) binaryOpToExpression(v *ast.BinaryOperationExpr) {
+	right := sr.pop()
+	left := sr.pop()
+	var function Expression
+	switch v.Op {
+	case opcode.EQ, opcode.NE, opcode.NullEQ, opcode.GT, opcode.GE, opcode.LT, opcode.LE:
+		function = sr.newFunction(v.Op.String(), types.NewFieldType(mysql.TypeTiny), left, right)
+	default:
+		function = sr.newFunction(v.Op.String(), types.NewFieldType(mysql.TypeUnspecified), left, right)
+	}
+	sr.push(function)
+}
+
+func (sr *simpleRewriter) unaryOpToExpression(v *ast.UnaryOperationExpr) {
+	var op string
+	switch v.Op {
+	case opcode.Plus:
+		// expression (+ a) is equal to a
+		return
+	case opcode.Minus:
+		op = ast.UnaryMinus
+	case opcode.BitNeg:
+		op = ast.BitNeg
+	case opcode.Not:
+		op = ast.UnaryNot
+	default:
+		sr.err = ErrNotSupportedYet.GenWithStackByArgs(fmt.Sprintf("Unknown Unary Op %T", v.Op))
+		return
+	}
+	expr := sr.pop()
+	sr.push(sr.newFunction(op, &v.Type, expr))
+}
+
+func (sr *simpleRewriter) likeToScalarFunc(v *ast.PatternLikeExpr) {
+	pattern := sr.pop()
+	expr := sr.pop()
+	escapeTp := types.NewFieldType(mysql.TypeLonglong)
+	escapeTp.Flen, escapeTp.Decimal = mysql.MaxIntWidth, 0
+	escape := &Constant{Value: types.NewIntDatum(int64(v.Escape)), RetType: escapeTp}
+	function := sr.newFunction(ast.Like, types.NewFieldType(mysql.TypeTiny), expr, pattern, escape)
+	if v.Not {
+		function = sr.notToExpression(function)
+	}
+	sr.push(function)
+}
+
+func (sr *simpleRewriter) notToExpression(hasNot Expression) Expression {
+	return sr.newFunction(ast.UnaryNot, types.NewFieldType(mysql.TypeTiny), hasNot)
+}
+
+func (sr *simpleRewriter) isNullToExpression(v *ast.IsNullExpr) {
+	arg := sr.pop()
+	function := sr.newFunction(ast.IsNull, types.NewFieldType(mysql.TypeTiny), arg)
+	if v.Not {
+		function = sr.notToExpression(function)
+	}
+	sr.push(function)
+}
+
+func (sr *simpleRewriter) isTrueToScalarFunc(v *ast.IsTruthExpr) {
+	arg := sr.pop()
+	op := ast.IsTruth
+	if v.True == 0 {
+		op = ast.IsFalsity
+	}
+	function := sr.newFunction(op, types.NewFieldType(mysql.TypeTiny), arg)
+	if v.Not {
+		function = sr.notToExpression(function)
+	}
+	sr.push(function)
+}
+
+// inToExpression converts in expression to a scalar function. The argument lLen means the length of in list.
+// The argument not means if the expression is not in.
+func (sr *simpleRewriter) inToExpression(lLen int, not bool) {
+	exprs := sr.popN(lLen + 1)
+	function := sr.newFunction(ast.In, types.NewFieldType(mysql.TypeTiny), exprs...)
+	if not {
+		function = sr.notToExpression(function)
+	}
+	sr.push(function)
+}
+
+func (sr *simpleRewriter) betweenToExpression(v *ast.BetweenExpr) {
+	right := sr.pop()
+	left := sr.pop()
+	expr := sr.pop()
+	ge := sr.newFunction(ast.GE, types.NewFieldType(mysql.TypeTiny), expr, left)
+	le := sr.newFunction(ast.LE, types.NewFieldType(mysql.TypeTiny), expr, right)
+	function := sr.newFunction(ast.LogicAnd, types.NewFieldType(mysql.TypeTiny), ge, le)
+	if v.Not {
+		function = sr.notToExpression(function)
+	}
+	sr.push(function)
+}
+
+// exprStack is the stack of the rewritten expressions, the arguments of a node are pushed before the node is left.
+type exprStack struct {
+	stack []Expression
+}
+
+func (s *exprStack) pop() Expression {
+	if len(s.stack) == 0 {
+		return nil
+	}
+	lastIdx := len(s.stack) - 1
+	expr := s.stack[lastIdx]
+	s.stack = s.stack[:lastIdx]
+	return expr
+}
+
+func (s *exprStack) popN(n int) []Expression {
+	if n > len(s.stack) {
+		n = len(s.stack)
+	}
+	idx := len(s.stack) - n
+	exprs := make([]Expression, n)
+	copy(exprs, s.stack[idx:])
+	s.stack = s.stack[:idx]
+	return exprs
+}
+
+func (s *exprStack) push(expr Expression) {
+	s.stack = append(s.stack, expr)
+}
@@ -0,0 +1,196 @@
+package expression
+
+import (
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/sessionctx"
+	"grant-db/types"
+	"grant-db/util/chunk"
+)
+
+// ExtractColumns extracts all the columns from an expression, the correlated columns are excluded.
+func ExtractColumns(expr Expression) []*Column {
+	return extractColumns(make([]*Column, 0, 8), expr)
+}
+
+// ExtractColumnsFromExpressions extracts all the columns from a list of expressions.
+func ExtractColumnsFromExpressions(exprs []Expression) []*Column {
+	result := make([]*Column, 0, 8)
+	for _, expr := range exprs {
+		result = extractColumns(result, expr)
+	}
+	return result
+}
+
+func extractColumns(result []*Column, expr Expression) []*Column {
+	switch v := expr.(type) {
+	case *Column:
+		result = append(result, v)
+	case *ScalarFunction:
+		for _, arg := range v.GetArgs() {
+			result = extractColumns(result, arg)
+		}
+	}
+	return result
+}
+
+// ExtractCorColumns extracts the correlated columns from an expression.
+func ExtractCorColumns(expr Expression) []*CorrelatedColumn {
+	switch v := expr.(type) {
+	case *CorrelatedColumn:
+		return []*CorrelatedColumn{v}
+	case *ScalarFunction:
+		var cols []*CorrelatedColumn
+		for _, arg := range v.GetArgs() {
+			cols = append(cols, ExtractCorColumns(arg)...)
+		}
+		return cols
+	}
+	return nil
+}
+
+// IsCorrelated checks whether the expression contains a correlated column.
+func IsCorrelated(expr Expression) bool {
+	return len(ExtractCorColumns(expr)) > 0
+}
+
+// Decorrelate turns the correlated columns whose columns are in the schema into the columns.
+func Decorrelate(expr Expression, schema *Schema) Expression {
+	switch v := expr.(type) {
+	case *CorrelatedColumn:
+		return v.Decorrelate(schema)
+	case *ScalarFunction:
+		args := v.GetArgs()
+		for i, arg := range args {
+			args[i] = Decorrelate(arg, schema)
+		}
+	}
+	return expr
+}
+
+// ExprFromSchema checks whether all the columns of the expression are in the schema.
+func ExprFromSchema(expr Expression, schema *Schema) bool {
+	switch v := expr.(type) {
+	case *Column:
+		return schema.Contains(v)
+	case *ScalarFunction:
+		for _, arg := range v.GetArgs() {
+			if !ExprFromSchema(arg, schema) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ColumnSubstitute substitutes the columns of the schema in the expression with the expressions of the same
+// offsets in newExprs, the functions whose arguments are substituted are built again to be folded.
+func ColumnSubstitute(expr Expression, schema *Schema, newExprs []Expression) Expression {
+	switch v := expr.(type) {
+	case *Column:
+		if idx := schema.ColumnIndex(v); idx != -1 {
+			return newExprs[idx]
+		}
+	case *ScalarFunction:
+		if v.FuncName.L == ast.Cast {
+			newFunc := v.Clone().(*ScalarFunction)
+			args := newFunc.GetArgs()
+			args[0] = ColumnSubstitute(args[0], schema, newExprs)
+			return newFunc
+		}
+		args := v.GetArgs()
+		newArgs := make([]Expression, 0, len(args))
+		substituted := false
+		for _, arg := range args {
+			newArg := ColumnSubstitute(arg, schema, newExprs)
+			substituted = substituted || newArg != arg
+			newArgs = append(newArgs, newArg)
+		}
+		if substituted {
+			return NewFunctionInternal(v.GetCtx(), v.FuncName.L, v.RetType, newArgs...)
+		}
+	}
+	return expr
+}
+
+// EvaluateExprWithNull sets the columns of the schema in the expression to NULL and folds it, which tells
+// whether the expression rejects the rows whose columns of the schema are all NULL.
+func EvaluateExprWithNull(ctx sessionctx.Context, schema *Schema, expr Expression) Expression {
+	nulls := make([]Expression, 0, schema.Len())
+	for range schema.Columns {
+		nulls = append(nulls, NewNull())
+	}
+	return FoldConstant(ColumnSubstitute(expr.Clone(), schema, nulls))
+}
+
+// IsFalseOrNull checks whether the expression is a constant which is false or NULL.
+func IsFalseOrNull(ctx sessionctx.Context, expr Expression) bool {
+	c, ok := expr.(*Constant)
+	if !ok {
+		return false
+	}
+	if c.Value.IsNull() {
+		return true
+	}
+	isTrue, _, err := evalBool(ctx, c, chunk.Row{})
+	return err == nil && !isTrue
+}
+
+// IsTrue checks whether the expression is a constant which is true.
+func IsTrue(ctx sessionctx.Context, expr Expression) bool {
+	c, ok := expr.(*Constant)
+	if !ok || c.Value.IsNull() {
+		return false
+	}
+	isTrue, _, err := evalBool(ctx, c, chunk.Row{})
+	return err == nil && isTrue
+}
+
+// SplitCNFItems splits a CNF expression to the conjunctive items.
+func SplitCNFItems(onExpr Expression) []Expression {
+	return splitNormalFormItems(onExpr, ast.LogicAnd)
+}
+
+// SplitDNFItems splits a DNF expression to the disjunctive items.
+func SplitDNFItems(onExpr Expression) []Expression {
+	return splitNormalFormItems(onExpr, ast.LogicOr)
+}
+
+func splitNormalFormItems(onExpr Expression, funcName string) []Expression {
+	if sf, ok := onExpr.(*ScalarFunction); ok && sf.FuncName.L == funcName {
+		var items []Expression
+		for _, arg := range sf.GetArgs() {
+			items = append(items, splitNormalFormItems(arg, funcName)...)
+		}
+		return items
+	}
+	return []Expression{onExpr}
+}
+
+// ComposeCNFCondition composes the conjunctive items to a CNF expression.
+func ComposeCNFCondition(ctx sessionctx.Context, conditions ...Expression) Expression {
+	return composeConditionWithBinaryOp(ctx, conditions, ast.LogicAnd)
+}
+
+// ComposeDNFCondition composes the disjunctive items to a DNF expression.
+func ComposeDNFCondition(ctx sessionctx.Context, conditions ...Expression) Expression {
+	return composeConditionWithBinaryOp(ctx, conditions, ast.LogicOr)
+}
+
+func composeConditionWithBinaryOp(ctx sessionctx.Context, conditions []Expression, funcName string) Expression {
+	switch len(conditions) {
+	case 0:
+		return nil
+	case 1:
+		return conditions[0]
+	}
+	mid := len(conditions) / 2
+	return NewFunctionInternal(ctx, funcName, types.NewFieldType(mysql.TypeTiny),
+		composeConditionWithBinaryOp(ctx, conditions[:mid], funcName),
+		composeConditionWithBinaryOp(ctx, conditions[mid:], funcName))
+}
+
+// NewNotFunction builds NOT(expr).
+func NewNotFunction(ctx sessionctx.Context, expr Expression) Expression {
+	return NewFunctionInternal(ctx, ast.UnaryNot, types.NewFieldType(mysql.TypeTiny), expr)
+}
@@ -4,7 +4,12 @@ go 1.14
 
 require (
 	github.com/google/gops v0.3.14
+	github.com/pingcap/errors v0.11.5-0.20190809092503-95897b64e011
 	github.com/pingcap/parser v0.0.0-20200623164729-3a18f1e5dceb
 	github.com/pingcap/tidb v1.1.0-beta.0.20200630082100-328b6d0a955c
 	github.com/shirou/gopsutil v3.20.11+incompatible // indirect
+	golang.org/x/text v0.3.2
 )
+
+// The parser is forked to support more charsets, see third_party/parser/README.md.
+replace github.com/pingcap/parser => ./third_party/parser
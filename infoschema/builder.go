@@ -0,0 +1,112 @@
+package infoschema
+
+import (
+	"github.com/pingcap/parser/model"
+	"grant-db/kv"
+	"grant-db/meta"
+	"grant-db/meta/autoid"
+	"grant-db/table"
+	"grant-db/table/tables"
+)
+
+// Builder builds a new InfoSchema.
+type Builder struct {
+	is    *infoSchema
+	store kv.Storage
+	old   InfoSchema
+}
+
+// NewBuilder creates a new Builder, the tables take over the allocators of the tables in old with the same
+// IDs to keep their cached IDs, and the other ones get new allocators of store. Both store and old may be nil.
+func NewBuilder(store kv.Storage, old InfoSchema) *Builder {
+	return &Builder{
+		store: store,
+		old:   old,
+		is: &infoSchema{
+			schemaMap:  map[string]*schemaTables{},
+			tableByID:  map[int64]table.Table{},
+			dbOfTable:  map[int64]int64{},
+			schemaByID: map[int64]*model.DBInfo{},
+		},
+	}
+}
+
+// InitWithDBInfos initializes an empty new InfoSchema with a slice of DBInfo and schema version.
+func (b *Builder) InitWithDBInfos(dbInfos []*model.DBInfo, schemaVersion int64) (*Builder, error) {
+	b.is.schemaMetaVersion = schemaVersion
+	for _, di := range dbInfos {
+		if err := b.createSchemaTablesForDB(di); err != nil {
+			return nil, err
+		}
+	}
+	return b, nil
+}
+
+// InitWithMeta loads all the schemas visible to m.
+func (b *Builder) InitWithMeta(m *meta.Meta) (*Builder, error) {
+	schemaVersion, err := m.GetSchemaVersion()
+	if err != nil {
+		return nil, err
+	}
+	dbInfos, err := m.ListDatabases()
+	if err != nil {
+		return nil, err
+	}
+	publicDBs := make([]*model.DBInfo, 0, len(dbInfos))
+	for _, di := range dbInfos {
+		if di.State != model.StatePublic {
+			// schema is not public, can't be used outside.
+			continue
+		}
+		publicDBs = append(publicDBs, di)
+		tables, err := m.ListTables(di.ID)
+		if err != nil {
+			return nil, err
+		}
+		di.Tables = make([]*model.TableInfo, 0, len(tables))
+		for _, tbl := range tables {
+			if tbl.State != model.StatePublic {
+				// schema is not public, can't be used outside.
+				continue
+			}
+			di.Tables = append(di.Tables, tbl)
+		}
+	}
+	return b.InitWithDBInfos(publicDBs, schemaVersion)
+}
+
+func (b *Builder) createSchemaTablesForDB(di *model.DBInfo) error {
+	schTbls := &schemaTables{
+		dbInfo: di,
+		tables: make(map[string]table.Table, len(di.Tables)),
+	}
+	b.is.schemaMap[di.Name.L] = schTbls
+	b.is.schemaByID[di.ID] = di
+	for _, t := range di.Tables {
+		tbl, err := tables.TableFromMeta(b.allocator(t.ID), t)
+		if err != nil {
+			return err
+		}
+		schTbls.tables[t.Name.L] = tbl
+		b.is.tableByID[t.ID] = tbl
+		b.is.dbOfTable[t.ID] = di.ID
+	}
+	return nil
+}
+
+func (b *Builder) allocator(tableID int64) autoid.Allocator {
+	if b.old != nil {
+		if tbl, ok := b.old.TableByID(tableID); ok && tbl.Allocator() != nil {
+			return tbl.Allocator()
+		}
+	}
+	if b.store == nil {
+		return nil
+	}
+	return autoid.NewAllocator(b.store, tableID)
+}
+
+// Build builds and returns the built infoschema.
+func (b *Builder) Build() InfoSchema {
+	return b.is
+}
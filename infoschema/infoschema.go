@@ -0,0 +1,143 @@
+package infoschema
+
+import (
+	"sort"
+
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
+	"grant-db/table"
+)
+
+var (
+	// ErrDatabaseExists returns for database already exists.
+	ErrDatabaseExists = terror.ClassSchema.New(mysql.ErrDBCreateExists, mysql.MySQLErrName[mysql.ErrDBCreateExists])
+	// ErrDatabaseNotExists returns for database not exists.
+	ErrDatabaseNotExists = terror.ClassSchema.New(mysql.ErrBadDB, mysql.MySQLErrName[mysql.ErrBadDB])
+	// ErrTableExists returns for table already exists.
+	ErrTableExists = terror.ClassSchema.New(mysql.ErrTableExists, mysql.MySQLErrName[mysql.ErrTableExists])
+	// ErrTableNotExists returns for table not exists.
+	ErrTableNotExists = terror.ClassSchema.New(mysql.ErrNoSuchTable, mysql.MySQLErrName[mysql.ErrNoSuchTable])
+	// ErrColumnNotExists returns for column not exists.
+	ErrColumnNotExists = terror.ClassSchema.New(mysql.ErrBadField, mysql.MySQLErrName[mysql.ErrBadField])
+	// ErrColumnExists returns for column already exists.
+	ErrColumnExists = terror.ClassSchema.New(mysql.ErrDupFieldName, mysql.MySQLErrName[mysql.ErrDupFieldName])
+	// ErrIndexExists returns for index already exists.
+	ErrIndexExists = terror.ClassSchema.New(mysql.ErrDupKeyName, mysql.MySQLErrName[mysql.ErrDupKeyName])
+)
+
+// InfoSchema is the interface used to retrieve the schema information.
+// It works as a in memory cache and doesn't handle any schema change.
+// InfoSchema is read-only, and the returned value is a copy.
+type InfoSchema interface {
+	SchemaByName(schema model.CIStr) (*model.DBInfo, bool)
+	SchemaExists(schema model.CIStr) bool
+	TableByName(schema, table model.CIStr) (table.Table, error)
+	TableExists(schema, table model.CIStr) bool
+	SchemaByID(id int64) (*model.DBInfo, bool)
+	SchemaByTable(tableInfo *model.TableInfo) (*model.DBInfo, bool)
+	TableByID(id int64) (table.Table, bool)
+	AllSchemas() []*model.DBInfo
+	AllSchemaNames() []string
+	SchemaTables(schema model.CIStr) []table.Table
+	SchemaMetaVersion() int64
+}
+
+type schemaTables struct {
+	dbInfo *model.DBInfo
+	tables map[string]table.Table
+}
+
+type infoSchema struct {
+	schemaMap map[string]*schemaTables
+	// tableByID maps table ID to the table and the ID of the database it belongs to.
+	tableByID  map[int64]table.Table
+	dbOfTable  map[int64]int64
+	schemaByID map[int64]*model.DBInfo
+
+	// schemaMetaVersion is the version of schema, and we should check version when change schema.
+	schemaMetaVersion int64
+}
+
+func (is *infoSchema) SchemaByName(schema model.CIStr) (val *model.DBInfo, ok bool) {
+	tableNames, ok := is.schemaMap[schema.L]
+	if !ok {
+		return
+	}
+	return tableNames.dbInfo, true
+}
+
+func (is *infoSchema) SchemaMetaVersion() int64 {
+	return is.schemaMetaVersion
+}
+
+func (is *infoSchema) SchemaExists(schema model.CIStr) bool {
+	_, ok := is.schemaMap[schema.L]
+	return ok
+}
+
+func (is *infoSchema) TableByName(schema, table model.CIStr) (t table.Table, err error) {
+	if tbNames, ok := is.schemaMap[schema.L]; ok {
+		if t, ok = tbNames.tables[table.L]; ok {
+			return
+		}
+	}
+	return nil, ErrTableNotExists.GenWithStackByArgs(schema, table)
+}
+
+func (is *infoSchema) TableExists(schema, table model.CIStr) bool {
+	if tbNames, ok := is.schemaMap[schema.L]; ok {
+		if _, ok = tbNames.tables[table.L]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (is *infoSchema) SchemaByID(id int64) (val *model.DBInfo, ok bool) {
+	val, ok = is.schemaByID[id]
+	return
+}
+
+func (is *infoSchema) SchemaByTable(tableInfo *model.TableInfo) (val *model.DBInfo, ok bool) {
+	if tableInfo == nil {
+		return nil, false
+	}
+	dbID, ok := is.dbOfTable[tableInfo.ID]
+	if !ok {
+		return nil, false
+	}
+	return is.SchemaByID(dbID)
+}
+
+func (is *infoSchema) TableByID(id int64) (val table.Table, ok bool) {
+	val, ok = is.tableByID[id]
+	return
+}
+
+func (is *infoSchema) AllSchemas() (schemas []*model.DBInfo) {
+	for _, v := range is.schemaMap {
+		schemas = append(schemas, v.dbInfo)
+	}
+	sort.Slice(schemas, func(i, j int) bool { return schemas[i].Name.L < schemas[j].Name.L })
+	return
+}
+
+func (is *infoSchema) AllSchemaNames() (names []string) {
+	for _, v := range is.AllSchemas() {
+		names = append(names, v.Name.O)
+	}
+	return
+}
+
+func (is *infoSchema) SchemaTables(schema model.CIStr) (tables []table.Table) {
+	schemaTables, ok := is.schemaMap[schema.L]
+	if !ok {
+		return
+	}
+	for _, tbl := range schemaTables.tables {
+		tables = append(tables, tbl)
+	}
+	sort.Slice(tables, func(i, j int) bool { return tables[i].Meta().Name.L < tables[j].Meta().Name.L })
+	return
+}
@@ -0,0 +1,187 @@
+package kv
+
+import (
+	"bufio"
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+	"os"
+)
+
+// commitLog is an append-only file of committed transactions. Every record is
+//  [payload length uint32][crc32 of payload uint32][payload]
+// and the payload is
+//  [commitTS uvarint][mutation count uvarint]{[key length uvarint][key][value length uvarint][value]}...
+// where an empty value stands for a deletion. A record of commitTS 0 deletes all the versions of the keys
+// in a range, its only mutation is the start key and the end key of the range.
+// The log is compacted by rewriting the versions left in the store once it doubles in size.
+type commitLog struct {
+	f    *os.File
+	path string
+	// size is the size of the file, compactedSize is the one after the last compaction.
+	size          int64
+	compactedSize int64
+}
+
+// openCommitLog opens the log at path and replays every complete record with apply and deleteRange,
+// a torn record at the tail left by a crash is truncated.
+func openCommitLog(path string, apply func(commitTS uint64, key, value []byte),
+	deleteRange func(startKey, endKey []byte)) (*commitLog, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	valid, err := replayCommitLog(f, apply, deleteRange)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	if err = f.Truncate(valid); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if _, err = f.Seek(valid, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &commitLog{f: f, path: path, size: valid, compactedSize: valid}, nil
+}
+
+func replayCommitLog(f *os.File, apply func(commitTS uint64, key, value []byte),
+	deleteRange func(startKey, endKey []byte)) (int64, error) {
+	r := bufio.NewReader(f)
+	var offset int64
+	var head [8]byte
+	for {
+		if _, err := io.ReadFull(r, head[:]); err != nil {
+			return offset, nil
+		}
+		payload := make([]byte, binary.BigEndian.Uint32(head[:4]))
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return offset, nil
+		}
+		if crc32.ChecksumIEEE(payload) != binary.BigEndian.Uint32(head[4:]) {
+			return offset, nil
+		}
+		commitTS, n := binary.Uvarint(payload)
+		payload = payload[n:]
+		cnt, n := binary.Uvarint(payload)
+		payload = payload[n:]
+		for i := uint64(0); i < cnt; i++ {
+			var key, value []byte
+			key, payload = readLogBytes(payload)
+			value, payload = readLogBytes(payload)
+			if commitTS == 0 {
+				deleteRange(key, value)
+			} else {
+				apply(commitTS, key, value)
+			}
+		}
+		offset += int64(len(head)) + int64(binary.BigEndian.Uint32(head[:4]))
+	}
+}
+
+func readLogBytes(b []byte) ([]byte, []byte) {
+	l, n := binary.Uvarint(b)
+	b = b[n:]
+	return append([]byte(nil), b[:l]...), b[l:]
+}
+
+func (l *commitLog) append(commitTS uint64, buffer *memDB) error {
+	payload := make([]byte, 0, 64)
+	payload = appendLogUvarint(payload, commitTS)
+	payload = appendLogUvarint(payload, uint64(buffer.Len()))
+	for it := buffer.seek(nil, nil); it.Valid(); it.Next() {
+		payload = appendLogMutation(payload, it.Key(), it.Value().([]byte))
+	}
+	return l.write(makeLogRecord(payload))
+}
+
+// appendDeleteRange appends the record which deletes the keys in [startKey, endKey).
+func (l *commitLog) appendDeleteRange(startKey, endKey []byte) error {
+	payload := appendLogUvarint(nil, 0)
+	payload = appendLogUvarint(payload, 1)
+	payload = appendLogMutation(payload, startKey, endKey)
+	return l.write(makeLogRecord(payload))
+}
+
+func (l *commitLog) write(record []byte) error {
+	if _, err := l.f.Write(record); err != nil {
+		return err
+	}
+	l.size += int64(len(record))
+	return l.f.Sync()
+}
+
+// needCompact checks whether the log doubles in size since the last compaction.
+func (l *commitLog) needCompact() bool {
+	return l.size > 2*l.compactedSize
+}
+
+// compact rewrites the log with the versions of data, every version is a record of its own. The new log is
+// written to a temporary file which replaces the log at last, so a crash leaves one of them complete.
+func (l *commitLog) compact(data *memDB) error {
+	tmpPath := l.path + ".tmp"
+	f, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_TRUNC|os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+	size, err := writeVersions(f, data)
+	if err == nil {
+		err = os.Rename(tmpPath, l.path)
+	}
+	if err != nil {
+		f.Close()
+		_ = os.Remove(tmpPath)
+		return err
+	}
+	l.f.Close()
+	l.f, l.size, l.compactedSize = f, size, size
+	return nil
+}
+
+// writeVersions writes the versions of data to f and syncs it, it returns the size written.
+func writeVersions(f *os.File, data *memDB) (int64, error) {
+	w := bufio.NewWriter(f)
+	var size int64
+	for it := data.seek(nil, nil); it.Valid(); it.Next() {
+		for _, v := range it.Value().(*mvccEntry).versions {
+			payload := appendLogUvarint(nil, v.commitTS)
+			payload = appendLogUvarint(payload, 1)
+			payload = appendLogMutation(payload, it.Key(), v.value)
+			record := makeLogRecord(payload)
+			if _, err := w.Write(record); err != nil {
+				return 0, err
+			}
+			size += int64(len(record))
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return 0, err
+	}
+	return size, f.Sync()
+}
+
+func makeLogRecord(payload []byte) []byte {
+	record := make([]byte, 8, 8+len(payload))
+	binary.BigEndian.PutUint32(record[:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(record[4:], crc32.ChecksumIEEE(payload))
+	return append(record, payload...)
+}
+
+func appendLogMutation(b []byte, key, value []byte) []byte {
+	b = appendLogUvarint(b, uint64(len(key)))
+	b = append(b, key...)
+	b = appendLogUvarint(b, uint64(len(value)))
+	return append(b, value...)
+}
+
+func appendLogUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(buf[:], v)
+	return append(b, buf[:n]...)
+}
+
+func (l *commitLog) close() error {
+	return l.f.Close()
+}
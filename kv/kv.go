@@ -1,14 +1,203 @@
 package kv
 
+import (
+	"context"
+	"errors"
+
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
+	"grant-db/oracle"
+)
+
+var (
+	// ErrNotExist is used when try to get an entry with an unexist key from KV store.
+	ErrNotExist = errors.New("key not exist")
+	// ErrWriteConflict is returned when a transaction commits keys modified by
+	// another transaction committed after it started.
+	ErrWriteConflict = errors.New("write conflict")
+	// ErrSnapshotTooOld is returned when a transaction begins at a version older than the GC safe point.
+	ErrSnapshotTooOld = errors.New("snapshot is older than GC safe point")
+	// ErrInvalidTxn is returned when a transaction is used after commit or rollback.
+	ErrInvalidTxn = errors.New("invalid transaction")
+	// ErrCannotSetNilValue is the error when sets an empty value.
+	ErrCannotSetNilValue = errors.New("can not set nil value")
+	// ErrKeyExists returns when key is already exist.
+	ErrKeyExists = terror.ClassKV.New(mysql.ErrDupEntry, mysql.MySQLErrName[mysql.ErrDupEntry])
+)
+
+// IsErrNotFound checks if err is a kind of NotFound error.
+func IsErrNotFound(err error) bool {
+	return errors.Is(err, ErrNotExist)
+}
+
+// IsTxnRetryableError checks if the error could safely retry the transaction.
+func IsTxnRetryableError(err error) bool {
+	return err != nil && errors.Is(err, ErrWriteConflict)
+}
+
+// Version is the wrapper of KV's version.
+type Version struct {
+	Ver uint64
+}
+
+// MaxVersion is the maximum version, notice that it's not a valid version.
+var MaxVersion = Version{Ver: ^uint64(0)}
+
+// Retriever is the interface wraps the basic Get and Seek methods.
+type Retriever interface {
+	// Get gets the value for key k from kv store.
+	// If corresponding kv pair does not exist, it returns nil and ErrNotExist.
+	Get(ctx context.Context, k Key) ([]byte, error)
+	// Iter creates an Iterator positioned on the first entry that k <= entry's key.
+	// If such entry is not found, it returns an invalid Iterator with no error.
+	// It yields only keys that < upperBound. If upperBound is nil, it means the upperBound is unbounded.
+	// The Iterator must be Closed after use.
+	Iter(k Key, upperBound Key) (Iterator, error)
+	// IterReverse creates a reversed Iterator positioned on the first entry which key is less than k.
+	// The returned iterator will iterate from greater key to smaller key.
+	// If k is nil, the returned iterator will be positioned at the last key.
+	IterReverse(k Key) (Iterator, error)
+}
+
+// Mutator is the interface wraps the basic Set and Delete methods.
+type Mutator interface {
+	// Set sets the value for key k as v into kv store.
+	// v must NOT be nil or empty, otherwise it returns ErrCannotSetNilValue.
+	Set(k Key, v []byte) error
+	// Delete removes the entry for key k from kv store.
+	Delete(k Key) error
+}
+
+// RetrieverMutator is the interface that groups Retriever and Mutator interfaces.
+type RetrieverMutator interface {
+	Retriever
+	Mutator
+}
+
+// Iterator is the interface for a iterator on KV store.
+type Iterator interface {
+	Valid() bool
+	Key() Key
+	Value() []byte
+	Next() error
+	Close()
+}
+
+// Transaction defines the interface for operations inside a Transaction.
+// This is not thread safe.
+type Transaction interface {
+	RetrieverMutator
+	// Commit commits the transaction operations to KV store.
+	Commit(context.Context) error
+	// Rollback undoes the transaction operations to KV store.
+	Rollback() error
+	// LockKeys makes the commit fail with a write conflict if any of the keys
+	// is modified by other transactions after the start of this transaction.
+	LockKeys(keys ...Key) error
+	// StartTS returns the transaction start timestamp.
+	StartTS() uint64
+	// CommitTS returns the transaction commit timestamp, it is 0 before a successful commit.
+	CommitTS() uint64
+	// Valid returns if the transaction is valid.
+	// A transaction become invalid after commit or rollback.
+	Valid() bool
+	// Len returns the number of entries in the transaction's buffer.
+	Len() int
+	// IsReadOnly checks if the transaction has only performed read operations.
+	IsReadOnly() bool
+	// Staging begins a staging of the mutations, which is finished by Release to keep its mutations or by
+	// Cleanup to discard them. The stagings are nested, the mutations of a staging include the ones of the
+	// stagings begun after it.
+	Staging() StagingHandle
+	// Release finishes the staging and keeps its mutations, which belong to the outer staging then. The
+	// stagings begun after it are kept.
+	Release(h StagingHandle)
+	// Cleanup finishes the staging and the ones begun after it, and discards their mutations.
+	Cleanup(h StagingHandle)
+	// SetSchemaChecker sets the checker which checks the schema the transaction uses on commit.
+	SetSchemaChecker(checker SchemaChecker)
+}
+
+// SchemaChecker checks whether a transaction can commit with the schema it uses. The check is atomic with
+// the commit, no other transaction commits between them.
+type SchemaChecker interface {
+	// Check returns an error if the transaction can't commit, latest reads the data committed before the
+	// commit. latest is only valid during the check and doesn't support iterating.
+	Check(latest Snapshot) error
+}
+
+// StagingHandle is the handle of a staging of a transaction, it's never 0.
+type StagingHandle int
+
+// Snapshot defines the interface for the snapshot fetched from KV store.
+type Snapshot interface {
+	Retriever
+}
+
 // Storage defines the interface for storage
 type Storage interface {
-	store()
+	// Begin transaction
+	Begin() (Transaction, error)
+	// BeginWithStartTS begins transaction with startTS.
+	BeginWithStartTS(startTS uint64) (Transaction, error)
+	// GetSnapshot gets a snapshot that is able to read any data which data is <= ver.
+	// if ver is MaxVersion or > current max committed version, we will use current version for this snapshot.
+	GetSnapshot(ver Version) Snapshot
+	// CurrentVersion returns current max committed version.
+	CurrentVersion() (Version, error)
+	// GetOracle gets the timestamp oracle related to storage.
+	GetOracle() oracle.Oracle
+	// GC removes the versions which are invisible to any read at or after safePoint, the versions read by
+	// the transactions in progress are kept.
+	GC(ctx context.Context, safePoint uint64) error
+	// DeleteRange removes the keys in [startKey, endKey) with all their versions. It's only used for the keys
+	// which are invisible to any read and never written again, e.g. the data of a table dropped before the
+	// GC safe point.
+	DeleteRange(ctx context.Context, startKey, endKey Key) error
+	// UUID return a unique ID which represents a Storage.
+	UUID() string
+	Close() error
 }
 
-type tempStorage struct {}
-
-func (ts *tempStorage) store(){}
+// RunInNewTxn will run the f in a new transaction environment.
+func RunInNewTxn(store Storage, retryable bool, f func(txn Transaction) error) error {
+	const maxRetryCnt = 10
+	var err error
+	for i := 0; i < maxRetryCnt; i++ {
+		var txn Transaction
+		txn, err = store.Begin()
+		if err != nil {
+			return err
+		}
+		err = f(txn)
+		if err != nil {
+			_ = txn.Rollback()
+			if retryable && IsTxnRetryableError(err) {
+				continue
+			}
+			return err
+		}
+		err = txn.Commit(context.Background())
+		if err == nil {
+			return nil
+		}
+		if retryable && IsTxnRetryableError(err) {
+			continue
+		}
+		return err
+	}
+	return err
+}
 
-func NewStorage() Storage {
-	return &tempStorage{}
-}
\ No newline at end of file
+// NextUntil applies FnKeyCmp to each entry of the iterator until meets some condition.
+// It will stop when fn returns true, or iterator is invalid or an error occurs.
+func NextUntil(it Iterator, fn func(Key) bool) error {
+	var err error
+	for it.Valid() && !fn(it.Key()) {
+		err = it.Next()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
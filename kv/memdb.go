@@ -0,0 +1,184 @@
+package kv
+
+import (
+	"bytes"
+	"math/rand"
+)
+
+const maxHeight = 16
+
+type node struct {
+	key   []byte
+	value interface{}
+	next  []*node
+}
+
+// memDB is an ordered map from byte keys to values implemented as a skiplist,
+// it is not safe for concurrent use.
+type memDB struct {
+	head   *node
+	height int
+	length int
+	rand   *rand.Rand
+}
+
+func newMemDB() *memDB {
+	return &memDB{
+		head:   &node{next: make([]*node, maxHeight)},
+		height: 1,
+		rand:   rand.New(rand.NewSource(0xfeed)),
+	}
+}
+
+func (db *memDB) randomHeight() int {
+	h := 1
+	for h < maxHeight && db.rand.Intn(4) == 0 {
+		h++
+	}
+	return h
+}
+
+// findGE returns the first node whose key >= key, prev is filled with the
+// rightmost node before it at each level if not nil.
+func (db *memDB) findGE(key []byte, prev []*node) *node {
+	x := db.head
+	for level := db.height - 1; level >= 0; level-- {
+		for next := x.next[level]; next != nil && bytes.Compare(next.key, key) < 0; next = x.next[level] {
+			x = next
+		}
+		if prev != nil {
+			prev[level] = x
+		}
+	}
+	return x.next[0]
+}
+
+// findLT returns the last node whose key < key, nil if there is none.
+func (db *memDB) findLT(key []byte) *node {
+	x := db.head
+	for level := db.height - 1; level >= 0; level-- {
+		for next := x.next[level]; next != nil && bytes.Compare(next.key, key) < 0; next = x.next[level] {
+			x = next
+		}
+	}
+	if x == db.head {
+		return nil
+	}
+	return x
+}
+
+// findLast returns the last node, nil if the db is empty.
+func (db *memDB) findLast() *node {
+	x := db.head
+	for level := db.height - 1; level >= 0; level-- {
+		for next := x.next[level]; next != nil; next = x.next[level] {
+			x = next
+		}
+	}
+	if x == db.head {
+		return nil
+	}
+	return x
+}
+
+func (db *memDB) Get(key []byte) (interface{}, bool) {
+	n := db.findGE(key, nil)
+	if n != nil && bytes.Equal(n.key, key) {
+		return n.value, true
+	}
+	return nil, false
+}
+
+func (db *memDB) Put(key []byte, value interface{}) {
+	var prev [maxHeight]*node
+	n := db.findGE(key, prev[:])
+	if n != nil && bytes.Equal(n.key, key) {
+		n.value = value
+		return
+	}
+	h := db.randomHeight()
+	if h > db.height {
+		for level := db.height; level < h; level++ {
+			prev[level] = db.head
+		}
+		db.height = h
+	}
+	n = &node{key: append([]byte(nil), key...), value: value, next: make([]*node, h)}
+	for level := 0; level < h; level++ {
+		n.next[level] = prev[level].next[level]
+		prev[level].next[level] = n
+	}
+	db.length++
+}
+
+func (db *memDB) Remove(key []byte) {
+	var prev [maxHeight]*node
+	n := db.findGE(key, prev[:])
+	if n == nil || !bytes.Equal(n.key, key) {
+		return
+	}
+	for level := 0; level < len(n.next); level++ {
+		prev[level].next[level] = n.next[level]
+	}
+	db.length--
+}
+
+func (db *memDB) Len() int {
+	return db.length
+}
+
+// memDBIter iterates the memDB in ascending or descending order.
+type memDBIter struct {
+	db      *memDB
+	curr    *node
+	reverse bool
+	// upperBound is exclusive, nil means unbounded.
+	upperBound []byte
+}
+
+// seek returns an ascending iterator positioned at the first key >= key,
+// a nil key means the smallest key.
+func (db *memDB) seek(key []byte, upperBound []byte) *memDBIter {
+	it := &memDBIter{db: db, upperBound: upperBound}
+	if key == nil {
+		it.curr = db.head.next[0]
+	} else {
+		it.curr = db.findGE(key, nil)
+	}
+	return it
+}
+
+// seekReverse returns a descending iterator positioned at the last key < key,
+// a nil key means the largest key.
+func (db *memDB) seekReverse(key []byte) *memDBIter {
+	it := &memDBIter{db: db, reverse: true}
+	if key == nil {
+		it.curr = db.findLast()
+	} else {
+		it.curr = db.findLT(key)
+	}
+	return it
+}
+
+func (it *memDBIter) Valid() bool {
+	if it.curr == nil {
+		return false
+	}
+	return it.upperBound == nil || bytes.Compare(it.curr.key, it.upperBound) < 0
+}
+
+func (it *memDBIter) Key() []byte {
+	return it.curr.key
+}
+
+func (it *memDBIter) Value() interface{} {
+	return it.curr.value
+}
+
+func (it *memDBIter) Next() {
+	if it.reverse {
+		it.curr = it.db.findLT(it.curr.key)
+	} else {
+		it.curr = it.curr.next[0]
+	}
+}
@@ -0,0 +1,111 @@
+package kv
+
+import (
+	"bytes"
+	"fmt"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// sortedKeys returns the keys of m in ascending order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// expectedRange returns the keys of m in [start, upperBound) ascending, or the keys < start descending if
+// reverse, a nil start or upperBound is unbounded.
+func expectedRange(m map[string]string, start, upperBound []byte, reverse bool) []string {
+	var res []string
+	for _, k := range sortedKeys(m) {
+		if reverse {
+			if start == nil || k < string(start) {
+				res = append([]string{k}, res...)
+			}
+			continue
+		}
+		if (start == nil || k >= string(start)) && (upperBound == nil || k < string(upperBound)) {
+			res = append(res, k)
+		}
+	}
+	return res
+}
+
+func randKey(r *rand.Rand) []byte {
+	return []byte(fmt.Sprintf("k%03d", r.Intn(200)))
+}
+
+// randBound returns a random key or nil, which is unbounded.
+func randBound(r *rand.Rand) []byte {
+	if r.Intn(5) == 0 {
+		return nil
+	}
+	return randKey(r)
+}
+
+func TestMemDB(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	db := newMemDB()
+	model := make(map[string]string)
+	for i := 0; i < 2000; i++ {
+		k := randKey(r)
+		if r.Intn(3) == 0 {
+			db.Remove(k)
+			delete(model, string(k))
+		} else {
+			v := fmt.Sprintf("v%d", i)
+			db.Put(k, v)
+			model[string(k)] = v
+		}
+		if db.Len() != len(model) {
+			t.Fatalf("the length is %d, expected %d", db.Len(), len(model))
+		}
+	}
+	for k, v := range model {
+		if got, ok := db.Get([]byte(k)); !ok || got.(string) != v {
+			t.Fatalf("Get(%q) = %v, %v, expected %q", k, got, ok, v)
+		}
+	}
+	if _, ok := db.Get([]byte("absent")); ok {
+		t.Fatal("an absent key is found")
+	}
+	for i := 0; i < 500; i++ {
+		start, upperBound := randBound(r), randBound(r)
+		var got []string
+		for it := db.seek(start, upperBound); it.Valid(); it.Next() {
+			if v := it.Value().(string); v != model[string(it.Key())] {
+				t.Fatalf("the value of %q is %q, expected %q", it.Key(), v, model[string(it.Key())])
+			}
+			got = append(got, string(it.Key()))
+		}
+		if expected := expectedRange(model, start, upperBound, false); fmt.Sprint(got) != fmt.Sprint(expected) {
+			t.Fatalf("seek(%q, %q) = %v, expected %v", start, upperBound, got, expected)
+		}
+		got = got[:0]
+		for it := db.seekReverse(start); it.Valid(); it.Next() {
+			got = append(got, string(it.Key()))
+		}
+		if expected := expectedRange(model, start, nil, true); fmt.Sprint(got) != fmt.Sprint(expected) {
+			t.Fatalf("seekReverse(%q) = %v, expected %v", start, got, expected)
+		}
+	}
+}
+
+func TestMemDBKeyCopied(t *testing.T) {
+	db := newMemDB()
+	key := []byte("a")
+	db.Put(key, 1)
+	key[0] = 'b'
+	if _, ok := db.Get([]byte("a")); !ok {
+		t.Fatal("the key is changed with the caller's buffer")
+	}
+	it := db.seek(nil, nil)
+	if !it.Valid() || !bytes.Equal(it.Key(), []byte("a")) {
+		t.Fatalf("the key is %q, expected \"a\"", it.Key())
+	}
+}
@@ -0,0 +1,419 @@
+package kv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"grant-db/oracle"
+)
+
+// gcBatchSize is the number of keys GC prunes while holding the lock of the store.
+const gcBatchSize = 1024
+
+// mvccVersion is one committed version of a key, a nil value means the key was deleted.
+type mvccVersion struct {
+	commitTS uint64
+	value    []byte
+}
+
+// mvccEntry holds all the versions of a key ordered by commitTS ascending, a new version is appended at the
+// tail and the reads search from the tail.
+type mvccEntry struct {
+	versions []mvccVersion
+}
+
+// get returns the newest version visible to ts.
+func (e *mvccEntry) get(ts uint64) ([]byte, bool) {
+	for i := len(e.versions) - 1; i >= 0; i-- {
+		if v := e.versions[i]; v.commitTS <= ts {
+			return v.value, v.value != nil
+		}
+	}
+	return nil, false
+}
+
+// latestCommitTS returns the commitTS of the newest version.
+func (e *mvccEntry) latestCommitTS() uint64 {
+	return e.versions[len(e.versions)-1].commitTS
+}
+
+// prune removes the versions which are invisible to any read at or after safePoint, they're the ones older
+// than the newest version no later than safePoint, and that version too if it's a deletion. It returns
+// whether no version is left.
+func (e *mvccEntry) prune(safePoint uint64) bool {
+	i := len(e.versions) - 1
+	for i >= 0 && e.versions[i].commitTS > safePoint {
+		i--
+	}
+	if i < 0 {
+		return false
+	}
+	if e.versions[i].value == nil {
+		i++
+	}
+	if i > 0 {
+		// Copies the versions left so the pruned ones are released.
+		e.versions = append([]mvccVersion(nil), e.versions[i:]...)
+	}
+	return len(e.versions) == 0
+}
+
+// memStore is a multi-version KV store which keeps all the data in memory,
+// committed mutations are appended to a log under the data directory and
+// replayed when the store is opened again.
+type memStore struct {
+	mu     sync.RWMutex
+	data   *memDB
+	oracle oracle.Oracle
+	log    *commitLog
+	uuid   string
+
+	// activeMu protects active and safePoint.
+	activeMu sync.Mutex
+	// active counts the transactions in progress by their startTS.
+	active map[uint64]int
+	// safePoint is the effective GC safe point, the versions invisible to any read at or after it are
+	// pruned. It's never later than the startTS of a transaction in progress.
+	safePoint uint64
+}
+
+// NewStorage creates the storage, the data directory is path and
+// an empty path means nothing is persisted.
+func NewStorage(path string) (Storage, error) {
+	var persister oracle.Persister
+	s := &memStore{
+		data:   newMemDB(),
+		uuid:   fmt.Sprintf("memstore-%d", time.Now().UnixNano()),
+		active: make(map[uint64]int),
+	}
+	if path != "" {
+		var err error
+		persister, err = oracle.NewFilePersister(filepath.Join(path, "tso"))
+		if err != nil {
+			return nil, err
+		}
+		s.log, err = openCommitLog(filepath.Join(path, "kv.log"), s.apply, s.deleteRange)
+		if err != nil {
+			return nil, err
+		}
+		s.uuid = "memstore-" + path
+	}
+	o, err := oracle.NewLocalOracle(persister)
+	if err != nil {
+		return nil, err
+	}
+	s.oracle = o
+	return s, nil
+}
+
+// Begin allocates the startTS and registers the transaction at once, so GC never prunes the versions it reads.
+func (s *memStore) Begin() (Transaction, error) {
+	s.activeMu.Lock()
+	defer s.activeMu.Unlock()
+	startTS, err := s.oracle.GetTimestamp(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return s.beginLocked(startTS)
+}
+
+func (s *memStore) BeginWithStartTS(startTS uint64) (Transaction, error) {
+	s.activeMu.Lock()
+	defer s.activeMu.Unlock()
+	return s.beginLocked(startTS)
+}
+
+func (s *memStore) beginLocked(startTS uint64) (Transaction, error) {
+	if startTS < s.safePoint {
+		return nil, fmt.Errorf("%w, startTS=%d, safePoint=%d", ErrSnapshotTooOld, startTS, s.safePoint)
+	}
+	s.active[startTS]++
+	return newMemTxn(s, startTS), nil
+}
+
+// finishTxn unregisters the transaction of startTS once it's committed or rolled back.
+func (s *memStore) finishTxn(startTS uint64) {
+	s.activeMu.Lock()
+	if s.active[startTS]--; s.active[startTS] == 0 {
+		delete(s.active, startTS)
+	}
+	s.activeMu.Unlock()
+}
+
+// GC prunes the versions invisible to any read at or after safePoint, which is moved back to the startTS
+// of the oldest transaction in progress. The keys are pruned in batches, the reads and writes go on between them.
+// The commit log is compacted at last if it doubles in size.
+func (s *memStore) GC(ctx context.Context, safePoint uint64) error {
+	s.activeMu.Lock()
+	for startTS := range s.active {
+		if startTS < safePoint {
+			safePoint = startTS
+		}
+	}
+	if safePoint <= s.safePoint {
+		s.activeMu.Unlock()
+		return nil
+	}
+	s.safePoint = safePoint
+	s.activeMu.Unlock()
+
+	s.mu.RLock()
+	it := s.data.seek(nil, nil)
+	s.mu.RUnlock()
+
+	for done := false; !done; {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		s.mu.Lock()
+		for i := 0; i < gcBatchSize && it.Valid(); i++ {
+			if it.Value().(*mvccEntry).prune(safePoint) {
+				s.data.Remove(it.Key())
+			}
+			it.Next()
+		}
+		done = !it.Valid()
+		s.mu.Unlock()
+	}
+	return s.compactLog()
+}
+
+// compactLog compacts the commit log if it doubles in size, the commits wait for it but the reads don't.
+func (s *memStore) compactLog() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.log == nil || !s.log.needCompact() {
+		return nil
+	}
+	return s.log.compact(s.data)
+}
+
+// DeleteRange removes the keys in [startKey, endKey) with all their versions, the keys are removed in batches.
+func (s *memStore) DeleteRange(ctx context.Context, startKey, endKey Key) error {
+	s.mu.Lock()
+	if s.log != nil {
+		if err := s.log.appendDeleteRange(startKey, endKey); err != nil {
+			s.mu.Unlock()
+			return err
+		}
+	}
+	it := s.data.seek(startKey, endKey)
+	s.mu.Unlock()
+
+	for done := false; !done; {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		s.mu.Lock()
+		for i := 0; i < gcBatchSize && it.Valid(); i++ {
+			key := it.Key()
+			it.Next()
+			s.data.Remove(key)
+		}
+		done = !it.Valid()
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+// deleteRange removes the keys in [startKey, endKey) when the commit log is replayed.
+func (s *memStore) deleteRange(startKey, endKey []byte) {
+	for it := s.data.seek(startKey, endKey); it.Valid(); {
+		key := it.Key()
+		it.Next()
+		s.data.Remove(key)
+	}
+}
+
+func (s *memStore) GetSnapshot(ver Version) Snapshot {
+	return &memSnapshot{store: s, ts: ver.Ver}
+}
+
+func (s *memStore) CurrentVersion() (Version, error) {
+	ver, err := s.oracle.GetTimestamp(context.Background())
+	if err != nil {
+		return Version{}, err
+	}
+	return Version{Ver: ver}, nil
+}
+
+func (s *memStore) GetOracle() oracle.Oracle {
+	return s.oracle
+}
+
+func (s *memStore) UUID() string {
+	return s.uuid
+}
+
+func (s *memStore) Close() error {
+	s.oracle.Close()
+	if s.log != nil {
+		return s.log.close()
+	}
+	return nil
+}
+
+// commit checks the write conflicts of the transaction's mutations and locked keys, and the schema by checker
+// if it's not nil, then applies the mutations with a new commitTS.
+func (s *memStore) commit(startTS uint64, buffer *memDB, lockKeys map[string]struct{}, checker SchemaChecker) (
+	uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for it := buffer.seek(nil, nil); it.Valid(); it.Next() {
+		if err := s.checkConflict(startTS, it.Key()); err != nil {
+			return 0, err
+		}
+	}
+	for k := range lockKeys {
+		if err := s.checkConflict(startTS, []byte(k)); err != nil {
+			return 0, err
+		}
+	}
+	if buffer.Len() == 0 {
+		return startTS, nil
+	}
+	if checker != nil {
+		if err := checker.Check(&latestSnapshot{store: s}); err != nil {
+			return 0, err
+		}
+	}
+	commitTS, err := s.oracle.GetTimestamp(context.Background())
+	if err != nil {
+		return 0, err
+	}
+	if s.log != nil {
+		if err := s.log.append(commitTS, buffer); err != nil {
+			return 0, err
+		}
+	}
+	for it := buffer.seek(nil, nil); it.Valid(); it.Next() {
+		s.apply(commitTS, it.Key(), it.Value().([]byte))
+	}
+	return commitTS, nil
+}
+
+func (s *memStore) checkConflict(startTS uint64, key []byte) error {
+	if e, ok := s.data.Get(key); ok {
+		if latest := e.(*mvccEntry).latestCommitTS(); latest > startTS {
+			return fmt.Errorf("%w, txnStartTS=%d, conflictCommitTS=%d, key=%s",
+				ErrWriteConflict, startTS, latest, Key(key))
+		}
+	}
+	return nil
+}
+
+// apply adds a new version of key, an empty value stands for a deletion.
+func (s *memStore) apply(commitTS uint64, key []byte, value []byte) {
+	if len(value) == 0 {
+		value = nil
+	}
+	var entry *mvccEntry
+	if e, ok := s.data.Get(key); ok {
+		entry = e.(*mvccEntry)
+	} else {
+		if value == nil {
+			return
+		}
+		entry = &mvccEntry{}
+		s.data.Put(key, entry)
+	}
+	entry.versions = append(entry.versions, mvccVersion{commitTS: commitTS, value: value})
+}
+
+// latestSnapshot reads the newest versions while the lock of the store is held by the commit.
+type latestSnapshot struct {
+	store *memStore
+}
+
+func (s *latestSnapshot) Get(ctx context.Context, k Key) ([]byte, error) {
+	if e, ok := s.store.data.Get(k); ok {
+		if v, ok := e.(*mvccEntry).get(MaxVersion.Ver); ok {
+			return v, nil
+		}
+	}
+	return nil, ErrNotExist
+}
+
+func (s *latestSnapshot) Iter(k Key, upperBound Key) (Iterator, error) {
+	return nil, errors.New("iterating the latest snapshot is not supported")
+}
+
+func (s *latestSnapshot) IterReverse(k Key) (Iterator, error) {
+	return nil, errors.New("iterating the latest snapshot is not supported")
+}
+
+type memSnapshot struct {
+	store *memStore
+	ts    uint64
+}
+
+func (s *memSnapshot) Get(ctx context.Context, k Key) ([]byte, error) {
+	s.store.mu.RLock()
+	defer s.store.mu.RUnlock()
+	if e, ok := s.store.data.Get(k); ok {
+		if v, ok := e.(*mvccEntry).get(s.ts); ok {
+			return v, nil
+		}
+	}
+	return nil, ErrNotExist
+}
+
+func (s *memSnapshot) Iter(k Key, upperBound Key) (Iterator, error) {
+	s.store.mu.RLock()
+	it := &snapshotIter{snap: s, it: s.store.data.seek(k, upperBound)}
+	s.store.mu.RUnlock()
+	return it, it.Next()
+}
+
+func (s *memSnapshot) IterReverse(k Key) (Iterator, error) {
+	s.store.mu.RLock()
+	it := &snapshotIter{snap: s, it: s.store.data.seekReverse(k)}
+	s.store.mu.RUnlock()
+	return it, it.Next()
+}
+
+// snapshotIter iterates the keys visible to a snapshot.
+type snapshotIter struct {
+	snap  *memSnapshot
+	it    *memDBIter
+	key   Key
+	value []byte
+	valid bool
+}
+
+func (it *snapshotIter) Valid() bool {
+	return it.valid
+}
+
+func (it *snapshotIter) Key() Key {
+	return it.key
+}
+
+func (it *snapshotIter) Value() []byte {
+	return it.value
+}
+
+// Next moves to the next key which has a version visible to the snapshot.
+func (it *snapshotIter) Next() error {
+	it.snap.store.mu.RLock()
+	defer it.snap.store.mu.RUnlock()
+	if it.valid {
+		it.it.Next()
+	}
+	for ; it.it.Valid(); it.it.Next() {
+		if v, ok := it.it.Value().(*mvccEntry).get(it.snap.ts); ok {
+			it.key, it.value, it.valid = it.it.Key(), v, true
+			return nil
+		}
+	}
+	it.valid = false
+	return nil
+}
+
+func (it *snapshotIter) Close() {
+	it.valid = false
+}
@@ -0,0 +1,371 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+)
+
+// memTxn is an optimistic transaction of memStore, mutations are buffered
+// in memory and checked for write conflicts on commit.
+type memTxn struct {
+	store    *memStore
+	snapshot *memSnapshot
+	// buffer maps keys to values, an empty value stands for a deletion.
+	buffer *memDB
+	// lockKeys are checked for write conflicts on commit but not written.
+	lockKeys map[string]struct{}
+	// stages are the unfinished stagings in the order they're begun.
+	stages []stage
+	// lastStaging is the handle of the last begun staging.
+	lastStaging StagingHandle
+	// schemaChecker checks the schema on commit if it's not nil.
+	schemaChecker SchemaChecker
+	startTS       uint64
+	commitTS      uint64
+	valid         bool
+}
+
+func newMemTxn(store *memStore, startTS uint64) *memTxn {
+	return &memTxn{
+		store:    store,
+		snapshot: &memSnapshot{store: store, ts: startTS},
+		buffer:   newMemDB(),
+		startTS:  startTS,
+		valid:    true,
+	}
+}
+
+func (txn *memTxn) Get(ctx context.Context, k Key) ([]byte, error) {
+	if v, ok := txn.buffer.Get(k); ok {
+		if len(v.([]byte)) == 0 {
+			return nil, ErrNotExist
+		}
+		return v.([]byte), nil
+	}
+	return txn.snapshot.Get(ctx, k)
+}
+
+func (txn *memTxn) Set(k Key, v []byte) error {
+	if len(v) == 0 {
+		return ErrCannotSetNilValue
+	}
+	txn.saveUndo(k)
+	txn.buffer.Put(k, append([]byte(nil), v...))
+	return nil
+}
+
+func (txn *memTxn) Delete(k Key) error {
+	txn.saveUndo(k)
+	txn.buffer.Put(k, []byte{})
+	return nil
+}
+
+// undoEntry is the value of a key in the buffer before a staging, exists is false if the key wasn't in it.
+type undoEntry struct {
+	value  []byte
+	exists bool
+}
+
+// stage is a staging, undo is the log of the keys written in it, which restores the buffer to the one before it.
+type stage struct {
+	handle StagingHandle
+	undo   map[string]undoEntry
+}
+
+// saveUndo saves the value of the key in the buffer to the undo log of the last staging before the key is
+// first written in the staging.
+func (txn *memTxn) saveUndo(k Key) {
+	if len(txn.stages) == 0 {
+		return
+	}
+	undo := txn.stages[len(txn.stages)-1].undo
+	if _, ok := undo[string(k)]; ok {
+		return
+	}
+	v, ok := txn.buffer.Get(k)
+	if ok {
+		undo[string(k)] = undoEntry{value: v.([]byte), exists: true}
+	} else {
+		undo[string(k)] = undoEntry{}
+	}
+}
+
+// stageIndex returns the index of the staging in stages, or -1 if it's finished.
+func (txn *memTxn) stageIndex(h StagingHandle) int {
+	for i, st := range txn.stages {
+		if st.handle == h {
+			return i
+		}
+	}
+	return -1
+}
+
+func (txn *memTxn) Staging() StagingHandle {
+	txn.lastStaging++
+	txn.stages = append(txn.stages, stage{handle: txn.lastStaging, undo: make(map[string]undoEntry)})
+	return txn.lastStaging
+}
+
+// Release merges the undo log of the staging into the outer staging, in which the values of the keys before
+// the outer staging are kept.
+func (txn *memTxn) Release(h StagingHandle) {
+	i := txn.stageIndex(h)
+	if i < 0 {
+		return
+	}
+	if i > 0 {
+		outer := txn.stages[i-1].undo
+		for k, entry := range txn.stages[i].undo {
+			if _, ok := outer[k]; !ok {
+				outer[k] = entry
+			}
+		}
+	}
+	txn.stages = append(txn.stages[:i], txn.stages[i+1:]...)
+}
+
+// Cleanup restores the buffer by the undo logs of the stagings from the last one, the locked keys are kept.
+func (txn *memTxn) Cleanup(h StagingHandle) {
+	i := txn.stageIndex(h)
+	if i < 0 {
+		return
+	}
+	for len(txn.stages) > i {
+		for k, entry := range txn.stages[len(txn.stages)-1].undo {
+			if entry.exists {
+				txn.buffer.Put([]byte(k), entry.value)
+			} else {
+				txn.buffer.Remove([]byte(k))
+			}
+		}
+		txn.stages = txn.stages[:len(txn.stages)-1]
+	}
+}
+
+func (txn *memTxn) LockKeys(keys ...Key) error {
+	if txn.lockKeys == nil {
+		txn.lockKeys = make(map[string]struct{}, len(keys))
+	}
+	for _, k := range keys {
+		txn.lockKeys[string(k)] = struct{}{}
+	}
+	return nil
+}
+
+func (txn *memTxn) Iter(k Key, upperBound Key) (Iterator, error) {
+	snapIt, err := txn.snapshot.Iter(k, upperBound)
+	if err != nil {
+		return nil, err
+	}
+	return newUnionIter(&bufferIter{it: txn.buffer.seek(k, upperBound)}, snapIt, false)
+}
+
+func (txn *memTxn) IterReverse(k Key) (Iterator, error) {
+	snapIt, err := txn.snapshot.IterReverse(k)
+	if err != nil {
+		return nil, err
+	}
+	return newUnionIter(&bufferIter{it: txn.buffer.seekReverse(k)}, snapIt, true)
+}
+
+func (txn *memTxn) Commit(ctx context.Context) error {
+	if !txn.valid {
+		return ErrInvalidTxn
+	}
+	txn.valid = false
+	defer txn.store.finishTxn(txn.startTS)
+	if txn.buffer.Len() == 0 && len(txn.lockKeys) == 0 {
+		return nil
+	}
+	commitTS, err := txn.store.commit(txn.startTS, txn.buffer, txn.lockKeys, txn.schemaChecker)
+	if err != nil {
+		return err
+	}
+	txn.commitTS = commitTS
+	return nil
+}
+
+func (txn *memTxn) Rollback() error {
+	if !txn.valid {
+		return ErrInvalidTxn
+	}
+	txn.valid = false
+	txn.store.finishTxn(txn.startTS)
+	return nil
+}
+
+func (txn *memTxn) SetSchemaChecker(checker SchemaChecker) {
+	txn.schemaChecker = checker
+}
+
+func (txn *memTxn) StartTS() uint64 {
+	return txn.startTS
+}
+
+func (txn *memTxn) CommitTS() uint64 {
+	return txn.commitTS
+}
+
+func (txn *memTxn) Valid() bool {
+	return txn.valid
+}
+
+func (txn *memTxn) Len() int {
+	return txn.buffer.Len()
+}
+
+func (txn *memTxn) IsReadOnly() bool {
+	return txn.buffer.Len() == 0
+}
+
+// bufferIter adapts memDBIter to Iterator, deletions are kept as empty values.
+type bufferIter struct {
+	it *memDBIter
+}
+
+func (it *bufferIter) Valid() bool   { return it.it.Valid() }
+func (it *bufferIter) Key() Key      { return it.it.Key() }
+func (it *bufferIter) Value() []byte { return it.it.Value().([]byte) }
+func (it *bufferIter) Next() error   { it.it.Next(); return nil }
+func (it *bufferIter) Close()        {}
+
+// unionIter merges the transaction buffer with the snapshot, the buffer takes
+// precedence on equal keys and deleted keys are skipped.
+type unionIter struct {
+	dirtyIt    Iterator
+	snapshotIt Iterator
+	// isValid is true when the current position is valid.
+	isValid bool
+	// curIsDirty is true when the current key comes from the buffer.
+	curIsDirty bool
+	reverse    bool
+}
+
+func newUnionIter(dirtyIt Iterator, snapshotIt Iterator, reverse bool) (*unionIter, error) {
+	it := &unionIter{
+		dirtyIt:    dirtyIt,
+		snapshotIt: snapshotIt,
+		reverse:    reverse,
+	}
+	return it, it.updateCur()
+}
+
+func (iter *unionIter) dirtyNext() error {
+	return iter.dirtyIt.Next()
+}
+
+func (iter *unionIter) snapshotNext() error {
+	return iter.snapshotIt.Next()
+}
+
+// updateCur positions the iterator on the next valid key.
+func (iter *unionIter) updateCur() error {
+	iter.isValid = true
+	for {
+		if !iter.dirtyIt.Valid() && !iter.snapshotIt.Valid() {
+			iter.isValid = false
+			break
+		}
+
+		if !iter.dirtyIt.Valid() {
+			iter.curIsDirty = false
+			break
+		}
+
+		if !iter.snapshotIt.Valid() {
+			iter.curIsDirty = true
+			// if delete it
+			if len(iter.dirtyIt.Value()) == 0 {
+				if err := iter.dirtyNext(); err != nil {
+					return err
+				}
+				continue
+			}
+			break
+		}
+
+		// both valid
+		cmp := bytes.Compare(iter.dirtyIt.Key(), iter.snapshotIt.Key())
+		if iter.reverse {
+			cmp = -cmp
+		}
+		// if equal, means both have value
+		if cmp == 0 {
+			if len(iter.dirtyIt.Value()) == 0 {
+				// snapshot has a record, but txn says we have deleted it
+				// just go next
+				if err := iter.dirtyNext(); err != nil {
+					return err
+				}
+				if err := iter.snapshotNext(); err != nil {
+					return err
+				}
+				continue
+			}
+			// both go next
+			if err := iter.snapshotNext(); err != nil {
+				return err
+			}
+			iter.curIsDirty = true
+			break
+		} else if cmp > 0 {
+			// record from snapshot comes first
+			iter.curIsDirty = false
+			break
+		} else {
+			// record from dirty comes first
+			if len(iter.dirtyIt.Value()) == 0 {
+				// dirty has a record that doesn't exist in snapshot, skip it
+				if err := iter.dirtyNext(); err != nil {
+					return err
+				}
+				continue
+			}
+			iter.curIsDirty = true
+			break
+		}
+	}
+	return nil
+}
+
+func (iter *unionIter) Next() error {
+	var err error
+	if !iter.curIsDirty {
+		err = iter.snapshotIt.Next()
+	} else {
+		err = iter.dirtyIt.Next()
+	}
+	if err != nil {
+		return err
+	}
+	return iter.updateCur()
+}
+
+func (iter *unionIter) Value() []byte {
+	if !iter.curIsDirty {
+		return iter.snapshotIt.Value()
+	}
+	return iter.dirtyIt.Value()
+}
+
+func (iter *unionIter) Key() Key {
+	if !iter.curIsDirty {
+		return iter.snapshotIt.Key()
+	}
+	return iter.dirtyIt.Key()
+}
+
+func (iter *unionIter) Valid() bool {
+	return iter.isValid
+}
+
+func (iter *unionIter) Close() {
+	if iter.snapshotIt != nil {
+		iter.snapshotIt.Close()
+		iter.snapshotIt = nil
+	}
+	if iter.dirtyIt != nil {
+		iter.dirtyIt.Close()
+		iter.dirtyIt = nil
+	}
+}
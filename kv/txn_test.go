@@ -0,0 +1,556 @@
+package kv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestStore(t *testing.T) Storage {
+	t.Helper()
+	store, err := NewStorage("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func mustBegin(t *testing.T, store Storage) Transaction {
+	t.Helper()
+	txn, err := store.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return txn
+}
+
+func mustSet(t *testing.T, m Mutator, k, v string) {
+	t.Helper()
+	if err := m.Set(Key(k), []byte(v)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func mustCommit(t *testing.T, txn Transaction) {
+	t.Helper()
+	if err := txn.Commit(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// checkGet checks the value of k read by r, an empty expected value means k doesn't exist.
+func checkGet(t *testing.T, r Retriever, k, expected string) {
+	t.Helper()
+	v, err := r.Get(context.Background(), Key(k))
+	if expected == "" {
+		if !IsErrNotFound(err) {
+			t.Fatalf("Get(%q) = %q, %v, expected not found", k, v, err)
+		}
+		return
+	}
+	if err != nil || string(v) != expected {
+		t.Fatalf("Get(%q) = %q, %v, expected %q", k, v, err, expected)
+	}
+}
+
+// scan returns the keys and values of the iterator as "k=v" pairs.
+func scan(t *testing.T, it Iterator, err error) []string {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer it.Close()
+	var res []string
+	for it.Valid() {
+		res = append(res, string(it.Key())+"="+string(it.Value()))
+		if err := it.Next(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return res
+}
+
+func TestSnapshotIsolation(t *testing.T) {
+	store := newTestStore(t)
+	txn := mustBegin(t, store)
+	mustSet(t, txn, "a", "1")
+	mustSet(t, txn, "c", "1")
+	mustCommit(t, txn)
+	firstCommitTS := txn.CommitTS()
+
+	reader := mustBegin(t, store)
+	writer := mustBegin(t, store)
+	mustSet(t, writer, "a", "2")
+	mustSet(t, writer, "b", "2")
+	if err := writer.Delete(Key("c")); err != nil {
+		t.Fatal(err)
+	}
+	mustCommit(t, writer)
+
+	// The reader began before the writer committed, it reads the data at its startTS.
+	checkGet(t, reader, "a", "1")
+	checkGet(t, reader, "b", "")
+	checkGet(t, reader, "c", "1")
+	it, err := reader.Iter(nil, nil)
+	if got := fmt.Sprint(scan(t, it, err)); got != "[a=1 c=1]" {
+		t.Fatalf("the reader scans %s", got)
+	}
+	it, err = reader.IterReverse(nil)
+	if got := fmt.Sprint(scan(t, it, err)); got != "[c=1 a=1]" {
+		t.Fatalf("the reader scans %s in reverse", got)
+	}
+
+	// The snapshots read the versions committed before their timestamps.
+	snap := store.GetSnapshot(Version{Ver: firstCommitTS})
+	checkGet(t, snap, "a", "1")
+	checkGet(t, snap, "c", "1")
+	snap = store.GetSnapshot(Version{Ver: firstCommitTS - 1})
+	checkGet(t, snap, "a", "")
+	snap = store.GetSnapshot(Version{Ver: writer.CommitTS()})
+	checkGet(t, snap, "a", "2")
+	checkGet(t, snap, "c", "")
+
+	checkGet(t, mustBegin(t, store), "b", "2")
+	old, err := store.BeginWithStartTS(firstCommitTS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGet(t, old, "a", "1")
+	checkGet(t, old, "b", "")
+}
+
+func TestWriteConflict(t *testing.T) {
+	store := newTestStore(t)
+	txn1, txn2 := mustBegin(t, store), mustBegin(t, store)
+	mustSet(t, txn1, "k", "1")
+	mustSet(t, txn2, "k", "2")
+	mustSet(t, txn2, "other", "2")
+	mustCommit(t, txn1)
+	err := txn2.Commit(context.Background())
+	if !errors.Is(err, ErrWriteConflict) || !IsTxnRetryableError(err) {
+		t.Fatalf("the overlapping commit returns %v, expected a write conflict", err)
+	}
+	// Nothing of the failed transaction is written.
+	checkGet(t, mustBegin(t, store), "k", "1")
+	checkGet(t, mustBegin(t, store), "other", "")
+	if err = txn2.Commit(context.Background()); !errors.Is(err, ErrInvalidTxn) {
+		t.Fatalf("the second commit returns %v, expected an invalid transaction", err)
+	}
+
+	// The transactions writing the different keys don't conflict.
+	txn1, txn2 = mustBegin(t, store), mustBegin(t, store)
+	mustSet(t, txn1, "x", "1")
+	mustSet(t, txn2, "y", "1")
+	mustCommit(t, txn1)
+	mustCommit(t, txn2)
+
+	// A deletion conflicts as well.
+	txn1, txn2 = mustBegin(t, store), mustBegin(t, store)
+	if err = txn1.Delete(Key("x")); err != nil {
+		t.Fatal(err)
+	}
+	mustSet(t, txn2, "x", "2")
+	mustCommit(t, txn1)
+	if err = txn2.Commit(context.Background()); !errors.Is(err, ErrWriteConflict) {
+		t.Fatalf("the commit overlapping a deletion returns %v, expected a write conflict", err)
+	}
+
+	// The retryable transaction is retried after a conflict.
+	retries := 0
+	err = RunInNewTxn(store, true, func(txn Transaction) error {
+		retries++
+		if retries == 1 {
+			concurrent := mustBegin(t, store)
+			mustSet(t, concurrent, "k", "concurrent")
+			mustCommit(t, concurrent)
+		}
+		v, err := txn.Get(context.Background(), Key("k"))
+		if err != nil {
+			return err
+		}
+		return txn.Set(Key("k"), append(v, '+'))
+	})
+	if err != nil || retries != 2 {
+		t.Fatalf("RunInNewTxn returns %v after %d runs", err, retries)
+	}
+	checkGet(t, mustBegin(t, store), "k", "concurrent+")
+}
+
+func TestLockKeys(t *testing.T) {
+	store := newTestStore(t)
+	txn := mustBegin(t, store)
+	mustSet(t, txn, "k", "1")
+	mustCommit(t, txn)
+
+	// A transaction locking a key fails if the key is written after it began.
+	locker, writer := mustBegin(t, store), mustBegin(t, store)
+	if err := locker.LockKeys(Key("k")); err != nil {
+		t.Fatal(err)
+	}
+	mustSet(t, locker, "unrelated", "1")
+	mustSet(t, writer, "k", "2")
+	mustCommit(t, writer)
+	if err := locker.Commit(context.Background()); !errors.Is(err, ErrWriteConflict) {
+		t.Fatalf("the commit of the locked key returns %v, expected a write conflict", err)
+	}
+	checkGet(t, mustBegin(t, store), "unrelated", "")
+
+	// A transaction which only locks keys is checked as well.
+	locker, writer = mustBegin(t, store), mustBegin(t, store)
+	if err := locker.LockKeys(Key("k"), Key("absent")); err != nil {
+		t.Fatal(err)
+	}
+	if !locker.IsReadOnly() {
+		t.Fatal("locking the keys writes them")
+	}
+	mustSet(t, writer, "absent", "1")
+	mustCommit(t, writer)
+	if err := locker.Commit(context.Background()); !errors.Is(err, ErrWriteConflict) {
+		t.Fatalf("the commit of the locked keys returns %v, expected a write conflict", err)
+	}
+
+	// The locked keys aren't written by the commit, so they don't conflict with the later transactions.
+	locker = mustBegin(t, store)
+	writer = mustBegin(t, store)
+	if err := locker.LockKeys(Key("k")); err != nil {
+		t.Fatal(err)
+	}
+	mustCommit(t, locker)
+	mustSet(t, writer, "k", "3")
+	mustCommit(t, writer)
+	checkGet(t, mustBegin(t, store), "k", "3")
+}
+
+func TestTxnIter(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	store := newTestStore(t)
+	model := make(map[string]string)
+	for round := 0; round < 20; round++ {
+		txn := mustBegin(t, store)
+		// The model is what the transaction reads, the snapshot merged with its buffer.
+		for i := 0; i < 30; i++ {
+			k := randKey(r)
+			if r.Intn(3) == 0 {
+				if err := txn.Delete(k); err != nil {
+					t.Fatal(err)
+				}
+				delete(model, string(k))
+				continue
+			}
+			v := fmt.Sprintf("%d-%d", round, i)
+			mustSet(t, txn, string(k), v)
+			model[string(k)] = v
+		}
+		for i := 0; i < 30; i++ {
+			start, upperBound := randBound(r), randBound(r)
+			var expected []string
+			for _, k := range expectedRange(model, start, upperBound, false) {
+				expected = append(expected, k+"="+model[k])
+			}
+			it, err := txn.Iter(start, upperBound)
+			if got := scan(t, it, err); fmt.Sprint(got) != fmt.Sprint(expected) {
+				t.Fatalf("Iter(%q, %q) = %v, expected %v", start, upperBound, got, expected)
+			}
+			expected = expected[:0]
+			for _, k := range expectedRange(model, start, nil, true) {
+				expected = append(expected, k+"="+model[k])
+			}
+			it, err = txn.IterReverse(start)
+			if got := scan(t, it, err); fmt.Sprint(got) != fmt.Sprint(expected) {
+				t.Fatalf("IterReverse(%q) = %v, expected %v", start, got, expected)
+			}
+		}
+		mustCommit(t, txn)
+	}
+}
+
+// bufferState returns the keys and values the transaction reads, the store must be empty.
+func bufferState(t *testing.T, txn Transaction) string {
+	t.Helper()
+	it, err := txn.Iter(nil, nil)
+	return fmt.Sprint(scan(t, it, err))
+}
+
+func TestStaging(t *testing.T) {
+	store := newTestStore(t)
+	txn := mustBegin(t, store)
+	mustSet(t, txn, "a", "0")
+
+	// Release keeps the mutations of the staging, which are discarded with the outer staging.
+	outer := txn.Staging()
+	mustSet(t, txn, "a", "1")
+	inner := txn.Staging()
+	mustSet(t, txn, "a", "2")
+	mustSet(t, txn, "b", "2")
+	txn.Release(inner)
+	if got := bufferState(t, txn); got != "[a=2 b=2]" {
+		t.Fatalf("the buffer is %s after the release", got)
+	}
+	txn.Cleanup(outer)
+	if got := bufferState(t, txn); got != "[a=0]" {
+		t.Fatalf("the buffer is %s after the cleanup of the outer staging", got)
+	}
+
+	// Cleanup discards the stagings begun after it.
+	outer = txn.Staging()
+	if err := txn.Delete(Key("a")); err != nil {
+		t.Fatal(err)
+	}
+	txn.Staging()
+	mustSet(t, txn, "c", "3")
+	txn.Cleanup(outer)
+	if got := bufferState(t, txn); got != "[a=0]" {
+		t.Fatalf("the buffer is %s after the cleanup of the nested stagings", got)
+	}
+	txn.Cleanup(outer)
+	txn.Release(outer)
+
+	// Releasing the outer staging keeps the inner one, whose cleanup only discards its own mutations.
+	outer = txn.Staging()
+	mustSet(t, txn, "b", "4")
+	inner = txn.Staging()
+	mustSet(t, txn, "b", "5")
+	mustSet(t, txn, "c", "5")
+	txn.Release(outer)
+	txn.Cleanup(inner)
+	if got := bufferState(t, txn); got != "[a=0 b=4]" {
+		t.Fatalf("the buffer is %s after the cleanup of the inner staging", got)
+	}
+	mustCommit(t, txn)
+	checkGet(t, mustBegin(t, store), "b", "4")
+	checkGet(t, mustBegin(t, store), "c", "")
+}
+
+func TestStagingRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	txn := mustBegin(t, newTestStore(t))
+	type staging struct {
+		handle StagingHandle
+		// before is the buffer before the staging.
+		before map[string]string
+	}
+	model := make(map[string]string)
+	var stagings []staging
+	copyModel := func() map[string]string {
+		m := make(map[string]string, len(model))
+		for k, v := range model {
+			m[k] = v
+		}
+		return m
+	}
+	for i := 0; i < 3000; i++ {
+		switch op := r.Intn(10); {
+		case op < 2:
+			stagings = append(stagings, staging{txn.Staging(), copyModel()})
+		case op < 3 && len(stagings) > 0:
+			j := r.Intn(len(stagings))
+			// The stagings begun after it still discard the mutations since their begins only.
+			txn.Release(stagings[j].handle)
+			stagings = append(stagings[:j], stagings[j+1:]...)
+		case op < 4 && len(stagings) > 0:
+			j := r.Intn(len(stagings))
+			txn.Cleanup(stagings[j].handle)
+			model = stagings[j].before
+			stagings = stagings[:j]
+		case op < 6:
+			k := randKey(r)
+			if err := txn.Delete(k); err != nil {
+				t.Fatal(err)
+			}
+			delete(model, string(k))
+		default:
+			k, v := randKey(r), fmt.Sprint(i)
+			mustSet(t, txn, string(k), v)
+			model[string(k)] = v
+		}
+		if i%50 != 0 {
+			continue
+		}
+		var expected []string
+		for _, k := range sortedKeys(model) {
+			expected = append(expected, k+"="+model[k])
+		}
+		if got, expected := bufferState(t, txn), fmt.Sprint(expected); got != expected {
+			t.Fatalf("the buffer is %s after %d operations, expected %s", got, i+1, expected)
+		}
+	}
+}
+
+// versionCount returns the number of the versions of k kept by the store.
+func versionCount(store Storage, k string) int {
+	s := store.(*memStore)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if e, ok := s.data.Get([]byte(k)); ok {
+		return len(e.(*mvccEntry).versions)
+	}
+	return 0
+}
+
+func TestGC(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	var commitTS []uint64
+	var reader Transaction
+	for i := 1; i <= 5; i++ {
+		txn := mustBegin(t, store)
+		mustSet(t, txn, "a", fmt.Sprint(i))
+		switch i {
+		case 1:
+			mustSet(t, txn, "d", "1")
+		case 2:
+			if err := txn.Delete(Key("d")); err != nil {
+				t.Fatal(err)
+			}
+		}
+		mustCommit(t, txn)
+		commitTS = append(commitTS, txn.CommitTS())
+		if i == 3 {
+			reader = mustBegin(t, store)
+		}
+	}
+	if n := versionCount(store, "a"); n != 5 {
+		t.Fatalf("a has %d versions, expected 5", n)
+	}
+
+	// The reader in progress keeps the version it reads.
+	if err := store.GC(ctx, commitTS[4]); err != nil {
+		t.Fatal(err)
+	}
+	if n := versionCount(store, "a"); n != 3 {
+		t.Fatalf("a has %d versions after GC, expected 3", n)
+	}
+	if n := versionCount(store, "d"); n != 0 {
+		t.Fatalf("the deleted d has %d versions after GC, expected 0", n)
+	}
+	checkGet(t, reader, "a", "3")
+	checkGet(t, reader, "d", "")
+	if _, err := store.BeginWithStartTS(commitTS[0]); !errors.Is(err, ErrSnapshotTooOld) {
+		t.Fatalf("the error of a transaction older than the safe point is %v, expected %v", err, ErrSnapshotTooOld)
+	}
+
+	if err := reader.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.GC(ctx, commitTS[4]); err != nil {
+		t.Fatal(err)
+	}
+	if n := versionCount(store, "a"); n != 1 {
+		t.Fatalf("a has %d versions after the reader finishes, expected 1", n)
+	}
+	checkGet(t, store.GetSnapshot(Version{Ver: commitTS[4]}), "a", "5")
+
+	// A new version is appended after the ones left, the snapshots read the newest version before them.
+	txn := mustBegin(t, store)
+	mustSet(t, txn, "a", "6")
+	mustCommit(t, txn)
+	checkGet(t, store.GetSnapshot(Version{Ver: commitTS[4]}), "a", "5")
+	checkGet(t, mustBegin(t, store), "a", "6")
+	conflict := mustBegin(t, store)
+	mustSet(t, conflict, "a", "7")
+	txn = mustBegin(t, store)
+	mustSet(t, txn, "a", "8")
+	mustCommit(t, txn)
+	if err := conflict.Commit(ctx); !errors.Is(err, ErrWriteConflict) {
+		t.Fatalf("the commit error is %v, expected %v", err, ErrWriteConflict)
+	}
+}
+
+func TestDeleteRange(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+	for i := 0; i < 3; i++ {
+		txn := mustBegin(t, store)
+		for _, k := range []string{"a", "b1", "b2", "c"} {
+			mustSet(t, txn, k, fmt.Sprint(i))
+		}
+		mustCommit(t, txn)
+	}
+	if err := store.DeleteRange(ctx, Key("b"), Key("c")); err != nil {
+		t.Fatal(err)
+	}
+	if n := versionCount(store, "b1") + versionCount(store, "b2"); n != 0 {
+		t.Fatalf("the deleted keys have %d versions, expected 0", n)
+	}
+	if n := versionCount(store, "a") + versionCount(store, "c"); n != 6 {
+		t.Fatalf("the keys out of the range have %d versions, expected 6", n)
+	}
+	it, err := store.GetSnapshot(MaxVersion).Iter(nil, nil)
+	if res := scan(t, it, err); fmt.Sprint(res) != "[a=2 c=2]" {
+		t.Fatalf("the keys are %v after DeleteRange", res)
+	}
+}
+
+func TestCommitLogCompaction(t *testing.T) {
+	dir, err := ioutil.TempDir("", "kv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	logPath := filepath.Join(dir, "kv.log")
+	ctx := context.Background()
+
+	store, err := NewStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		txn := mustBegin(t, store)
+		mustSet(t, txn, "a", fmt.Sprint(i))
+		mustSet(t, txn, fmt.Sprintf("b%03d", i), "x")
+		mustCommit(t, txn)
+	}
+	txn := mustBegin(t, store)
+	if err = txn.Delete(Key("b000")); err != nil {
+		t.Fatal(err)
+	}
+	mustCommit(t, txn)
+	if err = store.DeleteRange(ctx, Key("b050"), Key("c")); err != nil {
+		t.Fatal(err)
+	}
+	before, err := os.Stat(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ver, err := store.CurrentVersion()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = store.GC(ctx, ver.Ver); err != nil {
+		t.Fatal(err)
+	}
+	after, err := os.Stat(logPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Size()*2 > before.Size() {
+		t.Fatalf("the log of %d bytes is compacted to %d bytes", before.Size(), after.Size())
+	}
+	// The commits after the compaction are appended to the new log.
+	txn = mustBegin(t, store)
+	mustSet(t, txn, "c", "1")
+	mustCommit(t, txn)
+	if err = store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err = NewStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	it, err := store.GetSnapshot(MaxVersion).Iter(nil, nil)
+	res := scan(t, it, err)
+	if len(res) != 51 || res[0] != "a=99" || res[1] != "b001=x" || res[49] != "b049=x" || res[50] != "c=1" {
+		t.Fatalf("the keys replayed from the compacted log are %v", res)
+	}
+	if n := versionCount(store, "a"); n != 1 {
+		t.Fatalf("a has %d versions replayed from the compacted log, expected 1", n)
+	}
+}
@@ -0,0 +1,146 @@
+package autoid
+
+import (
+	"math"
+	"sync"
+
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
+	"grant-db/kv"
+	"grant-db/meta"
+)
+
+// step is the count of the IDs which are allocated from the store at a time.
+const step = 1000
+
+// The shard bits of AUTO_RANDOM.
+const (
+	// DefaultAutoRandomBits is the number of the shard bits if AUTO_RANDOM doesn't specify it.
+	DefaultAutoRandomBits = 5
+	// MaxAutoRandomBits is the max number of the shard bits.
+	MaxAutoRandomBits = 15
+)
+
+// codeInvalidAutoRandom is the error code of the invalid AUTO_RANDOM column, which is the one of TiDB.
+const codeInvalidAutoRandom = 8216
+
+var (
+	// ErrAutoincReadFailed is returned when the IDs are used up.
+	ErrAutoincReadFailed = terror.ClassAutoid.New(mysql.ErrAutoincReadFailed, mysql.MySQLErrName[mysql.ErrAutoincReadFailed])
+	// ErrInvalidAutoRandom is returned when the AUTO_RANDOM column is invalid.
+	ErrInvalidAutoRandom = terror.ClassAutoid.New(codeInvalidAutoRandom, "Invalid auto random: %s")
+)
+
+// Allocator allocates the row IDs of a table.
+type Allocator interface {
+	// Alloc allocates the IDs of n rows, which are the n values from SeekToFirstAutoID(min, increment, offset)
+	// stepped by increment, max is the last of them.
+	Alloc(n, increment, offset int64) (min int64, max int64, err error)
+	// Rebase makes the IDs allocated later greater than requiredBase, which is an ID written explicitly.
+	Rebase(requiredBase int64) error
+	// Base returns the last allocated ID.
+	Base() int64
+}
+
+// SeekToFirstAutoID returns the first ID greater than base in the sequence of offset stepped by increment.
+func SeekToFirstAutoID(base, increment, offset int64) int64 {
+	nr := (base + increment - offset) / increment
+	return nr*increment + offset
+}
+
+// calcNeededBatchSize returns the count of the IDs after base which the n IDs of the sequence take.
+func calcNeededBatchSize(base, n, increment, offset int64) int64 {
+	if increment == 1 {
+		return n
+	}
+	return SeekToFirstAutoID(base, increment, offset) + (n-1)*increment - base
+}
+
+// allocator caches a batch of the IDs allocated from the store, the IDs which aren't used before the
+// allocator is released are skipped.
+type allocator struct {
+	mu      sync.Mutex
+	store   kv.Storage
+	tableID int64
+	// base is the last allocated ID and end is the last cached one.
+	base int64
+	end  int64
+}
+
+// NewAllocator creates an Allocator of the table.
+func NewAllocator(store kv.Storage, tableID int64) Allocator {
+	return &allocator{store: store, tableID: tableID}
+}
+
+// Base implements Allocator Base interface.
+func (alloc *allocator) Base() int64 {
+	alloc.mu.Lock()
+	defer alloc.mu.Unlock()
+	return alloc.base
+}
+
+// Alloc implements Allocator Alloc interface.
+func (alloc *allocator) Alloc(n, increment, offset int64) (int64, int64, error) {
+	alloc.mu.Lock()
+	defer alloc.mu.Unlock()
+	need := calcNeededBatchSize(alloc.base, n, increment, offset)
+	if alloc.end-alloc.base < need {
+		var newBase, newStep int64
+		err := kv.RunInNewTxn(alloc.store, true, func(txn kv.Transaction) error {
+			m := meta.NewMeta(txn)
+			var err error
+			newBase, err = m.GetAutoTableID(alloc.tableID)
+			if err != nil {
+				return err
+			}
+			// The IDs are sought from the new base since the cached ones are skipped.
+			need = calcNeededBatchSize(newBase, n, increment, offset)
+			newStep = step
+			if newStep < need {
+				newStep = need
+			}
+			if newBase > math.MaxInt64-newStep {
+				return ErrAutoincReadFailed
+			}
+			_, err = m.GenAutoTableID(alloc.tableID, newStep)
+			return err
+		})
+		if err != nil {
+			return 0, 0, err
+		}
+		alloc.base, alloc.end = newBase, newBase+newStep
+	}
+	min := alloc.base
+	alloc.base += need
+	return min, alloc.base, nil
+}
+
+// Rebase implements Allocator Rebase interface. The store is rebased if requiredBase is beyond the cached
+// IDs, which are skipped then.
+func (alloc *allocator) Rebase(requiredBase int64) error {
+	alloc.mu.Lock()
+	defer alloc.mu.Unlock()
+	if requiredBase <= alloc.base {
+		return nil
+	}
+	if requiredBase <= alloc.end {
+		alloc.base = requiredBase
+		return nil
+	}
+	var newBase int64
+	err := kv.RunInNewTxn(alloc.store, true, func(txn kv.Transaction) error {
+		m := meta.NewMeta(txn)
+		var err error
+		newBase, err = m.GetAutoTableID(alloc.tableID)
+		if err != nil || newBase >= requiredBase {
+			return err
+		}
+		newBase, err = m.GenAutoTableID(alloc.tableID, requiredBase-newBase)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	alloc.base, alloc.end = newBase, newBase
+	return nil
+}
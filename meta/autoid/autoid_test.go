@@ -0,0 +1,93 @@
+package autoid_test
+
+import (
+	"math"
+	"testing"
+
+	"grant-db/kv"
+	"grant-db/meta/autoid"
+)
+
+func newStore(t *testing.T) kv.Storage {
+	store, err := kv.NewStorage("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func TestSeekToFirstAutoID(t *testing.T) {
+	tests := []struct {
+		base, increment, offset int64
+		expected                int64
+	}{
+		{0, 1, 1, 1},
+		{5, 1, 1, 6},
+		{0, 5, 3, 3},
+		{3, 5, 3, 8},
+		{4, 5, 3, 8},
+		{8, 5, 3, 13},
+		{13, 10, 10, 20},
+	}
+	for _, tt := range tests {
+		if got := autoid.SeekToFirstAutoID(tt.base, tt.increment, tt.offset); got != tt.expected {
+			t.Fatalf("the first ID after %d stepped by %d from %d is %d, expected %d", tt.base, tt.increment, tt.offset, got, tt.expected)
+		}
+	}
+}
+
+func TestAlloc(t *testing.T) {
+	store := newStore(t)
+	alloc := autoid.NewAllocator(store, 1)
+	checkAlloc := func(alloc autoid.Allocator, n, increment, offset, expectedMin, expectedMax int64) {
+		t.Helper()
+		min, max, err := alloc.Alloc(n, increment, offset)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if min != expectedMin || max != expectedMax {
+			t.Fatalf("Alloc(%d, %d, %d) = (%d, %d), expected (%d, %d)", n, increment, offset, min, max, expectedMin, expectedMax)
+		}
+	}
+	checkAlloc(alloc, 1, 1, 1, 0, 1)
+	checkAlloc(alloc, 2, 1, 1, 1, 3)
+	// The IDs 8, 13 and 18 are allocated.
+	checkAlloc(alloc, 3, 5, 3, 3, 18)
+	if base := alloc.Base(); base != 18 {
+		t.Fatalf("the base is %d", base)
+	}
+
+	// Another allocator of the table allocates the IDs after the batch cached by the first one.
+	other := autoid.NewAllocator(store, 1)
+	checkAlloc(other, 1, 1, 1, 1000, 1001)
+	checkAlloc(alloc, 1, 1, 1, 18, 19)
+	// The IDs more than the cached ones are allocated in a new batch, the cached ones are skipped.
+	checkAlloc(other, 1500, 1, 1, 2000, 3500)
+	checkAlloc(autoid.NewAllocator(store, 2), 1, 1, 1, 0, 1)
+
+	// The rebase in the cached batch doesn't touch the store, the one beyond it does.
+	if err := alloc.Rebase(500); err != nil {
+		t.Fatal(err)
+	}
+	checkAlloc(alloc, 1, 1, 1, 500, 501)
+	if err := alloc.Rebase(10); err != nil {
+		t.Fatal(err)
+	}
+	checkAlloc(alloc, 1, 1, 1, 501, 502)
+	if err := alloc.Rebase(5000); err != nil {
+		t.Fatal(err)
+	}
+	checkAlloc(alloc, 1, 1, 1, 5000, 5001)
+	checkAlloc(autoid.NewAllocator(store, 1), 1, 1, 1, 6000, 6001)
+}
+
+func TestAllocExhausted(t *testing.T) {
+	alloc := autoid.NewAllocator(newStore(t), 1)
+	if err := alloc.Rebase(math.MaxInt64 - 10); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := alloc.Alloc(100, 1, 1); !autoid.ErrAutoincReadFailed.Equal(err) {
+		t.Fatalf("the error of the IDs used up is %v, expected %v", err, autoid.ErrAutoincReadFailed)
+	}
+}
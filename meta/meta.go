@@ -0,0 +1,673 @@
+package meta
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
+	"grant-db/kv"
+	"grant-db/util/codec"
+)
+
+// Meta structure in the KV store:
+//	mNextGlobalID -> int64
+//	mSchemaVersion -> int64
+//	mDB_[dbID] -> db meta data []byte
+//	mTable_[dbID]_[tableID] -> table meta data []byte
+//	mAutoID_[tableID] -> the last allocated row ID of the table int64
+//	mDDLJobList_[jobID] -> job []byte
+//	mDDLJobHistory_[jobID] -> job []byte
+//	mDDLJobReorg_[jobID] -> reorg handle int64
+//	mDelRange_[jobID]_[elementID] -> delete range task []byte
+//	mGCSafePoint -> safe point uint64
+//	mStatsMeta_[tableID] -> stats meta data []byte
+//	mStatsHist_[tableID]_[isIndex]_[histID] -> histogram data []byte
+// Every meta key is written in transactions, so the MVCC versions of the keys
+// keep the history of the schema.
+
+var (
+	mNextGlobalIDKey  = []byte("mNextGlobalID")
+	mSchemaVersionKey = []byte("mSchemaVersion")
+	mDBPrefix         = "mDB_"
+	mTablePrefix      = "mTable_"
+	mAutoIDPrefix     = "mAutoID_"
+	mDDLJobListPrefix = "mDDLJobList_"
+	mDDLJobHistory    = "mDDLJobHistory_"
+	mDDLJobReorg      = "mDDLJobReorg_"
+	mDelRangePrefix   = "mDelRange_"
+	mGCSafePointKey   = []byte("mGCSafePoint")
+	mStatsMetaPrefix  = "mStatsMeta_"
+	mStatsHistPrefix  = "mStatsHist_"
+)
+
+var (
+	// ErrDBExists is the error for db exists.
+	ErrDBExists = terror.ClassMeta.New(mysql.ErrDBCreateExists, mysql.MySQLErrName[mysql.ErrDBCreateExists])
+	// ErrDBNotExists is the error for db not exists.
+	ErrDBNotExists = terror.ClassMeta.New(mysql.ErrBadDB, mysql.MySQLErrName[mysql.ErrBadDB])
+	// ErrTableExists is the error for table exists.
+	ErrTableExists = terror.ClassMeta.New(mysql.ErrTableExists, mysql.MySQLErrName[mysql.ErrTableExists])
+	// ErrTableNotExists is the error for table not exists.
+	ErrTableNotExists = terror.ClassMeta.New(mysql.ErrNoSuchTable, mysql.MySQLErrName[mysql.ErrNoSuchTable])
+)
+
+// Meta is for handling meta information in a transaction.
+type Meta struct {
+	txn kv.RetrieverMutator
+	// StartTS is the start timestamp of the transaction, it's 0 for a snapshot Meta.
+	StartTS uint64
+}
+
+// NewMeta creates a Meta in transaction txn.
+func NewMeta(txn kv.Transaction) *Meta {
+	return &Meta{txn: txn, StartTS: txn.StartTS()}
+}
+
+// NewSnapshotMeta creates a Meta with snapshot, it can only read.
+func NewSnapshotMeta(snapshot kv.Snapshot) *Meta {
+	return &Meta{txn: readOnly{snapshot}}
+}
+
+type readOnly struct {
+	kv.Snapshot
+}
+
+func (readOnly) Set(k kv.Key, v []byte) error {
+	return kv.ErrInvalidTxn
+}
+
+func (readOnly) Delete(k kv.Key) error {
+	return kv.ErrInvalidTxn
+}
+
+func (m *Meta) getInt64(key []byte) (int64, error) {
+	v, err := m.txn.Get(context.Background(), key)
+	if kv.IsErrNotFound(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(string(v), 10, 64)
+}
+
+func (m *Meta) incInt64(key []byte, step int64) (int64, error) {
+	n, err := m.getInt64(key)
+	if err != nil {
+		return 0, err
+	}
+	n += step
+	return n, m.txn.Set(key, []byte(strconv.FormatInt(n, 10)))
+}
+
+// GenGlobalID generates next id globally.
+func (m *Meta) GenGlobalID() (int64, error) {
+	return m.incInt64(mNextGlobalIDKey, 1)
+}
+
+// GenGlobalIDs generates the next n global IDs.
+func (m *Meta) GenGlobalIDs(n int) ([]int64, error) {
+	newID, err := m.incInt64(mNextGlobalIDKey, int64(n))
+	if err != nil {
+		return nil, err
+	}
+	origID := newID - int64(n)
+	ids := make([]int64, 0, n)
+	for i := origID + 1; i <= newID; i++ {
+		ids = append(ids, i)
+	}
+	return ids, nil
+}
+
+// GetGlobalID gets current global id.
+func (m *Meta) GetGlobalID() (int64, error) {
+	return m.getInt64(mNextGlobalIDKey)
+}
+
+// GetSchemaVersion gets current global schema version.
+func (m *Meta) GetSchemaVersion() (int64, error) {
+	return m.getInt64(mSchemaVersionKey)
+}
+
+// GenSchemaVersion generates next schema version.
+func (m *Meta) GenSchemaVersion() (int64, error) {
+	return m.incInt64(mSchemaVersionKey, 1)
+}
+
+func dbKey(dbID int64) kv.Key {
+	return codec.EncodeInt([]byte(mDBPrefix), dbID)
+}
+
+func tablePrefix(dbID int64) kv.Key {
+	return append(codec.EncodeInt([]byte(mTablePrefix), dbID), '_')
+}
+
+func tableKey(dbID int64, tableID int64) kv.Key {
+	return codec.EncodeInt(tablePrefix(dbID), tableID)
+}
+
+// The auto ID of a table is kept when the table is renamed or dropped, so a recovered table goes on
+// with its IDs, and a truncated table starts from 0 with its new table ID.
+func autoTableIDKey(tableID int64) kv.Key {
+	return codec.EncodeInt([]byte(mAutoIDPrefix), tableID)
+}
+
+// GenAutoTableID adds step to the auto ID of a table and returns the new auto ID.
+func (m *Meta) GenAutoTableID(tableID, step int64) (int64, error) {
+	return m.incInt64(autoTableIDKey(tableID), step)
+}
+
+// GetAutoTableID gets the auto ID of a table.
+func (m *Meta) GetAutoTableID(tableID int64) (int64, error) {
+	return m.getInt64(autoTableIDKey(tableID))
+}
+
+func (m *Meta) exists(key kv.Key) (bool, error) {
+	_, err := m.txn.Get(context.Background(), key)
+	if kv.IsErrNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (m *Meta) checkDBExists(dbKey kv.Key) error {
+	exists, err := m.exists(dbKey)
+	if err == nil && !exists {
+		err = ErrDBNotExists.GenWithStack("database %q doesn't exist", dbKey)
+	}
+	return err
+}
+
+func (m *Meta) checkDBNotExists(dbKey kv.Key) error {
+	exists, err := m.exists(dbKey)
+	if err == nil && exists {
+		err = ErrDBExists.GenWithStack("database %q already exists", dbKey)
+	}
+	return err
+}
+
+func (m *Meta) checkTableExists(tableKey kv.Key) error {
+	exists, err := m.exists(tableKey)
+	if err == nil && !exists {
+		err = ErrTableNotExists.GenWithStack("table %q doesn't exist", tableKey)
+	}
+	return err
+}
+
+func (m *Meta) checkTableNotExists(tableKey kv.Key) error {
+	exists, err := m.exists(tableKey)
+	if err == nil && exists {
+		err = ErrTableExists.GenWithStack("table %q already exists", tableKey)
+	}
+	return err
+}
+
+func (m *Meta) setJSON(key kv.Key, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return m.txn.Set(key, data)
+}
+
+// CreateDatabase creates a database with db info.
+func (m *Meta) CreateDatabase(dbInfo *model.DBInfo) error {
+	key := dbKey(dbInfo.ID)
+	if err := m.checkDBNotExists(key); err != nil {
+		return err
+	}
+	return m.setJSON(key, dbInfo)
+}
+
+// UpdateDatabase updates a database with db info.
+func (m *Meta) UpdateDatabase(dbInfo *model.DBInfo) error {
+	key := dbKey(dbInfo.ID)
+	if err := m.checkDBExists(key); err != nil {
+		return err
+	}
+	return m.setJSON(key, dbInfo)
+}
+
+// CreateTableOrView creates a table with tableInfo in database.
+func (m *Meta) CreateTableOrView(dbID int64, tableInfo *model.TableInfo) error {
+	// Check if db exists.
+	if err := m.checkDBExists(dbKey(dbID)); err != nil {
+		return err
+	}
+	// Check if table exists.
+	key := tableKey(dbID, tableInfo.ID)
+	if err := m.checkTableNotExists(key); err != nil {
+		return err
+	}
+	return m.setJSON(key, tableInfo)
+}
+
+// DropDatabase drops whole database.
+func (m *Meta) DropDatabase(dbID int64) error {
+	tables, err := m.ListTables(dbID)
+	if err != nil {
+		return err
+	}
+	for _, tbl := range tables {
+		if err := m.txn.Delete(tableKey(dbID, tbl.ID)); err != nil {
+			return err
+		}
+	}
+	return m.txn.Delete(dbKey(dbID))
+}
+
+// DropTableOrView drops table in database.
+func (m *Meta) DropTableOrView(dbID int64, tblID int64) error {
+	// Check if db exists.
+	if err := m.checkDBExists(dbKey(dbID)); err != nil {
+		return err
+	}
+	// Check if table exists.
+	key := tableKey(dbID, tblID)
+	if err := m.checkTableExists(key); err != nil {
+		return err
+	}
+	return m.txn.Delete(key)
+}
+
+// UpdateTable updates the table with table info.
+func (m *Meta) UpdateTable(dbID int64, tableInfo *model.TableInfo) error {
+	// Check if db exists.
+	if err := m.checkDBExists(dbKey(dbID)); err != nil {
+		return err
+	}
+	// Check if table exists.
+	key := tableKey(dbID, tableInfo.ID)
+	if err := m.checkTableExists(key); err != nil {
+		return err
+	}
+	return m.setJSON(key, tableInfo)
+}
+
+// ListTables shows all tables in database.
+func (m *Meta) ListTables(dbID int64) ([]*model.TableInfo, error) {
+	if err := m.checkDBExists(dbKey(dbID)); err != nil {
+		return nil, err
+	}
+	prefix := tablePrefix(dbID)
+	tables := make([]*model.TableInfo, 0)
+	err := m.iterPrefix(prefix, func(value []byte) error {
+		tbInfo := &model.TableInfo{}
+		if err := json.Unmarshal(value, tbInfo); err != nil {
+			return err
+		}
+		tables = append(tables, tbInfo)
+		return nil
+	})
+	return tables, err
+}
+
+// ListDatabases shows all databases.
+func (m *Meta) ListDatabases() ([]*model.DBInfo, error) {
+	dbs := make([]*model.DBInfo, 0)
+	err := m.iterPrefix([]byte(mDBPrefix), func(value []byte) error {
+		dbInfo := &model.DBInfo{}
+		if err := json.Unmarshal(value, dbInfo); err != nil {
+			return err
+		}
+		dbs = append(dbs, dbInfo)
+		return nil
+	})
+	return dbs, err
+}
+
+// GetDatabase gets the database value with ID.
+func (m *Meta) GetDatabase(dbID int64) (*model.DBInfo, error) {
+	value, err := m.txn.Get(context.Background(), dbKey(dbID))
+	if kv.IsErrNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	dbInfo := &model.DBInfo{}
+	err = json.Unmarshal(value, dbInfo)
+	return dbInfo, err
+}
+
+// GetTable gets the table value in database with tableID.
+func (m *Meta) GetTable(dbID int64, tableID int64) (*model.TableInfo, error) {
+	// Check if db exists.
+	if err := m.checkDBExists(dbKey(dbID)); err != nil {
+		return nil, err
+	}
+	value, err := m.txn.Get(context.Background(), tableKey(dbID, tableID))
+	if kv.IsErrNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	tableInfo := &model.TableInfo{}
+	err = json.Unmarshal(value, tableInfo)
+	return tableInfo, err
+}
+
+func (m *Meta) iterPrefix(prefix kv.Key, fn func(value []byte) error) error {
+	it, err := m.txn.Iter(prefix, prefix.PrefixNext())
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+	for it.Valid() {
+		if err := fn(it.Value()); err != nil {
+			return err
+		}
+		if err := it.Next(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DDL job structure
+//	mDDLJobList_[jobID]: job
+//
+// The jobs are ordered by their IDs in the queue, the first job is the one to run.
+
+func ddlJobKey(prefix string, jobID int64) kv.Key {
+	return codec.EncodeInt([]byte(prefix), jobID)
+}
+
+func (m *Meta) getDDLJob(key kv.Key) (*model.Job, error) {
+	value, err := m.txn.Get(context.Background(), key)
+	if kv.IsErrNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	job := &model.Job{}
+	err = job.Decode(value)
+	return job, err
+}
+
+func (m *Meta) setDDLJob(key kv.Key, job *model.Job, updateRawArgs bool) error {
+	b, err := job.Encode(updateRawArgs)
+	if err != nil {
+		return err
+	}
+	return m.txn.Set(key, b)
+}
+
+func (m *Meta) listDDLJobs(prefix string, reverse bool, limit int) ([]*model.Job, error) {
+	var (
+		it   kv.Iterator
+		err  error
+		keys = kv.Key(prefix)
+	)
+	if reverse {
+		it, err = m.txn.IterReverse(keys.PrefixNext())
+	} else {
+		it, err = m.txn.Iter(keys, keys.PrefixNext())
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer it.Close()
+	jobs := make([]*model.Job, 0)
+	for it.Valid() && it.Key().HasPrefix(keys) && (limit <= 0 || len(jobs) < limit) {
+		job := &model.Job{}
+		if err := job.Decode(it.Value()); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+		if err := it.Next(); err != nil {
+			return nil, err
+		}
+	}
+	return jobs, nil
+}
+
+// EnQueueDDLJob adds a DDL job to the job queue.
+func (m *Meta) EnQueueDDLJob(job *model.Job) error {
+	return m.setDDLJob(ddlJobKey(mDDLJobListPrefix, job.ID), job, true)
+}
+
+// DeQueueDDLJob removes the job from the job queue.
+func (m *Meta) DeQueueDDLJob(jobID int64) error {
+	return m.txn.Delete(ddlJobKey(mDDLJobListPrefix, jobID))
+}
+
+// GetFirstDDLJob gets the first job in the queue, it returns nil if the queue is empty.
+func (m *Meta) GetFirstDDLJob() (*model.Job, error) {
+	jobs, err := m.listDDLJobs(mDDLJobListPrefix, false, 1)
+	if err != nil || len(jobs) == 0 {
+		return nil, err
+	}
+	return jobs[0], nil
+}
+
+// GetDDLJobByID gets the job in the queue with ID, it returns nil if the job is not in the queue.
+func (m *Meta) GetDDLJobByID(jobID int64) (*model.Job, error) {
+	return m.getDDLJob(ddlJobKey(mDDLJobListPrefix, jobID))
+}
+
+// UpdateDDLJob updates the job in the queue.
+func (m *Meta) UpdateDDLJob(job *model.Job, updateRawArgs bool) error {
+	return m.setDDLJob(ddlJobKey(mDDLJobListPrefix, job.ID), job, updateRawArgs)
+}
+
+// GetAllDDLJobsInQueue gets all DDL jobs in the queue.
+func (m *Meta) GetAllDDLJobsInQueue() ([]*model.Job, error) {
+	return m.listDDLJobs(mDDLJobListPrefix, false, 0)
+}
+
+// AddHistoryDDLJob adds a finished DDL job to the history.
+func (m *Meta) AddHistoryDDLJob(job *model.Job) error {
+	return m.setDDLJob(ddlJobKey(mDDLJobHistory, job.ID), job, true)
+}
+
+// GetHistoryDDLJob gets a history DDL job, it returns nil if the job is not in the history.
+func (m *Meta) GetHistoryDDLJob(jobID int64) (*model.Job, error) {
+	return m.getDDLJob(ddlJobKey(mDDLJobHistory, jobID))
+}
+
+// GetLastNHistoryDDLJobs gets the latest n history DDL jobs, the newest one comes first.
+func (m *Meta) GetLastNHistoryDDLJobs(n int) ([]*model.Job, error) {
+	return m.listDDLJobs(mDDLJobHistory, true, n)
+}
+
+// UpdateDDLReorgHandle saves the handle from which the reorganization of the job continues.
+func (m *Meta) UpdateDDLReorgHandle(job *model.Job, handle int64) error {
+	return m.txn.Set(ddlJobKey(mDDLJobReorg, job.ID), []byte(strconv.FormatInt(handle, 10)))
+}
+
+// GetDDLReorgHandle gets the handle from which the reorganization of the job continues,
+// found is false if the job has no saved handle.
+func (m *Meta) GetDDLReorgHandle(job *model.Job) (handle int64, found bool, err error) {
+	key := ddlJobKey(mDDLJobReorg, job.ID)
+	found, err = m.exists(key)
+	if err != nil || !found {
+		return 0, false, err
+	}
+	handle, err = m.getInt64(key)
+	return handle, true, err
+}
+
+// RemoveDDLReorgHandle removes the reorganization handle of the job.
+func (m *Meta) RemoveDDLReorgHandle(job *model.Job) error {
+	return m.txn.Delete(ddlJobKey(mDDLJobReorg, job.ID))
+}
+
+// DelRangeTask is a range of data left by a finished DDL job, it's deleted by GC once the
+// task is older than the GC safe point.
+type DelRangeTask struct {
+	JobID     int64  `json:"job_id"`
+	ElementID int64  `json:"element_id"`
+	StartKey  kv.Key `json:"start_key"`
+	EndKey    kv.Key `json:"end_key"`
+	// TS is the commit version of the job step which leaves the range.
+	TS uint64 `json:"ts"`
+}
+
+func delRangeKey(jobID, elementID int64) kv.Key {
+	return codec.EncodeInt(ddlJobKey(mDelRangePrefix, jobID), elementID)
+}
+
+// AddDelRange adds a delete range task.
+func (m *Meta) AddDelRange(task *DelRangeTask) error {
+	return m.setJSON(delRangeKey(task.JobID, task.ElementID), task)
+}
+
+// RemoveDelRange removes the delete range task, it's called when the range is deleted or
+// the data of the range is recovered.
+func (m *Meta) RemoveDelRange(jobID, elementID int64) error {
+	return m.txn.Delete(delRangeKey(jobID, elementID))
+}
+
+// GetDelRange gets the delete range task, it returns nil if the task doesn't exist.
+func (m *Meta) GetDelRange(jobID, elementID int64) (*DelRangeTask, error) {
+	value, err := m.txn.Get(context.Background(), delRangeKey(jobID, elementID))
+	if kv.IsErrNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	task := &DelRangeTask{}
+	err = json.Unmarshal(value, task)
+	return task, err
+}
+
+// ListDelRanges lists all the delete range tasks ordered by job ID.
+func (m *Meta) ListDelRanges() ([]*DelRangeTask, error) {
+	tasks := make([]*DelRangeTask, 0)
+	err := m.iterPrefix([]byte(mDelRangePrefix), func(value []byte) error {
+		task := &DelRangeTask{}
+		if err := json.Unmarshal(value, task); err != nil {
+			return err
+		}
+		tasks = append(tasks, task)
+		return nil
+	})
+	return tasks, err
+}
+
+// SetGCSafePoint saves the GC safe point, the data deleted before it may be cleaned up.
+func (m *Meta) SetGCSafePoint(safePoint uint64) error {
+	return m.txn.Set(mGCSafePointKey, []byte(strconv.FormatUint(safePoint, 10)))
+}
+
+// GetGCSafePoint gets the GC safe point, it's 0 if GC never runs.
+func (m *Meta) GetGCSafePoint() (uint64, error) {
+	value, err := m.txn.Get(context.Background(), mGCSafePointKey)
+	if kv.IsErrNotFound(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseUint(string(value), 10, 64)
+}
+
+// StatsMeta is the version and the row counts of the statistics of a table.
+type StatsMeta struct {
+	TableID int64 `json:"table_id"`
+	// Version is the start timestamp of the transaction which analyzes the table last time, it's 0 if
+	// the table isn't analyzed.
+	Version uint64 `json:"version"`
+	// Count is the row count of the table, which is kept updated by the DML.
+	Count int64 `json:"count"`
+	// ModifyCount is the number of the rows modified since the last analyze.
+	ModifyCount int64 `json:"modify_count"`
+}
+
+func statsMetaKey(tableID int64) kv.Key {
+	return codec.EncodeInt([]byte(mStatsMetaPrefix), tableID)
+}
+
+func statsHistPrefix(tableID int64) kv.Key {
+	return codec.EncodeInt([]byte(mStatsHistPrefix), tableID)
+}
+
+func statsHistKey(tableID int64, isIndex bool, histID int64) kv.Key {
+	flag := int64(0)
+	if isIndex {
+		flag = 1
+	}
+	return codec.EncodeInt(codec.EncodeInt(statsHistPrefix(tableID), flag), histID)
+}
+
+// SetStatsMeta saves the stats meta of a table.
+func (m *Meta) SetStatsMeta(statsMeta *StatsMeta) error {
+	return m.setJSON(statsMetaKey(statsMeta.TableID), statsMeta)
+}
+
+// GetStatsMeta gets the stats meta of a table, it returns nil if the table has no statistics.
+func (m *Meta) GetStatsMeta(tableID int64) (*StatsMeta, error) {
+	value, err := m.txn.Get(context.Background(), statsMetaKey(tableID))
+	if kv.IsErrNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	statsMeta := &StatsMeta{}
+	err = json.Unmarshal(value, statsMeta)
+	return statsMeta, err
+}
+
+// ListStatsMeta lists the stats meta of all the tables ordered by table ID.
+func (m *Meta) ListStatsMeta() ([]*StatsMeta, error) {
+	metas := make([]*StatsMeta, 0)
+	err := m.iterPrefix([]byte(mStatsMetaPrefix), func(value []byte) error {
+		statsMeta := &StatsMeta{}
+		if err := json.Unmarshal(value, statsMeta); err != nil {
+			return err
+		}
+		metas = append(metas, statsMeta)
+		return nil
+	})
+	return metas, err
+}
+
+// SetStatsHistogram saves the encoded histogram of a column or an index of a table.
+func (m *Meta) SetStatsHistogram(tableID int64, isIndex bool, histID int64, value []byte) error {
+	return m.txn.Set(statsHistKey(tableID, isIndex, histID), value)
+}
+
+// ListStatsHistograms lists the encoded histograms of the columns and the indices of a table.
+func (m *Meta) ListStatsHistograms(tableID int64) ([][]byte, error) {
+	var values [][]byte
+	err := m.iterPrefix(statsHistPrefix(tableID), func(value []byte) error {
+		values = append(values, append([]byte(nil), value...))
+		return nil
+	})
+	return values, err
+}
+
+// DropStats removes the statistics of a table.
+func (m *Meta) DropStats(tableID int64) error {
+	if err := m.txn.Delete(statsMetaKey(tableID)); err != nil {
+		return err
+	}
+	prefix := statsHistPrefix(tableID)
+	it, err := m.txn.Iter(prefix, prefix.PrefixNext())
+	if err != nil {
+		return err
+	}
+	var keys []kv.Key
+	for it.Valid() {
+		keys = append(keys, it.Key().Clone())
+		if err = it.Next(); err != nil {
+			it.Close()
+			return err
+		}
+	}
+	it.Close()
+	for _, key := range keys {
+		if err = m.txn.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package mysql
+
+import (
+	pmysql "github.com/pingcap/parser/mysql"
+)
+
+// SQLError records an error information, from executing SQL.
+type SQLError = pmysql.SQLError
+
+// ErrUnknown is the code of errors which have no corresponding MySQL error.
+const ErrUnknown = pmysql.ErrUnknown
+
+// NewErrf creates a SQL error, with an error code and a format specifier.
+func NewErrf(errCode uint16, format string, args ...interface{}) *SQLError {
+	return pmysql.NewErrf(errCode, format, args...)
+}
@@ -0,0 +1,149 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// saveInterval is how far ahead of the current physical time the high-water mark is persisted.
+// Timestamps are allocated freely below the saved mark, so the persister is touched at most
+// once per interval.
+const saveInterval = 3 * time.Second
+
+var (
+	// ErrFutureTSRead is returned when a stale read asks for a time later than now.
+	ErrFutureTSRead = errors.New("cannot read data in the future")
+	// ErrOracleClosed is returned when the oracle is used after Close.
+	ErrOracleClosed = errors.New("oracle is closed")
+)
+
+type localOracle struct {
+	sync.Mutex
+	persister Persister
+	physical  int64
+	logical   int64
+	// hwm is the persisted high-water mark of the physical part, no timestamp
+	// allocated by this oracle reaches it.
+	hwm    int64
+	closed bool
+	// now is replaceable in order to control the clock.
+	now func() time.Time
+}
+
+// NewLocalOracle creates an Oracle that allocates hybrid physical/logical timestamps in
+// process. If persister is not nil, the allocated timestamps never go backwards across restarts.
+func NewLocalOracle(persister Persister) (Oracle, error) {
+	o := &localOracle{
+		persister: persister,
+		now:       time.Now,
+	}
+	if err := o.syncTimestamp(); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// syncTimestamp loads the last saved high-water mark and reserves a new window above it.
+func (o *localOracle) syncTimestamp() error {
+	var last int64
+	if o.persister != nil {
+		ts, err := o.persister.Load()
+		if err != nil {
+			return err
+		}
+		last = ExtractPhysical(ts)
+	}
+	next := GetPhysical(o.now())
+	if next <= last {
+		next = last + 1
+	}
+	if err := o.saveHWM(next); err != nil {
+		return err
+	}
+	o.physical = next
+	o.logical = 0
+	return nil
+}
+
+func (o *localOracle) saveHWM(physical int64) error {
+	hwm := physical + int64(saveInterval/time.Millisecond)
+	if o.persister != nil {
+		if err := o.persister.Save(ComposeTS(hwm, 0)); err != nil {
+			return fmt.Errorf("save timestamp high-water mark: %v", err)
+		}
+	}
+	o.hwm = hwm
+	return nil
+}
+
+func (o *localOracle) GetTimestamp(ctx context.Context) (uint64, error) {
+	return o.GetTimestamps(ctx, 1)
+}
+
+func (o *localOracle) GetTimestamps(ctx context.Context, count uint32) (uint64, error) {
+	if count == 0 || count >= maxLogical {
+		return 0, fmt.Errorf("invalid timestamp count %d", count)
+	}
+	o.Lock()
+	defer o.Unlock()
+	if o.closed {
+		return 0, ErrOracleClosed
+	}
+	// The physical part follows the wall clock but never goes backwards,
+	// when the logical part is exhausted it is pushed forward by one millisecond.
+	if physical := GetPhysical(o.now()); physical > o.physical {
+		o.physical = physical
+		o.logical = 0
+	}
+	if o.logical+int64(count) >= maxLogical {
+		o.physical++
+		o.logical = 0
+	}
+	if o.physical >= o.hwm {
+		if err := o.saveHWM(o.physical); err != nil {
+			return 0, err
+		}
+	}
+	ts := ComposeTS(o.physical, o.logical+1)
+	o.logical += int64(count)
+	return ts, nil
+}
+
+func (o *localOracle) GetStaleTimestamp(t time.Time) (uint64, error) {
+	o.Lock()
+	physical := o.physical
+	o.Unlock()
+	stale := GetPhysical(t)
+	if stale > physical && stale > GetPhysical(o.now()) {
+		return 0, ErrFutureTSRead
+	}
+	return ComposeTS(stale, 0), nil
+}
+
+// GetExactStaleTimestamp counts back from the oracle's time, which doesn't go backwards with the wall clock,
+// so the stale read never reads later than the timestamps already allocated minus d.
+func (o *localOracle) GetExactStaleTimestamp(d time.Duration) (uint64, error) {
+	if d < 0 {
+		return 0, ErrFutureTSRead
+	}
+	o.Lock()
+	physical := o.physical
+	o.Unlock()
+	if now := GetPhysical(o.now()); now > physical {
+		physical = now
+	}
+	return ComposeTS(physical-int64(d/time.Millisecond), 0), nil
+}
+
+func (o *localOracle) IsExpired(lockTS uint64, TTL uint64) bool {
+	return GetPhysical(o.now()) >= ExtractPhysical(lockTS)+int64(TTL)
+}
+
+func (o *localOracle) Close() {
+	o.Lock()
+	o.closed = true
+	o.Unlock()
+}
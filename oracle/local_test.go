@@ -0,0 +1,194 @@
+package oracle
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// memPersister keeps the high-water mark in memory, a new oracle on it acts as a restarted one.
+type memPersister struct {
+	ts    uint64
+	saves int
+}
+
+func (p *memPersister) Load() (uint64, error) {
+	return p.ts, nil
+}
+
+func (p *memPersister) Save(ts uint64) error {
+	p.ts = ts
+	p.saves++
+	return nil
+}
+
+// fakeClock is a wall clock which only moves when it is told to.
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	return c.t
+}
+
+// newTestOracle creates an oracle on persister whose clock is controlled by the returned fakeClock.
+func newTestOracle(t *testing.T, persister Persister, start time.Time) (*localOracle, *fakeClock) {
+	clock := &fakeClock{t: start}
+	o := &localOracle{persister: persister, now: clock.now}
+	if err := o.syncTimestamp(); err != nil {
+		t.Fatal(err)
+	}
+	return o, clock
+}
+
+func mustGetTimestamp(t *testing.T, o Oracle) uint64 {
+	ts, err := o.GetTimestamp(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ts
+}
+
+func TestClockRegression(t *testing.T) {
+	start := time.Unix(1600000000, 0)
+	o, clock := newTestOracle(t, nil, start)
+	last := mustGetTimestamp(t, o)
+	steps := []time.Duration{time.Millisecond, -time.Hour, 0, time.Second, -time.Second, 2 * time.Hour}
+	for _, step := range steps {
+		clock.t = clock.t.Add(step)
+		for i := 0; i < 10; i++ {
+			ts := mustGetTimestamp(t, o)
+			if ts <= last {
+				t.Fatalf("the timestamp %d after the clock moves %v is not larger than %d", ts, step, last)
+			}
+			last = ts
+		}
+	}
+	if physical := ExtractPhysical(last); physical != GetPhysical(clock.t) {
+		t.Fatalf("the physical part is %d, expected to catch up with the clock %d", physical, GetPhysical(clock.t))
+	}
+}
+
+func TestRestartFromHighWaterMark(t *testing.T) {
+	start := time.Unix(1600000000, 0)
+	persister := &memPersister{}
+	o, clock := newTestOracle(t, persister, start)
+	var last uint64
+	for i := 0; i < 5; i++ {
+		clock.t = clock.t.Add(time.Second)
+		last = mustGetTimestamp(t, o)
+	}
+	if ExtractPhysical(last) >= ExtractPhysical(persister.ts) {
+		t.Fatalf("the timestamp %d reaches the high-water mark %d", last, persister.ts)
+	}
+	o.Close()
+	if _, err := o.GetTimestamp(context.Background()); err != ErrOracleClosed {
+		t.Fatalf("the error after Close is %v, expected %v", err, ErrOracleClosed)
+	}
+
+	// The restarted oracle's clock is an hour behind, it still begins above the saved mark.
+	restarted, _ := newTestOracle(t, persister, start.Add(-time.Hour))
+	if ts := mustGetTimestamp(t, restarted); ts <= last {
+		t.Fatalf("the timestamp %d after restart is not larger than %d", ts, last)
+	}
+}
+
+func TestFilePersister(t *testing.T) {
+	dir, err := ioutil.TempDir("", "oracle")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "oracle", "ts")
+	persister, err := NewFilePersister(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ts, err := persister.Load(); err != nil || ts != 0 {
+		t.Fatalf("Load() = %d, %v before any save, expected 0", ts, err)
+	}
+	o, err := NewLocalOracle(persister)
+	if err != nil {
+		t.Fatal(err)
+	}
+	last := mustGetTimestamp(t, o)
+	o.Close()
+	saved, err := persister.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved <= last {
+		t.Fatalf("the saved high-water mark %d is not larger than the allocated %d", saved, last)
+	}
+}
+
+func TestLogicalOverflow(t *testing.T) {
+	start := time.Unix(1600000000, 0)
+	persister := &memPersister{}
+	o, _ := newTestOracle(t, persister, start)
+	ctx := context.Background()
+	if _, err := o.GetTimestamps(ctx, maxLogical); err == nil {
+		t.Fatal("allocating a whole physical tick of timestamps succeeds")
+	}
+	// The clock is frozen, so the logical part overflows into the physical part.
+	last := mustGetTimestamp(t, o)
+	for i := 0; i < 5; i++ {
+		ts, err := o.GetTimestamps(ctx, maxLogical/2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ts <= last {
+			t.Fatalf("the timestamp %d is not larger than %d", ts, last)
+		}
+		if ExtractLogical(ts)+maxLogical/2 > maxLogical {
+			t.Fatalf("the range from %d crosses the physical tick", ts)
+		}
+		last = ts + maxLogical/2 - 1
+	}
+	if ExtractPhysical(last) <= GetPhysical(start) {
+		t.Fatalf("the physical part %d is not pushed forward", ExtractPhysical(last))
+	}
+
+	// Exhausting the window above the high-water mark saves a new one.
+	saves := persister.saves
+	window := int64(saveInterval / time.Millisecond)
+	for i := int64(0); i <= window; i++ {
+		if _, err := o.GetTimestamps(ctx, maxLogical-1); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if persister.saves == saves {
+		t.Fatal("the high-water mark is not saved again")
+	}
+	if ExtractPhysical(persister.ts) <= o.physical {
+		t.Fatalf("the saved high-water mark %d is not above the physical part %d", persister.ts, o.physical)
+	}
+}
+
+func TestExactStaleTimestamp(t *testing.T) {
+	start := time.Unix(1600000000, 0)
+	o, clock := newTestOracle(t, nil, start)
+	mustGetTimestamp(t, o)
+	ts, err := o.GetExactStaleTimestamp(10 * time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := GoTimeToTS(start.Add(-10 * time.Second)); ts != expected {
+		t.Fatalf("the stale timestamp is %d, expected %d", ts, expected)
+	}
+	// The stale read counts back from the oracle, not from a wall clock behind it.
+	clock.t = start.Add(-time.Hour)
+	ts, err = o.GetExactStaleTimestamp(10 * time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := GoTimeToTS(start.Add(-10 * time.Second)); ts != expected {
+		t.Fatalf("the stale timestamp after the clock regression is %d, expected %d", ts, expected)
+	}
+	if _, err = o.GetExactStaleTimestamp(-time.Second); err != ErrFutureTSRead {
+		t.Fatalf("the error of a negative staleness is %v, expected %v", err, ErrFutureTSRead)
+	}
+}
@@ -0,0 +1,61 @@
+package oracle
+
+import (
+	"context"
+	"time"
+)
+
+// Oracle is the interface that provides strictly ascending timestamps.
+type Oracle interface {
+	// GetTimestamp returns a new timestamp which is larger than any timestamp returned before.
+	GetTimestamp(ctx context.Context) (uint64, error)
+	// GetTimestamps allocates count continuous timestamps at once and returns the first one,
+	// the caller owns the range [ts, ts+count).
+	GetTimestamps(ctx context.Context, count uint32) (uint64, error)
+	// GetStaleTimestamp converts a wall clock time in the past into a version used by stale read.
+	GetStaleTimestamp(t time.Time) (uint64, error)
+	// GetExactStaleTimestamp returns the version used by stale read which is d before the current
+	// time of the oracle.
+	GetExactStaleTimestamp(d time.Duration) (uint64, error)
+	// IsExpired returns whether lockTS+TTL is expired, TTL is in milliseconds.
+	IsExpired(lockTS uint64, TTL uint64) bool
+	Close()
+}
+
+const (
+	// physicalShiftBits is the number of bits the logical part takes in a timestamp.
+	physicalShiftBits = 18
+	// maxLogical is the upper bound(exclusive) of the logical part.
+	maxLogical = 1 << physicalShiftBits
+)
+
+// ComposeTS creates a timestamp with physical and logical parts.
+func ComposeTS(physical, logical int64) uint64 {
+	return uint64((physical << physicalShiftBits) + logical)
+}
+
+// ExtractPhysical returns a ts's physical part.
+func ExtractPhysical(ts uint64) int64 {
+	return int64(ts >> physicalShiftBits)
+}
+
+// ExtractLogical returns a ts's logical part.
+func ExtractLogical(ts uint64) int64 {
+	return int64(ts & (maxLogical - 1))
+}
+
+// GetPhysical returns physical from an instant time with millisecond precision.
+func GetPhysical(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+// GetTimeFromTS extracts time.Time from a timestamp.
+func GetTimeFromTS(ts uint64) time.Time {
+	ms := ExtractPhysical(ts)
+	return time.Unix(ms/1e3, (ms%1e3)*1e6)
+}
+
+// GoTimeToTS converts a Go time to uint64 timestamp.
+func GoTimeToTS(t time.Time) uint64 {
+	return ComposeTS(GetPhysical(t), 0)
+}
@@ -0,0 +1,64 @@
+package oracle
+
+import (
+	"encoding/binary"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// Persister saves the timestamp high-water mark of an oracle.
+type Persister interface {
+	// Load returns the last saved timestamp, 0 if nothing has been saved.
+	Load() (uint64, error)
+	// Save durably records ts.
+	Save(ts uint64) error
+}
+
+type filePersister struct {
+	path string
+}
+
+// NewFilePersister creates a Persister which keeps the timestamp in the file at path.
+func NewFilePersister(path string) (Persister, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	return &filePersister{path: path}, nil
+}
+
+func (p *filePersister) Load() (uint64, error) {
+	data, err := ioutil.ReadFile(p.path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(data) != 8 {
+		return 0, errors.New("corrupted timestamp file " + p.path)
+	}
+	return binary.BigEndian.Uint64(data), nil
+}
+
+// Save writes to a temporary file and renames it, so a crash never leaves a torn file behind.
+func (p *filePersister) Save(ts uint64) error {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], ts)
+	tmp := p.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Write(buf[:]); err == nil {
+		err = f.Sync()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, p.path)
+}
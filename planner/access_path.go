@@ -0,0 +1,275 @@
+package planner
+
+import (
+	"math"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/expression"
+	"grant-db/types"
+	"grant-db/util/collate"
+	"grant-db/util/ranger"
+)
+
+// accessPath is a way to read the rows of a table, by the handle ranges of the table or the ranges of
+// an index.
+type accessPath struct {
+	index      *model.IndexInfo
+	idxCols    []*expression.Column
+	idxColLens []int
+	ranges     []*ranger.Range
+	// countAfterAccess is the row count after the ranges are applied and before the filters are applied.
+	countAfterAccess float64
+	// countAfterIndex is the row count after the index filters are applied.
+	countAfterIndex float64
+	accessConds     []expression.Expression
+	// eqCondCount is the number of the leading index columns which are equal to a single value.
+	eqCondCount int
+	// indexFilters are the filters evaluated on the index rows.
+	indexFilters []expression.Expression
+	// tableFilters are the filters evaluated on the table rows.
+	tableFilters []expression.Expression
+	isTablePath  bool
+	// isSingleScan is true if the index covers all the columns, the table rows aren't read then.
+	isSingleScan bool
+	// forced is true if the path is specified by the index hints.
+	forced bool
+}
+
+func getPathByIndexName(paths []*accessPath, idxName model.CIStr, tblInfo *model.TableInfo) *accessPath {
+	var tablePath *accessPath
+	for _, path := range paths {
+		if path.isTablePath {
+			tablePath = path
+			continue
+		}
+		if path.index.Name.L == idxName.L {
+			return path
+		}
+	}
+	if isPrimaryIndex(idxName) && tblInfo.PKIsHandle {
+		return tablePath
+	}
+	return nil
+}
+
+func isPrimaryIndex(indexName model.CIStr) bool {
+	return indexName.L == "primary"
+}
+
+// getPossibleAccessPaths gets the access paths of the table which are allowed by the index hints. The paths
+// of USE and FORCE INDEX are used only, and the ones of IGNORE INDEX are removed.
+func getPossibleAccessPaths(indexHints []*ast.IndexHint, tblInfo *model.TableInfo) ([]*accessPath, error) {
+	publicPaths := make([]*accessPath, 0, len(tblInfo.Indices)+1)
+	publicPaths = append(publicPaths, &accessPath{isTablePath: true})
+	for _, index := range tblInfo.Indices {
+		if index.State == model.StatePublic {
+			publicPaths = append(publicPaths, &accessPath{index: index})
+		}
+	}
+
+	hasScanHint, hasUseOrForce := false, false
+	available := make([]*accessPath, 0, len(publicPaths))
+	ignored := make([]*accessPath, 0, len(publicPaths))
+	for _, hint := range indexHints {
+		if hint.HintScope != ast.HintForScan {
+			continue
+		}
+		hasScanHint = true
+		// `USE INDEX ()` means the table is read without any index.
+		if hint.HintType == ast.HintUse && len(hint.IndexNames) == 0 {
+			hasUseOrForce = true
+			available = append(available, publicPaths[0])
+			continue
+		}
+		for _, idxName := range hint.IndexNames {
+			path := getPathByIndexName(publicPaths, idxName, tblInfo)
+			if path == nil {
+				return nil, ErrKeyDoesNotExist.GenWithStackByArgs(idxName, tblInfo.Name)
+			}
+			if hint.HintType == ast.HintIgnore {
+				ignored = append(ignored, path)
+				continue
+			}
+			// Currently we don't distinguish between "FORCE" and "USE" because
+			// our cost estimation is not reliable.
+			hasUseOrForce = true
+			path.forced = true
+			available = append(available, path)
+		}
+	}
+	if !hasScanHint || !hasUseOrForce {
+		available = publicPaths
+	}
+	available = removeIgnoredPaths(available, ignored)
+	// The table is read by the table path if all the paths are ignored.
+	if len(available) == 0 {
+		available = append(available, publicPaths[0])
+	}
+	return available, nil
+}
+
+func removeIgnoredPaths(paths, ignoredPaths []*accessPath) []*accessPath {
+	if len(ignoredPaths) == 0 {
+		return paths
+	}
+	remainedPaths := make([]*accessPath, 0, len(paths))
+	for _, path := range paths {
+		ignored := false
+		for _, ignoredPath := range ignoredPaths {
+			if path == ignoredPath {
+				ignored = true
+				break
+			}
+		}
+		if !ignored {
+			remainedPaths = append(remainedPaths, path)
+		}
+	}
+	return remainedPaths
+}
+
+// deriveTablePathStats builds the handle ranges of the table path, the conditions on the integer primary
+// key are the access conditions.
+func (ds *DataSource) deriveTablePathStats(path *accessPath) error {
+	sc := ds.ctx.GetSessionVars().StmtCtx
+	path.countAfterAccess = float64(ds.statisticTable.Count)
+	path.accessConds, path.tableFilters = nil, ds.pushedDownConds
+	pkCol := ds.getPKIsHandleCol()
+	if pkCol == nil {
+		path.ranges = ranger.FullIntRange(false)
+		return nil
+	}
+	path.ranges = ranger.FullIntRange(mysql.HasUnsignedFlag(pkCol.RetType.Flag))
+	if len(ds.pushedDownConds) == 0 {
+		return nil
+	}
+	accessConds, filters := ranger.DetachCondsForColumn(ds.ctx, ds.pushedDownConds, pkCol)
+	if len(accessConds) == 0 {
+		return nil
+	}
+	ranges, err := ranger.BuildTableRange(accessConds, sc, pkCol.RetType)
+	if err != nil {
+		// The values which can't be converted to the type of the handle are compared by the filters.
+		return nil
+	}
+	path.ranges, path.accessConds, path.tableFilters = ranges, accessConds, filters
+	path.countAfterAccess, err = ds.statisticTable.GetRowCountByIntColumnRanges(sc, pkCol.ID, path.ranges)
+	if err != nil {
+		return err
+	}
+	ds.adjustCountAfterAccess(path)
+	return nil
+}
+
+// adjustCountAfterAccess adjusts the count of the path if it's less than the count of the output rows,
+// which means the statistics are inconsistent. The count of the output rows is preferred because more
+// statistics are used to estimate it.
+func (ds *DataSource) adjustCountAfterAccess(path *accessPath) {
+	if path.countAfterAccess < ds.stats.RowCount {
+		path.countAfterAccess = math.Min(ds.stats.RowCount/selectionFactor, float64(ds.statisticTable.Count))
+	}
+}
+
+// deriveIndexPathStats builds the ranges of the index path and divides the other conditions to the index
+// filters and the table filters.
+func (ds *DataSource) deriveIndexPathStats(path *accessPath) error {
+	sc := ds.ctx.GetSessionVars().StmtCtx
+	path.ranges = ranger.FullRange()
+	path.countAfterAccess = float64(ds.statisticTable.Count)
+	path.accessConds, path.eqCondCount = nil, 0
+	path.idxCols, path.idxColLens = ds.indexPrefixCols(path.index)
+	remained := ds.pushedDownConds
+	if len(path.idxCols) > 0 {
+		res, err := ranger.DetachCondAndBuildRangeForIndex(ds.ctx, ds.pushedDownConds, path.idxCols, path.idxColLens)
+		if err != nil {
+			return err
+		}
+		path.ranges, path.accessConds, path.eqCondCount = res.Ranges, res.AccessConds, res.EqCondCount
+		remained = res.RemainedConds
+		path.countAfterAccess, err = ds.statisticTable.GetRowCountByIndexRanges(sc, path.index.ID, path.ranges)
+		if err != nil {
+			return err
+		}
+	}
+	path.indexFilters, path.tableFilters = nil, nil
+	for _, cond := range remained {
+		if ds.isCoveringCols(expression.ExtractColumns(cond), path.index) {
+			path.indexFilters = append(path.indexFilters, cond)
+		} else {
+			path.tableFilters = append(path.tableFilters, cond)
+		}
+	}
+	path.isSingleScan = ds.isCoveringCols(ds.schema.Columns, path.index)
+	ds.adjustCountAfterAccess(path)
+	path.countAfterIndex = path.countAfterAccess
+	if len(path.indexFilters) > 0 {
+		selectivity, err := ds.statisticTable.Selectivity(ds.ctx, path.indexFilters)
+		if err != nil {
+			selectivity = selectionFactor
+		}
+		path.countAfterIndex = math.Max(path.countAfterAccess*selectivity, ds.stats.RowCount)
+	}
+	return nil
+}
+
+// indexPrefixCols returns the columns of the index in the schema and their prefix lengths, the columns
+// stop at the first one which is pruned.
+func (ds *DataSource) indexPrefixCols(index *model.IndexInfo) ([]*expression.Column, []int) {
+	cols := make([]*expression.Column, 0, len(index.Columns))
+	lengths := make([]int, 0, len(index.Columns))
+	for _, idxCol := range index.Columns {
+		offset := ds.columnOffset(idxCol.Name)
+		if offset == -1 {
+			break
+		}
+		cols = append(cols, ds.schema.Columns[offset])
+		if idxCol.Length != types.UnspecifiedLength && idxCol.Length == ds.Columns[offset].Flen {
+			lengths = append(lengths, types.UnspecifiedLength)
+		} else {
+			lengths = append(lengths, idxCol.Length)
+		}
+	}
+	return cols, lengths
+}
+
+func (ds *DataSource) columnOffset(name model.CIStr) int {
+	for i, col := range ds.Columns {
+		if col.Name.L == name.L {
+			return i
+		}
+	}
+	return -1
+}
+
+// isCoveringCols checks whether the values of the columns can be read from the index. The values of the
+// prefix columns are cut and the strings of the collations which aren't binary are stored as the sort keys,
+// which can't be read from the index. The integer primary key and the extra handle column are the handle of
+// the index rows.
+func (ds *DataSource) isCoveringCols(cols []*expression.Column, index *model.IndexInfo) bool {
+	pkCol := ds.getPKIsHandleCol()
+	for _, col := range cols {
+		if (pkCol != nil && col.Equal(nil, pkCol)) || col.ID == model.ExtraHandleID {
+			continue
+		}
+		offset := ds.schema.ColumnIndex(col)
+		if offset == -1 || !indexCoveringColumn(ds.Columns[offset], index) {
+			return false
+		}
+	}
+	return true
+}
+
+func indexCoveringColumn(col *model.ColumnInfo, index *model.IndexInfo) bool {
+	for _, idxCol := range index.Columns {
+		if idxCol.Name.L != col.Name.L {
+			continue
+		}
+		if idxCol.Length != types.UnspecifiedLength && idxCol.Length != col.Flen {
+			return false
+		}
+		return col.EvalType() != types.ETString || collate.IsBinCollation(col.Collate)
+	}
+	return false
+}
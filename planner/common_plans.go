@@ -0,0 +1,137 @@
+package planner
+
+import (
+	"grant-db/expression"
+	"grant-db/planner/property"
+	"grant-db/sessionctx"
+	"grant-db/table"
+	"grant-db/types"
+)
+
+// The type names of the plans of the DML statements.
+const (
+	TypeInsert = "Insert"
+	TypeUpdate = "Update"
+	TypeDelete = "Delete"
+)
+
+// Insert writes the rows of VALUES or of the select plan to the table, it's also the plan of REPLACE.
+type Insert struct {
+	physicalSchemaProducer
+
+	Table table.Table
+	// Columns are the public columns the values are written to in order.
+	Columns []*table.Column
+	// Lists are the rows of VALUES, whose expressions are evaluated on the row of the public columns being
+	// built. A nil expression is DEFAULT.
+	Lists      [][]expression.Expression
+	SelectPlan PhysicalPlan
+	// OnDuplicate are the assignments of ON DUPLICATE KEY UPDATE, which are evaluated on the row of the
+	// public columns which conflicts followed by the row being inserted.
+	OnDuplicate []*expression.Assignment
+	IsReplace   bool
+	IgnoreErr   bool
+
+	// tableSchema and tableNames are the public columns of the table, dupSchema is tableSchema followed
+	// by the columns of the row being inserted which VALUES() refers to.
+	tableSchema *expression.Schema
+	tableNames  types.NameSlice
+	dupSchema   *expression.Schema
+}
+
+// Init initializes Insert.
+func (p Insert) Init(ctx sessionctx.Context, stats *property.StatsInfo) *Insert {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeInsert, &p)
+	p.stats = stats
+	return &p
+}
+
+// ResolveIndices implements PhysicalPlan interface.
+func (p *Insert) ResolveIndices() error {
+	for i, list := range p.Lists {
+		for j, expr := range list {
+			if expr == nil {
+				continue
+			}
+			resolved, err := expr.ResolveIndices(p.tableSchema)
+			if err != nil {
+				return err
+			}
+			p.Lists[i][j] = resolved
+		}
+	}
+	for _, assign := range p.OnDuplicate {
+		col, err := assign.Col.ResolveIndices(p.dupSchema)
+		if err != nil {
+			return err
+		}
+		assign.Col = col.(*expression.Column)
+		if assign.Expr, err = assign.Expr.ResolveIndices(p.dupSchema); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TblColPosInfo is the positions of the handle and the public columns of a table written by UPDATE or
+// DELETE in the rows of the select plan.
+type TblColPosInfo struct {
+	Table         table.Table
+	HandleOrdinal int
+	ColOrdinals   []int
+}
+
+// Update updates the rows of the tables read by the select plan.
+type Update struct {
+	physicalSchemaProducer
+
+	// OrderedList are the assignments of SET in order, whose columns are the ones of the select plan.
+	OrderedList    []*expression.Assignment
+	SelectPlan     PhysicalPlan
+	TblColPosInfos []TblColPosInfo
+	IgnoreErr      bool
+}
+
+// Init initializes Update.
+func (p Update) Init(ctx sessionctx.Context, stats *property.StatsInfo) *Update {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeUpdate, &p)
+	p.stats = stats
+	return &p
+}
+
+// ResolveIndices implements PhysicalPlan interface.
+func (p *Update) ResolveIndices() error {
+	schema := p.SelectPlan.Schema()
+	for _, assign := range p.OrderedList {
+		col, err := assign.Col.ResolveIndices(schema)
+		if err != nil {
+			return err
+		}
+		assign.Col = col.(*expression.Column)
+		if assign.Expr, err = assign.Expr.ResolveIndices(schema); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete deletes the rows of the tables read by the select plan.
+type Delete struct {
+	physicalSchemaProducer
+
+	SelectPlan     PhysicalPlan
+	TblColPosInfos []TblColPosInfo
+	IsMultiTable   bool
+}
+
+// Init initializes Delete.
+func (p Delete) Init(ctx sessionctx.Context, stats *property.StatsInfo) *Delete {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeDelete, &p)
+	p.stats = stats
+	return &p
+}
+
+// ResolveIndices implements PhysicalPlan interface, the select plan is resolved when it's optimized.
+func (p *Delete) ResolveIndices() error {
+	return nil
+}
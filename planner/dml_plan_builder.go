@@ -0,0 +1,501 @@
+package planner
+
+import (
+	"fmt"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"grant-db/expression"
+	"grant-db/planner/property"
+	"grant-db/table"
+)
+
+// dmlTarget is a table of the table references of UPDATE or DELETE, whose rows can be written.
+type dmlTarget struct {
+	tbl    table.Table
+	dbName model.CIStr
+	// tblName is the alias of the table if it has one.
+	tblName model.CIStr
+	// cols are the columns of the public columns of the table, handle is the column of the handle, which is
+	// the integer primary key or the extra handle column.
+	cols   []*expression.Column
+	handle *expression.Column
+}
+
+// buildDML builds the plan of INSERT, UPDATE or DELETE, the rows they read are optimized as a query.
+func (b *PlanBuilder) buildDML(node ast.Node) (PhysicalPlan, error) {
+	b.optFlag = flagPrunColumns
+	node.Accept(&aggFuncConverter{})
+	switch x := node.(type) {
+	case *ast.InsertStmt:
+		return b.buildInsert(x)
+	case *ast.UpdateStmt:
+		return b.buildUpdate(x)
+	case *ast.DeleteStmt:
+		return b.buildDelete(x)
+	}
+	return nil, ErrNotSupportedYet.GenWithStackByArgs(fmt.Sprintf("statement %T", node))
+}
+
+// optimizeSource optimizes the plan of the rows a DML statement reads.
+func (b *PlanBuilder) optimizeSource(p LogicalPlan) (PhysicalPlan, error) {
+	logic, err := logicalOptimize(b.optFlag, p)
+	if err != nil {
+		return nil, err
+	}
+	return physicalOptimize(logic)
+}
+
+// resolveTable finds the table of the name, the current database is used if the name isn't qualified.
+func (b *PlanBuilder) resolveTable(tn *ast.TableName) (model.CIStr, table.Table, error) {
+	dbName := tn.Schema
+	if dbName.L == "" {
+		if b.ctx.GetSessionVars().CurrentDB == "" {
+			return dbName, nil, ErrNoDB
+		}
+		dbName = model.NewCIStr(b.ctx.GetSessionVars().CurrentDB)
+	}
+	tbl, err := b.is.TableByName(dbName, tn.Name)
+	return dbName, tbl, err
+}
+
+func (b *PlanBuilder) buildInsert(insert *ast.InsertStmt) (PhysicalPlan, error) {
+	ts, ok := insert.Table.TableRefs.Left.(*ast.TableSource)
+	if !ok {
+		return nil, ErrNotSupportedYet.GenWithStackByArgs(fmt.Sprintf("table reference %T", insert.Table.TableRefs.Left))
+	}
+	tn, ok := ts.Source.(*ast.TableName)
+	if !ok {
+		return nil, ErrNotSupportedYet.GenWithStackByArgs(fmt.Sprintf("table source %T", ts.Source))
+	}
+	dbName, tbl, err := b.resolveTable(tn)
+	if err != nil {
+		return nil, err
+	}
+	colInfos := make([]*model.ColumnInfo, 0, len(tbl.Cols()))
+	for _, col := range tbl.Cols() {
+		colInfos = append(colInfos, col.ColumnInfo)
+	}
+	cols, names := expression.ColumnInfos2ColumnsAndNames(b.ctx, dbName, tbl.Meta().Name, colInfos)
+	insertPlan := Insert{
+		Table:       tbl,
+		IsReplace:   insert.IsReplace,
+		IgnoreErr:   insert.IgnoreErr,
+		tableSchema: expression.NewSchema(cols...),
+		tableNames:  names,
+	}.Init(b.ctx, nil)
+	// The values are rewritten over the row of the public columns being built.
+	mockTablePlan := LogicalTableDual{}.Init(b.ctx)
+	mockTablePlan.SetSchema(insertPlan.tableSchema)
+	mockTablePlan.names = names
+	b.curClause = fieldList
+	if insertPlan.Columns, err = insertColumns(insert, tbl); err != nil {
+		return nil, err
+	}
+	switch {
+	case len(insert.Setlist) > 0:
+		list := make([]ast.ExprNode, 0, len(insert.Setlist))
+		for _, assign := range insert.Setlist {
+			list = append(list, assign.Expr)
+		}
+		err = b.buildValuesList(insertPlan, mockTablePlan, [][]ast.ExprNode{list}, true)
+	case insert.Select != nil:
+		err = b.buildInsertSelect(insertPlan, insert.Select)
+	default:
+		err = b.buildValuesList(insertPlan, mockTablePlan, insert.Lists, len(insert.Columns) > 0)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = b.buildOnDuplicate(insertPlan, mockTablePlan, insert.OnDuplicate); err != nil {
+		return nil, err
+	}
+	if insertPlan.SelectPlan != nil {
+		insertPlan.stats = insertPlan.SelectPlan.statsInfo()
+		insertPlan.SetChildren(insertPlan.SelectPlan)
+	} else {
+		insertPlan.stats = &property.StatsInfo{RowCount: float64(len(insertPlan.Lists))}
+	}
+	return insertPlan, insertPlan.ResolveIndices()
+}
+
+// insertColumns returns the columns INSERT writes, which are the ones of the column list or SET, or all the
+// public columns.
+func insertColumns(insert *ast.InsertStmt, tbl table.Table) ([]*table.Column, error) {
+	var names []*ast.ColumnName
+	switch {
+	case len(insert.Setlist) > 0:
+		for _, assign := range insert.Setlist {
+			names = append(names, assign.Column)
+		}
+	case len(insert.Columns) > 0:
+		names = insert.Columns
+	default:
+		return tbl.Cols(), nil
+	}
+	cols := make([]*table.Column, 0, len(names))
+	seen := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		col := table.FindCol(tbl.Cols(), name.Name.O)
+		if col == nil {
+			return nil, ErrUnknownColumn.GenWithStackByArgs(name.Name.O, clauseMsg[fieldList])
+		}
+		if _, ok := seen[col.Name.L]; ok {
+			return nil, ErrFieldSpecifiedTwice.GenWithStackByArgs(col.Name.O)
+		}
+		seen[col.Name.L] = struct{}{}
+		cols = append(cols, col)
+	}
+	return cols, nil
+}
+
+// buildValuesList rewrites the rows of VALUES, an empty row is the default values of all the columns if the
+// columns aren't listed.
+func (b *PlanBuilder) buildValuesList(insertPlan *Insert, mockTablePlan LogicalPlan, lists [][]ast.ExprNode, explicitCols bool) error {
+	n := len(insertPlan.Columns)
+	insertPlan.Lists = make([][]expression.Expression, 0, len(lists))
+	for i, list := range lists {
+		if len(list) != n && (len(list) > 0 || explicitCols) {
+			return ErrWrongValueCountOnRow.GenWithStackByArgs(i + 1)
+		}
+		exprs := make([]expression.Expression, n)
+		for j, valueExpr := range list {
+			if isBareDefault(valueExpr) {
+				continue
+			}
+			expr, np, err := b.rewrite(valueExpr, mockTablePlan, nil, true)
+			if err != nil {
+				return err
+			}
+			if np != mockTablePlan {
+				return ErrNotSupportedYet.GenWithStackByArgs("sub query in VALUES")
+			}
+			exprs[j] = expr
+		}
+		insertPlan.Lists = append(insertPlan.Lists, exprs)
+	}
+	return nil
+}
+
+func (b *PlanBuilder) buildInsertSelect(insertPlan *Insert, sel ast.ResultSetNode) error {
+	p, err := b.buildResultSetNode(sel)
+	if err != nil {
+		return err
+	}
+	if p.Schema().Len() != len(insertPlan.Columns) {
+		return ErrWrongValueCountOnRow.GenWithStackByArgs(1)
+	}
+	insertPlan.SelectPlan, err = b.optimizeSource(p)
+	return err
+}
+
+// buildOnDuplicate rewrites the assignments of ON DUPLICATE KEY UPDATE over the public columns of the row
+// which conflicts, VALUES() refers to the columns of the row being inserted.
+func (b *PlanBuilder) buildOnDuplicate(insertPlan *Insert, mockTablePlan LogicalPlan, onDup []*ast.Assignment) error {
+	if len(onDup) == 0 {
+		return nil
+	}
+	cols := insertPlan.tableSchema.Columns
+	dupCols := make([]*expression.Column, 0, 2*len(cols))
+	dupCols = append(dupCols, cols...)
+	for _, col := range cols {
+		newCol := col.Clone().(*expression.Column)
+		newCol.UniqueID = b.ctx.GetSessionVars().AllocPlanColumnID()
+		dupCols = append(dupCols, newCol)
+	}
+	insertPlan.dupSchema = expression.NewSchema(dupCols...)
+	b.insertPlan = insertPlan
+	defer func() { b.insertPlan = nil }()
+	for _, assign := range onDup {
+		idx, err := expression.FindFieldName(insertPlan.tableNames, assign.Column)
+		if err != nil {
+			return err
+		}
+		if idx == -1 {
+			return ErrUnknownColumn.GenWithStackByArgs(assign.Column.String(), clauseMsg[fieldList])
+		}
+		expr, np, err := b.rewriteAssignment(assign.Expr, mockTablePlan, insertPlan.Table.Cols()[idx])
+		if err != nil {
+			return err
+		}
+		if np != mockTablePlan {
+			return ErrNotSupportedYet.GenWithStackByArgs("sub query in ON DUPLICATE KEY UPDATE")
+		}
+		insertPlan.OnDuplicate = append(insertPlan.OnDuplicate, &expression.Assignment{
+			Col:     cols[idx],
+			ColName: insertPlan.tableNames[idx].ColName,
+			Expr:    expr,
+		})
+	}
+	return nil
+}
+
+// rewriteAssignment rewrites the value assigned to the column, DEFAULT is the default value of the column.
+// The sub queries of the value are joined to p.
+func (b *PlanBuilder) rewriteAssignment(expr ast.ExprNode, p LogicalPlan, col *table.Column) (expression.Expression, LogicalPlan, error) {
+	if isBareDefault(expr) {
+		con, err := defaultValueConstant(col)
+		return con, p, err
+	}
+	return b.rewrite(expr, p, nil, true)
+}
+
+// isBareDefault checks whether the value is DEFAULT without a column name.
+func isBareDefault(expr ast.ExprNode) bool {
+	d, ok := expr.(*ast.DefaultExpr)
+	return ok && d.Name == nil
+}
+
+// defaultValueConstant returns the default value of the column as a constant.
+func defaultValueConstant(col *table.Column) (*expression.Constant, error) {
+	val, err := table.GetColDefaultValue(col.ColumnInfo)
+	if err != nil {
+		return nil, err
+	}
+	tp := col.FieldType
+	return &expression.Constant{Value: val, RetType: &tp}, nil
+}
+
+// buildDMLSource builds the plan of the rows UPDATE or DELETE reads, the tables of the table references are
+// recorded as the targets.
+func (b *PlanBuilder) buildDMLSource(tableRefs *ast.Join, where ast.ExprNode, order *ast.OrderByClause, limit *ast.Limit) (LogicalPlan, error) {
+	b.inDMLTableRefs = true
+	p, err := b.buildResultSetNode(tableRefs)
+	b.inDMLTableRefs = false
+	if err != nil {
+		return nil, err
+	}
+	if where != nil {
+		b.curClause = whereClause
+		if p, err = b.buildSelection(p, where, nil, nil); err != nil {
+			return nil, err
+		}
+	}
+	if order != nil {
+		if p, err = b.buildSort(p, order.Items, nil, nil, nil, 0); err != nil {
+			return nil, err
+		}
+	}
+	if limit != nil {
+		if p, err = b.buildLimit(p, limit); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// addDMLTarget records the data source of the table references of UPDATE or DELETE as a target, the extra
+// handle column is the last one if the handle isn't a column, whose name is only resolved if it's qualified.
+func (b *PlanBuilder) addDMLTarget(ds *DataSource) {
+	target := &dmlTarget{
+		tbl:     ds.table,
+		dbName:  ds.DBName,
+		tblName: ds.tableInfo.Name,
+		cols:    ds.schema.Columns[:len(ds.table.Cols())],
+	}
+	if ds.TableAsName.L != "" {
+		target.tblName = *ds.TableAsName
+	}
+	if target.handle = ds.getPKIsHandleCol(); target.handle == nil {
+		target.handle = ds.schema.Columns[len(ds.schema.Columns)-1]
+		ds.names[len(ds.names)-1].Redundant = true
+	}
+	b.dmlTargets = append(b.dmlTargets, target)
+}
+
+// findDMLTarget finds the target whose public columns have the column, the offset of it is returned too.
+func (b *PlanBuilder) findDMLTarget(col *expression.Column) (*dmlTarget, int) {
+	for _, target := range b.dmlTargets {
+		for i, c := range target.cols {
+			if c.UniqueID == col.UniqueID {
+				return target, i
+			}
+		}
+	}
+	return nil, -1
+}
+
+// tblColPosInfos finds the positions of the handles and the public columns of the targets in the schema.
+func tblColPosInfos(schema *expression.Schema, targets []*dmlTarget) ([]TblColPosInfo, error) {
+	infos := make([]TblColPosInfo, 0, len(targets))
+	for _, target := range targets {
+		info := TblColPosInfo{Table: target.tbl, HandleOrdinal: schema.ColumnIndex(target.handle)}
+		if info.HandleOrdinal == -1 {
+			return nil, ErrInternal.GenWithStackByArgs(fmt.Sprintf("the handle of %s is pruned", target.tblName.O))
+		}
+		for _, col := range target.cols {
+			offset := schema.ColumnIndex(col)
+			if offset == -1 {
+				return nil, ErrInternal.GenWithStackByArgs(fmt.Sprintf("the column %s is pruned", col))
+			}
+			info.ColOrdinals = append(info.ColOrdinals, offset)
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+func (b *PlanBuilder) buildUpdate(update *ast.UpdateStmt) (PhysicalPlan, error) {
+	b.pushTableHints(update.TableHints)
+	defer b.popTableHints()
+	if update.MultipleTable && update.Order != nil {
+		return nil, ErrWrongUsage.GenWithStackByArgs("UPDATE", "ORDER BY")
+	}
+	if update.MultipleTable && update.Limit != nil {
+		return nil, ErrWrongUsage.GenWithStackByArgs("UPDATE", "LIMIT")
+	}
+	p, err := b.buildDMLSource(update.TableRefs.TableRefs, update.Where, update.Order, update.Limit)
+	if err != nil {
+		return nil, err
+	}
+	// The columns are resolved before the sub queries of the values are joined.
+	b.curClause = fieldList
+	var targets []*dmlTarget
+	assignCols := make([]*expression.Column, 0, len(update.List))
+	tblCols := make([]*table.Column, 0, len(update.List))
+	for _, assign := range update.List {
+		idx, err := expression.FindFieldName(p.OutputNames(), assign.Column)
+		if err != nil {
+			return nil, err
+		}
+		if idx == -1 {
+			return nil, ErrUnknownColumn.GenWithStackByArgs(assign.Column.String(), clauseMsg[fieldList])
+		}
+		col := p.Schema().Columns[idx]
+		target, offset := b.findDMLTarget(col)
+		if target == nil {
+			return nil, ErrNonUpdatableTable.GenWithStackByArgs(p.OutputNames()[idx].TblName.O, "UPDATE")
+		}
+		if !containsTarget(targets, target) {
+			targets = append(targets, target)
+		}
+		assignCols = append(assignCols, col)
+		tblCols = append(tblCols, target.tbl.Cols()[offset])
+	}
+	updatePlan := Update{IgnoreErr: update.IgnoreErr}.Init(b.ctx, nil)
+	for i, assign := range update.List {
+		var expr expression.Expression
+		if expr, p, err = b.rewriteAssignment(assign.Expr, p, tblCols[i]); err != nil {
+			return nil, err
+		}
+		updatePlan.OrderedList = append(updatePlan.OrderedList, &expression.Assignment{
+			Col:     assignCols[i],
+			ColName: tblCols[i].Name,
+			Expr:    expr,
+		})
+	}
+	if updatePlan.SelectPlan, err = b.optimizeSource(p); err != nil {
+		return nil, err
+	}
+	if updatePlan.TblColPosInfos, err = tblColPosInfos(updatePlan.SelectPlan.Schema(), targets); err != nil {
+		return nil, err
+	}
+	updatePlan.stats = updatePlan.SelectPlan.statsInfo()
+	updatePlan.SetChildren(updatePlan.SelectPlan)
+	return updatePlan, updatePlan.ResolveIndices()
+}
+
+func containsTarget(targets []*dmlTarget, target *dmlTarget) bool {
+	for _, t := range targets {
+		if t == target {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *PlanBuilder) buildDelete(del *ast.DeleteStmt) (PhysicalPlan, error) {
+	b.pushTableHints(del.TableHints)
+	defer b.popTableHints()
+	if del.IsMultiTable && del.Order != nil {
+		return nil, ErrWrongUsage.GenWithStackByArgs("DELETE", "ORDER BY")
+	}
+	if del.IsMultiTable && del.Limit != nil {
+		return nil, ErrWrongUsage.GenWithStackByArgs("DELETE", "LIMIT")
+	}
+	p, err := b.buildDMLSource(del.TableRefs.TableRefs, del.Where, del.Order, del.Limit)
+	if err != nil {
+		return nil, err
+	}
+	targets := b.dmlTargets
+	if del.IsMultiTable {
+		targets = nil
+		for _, tn := range del.Tables.Tables {
+			target := b.findDeleteTarget(tn)
+			if target == nil {
+				return nil, ErrUnknownTable.GenWithStackByArgs(tn.Name.O, "MULTI DELETE")
+			}
+			if !containsTarget(targets, target) {
+				targets = append(targets, target)
+			}
+		}
+	}
+	selectPlan, err := b.optimizeSource(p)
+	if err != nil {
+		return nil, err
+	}
+	infos, err := tblColPosInfos(selectPlan.Schema(), targets)
+	if err != nil {
+		return nil, err
+	}
+	deletePlan := Delete{
+		SelectPlan:     selectPlan,
+		TblColPosInfos: infos,
+		IsMultiTable:   del.IsMultiTable,
+	}.Init(b.ctx, selectPlan.statsInfo())
+	deletePlan.SetChildren(selectPlan)
+	return deletePlan, nil
+}
+
+// findDeleteTarget finds the target of a table of the multiple table DELETE, which is named by the alias
+// of the table if it has one.
+func (b *PlanBuilder) findDeleteTarget(tn *ast.TableName) *dmlTarget {
+	for _, target := range b.dmlTargets {
+		if target.tblName.L == tn.Name.L && (tn.Schema.L == "" || tn.Schema.L == target.dbName.L) {
+			return target
+		}
+	}
+	return nil
+}
+
+// valuesToColumn resolves VALUES(col) of ON DUPLICATE KEY UPDATE to the column of the row being inserted,
+// VALUES() is NULL elsewhere.
+func (er *expressionRewriter) valuesToColumn(v *ast.ValuesExpr) (expression.Expression, error) {
+	insertPlan := er.b.insertPlan
+	if insertPlan == nil {
+		return expression.NewNull(), nil
+	}
+	idx, err := expression.FindFieldName(insertPlan.tableNames, v.Column.Name)
+	if err != nil {
+		return nil, err
+	}
+	if idx == -1 {
+		return nil, ErrUnknownColumn.GenWithStackByArgs(v.Column.Name.String(), clauseMsg[fieldList])
+	}
+	return insertPlan.dupSchema.Columns[insertPlan.tableSchema.Len()+idx], nil
+}
+
+// defaultToConstant rewrites DEFAULT(col) to the default value of the column of the base table.
+func (er *expressionRewriter) defaultToConstant(v *ast.DefaultExpr) (expression.Expression, error) {
+	if v.Name == nil {
+		return nil, ErrNotSupportedYet.GenWithStackByArgs("DEFAULT in expression")
+	}
+	names := er.p.OutputNames()
+	idx, err := expression.FindFieldName(names, v.Name)
+	if err != nil {
+		return nil, err
+	}
+	if idx == -1 {
+		return nil, ErrUnknownColumn.GenWithStackByArgs(v.Name.String(), clauseMsg[er.b.curClause])
+	}
+	name := names[idx]
+	tbl, err := er.b.is.TableByName(name.DBName, name.OrigTblName)
+	if err != nil {
+		return nil, err
+	}
+	col := table.FindCol(tbl.Cols(), name.OrigColName.O)
+	if col == nil {
+		return nil, ErrUnknownColumn.GenWithStackByArgs(v.Name.String(), clauseMsg[er.b.curClause])
+	}
+	return defaultValueConstant(col)
+}
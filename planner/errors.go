@@ -0,0 +1,85 @@
+package planner
+
+import (
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
+)
+
+var (
+	// ErrUnknownColumn is returned when a column can not be found in the clause.
+	ErrUnknownColumn = terror.ClassOptimizer.New(mysql.ErrBadField, mysql.MySQLErrName[mysql.ErrBadField])
+	// ErrBadTable is returned when the table of a wildcard can not be found.
+	ErrBadTable = terror.ClassOptimizer.New(mysql.ErrBadTable, mysql.MySQLErrName[mysql.ErrBadTable])
+	// ErrNonUniqTable is returned when two tables of a query have the same name.
+	ErrNonUniqTable = terror.ClassOptimizer.New(mysql.ErrNonuniqTable, mysql.MySQLErrName[mysql.ErrNonuniqTable])
+	// ErrInvalidGroupFuncUse is returned when an aggregate function is used where it's not allowed.
+	ErrInvalidGroupFuncUse = terror.ClassOptimizer.New(mysql.ErrInvalidGroupFuncUse, mysql.MySQLErrName[mysql.ErrInvalidGroupFuncUse])
+	// ErrWrongGroupField is returned when a query is grouped by an aggregate function.
+	ErrWrongGroupField = terror.ClassOptimizer.New(mysql.ErrWrongGroupField, mysql.MySQLErrName[mysql.ErrWrongGroupField])
+	// ErrWrongUsage is returned when two clauses can not be used together.
+	ErrWrongUsage = terror.ClassOptimizer.New(mysql.ErrWrongUsage, mysql.MySQLErrName[mysql.ErrWrongUsage])
+	// ErrOperandColumns is returned when an operand has a wrong number of columns.
+	ErrOperandColumns = terror.ClassOptimizer.New(mysql.ErrOperandColumns, mysql.MySQLErrName[mysql.ErrOperandColumns])
+	// ErrWrongNumberOfColumnsInSelect is returned when the selects of a UNION have different numbers of columns.
+	ErrWrongNumberOfColumnsInSelect = terror.ClassOptimizer.New(mysql.ErrWrongNumberOfColumnsInSelect, mysql.MySQLErrName[mysql.ErrWrongNumberOfColumnsInSelect])
+	// ErrUnknownTable is returned when a table of the multiple table DELETE can not be found in the table references.
+	ErrUnknownTable = terror.ClassOptimizer.New(mysql.ErrUnknownTable, mysql.MySQLErrName[mysql.ErrUnknownTable])
+	// ErrNonUpdatableTable is returned when the column assigned by UPDATE isn't a column of a base table.
+	ErrNonUpdatableTable = terror.ClassOptimizer.New(mysql.ErrNonUpdatableTable, mysql.MySQLErrName[mysql.ErrNonUpdatableTable])
+	// ErrFieldSpecifiedTwice is returned when a column is listed twice in INSERT.
+	ErrFieldSpecifiedTwice = terror.ClassOptimizer.New(mysql.ErrFieldSpecifiedTwice, mysql.MySQLErrName[mysql.ErrFieldSpecifiedTwice])
+	// ErrWrongValueCountOnRow is returned when a row of INSERT has a different number of values from the columns.
+	ErrWrongValueCountOnRow = terror.ClassOptimizer.New(mysql.ErrWrongValueCountOnRow, mysql.MySQLErrName[mysql.ErrWrongValueCountOnRow])
+	// ErrNoDB is returned when a table is not qualified and there is no current database.
+	ErrNoDB = terror.ClassOptimizer.New(mysql.ErrNoDB, mysql.MySQLErrName[mysql.ErrNoDB])
+	// ErrWrongArguments is returned when the arguments of a clause are invalid, like the non-constant LIMIT.
+	ErrWrongArguments = terror.ClassOptimizer.New(mysql.ErrWrongArguments, mysql.MySQLErrName[mysql.ErrWrongArguments])
+	// ErrNoTablesUsed is returned when the wildcard is used without FROM.
+	ErrNoTablesUsed = terror.ClassOptimizer.New(mysql.ErrNoTablesUsed, mysql.MySQLErrName[mysql.ErrNoTablesUsed])
+	// ErrNotSupportedYet is returned when a statement is not supported.
+	ErrNotSupportedYet = terror.ClassOptimizer.New(mysql.ErrNotSupportedYet, mysql.MySQLErrName[mysql.ErrNotSupportedYet])
+	// ErrKeyDoesNotExist is returned when an index of the index hints can not be found.
+	ErrKeyDoesNotExist = terror.ClassOptimizer.New(mysql.ErrKeyDoesNotExist, mysql.MySQLErrName[mysql.ErrKeyDoesNotExist])
+	// ErrUnknownExplainFormat is returned when the format of EXPLAIN is unknown.
+	ErrUnknownExplainFormat = terror.ClassOptimizer.New(mysql.ErrUnknownExplainFormat, mysql.MySQLErrName[mysql.ErrUnknownExplainFormat])
+	// ErrInternal is the warning of the optimizer hints which can't be applied.
+	ErrInternal = terror.ClassOptimizer.New(mysql.ErrInternal, mysql.MySQLErrName[mysql.ErrInternal])
+	// ErrWindowNoSuchWindow is returned when a window references a window name which is not defined.
+	ErrWindowNoSuchWindow = terror.ClassOptimizer.New(mysql.ErrWindowNoSuchWindow, mysql.MySQLErrName[mysql.ErrWindowNoSuchWindow])
+	// ErrWindowCircularityInWindowGraph is returned when the named windows reference each other circularly.
+	ErrWindowCircularityInWindowGraph = terror.ClassOptimizer.New(mysql.ErrWindowCircularityInWindowGraph, mysql.MySQLErrName[mysql.ErrWindowCircularityInWindowGraph])
+	// ErrWindowNoChildPartitioning is returned when a window which references another one defines PARTITION BY.
+	ErrWindowNoChildPartitioning = terror.ClassOptimizer.New(mysql.ErrWindowNoChildPartitioning, mysql.MySQLErrName[mysql.ErrWindowNoChildPartitioning])
+	// ErrWindowNoInherentFrame is returned when a window with a frame is referenced by another one.
+	ErrWindowNoInherentFrame = terror.ClassOptimizer.New(mysql.ErrWindowNoInherentFrame, mysql.MySQLErrName[mysql.ErrWindowNoInherentFrame])
+	// ErrWindowNoRedefineOrderBy is returned when a window and the window it references both have ORDER BY.
+	ErrWindowNoRedefineOrderBy = terror.ClassOptimizer.New(mysql.ErrWindowNoRedefineOrderBy, mysql.MySQLErrName[mysql.ErrWindowNoRedefineOrderBy])
+	// ErrWindowFrameStartIllegal is returned when the frame starts with UNBOUNDED FOLLOWING.
+	ErrWindowFrameStartIllegal = terror.ClassOptimizer.New(mysql.ErrWindowFrameStartIllegal, mysql.MySQLErrName[mysql.ErrWindowFrameStartIllegal])
+	// ErrWindowFrameEndIllegal is returned when the frame ends with UNBOUNDED PRECEDING.
+	ErrWindowFrameEndIllegal = terror.ClassOptimizer.New(mysql.ErrWindowFrameEndIllegal, mysql.MySQLErrName[mysql.ErrWindowFrameEndIllegal])
+	// ErrWindowFrameIllegal is returned when a frame bound is invalid or the frame starts after its end.
+	ErrWindowFrameIllegal = terror.ClassOptimizer.New(mysql.ErrWindowFrameIllegal, mysql.MySQLErrName[mysql.ErrWindowFrameIllegal])
+	// ErrWindowRangeFrameOrderType is returned when a RANGE frame with N PRECEDING or FOLLOWING doesn't have exactly one ORDER BY item.
+	ErrWindowRangeFrameOrderType = terror.ClassOptimizer.New(mysql.ErrWindowRangeFrameOrderType, mysql.MySQLErrName[mysql.ErrWindowRangeFrameOrderType])
+	// ErrWindowRangeFrameTemporalType is returned when a RANGE frame over a temporal ORDER BY item has a numeric bound.
+	ErrWindowRangeFrameTemporalType = terror.ClassOptimizer.New(mysql.ErrWindowRangeFrameTemporalType, mysql.MySQLErrName[mysql.ErrWindowRangeFrameTemporalType])
+	// ErrWindowRangeFrameNumericType is returned when a RANGE frame over a numeric ORDER BY item has an INTERVAL bound.
+	ErrWindowRangeFrameNumericType = terror.ClassOptimizer.New(mysql.ErrWindowRangeFrameNumericType, mysql.MySQLErrName[mysql.ErrWindowRangeFrameNumericType])
+	// ErrWindowRangeBoundNotConstant is returned when a frame bound is not a constant.
+	ErrWindowRangeBoundNotConstant = terror.ClassOptimizer.New(mysql.ErrWindowRangeBoundNotConstant, mysql.MySQLErrName[mysql.ErrWindowRangeBoundNotConstant])
+	// ErrWindowDuplicateName is returned when two windows have the same name.
+	ErrWindowDuplicateName = terror.ClassOptimizer.New(mysql.ErrWindowDuplicateName, mysql.MySQLErrName[mysql.ErrWindowDuplicateName])
+	// ErrWindowIllegalOrderBy is returned when the position is used in PARTITION BY or ORDER BY of a window.
+	ErrWindowIllegalOrderBy = terror.ClassOptimizer.New(mysql.ErrWindowIllegalOrderBy, mysql.MySQLErrName[mysql.ErrWindowIllegalOrderBy])
+	// ErrWindowInvalidWindowFuncUse is returned when a window function is used where it's not allowed.
+	ErrWindowInvalidWindowFuncUse = terror.ClassOptimizer.New(mysql.ErrWindowInvalidWindowFuncUse, mysql.MySQLErrName[mysql.ErrWindowInvalidWindowFuncUse])
+	// ErrWindowInvalidWindowFuncAliasUse is returned when the alias of a field containing a window function is used where it's not allowed.
+	ErrWindowInvalidWindowFuncAliasUse = terror.ClassOptimizer.New(mysql.ErrWindowInvalidWindowFuncAliasUse, mysql.MySQLErrName[mysql.ErrWindowInvalidWindowFuncAliasUse])
+	// ErrWindowNestedWindowFuncUseInWindowSpec is returned when a window function is nested in a window function or a window.
+	ErrWindowNestedWindowFuncUseInWindowSpec = terror.ClassOptimizer.New(mysql.ErrWindowNestedWindowFuncUseInWindowSpec, mysql.MySQLErrName[mysql.ErrWindowNestedWindowFuncUseInWindowSpec])
+	// ErrWindowRowsIntervalUse is returned when a ROWS frame has an INTERVAL bound.
+	ErrWindowRowsIntervalUse = terror.ClassOptimizer.New(mysql.ErrWindowRowsIntervalUse, mysql.MySQLErrName[mysql.ErrWindowRowsIntervalUse])
+	// ErrWindowFunctionIgnoresFrame is the warning of the window functions which ignore the frame.
+	ErrWindowFunctionIgnoresFrame = terror.ClassOptimizer.New(mysql.ErrWindowFunctionIgnoresFrame, mysql.MySQLErrName[mysql.ErrWindowFunctionIgnoresFrame])
+)
@@ -0,0 +1,552 @@
+package planner
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/pingcap/parser/mysql"
+	"grant-db/expression"
+	"grant-db/planner/property"
+	"grant-db/sessionctx"
+)
+
+// warnInapplicableHint warns once that the hint can't be applied, it's warned for the empty property only
+// because the other properties may be satisfied by the sort over the plan of the hint.
+func warnInapplicableHint(ctx sessionctx.Context, prop *property.PhysicalProperty, warned *bool, hint string) {
+	if !prop.IsEmpty() || *warned {
+		return
+	}
+	*warned = true
+	ctx.GetSessionVars().StmtCtx.AppendWarning(ErrInternal.GenWithStackByArgs(fmt.Sprintf("Optimizer Hint %s is inapplicable", hint)))
+}
+
+// scaleExpectCnt returns the expected count of the child of the expected count of the parent, the rows of
+// the child are read in the same ratio.
+func scaleExpectCnt(prop *property.PhysicalProperty, parent, child *property.StatsInfo) float64 {
+	if prop.ExpectedCnt >= parent.RowCount || parent.RowCount == 0 {
+		return math.MaxFloat64
+	}
+	return child.RowCount * prop.ExpectedCnt / parent.RowCount
+}
+
+func (p *LogicalSelection) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
+	childProp := prop.Clone()
+	if prop.ExpectedCnt < p.stats.RowCount && p.stats.RowCount > 0 {
+		childProp.ExpectedCnt = prop.ExpectedCnt * p.children[0].statsInfo().RowCount / p.stats.RowCount
+	}
+	sel := PhysicalSelection{Conditions: p.Conditions}.Init(p.ctx, p.stats.ScaleByExpectCnt(prop.ExpectedCnt), childProp)
+	return []PhysicalPlan{sel}
+}
+
+// tryToGetChildProp maps the columns of the property to the columns of the child, which fails if a column
+// isn't a column of the child.
+func (p *LogicalProjection) tryToGetChildProp(prop *property.PhysicalProperty) (*property.PhysicalProperty, bool) {
+	newProp := &property.PhysicalProperty{ExpectedCnt: prop.ExpectedCnt}
+	for _, item := range prop.Items {
+		idx := p.schema.ColumnIndex(item.Col)
+		if idx == -1 {
+			return nil, false
+		}
+		col, ok := p.Exprs[idx].(*expression.Column)
+		if !ok {
+			return nil, false
+		}
+		newProp.Items = append(newProp.Items, property.SortItem{Col: col, Desc: item.Desc})
+	}
+	return newProp, true
+}
+
+func (p *LogicalProjection) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
+	newProp, ok := p.tryToGetChildProp(prop)
+	if !ok {
+		return nil
+	}
+	proj := PhysicalProjection{Exprs: p.Exprs}.Init(p.ctx, p.stats.ScaleByExpectCnt(prop.ExpectedCnt), newProp)
+	proj.SetSchema(p.schema)
+	proj.names = p.names
+	return []PhysicalPlan{proj}
+}
+
+// matchItems checks whether the property is a prefix of the items.
+func matchItems(p *property.PhysicalProperty, items []*ByItems) bool {
+	if len(items) < len(p.Items) {
+		return false
+	}
+	for i, col := range p.Items {
+		sortItem := items[i]
+		if sortItem.Desc != col.Desc || !sortItem.Expr.Equal(nil, col.Col) {
+			return false
+		}
+	}
+	return true
+}
+
+// getSortItemsProp returns the property of the items, it's nil if an item isn't a column.
+func getSortItemsProp(items []*ByItems, expectedCnt float64) *property.PhysicalProperty {
+	prop := &property.PhysicalProperty{ExpectedCnt: expectedCnt}
+	for _, item := range items {
+		col, ok := item.Expr.(*expression.Column)
+		if !ok {
+			return nil
+		}
+		prop.Items = append(prop.Items, property.SortItem{Col: col, Desc: item.Desc})
+	}
+	return prop
+}
+
+// exhaustPhysicalPlans implements LogicalPlan interface, the child may return the rows in the order and then
+// the sort is nominal.
+func (ls *LogicalSort) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
+	if !matchItems(prop, ls.ByItems) {
+		return nil
+	}
+	sort := PhysicalSort{ByItems: ls.ByItems}.Init(ls.ctx, ls.stats.ScaleByExpectCnt(prop.ExpectedCnt),
+		&property.PhysicalProperty{ExpectedCnt: math.MaxFloat64})
+	ret := []PhysicalPlan{sort}
+	if childProp := getSortItemsProp(ls.ByItems, prop.ExpectedCnt); childProp != nil {
+		ret = append(ret, NominalSort{}.Init(ls.ctx, childProp))
+	}
+	return ret
+}
+
+func (p *LogicalLimit) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
+	if !prop.IsEmpty() {
+		return nil
+	}
+	limit := PhysicalLimit{Offset: p.Offset, Count: p.Count}.Init(p.ctx, p.stats,
+		&property.PhysicalProperty{ExpectedCnt: float64(p.Count + p.Offset)})
+	return []PhysicalPlan{limit}
+}
+
+// exhaustPhysicalPlans implements LogicalPlan interface, the top-n is a limit if the child returns the rows
+// in the order.
+func (lt *LogicalTopN) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
+	if !matchItems(prop, lt.ByItems) {
+		return nil
+	}
+	topN := PhysicalTopN{ByItems: lt.ByItems, Offset: lt.Offset, Count: lt.Count}.Init(lt.ctx, lt.stats,
+		&property.PhysicalProperty{ExpectedCnt: math.MaxFloat64})
+	ret := []PhysicalPlan{topN}
+	if childProp := getSortItemsProp(lt.ByItems, float64(lt.Count+lt.Offset)); childProp != nil {
+		limit := PhysicalLimit{Offset: lt.Offset, Count: lt.Count}.Init(lt.ctx, lt.stats, childProp)
+		ret = append(ret, limit)
+	}
+	return ret
+}
+
+func (p *LogicalUnionAll) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
+	if !prop.IsEmpty() {
+		return nil
+	}
+	chReqProps := make([]*property.PhysicalProperty, 0, len(p.children))
+	for range p.children {
+		chReqProps = append(chReqProps, &property.PhysicalProperty{ExpectedCnt: prop.ExpectedCnt})
+	}
+	ua := PhysicalUnionAll{}.Init(p.ctx, p.stats.ScaleByExpectCnt(prop.ExpectedCnt), chReqProps...)
+	ua.SetSchema(p.schema)
+	ua.names = p.names
+	return []PhysicalPlan{ua}
+}
+
+func (p *LogicalMaxOneRow) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
+	if !prop.IsEmpty() {
+		return nil
+	}
+	// The second row is read to check whether there is more than one row.
+	mor := PhysicalMaxOneRow{}.Init(p.ctx, p.stats, &property.PhysicalProperty{ExpectedCnt: 2})
+	return []PhysicalPlan{mor}
+}
+
+// exhaustPhysicalPlans implements LogicalPlan interface, the child returns the rows ordered by the PARTITION
+// BY columns and then the ORDER BY columns, and the window keeps the order.
+func (p *LogicalWindow) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
+	childProp := &property.PhysicalProperty{
+		Items:       make([]property.SortItem, 0, len(p.PartitionBy)+len(p.OrderBy)),
+		ExpectedCnt: math.MaxFloat64,
+	}
+	childProp.Items = append(childProp.Items, p.PartitionBy...)
+	childProp.Items = append(childProp.Items, p.OrderBy...)
+	if !prop.IsPrefix(childProp) {
+		return nil
+	}
+	window := PhysicalWindow{
+		WindowFuncDescs: p.WindowFuncDescs,
+		PartitionBy:     p.PartitionBy,
+		OrderBy:         p.OrderBy,
+		Frame:           p.Frame,
+	}.Init(p.ctx, p.stats.ScaleByExpectCnt(prop.ExpectedCnt), childProp)
+	window.SetSchema(p.schema)
+	window.names = p.names
+	return []PhysicalPlan{window}
+}
+
+// exhaustPhysicalPlans implements LogicalPlan interface, the hash aggregation and the stream aggregation
+// are chosen by the hints first.
+func (la *LogicalAggregation) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
+	hashAggs := la.getHashAggs(prop)
+	if la.aggHints.preferAggType&preferHashAgg > 0 {
+		if len(hashAggs) > 0 {
+			return hashAggs
+		}
+		warnInapplicableHint(la.ctx, prop, &la.hintWarned, "HASH_AGG")
+	}
+	streamAggs := la.getStreamAggs(prop)
+	if la.aggHints.preferAggType&preferStreamAgg > 0 {
+		if len(streamAggs) > 0 {
+			return streamAggs
+		}
+		warnInapplicableHint(la.ctx, prop, &la.hintWarned, "STREAM_AGG")
+	}
+	return append(hashAggs, streamAggs...)
+}
+
+func (la *LogicalAggregation) getHashAggs(prop *property.PhysicalProperty) []PhysicalPlan {
+	if !prop.IsEmpty() {
+		return nil
+	}
+	agg := PhysicalHashAgg{basePhysicalAgg{AggFuncs: la.AggFuncs, GroupByItems: la.GroupByItems}}.Init(
+		la.ctx, la.stats.ScaleByExpectCnt(prop.ExpectedCnt), &property.PhysicalProperty{ExpectedCnt: math.MaxFloat64})
+	agg.SetSchema(la.schema)
+	agg.names = la.names
+	return []PhysicalPlan{agg}
+}
+
+// getStreamAggs returns the stream aggregation if the group by items are columns, the child returns the
+// rows ordered by the columns of the property first and then the other group by columns.
+func (la *LogicalAggregation) getStreamAggs(prop *property.PhysicalProperty) []PhysicalPlan {
+	gbyCols := la.groupByCols()
+	if len(gbyCols) != len(la.GroupByItems) {
+		return nil
+	}
+	all, desc := prop.AllSameOrder()
+	if !all {
+		return nil
+	}
+	childProp := &property.PhysicalProperty{ExpectedCnt: scaleExpectCnt(prop, la.stats, la.children[0].statsInfo())}
+	if len(gbyCols) > 0 {
+		used := make([]bool, len(gbyCols))
+		for _, item := range prop.Items {
+			found := false
+			for i, col := range gbyCols {
+				if !used[i] && col.Equal(nil, item.Col) {
+					used[i], found = true, true
+					break
+				}
+			}
+			if !found {
+				return nil
+			}
+			childProp.Items = append(childProp.Items, item)
+		}
+		for _, col := range gbyCols {
+			if !itemsContainCol(childProp.Items, col) {
+				childProp.Items = append(childProp.Items, property.SortItem{Col: col, Desc: desc})
+			}
+		}
+	}
+	agg := PhysicalStreamAgg{basePhysicalAgg{AggFuncs: la.AggFuncs, GroupByItems: la.GroupByItems}}.Init(
+		la.ctx, la.stats.ScaleByExpectCnt(prop.ExpectedCnt), childProp)
+	agg.SetSchema(la.schema)
+	agg.names = la.names
+	return []PhysicalPlan{agg}
+}
+
+func itemsContainCol(items []property.SortItem, col *expression.Column) bool {
+	for _, item := range items {
+		if item.Col.Equal(nil, col) {
+			return true
+		}
+	}
+	return false
+}
+
+// exhaustPhysicalPlans implements LogicalPlan interface, the merge join, the index join and the hash join
+// are chosen by the hints first.
+func (p *LogicalJoin) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
+	mergeJoins := p.getMergeJoin(prop)
+	if p.preferJoinType&preferMergeJoin > 0 {
+		if len(mergeJoins) > 0 {
+			return mergeJoins
+		}
+		warnInapplicableHint(p.ctx, prop, &p.hintWarned, "MERGE_JOIN")
+	}
+	joins := mergeJoins
+	indexJoins, forced := p.tryToGetIndexJoin(prop)
+	if forced {
+		return indexJoins
+	}
+	if p.preferJoinType&(preferLeftAsIndexInner|preferRightAsIndexInner) > 0 {
+		warnInapplicableHint(p.ctx, prop, &p.hintWarned, "INL_JOIN")
+	}
+	joins = append(joins, indexJoins...)
+	hashJoins := p.getHashJoins(prop)
+	if p.preferJoinType&preferHashJoin > 0 && len(hashJoins) > 0 {
+		return hashJoins
+	}
+	return append(joins, hashJoins...)
+}
+
+func (p *LogicalJoin) newBasePhysicalJoin(innerIdx int, leftKeys, rightKeys []*expression.Column) basePhysicalJoin {
+	return basePhysicalJoin{
+		JoinType:        p.JoinType,
+		LeftConditions:  p.LeftConditions,
+		RightConditions: p.RightConditions,
+		OtherConditions: p.OtherConditions,
+		InnerChildIdx:   innerIdx,
+		LeftJoinKeys:    leftKeys,
+		RightJoinKeys:   rightKeys,
+		NullAware:       p.NullAware,
+		DefaultValues:   p.DefaultValues,
+	}
+}
+
+// getMergeJoin returns the merge join whose keys are ordered by the property. The order of the rows of an
+// outer join is kept by the keys of the outer child only, the NULLs of the inner columns are out of order.
+func (p *LogicalJoin) getMergeJoin(prop *property.PhysicalProperty) []PhysicalPlan {
+	if len(p.EqualConditions) == 0 || p.NullAware {
+		return nil
+	}
+	all, desc := prop.AllSameOrder()
+	if !all {
+		return nil
+	}
+	leftKeys, rightKeys := p.joinKeys()
+	if !orderedByValues(leftKeys) || !orderedByValues(rightKeys) {
+		return nil
+	}
+	useLeft := p.JoinType != RightOuterJoin
+	useRight := p.JoinType == InnerJoin || p.JoinType == RightOuterJoin
+	offsets := make([]int, 0, len(leftKeys))
+	used := make([]bool, len(leftKeys))
+	for _, item := range prop.Items {
+		offset := -1
+		for i := range leftKeys {
+			if used[i] {
+				continue
+			}
+			if (useLeft && item.Col.Equal(nil, leftKeys[i])) || (useRight && item.Col.Equal(nil, rightKeys[i])) {
+				offset = i
+				break
+			}
+		}
+		if offset == -1 {
+			return nil
+		}
+		used[offset] = true
+		offsets = append(offsets, offset)
+	}
+	for i := range leftKeys {
+		if !used[i] {
+			offsets = append(offsets, i)
+		}
+	}
+	newLeftKeys := make([]*expression.Column, 0, len(offsets))
+	newRightKeys := make([]*expression.Column, 0, len(offsets))
+	for _, offset := range offsets {
+		newLeftKeys = append(newLeftKeys, leftKeys[offset])
+		newRightKeys = append(newRightKeys, rightKeys[offset])
+	}
+	lProp := property.NewPhysicalProperty(newLeftKeys, desc)
+	rProp := property.NewPhysicalProperty(newRightKeys, desc)
+	lProp.ExpectedCnt = scaleExpectCnt(prop, p.stats, p.children[0].statsInfo())
+	rProp.ExpectedCnt = scaleExpectCnt(prop, p.stats, p.children[1].statsInfo())
+	innerIdx := 1
+	if p.JoinType == RightOuterJoin {
+		innerIdx = 0
+	}
+	mergeJoin := PhysicalMergeJoin{
+		basePhysicalJoin: p.newBasePhysicalJoin(innerIdx, newLeftKeys, newRightKeys),
+		Desc:             desc,
+	}.Init(p.ctx, p.stats.ScaleByExpectCnt(prop.ExpectedCnt), lProp, rProp)
+	mergeJoin.SetSchema(p.schema)
+	mergeJoin.names = p.names
+	return []PhysicalPlan{mergeJoin}
+}
+
+// orderedByValues checks whether the keys are ordered by their values, the enums and the sets are ordered by
+// their indexes, which can't be merged with the values of the other types.
+func orderedByValues(keys []*expression.Column) bool {
+	for _, key := range keys {
+		if tp := key.RetType.Tp; tp == mysql.TypeEnum || tp == mysql.TypeSet {
+			return false
+		}
+	}
+	return true
+}
+
+// getHashJoins returns the hash joins, the hash table is built by the inner child of an outer join or a semi
+// join, and by either child of an inner join.
+func (p *LogicalJoin) getHashJoins(prop *property.PhysicalProperty) []PhysicalPlan {
+	if !prop.IsEmpty() {
+		return nil
+	}
+	switch p.JoinType {
+	case RightOuterJoin:
+		return []PhysicalPlan{p.getHashJoin(prop, 0)}
+	case InnerJoin:
+		return []PhysicalPlan{p.getHashJoin(prop, 1), p.getHashJoin(prop, 0)}
+	}
+	return []PhysicalPlan{p.getHashJoin(prop, 1)}
+}
+
+func (p *LogicalJoin) getHashJoin(prop *property.PhysicalProperty, innerIdx int) *PhysicalHashJoin {
+	chReqProps := make([]*property.PhysicalProperty, 2)
+	chReqProps[innerIdx] = &property.PhysicalProperty{ExpectedCnt: math.MaxFloat64}
+	chReqProps[1-innerIdx] = &property.PhysicalProperty{
+		ExpectedCnt: scaleExpectCnt(prop, p.stats, p.children[1-innerIdx].statsInfo()),
+	}
+	leftKeys, rightKeys := p.joinKeys()
+	hashJoin := PhysicalHashJoin{
+		basePhysicalJoin: p.newBasePhysicalJoin(innerIdx, leftKeys, rightKeys),
+		EqualConditions:  p.EqualConditions,
+	}.Init(p.ctx, p.stats.ScaleByExpectCnt(prop.ExpectedCnt), chReqProps...)
+	hashJoin.SetSchema(p.schema)
+	hashJoin.names = p.names
+	return hashJoin
+}
+
+// tryToGetIndexJoin returns the index joins whose inner child is read by the index of the join keys, the
+// index joins of the hints are forced if they can be built.
+func (p *LogicalJoin) tryToGetIndexJoin(prop *property.PhysicalProperty) (indexJoins []PhysicalPlan, forced bool) {
+	if len(p.EqualConditions) == 0 || p.NullAware {
+		return nil, false
+	}
+	supportLeftOuter, supportRightOuter := false, false
+	switch p.JoinType {
+	case InnerJoin:
+		supportLeftOuter, supportRightOuter = true, true
+	case RightOuterJoin:
+		supportRightOuter = true
+	default:
+		supportLeftOuter = true
+	}
+	var leftOuterJoins, rightOuterJoins []PhysicalPlan
+	if supportLeftOuter {
+		leftOuterJoins = p.getIndexJoinByOuterIdx(prop, 0)
+	}
+	if supportRightOuter {
+		rightOuterJoins = p.getIndexJoinByOuterIdx(prop, 1)
+	}
+	forceRightInner := p.preferJoinType&preferRightAsIndexInner > 0 && len(leftOuterJoins) > 0
+	forceLeftInner := p.preferJoinType&preferLeftAsIndexInner > 0 && len(rightOuterJoins) > 0
+	switch {
+	case forceLeftInner && forceRightInner:
+		return append(leftOuterJoins, rightOuterJoins...), true
+	case forceRightInner:
+		return leftOuterJoins, true
+	case forceLeftInner:
+		return rightOuterJoins, true
+	}
+	return append(leftOuterJoins, rightOuterJoins...), false
+}
+
+// getIndexJoinByOuterIdx returns the index join of the outer child, the inner child must be a data source
+// which is read by the handle or the index whose leading columns are the most join keys.
+func (p *LogicalJoin) getIndexJoinByOuterIdx(prop *property.PhysicalProperty, outerIdx int) []PhysicalPlan {
+	ds, ok := p.children[1-outerIdx].(*DataSource)
+	if !ok || !prop.AllColsFromSchema(p.children[outerIdx].Schema()) {
+		return nil
+	}
+	outerKeys, innerKeys := p.joinKeys()
+	if outerIdx == 1 {
+		outerKeys, innerKeys = innerKeys, outerKeys
+	}
+	if pkCol := ds.getPKIsHandleCol(); pkCol != nil {
+		keyOff2IdxOff := make([]int, len(innerKeys))
+		found := false
+		for i, key := range innerKeys {
+			keyOff2IdxOff[i] = -1
+			if !found && key.Equal(nil, pkCol) {
+				keyOff2IdxOff[i], found = 0, true
+			}
+		}
+		if found {
+			join := p.constructIndexJoin(prop, outerIdx, ds.buildInnerTableScan(), outerKeys, innerKeys, keyOff2IdxOff, nil)
+			return []PhysicalPlan{join}
+		}
+	}
+	var (
+		bestPath          *accessPath
+		bestKeyOff2IdxOff []int
+		bestKeyCols       []*expression.Column
+	)
+	for _, path := range ds.possibleAccessPaths {
+		if path.isTablePath {
+			continue
+		}
+		keyOff2IdxOff := make([]int, len(innerKeys))
+		for i := range keyOff2IdxOff {
+			keyOff2IdxOff[i] = -1
+		}
+		var keyCols []*expression.Column
+		for idxOff, idxCol := range path.idxCols {
+			found := false
+			for keyOff, key := range innerKeys {
+				if keyOff2IdxOff[keyOff] == -1 && key.Equal(nil, idxCol) {
+					keyOff2IdxOff[keyOff], found = idxOff, true
+					keyCols = append(keyCols, key)
+					break
+				}
+			}
+			if !found {
+				break
+			}
+		}
+		if len(keyCols) > len(bestKeyCols) {
+			bestPath, bestKeyOff2IdxOff, bestKeyCols = path, keyOff2IdxOff, keyCols
+		}
+	}
+	if bestPath == nil {
+		return nil
+	}
+	innerTask := ds.buildInnerIndexScan(bestPath, bestKeyCols)
+	join := p.constructIndexJoin(prop, outerIdx, innerTask, outerKeys, innerKeys, bestKeyOff2IdxOff, bestPath.idxColLens)
+	return []PhysicalPlan{join}
+}
+
+func (p *LogicalJoin) constructIndexJoin(prop *property.PhysicalProperty, outerIdx int, innerTask task,
+	outerKeys, innerKeys []*expression.Column, keyOff2IdxOff, idxColLens []int) *PhysicalIndexJoin {
+	chReqProps := make([]*property.PhysicalProperty, 2)
+	chReqProps[outerIdx] = &property.PhysicalProperty{
+		Items:       prop.Items,
+		ExpectedCnt: scaleExpectCnt(prop, p.stats, p.children[outerIdx].statsInfo()),
+	}
+	// The task of the inner child is replaced by the inner task.
+	chReqProps[1-outerIdx] = &property.PhysicalProperty{ExpectedCnt: math.MaxFloat64}
+	leftKeys, rightKeys := outerKeys, innerKeys
+	if outerIdx == 1 {
+		leftKeys, rightKeys = innerKeys, outerKeys
+	}
+	join := PhysicalIndexJoin{
+		basePhysicalJoin: p.newBasePhysicalJoin(1-outerIdx, leftKeys, rightKeys),
+		OuterJoinKeys:    outerKeys,
+		InnerJoinKeys:    innerKeys,
+		KeyOff2IdxOff:    keyOff2IdxOff,
+		IdxColLens:       idxColLens,
+		innerTask:        innerTask,
+	}.Init(p.ctx, p.stats.ScaleByExpectCnt(prop.ExpectedCnt), chReqProps...)
+	join.SetSchema(p.schema)
+	join.names = p.names
+	return join
+}
+
+// exhaustPhysicalPlans implements LogicalPlan interface, the rows are returned in the order of the outer
+// child.
+func (la *LogicalApply) exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan {
+	if !prop.AllColsFromSchema(la.children[0].Schema()) {
+		return nil
+	}
+	leftKeys, rightKeys := la.joinKeys()
+	apply := PhysicalApply{
+		PhysicalHashJoin: PhysicalHashJoin{
+			basePhysicalJoin: la.newBasePhysicalJoin(1, leftKeys, rightKeys),
+			EqualConditions:  la.EqualConditions,
+		},
+		CorCols: la.CorCols,
+	}.Init(la.ctx, la.stats.ScaleByExpectCnt(prop.ExpectedCnt),
+		&property.PhysicalProperty{Items: prop.Items, ExpectedCnt: math.MaxFloat64},
+		&property.PhysicalProperty{ExpectedCnt: math.MaxFloat64})
+	apply.SetSchema(la.schema)
+	apply.names = la.names
+	return []PhysicalPlan{apply}
+}
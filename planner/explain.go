@@ -0,0 +1,468 @@
+package planner
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/parser/ast"
+	"grant-db/expression"
+	"grant-db/planner/property"
+	"grant-db/util/memory"
+	"grant-db/util/ranger"
+	"grant-db/util/texttree"
+)
+
+// The formats of EXPLAIN, the brief format is the row format whose ids are without the plan ids.
+const (
+	ExplainFormatRow   = ast.ExplainFormatROW
+	ExplainFormatBrief = "brief"
+	ExplainFormatDot   = ast.ExplainFormatDOT
+	ExplainFormatJSON  = "json"
+)
+
+// The task type of the operators, all of them are run by the server.
+const taskTypeRoot = "root"
+
+// Explain renders the physical plan in a format of EXPLAIN. The runtime statistics of the executors, which
+// are collected in the statement context, are rendered too if Analyze is true.
+type Explain struct {
+	TargetPlan PhysicalPlan
+	Format     string
+	Analyze    bool
+
+	// Rows are the rendered rows, whose columns are the ones of FieldNames.
+	Rows [][]string
+}
+
+// NewExplain creates an Explain of the plan, the format is case insensitive.
+func NewExplain(p PhysicalPlan, format string, analyze bool) (*Explain, error) {
+	format = strings.ToLower(format)
+	switch format {
+	case ExplainFormatRow, ExplainFormatBrief, ExplainFormatDot, ExplainFormatJSON:
+	default:
+		return nil, ErrUnknownExplainFormat.GenWithStackByArgs(format)
+	}
+	return &Explain{TargetPlan: p, Format: format, Analyze: analyze}, nil
+}
+
+// FieldNames returns the names of the columns of the rendered rows.
+func (e *Explain) FieldNames() []string {
+	switch e.Format {
+	case ExplainFormatDot:
+		return []string{"dot contents"}
+	case ExplainFormatJSON:
+		return []string{"json contents"}
+	}
+	if e.Analyze {
+		return []string{"id", "estRows", "actRows", "task", "access object", "execution info", "operator info", "memory", "disk"}
+	}
+	return []string{"id", "estRows", "task", "access object", "operator info"}
+}
+
+// explainNode is the rendered fields of an operator and its children.
+type explainNode struct {
+	ID           string         `json:"id"`
+	EstRows      string         `json:"estRows"`
+	ActRows      string         `json:"actRows,omitempty"`
+	TaskType     string         `json:"taskType"`
+	AccessObject string         `json:"accessObject,omitempty"`
+	ExecuteInfo  string         `json:"executeInfo,omitempty"`
+	OperatorInfo string         `json:"operatorInfo,omitempty"`
+	MemoryInfo   string         `json:"memoryInfo,omitempty"`
+	DiskInfo     string         `json:"diskInfo,omitempty"`
+	SubOperators []*explainNode `json:"subOperators,omitempty"`
+
+	plan PhysicalPlan
+}
+
+// RenderResult renders the plan to the rows.
+func (e *Explain) RenderResult() error {
+	root := e.buildExplainNode(e.TargetPlan, "")
+	e.Rows = e.Rows[:0]
+	switch e.Format {
+	case ExplainFormatRow, ExplainFormatBrief:
+		e.explainInRowFormat(root, "", true)
+	case ExplainFormatDot:
+		e.Rows = append(e.Rows, []string{explainInDotFormat(root)})
+	case ExplainFormatJSON:
+		data, err := json.MarshalIndent([]*explainNode{root}, "", "    ")
+		if err != nil {
+			return err
+		}
+		e.Rows = append(e.Rows, []string{string(data)})
+	}
+	return nil
+}
+
+func (e *Explain) buildExplainNode(p PhysicalPlan, driverSide string) *explainNode {
+	node := &explainNode{
+		ID:           p.ExplainID() + driverSide,
+		EstRows:      strconv.FormatFloat(p.StatsCount(), 'f', 2, 64),
+		TaskType:     taskTypeRoot,
+		OperatorInfo: p.ExplainInfo(),
+		plan:         p,
+	}
+	if e.Format == ExplainFormatBrief {
+		node.ID = p.TP() + driverSide
+	}
+	if x, ok := p.(dataAccesser); ok {
+		node.AccessObject = x.AccessObject()
+	}
+	if e.Analyze {
+		node.ActRows, node.ExecuteInfo, node.MemoryInfo, node.DiskInfo = runtimeInfo(p)
+	}
+	children, driverSides := explainChildren(p)
+	for i, child := range children {
+		node.SubOperators = append(node.SubOperators, e.buildExplainNode(child, driverSides[i]))
+	}
+	return node
+}
+
+// explainChildren returns the children of the plan in the order they are shown, the build side of a join
+// is above the probe side.
+func explainChildren(p PhysicalPlan) ([]PhysicalPlan, []string) {
+	buildSide := -1
+	switch x := p.(type) {
+	case *PhysicalIndexLookUp:
+		return []PhysicalPlan{x.IndexPlan, x.TablePlan}, []string{"(Build)", "(Probe)"}
+	case *PhysicalApply:
+		buildSide = x.InnerChildIdx ^ 1
+	case *PhysicalHashJoin:
+		buildSide = x.InnerChildIdx
+	case *PhysicalMergeJoin:
+		buildSide = 1
+		if x.JoinType == RightOuterJoin {
+			buildSide = 0
+		}
+	case *PhysicalIndexJoin:
+		buildSide = x.InnerChildIdx ^ 1
+	}
+	children := p.Children()
+	if buildSide < 0 {
+		return children, make([]string, len(children))
+	}
+	return []PhysicalPlan{children[buildSide], children[buildSide^1]}, []string{"(Build)", "(Probe)"}
+}
+
+// runtimeInfo returns the runtime statistics of the executor of the plan, and the memory and the disk it
+// used at most.
+func runtimeInfo(p PhysicalPlan) (actRows, execInfo, memoryInfo, diskInfo string) {
+	sc := p.SCtx().GetSessionVars().StmtCtx
+	actRows, execInfo = "0", "time:0s, loops:0"
+	if coll := sc.RuntimeStatsColl; coll != nil && coll.ExistsRootStats(p.ID()) {
+		stats := coll.GetRootStats(p.ID())
+		actRows, execInfo = strconv.FormatInt(stats.GetActRows(), 10), stats.String()
+	}
+	memoryInfo, diskInfo = "N/A", "N/A"
+	if sc.MemTracker != nil {
+		if tracker := sc.MemTracker.SearchTracker(p.ID()); tracker != nil {
+			memoryInfo = memory.BytesToString(tracker.MaxConsumed())
+		}
+	}
+	if sc.DiskTracker != nil {
+		if tracker := sc.DiskTracker.SearchTracker(p.ID()); tracker != nil {
+			diskInfo = memory.BytesToString(tracker.MaxConsumed())
+		}
+	}
+	return
+}
+
+func (e *Explain) explainInRowFormat(node *explainNode, indent string, isLastChild bool) {
+	id := texttree.PrettyIdentifier(node.ID, indent, isLastChild)
+	if e.Analyze {
+		e.Rows = append(e.Rows, []string{id, node.EstRows, node.ActRows, node.TaskType, node.AccessObject,
+			node.ExecuteInfo, node.OperatorInfo, node.MemoryInfo, node.DiskInfo})
+	} else {
+		e.Rows = append(e.Rows, []string{id, node.EstRows, node.TaskType, node.AccessObject, node.OperatorInfo})
+	}
+	childIndent := texttree.Indent4Child(indent, isLastChild)
+	for i, child := range node.SubOperators {
+		e.explainInRowFormat(child, childIndent, i == len(node.SubOperators)-1)
+	}
+}
+
+// explainInDotFormat renders the plan as a graph of the DOT language, all the operators are in the cluster
+// of the root task.
+func explainInDotFormat(root *explainNode) string {
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "\ndigraph %s {\n", root.plan.ExplainID())
+	fmt.Fprintf(&buffer, "subgraph cluster%d{\n", root.plan.ID())
+	buffer.WriteString("node [style=filled, color=lightgrey]\n")
+	buffer.WriteString("color=black\n")
+	fmt.Fprintf(&buffer, "label = \"%s\"\n", taskTypeRoot)
+	if len(root.SubOperators) == 0 {
+		fmt.Fprintf(&buffer, "\"%s\"\n", root.plan.ExplainID())
+	}
+	nodes := []*explainNode{root}
+	for len(nodes) > 0 {
+		node := nodes[0]
+		nodes = nodes[1:]
+		for _, child := range node.SubOperators {
+			fmt.Fprintf(&buffer, "\"%s\" -> \"%s\"\n", node.plan.ExplainID(), child.plan.ExplainID())
+			nodes = append(nodes, child)
+		}
+	}
+	buffer.WriteString("}\n}\n")
+	return buffer.String()
+}
+
+// dataAccesser is the plan which reads the data of a table.
+type dataAccesser interface {
+	// AccessObject returns the table and the index the plan reads.
+	AccessObject() string
+}
+
+// AccessObject implements dataAccesser interface.
+func (p *physicalTableSource) AccessObject() string {
+	if p.TableAsName != nil && p.TableAsName.O != "" {
+		return "table:" + p.TableAsName.O
+	}
+	return "table:" + p.TableInfo.Name.O
+}
+
+// AccessObject implements dataAccesser interface.
+func (p *PhysicalIndexScan) AccessObject() string {
+	cols := make([]string, 0, len(p.Index.Columns))
+	for _, col := range p.Index.Columns {
+		cols = append(cols, col.Name.O)
+	}
+	return fmt.Sprintf("%s, index:%s(%s)", p.physicalTableSource.AccessObject(), p.Index.Name.O, strings.Join(cols, ", "))
+}
+
+// AccessObject implements dataAccesser interface.
+func (p *PointGetPlan) AccessObject() string {
+	if p.IndexInfo == nil {
+		return p.physicalTableSource.AccessObject()
+	}
+	return fmt.Sprintf("%s, index:%s", p.physicalTableSource.AccessObject(), p.IndexInfo.Name.O)
+}
+
+// AccessObject implements dataAccesser interface.
+func (p *BatchPointGetPlan) AccessObject() string {
+	if p.IndexInfo == nil {
+		return p.physicalTableSource.AccessObject()
+	}
+	return fmt.Sprintf("%s, index:%s", p.physicalTableSource.AccessObject(), p.IndexInfo.Name.O)
+}
+
+// explainScanInfo returns the ranges and the order of a scan, the table scan of an index lookup has no
+// ranges because it reads the rows by the handles.
+func explainScanInfo(ts *physicalTableSource, ranges []*ranger.Range, rangeDecidedBy []*expression.Column, keepOrder, desc bool) string {
+	var buffer strings.Builder
+	if rangeDecidedBy != nil {
+		fmt.Fprintf(&buffer, "range: decided by %v, ", rangeDecidedBy)
+	} else if len(ranges) > 0 {
+		buffer.WriteString("range:")
+		for i, ran := range ranges {
+			if i > 0 {
+				buffer.WriteString(", ")
+			}
+			buffer.WriteString(ran.String())
+		}
+		buffer.WriteString(", ")
+	}
+	fmt.Fprintf(&buffer, "keep order:%v", keepOrder)
+	if desc {
+		buffer.WriteString(", desc")
+	}
+	if ts.StatsPseudo {
+		buffer.WriteString(", stats:pseudo")
+	}
+	return buffer.String()
+}
+
+// ExplainInfo implements PhysicalPlan interface.
+func (p *PhysicalTableScan) ExplainInfo() string {
+	return explainScanInfo(&p.physicalTableSource, p.Ranges, p.rangeDecidedBy, p.KeepOrder, p.Desc)
+}
+
+// ExplainInfo implements PhysicalPlan interface.
+func (p *PhysicalIndexScan) ExplainInfo() string {
+	return explainScanInfo(&p.physicalTableSource, p.Ranges, p.rangeDecidedBy, p.KeepOrder, p.Desc)
+}
+
+// ExplainInfo implements PhysicalPlan interface.
+func (p *PointGetPlan) ExplainInfo() string {
+	if p.IndexInfo == nil {
+		return fmt.Sprintf("handle:%d", p.Handle)
+	}
+	return ""
+}
+
+// ExplainInfo implements PhysicalPlan interface.
+func (p *BatchPointGetPlan) ExplainInfo() string {
+	if p.IndexInfo == nil {
+		return fmt.Sprintf("handle:%v", p.Handles)
+	}
+	return ""
+}
+
+// ExplainInfo implements PhysicalPlan interface.
+func (p *PhysicalTableDual) ExplainInfo() string {
+	return fmt.Sprintf("rows:%d", p.RowCount)
+}
+
+// ExplainInfo implements PhysicalPlan interface.
+func (p *PhysicalSelection) ExplainInfo() string {
+	return explainExprs(p.Conditions)
+}
+
+// ExplainInfo implements PhysicalPlan interface.
+func (p *PhysicalProjection) ExplainInfo() string {
+	return explainExprs(p.Exprs)
+}
+
+// ExplainInfo implements PhysicalPlan interface.
+func (p *PhysicalSort) ExplainInfo() string {
+	return explainByItems(p.ByItems)
+}
+
+// ExplainInfo implements PhysicalPlan interface.
+func (p *PhysicalTopN) ExplainInfo() string {
+	return fmt.Sprintf("%s, offset:%d, count:%d", explainByItems(p.ByItems), p.Offset, p.Count)
+}
+
+// ExplainInfo implements PhysicalPlan interface.
+func (p *PhysicalLimit) ExplainInfo() string {
+	return fmt.Sprintf("offset:%d, count:%d", p.Offset, p.Count)
+}
+
+// explainJoinType returns the join type, the null-aware anti joins are marked.
+func (p *basePhysicalJoin) explainJoinType() string {
+	if p.NullAware {
+		return "null-aware " + p.JoinType.String()
+	}
+	return p.JoinType.String()
+}
+
+// explainConditions returns the conditions of the join which aren't the join keys.
+func (p *basePhysicalJoin) explainConditions() string {
+	var buffer strings.Builder
+	if len(p.LeftConditions) > 0 {
+		fmt.Fprintf(&buffer, ", left cond:[%s]", explainExprs(p.LeftConditions))
+	}
+	if len(p.RightConditions) > 0 {
+		fmt.Fprintf(&buffer, ", right cond:[%s]", explainExprs(p.RightConditions))
+	}
+	if len(p.OtherConditions) > 0 {
+		fmt.Fprintf(&buffer, ", other cond:[%s]", explainExprs(p.OtherConditions))
+	}
+	return buffer.String()
+}
+
+// ExplainInfo implements PhysicalPlan interface.
+func (p *PhysicalHashJoin) ExplainInfo() string {
+	str := p.explainJoinType()
+	if len(p.EqualConditions) > 0 {
+		str += fmt.Sprintf(", equal:[%s]", explainExprs(expression.ScalarFuncs2Exprs(p.EqualConditions)))
+	}
+	return str + p.explainConditions()
+}
+
+// ExplainInfo implements PhysicalPlan interface.
+func (p *PhysicalMergeJoin) ExplainInfo() string {
+	str := fmt.Sprintf("%s, left key:%s, right key:%s", p.explainJoinType(),
+		explainColumns(p.LeftJoinKeys), explainColumns(p.RightJoinKeys))
+	if p.Desc {
+		str += ", desc"
+	}
+	return str + p.explainConditions()
+}
+
+// ExplainInfo implements PhysicalPlan interface.
+func (p *PhysicalIndexJoin) ExplainInfo() string {
+	return fmt.Sprintf("%s, inner:%s, outer key:%s, inner key:%s", p.explainJoinType(),
+		p.children[p.InnerChildIdx].ExplainID(), explainColumns(p.OuterJoinKeys), explainColumns(p.InnerJoinKeys)) +
+		p.explainConditions()
+}
+
+// ExplainInfo implements PhysicalPlan interface, every aggregate function is followed by its output column.
+func (p *basePhysicalAgg) ExplainInfo() string {
+	var buffer strings.Builder
+	if len(p.GroupByItems) > 0 {
+		fmt.Fprintf(&buffer, "group by:%s, ", explainExprs(p.GroupByItems))
+	}
+	buffer.WriteString("funcs:")
+	for i, aggFunc := range p.AggFuncs {
+		if i > 0 {
+			buffer.WriteString(", ")
+		}
+		buffer.WriteString(aggFunc.String())
+		if i < p.Schema().Len() {
+			buffer.WriteString("->" + p.Schema().Columns[i].String())
+		}
+	}
+	return buffer.String()
+}
+
+// ExplainInfo implements PhysicalPlan interface, every window function is followed by its output column
+// and then the window.
+func (p *PhysicalWindow) ExplainInfo() string {
+	var buffer strings.Builder
+	resultCols := p.Schema().Columns[p.Schema().Len()-len(p.WindowFuncDescs):]
+	for i, windowFunc := range p.WindowFuncDescs {
+		if i > 0 {
+			buffer.WriteString(", ")
+		}
+		buffer.WriteString(windowFunc.String() + "->" + resultCols[i].String())
+	}
+	clauses := make([]string, 0, 3)
+	if len(p.PartitionBy) > 0 {
+		clauses = append(clauses, "partition by "+explainSortItems(p.PartitionBy))
+	}
+	if len(p.OrderBy) > 0 {
+		clauses = append(clauses, "order by "+explainSortItems(p.OrderBy))
+	}
+	if p.Frame != nil {
+		frameType := "rows"
+		if p.Frame.Type == ast.Ranges {
+			frameType = "range"
+		}
+		clauses = append(clauses, fmt.Sprintf("%s between %s and %s", frameType, p.Frame.Start, p.Frame.End))
+	}
+	fmt.Fprintf(&buffer, " over(%s)", strings.Join(clauses, " "))
+	return buffer.String()
+}
+
+func explainExprs(exprs []expression.Expression) string {
+	strs := make([]string, 0, len(exprs))
+	for _, expr := range exprs {
+		strs = append(strs, expr.String())
+	}
+	return strings.Join(strs, ", ")
+}
+
+func explainColumns(cols []*expression.Column) string {
+	strs := make([]string, 0, len(cols))
+	for _, col := range cols {
+		strs = append(strs, col.String())
+	}
+	return strings.Join(strs, ", ")
+}
+
+func explainByItems(byItems []*ByItems) string {
+	strs := make([]string, 0, len(byItems))
+	for _, item := range byItems {
+		if item.Desc {
+			strs = append(strs, item.Expr.String()+":desc")
+		} else {
+			strs = append(strs, item.Expr.String())
+		}
+	}
+	return strings.Join(strs, ", ")
+}
+
+func explainSortItems(items []property.SortItem) string {
+	strs := make([]string, 0, len(items))
+	for _, item := range items {
+		if item.Desc {
+			strs = append(strs, item.Col.String()+":desc")
+		} else {
+			strs = append(strs, item.Col.String())
+		}
+	}
+	return strings.Join(strs, ", ")
+}
@@ -0,0 +1,54 @@
+package planner_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pingcap/parser/mysql"
+)
+
+func TestExplain(t *testing.T) {
+	tk, _ := newPlanTestKit(t)
+	tk.MustExec("insert into t values (1, 1, 1, 1), (2, 2, 2, 2), (3, 1, 3, 3)")
+	tk.MustQuery("explain select d from t where b = 1").Check(
+		"Projection_4 10.00 root  test.t.d",
+		"└─IndexLookUp_9 10.00 root  ",
+		"  ├─IndexScan_7(Build) 10.00 root table:t, index:idx_b(b) range:[1,1], keep order:false, stats:pseudo",
+		"  └─TableScan_8(Probe) 10.00 root table:t keep order:false, stats:pseudo")
+	// The brief format drops the IDs of the operators.
+	tk.MustQuery("explain format = 'brief' select d from t where b = 1").Check(
+		"Projection 10.00 root  test.t.d",
+		"└─IndexLookUp 10.00 root  ",
+		"  ├─IndexScan(Build) 10.00 root table:t, index:idx_b(b) range:[1,1], keep order:false, stats:pseudo",
+		"  └─TableScan(Probe) 10.00 root table:t keep order:false, stats:pseudo")
+	tk.MustQuery("explain select t.a from t join s on t.a = s.a").Check(
+		"Projection_6 10000.00 root  test.t.a",
+		"└─HashJoin_12 10000.00 root  inner join, equal:[eq(test.t.a, test.s.a)]",
+		"  ├─TableScan_20(Build) 10000.00 root table:s range:[-inf,+inf], keep order:false, stats:pseudo",
+		"  └─IndexScan_16(Probe) 10000.00 root table:t, index:idx_b(b) range:[NULL,+inf], keep order:false, stats:pseudo")
+	tk.MustQuery("explain insert into t values (5, 5, 5, 5)").Check("Insert_1 1.00 root  ")
+
+	dot := strings.Join(tk.MustQuery("explain format = 'dot' select a from t where a = 1").Rows(), "\n")
+	if !strings.Contains(dot, "digraph Projection_4 {") || !strings.Contains(dot, `"Projection_4" -> "Point_Get_5"`) {
+		t.Fatalf("the dot format is %s", dot)
+	}
+	json := strings.Join(tk.MustQuery("explain format = 'json' select a from t where a = 1").Rows(), "\n")
+	if !strings.Contains(json, `"id": "Point_Get_5"`) || !strings.Contains(json, `"operatorInfo": "handle:1"`) {
+		t.Fatalf("the json format is %s", json)
+	}
+	tk.MustGetErrCode("explain format = 'foo' select 1", mysql.ErrUnknownExplainFormat)
+
+	// EXPLAIN ANALYZE runs the statement and reports the actual rows and the execution info.
+	rows := tk.MustQuery("explain analyze select d from t where b = 1").Rows()
+	if len(rows) != 4 {
+		t.Fatalf("explain analyze returns %v", rows)
+	}
+	for _, row := range rows {
+		fields := strings.Fields(row)
+		if fields[2] != "2" || !strings.Contains(row, "time:") || !strings.Contains(row, "loops:") {
+			t.Fatalf("the operator %s doesn't return 2 rows with its execution info", row)
+		}
+	}
+	tk.MustExec("explain analyze insert into t values (5, 5, 5, 5)")
+	tk.MustQuery("select d from t where a = 5").Check("5")
+}
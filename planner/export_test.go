@@ -0,0 +1,4 @@
+package planner
+
+// LogicalOptimize is exported for the tests of the logical optimization rules.
+var LogicalOptimize = logicalOptimize
@@ -0,0 +1,373 @@
+package planner
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/opcode"
+	"grant-db/expression"
+	"grant-db/types"
+)
+
+// expressionRewriter rewrites the AST expressions over a plan, the columns are resolved by the names of the
+// plan and its outer plans, and the sub queries are joined to the plan.
+type expressionRewriter struct {
+	b *PlanBuilder
+	p LogicalPlan
+	// aggMapper and colMapper map the aggregate functions and the columns to the offsets of the columns of
+	// the plan, which are resolved before the plan is built.
+	aggMapper map[*ast.AggregateFuncExpr]int
+	colMapper map[*ast.ColumnNameExpr]int
+	// windowMapper maps the window functions to the offsets of the columns of the plan, a window function
+	// is not allowed if it's not mapped.
+	windowMapper map[*ast.WindowFuncExpr]int
+	// asScalar is false if the value of the root expression is only used as a filter, the sub queries of
+	// EXISTS and IN at the root are turned into semi joins then.
+	asScalar bool
+	root     ast.ExprNode
+}
+
+// rewrite rewrites the AST expression over the plan, the plan which the sub queries are joined to is returned.
+func (b *PlanBuilder) rewrite(exprNode ast.ExprNode, p LogicalPlan, aggMapper map[*ast.AggregateFuncExpr]int, asScalar bool) (expression.Expression, LogicalPlan, error) {
+	return b.rewriteWithMapper(exprNode, p, aggMapper, nil, nil, asScalar)
+}
+
+func (b *PlanBuilder) rewriteWithMapper(exprNode ast.ExprNode, p LogicalPlan, aggMapper map[*ast.AggregateFuncExpr]int,
+	colMapper map[*ast.ColumnNameExpr]int, windowMapper map[*ast.WindowFuncExpr]int, asScalar bool) (expression.Expression, LogicalPlan, error) {
+	er := &expressionRewriter{b: b, p: p, aggMapper: aggMapper, colMapper: colMapper, windowMapper: windowMapper,
+		asScalar: asScalar, root: unwrapParentheses(exprNode)}
+	expr, err := er.rewrite(exprNode)
+	if err != nil {
+		return nil, nil, err
+	}
+	return expr, er.p, nil
+}
+
+func (er *expressionRewriter) rewrite(exprNode ast.ExprNode) (expression.Expression, error) {
+	return expression.RewriteExprWithHook(er.b.ctx, exprNode, er.hook)
+}
+
+// rewriteScalar rewrites a sub expression whose value is used, the sub queries of it are joined to er.p.
+func (er *expressionRewriter) rewriteScalar(exprNode ast.ExprNode) (expression.Expression, error) {
+	sub := &expressionRewriter{b: er.b, p: er.p, aggMapper: er.aggMapper, colMapper: er.colMapper, windowMapper: er.windowMapper, asScalar: true}
+	expr, err := sub.rewrite(exprNode)
+	if err != nil {
+		return nil, err
+	}
+	er.p = sub.p
+	return expr, nil
+}
+
+func unwrapParentheses(node ast.ExprNode) ast.ExprNode {
+	for {
+		paren, ok := node.(*ast.ParenthesesExpr)
+		if !ok {
+			return node
+		}
+		node = paren.Expr
+	}
+}
+
+// isRoot checks whether the node is the root expression whose value is only used as a filter.
+func (er *expressionRewriter) isRoot(node ast.Node) bool {
+	return !er.asScalar && node == er.root
+}
+
+func (er *expressionRewriter) hook(node ast.Node) (expression.Expression, bool, error) {
+	var (
+		expr expression.Expression
+		err  error
+	)
+	switch v := node.(type) {
+	case *ast.ColumnNameExpr:
+		expr, err = er.toColumn(v)
+	case *ast.AggregateFuncExpr:
+		idx, ok := er.aggMapper[v]
+		if !ok {
+			return nil, false, ErrInvalidGroupFuncUse
+		}
+		expr = er.p.Schema().Columns[idx]
+	case *ast.WindowFuncExpr:
+		idx, ok := er.windowMapper[v]
+		if !ok {
+			return nil, false, ErrWindowInvalidWindowFuncUse.GenWithStackByArgs(strings.ToLower(v.F))
+		}
+		expr = er.p.Schema().Columns[idx]
+	case *ast.ValuesExpr:
+		expr, err = er.valuesToColumn(v)
+	case *ast.DefaultExpr:
+		expr, err = er.defaultToConstant(v)
+	case *ast.SubqueryExpr:
+		expr, err = er.handleScalarSubquery(v)
+	case *ast.ExistsSubqueryExpr:
+		expr, err = er.handleExistSubquery(v, v.Not, er.isRoot(v))
+	case *ast.PatternInExpr:
+		if v.Sel == nil {
+			return nil, false, nil
+		}
+		expr, err = er.handleInSubquery(v, v.Not, er.isRoot(v))
+	case *ast.CompareSubqueryExpr:
+		expr, err = er.handleCompareSubquery(v, er.isRoot(v))
+	case *ast.UnaryOperationExpr:
+		// NOT EXISTS and NOT IN of the root are anti semi joins.
+		if v.Op != opcode.Not || !er.isRoot(v) {
+			return nil, false, nil
+		}
+		switch x := unwrapParentheses(v.V).(type) {
+		case *ast.ExistsSubqueryExpr:
+			expr, err = er.handleExistSubquery(x, !x.Not, true)
+		case *ast.PatternInExpr:
+			if x.Sel == nil {
+				return nil, false, nil
+			}
+			expr, err = er.handleInSubquery(x, !x.Not, true)
+		default:
+			return nil, false, nil
+		}
+	default:
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return expr, true, nil
+}
+
+// toColumn resolves the column name in the plan first, and then in the outer plans from the innermost one,
+// the columns of the outer plans are correlated columns.
+func (er *expressionRewriter) toColumn(v *ast.ColumnNameExpr) (expression.Expression, error) {
+	if idx, ok := er.colMapper[v]; ok {
+		return er.p.Schema().Columns[idx], nil
+	}
+	idx, err := expression.FindFieldName(er.p.OutputNames(), v.Name)
+	if err != nil {
+		return nil, err
+	}
+	if idx >= 0 {
+		return er.p.Schema().Columns[idx], nil
+	}
+	for i := len(er.b.outerSchemas) - 1; i >= 0; i-- {
+		idx, err = expression.FindFieldName(er.b.outerNames[i], v.Name)
+		if err != nil {
+			return nil, err
+		}
+		if idx >= 0 {
+			er.b.optFlag |= flagDecorrelate
+			column := er.b.outerSchemas[i].Columns[idx]
+			return &expression.CorrelatedColumn{Column: *column, Data: new(types.Datum)}, nil
+		}
+	}
+	return nil, ErrUnknownColumn.GenWithStackByArgs(v.Name.String(), clauseMsg[er.b.curClause])
+}
+
+// buildSubquery builds the plan of a sub query, whose columns can refer to the columns of er.p.
+func (er *expressionRewriter) buildSubquery(subq *ast.SubqueryExpr) (LogicalPlan, error) {
+	b := er.b
+	b.outerSchemas = append(b.outerSchemas, er.p.Schema())
+	b.outerNames = append(b.outerNames, er.p.OutputNames())
+	oldClause := b.curClause
+	defer func() {
+		b.outerSchemas = b.outerSchemas[:len(b.outerSchemas)-1]
+		b.outerNames = b.outerNames[:len(b.outerNames)-1]
+		b.curClause = oldClause
+	}()
+	return b.buildResultSetNode(subq.Query)
+}
+
+// handleScalarSubquery joins the sub query which returns one row at most to er.p with a left outer join,
+// the value is NULL if the sub query returns no row.
+func (er *expressionRewriter) handleScalarSubquery(v *ast.SubqueryExpr) (expression.Expression, error) {
+	np, err := er.buildSubquery(v)
+	if err != nil {
+		return nil, err
+	}
+	if np.Schema().Len() != 1 {
+		return nil, ErrOperandColumns.GenWithStackByArgs(1)
+	}
+	maxOneRow := LogicalMaxOneRow{}.Init(er.b.ctx)
+	maxOneRow.SetChildren(np)
+	outerLen := er.p.Schema().Len()
+	er.p = er.b.buildJoinWithSubquery(er.p, maxOneRow, LeftOuterJoin, nil, false)
+	return er.p.Schema().Columns[outerLen], nil
+}
+
+// handleExistSubquery turns EXISTS into a semi join if it's the root, otherwise the result of it is the
+// auxiliary column of a left outer semi join.
+func (er *expressionRewriter) handleExistSubquery(v *ast.ExistsSubqueryExpr, not bool, asFilter bool) (expression.Expression, error) {
+	subq, ok := v.Sel.(*ast.SubqueryExpr)
+	if !ok {
+		return nil, ErrNotSupportedYet.GenWithStackByArgs("EXISTS without a sub query")
+	}
+	np, err := er.buildSubquery(subq)
+	if err != nil {
+		return nil, err
+	}
+	return er.joinSubquery(np, nil, not, asFilter, false), nil
+}
+
+// handleInSubquery turns `a IN (SELECT b ...)` into a semi join on `a = b`, NOT IN is an anti semi join
+// which is NULL aware.
+func (er *expressionRewriter) handleInSubquery(v *ast.PatternInExpr, not bool, asFilter bool) (expression.Expression, error) {
+	lExpr, err := er.rewriteScalar(v.Expr)
+	if err != nil {
+		return nil, err
+	}
+	subq, ok := v.Sel.(*ast.SubqueryExpr)
+	if !ok {
+		return nil, ErrNotSupportedYet.GenWithStackByArgs("IN without a sub query")
+	}
+	np, err := er.buildSubquery(subq)
+	if err != nil {
+		return nil, err
+	}
+	if np.Schema().Len() != 1 {
+		return nil, ErrOperandColumns.GenWithStackByArgs(1)
+	}
+	cond, err := expression.NewFunction(er.b.ctx, ast.EQ, types.NewFieldType(mysql.TypeTiny), lExpr, np.Schema().Columns[0])
+	if err != nil {
+		return nil, err
+	}
+	// The NULL results of IN don't matter for the filter, but NOT IN rejects the rows whose result is NULL.
+	nullAware := !asFilter || not
+	return er.joinSubquery(np, []expression.Expression{cond}, not, asFilter, nullAware), nil
+}
+
+// handleCompareSubquery turns `a op ANY (SELECT b ...)` into a semi join on `a op b`, and `a op ALL (SELECT b ...)`
+// into an anti semi join on the negated condition, like `a > ALL` is NOT `a <= ANY`.
+func (er *expressionRewriter) handleCompareSubquery(v *ast.CompareSubqueryExpr, asFilter bool) (expression.Expression, error) {
+	lExpr, err := er.rewriteScalar(v.L)
+	if err != nil {
+		return nil, err
+	}
+	subq, ok := v.R.(*ast.SubqueryExpr)
+	if !ok {
+		return nil, ErrNotSupportedYet.GenWithStackByArgs("ANY or ALL without a sub query")
+	}
+	np, err := er.buildSubquery(subq)
+	if err != nil {
+		return nil, err
+	}
+	if np.Schema().Len() != 1 {
+		return nil, ErrOperandColumns.GenWithStackByArgs(1)
+	}
+	op := v.Op
+	if v.All {
+		op = negatedCmpOps[op]
+	}
+	cond, err := expression.NewFunction(er.b.ctx, op.String(), types.NewFieldType(mysql.TypeTiny), lExpr, np.Schema().Columns[0])
+	if err != nil {
+		return nil, err
+	}
+	nullAware := !asFilter || v.All
+	return er.joinSubquery(np, []expression.Expression{cond}, v.All, asFilter, nullAware), nil
+}
+
+var negatedCmpOps = map[opcode.Op]opcode.Op{
+	opcode.EQ: opcode.NE,
+	opcode.NE: opcode.EQ,
+	opcode.LT: opcode.GE,
+	opcode.GE: opcode.LT,
+	opcode.GT: opcode.LE,
+	opcode.LE: opcode.GT,
+}
+
+// joinSubquery joins the plan of the sub query to er.p by a semi join, the result of the sub query expression
+// is the constant true for a filter, or the auxiliary column of a left outer semi join.
+func (er *expressionRewriter) joinSubquery(np LogicalPlan, conds []expression.Expression, anti, asFilter, nullAware bool) expression.Expression {
+	if asFilter {
+		tp := SemiJoin
+		if anti {
+			tp = AntiSemiJoin
+		}
+		er.p = er.b.buildJoinWithSubquery(er.p, np, tp, conds, nullAware)
+		return expression.NewOne()
+	}
+	tp := LeftOuterSemiJoin
+	if anti {
+		tp = AntiLeftOuterSemiJoin
+	}
+	er.p = er.b.buildJoinWithSubquery(er.p, np, tp, conds, nullAware)
+	return er.p.Schema().Columns[er.p.Schema().Len()-1]
+}
+
+// buildJoinWithSubquery joins the outer plan and the plan of a sub query, an apply is built if the sub query
+// refers to the columns of the outer plan.
+func (b *PlanBuilder) buildJoinWithSubquery(outerPlan, innerPlan LogicalPlan, tp JoinType, conds []expression.Expression, nullAware bool) LogicalPlan {
+	var (
+		join *LogicalJoin
+		plan LogicalPlan
+	)
+	if corCols := extractCorColumnsBySchema(innerPlan, outerPlan.Schema()); len(corCols) > 0 {
+		apply := LogicalApply{CorCols: corCols}.Init(b.ctx)
+		join, plan = &apply.LogicalJoin, apply
+	} else {
+		join = LogicalJoin{}.Init(b.ctx)
+		plan = join
+	}
+	join.JoinType, join.NullAware = tp, nullAware
+	join.SetChildren(outerPlan, innerPlan)
+	join.buildSchema()
+	if tp == LeftOuterJoin {
+		// The columns of the sub query can't be referred by names in the outer query.
+		for i := outerPlan.Schema().Len(); i < len(join.names); i++ {
+			join.names[i] = &types.FieldName{}
+		}
+	}
+	if tp == LeftOuterSemiJoin || tp == AntiLeftOuterSemiJoin {
+		auxTp := types.NewFieldType(mysql.TypeTiny)
+		auxTp.Flen = 1
+		if !nullAware {
+			auxTp.Flag |= mysql.NotNullFlag
+		}
+		join.schema.Append(&expression.Column{UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(), RetType: auxTp})
+		join.names = append(join.names, &types.FieldName{ColName: auxColName})
+	}
+	join.AttachOnConds(conds)
+	b.optFlag |= flagPredicatePushDown | flagBuildKeyInfo
+	return plan
+}
+
+// buildSchema builds the schema and the names of the join from its children, the inner columns of an outer
+// join are nullable.
+func (p *LogicalJoin) buildSchema() {
+	left, right := p.children[0], p.children[1]
+	switch p.JoinType {
+	case SemiJoin, AntiSemiJoin, LeftOuterSemiJoin, AntiLeftOuterSemiJoin:
+		p.schema = left.Schema().Clone()
+		p.names = append(left.OutputNames()[:0:0], left.OutputNames()...)
+		return
+	}
+	lSchema, rSchema := left.Schema(), right.Schema()
+	switch p.JoinType {
+	case LeftOuterJoin:
+		rSchema = nullableSchema(rSchema)
+	case RightOuterJoin:
+		lSchema = nullableSchema(lSchema)
+	}
+	p.schema = expression.MergeSchema(lSchema, rSchema)
+	p.names = make(types.NameSlice, 0, p.schema.Len())
+	p.names = append(p.names, left.OutputNames()...)
+	p.names = append(p.names, right.OutputNames()...)
+}
+
+// nullableSchema returns a copy of the schema whose columns are nullable.
+func nullableSchema(schema *expression.Schema) *expression.Schema {
+	newSchema := schema.Clone()
+	for i, col := range newSchema.Columns {
+		newCol := *col
+		newCol.RetType = col.RetType.Clone()
+		newCol.RetType.Flag &^= mysql.NotNullFlag
+		newSchema.Columns[i] = &newCol
+	}
+	return newSchema
+}
+
+// positionToColumn resolves the position of ORDER BY, which refers to the select fields.
+func positionToColumn(p LogicalPlan, v *ast.PositionExpr, fieldLen int) (*expression.Column, error) {
+	if v.N < 1 || v.N > fieldLen {
+		return nil, ErrUnknownColumn.GenWithStackByArgs(strconv.Itoa(v.N), clauseMsg[orderByClause])
+	}
+	return p.Schema().Columns[v.N-1], nil
+}
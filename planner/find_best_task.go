@@ -0,0 +1,450 @@
+package planner
+
+import (
+	"math"
+
+	"github.com/pingcap/parser/mysql"
+	"grant-db/expression"
+	"grant-db/planner/property"
+	"grant-db/types"
+	"grant-db/util/ranger"
+)
+
+// findBestTask implements LogicalPlan interface, the physical plans of the plan are enumerated with the best
+// tasks of the children, and the sort enforced on the best task without the order is tried too.
+func (p *baseLogicalPlan) findBestTask(prop *property.PhysicalProperty) (bestTask task, err error) {
+	bestTask = p.getTask(prop)
+	if bestTask != nil {
+		return bestTask, nil
+	}
+	bestTask = invalidTask
+	for _, pp := range p.self.exhaustPhysicalPlans(prop) {
+		childTasks := make([]task, 0, len(p.children))
+		for i, child := range p.children {
+			childTask, err := child.findBestTask(pp.GetChildReqProps(i))
+			if err != nil {
+				return nil, err
+			}
+			if childTask.invalid() {
+				break
+			}
+			childTasks = append(childTasks, childTask)
+		}
+		if len(childTasks) != len(p.children) {
+			continue
+		}
+		curTask := pp.attach2Task(childTasks...)
+		if curTask.cost() < bestTask.cost() {
+			bestTask = curTask
+		}
+	}
+	bestTask, err = enforceProperty(p.self, prop, bestTask)
+	if err != nil {
+		return nil, err
+	}
+	p.storeTask(prop, bestTask)
+	return bestTask, nil
+}
+
+// enforceProperty tries to sort the rows of the best task without the order, the sorted task is returned if
+// it's cheaper.
+func enforceProperty(p LogicalPlan, prop *property.PhysicalProperty, bestTask task) (task, error) {
+	if prop.IsEmpty() || !prop.AllColsFromSchema(p.Schema()) {
+		return bestTask, nil
+	}
+	t, err := p.findBestTask(&property.PhysicalProperty{ExpectedCnt: math.MaxFloat64})
+	if err != nil || t.invalid() {
+		return bestTask, err
+	}
+	sort := PhysicalSort{ByItems: make([]*ByItems, 0, len(prop.Items))}.Init(p.SCtx(), t.plan().statsInfo())
+	for _, item := range prop.Items {
+		sort.ByItems = append(sort.ByItems, &ByItems{Expr: item.Col, Desc: item.Desc})
+	}
+	if t = sort.attach2Task(t); t.cost() < bestTask.cost() {
+		return t, nil
+	}
+	return bestTask, nil
+}
+
+// exhaustPhysicalPlans implements LogicalPlan interface, the plans which find the best tasks by themselves
+// have no physical plans.
+func (p *baseLogicalPlan) exhaustPhysicalPlans(_ *property.PhysicalProperty) []PhysicalPlan {
+	return nil
+}
+
+// findBestTask implements LogicalPlan interface.
+func (p *LogicalTableDual) findBestTask(prop *property.PhysicalProperty) (task, error) {
+	// The rows of the dual are ordered if there is one row at most.
+	if !prop.IsEmpty() && p.RowCount > 1 {
+		return enforceProperty(p, prop, invalidTask)
+	}
+	dual := PhysicalTableDual{RowCount: p.RowCount}.Init(p.ctx, p.stats)
+	dual.SetSchema(p.schema)
+	dual.names = p.names
+	return &rootTask{p: dual}, nil
+}
+
+// findBestTask implements LogicalPlan interface, the tasks of the access paths are compared by the costs.
+func (ds *DataSource) findBestTask(prop *property.PhysicalProperty) (t task, err error) {
+	t = ds.getTask(prop)
+	if t != nil {
+		return t, nil
+	}
+	t = invalidTask
+	for _, path := range ds.possibleAccessPaths {
+		// The point get is the fastest way to read the rows, the other paths aren't compared with it.
+		if pointTask := ds.convertToPointGet(prop, path); pointTask != nil {
+			t = pointTask
+			break
+		}
+		var pathTask task
+		if path.isTablePath {
+			pathTask = ds.convertToTableScan(prop, path)
+		} else {
+			pathTask = ds.convertToIndexScan(prop, path)
+		}
+		if pathTask.cost() < t.cost() {
+			t = pathTask
+		}
+	}
+	t, err = enforceProperty(ds, prop, t)
+	if err != nil {
+		return nil, err
+	}
+	ds.storeTask(prop, t)
+	return t, nil
+}
+
+// tblRowSize is the estimated size of a row of the table, the whole row is read from the storage.
+func (ds *DataSource) tblRowSize() float64 {
+	return float64(len(ds.tableInfo.Columns)) * columnSize
+}
+
+// idxRowSize is the estimated size of a row of the index, which contains the handle.
+func idxRowSize(path *accessPath) float64 {
+	return float64(len(path.index.Columns)+1) * columnSize
+}
+
+// scaledTableStats returns the statistic info of the rows read by the path.
+func (ds *DataSource) scaledTableStats(count float64) *property.StatsInfo {
+	if ds.tableStats.RowCount == 0 {
+		return ds.tableStats
+	}
+	return ds.tableStats.Scale(count / ds.tableStats.RowCount)
+}
+
+// getScanRowCount returns the count of the rows read by the path, the scan stops after the expected count
+// of the rows are returned.
+func (ds *DataSource) getScanRowCount(prop *property.PhysicalProperty, count float64) float64 {
+	if prop.ExpectedCnt < ds.stats.RowCount && ds.stats.RowCount > 0 {
+		selectivity := ds.stats.RowCount / count
+		count = math.Min(prop.ExpectedCnt/selectivity, count)
+	}
+	return count
+}
+
+func (ds *DataSource) newTableSource() physicalTableSource {
+	return physicalTableSource{
+		DBName:      ds.DBName,
+		TableAsName: ds.TableAsName,
+		Table:       ds.table,
+		TableInfo:   ds.tableInfo,
+		Columns:     ds.Columns,
+		StatsPseudo: ds.statisticTable.Pseudo,
+	}
+}
+
+// isMatchProp checks whether the rows read by the path are ordered by the property. The leading index
+// columns which are equal to the single values can be skipped, and the prefix columns aren't ordered.
+func (ds *DataSource) isMatchProp(path *accessPath, prop *property.PhysicalProperty) bool {
+	if prop.IsEmpty() {
+		return true
+	}
+	if all, _ := prop.AllSameOrder(); !all {
+		return false
+	}
+	if path.isTablePath {
+		pkCol := ds.getPKIsHandleCol()
+		return len(prop.Items) == 1 && pkCol != nil && prop.Items[0].Col.Equal(nil, pkCol)
+	}
+	i := 0
+	for _, sortItem := range prop.Items {
+		found := false
+		for ; i < len(path.idxCols); i++ {
+			if path.idxColLens[i] == types.UnspecifiedLength && sortItem.Col.Equal(nil, path.idxCols[i]) {
+				found = true
+				i++
+				break
+			}
+			if i >= path.eqCondCount {
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// addSelectionTask adds the selection of the filters over the plan.
+func addSelectionTask(p PhysicalPlan, filters []expression.Expression, stats *property.StatsInfo, cost *float64) PhysicalPlan {
+	if len(filters) == 0 {
+		return p
+	}
+	*cost += p.StatsCount() * cpuFactor
+	sel := PhysicalSelection{Conditions: filters}.Init(p.SCtx(), stats)
+	sel.SetChildren(p)
+	return sel
+}
+
+// convertToTableScan converts the table path to the table scan.
+func (ds *DataSource) convertToTableScan(prop *property.PhysicalProperty, path *accessPath) task {
+	if !ds.isMatchProp(path, prop) {
+		return invalidTask
+	}
+	_, desc := prop.AllSameOrder()
+	rowCount := ds.getScanRowCount(prop, path.countAfterAccess)
+	ts := PhysicalTableScan{
+		physicalTableSource: ds.newTableSource(),
+		Ranges:              path.ranges,
+		AccessCondition:     path.accessConds,
+		HandleCol:           ds.getPKIsHandleCol(),
+		KeepOrder:           !prop.IsEmpty(),
+		Desc:                desc,
+	}.Init(ds.ctx, ds.scaledTableStats(rowCount))
+	ts.SetSchema(ds.schema)
+	ts.names = ds.names
+	factor := scanFactor
+	if desc {
+		factor = descScanFactor
+	}
+	cost := rowCount*ds.tblRowSize()*factor + float64(len(ts.Ranges))*seekFactor
+	p := addSelectionTask(ts, path.tableFilters, ds.stats.ScaleByExpectCnt(prop.ExpectedCnt), &cost)
+	return &rootTask{p: p, cst: cost}
+}
+
+// convertToIndexScan converts the index path to the index scan, or the index lookup if the index doesn't
+// cover all the columns.
+func (ds *DataSource) convertToIndexScan(prop *property.PhysicalProperty, path *accessPath) task {
+	if !ds.isMatchProp(path, prop) {
+		return invalidTask
+	}
+	_, desc := prop.AllSameOrder()
+	rowCount := ds.getScanRowCount(prop, path.countAfterAccess)
+	is := PhysicalIndexScan{
+		physicalTableSource: ds.newTableSource(),
+		Index:               path.index,
+		IdxCols:             path.idxCols,
+		IdxColLens:          path.idxColLens,
+		Ranges:              path.ranges,
+		AccessCondition:     path.accessConds,
+		dataSourceSchema:    ds.schema,
+		KeepOrder:           !prop.IsEmpty(),
+		Desc:                desc,
+	}.Init(ds.ctx, ds.scaledTableStats(rowCount))
+	factor := scanFactor
+	if desc {
+		factor = descScanFactor
+	}
+	cost := rowCount*idxRowSize(path)*factor + float64(len(is.Ranges))*seekFactor
+	if path.isSingleScan {
+		is.SetSchema(ds.schema)
+		is.names = ds.names
+		p := addSelectionTask(is, path.indexFilters, ds.stats.ScaleByExpectCnt(prop.ExpectedCnt), &cost)
+		return &rootTask{p: p, cst: cost}
+	}
+	ds.setIndexScanSchema(is)
+	lookupCount := rowCount
+	if path.countAfterAccess > 0 {
+		lookupCount = rowCount * path.countAfterIndex / path.countAfterAccess
+	}
+	indexPlan := addSelectionTask(is, path.indexFilters, ds.scaledTableStats(lookupCount), &cost)
+	ts := PhysicalTableScan{
+		physicalTableSource: ds.newTableSource(),
+		HandleCol:           ds.getPKIsHandleCol(),
+	}.Init(ds.ctx, ds.scaledTableStats(lookupCount))
+	ts.SetSchema(ds.schema)
+	// Every row is read by its handle.
+	cost += lookupCount * (ds.tblRowSize()*scanFactor + seekFactor)
+	tablePlan := addSelectionTask(ts, path.tableFilters, ds.stats.ScaleByExpectCnt(prop.ExpectedCnt), &cost)
+	lookup := PhysicalIndexLookUp{
+		IndexPlan: indexPlan,
+		TablePlan: tablePlan,
+		KeepOrder: is.KeepOrder,
+	}.Init(ds.ctx, tablePlan.statsInfo())
+	lookup.SetSchema(ds.schema)
+	lookup.names = ds.names
+	return &rootTask{p: lookup, cst: cost}
+}
+
+// setIndexScanSchema sets the schema of the index scan of an index lookup, which is the columns covered by
+// the index.
+func (ds *DataSource) setIndexScanSchema(is *PhysicalIndexScan) {
+	cols := make([]*expression.Column, 0, len(is.Index.Columns))
+	is.Columns = is.Columns[:0:0]
+	for i, col := range ds.schema.Columns {
+		if ds.isCoveringCols([]*expression.Column{col}, is.Index) {
+			cols = append(cols, col)
+			is.Columns = append(is.Columns, ds.Columns[i])
+		}
+	}
+	is.SetSchema(expression.NewSchema(cols...))
+}
+
+// isPointPath checks whether the ranges of the path are the points of the handle or all the columns of a
+// unique index, every point is one row at most.
+func (ds *DataSource) isPointPath(path *accessPath) bool {
+	if len(path.ranges) == 0 || len(path.accessConds) == 0 {
+		return false
+	}
+	if path.isTablePath {
+		if ds.getPKIsHandleCol() == nil {
+			return false
+		}
+	} else {
+		if !path.index.Unique || len(path.idxCols) != len(path.index.Columns) {
+			return false
+		}
+		for _, l := range path.idxColLens {
+			if l != types.UnspecifiedLength {
+				return false
+			}
+		}
+	}
+	sc := ds.ctx.GetSessionVars().StmtCtx
+	for _, ran := range path.ranges {
+		if !ran.IsPoint(sc) || (!path.isTablePath && len(ran.LowVal) != len(path.index.Columns)) {
+			return false
+		}
+	}
+	return true
+}
+
+// convertToPointGet converts the path to the point get or the batch point get if every range is a point,
+// nil is returned if it can't.
+func (ds *DataSource) convertToPointGet(prop *property.PhysicalProperty, path *accessPath) task {
+	if !ds.isPointPath(path) {
+		return nil
+	}
+	// The rows of the points are read in the order of the points, they are ordered if there is only one.
+	if !prop.IsEmpty() && len(path.ranges) > 1 {
+		return nil
+	}
+	count := float64(len(path.ranges))
+	stats := ds.scaledTableStats(count)
+	cost := count * (ds.tblRowSize()*scanFactor + seekFactor)
+	var filters []expression.Expression
+	var p PhysicalPlan
+	if len(path.ranges) == 1 {
+		pointGet := PointGetPlan{
+			physicalTableSource: ds.newTableSource(),
+			AccessConditions:    path.accessConds,
+		}.Init(ds.ctx, stats)
+		if path.isTablePath {
+			pointGet.Handle = path.ranges[0].LowVal[0].GetInt64()
+		} else {
+			pointGet.IndexInfo, pointGet.IndexValues = path.index, path.ranges[0].LowVal
+		}
+		pointGet.SetSchema(ds.schema)
+		pointGet.names = ds.names
+		p = pointGet
+	} else {
+		batchPointGet := BatchPointGetPlan{
+			physicalTableSource: ds.newTableSource(),
+			AccessConditions:    path.accessConds,
+		}.Init(ds.ctx, stats)
+		for _, ran := range path.ranges {
+			if path.isTablePath {
+				batchPointGet.Handles = append(batchPointGet.Handles, ran.LowVal[0].GetInt64())
+			} else {
+				batchPointGet.IndexValues = append(batchPointGet.IndexValues, ran.LowVal)
+			}
+		}
+		if !path.isTablePath {
+			batchPointGet.IndexInfo = path.index
+		}
+		batchPointGet.SetSchema(ds.schema)
+		batchPointGet.names = ds.names
+		p = batchPointGet
+	}
+	if !path.isTablePath {
+		// The index value is read before the row.
+		cost += count * seekFactor
+		filters = append(filters, path.indexFilters...)
+	}
+	filters = append(filters, path.tableFilters...)
+	p = addSelectionTask(p, filters, ds.stats.ScaleByExpectCnt(prop.ExpectedCnt), &cost)
+	return &rootTask{p: p, cst: cost}
+}
+
+// buildInnerTableScan builds the table scan of the inner child of an index join, which reads the rows by the
+// handles of the join keys.
+func (ds *DataSource) buildInnerTableScan() task {
+	pkCol := ds.getPKIsHandleCol()
+	ts := PhysicalTableScan{
+		physicalTableSource: ds.newTableSource(),
+		Ranges:              ranger.FullIntRange(mysql.HasUnsignedFlag(pkCol.RetType.Flag)),
+		HandleCol:           pkCol,
+		rangeDecidedBy:      []*expression.Column{pkCol},
+	}.Init(ds.ctx, ds.scaledTableStats(1))
+	ts.SetSchema(ds.schema)
+	ts.names = ds.names
+	cost := ds.tblRowSize()*scanFactor + seekFactor
+	p := addSelectionTask(ts, ds.pushedDownConds, ds.stats.Scale(1/math.Max(ds.tableStats.RowCount, 1)), &cost)
+	return &rootTask{p: p, cst: cost}
+}
+
+// buildInnerIndexScan builds the index scan or the index lookup of the inner child of an index join, which
+// reads the rows by the ranges of the join keys on the leading columns of the index. The count of the rows
+// of a lookup is the count of the rows of the same keys.
+func (ds *DataSource) buildInnerIndexScan(path *accessPath, keyCols []*expression.Column) task {
+	rowCount := 1.0
+	if !path.index.Unique || len(keyCols) != len(path.index.Columns) {
+		rowCount = ds.tableStats.RowCount / getCardinality(keyCols, ds.schema, ds.tableStats)
+	}
+	selectivity := 1.0
+	if ds.tableStats.RowCount > 0 {
+		selectivity = ds.stats.RowCount / ds.tableStats.RowCount
+	}
+	is := PhysicalIndexScan{
+		physicalTableSource: ds.newTableSource(),
+		Index:               path.index,
+		IdxCols:             path.idxCols,
+		IdxColLens:          path.idxColLens,
+		dataSourceSchema:    ds.schema,
+		rangeDecidedBy:      keyCols,
+	}.Init(ds.ctx, ds.scaledTableStats(rowCount))
+	cost := rowCount*idxRowSize(path)*scanFactor + seekFactor
+	var indexFilters, tableFilters []expression.Expression
+	for _, cond := range ds.pushedDownConds {
+		if ds.isCoveringCols(expression.ExtractColumns(cond), path.index) {
+			indexFilters = append(indexFilters, cond)
+		} else {
+			tableFilters = append(tableFilters, cond)
+		}
+	}
+	if path.isSingleScan {
+		is.SetSchema(ds.schema)
+		is.names = ds.names
+		p := addSelectionTask(is, indexFilters, ds.scaledTableStats(rowCount*selectivity), &cost)
+		return &rootTask{p: p, cst: cost}
+	}
+	ds.setIndexScanSchema(is)
+	indexPlan := addSelectionTask(is, indexFilters, ds.scaledTableStats(rowCount*selectionFactor), &cost)
+	lookupCount := indexPlan.StatsCount()
+	ts := PhysicalTableScan{
+		physicalTableSource: ds.newTableSource(),
+		HandleCol:           ds.getPKIsHandleCol(),
+	}.Init(ds.ctx, ds.scaledTableStats(lookupCount))
+	ts.SetSchema(ds.schema)
+	cost += lookupCount * (ds.tblRowSize()*scanFactor + seekFactor)
+	tablePlan := addSelectionTask(ts, tableFilters, ds.scaledTableStats(rowCount*selectivity), &cost)
+	lookup := PhysicalIndexLookUp{
+		IndexPlan: indexPlan,
+		TablePlan: tablePlan,
+	}.Init(ds.ctx, tablePlan.statsInfo())
+	lookup.SetSchema(ds.schema)
+	lookup.names = ds.names
+	return &rootTask{p: lookup, cst: cost}
+}
@@ -0,0 +1,274 @@
+package planner
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+)
+
+// The join algorithms preferred by the hints, the index join ones tell which child is the inner table.
+const (
+	preferLeftAsIndexInner = 1 << iota
+	preferRightAsIndexInner
+	preferHashJoin
+	preferMergeJoin
+)
+
+// The aggregation algorithms preferred by the hints.
+const (
+	preferHashAgg = 1 << iota
+	preferStreamAgg
+)
+
+// The names of the optimizer hints.
+const (
+	hintMergeJoin   = "merge_join"
+	hintSMJ         = "tidb_smj"
+	hintHashJoin    = "hash_join"
+	hintHJ          = "tidb_hj"
+	hintINLJ        = "inl_join"
+	hintTiDBINLJ    = "tidb_inlj"
+	hintHashAgg     = "hash_agg"
+	hintStreamAgg   = "stream_agg"
+	hintUseIndex    = "use_index"
+	hintIgnoreIndex = "ignore_index"
+)
+
+// hintTableInfo is a table of a hint, matched is set if the table is found in the query.
+type hintTableInfo struct {
+	dbName  model.CIStr
+	tblName model.CIStr
+	matched bool
+}
+
+// indexHintInfo is an index hint of the comment, which works like the index hint of the table.
+type indexHintInfo struct {
+	dbName    model.CIStr
+	tblName   model.CIStr
+	indexHint *ast.IndexHint
+	matched   bool
+}
+
+// aggHintInfo is the aggregation algorithms specified by the hints.
+type aggHintInfo struct {
+	preferAggType uint
+}
+
+// tableHintInfo is the optimizer hints of a select.
+type tableHintInfo struct {
+	sortMergeJoinTables       []hintTableInfo
+	hashJoinTables            []hintTableInfo
+	indexNestedLoopJoinTables []hintTableInfo
+	indexHintList             []indexHintInfo
+	aggHints                  aggHintInfo
+}
+
+func tableNames2HintTableInfo(hintTables []ast.HintTable) []hintTableInfo {
+	tables := make([]hintTableInfo, 0, len(hintTables))
+	for _, t := range hintTables {
+		tables = append(tables, hintTableInfo{dbName: t.DBName, tblName: t.TableName})
+	}
+	return tables
+}
+
+// matchTableName checks whether a table of the hint is the table of the alias, the tables are marked as
+// matched.
+func matchTableName(alias *hintTableInfo, hintTables []hintTableInfo) bool {
+	if alias == nil {
+		return false
+	}
+	found := false
+	for i := range hintTables {
+		table := &hintTables[i]
+		if table.tblName.L == alias.tblName.L && (table.dbName.L == "" || table.dbName.L == alias.dbName.L) {
+			table.matched = true
+			found = true
+		}
+	}
+	return found
+}
+
+func (info *tableHintInfo) ifPreferMergeJoin(tableNames ...*hintTableInfo) bool {
+	return info.matchTableNames(tableNames, info.sortMergeJoinTables)
+}
+
+func (info *tableHintInfo) ifPreferHashJoin(tableNames ...*hintTableInfo) bool {
+	return info.matchTableNames(tableNames, info.hashJoinTables)
+}
+
+func (info *tableHintInfo) ifPreferINLJ(tableNames ...*hintTableInfo) bool {
+	return info.matchTableNames(tableNames, info.indexNestedLoopJoinTables)
+}
+
+func (info *tableHintInfo) matchTableNames(tableNames []*hintTableInfo, hintTables []hintTableInfo) bool {
+	found := false
+	for _, name := range tableNames {
+		if matchTableName(name, hintTables) {
+			found = true
+		}
+	}
+	return found
+}
+
+// unmatchedTableWarnings returns the warnings of the tables of the hints which aren't found in the query.
+func (info *tableHintInfo) unmatchedTableWarnings() []error {
+	var warnings []error
+	appendWarning := func(hintName string, tables []hintTableInfo) {
+		var names []string
+		for _, table := range tables {
+			if !table.matched {
+				names = append(names, table.tblName.O)
+			}
+		}
+		if len(names) > 0 {
+			warnings = append(warnings, ErrInternal.GenWithStackByArgs(
+				fmt.Sprintf("There are no matching table names for (%s) in optimizer hint %s", strings.Join(names, ", "), hintName)))
+		}
+	}
+	appendWarning(hintMergeJoin, info.sortMergeJoinTables)
+	appendWarning(hintHashJoin, info.hashJoinTables)
+	appendWarning(hintINLJ, info.indexNestedLoopJoinTables)
+	for _, hint := range info.indexHintList {
+		if !hint.matched {
+			hintName := hintUseIndex
+			if hint.indexHint.HintType == ast.HintIgnore {
+				hintName = hintIgnoreIndex
+			}
+			warnings = append(warnings, ErrInternal.GenWithStackByArgs(
+				fmt.Sprintf("There are no matching table names for (%s) in optimizer hint %s", hint.tblName.O, hintName)))
+		}
+	}
+	return warnings
+}
+
+// pushTableHints pushes the hints of a select, the hints which can't be recognized are warned.
+func (b *PlanBuilder) pushTableHints(hints []*ast.TableOptimizerHint) {
+	info := tableHintInfo{}
+	sc := b.ctx.GetSessionVars().StmtCtx
+	for _, hint := range hints {
+		switch hint.HintName.L {
+		case hintMergeJoin, hintSMJ:
+			info.sortMergeJoinTables = append(info.sortMergeJoinTables, tableNames2HintTableInfo(hint.Tables)...)
+		case hintHashJoin, hintHJ:
+			info.hashJoinTables = append(info.hashJoinTables, tableNames2HintTableInfo(hint.Tables)...)
+		case hintINLJ, hintTiDBINLJ:
+			info.indexNestedLoopJoinTables = append(info.indexNestedLoopJoinTables, tableNames2HintTableInfo(hint.Tables)...)
+		case hintHashAgg:
+			info.aggHints.preferAggType |= preferHashAgg
+		case hintStreamAgg:
+			info.aggHints.preferAggType |= preferStreamAgg
+		case hintUseIndex, hintIgnoreIndex:
+			if len(hint.Tables) == 0 {
+				continue
+			}
+			hintType := ast.HintUse
+			if hint.HintName.L == hintIgnoreIndex {
+				hintType = ast.HintIgnore
+			}
+			info.indexHintList = append(info.indexHintList, indexHintInfo{
+				dbName:  hint.Tables[0].DBName,
+				tblName: hint.Tables[0].TableName,
+				indexHint: &ast.IndexHint{
+					IndexNames: hint.Indexes,
+					HintType:   hintType,
+					HintScope:  ast.HintForScan,
+				},
+			})
+		default:
+			sc.AppendWarning(ErrInternal.GenWithStackByArgs(fmt.Sprintf("Optimizer hint %s is not supported", hint.HintName.O)))
+		}
+	}
+	b.tableHintInfo = append(b.tableHintInfo, info)
+}
+
+// popTableHints pops the hints of a select, the tables of the hints which aren't found are warned.
+func (b *PlanBuilder) popTableHints() {
+	info := b.tableHintInfo[len(b.tableHintInfo)-1]
+	sc := b.ctx.GetSessionVars().StmtCtx
+	for _, warning := range info.unmatchedTableWarnings() {
+		sc.AppendWarning(warning)
+	}
+	b.tableHintInfo = b.tableHintInfo[:len(b.tableHintInfo)-1]
+}
+
+// currentTableHints returns the hints of the innermost select, which is nil if there is no select.
+func (b *PlanBuilder) currentTableHints() *tableHintInfo {
+	if len(b.tableHintInfo) == 0 {
+		return nil
+	}
+	return &b.tableHintInfo[len(b.tableHintInfo)-1]
+}
+
+// extractTableAlias returns the name of the table the plan reads, it's nil if the plan reads more than one
+// table.
+func extractTableAlias(p LogicalPlan) *hintTableInfo {
+	names := p.OutputNames()
+	if len(names) == 0 || names[0].TblName.L == "" {
+		return nil
+	}
+	for _, name := range names[1:] {
+		if name.TblName.L != names[0].TblName.L || name.DBName.L != names[0].DBName.L {
+			return nil
+		}
+	}
+	return &hintTableInfo{dbName: names[0].DBName, tblName: names[0].TblName}
+}
+
+// setPreferredJoinType sets the join algorithms of the join by the hints of the tables of the children.
+// The table of an index join hint is the inner table.
+func (b *PlanBuilder) setPreferredJoinType(p *LogicalJoin) {
+	info := b.currentTableHints()
+	if info == nil {
+		return
+	}
+	lAlias, rAlias := extractTableAlias(p.children[0]), extractTableAlias(p.children[1])
+	if info.ifPreferMergeJoin(lAlias, rAlias) {
+		p.preferJoinType |= preferMergeJoin
+	}
+	if info.ifPreferHashJoin(lAlias, rAlias) {
+		p.preferJoinType |= preferHashJoin
+	}
+	if info.ifPreferINLJ(lAlias) {
+		p.preferJoinType |= preferLeftAsIndexInner
+	}
+	if info.ifPreferINLJ(rAlias) {
+		p.preferJoinType |= preferRightAsIndexInner
+	}
+}
+
+// getIndexHints returns the index hints of the table, the ones of the comment hints are appended to the
+// ones of the table. The indices of the comment hints which aren't found are warned and ignored.
+func (b *PlanBuilder) getIndexHints(tn *ast.TableName, dbName, tblName model.CIStr, tblInfo *model.TableInfo) []*ast.IndexHint {
+	info := b.currentTableHints()
+	if info == nil {
+		return tn.IndexHints
+	}
+	indexHints := append(tn.IndexHints[:0:0], tn.IndexHints...)
+	for i := range info.indexHintList {
+		hint := &info.indexHintList[i]
+		if hint.tblName.L != tblName.L || (hint.dbName.L != "" && hint.dbName.L != dbName.L) {
+			continue
+		}
+		hint.matched = true
+		names := make([]model.CIStr, 0, len(hint.indexHint.IndexNames))
+		for _, idxName := range hint.indexHint.IndexNames {
+			if tblInfo.FindIndexByName(idxName.L) == nil && !(isPrimaryIndex(idxName) && tblInfo.PKIsHandle) {
+				b.ctx.GetSessionVars().StmtCtx.AppendWarning(ErrKeyDoesNotExist.GenWithStackByArgs(idxName, tblInfo.Name))
+				continue
+			}
+			names = append(names, idxName)
+		}
+		// The hint of no valid index is ignored, unlike `USE INDEX ()` which reads the table by the handles.
+		if len(names) == 0 {
+			continue
+		}
+		indexHints = append(indexHints, &ast.IndexHint{
+			IndexNames: names,
+			HintType:   hint.indexHint.HintType,
+			HintScope:  hint.indexHint.HintScope,
+		})
+	}
+	return indexHints
+}
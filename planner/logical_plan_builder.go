@@ -0,0 +1,1551 @@
+package planner
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/opcode"
+	"grant-db/expression"
+	"grant-db/expression/aggregation"
+	"grant-db/infoschema"
+	"grant-db/planner/property"
+	"grant-db/sessionctx"
+	"grant-db/types"
+)
+
+type clauseCode int
+
+const (
+	unknownClause clauseCode = iota
+	fieldList
+	havingClause
+	onClause
+	orderByClause
+	whereClause
+	groupByClause
+	windowPartitionByClause
+	windowOrderByClause
+)
+
+var clauseMsg = map[clauseCode]string{
+	unknownClause: "",
+	fieldList:     "field list",
+	havingClause:  "having clause",
+	onClause:      "on clause",
+	orderByClause: "order clause",
+	whereClause:   "where clause",
+	groupByClause: "group statement",
+
+	windowPartitionByClause: "window partition by",
+	windowOrderByClause:     "window order by",
+}
+
+// auxColName is the name of the auxiliary column of a left outer semi join.
+var auxColName = model.NewCIStr("aux")
+
+// PlanBuilder builds the logical plans from the AST statements.
+type PlanBuilder struct {
+	ctx sessionctx.Context
+	is  infoschema.InfoSchema
+	// outerSchemas and outerNames are the schemas and the names of the outer queries of a sub query, the
+	// innermost one is the last.
+	outerSchemas []*expression.Schema
+	outerNames   []types.NameSlice
+	// optFlag marks the logical optimization rules the plan needs.
+	optFlag   uint64
+	curClause clauseCode
+	// tableHintInfo is the stack of the optimizer hints of the selects, the innermost one is the last.
+	tableHintInfo []tableHintInfo
+	// inStraightJoin is set if the select has the STRAIGHT_JOIN option.
+	inStraightJoin bool
+	// inDMLTableRefs is set while the table references of UPDATE or DELETE are built, their data sources are
+	// recorded in dmlTargets.
+	inDMLTableRefs bool
+	dmlTargets     []*dmlTarget
+	// insertPlan is the INSERT whose ON DUPLICATE KEY UPDATE is being rewritten.
+	insertPlan *Insert
+}
+
+// NewPlanBuilder creates a new PlanBuilder.
+func NewPlanBuilder(ctx sessionctx.Context, is infoschema.InfoSchema) *PlanBuilder {
+	return &PlanBuilder{ctx: ctx, is: is}
+}
+
+// Build builds the logical plan of the statement.
+func (b *PlanBuilder) Build(node ast.Node) (LogicalPlan, error) {
+	b.optFlag = flagPrunColumns
+	switch x := node.(type) {
+	case *ast.SelectStmt, *ast.UnionStmt:
+		x.Accept(&aggFuncConverter{})
+		return b.buildResultSetNode(x.(ast.ResultSetNode))
+	}
+	return nil, ErrNotSupportedYet.GenWithStackByArgs(fmt.Sprintf("statement %T", node))
+}
+
+// aggFuncConverter converts the aggregate functions which are parsed as the function calls.
+type aggFuncConverter struct{}
+
+func (c *aggFuncConverter) Enter(n ast.Node) (ast.Node, bool) {
+	return n, false
+}
+
+func (c *aggFuncConverter) Leave(n ast.Node) (ast.Node, bool) {
+	if v, ok := n.(*ast.FuncCallExpr); ok && v.FnName.L == aggregation.AggFuncJSONArrayAgg {
+		return &ast.AggregateFuncExpr{F: aggregation.AggFuncJSONArrayAgg, Args: v.Args}, true
+	}
+	return n, true
+}
+
+func (b *PlanBuilder) buildResultSetNode(node ast.ResultSetNode) (LogicalPlan, error) {
+	switch x := node.(type) {
+	case *ast.Join:
+		return b.buildJoin(x)
+	case *ast.TableSource:
+		switch v := x.Source.(type) {
+		case *ast.TableName:
+			return b.buildDataSource(v, &x.AsName)
+		case *ast.SelectStmt, *ast.UnionStmt:
+			p, err := b.buildResultSetNode(v)
+			if err != nil {
+				return nil, err
+			}
+			// The columns of a derived table are named by the alias of the table.
+			names := make(types.NameSlice, 0, len(p.OutputNames()))
+			for _, name := range p.OutputNames() {
+				names = append(names, &types.FieldName{
+					OrigTblName: name.OrigTblName,
+					OrigColName: name.OrigColName,
+					TblName:     x.AsName,
+					ColName:     name.ColName,
+				})
+			}
+			p.SetOutputNames(names)
+			return p, nil
+		}
+		return nil, ErrNotSupportedYet.GenWithStackByArgs(fmt.Sprintf("table source %T", x.Source))
+	case *ast.SelectStmt:
+		return b.buildSelect(x)
+	case *ast.UnionStmt:
+		return b.buildUnion(x)
+	}
+	return nil, ErrNotSupportedYet.GenWithStackByArgs(fmt.Sprintf("result set node %T", node))
+}
+
+func (b *PlanBuilder) buildSelect(sel *ast.SelectStmt) (p LogicalPlan, err error) {
+	b.pushTableHints(sel.TableHints)
+	defer b.popTableHints()
+	// The tables of the sub queries and the derived tables aren't the targets of UPDATE or DELETE.
+	if b.inDMLTableRefs {
+		b.inDMLTableRefs = false
+		defer func() { b.inDMLTableRefs = true }()
+	}
+	if sel.SelectStmtOpts != nil {
+		origin := b.inStraightJoin
+		b.inStraightJoin = sel.SelectStmtOpts.StraightJoin
+		defer func() { b.inStraightJoin = origin }()
+	}
+	if sel.From != nil {
+		p, err = b.buildResultSetNode(sel.From.TableRefs)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		dual := LogicalTableDual{RowCount: 1}.Init(b.ctx)
+		dual.SetSchema(expression.NewSchema())
+		p = dual
+	}
+	fields, err := b.unfoldWildStar(p, sel.Fields.Fields)
+	if err != nil {
+		return nil, err
+	}
+	visibleLen := len(fields)
+	if sel.Where != nil {
+		b.curClause = whereClause
+		p, err = b.buildSelection(p, sel.Where, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// The columns and the aggregate functions of HAVING and ORDER BY are resolved to the select fields, the
+	// ones not selected are appended as the auxiliary fields.
+	resolver := &havingAndOrderbyResolver{
+		p:            p,
+		fields:       fields,
+		colMapper:    make(map[*ast.ColumnNameExpr]int),
+		aggMapper:    make(map[*ast.AggregateFuncExpr]int),
+		windowMapper: make(map[*ast.WindowFuncExpr]int),
+	}
+	if sel.Having != nil {
+		sel.Having.Expr.Accept(resolver)
+	}
+	if sel.OrderBy != nil {
+		resolver.inOrderBy = true
+		for _, item := range sel.OrderBy.Items {
+			item.Expr.Accept(resolver)
+		}
+	}
+	if resolver.err != nil {
+		return nil, resolver.err
+	}
+	fields = resolver.fields
+	windowFuncs := extractWindowFuncs(fields)
+	var windowResolver *windowFuncResolver
+	if len(windowFuncs) > 0 {
+		windowResolver, err = resolveWindowFuncs(p, fields, sel.WindowSpecs)
+		if err != nil {
+			return nil, err
+		}
+		fields = windowResolver.fields
+	}
+	aggFuncs := extractAggFuncs(fields)
+	var aggMapper map[*ast.AggregateFuncExpr]int
+	if sel.GroupBy != nil || len(aggFuncs) > 0 {
+		var gbyItems []expression.Expression
+		if sel.GroupBy != nil {
+			p, gbyItems, err = b.resolveGbyExprs(p, sel.GroupBy, fields[:visibleLen])
+			if err != nil {
+				return nil, err
+			}
+		}
+		p, aggMapper, err = b.buildAggregation(p, aggFuncs, gbyItems)
+		if err != nil {
+			return nil, err
+		}
+	}
+	p, err = b.buildProjection(p, fields, aggMapper)
+	if err != nil {
+		return nil, err
+	}
+	if sel.Having != nil {
+		b.curClause = havingClause
+		p, err = b.buildSelection(p, sel.Having.Expr, resolver.aggMapper, resolver.colMapper)
+		if err != nil {
+			return nil, err
+		}
+	}
+	// The window functions are evaluated on the rows filtered by HAVING, so the fields containing them are
+	// built over the windows after the other fields.
+	if len(windowFuncs) > 0 {
+		var windowMapper map[*ast.WindowFuncExpr]int
+		p, windowMapper, err = b.buildWindowFunctions(p, windowFuncs, sel.WindowSpecs, windowResolver.aggMapper, windowResolver.colMapper)
+		if err != nil {
+			return nil, err
+		}
+		p, err = b.buildWindowProjection(p, fields, windowResolver.aggMapper, windowResolver.colMapper, windowMapper)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if sel.Distinct {
+		p, err = b.buildDistinct(p)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if sel.OrderBy != nil {
+		p, err = b.buildSort(p, sel.OrderBy.Items, resolver.aggMapper, resolver.colMapper, resolver.windowMapper, visibleLen)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if sel.Limit != nil {
+		p, err = b.buildLimit(p, sel.Limit)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return b.trimColumns(p, visibleLen), nil
+}
+
+// trimColumns removes the auxiliary columns behind the first n columns by a projection.
+func (b *PlanBuilder) trimColumns(p LogicalPlan, n int) LogicalPlan {
+	if p.Schema().Len() == n {
+		return p
+	}
+	cols := make([]*expression.Column, 0, n)
+	exprs := make([]expression.Expression, 0, n)
+	for _, col := range p.Schema().Columns[:n] {
+		cols = append(cols, col)
+		exprs = append(exprs, col)
+	}
+	proj := LogicalProjection{Exprs: exprs}.Init(b.ctx)
+	proj.SetSchema(expression.NewSchema(cols...))
+	proj.names = append(p.OutputNames()[:0:0], p.OutputNames()[:n]...)
+	proj.SetChildren(p)
+	return proj
+}
+
+// unfoldWildStar expands the wildcards of the select fields to the columns.
+func (b *PlanBuilder) unfoldWildStar(p LogicalPlan, selectFields []*ast.SelectField) ([]*ast.SelectField, error) {
+	resultList := make([]*ast.SelectField, 0, len(selectFields))
+	for _, field := range selectFields {
+		if field.WildCard == nil {
+			resultList = append(resultList, field)
+			continue
+		}
+		dbName, tblName := field.WildCard.Schema, field.WildCard.Table
+		if tblName.L == "" && len(p.OutputNames()) == 0 {
+			return nil, ErrNoTablesUsed
+		}
+		found := false
+		for _, name := range p.OutputNames() {
+			if tblName.L == "" {
+				if name.Redundant {
+					continue
+				}
+			} else if name.TblName.L != tblName.L || (dbName.L != "" && dbName.L != name.DBName.L) {
+				continue
+			}
+			found = true
+			colName := &ast.ColumnNameExpr{Name: &ast.ColumnName{Schema: name.DBName, Table: name.TblName, Name: name.ColName}}
+			resultList = append(resultList, &ast.SelectField{Expr: colName})
+		}
+		if !found && tblName.L != "" {
+			return nil, ErrBadTable.GenWithStackByArgs(tblName.O)
+		}
+	}
+	return resultList, nil
+}
+
+// splitWhere splits the AND conditions of the AST expression.
+func splitWhere(where ast.ExprNode) []ast.ExprNode {
+	var conditions []ast.ExprNode
+	switch x := where.(type) {
+	case *ast.BinaryOperationExpr:
+		if x.Op == opcode.LogicAnd {
+			conditions = append(conditions, splitWhere(x.L)...)
+			conditions = append(conditions, splitWhere(x.R)...)
+		} else {
+			conditions = append(conditions, x)
+		}
+	case *ast.ParenthesesExpr:
+		conditions = append(conditions, splitWhere(x.Expr)...)
+	default:
+		conditions = append(conditions, where)
+	}
+	return conditions
+}
+
+// buildSelection builds the selection of WHERE and HAVING, the sub queries of EXISTS and IN of the conditions
+// are semi joins.
+func (b *PlanBuilder) buildSelection(p LogicalPlan, where ast.ExprNode, aggMapper map[*ast.AggregateFuncExpr]int,
+	colMapper map[*ast.ColumnNameExpr]int) (LogicalPlan, error) {
+	b.optFlag |= flagPredicatePushDown | flagConstantPropagation
+	conditions := splitWhere(where)
+	expressions := make([]expression.Expression, 0, len(conditions))
+	for _, cond := range conditions {
+		expr, np, err := b.rewriteWithMapper(cond, p, aggMapper, colMapper, nil, false)
+		if err != nil {
+			return nil, err
+		}
+		p = np
+		for _, item := range expression.SplitCNFItems(expr) {
+			if expression.IsTrue(b.ctx, item) {
+				continue
+			}
+			expressions = append(expressions, item)
+		}
+	}
+	if len(expressions) == 0 {
+		return p, nil
+	}
+	selection := LogicalSelection{Conditions: expressions}.Init(b.ctx)
+	selection.SetChildren(p)
+	return selection, nil
+}
+
+// havingAndOrderbyResolver resolves the columns and the aggregate functions of HAVING and ORDER BY to the select
+// fields, which are appended to the fields if they are not selected. The window functions are only allowed in
+// ORDER BY.
+type havingAndOrderbyResolver struct {
+	p            LogicalPlan
+	fields       []*ast.SelectField
+	colMapper    map[*ast.ColumnNameExpr]int
+	aggMapper    map[*ast.AggregateFuncExpr]int
+	windowMapper map[*ast.WindowFuncExpr]int
+	inOrderBy    bool
+	// ignoreAlias is set if the aliases of the select fields are invisible.
+	ignoreAlias bool
+	err         error
+}
+
+func (r *havingAndOrderbyResolver) Enter(n ast.Node) (ast.Node, bool) {
+	switch v := n.(type) {
+	case *ast.ColumnNameExpr:
+		idx := r.matchField(v.Name)
+		if idx >= 0 && !r.inOrderBy && containsWindowFunc(r.fields[idx].Expr) {
+			r.err = ErrWindowInvalidWindowFuncAliasUse.GenWithStackByArgs(v.Name.Name.O)
+			return n, true
+		}
+		if idx == -1 {
+			i, err := expression.FindFieldName(r.p.OutputNames(), v.Name)
+			if err != nil {
+				r.err = err
+				return n, true
+			}
+			if i >= 0 {
+				r.fields = append(r.fields, &ast.SelectField{Expr: v, Auxiliary: true})
+				idx = len(r.fields) - 1
+			}
+		}
+		if idx >= 0 {
+			r.colMapper[v] = idx
+		}
+		return n, true
+	case *ast.AggregateFuncExpr:
+		r.fields = append(r.fields, &ast.SelectField{Expr: v, Auxiliary: true})
+		r.aggMapper[v] = len(r.fields) - 1
+		return n, true
+	case *ast.WindowFuncExpr:
+		if !r.inOrderBy {
+			r.err = ErrWindowInvalidWindowFuncUse.GenWithStackByArgs(strings.ToLower(v.F))
+			return n, true
+		}
+		r.fields = append(r.fields, &ast.SelectField{Expr: v, Auxiliary: true})
+		r.windowMapper[v] = len(r.fields) - 1
+		return n, true
+	case *ast.SubqueryExpr:
+		return n, true
+	}
+	return n, false
+}
+
+func (r *havingAndOrderbyResolver) Leave(n ast.Node) (ast.Node, bool) {
+	return n, r.err == nil
+}
+
+// matchField finds the select field which is the column or whose alias is the column name, -1 is returned if
+// there is none.
+func (r *havingAndOrderbyResolver) matchField(name *ast.ColumnName) int {
+	for i, field := range r.fields {
+		if field.AsName.L != "" && !r.ignoreAlias {
+			if name.Schema.L == "" && name.Table.L == "" && field.AsName.L == name.Name.L {
+				return i
+			}
+			continue
+		}
+		col, ok := field.Expr.(*ast.ColumnNameExpr)
+		if !ok {
+			continue
+		}
+		idx1, err1 := expression.FindFieldName(r.p.OutputNames(), col.Name)
+		idx2, err2 := expression.FindFieldName(r.p.OutputNames(), name)
+		if err1 == nil && err2 == nil && idx1 >= 0 && idx1 == idx2 {
+			return i
+		}
+	}
+	return -1
+}
+
+// aggFuncExtractor collects the aggregate functions of an expression, the ones of the sub queries are excluded.
+type aggFuncExtractor struct {
+	aggFuncs []*ast.AggregateFuncExpr
+	seen     map[*ast.AggregateFuncExpr]struct{}
+}
+
+func (e *aggFuncExtractor) Enter(n ast.Node) (ast.Node, bool) {
+	switch v := n.(type) {
+	case *ast.AggregateFuncExpr:
+		if _, ok := e.seen[v]; !ok {
+			e.seen[v] = struct{}{}
+			e.aggFuncs = append(e.aggFuncs, v)
+		}
+		return n, true
+	case *ast.SubqueryExpr:
+		return n, true
+	}
+	return n, false
+}
+
+func (e *aggFuncExtractor) Leave(n ast.Node) (ast.Node, bool) {
+	return n, true
+}
+
+func extractAggFuncs(fields []*ast.SelectField) []*ast.AggregateFuncExpr {
+	extractor := &aggFuncExtractor{seen: make(map[*ast.AggregateFuncExpr]struct{})}
+	for _, field := range fields {
+		field.Expr.Accept(extractor)
+	}
+	return extractor.aggFuncs
+}
+
+// windowFuncExtractor collects the window functions of an expression, the ones of the sub queries are excluded.
+type windowFuncExtractor struct {
+	windowFuncs []*ast.WindowFuncExpr
+}
+
+func (e *windowFuncExtractor) Enter(n ast.Node) (ast.Node, bool) {
+	switch v := n.(type) {
+	case *ast.WindowFuncExpr:
+		e.windowFuncs = append(e.windowFuncs, v)
+		return n, true
+	case *ast.SubqueryExpr:
+		return n, true
+	}
+	return n, false
+}
+
+func (e *windowFuncExtractor) Leave(n ast.Node) (ast.Node, bool) {
+	return n, true
+}
+
+func extractWindowFuncs(fields []*ast.SelectField) []*ast.WindowFuncExpr {
+	extractor := &windowFuncExtractor{}
+	for _, field := range fields {
+		field.Expr.Accept(extractor)
+	}
+	return extractor.windowFuncs
+}
+
+func containsWindowFunc(expr ast.ExprNode) bool {
+	extractor := &windowFuncExtractor{}
+	expr.Accept(extractor)
+	return len(extractor.windowFuncs) > 0
+}
+
+// windowFuncResolver resolves the columns and the aggregate functions of the select fields containing window
+// functions. These fields are built over the windows whose child is the projection of the other fields, so
+// the columns and the aggregate functions they use are appended as the auxiliary fields of the projection.
+type windowFuncResolver struct {
+	havingAndOrderbyResolver
+	// inWindow is set when a window function is resolved, and specName is the name of its window when the
+	// window is resolved, a nested window function is not allowed.
+	inWindow bool
+	specName string
+}
+
+func resolveWindowFuncs(p LogicalPlan, fields []*ast.SelectField, specs []ast.WindowSpec) (*windowFuncResolver, error) {
+	r := &windowFuncResolver{havingAndOrderbyResolver: havingAndOrderbyResolver{
+		p:           p,
+		fields:      fields,
+		colMapper:   make(map[*ast.ColumnNameExpr]int),
+		aggMapper:   make(map[*ast.AggregateFuncExpr]int),
+		ignoreAlias: true,
+	}}
+	for _, field := range fields {
+		if containsWindowFunc(field.Expr) {
+			field.Expr.Accept(r)
+		}
+	}
+	for i := range specs {
+		r.resolveSpec(&specs[i])
+	}
+	return r, r.err
+}
+
+func (r *windowFuncResolver) Enter(n ast.Node) (ast.Node, bool) {
+	v, ok := n.(*ast.WindowFuncExpr)
+	if !ok {
+		return r.havingAndOrderbyResolver.Enter(n)
+	}
+	switch {
+	case r.specName != "":
+		r.err = ErrWindowNestedWindowFuncUseInWindowSpec.GenWithStackByArgs(r.specName)
+	case r.inWindow:
+		r.err = ErrWindowInvalidWindowFuncUse.GenWithStackByArgs(strings.ToLower(v.F))
+	default:
+		r.inWindow = true
+		for _, arg := range v.Args {
+			arg.Accept(r)
+		}
+		r.resolveSpec(&v.Spec)
+		r.inWindow = false
+	}
+	return n, true
+}
+
+func (r *windowFuncResolver) resolveSpec(spec *ast.WindowSpec) {
+	if r.err != nil {
+		return
+	}
+	r.specName = windowSpecName(spec)
+	spec.Accept(r)
+	r.specName = ""
+}
+
+func windowSpecName(spec *ast.WindowSpec) string {
+	if spec.Name.O != "" {
+		return spec.Name.O
+	}
+	return "<unnamed window>"
+}
+
+// resolveGbyExprs rewrites the group by items, a position or an alias refers to the select field.
+func (b *PlanBuilder) resolveGbyExprs(p LogicalPlan, gby *ast.GroupByClause, fields []*ast.SelectField) (LogicalPlan, []expression.Expression, error) {
+	b.curClause = groupByClause
+	exprs := make([]expression.Expression, 0, len(gby.Items))
+	for _, item := range gby.Items {
+		itemExpr, err := resolveGbyItem(p, item.Expr, fields)
+		if err != nil {
+			return nil, nil, err
+		}
+		expr, np, err := b.rewrite(itemExpr, p, nil, true)
+		if err != nil {
+			return nil, nil, err
+		}
+		p = np
+		exprs = append(exprs, expr)
+	}
+	return p, exprs, nil
+}
+
+func resolveGbyItem(p LogicalPlan, item ast.ExprNode, fields []*ast.SelectField) (ast.ExprNode, error) {
+	var field *ast.SelectField
+	switch v := item.(type) {
+	case *ast.PositionExpr:
+		if v.N < 1 || v.N > len(fields) {
+			return nil, ErrUnknownColumn.GenWithStackByArgs(strconv.Itoa(v.N), clauseMsg[groupByClause])
+		}
+		field = fields[v.N-1]
+	case *ast.ColumnNameExpr:
+		// The columns of the tables go before the aliases of the select fields.
+		idx, err := expression.FindFieldName(p.OutputNames(), v.Name)
+		if err != nil || idx >= 0 || v.Name.Table.L != "" {
+			return item, err
+		}
+		for _, f := range fields {
+			if f.AsName.L == v.Name.Name.L {
+				field = f
+				break
+			}
+		}
+	}
+	if field == nil {
+		return item, nil
+	}
+	name := field.AsName.O
+	if name == "" {
+		name = field.Text()
+	}
+	if len(extractAggFuncs([]*ast.SelectField{field})) > 0 {
+		return nil, ErrWrongGroupField.GenWithStackByArgs(name)
+	}
+	if containsWindowFunc(field.Expr) {
+		return nil, ErrWindowInvalidWindowFuncAliasUse.GenWithStackByArgs(name)
+	}
+	return field.Expr, nil
+}
+
+// buildAggregation builds the aggregation of the aggregate functions, the columns of the child are kept by
+// the firstrow functions behind them.
+func (b *PlanBuilder) buildAggregation(p LogicalPlan, aggFuncList []*ast.AggregateFuncExpr,
+	gbyItems []expression.Expression) (LogicalPlan, map[*ast.AggregateFuncExpr]int, error) {
+	b.optFlag |= flagBuildKeyInfo | flagPredicatePushDown | flagMaxMinEliminate | flagPushDownTopN
+	b.curClause = fieldList
+	plan4Agg := LogicalAggregation{AggFuncs: make([]*aggregation.AggFuncDesc, 0, len(aggFuncList))}.Init(b.ctx)
+	schema := expression.NewSchema()
+	names := make(types.NameSlice, 0, len(aggFuncList)+p.Schema().Len())
+	aggIndexMap := make(map[*ast.AggregateFuncExpr]int, len(aggFuncList))
+	for _, aggFunc := range aggFuncList {
+		newArgs := make([]expression.Expression, 0, len(aggFunc.Args))
+		for _, arg := range aggFunc.Args {
+			newArg, np, err := b.rewrite(arg, p, nil, true)
+			if err != nil {
+				return nil, nil, err
+			}
+			p = np
+			newArgs = append(newArgs, newArg)
+		}
+		newFunc, err := aggregation.NewAggFuncDesc(b.ctx, aggFunc.F, newArgs, aggFunc.Distinct)
+		if err != nil {
+			return nil, nil, err
+		}
+		if aggFunc.Order != nil {
+			if newFunc.OrderByItems, p, err = b.buildAggOrderByItems(p, aggFunc.Order.Items, newFunc.Args); err != nil {
+				return nil, nil, err
+			}
+		}
+		combined := false
+		for j, oldFunc := range plan4Agg.AggFuncs {
+			if oldFunc.Equal(b.ctx, newFunc) {
+				aggIndexMap[aggFunc] = j
+				combined = true
+				break
+			}
+		}
+		if combined {
+			continue
+		}
+		aggIndexMap[aggFunc] = len(plan4Agg.AggFuncs)
+		plan4Agg.AggFuncs = append(plan4Agg.AggFuncs, newFunc)
+		schema.Append(&expression.Column{UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(), RetType: newFunc.RetTp})
+		names = append(names, &types.FieldName{})
+	}
+	for i, col := range p.Schema().Columns {
+		newFunc, err := aggregation.NewAggFuncDesc(b.ctx, ast.AggFuncFirstRow, []expression.Expression{col}, false)
+		if err != nil {
+			return nil, nil, err
+		}
+		plan4Agg.AggFuncs = append(plan4Agg.AggFuncs, newFunc)
+		newCol := col.Clone().(*expression.Column)
+		newCol.RetType = newFunc.RetTp
+		schema.Append(newCol)
+		names = append(names, p.OutputNames()[i])
+	}
+	plan4Agg.GroupByItems = gbyItems
+	if hints := b.currentTableHints(); hints != nil {
+		plan4Agg.aggHints = hints.aggHints
+	}
+	plan4Agg.SetChildren(p)
+	plan4Agg.SetSchema(schema)
+	plan4Agg.names = names
+	return plan4Agg, aggIndexMap, nil
+}
+
+// buildAggOrderByItems builds the ORDER BY items of GROUP_CONCAT, a position refers to the argument of the
+// function, which doesn't include the separator.
+func (b *PlanBuilder) buildAggOrderByItems(p LogicalPlan, items []*ast.ByItem, args []expression.Expression) ([]*aggregation.ByItem, LogicalPlan, error) {
+	byItems := make([]*aggregation.ByItem, 0, len(items))
+	for _, item := range items {
+		if pos, ok := item.Expr.(*ast.PositionExpr); ok {
+			if pos.P != nil || pos.N < 1 || pos.N >= len(args) {
+				return nil, nil, ErrUnknownColumn.GenWithStackByArgs(strconv.Itoa(pos.N), clauseMsg[orderByClause])
+			}
+			byItems = append(byItems, &aggregation.ByItem{Expr: args[pos.N-1], Desc: item.Desc})
+			continue
+		}
+		expr, np, err := b.rewrite(item.Expr, p, nil, true)
+		if err != nil {
+			return nil, nil, err
+		}
+		p = np
+		byItems = append(byItems, &aggregation.ByItem{Expr: expr, Desc: item.Desc})
+	}
+	return byItems, p, nil
+}
+
+// buildDistinct builds the aggregation which groups the rows by all the columns of the child.
+func (b *PlanBuilder) buildDistinct(child LogicalPlan) (LogicalPlan, error) {
+	b.optFlag |= flagBuildKeyInfo | flagPredicatePushDown
+	plan4Agg := LogicalAggregation{
+		AggFuncs:     make([]*aggregation.AggFuncDesc, 0, child.Schema().Len()),
+		GroupByItems: make([]expression.Expression, 0, child.Schema().Len()),
+	}.Init(b.ctx)
+	schema := expression.NewSchema()
+	for _, col := range child.Schema().Columns {
+		plan4Agg.GroupByItems = append(plan4Agg.GroupByItems, col)
+		newFunc, err := aggregation.NewAggFuncDesc(b.ctx, ast.AggFuncFirstRow, []expression.Expression{col}, false)
+		if err != nil {
+			return nil, err
+		}
+		plan4Agg.AggFuncs = append(plan4Agg.AggFuncs, newFunc)
+		newCol := col.Clone().(*expression.Column)
+		newCol.RetType = newFunc.RetTp
+		schema.Append(newCol)
+	}
+	if hints := b.currentTableHints(); hints != nil {
+		plan4Agg.aggHints = hints.aggHints
+	}
+	plan4Agg.SetChildren(child)
+	plan4Agg.SetSchema(schema)
+	plan4Agg.names = append(child.OutputNames()[:0:0], child.OutputNames()...)
+	return plan4Agg, nil
+}
+
+// buildProjection builds the projection of the select fields.
+func (b *PlanBuilder) buildProjection(p LogicalPlan, fields []*ast.SelectField, aggMapper map[*ast.AggregateFuncExpr]int) (LogicalPlan, error) {
+	b.curClause = fieldList
+	proj := LogicalProjection{Exprs: make([]expression.Expression, 0, len(fields))}.Init(b.ctx)
+	schema := expression.NewSchema(make([]*expression.Column, 0, len(fields))...)
+	names := make(types.NameSlice, 0, len(fields))
+	for _, field := range fields {
+		var newExpr expression.Expression = expression.NewZero()
+		// The fields containing window functions are built over the windows, the placeholders are pruned.
+		if !containsWindowFunc(field.Expr) {
+			var (
+				np  LogicalPlan
+				err error
+			)
+			newExpr, np, err = b.rewrite(field.Expr, p, aggMapper, true)
+			if err != nil {
+				return nil, err
+			}
+			p = np
+		}
+		proj.Exprs = append(proj.Exprs, newExpr)
+		col := &expression.Column{UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(), RetType: newExpr.GetType()}
+		if c, ok := newExpr.(*expression.Column); ok {
+			col.OrigName = c.OrigName
+		}
+		schema.Append(col)
+		names = append(names, buildProjectionFieldName(p, field, newExpr))
+	}
+	proj.SetSchema(schema)
+	proj.names = names
+	proj.SetChildren(p)
+	return proj, nil
+}
+
+// buildProjectionFieldName names the select field, a column keeps the name of the table column and an
+// expression is named by its text, the alias goes first.
+func buildProjectionFieldName(p LogicalPlan, field *ast.SelectField, expr expression.Expression) *types.FieldName {
+	var name *types.FieldName
+	if _, ok := field.Expr.(*ast.ColumnNameExpr); ok {
+		if col, ok := expr.(*expression.Column); ok {
+			if idx := p.Schema().ColumnIndex(col); idx != -1 {
+				colName := *p.OutputNames()[idx]
+				colName.Redundant = false
+				name = &colName
+			}
+		}
+	}
+	if name == nil {
+		name = &types.FieldName{ColName: model.NewCIStr(field.Text())}
+	}
+	if field.AsName.L != "" {
+		name.ColName = field.AsName
+	}
+	return name
+}
+
+// buildSort builds the sort of ORDER BY, a position refers to the select field.
+func (b *PlanBuilder) buildSort(p LogicalPlan, byItems []*ast.ByItem, aggMapper map[*ast.AggregateFuncExpr]int,
+	colMapper map[*ast.ColumnNameExpr]int, windowMapper map[*ast.WindowFuncExpr]int, fieldLen int) (LogicalPlan, error) {
+	b.curClause = orderByClause
+	sort := LogicalSort{ByItems: make([]*ByItems, 0, len(byItems))}.Init(b.ctx)
+	for _, item := range byItems {
+		var (
+			expr expression.Expression
+			err  error
+		)
+		if pos, ok := item.Expr.(*ast.PositionExpr); ok {
+			expr, err = positionToColumn(p, pos, fieldLen)
+		} else {
+			expr, p, err = b.rewriteWithMapper(item.Expr, p, aggMapper, colMapper, windowMapper, true)
+		}
+		if err != nil {
+			return nil, err
+		}
+		sort.ByItems = append(sort.ByItems, &ByItems{Expr: expr, Desc: item.Desc})
+	}
+	sort.SetChildren(p)
+	return sort, nil
+}
+
+// buildWindowProjection builds the projection of the select fields over the windows, the fields without
+// window functions are the columns of the projection below the windows.
+func (b *PlanBuilder) buildWindowProjection(p LogicalPlan, fields []*ast.SelectField, aggMapper map[*ast.AggregateFuncExpr]int,
+	colMapper map[*ast.ColumnNameExpr]int, windowMapper map[*ast.WindowFuncExpr]int) (LogicalPlan, error) {
+	b.curClause = fieldList
+	proj := LogicalProjection{Exprs: make([]expression.Expression, 0, len(fields))}.Init(b.ctx)
+	schema := expression.NewSchema(make([]*expression.Column, 0, len(fields))...)
+	names := make(types.NameSlice, 0, len(fields))
+	for i, field := range fields {
+		if !containsWindowFunc(field.Expr) {
+			col := p.Schema().Columns[i]
+			proj.Exprs = append(proj.Exprs, col)
+			schema.Append(&expression.Column{UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(), RetType: col.RetType, OrigName: col.OrigName})
+			names = append(names, p.OutputNames()[i])
+			continue
+		}
+		newExpr, np, err := b.rewriteWithMapper(field.Expr, p, aggMapper, colMapper, windowMapper, true)
+		if err != nil {
+			return nil, err
+		}
+		p = np
+		proj.Exprs = append(proj.Exprs, newExpr)
+		schema.Append(&expression.Column{UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(), RetType: newExpr.GetType()})
+		names = append(names, buildProjectionFieldName(p, field, newExpr))
+	}
+	proj.SetSchema(schema)
+	proj.names = names
+	proj.SetChildren(p)
+	return proj, nil
+}
+
+// buildWindowFunctions builds the windows of the window functions, the functions of the same window are
+// evaluated by one LogicalWindow, and the windows are stacked in the order they're used. The offsets of the
+// results of the functions in the schema of the top window are returned.
+func (b *PlanBuilder) buildWindowFunctions(p LogicalPlan, windowFuncs []*ast.WindowFuncExpr, specs []ast.WindowSpec,
+	aggMapper map[*ast.AggregateFuncExpr]int, colMapper map[*ast.ColumnNameExpr]int) (LogicalPlan, map[*ast.WindowFuncExpr]int, error) {
+	named := make(map[string]*ast.WindowSpec, len(specs))
+	for i := range specs {
+		if _, ok := named[specs[i].Name.L]; ok {
+			return nil, nil, ErrWindowDuplicateName.GenWithStackByArgs(specs[i].Name.O)
+		}
+		named[specs[i].Name.L] = &specs[i]
+	}
+	merger := &windowSpecMerger{named: named, merged: make(map[*ast.WindowSpec]*ast.WindowSpec)}
+	for i := range specs {
+		if _, err := merger.merge(&specs[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+	var windowSpecs []*ast.WindowSpec
+	groupedFuncs := make(map[*ast.WindowSpec][]*ast.WindowFuncExpr)
+	for _, windowFunc := range windowFuncs {
+		spec, err := merger.merge(&windowFunc.Spec)
+		if err != nil {
+			return nil, nil, err
+		}
+		if _, ok := groupedFuncs[spec]; !ok {
+			windowSpecs = append(windowSpecs, spec)
+		}
+		groupedFuncs[spec] = append(groupedFuncs[spec], windowFunc)
+	}
+	resultCols := make(map[*ast.WindowFuncExpr]*expression.Column, len(windowFuncs))
+	for _, spec := range windowSpecs {
+		var err error
+		p, err = b.buildWindow(p, spec, groupedFuncs[spec], aggMapper, colMapper, resultCols)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	windowMapper := make(map[*ast.WindowFuncExpr]int, len(resultCols))
+	for windowFunc, col := range resultCols {
+		windowMapper[windowFunc] = p.Schema().ColumnIndex(col)
+	}
+	return p, windowMapper, nil
+}
+
+// windowSpecMerger merges the windows with the named windows they reference.
+type windowSpecMerger struct {
+	named  map[string]*ast.WindowSpec
+	merged map[*ast.WindowSpec]*ast.WindowSpec
+	// visiting are the windows being merged, which detect the circular references.
+	visiting map[*ast.WindowSpec]bool
+}
+
+func (m *windowSpecMerger) merge(spec *ast.WindowSpec) (*ast.WindowSpec, error) {
+	if spec.OnlyAlias {
+		ref, ok := m.named[spec.Name.L]
+		if !ok {
+			return nil, ErrWindowNoSuchWindow.GenWithStackByArgs(spec.Name.O)
+		}
+		return m.merge(ref)
+	}
+	if spec.Ref.L == "" {
+		return spec, nil
+	}
+	if merged, ok := m.merged[spec]; ok {
+		return merged, nil
+	}
+	if m.visiting[spec] {
+		return nil, ErrWindowCircularityInWindowGraph
+	}
+	ref, ok := m.named[spec.Ref.L]
+	if !ok {
+		return nil, ErrWindowNoSuchWindow.GenWithStackByArgs(spec.Ref.O)
+	}
+	if m.visiting == nil {
+		m.visiting = make(map[*ast.WindowSpec]bool)
+	}
+	m.visiting[spec] = true
+	base, err := m.merge(ref)
+	delete(m.visiting, spec)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case spec.PartitionBy != nil:
+		return nil, ErrWindowNoChildPartitioning
+	case base.Frame != nil:
+		return nil, ErrWindowNoInherentFrame.GenWithStackByArgs(ref.Name.O)
+	case spec.OrderBy != nil && base.OrderBy != nil:
+		return nil, ErrWindowNoRedefineOrderBy.GenWithStackByArgs(windowSpecName(spec), ref.Name.O)
+	}
+	merged := &ast.WindowSpec{Name: spec.Name, PartitionBy: base.PartitionBy, OrderBy: base.OrderBy, Frame: spec.Frame}
+	if spec.OrderBy != nil {
+		merged.OrderBy = spec.OrderBy
+	}
+	m.merged[spec] = merged
+	return merged, nil
+}
+
+// buildWindow builds the LogicalWindow of the functions of the window, the PARTITION BY and ORDER BY items
+// which are not columns are computed by a projection below it.
+func (b *PlanBuilder) buildWindow(p LogicalPlan, spec *ast.WindowSpec, windowFuncs []*ast.WindowFuncExpr, aggMapper map[*ast.AggregateFuncExpr]int,
+	colMapper map[*ast.ColumnNameExpr]int, resultCols map[*ast.WindowFuncExpr]*expression.Column) (LogicalPlan, error) {
+	name := windowSpecName(spec)
+	b.curClause = fieldList
+	argsList := make([][]expression.Expression, 0, len(windowFuncs))
+	for _, windowFunc := range windowFuncs {
+		if windowFunc.IgnoreNull {
+			return nil, ErrNotSupportedYet.GenWithStackByArgs("IGNORE NULLS")
+		}
+		if windowFunc.FromLast {
+			return nil, ErrNotSupportedYet.GenWithStackByArgs("FROM LAST")
+		}
+		args := make([]expression.Expression, 0, len(windowFunc.Args))
+		for _, arg := range windowFunc.Args {
+			newArg, np, err := b.rewriteWithMapper(arg, p, aggMapper, colMapper, nil, true)
+			if err != nil {
+				return nil, err
+			}
+			p = np
+			args = append(args, newArg)
+		}
+		argsList = append(argsList, args)
+	}
+	var partitionItems, orderItems []*ast.ByItem
+	if spec.PartitionBy != nil {
+		partitionItems = spec.PartitionBy.Items
+	}
+	if spec.OrderBy != nil {
+		orderItems = spec.OrderBy.Items
+	}
+	// The items which are not columns are appended to the columns of the child by a projection.
+	var (
+		extraExprs []expression.Expression
+		extraCols  []*expression.Column
+	)
+	buildItems := func(items []*ast.ByItem, clause clauseCode) ([]property.SortItem, error) {
+		b.curClause = clause
+		sortItems := make([]property.SortItem, 0, len(items))
+		for _, item := range items {
+			if _, ok := item.Expr.(*ast.PositionExpr); ok {
+				return nil, ErrWindowIllegalOrderBy.GenWithStackByArgs(name)
+			}
+			expr, np, err := b.rewriteWithMapper(item.Expr, p, aggMapper, colMapper, nil, true)
+			if err != nil {
+				return nil, err
+			}
+			p = np
+			col, ok := expr.(*expression.Column)
+			if !ok {
+				col = &expression.Column{UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(), RetType: expr.GetType()}
+				extraExprs = append(extraExprs, expr)
+				extraCols = append(extraCols, col)
+			}
+			sortItems = append(sortItems, property.SortItem{Col: col, Desc: item.Desc})
+		}
+		return sortItems, nil
+	}
+	partitionBy, err := buildItems(partitionItems, windowPartitionByClause)
+	if err != nil {
+		return nil, err
+	}
+	orderBy, err := buildItems(orderItems, windowOrderByClause)
+	if err != nil {
+		return nil, err
+	}
+	if len(extraExprs) > 0 {
+		p = b.buildWindowItemsProjection(p, extraExprs, extraCols)
+	}
+	frame, err := b.buildWindowFrame(p, spec, orderBy)
+	if err != nil {
+		return nil, err
+	}
+
+	window := LogicalWindow{
+		WindowFuncDescs: make([]*aggregation.WindowFuncDesc, 0, len(windowFuncs)),
+		PartitionBy:     partitionBy,
+		OrderBy:         orderBy,
+		Frame:           frame,
+	}.Init(b.ctx)
+	schema := p.Schema().Clone()
+	names := append(p.OutputNames()[:0:0], p.OutputNames()...)
+	b.curClause = fieldList
+	for i, windowFunc := range windowFuncs {
+		desc, err := aggregation.NewWindowFuncDesc(b.ctx, windowFunc.F, argsList[i], windowFunc.Distinct)
+		if err != nil {
+			return nil, err
+		}
+		if spec.Frame != nil && !aggregation.NeedFrame(desc.Name) {
+			b.ctx.GetSessionVars().StmtCtx.AppendWarning(ErrWindowFunctionIgnoresFrame.GenWithStackByArgs(desc.Name, name))
+		}
+		window.WindowFuncDescs = append(window.WindowFuncDescs, desc)
+		col := &expression.Column{UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(), RetType: desc.RetTp}
+		schema.Append(col)
+		names = append(names, &types.FieldName{})
+		resultCols[windowFunc] = col
+	}
+	window.SetChildren(p)
+	window.SetSchema(schema)
+	window.names = names
+	return window, nil
+}
+
+// buildWindowItemsProjection builds the projection which appends the PARTITION BY and ORDER BY items which
+// are not columns to the columns of the child.
+func (b *PlanBuilder) buildWindowItemsProjection(p LogicalPlan, extraExprs []expression.Expression, extraCols []*expression.Column) LogicalPlan {
+	proj := LogicalProjection{Exprs: make([]expression.Expression, 0, p.Schema().Len()+len(extraExprs))}.Init(b.ctx)
+	schema := expression.NewSchema(make([]*expression.Column, 0, p.Schema().Len()+len(extraCols))...)
+	for _, col := range p.Schema().Columns {
+		proj.Exprs = append(proj.Exprs, col)
+		schema.Append(col)
+	}
+	proj.Exprs = append(proj.Exprs, extraExprs...)
+	schema.Append(extraCols...)
+	names := append(p.OutputNames()[:0:0], p.OutputNames()...)
+	for range extraCols {
+		names = append(names, &types.FieldName{})
+	}
+	proj.SetSchema(schema)
+	proj.names = names
+	proj.SetChildren(p)
+	return proj
+}
+
+// buildWindowFrame builds the frame of the window, the frame is nil if it's the whole partition. The
+// default frame is from the beginning of the partition to the last peer of the current row if there is
+// ORDER BY.
+func (b *PlanBuilder) buildWindowFrame(p LogicalPlan, spec *ast.WindowSpec, orderBy []property.SortItem) (*WindowFrame, error) {
+	if spec.Frame == nil {
+		if len(orderBy) == 0 {
+			return nil, nil
+		}
+		return &WindowFrame{
+			Type:  ast.Ranges,
+			Start: &FrameBound{Type: ast.Preceding, UnBounded: true},
+			End:   &FrameBound{Type: ast.CurrentRow},
+		}, nil
+	}
+	if spec.Frame.Type == ast.Groups {
+		return nil, ErrNotSupportedYet.GenWithStackByArgs("GROUPS frame")
+	}
+	name := windowSpecName(spec)
+	start, end := &spec.Frame.Extent.Start, &spec.Frame.Extent.End
+	if start.Type == ast.Following && start.UnBounded {
+		return nil, ErrWindowFrameStartIllegal.GenWithStackByArgs(name)
+	}
+	if end.Type == ast.Preceding && end.UnBounded {
+		return nil, ErrWindowFrameEndIllegal.GenWithStackByArgs(name)
+	}
+	// The frame can't start after the kind of its end, like FOLLOWING to CURRENT ROW.
+	boundOrder := map[ast.BoundType]int{ast.Preceding: 0, ast.CurrentRow: 1, ast.Following: 2}
+	if boundOrder[start.Type] > boundOrder[end.Type] {
+		return nil, ErrWindowFrameIllegal.GenWithStackByArgs(name)
+	}
+	if start.UnBounded && end.UnBounded {
+		return nil, nil
+	}
+	frame := &WindowFrame{Type: spec.Frame.Type}
+	var err error
+	if frame.Start, err = b.buildFrameBound(p, frame.Type, start, orderBy, name); err != nil {
+		return nil, err
+	}
+	if frame.End, err = b.buildFrameBound(p, frame.Type, end, orderBy, name); err != nil {
+		return nil, err
+	}
+	return frame, nil
+}
+
+// buildFrameBound builds the bound of the frame. The bound N PRECEDING or N FOLLOWING of RANGE is computed
+// from the ORDER BY column of the current row by CalcFunc, which is the value of the ORDER BY column of the
+// first or the last row of the frame.
+func (b *PlanBuilder) buildFrameBound(p LogicalPlan, frameType ast.FrameType, bound *ast.FrameBound, orderBy []property.SortItem,
+	name string) (*FrameBound, error) {
+	frameBound := &FrameBound{Type: bound.Type, UnBounded: bound.UnBounded}
+	if bound.Type == ast.CurrentRow || bound.UnBounded {
+		return frameBound, nil
+	}
+	isInterval := bound.Unit != ast.TimeUnitInvalid
+	if frameType == ast.Rows {
+		if isInterval {
+			return nil, ErrWindowRowsIntervalUse.GenWithStackByArgs(name)
+		}
+		num, err := getUintFromNode(bound.Expr)
+		if err != nil {
+			return nil, ErrWindowFrameIllegal.GenWithStackByArgs(name)
+		}
+		frameBound.Num = num
+		return frameBound, nil
+	}
+	if len(orderBy) != 1 {
+		return nil, ErrWindowRangeFrameOrderType.GenWithStackByArgs(name)
+	}
+	col := orderBy[0].Col
+	switch col.RetType.EvalType() {
+	case types.ETInt, types.ETReal, types.ETDecimal:
+		if isInterval {
+			return nil, ErrWindowRangeFrameNumericType.GenWithStackByArgs(name)
+		}
+	case types.ETDatetime:
+		if !isInterval {
+			return nil, ErrWindowRangeFrameTemporalType.GenWithStackByArgs(name)
+		}
+	default:
+		return nil, ErrWindowRangeFrameOrderType.GenWithStackByArgs(name)
+	}
+	expr, _, err := b.rewrite(bound.Expr, p, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	con, ok := expression.FoldConstant(expr).(*expression.Constant)
+	if !ok {
+		return nil, ErrWindowRangeBoundNotConstant.GenWithStackByArgs(name)
+	}
+	if con.Value.IsNull() {
+		return nil, ErrWindowFrameIllegal.GenWithStackByArgs(name)
+	}
+	if !isInterval {
+		zero := types.NewIntDatum(0)
+		cmp, err := con.Value.CompareDatum(b.ctx.GetSessionVars().StmtCtx, &zero)
+		if err != nil {
+			return nil, err
+		}
+		if cmp < 0 {
+			return nil, ErrWindowFrameIllegal.GenWithStackByArgs(name)
+		}
+	}
+	// The rows before the current row have the smaller values if the order is ascending.
+	isSub := (bound.Type == ast.Preceding) != orderBy[0].Desc
+	retTp := types.NewFieldType(mysql.TypeUnspecified)
+	if isInterval {
+		unit, _, err := b.rewrite(&ast.TimeUnitExpr{Unit: bound.Unit}, p, nil, true)
+		if err != nil {
+			return nil, err
+		}
+		funcName := ast.DateAdd
+		if isSub {
+			funcName = ast.DateSub
+		}
+		frameBound.CalcFunc, err = expression.NewFunction(b.ctx, funcName, retTp, col, con, unit)
+		return frameBound, err
+	}
+	var target expression.Expression = col
+	// The unsigned column is computed as a decimal, or the value near zero would overflow.
+	if col.RetType.EvalType() == types.ETInt && mysql.HasUnsignedFlag(col.RetType.Flag) {
+		target = expression.BuildCastFunction(b.ctx, col, types.NewFieldType(mysql.TypeNewDecimal))
+	}
+	funcName := ast.Plus
+	if isSub {
+		funcName = ast.Minus
+	}
+	frameBound.CalcFunc, err = expression.NewFunction(b.ctx, funcName, retTp, target, con)
+	return frameBound, err
+}
+
+func (b *PlanBuilder) buildLimit(src LogicalPlan, limit *ast.Limit) (LogicalPlan, error) {
+	var (
+		offset, count uint64
+		err           error
+	)
+	if count, err = getUintFromNode(limit.Count); err != nil {
+		return nil, err
+	}
+	if limit.Offset != nil {
+		if offset, err = getUintFromNode(limit.Offset); err != nil {
+			return nil, err
+		}
+	}
+	b.optFlag |= flagPushDownTopN
+	li := LogicalLimit{Offset: offset, Count: count}.Init(b.ctx)
+	li.SetChildren(src)
+	return li, nil
+}
+
+// getUintFromNode gets the value of the LIMIT arguments, which must be non-negative integer constants.
+func getUintFromNode(n ast.ExprNode) (uint64, error) {
+	if v, ok := n.(ast.ValueExpr); ok {
+		switch x := v.GetValue().(type) {
+		case uint64:
+			return x, nil
+		case int64:
+			if x >= 0 {
+				return uint64(x), nil
+			}
+		}
+	}
+	return 0, ErrWrongArguments.GenWithStackByArgs("LIMIT")
+}
+
+func (b *PlanBuilder) buildDataSource(tn *ast.TableName, asName *model.CIStr) (LogicalPlan, error) {
+	dbName, tbl, err := b.resolveTable(tn)
+	if err != nil {
+		return nil, err
+	}
+	tableInfo := tbl.Meta()
+	tblName := tableInfo.Name
+	if asName.L != "" {
+		tblName = *asName
+	}
+	colInfos := make([]*model.ColumnInfo, 0, len(tbl.Cols()))
+	for _, col := range tbl.Cols() {
+		colInfos = append(colInfos, col.ColumnInfo)
+	}
+	// The rows of UPDATE and DELETE are written by the handles.
+	if b.inDMLTableRefs && !tableInfo.PKIsHandle {
+		colInfos = append(colInfos, model.NewExtraHandleColInfo())
+	}
+	columns, names := expression.ColumnInfos2ColumnsAndNames(b.ctx, dbName, tblName, colInfos)
+	for _, name := range names {
+		name.OrigTblName = tableInfo.Name
+	}
+	indexHints := b.getIndexHints(tn, dbName, tblName, tableInfo)
+	possiblePaths, err := getPossibleAccessPaths(indexHints, tableInfo)
+	if err != nil {
+		return nil, err
+	}
+	ds := DataSource{
+		DBName:              dbName,
+		TableAsName:         asName,
+		table:               tbl,
+		tableInfo:           tableInfo,
+		Columns:             colInfos,
+		IndexHints:          indexHints,
+		possibleAccessPaths: possiblePaths,
+	}.Init(b.ctx)
+	ds.SetSchema(expression.NewSchema(columns...))
+	ds.names = names
+	if b.inDMLTableRefs {
+		b.addDMLTarget(ds)
+	}
+	return ds, nil
+}
+
+func (b *PlanBuilder) buildJoin(joinNode *ast.Join) (LogicalPlan, error) {
+	if joinNode.Right == nil {
+		return b.buildResultSetNode(joinNode.Left)
+	}
+	b.optFlag |= flagPredicatePushDown | flagBuildKeyInfo | flagEliminateOuterJoin | flagJoinReOrder
+	leftPlan, err := b.buildResultSetNode(joinNode.Left)
+	if err != nil {
+		return nil, err
+	}
+	rightPlan, err := b.buildResultSetNode(joinNode.Right)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTableNames(leftPlan, rightPlan); err != nil {
+		return nil, err
+	}
+	joinPlan := LogicalJoin{}.Init(b.ctx)
+	joinPlan.SetChildren(leftPlan, rightPlan)
+	switch joinNode.Tp {
+	case ast.LeftJoin:
+		joinPlan.JoinType = LeftOuterJoin
+	case ast.RightJoin:
+		joinPlan.JoinType = RightOuterJoin
+	default:
+		joinPlan.JoinType = InnerJoin
+	}
+	joinPlan.StraightJoin = joinNode.StraightJoin || b.inStraightJoin
+	b.setPreferredJoinType(joinPlan)
+	joinPlan.buildSchema()
+	if joinNode.NaturalJoin {
+		return b.coalesceCommonColumns(joinPlan, nil)
+	}
+	if joinNode.Using != nil {
+		filter := make(map[string]bool, len(joinNode.Using))
+		for _, col := range joinNode.Using {
+			filter[col.Name.L] = true
+		}
+		return b.coalesceCommonColumns(joinPlan, filter)
+	}
+	if joinNode.On != nil {
+		b.curClause = onClause
+		onExpr, newPlan, err := b.rewrite(joinNode.On.Expr, joinPlan, nil, true)
+		if err != nil {
+			return nil, err
+		}
+		if newPlan != joinPlan {
+			return nil, ErrNotSupportedYet.GenWithStackByArgs("sub query in ON clause")
+		}
+		joinPlan.AttachOnConds(expression.SplitCNFItems(onExpr))
+	}
+	return joinPlan, nil
+}
+
+// checkTableNames checks that the tables of the two sides of a join have different names.
+func checkTableNames(left, right LogicalPlan) error {
+	leftTables := make(map[string]struct{})
+	for _, name := range left.OutputNames() {
+		if name.TblName.L != "" {
+			leftTables[name.DBName.L+"."+name.TblName.L] = struct{}{}
+		}
+	}
+	for _, name := range right.OutputNames() {
+		if _, ok := leftTables[name.DBName.L+"."+name.TblName.L]; ok && name.TblName.L != "" {
+			return ErrNonUniqTable.GenWithStackByArgs(name.TblName.O)
+		}
+	}
+	return nil
+}
+
+// coalesceCommonColumns builds the equal conditions of the common columns of a NATURAL or USING join, the
+// columns of the filter are used if it's not nil. The common columns go first and come from the left child,
+// or the right child of a right join, the other copies of them are redundant.
+func (b *PlanBuilder) coalesceCommonColumns(p *LogicalJoin, filter map[string]bool) (LogicalPlan, error) {
+	lLen := p.children[0].Schema().Len()
+	lCols, rCols := p.schema.Columns[:lLen], p.schema.Columns[lLen:]
+	lNames, rNames := p.names[:lLen], p.names[lLen:]
+	if p.JoinType == RightOuterJoin {
+		lCols, rCols = rCols, lCols
+		lNames, rNames = rNames, lNames
+	}
+	lCommon, rCommon := make([]bool, len(lCols)), make([]bool, len(rCols))
+	var (
+		commonCols, redundantCols   []*expression.Column
+		commonNames, redundantNames types.NameSlice
+		conds                       []expression.Expression
+	)
+	found := make(map[string]bool)
+	for i, lName := range lNames {
+		if lName.Redundant || found[lName.ColName.L] || (filter != nil && !filter[lName.ColName.L]) {
+			continue
+		}
+		for j, rName := range rNames {
+			if rName.Redundant || rCommon[j] || lName.ColName.L != rName.ColName.L {
+				continue
+			}
+			found[lName.ColName.L] = true
+			lCommon[i], rCommon[j] = true, true
+			cond, err := expression.NewFunction(b.ctx, ast.EQ, types.NewFieldType(mysql.TypeTiny), lCols[i], rCols[j])
+			if err != nil {
+				return nil, err
+			}
+			conds = append(conds, cond)
+			commonCols = append(commonCols, lCols[i])
+			commonNames = append(commonNames, lName)
+			redundantName := *rName
+			redundantName.Redundant = true
+			redundantCols = append(redundantCols, rCols[j])
+			redundantNames = append(redundantNames, &redundantName)
+			break
+		}
+	}
+	for name := range filter {
+		if !found[name] {
+			return nil, ErrUnknownColumn.GenWithStackByArgs(name, "from clause")
+		}
+	}
+	cols, names := commonCols, commonNames
+	for i, col := range lCols {
+		if !lCommon[i] {
+			cols, names = append(cols, col), append(names, lNames[i])
+		}
+	}
+	for i, col := range rCols {
+		if !rCommon[i] {
+			cols, names = append(cols, col), append(names, rNames[i])
+		}
+	}
+	cols, names = append(cols, redundantCols...), append(names, redundantNames...)
+	p.AttachOnConds(conds)
+	exprs := make([]expression.Expression, 0, len(cols))
+	for _, col := range cols {
+		exprs = append(exprs, col)
+	}
+	proj := LogicalProjection{Exprs: exprs}.Init(b.ctx)
+	proj.SetSchema(expression.NewSchema(cols...))
+	proj.names = names
+	proj.SetChildren(p)
+	return proj, nil
+}
+
+func (b *PlanBuilder) buildUnion(union *ast.UnionStmt) (LogicalPlan, error) {
+	selects := union.SelectList.Selects
+	// The selects before the last UNION DISTINCT are distinct all together.
+	lastDistinct := 0
+	for i, sel := range selects {
+		if sel.IsAfterUnionDistinct {
+			lastDistinct = i
+		}
+	}
+	children := make([]LogicalPlan, 0, len(selects))
+	if lastDistinct > 0 {
+		distinctChildren, err := b.buildSelects(selects[:lastDistinct+1])
+		if err != nil {
+			return nil, err
+		}
+		unionDistinct, err := b.buildUnionAll(distinctChildren)
+		if err != nil {
+			return nil, err
+		}
+		p, err := b.buildDistinct(unionDistinct)
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, p)
+		selects = selects[lastDistinct+1:]
+	}
+	allChildren, err := b.buildSelects(selects)
+	if err != nil {
+		return nil, err
+	}
+	p, err := b.buildUnionAll(append(children, allChildren...))
+	if err != nil {
+		return nil, err
+	}
+	fieldLen := p.Schema().Len()
+	if union.OrderBy != nil {
+		p, err = b.buildSort(p, union.OrderBy.Items, nil, nil, nil, fieldLen)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if union.Limit != nil {
+		p, err = b.buildLimit(p, union.Limit)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return b.trimColumns(p, fieldLen), nil
+}
+
+func (b *PlanBuilder) buildSelects(selects []*ast.SelectStmt) ([]LogicalPlan, error) {
+	plans := make([]LogicalPlan, 0, len(selects))
+	for _, sel := range selects {
+		p, err := b.buildSelect(sel)
+		if err != nil {
+			return nil, err
+		}
+		plans = append(plans, p)
+	}
+	return plans, nil
+}
+
+// buildUnionAll unions the children, the columns of the union are typed by the columns of all the children,
+// and the children are cast to the types by the projections.
+func (b *PlanBuilder) buildUnionAll(children []LogicalPlan) (LogicalPlan, error) {
+	if len(children) == 1 {
+		return children[0], nil
+	}
+	first := children[0]
+	for _, child := range children[1:] {
+		if child.Schema().Len() != first.Schema().Len() {
+			return nil, ErrWrongNumberOfColumnsInSelect
+		}
+	}
+	unionCols := make([]*expression.Column, 0, first.Schema().Len())
+	names := make(types.NameSlice, 0, first.Schema().Len())
+	for i, name := range first.OutputNames() {
+		args := make([]expression.Expression, 0, len(children))
+		notNull := true
+		for _, child := range children {
+			col := child.Schema().Columns[i]
+			args = append(args, col)
+			notNull = notNull && mysql.HasNotNullFlag(col.RetType.Flag)
+		}
+		tp := expression.InferType4ControlFuncs(args...)
+		tp.Flag &^= mysql.NotNullFlag
+		if notNull {
+			tp.Flag |= mysql.NotNullFlag
+		}
+		unionCols = append(unionCols, &expression.Column{UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(), RetType: tp})
+		names = append(names, &types.FieldName{OrigTblName: name.OrigTblName, OrigColName: name.OrigColName, ColName: name.ColName})
+	}
+	u := LogicalUnionAll{}.Init(b.ctx)
+	u.SetSchema(expression.NewSchema(unionCols...))
+	u.names = names
+	for _, child := range children {
+		u.children = append(u.children, b.castToUnionType(child, unionCols))
+	}
+	return u, nil
+}
+
+// castToUnionType builds the projection which casts the columns of the child to the types of the union.
+func (b *PlanBuilder) castToUnionType(child LogicalPlan, unionCols []*expression.Column) LogicalPlan {
+	exprs := make([]expression.Expression, 0, len(unionCols))
+	cols := make([]*expression.Column, 0, len(unionCols))
+	for i, col := range child.Schema().Columns {
+		tp := unionCols[i].RetType
+		var expr expression.Expression = col
+		if !col.RetType.Equal(tp) {
+			expr = expression.BuildCastFunction(b.ctx, col, tp)
+		}
+		exprs = append(exprs, expr)
+		cols = append(cols, &expression.Column{UniqueID: b.ctx.GetSessionVars().AllocPlanColumnID(), RetType: tp})
+	}
+	proj := LogicalProjection{Exprs: exprs}.Init(b.ctx)
+	proj.SetSchema(expression.NewSchema(cols...))
+	proj.names = child.OutputNames()
+	proj.SetChildren(child)
+	return proj
+}
@@ -0,0 +1,80 @@
+package planner_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pingcap/parser/ast"
+	"grant-db/domain"
+	"grant-db/planner"
+	"grant-db/util/testkit"
+)
+
+// newPlanTestKit creates the tables t and s in the database test for the plan tests.
+func newPlanTestKit(t *testing.T) (*testkit.TestKit, *domain.Domain) {
+	store, dom := testkit.NewMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create database test")
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int primary key, b int, c int, d int, key idx_b (b), unique key idx_c (c))")
+	tk.MustExec("create table s (a int primary key, b int, c int)")
+	return tk, dom
+}
+
+func parseOne(t *testing.T, tk *testkit.TestKit, sql string) ast.StmtNode {
+	t.Helper()
+	stmts, err := tk.Se.Parse(context.Background(), sql)
+	if err != nil {
+		t.Fatalf("%s: %v", sql, err)
+	}
+	return stmts[0]
+}
+
+func TestLogicalOptimize(t *testing.T) {
+	tk, dom := newPlanTestKit(t)
+	tests := []struct {
+		sql      string
+		expected string
+	}{
+		// The predicates are pushed down to the data sources and the constants are folded.
+		{"select a from t where b > 1 and 1 = 1", "DataScan(t)[gt(test.t.b, 1)]->Projection"},
+		{"select a from t where b = 1 + 2", "DataScan(t)[eq(test.t.b, 3)]->Projection"},
+		{"select t.a from t join s on t.a = s.a where s.b = 1",
+			"Join{DataScan(s)[eq(test.s.b, 1)]->DataScan(t)}([eq(test.s.a, test.t.a)])->Projection->Projection"},
+		// The outer join is an inner join if the predicate rejects the NULL of the inner side.
+		{"select t.a from t left join s on t.b = s.a where s.c = 1",
+			"Join{DataScan(s)[eq(test.s.c, 1)]->DataScan(t)}([eq(test.s.a, test.t.b)])->Projection->Projection"},
+		// The outer join is eliminated if no column of the inner side is used and the join key is unique.
+		{"select t.a from t left join s on t.a = s.a", "DataScan(t)->Projection"},
+		// MAX is the first row in the descending order.
+		{"select max(b) from t", "DataScan(t)[not(isnull(test.t.b))]->TopN([test.t.b desc],0,1)->Aggr(max(test.t.b))->Projection"},
+		// The correlated subqueries are decorrelated into joins.
+		{"select a from t where b in (select b from s where s.a = t.a)",
+			"Join{DataScan(t)->DataScan(s)}([eq(test.t.a, test.s.a) eq(test.t.b, test.s.b)])->Projection"},
+		{"select a from t where exists (select 1 from s where s.c = t.c)",
+			"Join{DataScan(t)->DataScan(s)}([eq(test.t.c, test.s.c)])->Projection"},
+		{"select a from t order by b limit 2", "DataScan(t)->TopN([test.t.b],0,2)->Projection->Projection"},
+		{"select b, count(*) from t group by b", "DataScan(t)->Aggr(count(1),firstrow(test.t.b))->Projection"},
+		{"select a, b from t union all select a, b from s",
+			"UnionAll{DataScan(t)->Projection->Projection->DataScan(s)->Projection->Projection}"},
+	}
+	for _, tt := range tests {
+		p, flag, err := planner.BuildLogicalPlan(tk.Se, parseOne(t, tk, tt.sql), dom.InfoSchema())
+		if err != nil {
+			t.Fatalf("%s: %v", tt.sql, err)
+		}
+		if p, err = planner.LogicalOptimize(flag, p); err != nil {
+			t.Fatalf("%s: %v", tt.sql, err)
+		}
+		if got := planner.ToString(p); got != tt.expected {
+			t.Fatalf("the plan of %s is\n%s\nexpected\n%s", tt.sql, got, tt.expected)
+		}
+	}
+
+	// The names are resolved against the schema.
+	for _, sql := range []string{"select x from t", "select a from t join s on t.a = s.a", "select * from nope"} {
+		if _, _, err := planner.BuildLogicalPlan(tk.Se, parseOne(t, tk, sql), dom.InfoSchema()); err == nil {
+			t.Fatalf("%s is planned", sql)
+		}
+	}
+}
@@ -0,0 +1,487 @@
+package planner
+
+import (
+	"strconv"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/expression"
+	"grant-db/expression/aggregation"
+	"grant-db/planner/property"
+	"grant-db/sessionctx"
+	"grant-db/statistics"
+	"grant-db/table"
+	"grant-db/types"
+)
+
+// The type names of the plans.
+const (
+	TypeDataSource = "DataSource"
+	TypeSel        = "Selection"
+	TypeProj       = "Projection"
+	TypeAgg        = "Aggregation"
+	TypeJoin       = "Join"
+	TypeApply      = "Apply"
+	TypeSort       = "Sort"
+	TypeLimit      = "Limit"
+	TypeTopN       = "TopN"
+	TypeUnion      = "Union"
+	TypeDual       = "TableDual"
+	TypeMaxOneRow  = "MaxOneRow"
+	TypeWindow     = "Window"
+)
+
+// JoinType contains CrossJoin, InnerJoin, LeftOuterJoin, RightOuterJoin, SemiJoin and so on.
+type JoinType int
+
+const (
+	// InnerJoin means inner join.
+	InnerJoin JoinType = iota
+	// LeftOuterJoin means left join.
+	LeftOuterJoin
+	// RightOuterJoin means right join.
+	RightOuterJoin
+	// SemiJoin means if row a in table A matches some rows in B, just output a.
+	SemiJoin
+	// AntiSemiJoin means if row a in table A does not match any row in B, then output a.
+	AntiSemiJoin
+	// LeftOuterSemiJoin means if row a in table A matches some rows in B, output (a, true), otherwise, output (a, false).
+	LeftOuterSemiJoin
+	// AntiLeftOuterSemiJoin means if row a in table A matches some rows in B, output (a, false), otherwise, output (a, true).
+	AntiLeftOuterSemiJoin
+)
+
+// IsOuterJoin returns if this joiner is a outer joiner
+func (tp JoinType) IsOuterJoin() bool {
+	return tp == LeftOuterJoin || tp == RightOuterJoin ||
+		tp == LeftOuterSemiJoin || tp == AntiLeftOuterSemiJoin
+}
+
+// IsSemiJoin returns if the join outputs only the rows of the left child.
+func (tp JoinType) IsSemiJoin() bool {
+	return tp == SemiJoin || tp == AntiSemiJoin || tp == LeftOuterSemiJoin || tp == AntiLeftOuterSemiJoin
+}
+
+func (tp JoinType) String() string {
+	switch tp {
+	case InnerJoin:
+		return "inner join"
+	case LeftOuterJoin:
+		return "left outer join"
+	case RightOuterJoin:
+		return "right outer join"
+	case SemiJoin:
+		return "semi join"
+	case AntiSemiJoin:
+		return "anti semi join"
+	case LeftOuterSemiJoin:
+		return "left outer semi join"
+	case AntiLeftOuterSemiJoin:
+		return "anti left outer semi join"
+	}
+	return "unsupported join type"
+}
+
+// LogicalJoin is the logical join plan.
+type LogicalJoin struct {
+	logicalSchemaProducer
+
+	JoinType JoinType
+	// EqualConditions are the `left column = right column` conditions.
+	EqualConditions []*expression.ScalarFunction
+	// LeftConditions and RightConditions are the conditions only on the left and the right child, which
+	// decide whether the rows match.
+	LeftConditions  expression.CNFExprs
+	RightConditions expression.CNFExprs
+	OtherConditions expression.CNFExprs
+	// NullAware is set for the joins of IN, ANY and ALL sub queries, a NULL result of the conditions of a row
+	// is neither a match nor a mismatch, like `1 NOT IN (NULL)` is NULL.
+	NullAware bool
+	// DefaultValues are the values of the inner columns for the unmatched outer rows, which are NULL if it's
+	// nil, like the 0 of COUNT of a decorrelated scalar sub query.
+	DefaultValues []types.Datum
+
+	// StraightJoin means the children are joined in the written order, the join isn't reordered.
+	StraightJoin bool
+	// preferJoinType is the join algorithms specified by the hints.
+	preferJoinType uint
+	// hintWarned is set once the hints are warned as inapplicable.
+	hintWarned bool
+}
+
+// Init initializes LogicalJoin.
+func (p LogicalJoin) Init(ctx sessionctx.Context) *LogicalJoin {
+	p.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeJoin, &p)
+	return &p
+}
+
+// LogicalApply gets one row from the outer child and evaluates the inner child by the correlated columns of
+// the row, it's turned into a join if the inner child can be decorrelated.
+type LogicalApply struct {
+	LogicalJoin
+
+	CorCols []*expression.CorrelatedColumn
+}
+
+// Init initializes LogicalApply.
+func (la LogicalApply) Init(ctx sessionctx.Context) *LogicalApply {
+	la.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeApply, &la)
+	return &la
+}
+
+// LogicalProjection represents a select fields plan.
+type LogicalProjection struct {
+	logicalSchemaProducer
+
+	Exprs []expression.Expression
+}
+
+// Init initializes LogicalProjection.
+func (p LogicalProjection) Init(ctx sessionctx.Context) *LogicalProjection {
+	p.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeProj, &p)
+	return &p
+}
+
+// LogicalAggregation represents an aggregate plan, the output columns are the results of the aggregate
+// functions in order.
+type LogicalAggregation struct {
+	logicalSchemaProducer
+
+	AggFuncs     []*aggregation.AggFuncDesc
+	GroupByItems []expression.Expression
+
+	// aggHints is the aggregation algorithms specified by the hints.
+	aggHints aggHintInfo
+	// hintWarned is set once the hints are warned as inapplicable.
+	hintWarned bool
+}
+
+// Init initializes LogicalAggregation.
+func (la LogicalAggregation) Init(ctx sessionctx.Context) *LogicalAggregation {
+	la.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeAgg, &la)
+	return &la
+}
+
+// groupByCols returns the group by items which are columns.
+func (la *LogicalAggregation) groupByCols() []*expression.Column {
+	cols := make([]*expression.Column, 0, len(la.GroupByItems))
+	for _, item := range la.GroupByItems {
+		if col, ok := item.(*expression.Column); ok {
+			cols = append(cols, col)
+		}
+	}
+	return cols
+}
+
+// FrameBound is the start or the end of a window frame.
+type FrameBound struct {
+	Type      ast.BoundType
+	UnBounded bool
+	// Num is the N of N PRECEDING and N FOLLOWING of ROWS.
+	Num uint64
+	// CalcFunc is the value of N PRECEDING and N FOLLOWING of RANGE, which is computed from the ORDER BY
+	// column of the current row.
+	CalcFunc expression.Expression
+}
+
+// String implements fmt.Stringer interface.
+func (b *FrameBound) String() string {
+	if b.Type == ast.CurrentRow {
+		return "current row"
+	}
+	dir := "preceding"
+	if b.Type == ast.Following {
+		dir = "following"
+	}
+	switch {
+	case b.UnBounded:
+		return "unbounded " + dir
+	case b.CalcFunc != nil:
+		return b.CalcFunc.String() + " " + dir
+	}
+	return strconv.FormatUint(b.Num, 10) + " " + dir
+}
+
+// WindowFrame is the frame of the rows of the partition which a window function is evaluated on.
+type WindowFrame struct {
+	Type  ast.FrameType
+	Start *FrameBound
+	End   *FrameBound
+}
+
+// LogicalWindow evaluates the window functions of the same window on the rows of the child, the output
+// columns are the ones of the child followed by the results of the functions.
+type LogicalWindow struct {
+	logicalSchemaProducer
+
+	WindowFuncDescs []*aggregation.WindowFuncDesc
+	PartitionBy     []property.SortItem
+	OrderBy         []property.SortItem
+	// Frame is nil if the functions are evaluated on the whole partition.
+	Frame *WindowFrame
+}
+
+// Init initializes LogicalWindow.
+func (p LogicalWindow) Init(ctx sessionctx.Context) *LogicalWindow {
+	p.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeWindow, &p)
+	return &p
+}
+
+// GetWindowResultColumns returns the columns of the results of the window functions.
+func (p *LogicalWindow) GetWindowResultColumns() []*expression.Column {
+	return p.schema.Columns[p.schema.Len()-len(p.WindowFuncDescs):]
+}
+
+// extractUsedCols extracts the columns used by the window functions and the window.
+func (p *LogicalWindow) extractUsedCols() []*expression.Column {
+	var cols []*expression.Column
+	for _, windowFunc := range p.WindowFuncDescs {
+		cols = append(cols, expression.ExtractColumnsFromExpressions(windowFunc.Args)...)
+	}
+	for _, item := range p.PartitionBy {
+		cols = append(cols, item.Col)
+	}
+	for _, item := range p.OrderBy {
+		cols = append(cols, item.Col)
+	}
+	return cols
+}
+
+// LogicalSelection represents a where or having predicate.
+type LogicalSelection struct {
+	baseLogicalPlan
+
+	// Conditions are the conjunctive predicates.
+	Conditions []expression.Expression
+}
+
+// Init initializes LogicalSelection.
+func (p LogicalSelection) Init(ctx sessionctx.Context) *LogicalSelection {
+	p.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeSel, &p)
+	return &p
+}
+
+// ByItems wraps a "by" item.
+type ByItems struct {
+	Expr expression.Expression
+	Desc bool
+}
+
+// String implements fmt.Stringer interface.
+func (by *ByItems) String() string {
+	if by.Desc {
+		return by.Expr.String() + " desc"
+	}
+	return by.Expr.String()
+}
+
+// Clone makes a copy of ByItems.
+func (by *ByItems) Clone() *ByItems {
+	return &ByItems{Expr: by.Expr.Clone(), Desc: by.Desc}
+}
+
+// LogicalSort stands for the order by plan.
+type LogicalSort struct {
+	baseLogicalPlan
+
+	ByItems []*ByItems
+}
+
+// Init initializes LogicalSort.
+func (ls LogicalSort) Init(ctx sessionctx.Context) *LogicalSort {
+	ls.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeSort, &ls)
+	return &ls
+}
+
+// LogicalLimit represents offset and limit plan.
+type LogicalLimit struct {
+	baseLogicalPlan
+
+	Offset uint64
+	Count  uint64
+}
+
+// Init initializes LogicalLimit.
+func (p LogicalLimit) Init(ctx sessionctx.Context) *LogicalLimit {
+	p.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeLimit, &p)
+	return &p
+}
+
+// LogicalTopN represents a top-n plan, which is the sort of ORDER BY with a LIMIT.
+type LogicalTopN struct {
+	baseLogicalPlan
+
+	ByItems []*ByItems
+	Offset  uint64
+	Count   uint64
+}
+
+// Init initializes LogicalTopN.
+func (lt LogicalTopN) Init(ctx sessionctx.Context) *LogicalTopN {
+	lt.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeTopN, &lt)
+	return &lt
+}
+
+// isLimit checks whether the top-n is a limit without order.
+func (lt *LogicalTopN) isLimit() bool {
+	return len(lt.ByItems) == 0
+}
+
+// LogicalUnionAll represents LogicalUnionAll plan, the children output the columns of the same types.
+type LogicalUnionAll struct {
+	logicalSchemaProducer
+}
+
+// Init initializes LogicalUnionAll.
+func (p LogicalUnionAll) Init(ctx sessionctx.Context) *LogicalUnionAll {
+	p.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeUnion, &p)
+	return &p
+}
+
+// LogicalTableDual represents a dual table plan, which outputs RowCount rows with no column or NULLs.
+type LogicalTableDual struct {
+	logicalSchemaProducer
+
+	RowCount int
+}
+
+// Init initializes LogicalTableDual.
+func (p LogicalTableDual) Init(ctx sessionctx.Context) *LogicalTableDual {
+	p.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeDual, &p)
+	return &p
+}
+
+// LogicalMaxOneRow checks if a query returns no more than one row, it's the parent of a scalar sub query.
+type LogicalMaxOneRow struct {
+	baseLogicalPlan
+}
+
+// Init initializes LogicalMaxOneRow.
+func (p LogicalMaxOneRow) Init(ctx sessionctx.Context) *LogicalMaxOneRow {
+	p.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeMaxOneRow, &p)
+	return &p
+}
+
+// DataSource represents a tableScan without condition push down.
+type DataSource struct {
+	logicalSchemaProducer
+
+	DBName      model.CIStr
+	TableAsName *model.CIStr
+	table       table.Table
+	tableInfo   *model.TableInfo
+	// Columns are the column infos of the schema columns in order.
+	Columns []*model.ColumnInfo
+	// IndexHints are the USE, FORCE and IGNORE INDEX hints of the table.
+	IndexHints []*ast.IndexHint
+
+	// pushedDownConds are the conditions that will be pushed down to coprocessor.
+	pushedDownConds []expression.Expression
+
+	// statisticTable is the statistics of the table, which are pseudo if the table isn't analyzed.
+	statisticTable *statistics.Table
+	// tableStats is the statistic info of the whole table.
+	tableStats *property.StatsInfo
+	// possibleAccessPaths are the table path and the index paths the rows can be read by.
+	possibleAccessPaths []*accessPath
+}
+
+// Init initializes DataSource.
+func (ds DataSource) Init(ctx sessionctx.Context) *DataSource {
+	ds.baseLogicalPlan = newBaseLogicalPlan(ctx, TypeDataSource, &ds)
+	return &ds
+}
+
+// TableInfo returns the info of the table.
+func (ds *DataSource) TableInfo() *model.TableInfo {
+	return ds.tableInfo
+}
+
+// Table returns the table.
+func (ds *DataSource) Table() table.Table {
+	return ds.table
+}
+
+// PushedDownConds returns the conditions which are pushed down to the table.
+func (ds *DataSource) PushedDownConds() []expression.Expression {
+	return ds.pushedDownConds
+}
+
+// getPKIsHandleCol returns the column of the integer primary key, which is the handle of the rows.
+func (ds *DataSource) getPKIsHandleCol() *expression.Column {
+	if !ds.tableInfo.PKIsHandle {
+		return nil
+	}
+	for i, col := range ds.Columns {
+		if mysql.HasPriKeyFlag(col.Flag) {
+			return ds.schema.Columns[i]
+		}
+	}
+	return nil
+}
+
+// extractCorColumnsBySchema extracts the correlated columns of the plan tree which refer to the columns of the schema.
+func extractCorColumnsBySchema(p LogicalPlan, schema *expression.Schema) []*expression.CorrelatedColumn {
+	var corCols []*expression.CorrelatedColumn
+	for _, corCol := range extractCorrelatedCols(p) {
+		if schema.Contains(&corCol.Column) {
+			corCols = append(corCols, corCol)
+		}
+	}
+	return corCols
+}
+
+// extractCorrelatedCols extracts all the correlated columns of the plan tree.
+func extractCorrelatedCols(p LogicalPlan) []*expression.CorrelatedColumn {
+	var exprs []expression.Expression
+	switch x := p.(type) {
+	case *LogicalSelection:
+		exprs = x.Conditions
+	case *LogicalProjection:
+		exprs = x.Exprs
+	case *LogicalAggregation:
+		exprs = append(exprs, x.GroupByItems...)
+		for _, aggFunc := range x.AggFuncs {
+			exprs = append(exprs, aggFunc.Args...)
+			for _, item := range aggFunc.OrderByItems {
+				exprs = append(exprs, item.Expr)
+			}
+		}
+	case *LogicalSort:
+		for _, item := range x.ByItems {
+			exprs = append(exprs, item.Expr)
+		}
+	case *LogicalWindow:
+		for _, windowFunc := range x.WindowFuncDescs {
+			exprs = append(exprs, windowFunc.Args...)
+		}
+	case *LogicalTopN:
+		for _, item := range x.ByItems {
+			exprs = append(exprs, item.Expr)
+		}
+	case *DataSource:
+		exprs = x.pushedDownConds
+	case *LogicalJoin:
+		exprs = x.allConditions()
+	case *LogicalApply:
+		exprs = x.allConditions()
+	}
+	var corCols []*expression.CorrelatedColumn
+	for _, expr := range exprs {
+		corCols = append(corCols, expression.ExtractCorColumns(expr)...)
+	}
+	for _, child := range p.Children() {
+		corCols = append(corCols, extractCorrelatedCols(child)...)
+	}
+	return corCols
+}
+
+// allConditions returns all the conditions of the join.
+func (p *LogicalJoin) allConditions() []expression.Expression {
+	conds := make([]expression.Expression, 0, len(p.EqualConditions)+len(p.LeftConditions)+len(p.RightConditions)+len(p.OtherConditions))
+	conds = append(conds, expression.ScalarFuncs2Exprs(p.EqualConditions)...)
+	conds = append(conds, p.LeftConditions...)
+	conds = append(conds, p.RightConditions...)
+	return append(conds, p.OtherConditions...)
+}
@@ -0,0 +1,111 @@
+package planner
+
+import (
+	"math"
+
+	"github.com/pingcap/parser/ast"
+	"grant-db/infoschema"
+	"grant-db/planner/property"
+	"grant-db/sessionctx"
+)
+
+// The flags mark the logical optimization rules, which are applied in the order of optRuleList.
+const (
+	flagPrunColumns uint64 = 1 << iota
+	flagBuildKeyInfo
+	flagDecorrelate
+	flagMaxMinEliminate
+	flagPredicatePushDown
+	flagConstantPropagation
+	flagEliminateOuterJoin
+	flagPushDownTopN
+	flagJoinReOrder
+	flagPrunColumnsAgain
+)
+
+var optRuleList = []logicalOptRule{
+	&columnPruner{},
+	&buildKeySolver{},
+	&decorrelateSolver{},
+	&maxMinEliminator{},
+	&ppdSolver{},
+	&constantPropagationSolver{},
+	&outerJoinEliminator{},
+	&pushDownTopNOptimizer{},
+	&joinReOrderSolver{},
+	&columnPruner{},
+}
+
+// logicalOptRule means a logical optimizing rule, which contains decorrelate, ppd, column pruning, etc.
+type logicalOptRule interface {
+	optimize(LogicalPlan) (LogicalPlan, error)
+	name() string
+}
+
+// BuildLogicalPlan builds the logical plan of the statement, the flags of the rules it needs are returned.
+func BuildLogicalPlan(ctx sessionctx.Context, node ast.Node, is infoschema.InfoSchema) (LogicalPlan, uint64, error) {
+	builder := NewPlanBuilder(ctx, is)
+	p, err := builder.Build(node)
+	if err != nil {
+		return nil, 0, err
+	}
+	return p, builder.optFlag, nil
+}
+
+// Optimize builds the logical plan of the statement, optimizes it by the rules and chooses the physical plan
+// of the lowest cost.
+func Optimize(ctx sessionctx.Context, node ast.Node, is infoschema.InfoSchema) (PhysicalPlan, error) {
+	switch node.(type) {
+	case *ast.InsertStmt, *ast.UpdateStmt, *ast.DeleteStmt:
+		return NewPlanBuilder(ctx, is).buildDML(node)
+	}
+	p, flag, err := BuildLogicalPlan(ctx, node, is)
+	if err != nil {
+		return nil, err
+	}
+	logic, err := logicalOptimize(flag, p)
+	if err != nil {
+		return nil, err
+	}
+	return physicalOptimize(logic)
+}
+
+func logicalOptimize(flag uint64, logic LogicalPlan) (LogicalPlan, error) {
+	var err error
+	if flag&flagDecorrelate > 0 {
+		// The columns which are not used any more after the decorrelation are pruned again.
+		flag |= flagPrunColumnsAgain
+	}
+	for i, rule := range optRuleList {
+		// The order of flags is same as the order of optRule in the list.
+		// We use a bitmask to record which opt rules should be used. If the i-th bit is 1, it means we should
+		// apply i-th optimizing rule.
+		if flag&(1<<uint(i)) == 0 {
+			continue
+		}
+		logic, err = rule.optimize(logic)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return logic, nil
+}
+
+func physicalOptimize(logic LogicalPlan) (PhysicalPlan, error) {
+	if _, err := logic.recursiveDeriveStats(); err != nil {
+		return nil, err
+	}
+	prop := &property.PhysicalProperty{ExpectedCnt: math.MaxFloat64}
+	t, err := logic.findBestTask(prop)
+	if err != nil {
+		return nil, err
+	}
+	if t.invalid() {
+		return nil, ErrInternal.GenWithStackByArgs("Can't find a proper physical plan for this query")
+	}
+	p := injectExtraProjection(t.plan())
+	if err = p.ResolveIndices(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
@@ -0,0 +1,46 @@
+package planner_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/parser/mysql"
+	"grant-db/planner"
+)
+
+func TestPhysicalPlan(t *testing.T) {
+	tk, dom := newPlanTestKit(t)
+	tests := []struct {
+		sql      string
+		expected string
+	}{
+		{"select a from t where a = 1", "PointGet(t)->Projection"},
+		{"select a from t where a in (1, 2)", "BatchPointGet(t)->Projection"},
+		{"select a from t where c = 1", "PointGet(t)->Projection"},
+		// The index covers the handle, the other columns are looked up in the table.
+		{"select a from t where b = 1", "IndexScan(t.idx_b)->Projection"},
+		{"select d from t where b = 1", "IndexLookUp(IndexScan(t.idx_b), TableScan(t))->Projection"},
+		{"select d from t use index (idx_b) where b > 1", "IndexLookUp(IndexScan(t.idx_b), TableScan(t))->Projection"},
+		{"select d from t ignore index (idx_b) where b = 1", "TableScan(t)->Sel([eq(test.t.b, 1)])->Projection"},
+		// The order of the index is kept by the limit and the stream aggregation.
+		{"select a from t order by b limit 2", "IndexScan(t.idx_b)->Limit->Projection->Projection"},
+		{"select b, count(*) from t group by b", "IndexScan(t.idx_b)->StreamAgg->Projection"},
+		{"select max(b) from t", "IndexScan(t.idx_b)->Limit->StreamAgg->Projection"},
+		// The join algorithms are chosen by the hints.
+		{"select /*+ MERGE_JOIN(t, s) */ t.a from t join s on t.a = s.a",
+			"MergeJoin{TableScan(t)->TableScan(s)}([test.t.a],[test.s.a])->Projection"},
+		{"select /*+ INL_JOIN(s) */ t.a from t join s on t.b = s.a",
+			"IndexJoin{IndexScan(t.idx_b)->TableScan(s)}([test.t.b],[test.s.a])->Projection"},
+		{"select /*+ HASH_JOIN(t, s) */ t.a from t join s on t.a = s.a",
+			"HashJoin{IndexScan(t.idx_b)->TableScan(s)}([test.t.a],[test.s.a])->Projection"},
+	}
+	for _, tt := range tests {
+		p, err := planner.Optimize(tk.Se, parseOne(t, tk, tt.sql), dom.InfoSchema())
+		if err != nil {
+			t.Fatalf("%s: %v", tt.sql, err)
+		}
+		if got := planner.ToString(p); got != tt.expected {
+			t.Fatalf("the plan of %s is\n%s\nexpected\n%s", tt.sql, got, tt.expected)
+		}
+	}
+	tk.MustGetErrCode("select a from t use index (nope)", mysql.ErrKeyDoesNotExist)
+}
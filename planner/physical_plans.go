@@ -0,0 +1,423 @@
+package planner
+
+import (
+	"github.com/pingcap/parser/model"
+	"grant-db/expression"
+	"grant-db/expression/aggregation"
+	"grant-db/planner/property"
+	"grant-db/sessionctx"
+	"grant-db/table"
+	"grant-db/types"
+	"grant-db/util/ranger"
+)
+
+// The type names of the physical plans.
+const (
+	TypeTableScan     = "TableScan"
+	TypeIdxScan       = "IndexScan"
+	TypeIndexLookUp   = "IndexLookUp"
+	TypePointGet      = "Point_Get"
+	TypeBatchPointGet = "Batch_Point_Get"
+	TypeHashJoin      = "HashJoin"
+	TypeMergeJoin     = "MergeJoin"
+	TypeIndexJoin     = "IndexJoin"
+	TypeHashAgg       = "HashAgg"
+	TypeStreamAgg     = "StreamAgg"
+)
+
+// physicalTableSource is the table a scan reads.
+type physicalTableSource struct {
+	physicalSchemaProducer
+
+	DBName      model.CIStr
+	TableAsName *model.CIStr
+	Table       table.Table
+	TableInfo   *model.TableInfo
+	// Columns are the column infos of the schema columns in order.
+	Columns []*model.ColumnInfo
+	// StatsPseudo is true if the row counts are estimated by the pseudo statistics.
+	StatsPseudo bool
+}
+
+// PhysicalTableScan reads the rows of the table in the ranges of the handle.
+type PhysicalTableScan struct {
+	physicalTableSource
+
+	Ranges []*ranger.Range
+	// AccessCondition are the conditions the ranges are built from.
+	AccessCondition []expression.Expression
+	// HandleCol is the column of the integer primary key, it's nil if the handle isn't a column.
+	HandleCol *expression.Column
+	// KeepOrder is true if the rows are read in the order of the handle.
+	KeepOrder bool
+	Desc      bool
+	// rangeDecidedBy is the handle column if the scan is the inner child of an index join, whose ranges are
+	// decided by the outer rows.
+	rangeDecidedBy []*expression.Column
+}
+
+// Init initializes PhysicalTableScan.
+func (p PhysicalTableScan) Init(ctx sessionctx.Context, stats *property.StatsInfo) *PhysicalTableScan {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeTableScan, &p)
+	p.stats = stats
+	return &p
+}
+
+// PhysicalIndexScan reads the rows of the index in the ranges, the schema is the columns read from the index.
+type PhysicalIndexScan struct {
+	physicalTableSource
+
+	Index *model.IndexInfo
+	// IdxCols are the columns of the index in the schema of the data source, and IdxColLens are the prefix
+	// lengths of them.
+	IdxCols    []*expression.Column
+	IdxColLens []int
+	Ranges     []*ranger.Range
+	// AccessCondition are the conditions the ranges are built from.
+	AccessCondition []expression.Expression
+	// dataSourceSchema is the schema of the data source, the index lookup reads the table rows of it.
+	dataSourceSchema *expression.Schema
+	// KeepOrder is true if the rows are read in the order of the index.
+	KeepOrder bool
+	Desc      bool
+	// rangeDecidedBy are the join keys if the scan is the inner child of an index join, whose ranges are
+	// decided by the outer rows.
+	rangeDecidedBy []*expression.Column
+}
+
+// Init initializes PhysicalIndexScan.
+func (p PhysicalIndexScan) Init(ctx sessionctx.Context, stats *property.StatsInfo) *PhysicalIndexScan {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeIdxScan, &p)
+	p.stats = stats
+	return &p
+}
+
+// PhysicalIndexLookUp reads the handles by the index plan, and then the rows of the handles by the table plan.
+// The plans are the scans with the selections of the index filters and the table filters on them.
+type PhysicalIndexLookUp struct {
+	physicalSchemaProducer
+
+	IndexPlan PhysicalPlan
+	TablePlan PhysicalPlan
+	// KeepOrder is true if the rows are returned in the order of the index.
+	KeepOrder bool
+}
+
+// Init initializes PhysicalIndexLookUp.
+func (p PhysicalIndexLookUp) Init(ctx sessionctx.Context, stats *property.StatsInfo) *PhysicalIndexLookUp {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeIndexLookUp, &p)
+	p.stats = stats
+	return &p
+}
+
+// PointGetPlan reads one row by the handle, or the values of all the columns of a unique index.
+type PointGetPlan struct {
+	physicalTableSource
+
+	// IndexInfo is nil if the row is read by the handle.
+	IndexInfo   *model.IndexInfo
+	Handle      int64
+	IndexValues []types.Datum
+	// AccessConditions are the conditions the handle or the index values are built from.
+	AccessConditions []expression.Expression
+}
+
+// Init initializes PointGetPlan.
+func (p PointGetPlan) Init(ctx sessionctx.Context, stats *property.StatsInfo) *PointGetPlan {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypePointGet, &p)
+	p.stats = stats
+	return &p
+}
+
+// BatchPointGetPlan reads the rows by the handles, or the values of all the columns of a unique index.
+type BatchPointGetPlan struct {
+	physicalTableSource
+
+	// IndexInfo is nil if the rows are read by the handles.
+	IndexInfo   *model.IndexInfo
+	Handles     []int64
+	IndexValues [][]types.Datum
+	// AccessConditions are the conditions the handles or the index values are built from.
+	AccessConditions []expression.Expression
+}
+
+// Init initializes BatchPointGetPlan.
+func (p BatchPointGetPlan) Init(ctx sessionctx.Context, stats *property.StatsInfo) *BatchPointGetPlan {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeBatchPointGet, &p)
+	p.stats = stats
+	return &p
+}
+
+// PhysicalTableDual is the physical operator of dual.
+type PhysicalTableDual struct {
+	physicalSchemaProducer
+
+	RowCount int
+}
+
+// Init initializes PhysicalTableDual.
+func (p PhysicalTableDual) Init(ctx sessionctx.Context, stats *property.StatsInfo) *PhysicalTableDual {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeDual, &p)
+	p.stats = stats
+	return &p
+}
+
+// PhysicalSelection represents a filter.
+type PhysicalSelection struct {
+	basePhysicalPlan
+
+	Conditions []expression.Expression
+}
+
+// Init initializes PhysicalSelection.
+func (p PhysicalSelection) Init(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalSelection {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeSel, &p)
+	p.childrenReqProps = props
+	p.stats = stats
+	return &p
+}
+
+// PhysicalProjection is the physical operator of projection.
+type PhysicalProjection struct {
+	physicalSchemaProducer
+
+	Exprs []expression.Expression
+}
+
+// Init initializes PhysicalProjection.
+func (p PhysicalProjection) Init(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalProjection {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeProj, &p)
+	p.childrenReqProps = props
+	p.stats = stats
+	return &p
+}
+
+// PhysicalSort is the physical operator of sort, which implements a memory sort.
+type PhysicalSort struct {
+	basePhysicalPlan
+
+	ByItems []*ByItems
+}
+
+// Init initializes PhysicalSort.
+func (p PhysicalSort) Init(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalSort {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeSort, &p)
+	p.childrenReqProps = props
+	p.stats = stats
+	return &p
+}
+
+// NominalSort asks the child to return the rows in the order, it's removed from the physical plan.
+type NominalSort struct {
+	basePhysicalPlan
+}
+
+// Init initializes NominalSort.
+func (p NominalSort) Init(ctx sessionctx.Context, props ...*property.PhysicalProperty) *NominalSort {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeSort, &p)
+	p.childrenReqProps = props
+	return &p
+}
+
+// PhysicalTopN is the physical operator of top-n.
+type PhysicalTopN struct {
+	basePhysicalPlan
+
+	ByItems []*ByItems
+	Offset  uint64
+	Count   uint64
+}
+
+// Init initializes PhysicalTopN.
+func (p PhysicalTopN) Init(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalTopN {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeTopN, &p)
+	p.childrenReqProps = props
+	p.stats = stats
+	return &p
+}
+
+// PhysicalLimit is the physical operator of limit.
+type PhysicalLimit struct {
+	basePhysicalPlan
+
+	Offset uint64
+	Count  uint64
+}
+
+// Init initializes PhysicalLimit.
+func (p PhysicalLimit) Init(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalLimit {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeLimit, &p)
+	p.childrenReqProps = props
+	p.stats = stats
+	return &p
+}
+
+// PhysicalUnionAll is the physical operator of UnionAll.
+type PhysicalUnionAll struct {
+	physicalSchemaProducer
+}
+
+// Init initializes PhysicalUnionAll.
+func (p PhysicalUnionAll) Init(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalUnionAll {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeUnion, &p)
+	p.childrenReqProps = props
+	p.stats = stats
+	return &p
+}
+
+// PhysicalMaxOneRow is the physical operator of maxOneRow.
+type PhysicalMaxOneRow struct {
+	basePhysicalPlan
+}
+
+// Init initializes PhysicalMaxOneRow.
+func (p PhysicalMaxOneRow) Init(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalMaxOneRow {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeMaxOneRow, &p)
+	p.childrenReqProps = props
+	p.stats = stats
+	return &p
+}
+
+// basePhysicalJoin is the base of the physical joins, the conditions have the same meanings as the ones of
+// LogicalJoin.
+type basePhysicalJoin struct {
+	physicalSchemaProducer
+
+	JoinType        JoinType
+	LeftConditions  expression.CNFExprs
+	RightConditions expression.CNFExprs
+	OtherConditions expression.CNFExprs
+	// InnerChildIdx is the index of the inner child, whose rows are matched by the rows of the outer child.
+	InnerChildIdx int
+	LeftJoinKeys  []*expression.Column
+	RightJoinKeys []*expression.Column
+	NullAware     bool
+	DefaultValues []types.Datum
+}
+
+// PhysicalHashJoin builds the hash table by the rows of the inner child, and probes it by the rows of the
+// outer child.
+type PhysicalHashJoin struct {
+	basePhysicalJoin
+
+	EqualConditions []*expression.ScalarFunction
+}
+
+// Init initializes PhysicalHashJoin.
+func (p PhysicalHashJoin) Init(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalHashJoin {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeHashJoin, &p)
+	p.childrenReqProps = props
+	p.stats = stats
+	return &p
+}
+
+// PhysicalMergeJoin merges the rows of the children which are ordered by the join keys.
+type PhysicalMergeJoin struct {
+	basePhysicalJoin
+
+	// Desc is true if the rows are ordered by the join keys in the descending order.
+	Desc bool
+}
+
+// Init initializes PhysicalMergeJoin.
+func (p PhysicalMergeJoin) Init(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalMergeJoin {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeMergeJoin, &p)
+	p.childrenReqProps = props
+	p.stats = stats
+	return &p
+}
+
+// PhysicalIndexJoin reads the rows of the inner table by the join keys of the outer rows, the inner child is
+// a scan of the inner table whose ranges are built from the outer rows.
+type PhysicalIndexJoin struct {
+	basePhysicalJoin
+
+	// OuterJoinKeys and InnerJoinKeys are the join keys of the outer child and the inner child in pairs.
+	OuterJoinKeys []*expression.Column
+	InnerJoinKeys []*expression.Column
+	// KeyOff2IdxOff maps the offsets of the join keys to the offsets of the index columns, which is -1 if
+	// the key isn't used to build the ranges. The handle is the only column if the inner child is read by
+	// the handles.
+	KeyOff2IdxOff []int
+	// IdxColLens are the prefix lengths of the index columns.
+	IdxColLens []int
+
+	// innerTask is the task of the inner child, whose cost is the cost of a lookup.
+	innerTask task
+}
+
+// Init initializes PhysicalIndexJoin.
+func (p PhysicalIndexJoin) Init(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalIndexJoin {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeIndexJoin, &p)
+	p.childrenReqProps = props
+	p.stats = stats
+	return &p
+}
+
+// PhysicalApply evaluates the inner child by every row of the outer child.
+type PhysicalApply struct {
+	PhysicalHashJoin
+
+	CorCols []*expression.CorrelatedColumn
+}
+
+// Init initializes PhysicalApply.
+func (p PhysicalApply) Init(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalApply {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeApply, &p)
+	p.childrenReqProps = props
+	p.stats = stats
+	return &p
+}
+
+// basePhysicalAgg is the base of the physical aggregations.
+type basePhysicalAgg struct {
+	physicalSchemaProducer
+
+	AggFuncs     []*aggregation.AggFuncDesc
+	GroupByItems []expression.Expression
+}
+
+// PhysicalHashAgg groups the rows by a hash table.
+type PhysicalHashAgg struct {
+	basePhysicalAgg
+}
+
+// Init initializes PhysicalHashAgg.
+func (p PhysicalHashAgg) Init(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalHashAgg {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeHashAgg, &p)
+	p.childrenReqProps = props
+	p.stats = stats
+	return &p
+}
+
+// PhysicalStreamAgg groups the rows which are ordered by the group by items.
+type PhysicalStreamAgg struct {
+	basePhysicalAgg
+}
+
+// Init initializes PhysicalStreamAgg.
+func (p PhysicalStreamAgg) Init(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalStreamAgg {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeStreamAgg, &p)
+	p.childrenReqProps = props
+	p.stats = stats
+	return &p
+}
+
+// PhysicalWindow evaluates the window functions on the rows of the child, which are ordered by the
+// PARTITION BY columns and then the ORDER BY columns.
+type PhysicalWindow struct {
+	physicalSchemaProducer
+
+	WindowFuncDescs []*aggregation.WindowFuncDesc
+	PartitionBy     []property.SortItem
+	OrderBy         []property.SortItem
+	Frame           *WindowFrame
+}
+
+// Init initializes PhysicalWindow.
+func (p PhysicalWindow) Init(ctx sessionctx.Context, stats *property.StatsInfo, props ...*property.PhysicalProperty) *PhysicalWindow {
+	p.basePhysicalPlan = newBasePhysicalPlan(ctx, TypeWindow, &p)
+	p.childrenReqProps = props
+	p.stats = stats
+	return &p
+}
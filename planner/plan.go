@@ -0,0 +1,377 @@
+package planner
+
+import (
+	"fmt"
+
+	"grant-db/expression"
+	"grant-db/planner/property"
+	"grant-db/sessionctx"
+	"grant-db/types"
+)
+
+// Plan is the description of an execution flow, it's created from the AST by the PlanBuilder.
+type Plan interface {
+	// Schema returns the schema of the output rows.
+	Schema() *expression.Schema
+	// OutputNames returns the names of the output columns, which are used to resolve the column names.
+	OutputNames() types.NameSlice
+	// SetOutputNames sets the names of the output columns.
+	SetOutputNames(names types.NameSlice)
+	// ID returns the id of the plan, which is unique in a statement.
+	ID() int
+	// TP returns the type name of the plan.
+	TP() string
+	// ExplainID returns the name of the plan shown by EXPLAIN, like "Projection_3".
+	ExplainID() string
+	// SCtx returns the session context of the plan.
+	SCtx() sessionctx.Context
+}
+
+// LogicalPlan is a tree of logical operators, which is rewritten by the logical optimization rules.
+type LogicalPlan interface {
+	Plan
+
+	// PredicatePushDown pushes down the predicates of the parent as deep as possible, the predicates which
+	// can't be pushed down are returned and the new plan replaces this one.
+	PredicatePushDown(predicates []expression.Expression) ([]expression.Expression, LogicalPlan)
+
+	// PruneColumns prunes the output columns which are not used by the parent.
+	PruneColumns(parentUsedCols []*expression.Column) error
+
+	// buildKeyInfo derives the unique keys of the output and whether there is at most one row from the children.
+	buildKeyInfo()
+
+	// MaxOneRow checks whether the plan outputs at most one row.
+	MaxOneRow() bool
+
+	// Children returns the children of the plan.
+	Children() []LogicalPlan
+
+	// SetChildren sets the children of the plan.
+	SetChildren(children ...LogicalPlan)
+
+	// SetChild sets the i-th child of the plan.
+	SetChild(i int, child LogicalPlan)
+
+	// pushDownTopN pushes down the top-n or the limit of the parent, which is nil if there is none, the new
+	// plan replaces this one.
+	pushDownTopN(topN *LogicalTopN) LogicalPlan
+
+	// DeriveStats derives the statistic info of the plan from the statistic info of the children.
+	DeriveStats(childStats []*property.StatsInfo) (*property.StatsInfo, error)
+
+	// recursiveDeriveStats derives the statistic info of the plan tree bottom up.
+	recursiveDeriveStats() (*property.StatsInfo, error)
+
+	// findBestTask converts the logical plan to the physical plan of the lowest cost which satisfies the
+	// required property.
+	findBestTask(prop *property.PhysicalProperty) (task, error)
+
+	// exhaustPhysicalPlans generates all the possible physical plans of the plan which satisfy the required
+	// property, the properties the children are required are kept in the physical plans.
+	exhaustPhysicalPlans(prop *property.PhysicalProperty) []PhysicalPlan
+
+	statsInfo() *property.StatsInfo
+}
+
+// PhysicalPlan is a tree of physical operators, which is chosen from the logical plan by the cost.
+type PhysicalPlan interface {
+	Plan
+
+	// attach2Task makes the task of the plan from the tasks of the children, the cost is accumulated.
+	attach2Task(...task) task
+
+	// GetChildReqProps gets the required property by child index.
+	GetChildReqProps(idx int) *property.PhysicalProperty
+
+	// StatsCount returns the estimated count of the output rows.
+	StatsCount() float64
+
+	// ExplainInfo returns the operator info of the plan shown by EXPLAIN.
+	ExplainInfo() string
+
+	// ResolveIndices resolves the offsets of the columns the expressions of the plan read in the rows of the
+	// children, which are used by the executors.
+	ResolveIndices() error
+
+	// Children returns the children of the plan.
+	Children() []PhysicalPlan
+
+	// SetChildren sets the children of the plan.
+	SetChildren(children ...PhysicalPlan)
+
+	// SetChild sets the i-th child of the plan.
+	SetChild(i int, child PhysicalPlan)
+
+	statsInfo() *property.StatsInfo
+}
+
+type basePlan struct {
+	tp  string
+	id  int
+	ctx sessionctx.Context
+}
+
+func newBasePlan(ctx sessionctx.Context, tp string) basePlan {
+	return basePlan{tp: tp, id: ctx.GetSessionVars().AllocPlanID(), ctx: ctx}
+}
+
+// ID implements Plan ID interface.
+func (p *basePlan) ID() int {
+	return p.id
+}
+
+// TP implements Plan interface.
+func (p *basePlan) TP() string {
+	return p.tp
+}
+
+// ExplainID implements Plan interface.
+func (p *basePlan) ExplainID() string {
+	return fmt.Sprintf("%s_%d", p.tp, p.id)
+}
+
+// SCtx implements Plan interface.
+func (p *basePlan) SCtx() sessionctx.Context {
+	return p.ctx
+}
+
+// baseLogicalPlan is the base of the logical plans, the plans which don't produce their own schema use
+// the schema of the first child.
+type baseLogicalPlan struct {
+	basePlan
+
+	self      LogicalPlan
+	children  []LogicalPlan
+	maxOneRow bool
+	stats     *property.StatsInfo
+	// taskMap memorizes the best tasks of the required properties.
+	taskMap map[string]task
+}
+
+func newBaseLogicalPlan(ctx sessionctx.Context, tp string, self LogicalPlan) baseLogicalPlan {
+	return baseLogicalPlan{basePlan: newBasePlan(ctx, tp), self: self, taskMap: make(map[string]task)}
+}
+
+func (p *baseLogicalPlan) statsInfo() *property.StatsInfo {
+	return p.stats
+}
+
+func (p *baseLogicalPlan) getTask(prop *property.PhysicalProperty) task {
+	return p.taskMap[prop.HashCode()]
+}
+
+func (p *baseLogicalPlan) storeTask(prop *property.PhysicalProperty, t task) {
+	p.taskMap[prop.HashCode()] = t
+}
+
+// Schema implements Plan interface.
+func (p *baseLogicalPlan) Schema() *expression.Schema {
+	return p.children[0].Schema()
+}
+
+// OutputNames implements Plan interface.
+func (p *baseLogicalPlan) OutputNames() types.NameSlice {
+	return p.children[0].OutputNames()
+}
+
+// SetOutputNames implements Plan interface.
+func (p *baseLogicalPlan) SetOutputNames(names types.NameSlice) {
+	p.children[0].SetOutputNames(names)
+}
+
+// Children implements LogicalPlan interface.
+func (p *baseLogicalPlan) Children() []LogicalPlan {
+	return p.children
+}
+
+// SetChildren implements LogicalPlan interface.
+func (p *baseLogicalPlan) SetChildren(children ...LogicalPlan) {
+	p.children = children
+}
+
+// SetChild implements LogicalPlan interface.
+func (p *baseLogicalPlan) SetChild(i int, child LogicalPlan) {
+	p.children[i] = child
+}
+
+// MaxOneRow implements LogicalPlan interface.
+func (p *baseLogicalPlan) MaxOneRow() bool {
+	return p.maxOneRow
+}
+
+// PredicatePushDown implements LogicalPlan interface, the predicates are pushed down to the only child.
+func (p *baseLogicalPlan) PredicatePushDown(predicates []expression.Expression) ([]expression.Expression, LogicalPlan) {
+	if len(p.children) == 0 {
+		return predicates, p.self
+	}
+	rest, newChild := p.children[0].PredicatePushDown(predicates)
+	addSelection(p.self, newChild, rest, 0)
+	return nil, p.self
+}
+
+// PruneColumns implements LogicalPlan interface, the child is pruned by the columns the parent uses.
+func (p *baseLogicalPlan) PruneColumns(parentUsedCols []*expression.Column) error {
+	if len(p.children) == 0 {
+		return nil
+	}
+	return p.children[0].PruneColumns(parentUsedCols)
+}
+
+// buildKeyInfo implements LogicalPlan interface, the keys of the only child are kept.
+func (p *baseLogicalPlan) buildKeyInfo() {
+	if len(p.children) == 1 {
+		p.maxOneRow = p.children[0].MaxOneRow()
+	}
+}
+
+// logicalSchemaProducer is the base of the logical plans which produce their own schema and names.
+type logicalSchemaProducer struct {
+	baseLogicalPlan
+
+	schema *expression.Schema
+	names  types.NameSlice
+}
+
+// Schema implements Plan interface.
+func (p *logicalSchemaProducer) Schema() *expression.Schema {
+	if p.schema == nil {
+		p.schema = expression.NewSchema()
+	}
+	return p.schema
+}
+
+// SetSchema sets the schema of the plan.
+func (p *logicalSchemaProducer) SetSchema(schema *expression.Schema) {
+	p.schema = schema
+}
+
+// OutputNames implements Plan interface.
+func (p *logicalSchemaProducer) OutputNames() types.NameSlice {
+	return p.names
+}
+
+// SetOutputNames implements Plan interface.
+func (p *logicalSchemaProducer) SetOutputNames(names types.NameSlice) {
+	p.names = names
+}
+
+// inlineProjection prunes the unused columns of the schema and the names, the columns are kept in order.
+func (p *logicalSchemaProducer) inlineProjection(parentUsedCols []*expression.Column) {
+	used := getUsedList(parentUsedCols, p.Schema())
+	cols := make([]*expression.Column, 0, len(used))
+	names := make(types.NameSlice, 0, len(used))
+	for i, col := range p.schema.Columns {
+		if used[i] {
+			cols = append(cols, col)
+			names = append(names, p.names[i])
+		}
+	}
+	p.schema.Columns, p.names = cols, names
+}
+
+// addSelection adds a selection of the conditions between the parent and its idx-th child.
+func addSelection(p LogicalPlan, child LogicalPlan, conditions []expression.Expression, idx int) {
+	if len(conditions) == 0 {
+		p.SetChild(idx, child)
+		return
+	}
+	selection := LogicalSelection{Conditions: conditions}.Init(p.SCtx())
+	selection.SetChildren(child)
+	p.SetChild(idx, selection)
+}
+
+// basePhysicalPlan is the base of the physical plans, the plans which don't produce their own schema use
+// the schema of the first child.
+type basePhysicalPlan struct {
+	basePlan
+
+	self             PhysicalPlan
+	childrenReqProps []*property.PhysicalProperty
+	children         []PhysicalPlan
+	stats            *property.StatsInfo
+}
+
+func newBasePhysicalPlan(ctx sessionctx.Context, tp string, self PhysicalPlan) basePhysicalPlan {
+	return basePhysicalPlan{basePlan: newBasePlan(ctx, tp), self: self}
+}
+
+// Schema implements Plan interface.
+func (p *basePhysicalPlan) Schema() *expression.Schema {
+	return p.children[0].Schema()
+}
+
+// OutputNames implements Plan interface.
+func (p *basePhysicalPlan) OutputNames() types.NameSlice {
+	return p.children[0].OutputNames()
+}
+
+// SetOutputNames implements Plan interface.
+func (p *basePhysicalPlan) SetOutputNames(names types.NameSlice) {
+	p.children[0].SetOutputNames(names)
+}
+
+// GetChildReqProps implements PhysicalPlan interface.
+func (p *basePhysicalPlan) GetChildReqProps(idx int) *property.PhysicalProperty {
+	return p.childrenReqProps[idx]
+}
+
+// StatsCount implements PhysicalPlan interface.
+func (p *basePhysicalPlan) StatsCount() float64 {
+	return p.stats.RowCount
+}
+
+// ExplainInfo implements PhysicalPlan interface.
+func (p *basePhysicalPlan) ExplainInfo() string {
+	return ""
+}
+
+func (p *basePhysicalPlan) statsInfo() *property.StatsInfo {
+	return p.stats
+}
+
+// Children implements PhysicalPlan interface.
+func (p *basePhysicalPlan) Children() []PhysicalPlan {
+	return p.children
+}
+
+// SetChildren implements PhysicalPlan interface.
+func (p *basePhysicalPlan) SetChildren(children ...PhysicalPlan) {
+	p.children = children
+}
+
+// SetChild implements PhysicalPlan interface.
+func (p *basePhysicalPlan) SetChild(i int, child PhysicalPlan) {
+	p.children[i] = child
+}
+
+// physicalSchemaProducer is the base of the physical plans which produce their own schema and names.
+type physicalSchemaProducer struct {
+	basePhysicalPlan
+
+	schema *expression.Schema
+	names  types.NameSlice
+}
+
+// Schema implements Plan interface.
+func (p *physicalSchemaProducer) Schema() *expression.Schema {
+	if p.schema == nil {
+		p.schema = expression.NewSchema()
+	}
+	return p.schema
+}
+
+// SetSchema sets the schema of the plan.
+func (p *physicalSchemaProducer) SetSchema(schema *expression.Schema) {
+	p.schema = schema
+}
+
+// OutputNames implements Plan interface.
+func (p *physicalSchemaProducer) OutputNames() types.NameSlice {
+	return p.names
+}
+
+// SetOutputNames implements Plan interface.
+func (p *physicalSchemaProducer) SetOutputNames(names types.NameSlice) {
+	p.names = names
+}
@@ -0,0 +1,110 @@
+package property
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"grant-db/expression"
+)
+
+// SortItem wraps the column and its order.
+type SortItem struct {
+	Col  *expression.Column
+	Desc bool
+}
+
+// PhysicalProperty stands for the required physical property by parents.
+// It contains the orders and the expected count of the output rows.
+type PhysicalProperty struct {
+	Items []SortItem
+
+	// ExpectedCnt means this operator may be closed after fetching ExpectedCnt
+	// records.
+	ExpectedCnt float64
+}
+
+// NewPhysicalProperty builds property from columns.
+func NewPhysicalProperty(cols []*expression.Column, desc bool) *PhysicalProperty {
+	return &PhysicalProperty{
+		Items:       ItemsFromCols(cols, desc),
+		ExpectedCnt: math.MaxFloat64,
+	}
+}
+
+// ItemsFromCols builds the sort items of the columns in the same order.
+func ItemsFromCols(cols []*expression.Column, desc bool) []SortItem {
+	items := make([]SortItem, 0, len(cols))
+	for _, col := range cols {
+		items = append(items, SortItem{Col: col, Desc: desc})
+	}
+	return items
+}
+
+// AllColsFromSchema checks whether all the columns needed by this physical
+// property can be found in the given schema.
+func (p *PhysicalProperty) AllColsFromSchema(schema *expression.Schema) bool {
+	for _, col := range p.Items {
+		if schema.ColumnIndex(col.Col) == -1 {
+			return false
+		}
+	}
+	return true
+}
+
+// IsPrefix checks whether the order property is the prefix of another.
+func (p *PhysicalProperty) IsPrefix(prop *PhysicalProperty) bool {
+	if len(p.Items) > len(prop.Items) {
+		return false
+	}
+	for i := range p.Items {
+		if !p.Items[i].Col.Equal(nil, prop.Items[i].Col) || p.Items[i].Desc != prop.Items[i].Desc {
+			return false
+		}
+	}
+	return true
+}
+
+// IsEmpty checks whether the order property is empty.
+func (p *PhysicalProperty) IsEmpty() bool {
+	return len(p.Items) == 0
+}
+
+// AllSameOrder checks if all the items have same order.
+func (p *PhysicalProperty) AllSameOrder() (bool, bool) {
+	if len(p.Items) == 0 {
+		return true, false
+	}
+	for i := 1; i < len(p.Items); i++ {
+		if p.Items[i].Desc != p.Items[i-1].Desc {
+			return false, false
+		}
+	}
+	return true, p.Items[0].Desc
+}
+
+// HashCode calculates hash code for a PhysicalProperty object, it's the key of the memorized tasks.
+func (p *PhysicalProperty) HashCode() string {
+	return p.String()
+}
+
+// String implements fmt.Stringer interface. Just for test.
+func (p *PhysicalProperty) String() string {
+	items := make([]string, 0, len(p.Items))
+	for _, item := range p.Items {
+		order := "asc"
+		if item.Desc {
+			order = "desc"
+		}
+		items = append(items, fmt.Sprintf("Column#%d %s", item.Col.UniqueID, order))
+	}
+	return fmt.Sprintf("Prop{cols: [%s], cnt: %v}", strings.Join(items, ", "), p.ExpectedCnt)
+}
+
+// Clone returns a copy of PhysicalProperty.
+func (p *PhysicalProperty) Clone() *PhysicalProperty {
+	return &PhysicalProperty{
+		Items:       append([]SortItem(nil), p.Items...),
+		ExpectedCnt: p.ExpectedCnt,
+	}
+}
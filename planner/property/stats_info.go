@@ -0,0 +1,44 @@
+package property
+
+import (
+	"fmt"
+)
+
+// StatsInfo stores the basic information of statistics for the plan's output. It is used for cost estimation.
+type StatsInfo struct {
+	RowCount float64
+	// Cardinality is the number of the distinct values of the output columns in order.
+	Cardinality []float64
+}
+
+// String implements fmt.Stringer interface.
+func (s *StatsInfo) String() string {
+	return fmt.Sprintf("count %v, Cardinality %v", s.RowCount, s.Cardinality)
+}
+
+// Count gets the RowCount in the StatsInfo.
+func (s *StatsInfo) Count() int64 {
+	return int64(s.RowCount)
+}
+
+// Scale receives a selectivity and multiplies it with RowCount and Cardinality.
+func (s *StatsInfo) Scale(factor float64) *StatsInfo {
+	profile := &StatsInfo{
+		RowCount:    s.RowCount * factor,
+		Cardinality: make([]float64, len(s.Cardinality)),
+	}
+	for i := range profile.Cardinality {
+		profile.Cardinality[i] = s.Cardinality[i] * factor
+	}
+	return profile
+}
+
+// ScaleByExpectCnt tries to Scale StatsInfo to an expectCnt which must be
+// smaller than the derived cnt.
+// TODO: try to use a better way to do this.
+func (s *StatsInfo) ScaleByExpectCnt(expectCnt float64) *StatsInfo {
+	if expectCnt < s.RowCount {
+		return s.Scale(expectCnt / s.RowCount)
+	}
+	return s
+}
@@ -0,0 +1,266 @@
+package planner
+
+import (
+	"grant-db/expression"
+	"grant-db/expression/aggregation"
+	"grant-db/planner/property"
+)
+
+// ResolveIndices implements PhysicalPlan interface, the plans which evaluate no expressions only resolve
+// the children.
+func (p *basePhysicalPlan) ResolveIndices() error {
+	for _, child := range p.children {
+		if err := child.ResolveIndices(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveExprs returns the copies of the expressions whose columns are resolved by the schema, the
+// expressions may be shared with the other plans so they aren't changed.
+func resolveExprs(exprs []expression.Expression, schema *expression.Schema) ([]expression.Expression, error) {
+	resolved := make([]expression.Expression, 0, len(exprs))
+	for _, expr := range exprs {
+		newExpr, err := expr.ResolveIndices(schema)
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, newExpr)
+	}
+	return resolved, nil
+}
+
+// resolveByItems returns the copies of the items whose columns are resolved by the schema.
+func resolveByItems(items []*ByItems, schema *expression.Schema) ([]*ByItems, error) {
+	resolved := make([]*ByItems, 0, len(items))
+	for _, item := range items {
+		expr, err := item.Expr.ResolveIndices(schema)
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, &ByItems{Expr: expr, Desc: item.Desc})
+	}
+	return resolved, nil
+}
+
+// ResolveIndices implements PhysicalPlan interface.
+func (p *PhysicalSelection) ResolveIndices() (err error) {
+	if err = p.basePhysicalPlan.ResolveIndices(); err != nil {
+		return err
+	}
+	p.Conditions, err = resolveExprs(p.Conditions, p.children[0].Schema())
+	return err
+}
+
+// ResolveIndices implements PhysicalPlan interface.
+func (p *PhysicalProjection) ResolveIndices() (err error) {
+	if err = p.basePhysicalPlan.ResolveIndices(); err != nil {
+		return err
+	}
+	p.Exprs, err = resolveExprs(p.Exprs, p.children[0].Schema())
+	return err
+}
+
+// ResolveIndices implements PhysicalPlan interface.
+func (p *PhysicalSort) ResolveIndices() (err error) {
+	if err = p.basePhysicalPlan.ResolveIndices(); err != nil {
+		return err
+	}
+	p.ByItems, err = resolveByItems(p.ByItems, p.children[0].Schema())
+	return err
+}
+
+// ResolveIndices implements PhysicalPlan interface.
+func (p *PhysicalTopN) ResolveIndices() (err error) {
+	if err = p.basePhysicalPlan.ResolveIndices(); err != nil {
+		return err
+	}
+	p.ByItems, err = resolveByItems(p.ByItems, p.children[0].Schema())
+	return err
+}
+
+// ResolveIndices implements PhysicalPlan interface, the index plan and the table plan aren't the children.
+func (p *PhysicalIndexLookUp) ResolveIndices() error {
+	if err := p.IndexPlan.ResolveIndices(); err != nil {
+		return err
+	}
+	return p.TablePlan.ResolveIndices()
+}
+
+// resolveColumns returns the copies of the columns which are resolved by the schema.
+func resolveColumns(cols []*expression.Column, schema *expression.Schema) ([]*expression.Column, error) {
+	resolved := make([]*expression.Column, 0, len(cols))
+	for _, col := range cols {
+		newCol, err := col.ResolveIndices(schema)
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, newCol.(*expression.Column))
+	}
+	return resolved, nil
+}
+
+// resolveJoinIndices resolves the join keys and the conditions on one child by the schema of the child, the
+// other conditions are evaluated on the joined rows, whose columns are the ones of the left child and the
+// right child. The conditions of a NULL aware join are all evaluated on the joined rows.
+func (p *basePhysicalJoin) resolveJoinIndices() (err error) {
+	if err = p.basePhysicalPlan.ResolveIndices(); err != nil {
+		return err
+	}
+	lSchema, rSchema := p.children[0].Schema(), p.children[1].Schema()
+	mergedSchema := expression.MergeSchema(lSchema, rSchema)
+	if p.LeftJoinKeys, err = resolveColumns(p.LeftJoinKeys, lSchema); err != nil {
+		return err
+	}
+	if p.RightJoinKeys, err = resolveColumns(p.RightJoinKeys, rSchema); err != nil {
+		return err
+	}
+	lCondSchema, rCondSchema := lSchema, rSchema
+	if p.NullAware {
+		lCondSchema, rCondSchema = mergedSchema, mergedSchema
+	}
+	if p.LeftConditions, err = resolveExprs(p.LeftConditions, lCondSchema); err != nil {
+		return err
+	}
+	if p.RightConditions, err = resolveExprs(p.RightConditions, rCondSchema); err != nil {
+		return err
+	}
+	p.OtherConditions, err = resolveExprs(p.OtherConditions, mergedSchema)
+	return err
+}
+
+// ResolveIndices implements PhysicalPlan interface, the equal conditions are evaluated on the joined rows.
+func (p *PhysicalHashJoin) ResolveIndices() (err error) {
+	if err = p.resolveJoinIndices(); err != nil {
+		return err
+	}
+	mergedSchema := expression.MergeSchema(p.children[0].Schema(), p.children[1].Schema())
+	eqConds := make([]*expression.ScalarFunction, 0, len(p.EqualConditions))
+	for _, cond := range p.EqualConditions {
+		newCond, err := cond.ResolveIndices(mergedSchema)
+		if err != nil {
+			return err
+		}
+		eqConds = append(eqConds, newCond.(*expression.ScalarFunction))
+	}
+	p.EqualConditions = eqConds
+	return nil
+}
+
+// ResolveIndices implements PhysicalPlan interface.
+func (p *PhysicalMergeJoin) ResolveIndices() error {
+	return p.resolveJoinIndices()
+}
+
+// ResolveIndices implements PhysicalPlan interface.
+func (p *PhysicalIndexJoin) ResolveIndices() (err error) {
+	if err = p.resolveJoinIndices(); err != nil {
+		return err
+	}
+	outerSchema, innerSchema := p.children[1-p.InnerChildIdx].Schema(), p.children[p.InnerChildIdx].Schema()
+	if p.OuterJoinKeys, err = resolveColumns(p.OuterJoinKeys, outerSchema); err != nil {
+		return err
+	}
+	p.InnerJoinKeys, err = resolveColumns(p.InnerJoinKeys, innerSchema)
+	return err
+}
+
+// ResolveIndices implements PhysicalPlan interface, the correlated columns are resolved by the schema of the
+// outer child, whose values are read from the outer rows.
+func (p *PhysicalApply) ResolveIndices() (err error) {
+	if err = p.PhysicalHashJoin.ResolveIndices(); err != nil {
+		return err
+	}
+	for _, corCol := range p.CorCols {
+		col, err := corCol.Column.ResolveIndices(p.children[0].Schema())
+		if err != nil {
+			return err
+		}
+		corCol.Column = *col.(*expression.Column)
+	}
+	return nil
+}
+
+// ResolveIndices implements PhysicalPlan interface, the aggregate functions are copied since they're shared
+// with the logical aggregation.
+func (p *basePhysicalAgg) ResolveIndices() (err error) {
+	if err = p.basePhysicalPlan.ResolveIndices(); err != nil {
+		return err
+	}
+	childSchema := p.children[0].Schema()
+	aggFuncs := make([]*aggregation.AggFuncDesc, 0, len(p.AggFuncs))
+	for _, aggFunc := range p.AggFuncs {
+		newFunc := *aggFunc
+		if newFunc.Args, err = resolveExprs(aggFunc.Args, childSchema); err != nil {
+			return err
+		}
+		if aggFunc.OrderByItems != nil {
+			newFunc.OrderByItems = make([]*aggregation.ByItem, 0, len(aggFunc.OrderByItems))
+			for _, item := range aggFunc.OrderByItems {
+				expr, err := item.Expr.ResolveIndices(childSchema)
+				if err != nil {
+					return err
+				}
+				newFunc.OrderByItems = append(newFunc.OrderByItems, &aggregation.ByItem{Expr: expr, Desc: item.Desc})
+			}
+		}
+		aggFuncs = append(aggFuncs, &newFunc)
+	}
+	p.AggFuncs = aggFuncs
+	p.GroupByItems, err = resolveExprs(p.GroupByItems, childSchema)
+	return err
+}
+
+// resolveSortItems returns the copies of the items whose columns are resolved by the schema.
+func resolveSortItems(items []property.SortItem, schema *expression.Schema) ([]property.SortItem, error) {
+	resolved := make([]property.SortItem, 0, len(items))
+	for _, item := range items {
+		col, err := item.Col.ResolveIndices(schema)
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, property.SortItem{Col: col.(*expression.Column), Desc: item.Desc})
+	}
+	return resolved, nil
+}
+
+// ResolveIndices implements PhysicalPlan interface, the window functions and the frame are copied since
+// they're shared with the logical window.
+func (p *PhysicalWindow) ResolveIndices() (err error) {
+	if err = p.basePhysicalPlan.ResolveIndices(); err != nil {
+		return err
+	}
+	childSchema := p.children[0].Schema()
+	windowFuncs := make([]*aggregation.WindowFuncDesc, 0, len(p.WindowFuncDescs))
+	for _, windowFunc := range p.WindowFuncDescs {
+		newFunc := *windowFunc
+		if newFunc.Args, err = resolveExprs(windowFunc.Args, childSchema); err != nil {
+			return err
+		}
+		windowFuncs = append(windowFuncs, &newFunc)
+	}
+	p.WindowFuncDescs = windowFuncs
+	if p.PartitionBy, err = resolveSortItems(p.PartitionBy, childSchema); err != nil {
+		return err
+	}
+	if p.OrderBy, err = resolveSortItems(p.OrderBy, childSchema); err != nil {
+		return err
+	}
+	if p.Frame == nil {
+		return nil
+	}
+	frame := *p.Frame
+	for _, bound := range []**FrameBound{&frame.Start, &frame.End} {
+		if (*bound).CalcFunc == nil {
+			continue
+		}
+		newBound := **bound
+		if newBound.CalcFunc, err = newBound.CalcFunc.ResolveIndices(childSchema); err != nil {
+			return err
+		}
+		*bound = &newBound
+	}
+	p.Frame = &frame
+	return nil
+}
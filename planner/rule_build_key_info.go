@@ -0,0 +1,180 @@
+package planner
+
+import (
+	"github.com/pingcap/parser/mysql"
+	"grant-db/expression"
+)
+
+// buildKeySolver derives the unique keys and whether there is at most one row for every plan, which
+// are used by the decorrelation and the outer join elimination.
+type buildKeySolver struct{}
+
+func (s *buildKeySolver) optimize(lp LogicalPlan) (LogicalPlan, error) {
+	buildKeyInfo(lp)
+	return lp, nil
+}
+
+func (*buildKeySolver) name() string {
+	return "build_keys"
+}
+
+func buildKeyInfo(lp LogicalPlan) {
+	for _, child := range lp.Children() {
+		buildKeyInfo(child)
+	}
+	lp.buildKeyInfo()
+}
+
+func (ds *DataSource) buildKeyInfo() {
+	ds.schema.Keys = nil
+	if col := ds.getPKIsHandleCol(); col != nil {
+		ds.schema.Keys = append(ds.schema.Keys, expression.KeyInfo{col})
+	}
+	for _, idx := range ds.tableInfo.Indices {
+		if !idx.Unique && !idx.Primary {
+			continue
+		}
+		key := make(expression.KeyInfo, 0, len(idx.Columns))
+		for _, idxCol := range idx.Columns {
+			offset := -1
+			for i, col := range ds.Columns {
+				if col.Name.L == idxCol.Name.L {
+					offset = i
+					break
+				}
+			}
+			// The key is not unique if a column is pruned, nullable or a prefix of the column.
+			if offset == -1 || !mysql.HasNotNullFlag(ds.Columns[offset].Flag) ||
+				(idxCol.Length != -1 && idxCol.Length != ds.Columns[offset].Flen) {
+				key = nil
+				break
+			}
+			key = append(key, ds.schema.Columns[offset])
+		}
+		if key != nil {
+			ds.schema.Keys = append(ds.schema.Keys, key)
+		}
+	}
+}
+
+func (p *LogicalProjection) buildKeyInfo() {
+	p.baseLogicalPlan.buildKeyInfo()
+	p.schema.Keys = nil
+	childSchema := p.children[0].Schema()
+	for _, key := range childSchema.Keys {
+		newKey := make(expression.KeyInfo, 0, len(key))
+		for _, col := range key {
+			idx := p.exprColumnIndex(col)
+			if idx == -1 {
+				newKey = nil
+				break
+			}
+			newKey = append(newKey, p.schema.Columns[idx])
+		}
+		if newKey != nil {
+			p.schema.Keys = append(p.schema.Keys, newKey)
+		}
+	}
+}
+
+// exprColumnIndex returns the offset of the expression which is the column, -1 is returned if there is none.
+func (p *LogicalProjection) exprColumnIndex(col *expression.Column) int {
+	for i, expr := range p.Exprs {
+		if exprCol, ok := expr.(*expression.Column); ok && exprCol.Equal(nil, col) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (la *LogicalAggregation) buildKeyInfo() {
+	la.baseLogicalPlan.buildKeyInfo()
+	la.schema.Keys = nil
+	if len(la.GroupByItems) == 0 {
+		la.maxOneRow = true
+		return
+	}
+	// The group by columns are a key if their first rows are in the output.
+	gbyCols := la.groupByCols()
+	if len(gbyCols) != len(la.GroupByItems) {
+		return
+	}
+	key := make(expression.KeyInfo, 0, len(gbyCols))
+	for _, col := range gbyCols {
+		idx := -1
+		for i, aggFunc := range la.AggFuncs {
+			if isFirstRowOf(aggFunc, col) {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			return
+		}
+		key = append(key, la.schema.Columns[idx])
+	}
+	la.schema.Keys = append(la.schema.Keys, key)
+}
+
+func (p *LogicalJoin) buildKeyInfo() {
+	p.schema.Keys = nil
+	left, right := p.children[0], p.children[1]
+	switch p.JoinType {
+	case SemiJoin, AntiSemiJoin, LeftOuterSemiJoin, AntiLeftOuterSemiJoin:
+		p.maxOneRow = left.MaxOneRow()
+		p.schema.Keys = append(p.schema.Keys, left.Schema().Keys...)
+		return
+	}
+	p.maxOneRow = left.MaxOneRow() && right.MaxOneRow()
+	// Every row of a child matches one row of the other at most if the join keys of the other are unique,
+	// so the keys of the child are kept.
+	leftJoinKeys, rightJoinKeys := p.joinKeys()
+	if right.Schema().IsUniqueKey(rightJoinKeys...) {
+		p.schema.Keys = append(p.schema.Keys, left.Schema().Keys...)
+	}
+	if left.Schema().IsUniqueKey(leftJoinKeys...) {
+		p.schema.Keys = append(p.schema.Keys, right.Schema().Keys...)
+	}
+}
+
+// joinKeys returns the columns of the equal conditions of the left and the right child.
+func (p *LogicalJoin) joinKeys() (leftKeys, rightKeys []*expression.Column) {
+	for _, cond := range p.EqualConditions {
+		args := cond.GetArgs()
+		leftKeys = append(leftKeys, args[0].(*expression.Column))
+		rightKeys = append(rightKeys, args[1].(*expression.Column))
+	}
+	return leftKeys, rightKeys
+}
+
+func (p *LogicalLimit) buildKeyInfo() {
+	p.baseLogicalPlan.buildKeyInfo()
+	if p.Count <= 1 {
+		p.maxOneRow = true
+	}
+}
+
+func (lt *LogicalTopN) buildKeyInfo() {
+	lt.baseLogicalPlan.buildKeyInfo()
+	if lt.Count <= 1 {
+		lt.maxOneRow = true
+	}
+}
+
+func (p *LogicalMaxOneRow) buildKeyInfo() {
+	p.maxOneRow = true
+}
+
+// buildKeyInfo implements LogicalPlan interface, the rows of the child are kept so are the keys.
+func (p *LogicalWindow) buildKeyInfo() {
+	p.baseLogicalPlan.buildKeyInfo()
+	p.schema.Keys = p.children[0].Schema().Keys
+}
+
+func (p *LogicalTableDual) buildKeyInfo() {
+	p.maxOneRow = p.RowCount <= 1
+}
+
+func (p *LogicalUnionAll) buildKeyInfo() {
+	p.schema.Keys = nil
+}
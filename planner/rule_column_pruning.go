@@ -0,0 +1,283 @@
+package planner
+
+import (
+	"github.com/pingcap/parser/ast"
+	"grant-db/expression"
+	"grant-db/expression/aggregation"
+	"grant-db/types"
+)
+
+// columnPruner prunes the columns which are not used by the parents from the bottom plans.
+type columnPruner struct{}
+
+func (s *columnPruner) optimize(lp LogicalPlan) (LogicalPlan, error) {
+	err := lp.PruneColumns(lp.Schema().Columns)
+	return lp, err
+}
+
+func (*columnPruner) name() string {
+	return "column_prune"
+}
+
+// getUsedList returns a list of bool values, which tell whether the columns of the schema are used.
+func getUsedList(usedCols []*expression.Column, schema *expression.Schema) []bool {
+	used := make([]bool, schema.Len())
+	for _, col := range usedCols {
+		if idx := schema.ColumnIndex(col); idx != -1 {
+			used[idx] = true
+		}
+	}
+	return used
+}
+
+// PruneColumns implements LogicalPlan interface.
+func (p *LogicalProjection) PruneColumns(parentUsedCols []*expression.Column) error {
+	used := getUsedList(parentUsedCols, p.schema)
+	exprs := make([]expression.Expression, 0, len(used))
+	for i, expr := range p.Exprs {
+		if used[i] {
+			exprs = append(exprs, expr)
+		}
+	}
+	p.Exprs = exprs
+	p.inlineProjection(parentUsedCols)
+	return p.children[0].PruneColumns(expression.ExtractColumnsFromExpressions(p.Exprs))
+}
+
+// PruneColumns implements LogicalPlan interface.
+func (p *LogicalSelection) PruneColumns(parentUsedCols []*expression.Column) error {
+	parentUsedCols = append(parentUsedCols, expression.ExtractColumnsFromExpressions(p.Conditions)...)
+	return p.children[0].PruneColumns(parentUsedCols)
+}
+
+// PruneColumns implements LogicalPlan interface, the unused aggregate functions are removed but one.
+func (la *LogicalAggregation) PruneColumns(parentUsedCols []*expression.Column) error {
+	used := getUsedList(parentUsedCols, la.schema)
+	aggFuncs := make([]*aggregation.AggFuncDesc, 0, len(used))
+	for i, aggFunc := range la.AggFuncs {
+		if used[i] {
+			aggFuncs = append(aggFuncs, aggFunc)
+		}
+	}
+	if len(aggFuncs) == 0 && len(la.AggFuncs) > 0 {
+		// An aggregation outputs one row for every group, so a function is kept for the rows.
+		used[0] = true
+		aggFuncs = append(aggFuncs, la.AggFuncs[0])
+		parentUsedCols = append(parentUsedCols, la.schema.Columns[0])
+	}
+	la.AggFuncs = aggFuncs
+	la.inlineProjection(parentUsedCols)
+	var selfUsedCols []*expression.Column
+	for _, aggFunc := range la.AggFuncs {
+		selfUsedCols = append(selfUsedCols, expression.ExtractColumnsFromExpressions(aggFunc.Args)...)
+		for _, item := range aggFunc.OrderByItems {
+			selfUsedCols = append(selfUsedCols, expression.ExtractColumns(item.Expr)...)
+		}
+	}
+	selfUsedCols = append(selfUsedCols, expression.ExtractColumnsFromExpressions(la.GroupByItems)...)
+	return la.children[0].PruneColumns(selfUsedCols)
+}
+
+// PruneColumns implements LogicalPlan interface, the constant items are removed.
+func (ls *LogicalSort) PruneColumns(parentUsedCols []*expression.Column) error {
+	byItems := ls.ByItems[:0]
+	for _, item := range ls.ByItems {
+		cols := expression.ExtractColumns(item.Expr)
+		if len(cols) == 0 {
+			if _, ok := item.Expr.(*expression.ScalarFunction); !ok {
+				continue
+			}
+		}
+		byItems = append(byItems, item)
+		parentUsedCols = append(parentUsedCols, cols...)
+	}
+	ls.ByItems = byItems
+	return ls.children[0].PruneColumns(parentUsedCols)
+}
+
+// PruneColumns implements LogicalPlan interface, the columns of the order are used.
+func (lt *LogicalTopN) PruneColumns(parentUsedCols []*expression.Column) error {
+	for _, item := range lt.ByItems {
+		parentUsedCols = append(parentUsedCols, expression.ExtractColumns(item.Expr)...)
+	}
+	return lt.children[0].PruneColumns(parentUsedCols)
+}
+
+// PruneColumns implements LogicalPlan interface, the children are pruned by the same offsets.
+func (p *LogicalUnionAll) PruneColumns(parentUsedCols []*expression.Column) error {
+	used := getUsedList(parentUsedCols, p.schema)
+	for _, child := range p.children {
+		childCols := make([]*expression.Column, 0, len(used))
+		for i, col := range child.Schema().Columns {
+			if used[i] {
+				childCols = append(childCols, col)
+			}
+		}
+		if err := child.PruneColumns(childCols); err != nil {
+			return err
+		}
+	}
+	p.inlineProjection(parentUsedCols)
+	return nil
+}
+
+// PruneColumns implements LogicalPlan interface, one column is kept at least to read the rows.
+func (ds *DataSource) PruneColumns(parentUsedCols []*expression.Column) error {
+	used := getUsedList(parentUsedCols, ds.schema)
+	for _, cond := range ds.pushedDownConds {
+		for _, col := range expression.ExtractColumns(cond) {
+			if idx := ds.schema.ColumnIndex(col); idx != -1 {
+				used[idx] = true
+			}
+		}
+	}
+	keep := false
+	for _, u := range used {
+		keep = keep || u
+	}
+	if !keep && len(used) > 0 {
+		used[0] = true
+		if handleCol := ds.getPKIsHandleCol(); handleCol != nil {
+			used[0] = false
+			used[ds.schema.ColumnIndex(handleCol)] = true
+		}
+	}
+	cols := ds.Columns[:0:0]
+	usedCols := make([]*expression.Column, 0, len(used))
+	for i, u := range used {
+		if u {
+			cols = append(cols, ds.Columns[i])
+			usedCols = append(usedCols, ds.schema.Columns[i])
+		}
+	}
+	ds.Columns = cols
+	ds.inlineProjection(usedCols)
+	return nil
+}
+
+// PruneColumns implements LogicalPlan interface.
+func (p *LogicalTableDual) PruneColumns(parentUsedCols []*expression.Column) error {
+	p.inlineProjection(parentUsedCols)
+	return nil
+}
+
+// extractUsedCols splits the columns used by the parent and the conditions to the left and the right child.
+func (p *LogicalJoin) extractUsedCols(parentUsedCols []*expression.Column) (leftCols, rightCols []*expression.Column) {
+	for _, eqCond := range p.EqualConditions {
+		parentUsedCols = append(parentUsedCols, expression.ExtractColumns(eqCond)...)
+	}
+	for _, conds := range []expression.CNFExprs{p.LeftConditions, p.RightConditions, p.OtherConditions} {
+		parentUsedCols = append(parentUsedCols, expression.ExtractColumnsFromExpressions(conds)...)
+	}
+	lSchema, rSchema := p.children[0].Schema(), p.children[1].Schema()
+	for _, col := range parentUsedCols {
+		if lSchema.Contains(col) {
+			leftCols = append(leftCols, col)
+		} else if rSchema.Contains(col) {
+			rightCols = append(rightCols, col)
+		}
+	}
+	return leftCols, rightCols
+}
+
+// PruneColumns implements LogicalPlan interface.
+func (p *LogicalJoin) PruneColumns(parentUsedCols []*expression.Column) error {
+	leftCols, rightCols := p.extractUsedCols(parentUsedCols)
+	if err := p.children[0].PruneColumns(leftCols); err != nil {
+		return err
+	}
+	oldRightSchema := p.children[1].Schema().Clone()
+	if err := p.children[1].PruneColumns(rightCols); err != nil {
+		return err
+	}
+	if p.DefaultValues != nil {
+		// The default values are kept for the inner columns which are not pruned.
+		defaultValues := make([]types.Datum, 0, p.children[1].Schema().Len())
+		for _, col := range p.children[1].Schema().Columns {
+			defaultValues = append(defaultValues, p.DefaultValues[oldRightSchema.ColumnIndex(col)])
+		}
+		p.DefaultValues = defaultValues
+	}
+	p.mergeSchema()
+	return nil
+}
+
+// PruneColumns implements LogicalPlan interface, the columns of the outer child used by the inner child
+// are kept.
+func (la *LogicalApply) PruneColumns(parentUsedCols []*expression.Column) error {
+	leftCols, rightCols := la.extractUsedCols(parentUsedCols)
+	if err := la.children[1].PruneColumns(rightCols); err != nil {
+		return err
+	}
+	la.CorCols = extractCorColumnsBySchema(la.children[1], la.children[0].Schema())
+	for _, col := range la.CorCols {
+		leftCols = append(leftCols, &col.Column)
+	}
+	if err := la.children[0].PruneColumns(leftCols); err != nil {
+		return err
+	}
+	la.mergeSchema()
+	return nil
+}
+
+// mergeSchema builds the schema and the names of the join from the children after they are pruned, the
+// auxiliary column of a left outer semi join is kept.
+func (p *LogicalJoin) mergeSchema() {
+	var auxCol *expression.Column
+	var auxName *types.FieldName
+	if p.JoinType == LeftOuterSemiJoin || p.JoinType == AntiLeftOuterSemiJoin {
+		auxCol, auxName = p.schema.Columns[p.schema.Len()-1], p.names[len(p.names)-1]
+	}
+	p.buildSchema()
+	if auxCol != nil {
+		p.schema.Append(auxCol)
+		p.names = append(p.names, auxName)
+	}
+}
+
+// PruneColumns implements LogicalPlan interface.
+func (p *LogicalMaxOneRow) PruneColumns(parentUsedCols []*expression.Column) error {
+	return p.children[0].PruneColumns(parentUsedCols)
+}
+
+// PruneColumns implements LogicalPlan interface, the unused window functions are removed but one, and the
+// output columns of the child are the pruned ones.
+func (p *LogicalWindow) PruneColumns(parentUsedCols []*expression.Column) error {
+	windowCols := p.GetWindowResultColumns()
+	windowNames := p.names[len(p.names)-len(windowCols):]
+	used := getUsedList(parentUsedCols, expression.NewSchema(windowCols...))
+	windowFuncs := make([]*aggregation.WindowFuncDesc, 0, len(used))
+	cols := make([]*expression.Column, 0, len(used))
+	names := make(types.NameSlice, 0, len(used))
+	for i, windowFunc := range p.WindowFuncDescs {
+		if used[i] {
+			windowFuncs = append(windowFuncs, windowFunc)
+			cols = append(cols, windowCols[i])
+			names = append(names, windowNames[i])
+		}
+	}
+	if len(windowFuncs) == 0 {
+		// The window can't be removed by itself, so a function is kept.
+		windowFuncs = append(windowFuncs, p.WindowFuncDescs[0])
+		cols = append(cols, windowCols[0])
+		names = append(names, windowNames[0])
+	}
+	p.WindowFuncDescs = windowFuncs
+	selfUsedCols := append(parentUsedCols[:len(parentUsedCols):len(parentUsedCols)], p.extractUsedCols()...)
+	if err := p.children[0].PruneColumns(selfUsedCols); err != nil {
+		return err
+	}
+	childCols, childNames := p.children[0].Schema().Columns, p.children[0].OutputNames()
+	p.schema = expression.NewSchema(append(childCols[:len(childCols):len(childCols)], cols...)...)
+	p.names = append(childNames[:len(childNames):len(childNames)], names...)
+	return nil
+}
+
+// isFirstRowOf checks whether the aggregate function is the first row of the column.
+func isFirstRowOf(aggFunc *aggregation.AggFuncDesc, col *expression.Column) bool {
+	if aggFunc.Name != ast.AggFuncFirstRow {
+		return false
+	}
+	argCol, ok := aggFunc.Args[0].(*expression.Column)
+	return ok && argCol.Equal(nil, col)
+}
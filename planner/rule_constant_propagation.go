@@ -0,0 +1,133 @@
+package planner
+
+import (
+	"github.com/pingcap/parser/ast"
+	"grant-db/expression"
+	"grant-db/sessionctx"
+	"grant-db/types"
+)
+
+// constantPropagationSolver folds the conditions, the columns which equal to the constants are substituted by
+// the constants in the other conditions, like `a = 1 and a > b` to `a = 1 and 1 > b`. The plans whose conditions
+// are always false or NULL return no row, they are replaced by the empty table duals.
+type constantPropagationSolver struct{}
+
+func (s *constantPropagationSolver) optimize(p LogicalPlan) (LogicalPlan, error) {
+	return s.propagate(p), nil
+}
+
+func (*constantPropagationSolver) name() string {
+	return "constant_propagation"
+}
+
+func (s *constantPropagationSolver) propagate(p LogicalPlan) LogicalPlan {
+	for i, child := range p.Children() {
+		p.SetChild(i, s.propagate(child))
+	}
+	ctx := p.SCtx()
+	switch x := p.(type) {
+	case *LogicalSelection:
+		conds, alwaysFalse := propagateConstant(ctx, x.Conditions)
+		if alwaysFalse {
+			return newEmptyDual(p)
+		}
+		if len(conds) == 0 {
+			return x.children[0]
+		}
+		x.Conditions = conds
+	case *DataSource:
+		conds, alwaysFalse := propagateConstant(ctx, x.pushedDownConds)
+		if alwaysFalse {
+			return newEmptyDual(p)
+		}
+		x.pushedDownConds = conds
+	case *LogicalJoin:
+		if x.JoinType != InnerJoin && x.JoinType != SemiJoin {
+			break
+		}
+		if isEmptyDual(x.children[0]) || isEmptyDual(x.children[1]) {
+			return newEmptyDual(p)
+		}
+		var alwaysFalse bool
+		x.OtherConditions, alwaysFalse = propagateConstant(ctx, x.OtherConditions)
+		if alwaysFalse {
+			return newEmptyDual(p)
+		}
+	}
+	return p
+}
+
+// propagateConstant substitutes the columns which equal to the constants and folds the conditions, the true
+// conditions are removed.
+func propagateConstant(ctx sessionctx.Context, conds []expression.Expression) ([]expression.Expression, bool) {
+	if len(conds) == 0 {
+		return conds, false
+	}
+	var (
+		cols   []*expression.Column
+		consts []expression.Expression
+		srcIdx []int
+	)
+	for i, cond := range conds {
+		col, con := validEqualCond(cond)
+		if col == nil || expression.NewSchema(cols...).Contains(col) {
+			continue
+		}
+		cols, consts, srcIdx = append(cols, col), append(consts, con), append(srcIdx, i)
+	}
+	schema := expression.NewSchema(cols...)
+	newConds := make([]expression.Expression, 0, len(conds))
+	j := 0
+	for i, cond := range conds {
+		if j < len(srcIdx) && srcIdx[j] == i {
+			j++
+		} else if len(cols) > 0 {
+			cond = expression.ColumnSubstitute(cond, schema, consts)
+		}
+		cond = expression.FoldConstant(cond)
+		if expression.IsTrue(ctx, cond) {
+			continue
+		}
+		if expression.IsFalseOrNull(ctx, cond) {
+			return nil, true
+		}
+		newConds = append(newConds, cond)
+	}
+	return newConds, false
+}
+
+// validEqualCond returns the column and the constant of a `column = constant` condition, the constant is
+// substituted only if it's compared in the same way as the column.
+func validEqualCond(cond expression.Expression) (*expression.Column, expression.Expression) {
+	sf, ok := cond.(*expression.ScalarFunction)
+	if !ok || sf.FuncName.L != ast.EQ {
+		return nil, nil
+	}
+	args := sf.GetArgs()
+	col, ok := args[0].(*expression.Column)
+	con, isConst := args[1].(*expression.Constant)
+	if !ok || !isConst {
+		col, ok = args[1].(*expression.Column)
+		con, isConst = args[0].(*expression.Constant)
+	}
+	if !ok || !isConst || con.Value.IsNull() || !sameEvalType(col.GetType(), con.GetType()) {
+		return nil, nil
+	}
+	if col.GetType().EvalType() == types.ETString && col.GetType().Collate != con.GetType().Collate {
+		return nil, nil
+	}
+	return col, con
+}
+
+// newEmptyDual creates the table dual which returns no row and has the same schema and names as the plan.
+func newEmptyDual(p LogicalPlan) LogicalPlan {
+	dual := LogicalTableDual{}.Init(p.SCtx())
+	dual.SetSchema(p.Schema())
+	dual.names = p.OutputNames()
+	return dual
+}
+
+func isEmptyDual(p LogicalPlan) bool {
+	dual, ok := p.(*LogicalTableDual)
+	return ok && dual.RowCount == 0
+}
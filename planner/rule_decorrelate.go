@@ -0,0 +1,228 @@
+package planner
+
+import (
+	"math"
+
+	"github.com/pingcap/parser/ast"
+	"grant-db/expression"
+	"grant-db/expression/aggregation"
+	"grant-db/types"
+)
+
+// decorrelateSolver tries to convert apply plan to join plan, the plans of the inner child which refer to the
+// outer columns are pulled up into the conditions of the join.
+type decorrelateSolver struct{}
+
+func (s *decorrelateSolver) optimize(p LogicalPlan) (LogicalPlan, error) {
+	if apply, ok := p.(*LogicalApply); ok {
+		outerPlan, innerPlan := apply.children[0], apply.children[1]
+		apply.CorCols = extractCorColumnsBySchema(innerPlan, outerPlan.Schema())
+		if len(apply.CorCols) == 0 {
+			// The inner child doesn't refer to the outer child any more, so the apply is a join.
+			join := &apply.LogicalJoin
+			join.self, join.tp = join, TypeJoin
+			return s.optimize(join)
+		}
+		switch x := innerPlan.(type) {
+		case *LogicalSelection:
+			// The conditions of a NULL aware join are not filters of the inner rows, so they can't be mixed.
+			if apply.NullAware {
+				break
+			}
+			conds := make([]expression.Expression, 0, len(x.Conditions))
+			for _, cond := range x.Conditions {
+				conds = append(conds, expression.Decorrelate(cond, outerPlan.Schema()))
+			}
+			apply.SetChildren(outerPlan, x.children[0])
+			apply.AttachOnConds(conds)
+			return s.optimize(p)
+		case *LogicalMaxOneRow:
+			if x.children[0].MaxOneRow() {
+				apply.SetChildren(outerPlan, x.children[0])
+				return s.optimize(p)
+			}
+		case *LogicalSort:
+			// The order of the inner rows doesn't matter.
+			apply.SetChildren(outerPlan, x.children[0])
+			return s.optimize(p)
+		case *LogicalProjection:
+			if np, ok := s.pullUpProjection(apply, x); ok {
+				return s.optimize(np)
+			}
+		case *LogicalAggregation:
+			if s.pullUpCorrelatedAgg(apply, x) {
+				return s.optimize(p)
+			}
+		}
+	}
+	newChildren := make([]LogicalPlan, 0, len(p.Children()))
+	for _, child := range p.Children() {
+		np, err := s.optimize(child)
+		if err != nil {
+			return nil, err
+		}
+		newChildren = append(newChildren, np)
+	}
+	p.SetChildren(newChildren...)
+	return p, nil
+}
+
+func (*decorrelateSolver) name() string {
+	return "decorrelate"
+}
+
+// pullUpProjection pulls the projection of the inner child above the apply. The conditions of a semi join are
+// substituted with the expressions of the projection, and a projection is built above the other joins.
+func (s *decorrelateSolver) pullUpProjection(apply *LogicalApply, proj *LogicalProjection) (LogicalPlan, bool) {
+	outerPlan := apply.children[0]
+	innerPlan := proj.children[0]
+	exprs := make([]expression.Expression, 0, len(proj.Exprs))
+	for _, expr := range proj.Exprs {
+		exprs = append(exprs, expression.Decorrelate(expr, outerPlan.Schema()))
+	}
+	if apply.JoinType.IsSemiJoin() {
+		conds := apply.allConditions()
+		for i, cond := range conds {
+			conds[i] = expression.ColumnSubstitute(cond, proj.schema, exprs)
+		}
+		apply.EqualConditions, apply.LeftConditions, apply.RightConditions, apply.OtherConditions = nil, nil, nil, nil
+		apply.SetChildren(outerPlan, innerPlan)
+		apply.AttachOnConds(conds)
+		return apply, true
+	}
+	if apply.JoinType == LeftOuterJoin {
+		// The unmatched outer rows get NULLs of the inner columns, so the expressions must be NULL for them.
+		for _, expr := range exprs {
+			result := expression.EvaluateExprWithNull(apply.SCtx(), innerPlan.Schema(), expr)
+			if con, ok := result.(*expression.Constant); !ok || !con.Value.IsNull() {
+				return nil, false
+			}
+		}
+	}
+	if len(apply.allConditions()) > 0 {
+		return nil, false
+	}
+	oldSchema, oldNames := apply.schema, apply.names
+	apply.SetChildren(outerPlan, innerPlan)
+	apply.buildSchema()
+	newExprs := make([]expression.Expression, 0, oldSchema.Len())
+	for _, col := range outerPlan.Schema().Columns {
+		newExprs = append(newExprs, col)
+	}
+	newExprs = append(newExprs, exprs...)
+	newProj := LogicalProjection{Exprs: newExprs}.Init(apply.SCtx())
+	newProj.SetSchema(oldSchema)
+	newProj.names = oldNames
+	newProj.SetChildren(apply)
+	return newProj, true
+}
+
+// pullUpCorrelatedAgg decorrelates the aggregation without group by of a scalar sub query, like
+// `select (select count(*) from t2 where t2.a = t1.a) from t1`, the aggregation is grouped by the inner
+// columns of the correlated equal conditions, which become the conditions of the left outer join. The
+// default values of the inner columns are the results of the aggregate functions over no row.
+func (s *decorrelateSolver) pullUpCorrelatedAgg(apply *LogicalApply, agg *LogicalAggregation) bool {
+	if apply.JoinType != LeftOuterJoin || len(agg.GroupByItems) > 0 || len(apply.allConditions()) > 0 {
+		return false
+	}
+	sel, ok := agg.children[0].(*LogicalSelection)
+	if !ok {
+		return false
+	}
+	outerSchema := apply.children[0].Schema()
+	for _, aggFunc := range agg.AggFuncs {
+		for _, arg := range aggFunc.Args {
+			if expression.IsCorrelated(arg) {
+				return false
+			}
+		}
+		for _, item := range aggFunc.OrderByItems {
+			if expression.IsCorrelated(item.Expr) {
+				return false
+			}
+		}
+	}
+	var corConds, innerConds []expression.Expression
+	for _, cond := range sel.Conditions {
+		if !expression.IsCorrelated(cond) {
+			innerConds = append(innerConds, cond)
+			continue
+		}
+		sf, ok := cond.(*expression.ScalarFunction)
+		if !ok || sf.FuncName.L != ast.EQ {
+			return false
+		}
+		args := sf.GetArgs()
+		_, lCor := args[0].(*expression.CorrelatedColumn)
+		_, rCol := args[1].(*expression.Column)
+		_, rCor := args[1].(*expression.CorrelatedColumn)
+		_, lCol := args[0].(*expression.Column)
+		if !(lCor && rCol) && !(rCor && lCol) {
+			return false
+		}
+		corConds = append(corConds, cond)
+	}
+	ctx := apply.SCtx()
+	defaultValues := make([]types.Datum, 0, agg.schema.Len())
+	for _, aggFunc := range agg.AggFuncs {
+		defaultValues = append(defaultValues, aggFuncDefaultValue(aggFunc))
+	}
+	// The inner columns of the conditions are grouped by, and output by the firstrow functions.
+	joinConds := make([]expression.Expression, 0, len(corConds))
+	for _, cond := range corConds {
+		cond = expression.Decorrelate(cond, outerSchema)
+		args := cond.(*expression.ScalarFunction).GetArgs()
+		innerCol := args[1].(*expression.Column)
+		if !outerSchema.Contains(args[0].(*expression.Column)) {
+			innerCol = args[0].(*expression.Column)
+		}
+		agg.GroupByItems = append(agg.GroupByItems, innerCol)
+		idx := -1
+		for i, aggFunc := range agg.AggFuncs {
+			if isFirstRowOf(aggFunc, innerCol) {
+				idx = i
+				break
+			}
+		}
+		if idx == -1 {
+			firstRow, err := aggregation.NewAggFuncDesc(ctx, ast.AggFuncFirstRow, []expression.Expression{innerCol}, false)
+			if err != nil {
+				return false
+			}
+			agg.AggFuncs = append(agg.AggFuncs, firstRow)
+			newCol := innerCol.Clone().(*expression.Column)
+			newCol.RetType = firstRow.RetTp
+			agg.schema.Append(newCol)
+			agg.names = append(agg.names, &types.FieldName{})
+			defaultValues = append(defaultValues, types.Datum{})
+		}
+		joinConds = append(joinConds, cond)
+	}
+	if len(innerConds) > 0 {
+		sel.Conditions = innerConds
+	} else {
+		agg.SetChildren(sel.children[0])
+	}
+	apply.DefaultValues = defaultValues
+	oldSchema := apply.schema
+	apply.buildSchema()
+	// The columns of the apply are kept to be used by the parents.
+	for i, col := range oldSchema.Columns {
+		apply.schema.Columns[i] = col
+	}
+	apply.AttachOnConds(joinConds)
+	return true
+}
+
+// aggFuncDefaultValue returns the result of the aggregate function over no row.
+func aggFuncDefaultValue(aggFunc *aggregation.AggFuncDesc) types.Datum {
+	switch aggFunc.Name {
+	case ast.AggFuncCount:
+		return types.NewIntDatum(0)
+	case ast.AggFuncBitOr, ast.AggFuncBitXor:
+		return types.NewUintDatum(0)
+	case ast.AggFuncBitAnd:
+		return types.NewUintDatum(math.MaxUint64)
+	}
+	return types.Datum{}
+}
@@ -0,0 +1,63 @@
+package planner
+
+import (
+	"grant-db/expression"
+)
+
+// injectExtraProjection injects the projections which evaluate the expressions the executors need as
+// columns, so the executors only read the columns of the rows.
+func injectExtraProjection(p PhysicalPlan) PhysicalPlan {
+	for i, child := range p.Children() {
+		p.SetChild(i, injectExtraProjection(child))
+	}
+	switch x := p.(type) {
+	case *PhysicalSort:
+		return injectProjBelowSort(x, &x.ByItems)
+	case *PhysicalTopN:
+		return injectProjBelowSort(x, &x.ByItems)
+	}
+	return p
+}
+
+// injectProjBelowSort evaluates the sort items which aren't columns by the projection below the sort, the
+// projection above the sort removes the columns of them. The items are replaced by the new columns.
+func injectProjBelowSort(p PhysicalPlan, byItems *[]*ByItems) PhysicalPlan {
+	hasExpr := false
+	for _, item := range *byItems {
+		if _, ok := item.Expr.(*expression.Column); !ok {
+			hasExpr = true
+			break
+		}
+	}
+	if !hasExpr {
+		return p
+	}
+	ctx := p.SCtx()
+	topProj := PhysicalProjection{Exprs: expression.Column2Exprs(p.Schema().Columns)}.Init(ctx, p.statsInfo())
+	topProj.SetSchema(p.Schema().Clone())
+	topProj.names = p.OutputNames()
+	topProj.SetChildren(p)
+
+	child := p.Children()[0]
+	cols := make([]*expression.Column, 0, child.Schema().Len()+len(*byItems))
+	cols = append(cols, child.Schema().Columns...)
+	exprs := expression.Column2Exprs(cols)
+	// The items may be shared with the logical plan, so they are replaced by the new ones.
+	newItems := make([]*ByItems, 0, len(*byItems))
+	for _, item := range *byItems {
+		if _, ok := item.Expr.(*expression.Column); ok {
+			newItems = append(newItems, item)
+			continue
+		}
+		col := &expression.Column{UniqueID: ctx.GetSessionVars().AllocPlanColumnID(), RetType: item.Expr.GetType()}
+		cols = append(cols, col)
+		exprs = append(exprs, item.Expr)
+		newItems = append(newItems, &ByItems{Expr: col, Desc: item.Desc})
+	}
+	*byItems = newItems
+	bottomProj := PhysicalProjection{Exprs: exprs}.Init(ctx, child.statsInfo())
+	bottomProj.SetSchema(expression.NewSchema(cols...))
+	bottomProj.SetChildren(child)
+	p.SetChild(0, bottomProj)
+	return topProj
+}
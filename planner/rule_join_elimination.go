@@ -0,0 +1,140 @@
+package planner
+
+import (
+	"github.com/pingcap/parser/ast"
+	"grant-db/expression"
+	"grant-db/expression/aggregation"
+)
+
+// outerJoinEliminator removes the inner child of an outer join if the parent only uses the outer columns and
+// every outer row is output once, which happens when the inner join keys are unique, or the duplicate rows
+// don't change the results of the aggregation above, like `select distinct t1.a from t1 left join t2 on ...`.
+type outerJoinEliminator struct{}
+
+func (o *outerJoinEliminator) optimize(p LogicalPlan) (LogicalPlan, error) {
+	buildKeyInfo(p)
+	return o.doOptimize(p, nil, p.Schema().Columns), nil
+}
+
+func (*outerJoinEliminator) name() string {
+	return "outer_join_eliminate"
+}
+
+// doOptimize eliminates the outer joins of the plan tree, parentCols are the columns used by the parents, and
+// aggCols are the columns used by the aggregation above which ignores the duplicate rows.
+func (o *outerJoinEliminator) doOptimize(p LogicalPlan, aggCols []*expression.Column, parentCols []*expression.Column) LogicalPlan {
+	if join, ok := p.(*LogicalJoin); ok && (join.JoinType == LeftOuterJoin || join.JoinType == RightOuterJoin) {
+		if outerPlan := o.tryToEliminateOuterJoin(join, aggCols, parentCols); outerPlan != nil {
+			return o.doOptimize(outerPlan, aggCols, parentCols)
+		}
+	}
+	switch x := p.(type) {
+	case *LogicalProjection:
+		parentCols = expression.ExtractColumnsFromExpressions(x.Exprs)
+		// The columns used by the aggregation are mapped to the columns of the expressions.
+		var childAggCols []*expression.Column
+		for _, col := range aggCols {
+			if idx := x.schema.ColumnIndex(col); idx != -1 {
+				childAggCols = append(childAggCols, expression.ExtractColumns(x.Exprs[idx])...)
+			}
+		}
+		aggCols = childAggCols
+	case *LogicalAggregation:
+		parentCols = expression.ExtractColumnsFromExpressions(x.GroupByItems)
+		for _, aggFunc := range x.AggFuncs {
+			parentCols = append(parentCols, expression.ExtractColumnsFromExpressions(aggFunc.Args)...)
+			for _, item := range aggFunc.OrderByItems {
+				parentCols = append(parentCols, expression.ExtractColumns(item.Expr)...)
+			}
+		}
+		aggCols = nil
+		if isDuplicateAgnostic(x.AggFuncs) {
+			aggCols = parentCols
+		}
+	default:
+		parentCols = append(parentCols[:len(parentCols):len(parentCols)], extractSelfUsedCols(p)...)
+		aggCols = nil
+	}
+	for i, child := range p.Children() {
+		p.SetChild(i, o.doOptimize(child, aggCols, parentCols))
+	}
+	return p
+}
+
+// tryToEliminateOuterJoin returns the outer child if the outer join can be eliminated, otherwise nil is returned.
+func (o *outerJoinEliminator) tryToEliminateOuterJoin(p *LogicalJoin, aggCols []*expression.Column, parentCols []*expression.Column) LogicalPlan {
+	innerChildIdx := 1
+	if p.JoinType == RightOuterJoin {
+		innerChildIdx = 0
+	}
+	outerPlan, innerPlan := p.children[1-innerChildIdx], p.children[innerChildIdx]
+	if !allColsFromSchema(parentCols, outerPlan.Schema()) {
+		return nil
+	}
+	if len(aggCols) > 0 && allColsFromSchema(aggCols, outerPlan.Schema()) {
+		return outerPlan
+	}
+	innerJoinKeys := make([]*expression.Column, 0, len(p.EqualConditions))
+	for _, cond := range p.EqualConditions {
+		args := cond.GetArgs()
+		innerJoinKeys = append(innerJoinKeys, args[innerChildIdx].(*expression.Column))
+	}
+	if innerPlan.Schema().IsUniqueKey(innerJoinKeys...) {
+		return outerPlan
+	}
+	return nil
+}
+
+func allColsFromSchema(cols []*expression.Column, schema *expression.Schema) bool {
+	for _, col := range cols {
+		if !schema.Contains(col) {
+			return false
+		}
+	}
+	return true
+}
+
+// isDuplicateAgnostic checks whether the results of the aggregate functions are not changed by the duplicate rows.
+func isDuplicateAgnostic(aggFuncs []*aggregation.AggFuncDesc) bool {
+	for _, aggFunc := range aggFuncs {
+		switch aggFunc.Name {
+		case ast.AggFuncFirstRow, ast.AggFuncMax, ast.AggFuncMin, ast.AggFuncBitAnd, ast.AggFuncBitOr:
+		case ast.AggFuncCount, ast.AggFuncSum, ast.AggFuncAvg, ast.AggFuncBitXor, ast.AggFuncGroupConcat,
+			ast.AggFuncVarPop, ast.AggFuncVarSamp, ast.AggFuncStddevPop, ast.AggFuncStddevSamp:
+			if !aggFunc.HasDistinct {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// extractSelfUsedCols extracts the columns used by the expressions of the plan itself.
+func extractSelfUsedCols(p LogicalPlan) []*expression.Column {
+	switch x := p.(type) {
+	case *LogicalSelection:
+		return expression.ExtractColumnsFromExpressions(x.Conditions)
+	case *LogicalSort:
+		var cols []*expression.Column
+		for _, item := range x.ByItems {
+			cols = append(cols, expression.ExtractColumns(item.Expr)...)
+		}
+		return cols
+	case *LogicalJoin:
+		return expression.ExtractColumnsFromExpressions(x.allConditions())
+	case *LogicalApply:
+		cols := expression.ExtractColumnsFromExpressions(x.allConditions())
+		for _, corCol := range x.CorCols {
+			cols = append(cols, &corCol.Column)
+		}
+		return cols
+	case *LogicalWindow:
+		// The columns of the child are output by the window.
+		cols := x.schema.Columns[:x.schema.Len()-len(x.WindowFuncDescs)]
+		cols = append(cols[:len(cols):len(cols)], x.extractUsedCols()...)
+		return cols
+	}
+	return nil
+}
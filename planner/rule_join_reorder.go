@@ -0,0 +1,203 @@
+package planner
+
+import (
+	"sort"
+
+	"github.com/pingcap/parser/ast"
+	"grant-db/expression"
+	"grant-db/sessionctx"
+)
+
+// joinReOrderSolver reorders the groups of the inner joins greedily, the join of the least rows is chosen
+// first. The joins of STRAIGHT_JOIN and the hints keep the written order.
+type joinReOrderSolver struct {
+	ctx sessionctx.Context
+}
+
+// jrNode is a node of a join group with the accumulated row count of its joins.
+type jrNode struct {
+	p       LogicalPlan
+	cumCost float64
+}
+
+func (s *joinReOrderSolver) optimize(p LogicalPlan) (LogicalPlan, error) {
+	s.ctx = p.SCtx()
+	return s.optimizeRecursive(p)
+}
+
+func (*joinReOrderSolver) name() string {
+	return "join_reorder"
+}
+
+// extractJoinGroup extracts the children of the inner joins of the tree, and the conditions of them.
+func extractJoinGroup(p LogicalPlan) (group []LogicalPlan, eqEdges []*expression.ScalarFunction, otherConds []expression.Expression) {
+	join, isJoin := p.(*LogicalJoin)
+	if !isJoin || join.JoinType != InnerJoin || join.StraightJoin || join.preferJoinType > 0 {
+		return []LogicalPlan{p}, nil, nil
+	}
+	lhsGroup, lhsEqualConds, lhsOtherConds := extractJoinGroup(join.children[0])
+	rhsGroup, rhsEqualConds, rhsOtherConds := extractJoinGroup(join.children[1])
+	group = append(group, lhsGroup...)
+	group = append(group, rhsGroup...)
+	eqEdges = append(eqEdges, join.EqualConditions...)
+	eqEdges = append(eqEdges, lhsEqualConds...)
+	eqEdges = append(eqEdges, rhsEqualConds...)
+	otherConds = append(otherConds, join.LeftConditions...)
+	otherConds = append(otherConds, join.RightConditions...)
+	otherConds = append(otherConds, join.OtherConditions...)
+	otherConds = append(otherConds, lhsOtherConds...)
+	otherConds = append(otherConds, rhsOtherConds...)
+	return group, eqEdges, otherConds
+}
+
+func (s *joinReOrderSolver) optimizeRecursive(p LogicalPlan) (LogicalPlan, error) {
+	group, eqEdges, otherConds := extractJoinGroup(p)
+	if len(group) <= 1 {
+		newChildren := make([]LogicalPlan, 0, len(p.Children()))
+		for _, child := range p.Children() {
+			newChild, err := s.optimizeRecursive(child)
+			if err != nil {
+				return nil, err
+			}
+			newChildren = append(newChildren, newChild)
+		}
+		p.SetChildren(newChildren...)
+		return p, nil
+	}
+	for i, node := range group {
+		newNode, err := s.optimizeRecursive(node)
+		if err != nil {
+			return nil, err
+		}
+		group[i] = newNode
+	}
+	originalSchema, originalNames := p.Schema(), p.OutputNames()
+	newPlan, err := s.solve(group, eqEdges, otherConds)
+	if err != nil {
+		return nil, err
+	}
+	// The columns are kept in the original order by a projection, the parent refers to them by the offsets.
+	if sameColumnOrder(originalSchema, newPlan.Schema()) {
+		return newPlan, nil
+	}
+	proj := LogicalProjection{Exprs: expression.Column2Exprs(originalSchema.Columns)}.Init(s.ctx)
+	proj.SetSchema(originalSchema.Clone())
+	proj.names = originalNames
+	proj.SetChildren(newPlan)
+	return proj, nil
+}
+
+func sameColumnOrder(a, b *expression.Schema) bool {
+	if a.Len() != b.Len() {
+		return false
+	}
+	for i, col := range a.Columns {
+		if col.UniqueID != b.Columns[i].UniqueID {
+			return false
+		}
+	}
+	return true
+}
+
+// solve joins the nodes greedily, a join tree starts from the node of the least rows and joins the connected
+// node which makes the least accumulated rows each time. The trees which aren't connected are joined by the
+// cartesian joins at last.
+func (s *joinReOrderSolver) solve(group []LogicalPlan, eqEdges []*expression.ScalarFunction, otherConds []expression.Expression) (LogicalPlan, error) {
+	nodes := make([]*jrNode, 0, len(group))
+	for _, node := range group {
+		stats, err := node.recursiveDeriveStats()
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, &jrNode{p: node, cumCost: stats.RowCount})
+	}
+	sort.SliceStable(nodes, func(i, j int) bool { return nodes[i].cumCost < nodes[j].cumCost })
+	var joinTrees []*jrNode
+	for len(nodes) > 0 {
+		curTree := nodes[0]
+		nodes = nodes[1:]
+		for {
+			bestCost, bestIdx := 0.0, -1
+			var (
+				bestJoin     LogicalPlan
+				bestRemained []expression.Expression
+			)
+			for i, node := range nodes {
+				newJoin, remained := s.checkConnectionAndMakeJoin(curTree.p, node.p, eqEdges, otherConds)
+				if newJoin == nil {
+					continue
+				}
+				stats, err := newJoin.recursiveDeriveStats()
+				if err != nil {
+					return nil, err
+				}
+				cost := stats.RowCount + curTree.cumCost + node.cumCost
+				if bestIdx == -1 || cost < bestCost {
+					bestCost, bestIdx, bestJoin, bestRemained = cost, i, newJoin, remained
+				}
+			}
+			if bestIdx == -1 {
+				break
+			}
+			otherConds = bestRemained
+			curTree = &jrNode{p: bestJoin, cumCost: bestCost}
+			nodes = append(nodes[:bestIdx], nodes[bestIdx+1:]...)
+		}
+		joinTrees = append(joinTrees, curTree)
+	}
+	// The trees which aren't connected are joined by the cartesian joins.
+	root := joinTrees[0].p
+	for _, tree := range joinTrees[1:] {
+		join := s.newCartesianJoin(root, tree.p)
+		join.OtherConditions, otherConds = s.extractCoveredConds(join, otherConds)
+		root = join
+	}
+	if len(otherConds) > 0 {
+		join := root.(*LogicalJoin)
+		join.OtherConditions = append(join.OtherConditions, otherConds...)
+	}
+	return root, nil
+}
+
+func (s *joinReOrderSolver) newCartesianJoin(lChild, rChild LogicalPlan) *LogicalJoin {
+	join := LogicalJoin{JoinType: InnerJoin}.Init(s.ctx)
+	join.SetChildren(lChild, rChild)
+	join.buildSchema()
+	return join
+}
+
+// checkConnectionAndMakeJoin makes the join of the two plans if they are connected by the equal conditions,
+// the other conditions on the columns of the join are attached to it and the remained ones are returned.
+func (s *joinReOrderSolver) checkConnectionAndMakeJoin(lChild, rChild LogicalPlan, eqEdges []*expression.ScalarFunction,
+	otherConds []expression.Expression) (LogicalPlan, []expression.Expression) {
+	var usedEdges []*expression.ScalarFunction
+	for _, edge := range eqEdges {
+		lCol := edge.GetArgs()[0].(*expression.Column)
+		rCol := edge.GetArgs()[1].(*expression.Column)
+		if lChild.Schema().Contains(lCol) && rChild.Schema().Contains(rCol) {
+			usedEdges = append(usedEdges, edge)
+		} else if rChild.Schema().Contains(lCol) && lChild.Schema().Contains(rCol) {
+			newEdge := expression.NewFunctionInternal(s.ctx, ast.EQ, edge.GetType(), rCol, lCol).(*expression.ScalarFunction)
+			usedEdges = append(usedEdges, newEdge)
+		}
+	}
+	if len(usedEdges) == 0 {
+		return nil, otherConds
+	}
+	join := s.newCartesianJoin(lChild, rChild)
+	join.EqualConditions = usedEdges
+	join.OtherConditions, otherConds = s.extractCoveredConds(join, otherConds)
+	return join, otherConds
+}
+
+// extractCoveredConds returns the conditions on the columns of the join and the remained ones.
+func (s *joinReOrderSolver) extractCoveredConds(join *LogicalJoin, conds []expression.Expression) (covered, remained []expression.Expression) {
+	for _, cond := range conds {
+		if expression.ExprFromSchema(cond, join.schema) {
+			covered = append(covered, cond)
+		} else {
+			remained = append(remained, cond)
+		}
+	}
+	return covered, remained
+}
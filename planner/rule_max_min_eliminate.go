@@ -0,0 +1,46 @@
+package planner
+
+import (
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/expression"
+	"grant-db/types"
+)
+
+// maxMinEliminator rewrites the only MAX or MIN of an aggregation without group by to the first row of the
+// sorted non-NULL values, like `select max(a) from t` to `select max(a) from (select a from t where a is not null
+// order by a desc limit 1)`, which reads one row if the values are ordered by an index.
+type maxMinEliminator struct{}
+
+func (a *maxMinEliminator) optimize(p LogicalPlan) (LogicalPlan, error) {
+	a.eliminateMaxMin(p)
+	return p, nil
+}
+
+func (*maxMinEliminator) name() string {
+	return "max_min_eliminate"
+}
+
+func (a *maxMinEliminator) eliminateMaxMin(p LogicalPlan) {
+	if agg, ok := p.(*LogicalAggregation); ok && len(agg.GroupByItems) == 0 && len(agg.AggFuncs) == 1 {
+		f := agg.AggFuncs[0]
+		if (f.Name == ast.AggFuncMax || f.Name == ast.AggFuncMin) && !f.HasDistinct {
+			ctx := p.SCtx()
+			child := agg.children[0]
+			if !mysql.HasNotNullFlag(f.Args[0].GetType().Flag) {
+				isNull := expression.NewFunctionInternal(ctx, ast.IsNull, types.NewFieldType(mysql.TypeTiny), f.Args[0])
+				sel := LogicalSelection{Conditions: []expression.Expression{expression.NewNotFunction(ctx, isNull)}}.Init(ctx)
+				sel.SetChildren(child)
+				child = sel
+			}
+			sort := LogicalSort{ByItems: []*ByItems{{Expr: f.Args[0], Desc: f.Name == ast.AggFuncMax}}}.Init(ctx)
+			sort.SetChildren(child)
+			limit := LogicalLimit{Count: 1}.Init(ctx)
+			limit.SetChildren(sort)
+			agg.SetChildren(limit)
+		}
+	}
+	for _, child := range p.Children() {
+		a.eliminateMaxMin(child)
+	}
+}
@@ -0,0 +1,404 @@
+package planner
+
+import (
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/expression"
+	"grant-db/types"
+)
+
+// ppdSolver pushes the predicates down to the data sources as deep as possible, and the outer joins
+// whose inner rows are rejected by the predicates are simplified to inner joins.
+type ppdSolver struct{}
+
+func (s *ppdSolver) optimize(lp LogicalPlan) (LogicalPlan, error) {
+	_, p := lp.PredicatePushDown(nil)
+	return p, nil
+}
+
+func (*ppdSolver) name() string {
+	return "predicate_push_down"
+}
+
+// PredicatePushDown implements LogicalPlan PredicatePushDown interface.
+func (p *LogicalSelection) PredicatePushDown(predicates []expression.Expression) ([]expression.Expression, LogicalPlan) {
+	retConditions, child := p.children[0].PredicatePushDown(append(p.Conditions, predicates...))
+	if len(retConditions) > 0 {
+		p.Conditions = retConditions
+		p.SetChildren(child)
+		return nil, p
+	}
+	return nil, child
+}
+
+// PredicatePushDown implements LogicalPlan PredicatePushDown interface, the predicates are kept in the
+// data source to be used as the access conditions.
+func (ds *DataSource) PredicatePushDown(predicates []expression.Expression) ([]expression.Expression, LogicalPlan) {
+	ds.pushedDownConds = append(ds.pushedDownConds, predicates...)
+	return nil, ds
+}
+
+// PredicatePushDown implements LogicalPlan PredicatePushDown interface.
+func (p *LogicalTableDual) PredicatePushDown(predicates []expression.Expression) ([]expression.Expression, LogicalPlan) {
+	return predicates, p
+}
+
+// PredicatePushDown implements LogicalPlan PredicatePushDown interface, the predicates can't be pushed
+// through the limit.
+func (p *LogicalLimit) PredicatePushDown(predicates []expression.Expression) ([]expression.Expression, LogicalPlan) {
+	p.baseLogicalPlan.PredicatePushDown(nil)
+	return predicates, p
+}
+
+// PredicatePushDown implements LogicalPlan PredicatePushDown interface, the predicates can't be pushed
+// through the top-n.
+func (lt *LogicalTopN) PredicatePushDown(predicates []expression.Expression) ([]expression.Expression, LogicalPlan) {
+	lt.baseLogicalPlan.PredicatePushDown(nil)
+	return predicates, lt
+}
+
+// PredicatePushDown implements LogicalPlan PredicatePushDown interface.
+func (p *LogicalMaxOneRow) PredicatePushDown(predicates []expression.Expression) ([]expression.Expression, LogicalPlan) {
+	p.baseLogicalPlan.PredicatePushDown(nil)
+	return predicates, p
+}
+
+// PredicatePushDown implements LogicalPlan PredicatePushDown interface, the columns of the predicates are
+// substituted with the expressions of the projection.
+func (p *LogicalProjection) PredicatePushDown(predicates []expression.Expression) ([]expression.Expression, LogicalPlan) {
+	var push, ret []expression.Expression
+	for _, cond := range predicates {
+		if p.canSubstitute(cond) {
+			push = append(push, expression.ColumnSubstitute(cond, p.schema, p.Exprs))
+		} else {
+			ret = append(ret, cond)
+		}
+	}
+	p.baseLogicalPlan.PredicatePushDown(push)
+	return ret, p
+}
+
+// canSubstitute checks whether the expressions of the projection which the predicate refers to can be
+// evaluated below the projection, the non-deterministic ones can't.
+func (p *LogicalProjection) canSubstitute(cond expression.Expression) bool {
+	for _, col := range expression.ExtractColumns(cond) {
+		idx := p.schema.ColumnIndex(col)
+		if idx == -1 {
+			continue
+		}
+		if sf, ok := p.Exprs[idx].(*expression.ScalarFunction); ok && !isDeterministic(sf) {
+			return false
+		}
+	}
+	return true
+}
+
+func isDeterministic(expr expression.Expression) bool {
+	sf, ok := expr.(*expression.ScalarFunction)
+	if !ok {
+		return true
+	}
+	switch sf.FuncName.L {
+	case ast.Rand, ast.UUID, ast.GetVar, ast.SetVar:
+		return false
+	}
+	for _, arg := range sf.GetArgs() {
+		if !isDeterministic(arg) {
+			return false
+		}
+	}
+	return true
+}
+
+// PredicatePushDown implements LogicalPlan PredicatePushDown interface, the predicates on the group by
+// columns are pushed down.
+func (la *LogicalAggregation) PredicatePushDown(predicates []expression.Expression) ([]expression.Expression, LogicalPlan) {
+	var push, ret []expression.Expression
+	gbySchema := expression.NewSchema(la.groupByCols()...)
+	exprsOfSchema := make([]expression.Expression, 0, la.schema.Len())
+	for _, aggFunc := range la.AggFuncs {
+		exprsOfSchema = append(exprsOfSchema, aggFunc.Args[0])
+	}
+	for _, cond := range predicates {
+		cols := expression.ExtractColumns(cond)
+		canPush := len(cols) > 0 && len(la.GroupByItems) > 0
+		for _, col := range cols {
+			idx := la.schema.ColumnIndex(col)
+			if idx == -1 || la.AggFuncs[idx].Name != ast.AggFuncFirstRow {
+				canPush = false
+				break
+			}
+			argCol, ok := la.AggFuncs[idx].Args[0].(*expression.Column)
+			if !ok || !gbySchema.Contains(argCol) {
+				canPush = false
+				break
+			}
+		}
+		if canPush {
+			push = append(push, expression.ColumnSubstitute(cond, la.schema, exprsOfSchema))
+		} else {
+			ret = append(ret, cond)
+		}
+	}
+	la.baseLogicalPlan.PredicatePushDown(push)
+	return ret, la
+}
+
+// PredicatePushDown implements LogicalPlan PredicatePushDown interface, the predicates only on the PARTITION
+// BY columns are pushed down since they filter the whole partitions.
+func (p *LogicalWindow) PredicatePushDown(predicates []expression.Expression) ([]expression.Expression, LogicalPlan) {
+	partitionSchema := expression.NewSchema()
+	for _, item := range p.PartitionBy {
+		partitionSchema.Append(item.Col)
+	}
+	var push, ret []expression.Expression
+	for _, cond := range predicates {
+		cols := expression.ExtractColumns(cond)
+		canPush := len(cols) > 0 && isDeterministic(cond)
+		for _, col := range cols {
+			if !partitionSchema.Contains(col) {
+				canPush = false
+				break
+			}
+		}
+		if canPush {
+			push = append(push, cond)
+		} else {
+			ret = append(ret, cond)
+		}
+	}
+	p.baseLogicalPlan.PredicatePushDown(push)
+	return ret, p
+}
+
+// PredicatePushDown implements LogicalPlan PredicatePushDown interface, the predicates are pushed down to
+// every child by the columns of the same offsets.
+func (p *LogicalUnionAll) PredicatePushDown(predicates []expression.Expression) ([]expression.Expression, LogicalPlan) {
+	for i, child := range p.children {
+		newExprs := make([]expression.Expression, 0, child.Schema().Len())
+		for _, col := range child.Schema().Columns {
+			newExprs = append(newExprs, col)
+		}
+		newPreds := make([]expression.Expression, 0, len(predicates))
+		for _, cond := range predicates {
+			newPreds = append(newPreds, expression.ColumnSubstitute(cond.Clone(), p.schema, newExprs))
+		}
+		rest, newChild := child.PredicatePushDown(newPreds)
+		addSelection(p, newChild, rest, i)
+	}
+	return nil, p
+}
+
+// PredicatePushDown implements LogicalPlan PredicatePushDown interface.
+func (p *LogicalJoin) PredicatePushDown(predicates []expression.Expression) (ret []expression.Expression, retPlan LogicalPlan) {
+	simplifyOuterJoin(p, predicates)
+	var leftPushCond, rightPushCond []expression.Expression
+	switch p.JoinType {
+	case LeftOuterJoin, LeftOuterSemiJoin, AntiLeftOuterSemiJoin:
+		// The predicates on the outer child filter the output rows, and the conditions on the inner child
+		// filter the inner rows.
+		leftPushCond, ret = p.extractOnlyLeft(predicates)
+		rightPushCond, p.RightConditions = p.pushDownRightConditions()
+	case RightOuterJoin:
+		rightPushCond, ret = p.extractOnlyRight(predicates)
+		leftPushCond, p.LeftConditions = p.LeftConditions, nil
+	case SemiJoin, InnerJoin:
+		tempCond := make([]expression.Expression, 0, len(p.LeftConditions)+len(p.RightConditions)+len(p.OtherConditions)+len(predicates))
+		tempCond = append(tempCond, p.LeftConditions...)
+		tempCond = append(tempCond, p.RightConditions...)
+		tempCond = append(tempCond, expression.ScalarFuncs2Exprs(p.EqualConditions)...)
+		tempCond = append(tempCond, p.OtherConditions...)
+		tempCond = append(tempCond, predicates...)
+		p.LeftConditions, p.RightConditions, p.OtherConditions, p.EqualConditions = nil, nil, nil, nil
+		p.EqualConditions, leftPushCond, rightPushCond, p.OtherConditions = p.extractOnCondition(tempCond)
+	case AntiSemiJoin:
+		// The conditions of the left child decide whether the rows match, so only the predicates are pushed.
+		leftPushCond, ret = p.extractOnlyLeft(predicates)
+		rightPushCond, p.RightConditions = p.pushDownRightConditions()
+	}
+	leftRet, lCh := p.children[0].PredicatePushDown(leftPushCond)
+	rightRet, rCh := p.children[1].PredicatePushDown(rightPushCond)
+	addSelection(p, lCh, leftRet, 0)
+	addSelection(p, rCh, rightRet, 1)
+	p.updateEQCond()
+	return ret, p.self
+}
+
+// pushDownRightConditions returns the conditions on the right child to be pushed down and the ones to be kept,
+// the conditions of a NULL aware join are kept since a NULL result of them isn't a mismatch.
+func (p *LogicalJoin) pushDownRightConditions() (pushed, kept []expression.Expression) {
+	if p.NullAware {
+		return nil, p.RightConditions
+	}
+	return p.RightConditions, nil
+}
+
+// extractOnlyLeft splits the predicates to the ones on the left child and the others.
+func (p *LogicalJoin) extractOnlyLeft(predicates []expression.Expression) (left, others []expression.Expression) {
+	lSchema := p.children[0].Schema()
+	for _, cond := range predicates {
+		if expression.ExprFromSchema(cond, lSchema) {
+			left = append(left, cond)
+		} else {
+			others = append(others, cond)
+		}
+	}
+	return left, others
+}
+
+// extractOnlyRight splits the predicates to the ones on the right child and the others.
+func (p *LogicalJoin) extractOnlyRight(predicates []expression.Expression) (right, others []expression.Expression) {
+	rSchema := p.children[1].Schema()
+	for _, cond := range predicates {
+		if expression.ExprFromSchema(cond, rSchema) {
+			right = append(right, cond)
+		} else {
+			others = append(others, cond)
+		}
+	}
+	return right, others
+}
+
+// updateEQCond moves the equal conditions of the other conditions to the equal conditions, which happens
+// when the children are changed.
+func (p *LogicalJoin) updateEQCond() {
+	if len(p.OtherConditions) == 0 {
+		return
+	}
+	eqConds, _, _, otherConds := p.extractOnCondition(p.OtherConditions)
+	p.EqualConditions = append(p.EqualConditions, eqConds...)
+	p.OtherConditions = otherConds
+}
+
+// PredicatePushDown implements LogicalPlan PredicatePushDown interface, the predicates on the outer child
+// are pushed down and the inner child is optimized alone.
+func (la *LogicalApply) PredicatePushDown(predicates []expression.Expression) ([]expression.Expression, LogicalPlan) {
+	// The outer rows are kept by every type of apply, so the predicates only on them can be pushed down.
+	leftPushCond, ret := la.extractOnlyLeft(predicates)
+	leftRet, lCh := la.children[0].PredicatePushDown(leftPushCond)
+	addSelection(la, lCh, leftRet, 0)
+	rightRet, rCh := la.children[1].PredicatePushDown(nil)
+	addSelection(la, rCh, rightRet, 1)
+	return ret, la
+}
+
+// simplifyOuterJoin turns an outer join into an inner join if a predicate rejects the rows whose inner
+// columns are NULL, like `t1 left join t2 on t1.a = t2.a where t2.b > 1`.
+func simplifyOuterJoin(p *LogicalJoin, predicates []expression.Expression) {
+	if p.JoinType != LeftOuterJoin && p.JoinType != RightOuterJoin && p.JoinType != InnerJoin {
+		return
+	}
+	innerTable := p.children[1]
+	outerTable := p.children[0]
+	if p.JoinType == RightOuterJoin {
+		innerTable, outerTable = outerTable, innerTable
+	}
+	// The nested outer joins are simplified first, whose inner columns may be rejected by the predicates.
+	if innerPlan, ok := innerTable.(*LogicalJoin); ok {
+		simplifyOuterJoin(innerPlan, predicates)
+	}
+	if outerPlan, ok := outerTable.(*LogicalJoin); ok {
+		simplifyOuterJoin(outerPlan, predicates)
+	}
+	if p.JoinType == InnerJoin {
+		return
+	}
+	for _, expr := range predicates {
+		if isNullRejected(p, innerTable.Schema(), expr) {
+			p.JoinType = InnerJoin
+			return
+		}
+	}
+}
+
+// isNullRejected checks whether the predicate is false or NULL when the columns of the inner schema are
+// NULL, the inner columns of an outer join are NULL for the unmatched rows.
+func isNullRejected(p *LogicalJoin, innerSchema *expression.Schema, predicate expression.Expression) bool {
+	if len(p.DefaultValues) > 0 {
+		return false
+	}
+	ctx := p.SCtx()
+	sc := ctx.GetSessionVars().StmtCtx
+	oldTruncate := sc.TruncateAsWarning
+	sc.TruncateAsWarning = true
+	defer func() { sc.TruncateAsWarning = oldTruncate }()
+	for _, cond := range expression.SplitCNFItems(predicate) {
+		if !expression.ExprFromSchema(cond, mergedSchema(p)) {
+			continue
+		}
+		result := expression.EvaluateExprWithNull(ctx, innerSchema, cond)
+		if expression.IsFalseOrNull(ctx, result) {
+			return true
+		}
+	}
+	return false
+}
+
+func mergedSchema(p *LogicalJoin) *expression.Schema {
+	return expression.MergeSchema(p.children[0].Schema(), p.children[1].Schema())
+}
+
+// extractOnCondition divides the conditions of a join into the equal conditions of a left column and a right
+// column, the conditions only on the left child, the conditions only on the right child and the others.
+func (p *LogicalJoin) extractOnCondition(conditions []expression.Expression) (eqCond []*expression.ScalarFunction,
+	leftCond []expression.Expression, rightCond []expression.Expression, otherCond []expression.Expression) {
+	left, right := p.children[0], p.children[1]
+	for _, expr := range conditions {
+		if sf, ok := expr.(*expression.ScalarFunction); ok && sf.FuncName.L == ast.EQ {
+			args := sf.GetArgs()
+			ln, lOK := args[0].(*expression.Column)
+			rn, rOK := args[1].(*expression.Column)
+			if lOK && rOK && sameEvalType(ln.GetType(), rn.GetType()) {
+				if left.Schema().Contains(ln) && right.Schema().Contains(rn) {
+					eqCond = append(eqCond, sf)
+					continue
+				}
+				if left.Schema().Contains(rn) && right.Schema().Contains(ln) {
+					cond := expression.NewFunctionInternal(p.ctx, ast.EQ, types.NewFieldType(mysql.TypeTiny), rn, ln)
+					eqCond = append(eqCond, cond.(*expression.ScalarFunction))
+					continue
+				}
+			}
+		}
+		columns := expression.ExtractColumns(expr)
+		allFromLeft, allFromRight := true, true
+		for _, col := range columns {
+			allFromLeft = allFromLeft && left.Schema().Contains(col)
+			allFromRight = allFromRight && right.Schema().Contains(col)
+		}
+		switch {
+		case len(columns) == 0:
+			// A constant condition decides the both children.
+			leftCond = append(leftCond, expr)
+			rightCond = append(rightCond, expr)
+		case allFromLeft:
+			leftCond = append(leftCond, expr)
+		case allFromRight:
+			rightCond = append(rightCond, expr)
+		default:
+			otherCond = append(otherCond, expr)
+		}
+	}
+	return
+}
+
+// sameEvalType checks whether the two types are compared in the same way, the values of an equal condition
+// are compared by their encoded keys in a hash join.
+func sameEvalType(a, b *types.FieldType) bool {
+	if a.EvalType() != b.EvalType() {
+		return false
+	}
+	if a.EvalType() == types.ETString && a.Collate != b.Collate {
+		return false
+	}
+	return mysql.HasUnsignedFlag(a.Flag) == mysql.HasUnsignedFlag(b.Flag)
+}
+
+// AttachOnConds divides the conditions of the ON clause and attaches them to the join.
+func (p *LogicalJoin) AttachOnConds(onConds []expression.Expression) {
+	eq, left, right, other := p.extractOnCondition(onConds)
+	p.EqualConditions = append(eq, p.EqualConditions...)
+	p.LeftConditions = append(left, p.LeftConditions...)
+	p.RightConditions = append(right, p.RightConditions...)
+	p.OtherConditions = append(other, p.OtherConditions...)
+}
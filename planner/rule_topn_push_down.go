@@ -0,0 +1,131 @@
+package planner
+
+import (
+	"grant-db/expression"
+)
+
+// pushDownTopNOptimizer turns the sorts under the limits into the top-ns, and pushes the top-ns and the
+// limits down through the projections, the outer joins and the unions.
+type pushDownTopNOptimizer struct{}
+
+func (s *pushDownTopNOptimizer) optimize(p LogicalPlan) (LogicalPlan, error) {
+	return p.pushDownTopN(nil), nil
+}
+
+func (*pushDownTopNOptimizer) name() string {
+	return "topn_push_down"
+}
+
+// setChild makes the top-n the parent of the plan, it's a limit if there is no order.
+func (lt *LogicalTopN) setChild(p LogicalPlan) LogicalPlan {
+	if lt.isLimit() {
+		limit := LogicalLimit{Count: lt.Count, Offset: lt.Offset}.Init(lt.ctx)
+		limit.SetChildren(p)
+		return limit
+	}
+	lt.SetChildren(p)
+	return lt
+}
+
+func (p *baseLogicalPlan) pushDownTopN(topN *LogicalTopN) LogicalPlan {
+	for i, child := range p.children {
+		p.children[i] = child.pushDownTopN(nil)
+	}
+	if topN != nil {
+		return topN.setChild(p.self)
+	}
+	return p.self
+}
+
+// pushDownTopN implements LogicalPlan interface, the sort under a limit is the order of the top-n.
+func (ls *LogicalSort) pushDownTopN(topN *LogicalTopN) LogicalPlan {
+	if topN == nil {
+		return ls.baseLogicalPlan.pushDownTopN(nil)
+	}
+	if topN.isLimit() {
+		topN.ByItems = ls.ByItems
+	}
+	// The sort under a top-n is useless.
+	return ls.children[0].pushDownTopN(topN)
+}
+
+func (p *LogicalLimit) convertToTopN() *LogicalTopN {
+	return LogicalTopN{Offset: p.Offset, Count: p.Count}.Init(p.ctx)
+}
+
+func (p *LogicalLimit) pushDownTopN(topN *LogicalTopN) LogicalPlan {
+	child := p.children[0].pushDownTopN(p.convertToTopN())
+	if topN != nil {
+		return topN.setChild(child)
+	}
+	return child
+}
+
+// pushDownTopN implements LogicalPlan interface, the columns of the top-n are substituted with the
+// expressions of the projection.
+func (p *LogicalProjection) pushDownTopN(topN *LogicalTopN) LogicalPlan {
+	if topN != nil {
+		for _, by := range topN.ByItems {
+			by.Expr = expression.ColumnSubstitute(by.Expr, p.schema, p.Exprs)
+		}
+	}
+	p.children[0] = p.children[0].pushDownTopN(topN)
+	return p
+}
+
+// pushDownTopN implements LogicalPlan interface, every child only needs the first offset+count rows.
+func (p *LogicalUnionAll) pushDownTopN(topN *LogicalTopN) LogicalPlan {
+	for i, child := range p.children {
+		var newTopN *LogicalTopN
+		if topN != nil {
+			newTopN = LogicalTopN{Count: topN.Count + topN.Offset}.Init(p.ctx)
+			childExprs := expression.Column2Exprs(child.Schema().Columns)
+			for _, by := range topN.ByItems {
+				newTopN.ByItems = append(newTopN.ByItems, &ByItems{
+					Expr: expression.ColumnSubstitute(by.Expr, p.schema, childExprs),
+					Desc: by.Desc,
+				})
+			}
+		}
+		p.children[i] = child.pushDownTopN(newTopN)
+	}
+	if topN != nil {
+		return topN.setChild(p)
+	}
+	return p
+}
+
+// pushDownTopN implements LogicalPlan interface, the outer child of an outer join only needs the first
+// offset+count rows if the top-n is ordered by its columns.
+func (p *LogicalJoin) pushDownTopN(topN *LogicalTopN) LogicalPlan {
+	for i, child := range p.children {
+		var newTopN *LogicalTopN
+		if topN != nil && p.isOuterChild(i) && p.canPushTopN(topN, child) {
+			newTopN = LogicalTopN{Count: topN.Count + topN.Offset}.Init(p.ctx)
+			for _, by := range topN.ByItems {
+				newTopN.ByItems = append(newTopN.ByItems, by.Clone())
+			}
+		}
+		p.children[i] = child.pushDownTopN(newTopN)
+	}
+	if topN != nil {
+		return topN.setChild(p.self)
+	}
+	return p.self
+}
+
+func (p *LogicalJoin) isOuterChild(idx int) bool {
+	return (p.JoinType == LeftOuterJoin && idx == 0) || (p.JoinType == RightOuterJoin && idx == 1)
+}
+
+// canPushTopN checks whether the columns of the top-n are the columns of the child.
+func (p *LogicalJoin) canPushTopN(topN *LogicalTopN, child LogicalPlan) bool {
+	for _, by := range topN.ByItems {
+		for _, col := range expression.ExtractColumns(by.Expr) {
+			if !child.Schema().Contains(col) {
+				return false
+			}
+		}
+	}
+	return true
+}
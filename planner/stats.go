@@ -0,0 +1,295 @@
+package planner
+
+import (
+	"math"
+
+	"grant-db/domain"
+	"grant-db/expression"
+	"grant-db/planner/property"
+	"grant-db/statistics"
+)
+
+const (
+	// selectionFactor is the selectivity of the conditions which can't be estimated by the statistics.
+	selectionFactor = 0.8
+	// distinctFactor is the ratio of the distinct values of a column without the statistics.
+	distinctFactor = 0.8
+)
+
+func (p *baseLogicalPlan) recursiveDeriveStats() (*property.StatsInfo, error) {
+	childStats := make([]*property.StatsInfo, len(p.children))
+	for i, child := range p.children {
+		childProfile, err := child.recursiveDeriveStats()
+		if err != nil {
+			return nil, err
+		}
+		childStats[i] = childProfile
+	}
+	return p.self.DeriveStats(childStats)
+}
+
+// DeriveStats implements LogicalPlan interface, the statistic info of the only child is kept.
+func (p *baseLogicalPlan) DeriveStats(childStats []*property.StatsInfo) (*property.StatsInfo, error) {
+	if len(childStats) == 1 {
+		p.stats = childStats[0]
+		return p.stats, nil
+	}
+	profile := &property.StatsInfo{
+		RowCount:    float64(1),
+		Cardinality: make([]float64, p.self.Schema().Len()),
+	}
+	for i := range profile.Cardinality {
+		profile.Cardinality[i] = float64(1)
+	}
+	p.stats = profile
+	return profile, nil
+}
+
+// getCardinality returns the cardinality of the columns, which is the max cardinality of them.
+func getCardinality(cols []*expression.Column, schema *expression.Schema, profile *property.StatsInfo) float64 {
+	cardinality := 1.0
+	for _, idx := range schema.ColumnsIndices(cols) {
+		if idx >= 0 {
+			cardinality = math.Max(cardinality, profile.Cardinality[idx])
+		}
+	}
+	return cardinality
+}
+
+// getStatsTable gets the statistics of the table from the stats handle, they are pseudo if the table has no
+// statistics.
+func getStatsTable(ds *DataSource) *statistics.Table {
+	dom := domain.GetDomain(ds.ctx)
+	if dom == nil || dom.StatsHandle() == nil {
+		return statistics.PseudoTable(ds.tableInfo)
+	}
+	return dom.StatsHandle().GetTableStats(ds.tableInfo)
+}
+
+// initStats initializes the statistic info of the whole table.
+func (ds *DataSource) initStats() {
+	if ds.statisticTable == nil {
+		ds.statisticTable = getStatsTable(ds)
+	}
+	count := float64(ds.statisticTable.Count)
+	tableStats := &property.StatsInfo{
+		RowCount:    count,
+		Cardinality: make([]float64, ds.schema.Len()),
+	}
+	for i, col := range ds.schema.Columns {
+		if ndv, ok := ds.statisticTable.ColumnNDV(col.ID); ok {
+			tableStats.Cardinality[i] = ndv
+		} else if ds.statisticTable.ColumnIsUnique(col.ID) {
+			tableStats.Cardinality[i] = count
+		} else {
+			tableStats.Cardinality[i] = count * distinctFactor
+		}
+	}
+	ds.tableStats = tableStats
+}
+
+func (ds *DataSource) deriveStatsByFilter(conds []expression.Expression) *property.StatsInfo {
+	selectivity, err := ds.statisticTable.Selectivity(ds.ctx, conds)
+	if err != nil {
+		selectivity = selectionFactor
+	}
+	return ds.tableStats.Scale(selectivity)
+}
+
+// DeriveStats implements LogicalPlan interface, the statistic info of the access paths are derived too.
+func (ds *DataSource) DeriveStats(childStats []*property.StatsInfo) (*property.StatsInfo, error) {
+	ds.initStats()
+	ds.stats = ds.deriveStatsByFilter(ds.pushedDownConds)
+	for _, path := range ds.possibleAccessPaths {
+		var err error
+		if path.isTablePath {
+			err = ds.deriveTablePathStats(path)
+		} else {
+			err = ds.deriveIndexPathStats(path)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ds.stats, nil
+}
+
+// DeriveStats implements LogicalPlan interface.
+func (p *LogicalSelection) DeriveStats(childStats []*property.StatsInfo) (*property.StatsInfo, error) {
+	p.stats = childStats[0].Scale(selectionFactor)
+	return p.stats, nil
+}
+
+// DeriveStats implements LogicalPlan interface.
+func (p *LogicalUnionAll) DeriveStats(childStats []*property.StatsInfo) (*property.StatsInfo, error) {
+	p.stats = &property.StatsInfo{
+		Cardinality: make([]float64, p.Schema().Len()),
+	}
+	for _, childProfile := range childStats {
+		p.stats.RowCount += childProfile.RowCount
+		for i := range p.stats.Cardinality {
+			p.stats.Cardinality[i] += childProfile.Cardinality[i]
+		}
+	}
+	return p.stats, nil
+}
+
+func deriveLimitStats(childProfile *property.StatsInfo, limitCount float64) *property.StatsInfo {
+	stats := &property.StatsInfo{
+		RowCount:    math.Min(limitCount, childProfile.RowCount),
+		Cardinality: make([]float64, len(childProfile.Cardinality)),
+	}
+	for i := range stats.Cardinality {
+		stats.Cardinality[i] = math.Min(childProfile.Cardinality[i], stats.RowCount)
+	}
+	return stats
+}
+
+// DeriveStats implements LogicalPlan interface.
+func (p *LogicalLimit) DeriveStats(childStats []*property.StatsInfo) (*property.StatsInfo, error) {
+	p.stats = deriveLimitStats(childStats[0], float64(p.Count))
+	return p.stats, nil
+}
+
+// DeriveStats implements LogicalPlan interface.
+func (lt *LogicalTopN) DeriveStats(childStats []*property.StatsInfo) (*property.StatsInfo, error) {
+	lt.stats = deriveLimitStats(childStats[0], float64(lt.Count))
+	return lt.stats, nil
+}
+
+// DeriveStats implements LogicalPlan interface.
+func (p *LogicalTableDual) DeriveStats(childStats []*property.StatsInfo) (*property.StatsInfo, error) {
+	profile := &property.StatsInfo{
+		RowCount:    float64(p.RowCount),
+		Cardinality: make([]float64, p.Schema().Len()),
+	}
+	for i := range profile.Cardinality {
+		profile.Cardinality[i] = float64(p.RowCount)
+	}
+	p.stats = profile
+	return p.stats, nil
+}
+
+// DeriveStats implements LogicalPlan interface.
+func (p *LogicalMaxOneRow) DeriveStats(childStats []*property.StatsInfo) (*property.StatsInfo, error) {
+	return p.baseLogicalPlan.DeriveStats(nil)
+}
+
+// DeriveStats implements LogicalPlan interface, the window keeps the rows of the child and the result of
+// every row is distinct.
+func (p *LogicalWindow) DeriveStats(childStats []*property.StatsInfo) (*property.StatsInfo, error) {
+	childProfile := childStats[0]
+	p.stats = &property.StatsInfo{
+		RowCount:    childProfile.RowCount,
+		Cardinality: make([]float64, p.schema.Len()),
+	}
+	childLen := p.schema.Len() - len(p.WindowFuncDescs)
+	copy(p.stats.Cardinality[:childLen], childProfile.Cardinality)
+	for i := childLen; i < p.schema.Len(); i++ {
+		p.stats.Cardinality[i] = childProfile.RowCount
+	}
+	return p.stats, nil
+}
+
+// DeriveStats implements LogicalPlan interface.
+func (p *LogicalProjection) DeriveStats(childStats []*property.StatsInfo) (*property.StatsInfo, error) {
+	childProfile := childStats[0]
+	p.stats = &property.StatsInfo{
+		RowCount:    childProfile.RowCount,
+		Cardinality: make([]float64, len(p.Exprs)),
+	}
+	for i, expr := range p.Exprs {
+		cols := expression.ExtractColumns(expr)
+		p.stats.Cardinality[i] = getCardinality(cols, p.children[0].Schema(), childProfile)
+	}
+	return p.stats, nil
+}
+
+// DeriveStats implements LogicalPlan interface, the count of the groups is the cardinality of the group by
+// columns.
+func (la *LogicalAggregation) DeriveStats(childStats []*property.StatsInfo) (*property.StatsInfo, error) {
+	childProfile := childStats[0]
+	cardinality := 1.0
+	if len(la.GroupByItems) > 0 {
+		gbyCols := expression.ExtractColumnsFromExpressions(la.GroupByItems)
+		cardinality = getCardinality(gbyCols, la.children[0].Schema(), childProfile)
+	}
+	la.stats = &property.StatsInfo{
+		RowCount:    cardinality,
+		Cardinality: make([]float64, la.schema.Len()),
+	}
+	for i := range la.stats.Cardinality {
+		la.stats.Cardinality[i] = cardinality
+	}
+	return la.stats, nil
+}
+
+// DeriveStats implements LogicalPlan interface.
+// If the type of join is SemiJoin, the selectivity of it will be same as selection's.
+// If the type of join is LeftOuterSemiJoin, it will not add or remove any row. The last column is a boolean value, whose Cardinality should be two.
+// If the type of join is inner/outer join, the output of join(s, t) should be N(s) * N(t) / (V(s.key) * V(t.key)) * Min(s.key, t.key).
+// N(s) stands for the number of rows in relation s. V(s.key) means the Cardinality of join key in s.
+// This is a quite simple strategy: We assume every bucket of relation which will participate join has the same number of rows, and apply cross join for
+// every matched bucket.
+func (p *LogicalJoin) DeriveStats(childStats []*property.StatsInfo) (*property.StatsInfo, error) {
+	leftProfile, rightProfile := childStats[0], childStats[1]
+	if p.JoinType.IsSemiJoin() {
+		p.stats = p.deriveSemiJoinStats(leftProfile)
+		return p.stats, nil
+	}
+	count := leftProfile.RowCount * rightProfile.RowCount
+	leftKeys, rightKeys := p.joinKeys()
+	if len(leftKeys) > 0 {
+		leftKeyCardinality := getCardinality(leftKeys, p.children[0].Schema(), leftProfile)
+		rightKeyCardinality := getCardinality(rightKeys, p.children[1].Schema(), rightProfile)
+		count /= math.Max(leftKeyCardinality, rightKeyCardinality)
+	}
+	if p.JoinType == LeftOuterJoin {
+		count = math.Max(count, leftProfile.RowCount)
+	} else if p.JoinType == RightOuterJoin {
+		count = math.Max(count, rightProfile.RowCount)
+	}
+	p.stats = joinStats(count, leftProfile, rightProfile)
+	return p.stats, nil
+}
+
+func (p *LogicalJoin) deriveSemiJoinStats(leftProfile *property.StatsInfo) *property.StatsInfo {
+	if p.JoinType == SemiJoin || p.JoinType == AntiSemiJoin {
+		return leftProfile.Scale(selectionFactor)
+	}
+	stats := &property.StatsInfo{
+		RowCount:    leftProfile.RowCount,
+		Cardinality: make([]float64, 0, len(leftProfile.Cardinality)+1),
+	}
+	stats.Cardinality = append(stats.Cardinality, leftProfile.Cardinality...)
+	stats.Cardinality = append(stats.Cardinality, 2.0)
+	return stats
+}
+
+// joinStats makes the statistic info of the join of the count, the cardinality of a column isn't more than
+// the count.
+func joinStats(count float64, leftProfile, rightProfile *property.StatsInfo) *property.StatsInfo {
+	cardinality := make([]float64, 0, len(leftProfile.Cardinality)+len(rightProfile.Cardinality))
+	cardinality = append(cardinality, leftProfile.Cardinality...)
+	cardinality = append(cardinality, rightProfile.Cardinality...)
+	for i := range cardinality {
+		cardinality[i] = math.Min(cardinality[i], count)
+	}
+	return &property.StatsInfo{RowCount: count, Cardinality: cardinality}
+}
+
+// DeriveStats implements LogicalPlan interface, every outer row is joined with the rows of the inner child
+// evaluated by it, which is one row at most if it's a scalar sub query.
+func (la *LogicalApply) DeriveStats(childStats []*property.StatsInfo) (*property.StatsInfo, error) {
+	leftProfile, rightProfile := childStats[0], childStats[1]
+	if la.JoinType.IsSemiJoin() {
+		la.stats = la.deriveSemiJoinStats(leftProfile)
+		return la.stats, nil
+	}
+	count := leftProfile.RowCount
+	if !la.children[1].MaxOneRow() {
+		count *= rightProfile.RowCount
+	}
+	la.stats = joinStats(count, leftProfile, rightProfile)
+	return la.stats, nil
+}
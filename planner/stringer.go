@@ -0,0 +1,164 @@
+package planner
+
+import (
+	"fmt"
+	"strings"
+
+	"grant-db/expression"
+)
+
+// ToString explains a Plan, returns description string.
+func ToString(p Plan) string {
+	strs, _ := toString(p, []string{}, []int{})
+	return strings.Join(strs, "->")
+}
+
+func toString(in Plan, strs []string, idxs []int) ([]string, []int) {
+	switch in.(type) {
+	case *LogicalJoin, *LogicalApply, *LogicalUnionAll, *PhysicalHashJoin, *PhysicalMergeJoin, *PhysicalIndexJoin,
+		*PhysicalApply, *PhysicalUnionAll:
+		idxs = append(idxs, len(strs))
+	}
+	switch x := in.(type) {
+	case LogicalPlan:
+		for _, c := range x.Children() {
+			strs, idxs = toString(c, strs, idxs)
+		}
+	case PhysicalPlan:
+		for _, c := range x.Children() {
+			strs, idxs = toString(c, strs, idxs)
+		}
+	}
+	var str string
+	switch x := in.(type) {
+	case *DataSource:
+		if x.TableAsName != nil && x.TableAsName.L != "" {
+			str = fmt.Sprintf("DataScan(%s)", x.TableAsName.L)
+		} else {
+			str = fmt.Sprintf("DataScan(%s)", x.tableInfo.Name.L)
+		}
+		if len(x.pushedDownConds) > 0 {
+			str += fmt.Sprintf("%s", x.pushedDownConds)
+		}
+	case *LogicalSelection:
+		str = fmt.Sprintf("Sel(%s)", x.Conditions)
+	case *LogicalProjection:
+		str = "Projection"
+	case *LogicalAggregation:
+		str = "Aggr("
+		for i, aggFunc := range x.AggFuncs {
+			if i > 0 {
+				str += ","
+			}
+			str += aggFunc.String()
+		}
+		str += ")"
+	case *LogicalSort:
+		str = "Sort"
+	case *LogicalWindow:
+		str = fmt.Sprintf("Window(%s)", x.WindowFuncDescs)
+	case *LogicalLimit:
+		str = "Limit"
+	case *LogicalTopN:
+		str = fmt.Sprintf("TopN(%s,%d,%d)", x.ByItems, x.Offset, x.Count)
+	case *LogicalTableDual:
+		str = "Dual"
+	case *LogicalMaxOneRow:
+		str = "MaxOneRow"
+	case *LogicalJoin, *LogicalApply, *LogicalUnionAll:
+		last := len(idxs) - 1
+		idx := idxs[last]
+		children := strs[idx:]
+		strs = strs[:idx]
+		idxs = idxs[:last]
+		switch x := in.(type) {
+		case *LogicalJoin:
+			str = "Join{" + strings.Join(children, "->") + "}"
+			str += joinConditionsString(x)
+		case *LogicalApply:
+			str = "Apply{" + strings.Join(children, "->") + "}"
+			str += joinConditionsString(&x.LogicalJoin)
+		default:
+			str = "UnionAll{" + strings.Join(children, "->") + "}"
+		}
+	case *PhysicalTableScan:
+		str = fmt.Sprintf("TableScan(%s)", tableSourceName(&x.physicalTableSource))
+	case *PhysicalIndexScan:
+		str = fmt.Sprintf("IndexScan(%s.%s)", tableSourceName(&x.physicalTableSource), x.Index.Name.L)
+	case *PhysicalIndexLookUp:
+		str = fmt.Sprintf("IndexLookUp(%s, %s)", ToString(x.IndexPlan), ToString(x.TablePlan))
+	case *PointGetPlan:
+		str = fmt.Sprintf("PointGet(%s)", tableSourceName(&x.physicalTableSource))
+	case *BatchPointGetPlan:
+		str = fmt.Sprintf("BatchPointGet(%s)", tableSourceName(&x.physicalTableSource))
+	case *PhysicalTableDual:
+		str = "Dual"
+	case *PhysicalSelection:
+		str = fmt.Sprintf("Sel(%s)", x.Conditions)
+	case *PhysicalProjection:
+		str = "Projection"
+	case *PhysicalSort:
+		str = fmt.Sprintf("Sort(%s)", x.ByItems)
+	case *PhysicalTopN:
+		str = fmt.Sprintf("TopN(%s,%d,%d)", x.ByItems, x.Offset, x.Count)
+	case *PhysicalLimit:
+		str = "Limit"
+	case *PhysicalMaxOneRow:
+		str = "MaxOneRow"
+	case *PhysicalHashAgg:
+		str = "HashAgg"
+	case *PhysicalStreamAgg:
+		str = "StreamAgg"
+	case *PhysicalWindow:
+		str = fmt.Sprintf("Window(%s)", x.WindowFuncDescs)
+	case *PhysicalHashJoin, *PhysicalMergeJoin, *PhysicalIndexJoin, *PhysicalApply, *PhysicalUnionAll:
+		last := len(idxs) - 1
+		idx := idxs[last]
+		children := strs[idx:]
+		strs = strs[:idx]
+		idxs = idxs[:last]
+		switch x := in.(type) {
+		case *PhysicalHashJoin:
+			str = "HashJoin{" + strings.Join(children, "->") + "}"
+			str += physicalJoinKeysString(&x.basePhysicalJoin)
+		case *PhysicalMergeJoin:
+			str = "MergeJoin{" + strings.Join(children, "->") + "}"
+			str += physicalJoinKeysString(&x.basePhysicalJoin)
+		case *PhysicalIndexJoin:
+			str = "IndexJoin{" + strings.Join(children, "->") + "}"
+			str += physicalJoinKeysString(&x.basePhysicalJoin)
+		case *PhysicalApply:
+			str = "Apply{" + strings.Join(children, "->") + "}"
+		default:
+			str = "UnionAll{" + strings.Join(children, "->") + "}"
+		}
+	default:
+		str = fmt.Sprintf("%T", in)
+	}
+	strs = append(strs, str)
+	return strs, idxs
+}
+
+func joinConditionsString(p *LogicalJoin) string {
+	var conds []expression.Expression
+	conds = append(conds, expression.ScalarFuncs2Exprs(p.EqualConditions)...)
+	conds = append(conds, p.OtherConditions...)
+	if len(conds) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("(%s)", conds)
+}
+
+func tableSourceName(ts *physicalTableSource) string {
+	if ts.TableAsName != nil && ts.TableAsName.L != "" {
+		return ts.TableAsName.L
+	}
+	return ts.TableInfo.Name.L
+}
+
+func physicalJoinKeysString(p *basePhysicalJoin) string {
+	if len(p.LeftJoinKeys) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("(%s,%s)", p.LeftJoinKeys, p.RightJoinKeys)
+}
@@ -0,0 +1,263 @@
+package planner
+
+import (
+	"math"
+)
+
+// The factors of the cost model, the cost of a plan is the sum of the costs of its operators.
+const (
+	// cpuFactor is the cost of evaluating a row.
+	cpuFactor = 3.0
+	// scanFactor is the cost of scanning a byte of the storage in the ascending order.
+	scanFactor = 1.5
+	// descScanFactor is the cost of scanning a byte of the storage in the descending order.
+	descScanFactor = 3.0
+	// seekFactor is the cost of seeking the start of a range in the storage.
+	seekFactor = 20.0
+	// memoryFactor is the cost of keeping a row in the memory.
+	memoryFactor = 0.001
+	// columnSize is the estimated size of a column of a row.
+	columnSize = 8.0
+)
+
+// task is the physical plan of a logical plan with its cost.
+type task interface {
+	count() float64
+	addCost(cost float64)
+	cost() float64
+	copy() task
+	plan() PhysicalPlan
+	invalid() bool
+}
+
+// rootTask is the task whose plan is executed by the executors of the session.
+type rootTask struct {
+	p   PhysicalPlan
+	cst float64
+}
+
+// invalidTask means the required property can't be satisfied.
+var invalidTask = &rootTask{cst: math.MaxFloat64}
+
+func (t *rootTask) invalid() bool {
+	return t.p == nil
+}
+
+func (t *rootTask) copy() task {
+	return &rootTask{
+		p:   t.p,
+		cst: t.cst,
+	}
+}
+
+func (t *rootTask) count() float64 {
+	return t.p.statsInfo().RowCount
+}
+
+func (t *rootTask) addCost(cst float64) {
+	t.cst += cst
+}
+
+func (t *rootTask) cost() float64 {
+	return t.cst
+}
+
+func (t *rootTask) plan() PhysicalPlan {
+	return t.p
+}
+
+// attachPlan2Task makes the plan the parent of the plan of the task.
+func attachPlan2Task(p PhysicalPlan, t task) task {
+	nt := t.copy().(*rootTask)
+	p.SetChildren(nt.p)
+	nt.p = p
+	return nt
+}
+
+// attach2Task implements PhysicalPlan interface, the plan costs nothing by default.
+func (p *basePhysicalPlan) attach2Task(tasks ...task) task {
+	return attachPlan2Task(p.self, tasks[0])
+}
+
+func (p *PhysicalSelection) attach2Task(tasks ...task) task {
+	t := attachPlan2Task(p, tasks[0])
+	t.addCost(tasks[0].count() * cpuFactor)
+	return t
+}
+
+func (p *PhysicalProjection) attach2Task(tasks ...task) task {
+	t := attachPlan2Task(p, tasks[0])
+	t.addCost(tasks[0].count() * cpuFactor)
+	return t
+}
+
+// GetCost computes the cost of in memory sort.
+func (p *PhysicalSort) GetCost(count float64) float64 {
+	if count < 2.0 {
+		count = 2.0
+	}
+	return count*math.Log2(count)*cpuFactor + count*memoryFactor
+}
+
+func (p *PhysicalSort) attach2Task(tasks ...task) task {
+	t := attachPlan2Task(p, tasks[0])
+	t.addCost(p.GetCost(tasks[0].count()))
+	return t
+}
+
+// attach2Task implements PhysicalPlan interface, the nominal sort is removed and the task of the child is
+// returned.
+func (p *NominalSort) attach2Task(tasks ...task) task {
+	return tasks[0]
+}
+
+// GetCost computes the cost of the top-n by a heap of the offset and the count.
+func (p *PhysicalTopN) GetCost(count float64) float64 {
+	heapSize := float64(p.Offset + p.Count)
+	if heapSize < 2.0 {
+		heapSize = 2.0
+	}
+	return count*math.Log2(heapSize)*cpuFactor + heapSize*memoryFactor
+}
+
+func (p *PhysicalTopN) attach2Task(tasks ...task) task {
+	t := attachPlan2Task(p, tasks[0])
+	t.addCost(p.GetCost(tasks[0].count()))
+	return t
+}
+
+func (p *PhysicalUnionAll) attach2Task(tasks ...task) task {
+	t := &rootTask{p: p}
+	childPlans := make([]PhysicalPlan, 0, len(tasks))
+	for _, task := range tasks {
+		childPlans = append(childPlans, task.plan())
+		t.cst += task.cost()
+	}
+	p.SetChildren(childPlans...)
+	return t
+}
+
+// GetCost computes the cost of the hash aggregation, the groups are kept in the hash table.
+func (p *PhysicalHashAgg) GetCost(inputRows float64) float64 {
+	cardinality := p.statsInfo().RowCount
+	aggFuncFactor := float64(len(p.AggFuncs) + 1)
+	cpuCost := inputRows * cpuFactor * aggFuncFactor
+	// Each row is hashed by the group by items.
+	cpuCost += inputRows * cpuFactor
+	memoryCost := cardinality * memoryFactor * float64(len(p.AggFuncs))
+	return cpuCost + memoryCost
+}
+
+func (p *PhysicalHashAgg) attach2Task(tasks ...task) task {
+	t := attachPlan2Task(p, tasks[0])
+	t.addCost(p.GetCost(tasks[0].count()))
+	return t
+}
+
+// GetCost computes the cost of the stream aggregation.
+func (p *PhysicalStreamAgg) GetCost(inputRows float64) float64 {
+	aggFuncFactor := float64(len(p.AggFuncs) + 1)
+	return inputRows * cpuFactor * aggFuncFactor
+}
+
+func (p *PhysicalStreamAgg) attach2Task(tasks ...task) task {
+	t := attachPlan2Task(p, tasks[0])
+	t.addCost(p.GetCost(tasks[0].count()))
+	return t
+}
+
+// GetCost computes the cost of the window, the rows of a partition are kept in the memory.
+func (p *PhysicalWindow) GetCost(inputRows float64) float64 {
+	windowFuncFactor := float64(len(p.WindowFuncDescs) + 1)
+	return inputRows*cpuFactor*windowFuncFactor + inputRows*memoryFactor
+}
+
+func (p *PhysicalWindow) attach2Task(tasks ...task) task {
+	t := attachPlan2Task(p, tasks[0])
+	t.addCost(p.GetCost(tasks[0].count()))
+	return t
+}
+
+// joinTask makes the task of the join from the tasks of the children.
+func joinTask(p PhysicalPlan, lTask, rTask task, cost float64) task {
+	p.SetChildren(lTask.plan(), rTask.plan())
+	return &rootTask{
+		p:   p,
+		cst: lTask.cost() + rTask.cost() + cost,
+	}
+}
+
+// GetCost computes the cost of the hash join, the hash table is built by the rows of the inner child.
+func (p *PhysicalHashJoin) GetCost(lCnt, rCnt float64) float64 {
+	buildCnt, probeCnt := lCnt, rCnt
+	if p.InnerChildIdx == 1 {
+		buildCnt, probeCnt = rCnt, lCnt
+	}
+	cpuCost := buildCnt * cpuFactor
+	memoryCost := buildCnt * memoryFactor
+	cpuCost += probeCnt * cpuFactor
+	// The pairs of the rows of the same keys are evaluated by the other conditions.
+	if len(p.OtherConditions) > 0 {
+		cpuCost += p.StatsCount() * cpuFactor
+	}
+	return cpuCost + memoryCost
+}
+
+func (p *PhysicalHashJoin) attach2Task(tasks ...task) task {
+	return joinTask(p, tasks[0], tasks[1], p.GetCost(tasks[0].count(), tasks[1].count()))
+}
+
+// GetCost computes the cost of the merge join.
+func (p *PhysicalMergeJoin) GetCost(lCnt, rCnt float64) float64 {
+	cpuCost := (lCnt + rCnt) * cpuFactor
+	if len(p.OtherConditions) > 0 {
+		cpuCost += p.StatsCount() * cpuFactor
+	}
+	return cpuCost
+}
+
+func (p *PhysicalMergeJoin) attach2Task(tasks ...task) task {
+	return joinTask(p, tasks[0], tasks[1], p.GetCost(tasks[0].count(), tasks[1].count()))
+}
+
+// GetCost computes the cost of the index join, the inner task is the lookup of one outer row.
+func (p *PhysicalIndexJoin) GetCost(outerTask, innerTask task) float64 {
+	outerCnt := outerTask.count()
+	// The ranges are built from the outer rows, and the inner rows are matched by the keys.
+	cpuCost := outerCnt * cpuFactor
+	cpuCost += outerCnt * innerTask.count() * cpuFactor
+	innerCost := outerCnt * innerTask.cost()
+	memoryCost := outerCnt * memoryFactor
+	return cpuCost + innerCost + memoryCost
+}
+
+// attach2Task implements PhysicalPlan interface, the task of the inner child is replaced by the inner task
+// built from the inner table.
+func (p *PhysicalIndexJoin) attach2Task(tasks ...task) task {
+	outerTask := tasks[1-p.InnerChildIdx]
+	children := make([]PhysicalPlan, 2)
+	children[1-p.InnerChildIdx], children[p.InnerChildIdx] = outerTask.plan(), p.innerTask.plan()
+	p.SetChildren(children...)
+	return &rootTask{
+		p:   p,
+		cst: outerTask.cost() + p.GetCost(outerTask, p.innerTask),
+	}
+}
+
+// GetCost computes the cost of the apply, the inner child is evaluated for every outer row.
+func (p *PhysicalApply) GetCost(lCount, rCount, rCost float64) float64 {
+	cpuCost := lCount * rCount * cpuFactor
+	if len(p.OtherConditions) > 0 || len(p.EqualConditions) > 0 {
+		cpuCost += p.StatsCount() * cpuFactor
+	}
+	return cpuCost + lCount*rCost
+}
+
+func (p *PhysicalApply) attach2Task(tasks ...task) task {
+	lTask, rTask := tasks[0], tasks[1]
+	p.SetChildren(lTask.plan(), rTask.plan())
+	return &rootTask{
+		p:   p,
+		cst: lTask.cost() + p.GetCost(lTask.count(), rTask.count(), rTask.cost()),
+	}
+}
@@ -15,3 +15,29 @@ type ColumnInfo struct {
 	DefaultValueLength uint64
 	DefaultValue       []byte
 }
+
+// Dump dumps ColumnInfo to bytes.
+func (column *ColumnInfo) Dump(buffer []byte) []byte {
+	buffer = dumpLengthEncodedString(buffer, []byte("def"))
+	buffer = dumpLengthEncodedString(buffer, []byte(column.Schema))
+	buffer = dumpLengthEncodedString(buffer, []byte(column.Table))
+	buffer = dumpLengthEncodedString(buffer, []byte(column.OrgTable))
+	buffer = dumpLengthEncodedString(buffer, []byte(column.Name))
+	buffer = dumpLengthEncodedString(buffer, []byte(column.OrgName))
+
+	buffer = append(buffer, 0x0c)
+
+	buffer = dumpUint16(buffer, column.Charset)
+	buffer = dumpUint32(buffer, column.ColumnLength)
+	buffer = append(buffer, column.Type)
+	buffer = dumpUint16(buffer, column.Flag)
+	buffer = append(buffer, column.Decimal)
+	buffer = append(buffer, 0, 0)
+
+	if column.DefaultValue != nil {
+		buffer = dumpUint64(buffer, uint64(len(column.DefaultValue)))
+		buffer = append(buffer, column.DefaultValue...)
+	}
+
+	return buffer
+}
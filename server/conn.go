@@ -5,14 +5,23 @@ import (
 	"context"
 	"encoding/binary"
 	"errors"
+	perrors "github.com/pingcap/errors"
 	"github.com/pingcap/parser/ast"
+	pmysql "github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
 	"grant-db/mysql"
+	"grant-db/sessionctx/variable"
+	"grant-db/types"
+	"grant-db/util/charset"
+	"grant-db/util/chunk"
 	"grant-db/util/customrand"
 	"grant-db/util/hack"
 	"io"
 	"log"
+	"math"
 	"math/rand"
 	"net"
+	"strconv"
 	"time"
 )
 
@@ -58,6 +67,7 @@ func (cc *clientConn) run(ctx context.Context) {
 		data, err := cc.readPacket()
 		if err != nil {
 			log.Printf("[connection:%d] read packet error:%s\n", cc.connectionID, err.Error())
+			return
 		}
 
 		if err := cc.dispatch(ctx, data); err != nil {
@@ -65,18 +75,27 @@ func (cc *clientConn) run(ctx context.Context) {
 				log.Println("client exit")
 				return
 			}
-			log.Println("connection quit, dispatch error:", err.Error())
-			break
+			log.Printf("[connection:%d] dispatch error:%s\n", cc.connectionID, err.Error())
+			if err := cc.writeError(ctx, err); err != nil {
+				log.Println("connection quit, write error:", err.Error())
+				break
+			}
 		}
 		cc.pkt.sequence = 0
 	}
 }
 
+// handleStmt executes the statement and writes its result set or an OK packet to the client.
 func (cc *clientConn) handleStmt(ctx context.Context, stmt ast.StmtNode, warns interface{}, last bool) error {
-
-	cc.ctx.ExecuteStmt(ctx, stmt)
-
-	return nil
+	rs, err := cc.ctx.ExecuteStmt(ctx, stmt)
+	if err != nil {
+		return err
+	}
+	if rs == nil {
+		return cc.writeOkWith(ctx, cc.ctx.LastMessage(), cc.ctx.AffectedRows(), cc.ctx.LastInsertID(), cc.ctx.Status(), cc.ctx.WarningCount())
+	}
+	defer terror.Call(rs.Close)
+	return cc.writeResultset(ctx, rs)
 }
 
 func (cc *clientConn) handleQuery(ctx context.Context, sql string) error {
@@ -91,11 +110,7 @@ func (cc *clientConn) handleQuery(ctx context.Context, sql string) error {
 	}
 	// current only support single-statement
 	stmt := stmts[0]
-	if err := cc.handleStmt(ctx, stmt, nil, true); err != nil {
-		return err
-	}
-
-	return cc.writeOk(ctx)
+	return cc.handleStmt(ctx, stmt, nil, true)
 }
 
 func (cc *clientConn) dispatch(ctx context.Context, data []byte) error {
@@ -105,17 +120,58 @@ func (cc *clientConn) dispatch(ctx context.Context, data []byte) error {
 	switch cmd {
 	case mysql.CmdQuit:
 		return io.EOF
+	case mysql.CmdInitDB:
+		return cc.useDB(ctx, cc.decodeClientString(data))
 	case mysql.CmdQuery:
 		if len(data) > 0 && data[len(data)-1] == 0 {
 			data = data[:len(data)-1]
 		}
-		cmdStr := string(hack.String(data))
+		cmdStr := cc.decodeClientString(data)
 		log.Println("handle sql: ", cmdStr)
 		return cc.handleQuery(ctx, cmdStr)
 	}
 	return nil
 }
 
+// decodeClientString converts the string sent by the client in character_set_client to UTF-8.
+func (cc *clientConn) decodeClientString(data []byte) string {
+	cs, _ := cc.ctx.GetSessionVars().GetSystemVar(variable.CharacterSetClient)
+	enc := charset.NewEncoding(cs)
+	if enc.IsIdentity() {
+		return string(hack.String(data))
+	}
+	return enc.Decode(data)
+}
+
+// resultEncoding returns the encoding of character_set_results, nil means the strings are sent as they are.
+func (cc *clientConn) resultEncoding() *charset.Encoding {
+	cs, _ := cc.ctx.GetSessionVars().GetSystemVar(variable.CharacterSetResults)
+	if cs == "" {
+		return nil
+	}
+	return charset.NewEncoding(cs)
+}
+
+// serverCollationID returns the ID of the default collation of the server, which is sent in the handshake.
+func serverCollationID() uint8 {
+	name, _ := variable.GetSysVarDefault(variable.CollationServer)
+	coll, err := charset.GetCollationByName(name)
+	if err != nil {
+		return pmysql.DefaultCollationID
+	}
+	return uint8(coll.ID)
+}
+
+func (cc *clientConn) useDB(ctx context.Context, db string) error {
+	// if input is "use `SELECT`", mysql client just send "SELECT"
+	// so we add `` around db.
+	stmts, err := cc.ctx.Parse(ctx, "use `"+db+"`")
+	if err != nil {
+		return err
+	}
+	return cc.handleStmt(ctx, stmts[0], nil, true)
+}
+
 func (cc *clientConn) authSwitchRequest(ctx context.Context) ([]byte, error) {
 	len := 1 + len("mysql_native_password") + 1 + len(cc.salt) + 1
 	data := make([]byte, 4, len)
@@ -151,6 +207,9 @@ func (cc *clientConn) handshake(ctx context.Context) error {
 	// 2. Login Authentication
 	// Client -> Server
 	if err := cc.readOptionalSSLRequestAndHandshakeResponse(ctx); err != nil {
+		if writeErr := cc.writeError(ctx, err); writeErr != nil {
+			log.Println("write handshake error:", writeErr.Error())
+		}
 		return err
 	}
 	// 3. Response Authentication Result
@@ -192,8 +251,7 @@ func (cc *clientConn) writeInitialHandshake(ctx context.Context) error {
 	// [2] capability flag (lower 2bytes)
 	data = append(data, byte(cc.server.capability), byte(cc.server.capability>>8))
 	// [1] character set
-	// 46 default utf8mb4_bin
-	data = append(data, uint8(46))
+	data = append(data, serverCollationID())
 	// [2] status flags
 	AutoCommitStatus := int16(0x0002)
 	data = append(data, byte(AutoCommitStatus), byte(AutoCommitStatus>>8))
@@ -270,7 +328,7 @@ func (cc *clientConn) openSessionAndDoAuth(auth []byte) error {
 	var err error
 	cc.ctx, err = cc.server.driver.OpenCtx(cc.connectionID, cc.capability, cc.collation, cc.dbname, nil)
 	if err != nil {
-		return nil
+		return err
 	}
 
 	return nil
@@ -281,7 +339,7 @@ func (cc *clientConn) writePacket(data []byte) error {
 }
 
 func (cc *clientConn) writeOk(ctx context.Context) error {
-	return cc.writeOkWith(ctx, "", 0, 0, cc.ctx.Status(), 0)
+	return cc.writeOkWith(ctx, "", 0, 0, cc.ctx.Status(), cc.ctx.WarningCount())
 }
 
 func (cc *clientConn) writeOkWith(ctx context.Context, msg string, affectedRows, lastInsertID uint64, status, warnCnt uint16) error {
@@ -291,6 +349,9 @@ func (cc *clientConn) writeOkWith(ctx context.Context, msg string, affectedRows,
 	data = dumpLengthEncodedInt(data, lastInsertID)
 	data = dumpUint16(data, status)
 	data = dumpUint16(data, warnCnt)
+	if msg != "" {
+		data = dumpLengthEncodedString(data, []byte(msg))
+	}
 
 	err := cc.writePacket(data)
 	if err != nil {
@@ -300,6 +361,100 @@ func (cc *clientConn) writeOkWith(ctx context.Context, msg string, affectedRows,
 	return cc.flush(ctx)
 }
 
+// writeError writes an ERR packet, errors without a MySQL error code are reported as ER_UNKNOWN_ERROR.
+func (cc *clientConn) writeError(ctx context.Context, e error) error {
+	var m *mysql.SQLError
+	switch y := perrors.Cause(e).(type) {
+	case *terror.Error:
+		m = y.ToSQLError()
+	case *mysql.SQLError:
+		m = y
+	default:
+		m = mysql.NewErrf(mysql.ErrUnknown, "%s", e.Error())
+	}
+
+	data := make([]byte, 4, 16+len(m.Message))
+	data = append(data, mysql.ErrHeader)
+	data = dumpUint16(data, m.Code)
+	data = append(data, '#')
+	data = append(data, m.State...)
+	data = append(data, m.Message...)
+
+	err := cc.writePacket(data)
+	if err != nil {
+		return err
+	}
+	return cc.flush(ctx)
+}
+
+// writeResultset writes the column definitions and the text rows of rs, the rows are
+// fetched chunk by chunk until an empty chunk is returned.
+func (cc *clientConn) writeResultset(ctx context.Context, rs ResultSet) error {
+	req := rs.NewChunk()
+	gotColumnInfo := false
+	enc := cc.resultEncoding()
+	var data []byte
+	for {
+		if err := rs.Next(ctx, req); err != nil {
+			return err
+		}
+		if !gotColumnInfo {
+			columns := rs.Columns()
+			if enc != nil {
+				setResultCharset(columns, enc.Name())
+			}
+			if err := cc.writeColumnInfo(columns); err != nil {
+				return err
+			}
+			gotColumnInfo = true
+		}
+		rowCount := req.NumRows()
+		if rowCount == 0 {
+			break
+		}
+		for i := 0; i < rowCount; i++ {
+			data = data[:0]
+			data = append(data, 0, 0, 0, 0)
+			var err error
+			data, err = dumpTextRow(data, rs.Columns(), req.GetRow(i), enc)
+			if err != nil {
+				return err
+			}
+			if err = cc.writePacket(data); err != nil {
+				return err
+			}
+		}
+	}
+	if err := cc.writeEOF(cc.ctx.WarningCount()); err != nil {
+		return err
+	}
+	return cc.flush(ctx)
+}
+
+func (cc *clientConn) writeColumnInfo(columns []*ColumnInfo) error {
+	data := make([]byte, 4, 1024)
+	data = dumpLengthEncodedInt(data, uint64(len(columns)))
+	if err := cc.writePacket(data); err != nil {
+		return err
+	}
+	for _, v := range columns {
+		data = data[0:4]
+		data = v.Dump(data)
+		if err := cc.writePacket(data); err != nil {
+			return err
+		}
+	}
+	return cc.writeEOF(0)
+}
+
+func (cc *clientConn) writeEOF(warnCnt uint16) error {
+	data := make([]byte, 4, 9)
+	data = append(data, mysql.EOFHeader)
+	data = dumpUint16(data, warnCnt)
+	data = dumpUint16(data, cc.ctx.Status())
+	return cc.writePacket(data)
+}
+
 func (cc *clientConn) readPacket() ([]byte, error) {
 	return cc.pkt.readPacket()
 }
@@ -427,6 +582,12 @@ func dumpLengthEncodedInt(buffer []byte, n uint64) []byte {
 	return buffer
 }
 
+func dumpLengthEncodedString(buffer []byte, bytes []byte) []byte {
+	buffer = dumpLengthEncodedInt(buffer, uint64(len(bytes)))
+	buffer = append(buffer, bytes...)
+	return buffer
+}
+
 func dumpUint16(buffer []byte, n uint16) []byte {
 	buffer = append(buffer, byte(n))
 	buffer = append(buffer, byte(n>>8))
@@ -452,3 +613,95 @@ func dumpUint64(buffer []byte, n uint64) []byte {
 	buffer = append(buffer, byte(n>>56))
 	return buffer
 }
+
+// setResultCharset sets the charset of the text columns to the one of character_set_results.
+func setResultCharset(columns []*ColumnInfo, cs string) {
+	id := uint16(pmysql.CharsetNameToID(cs))
+	for _, col := range columns {
+		if col.Charset != pmysql.BinaryDefaultCollationID {
+			col.Charset = id
+		}
+	}
+}
+
+// dumpTextRow dumps a row in the text protocol, NULL is written as 0xfb. The strings of the text columns
+// are converted to the charset of enc if it's not nil.
+func dumpTextRow(buffer []byte, columns []*ColumnInfo, row chunk.Row, enc *charset.Encoding) ([]byte, error) {
+	tmp := make([]byte, 0, 20)
+	for i, col := range columns {
+		if row.IsNull(i) {
+			buffer = append(buffer, 0xfb)
+			continue
+		}
+		switch col.Type {
+		case pmysql.TypeTiny, pmysql.TypeShort, pmysql.TypeInt24, pmysql.TypeLong, pmysql.TypeLonglong, pmysql.TypeYear:
+			tmp = tmp[:0]
+			if pmysql.HasUnsignedFlag(uint(col.Flag)) {
+				tmp = strconv.AppendUint(tmp, row.GetUint64(i), 10)
+			} else {
+				tmp = strconv.AppendInt(tmp, row.GetInt64(i), 10)
+			}
+			buffer = dumpLengthEncodedString(buffer, tmp)
+		case pmysql.TypeFloat:
+			prec := -1
+			if col.Decimal > 0 && int(col.Decimal) != pmysql.NotFixedDec {
+				prec = int(col.Decimal)
+			}
+			tmp = appendFormatFloat(tmp[:0], float64(row.GetFloat32(i)), prec, 32)
+			buffer = dumpLengthEncodedString(buffer, tmp)
+		case pmysql.TypeDouble:
+			prec := -1
+			if col.Decimal > 0 && int(col.Decimal) != pmysql.NotFixedDec {
+				prec = int(col.Decimal)
+			}
+			tmp = appendFormatFloat(tmp[:0], row.GetFloat64(i), prec, 64)
+			buffer = dumpLengthEncodedString(buffer, tmp)
+		case pmysql.TypeNewDecimal:
+			buffer = dumpLengthEncodedString(buffer, []byte(row.GetMyDecimal(i).String()))
+		case pmysql.TypeDate, pmysql.TypeDatetime, pmysql.TypeTimestamp:
+			buffer = dumpLengthEncodedString(buffer, []byte(row.GetTime(i).String()))
+		case pmysql.TypeDuration:
+			dur := row.GetDuration(i, int(col.Decimal))
+			buffer = dumpLengthEncodedString(buffer, []byte(dur.String()))
+		case pmysql.TypeBit:
+			// A BIT value is sent as the bytes in big endian, the length is the display width in bits.
+			bit := types.NewBinaryLiteralFromUint(row.GetUint64(i), int(col.ColumnLength+7)>>3)
+			buffer = dumpLengthEncodedString(buffer, bit)
+		case pmysql.TypeJSON:
+			buffer = dumpLengthEncodedString(buffer, []byte(row.GetJSON(i).String()))
+		default:
+			if enc != nil && !enc.IsIdentity() && col.Charset != pmysql.BinaryDefaultCollationID {
+				tmp = enc.Encode(tmp[:0], string(hack.String(row.GetBytes(i))))
+				buffer = dumpLengthEncodedString(buffer, tmp)
+				break
+			}
+			buffer = dumpLengthEncodedString(buffer, row.GetBytes(i))
+		}
+	}
+	return buffer, nil
+}
+
+const (
+	expFormatBig   = 1e15
+	expFormatSmall = 1e-15
+)
+
+// appendFormatFloat formats the float like MySQL, the exponent format is used for
+// very large or very small values.
+func appendFormatFloat(in []byte, fVal float64, prec, bitSize int) []byte {
+	absVal := math.Abs(fVal)
+	var out []byte
+	if prec == -1 && (absVal >= expFormatBig || (absVal != 0 && absVal < expFormatSmall)) {
+		out = strconv.AppendFloat(in, fVal, 'e', prec, bitSize)
+		valStr := out[len(in):]
+		// remove the '+' from the string for compatibility.
+		plusPos := bytes.IndexByte(valStr, '+')
+		if plusPos > 0 {
+			plusPosInOut := len(in) + plusPos
+			out = append(out[:plusPosInOut], out[plusPosInOut+1:]...)
+		}
+	} else {
+		out = strconv.AppendFloat(in, fVal, 'f', prec, bitSize)
+	}
+	return out
+}
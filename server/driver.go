@@ -1,14 +1,21 @@
 package server
 
+import (
+	"context"
+
+	"grant-db/util/chunk"
+)
+
 // IDriver opens IContext
 type IDriver interface {
 	// OpenCtx opens an IContext with connection id , client capability, collation ,dbname and optionally the tls state
 	OpenCtx(connID int64, capability uint32, collation uint8, dbname string, tlsState interface{}) (*GrantDBContext, error)
 }
 
+// ResultSet is the result set of an query.
 type ResultSet interface {
-	//Columns() []*ColumnInfo
-	//NewChunk() *chunk.Chunk
-	//Next(context.Context, *chunk.Chunk) error
-	//Close() error
+	Columns() []*ColumnInfo
+	NewChunk() *chunk.Chunk
+	Next(context.Context, *chunk.Chunk) error
+	Close() error
 }
@@ -2,9 +2,19 @@ package server
 
 import (
 	"context"
+	"unicode/utf8"
+
 	"github.com/pingcap/parser/ast"
+	pcharset "github.com/pingcap/parser/charset"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
 	"grant-db/kv"
 	"grant-db/session"
+	"grant-db/sessionctx/variable"
+	"grant-db/types"
+	"grant-db/util/charset"
+	"grant-db/util/chunk"
+	"grant-db/util/sqlexec"
 )
 
 // GrantDBDriver implements IDriver
@@ -20,13 +30,33 @@ func (qd *GrantDBDriver) OpenCtx(connID int64, capability uint32, collation uint
 	}
 	s.SetClientCapability(capability)
 	s.SetConnectionID(uint64(connID))
+	setClientCollation(s.GetSessionVars(), collation)
 	ctx := &GrantDBContext{
-		Session:   s,
-		currentDB: dbname,
+		Session: s,
+	}
+	if dbname != "" {
+		if _, err := ctx.ExecuteStmt(context.Background(), &ast.UseStmt{DBName: dbname}); err != nil {
+			return nil, err
+		}
 	}
 	return ctx, nil
 }
 
+// setClientCollation sets the charsets of the session to the ones of the collation sent by the client in the
+// handshake, the default collation of the charset is used if the collation is not supported, and the session
+// keeps the default charset of the server if the charset is not supported either.
+func setClientCollation(vars *variable.SessionVars, collation uint8) {
+	if coll, err := charset.GetCollationByID(int(collation)); err == nil {
+		terror.Log(vars.SetNames(coll.CharsetName, coll.Name))
+		return
+	}
+	if cs, _, err := pcharset.GetCharsetInfoByID(int(collation)); err == nil {
+		if _, err = charset.GetCharsetInfo(cs); err == nil {
+			terror.Log(vars.SetNames(cs, ""))
+		}
+	}
+}
+
 // NewGrantDBDriver create a new GrantDBDriver
 func NewGrantDBDriver(store kv.Storage) *GrantDBDriver {
 	return &GrantDBDriver{
@@ -42,7 +72,20 @@ type GrantDBContext struct {
 }
 
 func (tc *GrantDBContext) ExecuteStmt(ctx context.Context, stmt ast.StmtNode) (ResultSet, error) {
-	return nil, nil
+	rs, err := tc.Session.ExecuteStmt(ctx, stmt)
+	tc.currentDB = tc.GetSessionVars().CurrentDB
+	if err != nil {
+		return nil, err
+	}
+	if rs == nil {
+		return nil, nil
+	}
+	return &grantResultSet{recordSet: rs}, nil
+}
+
+// WarningCount returns the number of the warnings of the last statement.
+func (tc *GrantDBContext) WarningCount() uint16 {
+	return tc.GetSessionVars().StmtCtx.WarningCount()
 }
 
 type GrantDBStatement struct {
@@ -56,4 +99,129 @@ type GrantDBStatement struct {
 }
 
 type grantResultSet struct {
+	recordSet sqlexec.RecordSet
+	columns   []*ColumnInfo
+	closed    bool
+}
+
+func (trs *grantResultSet) NewChunk() *chunk.Chunk {
+	return trs.recordSet.NewChunk()
+}
+
+func (trs *grantResultSet) Next(ctx context.Context, req *chunk.Chunk) error {
+	return trs.recordSet.Next(ctx, req)
+}
+
+func (trs *grantResultSet) Close() error {
+	if trs.closed {
+		return nil
+	}
+	trs.closed = true
+	return trs.recordSet.Close()
+}
+
+func (trs *grantResultSet) Columns() []*ColumnInfo {
+	if trs.columns == nil {
+		fields := trs.recordSet.Fields()
+		for _, v := range fields {
+			trs.columns = append(trs.columns, convertColumnInfo(v))
+		}
+	}
+	return trs.columns
+}
+
+func convertColumnInfo(fld *ast.ResultField) (ci *ColumnInfo) {
+	ci = &ColumnInfo{
+		Name:    fld.ColumnAsName.O,
+		OrgName: fld.Column.Name.O,
+		Table:   fld.TableAsName.O,
+		Schema:  fld.DBName.O,
+		Flag:    uint16(fld.Column.Flag),
+		Charset: uint16(mysql.CharsetNameToID(fld.Column.Charset)),
+		Type:    fld.Column.Tp,
+	}
+	if fld.Table != nil {
+		ci.OrgTable = fld.Table.Name.O
+	}
+	if fld.Column.Flen == types.UnspecifiedLength {
+		ci.ColumnLength = 0
+	} else {
+		ci.ColumnLength = uint32(fld.Column.Flen)
+	}
+	if fld.Column.Tp == mysql.TypeNewDecimal {
+		// Consider the negative sign.
+		ci.ColumnLength++
+		if fld.Column.Decimal > int(types.DefaultFsp) {
+			// Consider the decimal point.
+			ci.ColumnLength++
+		}
+	} else if types.IsString(fld.Column.Tp) || fld.Column.Tp == mysql.TypeEnum || fld.Column.Tp == mysql.TypeSet {
+		if fld.Column.Tp == mysql.TypeEnum || fld.Column.Tp == mysql.TypeSet {
+			ci.ColumnLength = uint32(elemsDisplayLength(&fld.Column.FieldType))
+		}
+		// The flen is a hint counted in characters, some clients truncate the values by the byte length,
+		// so multiply it by the max bytes of a character in the charset.
+		charsetDesc, err := charset.GetCharsetInfo(fld.Column.Charset)
+		if err != nil {
+			ci.ColumnLength = ci.ColumnLength * 4
+		} else {
+			ci.ColumnLength = ci.ColumnLength * uint32(charsetDesc.Maxlen)
+		}
+	}
+
+	if fld.Column.Tp == mysql.TypeJSON {
+		// A JSON value is sent as its text in a binary LONGBLOB, refer to Field_json in MySQL.
+		ci.Charset = uint16(mysql.CharsetNameToID(pcharset.CharsetBin))
+		ci.Flag |= uint16(mysql.BinaryFlag | mysql.BlobFlag)
+		ci.ColumnLength = 1<<32 - 1
+	}
+
+	switch fld.Column.Tp {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong,
+		mysql.TypeYear, mysql.TypeBit, mysql.TypeDate, mysql.TypeEnum, mysql.TypeSet, mysql.TypeJSON:
+		// These types have no fraction part.
+		ci.Decimal = 0
+	case mysql.TypeDuration, mysql.TypeDatetime, mysql.TypeTimestamp:
+		ci.Decimal = uint8(types.DefaultFsp)
+		if fld.Column.Decimal != types.UnspecifiedLength {
+			ci.Decimal = uint8(fld.Column.Decimal)
+		}
+	default:
+		ci.Decimal = mysql.NotFixedDec
+		if fld.Column.Decimal != types.UnspecifiedLength {
+			ci.Decimal = uint8(fld.Column.Decimal)
+		}
+	}
+
+	// Keep things compatible for old clients.
+	// Refer to mysql-server/sql/protocol.cc send_result_set_metadata()
+	switch ci.Type {
+	case mysql.TypeVarchar:
+		ci.Type = mysql.TypeVarString
+	case mysql.TypeEnum:
+		// ENUM and SET are sent as CHAR with the flag of the type, refer to Field_enum::type().
+		ci.Type, ci.Flag = mysql.TypeString, ci.Flag|uint16(mysql.EnumFlag)
+	case mysql.TypeSet:
+		ci.Type, ci.Flag = mysql.TypeString, ci.Flag|uint16(mysql.SetFlag)
+	}
+	return
+}
+
+// elemsDisplayLength returns the max length in characters of the values of an ENUM or SET column, which is
+// the longest item of an ENUM or all the items joined by commas of a SET.
+func elemsDisplayLength(ft *types.FieldType) int {
+	length := 0
+	for _, elem := range ft.Elems {
+		n := utf8.RuneCountInString(elem)
+		if ft.Tp == mysql.TypeSet {
+			length += n + 1
+		} else if n > length {
+			length = n
+		}
+	}
+	if ft.Tp == mysql.TypeSet && length > 0 {
+		// No comma after the last item.
+		length--
+	}
+	return length
 }
@@ -71,6 +71,7 @@ func (s *Server) onConn(cc *clientConn) {
 	s.Unlock()
 
 	cc.run(ctx)
+	cc.ctx.Close()
 
 	log.Printf("[id:%d] connection closed\n", cc.connectionID)
 }
@@ -0,0 +1,124 @@
+package session
+
+import (
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/ddl"
+	"grant-db/kv"
+	"grant-db/types"
+	"grant-db/util/admin"
+	"grant-db/util/sqlexec"
+)
+
+// defNumHistoryJobs is the number of the history jobs shown by ADMIN SHOW DDL JOBS by default.
+const defNumHistoryJobs = 10
+
+func (s *session) executeAdmin(stmt *ast.AdminStmt) (sqlexec.RecordSet, error) {
+	switch stmt.Tp {
+	case ast.AdminShowDDLJobs:
+		if stmt.Where != nil {
+			return nil, ddl.ErrNotSupportedYet.GenWithStackByArgs("ADMIN SHOW DDL JOBS WHERE")
+		}
+		return s.showDDLJobs(int(stmt.JobNumber))
+	case ast.AdminCancelDDLJobs:
+		return s.cancelDDLJobs(stmt.JobIDs)
+	}
+	return nil, ddl.ErrNotSupportedYet.GenWithStackByArgs("ADMIN statement")
+}
+
+// showDDLJobs shows the jobs in the queue followed by the latest jobNumber history jobs.
+func (s *session) showDDLJobs(jobNumber int) (sqlexec.RecordSet, error) {
+	if jobNumber <= 0 {
+		jobNumber = defNumHistoryJobs
+	}
+	var jobs, historyJobs []*model.Job
+	err := kv.RunInNewTxn(s.store, false, func(txn kv.Transaction) error {
+		var err error
+		if jobs, err = admin.GetDDLJobs(txn); err != nil {
+			return err
+		}
+		historyJobs, err = admin.GetHistoryDDLJobs(txn, jobNumber)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rs := &datumRecordSet{
+		fields: []*ast.ResultField{
+			buildResultField("JOB_ID", mysql.TypeLonglong, 20),
+			buildResultField("DB_NAME", mysql.TypeVarchar, 64),
+			buildResultField("TABLE_NAME", mysql.TypeVarchar, 64),
+			buildResultField("JOB_TYPE", mysql.TypeVarchar, 64),
+			buildResultField("SCHEMA_STATE", mysql.TypeVarchar, 64),
+			buildResultField("SCHEMA_ID", mysql.TypeLonglong, 20),
+			buildResultField("TABLE_ID", mysql.TypeLonglong, 20),
+			buildResultField("ROW_COUNT", mysql.TypeLonglong, 20),
+			buildResultField("START_TIME", mysql.TypeDatetime, 19),
+			buildResultField("END_TIME", mysql.TypeDatetime, 19),
+			buildResultField("STATE", mysql.TypeVarchar, 64),
+		},
+	}
+	for _, job := range append(jobs, historyJobs...) {
+		rs.rows = append(rs.rows, s.ddlJobRow(job))
+	}
+	return rs, nil
+}
+
+func (s *session) ddlJobRow(job *model.Job) []types.Datum {
+	tableName := ""
+	if job.BinlogInfo != nil && job.BinlogInfo.TableInfo != nil {
+		tableName = job.BinlogInfo.TableInfo.Name.O
+	} else if tbl, ok := s.infoSchema.TableByID(job.TableID); ok {
+		tableName = tbl.Meta().Name.O
+	}
+	endTime := types.NewDatum(nil)
+	if job.BinlogInfo != nil && job.BinlogInfo.FinishedTS > 0 {
+		endTime = types.NewTimeDatum(tsToTime(job.BinlogInfo.FinishedTS))
+	}
+	return []types.Datum{
+		types.NewIntDatum(job.ID),
+		types.NewStringDatum(job.SchemaName),
+		types.NewStringDatum(tableName),
+		types.NewStringDatum(job.Type.String()),
+		types.NewStringDatum(job.SchemaState.String()),
+		types.NewIntDatum(job.SchemaID),
+		types.NewIntDatum(job.TableID),
+		types.NewIntDatum(job.GetRowCount()),
+		types.NewTimeDatum(tsToTime(job.StartTS)),
+		endTime,
+		types.NewStringDatum(job.State.String()),
+	}
+}
+
+func tsToTime(ts uint64) types.Time {
+	return types.FromGoTime(model.TSConvert2Time(ts), mysql.TypeDatetime, 0)
+}
+
+func (s *session) cancelDDLJobs(jobIDs []int64) (sqlexec.RecordSet, error) {
+	var errs []error
+	err := kv.RunInNewTxn(s.store, true, func(txn kv.Transaction) error {
+		var err error
+		errs, err = admin.CancelJobs(txn, jobIDs)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	rs := &datumRecordSet{
+		fields: []*ast.ResultField{
+			buildResultField("JOB_ID", mysql.TypeLonglong, 20),
+			buildResultField("RESULT", mysql.TypeVarchar, 128),
+		},
+	}
+	for i, id := range jobIDs {
+		result := "successful"
+		if errs[i] != nil {
+			result = errs[i].Error()
+		}
+		rs.rows = append(rs.rows, []types.Datum{types.NewIntDatum(id), types.NewStringDatum(result)})
+	}
+	return rs, nil
+}
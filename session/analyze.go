@@ -0,0 +1,94 @@
+package session
+
+import (
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"grant-db/ddl"
+	"grant-db/domain"
+	"grant-db/planner"
+	"grant-db/statistics"
+)
+
+// executeAnalyze analyzes the tables, only the indices are analyzed if the statement is ANALYZE TABLE t INDEX,
+// which analyzes all the indices if none is named. The statistics are reloaded for the following statements.
+func (s *session) executeAnalyze(stmt *ast.AnalyzeTableStmt) error {
+	if stmt.Incremental || len(stmt.PartitionNames) > 0 {
+		return ddl.ErrNotSupportedYet.GenWithStackByArgs("ANALYZE INCREMENTAL or PARTITION")
+	}
+	opts, err := analyzeOptions(stmt.AnalyzeOpts)
+	if err != nil {
+		return err
+	}
+	h := domain.GetDomain(s).StatsHandle()
+	for _, tn := range stmt.TableNames {
+		dbName := tn.Schema
+		if dbName.L == "" {
+			if s.sessionVars.CurrentDB == "" {
+				return ddl.ErrNoDB
+			}
+			dbName = model.NewCIStr(s.sessionVars.CurrentDB)
+		}
+		tbl, err := s.infoSchema.TableByName(dbName, tn.Name)
+		if err != nil {
+			return err
+		}
+		tblInfo := tbl.Meta()
+		var indexIDs []int64
+		for _, idx := range tblInfo.Indices {
+			if idx.State == model.StatePublic && (len(stmt.IndexNames) == 0 || containsName(stmt.IndexNames, idx.Name)) {
+				indexIDs = append(indexIDs, idx.ID)
+			}
+		}
+		for _, name := range stmt.IndexNames {
+			if idx := tblInfo.FindIndexByName(name.L); idx == nil || idx.State != model.StatePublic {
+				return planner.ErrKeyDoesNotExist.GenWithStackByArgs(name.O, tblInfo.Name.O)
+			}
+		}
+		if err = h.AnalyzeTable(tbl, indexIDs, stmt.IndexFlag, opts); err != nil {
+			return err
+		}
+	}
+	return h.Update(s.infoSchema)
+}
+
+func containsName(names []model.CIStr, name model.CIStr) bool {
+	for _, n := range names {
+		if n.L == name.L {
+			return true
+		}
+	}
+	return false
+}
+
+// analyzeOptions returns the options of ANALYZE TABLE, the options which aren't specified are the defaults.
+func analyzeOptions(analyzeOpts []ast.AnalyzeOpt) (statistics.AnalyzeOptions, error) {
+	opts := statistics.DefaultAnalyzeOptions()
+	for _, opt := range analyzeOpts {
+		var maxValue uint64
+		switch opt.Type {
+		case ast.AnalyzeOptNumBuckets:
+			maxValue = statistics.MaxNumBuckets
+			opts.NumBuckets = int(opt.Value)
+		case ast.AnalyzeOptNumTopN:
+			maxValue = statistics.MaxNumTopN
+			opts.NumTopN = int(opt.Value)
+		case ast.AnalyzeOptCMSketchDepth:
+			maxValue = statistics.MaxCMSketchSize
+			opts.CMSketchDepth = int32(opt.Value)
+		case ast.AnalyzeOptCMSketchWidth:
+			maxValue = statistics.MaxCMSketchSize
+			opts.CMSketchWidth = int32(opt.Value)
+		case ast.AnalyzeOptNumSamples:
+			maxValue = statistics.MaxNumSamples
+			opts.NumSamples = int(opt.Value)
+		}
+		// The top-n list can be empty, the others must be positive.
+		if opt.Value > maxValue || (opt.Value == 0 && opt.Type != ast.AnalyzeOptNumTopN) {
+			return opts, planner.ErrWrongArguments.GenWithStackByArgs(ast.AnalyzeOptionString[opt.Type])
+		}
+	}
+	if int64(opts.CMSketchDepth)*int64(opts.CMSketchWidth) > statistics.MaxCMSketchSize {
+		return opts, planner.ErrWrongArguments.GenWithStackByArgs("CMSKETCH DEPTH * CMSKETCH WIDTH")
+	}
+	return opts, nil
+}
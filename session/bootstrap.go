@@ -0,0 +1,56 @@
+package session
+
+import (
+	"sync"
+	"time"
+
+	"grant-db/ddl"
+	"grant-db/domain"
+	"grant-db/kv"
+)
+
+type domainMap struct {
+	mu      sync.Mutex
+	domains map[string]*domain.Domain
+}
+
+// Get returns the domain of store, the domain is created and initialized on the first call.
+func (dm *domainMap) Get(store kv.Storage) (*domain.Domain, error) {
+	dm.mu.Lock()
+	defer dm.mu.Unlock()
+
+	key := store.UUID()
+	if d, ok := dm.domains[key]; ok {
+		return d, nil
+	}
+	d := domain.NewDomain(store, ddlOptions)
+	if err := d.Init(); err != nil {
+		return nil, err
+	}
+	dm.domains[key] = d
+	return d, nil
+}
+
+// ddlOptions are the options of the DDL of the domains created after they're set.
+var ddlOptions = ddl.DefaultOptions
+
+// SetSchemaLease sets the longest time the DDL waits for the transactions in progress to use a new schema,
+// the ones still using the old schema after it fail to commit their writes of the changed tables.
+func SetSchemaLease(lease time.Duration) {
+	ddlOptions.Lease = lease
+}
+
+// SetGCLifeTime sets how long the data of a dropped or truncated table is kept and the interval GC runs.
+func SetGCLifeTime(lifeTime, runInterval time.Duration) {
+	ddlOptions.GCLifeTime = lifeTime
+	ddlOptions.GCRunInterval = runInterval
+}
+
+var domap = &domainMap{
+	domains: map[string]*domain.Domain{},
+}
+
+// BootstrapSession runs the first time when the storage is used, it loads the schema of the store.
+func BootstrapSession(store kv.Storage) (*domain.Domain, error) {
+	return domap.Get(store)
+}
@@ -0,0 +1,70 @@
+package session
+
+import (
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/executor"
+	"grant-db/planner"
+	"grant-db/types"
+	"grant-db/util/sqlexec"
+)
+
+// executeExplain shows the physical plan of the statement, EXPLAIN of a table shows its columns.
+func (s *session) executeExplain(stmt *ast.ExplainStmt) (sqlexec.RecordSet, error) {
+	if show, ok := stmt.Stmt.(*ast.ShowStmt); ok {
+		return s.executeShow(show)
+	}
+	p, err := planner.Optimize(s, stmt.Stmt, s.infoSchema)
+	if err != nil {
+		return nil, err
+	}
+	e, err := planner.NewExplain(p, stmt.Format, stmt.Analyze)
+	if err != nil {
+		return nil, err
+	}
+	if e.Analyze {
+		if err = s.runToCompletion(p); err != nil {
+			return nil, err
+		}
+	}
+	if err = e.RenderResult(); err != nil {
+		return nil, err
+	}
+	return explainRecordSet(e), nil
+}
+
+// runToCompletion executes the plan and discards the rows, the runtime statistics are collected by the
+// executors.
+func (s *session) runToCompletion(p planner.PhysicalPlan) error {
+	rs, err := (&executor.ExecStmt{Plan: p, Ctx: s}).Exec(s.currentCtx)
+	// The statement which returns no rows is run by Exec.
+	if err != nil || rs == nil {
+		return err
+	}
+	chk := rs.NewChunk()
+	for {
+		if err = rs.Next(s.currentCtx, chk); err != nil || chk.NumRows() == 0 {
+			break
+		}
+	}
+	if closeErr := rs.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// explainRecordSet returns the rendered rows of the explain as a record set.
+func explainRecordSet(e *planner.Explain) sqlexec.RecordSet {
+	rs := &datumRecordSet{}
+	for _, name := range e.FieldNames() {
+		rs.fields = append(rs.fields, buildResultField(name, mysql.TypeString, mysql.MaxBlobWidth))
+	}
+	for _, row := range e.Rows {
+		datums := make([]types.Datum, 0, len(row))
+		for _, str := range row {
+			datums = append(datums, types.NewStringDatum(str))
+		}
+		rs.rows = append(rs.rows, datums)
+	}
+	return rs
+}
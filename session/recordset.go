@@ -0,0 +1,67 @@
+package session
+
+import (
+	"context"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/charset"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/types"
+	"grant-db/util/chunk"
+)
+
+// maxChunkSize is the max number of rows in a chunk of the record set.
+const maxChunkSize = 1024
+
+// datumRecordSet is a record set of rows which are already computed, such as the
+// results of ADMIN statements.
+type datumRecordSet struct {
+	fields []*ast.ResultField
+	rows   [][]types.Datum
+	cursor int
+}
+
+func (rs *datumRecordSet) Fields() []*ast.ResultField {
+	return rs.fields
+}
+
+func (rs *datumRecordSet) Next(ctx context.Context, req *chunk.Chunk) error {
+	req.Reset()
+	for ; rs.cursor < len(rs.rows) && !req.IsFull(); rs.cursor++ {
+		for i := range rs.rows[rs.cursor] {
+			req.AppendDatum(i, &rs.rows[rs.cursor][i])
+		}
+	}
+	return nil
+}
+
+func (rs *datumRecordSet) NewChunk() *chunk.Chunk {
+	fts := make([]*types.FieldType, 0, len(rs.fields))
+	for _, f := range rs.fields {
+		fts = append(fts, &f.Column.FieldType)
+	}
+	return chunk.New(fts, maxChunkSize, maxChunkSize)
+}
+
+func (rs *datumRecordSet) Close() error {
+	rs.cursor = 0
+	return nil
+}
+
+// buildResultField builds the field of a result column which doesn't belong to any table.
+func buildResultField(name string, tp byte, size int) *ast.ResultField {
+	fieldType := types.NewFieldType(tp)
+	fieldType.Flen = size
+	if types.IsString(tp) {
+		fieldType.Charset, fieldType.Collate = mysql.DefaultCharset, mysql.DefaultCollationName
+	} else {
+		fieldType.Charset, fieldType.Collate = charset.CharsetBin, charset.CollationBin
+		fieldType.Flag |= mysql.BinaryFlag
+	}
+	colName := model.NewCIStr(name)
+	return &ast.ResultField{
+		Column:       &model.ColumnInfo{Name: colName, FieldType: *fieldType},
+		ColumnAsName: colName,
+	}
+}
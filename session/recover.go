@@ -0,0 +1,138 @@
+package session
+
+import (
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"grant-db/ddl"
+	"grant-db/domain"
+	"grant-db/kv"
+	"grant-db/meta"
+)
+
+// executeRecoverTable recovers the table dropped or truncated by the job, or the latest
+// dropped or truncated table with the name.
+func (s *session) executeRecoverTable(stmt *ast.RecoverTableStmt) error {
+	var (
+		job     *model.Job
+		tblInfo *model.TableInfo
+		err     error
+	)
+	if stmt.JobID != 0 {
+		job, tblInfo, err = s.getRecoverTableByJobID(stmt.JobID)
+	} else {
+		job, tblInfo, err = s.getRecoverTableByTableName(stmt.Table)
+	}
+	if err != nil {
+		return err
+	}
+	recoverInfo := &ddl.RecoverInfo{SchemaID: job.SchemaID, TableInfo: tblInfo, DropJobID: job.ID}
+	return domain.GetDomain(s).DDL().RecoverTable(s, recoverInfo)
+}
+
+// executeFlashbackTable recovers the latest dropped or truncated table with the name, it's
+// renamed to the new name if given.
+func (s *session) executeFlashbackTable(stmt *ast.FlashBackTableStmt) error {
+	job, tblInfo, err := s.getRecoverTableByTableName(stmt.Table)
+	if err != nil {
+		return err
+	}
+	if stmt.NewName != "" {
+		tblInfo.Name = model.NewCIStr(stmt.NewName)
+	}
+	recoverInfo := &ddl.RecoverInfo{SchemaID: job.SchemaID, TableInfo: tblInfo, DropJobID: job.ID}
+	return domain.GetDomain(s).DDL().RecoverTable(s, recoverInfo)
+}
+
+func (s *session) getRecoverTableByJobID(jobID int64) (*model.Job, *model.TableInfo, error) {
+	var (
+		job       *model.Job
+		safePoint uint64
+	)
+	err := kv.RunInNewTxn(s.store, false, func(txn kv.Transaction) error {
+		t := meta.NewMeta(txn)
+		var err error
+		if job, err = t.GetHistoryDDLJob(jobID); err != nil {
+			return err
+		}
+		safePoint, err = t.GetGCSafePoint()
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if job == nil {
+		return nil, nil, ddl.ErrDDLJobNotFound.GenWithStackByArgs(jobID)
+	}
+	if job.Type != model.ActionDropTable && job.Type != model.ActionTruncateTable {
+		return nil, nil, ddl.ErrNotDropOrTruncateJob.GenWithStackByArgs(job.ID, job.Type)
+	}
+	tblInfo, err := s.getDroppedTableInfo(job)
+	if err != nil {
+		return nil, nil, err
+	}
+	if job.BinlogInfo.FinishedTS < safePoint {
+		return nil, nil, ddl.ErrTableDataGCed.GenWithStackByArgs(tblInfo.Name)
+	}
+	return job, tblInfo, nil
+}
+
+// getRecoverTableByTableName finds the latest job which drops or truncates the table after
+// the GC safe point.
+func (s *session) getRecoverTableByTableName(tn *ast.TableName) (*model.Job, *model.TableInfo, error) {
+	schemaName := tn.Schema
+	if schemaName.L == "" {
+		if s.sessionVars.CurrentDB == "" {
+			return nil, nil, ddl.ErrNoDB
+		}
+		schemaName = model.NewCIStr(s.sessionVars.CurrentDB)
+	}
+	var (
+		jobs      []*model.Job
+		safePoint uint64
+	)
+	err := kv.RunInNewTxn(s.store, false, func(txn kv.Transaction) error {
+		t := meta.NewMeta(txn)
+		var err error
+		if jobs, err = t.GetLastNHistoryDDLJobs(0); err != nil {
+			return err
+		}
+		safePoint, err = t.GetGCSafePoint()
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, job := range jobs {
+		if job.BinlogInfo.FinishedTS < safePoint {
+			break
+		}
+		if job.Type != model.ActionDropTable && job.Type != model.ActionTruncateTable {
+			continue
+		}
+		if job.SchemaName != schemaName.L || job.BinlogInfo.TableInfo == nil ||
+			job.BinlogInfo.TableInfo.Name.L != tn.Name.L {
+			continue
+		}
+		tblInfo, err := s.getDroppedTableInfo(job)
+		if err != nil {
+			return nil, nil, err
+		}
+		return job, tblInfo, nil
+	}
+	return nil, nil, ddl.ErrNoDroppedTable.GenWithStackByArgs(
+		schemaName.O+"."+tn.Name.O, tsToTime(safePoint).String())
+}
+
+// getDroppedTableInfo reads the table info right before the job drops or truncates the table.
+func (s *session) getDroppedTableInfo(job *model.Job) (*model.TableInfo, error) {
+	snapshot := s.store.GetSnapshot(kv.Version{Ver: job.BinlogInfo.FinishedTS})
+	tblInfo, err := meta.NewSnapshotMeta(snapshot).GetTable(job.SchemaID, job.TableID)
+	if err != nil {
+		return nil, err
+	}
+	if tblInfo == nil {
+		return nil, ddl.ErrNoDroppedTable.GenWithStackByArgs(
+			job.SchemaName+"."+job.BinlogInfo.TableInfo.Name.O, tsToTime(job.BinlogInfo.FinishedTS).String())
+	}
+	return tblInfo, nil
+}
@@ -5,10 +5,21 @@ import (
 	"fmt"
 	"github.com/pingcap/parser"
 	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
 	_ "github.com/pingcap/tidb/types/parser_driver"
+	"grant-db/ddl"
+	"grant-db/domain"
+	"grant-db/executor"
+	"grant-db/infoschema"
 	"grant-db/kv"
+	"grant-db/planner"
 	"grant-db/sessionctx"
+	"grant-db/sessionctx/stmtctx"
 	"grant-db/sessionctx/variable"
+	"grant-db/util/chunk"
+	"grant-db/util/execdetails"
+	"grant-db/util/memory"
+	"grant-db/util/sqlexec"
 	"sync"
 )
 
@@ -16,8 +27,16 @@ type Session interface {
 	sessionctx.Context
 	Status() uint16
 	Parse(ctx context.Context, sql string) ([]ast.StmtNode, error)
+	ExecuteStmt(ctx context.Context, stmt ast.StmtNode) (sqlexec.RecordSet, error)
+	GetInfoSchema() infoschema.InfoSchema
 	SetClientCapability(uint32)
 	SetConnectionID(connectionID uint64)
+	// AffectedRows, LastInsertID and LastMessage are the result of the last statement which returns no rows.
+	AffectedRows() uint64
+	LastInsertID() uint64
+	LastMessage() string
+	// Close rolls back the transaction the session is in, it's called when the connection is closed.
+	Close()
 }
 
 type session struct {
@@ -25,6 +44,17 @@ type session struct {
 	parser      *parser.Parser
 	sessionVars *variable.SessionVars
 	currentCtx  context.Context
+	// infoSchema is the schema snapshot used by the current statement.
+	infoSchema infoschema.InfoSchema
+	// txn is the transaction of the current statement, it's begun when the statement reads or writes the
+	// data and finished with the statement, or with the explicit transaction which the statement is in.
+	txn kv.Transaction
+	// stmtStaging stages the mutations of the current statement in the explicit transaction, stageStmt is
+	// true if the current statement is staged.
+	stmtStaging kv.StagingHandle
+	stageStmt   bool
+	// savepoints are the savepoints of the explicit transaction in the order they're set.
+	savepoints []savepoint
 
 	mu struct {
 		sync.RWMutex
@@ -33,11 +63,19 @@ type session struct {
 }
 
 func NewSession(store kv.Storage) (Session, error) {
+	dom, err := domap.Get(store)
+	if err != nil {
+		return nil, err
+	}
 	se := &session{
 		store:       store,
 		parser:      parser.New(),
 		sessionVars: variable.NewSessionVars(),
 	}
+	se.parser.EnableWindowFunc(true)
+	se.sessionVars.GlobalVarsAccessor = se
+	se.mu.values = make(map[fmt.Stringer]interface{})
+	domain.BindDomain(se, dom)
 	return se, nil
 }
 
@@ -61,6 +99,18 @@ func (s *session) Value(key fmt.Stringer) interface{} {
 	return s.mu.values[key]
 }
 
+func (s *session) GetStore() kv.Storage {
+	return s.store
+}
+
+// Txn implements sessionctx.Context Txn interface.
+func (s *session) Txn(active bool) (kv.Transaction, error) {
+	if active {
+		return s.activateTxn()
+	}
+	return s.txn, nil
+}
+
 func (s *session) GetSessionVars() *variable.SessionVars {
 	return s.sessionVars
 }
@@ -69,8 +119,38 @@ func (s *session) Status() uint16 {
 	return s.sessionVars.Status
 }
 
+// AffectedRows implements Session AffectedRows interface.
+func (s *session) AffectedRows() uint64 {
+	return s.sessionVars.StmtCtx.AffectedRows()
+}
+
+// LastInsertID implements Session LastInsertID interface, it's the first allocated ID, or the first explicit
+// one if no ID is allocated.
+func (s *session) LastInsertID() uint64 {
+	sc := s.sessionVars.StmtCtx
+	if sc.LastInsertID > 0 {
+		return sc.LastInsertID
+	}
+	return sc.InsertID
+}
+
+// LastMessage implements Session LastMessage interface.
+func (s *session) LastMessage() string {
+	return s.sessionVars.StmtCtx.GetMessage()
+}
+
+// Close implements Session Close interface.
+func (s *session) Close() {
+	s.rollbackTxn()
+}
+
 func (s *session) Parse(ctx context.Context, sql string) ([]ast.StmtNode, error) {
-	stmts, _, err := s.ParseSQL(ctx, sql, "", "")
+	// The savepoint statements aren't supported by the parser.
+	if stmt := parseSavepointStmt(sql); stmt != nil {
+		return []ast.StmtNode{stmt}, nil
+	}
+	cs, coll := s.sessionVars.GetCharsetInfo()
+	stmts, _, err := s.ParseSQL(ctx, sql, cs, coll)
 	if err != nil {
 		return nil, err
 	}
@@ -78,6 +158,253 @@ func (s *session) Parse(ctx context.Context, sql string) ([]ast.StmtNode, error)
 }
 
 func (s *session) ParseSQL(ctx context.Context, sql, charset, collation string) ([]ast.StmtNode, []error, error) {
-	s.parser.SetSQLMode(0)
+	s.parser.SetSQLMode(s.sessionVars.SQLMode)
 	return s.parser.Parse(sql, charset, collation)
 }
+
+// GetInfoSchema returns the schema snapshot of the current statement.
+func (s *session) GetInfoSchema() infoschema.InfoSchema {
+	return s.infoSchema
+}
+
+// refreshInfoSchema reloads the schema of the domain if its version changed,
+// every statement sees the schema which is the latest when it starts, or the one used by the transaction in
+// progress, or the one at the start timestamp of the stale read transaction.
+func (s *session) refreshInfoSchema() error {
+	dom := domain.GetDomain(s)
+	if err := dom.Reload(); err != nil {
+		return err
+	}
+	if is, ok := s.sessionVars.TxnCtx.InfoSchema.(infoschema.InfoSchema); ok {
+		s.infoSchema = is
+		return nil
+	}
+	if ts := s.sessionVars.TxnCtx.StaleReadTS; ts != 0 {
+		is, err := dom.GetSnapshotInfoSchema(ts)
+		if err != nil {
+			return err
+		}
+		s.infoSchema = is
+		return nil
+	}
+	s.infoSchema = dom.InfoSchema()
+	return nil
+}
+
+func (s *session) ExecuteStmt(ctx context.Context, stmt ast.StmtNode) (sqlexec.RecordSet, error) {
+	s.currentCtx = ctx
+	// The diagnostics statements show the warnings of the previous statement.
+	if !isDiagnosticsStmt(stmt) {
+		s.resetStmtCtx(stmt)
+	}
+	var rs sqlexec.RecordSet
+	err := s.prepareTxn(stmt)
+	if err == nil {
+		rs, err = s.executeStmt(stmt)
+	}
+	if rs == nil {
+		err = s.finishStmt(err)
+	} else if err == nil {
+		rs = &execStmtResult{RecordSet: rs, se: s}
+	}
+	if err != nil {
+		s.sessionVars.StmtCtx.AppendError(err)
+	}
+	return rs, err
+}
+
+// execStmtResult finishes the statement when the record set is closed.
+type execStmtResult struct {
+	sqlexec.RecordSet
+	se *session
+	// lastErr is the error returned by Next, the statement is rolled back if it's not nil.
+	lastErr error
+}
+
+// Next implements sqlexec.RecordSet Next interface.
+func (rs *execStmtResult) Next(ctx context.Context, req *chunk.Chunk) error {
+	err := rs.RecordSet.Next(ctx, req)
+	if err != nil {
+		rs.lastErr = err
+	}
+	return err
+}
+
+// Close implements sqlexec.RecordSet Close interface.
+func (rs *execStmtResult) Close() error {
+	closeErr := rs.RecordSet.Close()
+	stmtErr := rs.lastErr
+	if stmtErr == nil {
+		stmtErr = closeErr
+	}
+	err := rs.se.finishStmt(stmtErr)
+	if rs.lastErr != nil {
+		// The error is returned by Next already.
+		return closeErr
+	}
+	return err
+}
+
+func (s *session) executeStmt(stmt ast.StmtNode) (sqlexec.RecordSet, error) {
+	if err := s.refreshInfoSchema(); err != nil {
+		return nil, err
+	}
+	switch x := stmt.(type) {
+	case ast.DDLNode:
+		return nil, s.executeDDL(x)
+	case *ast.UseStmt:
+		return nil, s.executeUse(x)
+	case *ast.AdminStmt:
+		return s.executeAdmin(x)
+	case *ast.SetStmt:
+		return nil, s.executeSet(x)
+	case *ast.ShowStmt:
+		return s.executeShow(x)
+	case *ast.AnalyzeTableStmt:
+		return nil, s.executeAnalyze(x)
+	case *ast.ExplainStmt:
+		return s.executeExplain(x)
+	case *ast.SelectStmt, *ast.UnionStmt, *ast.InsertStmt, *ast.UpdateStmt, *ast.DeleteStmt:
+		return s.executeQuery(x)
+	case *ast.BeginStmt:
+		return nil, s.executeBegin(x)
+	case *ast.CommitStmt:
+		return nil, s.executeCommit(x)
+	case *ast.RollbackStmt:
+		return nil, s.executeRollback(x)
+	case *savepointStmt:
+		return nil, s.executeSavepoint(x)
+	}
+	return nil, nil
+}
+
+// executeQuery executes the query by the executors of its physical plan.
+func (s *session) executeQuery(stmt ast.StmtNode) (sqlexec.RecordSet, error) {
+	p, err := planner.Optimize(s, stmt, s.infoSchema)
+	if err != nil {
+		return nil, err
+	}
+	return (&executor.ExecStmt{Plan: p, Ctx: s}).Exec(s.currentCtx)
+}
+
+// resetStmtCtx creates a new statement context for the statement, the way it handles the bad values
+// is decided by the sql_mode and the kind of the statement. EXPLAIN is treated as the statement it explains.
+func (s *session) resetStmtCtx(stmt ast.StmtNode) {
+	vars := s.sessionVars
+	sc := new(stmtctx.StatementContext)
+	sc.AllowInvalidDate = vars.SQLMode.HasAllowInvalidDatesMode()
+	sc.MemTracker = memory.NewTracker(memory.LabelForSQLText)
+	sc.MemTracker.SetBytesLimit(vars.MemQuotaQuery)
+	sc.MemTracker.SetActionOnExceed(&memory.PanicOnExceed{})
+	sc.DiskTracker = memory.NewTracker(memory.LabelForSQLText)
+	// LAST_INSERT_ID() returns the last ID allocated by the previous statements.
+	sc.PrevLastInsertID = vars.StmtCtx.PrevLastInsertID
+	if vars.StmtCtx.LastInsertID > 0 {
+		sc.PrevLastInsertID = vars.StmtCtx.LastInsertID
+	}
+	if explain, ok := stmt.(*ast.ExplainStmt); ok {
+		if explain.Analyze {
+			sc.RuntimeStatsColl = execdetails.NewRuntimeStatsColl()
+		}
+		stmt = explain.Stmt
+	}
+	switch x := stmt.(type) {
+	case *ast.InsertStmt:
+		sc.InInsertStmt = true
+		s.setDMLStmtCtx(sc, x.IgnoreErr)
+	case *ast.UpdateStmt:
+		sc.InUpdateStmt = true
+		s.setDMLStmtCtx(sc, x.IgnoreErr)
+	case *ast.DeleteStmt:
+		sc.InDeleteStmt = true
+		s.setDMLStmtCtx(sc, x.IgnoreErr)
+	case *ast.SelectStmt:
+		sc.InSelectStmt = true
+		sc.TruncateAsWarning = true
+		sc.OverflowAsWarning = true
+		sc.DividedByZeroAsWarning = true
+		sc.IgnoreZeroInDate = true
+	default:
+		sc.TruncateAsWarning = !vars.StrictSQLMode
+		sc.OverflowAsWarning = !vars.StrictSQLMode
+		sc.DividedByZeroAsWarning = !vars.StrictSQLMode
+		sc.IgnoreZeroInDate = true
+	}
+	vars.StmtCtx = sc
+	vars.PlanID, vars.PlanColumnID = 0, 0
+}
+
+// setDMLStmtCtx sets the statement context of the statement which writes data, the bad values are
+// errors in strict mode unless the statement is with IGNORE, and warnings otherwise.
+func (s *session) setDMLStmtCtx(sc *stmtctx.StatementContext, ignoreErr bool) {
+	vars := s.sessionVars
+	asWarning := !vars.StrictSQLMode || ignoreErr
+	sc.TruncateAsWarning = asWarning
+	sc.OverflowAsWarning = asWarning
+	sc.DividedByZeroAsWarning = asWarning
+	sc.BadNullAsWarning = asWarning
+	sc.IgnoreZeroInDate = !vars.SQLMode.HasNoZeroInDateMode()
+	sc.NoZeroDate = vars.SQLMode.HasNoZeroDateMode()
+}
+
+func (s *session) executeDDL(stmt ast.DDLNode) error {
+	// The explicit transaction is committed implicitly like MySQL.
+	if err := s.finishTxn(nil); err != nil {
+		return err
+	}
+	if err := s.refreshInfoSchema(); err != nil {
+		return err
+	}
+	d := domain.GetDomain(s).DDL()
+	var err error
+	switch x := stmt.(type) {
+	case *ast.CreateDatabaseStmt:
+		err = d.CreateSchema(s, x)
+	case *ast.DropDatabaseStmt:
+		err = d.DropSchema(s, x)
+	case *ast.CreateTableStmt:
+		err = d.CreateTable(s, x)
+	case *ast.DropTableStmt:
+		err = d.DropTable(s, x)
+	case *ast.CreateIndexStmt:
+		ident := ast.Ident{Schema: x.Table.Schema, Name: x.Table.Name}
+		err = d.CreateIndex(s, ident, x.KeyType, model.NewCIStr(x.IndexName),
+			x.IndexPartSpecifications, x.IndexOption, x.IfNotExists)
+	case *ast.DropIndexStmt:
+		ident := ast.Ident{Schema: x.Table.Schema, Name: x.Table.Name}
+		err = d.DropIndex(s, ident, model.NewCIStr(x.IndexName), x.IfExists)
+	case *ast.AlterTableStmt:
+		ident := ast.Ident{Schema: x.Table.Schema, Name: x.Table.Name}
+		err = d.AlterTable(s, ident, x.Specs)
+	case *ast.TruncateTableStmt:
+		ident := ast.Ident{Schema: x.Table.Schema, Name: x.Table.Name}
+		err = d.TruncateTable(s, ident)
+	case *ast.RenameTableStmt:
+		oldIdents := make([]ast.Ident, 0, len(x.TableToTables))
+		newIdents := make([]ast.Ident, 0, len(x.TableToTables))
+		for _, tt := range x.TableToTables {
+			oldIdents = append(oldIdents, ast.Ident{Schema: tt.OldTable.Schema, Name: tt.OldTable.Name})
+			newIdents = append(newIdents, ast.Ident{Schema: tt.NewTable.Schema, Name: tt.NewTable.Name})
+		}
+		err = d.RenameTables(s, oldIdents, newIdents, false)
+	case *ast.RecoverTableStmt:
+		err = s.executeRecoverTable(x)
+	case *ast.FlashBackTableStmt:
+		err = s.executeFlashbackTable(x)
+	default:
+		return ddl.ErrNotSupportedYet.GenWithStackByArgs(fmt.Sprintf("%T", stmt))
+	}
+	if err != nil {
+		return err
+	}
+	return s.refreshInfoSchema()
+}
+
+func (s *session) executeUse(stmt *ast.UseStmt) error {
+	dbName := model.NewCIStr(stmt.DBName)
+	if !s.infoSchema.SchemaExists(dbName) {
+		return infoschema.ErrDatabaseNotExists.GenWithStackByArgs(dbName.O)
+	}
+	s.sessionVars.CurrentDB = dbName.O
+	return nil
+}
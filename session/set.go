@@ -0,0 +1,119 @@
+package session
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/ddl"
+	"grant-db/domain"
+	"grant-db/expression"
+	"grant-db/sessionctx/variable"
+	"grant-db/util/chunk"
+)
+
+func (s *session) executeSet(stmt *ast.SetStmt) error {
+	wasAutocommit := s.sessionVars.IsAutocommit()
+	for _, v := range stmt.Variables {
+		switch {
+		case v.Name == ast.SetNames:
+			if err := s.setNames(v); err != nil {
+				return err
+			}
+			continue
+		case !v.IsSystem:
+			return ddl.ErrNotSupportedYet.GenWithStackByArgs("user variable")
+		case v.IsGlobal:
+			if err := s.setGlobalSysVar(v); err != nil {
+				return err
+			}
+			continue
+		}
+		value, err := s.getSetValue(v)
+		if err != nil {
+			return err
+		}
+		if err = variable.SetSessionSystemVar(s.sessionVars, v.Name, value); err != nil {
+			return err
+		}
+	}
+	// Turning autocommit on commits the explicit transaction.
+	if !wasAutocommit && s.sessionVars.IsAutocommit() {
+		return s.finishTxn(nil)
+	}
+	return nil
+}
+
+// setGlobalSysVar sets a global system variable, only the ones kept by the domain are supported.
+func (s *session) setGlobalSysVar(v *ast.VariableAssignment) error {
+	if strings.ToLower(v.Name) != variable.TiDBAutoAnalyzeRatio {
+		return ddl.ErrNotSupportedYet.GenWithStackByArgs("SET GLOBAL")
+	}
+	value, err := s.getSetValue(v)
+	if err != nil {
+		return err
+	}
+	ratio, err := variable.ParseAutoAnalyzeRatio(value)
+	if err != nil {
+		return err
+	}
+	domain.GetDomain(s).StatsHandle().SetAutoAnalyzeRatio(ratio)
+	return nil
+}
+
+// GetGlobalSysVar implements variable.GlobalVarAccessor, the global variables other than the ones kept by the
+// domain can't be set, so they have the default values.
+func (s *session) GetGlobalSysVar(name string) (string, error) {
+	if name == variable.TiDBAutoAnalyzeRatio {
+		ratio := domain.GetDomain(s).StatsHandle().AutoAnalyzeRatio()
+		return strconv.FormatFloat(ratio, 'f', -1, 64), nil
+	}
+	value, ok := variable.GetSysVarDefault(name)
+	if !ok {
+		return "", variable.ErrUnknownSystemVariable.GenWithStackByArgs(name)
+	}
+	return value, nil
+}
+
+// setNames handles SET NAMES charset [COLLATE collation] and SET CHARACTER SET charset, DEFAULT is the default charset.
+func (s *session) setNames(v *ast.VariableAssignment) error {
+	cs, coll := mysql.DefaultCharset, ""
+	if value, ok := v.Value.(ast.ValueExpr); ok {
+		cs = value.GetString()
+	}
+	if v.ExtendValue != nil {
+		coll = v.ExtendValue.GetString()
+	}
+	return s.sessionVars.SetNames(cs, coll)
+}
+
+// getSetValue evaluates the value assigned to the system variable, DEFAULT is the default value of the variable.
+func (s *session) getSetValue(v *ast.VariableAssignment) (string, error) {
+	if _, ok := v.Value.(*ast.DefaultExpr); ok {
+		value, ok := variable.GetSysVarDefault(v.Name)
+		if !ok {
+			return "", variable.ErrUnknownSystemVariable.GenWithStackByArgs(v.Name)
+		}
+		return value, nil
+	}
+	// An identifier is taken as a string, like SET sql_mode = ANSI.
+	if col, ok := v.Value.(*ast.ColumnNameExpr); ok && col.Name.Table.L == "" {
+		return col.Name.Name.O, nil
+	}
+	expr, err := expression.RewriteSimpleExprWithNames(s, v.Value, expression.NewSchema(), nil)
+	if err != nil {
+		return "", err
+	}
+	d, err := expr.Eval(chunk.Row{})
+	if err != nil {
+		return "", err
+	}
+	if d.IsNull() {
+		if variable.IsNullable(v.Name) {
+			return "", nil
+		}
+		return "", variable.ErrWrongValueForVar.GenWithStackByArgs(v.Name, "NULL")
+	}
+	return d.ToString()
+}
@@ -0,0 +1,40 @@
+package session_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/parser/mysql"
+	"grant-db/util/testkit"
+)
+
+func TestSetNames(t *testing.T) {
+	store, _ := testkit.NewMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	charsetVars := "select @@character_set_client, @@character_set_connection, @@collation_connection, @@character_set_results"
+	tk.MustQuery(charsetVars).Check("utf8mb4 utf8mb4 utf8mb4_bin utf8mb4")
+
+	// The collation of the connection is the default one of the charset.
+	tk.MustExec("set names latin1")
+	tk.MustQuery(charsetVars).Check("latin1 latin1 latin1_swedish_ci latin1")
+	tk.MustExec("set names gbk")
+	tk.MustQuery(charsetVars).Check("gbk gbk gbk_chinese_ci gbk")
+	tk.MustExec("set names gbk collate gbk_bin")
+	tk.MustQuery(charsetVars).Check("gbk gbk gbk_bin gbk")
+	tk.MustExec("set names 'utf8mb4' collate 'utf8mb4_general_ci'")
+	tk.MustQuery(charsetVars).Check("utf8mb4 utf8mb4 utf8mb4_general_ci utf8mb4")
+	tk.MustQuery("select 'a' = 'A ', 'a' < 'B'").Check("1 1")
+	tk.MustExec("set names default")
+	tk.MustQuery(charsetVars).Check("utf8mb4 utf8mb4 utf8mb4_bin utf8mb4")
+	tk.MustQuery("select 'a' = 'A'").Check("0")
+
+	tk.MustGetErrCode("set names foo", mysql.ErrUnknownCharacterSet)
+	tk.MustGetErrCode("set names gbk collate utf8mb4_bin", mysql.ErrCollationCharsetMismatch)
+	tk.MustGetErrCode("set names utf8mb4 collate foo", mysql.ErrUnknownCollation)
+
+	// The variables can be set one by one.
+	tk.MustExec("set character_set_connection = latin1")
+	tk.MustQuery("select @@collation_connection").Check("latin1_swedish_ci")
+	tk.MustExec("set collation_connection = gbk_bin, character_set_results = ''")
+	tk.MustQuery("select @@character_set_connection, @@character_set_results").Check("gbk ")
+	tk.MustGetErrCode("set character_set_client = foo", mysql.ErrUnknownCharacterSet)
+}
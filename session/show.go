@@ -0,0 +1,239 @@
+package session
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
+	"grant-db/ddl"
+	"grant-db/domain"
+	"grant-db/sessionctx/stmtctx"
+	"grant-db/sessionctx/variable"
+	"grant-db/statistics"
+	"grant-db/types"
+	"grant-db/util/sqlexec"
+	"grant-db/util/stringutil"
+)
+
+// isDiagnosticsStmt reports whether the statement shows the diagnostics of the previous statement.
+func isDiagnosticsStmt(stmt ast.StmtNode) bool {
+	show, ok := stmt.(*ast.ShowStmt)
+	return ok && (show.Tp == ast.ShowWarnings || show.Tp == ast.ShowErrors)
+}
+
+func (s *session) executeShow(stmt *ast.ShowStmt) (sqlexec.RecordSet, error) {
+	switch stmt.Tp {
+	case ast.ShowWarnings:
+		return s.showWarnings(false), nil
+	case ast.ShowErrors:
+		return s.showWarnings(true), nil
+	case ast.ShowStatsMeta, ast.ShowStatsHistograms, ast.ShowStatsBuckets:
+		if stmt.Pattern != nil || stmt.Where != nil {
+			return nil, ddl.ErrNotSupportedYet.GenWithStackByArgs("SHOW STATS with LIKE or WHERE")
+		}
+		return s.showStats(stmt.Tp)
+	case ast.ShowVariables:
+		if stmt.Where != nil {
+			return nil, ddl.ErrNotSupportedYet.GenWithStackByArgs("SHOW VARIABLES with WHERE")
+		}
+		return s.showVariables(stmt)
+	}
+	return nil, ddl.ErrNotSupportedYet.GenWithStackByArgs("SHOW statement")
+}
+
+// showWarnings shows the warnings of the previous statement, only the errors are shown if errOnly is true.
+func (s *session) showWarnings(errOnly bool) sqlexec.RecordSet {
+	rs := &datumRecordSet{
+		fields: []*ast.ResultField{
+			buildResultField("Level", mysql.TypeVarchar, 64),
+			buildResultField("Code", mysql.TypeLong, 19),
+			buildResultField("Message", mysql.TypeVarchar, 64),
+		},
+	}
+	for _, w := range s.sessionVars.StmtCtx.GetWarnings() {
+		if errOnly && w.Level != stmtctx.WarnLevelError {
+			continue
+		}
+		sqlErr := toSQLError(w.Err)
+		rs.rows = append(rs.rows, types.MakeDatums(w.Level, int64(sqlErr.Code), sqlErr.Message))
+	}
+	return rs
+}
+
+// showVariables shows the session or the global values of the system variables whose names match the LIKE
+// pattern, the names are matched case-insensitively.
+func (s *session) showVariables(stmt *ast.ShowStmt) (sqlexec.RecordSet, error) {
+	var patChars, patTypes []byte
+	if stmt.Pattern != nil {
+		pattern, ok := stmt.Pattern.Pattern.(ast.ValueExpr)
+		if !ok {
+			return nil, ddl.ErrNotSupportedYet.GenWithStackByArgs("SHOW VARIABLES LIKE with an expression")
+		}
+		patChars, patTypes = stringutil.CompilePattern(strings.ToLower(pattern.GetString()), stmt.Pattern.Escape)
+	}
+	rs := &datumRecordSet{
+		fields: []*ast.ResultField{
+			buildResultField("Variable_name", mysql.TypeVarchar, 64),
+			buildResultField("Value", mysql.TypeVarchar, 1024),
+		},
+	}
+	for _, name := range variable.GetSysVarNames() {
+		if stmt.Pattern != nil && !stringutil.DoMatch(name, patChars, patTypes) {
+			continue
+		}
+		var value string
+		var err error
+		if stmt.GlobalScope {
+			value, err = variable.GetGlobalSystemVar(s.sessionVars, name)
+		} else {
+			value, err = variable.GetSessionSystemVar(s.sessionVars, name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		rs.rows = append(rs.rows, types.MakeDatums(name, value))
+	}
+	return rs, nil
+}
+
+// toSQLError converts the error to a MySQL error, an error without a MySQL error code is ER_UNKNOWN_ERROR.
+func toSQLError(err error) *mysql.SQLError {
+	switch x := errors.Cause(err).(type) {
+	case *terror.Error:
+		return x.ToSQLError()
+	case *mysql.SQLError:
+		return x
+	}
+	return mysql.NewErrf(mysql.ErrUnknown, "%s", err.Error())
+}
+
+// statsTable is the statistics of a table of a database.
+type statsTable struct {
+	dbName  string
+	tblInfo *model.TableInfo
+	stats   *statistics.Table
+}
+
+// statsTables returns the statistics of the tables ordered by the names, the tables without statistics are
+// skipped.
+func (s *session) statsTables() []statsTable {
+	h := domain.GetDomain(s).StatsHandle()
+	var tables []statsTable
+	dbNames := s.infoSchema.AllSchemaNames()
+	sort.Strings(dbNames)
+	for _, dbName := range dbNames {
+		tbls := s.infoSchema.SchemaTables(model.NewCIStr(dbName))
+		sort.Slice(tbls, func(i, j int) bool { return tbls[i].Meta().Name.L < tbls[j].Meta().Name.L })
+		for _, tbl := range tbls {
+			stats := h.GetTableStats(tbl.Meta())
+			if !stats.Pseudo {
+				tables = append(tables, statsTable{dbName: dbName, tblInfo: tbl.Meta(), stats: stats})
+			}
+		}
+	}
+	return tables
+}
+
+// showStats shows the stats meta of the tables, the histograms of the columns and the indices or the buckets
+// of the histograms.
+func (s *session) showStats(tp ast.ShowStmtType) (sqlexec.RecordSet, error) {
+	rs := &datumRecordSet{
+		fields: []*ast.ResultField{
+			buildResultField("Db_name", mysql.TypeVarchar, 64),
+			buildResultField("Table_name", mysql.TypeVarchar, 64),
+			buildResultField("Partition_name", mysql.TypeVarchar, 64),
+		},
+	}
+	switch tp {
+	case ast.ShowStatsMeta:
+		rs.fields = append(rs.fields,
+			buildResultField("Update_time", mysql.TypeDatetime, 19),
+			buildResultField("Modify_count", mysql.TypeLonglong, 20),
+			buildResultField("Row_count", mysql.TypeLonglong, 20))
+	case ast.ShowStatsHistograms:
+		rs.fields = append(rs.fields,
+			buildResultField("Column_name", mysql.TypeVarchar, 64),
+			buildResultField("Is_index", mysql.TypeTiny, 1),
+			buildResultField("Update_time", mysql.TypeDatetime, 19),
+			buildResultField("Distinct_count", mysql.TypeLonglong, 20),
+			buildResultField("Null_count", mysql.TypeLonglong, 20))
+	case ast.ShowStatsBuckets:
+		rs.fields = append(rs.fields,
+			buildResultField("Column_name", mysql.TypeVarchar, 64),
+			buildResultField("Is_index", mysql.TypeTiny, 1),
+			buildResultField("Bucket_id", mysql.TypeLonglong, 20),
+			buildResultField("Count", mysql.TypeLonglong, 20),
+			buildResultField("Repeats", mysql.TypeLonglong, 20),
+			buildResultField("Lower_Bound", mysql.TypeVarchar, 255),
+			buildResultField("Upper_Bound", mysql.TypeVarchar, 255))
+	}
+	for _, t := range s.statsTables() {
+		prefix := []types.Datum{types.NewStringDatum(t.dbName), types.NewStringDatum(t.tblInfo.Name.O), types.NewStringDatum("")}
+		if tp == ast.ShowStatsMeta {
+			rs.rows = append(rs.rows, append(prefix, versionToTime(t.stats.Version),
+				types.NewIntDatum(t.stats.ModifyCount), types.NewIntDatum(t.stats.Count)))
+			continue
+		}
+		for _, col := range t.tblInfo.Columns {
+			c, ok := t.stats.Columns[col.ID]
+			if !ok || c.Histogram == nil {
+				continue
+			}
+			err := appendHistogramRows(rs, tp, prefix, col.Name.O, false, c.Histogram, []*model.ColumnInfo{col})
+			if err != nil {
+				return nil, err
+			}
+		}
+		for _, idxInfo := range t.tblInfo.Indices {
+			idx, ok := t.stats.Indices[idxInfo.ID]
+			if !ok || idx.Histogram == nil {
+				continue
+			}
+			err := appendHistogramRows(rs, tp, prefix, idxInfo.Name.O, true, idx.Histogram, idx.ColInfos)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return rs, nil
+}
+
+// appendHistogramRows appends the row of the histogram or the rows of its buckets.
+func appendHistogramRows(rs *datumRecordSet, tp ast.ShowStmtType, prefix []types.Datum, name string, isIndex bool,
+	hg *statistics.Histogram, cols []*model.ColumnInfo) error {
+	isIndexDatum := types.NewIntDatum(0)
+	if isIndex {
+		isIndexDatum = types.NewIntDatum(1)
+	}
+	row := append(append([]types.Datum(nil), prefix...), types.NewStringDatum(name), isIndexDatum)
+	if tp == ast.ShowStatsHistograms {
+		rs.rows = append(rs.rows, append(row, versionToTime(hg.LastUpdateVersion),
+			types.NewIntDatum(hg.NDV), types.NewIntDatum(hg.NullCount)))
+		return nil
+	}
+	for i, bkt := range hg.Buckets {
+		lower, err := statistics.ValueToString(bkt.Lower, cols)
+		if err != nil {
+			return err
+		}
+		upper, err := statistics.ValueToString(bkt.Upper, cols)
+		if err != nil {
+			return err
+		}
+		rs.rows = append(rs.rows, append(append([]types.Datum(nil), row...), types.NewIntDatum(int64(i)),
+			types.NewIntDatum(bkt.Count), types.NewIntDatum(bkt.Repeat), types.NewStringDatum(lower), types.NewStringDatum(upper)))
+	}
+	return nil
+}
+
+// versionToTime converts the version of the statistics to the time, it's NULL if the table isn't analyzed.
+func versionToTime(version uint64) types.Datum {
+	if version == statistics.PseudoVersion {
+		return types.NewDatum(nil)
+	}
+	return types.NewTimeDatum(tsToTime(version))
+}
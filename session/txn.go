@@ -0,0 +1,381 @@
+package session
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/format"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
+	"grant-db/ddl"
+	"grant-db/domain"
+	"grant-db/expression"
+	"grant-db/kv"
+	"grant-db/meta"
+	"grant-db/sessionctx/variable"
+	"grant-db/types"
+	"grant-db/util/chunk"
+)
+
+var (
+	// ErrSavepointNotExists is returned by ROLLBACK TO SAVEPOINT and RELEASE SAVEPOINT if the savepoint doesn't exist.
+	ErrSavepointNotExists = terror.ClassSession.New(mysql.ErrSpDoesNotExist, mysql.MySQLErrName[mysql.ErrSpDoesNotExist])
+	// ErrStaleReadTooOld is returned when the timestamp of the stale read is older than the GC safe point.
+	ErrStaleReadTooOld = terror.ClassSession.New(codeGCTooEarly, "GC life time is shorter than transaction duration, transaction starts at %v, GC safe point is %v")
+	// ErrReadOnlyTxn is returned when a statement writes data in a transaction begun by START TRANSACTION READ ONLY.
+	ErrReadOnlyTxn = terror.ClassSession.New(mysql.ErrCantExecuteInReadOnlyTransaction, mysql.MySQLErrName[mysql.ErrCantExecuteInReadOnlyTransaction])
+)
+
+// codeGCTooEarly is the error code of the stale read older than the GC safe point, which is the one of TiDB.
+const codeGCTooEarly = 9006
+
+// savepoint is a savepoint of the explicit transaction, ROLLBACK TO SAVEPOINT discards the mutations of the
+// staging.
+type savepoint struct {
+	name    string
+	staging kv.StagingHandle
+}
+
+// The kinds of the savepoint statements.
+const (
+	savepointSet = iota
+	savepointRollback
+	savepointRelease
+)
+
+// savepointStmt is SAVEPOINT, ROLLBACK TO SAVEPOINT or RELEASE SAVEPOINT, which the parser doesn't support.
+// The embedded StmtNode is always nil, it only makes savepointStmt a statement.
+type savepointStmt struct {
+	ast.StmtNode
+	tp   int
+	name string
+	text string
+}
+
+// Restore implements ast.Node Restore interface.
+func (n *savepointStmt) Restore(ctx *format.RestoreCtx) error {
+	switch n.tp {
+	case savepointRollback:
+		ctx.WriteKeyWord("ROLLBACK TO SAVEPOINT ")
+	case savepointRelease:
+		ctx.WriteKeyWord("RELEASE SAVEPOINT ")
+	default:
+		ctx.WriteKeyWord("SAVEPOINT ")
+	}
+	ctx.WriteName(n.name)
+	return nil
+}
+
+// Accept implements ast.Node Accept interface.
+func (n *savepointStmt) Accept(v ast.Visitor) (ast.Node, bool) {
+	newNode, _ := v.Enter(n)
+	return v.Leave(newNode)
+}
+
+// Text implements ast.Node Text interface.
+func (n *savepointStmt) Text() string {
+	return n.text
+}
+
+// SetText implements ast.Node SetText interface.
+func (n *savepointStmt) SetText(text string) {
+	n.text = text
+}
+
+var savepointStmtRegexp = regexp.MustCompile("(?i)^\\s*(SAVEPOINT|ROLLBACK\\s+(?:WORK\\s+)?TO(?:\\s+SAVEPOINT)?|RELEASE\\s+SAVEPOINT)\\s+(`(?:[^`]|``)+`|\\w+)\\s*;?\\s*$")
+
+// parseSavepointStmt parses the SQL if it's a single savepoint statement, or returns nil.
+func parseSavepointStmt(sql string) *savepointStmt {
+	m := savepointStmtRegexp.FindStringSubmatch(sql)
+	if m == nil {
+		return nil
+	}
+	stmt := &savepointStmt{name: m[2], text: sql}
+	if strings.HasPrefix(stmt.name, "`") {
+		stmt.name = strings.ReplaceAll(stmt.name[1:len(stmt.name)-1], "``", "`")
+	}
+	switch strings.ToUpper(m[1][:2]) {
+	case "RO":
+		stmt.tp = savepointRollback
+	case "RE":
+		stmt.tp = savepointRelease
+	default:
+		stmt.tp = savepointSet
+	}
+	return stmt
+}
+
+// prepareTxn decides whether the mutations of the statement are staged, which is done when the statement
+// begins its transaction or joins the explicit one, the staged mutations are discarded if the statement fails.
+func (s *session) prepareTxn(stmt ast.StmtNode) error {
+	switch stmt.(type) {
+	case *ast.BeginStmt, *ast.CommitStmt, *ast.RollbackStmt, *savepointStmt, ast.DDLNode:
+		return nil
+	case *ast.InsertStmt, *ast.UpdateStmt, *ast.DeleteStmt:
+		if s.sessionVars.InTxn() && s.sessionVars.TxnCtx.ReadOnly {
+			return ErrReadOnlyTxn
+		}
+	}
+	s.stageStmt = true
+	return nil
+}
+
+// activateTxn begins the transaction if it isn't begun, the first statement which reads or writes data
+// begins the explicit transaction implicitly if autocommit is off like MySQL. The mutations of the statement
+// in the explicit transaction are staged. The transaction uses the schema of the statement which begins it,
+// and it's registered in the domain so the DDL waits for it before the next schema change.
+func (s *session) activateTxn() (kv.Transaction, error) {
+	vars := s.sessionVars
+	if s.txn == nil {
+		txn, err := s.store.Begin()
+		if err != nil {
+			return nil, err
+		}
+		s.txn = txn
+		if s.infoSchema != nil {
+			vars.TxnCtx.InfoSchema = s.infoSchema
+			vars.TxnCtx.SchemaVersion = s.infoSchema.SchemaMetaVersion()
+			domain.GetDomain(s).RegisterTxn(vars.TxnCtx.SchemaVersion)
+		}
+		if !vars.IsAutocommit() {
+			vars.SetStatusFlag(mysql.ServerStatusInTrans, true)
+		}
+	}
+	if s.stageStmt && s.stmtStaging == 0 && vars.InTxn() {
+		s.stmtStaging = s.txn.Staging()
+	}
+	return s.txn, nil
+}
+
+// finishStmt finishes the statement, which is committed on its own unless it's in the explicit transaction.
+// A failed statement is rolled back, in which case err is returned.
+func (s *session) finishStmt(err error) error {
+	s.stageStmt = false
+	if !s.sessionVars.InTxn() {
+		return s.finishTxn(err)
+	}
+	if s.stmtStaging != 0 {
+		if err != nil {
+			s.txn.Cleanup(s.stmtStaging)
+		} else {
+			s.txn.Release(s.stmtStaging)
+		}
+		s.stmtStaging = 0
+	}
+	return err
+}
+
+// finishTxn commits the transaction, or rolls it back if err is not nil, in which case err is returned.
+// The session is out of the explicit transaction then. The commit fails with domain.ErrInfoSchemaChanged if
+// the transaction writes a table changed by the DDL after the schema it uses.
+func (s *session) finishTxn(err error) error {
+	vars := s.sessionVars
+	vars.SetStatusFlag(mysql.ServerStatusInTrans, false)
+	s.stmtStaging, s.savepoints = 0, nil
+	txnCtx := vars.TxnCtx
+	vars.TxnCtx = variable.NewTransactionContext()
+	dom := domain.GetDomain(s)
+	if txnCtx.InfoSchema != nil {
+		defer dom.UnregisterTxn(txnCtx.SchemaVersion)
+	}
+	txn := s.txn
+	if txn == nil {
+		return err
+	}
+	s.txn = nil
+	if err != nil {
+		_ = txn.Rollback()
+		return err
+	}
+	if txnCtx.InfoSchema != nil && len(txnCtx.TableDeltaMap) > 0 {
+		tableIDs := make([]int64, 0, len(txnCtx.TableDeltaMap))
+		for tableID := range txnCtx.TableDeltaMap {
+			tableIDs = append(tableIDs, tableID)
+		}
+		txn.SetSchemaChecker(dom.SchemaChecker(txnCtx.SchemaVersion, tableIDs))
+	}
+	if err = txn.Commit(s.currentCtx); err != nil {
+		return err
+	}
+	statsHandle := dom.StatsHandle()
+	for tableID, item := range txnCtx.TableDeltaMap {
+		statsHandle.UpdateTableDelta(tableID, item.Delta, item.Count)
+	}
+	return nil
+}
+
+// rollbackTxn rolls back the transaction, the session is out of the explicit transaction then.
+func (s *session) rollbackTxn() {
+	txn := s.txn
+	s.txn = nil
+	_ = s.finishTxn(nil)
+	if txn != nil {
+		_ = txn.Rollback()
+	}
+}
+
+// executeBegin commits the explicit transaction implicitly and begins a new one. The transaction of READ ONLY
+// WITH TIMESTAMP BOUND reads the data and the schema at the timestamp of the bound.
+func (s *session) executeBegin(stmt *ast.BeginStmt) error {
+	if err := s.finishTxn(nil); err != nil {
+		return err
+	}
+	if stmt.Bound == nil {
+		return s.beginTxn(stmt.ReadOnly)
+	}
+	ts, err := s.staleReadTS(stmt.Bound)
+	if err != nil || ts == 0 {
+		if err == nil {
+			err = s.beginTxn(true)
+		}
+		return err
+	}
+	txn, err := s.store.BeginWithStartTS(ts)
+	if err != nil {
+		return err
+	}
+	s.txn = txn
+	s.sessionVars.TxnCtx.ReadOnly = true
+	s.sessionVars.TxnCtx.StaleReadTS = ts
+	s.sessionVars.SetStatusFlag(mysql.ServerStatusInTrans, true)
+	return nil
+}
+
+// staleReadTS returns the timestamp which the timestamp bound reads at, it's 0 if the latest data is read,
+// which satisfies STRONG, MAX STALENESS and MIN READ TIMESTAMP. EXACT STALENESS is a TIME before the
+// current time of the oracle.
+func (s *session) staleReadTS(bound *ast.TimestampBound) (uint64, error) {
+	var tp byte
+	switch bound.Mode {
+	case ast.TimestampBoundReadTimestamp:
+		tp = mysql.TypeDatetime
+	case ast.TimestampBoundExactStaleness:
+		tp = mysql.TypeDuration
+	default:
+		return 0, nil
+	}
+	expr, err := expression.RewriteSimpleExprWithNames(s, bound.Timestamp, expression.NewSchema(), nil)
+	if err != nil {
+		return 0, err
+	}
+	d, err := expr.Eval(chunk.Row{})
+	if err != nil {
+		return 0, err
+	}
+	ft := types.NewFieldType(tp)
+	ft.Decimal = int(types.MaxFsp)
+	d, err = d.ConvertTo(s.sessionVars.StmtCtx, ft)
+	if err != nil {
+		return 0, err
+	}
+	if d.IsNull() {
+		return 0, errors.New("the timestamp of the timestamp bound is NULL")
+	}
+	var ts uint64
+	if tp == mysql.TypeDuration {
+		ts, err = s.store.GetOracle().GetExactStaleTimestamp(d.GetMysqlDuration().Duration)
+	} else {
+		var t time.Time
+		if t, err = d.GetMysqlTime().GoTime(time.Local); err != nil {
+			return 0, err
+		}
+		ts, err = s.store.GetOracle().GetStaleTimestamp(t)
+	}
+	if err != nil {
+		return 0, err
+	}
+	var safePoint uint64
+	err = kv.RunInNewTxn(s.store, false, func(txn kv.Transaction) error {
+		safePoint, err = meta.NewMeta(txn).GetGCSafePoint()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	if ts < safePoint {
+		return 0, ErrStaleReadTooOld.GenWithStackByArgs(ts, safePoint)
+	}
+	return ts, nil
+}
+
+// beginTxn begins the explicit transaction, whose snapshot is taken at once like WITH CONSISTENT SNAPSHOT.
+func (s *session) beginTxn(readOnly bool) error {
+	if _, err := s.Txn(true); err != nil {
+		return err
+	}
+	s.sessionVars.TxnCtx.ReadOnly = readOnly
+	s.sessionVars.SetStatusFlag(mysql.ServerStatusInTrans, true)
+	return nil
+}
+
+// executeCommit commits the explicit transaction, AND CHAIN begins a new one with the same access mode.
+func (s *session) executeCommit(stmt *ast.CommitStmt) error {
+	if stmt.CompletionType == ast.CompletionTypeRelease {
+		return ddl.ErrNotSupportedYet.GenWithStackByArgs("COMMIT RELEASE")
+	}
+	readOnly := s.sessionVars.TxnCtx.ReadOnly
+	if err := s.finishTxn(nil); err != nil {
+		return err
+	}
+	if stmt.CompletionType == ast.CompletionTypeChain {
+		return s.beginTxn(readOnly)
+	}
+	return nil
+}
+
+// executeRollback rolls back the explicit transaction, AND CHAIN begins a new one with the same access mode.
+func (s *session) executeRollback(stmt *ast.RollbackStmt) error {
+	if stmt.CompletionType == ast.CompletionTypeRelease {
+		return ddl.ErrNotSupportedYet.GenWithStackByArgs("ROLLBACK RELEASE")
+	}
+	readOnly := s.sessionVars.TxnCtx.ReadOnly
+	s.rollbackTxn()
+	if stmt.CompletionType == ast.CompletionTypeChain {
+		return s.beginTxn(readOnly)
+	}
+	return nil
+}
+
+// executeSavepoint executes the savepoint statement. SAVEPOINT replaces the savepoint of the same name, and
+// it does nothing out of the explicit transaction like MySQL. ROLLBACK TO SAVEPOINT keeps the savepoint but
+// removes the ones set after it, and RELEASE SAVEPOINT removes both.
+func (s *session) executeSavepoint(stmt *savepointStmt) error {
+	idx := -1
+	for i, sp := range s.savepoints {
+		if strings.EqualFold(sp.name, stmt.name) {
+			idx = i
+		}
+	}
+	if stmt.tp == savepointSet {
+		if !s.sessionVars.InTxn() && s.sessionVars.IsAutocommit() {
+			return nil
+		}
+		txn, err := s.Txn(true)
+		if err != nil {
+			return err
+		}
+		if idx >= 0 {
+			txn.Release(s.savepoints[idx].staging)
+			s.savepoints = append(s.savepoints[:idx], s.savepoints[idx+1:]...)
+		}
+		s.savepoints = append(s.savepoints, savepoint{name: stmt.name, staging: txn.Staging()})
+		return nil
+	}
+	if idx < 0 {
+		return ErrSavepointNotExists.GenWithStackByArgs("SAVEPOINT", stmt.name)
+	}
+	if stmt.tp == savepointRelease {
+		for _, sp := range s.savepoints[idx:] {
+			s.txn.Release(sp.staging)
+		}
+		s.savepoints = s.savepoints[:idx]
+		return nil
+	}
+	s.txn.Cleanup(s.savepoints[idx].staging)
+	s.savepoints[idx].staging = s.txn.Staging()
+	s.savepoints = s.savepoints[:idx+1]
+	return nil
+}
@@ -0,0 +1,144 @@
+package session_test
+
+import (
+	"testing"
+
+	"github.com/pingcap/parser/mysql"
+	"grant-db/util/testkit"
+)
+
+// checkStatus checks the transaction status bits of the session.
+func checkStatus(t *testing.T, tk *testkit.TestKit, inTxn, autocommit bool) {
+	t.Helper()
+	status := tk.Se.Status()
+	if got := status&mysql.ServerStatusInTrans != 0; got != inTxn {
+		t.Fatalf("the session is in the transaction: %v, expected %v", got, inTxn)
+	}
+	if got := status&mysql.ServerStatusAutocommit != 0; got != autocommit {
+		t.Fatalf("the session is in the autocommit mode: %v, expected %v", got, autocommit)
+	}
+}
+
+func TestExplicitTxn(t *testing.T) {
+	store, _ := testkit.NewMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk2 := testkit.NewTestKit(t, store)
+	tk.MustExec("create database test")
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int primary key, b int)")
+	checkStatus(t, tk, false, true)
+
+	// The mutations of the transaction aren't seen by the other sessions until it's committed.
+	tk.MustExec("begin")
+	checkStatus(t, tk, true, true)
+	tk.MustExec("insert into t values (1, 1)")
+	tk.MustQuery("select a from t").Check("1")
+	tk2.MustQuery("select a from test.t").Check()
+	tk.MustExec("commit")
+	checkStatus(t, tk, false, true)
+	tk2.MustQuery("select a from test.t").Check("1")
+
+	tk.MustExec("start transaction")
+	tk.MustExec("insert into t values (2, 2)")
+	tk.MustExec("rollback")
+	checkStatus(t, tk, false, true)
+	tk.MustQuery("select a from t").Check("1")
+
+	// The failed statement is rolled back alone.
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values (3, 3)")
+	tk.MustGetErrCode("insert into t values (4, 4), (1, 1)", mysql.ErrDupEntry)
+	checkStatus(t, tk, true, true)
+	tk.MustQuery("select a from t").Check("1", "3")
+
+	// The DDL and BEGIN commit the transaction implicitly.
+	tk.MustExec("create table x (a int)")
+	checkStatus(t, tk, false, true)
+	tk2.MustQuery("select a from test.t").Check("1", "3")
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values (4, 4)")
+	tk.MustExec("begin")
+	tk2.MustQuery("select a from test.t").Check("1", "3", "4")
+	tk.MustExec("rollback")
+
+	tk.MustExec("start transaction read only")
+	checkStatus(t, tk, true, true)
+	tk.MustGetErrCode("insert into t values (5, 5)", mysql.ErrCantExecuteInReadOnlyTransaction)
+	tk.MustQuery("select count(*) from t").Check("3")
+	tk.MustExec("commit")
+}
+
+func TestSavepoint(t *testing.T) {
+	store, _ := testkit.NewMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create database test")
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int primary key, b int)")
+	tk.MustExec("begin")
+	tk.MustExec("insert into t values (1, 1)")
+	tk.MustExec("savepoint s1")
+	tk.MustExec("insert into t values (2, 2)")
+	tk.MustExec("savepoint s2")
+	tk.MustExec("update t set b = 10")
+	tk.MustExec("rollback to savepoint s2")
+	tk.MustQuery("select * from t").Check("1 1", "2 2")
+
+	// The savepoints after the one rolled back to are removed, the one rolled back to is kept.
+	tk.MustExec("rollback to savepoint s1")
+	checkStatus(t, tk, true, true)
+	tk.MustQuery("select * from t").Check("1 1")
+	tk.MustGetErrCode("rollback to s2", mysql.ErrSpDoesNotExist)
+	tk.MustExec("insert into t values (3, 3)")
+	tk.MustExec("rollback work to s1")
+	tk.MustQuery("select * from t").Check("1 1")
+	tk.MustExec("release savepoint s1")
+	tk.MustGetErrCode("rollback to savepoint s1", mysql.ErrSpDoesNotExist)
+	tk.MustGetErrCode("release savepoint s1", mysql.ErrSpDoesNotExist)
+	tk.MustExec("savepoint `s 1`")
+	tk.MustExec("insert into t values (4, 4)")
+	tk.MustExec("rollback to `s 1`")
+	tk.MustExec("commit")
+	tk.MustQuery("select * from t").Check("1 1")
+
+	// The savepoints are released by the end of the transaction.
+	tk.MustExec("begin")
+	tk.MustExec("savepoint s1")
+	tk.MustExec("commit")
+	tk.MustGetErrCode("rollback to s1", mysql.ErrSpDoesNotExist)
+}
+
+func TestAutocommit(t *testing.T) {
+	store, _ := testkit.NewMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk2 := testkit.NewTestKit(t, store)
+	tk.MustExec("create database test")
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int primary key, b int)")
+
+	// The first statement which reads or writes data begins the transaction if autocommit is off.
+	tk.MustExec("set autocommit = 0")
+	checkStatus(t, tk, false, false)
+	tk.MustQuery("select @@autocommit").Check("0")
+	tk.MustExec("insert into t values (1, 1)")
+	checkStatus(t, tk, true, false)
+	tk2.MustQuery("select count(*) from test.t").Check("0")
+	tk.MustExec("commit")
+	checkStatus(t, tk, false, false)
+	tk2.MustQuery("select count(*) from test.t").Check("1")
+	tk.MustQuery("select a from t").Check("1")
+	checkStatus(t, tk, true, false)
+	tk.MustExec("delete from t")
+	tk.MustExec("rollback")
+	checkStatus(t, tk, false, false)
+	tk.MustQuery("select a from t").Check("1")
+
+	// Turning autocommit on commits the transaction.
+	tk.MustExec("insert into t values (2, 2)")
+	tk.MustExec("set autocommit = 1")
+	checkStatus(t, tk, false, true)
+	tk2.MustQuery("select count(*) from test.t").Check("2")
+	tk.MustExec("insert into t values (3, 3)")
+	checkStatus(t, tk, false, true)
+	tk2.MustQuery("select count(*) from test.t").Check("3")
+	tk.MustGetErrCode("set autocommit = 2", mysql.ErrWrongValueForVar)
+}
@@ -2,6 +2,7 @@ package sessionctx
 
 import (
 	"fmt"
+	"grant-db/kv"
 	"grant-db/sessionctx/variable"
 )
 
@@ -9,4 +10,9 @@ type Context interface {
 	SetValue(key fmt.Stringer, value interface{})
 	Value(key fmt.Stringer) interface{}
 	GetSessionVars() *variable.SessionVars
+	// GetStore returns the store of session.
+	GetStore() kv.Storage
+	// Txn returns the transaction of the current statement, a new one is begun if there is none and active
+	// is true, otherwise nil is returned.
+	Txn(active bool) (kv.Transaction, error)
 }
@@ -0,0 +1,185 @@
+package stmtctx
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"grant-db/util/execdetails"
+	"grant-db/util/memory"
+)
+
+// SQLWarn relates a sql warning and it's level.
+type SQLWarn struct {
+	Level string
+	Err   error
+}
+
+const (
+	// WarnLevelError represents level "Error" for 'SHOW WARNINGS' syntax.
+	WarnLevelError = "Error"
+	// WarnLevelWarning represents level "Warning" for 'SHOW WARNINGS' syntax.
+	WarnLevelWarning = "Warning"
+	// WarnLevelNote represents level "Note" for 'SHOW WARNINGS' syntax.
+	WarnLevelNote = "Note"
+)
+
+// StatementContext contains variables for a statement.
+// It should be reset before executing a statement.
+type StatementContext struct {
+	// Set the following variables before execution
+	InInsertStmt bool
+	InUpdateStmt bool
+	InDeleteStmt bool
+	InSelectStmt bool
+	// IgnoreZeroInDate allows the zero month or day in a date, like '2020-00-01'.
+	IgnoreZeroInDate bool
+	// NoZeroDate rejects the zero date '0000-00-00'.
+	NoZeroDate bool
+	// AllowInvalidDate allows any day from 1 to 31 in any month, like '2020-02-31'.
+	AllowInvalidDate bool
+
+	// TruncateAsWarning turns a truncation error into a warning.
+	TruncateAsWarning bool
+	// OverflowAsWarning turns an overflow error into a warning.
+	OverflowAsWarning bool
+	// DividedByZeroAsWarning turns a division by zero into a warning, the result is NULL.
+	DividedByZeroAsWarning bool
+	// BadNullAsWarning turns the error of a NULL written to a NOT NULL column into a warning, the zero
+	// value of the column is written instead.
+	BadNullAsWarning bool
+
+	// InsertID is the value explicitly written to the AUTO_INCREMENT column by the first row of an INSERT,
+	// it's 0 if there is no such value.
+	InsertID uint64
+	// LastInsertID is the first ID allocated for the AUTO_INCREMENT or AUTO_RANDOM column by the statement,
+	// or the value set by LAST_INSERT_ID(expr). PrevLastInsertID is the value LAST_INSERT_ID() returns, which
+	// is the last one of the previous statements.
+	LastInsertID     uint64
+	PrevLastInsertID uint64
+
+	// RuntimeStatsColl collects the runtime statistics of the executors, it's nil unless the statement is
+	// run by EXPLAIN ANALYZE.
+	RuntimeStatsColl *execdetails.RuntimeStatsColl
+	// MemTracker and DiskTracker track the memory and the disk used by the executors of the statement.
+	MemTracker  *memory.Tracker
+	DiskTracker *memory.Tracker
+
+	mu struct {
+		sync.Mutex
+		warnings []SQLWarn
+		// affectedRows is the count of the rows changed by the DML, message is the info of the OK packet.
+		affectedRows uint64
+		message      string
+	}
+	// nowTs is the current time of the statement, all the NOW() in a statement return the same value.
+	nowTs time.Time
+}
+
+// GetNowTsCached returns the current time of the statement, it's taken when the method is called first.
+func (sc *StatementContext) GetNowTsCached() time.Time {
+	if sc.nowTs.IsZero() {
+		sc.nowTs = time.Now()
+	}
+	return sc.nowTs
+}
+
+// AddAffectedRows adds the count of the affected rows.
+func (sc *StatementContext) AddAffectedRows(rows uint64) {
+	sc.mu.Lock()
+	sc.mu.affectedRows += rows
+	sc.mu.Unlock()
+}
+
+// AffectedRows gets the count of the affected rows.
+func (sc *StatementContext) AffectedRows() uint64 {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return sc.mu.affectedRows
+}
+
+// SetMessage sets the info message of the statement, which is sent in the OK packet.
+func (sc *StatementContext) SetMessage(msg string) {
+	sc.mu.Lock()
+	sc.mu.message = msg
+	sc.mu.Unlock()
+}
+
+// GetMessage gets the info message of the statement.
+func (sc *StatementContext) GetMessage() string {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return sc.mu.message
+}
+
+// GetWarnings gets warnings.
+func (sc *StatementContext) GetWarnings() []SQLWarn {
+	sc.mu.Lock()
+	warns := make([]SQLWarn, len(sc.mu.warnings))
+	copy(warns, sc.mu.warnings)
+	sc.mu.Unlock()
+	return warns
+}
+
+// WarningCount gets warning count.
+func (sc *StatementContext) WarningCount() uint16 {
+	sc.mu.Lock()
+	wc := uint16(len(sc.mu.warnings))
+	sc.mu.Unlock()
+	return wc
+}
+
+// SetWarnings sets warnings.
+func (sc *StatementContext) SetWarnings(warns []SQLWarn) {
+	sc.mu.Lock()
+	sc.mu.warnings = warns
+	sc.mu.Unlock()
+}
+
+// AppendWarning appends a warning with level 'Warning'.
+func (sc *StatementContext) AppendWarning(warn error) {
+	sc.appendWarning(WarnLevelWarning, warn)
+}
+
+// AppendNote appends a warning with level 'Note'.
+func (sc *StatementContext) AppendNote(warn error) {
+	sc.appendWarning(WarnLevelNote, warn)
+}
+
+// AppendError appends a warning with level 'Error'.
+func (sc *StatementContext) AppendError(warn error) {
+	sc.appendWarning(WarnLevelError, warn)
+}
+
+func (sc *StatementContext) appendWarning(level string, warn error) {
+	sc.mu.Lock()
+	// The warnings beyond the max count of SHOW WARNINGS are dropped.
+	if len(sc.mu.warnings) < math.MaxUint16 {
+		sc.mu.warnings = append(sc.mu.warnings, SQLWarn{level, warn})
+	}
+	sc.mu.Unlock()
+}
+
+// HandleTruncate ignores or returns the error based on the StatementContext state.
+func (sc *StatementContext) HandleTruncate(err error) error {
+	if err == nil {
+		return nil
+	}
+	if sc.TruncateAsWarning {
+		sc.AppendWarning(err)
+		return nil
+	}
+	return err
+}
+
+// HandleOverflow treats ErrOverflow as warnings or returns the error based on the StmtCtx.OverflowAsWarning state.
+func (sc *StatementContext) HandleOverflow(err error, warnErr error) error {
+	if err == nil {
+		return nil
+	}
+	if sc.OverflowAsWarning {
+		sc.AppendWarning(warnErr)
+		return nil
+	}
+	return err
+}
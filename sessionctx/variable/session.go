@@ -1,6 +1,11 @@
 package variable
 
-import "github.com/pingcap/parser/mysql"
+import (
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
+	"grant-db/sessionctx/stmtctx"
+	"grant-db/util/charset"
+)
 
 type SessionVars struct {
 	// Status stands for the session status
@@ -8,10 +13,187 @@ type SessionVars struct {
 	Status           uint16
 	ClientCapability uint32
 	ConnectionID     uint64
+	// CurrentDB is the default database of this session.
+	CurrentDB string
+	// StmtCtx holds variables for current executing statement.
+	StmtCtx *stmtctx.StatementContext
+	// TxnCtx holds variables for the current transaction.
+	TxnCtx *TransactionContext
+	// PlanID is the unique id allocated for the plans of a statement.
+	PlanID int
+	// PlanColumnID is the unique id allocated for the columns of expressions.
+	PlanColumnID int64
+	// EnableVectorizedExpression enables the vectorized evaluation of the expressions.
+	EnableVectorizedExpression bool
+	// MaxChunkSize is the max number of rows in a chunk returned by an executor.
+	MaxChunkSize int
+	// InitChunkSize is the number of rows the first chunk of an executor is allocated for, the capacity of
+	// the later chunks grows up to MaxChunkSize.
+	InitChunkSize int
+	// MemQuotaQuery is the memory quota of a query in bytes, there is no quota if it's not positive.
+	MemQuotaQuery int64
+	// HashJoinConcurrency is the number of the probe workers of a hash join.
+	HashJoinConcurrency int
+	// IndexJoinBatchSize is the number of the outer rows of a batch of an index join.
+	IndexJoinBatchSize int
+	// HashAggPartialConcurrency and HashAggFinalConcurrency are the numbers of the partial workers and the
+	// final workers of a hash aggregation.
+	HashAggPartialConcurrency int
+	HashAggFinalConcurrency   int
+	// GroupConcatMaxLen is the max length in bytes of the result of GROUP_CONCAT.
+	GroupConcatMaxLen int
+	// AutoIncrementIncrement and AutoIncrementOffset are the interval and the first value of the AUTO_INCREMENT
+	// IDs allocated for the session.
+	AutoIncrementIncrement int
+	AutoIncrementOffset    int
+	// SQLMode is the sql_mode of the session.
+	SQLMode mysql.SQLMode
+	// StrictSQLMode indicates if the session is in strict mode.
+	StrictSQLMode bool
+	// GlobalVarsAccessor reads the global values of the system variables.
+	GlobalVarsAccessor GlobalVarAccessor
+	// systems holds the values of the system variables which are kept as strings, like the charsets.
+	systems map[string]string
+}
+
+// GlobalVarAccessor reads the global values of the system variables, which are kept out of the sessions.
+type GlobalVarAccessor interface {
+	// GetGlobalSysVar returns the global value of the system variable.
+	GetGlobalSysVar(name string) (string, error)
+}
+
+// TableDelta is the change of a table made by a transaction, Delta is the change of the row count and Count is
+// the number of the modified rows.
+type TableDelta struct {
+	Delta int64
+	Count int64
+}
+
+// TransactionContext holds the variables of a transaction.
+type TransactionContext struct {
+	// TableDeltaMap is the changes of the tables made by the transaction, they're applied to the statistics
+	// after the transaction is committed.
+	TableDeltaMap map[int64]TableDelta
+	// ReadOnly is true if the transaction is begun by START TRANSACTION READ ONLY.
+	ReadOnly bool
+	// StaleReadTS is the start timestamp of the transaction which reads the data before it's begun, the
+	// statements in it use the schema at the timestamp.
+	StaleReadTS uint64
+	// InfoSchema is the schema used by all the statements of the transaction, it's nil before the transaction
+	// is begun and for the stale read transaction. It's an infoschema.InfoSchema.
+	InfoSchema interface{}
+	// SchemaVersion is the version of InfoSchema.
+	SchemaVersion int64
+}
+
+// NewTransactionContext creates a TransactionContext for a new transaction.
+func NewTransactionContext() *TransactionContext {
+	return &TransactionContext{TableDeltaMap: make(map[int64]TableDelta)}
+}
+
+// UpdateDeltaForTable records the change of a table.
+func (tc *TransactionContext) UpdateDeltaForTable(tableID, delta, count int64) {
+	item := tc.TableDeltaMap[tableID]
+	item.Delta += delta
+	item.Count += count
+	tc.TableDeltaMap[tableID] = item
 }
 
 func NewSessionVars() *SessionVars {
-	return &SessionVars{
-		Status: mysql.ServerStatusAutocommit,
+	vars := &SessionVars{
+		Status:                     mysql.ServerStatusAutocommit,
+		StmtCtx:                    new(stmtctx.StatementContext),
+		TxnCtx:                     NewTransactionContext(),
+		EnableVectorizedExpression: true,
+		MaxChunkSize:               DefMaxChunkSize,
+		InitChunkSize:              DefInitChunkSize,
+		MemQuotaQuery:              DefTiDBMemQuotaQuery,
+		HashJoinConcurrency:        DefTiDBHashJoinConcurrency,
+		IndexJoinBatchSize:         DefTiDBIndexJoinBatchSize,
+		HashAggPartialConcurrency:  DefTiDBHashAggConcurrency,
+		HashAggFinalConcurrency:    DefTiDBHashAggConcurrency,
+		GroupConcatMaxLen:          DefGroupConcatMaxLen,
+		AutoIncrementIncrement:     DefAutoIncrementIncrement,
+		AutoIncrementOffset:        DefAutoIncrementOffset,
+		systems:                    make(map[string]string),
+	}
+	for _, name := range []string{SQLModeVar, CharacterSetClient, CharacterSetConnection, CollationConnection,
+		CharacterSetResults, CharacterSetServer, CollationServer} {
+		vars.systems[name] = sysVarDefaults[name]
+	}
+	mode, err := mysql.GetSQLMode(mysql.DefaultSQLMode)
+	terror.Log(err)
+	vars.SetSQLMode(mode)
+	return vars
+}
+
+// SetSQLMode sets the sql_mode of the session.
+func (s *SessionVars) SetSQLMode(mode mysql.SQLMode) {
+	s.SQLMode = mode
+	s.StrictSQLMode = mode.HasStrictMode()
+}
+
+// SetStatusFlag sets or clears the flag of the server status.
+func (s *SessionVars) SetStatusFlag(flag uint16, on bool) {
+	if on {
+		s.Status |= flag
+		return
+	}
+	s.Status &^= flag
+}
+
+// InTxn returns whether the session is in an explicit transaction, which is begun by BEGIN or when autocommit
+// is off.
+func (s *SessionVars) InTxn() bool {
+	return s.Status&mysql.ServerStatusInTrans != 0
+}
+
+// IsAutocommit returns whether autocommit is on.
+func (s *SessionVars) IsAutocommit() bool {
+	return s.Status&mysql.ServerStatusAutocommit != 0
+}
+
+// AllocPlanID allocates the id of a plan.
+func (s *SessionVars) AllocPlanID() int {
+	s.PlanID++
+	return s.PlanID
+}
+
+// AllocPlanColumnID allocates column id for plan.
+func (s *SessionVars) AllocPlanColumnID() int64 {
+	s.PlanColumnID++
+	return s.PlanColumnID
+}
+
+// GetSystemVar returns the value of a system variable which is kept as a string.
+func (s *SessionVars) GetSystemVar(name string) (string, bool) {
+	val, ok := s.systems[name]
+	return val, ok
+}
+
+// GetCharsetInfo returns the charset and the collation of the connection, which are used for the literals.
+func (s *SessionVars) GetCharsetInfo() (string, string) {
+	return s.systems[CharacterSetConnection], s.systems[CollationConnection]
+}
+
+// SetNames sets the charset of the client, the connection and the results like SET NAMES, the collation of
+// the connection is the default one of the charset if coll is empty.
+func (s *SessionVars) SetNames(cs, coll string) error {
+	if err := s.setCharsetCollation(CharacterSetConnection, CollationConnection, cs, coll); err != nil {
+		return err
+	}
+	cs = s.systems[CharacterSetConnection]
+	s.systems[CharacterSetClient], s.systems[CharacterSetResults] = cs, cs
+	return nil
+}
+
+// setCharsetCollation sets a pair of charset and collation variables, the missing one of cs and coll
+// is derived from the other.
+func (s *SessionVars) setCharsetCollation(csVar, collVar, cs, coll string) error {
+	cs, coll, err := charset.ResolveCharsetCollation(cs, coll)
+	if err != nil {
+		return err
 	}
+	s.systems[csVar], s.systems[collVar] = cs, coll
+	return nil
 }
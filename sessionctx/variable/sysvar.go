@@ -0,0 +1,330 @@
+package variable
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
+	"grant-db/util/charset"
+)
+
+// The names of the system variables.
+const (
+	// SQLModeVar is the name of the sql_mode system variable.
+	SQLModeVar = "sql_mode"
+	// Autocommit is whether each statement is committed on its own, the statements are in an explicit
+	// transaction until COMMIT or ROLLBACK if it's off.
+	Autocommit = "autocommit"
+	// CharacterSetClient is the charset of the statements sent by the client.
+	CharacterSetClient = "character_set_client"
+	// CharacterSetConnection is the charset of the literals without a charset introducer.
+	CharacterSetConnection = "character_set_connection"
+	// CollationConnection is the collation of the literals.
+	CollationConnection = "collation_connection"
+	// CharacterSetResults is the charset of the results sent to the client, empty means no conversion.
+	CharacterSetResults = "character_set_results"
+	// CharacterSetServer is the default charset of the server.
+	CharacterSetServer = "character_set_server"
+	// CollationServer is the default collation of the server.
+	CollationServer = "collation_server"
+	// GroupConcatMaxLen is the max length in bytes of the result of GROUP_CONCAT.
+	GroupConcatMaxLen = "group_concat_max_len"
+	// AutoIncrementIncrement is the interval between the AUTO_INCREMENT IDs allocated for the session.
+	AutoIncrementIncrement = "auto_increment_increment"
+	// AutoIncrementOffset is the first AUTO_INCREMENT ID allocated for the session, it's ignored if it's
+	// greater than auto_increment_increment.
+	AutoIncrementOffset = "auto_increment_offset"
+	// TiDBAutoAnalyzeRatio is the ratio of the modified rows which makes a table analyzed automatically, it's
+	// a global variable.
+	TiDBAutoAnalyzeRatio = "tidb_auto_analyze_ratio"
+	// TiDBMaxChunkSize is the max number of rows in a chunk returned by an executor.
+	TiDBMaxChunkSize = "tidb_max_chunk_size"
+	// TiDBInitChunkSize is the number of rows the first chunk of an executor is allocated for.
+	TiDBInitChunkSize = "tidb_init_chunk_size"
+	// TiDBMemQuotaQuery is the memory quota of a query in bytes, the executors spill to the disk or the
+	// query is cancelled when it's exceeded.
+	TiDBMemQuotaQuery = "tidb_mem_quota_query"
+	// TiDBHashJoinConcurrency is the number of the workers which probe the hash table of a hash join.
+	TiDBHashJoinConcurrency = "tidb_hash_join_concurrency"
+	// TiDBIndexJoinBatchSize is the number of the outer rows whose inner rows are read together by an
+	// index join.
+	TiDBIndexJoinBatchSize = "tidb_index_join_batch_size"
+	// TiDBHashAggPartialConcurrency is the number of the workers which aggregate the rows of the child of a
+	// hash aggregation into the partial results.
+	TiDBHashAggPartialConcurrency = "tidb_hashagg_partial_concurrency"
+	// TiDBHashAggFinalConcurrency is the number of the workers which merge the partial results of a hash
+	// aggregation. A hash aggregation is executed by a single goroutine, which spills the rows to the disk when
+	// the memory quota is exceeded, if both the concurrencies are 1.
+	TiDBHashAggFinalConcurrency = "tidb_hashagg_final_concurrency"
+)
+
+// The default values and the limits of the chunk sizes.
+const (
+	DefMaxChunkSize  = 1024
+	DefInitChunkSize = 32
+	// minMaxChunkSize is the lower bound of tidb_max_chunk_size, and maxInitChunkSize is the upper bound of
+	// tidb_init_chunk_size.
+	minMaxChunkSize  = 32
+	maxInitChunkSize = 32
+)
+
+// minGroupConcatMaxLen is the lower bound of group_concat_max_len.
+const minGroupConcatMaxLen = 4
+
+// The default values of the variables of the executors.
+const (
+	DefTiDBMemQuotaQuery       = 1 << 30
+	DefTiDBHashJoinConcurrency = 5
+	DefTiDBIndexJoinBatchSize  = 25000
+	DefTiDBHashAggConcurrency  = 4
+	DefGroupConcatMaxLen       = 1024
+	DefAutoIncrementIncrement  = 1
+	DefAutoIncrementOffset     = 1
+)
+
+// maxAutoIncrementStep is the upper bound of auto_increment_increment and auto_increment_offset.
+const maxAutoIncrementStep = 65535
+
+var (
+	// ErrUnknownSystemVariable is returned when setting an unknown system variable.
+	ErrUnknownSystemVariable = terror.ClassVariable.New(mysql.ErrUnknownSystemVariable, mysql.MySQLErrName[mysql.ErrUnknownSystemVariable])
+	// ErrWrongValueForVar is returned when a system variable is set to an invalid value.
+	ErrWrongValueForVar = terror.ClassVariable.New(mysql.ErrWrongValueForVar, mysql.MySQLErrName[mysql.ErrWrongValueForVar])
+	// ErrGlobalVariable is returned when a global variable is set without GLOBAL.
+	ErrGlobalVariable = terror.ClassVariable.New(mysql.ErrGlobalVariable, mysql.MySQLErrName[mysql.ErrGlobalVariable])
+	// ErrWrongTypeForVar is returned when a system variable is set to a value of the wrong type.
+	ErrWrongTypeForVar = terror.ClassVariable.New(mysql.ErrWrongTypeForVar, mysql.MySQLErrName[mysql.ErrWrongTypeForVar])
+)
+
+// sysVarDefaults holds the default values of the supported system variables.
+var sysVarDefaults = map[string]string{
+	SQLModeVar:                    mysql.DefaultSQLMode,
+	Autocommit:                    "1",
+	CharacterSetClient:            mysql.DefaultCharset,
+	CharacterSetConnection:        mysql.DefaultCharset,
+	CollationConnection:           mysql.DefaultCollationName,
+	CharacterSetResults:           mysql.DefaultCharset,
+	CharacterSetServer:            mysql.DefaultCharset,
+	CollationServer:               mysql.DefaultCollationName,
+	GroupConcatMaxLen:             strconv.Itoa(DefGroupConcatMaxLen),
+	AutoIncrementIncrement:        strconv.Itoa(DefAutoIncrementIncrement),
+	AutoIncrementOffset:           strconv.Itoa(DefAutoIncrementOffset),
+	TiDBAutoAnalyzeRatio:          "0.5",
+	TiDBMaxChunkSize:              strconv.Itoa(DefMaxChunkSize),
+	TiDBInitChunkSize:             strconv.Itoa(DefInitChunkSize),
+	TiDBMemQuotaQuery:             strconv.Itoa(DefTiDBMemQuotaQuery),
+	TiDBHashJoinConcurrency:       strconv.Itoa(DefTiDBHashJoinConcurrency),
+	TiDBIndexJoinBatchSize:        strconv.Itoa(DefTiDBIndexJoinBatchSize),
+	TiDBHashAggPartialConcurrency: strconv.Itoa(DefTiDBHashAggConcurrency),
+	TiDBHashAggFinalConcurrency:   strconv.Itoa(DefTiDBHashAggConcurrency),
+}
+
+// GetSysVarDefault returns the default value of the system variable, the second returned value
+// reports whether the variable is known.
+func GetSysVarDefault(name string) (string, bool) {
+	val, ok := sysVarDefaults[strings.ToLower(name)]
+	return val, ok
+}
+
+// IsNullable returns whether the system variable can be set to NULL, which is taken as an empty string.
+func IsNullable(name string) bool {
+	return strings.ToLower(name) == CharacterSetResults
+}
+
+// SetSessionSystemVar sets the value of a system variable in the session.
+func SetSessionSystemVar(vars *SessionVars, name string, value string) error {
+	name = strings.ToLower(name)
+	switch name {
+	case SQLModeVar:
+		mode, err := mysql.GetSQLMode(mysql.FormatSQLModeStr(value))
+		if err != nil {
+			return ErrWrongValueForVar.GenWithStackByArgs(name, value)
+		}
+		vars.SetSQLMode(mode)
+		vars.systems[name] = mysql.FormatSQLModeStr(value)
+		return nil
+	case Autocommit:
+		switch strings.ToUpper(value) {
+		case "1", "ON":
+			vars.SetStatusFlag(mysql.ServerStatusAutocommit, true)
+		case "0", "OFF":
+			vars.SetStatusFlag(mysql.ServerStatusAutocommit, false)
+		default:
+			return ErrWrongValueForVar.GenWithStackByArgs(name, value)
+		}
+		return nil
+	case CharacterSetResults:
+		if value == "" {
+			vars.systems[name] = ""
+			return nil
+		}
+		fallthrough
+	case CharacterSetClient:
+		cs, err := charset.GetCharsetInfo(value)
+		if err != nil {
+			return err
+		}
+		vars.systems[name] = cs.Name
+		return nil
+	case CharacterSetConnection:
+		return vars.setCharsetCollation(CharacterSetConnection, CollationConnection, value, "")
+	case CollationConnection:
+		return vars.setCharsetCollation(CharacterSetConnection, CollationConnection, "", value)
+	case CharacterSetServer:
+		return vars.setCharsetCollation(CharacterSetServer, CollationServer, value, "")
+	case CollationServer:
+		return vars.setCharsetCollation(CharacterSetServer, CollationServer, "", value)
+	case TiDBAutoAnalyzeRatio:
+		return ErrGlobalVariable.GenWithStackByArgs(name)
+	case TiDBMaxChunkSize:
+		size, err := parseIntInRange(name, value, minMaxChunkSize, math.MaxInt32)
+		if err != nil {
+			return err
+		}
+		vars.MaxChunkSize = size
+		return nil
+	case TiDBInitChunkSize:
+		size, err := parseIntInRange(name, value, 1, maxInitChunkSize)
+		if err != nil {
+			return err
+		}
+		vars.InitChunkSize = size
+		return nil
+	case TiDBMemQuotaQuery:
+		quota, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return ErrWrongTypeForVar.GenWithStackByArgs(name)
+		}
+		vars.MemQuotaQuery = quota
+		return nil
+	case TiDBHashJoinConcurrency:
+		concurrency, err := parseIntInRange(name, value, 1, math.MaxInt32)
+		if err != nil {
+			return err
+		}
+		vars.HashJoinConcurrency = concurrency
+		return nil
+	case TiDBIndexJoinBatchSize:
+		size, err := parseIntInRange(name, value, 1, math.MaxInt32)
+		if err != nil {
+			return err
+		}
+		vars.IndexJoinBatchSize = size
+		return nil
+	case TiDBHashAggPartialConcurrency, TiDBHashAggFinalConcurrency:
+		concurrency, err := parseIntInRange(name, value, 1, math.MaxInt32)
+		if err != nil {
+			return err
+		}
+		if name == TiDBHashAggPartialConcurrency {
+			vars.HashAggPartialConcurrency = concurrency
+		} else {
+			vars.HashAggFinalConcurrency = concurrency
+		}
+		return nil
+	case GroupConcatMaxLen:
+		maxLen, err := parseIntInRange(name, value, minGroupConcatMaxLen, math.MaxInt64)
+		if err != nil {
+			return err
+		}
+		vars.GroupConcatMaxLen = maxLen
+		return nil
+	case AutoIncrementIncrement, AutoIncrementOffset:
+		val, err := parseIntInRange(name, value, 1, maxAutoIncrementStep)
+		if err != nil {
+			return err
+		}
+		if name == AutoIncrementIncrement {
+			vars.AutoIncrementIncrement = val
+		} else {
+			vars.AutoIncrementOffset = val
+		}
+		return nil
+	}
+	return ErrUnknownSystemVariable.GenWithStackByArgs(name)
+}
+
+// GetSessionSystemVar returns the value of a system variable in the session, the global variables are read by
+// the GlobalVarsAccessor of the session.
+func GetSessionSystemVar(vars *SessionVars, name string) (string, error) {
+	name = strings.ToLower(name)
+	if val, ok := vars.systems[name]; ok {
+		return val, nil
+	}
+	switch name {
+	case Autocommit:
+		if vars.IsAutocommit() {
+			return "1", nil
+		}
+		return "0", nil
+	case TiDBAutoAnalyzeRatio:
+		return GetGlobalSystemVar(vars, name)
+	case TiDBMaxChunkSize:
+		return strconv.Itoa(vars.MaxChunkSize), nil
+	case TiDBInitChunkSize:
+		return strconv.Itoa(vars.InitChunkSize), nil
+	case TiDBMemQuotaQuery:
+		return strconv.FormatInt(vars.MemQuotaQuery, 10), nil
+	case TiDBHashJoinConcurrency:
+		return strconv.Itoa(vars.HashJoinConcurrency), nil
+	case TiDBIndexJoinBatchSize:
+		return strconv.Itoa(vars.IndexJoinBatchSize), nil
+	case TiDBHashAggPartialConcurrency:
+		return strconv.Itoa(vars.HashAggPartialConcurrency), nil
+	case TiDBHashAggFinalConcurrency:
+		return strconv.Itoa(vars.HashAggFinalConcurrency), nil
+	case GroupConcatMaxLen:
+		return strconv.Itoa(vars.GroupConcatMaxLen), nil
+	case AutoIncrementIncrement:
+		return strconv.Itoa(vars.AutoIncrementIncrement), nil
+	case AutoIncrementOffset:
+		return strconv.Itoa(vars.AutoIncrementOffset), nil
+	}
+	return "", ErrUnknownSystemVariable.GenWithStackByArgs(name)
+}
+
+// GetGlobalSystemVar returns the global value of a system variable.
+func GetGlobalSystemVar(vars *SessionVars, name string) (string, error) {
+	name = strings.ToLower(name)
+	if _, ok := sysVarDefaults[name]; !ok {
+		return "", ErrUnknownSystemVariable.GenWithStackByArgs(name)
+	}
+	return vars.GlobalVarsAccessor.GetGlobalSysVar(name)
+}
+
+// GetSysVarNames returns the names of the supported system variables in order.
+func GetSysVarNames() []string {
+	names := make([]string, 0, len(sysVarDefaults))
+	for name := range sysVarDefaults {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// parseIntInRange parses the value of an integer system variable, which must be in [min, max].
+func parseIntInRange(name, value string, min, max int64) (int, error) {
+	val, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, ErrWrongTypeForVar.GenWithStackByArgs(name)
+	}
+	if val < min || val > max {
+		return 0, ErrWrongValueForVar.GenWithStackByArgs(name, value)
+	}
+	return int(val), nil
+}
+
+// ParseAutoAnalyzeRatio parses the value of tidb_auto_analyze_ratio, which is a non-negative number.
+func ParseAutoAnalyzeRatio(value string) (float64, error) {
+	ratio, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, ErrWrongTypeForVar.GenWithStackByArgs(TiDBAutoAnalyzeRatio)
+	}
+	if ratio < 0 {
+		return 0, ErrWrongValueForVar.GenWithStackByArgs(TiDBAutoAnalyzeRatio, value)
+	}
+	return ratio, nil
+}
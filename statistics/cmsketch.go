@@ -0,0 +1,151 @@
+package statistics
+
+import (
+	"hash/fnv"
+	"sort"
+)
+
+// CMSketch is used to estimate the count of a value by a count-min sketch, the values of the top-n list
+// are counted by the list and are removed from the sketch to reduce the collisions.
+type CMSketch struct {
+	Depth int32      `json:"depth"`
+	Width int32      `json:"width"`
+	Count uint64     `json:"count"`
+	Table [][]uint32 `json:"table"`
+	TopN  []TopNMeta `json:"top_n"`
+
+	topN map[string]uint64
+}
+
+// TopNMeta is a frequent value and its count.
+type TopNMeta struct {
+	Data  []byte `json:"data"`
+	Count uint64 `json:"count"`
+}
+
+// NewCMSketch returns a new CM sketch.
+func NewCMSketch(d, w int32) *CMSketch {
+	tbl := make([][]uint32, d)
+	arena := make([]uint32, d*w)
+	for i := range tbl {
+		tbl[i] = arena[i*int(w) : (i+1)*int(w)]
+	}
+	return &CMSketch{Depth: d, Width: w, Table: tbl}
+}
+
+func hash128(data []byte) (uint64, uint64) {
+	h := fnv.New128a()
+	_, _ = h.Write(data)
+	sum := h.Sum(nil)
+	var h1, h2 uint64
+	for i := 0; i < 8; i++ {
+		h1 = h1<<8 | uint64(sum[i])
+		h2 = h2<<8 | uint64(sum[i+8])
+	}
+	return mix64(h1), mix64(h2)
+}
+
+// InsertBytes inserts an encoded value into the sketch.
+func (c *CMSketch) InsertBytes(data []byte) {
+	c.insertBytesByCount(data, 1)
+}
+
+func (c *CMSketch) insertBytesByCount(data []byte, count uint64) {
+	h1, h2 := hash128(data)
+	c.Count += count
+	for i := range c.Table {
+		j := (h1 + h2*uint64(i)) % uint64(c.Width)
+		c.Table[i][j] += uint32(count)
+	}
+}
+
+// QueryBytes returns the estimated count of an encoded value.
+func (c *CMSketch) QueryBytes(data []byte) uint64 {
+	if cnt, ok := c.topN[string(data)]; ok {
+		return cnt
+	}
+	return c.queryBytes(data)
+}
+
+// queryBytes estimates the count by the count-mean-min of the rows, the noise of a counter is the mean of
+// the other counters of the row.
+func (c *CMSketch) queryBytes(data []byte) uint64 {
+	h1, h2 := hash128(data)
+	vals := make([]uint32, c.Depth)
+	min := uint32(1<<32 - 1)
+	for i := range c.Table {
+		j := (h1 + h2*uint64(i)) % uint64(c.Width)
+		if c.Table[i][j] < min {
+			min = c.Table[i][j]
+		}
+		noise := uint64(0)
+		if c.Width > 1 {
+			noise = (c.Count - uint64(c.Table[i][j])) / (uint64(c.Width) - 1)
+		}
+		if uint64(c.Table[i][j]) < noise {
+			vals[i] = 0
+		} else {
+			vals[i] = c.Table[i][j] - uint32(noise)
+		}
+	}
+	sort.Slice(vals, func(i, j int) bool { return vals[i] < vals[j] })
+	res := vals[(c.Depth-1)/2] + (vals[c.Depth/2]-vals[(c.Depth-1)/2])/2
+	if res > min {
+		return uint64(min)
+	}
+	return uint64(res)
+}
+
+// extractTopN moves the most frequent values of the samples out of the sketch to the top-n list, at most
+// numTop values which appear more than once in the samples are moved.
+func (c *CMSketch) extractTopN(samples [][]byte, numTop int) {
+	counter := make(map[string]uint64, len(samples))
+	for _, sample := range samples {
+		counter[string(sample)]++
+	}
+	sorted := make([]TopNMeta, 0, len(counter))
+	for data, cnt := range counter {
+		if cnt > 1 {
+			sorted = append(sorted, TopNMeta{Data: []byte(data), Count: cnt})
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Count != sorted[j].Count {
+			return sorted[i].Count > sorted[j].Count
+		}
+		return string(sorted[i].Data) < string(sorted[j].Data)
+	})
+	if len(sorted) > numTop {
+		sorted = sorted[:numTop]
+	}
+	for i := range sorted {
+		meta := &sorted[i]
+		// The counter of the minimum is the upper bound of the count, removing it never makes a counter
+		// negative.
+		meta.Count = c.queryBytes(meta.Data)
+		h1, h2 := hash128(meta.Data)
+		c.Count -= meta.Count
+		for j := range c.Table {
+			k := (h1 + h2*uint64(j)) % uint64(c.Width)
+			c.Table[j][k] -= uint32(meta.Count)
+		}
+	}
+	c.TopN = sorted
+	c.initTopN()
+}
+
+func (c *CMSketch) initTopN() {
+	c.topN = make(map[string]uint64, len(c.TopN))
+	for _, meta := range c.TopN {
+		c.topN[string(meta.Data)] = meta.Count
+	}
+}
+
+// TotalCount returns the count of the values of the sketch and the top-n list.
+func (c *CMSketch) TotalCount() uint64 {
+	total := c.Count
+	for _, meta := range c.TopN {
+		total += meta.Count
+	}
+	return total
+}
@@ -0,0 +1,150 @@
+package statistics
+
+import (
+	"encoding/json"
+	"math"
+
+	"github.com/pingcap/parser/model"
+	"grant-db/kv"
+	"grant-db/sessionctx/stmtctx"
+	"grant-db/types"
+	"grant-db/util/ranger"
+)
+
+// histogramRecord is the stored form of the statistics of a column or an index.
+type histogramRecord struct {
+	IsIndex   bool       `json:"is_index"`
+	Histogram *Histogram `json:"histogram"`
+	CMSketch  *CMSketch  `json:"cm_sketch"`
+}
+
+// EncodeHistogram encodes the histogram and the count-min sketch of a column or an index to be stored.
+func EncodeHistogram(hg *Histogram, cms *CMSketch, isIndex bool) ([]byte, error) {
+	return json.Marshal(&histogramRecord{IsIndex: isIndex, Histogram: hg, CMSketch: cms})
+}
+
+// DecodeHistogram decodes the histogram and the count-min sketch of a column or an index.
+func DecodeHistogram(data []byte) (hg *Histogram, cms *CMSketch, isIndex bool, err error) {
+	record := &histogramRecord{}
+	if err = json.Unmarshal(data, record); err != nil {
+		return nil, nil, false, err
+	}
+	if record.CMSketch != nil {
+		record.CMSketch.initTopN()
+	}
+	return record.Histogram, record.CMSketch, record.IsIndex, nil
+}
+
+// increaseFactor returns the ratio of the current row count to the row count when the histogram is built.
+func (coll *HistColl) increaseFactor(hg *Histogram) float64 {
+	total := hg.totalRowCount()
+	if total == 0 {
+		return 1
+	}
+	return float64(coll.Count) / total
+}
+
+// usable checks whether the estimation can be done by the histogram, a histogram of no values can't
+// estimate the rows inserted after analyzing.
+func (coll *HistColl) usable(hg *Histogram) bool {
+	return !coll.Pseudo && hg != nil && (hg.totalRowCount() > 0 || coll.Count == 0)
+}
+
+// ColumnNDV returns the NDV of the column scaled to the current row count, ok is false if the column
+// isn't analyzed.
+func (coll *HistColl) ColumnNDV(colID int64) (float64, bool) {
+	col, ok := coll.Columns[colID]
+	if !ok || !coll.usable(col.Histogram) {
+		return 0, false
+	}
+	ndv := float64(col.Histogram.NDV) * coll.increaseFactor(col.Histogram)
+	return math.Min(ndv, float64(coll.Count)), true
+}
+
+func (c *Column) encode(d types.Datum) ([]byte, error) {
+	return EncodeValues([]types.Datum{d}, []*model.ColumnInfo{c.Info})
+}
+
+func (c *Column) equalRowCount(val []byte) float64 {
+	if c.CMSketch != nil {
+		return float64(c.CMSketch.QueryBytes(val))
+	}
+	return c.Histogram.equalRowCount(val)
+}
+
+// isBound checks whether the datum is a bound of the range rather than a value of the column.
+func isBound(d *types.Datum) bool {
+	return d.Kind() == types.KindNull || d.Kind() == types.KindMinNotNull || d.Kind() == types.KindMaxValue
+}
+
+// getRowCountByRanges estimates the row count of the ranges of the column by the histogram, the points
+// are estimated by the count-min sketch.
+func (c *Column) getRowCountByRanges(sc *stmtctx.StatementContext, ranges []*ranger.Range) (float64, error) {
+	var rowCount float64
+	for _, rg := range ranges {
+		low, high := &rg.LowVal[0], &rg.HighVal[0]
+		if low.IsNull() && high.IsNull() {
+			if !rg.LowExclude && !rg.HighExclude {
+				rowCount += float64(c.Histogram.NullCount)
+			}
+			continue
+		}
+		lowVal, err := c.encode(*low)
+		if err != nil {
+			return 0, err
+		}
+		if rg.IsPoint(sc) {
+			rowCount += c.equalRowCount(lowVal)
+			continue
+		}
+		highVal, err := c.encode(*high)
+		if err != nil {
+			return 0, err
+		}
+		cnt := c.Histogram.betweenRowCount(lowVal, highVal)
+		if low.IsNull() && !rg.LowExclude {
+			cnt += float64(c.Histogram.NullCount)
+		}
+		if rg.LowExclude && !isBound(low) {
+			cnt -= c.equalRowCount(lowVal)
+		}
+		if !rg.HighExclude && !isBound(high) {
+			cnt += c.equalRowCount(highVal)
+		}
+		rowCount += math.Max(cnt, 0)
+	}
+	return rowCount, nil
+}
+
+// getRowCountByRanges estimates the row count of the ranges of the index by the histogram, the points of
+// all the columns are estimated by the count-min sketch.
+func (idx *Index) getRowCountByRanges(sc *stmtctx.StatementContext, ranges []*ranger.Range) (float64, error) {
+	var rowCount float64
+	for _, rg := range ranges {
+		lowVal, err := EncodeValues(rg.LowVal, idx.ColInfos)
+		if err != nil {
+			return 0, err
+		}
+		if len(rg.LowVal) == len(idx.ColInfos) && rg.IsPoint(sc) {
+			if idx.CMSketch != nil {
+				rowCount += float64(idx.CMSketch.QueryBytes(lowVal))
+			} else {
+				rowCount += idx.Histogram.equalRowCount(lowVal)
+			}
+			continue
+		}
+		highVal, err := EncodeValues(rg.HighVal, idx.ColInfos)
+		if err != nil {
+			return 0, err
+		}
+		// The keys of the index have the values as the prefixes, [low, high] is [low, high.PrefixNext()).
+		if rg.LowExclude {
+			lowVal = kv.Key(lowVal).PrefixNext()
+		}
+		if !rg.HighExclude {
+			highVal = kv.Key(highVal).PrefixNext()
+		}
+		rowCount += idx.Histogram.betweenRowCount(lowVal, highVal)
+	}
+	return rowCount, nil
+}
@@ -0,0 +1,59 @@
+package statistics
+
+import (
+	"hash/fnv"
+)
+
+// FMSketch is used to count the number of distinct elements in a set. It keeps the hash values whose
+// lowest bits under the mask are zero, the mask grows when the set is full, so every kept value stands
+// for mask+1 distinct values.
+type FMSketch struct {
+	hashset map[uint64]struct{}
+	mask    uint64
+	maxSize int
+}
+
+// NewFMSketch returns a new FM sketch.
+func NewFMSketch(maxSize int) *FMSketch {
+	return &FMSketch{
+		hashset: make(map[uint64]struct{}),
+		maxSize: maxSize,
+	}
+}
+
+// NDV returns the ndv of the sketch.
+func (s *FMSketch) NDV() int64 {
+	return int64(s.mask+1) * int64(len(s.hashset))
+}
+
+// InsertBytes inserts an encoded value into the sketch.
+func (s *FMSketch) InsertBytes(data []byte) {
+	h := fnv.New64a()
+	_, _ = h.Write(data)
+	s.insertHashValue(mix64(h.Sum64()))
+}
+
+func (s *FMSketch) insertHashValue(hashVal uint64) {
+	if hashVal&s.mask != 0 {
+		return
+	}
+	s.hashset[hashVal] = struct{}{}
+	if len(s.hashset) > s.maxSize {
+		s.mask = s.mask*2 + 1
+		for key := range s.hashset {
+			if key&s.mask != 0 {
+				delete(s.hashset, key)
+			}
+		}
+	}
+}
+
+// mix64 spreads the bits of the FNV hash value, whose low bits aren't random enough for the mask.
+func mix64(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
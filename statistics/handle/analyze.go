@@ -0,0 +1,114 @@
+package handle
+
+import (
+	"github.com/pingcap/parser/model"
+	"grant-db/kv"
+	"grant-db/meta"
+	"grant-db/statistics"
+	"grant-db/table"
+	"grant-db/tablecodec"
+	"grant-db/types"
+)
+
+// AnalyzeTable scans the table and builds the statistics of the columns and the indices, the columns are
+// skipped if indexOnly is true. The statistics are saved with a new version, and the ones of the other
+// indices are kept.
+func (h *Handle) AnalyzeTable(tbl table.Table, indexIDs []int64, indexOnly bool, opts statistics.AnalyzeOptions) error {
+	tblInfo := tbl.Meta()
+	var cols []*table.Column
+	if !indexOnly {
+		cols = tbl.Cols()
+	}
+	var indices []table.Index
+	for _, idx := range tbl.Indices() {
+		for _, id := range indexIDs {
+			if idx.Meta().ID == id {
+				indices = append(indices, idx)
+			}
+		}
+	}
+	colCollectors := make([]*statistics.SampleCollector, len(cols))
+	for i := range colCollectors {
+		colCollectors[i] = statistics.NewSampleCollector(opts)
+	}
+	idxCollectors := make([]*statistics.SampleCollector, len(indices))
+	for i := range idxCollectors {
+		idxCollectors[i] = statistics.NewSampleCollector(opts)
+	}
+
+	// The rows changed by the committed transactions are scanned, their deltas which aren't dumped are dropped
+	// so that they aren't counted again.
+	h.removeTableDelta(tblInfo.ID)
+	ver, err := h.store.CurrentVersion()
+	if err != nil {
+		return err
+	}
+	var (
+		rowCount int64
+		idxVals  []types.Datum
+		key      []byte
+	)
+	allCols := tbl.DeletableCols()
+	err = tbl.IterRecords(h.store.GetSnapshot(ver), tbl.FirstKey(), allCols,
+		func(handle int64, rec []types.Datum, _ []*table.Column) (bool, error) {
+			rowCount++
+			for i, col := range cols {
+				val := rec[col.Offset]
+				if val.IsNull() {
+					colCollectors[i].Collect(nil)
+					continue
+				}
+				data, err := statistics.EncodeValues(rec[col.Offset:col.Offset+1], []*model.ColumnInfo{col.ColumnInfo})
+				if err != nil {
+					return false, err
+				}
+				colCollectors[i].Collect(data)
+			}
+			for i, idx := range indices {
+				idxVals, err = idx.FetchValues(rec, idxVals)
+				if err != nil {
+					return false, err
+				}
+				// The values of the index are the key without the prefix and the handle.
+				key, _, err = idx.GenIndexKey(idxVals, handle, key)
+				if err != nil {
+					return false, err
+				}
+				_, remained, err := tablecodec.CutIndexKey(key, len(idx.Meta().Columns))
+				if err != nil {
+					return false, err
+				}
+				idxCollectors[i].Collect(key[len(idx.Prefix()) : len(key)-len(remained)])
+			}
+			return true, nil
+		})
+	if err != nil {
+		return err
+	}
+
+	return kv.RunInNewTxn(h.store, true, func(txn kv.Transaction) error {
+		t := meta.NewMeta(txn)
+		for i, col := range cols {
+			hg, cms := colCollectors[i].BuildColumnOrIndex(col.ID, opts)
+			if err := saveHistogram(t, tblInfo.ID, hg, cms, false); err != nil {
+				return err
+			}
+		}
+		for i, idx := range indices {
+			hg, cms := idxCollectors[i].BuildColumnOrIndex(idx.Meta().ID, opts)
+			if err := saveHistogram(t, tblInfo.ID, hg, cms, true); err != nil {
+				return err
+			}
+		}
+		return t.SetStatsMeta(&meta.StatsMeta{TableID: tblInfo.ID, Version: t.StartTS, Count: rowCount})
+	})
+}
+
+func saveHistogram(t *meta.Meta, tableID int64, hg *statistics.Histogram, cms *statistics.CMSketch, isIndex bool) error {
+	hg.LastUpdateVersion = t.StartTS
+	data, err := statistics.EncodeHistogram(hg, cms, isIndex)
+	if err != nil {
+		return err
+	}
+	return t.SetStatsHistogram(tableID, isIndex, hg.ID, data)
+}
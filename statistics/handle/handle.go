@@ -0,0 +1,135 @@
+package handle
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pingcap/parser/model"
+	"grant-db/infoschema"
+	"grant-db/kv"
+	"grant-db/meta"
+	"grant-db/statistics"
+)
+
+// DefaultAutoAnalyzeRatio is the default ratio of the modified rows which makes a table analyzed
+// automatically.
+const DefaultAutoAnalyzeRatio = 0.5
+
+// Handle loads and caches the statistics of the tables, it keeps the row count deltas of the DML and
+// analyzes the tables which are modified a lot.
+type Handle struct {
+	store kv.Storage
+	// statsCache is the map from the table ID to the cached statistics, it's replaced as a whole.
+	statsCache atomic.Value
+	// autoAnalyzeRatio is the float64 bits of the ratio, the auto analyze is disabled if it's 0.
+	autoAnalyzeRatio uint64
+
+	mu struct {
+		sync.Mutex
+		// deltaMap is the row count deltas which aren't dumped to the storage.
+		deltaMap map[int64]tableDelta
+	}
+	// updateMu serializes the updates of the cache.
+	updateMu sync.Mutex
+}
+
+// cacheItem is the statistics of a table and the table info they are loaded by.
+type cacheItem struct {
+	tbl     *statistics.Table
+	tblInfo *model.TableInfo
+}
+
+// NewHandle creates a Handle for the store.
+func NewHandle(store kv.Storage) *Handle {
+	h := &Handle{store: store}
+	h.mu.deltaMap = make(map[int64]tableDelta)
+	h.statsCache.Store(map[int64]cacheItem{})
+	h.SetAutoAnalyzeRatio(DefaultAutoAnalyzeRatio)
+	return h
+}
+
+// SetAutoAnalyzeRatio sets the ratio of the modified rows which makes a table analyzed automatically.
+func (h *Handle) SetAutoAnalyzeRatio(ratio float64) {
+	atomic.StoreUint64(&h.autoAnalyzeRatio, math.Float64bits(ratio))
+}
+
+// AutoAnalyzeRatio returns the ratio of the modified rows which makes a table analyzed automatically.
+func (h *Handle) AutoAnalyzeRatio() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&h.autoAnalyzeRatio))
+}
+
+func (h *Handle) cache() map[int64]cacheItem {
+	return h.statsCache.Load().(map[int64]cacheItem)
+}
+
+// GetTableStats returns the statistics of the table, they are pseudo if the table has no statistics.
+func (h *Handle) GetTableStats(tblInfo *model.TableInfo) *statistics.Table {
+	if item, ok := h.cache()[tblInfo.ID]; ok {
+		return item.tbl
+	}
+	return statistics.PseudoTable(tblInfo)
+}
+
+// Update reloads the statistics of the tables in the schema. The row counts are always refreshed, the
+// histograms are reloaded only if the table is analyzed again or its schema is changed.
+func (h *Handle) Update(is infoschema.InfoSchema) error {
+	h.updateMu.Lock()
+	defer h.updateMu.Unlock()
+
+	ver, err := h.store.CurrentVersion()
+	if err != nil {
+		return err
+	}
+	m := meta.NewSnapshotMeta(h.store.GetSnapshot(ver))
+	statsMetas, err := m.ListStatsMeta()
+	if err != nil {
+		return err
+	}
+	oldCache := h.cache()
+	newCache := make(map[int64]cacheItem, len(statsMetas))
+	for _, statsMeta := range statsMetas {
+		tbl, ok := is.TableByID(statsMeta.TableID)
+		if !ok {
+			continue
+		}
+		tblInfo := tbl.Meta()
+		if item, ok := oldCache[tblInfo.ID]; ok && item.tblInfo == tblInfo && item.tbl.Version == statsMeta.Version {
+			statsTbl := item.tbl.Copy()
+			statsTbl.Count, statsTbl.ModifyCount = statsMeta.Count, statsMeta.ModifyCount
+			newCache[tblInfo.ID] = cacheItem{tbl: statsTbl, tblInfo: tblInfo}
+			continue
+		}
+		statsTbl, err := tableStatsFromStorage(m, tblInfo, statsMeta)
+		if err != nil {
+			return err
+		}
+		newCache[tblInfo.ID] = cacheItem{tbl: statsTbl, tblInfo: tblInfo}
+	}
+	h.statsCache.Store(newCache)
+	return nil
+}
+
+// tableStatsFromStorage loads the histograms of the columns and the indices of the table, the ones of the
+// dropped columns and indices are ignored.
+func tableStatsFromStorage(m *meta.Meta, tblInfo *model.TableInfo, statsMeta *meta.StatsMeta) (*statistics.Table, error) {
+	statsTbl := statistics.NewTable(tblInfo, statsMeta.Version, statsMeta.Count, statsMeta.ModifyCount)
+	values, err := m.ListStatsHistograms(tblInfo.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, value := range values {
+		hg, cms, isIndex, err := statistics.DecodeHistogram(value)
+		if err != nil {
+			return nil, err
+		}
+		if isIndex {
+			if idx, ok := statsTbl.Indices[hg.ID]; ok {
+				statsTbl.Indices[hg.ID] = &statistics.Index{Info: idx.Info, ColInfos: idx.ColInfos, Histogram: hg, CMSketch: cms}
+			}
+		} else if col, ok := statsTbl.Columns[hg.ID]; ok {
+			statsTbl.Columns[hg.ID] = &statistics.Column{Info: col.Info, IsHandle: col.IsHandle, Histogram: hg, CMSketch: cms}
+		}
+	}
+	return statsTbl, nil
+}
@@ -0,0 +1,156 @@
+package handle_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/domain"
+	"grant-db/statistics"
+	"grant-db/statistics/handle"
+	"grant-db/types"
+	"grant-db/util/testkit"
+)
+
+func tableStats(t *testing.T, dom *domain.Domain, name string) (*model.TableInfo, *statistics.Table) {
+	t.Helper()
+	tbl, err := dom.InfoSchema().TableByName(model.NewCIStr("test"), model.NewCIStr(name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tbl.Meta(), dom.StatsHandle().GetTableStats(tbl.Meta())
+}
+
+// insertRows inserts the rows (i, i % 10, c) for i in [start, end), c is NULL for a tenth of the rows.
+func insertRows(tk *testkit.TestKit, start, end int) {
+	values := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		c := "'x'"
+		if i%10 == 0 {
+			c = "null"
+		}
+		values = append(values, fmt.Sprintf("(%d, %d, %s)", i, i%10, c))
+	}
+	tk.MustExec("insert into t values " + strings.Join(values, ", "))
+}
+
+func TestAnalyzeTable(t *testing.T) {
+	store, dom := testkit.NewMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create database test")
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int primary key, b int, c varchar(10), key idx_b (b))")
+	insertRows(tk, 0, 100)
+	if _, stats := tableStats(t, dom, "t"); !stats.Pseudo {
+		t.Fatal("the statistics of the table which isn't analyzed aren't pseudo")
+	}
+
+	tk.MustExec("analyze table t")
+	tblInfo, stats := tableStats(t, dom, "t")
+	if stats.Pseudo || stats.Count != 100 || stats.ModifyCount != 0 {
+		t.Fatalf("the statistics are pseudo %v, count %d, modify count %d", stats.Pseudo, stats.Count, stats.ModifyCount)
+	}
+	expected := []struct {
+		ndv, nullCount int64
+	}{{100, 0}, {10, 0}, {1, 10}}
+	for i, col := range tblInfo.Columns {
+		hg := stats.Columns[col.ID].Histogram
+		if hg == nil || hg.NDV != expected[i].ndv || hg.NullCount != expected[i].nullCount {
+			t.Fatalf("the histogram of %s is %+v, expected %+v", col.Name, hg, expected[i])
+		}
+		if last := hg.Buckets[len(hg.Buckets)-1]; last.Count != 100-expected[i].nullCount {
+			t.Fatalf("the buckets of %s count %d values", col.Name, last.Count)
+		}
+	}
+	idxStats := stats.Indices[tblInfo.Indices[0].ID]
+	if idxStats.Histogram == nil || idxStats.Histogram.NDV != 10 {
+		t.Fatalf("the histogram of the index is %+v", idxStats.Histogram)
+	}
+	// Every value of b is in 10 rows.
+	key, err := statistics.EncodeValues([]types.Datum{types.NewIntDatum(3)}, idxStats.ColInfos)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cnt := idxStats.CMSketch.QueryBytes(key); cnt != 10 {
+		t.Fatalf("the count-min sketch counts %d rows of b = 3", cnt)
+	}
+
+	// The DML changes are counted after the analysis.
+	tk.MustExec("delete from t where a < 10")
+	h := dom.StatsHandle()
+	if err = h.DumpStatsDeltaToKV(); err != nil {
+		t.Fatal(err)
+	}
+	if err = h.Update(dom.InfoSchema()); err != nil {
+		t.Fatal(err)
+	}
+	if _, stats = tableStats(t, dom, "t"); stats.Count != 90 || stats.ModifyCount != 10 {
+		t.Fatalf("the count is %d and the modify count is %d after the deletion", stats.Count, stats.ModifyCount)
+	}
+	tk.MustGetErrCode("analyze table nope", mysql.ErrNoSuchTable)
+}
+
+func TestAutoAnalyze(t *testing.T) {
+	store, dom := testkit.NewMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create database test")
+	tk.MustExec("use test")
+	tk.MustExec("create table t (a int primary key, b int, c varchar(10), key idx_b (b))")
+	h := dom.StatsHandle()
+	autoAnalyze := func() *statistics.Table {
+		t.Helper()
+		if err := h.DumpStatsDeltaToKV(); err != nil {
+			t.Fatal(err)
+		}
+		if err := h.Update(dom.InfoSchema()); err != nil {
+			t.Fatal(err)
+		}
+		if err := h.HandleAutoAnalyze(dom.InfoSchema()); err != nil {
+			t.Fatal(err)
+		}
+		// The statistics of the analyzed table are loaded by the next update.
+		if err := h.Update(dom.InfoSchema()); err != nil {
+			t.Fatal(err)
+		}
+		_, stats := tableStats(t, dom, "t")
+		return stats
+	}
+
+	// A small table isn't analyzed automatically.
+	insertRows(tk, 0, 500)
+	if stats := autoAnalyze(); stats.Version != statistics.PseudoVersion {
+		t.Fatalf("the table of %d rows is analyzed", stats.Count)
+	}
+	// The table which has never been analyzed is analyzed once it has enough rows.
+	insertRows(tk, 500, 1000)
+	stats := autoAnalyze()
+	if stats.Version == statistics.PseudoVersion || stats.Count != 1000 || stats.ModifyCount != 0 {
+		t.Fatalf("the table isn't analyzed, version %d, count %d, modify count %d", stats.Version, stats.Count, stats.ModifyCount)
+	}
+	version := stats.Version
+
+	// It's analyzed again when the ratio of the modified rows is larger than tidb_auto_analyze_ratio.
+	tk.MustExec("set @@global.tidb_auto_analyze_ratio = 0.3")
+	tk.MustQuery("select @@global.tidb_auto_analyze_ratio").Check("0.3")
+	tk.MustExec("update t set b = b + 1 where a < 200")
+	if stats = autoAnalyze(); stats.Version != version || stats.ModifyCount != 200 {
+		t.Fatalf("the table is analyzed after 200 rows are modified, modify count %d", stats.ModifyCount)
+	}
+	if handle.NeedAnalyzeTable(stats, h.AutoAnalyzeRatio()) {
+		t.Fatal("the table needs to be analyzed after 200 rows are modified")
+	}
+	insertRows(tk, 1000, 1200)
+	if stats = autoAnalyze(); stats.Version == version || stats.Count != 1200 || stats.ModifyCount != 0 {
+		t.Fatalf("the table isn't analyzed again, version %d, count %d, modify count %d", stats.Version, stats.Count, stats.ModifyCount)
+	}
+	version = stats.Version
+
+	// The ratio 0 turns off the auto analysis.
+	tk.MustExec("set @@global.tidb_auto_analyze_ratio = 0")
+	tk.MustExec("update t set b = b + 1")
+	if stats = autoAnalyze(); stats.Version != version || stats.ModifyCount != 1200 {
+		t.Fatal("the table is analyzed when the auto analysis is off")
+	}
+}
@@ -0,0 +1,118 @@
+package handle
+
+import (
+	"log"
+
+	"github.com/pingcap/parser/model"
+
+	"grant-db/infoschema"
+	"grant-db/kv"
+	"grant-db/meta"
+	"grant-db/statistics"
+	"grant-db/table"
+)
+
+// autoAnalyzeMinCnt is the least row count of a table to be analyzed automatically.
+const autoAnalyzeMinCnt = 1000
+
+// tableDelta is the change of a table made by the DML.
+type tableDelta struct {
+	// Delta is the change of the row count.
+	Delta int64
+	// Count is the number of the modified rows.
+	Count int64
+}
+
+// UpdateTableDelta records the change of a table made by a committed transaction, delta is the change of the
+// row count and count is the number of the modified rows.
+func (h *Handle) UpdateTableDelta(tableID, delta, count int64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	item := h.mu.deltaMap[tableID]
+	item.Delta += delta
+	item.Count += count
+	h.mu.deltaMap[tableID] = item
+}
+
+// removeTableDelta drops the row count delta of the table which isn't dumped.
+func (h *Handle) removeTableDelta(tableID int64) {
+	h.mu.Lock()
+	delete(h.mu.deltaMap, tableID)
+	h.mu.Unlock()
+}
+
+// DumpStatsDeltaToKV saves the row count deltas to the stats meta of the tables, the deltas which fail
+// to be saved are kept to be saved next time.
+func (h *Handle) DumpStatsDeltaToKV() error {
+	h.mu.Lock()
+	deltaMap := h.mu.deltaMap
+	h.mu.deltaMap = make(map[int64]tableDelta)
+	h.mu.Unlock()
+
+	var firstErr error
+	for tableID, item := range deltaMap {
+		err := kv.RunInNewTxn(h.store, true, func(txn kv.Transaction) error {
+			t := meta.NewMeta(txn)
+			statsMeta, err := t.GetStatsMeta(tableID)
+			if err != nil {
+				return err
+			}
+			if statsMeta == nil {
+				statsMeta = &meta.StatsMeta{TableID: tableID}
+			}
+			statsMeta.Count += item.Delta
+			if statsMeta.Count < 0 {
+				statsMeta.Count = 0
+			}
+			statsMeta.ModifyCount += item.Count
+			return t.SetStatsMeta(statsMeta)
+		})
+		if err != nil {
+			h.UpdateTableDelta(tableID, item.Delta, item.Count)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// NeedAnalyzeTable checks whether the table needs to be analyzed automatically, a table is analyzed if it
+// isn't analyzed or the ratio of the modified rows is larger than autoAnalyzeRatio.
+func NeedAnalyzeTable(statsTbl *statistics.Table, autoAnalyzeRatio float64) bool {
+	if autoAnalyzeRatio <= 0 || statsTbl.Count < autoAnalyzeMinCnt {
+		return false
+	}
+	// The table which has never been analyzed is analyzed once it has enough rows.
+	if statsTbl.Pseudo || statsTbl.Version == statistics.PseudoVersion {
+		return true
+	}
+	return float64(statsTbl.ModifyCount)/float64(statsTbl.Count) > autoAnalyzeRatio
+}
+
+// HandleAutoAnalyze analyzes the tables which need to be analyzed, it analyzes a table at most each time.
+func (h *Handle) HandleAutoAnalyze(is infoschema.InfoSchema) error {
+	ratio := h.AutoAnalyzeRatio()
+	for _, item := range h.cache() {
+		if !NeedAnalyzeTable(item.tbl, ratio) {
+			continue
+		}
+		tbl, ok := is.TableByID(item.tblInfo.ID)
+		if !ok {
+			continue
+		}
+		log.Printf("[stats] auto analyze table %s, count %d, modify count %d", tbl.Meta().Name, item.tbl.Count, item.tbl.ModifyCount)
+		return h.AnalyzeTable(tbl, analyzeAllIndices(tbl), false, statistics.DefaultAnalyzeOptions())
+	}
+	return nil
+}
+
+func analyzeAllIndices(tbl table.Table) []int64 {
+	ids := make([]int64, 0, len(tbl.Indices()))
+	for _, idx := range tbl.Indices() {
+		if idx.Meta().State == model.StatePublic {
+			ids = append(ids, idx.Meta().ID)
+		}
+	}
+	return ids
+}
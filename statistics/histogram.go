@@ -0,0 +1,182 @@
+package statistics
+
+import (
+	"bytes"
+	"math"
+	"sort"
+)
+
+// Histogram represents the equal-depth histogram of a column or an index, the values are the comparable
+// encoded keys.
+type Histogram struct {
+	ID        int64 `json:"id"`
+	NDV       int64 `json:"ndv"`
+	NullCount int64 `json:"null_count"`
+	// LastUpdateVersion is the version of the statistics when the histogram is built.
+	LastUpdateVersion uint64   `json:"last_update_version"`
+	Buckets           []Bucket `json:"buckets"`
+}
+
+// Bucket is a bucket of the histogram, the values of it are between Lower and Upper.
+type Bucket struct {
+	Lower []byte `json:"lower"`
+	Upper []byte `json:"upper"`
+	// Count is the number of the values of this bucket and all the previous buckets.
+	Count int64 `json:"count"`
+	// Repeat is the number of the values equal to Upper.
+	Repeat int64 `json:"repeat"`
+}
+
+// buildHistogram builds the histogram from the sorted samples, count and ndv are the number and the NDV
+// of all the non-null values. A value is kept in a single bucket even if the bucket is full.
+func buildHistogram(id int64, samples [][]byte, count, ndv, nullCount int64, numBuckets int) *Histogram {
+	if ndv > count {
+		ndv = count
+	}
+	hg := &Histogram{ID: id, NDV: ndv, NullCount: nullCount}
+	if count == 0 || len(samples) == 0 {
+		return hg
+	}
+	sampleFactor := float64(count) / float64(len(samples))
+	ndvFactor := float64(count) / float64(ndv)
+	if ndvFactor > sampleFactor {
+		ndvFactor = sampleFactor
+	}
+	valuesPerBucket := float64(count)/float64(numBuckets) + ndvFactor
+	hg.Buckets = append(hg.Buckets, Bucket{
+		Lower:  samples[0],
+		Upper:  samples[0],
+		Count:  int64(sampleFactor),
+		Repeat: int64(ndvFactor),
+	})
+	var lastCount int64
+	for i := 1; i < len(samples); i++ {
+		totalCount := int64(float64(i+1) * sampleFactor)
+		bkt := &hg.Buckets[len(hg.Buckets)-1]
+		if bytes.Equal(bkt.Upper, samples[i]) {
+			bkt.Count = totalCount
+			if bkt.Repeat == int64(ndvFactor) {
+				bkt.Repeat = int64(2 * sampleFactor)
+			} else {
+				bkt.Repeat += int64(sampleFactor)
+			}
+		} else if float64(totalCount-lastCount) <= valuesPerBucket {
+			bkt.Upper, bkt.Count, bkt.Repeat = samples[i], totalCount, int64(ndvFactor)
+		} else {
+			lastCount = bkt.Count
+			hg.Buckets = append(hg.Buckets, Bucket{
+				Lower:  samples[i],
+				Upper:  samples[i],
+				Count:  totalCount,
+				Repeat: int64(ndvFactor),
+			})
+		}
+	}
+	return hg
+}
+
+// notNullCount returns the number of the non-null values.
+func (hg *Histogram) notNullCount() float64 {
+	if len(hg.Buckets) == 0 {
+		return 0
+	}
+	return float64(hg.Buckets[len(hg.Buckets)-1].Count)
+}
+
+// totalRowCount returns the number of the values including the nulls.
+func (hg *Histogram) totalRowCount() float64 {
+	return hg.notNullCount() + float64(hg.NullCount)
+}
+
+// equalRowCount estimates the number of the values equal to value.
+func (hg *Histogram) equalRowCount(value []byte) float64 {
+	idx := hg.searchUpper(value)
+	if idx == len(hg.Buckets) || (idx == 0 && bytes.Compare(value, hg.Buckets[0].Lower) < 0) {
+		return 0
+	}
+	if bytes.Equal(hg.Buckets[idx].Upper, value) {
+		return float64(hg.Buckets[idx].Repeat)
+	}
+	if hg.NDV == 0 {
+		return 0
+	}
+	return hg.notNullCount() / float64(hg.NDV)
+}
+
+// lessRowCount estimates the number of the values less than value, the values in the bucket of it are
+// estimated by the position of it between the bounds of the bucket.
+func (hg *Histogram) lessRowCount(value []byte) float64 {
+	idx := hg.searchUpper(value)
+	if idx == len(hg.Buckets) {
+		return hg.notNullCount()
+	}
+	bkt := &hg.Buckets[idx]
+	preCount := 0.0
+	if idx > 0 {
+		preCount = float64(hg.Buckets[idx-1].Count)
+	}
+	if bytes.Equal(bkt.Upper, value) {
+		return float64(bkt.Count - bkt.Repeat)
+	}
+	if bytes.Compare(value, bkt.Lower) <= 0 {
+		return preCount
+	}
+	frac := calcFraction(bkt.Lower, bkt.Upper, value)
+	return preCount + frac*(float64(bkt.Count-bkt.Repeat)-preCount)
+}
+
+// betweenRowCount estimates the number of the values in [a, b).
+func (hg *Histogram) betweenRowCount(a, b []byte) float64 {
+	return math.Max(hg.lessRowCount(b)-hg.lessRowCount(a), 0)
+}
+
+// searchUpper returns the index of the first bucket whose upper bound isn't less than value.
+func (hg *Histogram) searchUpper(value []byte) int {
+	return sort.Search(len(hg.Buckets), func(i int) bool {
+		return bytes.Compare(hg.Buckets[i].Upper, value) >= 0
+	})
+}
+
+// calcFraction estimates the position of value between lower and upper by the first 8 bytes after the
+// common prefix of them.
+func calcFraction(lower, upper, value []byte) float64 {
+	prefixLen := commonPrefixLen(lower, upper)
+	l := bytesToScalar(lower[prefixLen:])
+	u := bytesToScalar(upper[prefixLen:])
+	v := 0.0
+	if len(value) >= prefixLen {
+		v = bytesToScalar(value[prefixLen:])
+	}
+	if u <= l {
+		return 0.5
+	}
+	frac := (v - l) / (u - l)
+	if math.IsNaN(frac) || math.IsInf(frac, 0) || frac < 0 || frac > 1 {
+		return 0.5
+	}
+	return frac
+}
+
+func commonPrefixLen(a, b []byte) int {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	for i := 0; i < n; i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	return n
+}
+
+func bytesToScalar(b []byte) float64 {
+	var v uint64
+	for i := 0; i < 8; i++ {
+		v <<= 8
+		if i < len(b) {
+			v |= uint64(b[i])
+		}
+	}
+	return float64(v)
+}
@@ -0,0 +1,153 @@
+package statistics
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"github.com/pingcap/parser/model"
+	"grant-db/types"
+	"grant-db/util/codec"
+	"grant-db/util/collate"
+	"grant-db/util/customrand"
+)
+
+// The default and the max values of the analyze options.
+const (
+	DefaultNumBuckets    = 256
+	DefaultNumTopN       = 20
+	DefaultCMSketchDepth = 5
+	DefaultCMSketchWidth = 2048
+	DefaultNumSamples    = 10000
+
+	MaxNumBuckets   = 1024
+	MaxNumTopN      = 1024
+	MaxCMSketchSize = 1024 * 1024
+	MaxNumSamples   = 100000
+	maxFMSketchSize = 10000
+	maxSampleBytes  = 4096
+)
+
+// AnalyzeOptions is the options of ANALYZE TABLE.
+type AnalyzeOptions struct {
+	NumBuckets    int
+	NumTopN       int
+	CMSketchDepth int32
+	CMSketchWidth int32
+	NumSamples    int
+}
+
+// DefaultAnalyzeOptions returns the default options of ANALYZE TABLE.
+func DefaultAnalyzeOptions() AnalyzeOptions {
+	return AnalyzeOptions{
+		NumBuckets:    DefaultNumBuckets,
+		NumTopN:       DefaultNumTopN,
+		CMSketchDepth: DefaultCMSketchDepth,
+		CMSketchWidth: DefaultCMSketchWidth,
+		NumSamples:    DefaultNumSamples,
+	}
+}
+
+// SampleCollector collects the samples of a column or an index by the reservoir sampling, the NDV and the
+// count-min sketch are built from all the values.
+type SampleCollector struct {
+	Samples   [][]byte
+	NullCount int64
+	// Count is the number of the non-null values.
+	Count    int64
+	FMSketch *FMSketch
+	CMSketch *CMSketch
+
+	maxSampleSize int
+}
+
+// NewSampleCollector creates a sample collector by the options.
+func NewSampleCollector(opts AnalyzeOptions) *SampleCollector {
+	return &SampleCollector{
+		FMSketch:      NewFMSketch(maxFMSketchSize),
+		CMSketch:      NewCMSketch(opts.CMSketchDepth, opts.CMSketchWidth),
+		maxSampleSize: opts.NumSamples,
+	}
+}
+
+// Collect collects an encoded value, nil is a NULL.
+func (c *SampleCollector) Collect(data []byte) {
+	if data == nil {
+		c.NullCount++
+		return
+	}
+	c.Count++
+	c.FMSketch.InsertBytes(data)
+	c.CMSketch.InsertBytes(data)
+	// The long values make the histogram large, they are counted but not sampled.
+	if len(data) > maxSampleBytes {
+		return
+	}
+	if len(c.Samples) < c.maxSampleSize {
+		c.Samples = append(c.Samples, append([]byte(nil), data...))
+	} else if idx := int64(customrand.Uint32N(uint32(c.Count))); idx < int64(c.maxSampleSize) {
+		c.Samples[idx] = append(c.Samples[idx][:0], data...)
+	}
+}
+
+// EncodeValues encodes the values of a column or the columns of an index to a comparable key, the strings
+// are replaced by their sort keys in the collations of the columns like the index keys.
+func EncodeValues(vals []types.Datum, cols []*model.ColumnInfo) ([]byte, error) {
+	var keys []types.Datum
+	for i := range vals {
+		v := &vals[i]
+		if v.Kind() != types.KindString && v.Kind() != types.KindBytes {
+			continue
+		}
+		if collate.IsBinCollation(cols[i].Collate) {
+			continue
+		}
+		if keys == nil {
+			keys = append([]types.Datum(nil), vals...)
+		}
+		keys[i].SetBytes(collate.GetCollator(cols[i].Collate).Key(v.GetString()))
+	}
+	if keys == nil {
+		keys = vals
+	}
+	return codec.EncodeKey(nil, keys...)
+}
+
+// ValueToString decodes the value encoded by EncodeValues to a readable string, the values of more than
+// one column are in parentheses. The strings of the non-binary collations are shown as their sort keys.
+func ValueToString(data []byte, cols []*model.ColumnInfo) (string, error) {
+	vals, err := codec.Decode(data, len(cols))
+	if err != nil {
+		return "", err
+	}
+	strs := make([]string, 0, len(vals))
+	for i, val := range vals {
+		if i < len(cols) {
+			if val, err = codec.Unflatten(val, &cols[i].FieldType); err != nil {
+				return "", err
+			}
+		}
+		if val.IsNull() {
+			strs = append(strs, "NULL")
+			continue
+		}
+		str, err := val.ToString()
+		if err != nil {
+			return "", err
+		}
+		strs = append(strs, str)
+	}
+	if len(strs) == 1 {
+		return strs[0], nil
+	}
+	return "(" + strings.Join(strs, ", ") + ")", nil
+}
+
+// BuildColumnOrIndex builds the histogram and the count-min sketch of the collected values, the most
+// frequent values of the samples are moved to the top-n list of the sketch.
+func (c *SampleCollector) BuildColumnOrIndex(id int64, opts AnalyzeOptions) (*Histogram, *CMSketch) {
+	sort.Slice(c.Samples, func(i, j int) bool { return bytes.Compare(c.Samples[i], c.Samples[j]) < 0 })
+	c.CMSketch.extractTopN(c.Samples, opts.NumTopN)
+	hg := buildHistogram(id, c.Samples, c.Count, c.FMSketch.NDV(), c.NullCount, opts.NumBuckets)
+	return hg, c.CMSketch
+}
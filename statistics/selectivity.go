@@ -0,0 +1,253 @@
+package statistics
+
+import (
+	"math"
+
+	"grant-db/expression"
+	"grant-db/sessionctx"
+	"grant-db/sessionctx/stmtctx"
+	"grant-db/types"
+	"grant-db/util/ranger"
+)
+
+// Selectivity estimates the selectivity of the conditions. The conditions of each column are estimated by the
+// ranges of the column, and the other conditions are estimated by selectionFactor.
+func (coll *HistColl) Selectivity(ctx sessionctx.Context, exprs []expression.Expression) (float64, error) {
+	if coll.Count == 0 || len(exprs) == 0 {
+		return 1, nil
+	}
+	sc := ctx.GetSessionVars().StmtCtx
+	ret := 1.0
+	remained := exprs
+	visited := make(map[int64]struct{})
+	for _, col := range expression.ExtractColumnsFromExpressions(exprs) {
+		if _, ok := visited[col.UniqueID]; ok {
+			continue
+		}
+		visited[col.UniqueID] = struct{}{}
+		colStats, ok := coll.Columns[col.ID]
+		if !ok {
+			continue
+		}
+		var access, others []expression.Expression
+		access, others = ranger.DetachCondsForColumn(ctx, remained, col)
+		if len(access) == 0 {
+			continue
+		}
+		var (
+			ranges []*ranger.Range
+			cnt    float64
+			err    error
+		)
+		if colStats.IsHandle {
+			ranges, err = ranger.BuildTableRange(access, sc, col.RetType)
+			if err == nil {
+				cnt, err = coll.GetRowCountByIntColumnRanges(sc, col.ID, ranges)
+			}
+		} else {
+			ranges, err = ranger.BuildColumnRange(access, sc, col, types.UnspecifiedLength)
+			if err == nil {
+				cnt, err = coll.GetRowCountByColumnRanges(sc, col.ID, ranges)
+			}
+		}
+		if err != nil {
+			// The conditions whose ranges can't be built are estimated as the others.
+			continue
+		}
+		remained = others
+		ret *= cnt / float64(coll.Count)
+	}
+	if len(remained) > 0 {
+		ret *= selectionFactor
+	}
+	return ret, nil
+}
+
+// GetRowCountByIntColumnRanges estimates the row count by a slice of the ranges of the integer handle.
+func (coll *HistColl) GetRowCountByIntColumnRanges(sc *stmtctx.StatementContext, colID int64, intRanges []*ranger.Range) (float64, error) {
+	if len(intRanges) == 0 {
+		return 0, nil
+	}
+	if c, ok := coll.Columns[colID]; ok && coll.usable(c.Histogram) {
+		cnt, err := c.getRowCountByRanges(sc, intRanges)
+		if err != nil {
+			return 0, err
+		}
+		return coll.scaleRowCount(c.Histogram, cnt), nil
+	}
+	if intRanges[0].LowVal[0].Kind() == types.KindInt64 {
+		return getPseudoRowCountBySignedIntRanges(intRanges, float64(coll.Count)), nil
+	}
+	return getPseudoRowCountByUnsignedIntRanges(intRanges, float64(coll.Count)), nil
+}
+
+// GetRowCountByColumnRanges estimates the row count by a slice of the ranges of a column.
+func (coll *HistColl) GetRowCountByColumnRanges(sc *stmtctx.StatementContext, colID int64, colRanges []*ranger.Range) (float64, error) {
+	if c, ok := coll.Columns[colID]; ok && coll.usable(c.Histogram) {
+		cnt, err := c.getRowCountByRanges(sc, colRanges)
+		if err != nil {
+			return 0, err
+		}
+		return coll.scaleRowCount(c.Histogram, cnt), nil
+	}
+	return getPseudoRowCountByColumnRanges(sc, float64(coll.Count), colRanges, 0)
+}
+
+// GetRowCountByIndexRanges estimates the row count by a slice of the ranges of an index.
+func (coll *HistColl) GetRowCountByIndexRanges(sc *stmtctx.StatementContext, idxID int64, indexRanges []*ranger.Range) (float64, error) {
+	idx, ok := coll.Indices[idxID]
+	if ok && coll.usable(idx.Histogram) {
+		cnt, err := idx.getRowCountByRanges(sc, indexRanges)
+		if err != nil {
+			return 0, err
+		}
+		return coll.scaleRowCount(idx.Histogram, cnt), nil
+	}
+	colsLen := -1
+	if ok && idx.Info.Unique {
+		colsLen = len(idx.Info.Columns)
+	}
+	return getPseudoRowCountByIndexRanges(sc, indexRanges, float64(coll.Count), colsLen)
+}
+
+// scaleRowCount scales the row count estimated by the histogram to the current row count of the table.
+func (coll *HistColl) scaleRowCount(hg *Histogram, rowCount float64) float64 {
+	return math.Min(rowCount*coll.increaseFactor(hg), float64(coll.Count))
+}
+
+// getPseudoRowCountByIndexRanges estimates the row count of the index ranges, a point of all the columns of
+// a unique index is one row.
+func getPseudoRowCountByIndexRanges(sc *stmtctx.StatementContext, indexRanges []*ranger.Range,
+	tableRowCount float64, colsLen int) (float64, error) {
+	if tableRowCount == 0 {
+		return 0, nil
+	}
+	var totalCount float64
+	for _, indexRange := range indexRanges {
+		count := tableRowCount
+		i, err := indexRange.PrefixEqualLen(sc)
+		if err != nil {
+			return 0, err
+		}
+		if i == colsLen && !indexRange.LowExclude && !indexRange.HighExclude {
+			totalCount += 1.0
+			continue
+		}
+		if i >= len(indexRange.LowVal) {
+			i = len(indexRange.LowVal) - 1
+		}
+		rowCount, err := getPseudoRowCountByColumnRanges(sc, tableRowCount, []*ranger.Range{indexRange}, i)
+		if err != nil {
+			return 0, err
+		}
+		count = count / tableRowCount * rowCount
+		// If the condition is a = 1, b = 1, c = 1, d = 1, we think every a=1, b=1, c=1 only filtrate 1/100 data,
+		// so as to avoid collapsing too fast.
+		for j := 0; j < i; j++ {
+			count = count / float64(100)
+		}
+		totalCount += count
+	}
+	if totalCount > tableRowCount {
+		totalCount = tableRowCount / 3.0
+	}
+	return totalCount, nil
+}
+
+// getPseudoRowCountByColumnRanges estimates the row count of the ranges by the colIdx-th values of them.
+func getPseudoRowCountByColumnRanges(sc *stmtctx.StatementContext, tableRowCount float64, columnRanges []*ranger.Range, colIdx int) (float64, error) {
+	var rowCount float64
+	for _, ran := range columnRanges {
+		if len(ran.LowVal) <= colIdx {
+			rowCount += tableRowCount
+			continue
+		}
+		if ran.LowVal[colIdx].Kind() == types.KindNull && ran.HighVal[colIdx].Kind() == types.KindMaxValue {
+			rowCount += tableRowCount
+		} else if ran.LowVal[colIdx].Kind() == types.KindMinNotNull {
+			nullCount := tableRowCount / pseudoEqualRate
+			if ran.HighVal[colIdx].Kind() == types.KindMaxValue {
+				rowCount += tableRowCount - nullCount
+			} else {
+				lessCount := tableRowCount / pseudoLessRate
+				rowCount += lessCount - nullCount
+			}
+		} else if ran.HighVal[colIdx].Kind() == types.KindMaxValue {
+			rowCount += tableRowCount / pseudoLessRate
+		} else {
+			compare, err := ran.LowVal[colIdx].CompareDatum(sc, &ran.HighVal[colIdx])
+			if err != nil {
+				return 0, err
+			}
+			if compare == 0 {
+				rowCount += tableRowCount / pseudoEqualRate
+			} else {
+				rowCount += tableRowCount / pseudoBetweenRate
+			}
+		}
+	}
+	if rowCount > tableRowCount {
+		rowCount = tableRowCount
+	}
+	return rowCount, nil
+}
+
+func getPseudoRowCountBySignedIntRanges(intRanges []*ranger.Range, tableRowCount float64) float64 {
+	var rowCount float64
+	for _, rg := range intRanges {
+		var cnt float64
+		low := rg.LowVal[0].GetInt64()
+		high := rg.HighVal[0].GetInt64()
+		if low == math.MinInt64 && high == math.MaxInt64 {
+			cnt = tableRowCount
+		} else if low == math.MinInt64 {
+			cnt = tableRowCount / pseudoLessRate
+		} else if high == math.MaxInt64 {
+			cnt = tableRowCount / pseudoLessRate
+		} else {
+			if low == high {
+				cnt = 1 // When primary key is handle, the equal row count is at most one.
+			} else {
+				cnt = tableRowCount / pseudoBetweenRate
+			}
+		}
+		if high-low > 0 && cnt > float64(high-low) {
+			cnt = float64(high - low)
+		}
+		rowCount += cnt
+	}
+	if rowCount > tableRowCount {
+		rowCount = tableRowCount
+	}
+	return rowCount
+}
+
+func getPseudoRowCountByUnsignedIntRanges(intRanges []*ranger.Range, tableRowCount float64) float64 {
+	var rowCount float64
+	for _, rg := range intRanges {
+		var cnt float64
+		low := rg.LowVal[0].GetUint64()
+		high := rg.HighVal[0].GetUint64()
+		if low == 0 && high == math.MaxUint64 {
+			cnt = tableRowCount
+		} else if low == 0 {
+			cnt = tableRowCount / pseudoLessRate
+		} else if high == math.MaxUint64 {
+			cnt = tableRowCount / pseudoLessRate
+		} else {
+			if low == high {
+				cnt = 1 // When primary key is handle, the equal row count is at most one.
+			} else {
+				cnt = tableRowCount / pseudoBetweenRate
+			}
+		}
+		if high > low && cnt > float64(high-low) {
+			cnt = float64(high - low)
+		}
+		rowCount += cnt
+	}
+	if rowCount > tableRowCount {
+		rowCount = tableRowCount
+	}
+	return rowCount
+}
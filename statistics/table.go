@@ -0,0 +1,137 @@
+package statistics
+
+import (
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+)
+
+const (
+	// PseudoVersion means the pseudo statistics version is 0.
+	PseudoVersion uint64 = 0
+
+	// PseudoRowCount export for other pkg to use.
+	// When we haven't analyzed a table, we use pseudo statistics to estimate costs.
+	// It has row count 10000, equal condition selects 1/1000 of total rows, less condition selects 1/3 of total rows,
+	// between condition selects 1/40 of total rows.
+	PseudoRowCount    = 10000
+	pseudoEqualRate   = 1000
+	pseudoLessRate    = 3
+	pseudoBetweenRate = 40
+
+	// selectionFactor is the selectivity of the conditions which can't be estimated by the ranges.
+	selectionFactor = 0.8
+)
+
+// Table represents statistics for a table.
+type Table struct {
+	HistColl
+	Version uint64
+	Name    string
+}
+
+// HistColl is a collection of the statistics of the columns and the indices, the column ids and the index
+// ids are the keys of the maps.
+type HistColl struct {
+	PhysicalID int64
+	Columns    map[int64]*Column
+	Indices    map[int64]*Index
+	// Count is the total row count of the table.
+	Count       int64
+	ModifyCount int64
+	// Pseudo is true if the statistics are estimated without analyzing the table.
+	Pseudo bool
+}
+
+// Column represents the statistics of a column, the histogram is nil if the column isn't analyzed.
+type Column struct {
+	Info *model.ColumnInfo
+	// IsHandle is true if the column is the integer primary key, which is the handle of the rows.
+	IsHandle  bool
+	Histogram *Histogram
+	CMSketch  *CMSketch
+}
+
+// Index represents the statistics of an index, the histogram is nil if the index isn't analyzed.
+type Index struct {
+	Info *model.IndexInfo
+	// ColInfos are the columns of the index, their collations encode the string values.
+	ColInfos  []*model.ColumnInfo
+	Histogram *Histogram
+	CMSketch  *CMSketch
+}
+
+// PseudoTable creates a pseudo table statistics.
+func PseudoTable(tblInfo *model.TableInfo) *Table {
+	t := NewTable(tblInfo, PseudoVersion, PseudoRowCount, 0)
+	t.Pseudo = true
+	return t
+}
+
+// NewTable creates the statistics of a table without the histograms, which are set when they are loaded.
+func NewTable(tblInfo *model.TableInfo, version uint64, count, modifyCount int64) *Table {
+	t := &Table{
+		HistColl: HistColl{
+			PhysicalID:  tblInfo.ID,
+			Count:       count,
+			ModifyCount: modifyCount,
+			Columns:     make(map[int64]*Column, len(tblInfo.Columns)),
+			Indices:     make(map[int64]*Index, len(tblInfo.Indices)),
+		},
+		Version: version,
+		Name:    tblInfo.Name.O,
+	}
+	for _, col := range tblInfo.Columns {
+		if col.State != model.StatePublic {
+			continue
+		}
+		t.Columns[col.ID] = &Column{
+			Info:     col,
+			IsHandle: tblInfo.PKIsHandle && mysql.HasPriKeyFlag(col.Flag),
+		}
+	}
+	for _, idx := range tblInfo.Indices {
+		if idx.State != model.StatePublic {
+			continue
+		}
+		colInfos := make([]*model.ColumnInfo, 0, len(idx.Columns))
+		for _, idxCol := range idx.Columns {
+			colInfos = append(colInfos, tblInfo.Columns[idxCol.Offset])
+		}
+		t.Indices[idx.ID] = &Index{Info: idx, ColInfos: colInfos}
+	}
+	return t
+}
+
+// Copy copies the table statistics, the histograms are shared.
+func (t *Table) Copy() *Table {
+	nt := &Table{
+		HistColl: t.HistColl,
+		Version:  t.Version,
+		Name:     t.Name,
+	}
+	nt.Columns = make(map[int64]*Column, len(t.Columns))
+	for id, col := range t.Columns {
+		nt.Columns[id] = col
+	}
+	nt.Indices = make(map[int64]*Index, len(t.Indices))
+	for id, idx := range t.Indices {
+		nt.Indices[id] = idx
+	}
+	return nt
+}
+
+// ColumnIsUnique checks whether the values of the column are unique, which is the handle or the single column
+// of a unique index.
+func (coll *HistColl) ColumnIsUnique(colID int64) bool {
+	if col, ok := coll.Columns[colID]; ok && col.IsHandle {
+		return true
+	}
+	for _, idx := range coll.Indices {
+		if idx.Info.Unique && len(idx.Info.Columns) == 1 {
+			if col, ok := coll.Columns[colID]; ok && col.Info.Offset == idx.Info.Columns[0].Offset {
+				return true
+			}
+		}
+	}
+	return false
+}
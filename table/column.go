@@ -0,0 +1,227 @@
+package table
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
+	"grant-db/sessionctx/stmtctx"
+	"grant-db/types"
+	"grant-db/types/json"
+)
+
+var (
+	// ErrUnknownColumn is returned when accessing an unknown column.
+	ErrUnknownColumn = terror.ClassTable.New(mysql.ErrBadField, mysql.MySQLErrName[mysql.ErrBadField])
+	// ErrNoDefaultValue is used when insert a row, the column value is not given, and the column has not null flag
+	// and it doesn't have a default value.
+	ErrNoDefaultValue = terror.ClassTable.New(mysql.ErrNoDefaultForField, mysql.MySQLErrName[mysql.ErrNoDefaultForField])
+	// ErrColumnCantNull is used when a NULL value is written to a NOT NULL column.
+	ErrColumnCantNull = terror.ClassTable.New(mysql.ErrBadNull, mysql.MySQLErrName[mysql.ErrBadNull])
+)
+
+// ChangingColumnPrefix is the name prefix of the hidden column which holds the converted values of a
+// column whose type is being modified.
+const ChangingColumnPrefix = "_Col$_"
+
+// Column provides meta data describing a table column.
+type Column struct {
+	*model.ColumnInfo
+}
+
+// ToColumn converts a *model.ColumnInfo to *Column.
+func ToColumn(col *model.ColumnInfo) *Column {
+	return &Column{col}
+}
+
+// FindCol finds column in cols by name.
+func FindCol(cols []*Column, name string) *Column {
+	for _, col := range cols {
+		if strings.EqualFold(col.Name.O, name) {
+			return col
+		}
+	}
+	return nil
+}
+
+// FindCols finds columns in cols by names.
+func FindCols(cols []*Column, names []string, pkIsHandle bool) ([]*Column, error) {
+	var rcols []*Column
+	for _, name := range names {
+		col := FindCol(cols, name)
+		if col != nil {
+			rcols = append(rcols, col)
+		} else if name == model.ExtraHandleName.L && !pkIsHandle {
+			rcols = append(rcols, ToColumn(model.NewExtraHandleColInfo()))
+		} else {
+			return nil, ErrUnknownColumn.GenWithStackByArgs(name, "field list")
+		}
+	}
+	return rcols, nil
+}
+
+// IsPKHandleColumn checks if the column is primary key handle column.
+func (c *Column) IsPKHandleColumn(tbInfo *model.TableInfo) bool {
+	return mysql.HasPriKeyFlag(c.Flag) && tbInfo.PKIsHandle
+}
+
+// CheckNotNull returns ErrColumnCantNull if the value of a NOT NULL column is NULL.
+func (c *Column) CheckNotNull(data types.Datum) error {
+	if mysql.HasNotNullFlag(c.Flag) && data.IsNull() {
+		return ErrColumnCantNull.GenWithStackByArgs(c.Name)
+	}
+	return nil
+}
+
+// CastValue converts the value written to the column of the row rowNum to the column type, the truncation
+// is turned into a warning or an error by the statement context. The conversion errors and warnings are
+// reported as the ones of the column.
+func CastValue(sc *stmtctx.StatementContext, val types.Datum, col *model.ColumnInfo, rowNum int64) (types.Datum, error) {
+	warnCnt := int(sc.WarningCount())
+	casted, err := val.ConvertTo(sc, &col.FieldType)
+	if err != nil {
+		err = columnConvertError(err, col, rowNum)
+	}
+	if warns := sc.GetWarnings(); len(warns) > warnCnt {
+		for i := warnCnt; i < len(warns); i++ {
+			warns[i].Err = columnConvertError(warns[i].Err, col, rowNum)
+		}
+		sc.SetWarnings(warns)
+	}
+	return casted, err
+}
+
+// columnConvertError returns the error of writing a value to the column of the row rowNum for the conversion
+// error err. A value too long for a string or BIT column is ErrDataTooLong, an out of range number is
+// ErrWarnDataOutOfRange, and a truncated value is ErrWarnDataTruncated except for the temporal types, whose
+// invalid values keep the error.
+func columnConvertError(err error, col *model.ColumnInfo, rowNum int64) error {
+	switch {
+	case types.ErrDataOutOfRange.Equal(err) && col.Tp == mysql.TypeBit,
+		types.ErrTruncatedWrongVal.Equal(err) && types.IsString(col.Tp):
+		return types.ErrDataTooLong.GenWithStack("Data too long for column '%s' at row %d", col.Name.O, rowNum)
+	case types.ErrDataOutOfRange.Equal(err):
+		return types.ErrWarnDataOutOfRange.GenWithStackByArgs(col.Name.O, rowNum)
+	case types.ErrTruncatedWrongVal.Equal(err) && !types.IsTypeTemporal(col.Tp):
+		return types.ErrWarnDataTruncated.GenWithStackByArgs(col.Name.O, rowNum)
+	}
+	return err
+}
+
+// GetColOriginDefaultValue gets default value of the column from original default value,
+// it fills the rows written before the column was added.
+func GetColOriginDefaultValue(col *model.ColumnInfo) (types.Datum, error) {
+	return getColDefaultValue(col, col.OriginDefaultValue)
+}
+
+// GetColDefaultValue gets default value of the column, it returns ErrNoDefaultValue
+// if the column has no default value.
+func GetColDefaultValue(col *model.ColumnInfo) (types.Datum, error) {
+	if mysql.HasNoDefaultValueFlag(col.Flag) {
+		return types.Datum{}, ErrNoDefaultValue.GenWithStackByArgs(col.Name)
+	}
+	return getColDefaultValue(col, col.GetDefaultValue())
+}
+
+func getColDefaultValue(col *model.ColumnInfo, defaultVal interface{}) (types.Datum, error) {
+	if defaultVal == nil {
+		return types.Datum{}, nil
+	}
+	s, ok := defaultVal.(string)
+	if !ok {
+		return types.NewDatum(defaultVal), nil
+	}
+	if (col.Tp == mysql.TypeTimestamp || col.Tp == mysql.TypeDatetime) && strings.EqualFold(s, ast.CurrentTimestamp) {
+		return types.NewTimeDatum(types.FromGoTime(time.Now(), col.Tp, int8(col.Decimal))), nil
+	}
+	return DatumFromString(&col.FieldType, s)
+}
+
+// DatumFromString converts the string form of a default value to the datum of the column type.
+func DatumFromString(ft *types.FieldType, s string) (types.Datum, error) {
+	switch ft.Tp {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong, mysql.TypeYear:
+		if mysql.HasUnsignedFlag(ft.Flag) {
+			v, err := strconv.ParseUint(s, 10, 64)
+			return types.NewUintDatum(v), err
+		}
+		v, err := strconv.ParseInt(s, 10, 64)
+		return types.NewIntDatum(v), err
+	case mysql.TypeFloat, mysql.TypeDouble:
+		v, err := strconv.ParseFloat(s, 64)
+		return types.NewFloat64Datum(v), err
+	case mysql.TypeNewDecimal:
+		dec := new(types.MyDecimal)
+		err := dec.FromString([]byte(s))
+		return types.NewDecimalDatum(dec), err
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		t, err := types.ParseTime(s, ft.Tp, int8(ft.Decimal))
+		return types.NewTimeDatum(t), err
+	case mysql.TypeDuration:
+		dur, err := types.ParseDuration(s, int8(ft.Decimal))
+		return types.NewDurationDatum(dur), err
+	case mysql.TypeEnum:
+		if s == "" {
+			return types.NewMysqlEnumDatum(types.Enum{}), nil
+		}
+		enum, err := types.ParseEnumName(ft.Elems, s)
+		return types.NewMysqlEnumDatum(enum), err
+	case mysql.TypeSet:
+		set, err := types.ParseSetName(ft.Elems, s)
+		return types.NewMysqlSetDatum(set), err
+	case mysql.TypeBit:
+		// A BIT value is kept in the string form of its uint64 value.
+		v, err := strconv.ParseUint(s, 10, 64)
+		return types.NewMysqlBitDatum(types.NewBinaryLiteralFromUint(v, (ft.Flen+7)>>3)), err
+	case mysql.TypeJSON:
+		j, err := json.ParseBinaryFromString(s)
+		return types.NewMysqlJSONDatum(j), err
+	}
+	if types.HasCharset(ft) && ft.Charset != "binary" {
+		return types.NewStringDatum(s), nil
+	}
+	return types.NewBytesDatum([]byte(s)), nil
+}
+
+// GetZeroValue gets zero value for given column type.
+func GetZeroValue(col *model.ColumnInfo) types.Datum {
+	var d types.Datum
+	switch col.Tp {
+	case mysql.TypeTiny, mysql.TypeInt24, mysql.TypeShort, mysql.TypeLong, mysql.TypeLonglong, mysql.TypeYear:
+		if mysql.HasUnsignedFlag(col.Flag) {
+			d.SetUint64(0)
+		} else {
+			d.SetInt64(0)
+		}
+	case mysql.TypeFloat, mysql.TypeDouble:
+		d.SetFloat64(0)
+	case mysql.TypeNewDecimal:
+		d.SetMysqlDecimal(new(types.MyDecimal))
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		t := types.ZeroDatetime
+		t.SetType(col.Tp)
+		t.SetFsp(int8(col.Decimal))
+		d.SetMysqlTime(t)
+	case mysql.TypeDuration:
+		d.SetMysqlDuration(types.Duration{Fsp: int8(col.Decimal)})
+	case mysql.TypeEnum:
+		d.SetMysqlEnum(types.Enum{})
+	case mysql.TypeSet:
+		d.SetMysqlSet(types.Set{})
+	case mysql.TypeBit:
+		d.SetMysqlBit(types.NewBinaryLiteralFromUint(0, (col.Flen+7)>>3))
+	case mysql.TypeJSON:
+		d.SetMysqlJSON(json.CreateBinary(nil))
+	default:
+		if types.HasCharset(&col.FieldType) && col.Charset != "binary" {
+			d.SetString("")
+		} else {
+			d.SetBytes([]byte{})
+		}
+	}
+	return d
+}
@@ -0,0 +1,67 @@
+package table_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pingcap/parser/mysql"
+	"grant-db/util/testkit"
+)
+
+// checkWarnings checks the codes of the warnings of the last statement.
+func checkWarnings(t *testing.T, tk *testkit.TestKit, codes ...int) {
+	t.Helper()
+	rows := tk.MustQuery("show warnings").Rows()
+	got := make([]string, 0, len(rows))
+	for _, row := range rows {
+		got = append(got, strings.Fields(row)[1])
+	}
+	if fmt.Sprint(got) != fmt.Sprint(codes) {
+		t.Fatalf("the warnings are %v, expected the codes %v", rows, codes)
+	}
+}
+
+func TestCastValueErrors(t *testing.T) {
+	store, _ := testkit.NewMockStore(t)
+	tk := testkit.NewTestKit(t, store)
+	tk.MustExec("create database test")
+	tk.MustExec("use test")
+	tk.MustExec("create table t (e enum('a', 'b'), s set('a', 'b'), d decimal(5, 2), y year, i tinyint, c char(2), b bit(4), dt date)")
+	tests := []struct {
+		sql  string
+		code int
+	}{
+		{"insert into t (e) values ('zz')", mysql.WarnDataTruncated},
+		{"insert into t (s) values ('zz')", mysql.WarnDataTruncated},
+		{"insert into t (i) values ('1x')", mysql.WarnDataTruncated},
+		{"insert into t (d) values (1234.5)", mysql.ErrWarnDataOutOfRange},
+		{"insert into t (y) values (1800)", mysql.ErrWarnDataOutOfRange},
+		{"insert into t (i) values (1000)", mysql.ErrWarnDataOutOfRange},
+		{"insert into t (c) values ('abc')", mysql.ErrDataTooLong},
+		{"insert into t (b) values (100)", mysql.ErrDataTooLong},
+		{"insert into t (dt) values ('2020-02-30')", mysql.ErrTruncatedWrongValue},
+	}
+	// The strict mode rejects the values.
+	for _, tt := range tests {
+		tk.MustGetErrCode(tt.sql, tt.code)
+	}
+	tk.MustExec("insert into t (e, d) values ('a', 1.5)")
+	tk.MustGetErrCode("update t set d = -1000", mysql.ErrWarnDataOutOfRange)
+	tk.MustQuery("select e, d from t").Check("a 1.50")
+
+	// Otherwise they're clipped with the warnings of the same codes.
+	tk.MustExec("set sql_mode = ''")
+	tk.MustExec("delete from t")
+	for _, tt := range tests {
+		tk.MustExec(tt.sql)
+		checkWarnings(t, tk, tt.code)
+	}
+	tk.MustQuery("select e, s, d, y, i, c, b + 0, dt from t where e is not null and e = ''").Check(" <nil> <nil> <nil> <nil> <nil> <nil> <nil>")
+	tk.MustQuery("select d, y from t where d is not null or y is not null order by d").Check("<nil> 0", "999.99 <nil>")
+	tk.MustQuery("select i from t where i is not null order by i").Check("1", "127")
+	tk.MustQuery("select c, b + 0 from t where c is not null or b is not null order by c").Check("<nil> 15", "ab <nil>")
+	tk.MustExec("insert into t (e, y) values (3, '2156'), ('b', '69')")
+	checkWarnings(t, tk, mysql.WarnDataTruncated, mysql.ErrWarnDataOutOfRange)
+	tk.MustQuery("select e, y from t where y is not null order by y").Check("<nil> 0", " 0", "b 2069")
+}
@@ -0,0 +1,27 @@
+package table
+
+import (
+	"github.com/pingcap/parser/model"
+	"grant-db/kv"
+	"grant-db/types"
+)
+
+// Index is the interface for index data on KV store.
+type Index interface {
+	// Meta returns IndexInfo.
+	Meta() *model.IndexInfo
+	// Prefix returns the key prefix of the index.
+	Prefix() kv.Key
+	// Create supports insert into statement, it returns kv.ErrKeyExists if a unique key is taken by another handle.
+	Create(rm kv.RetrieverMutator, indexedValues []types.Datum, h int64) error
+	// Delete supports delete from statement.
+	Delete(m kv.Mutator, indexedValues []types.Datum, h int64) error
+	// Drop supports drop index, it deletes all the index entries.
+	Drop(rm kv.RetrieverMutator) error
+	// Exist supports check index exists or not.
+	Exist(rm kv.Retriever, indexedValues []types.Datum, h int64) (bool, int64, error)
+	// GenIndexKey generates an index key.
+	GenIndexKey(indexedValues []types.Datum, h int64, buf []byte) (key []byte, distinct bool, err error)
+	// FetchValues fetches index column values in a row.
+	FetchValues(row []types.Datum, dst []types.Datum) ([]types.Datum, error)
+}
@@ -0,0 +1,83 @@
+package table
+
+import (
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
+	"grant-db/kv"
+	"grant-db/meta/autoid"
+	"grant-db/sessionctx"
+	"grant-db/types"
+)
+
+// RecordFunc is used for low-level record iteration, it stops the iteration when more is false.
+type RecordFunc func(h int64, rec []types.Datum, cols []*Column) (more bool, err error)
+
+// Table is used to retrieve and modify rows in table.
+type Table interface {
+	// IterRecords iterates records in the table and calls fn.
+	IterRecords(retriever kv.Retriever, startKey kv.Key, cols []*Column, fn RecordFunc) error
+
+	// RowWithCols returns a row that contains the given cols.
+	RowWithCols(retriever kv.Retriever, h int64, cols []*Column) ([]types.Datum, error)
+
+	// Cols returns the public columns of the table which are used in select.
+	Cols() []*Column
+
+	// WritableCols returns columns of the table in writable states.
+	// Writable states includes Public, WriteOnly, WriteOnlyReorganization.
+	WritableCols() []*Column
+
+	// DeletableCols returns columns of the table in deletable states.
+	// Deletable states includes Public, WriteOnly, WriteOnlyReorganization, DeleteOnly, DeleteReorganization.
+	DeletableCols() []*Column
+
+	// Indices returns the indices of the table.
+	Indices() []Index
+
+	// WritableIndices returns write-only and public indices of the table.
+	WritableIndices() []Index
+
+	// DeletableIndices returns delete-only, write-only and public indices of the table.
+	DeletableIndices() []Index
+
+	// RecordPrefix returns the record key prefix.
+	RecordPrefix() kv.Key
+
+	// IndexPrefix returns the index key prefix.
+	IndexPrefix() kv.Key
+
+	// FirstKey returns the first key.
+	FirstKey() kv.Key
+
+	// RecordKey returns the key in KV storage for the row.
+	RecordKey(h int64) kv.Key
+
+	// Meta returns TableInfo.
+	Meta() *model.TableInfo
+
+	// AddRecord inserts a row of the public columns into the table and returns its handle, the handle
+	// is allocated if the primary key isn't the handle. It returns kv.ErrKeyExists without writing
+	// anything if the handle or a unique key is taken.
+	AddRecord(ctx sessionctx.Context, r []types.Datum) (recordID int64, err error)
+
+	// UpdateRecord updates the row of the handle from oldData to newData, which are rows of the public
+	// columns, the row moves to a new handle if the primary key handle is changed. It returns
+	// kv.ErrKeyExists without writing anything if the new handle or a unique key is taken by another row.
+	UpdateRecord(ctx sessionctx.Context, h int64, oldData, newData []types.Datum) error
+
+	// RemoveRecord removes the row of the handle, r is the row of the public columns.
+	RemoveRecord(ctx sessionctx.Context, h int64, r []types.Datum) error
+
+	// Allocator returns the allocator of the handles.
+	Allocator() autoid.Allocator
+}
+
+var (
+	// ErrColumnStateCantNone is returned when a column's state is none.
+	ErrColumnStateCantNone = terror.ClassTable.New(mysql.ErrUnknown, "column %s can't be in none state")
+	// ErrIndexStateCantNone is returned when a index's state is none.
+	ErrIndexStateCantNone = terror.ClassTable.New(mysql.ErrUnknown, "index %s can't be in none state")
+	// ErrIndexOutBound returns for index column offset out of bound.
+	ErrIndexOutBound = terror.ClassTable.New(mysql.ErrUnknown, "index column %s offset out of bound, offset: %d, row: %v")
+)
@@ -0,0 +1,231 @@
+package tables
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pingcap/parser/model"
+	"grant-db/kv"
+	"grant-db/table"
+	"grant-db/tablecodec"
+	"grant-db/types"
+	"grant-db/util/codec"
+	"grant-db/util/collate"
+)
+
+// index is the data structure for index data in the KV store.
+// A unique index without null values stores
+//
+//	[index prefix][encoded values] -> handle
+//
+// and the other index entries store
+//
+//	[index prefix][encoded values][handle] -> "0"
+type index struct {
+	tblInfo *model.TableInfo
+	idxInfo *model.IndexInfo
+	prefix  kv.Key
+}
+
+// NewIndex builds a new Index object.
+func NewIndex(tblInfo *model.TableInfo, indexInfo *model.IndexInfo) table.Index {
+	return &index{
+		tblInfo: tblInfo,
+		idxInfo: indexInfo,
+		prefix:  tablecodec.EncodeTableIndexPrefix(tblInfo.ID, indexInfo.ID),
+	}
+}
+
+// Meta returns index info.
+func (c *index) Meta() *model.IndexInfo {
+	return c.idxInfo
+}
+
+// Prefix returns the key prefix of the index.
+func (c *index) Prefix() kv.Key {
+	return c.prefix
+}
+
+// truncateIndexValuesIfNeeded truncates the index values created using only the leading part of column values.
+func (c *index) truncateIndexValuesIfNeeded(indexedValues []types.Datum) []types.Datum {
+	for i := 0; i < len(indexedValues) && i < len(c.idxInfo.Columns); i++ {
+		v := &indexedValues[i]
+		length := c.idxInfo.Columns[i].Length
+		if length == types.UnspecifiedLength {
+			continue
+		}
+		switch v.Kind() {
+		case types.KindString:
+			s := v.GetString()
+			if utf8.RuneCountInString(s) > length {
+				rs := []rune(s)
+				v.SetString(string(rs[:length]))
+			}
+		case types.KindBytes:
+			if b := v.GetBytes(); len(b) > length {
+				v.SetBytes(b[:length])
+			}
+		}
+	}
+	return indexedValues
+}
+
+// collationKeys returns the index values with the strings replaced by their sort keys in the collations of
+// the columns, so the strings which are equal in the collation have the same index key. The strings of the binary
+// collations are kept as they are, which keeps the keys compatible with the ones written before collations.
+func (c *index) collationKeys(indexedValues []types.Datum) []types.Datum {
+	var keys []types.Datum
+	for i := 0; i < len(indexedValues) && i < len(c.idxInfo.Columns); i++ {
+		v := &indexedValues[i]
+		if v.Kind() != types.KindString && v.Kind() != types.KindBytes {
+			continue
+		}
+		col := c.tblInfo.Columns[c.idxInfo.Columns[i].Offset]
+		if collate.IsBinCollation(col.Collate) {
+			continue
+		}
+		if keys == nil {
+			keys = append([]types.Datum(nil), indexedValues...)
+		}
+		keys[i].SetBytes(collate.GetCollator(col.Collate).Key(v.GetString()))
+	}
+	if keys == nil {
+		return indexedValues
+	}
+	return keys
+}
+
+// GenIndexKey generates storage key for index values. Returned distinct indicates whether the
+// indexed values should be distinct in storage (i.e. whether handle is encoded in the key).
+func (c *index) GenIndexKey(indexedValues []types.Datum, h int64, buf []byte) (key []byte, distinct bool, err error) {
+	if c.idxInfo.Unique {
+		// See https://dev.mysql.com/doc/refman/5.7/en/create-index.html
+		// A UNIQUE index creates a constraint such that all values in the index must be distinct.
+		// An error occurs if you try to add a new row with a key value that matches an existing row.
+		// For all engines, a UNIQUE index permits multiple NULL values for columns that can contain NULL.
+		distinct = true
+		for _, cv := range indexedValues {
+			if cv.IsNull() {
+				distinct = false
+				break
+			}
+		}
+	}
+	key = append(buf[:0], c.prefix...)
+	key, err = codec.EncodeKey(key, c.collationKeys(c.truncateIndexValuesIfNeeded(indexedValues))...)
+	if err != nil {
+		return nil, false, err
+	}
+	if !distinct {
+		key, err = codec.EncodeKey(key, types.NewIntDatum(h))
+	}
+	return key, distinct, err
+}
+
+// Create creates a new entry in the kvIndex data.
+// If the index is unique and there is an existing entry with the same key,
+// Create will return kv.ErrKeyExists.
+func (c *index) Create(rm kv.RetrieverMutator, indexedValues []types.Datum, h int64) error {
+	key, distinct, err := c.GenIndexKey(indexedValues, h, nil)
+	if err != nil {
+		return err
+	}
+	if !distinct {
+		return rm.Set(key, []byte{'0'})
+	}
+	value, err := rm.Get(context.Background(), key)
+	if kv.IsErrNotFound(err) {
+		return rm.Set(key, codec.EncodeInt(nil, h))
+	}
+	if err != nil {
+		return err
+	}
+	if _, handle, err := codec.DecodeInt(value); err != nil || handle == h {
+		return err
+	}
+	return kv.ErrKeyExists.FastGenByArgs(c.entryString(indexedValues), c.idxInfo.Name.String())
+}
+
+func (c *index) entryString(indexedValues []types.Datum) string {
+	strs := make([]string, 0, len(indexedValues))
+	for _, v := range indexedValues {
+		str, err := v.ToString()
+		if err != nil {
+			str = v.String()
+		}
+		strs = append(strs, str)
+	}
+	return strings.Join(strs, "-")
+}
+
+// Delete removes the entry for handle h and indexedValues from KV index.
+func (c *index) Delete(m kv.Mutator, indexedValues []types.Datum, h int64) error {
+	key, _, err := c.GenIndexKey(indexedValues, h, nil)
+	if err != nil {
+		return err
+	}
+	return m.Delete(key)
+}
+
+// Drop removes the KV index from store.
+func (c *index) Drop(rm kv.RetrieverMutator) error {
+	it, err := rm.Iter(c.prefix, c.prefix.PrefixNext())
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+
+	// remove all indices
+	for it.Valid() {
+		if !it.Key().HasPrefix(c.prefix) {
+			break
+		}
+		if err := rm.Delete(it.Key()); err != nil {
+			return err
+		}
+		if err := it.Next(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Exist supports check index exists or not.
+func (c *index) Exist(rm kv.Retriever, indexedValues []types.Datum, h int64) (bool, int64, error) {
+	key, distinct, err := c.GenIndexKey(indexedValues, h, nil)
+	if err != nil {
+		return false, 0, err
+	}
+	value, err := rm.Get(context.Background(), key)
+	if kv.IsErrNotFound(err) {
+		return false, 0, nil
+	}
+	if err != nil {
+		return false, 0, err
+	}
+	if !distinct {
+		return true, h, nil
+	}
+	_, handle, err := codec.DecodeInt(value)
+	if err != nil {
+		return false, 0, err
+	}
+	return handle == h, handle, nil
+}
+
+// FetchValues implements table.Index interface.
+func (c *index) FetchValues(r []types.Datum, vals []types.Datum) ([]types.Datum, error) {
+	needLength := len(c.idxInfo.Columns)
+	if vals == nil || cap(vals) < needLength {
+		vals = make([]types.Datum, needLength)
+	}
+	vals = vals[:needLength]
+	for i, ic := range c.idxInfo.Columns {
+		if ic.Offset < 0 || ic.Offset >= len(r) {
+			return nil, table.ErrIndexOutBound.GenWithStackByArgs(ic.Name, ic.Offset, r)
+		}
+		r[ic.Offset].Copy(&vals[i])
+	}
+	return vals, nil
+}
@@ -0,0 +1,525 @@
+package tables
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"strings"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/model"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/kv"
+	"grant-db/meta/autoid"
+	"grant-db/sessionctx"
+	"grant-db/sessionctx/stmtctx"
+	"grant-db/table"
+	"grant-db/tablecodec"
+	"grant-db/types"
+	"grant-db/util/codec"
+)
+
+// TableCommon is shared by both Table and partition.
+type TableCommon struct {
+	alloc        autoid.Allocator
+	tableID      int64
+	Columns      []*table.Column
+	indices      []table.Index
+	meta         *model.TableInfo
+	recordPrefix kv.Key
+	indexPrefix  kv.Key
+}
+
+// MockTableFromMeta only serves for test.
+func MockTableFromMeta(tblInfo *model.TableInfo) table.Table {
+	t, _ := TableFromMeta(nil, tblInfo)
+	return t
+}
+
+// TableFromMeta creates a Table instance from model.TableInfo, alloc allocates the handles of the rows
+// and it may be nil if the table isn't written.
+func TableFromMeta(alloc autoid.Allocator, tblInfo *model.TableInfo) (table.Table, error) {
+	columns := make([]*table.Column, 0, len(tblInfo.Columns))
+	for _, colInfo := range tblInfo.Columns {
+		if colInfo.State == model.StateNone {
+			return nil, table.ErrColumnStateCantNone.GenWithStackByArgs(colInfo.Name)
+		}
+		columns = append(columns, table.ToColumn(colInfo))
+	}
+	t := &TableCommon{
+		alloc:        alloc,
+		tableID:      tblInfo.ID,
+		Columns:      columns,
+		meta:         tblInfo,
+		recordPrefix: tablecodec.GenTableRecordPrefix(tblInfo.ID),
+		indexPrefix:  tablecodec.GenTableIndexPrefix(tblInfo.ID),
+	}
+	for _, idxInfo := range tblInfo.Indices {
+		if idxInfo.State == model.StateNone {
+			return nil, table.ErrIndexStateCantNone.GenWithStackByArgs(idxInfo.Name)
+		}
+		t.indices = append(t.indices, NewIndex(tblInfo, idxInfo))
+	}
+	return t, nil
+}
+
+// Meta implements table.Table Meta interface.
+func (t *TableCommon) Meta() *model.TableInfo {
+	return t.meta
+}
+
+// Cols implements table.Table Cols interface.
+func (t *TableCommon) Cols() []*table.Column {
+	publicColumns := make([]*table.Column, 0, len(t.Columns))
+	for _, col := range t.Columns {
+		if col.State == model.StatePublic {
+			publicColumns = append(publicColumns, col)
+		}
+	}
+	return publicColumns
+}
+
+// WritableCols implements table WritableCols interface.
+func (t *TableCommon) WritableCols() []*table.Column {
+	writableColumns := make([]*table.Column, 0, len(t.Columns))
+	for _, col := range t.Columns {
+		if col.State == model.StateDeleteOnly || col.State == model.StateDeleteReorganization {
+			continue
+		}
+		writableColumns = append(writableColumns, col)
+	}
+	return writableColumns
+}
+
+// DeletableCols implements table DeletableCols interface.
+func (t *TableCommon) DeletableCols() []*table.Column {
+	return t.Columns
+}
+
+// Indices implements table.Table Indices interface.
+func (t *TableCommon) Indices() []table.Index {
+	return t.indices
+}
+
+// WritableIndices implements table.Table WritableIndices interface.
+func (t *TableCommon) WritableIndices() []table.Index {
+	writable := make([]table.Index, 0, len(t.indices))
+	for _, index := range t.indices {
+		s := index.Meta().State
+		if s != model.StateDeleteOnly && s != model.StateDeleteReorganization {
+			writable = append(writable, index)
+		}
+	}
+	return writable
+}
+
+// DeletableIndices implements table.Table DeletableIndices interface.
+func (t *TableCommon) DeletableIndices() []table.Index {
+	return t.indices
+}
+
+// RecordPrefix implements table.Table interface.
+func (t *TableCommon) RecordPrefix() kv.Key {
+	return t.recordPrefix
+}
+
+// IndexPrefix implements table.Table interface.
+func (t *TableCommon) IndexPrefix() kv.Key {
+	return t.indexPrefix
+}
+
+// RecordKey implements table.Table interface.
+func (t *TableCommon) RecordKey(h int64) kv.Key {
+	return tablecodec.EncodeRecordKey(t.recordPrefix, h)
+}
+
+// FirstKey implements table.Table interface.
+func (t *TableCommon) FirstKey() kv.Key {
+	return t.RecordKey(math.MinInt64)
+}
+
+// IterRecords implements table.Table IterRecords interface.
+func (t *TableCommon) IterRecords(retriever kv.Retriever, startKey kv.Key, cols []*table.Column, fn table.RecordFunc) error {
+	it, err := retriever.Iter(startKey, t.RecordPrefix().PrefixNext())
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+
+	colMap := make(map[int64]*types.FieldType, len(cols))
+	for _, col := range cols {
+		colMap[col.ID] = &col.FieldType
+	}
+	for it.Valid() && it.Key().HasPrefix(t.RecordPrefix()) {
+		handle, err := tablecodec.DecodeRowKey(it.Key())
+		if err != nil {
+			return err
+		}
+		rowMap, err := tablecodec.DecodeRowToDatumMap(it.Value(), colMap)
+		if err != nil {
+			return err
+		}
+		data, err := t.fillRow(handle, rowMap, cols)
+		if err != nil {
+			return err
+		}
+		more, err := fn(handle, data, cols)
+		if !more || err != nil {
+			return err
+		}
+		if err := it.Next(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RowWithCols implements table.Table RowWithCols interface.
+func (t *TableCommon) RowWithCols(retriever kv.Retriever, h int64, cols []*table.Column) ([]types.Datum, error) {
+	value, err := retriever.Get(context.Background(), t.RecordKey(h))
+	if err != nil {
+		return nil, err
+	}
+	colMap := make(map[int64]*types.FieldType, len(cols))
+	for _, col := range cols {
+		colMap[col.ID] = &col.FieldType
+	}
+	rowMap, err := tablecodec.DecodeRowToDatumMap(value, colMap)
+	if err != nil {
+		return nil, err
+	}
+	return t.fillRow(h, rowMap, cols)
+}
+
+// fillRow arranges the decoded columns in the order of cols, the handle column comes from
+// the handle and the columns absent from the row value get their original default values.
+func (t *TableCommon) fillRow(h int64, rowMap map[int64]types.Datum, cols []*table.Column) ([]types.Datum, error) {
+	data := make([]types.Datum, len(cols))
+	for i, col := range cols {
+		if col == nil {
+			continue
+		}
+		if col.IsPKHandleColumn(t.meta) || col.ID == model.ExtraHandleID {
+			if mysql.HasUnsignedFlag(col.Flag) {
+				data[i].SetUint64(uint64(h))
+			} else {
+				data[i].SetInt64(h)
+			}
+			continue
+		}
+		if d, ok := rowMap[col.ID]; ok {
+			data[i] = d
+			continue
+		}
+		d, err := table.GetColOriginDefaultValue(col.ColumnInfo)
+		if err != nil {
+			return nil, err
+		}
+		data[i] = d
+	}
+	return data, nil
+}
+
+// Allocator implements table.Table Allocator interface.
+func (t *TableCommon) Allocator() autoid.Allocator {
+	return t.alloc
+}
+
+// AddRecord implements table.Table AddRecord interface.
+func (t *TableCommon) AddRecord(ctx sessionctx.Context, r []types.Datum) (int64, error) {
+	txn, err := ctx.Txn(true)
+	if err != nil {
+		return 0, err
+	}
+	row, err := t.fullRow(ctx.GetSessionVars().StmtCtx, r, nil)
+	if err != nil {
+		return 0, err
+	}
+	var h int64
+	if pkCol := t.pkHandleCol(); pkCol != nil {
+		h = row[pkCol.Offset].GetInt64()
+		if err = t.checkHandleNotExists(txn, h, row[pkCol.Offset]); err != nil {
+			return 0, err
+		}
+	} else {
+		if t.alloc == nil {
+			return 0, errors.Errorf("table %s can't allocate handles", t.meta.Name)
+		}
+		if _, h, err = t.alloc.Alloc(1, 1, 1); err != nil {
+			return 0, err
+		}
+	}
+	if err = t.checkUniqueKeys(txn, h, h, row, nil); err != nil {
+		return 0, err
+	}
+	if err = t.addIndices(txn, h, row, nil); err != nil {
+		return 0, err
+	}
+	return h, t.writeRow(txn, h, row)
+}
+
+// UpdateRecord implements table.Table UpdateRecord interface.
+func (t *TableCommon) UpdateRecord(ctx sessionctx.Context, h int64, oldData, newData []types.Datum) error {
+	txn, err := ctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	oldRow, err := t.storedRow(txn, h, oldData)
+	if err != nil {
+		return err
+	}
+	newRow, err := t.fullRow(ctx.GetSessionVars().StmtCtx, newData, oldRow)
+	if err != nil {
+		return err
+	}
+	newH := h
+	if pkCol := t.pkHandleCol(); pkCol != nil {
+		newH = newRow[pkCol.Offset].GetInt64()
+	}
+	if newH == h {
+		if err = t.checkUniqueKeys(txn, h, h, newRow, oldRow); err != nil {
+			return err
+		}
+		if err = t.removeIndices(txn, h, oldRow, newRow); err != nil {
+			return err
+		}
+		if err = t.addIndices(txn, h, newRow, oldRow); err != nil {
+			return err
+		}
+		return t.writeRow(txn, h, newRow)
+	}
+	// The row moves to the new handle, the unique keys of the row itself are taken by the old handle.
+	if err = t.checkHandleNotExists(txn, newH, newRow[t.pkHandleCol().Offset]); err != nil {
+		return err
+	}
+	if err = t.checkUniqueKeys(txn, newH, h, newRow, nil); err != nil {
+		return err
+	}
+	if err = t.removeIndices(txn, h, oldRow, nil); err != nil {
+		return err
+	}
+	if err = txn.Delete(t.RecordKey(h)); err != nil {
+		return err
+	}
+	if err = t.addIndices(txn, newH, newRow, nil); err != nil {
+		return err
+	}
+	return t.writeRow(txn, newH, newRow)
+}
+
+// RemoveRecord implements table.Table RemoveRecord interface.
+func (t *TableCommon) RemoveRecord(ctx sessionctx.Context, h int64, r []types.Datum) error {
+	txn, err := ctx.Txn(true)
+	if err != nil {
+		return err
+	}
+	row, err := t.storedRow(txn, h, r)
+	if err != nil {
+		return err
+	}
+	if err = t.removeIndices(txn, h, row, nil); err != nil {
+		return err
+	}
+	return txn.Delete(t.RecordKey(h))
+}
+
+func (t *TableCommon) pkHandleCol() *table.Column {
+	if !t.meta.PKIsHandle {
+		return nil
+	}
+	for _, col := range t.Columns {
+		if col.IsPKHandleColumn(t.meta) {
+			return col
+		}
+	}
+	return nil
+}
+
+// fullRow arranges the values of the public columns r by the offsets of all the columns. The changing
+// columns get the converted values of the columns whose types are being modified, and the other non-public
+// writable columns keep their values in old, the stored row, or get their original default values.
+func (t *TableCommon) fullRow(sc *stmtctx.StatementContext, r, old []types.Datum) ([]types.Datum, error) {
+	row := make([]types.Datum, len(t.Columns))
+	i := 0
+	for _, col := range t.Columns {
+		if col.State == model.StatePublic {
+			row[col.Offset] = r[i]
+			i++
+		}
+	}
+	for _, col := range t.Columns {
+		if col.State == model.StatePublic || col.State == model.StateDeleteOnly || col.State == model.StateDeleteReorganization {
+			continue
+		}
+		var err error
+		if strings.HasPrefix(col.Name.O, table.ChangingColumnPrefix) {
+			if oldCol := table.FindCol(t.Columns, strings.TrimPrefix(col.Name.O, table.ChangingColumnPrefix)); oldCol != nil {
+				row[col.Offset], err = row[oldCol.Offset].ConvertTo(sc, &col.FieldType)
+				if err != nil {
+					return nil, err
+				}
+				continue
+			}
+		}
+		if old != nil {
+			row[col.Offset] = old[col.Offset]
+			continue
+		}
+		if row[col.Offset], err = table.GetColOriginDefaultValue(col.ColumnInfo); err != nil {
+			return nil, err
+		}
+	}
+	return row, nil
+}
+
+// storedRow returns the row of all the columns of the handle, r is the row of the public columns, which
+// is the stored row if all the columns are public.
+func (t *TableCommon) storedRow(txn kv.Transaction, h int64, r []types.Datum) ([]types.Datum, error) {
+	if len(r) == len(t.Columns) {
+		return r, nil
+	}
+	return t.RowWithCols(txn, h, t.Columns)
+}
+
+func (t *TableCommon) checkHandleNotExists(txn kv.Transaction, h int64, val types.Datum) error {
+	_, err := txn.Get(context.Background(), t.RecordKey(h))
+	if kv.IsErrNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return kv.ErrKeyExists.FastGenByArgs(val.String(), "PRIMARY")
+}
+
+// checkUniqueKeys returns kv.ErrKeyExists if a unique key of the row of handle h is taken by a handle other
+// than h and oldH. The keys which are the same as the ones of old are skipped.
+func (t *TableCommon) checkUniqueKeys(txn kv.Transaction, h, oldH int64, row, old []types.Datum) error {
+	for _, idx := range t.WritableIndices() {
+		if !idx.Meta().Unique {
+			continue
+		}
+		vals, err := idx.FetchValues(row, nil)
+		if err != nil {
+			return err
+		}
+		key, distinct, err := idx.GenIndexKey(vals, h, nil)
+		if err != nil {
+			return err
+		}
+		if !distinct {
+			continue
+		}
+		if old != nil {
+			same, err := sameIndexKey(idx, key, h, old)
+			if err != nil {
+				return err
+			}
+			if same {
+				continue
+			}
+		}
+		value, err := txn.Get(context.Background(), key)
+		if kv.IsErrNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		_, handle, err := codec.DecodeInt(value)
+		if err != nil {
+			return err
+		}
+		if handle != h && handle != oldH {
+			return kv.ErrKeyExists.FastGenByArgs(idx.(*index).entryString(vals), idx.Meta().Name.String())
+		}
+	}
+	return nil
+}
+
+// sameIndexKey checks if key is the index key of the row of handle h.
+func sameIndexKey(idx table.Index, key []byte, h int64, row []types.Datum) (bool, error) {
+	vals, err := idx.FetchValues(row, nil)
+	if err != nil {
+		return false, err
+	}
+	rowKey, _, err := idx.GenIndexKey(vals, h, nil)
+	return bytes.Equal(key, rowKey), err
+}
+
+// changedIndexKey checks if the index key of row differs from the one of other, it's true if other is nil.
+func changedIndexKey(idx table.Index, vals []types.Datum, h int64, other []types.Datum) (bool, error) {
+	if other == nil {
+		return true, nil
+	}
+	key, _, err := idx.GenIndexKey(vals, h, nil)
+	if err != nil {
+		return false, err
+	}
+	same, err := sameIndexKey(idx, key, h, other)
+	return !same, err
+}
+
+// addIndices creates the index entries of the row, the ones which are the same as the entries of old are
+// skipped.
+func (t *TableCommon) addIndices(txn kv.Transaction, h int64, row, old []types.Datum) error {
+	for _, idx := range t.WritableIndices() {
+		vals, err := idx.FetchValues(row, nil)
+		if err != nil {
+			return err
+		}
+		changed, err := changedIndexKey(idx, vals, h, old)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			continue
+		}
+		if err = idx.Create(txn, vals, h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeIndices deletes the index entries of the row, the ones which are the same as the entries of
+// newRow are kept.
+func (t *TableCommon) removeIndices(txn kv.Transaction, h int64, row, newRow []types.Datum) error {
+	for _, idx := range t.DeletableIndices() {
+		vals, err := idx.FetchValues(row, nil)
+		if err != nil {
+			return err
+		}
+		changed, err := changedIndexKey(idx, vals, h, newRow)
+		if err != nil {
+			return err
+		}
+		if !changed {
+			continue
+		}
+		if err = idx.Delete(txn, vals, h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeRow encodes the values of the writable columns of the row and writes it, the handle column isn't
+// stored in the value.
+func (t *TableCommon) writeRow(txn kv.Transaction, h int64, row []types.Datum) error {
+	cols := t.WritableCols()
+	colIDs := make([]int64, 0, len(cols))
+	vals := make([]types.Datum, 0, len(cols))
+	for _, col := range cols {
+		if col.IsPKHandleColumn(t.meta) {
+			continue
+		}
+		colIDs = append(colIDs, col.ID)
+		vals = append(vals, row[col.Offset])
+	}
+	value, err := tablecodec.EncodeRow(vals, colIDs, nil)
+	if err != nil {
+		return err
+	}
+	return txn.Set(t.RecordKey(h), value)
+}
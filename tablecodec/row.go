@@ -0,0 +1,133 @@
+package tablecodec
+
+import (
+	"errors"
+	"fmt"
+
+	"grant-db/types"
+	"grant-db/util/codec"
+)
+
+// rowVersion is the first byte of every encoded row value.
+const rowVersion byte = 128
+
+// EncodeRow encodes row data and column ids into a slice of byte.
+// The layout is:
+//  [rowVersion][notNullCount][nullCount][notNull column IDs...][null column IDs...][values...]
+// Counts and column IDs are varints, values of the not null columns are encoded with codec.EncodeValue
+// in the order of their IDs. Null columns only keep their IDs so a null value differs from a column
+// absent from the row, which is filled by the column default when decoding.
+// valBuf is an optional input parameter to reduce memory allocation.
+func EncodeRow(row []types.Datum, colIDs []int64, valBuf []byte) ([]byte, error) {
+	if len(row) != len(colIDs) {
+		return nil, fmt.Errorf("invalid row: len(row) %d != len(colIDs) %d", len(row), len(colIDs))
+	}
+	notNull := make([]int, 0, len(row))
+	null := make([]int, 0)
+	for i := range row {
+		if row[i].IsNull() {
+			null = append(null, i)
+		} else {
+			notNull = append(notNull, i)
+		}
+	}
+	valBuf = append(valBuf[:0], rowVersion)
+	valBuf = codec.EncodeUvarint(valBuf, uint64(len(notNull)))
+	valBuf = codec.EncodeUvarint(valBuf, uint64(len(null)))
+	for _, i := range notNull {
+		valBuf = codec.EncodeVarint(valBuf, colIDs[i])
+	}
+	for _, i := range null {
+		valBuf = codec.EncodeVarint(valBuf, colIDs[i])
+	}
+	var err error
+	for _, i := range notNull {
+		valBuf, err = codec.EncodeValue(valBuf, row[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	return valBuf, nil
+}
+
+type rowHeader struct {
+	notNullIDs []int64
+	nullIDs    []int64
+}
+
+func decodeRowHeader(b []byte) (rowHeader, []byte, error) {
+	var h rowHeader
+	if len(b) == 0 || b[0] != rowVersion {
+		return h, nil, errors.New("invalid row value")
+	}
+	b = b[1:]
+	b, notNullCnt, err := codec.DecodeUvarint(b)
+	if err != nil {
+		return h, nil, err
+	}
+	b, nullCnt, err := codec.DecodeUvarint(b)
+	if err != nil {
+		return h, nil, err
+	}
+	if notNullCnt+nullCnt > uint64(len(b)) {
+		return h, nil, errors.New("invalid row value")
+	}
+	h.notNullIDs = make([]int64, notNullCnt)
+	for i := range h.notNullIDs {
+		if b, h.notNullIDs[i], err = codec.DecodeVarint(b); err != nil {
+			return h, nil, err
+		}
+	}
+	h.nullIDs = make([]int64, nullCnt)
+	for i := range h.nullIDs {
+		if b, h.nullIDs[i], err = codec.DecodeVarint(b); err != nil {
+			return h, nil, err
+		}
+	}
+	return h, b, nil
+}
+
+// DecodeRowToDatumMap decodes a byte slice into datums with an existing row map.
+// Columns in cols which are absent from the row are absent from the returned map as well,
+// the caller is responsible for filling their default values.
+func DecodeRowToDatumMap(b []byte, cols map[int64]*types.FieldType) (map[int64]types.Datum, error) {
+	h, b, err := decodeRowHeader(b)
+	if err != nil {
+		return nil, err
+	}
+	row := make(map[int64]types.Datum, len(cols))
+	for _, id := range h.notNullIDs {
+		var data []byte
+		data, b, err = codec.CutOne(b)
+		if err != nil {
+			return nil, err
+		}
+		ft, ok := cols[id]
+		if !ok {
+			continue
+		}
+		_, d, err := codec.DecodeOne(data)
+		if err != nil {
+			return nil, err
+		}
+		if d, err = codec.Unflatten(d, ft); err != nil {
+			return nil, err
+		}
+		row[id] = d
+	}
+	for _, id := range h.nullIDs {
+		if _, ok := cols[id]; ok {
+			row[id] = types.Datum{}
+		}
+	}
+	return row, nil
+}
+
+// DecodeRowColumnIDs returns the IDs of all the columns stored in the row value.
+func DecodeRowColumnIDs(b []byte) ([]int64, error) {
+	h, _, err := decodeRowHeader(b)
+	if err != nil {
+		return nil, err
+	}
+	return append(h.notNullIDs, h.nullIDs...), nil
+}
@@ -0,0 +1,220 @@
+package tablecodec
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"grant-db/kv"
+	"grant-db/types"
+	"grant-db/util/codec"
+)
+
+var (
+	tablePrefix     = []byte{'t'}
+	recordPrefixSep = []byte("_r")
+	indexPrefixSep  = []byte("_i")
+	metaPrefix      = []byte{'m'}
+)
+
+const (
+	idLen                 = 8
+	prefixLen             = 1 + idLen /*tableID*/ + 2
+	// RecordRowKeyLen is public for calculating average row size.
+	RecordRowKeyLen       = prefixLen + idLen /*handle*/
+	tablePrefixLength     = 1
+	recordPrefixSepLength = 2
+)
+
+// ErrInvalidKey is returned when a key doesn't have the expected layout.
+var ErrInvalidKey = errors.New("invalid key")
+
+// TablePrefix returns table's prefix 't'.
+func TablePrefix() []byte {
+	return tablePrefix
+}
+
+// MetaPrefix returns meta prefix 'm'.
+func MetaPrefix() []byte {
+	return metaPrefix
+}
+
+// EncodeTablePrefix encodes table prefix with table ID.
+func EncodeTablePrefix(tableID int64) kv.Key {
+	var key kv.Key
+	key = append(key, tablePrefix...)
+	key = codec.EncodeInt(key, tableID)
+	return key
+}
+
+// appendTableRecordPrefix appends table record prefix  "t[tableID]_r".
+func appendTableRecordPrefix(buf []byte, tableID int64) []byte {
+	buf = append(buf, tablePrefix...)
+	buf = codec.EncodeInt(buf, tableID)
+	buf = append(buf, recordPrefixSep...)
+	return buf
+}
+
+// appendTableIndexPrefix appends table index prefix  "t[tableID]_i".
+func appendTableIndexPrefix(buf []byte, tableID int64) []byte {
+	buf = append(buf, tablePrefix...)
+	buf = codec.EncodeInt(buf, tableID)
+	buf = append(buf, indexPrefixSep...)
+	return buf
+}
+
+// GenTableRecordPrefix composes record prefix with tableID: "t[tableID]_r".
+func GenTableRecordPrefix(tableID int64) kv.Key {
+	buf := make([]byte, 0, len(tablePrefix)+8+len(recordPrefixSep))
+	return appendTableRecordPrefix(buf, tableID)
+}
+
+// GenTableIndexPrefix composes index prefix with tableID: "t[tableID]_i".
+func GenTableIndexPrefix(tableID int64) kv.Key {
+	buf := make([]byte, 0, len(tablePrefix)+8+len(indexPrefixSep))
+	return appendTableIndexPrefix(buf, tableID)
+}
+
+// EncodeRecordKey encodes the recordPrefix, row handle into a kv.Key.
+func EncodeRecordKey(recordPrefix kv.Key, h int64) kv.Key {
+	buf := make([]byte, 0, len(recordPrefix)+idLen)
+	buf = append(buf, recordPrefix...)
+	buf = codec.EncodeInt(buf, h)
+	return buf
+}
+
+// EncodeRowKeyWithHandle encodes the table id, row handle into a kv.Key
+func EncodeRowKeyWithHandle(tableID int64, handle int64) kv.Key {
+	return EncodeRecordKey(GenTableRecordPrefix(tableID), handle)
+}
+
+// DecodeRecordKey decodes the key and gets the tableID, handle.
+func DecodeRecordKey(key kv.Key) (tableID int64, handle int64, err error) {
+	if len(key) != RecordRowKeyLen || !key.HasPrefix(tablePrefix) {
+		return 0, 0, fmt.Errorf("%w %q", ErrInvalidKey, key)
+	}
+	k := key[tablePrefixLength:]
+	k, tableID, err = codec.DecodeInt(k)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !bytes.HasPrefix(k, recordPrefixSep) {
+		return 0, 0, fmt.Errorf("%w record key %q", ErrInvalidKey, key)
+	}
+	k = k[recordPrefixSepLength:]
+	_, handle, err = codec.DecodeInt(k)
+	return
+}
+
+// DecodeRowKey decodes the key and gets the handle.
+func DecodeRowKey(key kv.Key) (int64, error) {
+	_, handle, err := DecodeRecordKey(key)
+	return handle, err
+}
+
+// DecodeTableID decodes the table ID of the key, if the key is not table key, returns 0.
+func DecodeTableID(key kv.Key) int64 {
+	if !key.HasPrefix(tablePrefix) {
+		return 0
+	}
+	key = key[len(tablePrefix):]
+	_, tableID, err := codec.DecodeInt(key)
+	if err != nil {
+		return 0
+	}
+	return tableID
+}
+
+// IsRecordKey is used to check whether the key is an record key.
+func IsRecordKey(k []byte) bool {
+	return len(k) > prefixLen && k[0] == 't' && k[prefixLen-1] == 'r'
+}
+
+// IsIndexKey is used to check whether the key is an index key.
+func IsIndexKey(k []byte) bool {
+	return len(k) > prefixLen && k[0] == 't' && k[prefixLen-1] == 'i'
+}
+
+// EncodeTableIndexPrefix encodes index prefix with tableID and idxID.
+func EncodeTableIndexPrefix(tableID, idxID int64) kv.Key {
+	key := make([]byte, 0, prefixLen+idLen)
+	key = appendTableIndexPrefix(key, tableID)
+	key = codec.EncodeInt(key, idxID)
+	return key
+}
+
+// EncodeIndexSeekKey encodes an index value to kv.Key: "t[tableID]_i[indexID][encodedValue]".
+func EncodeIndexSeekKey(tableID int64, idxID int64, encodedValue []byte) kv.Key {
+	key := make([]byte, 0, prefixLen+idLen+len(encodedValue))
+	key = appendTableIndexPrefix(key, tableID)
+	key = codec.EncodeInt(key, idxID)
+	key = append(key, encodedValue...)
+	return key
+}
+
+// DecodeIndexKey decodes the key and gets the tableID, indexID, indexValues.
+func DecodeIndexKey(key kv.Key) (tableID int64, indexID int64, indexValues []types.Datum, err error) {
+	k := key
+
+	tableID, indexID, isRecord, err := DecodeKeyHead(key)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	if isRecord {
+		return 0, 0, nil, fmt.Errorf("%w index key %q", ErrInvalidKey, k)
+	}
+	if len(key) > prefixLen+idLen {
+		indexValues, err = codec.Decode(key[prefixLen+idLen:], 2)
+		if err != nil {
+			return 0, 0, nil, err
+		}
+	}
+	return tableID, indexID, indexValues, nil
+}
+
+// DecodeKeyHead decodes the key's head and gets the tableID, indexID. isRecordKey is true when is a record key.
+func DecodeKeyHead(key kv.Key) (tableID int64, indexID int64, isRecordKey bool, err error) {
+	isRecordKey = false
+	k := key
+	if !key.HasPrefix(tablePrefix) {
+		err = fmt.Errorf("%w %q", ErrInvalidKey, k)
+		return
+	}
+
+	key = key[len(tablePrefix):]
+	key, tableID, err = codec.DecodeInt(key)
+	if err != nil {
+		return
+	}
+
+	if key.HasPrefix(recordPrefixSep) {
+		isRecordKey = true
+		return
+	}
+	if !key.HasPrefix(indexPrefixSep) {
+		err = fmt.Errorf("%w %q", ErrInvalidKey, k)
+		return
+	}
+
+	key = key[len(indexPrefixSep):]
+
+	key, indexID, err = codec.DecodeInt(key)
+	return
+}
+
+// CutIndexKey cuts encoded index key into colIDs to bytes slices map.
+// The returned value b is the remaining bytes of the key which would be empty if it is unique index or handle data
+// if it is non-unique index.
+func CutIndexKey(key kv.Key, length int) (values [][]byte, b []byte, err error) {
+	b = key[prefixLen+idLen:]
+	values = make([][]byte, 0, length)
+	for i := 0; i < length; i++ {
+		var val []byte
+		val, b, err = codec.CutOne(b)
+		if err != nil {
+			return nil, nil, err
+		}
+		values = append(values, val)
+	}
+	return
+}
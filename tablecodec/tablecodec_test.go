@@ -0,0 +1,219 @@
+package tablecodec
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"testing"
+	"testing/quick"
+
+	"github.com/pingcap/parser/mysql"
+	"grant-db/kv"
+	"grant-db/types"
+	"grant-db/util/codec"
+)
+
+func compareInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// quickConfig is the config of the round-trip and order checks, which run on the seeds and then on random values.
+var quickConfig = &quick.Config{MaxCount: 1000}
+
+// checkOrder checks that the order of the keys a and b is cmp, which is the order of what they encode.
+func checkOrder(t *testing.T, cmp int, a, b kv.Key) {
+	t.Helper()
+	if got := bytes.Compare(a, b); got != cmp {
+		t.Fatalf("the keys %q and %q are in order %d, expected %d", a, b, got, cmp)
+	}
+}
+
+func TestRowKey(t *testing.T) {
+	check := func(tableA, handleA, tableB, handleB int64) bool {
+		keyA, keyB := EncodeRowKeyWithHandle(tableA, handleA), EncodeRowKeyWithHandle(tableB, handleB)
+		if !IsRecordKey(keyA) || IsIndexKey(keyA) {
+			t.Fatalf("%q isn't a record key", keyA)
+		}
+		tableID, handle, err := DecodeRecordKey(keyA)
+		if err != nil || tableID != tableA || handle != handleA {
+			t.Fatalf("DecodeRecordKey(%q) = %d, %d, %v, expected %d, %d", keyA, tableID, handle, err, tableA, handleA)
+		}
+		if tableID = DecodeTableID(keyA); tableID != tableA {
+			t.Fatalf("DecodeTableID(%q) = %d, expected %d", keyA, tableID, tableA)
+		}
+		if !keyA.HasPrefix(GenTableRecordPrefix(tableA)) || !keyA.HasPrefix(EncodeTablePrefix(tableA)) {
+			t.Fatalf("%q doesn't have the prefix of table %d", keyA, tableA)
+		}
+		cmp := compareInt(tableA, tableB)
+		if cmp == 0 {
+			cmp = compareInt(handleA, handleB)
+		}
+		checkOrder(t, cmp, keyA, keyB)
+		if _, _, err = DecodeRecordKey(keyA[:len(keyA)-1]); err == nil {
+			t.Fatalf("the truncated key %q is decoded", keyA[:len(keyA)-1])
+		}
+		return true
+	}
+	check(1, math.MinInt64, 1, math.MaxInt64)
+	check(1, -1, 2, -2)
+	check(math.MaxInt64, 0, -1, 0)
+	if err := quick.Check(check, quickConfig); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// indexEntry is the key of an entry of a non-unique index on (int, varchar), the handle is appended to the values.
+type indexEntry struct {
+	tableID, indexID int64
+	intVal           int64
+	strVal           []byte
+	handle           int64
+}
+
+func (e *indexEntry) key(t *testing.T) kv.Key {
+	t.Helper()
+	vals, err := codec.EncodeKey(nil, types.NewIntDatum(e.intVal), types.NewBytesDatum(e.strVal), types.NewIntDatum(e.handle))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return EncodeIndexSeekKey(e.tableID, e.indexID, vals)
+}
+
+func (e *indexEntry) compare(o *indexEntry) int {
+	for _, cmp := range []int{compareInt(e.tableID, o.tableID), compareInt(e.indexID, o.indexID),
+		compareInt(e.intVal, o.intVal), bytes.Compare(e.strVal, o.strVal)} {
+		if cmp != 0 {
+			return cmp
+		}
+	}
+	return compareInt(e.handle, o.handle)
+}
+
+func TestIndexKey(t *testing.T) {
+	check := func(tableID, indexID, intA int64, strA []byte, handleA, intB int64, strB []byte, handleB int64) bool {
+		a := &indexEntry{tableID, indexID, intA, strA, handleA}
+		b := &indexEntry{tableID, indexID ^ (intB & 1), intB, strB, handleB}
+		keyA, keyB := a.key(t), b.key(t)
+		if !IsIndexKey(keyA) || IsRecordKey(keyA) {
+			t.Fatalf("%q isn't an index key", keyA)
+		}
+		if !keyA.HasPrefix(EncodeTableIndexPrefix(tableID, indexID)) || !keyA.HasPrefix(GenTableIndexPrefix(tableID)) {
+			t.Fatalf("%q doesn't have the prefix of index %d of table %d", keyA, indexID, tableID)
+		}
+		gotTable, gotIndex, vals, err := DecodeIndexKey(keyA)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gotTable != tableID || gotIndex != indexID || len(vals) != 3 || vals[0].GetInt64() != intA ||
+			!bytes.Equal(vals[1].GetBytes(), strA) || vals[2].GetInt64() != handleA {
+			t.Fatalf("DecodeIndexKey(%q) = %d, %d, %v, expected %+v", keyA, gotTable, gotIndex, vals, a)
+		}
+		if gotTable, gotIndex, isRecord, err := DecodeKeyHead(keyA); err != nil || isRecord || gotTable != tableID || gotIndex != indexID {
+			t.Fatalf("DecodeKeyHead(%q) = %d, %d, %v, %v", keyA, gotTable, gotIndex, isRecord, err)
+		}
+		// The handle is left after the indexed columns are cut.
+		cut, remain, err := CutIndexKey(keyA, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(cut) != 2 || !bytes.Equal(remain, codec.EncodeInt([]byte{3}, handleA)) {
+			t.Fatalf("CutIndexKey(%q) = %q, %q", keyA, cut, remain)
+		}
+		checkOrder(t, a.compare(b), keyA, keyB)
+		return true
+	}
+	check(1, 1, -1, []byte("a"), 1, 0, []byte("a"), 0)
+	check(1, 2, 0, []byte(""), math.MinInt64, 0, []byte("\x00"), math.MaxInt64)
+	check(5, 1, 7, []byte("abcdefgh"), 3, 7, []byte("abcdefgh"), -3)
+	if err := quick.Check(check, quickConfig); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIndexValue(t *testing.T) {
+	check := func(i int64, u uint64, fl float64, b []byte, unscaled int64, frac uint8) bool {
+		if math.IsNaN(fl) {
+			return true
+		}
+		dec := new(types.MyDecimal)
+		if err := dec.FromString([]byte(fmt.Sprintf("%de-%d", unscaled, frac%31))); err != nil {
+			t.Fatal(err)
+		}
+		tm := types.NewTime(int(u%10000), int(u>>16%13), int(u>>24%32), int(u>>32%24), int(u>>40%60), int(u>>48%60),
+			int(i%1000000+1000000)%1000000, mysql.TypeDatetime, types.MaxFsp)
+		datums := []types.Datum{types.NewIntDatum(i), types.NewUintDatum(u), types.NewFloat64Datum(fl), types.NewBytesDatum(b),
+			types.NewDecimalDatum(dec), types.NewTimeDatum(tm), {}}
+		fts := []*types.FieldType{types.NewFieldType(mysql.TypeLonglong), types.NewFieldType(mysql.TypeLonglong),
+			types.NewFieldType(mysql.TypeDouble), types.NewFieldType(mysql.TypeBlob), types.NewFieldType(mysql.TypeNewDecimal),
+			types.NewFieldType(mysql.TypeDatetime), types.NewFieldType(mysql.TypeLonglong)}
+		fts[5].Decimal = int(types.MaxFsp)
+
+		vals, err := codec.EncodeKey(nil, datums...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		key := EncodeIndexSeekKey(1, 1, vals)
+		_, _, decoded, err := DecodeIndexKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkDatums(t, decoded, datums, fts)
+
+		// The same values are stored in a row value by their column IDs.
+		colIDs := []int64{1, 2, 3, 4, 5, 6, 7}
+		row, err := EncodeRow(datums, colIDs, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cols := make(map[int64]*types.FieldType, len(colIDs))
+		for j, id := range colIDs {
+			cols[id] = fts[j]
+		}
+		rowMap, err := DecodeRowToDatumMap(row, cols)
+		if err != nil {
+			t.Fatal(err)
+		}
+		fromRow := make([]types.Datum, 0, len(colIDs))
+		for _, id := range colIDs {
+			d, ok := rowMap[id]
+			if !ok {
+				t.Fatalf("column %d is absent from the row %x", id, row)
+			}
+			fromRow = append(fromRow, d)
+		}
+		checkDatums(t, fromRow, datums, fts)
+		if ids, err := DecodeRowColumnIDs(row); err != nil || len(ids) != len(colIDs) || ids[len(ids)-1] != 7 {
+			t.Fatalf("DecodeRowColumnIDs(%x) = %v, %v", row, ids, err)
+		}
+		return true
+	}
+	check(0, 0, 0.0, []byte(""), 0, 0)
+	check(-1, math.MaxUint64, -1.5, []byte("abc"), -12345, 2)
+	check(math.MaxInt64, 1, math.Inf(1), []byte("\x00\xff"), math.MaxInt64, 30)
+	if err := quick.Check(check, quickConfig); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// checkDatums checks the decoded datums are the expected ones after they're restored by their field types.
+func checkDatums(t *testing.T, got, expected []types.Datum, fts []*types.FieldType) {
+	t.Helper()
+	if len(got) != len(expected) {
+		t.Fatalf("got %d values, expected %d", len(got), len(expected))
+	}
+	for j := range got {
+		d, err := codec.Unflatten(got[j], fts[j])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d.Kind() != expected[j].Kind() || d.String() != expected[j].String() {
+			t.Fatalf("value %d is decoded to %v, expected %v", j, d, expected[j])
+		}
+	}
+}
@@ -0,0 +1,1232 @@
+// Code generated by goyacc DO NOT EDIT.
+
+// Copyright 2020 PingCAP, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parser
+
+import __yyfmt__ "fmt"
+
+import (
+	"math"
+	"strconv"
+
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/model"
+)
+
+type yyhintSymType struct {
+	yys    int
+	ident  string
+	number uint64
+	hint   *ast.TableOptimizerHint
+	hints  []*ast.TableOptimizerHint
+	table  ast.HintTable
+}
+
+type yyhintXError struct {
+	state, xsym int
+}
+
+const (
+	yyhintDefault             = 57409
+	yyhintEOFCode             = 57344
+	yyhintErrCode             = 57345
+	hintAggToCop              = 57376
+	hintBKA                   = 57354
+	hintBNL                   = 57356
+	hintDupsWeedOut           = 57405
+	hintFalse                 = 57401
+	hintFirstMatch            = 57406
+	hintGB                    = 57404
+	hintHashAgg               = 57378
+	hintHashJoin              = 57358
+	hintIdentifier            = 57347
+	hintIgnoreIndex           = 57379
+	hintIgnorePlanCache       = 57377
+	hintIndexMerge            = 57362
+	hintInlHashJoin           = 57380
+	hintInlJoin               = 57381
+	hintInlMergeJoin          = 57382
+	hintIntLit                = 57346
+	hintJoinFixedOrder        = 57350
+	hintJoinOrder             = 57351
+	hintJoinPrefix            = 57352
+	hintJoinSuffix            = 57353
+	hintLooseScan             = 57407
+	hintMB                    = 57403
+	hintMRR                   = 57364
+	hintMaterialization       = 57408
+	hintMaxExecutionTime      = 57372
+	hintMemoryQuota           = 57383
+	hintMerge                 = 57360
+	hintNoBKA                 = 57355
+	hintNoBNL                 = 57357
+	hintNoHashJoin            = 57359
+	hintNoICP                 = 57366
+	hintNoIndexMerge          = 57363
+	hintNoMRR                 = 57365
+	hintNoMerge               = 57361
+	hintNoRangeOptimization   = 57367
+	hintNoSemijoin            = 57371
+	hintNoSkipScan            = 57369
+	hintNoSwapJoinInputs      = 57384
+	hintOLAP                  = 57397
+	hintOLTP                  = 57398
+	hintQBName                = 57375
+	hintQueryType             = 57385
+	hintReadConsistentReplica = 57386
+	hintReadFromStorage       = 57387
+	hintResourceGroup         = 57374
+	hintSMJoin                = 57388
+	hintSemijoin              = 57370
+	hintSetVar                = 57373
+	hintSingleAtIdentifier    = 57348
+	hintSkipScan              = 57368
+	hintStreamAgg             = 57389
+	hintStringLit             = 57349
+	hintSwapJoinInputs        = 57390
+	hintTiFlash               = 57400
+	hintTiKV                  = 57399
+	hintTimeRange             = 57395
+	hintTrue                  = 57402
+	hintUseCascades           = 57396
+	hintUseIndex              = 57392
+	hintUseIndexMerge         = 57391
+	hintUsePlanCache          = 57393
+	hintUseToja               = 57394
+
+	yyhintMaxDepth = 200
+	yyhintTabOfs   = -158
+)
+
+var (
+	yyhintXLAT = map[int]int{
+		41:    0,   // ')' (118x)
+		44:    1,   // ',' (108x)
+		57376: 2,   // hintAggToCop (108x)
+		57354: 3,   // hintBKA (108x)
+		57356: 4,   // hintBNL (108x)
+		57378: 5,   // hintHashAgg (108x)
+		57358: 6,   // hintHashJoin (108x)
+		57379: 7,   // hintIgnoreIndex (108x)
+		57377: 8,   // hintIgnorePlanCache (108x)
+		57362: 9,   // hintIndexMerge (108x)
+		57380: 10,  // hintInlHashJoin (108x)
+		57381: 11,  // hintInlJoin (108x)
+		57382: 12,  // hintInlMergeJoin (108x)
+		57350: 13,  // hintJoinFixedOrder (108x)
+		57351: 14,  // hintJoinOrder (108x)
+		57352: 15,  // hintJoinPrefix (108x)
+		57353: 16,  // hintJoinSuffix (108x)
+		57372: 17,  // hintMaxExecutionTime (108x)
+		57383: 18,  // hintMemoryQuota (108x)
+		57360: 19,  // hintMerge (108x)
+		57364: 20,  // hintMRR (108x)
+		57355: 21,  // hintNoBKA (108x)
+		57357: 22,  // hintNoBNL (108x)
+		57359: 23,  // hintNoHashJoin (108x)
+		57366: 24,  // hintNoICP (108x)
+		57363: 25,  // hintNoIndexMerge (108x)
+		57361: 26,  // hintNoMerge (108x)
+		57365: 27,  // hintNoMRR (108x)
+		57367: 28,  // hintNoRangeOptimization (108x)
+		57371: 29,  // hintNoSemijoin (108x)
+		57369: 30,  // hintNoSkipScan (108x)
+		57384: 31,  // hintNoSwapJoinInputs (108x)
+		57375: 32,  // hintQBName (108x)
+		57385: 33,  // hintQueryType (108x)
+		57386: 34,  // hintReadConsistentReplica (108x)
+		57387: 35,  // hintReadFromStorage (108x)
+		57374: 36,  // hintResourceGroup (108x)
+		57370: 37,  // hintSemijoin (108x)
+		57373: 38,  // hintSetVar (108x)
+		57368: 39,  // hintSkipScan (108x)
+		57388: 40,  // hintSMJoin (108x)
+		57389: 41,  // hintStreamAgg (108x)
+		57390: 42,  // hintSwapJoinInputs (108x)
+		57395: 43,  // hintTimeRange (108x)
+		57396: 44,  // hintUseCascades (108x)
+		57392: 45,  // hintUseIndex (108x)
+		57391: 46,  // hintUseIndexMerge (108x)
+		57393: 47,  // hintUsePlanCache (108x)
+		57394: 48,  // hintUseToja (108x)
+		57405: 49,  // hintDupsWeedOut (87x)
+		57406: 50,  // hintFirstMatch (87x)
+		57407: 51,  // hintLooseScan (87x)
+		57408: 52,  // hintMaterialization (87x)
+		57400: 53,  // hintTiFlash (87x)
+		57399: 54,  // hintTiKV (87x)
+		57401: 55,  // hintFalse (86x)
+		57397: 56,  // hintOLAP (86x)
+		57398: 57,  // hintOLTP (86x)
+		57402: 58,  // hintTrue (86x)
+		57404: 59,  // hintGB (85x)
+		57403: 60,  // hintMB (85x)
+		57347: 61,  // hintIdentifier (84x)
+		57348: 62,  // hintSingleAtIdentifier (76x)
+		93:    63,  // ']' (68x)
+		46:    64,  // '.' (61x)
+		61:    65,  // '=' (61x)
+		40:    66,  // '(' (55x)
+		57344: 67,  // $end (23x)
+		57427: 68,  // QueryBlockOpt (16x)
+		57421: 69,  // Identifier (11x)
+		57346: 70,  // hintIntLit (6x)
+		57349: 71,  // hintStringLit (5x)
+		57417: 72,  // HintTable (4x)
+		57418: 73,  // HintTableList (4x)
+		91:    74,  // '[' (3x)
+		57411: 75,  // CommaOpt (3x)
+		57410: 76,  // BooleanHintName (2x)
+		57412: 77,  // HintIndexList (2x)
+		57414: 78,  // HintStorageType (2x)
+		57415: 79,  // HintStorageTypeAndTable (2x)
+		57419: 80,  // HintTableListOpt (2x)
+		57424: 81,  // JoinOrderOptimizerHintName (2x)
+		57425: 82,  // NullaryHintName (2x)
+		57429: 83,  // StorageOptimizerHintOpt (2x)
+		57430: 84,  // SubqueryOptimizerHintName (2x)
+		57433: 85,  // SubqueryStrategy (2x)
+		57434: 86,  // SupportedIndexLevelOptimizerHintName (2x)
+		57435: 87,  // SupportedTableLevelOptimizerHintName (2x)
+		57436: 88,  // TableOptimizerHintOpt (2x)
+		57438: 89,  // UnsupportedIndexLevelOptimizerHintName (2x)
+		57439: 90,  // UnsupportedTableLevelOptimizerHintName (2x)
+		57413: 91,  // HintQueryType (1x)
+		57416: 92,  // HintStorageTypeAndTableList (1x)
+		57420: 93,  // HintTrueOrFalse (1x)
+		57422: 94,  // IndexNameList (1x)
+		57423: 95,  // IndexNameListOpt (1x)
+		57426: 96,  // OptimizerHintList (1x)
+		57428: 97,  // Start (1x)
+		57431: 98,  // SubqueryStrategies (1x)
+		57432: 99,  // SubqueryStrategiesOpt (1x)
+		57437: 100, // UnitOfBytes (1x)
+		57440: 101, // Value (1x)
+		57409: 102, // $default (0x)
+		57345: 103, // error (0x)
+	}
+
+	yyhintSymNames = []string{
+		"')'",
+		"','",
+		"hintAggToCop",
+		"hintBKA",
+		"hintBNL",
+		"hintHashAgg",
+		"hintHashJoin",
+		"hintIgnoreIndex",
+		"hintIgnorePlanCache",
+		"hintIndexMerge",
+		"hintInlHashJoin",
+		"hintInlJoin",
+		"hintInlMergeJoin",
+		"hintJoinFixedOrder",
+		"hintJoinOrder",
+		"hintJoinPrefix",
+		"hintJoinSuffix",
+		"hintMaxExecutionTime",
+		"hintMemoryQuota",
+		"hintMerge",
+		"hintMRR",
+		"hintNoBKA",
+		"hintNoBNL",
+		"hintNoHashJoin",
+		"hintNoICP",
+		"hintNoIndexMerge",
+		"hintNoMerge",
+		"hintNoMRR",
+		"hintNoRangeOptimization",
+		"hintNoSemijoin",
+		"hintNoSkipScan",
+		"hintNoSwapJoinInputs",
+		"hintQBName",
+		"hintQueryType",
+		"hintReadConsistentReplica",
+		"hintReadFromStorage",
+		"hintResourceGroup",
+		"hintSemijoin",
+		"hintSetVar",
+		"hintSkipScan",
+		"hintSMJoin",
+		"hintStreamAgg",
+		"hintSwapJoinInputs",
+		"hintTimeRange",
+		"hintUseCascades",
+		"hintUseIndex",
+		"hintUseIndexMerge",
+		"hintUsePlanCache",
+		"hintUseToja",
+		"hintDupsWeedOut",
+		"hintFirstMatch",
+		"hintLooseScan",
+		"hintMaterialization",
+		"hintTiFlash",
+		"hintTiKV",
+		"hintFalse",
+		"hintOLAP",
+		"hintOLTP",
+		"hintTrue",
+		"hintGB",
+		"hintMB",
+		"hintIdentifier",
+		"hintSingleAtIdentifier",
+		"']'",
+		"'.'",
+		"'='",
+		"'('",
+		"$end",
+		"QueryBlockOpt",
+		"Identifier",
+		"hintIntLit",
+		"hintStringLit",
+		"HintTable",
+		"HintTableList",
+		"'['",
+		"CommaOpt",
+		"BooleanHintName",
+		"HintIndexList",
+		"HintStorageType",
+		"HintStorageTypeAndTable",
+		"HintTableListOpt",
+		"JoinOrderOptimizerHintName",
+		"NullaryHintName",
+		"StorageOptimizerHintOpt",
+		"SubqueryOptimizerHintName",
+		"SubqueryStrategy",
+		"SupportedIndexLevelOptimizerHintName",
+		"SupportedTableLevelOptimizerHintName",
+		"TableOptimizerHintOpt",
+		"UnsupportedIndexLevelOptimizerHintName",
+		"UnsupportedTableLevelOptimizerHintName",
+		"HintQueryType",
+		"HintStorageTypeAndTableList",
+		"HintTrueOrFalse",
+		"IndexNameList",
+		"IndexNameListOpt",
+		"OptimizerHintList",
+		"Start",
+		"SubqueryStrategies",
+		"SubqueryStrategiesOpt",
+		"UnitOfBytes",
+		"Value",
+		"$default",
+		"error",
+	}
+
+	yyhintReductions = []struct{ xsym, components int }{
+		{0, 1},
+		{97, 1},
+		{96, 1},
+		{96, 3},
+		{96, 1},
+		{96, 3},
+		{88, 4},
+		{88, 4},
+		{88, 4},
+		{88, 4},
+		{88, 4},
+		{88, 4},
+		{88, 5},
+		{88, 5},
+		{88, 6},
+		{88, 4},
+		{88, 4},
+		{88, 6},
+		{88, 6},
+		{88, 5},
+		{88, 4},
+		{88, 5},
+		{83, 5},
+		{92, 1},
+		{92, 3},
+		{79, 4},
+		{68, 0},
+		{68, 1},
+		{75, 0},
+		{75, 1},
+		{80, 1},
+		{80, 1},
+		{73, 2},
+		{73, 3},
+		{72, 2},
+		{72, 4},
+		{77, 4},
+		{95, 0},
+		{95, 1},
+		{94, 1},
+		{94, 3},
+		{99, 0},
+		{99, 1},
+		{98, 1},
+		{98, 3},
+		{101, 1},
+		{101, 1},
+		{101, 1},
+		{100, 1},
+		{100, 1},
+		{93, 1},
+		{93, 1},
+		{81, 1},
+		{81, 1},
+		{81, 1},
+		{90, 1},
+		{90, 1},
+		{90, 1},
+		{90, 1},
+		{90, 1},
+		{90, 1},
+		{90, 1},
+		{87, 1},
+		{87, 1},
+		{87, 1},
+		{87, 1},
+		{87, 1},
+		{87, 1},
+		{87, 1},
+		{89, 1},
+		{89, 1},
+		{89, 1},
+		{89, 1},
+		{89, 1},
+		{89, 1},
+		{89, 1},
+		{86, 1},
+		{86, 1},
+		{86, 1},
+		{84, 1},
+		{84, 1},
+		{85, 1},
+		{85, 1},
+		{85, 1},
+		{85, 1},
+		{76, 1},
+		{76, 1},
+		{82, 1},
+		{82, 1},
+		{82, 1},
+		{82, 1},
+		{82, 1},
+		{82, 1},
+		{82, 1},
+		{91, 1},
+		{91, 1},
+		{78, 1},
+		{78, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+		{69, 1},
+	}
+
+	yyhintXErrors = map[yyhintXError]string{}
+
+	yyhintParseTab = [232][]uint16{
+		// 0
+		{2: 214, 183, 185, 212, 196, 205, 217, 197, 192, 191, 195, 163, 180, 181, 182, 170, 174, 188, 198, 184, 186, 187, 200, 215, 189, 199, 201, 208, 203, 194, 173, 178, 216, 179, 172, 207, 171, 202, 190, 213, 193, 175, 210, 204, 206, 211, 209, 76: 176, 81: 164, 177, 162, 169, 86: 168, 166, 161, 167, 165, 96: 160, 159},
+		{67: 158},
+		{1: 319, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 67: 157, 75: 387},
+		{1: 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 156, 67: 156},
+		{1: 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 154, 67: 154},
+		// 5
+		{66: 384},
+		{66: 381},
+		{66: 378},
+		{66: 373},
+		{66: 370},
+		// 10
+		{66: 359},
+		{66: 347},
+		{66: 343},
+		{66: 335},
+		{66: 332},
+		// 15
+		{66: 329},
+		{66: 322},
+		{66: 316},
+		{66: 310},
+		{66: 307},
+		// 20
+		{66: 301},
+		{66: 218},
+		{66: 106},
+		{66: 105},
+		{66: 104},
+		// 25
+		{66: 103},
+		{66: 102},
+		{66: 101},
+		{66: 100},
+		{66: 99},
+		// 30
+		{66: 98},
+		{66: 97},
+		{66: 96},
+		{66: 95},
+		{66: 94},
+		// 35
+		{66: 93},
+		{66: 92},
+		{66: 91},
+		{66: 90},
+		{66: 89},
+		// 40
+		{66: 88},
+		{66: 87},
+		{66: 86},
+		{66: 85},
+		{66: 84},
+		// 45
+		{66: 83},
+		{66: 82},
+		{66: 81},
+		{66: 80},
+		{66: 79},
+		// 50
+		{66: 78},
+		{66: 73},
+		{66: 72},
+		{66: 71},
+		{66: 70},
+		// 55
+		{66: 69},
+		{66: 68},
+		{66: 67},
+		{66: 66},
+		{66: 65},
+		// 60
+		{53: 132, 132, 62: 220, 68: 219},
+		{53: 225, 224, 78: 223, 222, 92: 221},
+		{131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 131, 63: 131, 70: 131},
+		{298, 299},
+		{135, 135},
+		// 65
+		{74: 226},
+		{74: 62},
+		{74: 61},
+		{2: 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 220, 68: 228, 73: 227},
+		{1: 296, 63: 295},
+		// 70
+		{2: 258, 236, 238, 260, 240, 261, 259, 244, 262, 263, 264, 232, 233, 234, 235, 254, 265, 242, 246, 237, 239, 241, 248, 245, 243, 247, 249, 253, 251, 266, 257, 267, 268, 269, 256, 252, 255, 250, 270, 271, 272, 277, 278, 274, 273, 275, 276, 287, 288, 289, 290, 282, 281, 283, 279, 280, 284, 286, 285, 231, 69: 230, 72: 229},
+		{126, 126, 63: 126},
+		{132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 220, 132, 292, 68: 291},
+		{60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60, 60},
+		{59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59, 59},
+		// 75
+		{58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58, 58},
+		{57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57, 57},
+		{56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56, 56},
+		{55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55, 55},
+		{54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54, 54},
+		// 80
+		{53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53, 53},
+		{52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52, 52},
+		{51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51, 51},
+		{50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50, 50},
+		{49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49, 49},
+		// 85
+		{48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48, 48},
+		{47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47, 47},
+		{46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46, 46},
+		{45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45, 45},
+		{44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44, 44},
+		// 90
+		{43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43, 43},
+		{42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42, 42},
+		{41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41, 41},
+		{40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40, 40},
+		{39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39, 39},
+		// 95
+		{38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38, 38},
+		{37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37, 37},
+		{36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36, 36},
+		{35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35, 35},
+		{34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34, 34},
+		// 100
+		{33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33, 33},
+		{32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32, 32},
+		{31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31, 31},
+		{30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+		{29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29, 29},
+		// 105
+		{28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+		{27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27, 27},
+		{26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26, 26},
+		{25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25, 25},
+		{24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24, 24},
+		// 110
+		{23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23, 23},
+		{22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22, 22},
+		{21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21, 21},
+		{20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20, 20},
+		{19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19, 19},
+		// 115
+		{18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18, 18},
+		{17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17, 17},
+		{16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16, 16},
+		{15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15, 15},
+		{14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14, 14},
+		// 120
+		{13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13, 13},
+		{12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12, 12},
+		{11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11, 11},
+		{10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10, 10},
+		{9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9, 9},
+		// 125
+		{8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8, 8},
+		{7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7, 7},
+		{6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6, 6},
+		{5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5, 5},
+		{4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4, 4},
+		// 130
+		{3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3, 3},
+		{2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2, 2},
+		{1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1, 1},
+		{124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 124, 63: 124},
+		{2: 258, 236, 238, 260, 240, 261, 259, 244, 262, 263, 264, 232, 233, 234, 235, 254, 265, 242, 246, 237, 239, 241, 248, 245, 243, 247, 249, 253, 251, 266, 257, 267, 268, 269, 256, 252, 255, 250, 270, 271, 272, 277, 278, 274, 273, 275, 276, 287, 288, 289, 290, 282, 281, 283, 279, 280, 284, 286, 285, 231, 69: 293},
+		// 135
+		{132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 220, 132, 68: 294},
+		{123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 123, 63: 123},
+		{133, 133},
+		{2: 258, 236, 238, 260, 240, 261, 259, 244, 262, 263, 264, 232, 233, 234, 235, 254, 265, 242, 246, 237, 239, 241, 248, 245, 243, 247, 249, 253, 251, 266, 257, 267, 268, 269, 256, 252, 255, 250, 270, 271, 272, 277, 278, 274, 273, 275, 276, 287, 288, 289, 290, 282, 281, 283, 279, 280, 284, 286, 285, 231, 69: 230, 72: 297},
+		{125, 125, 63: 125},
+		// 140
+		{1: 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 136, 67: 136},
+		{53: 225, 224, 78: 223, 300},
+		{134, 134},
+		{56: 132, 132, 62: 220, 68: 302},
+		{56: 304, 305, 91: 303},
+		// 145
+		{306},
+		{64},
+		{63},
+		{1: 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 137, 67: 137},
+		{132, 62: 220, 68: 308},
+		// 150
+		{309},
+		{1: 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 138, 67: 138},
+		{55: 132, 58: 132, 62: 220, 68: 311},
+		{55: 314, 58: 313, 93: 312},
+		{315},
+		// 155
+		{108},
+		{107},
+		{1: 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 139, 67: 139},
+		{71: 317},
+		{1: 319, 71: 130, 75: 318},
+		// 160
+		{71: 320},
+		{129, 2: 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 129, 71: 129},
+		{321},
+		{1: 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 140, 67: 140},
+		{62: 220, 68: 323, 70: 132},
+		// 165
+		{70: 324},
+		{59: 327, 326, 100: 325},
+		{328},
+		{110},
+		{109},
+		// 170
+		{1: 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 141, 67: 141},
+		{2: 258, 236, 238, 260, 240, 261, 259, 244, 262, 263, 264, 232, 233, 234, 235, 254, 265, 242, 246, 237, 239, 241, 248, 245, 243, 247, 249, 253, 251, 266, 257, 267, 268, 269, 256, 252, 255, 250, 270, 271, 272, 277, 278, 274, 273, 275, 276, 287, 288, 289, 290, 282, 281, 283, 279, 280, 284, 286, 285, 231, 69: 330},
+		{331},
+		{1: 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 142, 67: 142},
+		{2: 258, 236, 238, 260, 240, 261, 259, 244, 262, 263, 264, 232, 233, 234, 235, 254, 265, 242, 246, 237, 239, 241, 248, 245, 243, 247, 249, 253, 251, 266, 257, 267, 268, 269, 256, 252, 255, 250, 270, 271, 272, 277, 278, 274, 273, 275, 276, 287, 288, 289, 290, 282, 281, 283, 279, 280, 284, 286, 285, 231, 69: 333},
+		// 175
+		{334},
+		{1: 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 143, 67: 143},
+		{2: 258, 236, 238, 260, 240, 261, 259, 244, 262, 263, 264, 232, 233, 234, 235, 254, 265, 242, 246, 237, 239, 241, 248, 245, 243, 247, 249, 253, 251, 266, 257, 267, 268, 269, 256, 252, 255, 250, 270, 271, 272, 277, 278, 274, 273, 275, 276, 287, 288, 289, 290, 282, 281, 283, 279, 280, 284, 286, 285, 231, 69: 336},
+		{65: 337},
+		{2: 258, 236, 238, 260, 240, 261, 259, 244, 262, 263, 264, 232, 233, 234, 235, 254, 265, 242, 246, 237, 239, 241, 248, 245, 243, 247, 249, 253, 251, 266, 257, 267, 268, 269, 256, 252, 255, 250, 270, 271, 272, 277, 278, 274, 273, 275, 276, 287, 288, 289, 290, 282, 281, 283, 279, 280, 284, 286, 285, 231, 69: 340, 341, 339, 101: 338},
+		// 180
+		{342},
+		{113},
+		{112},
+		{111},
+		{1: 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 144, 67: 144},
+		// 185
+		{62: 220, 68: 344, 70: 132},
+		{70: 345},
+		{346},
+		{1: 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 145, 67: 145},
+		{132, 49: 132, 132, 132, 132, 62: 220, 68: 348},
+		// 190
+		{117, 49: 352, 353, 354, 355, 85: 351, 98: 350, 349},
+		{358},
+		{116, 356},
+		{115, 115},
+		{77, 77},
+		// 195
+		{76, 76},
+		{75, 75},
+		{74, 74},
+		{49: 352, 353, 354, 355, 85: 357},
+		{114, 114},
+		// 200
+		{1: 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 146, 67: 146},
+		{2: 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 220, 68: 361, 77: 360},
+		{369},
+		{2: 258, 236, 238, 260, 240, 261, 259, 244, 262, 263, 264, 232, 233, 234, 235, 254, 265, 242, 246, 237, 239, 241, 248, 245, 243, 247, 249, 253, 251, 266, 257, 267, 268, 269, 256, 252, 255, 250, 270, 271, 272, 277, 278, 274, 273, 275, 276, 287, 288, 289, 290, 282, 281, 283, 279, 280, 284, 286, 285, 231, 69: 230, 72: 362},
+		{130, 319, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 130, 75: 363},
+		// 205
+		{121, 2: 258, 236, 238, 260, 240, 261, 259, 244, 262, 263, 264, 232, 233, 234, 235, 254, 265, 242, 246, 237, 239, 241, 248, 245, 243, 247, 249, 253, 251, 266, 257, 267, 268, 269, 256, 252, 255, 250, 270, 271, 272, 277, 278, 274, 273, 275, 276, 287, 288, 289, 290, 282, 281, 283, 279, 280, 284, 286, 285, 231, 69: 366, 94: 365, 364},
+		{122},
+		{120, 367},
+		{119, 119},
+		{2: 258, 236, 238, 260, 240, 261, 259, 244, 262, 263, 264, 232, 233, 234, 235, 254, 265, 242, 246, 237, 239, 241, 248, 245, 243, 247, 249, 253, 251, 266, 257, 267, 268, 269, 256, 252, 255, 250, 270, 271, 272, 277, 278, 274, 273, 275, 276, 287, 288, 289, 290, 282, 281, 283, 279, 280, 284, 286, 285, 231, 69: 368},
+		// 210
+		{118, 118},
+		{1: 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 147, 67: 147},
+		{2: 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 220, 68: 361, 77: 371},
+		{372},
+		{1: 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 148, 67: 148},
+		// 215
+		{132, 2: 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 220, 68: 376, 73: 375, 80: 374},
+		{377},
+		{128, 296},
+		{127, 2: 258, 236, 238, 260, 240, 261, 259, 244, 262, 263, 264, 232, 233, 234, 235, 254, 265, 242, 246, 237, 239, 241, 248, 245, 243, 247, 249, 253, 251, 266, 257, 267, 268, 269, 256, 252, 255, 250, 270, 271, 272, 277, 278, 274, 273, 275, 276, 287, 288, 289, 290, 282, 281, 283, 279, 280, 284, 286, 285, 231, 69: 230, 72: 229},
+		{1: 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 149, 67: 149},
+		// 220
+		{132, 2: 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 220, 68: 376, 73: 375, 80: 379},
+		{380},
+		{1: 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 150, 67: 150},
+		{2: 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 132, 220, 68: 228, 73: 382},
+		{383, 296},
+		// 225
+		{1: 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 151, 67: 151},
+		{132, 62: 220, 68: 385},
+		{386},
+		{1: 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 152, 67: 152},
+		{2: 214, 183, 185, 212, 196, 205, 217, 197, 192, 191, 195, 163, 180, 181, 182, 170, 174, 188, 198, 184, 186, 187, 200, 215, 189, 199, 201, 208, 203, 194, 173, 178, 216, 179, 172, 207, 171, 202, 190, 213, 193, 175, 210, 204, 206, 211, 209, 76: 176, 81: 164, 177, 389, 169, 86: 168, 166, 388, 167, 165},
+		// 230
+		{1: 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 155, 67: 155},
+		{1: 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 153, 67: 153},
+	}
+)
+
+var yyhintDebug = 0
+
+type yyhintLexer interface {
+	Lex(lval *yyhintSymType) int
+	Errorf(format string, a ...interface{}) error
+	AppendError(err error)
+	Errors() (warns []error, errs []error)
+}
+
+type yyhintLexerEx interface {
+	yyhintLexer
+	Reduced(rule, state int, lval *yyhintSymType) bool
+}
+
+func yyhintSymName(c int) (s string) {
+	x, ok := yyhintXLAT[c]
+	if ok {
+		return yyhintSymNames[x]
+	}
+
+	return __yyfmt__.Sprintf("%d", c)
+}
+
+func yyhintlex1(yylex yyhintLexer, lval *yyhintSymType) (n int) {
+	n = yylex.Lex(lval)
+	if n <= 0 {
+		n = yyhintEOFCode
+	}
+	if yyhintDebug >= 3 {
+		__yyfmt__.Printf("\nlex %s(%#x %d), lval: %+v\n", yyhintSymName(n), n, n, lval)
+	}
+	return n
+}
+
+func yyhintParse(yylex yyhintLexer, parser *hintParser) int {
+	const yyError = 103
+
+	yyEx, _ := yylex.(yyhintLexerEx)
+	var yyn int
+	parser.yylval = yyhintSymType{}
+	yyS := parser.cache
+
+	Nerrs := 0   /* number of errors */
+	Errflag := 0 /* error recovery flag */
+	yyerrok := func() {
+		if yyhintDebug >= 2 {
+			__yyfmt__.Printf("yyerrok()\n")
+		}
+		Errflag = 0
+	}
+	_ = yyerrok
+	yystate := 0
+	yychar := -1
+	var yyxchar int
+	var yyshift int
+	yyp := -1
+	goto yystack
+
+ret0:
+	return 0
+
+ret1:
+	return 1
+
+yystack:
+	/* put a state and value onto the stack */
+	yyp++
+	if yyp+1 >= len(yyS) {
+		nyys := make([]yyhintSymType, len(yyS)*2)
+		copy(nyys, yyS)
+		yyS = nyys
+		parser.cache = yyS
+	}
+	parser.yyVAL = &yyS[yyp+1]
+	yyS[yyp].yys = yystate
+
+yynewstate:
+	if yychar < 0 {
+		yychar = yyhintlex1(yylex, &parser.yylval)
+		var ok bool
+		if yyxchar, ok = yyhintXLAT[yychar]; !ok {
+			yyxchar = len(yyhintSymNames) // > tab width
+		}
+	}
+	if yyhintDebug >= 4 {
+		var a []int
+		for _, v := range yyS[:yyp+1] {
+			a = append(a, v.yys)
+		}
+		__yyfmt__.Printf("state stack %v\n", a)
+	}
+	row := yyhintParseTab[yystate]
+	yyn = 0
+	if yyxchar < len(row) {
+		if yyn = int(row[yyxchar]); yyn != 0 {
+			yyn += yyhintTabOfs
+		}
+	}
+	switch {
+	case yyn > 0: // shift
+		yychar = -1
+		*parser.yyVAL = parser.yylval
+		yystate = yyn
+		yyshift = yyn
+		if yyhintDebug >= 2 {
+			__yyfmt__.Printf("shift, and goto state %d\n", yystate)
+		}
+		if Errflag > 0 {
+			Errflag--
+		}
+		goto yystack
+	case yyn < 0: // reduce
+	case yystate == 1: // accept
+		if yyhintDebug >= 2 {
+			__yyfmt__.Println("accept")
+		}
+		goto ret0
+	}
+
+	if yyn == 0 {
+		/* error ... attempt to resume parsing */
+		switch Errflag {
+		case 0: /* brand new error */
+			if yyhintDebug >= 1 {
+				__yyfmt__.Printf("no action for %s in state %d\n", yyhintSymName(yychar), yystate)
+			}
+			msg, ok := yyhintXErrors[yyhintXError{yystate, yyxchar}]
+			if !ok {
+				msg, ok = yyhintXErrors[yyhintXError{yystate, -1}]
+			}
+			if !ok && yyshift != 0 {
+				msg, ok = yyhintXErrors[yyhintXError{yyshift, yyxchar}]
+			}
+			if !ok {
+				msg, ok = yyhintXErrors[yyhintXError{yyshift, -1}]
+			}
+			if !ok || msg == "" {
+				msg = "syntax error"
+			}
+			// ignore goyacc error message
+			yylex.AppendError(yylex.Errorf(""))
+			Nerrs++
+			fallthrough
+
+		case 1, 2: /* incompletely recovered error ... try again */
+			Errflag = 3
+
+			/* find a state where "error" is a legal shift action */
+			for yyp >= 0 {
+				row := yyhintParseTab[yyS[yyp].yys]
+				if yyError < len(row) {
+					yyn = int(row[yyError]) + yyhintTabOfs
+					if yyn > 0 { // hit
+						if yyhintDebug >= 2 {
+							__yyfmt__.Printf("error recovery found error shift in state %d\n", yyS[yyp].yys)
+						}
+						yystate = yyn /* simulate a shift of "error" */
+						goto yystack
+					}
+				}
+
+				/* the current p has no shift on "error", pop stack */
+				if yyhintDebug >= 2 {
+					__yyfmt__.Printf("error recovery pops state %d\n", yyS[yyp].yys)
+				}
+				yyp--
+			}
+			/* there is no state on the stack with an error shift ... abort */
+			if yyhintDebug >= 2 {
+				__yyfmt__.Printf("error recovery failed\n")
+			}
+			goto ret1
+
+		case 3: /* no shift yet; clobber input char */
+			if yyhintDebug >= 2 {
+				__yyfmt__.Printf("error recovery discards %s\n", yyhintSymName(yychar))
+			}
+			if yychar == yyhintEOFCode {
+				goto ret1
+			}
+
+			yychar = -1
+			goto yynewstate /* try again in the same state */
+		}
+	}
+
+	r := -yyn
+	x0 := yyhintReductions[r]
+	x, n := x0.xsym, x0.components
+	yypt := yyp
+	_ = yypt // guard against "declared and not used"
+
+	yyp -= n
+	if yyp+1 >= len(yyS) {
+		nyys := make([]yyhintSymType, len(yyS)*2)
+		copy(nyys, yyS)
+		yyS = nyys
+		parser.cache = yyS
+	}
+	parser.yyVAL = &yyS[yyp+1]
+
+	/* consult goto table to find next state */
+	exState := yystate
+	yystate = int(yyhintParseTab[yyS[yyp].yys][x]) + yyhintTabOfs
+	/* reduction by production r */
+	if yyhintDebug >= 2 {
+		__yyfmt__.Printf("reduce using rule %v (%s), and goto state %d\n", r, yyhintSymNames[x], yystate)
+	}
+
+	switch r {
+	case 1:
+		{
+			parser.result = yyS[yypt-0].hints
+		}
+	case 2:
+		{
+			if yyS[yypt-0].hint != nil {
+				parser.yyVAL.hints = []*ast.TableOptimizerHint{yyS[yypt-0].hint}
+			}
+		}
+	case 3:
+		{
+			if yyS[yypt-0].hint != nil {
+				parser.yyVAL.hints = append(yyS[yypt-2].hints, yyS[yypt-0].hint)
+			} else {
+				parser.yyVAL.hints = yyS[yypt-2].hints
+			}
+		}
+	case 4:
+		{
+			parser.yyVAL.hints = yyS[yypt-0].hints
+		}
+	case 5:
+		{
+			parser.yyVAL.hints = append(yyS[yypt-2].hints, yyS[yypt-0].hints...)
+		}
+	case 6:
+		{
+			parser.warnUnsupportedHint(yyS[yypt-3].ident)
+			parser.yyVAL.hint = nil
+		}
+	case 7:
+		{
+			parser.warnUnsupportedHint(yyS[yypt-3].ident)
+			parser.yyVAL.hint = nil
+		}
+	case 8:
+		{
+			parser.warnUnsupportedHint(yyS[yypt-3].ident)
+			parser.yyVAL.hint = nil
+		}
+	case 9:
+		{
+			h := yyS[yypt-1].hint
+			h.HintName = model.NewCIStr(yyS[yypt-3].ident)
+			parser.yyVAL.hint = h
+		}
+	case 10:
+		{
+			parser.warnUnsupportedHint(yyS[yypt-3].ident)
+			parser.yyVAL.hint = nil
+		}
+	case 11:
+		{
+			h := yyS[yypt-1].hint
+			h.HintName = model.NewCIStr(yyS[yypt-3].ident)
+			parser.yyVAL.hint = h
+		}
+	case 12:
+		{
+			parser.warnUnsupportedHint(yyS[yypt-4].ident)
+			parser.yyVAL.hint = nil
+		}
+	case 13:
+		{
+			parser.yyVAL.hint = &ast.TableOptimizerHint{
+				HintName: model.NewCIStr(yyS[yypt-4].ident),
+				QBName:   model.NewCIStr(yyS[yypt-2].ident),
+				HintData: yyS[yypt-1].number,
+			}
+		}
+	case 14:
+		{
+			parser.warnUnsupportedHint(yyS[yypt-5].ident)
+			parser.yyVAL.hint = nil
+		}
+	case 15:
+		{
+			parser.warnUnsupportedHint(yyS[yypt-3].ident)
+			parser.yyVAL.hint = nil
+		}
+	case 16:
+		{
+			parser.yyVAL.hint = &ast.TableOptimizerHint{
+				HintName: model.NewCIStr(yyS[yypt-3].ident),
+				QBName:   model.NewCIStr(yyS[yypt-1].ident),
+			}
+		}
+	case 17:
+		{
+			maxValue := uint64(math.MaxInt64) / yyS[yypt-1].number
+			if yyS[yypt-2].number <= maxValue {
+				parser.yyVAL.hint = &ast.TableOptimizerHint{
+					HintName: model.NewCIStr(yyS[yypt-5].ident),
+					HintData: int64(yyS[yypt-2].number * yyS[yypt-1].number),
+					QBName:   model.NewCIStr(yyS[yypt-3].ident),
+				}
+			} else {
+				yylex.AppendError(ErrWarnMemoryQuotaOverflow.GenWithStackByArgs(math.MaxInt64))
+				parser.lastErrorAsWarn()
+				parser.yyVAL.hint = nil
+			}
+		}
+	case 18:
+		{
+			parser.yyVAL.hint = &ast.TableOptimizerHint{
+				HintName: model.NewCIStr(yyS[yypt-5].ident),
+				HintData: ast.HintTimeRange{
+					From: yyS[yypt-3].ident,
+					To:   yyS[yypt-1].ident,
+				},
+			}
+		}
+	case 19:
+		{
+			h := yyS[yypt-1].hint
+			h.HintName = model.NewCIStr(yyS[yypt-4].ident)
+			h.QBName = model.NewCIStr(yyS[yypt-2].ident)
+			parser.yyVAL.hint = h
+		}
+	case 20:
+		{
+			parser.yyVAL.hint = &ast.TableOptimizerHint{
+				HintName: model.NewCIStr(yyS[yypt-3].ident),
+				QBName:   model.NewCIStr(yyS[yypt-1].ident),
+			}
+		}
+	case 21:
+		{
+			parser.yyVAL.hint = &ast.TableOptimizerHint{
+				HintName: model.NewCIStr(yyS[yypt-4].ident),
+				QBName:   model.NewCIStr(yyS[yypt-2].ident),
+				HintData: model.NewCIStr(yyS[yypt-1].ident),
+			}
+		}
+	case 22:
+		{
+			hs := yyS[yypt-1].hints
+			name := model.NewCIStr(yyS[yypt-4].ident)
+			qb := model.NewCIStr(yyS[yypt-2].ident)
+			for _, h := range hs {
+				h.HintName = name
+				h.QBName = qb
+			}
+			parser.yyVAL.hints = hs
+		}
+	case 23:
+		{
+			parser.yyVAL.hints = []*ast.TableOptimizerHint{yyS[yypt-0].hint}
+		}
+	case 24:
+		{
+			parser.yyVAL.hints = append(yyS[yypt-2].hints, yyS[yypt-0].hint)
+		}
+	case 25:
+		{
+			h := yyS[yypt-1].hint
+			h.HintData = model.NewCIStr(yyS[yypt-3].ident)
+			parser.yyVAL.hint = h
+		}
+	case 26:
+		{
+			parser.yyVAL.ident = ""
+		}
+	case 31:
+		{
+			parser.yyVAL.hint = &ast.TableOptimizerHint{
+				QBName: model.NewCIStr(yyS[yypt-0].ident),
+			}
+		}
+	case 32:
+		{
+			parser.yyVAL.hint = &ast.TableOptimizerHint{
+				Tables: []ast.HintTable{yyS[yypt-0].table},
+				QBName: model.NewCIStr(yyS[yypt-1].ident),
+			}
+		}
+	case 33:
+		{
+			h := yyS[yypt-2].hint
+			h.Tables = append(h.Tables, yyS[yypt-0].table)
+			parser.yyVAL.hint = h
+		}
+	case 34:
+		{
+			parser.yyVAL.table = ast.HintTable{
+				TableName: model.NewCIStr(yyS[yypt-1].ident),
+				QBName:    model.NewCIStr(yyS[yypt-0].ident),
+			}
+		}
+	case 35:
+		{
+			parser.yyVAL.table = ast.HintTable{
+				DBName:    model.NewCIStr(yyS[yypt-3].ident),
+				TableName: model.NewCIStr(yyS[yypt-1].ident),
+				QBName:    model.NewCIStr(yyS[yypt-0].ident),
+			}
+		}
+	case 36:
+		{
+			h := yyS[yypt-0].hint
+			h.Tables = []ast.HintTable{yyS[yypt-2].table}
+			h.QBName = model.NewCIStr(yyS[yypt-3].ident)
+			parser.yyVAL.hint = h
+		}
+	case 37:
+		{
+			parser.yyVAL.hint = &ast.TableOptimizerHint{}
+		}
+	case 39:
+		{
+			parser.yyVAL.hint = &ast.TableOptimizerHint{
+				Indexes: []model.CIStr{model.NewCIStr(yyS[yypt-0].ident)},
+			}
+		}
+	case 40:
+		{
+			h := yyS[yypt-2].hint
+			h.Indexes = append(h.Indexes, model.NewCIStr(yyS[yypt-0].ident))
+			parser.yyVAL.hint = h
+		}
+	case 47:
+		{
+			parser.yyVAL.ident = strconv.FormatUint(yyS[yypt-0].number, 10)
+		}
+	case 48:
+		{
+			parser.yyVAL.number = 1024 * 1024
+		}
+	case 49:
+		{
+			parser.yyVAL.number = 1024 * 1024 * 1024
+		}
+	case 50:
+		{
+			parser.yyVAL.hint = &ast.TableOptimizerHint{HintData: true}
+		}
+	case 51:
+		{
+			parser.yyVAL.hint = &ast.TableOptimizerHint{HintData: false}
+		}
+
+	}
+
+	if yyEx != nil && yyEx.Reduced(r, exState, parser.yyVAL) {
+		return -1
+	}
+	goto yystack /* stack new state and value */
+}
@@ -0,0 +1,97 @@
+package types
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+
+	"grant-db/sessionctx/stmtctx"
+)
+
+// BinaryLiteral is the internal type for storing bit / hex literal type.
+type BinaryLiteral []byte
+
+// ZeroBinaryLiteral is a BinaryLiteral literal with zero value.
+var ZeroBinaryLiteral = BinaryLiteral{}
+
+func trimLeadingZeroBytes(bytes []byte) []byte {
+	if len(bytes) == 0 {
+		return bytes
+	}
+	pos, posMax := 0, len(bytes)-1
+	for ; pos < posMax; pos++ {
+		if bytes[pos] != 0 {
+			break
+		}
+	}
+	return bytes[pos:]
+}
+
+// NewBinaryLiteralFromUint creates a new BinaryLiteral instance by the given uint value in BitEndian.
+// byteSize will be used as the length of the new BinaryLiteral, with leading bytes filled to zero.
+// If byteSize is -1, the leading zeros in new BinaryLiteral will be trimmed.
+func NewBinaryLiteralFromUint(value uint64, byteSize int) BinaryLiteral {
+	if byteSize != -1 && (byteSize < 1 || byteSize > 8) {
+		panic("Invalid byteSize")
+	}
+	buf := make([]byte, 8)
+	binary.BigEndian.PutUint64(buf, value)
+	if byteSize == -1 {
+		buf = trimLeadingZeroBytes(buf)
+	} else {
+		buf = buf[8-byteSize:]
+	}
+	return buf
+}
+
+// String implements fmt.Stringer interface, the value is formatted as a hex literal like 0x0a.
+func (b BinaryLiteral) String() string {
+	if len(b) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("0x%x", []byte(b))
+}
+
+// ToString returns the string representation for the literal.
+func (b BinaryLiteral) ToString() string {
+	return string(b)
+}
+
+// ToBitLiteralString returns the bit literal representation for the literal, like b'1010'.
+func (b BinaryLiteral) ToBitLiteralString(trimLeadingZero bool) string {
+	if len(b) == 0 {
+		return "b''"
+	}
+	var buf strings.Builder
+	for _, data := range b {
+		fmt.Fprintf(&buf, "%08b", data)
+	}
+	ret := buf.String()
+	if trimLeadingZero {
+		ret = strings.TrimLeft(ret, "0")
+		if len(ret) == 0 {
+			ret = "0"
+		}
+	}
+	return "b'" + ret + "'"
+}
+
+// ToInt returns the int value for the literal, a literal longer than 8 bytes overflows.
+func (b BinaryLiteral) ToInt(sc *stmtctx.StatementContext) (uint64, error) {
+	buf := trimLeadingZeroBytes(b)
+	length := len(buf)
+	if length == 0 {
+		return 0, nil
+	}
+	if length > 8 {
+		err := ErrDataOutOfRange.GenWithStackByArgs("BINARY", b)
+		return math.MaxUint64, sc.HandleOverflow(err, err)
+	}
+	// Note: the byte-order is BigEndian.
+	val := uint64(buf[0])
+	for i := 1; i < length; i++ {
+		val = (val << 8) | uint64(buf[i])
+	}
+	return val, nil
+}
@@ -0,0 +1,307 @@
+package types
+
+import (
+	"math"
+	"strings"
+
+	"grant-db/sessionctx/stmtctx"
+)
+
+// CompareDatum compares datum to another datum, the values of different kinds are compared as numbers or
+// strings like MySQL does, strings are compared by bytes.
+// TODO: return error properly.
+func (d *Datum) CompareDatum(sc *stmtctx.StatementContext, ad *Datum) (int, error) {
+	if d.k == KindMysqlJSON && ad.k != KindMysqlJSON {
+		cmp, err := ad.CompareDatum(sc, d)
+		return -cmp, err
+	}
+	switch ad.k {
+	case KindNull:
+		if d.k == KindNull {
+			return 0, nil
+		}
+		return 1, nil
+	case KindMinNotNull:
+		if d.k == KindNull {
+			return -1, nil
+		} else if d.k == KindMinNotNull {
+			return 0, nil
+		}
+		return 1, nil
+	case KindMaxValue:
+		if d.k == KindMaxValue {
+			return 0, nil
+		}
+		return -1, nil
+	case KindInt64:
+		return d.compareInt64(sc, ad.GetInt64())
+	case KindUint64:
+		return d.compareUint64(sc, ad.GetUint64())
+	case KindFloat64:
+		return d.compareFloat64(sc, ad.GetFloat64())
+	case KindString:
+		return d.compareString(sc, ad.GetString())
+	case KindBytes:
+		return d.compareString(sc, string(ad.GetBytes()))
+	case KindMysqlDecimal:
+		return d.compareMysqlDecimal(sc, ad.GetMysqlDecimal())
+	case KindMysqlDuration:
+		return d.compareMysqlDuration(sc, ad.GetMysqlDuration())
+	case KindMysqlEnum:
+		return d.compareMysqlEnum(sc, ad.GetMysqlEnum())
+	case KindMysqlSet:
+		return d.compareMysqlSet(sc, ad.GetMysqlSet())
+	case KindBinaryLiteral, KindMysqlBit:
+		return d.compareBinaryLiteral(sc, ad.GetBinaryLiteral())
+	case KindMysqlTime:
+		return d.compareMysqlTime(sc, ad.GetMysqlTime())
+	case KindMysqlJSON:
+		return d.compareString(sc, ad.GetMysqlJSON().String())
+	}
+	return 0, nil
+}
+
+// compareNullOrBound compares the datum of the special kinds to a normal value, ok is false for the other kinds.
+func (d *Datum) compareNullOrBound() (cmp int, ok bool) {
+	switch d.k {
+	case KindNull, KindMinNotNull:
+		return -1, true
+	case KindMaxValue:
+		return 1, true
+	}
+	return 0, false
+}
+
+func (d *Datum) compareInt64(sc *stmtctx.StatementContext, i int64) (int, error) {
+	if cmp, ok := d.compareNullOrBound(); ok {
+		return cmp, nil
+	}
+	switch d.k {
+	case KindInt64:
+		return compareInt64(d.GetInt64(), i), nil
+	case KindUint64:
+		if i < 0 || d.GetUint64() > math.MaxInt64 {
+			return 1, nil
+		}
+		return compareInt64(int64(d.GetUint64()), i), nil
+	}
+	return d.compareFloat64(sc, float64(i))
+}
+
+func (d *Datum) compareUint64(sc *stmtctx.StatementContext, u uint64) (int, error) {
+	if cmp, ok := d.compareNullOrBound(); ok {
+		return cmp, nil
+	}
+	switch d.k {
+	case KindInt64:
+		if d.GetInt64() < 0 || u > math.MaxInt64 {
+			return -1, nil
+		}
+		return compareInt64(d.GetInt64(), int64(u)), nil
+	case KindUint64:
+		return compareUint64(d.GetUint64(), u), nil
+	}
+	return d.compareFloat64(sc, float64(u))
+}
+
+func (d *Datum) compareFloat64(sc *stmtctx.StatementContext, f float64) (int, error) {
+	if cmp, ok := d.compareNullOrBound(); ok {
+		return cmp, nil
+	}
+	switch d.k {
+	case KindInt64:
+		return compareFloat64(float64(d.GetInt64()), f), nil
+	case KindUint64:
+		return compareFloat64(float64(d.GetUint64()), f), nil
+	case KindFloat64:
+		return compareFloat64(d.GetFloat64(), f), nil
+	case KindString, KindBytes:
+		fVal, err := StrToFloat(sc, d.GetString())
+		return compareFloat64(fVal, f), err
+	case KindMysqlDecimal:
+		fVal, err := d.GetMysqlDecimal().ToFloat64()
+		return compareFloat64(fVal, f), err
+	case KindMysqlDuration:
+		fVal, err := d.GetMysqlDuration().ToNumber().ToFloat64()
+		return compareFloat64(fVal, f), err
+	case KindMysqlEnum:
+		return compareFloat64(d.GetMysqlEnum().ToNumber(), f), nil
+	case KindMysqlSet:
+		return compareFloat64(d.GetMysqlSet().ToNumber(), f), nil
+	case KindBinaryLiteral, KindMysqlBit:
+		val, err := d.GetBinaryLiteral().ToInt(sc)
+		return compareFloat64(float64(val), f), err
+	case KindMysqlTime:
+		fVal, err := d.GetMysqlTime().ToNumber().ToFloat64()
+		return compareFloat64(fVal, f), err
+	}
+	return -1, nil
+}
+
+func (d *Datum) compareString(sc *stmtctx.StatementContext, s string) (int, error) {
+	if cmp, ok := d.compareNullOrBound(); ok {
+		return cmp, nil
+	}
+	switch d.k {
+	case KindString, KindBytes:
+		return strings.Compare(d.GetString(), s), nil
+	case KindMysqlDecimal:
+		dec, err := StrToDecimal(sc, s)
+		return d.GetMysqlDecimal().Compare(dec), err
+	case KindMysqlTime:
+		t, err := ParseTime(s, d.GetMysqlTime().Type(), MaxFsp)
+		if err != nil {
+			return 0, err
+		}
+		return d.GetMysqlTime().Compare(t), nil
+	case KindMysqlDuration:
+		dur, err := ParseDuration(s, MaxFsp)
+		if err != nil {
+			return 0, err
+		}
+		return d.GetMysqlDuration().Compare(dur), nil
+	case KindMysqlEnum:
+		return strings.Compare(d.GetMysqlEnum().String(), s), nil
+	case KindMysqlSet:
+		return strings.Compare(d.GetMysqlSet().String(), s), nil
+	case KindBinaryLiteral, KindMysqlBit:
+		return strings.Compare(d.GetBinaryLiteral().ToString(), s), nil
+	case KindMysqlJSON:
+		return strings.Compare(d.GetMysqlJSON().String(), s), nil
+	}
+	fVal, err := StrToFloat(sc, s)
+	if err != nil {
+		return 0, err
+	}
+	return d.compareFloat64(sc, fVal)
+}
+
+func (d *Datum) compareMysqlDecimal(sc *stmtctx.StatementContext, dec *MyDecimal) (int, error) {
+	if cmp, ok := d.compareNullOrBound(); ok {
+		return cmp, nil
+	}
+	switch d.k {
+	case KindMysqlDecimal:
+		return d.GetMysqlDecimal().Compare(dec), nil
+	case KindString, KindBytes:
+		dDec, err := StrToDecimal(sc, d.GetString())
+		return dDec.Compare(dec), err
+	case KindInt64:
+		return NewDecFromInt(d.GetInt64()).Compare(dec), nil
+	case KindUint64:
+		return NewDecFromUint(d.GetUint64()).Compare(dec), nil
+	}
+	fVal, err := dec.ToFloat64()
+	if err != nil {
+		return 0, err
+	}
+	return d.compareFloat64(sc, fVal)
+}
+
+func (d *Datum) compareMysqlDuration(sc *stmtctx.StatementContext, dur Duration) (int, error) {
+	if cmp, ok := d.compareNullOrBound(); ok {
+		return cmp, nil
+	}
+	switch d.k {
+	case KindMysqlDuration:
+		return d.GetMysqlDuration().Compare(dur), nil
+	case KindString, KindBytes:
+		dDur, err := ParseDuration(d.GetString(), MaxFsp)
+		if err != nil {
+			return 0, err
+		}
+		return dDur.Compare(dur), nil
+	}
+	fVal, err := dur.ToNumber().ToFloat64()
+	if err != nil {
+		return 0, err
+	}
+	return d.compareFloat64(sc, fVal)
+}
+
+func (d *Datum) compareMysqlEnum(sc *stmtctx.StatementContext, enum Enum) (int, error) {
+	if cmp, ok := d.compareNullOrBound(); ok {
+		return cmp, nil
+	}
+	switch d.k {
+	case KindString, KindBytes:
+		return strings.Compare(d.GetString(), enum.String()), nil
+	}
+	return d.compareFloat64(sc, enum.ToNumber())
+}
+
+func (d *Datum) compareMysqlSet(sc *stmtctx.StatementContext, set Set) (int, error) {
+	if cmp, ok := d.compareNullOrBound(); ok {
+		return cmp, nil
+	}
+	switch d.k {
+	case KindString, KindBytes:
+		return strings.Compare(d.GetString(), set.String()), nil
+	}
+	return d.compareFloat64(sc, set.ToNumber())
+}
+
+func (d *Datum) compareBinaryLiteral(sc *stmtctx.StatementContext, b BinaryLiteral) (int, error) {
+	if cmp, ok := d.compareNullOrBound(); ok {
+		return cmp, nil
+	}
+	switch d.k {
+	case KindString, KindBytes:
+		return strings.Compare(d.GetString(), b.ToString()), nil
+	case KindBinaryLiteral, KindMysqlBit:
+		return strings.Compare(d.GetBinaryLiteral().ToString(), b.ToString()), nil
+	}
+	val, err := b.ToInt(sc)
+	if err != nil {
+		return 0, err
+	}
+	return d.compareFloat64(sc, float64(val))
+}
+
+func (d *Datum) compareMysqlTime(sc *stmtctx.StatementContext, t Time) (int, error) {
+	if cmp, ok := d.compareNullOrBound(); ok {
+		return cmp, nil
+	}
+	switch d.k {
+	case KindMysqlTime:
+		return d.GetMysqlTime().Compare(t), nil
+	case KindString, KindBytes:
+		dt, err := ParseTime(d.GetString(), t.Type(), MaxFsp)
+		if err != nil {
+			return 0, err
+		}
+		return dt.Compare(t), nil
+	}
+	fVal, err := t.ToNumber().ToFloat64()
+	if err != nil {
+		return 0, err
+	}
+	return d.compareFloat64(sc, fVal)
+}
+
+func compareInt64(x, y int64) int {
+	if x < y {
+		return -1
+	} else if x == y {
+		return 0
+	}
+	return 1
+}
+
+func compareUint64(x, y uint64) int {
+	if x < y {
+		return -1
+	} else if x == y {
+		return 0
+	}
+	return 1
+}
+
+func compareFloat64(x, y float64) int {
+	if x < y {
+		return -1
+	} else if x == y {
+		return 0
+	}
+	return 1
+}
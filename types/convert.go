@@ -0,0 +1,428 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pingcap/parser/charset"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/sessionctx/stmtctx"
+	"grant-db/types/json"
+)
+
+// IntergerUnsignedUpperBound indicates the max uint64 values of different mysql types.
+func IntergerUnsignedUpperBound(intType byte) uint64 {
+	switch intType {
+	case mysql.TypeTiny:
+		return math.MaxUint8
+	case mysql.TypeShort:
+		return math.MaxUint16
+	case mysql.TypeInt24:
+		return 1<<24 - 1
+	case mysql.TypeLong:
+		return math.MaxUint32
+	default:
+		return math.MaxUint64
+	}
+}
+
+// IntergerSignedUpperBound indicates the max int64 values of different mysql types.
+func IntergerSignedUpperBound(intType byte) int64 {
+	switch intType {
+	case mysql.TypeTiny:
+		return math.MaxInt8
+	case mysql.TypeShort:
+		return math.MaxInt16
+	case mysql.TypeInt24:
+		return 1<<23 - 1
+	case mysql.TypeLong:
+		return math.MaxInt32
+	default:
+		return math.MaxInt64
+	}
+}
+
+// IntergerSignedLowerBound indicates the min int64 values of different mysql types.
+func IntergerSignedLowerBound(intType byte) int64 {
+	switch intType {
+	case mysql.TypeTiny:
+		return math.MinInt8
+	case mysql.TypeShort:
+		return math.MinInt16
+	case mysql.TypeInt24:
+		return -1 << 23
+	case mysql.TypeLong:
+		return math.MinInt32
+	default:
+		return math.MinInt64
+	}
+}
+
+// ConvertFloatToInt converts a float64 value to an int value, the value is rounded and clipped into [lowerBound, upperBound].
+func ConvertFloatToInt(fval float64, lowerBound, upperBound int64, tp byte) (int64, error) {
+	val := RoundFloat(fval)
+	if val < float64(lowerBound) {
+		return lowerBound, ErrOverflow
+	}
+	if val >= float64(upperBound) {
+		if val == float64(upperBound) {
+			return upperBound, nil
+		}
+		return upperBound, ErrOverflow
+	}
+	return int64(val), nil
+}
+
+// ConvertFloatToUint converts a float value to an uint value, the value is rounded and clipped into [0, upperBound].
+func ConvertFloatToUint(fval float64, upperBound uint64, tp byte) (uint64, error) {
+	val := RoundFloat(fval)
+	if val < 0 {
+		return 0, ErrOverflow
+	}
+	if val >= float64(upperBound) {
+		if val == float64(upperBound) {
+			return upperBound, nil
+		}
+		return upperBound, ErrOverflow
+	}
+	return uint64(val), nil
+}
+
+// RoundFloat rounds float val to the nearest integer value with float64 format, like MySQL Round function.
+// Half-way cases are rounded away from zero.
+func RoundFloat(f float64) float64 {
+	return math.Round(f)
+}
+
+// StrToInt converts a string to an integer at the best-effort.
+func StrToInt(sc *stmtctx.StatementContext, str string) (int64, error) {
+	str = strings.TrimSpace(str)
+	validPrefix, err := getValidIntPrefix(sc, str)
+	iVal, err1 := strconv.ParseInt(validPrefix, 10, 64)
+	if err1 != nil {
+		if numErr, ok := err1.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange {
+			return iVal, ErrOverflow
+		}
+		return iVal, ErrTruncatedWrongVal.GenWithStackByArgs("INTEGER", str)
+	}
+	return iVal, err
+}
+
+// StrToUint converts a string to an unsigned integer at the best-effort.
+func StrToUint(sc *stmtctx.StatementContext, str string) (uint64, error) {
+	str = strings.TrimSpace(str)
+	validPrefix, err := getValidIntPrefix(sc, str)
+	if validPrefix[0] == '+' {
+		validPrefix = validPrefix[1:]
+	}
+	if validPrefix[0] == '-' {
+		if validPrefix == "-0" {
+			return 0, err
+		}
+		return 0, ErrOverflow
+	}
+	uVal, err1 := strconv.ParseUint(validPrefix, 10, 64)
+	if err1 != nil {
+		if numErr, ok := err1.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange {
+			return uVal, ErrOverflow
+		}
+		return uVal, ErrTruncatedWrongVal.GenWithStackByArgs("INTEGER", str)
+	}
+	return uVal, err
+}
+
+// getValidIntPrefix gets the valid integer prefix of str, a float string is rounded to an integer string.
+func getValidIntPrefix(sc *stmtctx.StatementContext, str string) (string, error) {
+	floatPrefix, err := getValidFloatPrefix(sc, str)
+	if err != nil {
+		return floatPrefix, err
+	}
+	return floatStrToIntStr(floatPrefix)
+}
+
+// floatStrToIntStr converts a valid float string into a valid integer string which can be parsed by
+// strconv.ParseInt, the fraction part is rounded half away from zero.
+func floatStrToIntStr(validFloat string) (string, error) {
+	dec := new(MyDecimal)
+	// A huge value overflows below, a tiny one is rounded to zero.
+	if err := dec.FromString([]byte(validFloat)); err != nil && err != ErrOverflow && err != ErrTruncated {
+		return "0", err
+	}
+	// The exponent of a huge float makes the integer string too long, it overflows anyway.
+	if neg, digits, exp := dec.Scientific(); exp > 21 && len(digits) > 0 {
+		if neg {
+			return "-" + strings.Repeat("9", 20), nil
+		}
+		return strings.Repeat("9", 21), nil
+	}
+	dec.Rescale(0)
+	return dec.String(), nil
+}
+
+// StrToFloat converts a string to a float64 at the best-effort.
+func StrToFloat(sc *stmtctx.StatementContext, str string) (float64, error) {
+	str = strings.TrimSpace(str)
+	validStr, err := getValidFloatPrefix(sc, str)
+	f, err1 := strconv.ParseFloat(validStr, 64)
+	if err1 != nil {
+		if numErr, ok := err1.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange {
+			return f, ErrOverflow
+		}
+		return 0, err1
+	}
+	return f, err
+}
+
+// getValidFloatPrefix gets the longest prefix of s which is a valid float, a truncation is
+// handled by the statement context.
+func getValidFloatPrefix(sc *stmtctx.StatementContext, s string) (valid string, err error) {
+	var (
+		sawDot   bool
+		sawDigit bool
+		validLen int
+		eIdx     = -1
+	)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '+' || c == '-' {
+			if i != 0 && i != eIdx+1 { // "1e+1" is valid.
+				break
+			}
+		} else if c == '.' {
+			if sawDot || eIdx > 0 { // "1.1." or "1e1.1"
+				break
+			}
+			sawDot = true
+			if sawDigit { // "123." is valid.
+				validLen = i + 1
+			}
+		} else if c == 'e' || c == 'E' {
+			if !sawDigit { // "+.e"
+				break
+			}
+			if eIdx != -1 { // "1e5e"
+				break
+			}
+			eIdx = i
+		} else if c < '0' || c > '9' {
+			break
+		} else {
+			sawDigit = true
+			validLen = i + 1
+		}
+	}
+	valid = s[:validLen]
+	if valid == "" {
+		valid = "0"
+	}
+	if validLen == 0 || validLen != len(s) {
+		err = sc.HandleTruncate(ErrTruncatedWrongVal.GenWithStackByArgs("DOUBLE", s))
+	}
+	return valid, err
+}
+
+// ToNumber returns a formatted number, like 20101010 for a date and 20101010101010.123 for a datetime.
+func (t Time) ToNumber() *MyDecimal {
+	var s string
+	if t.tp == mysql.TypeDate {
+		s = strconv.Itoa(t.Year()*10000 + t.Month()*100 + t.Day())
+	} else {
+		s = strconv.FormatInt(int64(t.Year()*10000+t.Month()*100+t.Day())*1000000+int64(t.Hour()*10000+t.Minute()*100+t.Second()), 10)
+		if t.fsp > 0 {
+			frac := strconv.Itoa(t.Microsecond() + 1000000)[1:]
+			s += "." + frac[:t.fsp]
+		}
+	}
+	dec := new(MyDecimal)
+	// The string is always a valid decimal.
+	_ = dec.FromString([]byte(s))
+	return dec
+}
+
+// ConvertJSONToInt converts a JSON value to an integer, true is 1 and false and null are 0, a string is
+// converted as a string.
+func ConvertJSONToInt(sc *stmtctx.StatementContext, j json.BinaryJSON, unsigned bool) (int64, error) {
+	switch j.TypeCode {
+	case json.TypeCodeLiteral:
+		if j.Value[0] == json.LiteralTrue {
+			return 1, nil
+		}
+		return 0, nil
+	case json.TypeCodeInt64, json.TypeCodeUint64:
+		return j.GetInt64(), nil
+	case json.TypeCodeFloat64:
+		if unsigned {
+			u, err := ConvertFloatToUint(j.GetFloat64(), math.MaxUint64, mysql.TypeLonglong)
+			return int64(u), err
+		}
+		return ConvertFloatToInt(j.GetFloat64(), math.MinInt64, math.MaxInt64, mysql.TypeLonglong)
+	case json.TypeCodeString:
+		if unsigned {
+			u, err := StrToUint(sc, string(j.GetString()))
+			return int64(u), err
+		}
+		return StrToInt(sc, string(j.GetString()))
+	}
+	return 0, ErrTruncatedWrongVal.GenWithStackByArgs("INTEGER", j.String())
+}
+
+// ConvertJSONToFloat converts a JSON value to a float, true is 1 and false and null are 0, a string is
+// converted as a string.
+func ConvertJSONToFloat(sc *stmtctx.StatementContext, j json.BinaryJSON) (float64, error) {
+	switch j.TypeCode {
+	case json.TypeCodeLiteral:
+		if j.Value[0] == json.LiteralTrue {
+			return 1, nil
+		}
+		return 0, nil
+	case json.TypeCodeInt64:
+		return float64(j.GetInt64()), nil
+	case json.TypeCodeUint64:
+		return float64(j.GetUint64()), nil
+	case json.TypeCodeFloat64:
+		return j.GetFloat64(), nil
+	case json.TypeCodeString:
+		return StrToFloat(sc, string(j.GetString()))
+	}
+	return 0, ErrTruncatedWrongVal.GenWithStackByArgs("DOUBLE", j.String())
+}
+
+// ConvertJSONToDecimal converts a JSON value to a decimal.
+func ConvertJSONToDecimal(sc *stmtctx.StatementContext, j json.BinaryJSON) (*MyDecimal, error) {
+	switch j.TypeCode {
+	case json.TypeCodeInt64:
+		return NewDecFromInt(j.GetInt64()), nil
+	case json.TypeCodeUint64:
+		return NewDecFromUint(j.GetUint64()), nil
+	case json.TypeCodeString:
+		return StrToDecimal(sc, string(j.GetString()))
+	}
+	f, err := ConvertJSONToFloat(sc, j)
+	if err != nil {
+		return new(MyDecimal), err
+	}
+	dec := new(MyDecimal)
+	err = dec.FromFloat64(f)
+	return dec, err
+}
+
+// StrictFormatFloat formats a float like MySQL does, the shortest representation is used and
+// an exponent is used for very large or very small values.
+func StrictFormatFloat(f float64, bitSize int) string {
+	absVal := math.Abs(f)
+	if absVal == 0 || (absVal >= 1e-15 && absVal < 1e15) {
+		return strconv.FormatFloat(f, 'f', -1, bitSize)
+	}
+	s := strconv.FormatFloat(f, 'g', -1, bitSize)
+	// Go formats an exponent as e+20, MySQL omits the plus sign.
+	return strings.Replace(s, "e+", "e", 1)
+}
+
+// StrToDecimal converts a string to a decimal at the best-effort.
+func StrToDecimal(sc *stmtctx.StatementContext, str string) (*MyDecimal, error) {
+	str = strings.TrimSpace(str)
+	validStr, err := getValidFloatPrefix(sc, str)
+	dec := new(MyDecimal)
+	// The digits beyond MaxDecimalScale are rounded like the ones beyond the scale of the field type.
+	if err1 := dec.FromString([]byte(validStr)); err1 != nil && err1 != ErrTruncated {
+		return dec, err1
+	}
+	if err1 := dec.checkWidth(); err1 != nil {
+		return dec, err1
+	}
+	return dec, err
+}
+
+// ProduceDecWithSpecifiedTp rounds the decimal to the fraction digits of the field type, a value out of the
+// range of the field type is clipped to the max or min value of it, the overflow is handled by the statement context.
+func ProduceDecWithSpecifiedTp(dec *MyDecimal, tp *FieldType, sc *stmtctx.StatementContext) (*MyDecimal, error) {
+	flen, frac := tp.Flen, tp.Decimal
+	if flen == UnspecifiedLength || frac == UnspecifiedLength {
+		return dec, nil
+	}
+	res := dec.Copy().Rescale(frac)
+	intDigits := len(new(big.Int).Abs(res.intPart()).String())
+	if res.intPart().Sign() == 0 || intDigits <= flen-frac {
+		return res, nil
+	}
+	// The max value of DECIMAL(M, D) is 10^M-1 scaled by D digits.
+	res.unscaled.Sub(pow10(flen), big.NewInt(1))
+	res.frac = frac
+	if dec.IsNegative() {
+		res.unscaled.Neg(&res.unscaled)
+	}
+	err := ErrDataOutOfRange.GenWithStackByArgs("DECIMAL", dec.String())
+	return res, sc.HandleOverflow(err, err)
+}
+
+// ProduceFloatWithSpecifiedTp rounds the float to the fraction digits of FLOAT(M, D) or DOUBLE(M, D), a value
+// out of the range of the field type is clipped to the max or min value of it, the overflow is handled by the
+// statement context.
+func ProduceFloatWithSpecifiedTp(f float64, tp *FieldType, sc *stmtctx.StatementContext) (float64, error) {
+	origin := f
+	overflow := false
+	if tp.Flen != UnspecifiedLength && tp.Decimal != UnspecifiedLength {
+		pow := math.Pow10(tp.Decimal)
+		f = math.Round(f*pow) / pow
+		maxF := math.Pow10(tp.Flen-tp.Decimal) - 1/pow
+		if f > maxF {
+			f, overflow = maxF, true
+		} else if f < -maxF {
+			f, overflow = -maxF, true
+		}
+	}
+	if tp.Tp == mysql.TypeFloat && math.Abs(f) > math.MaxFloat32 {
+		f, overflow = math.Copysign(math.MaxFloat32, f), true
+	}
+	if mysql.HasUnsignedFlag(tp.Flag) && f < 0 {
+		f, overflow = 0, true
+	}
+	if overflow {
+		return f, handleOverflow(sc, tp.Tp, StrictFormatFloat(origin, 64))
+	}
+	return f, nil
+}
+
+// handleOverflow returns the error of converting the origin value to the type tp out of its range,
+// it's a warning if overflow is allowed by the statement context.
+func handleOverflow(sc *stmtctx.StatementContext, tp byte, origin string) error {
+	err := ErrDataOutOfRange.GenWithStackByArgs(strings.ToUpper(TypeStr(tp)), origin)
+	return sc.HandleOverflow(err, err)
+}
+
+// ProduceStrWithSpecifiedTp truncates the string to the length of the field type, which is counted in bytes
+// for a binary string or in characters otherwise, the truncation is handled by the statement context.
+// A BINARY(N) value is padded with zero bytes to the length.
+func ProduceStrWithSpecifiedTp(s string, tp *FieldType, sc *stmtctx.StatementContext) (string, error) {
+	flen := tp.Flen
+	if flen == UnspecifiedLength {
+		return s, nil
+	}
+	isBinary := tp.Charset == charset.CharsetBin
+	length := len(s)
+	if !isBinary {
+		length = utf8.RuneCountInString(s)
+	}
+	if length > flen {
+		var truncated string
+		if isBinary {
+			truncated = s[:flen]
+		} else {
+			truncated = string([]rune(s)[:flen])
+		}
+		tpName := "CHAR"
+		if isBinary {
+			tpName = "BINARY"
+		}
+		err := ErrTruncatedWrongVal.GenWithStackByArgs(fmt.Sprintf("%s(%d)", tpName, flen), s)
+		return truncated, sc.HandleTruncate(err)
+	}
+	if isBinary && tp.Tp == mysql.TypeString && length < flen {
+		return s + strings.Repeat("\x00", flen-length), nil
+	}
+	return s, nil
+}
@@ -0,0 +1,1007 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/pingcap/parser/charset"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/sessionctx/stmtctx"
+	"grant-db/types/json"
+)
+
+// Kind constants.
+const (
+	KindNull         byte = 0
+	KindInt64        byte = 1
+	KindUint64       byte = 2
+	KindFloat64      byte = 3
+	KindString       byte = 4
+	KindBytes        byte = 5
+	KindMysqlDecimal byte = 6
+	KindMysqlTime    byte = 7
+	KindMinNotNull   byte = 8
+	KindMaxValue     byte = 9
+	// KindMysqlDuration and the following kinds are appended to keep the values of the kinds above.
+	KindMysqlDuration byte = 10
+	KindMysqlEnum     byte = 11
+	KindMysqlSet      byte = 12
+	KindMysqlBit      byte = 13
+	KindBinaryLiteral byte = 14
+	KindMysqlJSON     byte = 15
+)
+
+// Datum is a data box holds different kind of data.
+// It has better performance and is easier to use than `interface{}`.
+type Datum struct {
+	k byte        // datum kind.
+	i int64       // i can hold int64 uint64 float64 values.
+	b []byte      // b can hold string or []byte values.
+	x interface{} // x hold all other types.
+}
+
+// Kind gets the kind of the datum.
+func (d *Datum) Kind() byte {
+	return d.k
+}
+
+// IsNull checks if datum is null.
+func (d *Datum) IsNull() bool {
+	return d.k == KindNull
+}
+
+// SetNull sets datum to nil.
+func (d *Datum) SetNull() {
+	d.k = KindNull
+	d.x = nil
+	d.b = nil
+}
+
+// GetInt64 gets int64 value.
+func (d *Datum) GetInt64() int64 {
+	return d.i
+}
+
+// SetInt64 sets int64 value.
+func (d *Datum) SetInt64(i int64) {
+	d.k = KindInt64
+	d.i = i
+}
+
+// GetUint64 gets uint64 value.
+func (d *Datum) GetUint64() uint64 {
+	return uint64(d.i)
+}
+
+// SetUint64 sets uint64 value.
+func (d *Datum) SetUint64(i uint64) {
+	d.k = KindUint64
+	d.i = int64(i)
+}
+
+// GetFloat64 gets float64 value.
+func (d *Datum) GetFloat64() float64 {
+	return d.x.(float64)
+}
+
+// SetFloat64 sets float64 value.
+func (d *Datum) SetFloat64(f float64) {
+	d.k = KindFloat64
+	d.x = f
+}
+
+// GetString gets string value.
+func (d *Datum) GetString() string {
+	return string(d.b)
+}
+
+// SetString sets string value.
+func (d *Datum) SetString(s string) {
+	d.k = KindString
+	d.b = []byte(s)
+}
+
+// GetBytes gets bytes value.
+func (d *Datum) GetBytes() []byte {
+	return d.b
+}
+
+// SetBytes sets bytes value to datum.
+func (d *Datum) SetBytes(b []byte) {
+	d.k = KindBytes
+	d.b = b
+}
+
+// GetMysqlDecimal gets Decimal value
+func (d *Datum) GetMysqlDecimal() *MyDecimal {
+	return d.x.(*MyDecimal)
+}
+
+// SetMysqlDecimal sets Decimal value
+func (d *Datum) SetMysqlDecimal(b *MyDecimal) {
+	d.k = KindMysqlDecimal
+	d.x = b
+}
+
+// GetMysqlTime gets types.Time value
+func (d *Datum) GetMysqlTime() Time {
+	return d.x.(Time)
+}
+
+// SetMysqlTime sets types.Time value
+func (d *Datum) SetMysqlTime(b Time) {
+	d.k = KindMysqlTime
+	d.x = b
+}
+
+// GetMysqlDuration gets Duration value
+func (d *Datum) GetMysqlDuration() Duration {
+	return d.x.(Duration)
+}
+
+// SetMysqlDuration sets Duration value
+func (d *Datum) SetMysqlDuration(b Duration) {
+	d.k = KindMysqlDuration
+	d.x = b
+}
+
+// GetMysqlEnum gets Enum value
+func (d *Datum) GetMysqlEnum() Enum {
+	return Enum{Value: uint64(d.i), Name: string(d.b)}
+}
+
+// SetMysqlEnum sets Enum value
+func (d *Datum) SetMysqlEnum(b Enum) {
+	d.k = KindMysqlEnum
+	d.i = int64(b.Value)
+	d.b = []byte(b.Name)
+}
+
+// GetMysqlSet gets Set value
+func (d *Datum) GetMysqlSet() Set {
+	return Set{Value: uint64(d.i), Name: string(d.b)}
+}
+
+// SetMysqlSet sets Set value
+func (d *Datum) SetMysqlSet(b Set) {
+	d.k = KindMysqlSet
+	d.i = int64(b.Value)
+	d.b = []byte(b.Name)
+}
+
+// GetMysqlBit gets MysqlBit value
+func (d *Datum) GetMysqlBit() BinaryLiteral {
+	return d.b
+}
+
+// SetMysqlBit sets MysqlBit value
+func (d *Datum) SetMysqlBit(b BinaryLiteral) {
+	d.k = KindMysqlBit
+	d.b = b
+}
+
+// GetBinaryLiteral gets Bit value
+func (d *Datum) GetBinaryLiteral() BinaryLiteral {
+	return d.b
+}
+
+// SetBinaryLiteral sets Bit value
+func (d *Datum) SetBinaryLiteral(b BinaryLiteral) {
+	d.k = KindBinaryLiteral
+	d.b = b
+}
+
+// GetMysqlJSON gets json.BinaryJSON value
+func (d *Datum) GetMysqlJSON() json.BinaryJSON {
+	return d.x.(json.BinaryJSON)
+}
+
+// SetMysqlJSON sets json.BinaryJSON value
+func (d *Datum) SetMysqlJSON(b json.BinaryJSON) {
+	d.k = KindMysqlJSON
+	d.x = b
+}
+
+// Copy deep copies a Datum into dst.
+func (d *Datum) Copy(dst *Datum) {
+	*dst = *d
+	if d.b != nil {
+		dst.b = make([]byte, len(d.b))
+		copy(dst.b, d.b)
+	}
+	switch d.k {
+	case KindMysqlDecimal:
+		dst.x = d.GetMysqlDecimal().Copy()
+	case KindMysqlJSON:
+		dst.x = d.GetMysqlJSON().Copy()
+	}
+}
+
+// GetValue gets the value of the datum of any kind.
+func (d *Datum) GetValue() interface{} {
+	switch d.k {
+	case KindInt64:
+		return d.GetInt64()
+	case KindUint64:
+		return d.GetUint64()
+	case KindFloat64:
+		return d.GetFloat64()
+	case KindString:
+		return d.GetString()
+	case KindBytes:
+		return d.GetBytes()
+	case KindMysqlEnum:
+		return d.GetMysqlEnum()
+	case KindMysqlSet:
+		return d.GetMysqlSet()
+	case KindMysqlBit, KindBinaryLiteral:
+		return d.GetBinaryLiteral()
+	default:
+		return d.x
+	}
+}
+
+// String returns a human-readable description of Datum. It is intended only for debugging.
+func (d Datum) String() string {
+	switch d.k {
+	case KindNull:
+		return "NULL"
+	case KindInt64:
+		return strconv.FormatInt(d.GetInt64(), 10)
+	case KindUint64:
+		return strconv.FormatUint(d.GetUint64(), 10)
+	case KindString, KindBytes:
+		return strconv.Quote(d.GetString())
+	case KindMinNotNull:
+		return "-inf"
+	case KindMaxValue:
+		return "+inf"
+	}
+	return fmt.Sprintf("%v", d.GetValue())
+}
+
+// NewDatum creates a new Datum from an interface{}.
+func NewDatum(in interface{}) (d Datum) {
+	switch x := in.(type) {
+	case nil:
+		d.SetNull()
+	case bool:
+		if x {
+			d.SetInt64(1)
+		} else {
+			d.SetInt64(0)
+		}
+	case int:
+		d.SetInt64(int64(x))
+	case int64:
+		d.SetInt64(x)
+	case uint64:
+		d.SetUint64(x)
+	case float64:
+		d.SetFloat64(x)
+	case string:
+		d.SetString(x)
+	case []byte:
+		d.SetBytes(x)
+	case *MyDecimal:
+		d.SetMysqlDecimal(x)
+	case Time:
+		d.SetMysqlTime(x)
+	case Duration:
+		d.SetMysqlDuration(x)
+	case Enum:
+		d.SetMysqlEnum(x)
+	case Set:
+		d.SetMysqlSet(x)
+	case BinaryLiteral:
+		d.SetBinaryLiteral(x)
+	case json.BinaryJSON:
+		d.SetMysqlJSON(x)
+	default:
+		panic(fmt.Sprintf("unsupported datum value %T", in))
+	}
+	return d
+}
+
+// NewIntDatum creates a new Datum from an int64 value.
+func NewIntDatum(i int64) (d Datum) {
+	d.SetInt64(i)
+	return d
+}
+
+// NewUintDatum creates a new Datum from an uint64 value.
+func NewUintDatum(i uint64) (d Datum) {
+	d.SetUint64(i)
+	return d
+}
+
+// NewFloat64Datum creates a new Datum from a float64 value.
+func NewFloat64Datum(f float64) (d Datum) {
+	d.SetFloat64(f)
+	return d
+}
+
+// NewStringDatum creates a new Datum from a string.
+func NewStringDatum(s string) (d Datum) {
+	d.SetString(s)
+	return d
+}
+
+// NewBytesDatum creates a new Datum from a byte slice.
+func NewBytesDatum(b []byte) (d Datum) {
+	d.SetBytes(b)
+	return d
+}
+
+// NewDecimalDatum creates a new Datum from a MyDecimal value.
+func NewDecimalDatum(dec *MyDecimal) (d Datum) {
+	d.SetMysqlDecimal(dec)
+	return d
+}
+
+// NewTimeDatum creates a new Time from a Time value.
+func NewTimeDatum(t Time) (d Datum) {
+	d.SetMysqlTime(t)
+	return d
+}
+
+// NewDurationDatum creates a new Datum from a Duration value.
+func NewDurationDatum(dur Duration) (d Datum) {
+	d.SetMysqlDuration(dur)
+	return d
+}
+
+// NewMysqlEnumDatum creates a new Datum from an Enum value.
+func NewMysqlEnumDatum(e Enum) (d Datum) {
+	d.SetMysqlEnum(e)
+	return d
+}
+
+// NewMysqlSetDatum creates a new Datum from a Set value.
+func NewMysqlSetDatum(e Set) (d Datum) {
+	d.SetMysqlSet(e)
+	return d
+}
+
+// NewMysqlBitDatum creates a new MysqlBit Datum for a BinaryLiteral value.
+func NewMysqlBitDatum(b BinaryLiteral) (d Datum) {
+	d.SetMysqlBit(b)
+	return d
+}
+
+// NewBinaryLiteralDatum creates a new BinaryLiteral Datum for a BinaryLiteral value.
+func NewBinaryLiteralDatum(b BinaryLiteral) (d Datum) {
+	d.SetBinaryLiteral(b)
+	return d
+}
+
+// NewMysqlJSONDatum creates a new Datum from a json.BinaryJSON value.
+func NewMysqlJSONDatum(j json.BinaryJSON) (d Datum) {
+	d.SetMysqlJSON(j)
+	return d
+}
+
+// MinNotNullDatum returns a datum represents minimum not null value.
+func MinNotNullDatum() Datum {
+	return Datum{k: KindMinNotNull}
+}
+
+// MaxValueDatum returns a datum represents max value.
+func MaxValueDatum() Datum {
+	return Datum{k: KindMaxValue}
+}
+
+// MakeDatums creates datum slice from interfaces.
+func MakeDatums(args ...interface{}) []Datum {
+	datums := make([]Datum, len(args))
+	for i, v := range args {
+		datums[i] = NewDatum(v)
+	}
+	return datums
+}
+
+// ToString gets the string representation of the datum.
+func (d *Datum) ToString() (string, error) {
+	switch d.Kind() {
+	case KindInt64:
+		return strconv.FormatInt(d.GetInt64(), 10), nil
+	case KindUint64:
+		return strconv.FormatUint(d.GetUint64(), 10), nil
+	case KindFloat64:
+		return StrictFormatFloat(d.GetFloat64(), 64), nil
+	case KindString, KindBytes:
+		return d.GetString(), nil
+	case KindMysqlDecimal:
+		return d.GetMysqlDecimal().String(), nil
+	case KindMysqlTime:
+		return d.GetMysqlTime().String(), nil
+	case KindMysqlDuration:
+		return d.GetMysqlDuration().String(), nil
+	case KindMysqlEnum, KindMysqlSet:
+		return string(d.b), nil
+	case KindMysqlBit, KindBinaryLiteral:
+		return d.GetBinaryLiteral().ToString(), nil
+	case KindMysqlJSON:
+		return d.GetMysqlJSON().String(), nil
+	default:
+		return "", fmt.Errorf("cannot convert %v(type %T) to string", d.GetValue(), d.GetValue())
+	}
+}
+
+// ToInt64 converts to a int64.
+func (d *Datum) ToInt64(sc *stmtctx.StatementContext) (int64, error) {
+	switch d.Kind() {
+	case KindInt64:
+		return d.GetInt64(), nil
+	case KindUint64:
+		return int64(d.GetUint64()), nil
+	case KindFloat64:
+		return ConvertFloatToInt(d.GetFloat64(), math.MinInt64, math.MaxInt64, mysql.TypeLonglong)
+	case KindString, KindBytes:
+		return StrToInt(sc, d.GetString())
+	case KindMysqlDecimal:
+		dec := d.GetMysqlDecimal().Copy().Rescale(0)
+		return dec.ToInt()
+	case KindMysqlTime:
+		dec := d.GetMysqlTime().ToNumber().Rescale(0)
+		return dec.ToInt()
+	case KindMysqlDuration:
+		dec := d.GetMysqlDuration().ToNumber().Rescale(0)
+		return dec.ToInt()
+	case KindMysqlEnum, KindMysqlSet:
+		return d.GetInt64(), nil
+	case KindMysqlBit, KindBinaryLiteral:
+		val, err := d.GetBinaryLiteral().ToInt(sc)
+		return int64(val), err
+	case KindMysqlJSON:
+		return ConvertJSONToInt(sc, d.GetMysqlJSON(), false)
+	default:
+		return 0, fmt.Errorf("cannot convert %v(type %T) to int64", d.GetValue(), d.GetValue())
+	}
+}
+
+// ToFloat64 converts to a float64
+func (d *Datum) ToFloat64(sc *stmtctx.StatementContext) (float64, error) {
+	switch d.Kind() {
+	case KindInt64:
+		return float64(d.GetInt64()), nil
+	case KindUint64:
+		return float64(d.GetUint64()), nil
+	case KindFloat64:
+		return d.GetFloat64(), nil
+	case KindString, KindBytes:
+		return StrToFloat(sc, d.GetString())
+	case KindMysqlDecimal:
+		return d.GetMysqlDecimal().ToFloat64()
+	case KindMysqlTime:
+		return d.GetMysqlTime().ToNumber().ToFloat64()
+	case KindMysqlDuration:
+		return d.GetMysqlDuration().ToNumber().ToFloat64()
+	case KindMysqlEnum, KindMysqlSet:
+		return float64(d.GetUint64()), nil
+	case KindMysqlBit, KindBinaryLiteral:
+		val, err := d.GetBinaryLiteral().ToInt(sc)
+		return float64(val), err
+	case KindMysqlJSON:
+		return ConvertJSONToFloat(sc, d.GetMysqlJSON())
+	default:
+		return 0, fmt.Errorf("cannot convert %v(type %T) to float64", d.GetValue(), d.GetValue())
+	}
+}
+
+// ToDecimal converts to a decimal.
+func (d *Datum) ToDecimal(sc *stmtctx.StatementContext) (*MyDecimal, error) {
+	switch d.Kind() {
+	case KindInt64:
+		return NewDecFromInt(d.GetInt64()), nil
+	case KindUint64:
+		return NewDecFromUint(d.GetUint64()), nil
+	case KindFloat64:
+		dec := new(MyDecimal)
+		err := dec.FromFloat64(d.GetFloat64())
+		return dec, err
+	case KindString, KindBytes:
+		return StrToDecimal(sc, d.GetString())
+	case KindMysqlDecimal:
+		return d.GetMysqlDecimal(), nil
+	case KindMysqlTime:
+		return d.GetMysqlTime().ToNumber(), nil
+	case KindMysqlDuration:
+		return d.GetMysqlDuration().ToNumber(), nil
+	case KindMysqlEnum, KindMysqlSet:
+		return NewDecFromUint(d.GetUint64()), nil
+	case KindMysqlBit, KindBinaryLiteral:
+		val, err := d.GetBinaryLiteral().ToInt(sc)
+		return NewDecFromUint(val), err
+	case KindMysqlJSON:
+		return ConvertJSONToDecimal(sc, d.GetMysqlJSON())
+	default:
+		return nil, fmt.Errorf("cannot convert %v(type %T) to decimal", d.GetValue(), d.GetValue())
+	}
+}
+
+// ToBool converts to a bool, 1 for true and 0 for false.
+func (d *Datum) ToBool(sc *stmtctx.StatementContext) (int64, error) {
+	var isZero bool
+	switch d.Kind() {
+	case KindInt64, KindUint64:
+		isZero = d.GetInt64() == 0
+	case KindFloat64:
+		isZero = d.GetFloat64() == 0
+	case KindString, KindBytes:
+		f, err := StrToFloat(sc, d.GetString())
+		if err != nil {
+			return 0, err
+		}
+		isZero = f == 0
+	case KindMysqlDecimal:
+		isZero = d.GetMysqlDecimal().IsZero()
+	case KindMysqlTime:
+		isZero = d.GetMysqlTime().IsZero()
+	case KindMysqlDuration:
+		isZero = d.GetMysqlDuration().Duration == 0
+	case KindMysqlEnum, KindMysqlSet:
+		isZero = d.GetUint64() == 0
+	case KindMysqlBit, KindBinaryLiteral:
+		val, err := d.GetBinaryLiteral().ToInt(sc)
+		if err != nil {
+			return 0, err
+		}
+		isZero = val == 0
+	case KindMysqlJSON:
+		f, err := ConvertJSONToFloat(sc, d.GetMysqlJSON())
+		if err != nil {
+			return 0, err
+		}
+		isZero = f == 0
+	default:
+		return 0, fmt.Errorf("cannot convert %v(type %T) to bool", d.GetValue(), d.GetValue())
+	}
+	if isZero {
+		return 0, nil
+	}
+	return 1, nil
+}
+
+// ConvertTo converts a datum to the target field type. A value out of the range of the target type
+// is clipped and a value that doesn't fit the target type is truncated, the overflow and the truncation
+// are handled by the statement context, the converted value is returned along with the error.
+func (d *Datum) ConvertTo(sc *stmtctx.StatementContext, target *FieldType) (Datum, error) {
+	if d.k == KindNull {
+		return Datum{}, nil
+	}
+	switch target.Tp {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong:
+		if mysql.HasUnsignedFlag(target.Flag) {
+			return d.convertToUint(sc, target)
+		}
+		return d.convertToInt(sc, target)
+	case mysql.TypeFloat, mysql.TypeDouble:
+		return d.convertToFloat(sc, target)
+	case mysql.TypeBlob, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob,
+		mysql.TypeString, mysql.TypeVarchar, mysql.TypeVarString:
+		return d.convertToString(sc, target)
+	case mysql.TypeTimestamp, mysql.TypeDatetime, mysql.TypeDate:
+		return d.convertToMysqlTime(sc, target)
+	case mysql.TypeDuration:
+		return d.convertToMysqlDuration(sc, target)
+	case mysql.TypeNewDecimal:
+		return d.convertToMysqlDecimal(sc, target)
+	case mysql.TypeYear:
+		return d.convertToMysqlYear(sc, target)
+	case mysql.TypeEnum:
+		return d.convertToMysqlEnum(sc, target)
+	case mysql.TypeSet:
+		return d.convertToMysqlSet(sc, target)
+	case mysql.TypeBit:
+		return d.convertToMysqlBit(sc, target)
+	case mysql.TypeJSON:
+		return d.convertToMysqlJSON(sc, target)
+	case mysql.TypeNull:
+		return Datum{}, nil
+	}
+	return Datum{}, fmt.Errorf("cannot convert datum from %T to type %s", d.GetValue(), TypeStr(target.Tp))
+}
+
+// toStringForError returns the string form of the datum used in the error messages.
+func (d *Datum) toStringForError() string {
+	if s, err := d.ToString(); err == nil {
+		return s
+	}
+	return d.String()
+}
+
+func (d *Datum) convertToInt(sc *stmtctx.StatementContext, target *FieldType) (Datum, error) {
+	lower, upper := IntergerSignedLowerBound(target.Tp), IntergerSignedUpperBound(target.Tp)
+	var (
+		val int64
+		err error
+		ret Datum
+	)
+	switch d.k {
+	case KindInt64:
+		val = d.GetInt64()
+	case KindUint64:
+		if d.GetUint64() > uint64(upper) {
+			val, err = upper, ErrOverflow
+		} else {
+			val = d.GetInt64()
+		}
+	case KindFloat64:
+		val, err = ConvertFloatToInt(d.GetFloat64(), lower, upper, target.Tp)
+	case KindString, KindBytes:
+		val, err = StrToInt(sc, d.GetString())
+	case KindMysqlEnum, KindMysqlSet:
+		val = d.GetInt64()
+		if d.GetUint64() > uint64(upper) {
+			val, err = upper, ErrOverflow
+		}
+	case KindMysqlBit, KindBinaryLiteral:
+		var uval uint64
+		uval, err = d.GetBinaryLiteral().ToInt(sc)
+		val = int64(uval)
+		if err == nil && uval > uint64(upper) {
+			val, err = upper, ErrOverflow
+		}
+	default:
+		val, err = d.ToInt64(sc)
+	}
+	if err == nil || err == ErrOverflow {
+		if val < lower {
+			val, err = lower, ErrOverflow
+		} else if val > upper {
+			val, err = upper, ErrOverflow
+		}
+	}
+	if err == ErrOverflow {
+		err = handleOverflow(sc, target.Tp, d.toStringForError())
+	}
+	ret.SetInt64(val)
+	return ret, err
+}
+
+func (d *Datum) convertToUint(sc *stmtctx.StatementContext, target *FieldType) (Datum, error) {
+	upper := IntergerUnsignedUpperBound(target.Tp)
+	var (
+		val uint64
+		err error
+		ret Datum
+	)
+	switch d.k {
+	case KindInt64:
+		if d.GetInt64() < 0 {
+			val, err = 0, ErrOverflow
+		} else {
+			val = d.GetUint64()
+		}
+	case KindUint64, KindMysqlEnum, KindMysqlSet:
+		val = d.GetUint64()
+	case KindFloat64:
+		val, err = ConvertFloatToUint(d.GetFloat64(), upper, target.Tp)
+	case KindString, KindBytes:
+		val, err = StrToUint(sc, d.GetString())
+	case KindMysqlBit, KindBinaryLiteral:
+		val, err = d.GetBinaryLiteral().ToInt(sc)
+	default:
+		var dec *MyDecimal
+		dec, err = d.ToDecimal(sc)
+		if err == nil {
+			val, err = dec.Copy().Rescale(0).ToUint()
+		}
+	}
+	if (err == nil || err == ErrOverflow) && val > upper {
+		val, err = upper, ErrOverflow
+	}
+	if err == ErrOverflow {
+		err = handleOverflow(sc, target.Tp, d.toStringForError())
+	}
+	ret.SetUint64(val)
+	return ret, err
+}
+
+func (d *Datum) convertToFloat(sc *stmtctx.StatementContext, target *FieldType) (Datum, error) {
+	var ret Datum
+	f, err := d.ToFloat64(sc)
+	if err == ErrOverflow {
+		err = handleOverflow(sc, target.Tp, d.toStringForError())
+	}
+	if err != nil {
+		ret.SetFloat64(f)
+		return ret, err
+	}
+	f, err = ProduceFloatWithSpecifiedTp(f, target, sc)
+	ret.SetFloat64(f)
+	return ret, err
+}
+
+func (d *Datum) convertToString(sc *stmtctx.StatementContext, target *FieldType) (Datum, error) {
+	var (
+		ret Datum
+		s   string
+		err error
+	)
+	switch d.k {
+	case KindFloat64:
+		s = StrictFormatFloat(d.GetFloat64(), 64)
+	default:
+		s, err = d.ToString()
+		if err != nil {
+			return ret, err
+		}
+	}
+	s, err = ProduceStrWithSpecifiedTp(s, target, sc)
+	if target.Charset == charset.CharsetBin {
+		ret.SetBytes([]byte(s))
+	} else {
+		ret.SetString(s)
+	}
+	return ret, err
+}
+
+func (d *Datum) convertToMysqlTime(sc *stmtctx.StatementContext, target *FieldType) (Datum, error) {
+	tp := target.Tp
+	fsp := DefaultFsp
+	if target.Decimal != UnspecifiedLength {
+		fsp = int8(target.Decimal)
+	}
+	var (
+		ret Datum
+		t   Time
+		err error
+	)
+	switch d.k {
+	case KindMysqlTime:
+		t, err = d.GetMysqlTime().Convert(tp, fsp)
+	case KindMysqlDuration:
+		t, err = d.GetMysqlDuration().ConvertToTime(sc.GetNowTsCached(), tp)
+		if err == nil {
+			t, err = t.Convert(tp, fsp)
+		}
+	case KindInt64, KindUint64:
+		if d.GetInt64() == 0 {
+			t = NewTime(0, 0, 0, 0, 0, 0, 0, tp, fsp)
+			break
+		}
+		t, err = parseTimeWithFsp(d.toStringForError(), tp, fsp)
+	case KindString, KindBytes, KindFloat64, KindMysqlDecimal:
+		t, err = parseTimeWithFsp(d.toStringForError(), tp, fsp)
+	case KindMysqlJSON:
+		t, err = parseTimeWithFsp(d.GetMysqlJSON().Unquote(), tp, fsp)
+	default:
+		return ret, fmt.Errorf("cannot convert datum from %T to type %s", d.GetValue(), TypeStr(tp))
+	}
+	if err == nil {
+		err = t.Check(sc)
+		if err == nil && tp == mysql.TypeTimestamp && !t.IsZero() && !t.inTimestampRange() {
+			err = ErrTruncatedWrongVal.GenWithStackByArgs(TypeStr(tp), t.String())
+		}
+	} else {
+		err = ErrTruncatedWrongVal.GenWithStackByArgs(TypeStr(tp), d.toStringForError())
+	}
+	if err != nil {
+		// An invalid value is stored as the zero value.
+		t = NewTime(0, 0, 0, 0, 0, 0, 0, tp, fsp)
+		err = sc.HandleTruncate(err)
+	}
+	ret.SetMysqlTime(t)
+	return ret, err
+}
+
+// parseTimeWithFsp parses the string into a time, the fraction part is rounded to fsp digits.
+func parseTimeWithFsp(s string, tp byte, fsp int8) (Time, error) {
+	t, err := ParseTime(s, tp, MaxFsp)
+	if err != nil {
+		return t, err
+	}
+	return t.Convert(tp, fsp)
+}
+
+func (d *Datum) convertToMysqlDuration(sc *stmtctx.StatementContext, target *FieldType) (Datum, error) {
+	fsp := DefaultFsp
+	if target.Decimal != UnspecifiedLength {
+		fsp = int8(target.Decimal)
+	}
+	var (
+		ret Datum
+		dur Duration
+		err error
+	)
+	switch d.k {
+	case KindMysqlDuration:
+		dur = d.GetMysqlDuration().RoundFrac(fsp)
+	case KindMysqlTime:
+		dur = d.GetMysqlTime().ConvertToDuration().RoundFrac(fsp)
+	case KindString, KindBytes:
+		dur, err = ParseDuration(d.GetString(), fsp)
+	case KindMysqlJSON:
+		dur, err = ParseDuration(d.GetMysqlJSON().Unquote(), fsp)
+	case KindInt64, KindUint64, KindFloat64, KindMysqlDecimal:
+		var dec *MyDecimal
+		if dec, err = d.ToDecimal(sc); err == nil {
+			dur, err = ParseDuration(dec.String(), fsp)
+		}
+	default:
+		return ret, fmt.Errorf("cannot convert datum from %T to type %s", d.GetValue(), TypeStr(target.Tp))
+	}
+	if err != nil {
+		if err != ErrOverflow {
+			dur = Duration{Fsp: fsp}
+		}
+		err = sc.HandleTruncate(ErrTruncatedWrongVal.GenWithStackByArgs(TypeStr(target.Tp), d.toStringForError()))
+	}
+	ret.SetMysqlDuration(dur)
+	return ret, err
+}
+
+func (d *Datum) convertToMysqlDecimal(sc *stmtctx.StatementContext, target *FieldType) (Datum, error) {
+	var ret Datum
+	dec, err := d.ToDecimal(sc)
+	if err == ErrOverflow || err == ErrBadNumber {
+		err = handleOverflow(sc, target.Tp, d.toStringForError())
+	}
+	if err != nil {
+		if dec == nil {
+			dec = new(MyDecimal)
+		}
+		ret.SetMysqlDecimal(dec)
+		return ret, err
+	}
+	if mysql.HasUnsignedFlag(target.Flag) && dec.IsNegative() {
+		dec = new(MyDecimal)
+		err = handleOverflow(sc, target.Tp, d.toStringForError())
+	}
+	if err == nil {
+		dec, err = ProduceDecWithSpecifiedTp(dec, target, sc)
+	}
+	ret.SetMysqlDecimal(dec)
+	return ret, err
+}
+
+// convertToMysqlYear converts the datum to a year in 1901~2155 or 0. A number in 1~99 and a string in 0~99
+// are two-digit years in 1970~2069.
+func (d *Datum) convertToMysqlYear(sc *stmtctx.StatementContext, target *FieldType) (Datum, error) {
+	var (
+		ret Datum
+		y   int64
+		err error
+	)
+	switch d.k {
+	case KindString, KindBytes:
+		s := strings.TrimSpace(d.GetString())
+		y, err = StrToInt(sc, s)
+		if err == nil && y >= 0 && y < 100 && len(s) <= 2 {
+			y = int64(adjustYear(int(y)))
+		}
+	case KindMysqlTime:
+		y = int64(d.GetMysqlTime().Year())
+	default:
+		y, err = d.ToInt64(sc)
+		if err == nil && y > 0 && y < 100 {
+			y = int64(adjustYear(int(y)))
+		}
+	}
+	if (err == nil || err == ErrOverflow) && y != 0 && (y < 1901 || y > 2155) {
+		y, err = 0, ErrOverflow
+	}
+	if err == ErrOverflow {
+		err = handleOverflow(sc, target.Tp, d.toStringForError())
+	}
+	if mysql.HasUnsignedFlag(target.Flag) {
+		ret.SetUint64(uint64(y))
+	} else {
+		ret.SetInt64(y)
+	}
+	return ret, err
+}
+
+func (d *Datum) convertToMysqlEnum(sc *stmtctx.StatementContext, target *FieldType) (Datum, error) {
+	var (
+		ret Datum
+		e   Enum
+		err error
+	)
+	switch d.k {
+	case KindString, KindBytes, KindMysqlEnum, KindMysqlSet:
+		e, err = ParseEnumName(target.Elems, string(d.b))
+	default:
+		var val int64
+		if val, err = d.ToInt64(sc); err == nil {
+			e, err = ParseEnumValue(target.Elems, uint64(val))
+		}
+	}
+	if err != nil {
+		// An invalid value is stored as the empty string, whose index is 0.
+		e = Enum{}
+		err = sc.HandleTruncate(ErrTruncatedWrongVal.GenWithStackByArgs("enum", d.toStringForError()))
+	}
+	ret.SetMysqlEnum(e)
+	return ret, err
+}
+
+func (d *Datum) convertToMysqlSet(sc *stmtctx.StatementContext, target *FieldType) (Datum, error) {
+	var (
+		ret Datum
+		s   Set
+		err error
+	)
+	switch d.k {
+	case KindString, KindBytes, KindMysqlEnum, KindMysqlSet:
+		s, err = ParseSetName(target.Elems, string(d.b))
+	default:
+		var val int64
+		if val, err = d.ToInt64(sc); err == nil {
+			s, err = ParseSetValue(target.Elems, uint64(val))
+		}
+	}
+	if err != nil {
+		s = Set{}
+		err = sc.HandleTruncate(ErrTruncatedWrongVal.GenWithStackByArgs("set", d.toStringForError()))
+	}
+	ret.SetMysqlSet(s)
+	return ret, err
+}
+
+func (d *Datum) convertToMysqlBit(sc *stmtctx.StatementContext, target *FieldType) (Datum, error) {
+	flen := target.Flen
+	if flen == UnspecifiedLength {
+		flen = 1
+	}
+	var (
+		ret Datum
+		val uint64
+		err error
+	)
+	switch d.k {
+	case KindString, KindBytes, KindMysqlBit, KindBinaryLiteral:
+		val, err = BinaryLiteral(d.b).ToInt(sc)
+	default:
+		uintType := NewFieldType(mysql.TypeLonglong)
+		uintType.Flag |= mysql.UnsignedFlag
+		var uintDatum Datum
+		uintDatum, err = d.convertToUint(sc, uintType)
+		val = uintDatum.GetUint64()
+	}
+	if err == nil && flen < 64 && val >= 1<<uint64(flen) {
+		val = 1<<uint64(flen) - 1
+		err = handleOverflow(sc, target.Tp, d.toStringForError())
+	}
+	ret.SetMysqlBit(NewBinaryLiteralFromUint(val, (flen+7)>>3))
+	return ret, err
+}
+
+// convertToMysqlJSON converts the datum to JSON. A string is parsed as a JSON text, a number is a JSON number
+// and the other values are JSON strings of their string forms.
+func (d *Datum) convertToMysqlJSON(sc *stmtctx.StatementContext, target *FieldType) (Datum, error) {
+	var (
+		ret Datum
+		j   json.BinaryJSON
+		err error
+	)
+	switch d.k {
+	case KindMysqlJSON:
+		j = d.GetMysqlJSON()
+	case KindString, KindBytes:
+		j, err = json.ParseBinaryFromString(d.GetString())
+	case KindInt64:
+		j = json.CreateBinary(d.GetInt64())
+	case KindUint64:
+		j = json.CreateBinary(d.GetUint64())
+	case KindFloat64:
+		j = json.CreateBinary(d.GetFloat64())
+	case KindMysqlDecimal:
+		var f float64
+		f, err = d.GetMysqlDecimal().ToFloat64()
+		j = json.CreateBinary(f)
+	default:
+		var s string
+		s, err = d.ToString()
+		j = json.CreateBinary(s)
+	}
+	if err != nil {
+		return ret, err
+	}
+	ret.SetMysqlJSON(j)
+	return ret, nil
+}
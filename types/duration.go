@@ -0,0 +1,240 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	gotime "time"
+
+	"github.com/pingcap/parser/mysql"
+)
+
+// The range of TIME is '-838:59:59.000000' to '838:59:59.000000'.
+const (
+	MaxHour   = 838
+	MaxMinute = 59
+	MaxSecond = 59
+	// MaxTime is the max value of TIME.
+	MaxTime = MaxHour*gotime.Hour + MaxMinute*gotime.Minute + MaxSecond*gotime.Second
+)
+
+// Duration is the type for MySQL TIME type.
+type Duration struct {
+	Duration gotime.Duration
+	// Fsp is short for Fractional Seconds Precision.
+	Fsp int8
+}
+
+// ZeroDuration is the zero value for Duration type.
+var ZeroDuration = Duration{Duration: 0, Fsp: DefaultFsp}
+
+// Hour returns the hour of the duration, which may be larger than 23.
+func (d Duration) Hour() int {
+	return int(absDuration(d.Duration) / gotime.Hour)
+}
+
+// Minute returns the minute of the duration.
+func (d Duration) Minute() int {
+	return int(absDuration(d.Duration) % gotime.Hour / gotime.Minute)
+}
+
+// Second returns the second of the duration.
+func (d Duration) Second() int {
+	return int(absDuration(d.Duration) % gotime.Minute / gotime.Second)
+}
+
+// MicroSecond returns the microsecond of the duration.
+func (d Duration) MicroSecond() int {
+	return int(absDuration(d.Duration) % gotime.Second / gotime.Microsecond)
+}
+
+func absDuration(d gotime.Duration) gotime.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// String returns the duration in the format of [-]HH:MM:SS[.fraction].
+func (d Duration) String() string {
+	sign := ""
+	if d.Duration < 0 {
+		sign = "-"
+	}
+	s := fmt.Sprintf("%s%02d:%02d:%02d", sign, d.Hour(), d.Minute(), d.Second())
+	if d.Fsp > 0 {
+		frac := fmt.Sprintf("%06d", d.MicroSecond())
+		s += "." + frac[:d.Fsp]
+	}
+	return s
+}
+
+// ToNumber returns a formatted number, like 101010 for '10:10:10' and -101010.123 for '-10:10:10.123'.
+func (d Duration) ToNumber() *MyDecimal {
+	s := strconv.Itoa(d.Hour()*10000 + d.Minute()*100 + d.Second())
+	if d.Duration < 0 {
+		s = "-" + s
+	}
+	if d.Fsp > 0 {
+		frac := strconv.Itoa(d.MicroSecond() + 1000000)[1:]
+		s += "." + frac[:d.Fsp]
+	}
+	dec := new(MyDecimal)
+	// The string is always a valid decimal.
+	_ = dec.FromString([]byte(s))
+	return dec
+}
+
+// Compare returns an integer comparing the duration d to o.
+func (d Duration) Compare(o Duration) int {
+	switch {
+	case d.Duration < o.Duration:
+		return -1
+	case d.Duration > o.Duration:
+		return 1
+	}
+	return 0
+}
+
+// RoundFrac rounds the fraction part to fsp digits, half away from zero, the result is clipped into the range of TIME.
+func (d Duration) RoundFrac(fsp int8) Duration {
+	if fsp < MinFsp {
+		fsp = DefaultFsp
+	}
+	if fsp > MaxFsp {
+		fsp = MaxFsp
+	}
+	unit := gotime.Duration(math.Pow10(9 - int(fsp)))
+	rounded := absDuration(d.Duration) + unit/2
+	rounded -= rounded % unit
+	if rounded > MaxTime {
+		rounded = MaxTime
+	}
+	if d.Duration < 0 {
+		rounded = -rounded
+	}
+	return Duration{Duration: rounded, Fsp: fsp}
+}
+
+// ConvertToTime converts the duration to a time of type tp on the date of now.
+func (d Duration) ConvertToTime(now gotime.Time, tp byte) (Time, error) {
+	year, month, day := now.Date()
+	tm := gotime.Date(year, month, day, 0, 0, 0, 0, gotime.UTC).Add(d.Duration)
+	if tp == mysql.TypeDate {
+		return NewTime(tm.Year(), int(tm.Month()), tm.Day(), 0, 0, 0, 0, tp, 0), nil
+	}
+	if tm.Year() < 0 || tm.Year() > 9999 {
+		return ZeroDatetime, ErrOverflow
+	}
+	return FromGoTime(tm, tp, d.Fsp), nil
+}
+
+// ConvertToDuration converts the time part of the time to a duration.
+func (t Time) ConvertToDuration() Duration {
+	if t.tp == mysql.TypeDate {
+		return ZeroDuration
+	}
+	dur := gotime.Duration(t.Hour())*gotime.Hour + gotime.Duration(t.Minute())*gotime.Minute +
+		gotime.Duration(t.Second())*gotime.Second + gotime.Duration(t.Microsecond())*gotime.Microsecond
+	return Duration{Duration: dur, Fsp: t.fsp}
+}
+
+// ParseDuration parses a formatted string into a duration with the specific fsp. The accepted formats are
+// '[-][D ]HH:MM[:SS[.fraction]]', '[-]HHMMSS[.fraction]' and a datetime whose time part is taken.
+// A value out of the range of TIME is clipped with ErrOverflow.
+func ParseDuration(str string, fsp int8) (Duration, error) {
+	str = strings.TrimSpace(str)
+	if len(str) == 0 {
+		return ZeroDuration, ErrInvalidTimeFormat
+	}
+	neg := false
+	body := str
+	if body[0] == '-' {
+		neg, body = true, body[1:]
+	}
+	if strings.ContainsAny(body, "-/") || (len(body) >= 12 && strings.IndexByte(body, ':') < 0) {
+		t, err := ParseTime(str, mysql.TypeDatetime, MaxFsp)
+		if err != nil {
+			return ZeroDuration, err
+		}
+		return t.ConvertToDuration().RoundFrac(fsp), nil
+	}
+	frac := ""
+	if i := strings.IndexByte(body, '.'); i >= 0 {
+		body, frac = body[:i], body[i+1:]
+	}
+	var day, hour, minute, second int
+	var err error
+	if strings.IndexByte(body, ':') >= 0 {
+		day, hour, minute, second, err = parseClockDuration(body)
+	} else {
+		hour, minute, second, err = parseNumericDuration(body)
+	}
+	if err != nil {
+		return ZeroDuration, err
+	}
+	if minute > MaxMinute || second > MaxSecond {
+		return ZeroDuration, ErrInvalidTimeFormat
+	}
+	var nanos int64
+	if frac != "" {
+		if len(frac) > 9 {
+			frac = frac[:9]
+		}
+		v, err := strconv.ParseInt(frac+strings.Repeat("0", 9-len(frac)), 10, 64)
+		if err != nil {
+			return ZeroDuration, ErrInvalidTimeFormat
+		}
+		nanos = v
+	}
+	hours := int64(day)*24 + int64(hour)
+	if hours > MaxHour {
+		hours, minute, second, nanos, err = MaxHour, MaxMinute, MaxSecond, 0, ErrOverflow
+	}
+	dur := gotime.Duration(hours)*gotime.Hour + gotime.Duration(minute)*gotime.Minute +
+		gotime.Duration(second)*gotime.Second + gotime.Duration(nanos)
+	if neg {
+		dur = -dur
+	}
+	return Duration{Duration: dur, Fsp: MaxFsp}.RoundFrac(fsp), err
+}
+
+// parseClockDuration parses the format '[D ]HH:MM[:SS]'.
+func parseClockDuration(s string) (day, hour, minute, second int, err error) {
+	if i := strings.IndexByte(s, ' '); i >= 0 {
+		if day, err = strconv.Atoi(s[:i]); err != nil || day > 34 {
+			return 0, 0, 0, 0, ErrInvalidTimeFormat
+		}
+		s = strings.TrimSpace(s[i+1:])
+	}
+	fields := strings.Split(s, ":")
+	if len(fields) > 3 {
+		return 0, 0, 0, 0, ErrInvalidTimeFormat
+	}
+	values := make([]int, 3)
+	for i, f := range fields {
+		if values[i], err = strconv.Atoi(f); err != nil || values[i] < 0 {
+			return 0, 0, 0, 0, ErrInvalidTimeFormat
+		}
+	}
+	return day, values[0], values[1], values[2], nil
+}
+
+// parseNumericDuration parses the format 'HHMMSS', 'MMSS' or 'SS'.
+func parseNumericDuration(s string) (hour, minute, second int, err error) {
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, 0, 0, ErrInvalidTimeFormat
+	}
+	if v/10000 > MaxHour {
+		return MaxHour + 1, 0, 0, nil
+	}
+	return int(v / 10000), int(v / 100 % 100), int(v % 100), nil
+}
+
+// NumberToDuration converts a number in the format of [-]HHMMSS to a duration, a number in the
+// format of YYYYMMDDHHMMSS is converted from the time part of the datetime.
+func NumberToDuration(number int64, fsp int8) (Duration, error) {
+	return ParseDuration(strconv.FormatInt(number, 10), fsp)
+}
@@ -0,0 +1,46 @@
+package types
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Enum is for MySQL enum type.
+type Enum struct {
+	Name  string
+	Value uint64
+}
+
+// String implements fmt.Stringer interface.
+func (e Enum) String() string {
+	return e.Name
+}
+
+// ToNumber changes enum index to float64 for numeric operation.
+func (e Enum) ToNumber() float64 {
+	return float64(e.Value)
+}
+
+// ParseEnumName creates a Enum with item name, the name is matched case-insensitively and the trailing
+// spaces are ignored. A number is taken as the index of the item if no item has the name.
+func ParseEnumName(elems []string, name string) (Enum, error) {
+	name = strings.TrimRight(name, " ")
+	for i, n := range elems {
+		if strings.EqualFold(strings.TrimRight(n, " "), name) {
+			return Enum{Name: n, Value: uint64(i) + 1}, nil
+		}
+	}
+	// name doesn't exist, maybe an integer?
+	if num, err := strconv.ParseUint(name, 10, 64); err == nil {
+		return ParseEnumValue(elems, num)
+	}
+	return Enum{}, ErrTruncatedWrongVal.GenWithStackByArgs("enum", name)
+}
+
+// ParseEnumValue creates a Enum with the 1-based index of the item.
+func ParseEnumValue(elems []string, number uint64) (Enum, error) {
+	if number == 0 || number > uint64(len(elems)) {
+		return Enum{}, ErrTruncatedWrongVal.GenWithStackByArgs("enum", strconv.FormatUint(number, 10))
+	}
+	return Enum{Name: elems[number-1], Value: number}, nil
+}
@@ -0,0 +1,33 @@
+package types
+
+import (
+	"errors"
+
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
+)
+
+var (
+	// ErrOverflow is returned when data is out of range for a field type.
+	ErrOverflow = errors.New("data out of range")
+	// ErrTruncated is returned when the digits beyond the precision of a type are dropped.
+	ErrTruncated = errors.New("data truncated")
+	// ErrInvalidTimeFormat is returned when a string can not be parsed into a time.
+	ErrInvalidTimeFormat = errors.New("invalid time format")
+
+	// ErrDataTooLong is returned when converts a string value that is longer than field type length.
+	ErrDataTooLong = terror.ClassTypes.New(mysql.ErrDataTooLong, mysql.MySQLErrName[mysql.ErrDataTooLong])
+	// ErrWarnDataOutOfRange is returned when the value in a numeric column that is outside the permissible range of the column data type.
+	ErrWarnDataOutOfRange = terror.ClassTypes.New(mysql.ErrWarnDataOutOfRange, mysql.MySQLErrName[mysql.ErrWarnDataOutOfRange])
+	// ErrWarnDataTruncated is returned when the value written to a column is truncated to the column type.
+	ErrWarnDataTruncated = terror.ClassTypes.New(mysql.WarnDataTruncated, mysql.MySQLErrName[mysql.WarnDataTruncated])
+	// ErrDataOutOfRange is returned when a value is out of the range of the type it's converted to, or the
+	// result of an expression is out of the range of its type.
+	ErrDataOutOfRange = terror.ClassTypes.New(mysql.ErrDataOutOfRange, mysql.MySQLErrName[mysql.ErrDataOutOfRange])
+	// ErrDatetimeFunctionOverflow is returned when the calculation in a datetime function is overflow.
+	ErrDatetimeFunctionOverflow = terror.ClassTypes.New(mysql.ErrDatetimeFunctionOverflow, mysql.MySQLErrName[mysql.ErrDatetimeFunctionOverflow])
+	// ErrTruncatedWrongVal is returned when data has been truncated during conversion.
+	ErrTruncatedWrongVal = terror.ClassTypes.New(mysql.ErrTruncatedWrongValue, mysql.MySQLErrName[mysql.ErrTruncatedWrongValue])
+	// ErrTooBigPrecision is returned when the fractional seconds precision is larger than MaxFsp.
+	ErrTooBigPrecision = terror.ClassTypes.New(mysql.ErrTooBigPrecision, mysql.MySQLErrName[mysql.ErrTooBigPrecision])
+)
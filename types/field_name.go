@@ -0,0 +1,35 @@
+package types
+
+import (
+	"strings"
+
+	"github.com/pingcap/parser/model"
+)
+
+// FieldName records the names used for mysql protocol.
+type FieldName struct {
+	OrigTblName model.CIStr
+	OrigColName model.CIStr
+	DBName      model.CIStr
+	TblName     model.CIStr
+	ColName     model.CIStr
+	// Redundant marks the column of a NATURAL or USING join which is merged into the common column,
+	// it's only found by the qualified name and not expanded by the wildcard.
+	Redundant bool
+}
+
+// String implements Stringer interface.
+func (name *FieldName) String() string {
+	builder := strings.Builder{}
+	if name.DBName.L != "" {
+		builder.WriteString(name.DBName.L + ".")
+	}
+	if name.TblName.L != "" {
+		builder.WriteString(name.TblName.L + ".")
+	}
+	builder.WriteString(name.ColName.L)
+	return builder.String()
+}
+
+// NameSlice is the slice of the *fieldName
+type NameSlice []*FieldName
@@ -0,0 +1,77 @@
+package types
+
+import (
+	"github.com/pingcap/parser/charset"
+	"github.com/pingcap/parser/mysql"
+	ptypes "github.com/pingcap/parser/types"
+)
+
+// FieldType records field type information.
+type FieldType = ptypes.FieldType
+
+// EvalType indicates the type a value takes in evaluation.
+type EvalType = ptypes.EvalType
+
+// UnspecifiedLength is unspecified length.
+const UnspecifiedLength = ptypes.UnspecifiedLength
+
+// EvalType values.
+const (
+	ETInt       = ptypes.ETInt
+	ETReal      = ptypes.ETReal
+	ETDecimal   = ptypes.ETDecimal
+	ETString    = ptypes.ETString
+	ETDatetime  = ptypes.ETDatetime
+	ETTimestamp = ptypes.ETTimestamp
+	ETDuration  = ptypes.ETDuration
+	ETJson      = ptypes.ETJson
+)
+
+// NewFieldType returns a FieldType with the type tp and unspecified length.
+func NewFieldType(tp byte) *FieldType {
+	return ptypes.NewFieldType(tp)
+}
+
+// IsTypeBlob returns a boolean indicating whether the tp is a blob type.
+func IsTypeBlob(tp byte) bool {
+	return ptypes.IsTypeBlob(tp)
+}
+
+// IsTypeChar returns a boolean indicating
+// whether the tp is the char type like a string type or a varchar type.
+func IsTypeChar(tp byte) bool {
+	return ptypes.IsTypeChar(tp)
+}
+
+// HasCharset checks if a field type has charset.
+func HasCharset(ft *FieldType) bool {
+	return ptypes.HasCharset(ft)
+}
+
+// TypeStr converts tp to a string.
+func TypeStr(tp byte) string {
+	return ptypes.TypeStr(tp)
+}
+
+// IsString returns a boolean indicating
+// whether the field type is a string type.
+func IsString(tp byte) bool {
+	return IsTypeChar(tp) || IsTypeBlob(tp) || tp == mysql.TypeVarString || tp == mysql.TypeVarchar
+}
+
+// SetBinChsClnFlag sets charset, collation as 'binary' and adds binaryFlag to FieldType.
+func SetBinChsClnFlag(ft *FieldType) {
+	ft.Charset = charset.CharsetBin
+	ft.Collate = charset.CollationBin
+	ft.Flag |= mysql.BinaryFlag
+}
+
+// IsTypeTemporal checks if a type is a temporal type.
+func IsTypeTemporal(tp byte) bool {
+	switch tp {
+	case mysql.TypeDuration, mysql.TypeDatetime, mysql.TypeTimestamp,
+		mysql.TypeDate, mysql.TypeNewDate:
+		return true
+	}
+	return false
+}
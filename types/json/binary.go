@@ -0,0 +1,374 @@
+package json
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// BinaryJSON represents a binary encoded JSON object.
+// It can be randomly accessed without deserialization.
+type BinaryJSON struct {
+	TypeCode TypeCode
+	Value    []byte
+}
+
+// String implements fmt.Stringer interface, the format is the same as the output of MySQL.
+func (bj BinaryJSON) String() string {
+	return string(bj.marshalTo(make([]byte, 0, len(bj.Value)*3/2)))
+}
+
+// Copy makes a copy of the BinaryJSON.
+func (bj BinaryJSON) Copy() BinaryJSON {
+	buf := make([]byte, len(bj.Value))
+	copy(buf, bj.Value)
+	return BinaryJSON{TypeCode: bj.TypeCode, Value: buf}
+}
+
+// GetInt64 gets the int64 value.
+func (bj BinaryJSON) GetInt64() int64 {
+	return int64(endian.Uint64(bj.Value))
+}
+
+// GetUint64 gets the uint64 value.
+func (bj BinaryJSON) GetUint64() uint64 {
+	return endian.Uint64(bj.Value)
+}
+
+// GetFloat64 gets the float64 value.
+func (bj BinaryJSON) GetFloat64() float64 {
+	return math.Float64frombits(bj.GetUint64())
+}
+
+// GetString gets the string value.
+func (bj BinaryJSON) GetString() []byte {
+	strLen, lenLen := binary.Uvarint(bj.Value)
+	return bj.Value[lenLen : lenLen+int(strLen)]
+}
+
+// GetElemCount gets the count of the elements of an object or an array.
+func (bj BinaryJSON) GetElemCount() int {
+	return int(endian.Uint32(bj.Value))
+}
+
+// IsNull checks whether the JSON is a JSON null literal.
+func (bj BinaryJSON) IsNull() bool {
+	return bj.TypeCode == TypeCodeLiteral && bj.Value[0] == LiteralNil
+}
+
+func (bj BinaryJSON) arrayGetElem(idx int) BinaryJSON {
+	return bj.valEntryGet(headerSize + idx*valEntrySize)
+}
+
+func (bj BinaryJSON) objectGetKey(i int) []byte {
+	keyOff := int(endian.Uint32(bj.Value[headerSize+i*keyEntrySize:]))
+	keyLen := int(endian.Uint16(bj.Value[headerSize+i*keyEntrySize+keyLenOff:]))
+	return bj.Value[keyOff : keyOff+keyLen]
+}
+
+func (bj BinaryJSON) objectGetVal(i int) BinaryJSON {
+	return bj.valEntryGet(headerSize + bj.GetElemCount()*keyEntrySize + i*valEntrySize)
+}
+
+func (bj BinaryJSON) valEntryGet(valEntryOff int) BinaryJSON {
+	tpCode := bj.Value[valEntryOff]
+	valOff := int(endian.Uint32(bj.Value[valEntryOff+valTypeSize:]))
+	switch tpCode {
+	case TypeCodeLiteral:
+		return BinaryJSON{TypeCode: TypeCodeLiteral, Value: bj.Value[valEntryOff+valTypeSize : valEntryOff+valTypeSize+1]}
+	case TypeCodeInt64, TypeCodeUint64, TypeCodeFloat64:
+		return BinaryJSON{TypeCode: tpCode, Value: bj.Value[valOff : valOff+numberSize]}
+	case TypeCodeString:
+		strLen, lenLen := binary.Uvarint(bj.Value[valOff:])
+		return BinaryJSON{TypeCode: tpCode, Value: bj.Value[valOff : valOff+lenLen+int(strLen)]}
+	}
+	dataSize := int(endian.Uint32(bj.Value[valOff+dataSizeOff:]))
+	return BinaryJSON{TypeCode: tpCode, Value: bj.Value[valOff : valOff+dataSize]}
+}
+
+// objectSearchKey searches the value of the key by binary search, the keys are ordered by compareKey.
+func (bj BinaryJSON) objectSearchKey(key []byte) (BinaryJSON, bool) {
+	elemCount := bj.GetElemCount()
+	idx := sort.Search(elemCount, func(i int) bool {
+		return compareKey(bj.objectGetKey(i), key) >= 0
+	})
+	if idx < elemCount && bytes.Equal(bj.objectGetKey(idx), key) {
+		return bj.objectGetVal(idx), true
+	}
+	return BinaryJSON{}, false
+}
+
+// compareKey orders the keys of an object by length first and then by bytes, which is the order used by MySQL.
+func compareKey(a, b []byte) int {
+	if len(a) != len(b) {
+		return len(a) - len(b)
+	}
+	return bytes.Compare(a, b)
+}
+
+func (bj BinaryJSON) marshalTo(buf []byte) []byte {
+	switch bj.TypeCode {
+	case TypeCodeString:
+		return marshalStringTo(buf, bj.GetString())
+	case TypeCodeLiteral:
+		switch bj.Value[0] {
+		case LiteralTrue:
+			return append(buf, "true"...)
+		case LiteralFalse:
+			return append(buf, "false"...)
+		}
+		return append(buf, "null"...)
+	case TypeCodeInt64:
+		return strconv.AppendInt(buf, bj.GetInt64(), 10)
+	case TypeCodeUint64:
+		return strconv.AppendUint(buf, bj.GetUint64(), 10)
+	case TypeCodeFloat64:
+		return marshalFloat64To(buf, bj.GetFloat64())
+	case TypeCodeArray:
+		buf = append(buf, '[')
+		for i := 0; i < bj.GetElemCount(); i++ {
+			if i != 0 {
+				buf = append(buf, ", "...)
+			}
+			buf = bj.arrayGetElem(i).marshalTo(buf)
+		}
+		return append(buf, ']')
+	case TypeCodeObject:
+		buf = append(buf, '{')
+		for i := 0; i < bj.GetElemCount(); i++ {
+			if i != 0 {
+				buf = append(buf, ", "...)
+			}
+			buf = marshalStringTo(buf, bj.objectGetKey(i))
+			buf = append(buf, ": "...)
+			buf = bj.objectGetVal(i).marshalTo(buf)
+		}
+		return append(buf, '}')
+	}
+	panic(fmt.Sprintf("unknown type code: %d", bj.TypeCode))
+}
+
+// marshalFloat64To formats the float like MySQL, an integral value keeps a ".0" suffix and a very large or
+// small value is in the scientific notation.
+func marshalFloat64To(buf []byte, f float64) []byte {
+	abs := math.Abs(f)
+	if abs != 0 && (abs < 1e-6 || abs >= 1e15) {
+		s := strconv.FormatFloat(f, 'e', -1, 64)
+		// Clean up e+15 to e15 and e-07 to e-7.
+		i := strings.IndexByte(s, 'e')
+		sign := ""
+		if s[i+1] == '-' {
+			sign = "-"
+		}
+		return append(buf, s[:i]+"e"+sign+strings.TrimLeft(s[i+2:], "0")...)
+	}
+	start := len(buf)
+	buf = strconv.AppendFloat(buf, f, 'f', -1, 64)
+	if bytes.IndexByte(buf[start:], '.') < 0 {
+		buf = append(buf, ".0"...)
+	}
+	return buf
+}
+
+func marshalStringTo(buf, s []byte) []byte {
+	buf = append(buf, '"')
+	for i := 0; i < len(s); {
+		c := s[i]
+		if c >= utf8.RuneSelf {
+			_, size := utf8.DecodeRune(s[i:])
+			buf = append(buf, s[i:i+size]...)
+			i += size
+			continue
+		}
+		switch c {
+		case '"', '\\':
+			buf = append(buf, '\\', c)
+		case '\b':
+			buf = append(buf, '\\', 'b')
+		case '\f':
+			buf = append(buf, '\\', 'f')
+		case '\n':
+			buf = append(buf, '\\', 'n')
+		case '\r':
+			buf = append(buf, '\\', 'r')
+		case '\t':
+			buf = append(buf, '\\', 't')
+		default:
+			if c < 0x20 {
+				buf = append(buf, fmt.Sprintf(`\u%04x`, c)...)
+			} else {
+				buf = append(buf, c)
+			}
+		}
+		i++
+	}
+	return append(buf, '"')
+}
+
+// ParseBinaryFromString parses a json from string.
+func ParseBinaryFromString(s string) (BinaryJSON, error) {
+	if len(s) == 0 {
+		return BinaryJSON{}, ErrInvalidJSONText.GenWithStackByArgs("The document is empty")
+	}
+	decoder := json.NewDecoder(bytes.NewReader([]byte(s)))
+	decoder.UseNumber()
+	var in interface{}
+	if err := decoder.Decode(&in); err != nil {
+		return BinaryJSON{}, ErrInvalidJSONText.GenWithStackByArgs(err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return BinaryJSON{}, ErrInvalidJSONText.GenWithStackByArgs("The document root must not be followed by other values.")
+	}
+	return CreateBinary(in), nil
+}
+
+// CreateBinary creates a BinaryJSON from a golang value, which is one of nil, bool, int64, uint64, float64,
+// string, json.Number, BinaryJSON and the slices and maps of them. It panics on the other types.
+func CreateBinary(in interface{}) BinaryJSON {
+	switch x := in.(type) {
+	case nil:
+		return BinaryJSON{TypeCode: TypeCodeLiteral, Value: []byte{LiteralNil}}
+	case bool:
+		if x {
+			return BinaryJSON{TypeCode: TypeCodeLiteral, Value: []byte{LiteralTrue}}
+		}
+		return BinaryJSON{TypeCode: TypeCodeLiteral, Value: []byte{LiteralFalse}}
+	case int64:
+		return BinaryJSON{TypeCode: TypeCodeInt64, Value: appendUint64(nil, uint64(x))}
+	case uint64:
+		return BinaryJSON{TypeCode: TypeCodeUint64, Value: appendUint64(nil, x)}
+	case float64:
+		return BinaryJSON{TypeCode: TypeCodeFloat64, Value: appendUint64(nil, math.Float64bits(x))}
+	case json.Number:
+		if i, err := x.Int64(); err == nil {
+			return CreateBinary(i)
+		}
+		if u, err := strconv.ParseUint(string(x), 10, 64); err == nil {
+			return CreateBinary(u)
+		}
+		f, _ := strconv.ParseFloat(string(x), 64)
+		return CreateBinary(f)
+	case string:
+		return BinaryJSON{TypeCode: TypeCodeString, Value: appendString(nil, x)}
+	case BinaryJSON:
+		return x
+	case []interface{}:
+		elems := make([]BinaryJSON, 0, len(x))
+		for _, elem := range x {
+			elems = append(elems, CreateBinary(elem))
+		}
+		return buildBinaryArray(elems)
+	case []BinaryJSON:
+		return buildBinaryArray(x)
+	case map[string]interface{}:
+		keys := make([][]byte, 0, len(x))
+		for key := range x {
+			keys = append(keys, []byte(key))
+		}
+		sort.Slice(keys, func(i, j int) bool { return compareKey(keys[i], keys[j]) < 0 })
+		elems := make([]BinaryJSON, 0, len(keys))
+		for _, key := range keys {
+			elems = append(elems, CreateBinary(x[string(key)]))
+		}
+		return buildBinaryObject(keys, elems)
+	}
+	panic(fmt.Sprintf("unknown type: %T", in))
+}
+
+func appendUint64(buf []byte, v uint64) []byte {
+	var b [numberSize]byte
+	endian.PutUint64(b[:], v)
+	return append(buf, b[:]...)
+}
+
+func appendUint32(buf []byte, v uint32) []byte {
+	var b [4]byte
+	endian.PutUint32(b[:], v)
+	return append(buf, b[:]...)
+}
+
+func appendString(buf []byte, v string) []byte {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(b[:], uint64(len(v)))
+	buf = append(buf, b[:n]...)
+	return append(buf, v...)
+}
+
+// buildBinaryArray builds an array from its elements.
+func buildBinaryArray(elems []BinaryJSON) BinaryJSON {
+	buf := make([]byte, headerSize+len(elems)*valEntrySize)
+	buf = buildBinaryElements(buf, headerSize, elems)
+	return BinaryJSON{TypeCode: TypeCodeArray, Value: buf}
+}
+
+// buildBinaryObject builds an object from its keys and values, the keys must be ordered by compareKey
+// and unique.
+func buildBinaryObject(keys [][]byte, elems []BinaryJSON) BinaryJSON {
+	buf := make([]byte, headerSize+len(elems)*(keyEntrySize+valEntrySize))
+	for i, key := range keys {
+		if len(key) > maxKeyLength {
+			key = key[:maxKeyLength]
+		}
+		endian.PutUint32(buf[headerSize+i*keyEntrySize:], uint32(len(buf)))
+		endian.PutUint16(buf[headerSize+i*keyEntrySize+keyLenOff:], uint16(len(key)))
+		buf = append(buf, key...)
+	}
+	buf = buildBinaryElements(buf, headerSize+len(elems)*keyEntrySize, elems)
+	return BinaryJSON{TypeCode: TypeCodeObject, Value: buf}
+}
+
+// buildBinaryElements fills the value entries starting at entryStart and appends the values, then sets the
+// header of the container.
+func buildBinaryElements(buf []byte, entryStart int, elems []BinaryJSON) []byte {
+	for i, elem := range elems {
+		entryOff := entryStart + i*valEntrySize
+		buf[entryOff] = elem.TypeCode
+		if elem.TypeCode == TypeCodeLiteral {
+			buf[entryOff+valTypeSize] = elem.Value[0]
+			continue
+		}
+		endian.PutUint32(buf[entryOff+valTypeSize:], uint32(len(buf)))
+		buf = append(buf, elem.Value...)
+	}
+	endian.PutUint32(buf, uint32(len(elems)))
+	endian.PutUint32(buf[dataSizeOff:], uint32(len(buf)))
+	return buf
+}
+
+// PeekBytesAsJSON trys to peek some bytes from b, which is a type code followed by the encoded value,
+// and returns the length of them.
+func PeekBytesAsJSON(b []byte) (n int, err error) {
+	if len(b) == 0 {
+		return 0, ErrInvalidJSONData.GenWithStackByArgs("peek", "empty data")
+	}
+	switch b[0] {
+	case TypeCodeLiteral:
+		n = 1
+	case TypeCodeInt64, TypeCodeUint64, TypeCodeFloat64:
+		n = numberSize
+	case TypeCodeString:
+		strLen, lenLen := binary.Uvarint(b[1:])
+		if lenLen <= 0 {
+			return 0, ErrInvalidJSONData.GenWithStackByArgs("peek", "invalid string length")
+		}
+		n = lenLen + int(strLen)
+	case TypeCodeObject, TypeCodeArray:
+		if len(b) < 1+headerSize {
+			return 0, ErrInvalidJSONData.GenWithStackByArgs("peek", "invalid container header")
+		}
+		n = int(endian.Uint32(b[1+dataSizeOff:]))
+	default:
+		return 0, ErrInvalidJSONData.GenWithStackByArgs("peek", fmt.Sprintf("unknown type code %d", b[0]))
+	}
+	if len(b) < 1+n {
+		return 0, ErrInvalidJSONData.GenWithStackByArgs("peek", "insufficient bytes")
+	}
+	return 1 + n, nil
+}
@@ -0,0 +1,436 @@
+package json
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"unicode/utf8"
+)
+
+// Type returns type of BinaryJSON as string.
+func (bj BinaryJSON) Type() string {
+	switch bj.TypeCode {
+	case TypeCodeObject:
+		return "OBJECT"
+	case TypeCodeArray:
+		return "ARRAY"
+	case TypeCodeLiteral:
+		if bj.Value[0] == LiteralNil {
+			return "NULL"
+		}
+		return "BOOLEAN"
+	case TypeCodeInt64:
+		return "INTEGER"
+	case TypeCodeUint64:
+		return "UNSIGNED INTEGER"
+	case TypeCodeFloat64:
+		return "DOUBLE"
+	case TypeCodeString:
+		return "STRING"
+	}
+	return "UNKNOWN"
+}
+
+// Unquote returns the string of a JSON string without quotes, or the JSON text of the other types.
+func (bj BinaryJSON) Unquote() string {
+	if bj.TypeCode == TypeCodeString {
+		return string(bj.GetString())
+	}
+	return bj.String()
+}
+
+// UnquoteString removes the quotes of a JSON string text and unescapes it, a string which is not quoted
+// is returned as it is.
+func UnquoteString(str string) (string, error) {
+	if len(str) < 2 || str[0] != '"' || str[len(str)-1] != '"' {
+		return str, nil
+	}
+	s := str[1 : len(str)-1]
+	buf := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			buf = append(buf, s[i])
+			continue
+		}
+		i++
+		if i == len(s) {
+			return "", ErrInvalidJSONText.GenWithStackByArgs("Missing a closing quotation mark in string")
+		}
+		switch s[i] {
+		case 'b':
+			buf = append(buf, '\b')
+		case 'f':
+			buf = append(buf, '\f')
+		case 'n':
+			buf = append(buf, '\n')
+		case 'r':
+			buf = append(buf, '\r')
+		case 't':
+			buf = append(buf, '\t')
+		case 'u':
+			if i+4 >= len(s) {
+				return "", ErrInvalidJSONText.GenWithStackByArgs("Incorrect hex digit after \\u escape in string")
+			}
+			r, err := strconv.ParseUint(s[i+1:i+5], 16, 32)
+			if err != nil {
+				return "", ErrInvalidJSONText.GenWithStackByArgs("Incorrect hex digit after \\u escape in string")
+			}
+			var char [utf8.UTFMax]byte
+			n := utf8.EncodeRune(char[:], rune(r))
+			buf = append(buf, char[:n]...)
+			i += 4
+		default:
+			// '\"', '\\', '\/' and the unknown escapes are the character itself.
+			buf = append(buf, s[i])
+		}
+	}
+	return string(buf), nil
+}
+
+// arrayElems returns the elements of an array.
+func (bj BinaryJSON) arrayElems() []BinaryJSON {
+	elems := make([]BinaryJSON, 0, bj.GetElemCount())
+	for i := 0; i < bj.GetElemCount(); i++ {
+		elems = append(elems, bj.arrayGetElem(i))
+	}
+	return elems
+}
+
+// objectEntries returns the keys and values of an object.
+func (bj BinaryJSON) objectEntries() ([][]byte, []BinaryJSON) {
+	keys := make([][]byte, 0, bj.GetElemCount())
+	elems := make([]BinaryJSON, 0, bj.GetElemCount())
+	for i := 0; i < bj.GetElemCount(); i++ {
+		keys = append(keys, bj.objectGetKey(i))
+		elems = append(elems, bj.objectGetVal(i))
+	}
+	return keys, elems
+}
+
+// Extract receives several path expressions as arguments, matches them in bj, and returns the matched
+// value, which is autowrapped as an array if there are several path expressions or any wildcard. found
+// is false if no path expression matches.
+func (bj BinaryJSON) Extract(pathExprList []PathExpression) (ret BinaryJSON, found bool) {
+	buf := make([]BinaryJSON, 0, 1)
+	for _, pathExpr := range pathExprList {
+		buf = bj.extractTo(buf, pathExpr)
+	}
+	if len(buf) == 0 {
+		return ret, false
+	}
+	if len(pathExprList) == 1 && !pathExprList[0].ContainsAnyAsterisk() {
+		return buf[0], true
+	}
+	return buildBinaryArray(buf), true
+}
+
+func (bj BinaryJSON) extractTo(buf []BinaryJSON, pathExpr PathExpression) []BinaryJSON {
+	if len(pathExpr.legs) == 0 {
+		return append(buf, bj)
+	}
+	currentLeg, subPathExpr := pathExpr.popOneLeg()
+	switch currentLeg.typ {
+	case pathLegIndex:
+		if bj.TypeCode != TypeCodeArray {
+			// A scalar or an object is treated as an array of itself.
+			if currentLeg.arrayIndex == 0 || currentLeg.arrayIndex == arrayWildcard {
+				buf = bj.extractTo(buf, subPathExpr)
+			}
+			return buf
+		}
+		elemCount := bj.GetElemCount()
+		if currentLeg.arrayIndex == arrayWildcard {
+			for i := 0; i < elemCount; i++ {
+				buf = bj.arrayGetElem(i).extractTo(buf, subPathExpr)
+			}
+		} else if currentLeg.arrayIndex < elemCount {
+			buf = bj.arrayGetElem(currentLeg.arrayIndex).extractTo(buf, subPathExpr)
+		}
+	case pathLegKey:
+		if bj.TypeCode != TypeCodeObject {
+			return buf
+		}
+		if currentLeg.dotKey == "*" {
+			for i := 0; i < bj.GetElemCount(); i++ {
+				buf = bj.objectGetVal(i).extractTo(buf, subPathExpr)
+			}
+		} else if child, ok := bj.objectSearchKey([]byte(currentLeg.dotKey)); ok {
+			buf = child.extractTo(buf, subPathExpr)
+		}
+	case pathLegDoubleAsterisk:
+		buf = bj.extractTo(buf, subPathExpr)
+		switch bj.TypeCode {
+		case TypeCodeArray:
+			for i := 0; i < bj.GetElemCount(); i++ {
+				buf = bj.arrayGetElem(i).extractTo(buf, pathExpr)
+			}
+		case TypeCodeObject:
+			for i := 0; i < bj.GetElemCount(); i++ {
+				buf = bj.objectGetVal(i).extractTo(buf, pathExpr)
+			}
+		}
+	}
+	return buf
+}
+
+// Modify modifies a JSON object by insert, replace or set.
+// All path expressions cannot contain * or ** wildcard.
+// If any error occurs, the input won't be changed.
+func (bj BinaryJSON) Modify(pathExprList []PathExpression, values []BinaryJSON, mt ModifyType) (BinaryJSON, error) {
+	if len(pathExprList) != len(values) {
+		return bj, ErrInvalidJSONData.GenWithStackByArgs("modify", "the counts of paths and values are not equal")
+	}
+	for _, pathExpr := range pathExprList {
+		if pathExpr.ContainsAnyAsterisk() {
+			return bj, ErrInvalidJSONPathWildcard
+		}
+	}
+	for i, pathExpr := range pathExprList {
+		bj = bj.modify(pathExpr, values[i], mt)
+	}
+	return bj, nil
+}
+
+func (bj BinaryJSON) modify(pathExpr PathExpression, newBj BinaryJSON, mt ModifyType) BinaryJSON {
+	if len(pathExpr.legs) == 0 {
+		if mt&ModifyReplace != 0 {
+			return newBj
+		}
+		return bj
+	}
+	currentLeg, subPathExpr := pathExpr.popOneLeg()
+	insertHere := len(subPathExpr.legs) == 0 && mt&ModifyInsert != 0
+	switch currentLeg.typ {
+	case pathLegIndex:
+		if bj.TypeCode != TypeCodeArray {
+			if currentLeg.arrayIndex == 0 {
+				return bj.modify(subPathExpr, newBj, mt)
+			}
+			if insertHere {
+				// The value is autowrapped as an array.
+				return buildBinaryArray([]BinaryJSON{bj, newBj})
+			}
+			return bj
+		}
+		elems := bj.arrayElems()
+		if currentLeg.arrayIndex < len(elems) {
+			elems[currentLeg.arrayIndex] = elems[currentLeg.arrayIndex].modify(subPathExpr, newBj, mt)
+			return buildBinaryArray(elems)
+		}
+		if insertHere {
+			return buildBinaryArray(append(elems, newBj))
+		}
+	case pathLegKey:
+		if bj.TypeCode != TypeCodeObject {
+			return bj
+		}
+		keys, elems := bj.objectEntries()
+		key := []byte(currentLeg.dotKey)
+		idx := sort.Search(len(keys), func(i int) bool { return compareKey(keys[i], key) >= 0 })
+		if idx < len(keys) && bytes.Equal(keys[idx], key) {
+			elems[idx] = elems[idx].modify(subPathExpr, newBj, mt)
+			return buildBinaryObject(keys, elems)
+		}
+		if insertHere {
+			keys = append(keys[:idx], append([][]byte{key}, keys[idx:]...)...)
+			elems = append(elems[:idx], append([]BinaryJSON{newBj}, elems[idx:]...)...)
+			return buildBinaryObject(keys, elems)
+		}
+	}
+	return bj
+}
+
+// Remove removes the elements indicated by pathExprList from JSON.
+func (bj BinaryJSON) Remove(pathExprList []PathExpression) (BinaryJSON, error) {
+	for _, pathExpr := range pathExprList {
+		if len(pathExpr.legs) == 0 {
+			return bj, ErrInvalidJSONPath.GenWithStackByArgs("$")
+		}
+		if pathExpr.ContainsAnyAsterisk() {
+			return bj, ErrInvalidJSONPathWildcard
+		}
+	}
+	for _, pathExpr := range pathExprList {
+		bj = bj.remove(pathExpr)
+	}
+	return bj, nil
+}
+
+func (bj BinaryJSON) remove(pathExpr PathExpression) BinaryJSON {
+	currentLeg, subPathExpr := pathExpr.popOneLeg()
+	last := len(subPathExpr.legs) == 0
+	switch currentLeg.typ {
+	case pathLegIndex:
+		if bj.TypeCode != TypeCodeArray {
+			if currentLeg.arrayIndex == 0 && !last {
+				return bj.remove(subPathExpr)
+			}
+			return bj
+		}
+		elems := bj.arrayElems()
+		if currentLeg.arrayIndex >= len(elems) {
+			return bj
+		}
+		if last {
+			elems = append(elems[:currentLeg.arrayIndex], elems[currentLeg.arrayIndex+1:]...)
+		} else {
+			elems[currentLeg.arrayIndex] = elems[currentLeg.arrayIndex].remove(subPathExpr)
+		}
+		return buildBinaryArray(elems)
+	case pathLegKey:
+		if bj.TypeCode != TypeCodeObject {
+			return bj
+		}
+		keys, elems := bj.objectEntries()
+		key := []byte(currentLeg.dotKey)
+		idx := sort.Search(len(keys), func(i int) bool { return compareKey(keys[i], key) >= 0 })
+		if idx == len(keys) || !bytes.Equal(keys[idx], key) {
+			return bj
+		}
+		if last {
+			keys = append(keys[:idx], keys[idx+1:]...)
+			elems = append(elems[:idx], elems[idx+1:]...)
+		} else {
+			elems[idx] = elems[idx].remove(subPathExpr)
+		}
+		return buildBinaryObject(keys, elems)
+	}
+	return bj
+}
+
+// ContainsBinary checks whether target is contained in obj. An object contains a target object if every key
+// of the target is in it and the value contains the value of the target. An array contains a target array if
+// every element of the target is contained in it, and contains a target non-array if any of its elements
+// contains the target. The other values contain the target only if they are equal.
+func ContainsBinary(obj, target BinaryJSON) bool {
+	switch obj.TypeCode {
+	case TypeCodeObject:
+		if target.TypeCode != TypeCodeObject {
+			return false
+		}
+		for i := 0; i < target.GetElemCount(); i++ {
+			val, ok := obj.objectSearchKey(target.objectGetKey(i))
+			if !ok || !ContainsBinary(val, target.objectGetVal(i)) {
+				return false
+			}
+		}
+		return true
+	case TypeCodeArray:
+		if target.TypeCode == TypeCodeArray {
+			for i := 0; i < target.GetElemCount(); i++ {
+				if !ContainsBinary(obj, target.arrayGetElem(i)) {
+					return false
+				}
+			}
+			return true
+		}
+		for i := 0; i < obj.GetElemCount(); i++ {
+			if ContainsBinary(obj.arrayGetElem(i), target) {
+				return true
+			}
+		}
+		return false
+	}
+	return CompareBinary(obj, target) == 0
+}
+
+// jsonTypePrecedences is the precedences of the types of JSON values in comparison, the numbers are equal
+// and JSON null is the smallest.
+var jsonTypePrecedences = map[string]int{
+	"BOOLEAN":          -7,
+	"ARRAY":            -8,
+	"OBJECT":           -9,
+	"STRING":           -10,
+	"INTEGER":          -11,
+	"UNSIGNED INTEGER": -11,
+	"DOUBLE":           -11,
+	"NULL":             -12,
+}
+
+// CompareBinary compares two binary json objects. Returns -1 if left < right,
+// 0 if left == right, else returns 1.
+func CompareBinary(left, right BinaryJSON) int {
+	precedence1 := jsonTypePrecedences[left.Type()]
+	precedence2 := jsonTypePrecedences[right.Type()]
+	if precedence1 != precedence2 {
+		return compareInt(precedence1, precedence2)
+	}
+	switch left.TypeCode {
+	case TypeCodeLiteral:
+		// JSON null equals to JSON null, and false is less than true.
+		return compareInt(int(right.Value[0]), int(left.Value[0]))
+	case TypeCodeInt64, TypeCodeUint64, TypeCodeFloat64:
+		return compareNumber(left, right)
+	case TypeCodeString:
+		return bytes.Compare(left.GetString(), right.GetString())
+	case TypeCodeArray:
+		leftCount, rightCount := left.GetElemCount(), right.GetElemCount()
+		for i := 0; i < leftCount && i < rightCount; i++ {
+			if cmp := CompareBinary(left.arrayGetElem(i), right.arrayGetElem(i)); cmp != 0 {
+				return cmp
+			}
+		}
+		return compareInt(leftCount, rightCount)
+	}
+	// Only equality is defined on two objects.
+	return bytes.Compare(left.Value, right.Value)
+}
+
+func compareNumber(left, right BinaryJSON) int {
+	switch {
+	case left.TypeCode == TypeCodeFloat64 || right.TypeCode == TypeCodeFloat64:
+		return compareFloat64PrecisionLoss(left.toFloat64(), right.toFloat64())
+	case left.TypeCode == TypeCodeInt64 && right.TypeCode == TypeCodeInt64:
+		l, r := left.GetInt64(), right.GetInt64()
+		if l < r {
+			return -1
+		} else if l > r {
+			return 1
+		}
+		return 0
+	case left.TypeCode == TypeCodeInt64 && left.GetInt64() < 0:
+		return -1
+	case right.TypeCode == TypeCodeInt64 && right.GetInt64() < 0:
+		return 1
+	}
+	// Both of them are non-negative, and the bits of a non-negative int64 are the same as its uint64.
+	l, r := left.GetUint64(), right.GetUint64()
+	if l < r {
+		return -1
+	} else if l > r {
+		return 1
+	}
+	return 0
+}
+
+func (bj BinaryJSON) toFloat64() float64 {
+	switch bj.TypeCode {
+	case TypeCodeInt64:
+		return float64(bj.GetInt64())
+	case TypeCodeUint64:
+		return float64(bj.GetUint64())
+	}
+	return bj.GetFloat64()
+}
+
+// floatEpsilon is the acceptable error quantity when comparing two float numbers.
+const floatEpsilon = 1.e-8
+
+// compareFloat64PrecisionLoss returns an integer comparing the float64 x to y, allowing precision loss.
+func compareFloat64PrecisionLoss(x, y float64) int {
+	if x-y < floatEpsilon && y-x < floatEpsilon {
+		return 0
+	} else if x-y < 0 {
+		return -1
+	}
+	return 1
+}
+
+func compareInt(x, y int) int {
+	if x < y {
+		return -1
+	} else if x > y {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,186 @@
+package json
+
+import (
+	"testing"
+)
+
+func mustParseBinary(t *testing.T, s string) BinaryJSON {
+	t.Helper()
+	bj, err := ParseBinaryFromString(s)
+	if err != nil {
+		t.Fatalf("ParseBinaryFromString(%q): %v", s, err)
+	}
+	return bj
+}
+
+func mustParsePaths(t *testing.T, paths ...string) []PathExpression {
+	t.Helper()
+	exprs := make([]PathExpression, 0, len(paths))
+	for _, p := range paths {
+		expr, err := ParseJSONPathExpr(p)
+		if err != nil {
+			t.Fatalf("ParseJSONPathExpr(%q): %v", p, err)
+		}
+		exprs = append(exprs, expr)
+	}
+	return exprs
+}
+
+func TestBinaryJSONString(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected string
+		tp       string
+	}{
+		{`{"b": 1, "a": [1, 2.5, "x", true, null], "aa": {}}`, `{"a": [1, 2.5, "x", true, null], "b": 1, "aa": {}}`, "OBJECT"},
+		{`[]`, `[]`, "ARRAY"},
+		{`"a\"bé"`, `"a\"bé"`, "STRING"},
+		{`-3`, `-3`, "INTEGER"},
+		{`18446744073709551615`, `18446744073709551615`, "UNSIGNED INTEGER"},
+		{`1.5e3`, `1500.0`, "DOUBLE"},
+		{`false`, `false`, "BOOLEAN"},
+		{`null`, `null`, "NULL"},
+	}
+	for _, tt := range tests {
+		bj := mustParseBinary(t, tt.s)
+		if s := bj.String(); s != tt.expected {
+			t.Fatalf("%s is formatted as %s, expected %s", tt.s, s, tt.expected)
+		}
+		if tp := bj.Type(); tp != tt.tp {
+			t.Fatalf("the type of %s is %s, expected %s", tt.s, tp, tt.tp)
+		}
+		// The binary form is restored by its string form.
+		if restored := mustParseBinary(t, bj.String()); CompareBinary(restored, bj) != 0 {
+			t.Fatalf("%s is restored to %s", bj, restored)
+		}
+	}
+	for _, s := range []string{`{"a": 1`, `[1, 2,]`, `abc`, ``} {
+		if _, err := ParseBinaryFromString(s); err == nil {
+			t.Fatalf("ParseBinaryFromString(%q) succeeds", s)
+		}
+	}
+	if s := mustParseBinary(t, `"a\tb"`).Unquote(); s != "a\tb" {
+		t.Fatalf("the unquoted string is %q", s)
+	}
+}
+
+func TestBinaryJSONExtract(t *testing.T) {
+	bj := mustParseBinary(t, `{"a": {"b": [1, {"c": 2}, 3]}, "d": "x", "e f": 4}`)
+	tests := []struct {
+		paths    []string
+		expected string
+	}{
+		{[]string{`$.a.b[1].c`}, `2`},
+		{[]string{`$.d`}, `"x"`},
+		{[]string{`$."e f"`}, `4`},
+		{[]string{`$.a.b[*]`}, `[1, {"c": 2}, 3]`},
+		{[]string{`$**.c`}, `[2]`},
+		{[]string{`$.d`, `$.a.b[2]`}, `["x", 3]`},
+		{[]string{`$.d`, `$.z`}, `["x"]`},
+		{[]string{`$`}, bj.String()},
+	}
+	for _, tt := range tests {
+		ret, found := bj.Extract(mustParsePaths(t, tt.paths...))
+		if !found || ret.String() != tt.expected {
+			t.Fatalf("extract %v = %s, %v, expected %s", tt.paths, ret, found, tt.expected)
+		}
+	}
+	for _, paths := range [][]string{{`$.z`}, {`$.a.b[5]`}, {`$.d.x`}} {
+		if ret, found := bj.Extract(mustParsePaths(t, paths...)); found {
+			t.Fatalf("extract %v = %s, expected nothing", paths, ret)
+		}
+	}
+	for _, p := range []string{`a`, `$.`, `$[a]`, `$.a[`, `$**`} {
+		if _, err := ParseJSONPathExpr(p); err == nil {
+			t.Fatalf("ParseJSONPathExpr(%q) succeeds", p)
+		}
+	}
+}
+
+func TestBinaryJSONModify(t *testing.T) {
+	tests := []struct {
+		mt       ModifyType
+		path     string
+		value    string
+		expected string
+	}{
+		{ModifySet, `$.a`, `10`, `{"a": 10, "b": [1, 2]}`},
+		{ModifySet, `$.c`, `true`, `{"a": 1, "b": [1, 2], "c": true}`},
+		{ModifyInsert, `$.a`, `10`, `{"a": 1, "b": [1, 2]}`},
+		{ModifyInsert, `$.c`, `"x"`, `{"a": 1, "b": [1, 2], "c": "x"}`},
+		{ModifyInsert, `$.b[5]`, `3`, `{"a": 1, "b": [1, 2, 3]}`},
+		{ModifyReplace, `$.a`, `null`, `{"a": null, "b": [1, 2]}`},
+		{ModifyReplace, `$.c`, `1`, `{"a": 1, "b": [1, 2]}`},
+		{ModifyReplace, `$.b[0]`, `[0]`, `{"a": 1, "b": [[0], 2]}`},
+	}
+	for _, tt := range tests {
+		bj := mustParseBinary(t, `{"a": 1, "b": [1, 2]}`)
+		ret, err := bj.Modify(mustParsePaths(t, tt.path), []BinaryJSON{mustParseBinary(t, tt.value)}, tt.mt)
+		if err != nil {
+			t.Fatalf("modify %s by %s: %v", tt.path, tt.value, err)
+		}
+		if ret.String() != tt.expected {
+			t.Fatalf("modify %s by %s = %s, expected %s", tt.path, tt.value, ret, tt.expected)
+		}
+		// The original one is unchanged.
+		if s := bj.String(); s != `{"a": 1, "b": [1, 2]}` {
+			t.Fatalf("the original JSON is changed to %s", s)
+		}
+	}
+	bj := mustParseBinary(t, `{"a": 1, "b": [1, 2]}`)
+	if _, err := bj.Modify(mustParsePaths(t, `$.b[*]`), []BinaryJSON{CreateBinary(int64(1))}, ModifySet); err == nil {
+		t.Fatal("modify by a path with a wildcard succeeds")
+	}
+
+	ret, err := bj.Remove(mustParsePaths(t, `$.b[0]`, `$.z`))
+	if err != nil || ret.String() != `{"a": 1, "b": [2]}` {
+		t.Fatalf("remove = %s, %v", ret, err)
+	}
+	if _, err = bj.Remove(mustParsePaths(t, `$`)); err == nil {
+		t.Fatal("remove the root succeeds")
+	}
+}
+
+func TestBinaryJSONContains(t *testing.T) {
+	tests := []struct {
+		obj      string
+		target   string
+		expected bool
+	}{
+		{`{"a": 1, "b": [1, 2, {"c": 3}]}`, `{"a": 1}`, true},
+		{`{"a": 1, "b": [1, 2, {"c": 3}]}`, `{"b": [2, {"c": 3}]}`, true},
+		{`{"a": 1, "b": [1, 2, {"c": 3}]}`, `{"a": 2}`, false},
+		{`[1, 2, [3, 4]]`, `[3, 4]`, true},
+		{`[1, 2, [3, 4]]`, `3`, true},
+		{`[1, 2, [3, 4]]`, `[1, 5]`, false},
+		{`1`, `1`, true},
+		{`1`, `[1]`, false},
+		{`"a"`, `"a"`, true},
+	}
+	for _, tt := range tests {
+		if got := ContainsBinary(mustParseBinary(t, tt.obj), mustParseBinary(t, tt.target)); got != tt.expected {
+			t.Fatalf("%s contains %s: %v, expected %v", tt.obj, tt.target, got, tt.expected)
+		}
+	}
+}
+
+func TestCompareBinary(t *testing.T) {
+	// The values are in ascending order.
+	ordered := []string{`null`, `-1.5`, `-1`, `0`, `1`, `1.5`, `18446744073709551615`, `""`, `"a"`, `"b"`, `{"a": 1}`,
+		`[]`, `[1]`, `[1, 2]`, `[2]`, `false`, `true`}
+	for i := range ordered {
+		for j := range ordered {
+			left, right := mustParseBinary(t, ordered[i]), mustParseBinary(t, ordered[j])
+			expected := compareInt(i, j)
+			if got := CompareBinary(left, right); got != expected {
+				t.Fatalf("compare %s with %s = %d, expected %d", left, right, got, expected)
+			}
+		}
+	}
+	if CompareBinary(mustParseBinary(t, `1`), mustParseBinary(t, `1.0`)) != 0 {
+		t.Fatal("1 isn't equal to 1.0")
+	}
+	if CompareBinary(mustParseBinary(t, `{"a": 1, "b": 2}`), mustParseBinary(t, `{"b": 2, "a": 1}`)) != 0 {
+		t.Fatal("the objects of the same keys and values aren't equal")
+	}
+}
@@ -0,0 +1,180 @@
+package json
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// pathLegType is the type of a leg of a path expression.
+type pathLegType byte
+
+const (
+	// pathLegKey indicates the path leg is '.key', '."key"' or '.*'.
+	pathLegKey pathLegType = 0x01
+	// pathLegIndex indicates the path leg is '[n]' or '[*]'.
+	pathLegIndex pathLegType = 0x02
+	// pathLegDoubleAsterisk indicates the path leg is '**'.
+	pathLegDoubleAsterisk pathLegType = 0x03
+)
+
+// pathLeg is only used by PathExpression.
+type pathLeg struct {
+	typ        pathLegType
+	arrayIndex int    // arrayWildcard for '[*]'.
+	dotKey     string // "*" for '.*'.
+}
+
+// PathExpression is for JSON path expression, like '$.a[1].b', '$**.c' or '$.*'.
+// See https://dev.mysql.com/doc/refman/5.7/en/json-path-syntax.html.
+type PathExpression struct {
+	legs     []pathLeg
+	asterisk bool
+}
+
+// ContainsAnyAsterisk returns true if the path expression contains any of '*', '[*]' and '**'.
+func (pe PathExpression) ContainsAnyAsterisk() bool {
+	return pe.asterisk
+}
+
+// popOneLeg returns the first leg and the expression of the remaining legs.
+func (pe PathExpression) popOneLeg() (pathLeg, PathExpression) {
+	return pe.legs[0], PathExpression{legs: pe.legs[1:], asterisk: pe.asterisk}
+}
+
+// ParseJSONPathExpr parses a JSON path expression. An error is returned on the invalid expression.
+func ParseJSONPathExpr(pathExpr string) (PathExpression, error) {
+	p := &pathParser{s: pathExpr}
+	p.skipSpaces()
+	if !p.consume('$') {
+		return PathExpression{}, p.err()
+	}
+	var pe PathExpression
+	for {
+		p.skipSpaces()
+		if p.eof() {
+			break
+		}
+		leg, ok := p.parseLeg()
+		if !ok {
+			return PathExpression{}, p.err()
+		}
+		if leg.typ == pathLegDoubleAsterisk || leg.arrayIndex == arrayWildcard || leg.dotKey == "*" {
+			pe.asterisk = true
+		}
+		pe.legs = append(pe.legs, leg)
+	}
+	if n := len(pe.legs); n > 0 && pe.legs[n-1].typ == pathLegDoubleAsterisk {
+		// '**' must be followed by another leg.
+		return PathExpression{}, p.err()
+	}
+	return pe, nil
+}
+
+type pathParser struct {
+	s   string
+	pos int
+}
+
+func (p *pathParser) eof() bool {
+	return p.pos >= len(p.s)
+}
+
+func (p *pathParser) err() error {
+	return ErrInvalidJSONPath.GenWithStackByArgs(p.s)
+}
+
+func (p *pathParser) skipSpaces() {
+	for !p.eof() && unicode.IsSpace(rune(p.s[p.pos])) {
+		p.pos++
+	}
+}
+
+func (p *pathParser) consume(c byte) bool {
+	if !p.eof() && p.s[p.pos] == c {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *pathParser) parseLeg() (pathLeg, bool) {
+	switch {
+	case p.consume('.'):
+		return p.parseKey()
+	case p.consume('['):
+		return p.parseIndex()
+	case strings.HasPrefix(p.s[p.pos:], "**"):
+		p.pos += 2
+		return pathLeg{typ: pathLegDoubleAsterisk}, true
+	}
+	return pathLeg{}, false
+}
+
+func (p *pathParser) parseKey() (pathLeg, bool) {
+	p.skipSpaces()
+	if p.consume('*') {
+		return pathLeg{typ: pathLegKey, dotKey: "*"}, true
+	}
+	if p.consume('"') {
+		// The quoted key is a JSON string.
+		start := p.pos - 1
+		for !p.eof() && p.s[p.pos] != '"' {
+			if p.s[p.pos] == '\\' {
+				p.pos++
+			}
+			p.pos++
+		}
+		if !p.consume('"') {
+			return pathLeg{}, false
+		}
+		var key string
+		if err := json.Unmarshal([]byte(p.s[start:p.pos]), &key); err != nil {
+			return pathLeg{}, false
+		}
+		return pathLeg{typ: pathLegKey, dotKey: key}, true
+	}
+	start := p.pos
+	for !p.eof() {
+		r := rune(p.s[p.pos])
+		if r == '.' || r == '[' || r == '*' || unicode.IsSpace(r) {
+			break
+		}
+		p.pos++
+	}
+	key := p.s[start:p.pos]
+	if len(key) == 0 || !isIdentifier(key) {
+		return pathLeg{}, false
+	}
+	return pathLeg{typ: pathLegKey, dotKey: key}, true
+}
+
+// isIdentifier checks whether the unquoted key is an ECMAScript identifier.
+func isIdentifier(key string) bool {
+	for i, r := range key {
+		if r == '_' || r == '$' || unicode.IsLetter(r) || r >= 0x80 || (i > 0 && unicode.IsDigit(r)) {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+func (p *pathParser) parseIndex() (pathLeg, bool) {
+	p.skipSpaces()
+	leg := pathLeg{typ: pathLegIndex, arrayIndex: arrayWildcard}
+	if !p.consume('*') {
+		start := p.pos
+		for !p.eof() && p.s[p.pos] >= '0' && p.s[p.pos] <= '9' {
+			p.pos++
+		}
+		idx, err := strconv.Atoi(p.s[start:p.pos])
+		if err != nil {
+			return pathLeg{}, false
+		}
+		leg.arrayIndex = idx
+	}
+	p.skipSpaces()
+	return leg, p.consume(']')
+}
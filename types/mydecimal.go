@@ -0,0 +1,470 @@
+package types
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ErrBadNumber is returned when a string can not be parsed into a number.
+var ErrBadNumber = errors.New("bad number")
+
+var (
+	bigTen  = big.NewInt(10)
+	bigZero = big.NewInt(0)
+)
+
+// MyDecimal is an exact fixed-point decimal number, its value is unscaled / 10^frac.
+type MyDecimal struct {
+	unscaled big.Int
+	frac     int
+}
+
+// NewDecFromInt creates a MyDecimal from int.
+func NewDecFromInt(i int64) *MyDecimal {
+	return new(MyDecimal).FromInt(i)
+}
+
+// NewDecFromUint creates a MyDecimal from uint.
+func NewDecFromUint(i uint64) *MyDecimal {
+	return new(MyDecimal).FromUint(i)
+}
+
+// NewDecFromFloatForTest creates a MyDecimal from float, as it returns no error, it should only be used in test.
+func NewDecFromFloatForTest(f float64) *MyDecimal {
+	dec := new(MyDecimal)
+	_ = dec.FromFloat64(f)
+	return dec
+}
+
+// NewDecFromStringForTest creates a MyDecimal from string, as it returns no error, it should only be used in test.
+func NewDecFromStringForTest(s string) *MyDecimal {
+	dec := new(MyDecimal)
+	_ = dec.FromString([]byte(s))
+	return dec
+}
+
+// FromInt sets the decimal value from int64.
+func (d *MyDecimal) FromInt(i int64) *MyDecimal {
+	d.unscaled.SetInt64(i)
+	d.frac = 0
+	return d
+}
+
+// FromUint sets the decimal value from uint64.
+func (d *MyDecimal) FromUint(i uint64) *MyDecimal {
+	d.unscaled.SetUint64(i)
+	d.frac = 0
+	return d
+}
+
+// FromFloat64 creates a decimal from float64 value.
+func (d *MyDecimal) FromFloat64(f float64) error {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return ErrBadNumber
+	}
+	err := d.FromString(strconv.AppendFloat(nil, f, 'g', -1, 64))
+	// A float is inexact anyway, the digits of a tiny one beyond the scale are dropped silently.
+	if err == ErrTruncated {
+		err = nil
+	}
+	return err
+}
+
+// FromString parses decimal from string, an exponent part like "1.5e3" is accepted. A value with more than
+// MaxDecimalWidth integer digits is clipped to the max decimal with ErrOverflow, the fraction digits beyond
+// MaxDecimalScale are rounded with ErrTruncated.
+func (d *MyDecimal) FromString(str []byte) error {
+	s := strings.TrimSpace(string(str))
+	neg := false
+	if len(s) > 0 && (s[0] == '-' || s[0] == '+') {
+		neg = s[0] == '-'
+		s = s[1:]
+	}
+	exp := 0
+	if i := strings.IndexAny(s, "eE"); i >= 0 {
+		e, err := strconv.Atoi(s[i+1:])
+		if numErr, ok := err.(*strconv.NumError); ok && numErr.Err == strconv.ErrRange {
+			// Any exponent out of the int range is far beyond the width of a decimal.
+			e, err = maxExponent, nil
+			if strings.HasPrefix(s[i+1:], "-") {
+				e = -maxExponent
+			}
+		}
+		if err != nil {
+			return ErrBadNumber
+		}
+		exp = e
+		s = s[:i]
+	}
+	intPart, fracPart := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		intPart, fracPart = s[:i], s[i+1:]
+	}
+	if len(intPart)+len(fracPart) == 0 {
+		return ErrBadNumber
+	}
+	digits := intPart + fracPart
+	for i := 0; i < len(digits); i++ {
+		if digits[i] < '0' || digits[i] > '9' {
+			return ErrBadNumber
+		}
+	}
+	if exp > maxExponent {
+		exp = maxExponent
+	} else if exp < -maxExponent {
+		exp = -maxExponent
+	}
+	frac := len(fracPart) - exp
+	digits = strings.TrimLeft(digits, "0")
+	if digits == "" {
+		d.unscaled.SetInt64(0)
+		d.frac = minInt(maxInt(frac, 0), MaxDecimalScale)
+		return nil
+	}
+	if len(digits)-frac > MaxDecimalWidth {
+		d.setMax(neg)
+		return ErrOverflow
+	}
+	var err error
+	if frac > MaxDecimalScale {
+		err = ErrTruncated
+		// A value below half of the least fraction digit is rounded to zero, so the digits are never
+		// scaled by more than their own length.
+		if frac-MaxDecimalScale > len(digits) {
+			digits, frac = "0", MaxDecimalScale
+		}
+	}
+	d.unscaled.SetString(digits, 10)
+	d.frac = frac
+	if frac < 0 {
+		d.unscaled.Mul(&d.unscaled, pow10(-frac))
+		d.frac = 0
+	} else if frac > MaxDecimalScale {
+		d.Rescale(MaxDecimalScale)
+	}
+	if neg {
+		d.unscaled.Neg(&d.unscaled)
+	}
+	return err
+}
+
+// setMax sets the decimal to the max value with MaxDecimalWidth integer digits, or the min value if neg.
+func (d *MyDecimal) setMax(neg bool) {
+	d.unscaled.Sub(pow10(MaxDecimalWidth), big.NewInt(1))
+	d.frac = 0
+	if neg {
+		d.unscaled.Neg(&d.unscaled)
+	}
+}
+
+// String returns the decimal string representation, keeping its fraction digits.
+func (d *MyDecimal) String() string {
+	s := new(big.Int).Abs(&d.unscaled).String()
+	if d.frac > 0 {
+		if len(s) <= d.frac {
+			s = strings.Repeat("0", d.frac-len(s)+1) + s
+		}
+		s = s[:len(s)-d.frac] + "." + s[len(s)-d.frac:]
+	}
+	if d.unscaled.Sign() < 0 {
+		s = "-" + s
+	}
+	return s
+}
+
+// ToString converts decimal to its printable string representation.
+func (d *MyDecimal) ToString() []byte {
+	return []byte(d.String())
+}
+
+// ToInt returns the integer part of the decimal, it returns ErrOverflow if it doesn't fit int64.
+func (d *MyDecimal) ToInt() (int64, error) {
+	i := d.intPart()
+	if !i.IsInt64() {
+		if i.Sign() < 0 {
+			return math.MinInt64, ErrOverflow
+		}
+		return math.MaxInt64, ErrOverflow
+	}
+	return i.Int64(), nil
+}
+
+// ToUint returns the integer part of the decimal as uint64.
+func (d *MyDecimal) ToUint() (uint64, error) {
+	i := d.intPart()
+	if i.Sign() < 0 {
+		return 0, ErrOverflow
+	}
+	if !i.IsUint64() {
+		return math.MaxUint64, ErrOverflow
+	}
+	return i.Uint64(), nil
+}
+
+func (d *MyDecimal) intPart() *big.Int {
+	return new(big.Int).Quo(&d.unscaled, pow10(d.frac))
+}
+
+// ToFloat64 converts decimal to float64 value.
+func (d *MyDecimal) ToFloat64() (float64, error) {
+	return strconv.ParseFloat(d.String(), 64)
+}
+
+// GetDigitsFrac returns the number of fraction digits.
+func (d *MyDecimal) GetDigitsFrac() int {
+	return d.frac
+}
+
+// IsNegative returns whether a decimal is negative.
+func (d *MyDecimal) IsNegative() bool {
+	return d.unscaled.Sign() < 0
+}
+
+// IsZero checks whether it's a zero decimal.
+func (d *MyDecimal) IsZero() bool {
+	return d.unscaled.Sign() == 0
+}
+
+// Copy returns a deep copy of d.
+func (d *MyDecimal) Copy() *MyDecimal {
+	dst := &MyDecimal{frac: d.frac}
+	dst.unscaled.Set(&d.unscaled)
+	return dst
+}
+
+// Compare compares one decimal to another, returns -1/0/1.
+func (d *MyDecimal) Compare(to *MyDecimal) int {
+	a, b := &d.unscaled, &to.unscaled
+	if d.frac != to.frac {
+		frac := maxInt(d.frac, to.frac)
+		a = d.scaledTo(frac)
+		b = to.scaledTo(frac)
+	}
+	return a.Cmp(b)
+}
+
+// scaledTo returns the unscaled value with frac fraction digits, frac must be no less than d.frac.
+func (d *MyDecimal) scaledTo(frac int) *big.Int {
+	return new(big.Int).Mul(&d.unscaled, pow10(frac-d.frac))
+}
+
+// Scientific returns the decimal in normalized scientific form, its absolute value is 0.digits * 10^exp.
+// digits has no leading or trailing zeros and is empty for zero.
+func (d *MyDecimal) Scientific() (neg bool, digits string, exp int) {
+	if d.IsZero() {
+		return false, "", 0
+	}
+	s := new(big.Int).Abs(&d.unscaled).String()
+	exp = len(s) - d.frac
+	digits = strings.TrimRight(s, "0")
+	return d.IsNegative(), digits, exp
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// Rescale changes the number of fraction digits to frac, rounding half away from zero when digits are dropped.
+// frac is clamped to [0, MaxDecimalScale].
+func (d *MyDecimal) Rescale(frac int) *MyDecimal {
+	frac = minInt(maxInt(frac, 0), MaxDecimalScale)
+	switch {
+	case frac > d.frac:
+		d.unscaled.Mul(&d.unscaled, pow10(frac-d.frac))
+	case frac < d.frac:
+		divisor := pow10(d.frac - frac)
+		q, r := new(big.Int).QuoRem(&d.unscaled, divisor, new(big.Int))
+		// round half away from zero
+		r.Abs(r).Mul(r, big.NewInt(2))
+		if r.Cmp(divisor) >= 0 {
+			if d.unscaled.Sign() < 0 {
+				q.Sub(q, big.NewInt(1))
+			} else {
+				q.Add(q, big.NewInt(1))
+			}
+		}
+		d.unscaled.Set(q)
+	}
+	d.frac = frac
+	return d
+}
+
+// AppendBinary appends the compact binary form of the decimal to b, it's not memcomparable
+// and is used to hold the decimal in memory, such as in chunk columns. The fraction digits
+// never exceed MaxDecimalScale, so they fit in a byte.
+func (d *MyDecimal) AppendBinary(b []byte) []byte {
+	sign := byte(0)
+	if d.unscaled.Sign() < 0 {
+		sign = 1
+	}
+	b = append(b, byte(d.frac), sign)
+	return append(b, new(big.Int).Abs(&d.unscaled).Bytes()...)
+}
+
+// FromBinary restores the decimal from the binary form produced by AppendBinary.
+func (d *MyDecimal) FromBinary(b []byte) error {
+	if len(b) < 2 || b[0] > MaxDecimalScale {
+		return ErrBadNumber
+	}
+	d.frac = int(b[0])
+	d.unscaled.SetBytes(b[2:])
+	if b[1] == 1 {
+		d.unscaled.Neg(&d.unscaled)
+	}
+	return nil
+}
+
+const (
+	// maxExponent bounds the exponent of a decimal string, it's far beyond MaxDecimalWidth and keeps the
+	// digit arithmetic from overflowing.
+	maxExponent = math.MaxInt32
+	// DivFracIncr is the number of fraction digits a division adds to the dividend.
+	DivFracIncr = 4
+	// MaxDecimalScale is the maximum number of fraction digits of a decimal.
+	MaxDecimalScale = 30
+	// MaxDecimalWidth is the maximum number of digits of a decimal.
+	MaxDecimalWidth = 65
+)
+
+// ErrDivByZero is returned when a decimal is divided by zero.
+var ErrDivByZero = errors.New("division by zero")
+
+// DecimalAdd adds two decimals, sets the result to 'to'.
+func DecimalAdd(from1, from2, to *MyDecimal) error {
+	frac := maxInt(from1.frac, from2.frac)
+	a, b := from1.scaledTo(frac), from2.scaledTo(frac)
+	to.unscaled.Add(a, b)
+	to.frac = frac
+	return to.checkWidth()
+}
+
+// DecimalSub subs one decimal from another, sets the result to 'to'.
+func DecimalSub(from1, from2, to *MyDecimal) error {
+	frac := maxInt(from1.frac, from2.frac)
+	a, b := from1.scaledTo(frac), from2.scaledTo(frac)
+	to.unscaled.Sub(a, b)
+	to.frac = frac
+	return to.checkWidth()
+}
+
+// DecimalMul multiplies two decimals, the fraction digits of the result are the sum of the operands'
+// and are rounded to MaxDecimalScale.
+func DecimalMul(from1, from2, to *MyDecimal) error {
+	frac := from1.frac + from2.frac
+	to.unscaled.Mul(&from1.unscaled, &from2.unscaled)
+	to.frac = frac
+	if frac > MaxDecimalScale {
+		to.Rescale(MaxDecimalScale)
+	}
+	return to.checkWidth()
+}
+
+// DecimalDiv divides one decimal by another, the result has fracIncr more fraction digits than from1
+// and is rounded half away from zero.
+func DecimalDiv(from1, from2, to *MyDecimal, fracIncr int) error {
+	if from2.IsZero() {
+		return ErrDivByZero
+	}
+	frac := from1.frac + fracIncr
+	if frac > MaxDecimalScale {
+		frac = MaxDecimalScale
+	}
+	// Computes from1 / from2 with one more fraction digit to round, the exponent is always positive as frac >= from1.frac.
+	q := new(big.Int).Mul(&from1.unscaled, pow10(frac+1-from1.frac+from2.frac))
+	q.Quo(q, &from2.unscaled)
+	to.unscaled.Set(q)
+	to.frac = frac + 1
+	to.Rescale(frac)
+	return to.checkWidth()
+}
+
+// DecimalMod does modulo of two decimals, the sign of the result is the sign of from1.
+func DecimalMod(from1, from2, to *MyDecimal) error {
+	if from2.IsZero() {
+		return ErrDivByZero
+	}
+	frac := maxInt(from1.frac, from2.frac)
+	a, b := from1.scaledTo(frac), from2.scaledTo(frac)
+	to.unscaled.Rem(a, b)
+	to.frac = frac
+	return to.checkWidth()
+}
+
+// Round rounds the decimal to frac fraction digits half away from zero, frac may be negative.
+func (d *MyDecimal) Round(to *MyDecimal, frac int) {
+	tmp := d.Copy()
+	if frac >= 0 {
+		*to = *tmp.Rescale(frac)
+		return
+	}
+	if -frac > MaxDecimalWidth {
+		// All the digits of a decimal are rounded away.
+		*to = MyDecimal{}
+		return
+	}
+	tmp.frac -= frac
+	tmp.Rescale(0)
+	tmp.unscaled.Mul(&tmp.unscaled, pow10(-frac))
+	*to = *tmp
+}
+
+// Floor sets to the largest integer no greater than d.
+func (d *MyDecimal) Floor(to *MyDecimal) {
+	q := new(big.Int).Div(&d.unscaled, pow10(d.frac))
+	to.unscaled.Set(q)
+	to.frac = 0
+}
+
+// Ceil sets to the smallest integer no less than d.
+func (d *MyDecimal) Ceil(to *MyDecimal) {
+	q, m := new(big.Int).DivMod(&d.unscaled, pow10(d.frac), new(big.Int))
+	if m.Sign() != 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	to.unscaled.Set(q)
+	to.frac = 0
+}
+
+// Neg returns the negation of d.
+func (d *MyDecimal) Neg() *MyDecimal {
+	dst := d.Copy()
+	dst.unscaled.Neg(&dst.unscaled)
+	return dst
+}
+
+// checkWidth returns ErrOverflow if the integer digits exceed MaxDecimalWidth.
+func (d *MyDecimal) checkWidth() error {
+	digits := len(new(big.Int).Abs(&d.unscaled).String())
+	if digits-d.frac > MaxDecimalWidth {
+		return ErrOverflow
+	}
+	return nil
+}
+
+// DecimalIntDiv divides one decimal by another and truncates the quotient toward zero, it's used by the DIV operator.
+func DecimalIntDiv(from1, from2, to *MyDecimal) error {
+	if from2.IsZero() {
+		return ErrDivByZero
+	}
+	frac := maxInt(from1.frac, from2.frac)
+	to.unscaled.Quo(from1.scaledTo(frac), from2.scaledTo(frac))
+	to.frac = 0
+	return to.checkWidth()
+}
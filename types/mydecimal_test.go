@@ -0,0 +1,130 @@
+package types
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pingcap/parser/mysql"
+	"grant-db/sessionctx/stmtctx"
+)
+
+func TestDecimalFromString(t *testing.T) {
+	maxDec := strings.Repeat("9", MaxDecimalWidth)
+	tests := []struct {
+		s        string
+		expected string
+		err      error
+	}{
+		{"1.5e3", "1500", nil},
+		{"-0.00125e2", "-0.125", nil},
+		{"12.50", "12.50", nil},
+		{"0e-99999999", "0.000000000000000000000000000000", nil},
+		{"0e999999999", "0", nil},
+		{"1e-30", "0.000000000000000000000000000001", nil},
+		{"5e-31", "0.000000000000000000000000000001", ErrTruncated},
+		{"4e-31", "0.000000000000000000000000000000", ErrTruncated},
+		{"-15e-31", "-0.000000000000000000000000000002", ErrTruncated},
+		{"1e-99999999", "0.000000000000000000000000000000", ErrTruncated},
+		{"-1e-99999999999999999999", "0.000000000000000000000000000000", ErrTruncated},
+		{"1e64", "1" + strings.Repeat("0", 64), nil},
+		{"1e65", maxDec, ErrOverflow},
+		{"1e999999999", maxDec, ErrOverflow},
+		{"-1e99999999999999999999", "-" + maxDec, ErrOverflow},
+		{"0.01e66", "1" + strings.Repeat("0", 64), nil},
+		{"1e", "", ErrBadNumber},
+		{"1ex", "", ErrBadNumber},
+		{".", "", ErrBadNumber},
+	}
+	for _, tt := range tests {
+		dec := new(MyDecimal)
+		err := dec.FromString([]byte(tt.s))
+		if err != tt.err {
+			t.Fatalf("FromString(%q) returns %v, expected %v", tt.s, err, tt.err)
+		}
+		if err != ErrBadNumber && dec.String() != tt.expected {
+			t.Fatalf("FromString(%q) = %s, expected %s", tt.s, dec, tt.expected)
+		}
+		if dec.GetDigitsFrac() > MaxDecimalScale {
+			t.Fatalf("FromString(%q) has %d fraction digits", tt.s, dec.GetDigitsFrac())
+		}
+	}
+}
+
+func TestDecimalRescaleAndRound(t *testing.T) {
+	dec := NewDecFromStringForTest("1.25")
+	if s := dec.Copy().Rescale(999999999).String(); s != "1.250000000000000000000000000000" {
+		t.Fatalf("Rescale(999999999) = %s", s)
+	}
+	if s := dec.Copy().Rescale(-5).String(); s != "1" {
+		t.Fatalf("Rescale(-5) = %s", s)
+	}
+	tests := []struct {
+		s        string
+		frac     int
+		expected string
+	}{
+		{"1.25", 1, "1.3"},
+		{"-1.25", 1, "-1.3"},
+		{"1250", -2, "1300"},
+		{"1250", -4, "0"},
+		{"1250", -999999999, "0"},
+		{"1250", 999999999, "1250.000000000000000000000000000000"},
+	}
+	for _, tt := range tests {
+		to := new(MyDecimal)
+		NewDecFromStringForTest(tt.s).Round(to, tt.frac)
+		if to.String() != tt.expected {
+			t.Fatalf("Round(%s, %d) = %s, expected %s", tt.s, tt.frac, to, tt.expected)
+		}
+	}
+}
+
+func TestDecimalBinary(t *testing.T) {
+	for _, s := range []string{"0", "-1.5", "1e-30", "-" + strings.Repeat("9", MaxDecimalWidth)} {
+		dec := NewDecFromStringForTest(s)
+		restored := new(MyDecimal)
+		if err := restored.FromBinary(dec.AppendBinary(nil)); err != nil {
+			t.Fatal(err)
+		}
+		if restored.String() != dec.String() {
+			t.Fatalf("the binary of %s is restored to %s", dec, restored)
+		}
+	}
+	if err := new(MyDecimal).FromBinary([]byte{MaxDecimalScale + 1, 0, 1}); err != ErrBadNumber {
+		t.Fatalf("FromBinary of too many fraction digits returns %v, expected %v", err, ErrBadNumber)
+	}
+}
+
+func TestCastHugeExponentToDecimal(t *testing.T) {
+	ft := NewFieldType(mysql.TypeNewDecimal)
+	ft.Flen, ft.Decimal = 10, 2
+	tests := []struct {
+		s        string
+		expected string
+		warnings int
+	}{
+		{"1e-99999999", "0.00", 0},
+		{"-2.5e-3", "0.00", 0},
+		{"1e999999999", "99999999.99", 2},
+		{"-1e999999999", "-99999999.99", 2},
+	}
+	for _, tt := range tests {
+		sc := &stmtctx.StatementContext{TruncateAsWarning: true, OverflowAsWarning: true}
+		origin := NewStringDatum(tt.s)
+		d, err := origin.ConvertTo(sc, ft)
+		if err != nil {
+			t.Fatalf("CAST(%q AS DECIMAL(10,2)) returns %v", tt.s, err)
+		}
+		if s := d.GetMysqlDecimal().String(); s != tt.expected {
+			t.Fatalf("CAST(%q AS DECIMAL(10,2)) = %s, expected %s", tt.s, s, tt.expected)
+		}
+		if n := len(sc.GetWarnings()); n != tt.warnings {
+			t.Fatalf("CAST(%q AS DECIMAL(10,2)) has %d warnings, expected %d", tt.s, n, tt.warnings)
+		}
+	}
+	sc := &stmtctx.StatementContext{}
+	origin := NewStringDatum("1e999999999")
+	if _, err := origin.ConvertTo(sc, ft); err == nil {
+		t.Fatal("the overflow is not an error without OverflowAsWarning")
+	}
+}
@@ -0,0 +1,65 @@
+package types
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Set is for MySQL Set type.
+type Set struct {
+	Name  string
+	Value uint64
+}
+
+// String implements fmt.Stringer interface.
+func (e Set) String() string {
+	return e.Name
+}
+
+// ToNumber changes Set to float64 for numeric operation.
+func (e Set) ToNumber() float64 {
+	return float64(e.Value)
+}
+
+// ParseSetName creates a Set with a comma separated list of item names, the names are matched
+// case-insensitively and the duplicated ones are merged. The name of the Set lists the items in
+// the order of elems. A number is taken as the bits of the items if any name doesn't exist.
+func ParseSetName(elems []string, name string) (Set, error) {
+	if len(name) == 0 {
+		return Set{}, nil
+	}
+	var value uint64
+	for _, part := range strings.Split(name, ",") {
+		part = strings.TrimRight(part, " ")
+		found := false
+		for i, n := range elems {
+			if strings.EqualFold(strings.TrimRight(n, " "), part) {
+				value |= 1 << uint64(i)
+				found = true
+				break
+			}
+		}
+		if !found {
+			// name doesn't exist, maybe an integer?
+			if num, err := strconv.ParseUint(name, 10, 64); err == nil {
+				return ParseSetValue(elems, num)
+			}
+			return Set{}, ErrTruncatedWrongVal.GenWithStackByArgs("set", name)
+		}
+	}
+	return ParseSetValue(elems, value)
+}
+
+// ParseSetValue creates a Set with the bits of the items, the bit i stands for the item i.
+func ParseSetValue(elems []string, number uint64) (Set, error) {
+	if len(elems) < 64 && number >= 1<<uint64(len(elems)) {
+		return Set{}, ErrTruncatedWrongVal.GenWithStackByArgs("set", strconv.FormatUint(number, 10))
+	}
+	names := make([]string, 0, len(elems))
+	for i, n := range elems {
+		if number&(1<<uint64(i)) != 0 {
+			names = append(names, n)
+		}
+	}
+	return Set{Name: strings.Join(names, ","), Value: number}, nil
+}
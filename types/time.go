@@ -0,0 +1,536 @@
+package types
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	gotime "time"
+
+	"github.com/pingcap/parser/mysql"
+	"grant-db/sessionctx/stmtctx"
+)
+
+// Fsp is the fractional seconds precision, 0~6.
+const (
+	MinFsp     int8 = 0
+	MaxFsp     int8 = 6
+	DefaultFsp int8 = 0
+)
+
+// Time is the struct for handling datetime, timestamp and date.
+// The value is kept in MySQL's packed format so comparing two packed values
+// gives the time order.
+type Time struct {
+	packed uint64
+	tp     byte
+	fsp    int8
+}
+
+// ZeroDatetime is the zero value for datetime Time.
+var ZeroDatetime = Time{tp: mysql.TypeDatetime}
+
+// NewTime constructs time from its parts.
+func NewTime(year, month, day, hour, minute, second, microsecond int, tp byte, fsp int8) Time {
+	ymd := uint64(((year*13 + month) << 5) | day)
+	hms := uint64(hour<<12 | minute<<6 | second)
+	return Time{
+		packed: ((ymd<<17 | hms) << 24) | uint64(microsecond),
+		tp:     tp,
+		fsp:    fsp,
+	}
+}
+
+// FromGoTime builds a Time from a Go time in its location.
+func FromGoTime(t gotime.Time, tp byte, fsp int8) Time {
+	year, month, day := t.Date()
+	hour, minute, second := t.Clock()
+	return NewTime(year, int(month), day, hour, minute, second, t.Nanosecond()/1000, tp, fsp)
+}
+
+// FromPackedUint restores a Time from its packed representation.
+func FromPackedUint(packed uint64, tp byte, fsp int8) Time {
+	return Time{packed: packed, tp: tp, fsp: fsp}
+}
+
+// ToPackedUint encodes Time to a packed uint64 value.
+func (t Time) ToPackedUint() uint64 {
+	return t.packed
+}
+
+func (t Time) ymd() int {
+	return int(t.packed >> 41)
+}
+
+func (t Time) hms() int {
+	return int(t.packed>>24) & (1<<17 - 1)
+}
+
+// Year returns the year part.
+func (t Time) Year() int {
+	return t.ymd() >> 5 / 13
+}
+
+// Month returns the month part.
+func (t Time) Month() int {
+	return t.ymd() >> 5 % 13
+}
+
+// Day returns the day part.
+func (t Time) Day() int {
+	return t.ymd() & 31
+}
+
+// Hour returns the hour part.
+func (t Time) Hour() int {
+	return t.hms() >> 12
+}
+
+// Minute returns the minute part.
+func (t Time) Minute() int {
+	return t.hms() >> 6 & 63
+}
+
+// Second returns the second part.
+func (t Time) Second() int {
+	return t.hms() & 63
+}
+
+// Microsecond returns the microsecond part.
+func (t Time) Microsecond() int {
+	return int(t.packed & (1<<24 - 1))
+}
+
+// Type returns the type of Time, one of TypeDate, TypeDatetime and TypeTimestamp.
+func (t Time) Type() byte {
+	return t.tp
+}
+
+// SetType updates the type in Time.
+func (t *Time) SetType(tp byte) {
+	t.tp = tp
+}
+
+// Fsp returns the fractional seconds precision.
+func (t Time) Fsp() int8 {
+	return t.fsp
+}
+
+// SetFsp updates the fsp in Time.
+func (t *Time) SetFsp(fsp int8) {
+	t.fsp = fsp
+}
+
+// IsZero returns a boolean indicating whether the time is equal to ZeroTime.
+func (t Time) IsZero() bool {
+	return t.packed == 0
+}
+
+// Compare returns an integer comparing the time instant t to o.
+func (t Time) Compare(o Time) int {
+	switch {
+	case t.packed < o.packed:
+		return -1
+	case t.packed > o.packed:
+		return 1
+	}
+	return 0
+}
+
+// String returns the time in MySQL's textual format according to its type and fsp.
+func (t Time) String() string {
+	if t.tp == mysql.TypeDate {
+		return fmt.Sprintf("%04d-%02d-%02d", t.Year(), t.Month(), t.Day())
+	}
+	s := fmt.Sprintf("%04d-%02d-%02d %02d:%02d:%02d", t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second())
+	if t.fsp > 0 {
+		frac := fmt.Sprintf("%06d", t.Microsecond())
+		s += "." + frac[:t.fsp]
+	}
+	return s
+}
+
+// ParseTime parses a formatted string with type tp and specific fsp.
+// The accepted formats are 'YYYY-MM-DD[ HH:MM:SS[.fraction]]' and 'YYYYMMDD[HHMMSS]'.
+func ParseTime(str string, tp byte, fsp int8) (Time, error) {
+	t, _, err := parseDatetime(str, fsp)
+	if err != nil {
+		return ZeroDatetime, err
+	}
+	if tp == mysql.TypeDate {
+		return NewTime(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, mysql.TypeDate, 0), nil
+	}
+	t.tp = tp
+	return t, nil
+}
+
+// ParseDateOrDatetime parses a formatted string into a date if it has no time part, or a datetime
+// with the max fsp otherwise.
+func ParseDateOrDatetime(str string) (Time, error) {
+	t, hasTime, err := parseDatetime(str, MaxFsp)
+	if err != nil {
+		return ZeroDatetime, err
+	}
+	if !hasTime {
+		return NewTime(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, mysql.TypeDate, 0), nil
+	}
+	return t, nil
+}
+
+// parseDatetime parses a formatted string into a datetime, hasTime reports whether the string has the time part.
+func parseDatetime(str string, fsp int8) (t Time, hasTime bool, err error) {
+	str = strings.TrimSpace(str)
+	var parts []int
+	frac := ""
+	if strings.ContainsAny(str, "-/:") {
+		if i := strings.IndexByte(str, '.'); i >= 0 {
+			str, frac = str[:i], str[i+1:]
+		}
+		fields := strings.FieldsFunc(str, func(r rune) bool {
+			return r == '-' || r == '/' || r == ':' || r == ' ' || r == 'T'
+		})
+		for _, f := range fields {
+			v, err := strconv.Atoi(f)
+			if err != nil {
+				return ZeroDatetime, false, ErrInvalidTimeFormat
+			}
+			parts = append(parts, v)
+		}
+		// A two-digit year is in 1970~2069.
+		if len(fields) > 0 && len(fields[0]) <= 2 {
+			parts[0] = adjustYear(parts[0])
+		}
+	} else {
+		if i := strings.IndexByte(str, '.'); i >= 0 {
+			str, frac = str[:i], str[i+1:]
+		}
+		if len(str) != 8 && len(str) != 14 {
+			return ZeroDatetime, false, ErrInvalidTimeFormat
+		}
+		widths := []int{4, 2, 2, 2, 2, 2}
+		for _, w := range widths {
+			if len(str) == 0 {
+				break
+			}
+			v, err := strconv.Atoi(str[:w])
+			if err != nil {
+				return ZeroDatetime, false, ErrInvalidTimeFormat
+			}
+			parts = append(parts, v)
+			str = str[w:]
+		}
+	}
+	if len(parts) != 3 && len(parts) != 6 {
+		return ZeroDatetime, false, ErrInvalidTimeFormat
+	}
+	hasTime = len(parts) == 6
+	for len(parts) < 6 {
+		parts = append(parts, 0)
+	}
+	microsecond := 0
+	if frac != "" {
+		if len(frac) > 6 {
+			frac = frac[:6]
+		}
+		v, err := strconv.Atoi(frac + strings.Repeat("0", 6-len(frac)))
+		if err != nil {
+			return ZeroDatetime, false, ErrInvalidTimeFormat
+		}
+		microsecond = v
+	}
+	if parts[0] > 9999 || parts[1] > 12 || parts[2] > 31 || parts[3] > 23 || parts[4] > 59 || parts[5] > 59 {
+		return ZeroDatetime, false, ErrInvalidTimeFormat
+	}
+	return NewTime(parts[0], parts[1], parts[2], parts[3], parts[4], parts[5], microsecond, mysql.TypeDatetime, fsp), hasTime, nil
+}
+
+func adjustYear(y int) int {
+	if y < 70 {
+		return y + 2000
+	}
+	if y < 100 {
+		return y + 1900
+	}
+	return y
+}
+
+// Check checks whether the time is a valid date by the rules of the statement context. The zero date
+// is invalid if NoZeroDate, a zero month or day is invalid unless IgnoreZeroInDate, and a day after the
+// last day of the month is invalid unless AllowInvalidDate.
+func (t Time) Check(sc *stmtctx.StatementContext) error {
+	year, month, day := t.Year(), t.Month(), t.Day()
+	valid := true
+	switch {
+	case year == 0 && month == 0 && day == 0:
+		valid = !sc.NoZeroDate
+	case month == 0 || day == 0:
+		valid = sc.IgnoreZeroInDate
+	case !sc.AllowInvalidDate:
+		valid = day <= GetLastDay(year, month)
+	}
+	if !valid {
+		return ErrTruncatedWrongVal.GenWithStackByArgs(TypeStr(t.tp), t.String())
+	}
+	return nil
+}
+
+// InvalidZero reports whether the time has a zero month or day, which can't be used in the date calculation.
+func (t Time) InvalidZero() bool {
+	return t.Month() == 0 || t.Day() == 0
+}
+
+// GoTime converts the time to a Go time in the location, an error is returned if the time isn't a valid Go time,
+// like a zero date or '2020-02-30'.
+func (t Time) GoTime(loc *gotime.Location) (gotime.Time, error) {
+	year, month, day := t.Year(), t.Month(), t.Day()
+	hour, minute, second, microsecond := t.Hour(), t.Minute(), t.Second(), t.Microsecond()
+	tm := gotime.Date(year, gotime.Month(month), day, hour, minute, second, microsecond*1000, loc)
+	year2, month2, day2 := tm.Date()
+	hour2, minute2, second2 := tm.Clock()
+	if year2 != year || int(month2) != month || day2 != day || hour2 != hour || minute2 != minute || second2 != second {
+		return tm, ErrTruncatedWrongVal.GenWithStackByArgs(TypeStr(t.tp), t.String())
+	}
+	return tm, nil
+}
+
+const nanosPerDay = int64(24 * gotime.Hour)
+
+// maxIntervalDays is the max number of days in the range of datetime, a larger interval always overflows.
+const maxIntervalDays = 10000 * 366
+
+// AddInterval adds the interval of years, months, days and nanoseconds to the time. The day is clipped to the
+// last day of the month when years or months are added, like '2020-01-31' + INTERVAL 1 MONTH is '2020-02-29'.
+// ErrDatetimeFunctionOverflow is returned if the result is out of the range of datetime.
+func (t Time) AddInterval(years, months, days, nanos int64) (Time, error) {
+	overflow := ErrDatetimeFunctionOverflow.GenWithStackByArgs("datetime")
+	totalMonths := int64(t.Year())*12 + int64(t.Month()-1) + years*12 + months
+	if totalMonths < 0 || totalMonths >= 10000*12 {
+		return ZeroDatetime, overflow
+	}
+	year, month := int(totalMonths/12), int(totalMonths%12)+1
+	day := t.Day()
+	if lastDay := GetLastDay(year, month); day > lastDay {
+		day = lastDay
+	}
+	days += nanos / nanosPerDay
+	nanos %= nanosPerDay
+	if days > maxIntervalDays || days < -maxIntervalDays {
+		return ZeroDatetime, overflow
+	}
+	tm := gotime.Date(year, gotime.Month(month), day, t.Hour(), t.Minute(), t.Second(), t.Microsecond()*1000, gotime.UTC)
+	tm = tm.AddDate(0, 0, int(days)).Add(gotime.Duration(nanos))
+	if tm.Year() < 0 || tm.Year() > 9999 {
+		return ZeroDatetime, overflow
+	}
+	return FromGoTime(tm, t.tp, t.fsp), nil
+}
+
+// Convert converts the time to the type tp, the fraction part is rounded to fsp digits.
+func (t Time) Convert(tp byte, fsp int8) (Time, error) {
+	if tp == mysql.TypeDate {
+		return NewTime(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, tp, 0), nil
+	}
+	res, err := t.RoundFrac(fsp)
+	res.tp = tp
+	return res, err
+}
+
+// RoundFrac rounds the fraction part of the time to fsp digits, half away from zero.
+func (t Time) RoundFrac(fsp int8) (Time, error) {
+	if fsp < MinFsp || fsp > MaxFsp {
+		fsp = MaxFsp
+	}
+	unit := int(math.Pow10(int(MaxFsp - fsp)))
+	usec := (t.Microsecond() + unit/2) / unit * unit
+	res := NewTime(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), usec%1000000, t.tp, fsp)
+	if usec < 1000000 || t.InvalidZero() {
+		return res, nil
+	}
+	return res.AddInterval(0, 0, 0, int64(gotime.Second))
+}
+
+var (
+	minTimestamp = NewTime(1970, 1, 1, 0, 0, 1, 0, mysql.TypeTimestamp, 0)
+	maxTimestamp = NewTime(2038, 1, 19, 3, 14, 7, 999999, mysql.TypeTimestamp, MaxFsp)
+)
+
+// inTimestampRange checks whether the time is in the range of TIMESTAMP.
+func (t Time) inTimestampRange() bool {
+	return t.Compare(minTimestamp) >= 0 && t.Compare(maxTimestamp) <= 0
+}
+
+// DateDiff returns the number of days from t2 to t1, the time parts are ignored.
+func DateDiff(t1, t2 Time) int {
+	return calcDaynr(t1.Year(), t1.Month(), t1.Day()) - calcDaynr(t2.Year(), t2.Month(), t2.Day())
+}
+
+// Weekday returns the day of the week, 0 is Sunday.
+func (t Time) Weekday() int {
+	return calcWeekday(calcDaynr(t.Year(), t.Month(), t.Day()), true)
+}
+
+// YearDay returns the day of the year, 1~366.
+func (t Time) YearDay() int {
+	if t.InvalidZero() {
+		return 0
+	}
+	return calcDaynr(t.Year(), t.Month(), t.Day()) - calcDaynr(t.Year(), 1, 1) + 1
+}
+
+// Week returns the week of the year in the mode of the WEEK function.
+func (t Time) Week(mode int) int {
+	if t.InvalidZero() {
+		return 0
+	}
+	_, week := calcWeek(t.Year(), t.Month(), t.Day(), weekMode(mode))
+	return week
+}
+
+// YearWeek returns the year and the week of the year in the mode of the YEARWEEK function.
+func (t Time) YearWeek(mode int) (int, int) {
+	return calcWeek(t.Year(), t.Month(), t.Day(), weekMode(mode)|weekBehaviourYear)
+}
+
+var monthNames = []string{
+	"January", "February", "March", "April", "May", "June",
+	"July", "August", "September", "October", "November", "December",
+}
+
+var weekdayNames = []string{
+	"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday",
+}
+
+// DateFormat formats the time by the layout of the DATE_FORMAT function, an error is returned if
+// the name of a zero month or the weekday of an invalid date is formatted.
+func (t Time) DateFormat(layout string) (string, error) {
+	var buf strings.Builder
+	inPatternMatch := false
+	for _, b := range layout {
+		if !inPatternMatch {
+			if b == '%' {
+				inPatternMatch = true
+			} else {
+				buf.WriteRune(b)
+			}
+			continue
+		}
+		inPatternMatch = false
+		if err := t.convertDateFormat(b, &buf); err != nil {
+			return "", err
+		}
+	}
+	return buf.String(), nil
+}
+
+func (t Time) convertDateFormat(b rune, buf *strings.Builder) error {
+	switch b {
+	case 'b', 'M':
+		m := t.Month()
+		if m == 0 {
+			return ErrTruncatedWrongVal.GenWithStackByArgs(TypeStr(t.tp), t.String())
+		}
+		if b == 'b' {
+			buf.WriteString(monthNames[m-1][:3])
+		} else {
+			buf.WriteString(monthNames[m-1])
+		}
+	case 'a', 'W', 'w':
+		if t.InvalidZero() {
+			return ErrTruncatedWrongVal.GenWithStackByArgs(TypeStr(t.tp), t.String())
+		}
+		weekday := t.Weekday()
+		switch b {
+		case 'a':
+			buf.WriteString(weekdayNames[weekday][:3])
+		case 'W':
+			buf.WriteString(weekdayNames[weekday])
+		default:
+			buf.WriteString(strconv.Itoa(weekday))
+		}
+	case 'm':
+		fmt.Fprintf(buf, "%02d", t.Month())
+	case 'c':
+		buf.WriteString(strconv.Itoa(t.Month()))
+	case 'D':
+		buf.WriteString(strconv.Itoa(t.Day()))
+		buf.WriteString(abbrDayOfMonth(t.Day()))
+	case 'd':
+		fmt.Fprintf(buf, "%02d", t.Day())
+	case 'e':
+		buf.WriteString(strconv.Itoa(t.Day()))
+	case 'j':
+		fmt.Fprintf(buf, "%03d", t.YearDay())
+	case 'H':
+		fmt.Fprintf(buf, "%02d", t.Hour())
+	case 'k':
+		buf.WriteString(strconv.Itoa(t.Hour()))
+	case 'h', 'I':
+		fmt.Fprintf(buf, "%02d", hour12(t.Hour()))
+	case 'l':
+		buf.WriteString(strconv.Itoa(hour12(t.Hour())))
+	case 'i':
+		fmt.Fprintf(buf, "%02d", t.Minute())
+	case 'p':
+		buf.WriteString(amOrPm(t.Hour()))
+	case 'r':
+		fmt.Fprintf(buf, "%02d:%02d:%02d %s", hour12(t.Hour()), t.Minute(), t.Second(), amOrPm(t.Hour()))
+	case 'T':
+		fmt.Fprintf(buf, "%02d:%02d:%02d", t.Hour(), t.Minute(), t.Second())
+	case 'S', 's':
+		fmt.Fprintf(buf, "%02d", t.Second())
+	case 'f':
+		fmt.Fprintf(buf, "%06d", t.Microsecond())
+	case 'U':
+		fmt.Fprintf(buf, "%02d", t.Week(0))
+	case 'u':
+		fmt.Fprintf(buf, "%02d", t.Week(1))
+	case 'V':
+		fmt.Fprintf(buf, "%02d", t.Week(2))
+	case 'v':
+		_, w := t.YearWeek(3)
+		fmt.Fprintf(buf, "%02d", w)
+	case 'X':
+		year, _ := t.YearWeek(2)
+		fmt.Fprintf(buf, "%04d", year)
+	case 'x':
+		year, _ := t.YearWeek(3)
+		fmt.Fprintf(buf, "%04d", year)
+	case 'Y':
+		fmt.Fprintf(buf, "%04d", t.Year())
+	case 'y':
+		fmt.Fprintf(buf, "%02d", t.Year()%100)
+	default:
+		buf.WriteRune(b)
+	}
+	return nil
+}
+
+func hour12(hour int) int {
+	if hour%12 == 0 {
+		return 12
+	}
+	return hour % 12
+}
+
+func amOrPm(hour int) string {
+	if hour < 12 {
+		return "AM"
+	}
+	return "PM"
+}
+
+// abbrDayOfMonth returns the English suffix of the day of the month, like "st" of 1st.
+func abbrDayOfMonth(day int) string {
+	if day/10 == 1 {
+		return "th"
+	}
+	switch day % 10 {
+	case 1:
+		return "st"
+	case 2:
+		return "nd"
+	case 3:
+		return "rd"
+	}
+	return "th"
+}
@@ -0,0 +1,153 @@
+package types
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+	gotime "time"
+
+	"grant-db/sessionctx/stmtctx"
+)
+
+// The indexes of the fields of an interval.
+const (
+	intervalYear = iota
+	intervalMonth
+	intervalDay
+	intervalHour
+	intervalMinute
+	intervalSecond
+	intervalMicrosecond
+)
+
+// maxIntervalMicros is the max microseconds of an interval in SECOND, a larger interval always overflows.
+var maxIntervalMicros = big.NewInt(maxIntervalDays * 24 * 3600 * 1e6)
+
+// compoundIntervalUnits maps a compound unit to the fields of its value in order, like '1 2:30' DAY_MINUTE.
+var compoundIntervalUnits = map[string][]int{
+	"SECOND_MICROSECOND": {intervalSecond, intervalMicrosecond},
+	"MINUTE_MICROSECOND": {intervalMinute, intervalSecond, intervalMicrosecond},
+	"MINUTE_SECOND":      {intervalMinute, intervalSecond},
+	"HOUR_MICROSECOND":   {intervalHour, intervalMinute, intervalSecond, intervalMicrosecond},
+	"HOUR_SECOND":        {intervalHour, intervalMinute, intervalSecond},
+	"HOUR_MINUTE":        {intervalHour, intervalMinute},
+	"DAY_MICROSECOND":    {intervalDay, intervalHour, intervalMinute, intervalSecond, intervalMicrosecond},
+	"DAY_SECOND":         {intervalDay, intervalHour, intervalMinute, intervalSecond},
+	"DAY_MINUTE":         {intervalDay, intervalHour, intervalMinute},
+	"DAY_HOUR":           {intervalDay, intervalHour},
+	"YEAR_MONTH":         {intervalYear, intervalMonth},
+}
+
+// IsClockUnit reports whether the interval unit has a time part.
+func IsClockUnit(unit string) bool {
+	switch unit {
+	case "YEAR", "QUARTER", "MONTH", "WEEK", "DAY", "YEAR_MONTH":
+		return false
+	}
+	return true
+}
+
+// IsMicrosecondUnit reports whether the interval unit has a microsecond part.
+func IsMicrosecondUnit(unit string) bool {
+	return strings.HasSuffix(unit, "MICROSECOND")
+}
+
+// ParseDurationValue parses the value of an INTERVAL of the unit into years, months, days and nanoseconds.
+// The value of a single unit is a number, like 1.5 SECOND, while the value of a compound unit is a string
+// of the fields separated by any non-digit, like '1 2:30' DAY_MINUTE. The fields of a compound unit are
+// aligned to the right if some are missing, '2:30' DAY_MINUTE is 2 hours and 30 minutes.
+func ParseDurationValue(sc *stmtctx.StatementContext, unit string, format string) (years, months, days, nanos int64, err error) {
+	format = strings.TrimSpace(format)
+	switch unit {
+	case "MICROSECOND", "MINUTE", "HOUR", "DAY", "WEEK", "MONTH", "QUARTER", "YEAR":
+		v, err := StrToInt(sc, format)
+		if err != nil {
+			return 0, 0, 0, 0, err
+		}
+		switch unit {
+		case "MICROSECOND":
+			days, nanos = splitNanos(v, gotime.Microsecond)
+			return 0, 0, days, nanos, nil
+		case "MINUTE":
+			days, nanos = splitNanos(v, gotime.Minute)
+			return 0, 0, days, nanos, nil
+		case "HOUR":
+			days, nanos = splitNanos(v, gotime.Hour)
+			return 0, 0, days, nanos, nil
+		case "DAY":
+			return 0, 0, v, 0, nil
+		case "WEEK":
+			return 0, 0, v * 7, 0, nil
+		case "MONTH":
+			return 0, v, 0, 0, nil
+		case "QUARTER":
+			return 0, v * 3, 0, 0, nil
+		default:
+			return v, 0, 0, 0, nil
+		}
+	case "SECOND":
+		dec, err := StrToDecimal(sc, format)
+		if err != nil {
+			return 0, 0, 0, 0, err
+		}
+		// The unscaled value of the decimal with 6 fraction digits is in microseconds.
+		micros := &dec.Rescale(6).unscaled
+		if micros.CmpAbs(maxIntervalMicros) > 0 {
+			return 0, 0, 0, 0, ErrDatetimeFunctionOverflow.GenWithStackByArgs("datetime")
+		}
+		days, nanos = splitNanos(micros.Int64(), gotime.Microsecond)
+		return 0, 0, days, nanos, nil
+	}
+	fields, ok := compoundIntervalUnits[unit]
+	if !ok {
+		return 0, 0, 0, 0, ErrTruncatedWrongVal.GenWithStackByArgs("INTERVAL", unit)
+	}
+	values, neg, ok := splitIntervalValue(format, len(fields), fields[len(fields)-1] == intervalMicrosecond)
+	if !ok {
+		return 0, 0, 0, 0, ErrTruncatedWrongVal.GenWithStackByArgs(unit, format)
+	}
+	var parts [intervalMicrosecond + 1]int64
+	for i, v := range values {
+		parts[fields[len(fields)-len(values)+i]] = v
+	}
+	years, months, days = parts[intervalYear], parts[intervalMonth], parts[intervalDay]
+	for i, unit := range []gotime.Duration{gotime.Hour, gotime.Minute, gotime.Second, gotime.Microsecond} {
+		d, n := splitNanos(parts[intervalHour+i], unit)
+		days, nanos = days+d, nanos+n
+	}
+	if neg {
+		return -years, -months, -days, -nanos, nil
+	}
+	return years, months, days, nanos, nil
+}
+
+// splitNanos splits v units into days and the remaining nanoseconds, so a large interval doesn't overflow.
+func splitNanos(v int64, unit gotime.Duration) (days, nanos int64) {
+	perDay := nanosPerDay / int64(unit)
+	return v / perDay, v % perDay * int64(unit)
+}
+
+// splitIntervalValue splits the value of a compound interval into at most n numbers. The last number is
+// scaled to microseconds like a fraction if hasMicrosecond, '1.5' SECOND_MICROSECOND is 1.5 seconds.
+func splitIntervalValue(format string, n int, hasMicrosecond bool) (values []int64, neg bool, ok bool) {
+	if strings.HasPrefix(format, "-") {
+		neg, format = true, format[1:]
+	}
+	fields := strings.FieldsFunc(format, func(r rune) bool {
+		return r < '0' || r > '9'
+	})
+	if len(fields) == 0 || len(fields) > n {
+		return nil, false, false
+	}
+	for i, f := range fields {
+		if hasMicrosecond && i == len(fields)-1 && len(fields) > 1 && len(f) < 6 {
+			f += strings.Repeat("0", 6-len(f))
+		}
+		v, err := strconv.ParseInt(f, 10, 64)
+		if err != nil {
+			return nil, false, false
+		}
+		values = append(values, v)
+	}
+	return values, neg, true
+}
@@ -0,0 +1,76 @@
+package admin
+
+import (
+	"github.com/pingcap/parser/model"
+	"grant-db/ddl"
+	"grant-db/kv"
+	"grant-db/meta"
+)
+
+// GetDDLJobs gets the DDL jobs in the job queue.
+func GetDDLJobs(txn kv.Transaction) ([]*model.Job, error) {
+	return meta.NewMeta(txn).GetAllDDLJobsInQueue()
+}
+
+// GetHistoryDDLJobs gets the latest maxNumJobs finished DDL jobs, the newest one comes first.
+func GetHistoryDDLJobs(txn kv.Transaction, maxNumJobs int) ([]*model.Job, error) {
+	return meta.NewMeta(txn).GetLastNHistoryDDLJobs(maxNumJobs)
+}
+
+// isJobRollbackable checks whether the job can be rolled back from its current state.
+func isJobRollbackable(job *model.Job) bool {
+	switch job.Type {
+	case model.ActionDropIndex, model.ActionDropPrimaryKey:
+		// The index data may be deleted partly in delete only and delete reorganization,
+		// the job can't be rolled back then.
+		if job.SchemaState == model.StateDeleteOnly || job.SchemaState == model.StateDeleteReorganization {
+			return false
+		}
+	case model.ActionAddIndex, model.ActionAddPrimaryKey, model.ActionAddColumn, model.ActionModifyColumn:
+	default:
+		// The other jobs finish in one step, they can be cancelled only before running.
+		if job.State != model.JobStateNone {
+			return false
+		}
+	}
+	return true
+}
+
+// CancelJobs cancels the DDL jobs, the errors are returned in the order of ids and a nil
+// error means the job will be cancelled by the DDL worker.
+func CancelJobs(txn kv.Transaction, ids []int64) ([]error, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	errs := make([]error, len(ids))
+	t := meta.NewMeta(txn)
+	for i, id := range ids {
+		job, err := t.GetDDLJobByID(id)
+		if err != nil {
+			return nil, err
+		}
+		if job == nil {
+			errs[i] = ddl.ErrDDLJobNotFound.GenWithStackByArgs(id)
+			continue
+		}
+		if job.IsDone() || job.IsSynced() {
+			errs[i] = ddl.ErrCancelFinishedDDLJob.GenWithStackByArgs(id)
+			continue
+		}
+		// If the job is rolling back, we can just return the cancel is done.
+		if job.IsCancelled() || job.IsRollingback() || job.IsRollbackDone() || job.IsCancelling() {
+			continue
+		}
+		if !isJobRollbackable(job) {
+			errs[i] = ddl.ErrCannotCancelDDLJob.GenWithStackByArgs(id)
+			continue
+		}
+
+		job.State = model.JobStateCancelling
+		if err = t.UpdateDDLJob(job, false); err != nil {
+			return nil, err
+		}
+	}
+	return errs, nil
+}
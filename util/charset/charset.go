@@ -0,0 +1,145 @@
+package charset
+
+import (
+	"strings"
+
+	"github.com/pingcap/parser/charset"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
+)
+
+var (
+	// ErrUnknownCharacterSet is returned for a charset which is unknown or not supported.
+	ErrUnknownCharacterSet = terror.ClassDDL.New(mysql.ErrUnknownCharacterSet, mysql.MySQLErrName[mysql.ErrUnknownCharacterSet])
+	// ErrUnknownCollation is returned for a collation which is unknown or not supported.
+	ErrUnknownCollation = terror.ClassDDL.New(mysql.ErrUnknownCollation, mysql.MySQLErrName[mysql.ErrUnknownCollation])
+	// ErrCollationCharsetMismatch is returned when the collation doesn't belong to the charset.
+	ErrCollationCharsetMismatch = terror.ClassDDL.New(mysql.ErrCollationCharsetMismatch, mysql.MySQLErrName[mysql.ErrCollationCharsetMismatch])
+)
+
+// Charset is a supported charset.
+type Charset struct {
+	Name             string
+	DefaultCollation string
+	Desc             string
+	// Maxlen is the max number of bytes of a character.
+	Maxlen int
+}
+
+// Collation is a supported collation.
+type Collation struct {
+	ID          int
+	CharsetName string
+	Name        string
+	IsDefault   bool
+}
+
+// charsetInfos are the supported charsets, the parser is forked to know all of them.
+var charsetInfos = []*Charset{
+	{charset.CharsetUTF8, charset.CollationUTF8, "UTF-8 Unicode", 3},
+	{charset.CharsetUTF8MB4, charset.CollationUTF8MB4, "UTF-8 Unicode", 4},
+	{charset.CharsetASCII, charset.CollationASCII, "US ASCII", 1},
+	{charset.CharsetLatin1, charset.CollationLatin1SwedishCI, "cp1252 West European", 1},
+	{charset.CharsetBin, charset.CollationBin, "Binary pseudo charset", 1},
+	{charset.CharsetGBK, charset.CollationGBK, "GBK Simplified Chinese", 2},
+}
+
+// supportedCollationNames are the collations which have a collator, see util/collate.
+var supportedCollationNames = []string{
+	"utf8_bin", "utf8_general_ci", "utf8_unicode_ci",
+	"utf8mb4_bin", "utf8mb4_general_ci", "utf8mb4_unicode_ci", "utf8mb4_0900_ai_ci",
+	"ascii_bin", "latin1_bin", "latin1_swedish_ci", "binary", "gbk_chinese_ci", "gbk_bin",
+}
+
+var (
+	charsets       = make(map[string]*Charset, len(charsetInfos))
+	collationNames = make(map[string]*Collation, len(supportedCollationNames))
+	collationIDs   = make(map[int]*Collation, len(supportedCollationNames))
+	collations     = make([]*Collation, 0, len(supportedCollationNames))
+)
+
+// GetCharsetInfo returns the supported charset of the name.
+func GetCharsetInfo(name string) (*Charset, error) {
+	if cs, ok := charsets[strings.ToLower(name)]; ok {
+		return cs, nil
+	}
+	return nil, ErrUnknownCharacterSet.GenWithStackByArgs(name)
+}
+
+// GetDefaultCollation returns the default collation of the charset.
+func GetDefaultCollation(name string) (string, error) {
+	cs, err := GetCharsetInfo(name)
+	if err != nil {
+		return "", err
+	}
+	return cs.DefaultCollation, nil
+}
+
+// GetCollationByName returns the supported collation of the name.
+func GetCollationByName(name string) (*Collation, error) {
+	if coll, ok := collationNames[strings.ToLower(name)]; ok {
+		return coll, nil
+	}
+	return nil, ErrUnknownCollation.GenWithStackByArgs(name)
+}
+
+// GetCollationByID returns the supported collation of the ID.
+func GetCollationByID(id int) (*Collation, error) {
+	if coll, ok := collationIDs[id]; ok {
+		return coll, nil
+	}
+	return nil, ErrUnknownCollation.GenWithStackByArgs(id)
+}
+
+// GetSupportedCharsets returns all the supported charsets.
+func GetSupportedCharsets() []*Charset {
+	return charsetInfos
+}
+
+// GetSupportedCollations returns all the supported collations.
+func GetSupportedCollations() []*Collation {
+	return collations
+}
+
+// ResolveCharsetCollation checks the charset and the collation, a missing one is derived from the other
+// and the default charset is used if both are missing. The returned names are in lower case.
+func ResolveCharsetCollation(cs, coll string) (string, string, error) {
+	if cs == "" && coll == "" {
+		return mysql.DefaultCharset, mysql.DefaultCollationName, nil
+	}
+	if coll == "" {
+		info, err := GetCharsetInfo(cs)
+		if err != nil {
+			return "", "", err
+		}
+		return info.Name, info.DefaultCollation, nil
+	}
+	info, err := GetCollationByName(coll)
+	if err != nil {
+		return "", "", err
+	}
+	if cs == "" {
+		return info.CharsetName, info.Name, nil
+	}
+	if _, err = GetCharsetInfo(cs); err != nil {
+		return "", "", err
+	}
+	if !strings.EqualFold(cs, info.CharsetName) {
+		return "", "", ErrCollationCharsetMismatch.GenWithStackByArgs(info.Name, cs)
+	}
+	return info.CharsetName, info.Name, nil
+}
+
+func init() {
+	for _, cs := range charsetInfos {
+		charsets[cs.Name] = cs
+	}
+	for _, name := range supportedCollationNames {
+		c, err := charset.GetCollationByName(name)
+		terror.MustNil(err)
+		coll := &Collation{ID: c.ID, CharsetName: c.CharsetName, Name: c.Name, IsDefault: charsets[c.CharsetName].DefaultCollation == c.Name}
+		collationNames[coll.Name] = coll
+		collationIDs[coll.ID] = coll
+		collations = append(collations, coll)
+	}
+}
@@ -0,0 +1,109 @@
+package charset
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pingcap/parser/charset"
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// replacementChar replaces the characters which can't be represented in the charset, like MySQL.
+const replacementChar = '?'
+
+// Encoding converts the strings between UTF-8, in which the server keeps all the strings, and a charset.
+// All the supported charsets are ASCII compatible.
+type Encoding struct {
+	name string
+	// enc is nil for the charsets in which the UTF-8 bytes are taken as is.
+	enc encoding.Encoding
+	// maxRuneLen is the max length of a rune in UTF-8 which can be represented in the charset, 0 means no limit.
+	maxRuneLen int
+}
+
+// NewEncoding returns the encoding of the charset, the unknown charsets are treated as binary.
+func NewEncoding(cs string) *Encoding {
+	e := &Encoding{name: strings.ToLower(cs)}
+	switch e.name {
+	case charset.CharsetASCII:
+		e.maxRuneLen = 1
+	case charset.CharsetUTF8:
+		e.maxRuneLen = 3
+	case charset.CharsetLatin1:
+		// The latin1 of MySQL is cp1252.
+		e.enc = charmap.Windows1252
+	case charset.CharsetGBK:
+		e.enc = simplifiedchinese.GBK
+	}
+	return e
+}
+
+// Name returns the name of the charset.
+func (e *Encoding) Name() string {
+	return e.name
+}
+
+// IsIdentity returns whether the strings are the same in UTF-8 and the charset.
+func (e *Encoding) IsIdentity() bool {
+	return e.enc == nil && e.maxRuneLen == 0
+}
+
+// Encode appends the UTF-8 string src converted to the charset to dst.
+func (e *Encoding) Encode(dst []byte, src string) []byte {
+	if e.IsIdentity() {
+		return append(dst, src...)
+	}
+	var encoder *encoding.Encoder
+	var buf [4]byte
+	for i := 0; i < len(src); {
+		r, size := utf8.DecodeRuneInString(src[i:])
+		switch {
+		case r < utf8.RuneSelf:
+			dst = append(dst, byte(r))
+		case r == utf8.RuneError && size == 1:
+			dst = append(dst, replacementChar)
+		case e.enc == nil:
+			if size > e.maxRuneLen {
+				dst = append(dst, replacementChar)
+			} else {
+				dst = append(dst, src[i:i+size]...)
+			}
+		default:
+			if encoder == nil {
+				encoder = e.enc.NewEncoder()
+			}
+			n, _, err := encoder.Transform(buf[:], []byte(src[i:i+size]), true)
+			if err != nil {
+				dst = append(dst, replacementChar)
+			} else {
+				dst = append(dst, buf[:n]...)
+			}
+		}
+		i += size
+	}
+	return dst
+}
+
+// Decode converts the bytes in the charset to a UTF-8 string, the invalid bytes are replaced.
+func (e *Encoding) Decode(src []byte) string {
+	switch {
+	case e.enc != nil:
+		if b, err := e.enc.NewDecoder().Bytes(src); err == nil {
+			return string(b)
+		}
+	case e.maxRuneLen == 1:
+		for _, c := range src {
+			if c >= utf8.RuneSelf {
+				return strings.Map(func(r rune) rune {
+					if r >= utf8.RuneSelf {
+						return replacementChar
+					}
+					return r
+				}, string(src))
+			}
+		}
+	}
+	return string(src)
+}
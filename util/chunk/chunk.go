@@ -1,15 +1,409 @@
 package chunk
 
-// Chunk define the struct
-// store data in Apache Arrow Format
+import (
+	"unsafe"
+
+	"grant-db/types"
+	"grant-db/types/json"
+)
+
+// Chunk stores multiple rows of data in Apache Arrow format.
+// See https://arrow.apache.org/docs/format/Columnar.html
+// Values are appended in compact format and can be directly accessed without decoding.
+// When the chunk is done processing, we can reuse the allocated memory by resetting it.
 type Chunk struct {
-	// Indicate which rows are selected
+	// sel indicates which rows are selected.
+	// If it is nil, all rows are selected.
 	sel []int
-	//TODO Grant: columns
 
+	columns []*Column
+	// numVirtualRows indicates the number of virtual rows, which have zero Column.
+	// It is used only when this Chunk doesn't hold any data, i.e. "len(columns)==0".
 	numVirtualRows int
-
+	// capacity indicates the max number of rows this chunk can hold.
 	capacity int
 
+	// requiredRows indicates how many rows the parent executor want.
 	requiredRows int
 }
+
+// Capacity constants.
+const (
+	InitialCapacity = 32
+	ZeroCapacity    = 0
+)
+
+// NewChunkWithCapacity creates a new chunk with field types and capacity.
+func NewChunkWithCapacity(fields []*types.FieldType, cap int) *Chunk {
+	return New(fields, cap, cap)
+}
+
+// New creates a new chunk.
+//  cap: the limit for the max number of rows.
+//  maxChunkSize: the max limit for the number of rows.
+func New(fields []*types.FieldType, cap, maxChunkSize int) *Chunk {
+	chk := &Chunk{
+		columns:  make([]*Column, 0, len(fields)),
+		capacity: mathMin(cap, maxChunkSize),
+		// set the default value of requiredRows to maxChunkSize to let chk.IsFull() behave
+		// like how we judge whether a chunk is full now, then the statement
+		// "chk.NumRows() < maxChunkSize"
+		// equals to "!chk.IsFull()".
+		requiredRows: maxChunkSize,
+	}
+	for _, f := range fields {
+		chk.columns = append(chk.columns, NewColumn(f, chk.capacity))
+	}
+	return chk
+}
+
+// Renew creates a new Chunk based on an existing Chunk. The newly created Chunk
+// has the same data schema with the old Chunk. The capacity of the new Chunk
+// might be doubled based on the capacity of the old Chunk and the maxChunkSize.
+//  chk: old chunk(often used in previous call).
+//  maxChunkSize: the limit for the max number of rows.
+func Renew(chk *Chunk, maxChunkSize int) *Chunk {
+	newCap := reCalcCapacity(chk, maxChunkSize)
+	newChk := new(Chunk)
+	if chk.columns == nil {
+		return newChk
+	}
+	newChk.columns = renewColumns(chk.columns, newCap)
+	newChk.numVirtualRows = 0
+	newChk.capacity = newCap
+	newChk.requiredRows = maxChunkSize
+	return newChk
+}
+
+// renewColumns creates the columns of a Chunk. The capacity of the newly
+// created columns is equal to cap.
+func renewColumns(oldCol []*Column, cap int) []*Column {
+	columns := make([]*Column, 0, len(oldCol))
+	for _, col := range oldCol {
+		columns = append(columns, newColumn(col.typeSize(), cap))
+	}
+	return columns
+}
+
+// reCalcCapacity calculates the capacity for another Chunk based on the current
+// Chunk. The new capacity is doubled only when the current Chunk is full.
+func reCalcCapacity(c *Chunk, maxChunkSize int) int {
+	if c.NumRows() < c.capacity {
+		return c.capacity
+	}
+	return mathMin(c.capacity*2, maxChunkSize)
+}
+
+// Capacity returns the capacity of the Chunk.
+func (c *Chunk) Capacity() int {
+	return c.capacity
+}
+
+// NumCols returns the number of columns in the chunk.
+func (c *Chunk) NumCols() int {
+	return len(c.columns)
+}
+
+// NumRows returns the number of rows in the chunk.
+func (c *Chunk) NumRows() int {
+	if c.sel != nil {
+		return len(c.sel)
+	}
+	if c.NumCols() == 0 {
+		return c.numVirtualRows
+	}
+	return c.columns[0].length
+}
+
+// GetRow gets the Row in the chunk with the row index.
+func (c *Chunk) GetRow(idx int) Row {
+	if c.sel != nil {
+		// mapping the logical RowIdx to the actual physical RowIdx;
+		// for example, if the Sel is [1, 5, 6], then
+		//	logical 0 -> physical 1,
+		//	logical 1 -> physical 5,
+		//	logical 2 -> physical 6.
+		// Then when we iterate this Chunk according to Row, only selected rows will be
+		// accessed while all filtered rows will be ignored.
+		return Row{c: c, idx: c.sel[idx]}
+	}
+	return Row{c: c, idx: idx}
+}
+
+// Column returns the specific column.
+func (c *Chunk) Column(colIdx int) *Column {
+	return c.columns[colIdx]
+}
+
+// SetCol sets the colIdx Column to col and returns the old Column.
+func (c *Chunk) SetCol(colIdx int, col *Column) *Column {
+	if col == c.columns[colIdx] {
+		return nil
+	}
+	old := c.columns[colIdx]
+	c.columns[colIdx] = col
+	return old
+}
+
+// Sel returns Sel of this Chunk.
+func (c *Chunk) Sel() []int {
+	return c.sel
+}
+
+// SetSel sets a Sel for this Chunk.
+func (c *Chunk) SetSel(sel []int) {
+	c.sel = sel
+}
+
+// SetNumVirtualRows sets the virtual row number for a Chunk.
+// It should only be used when there exists no Column in the Chunk.
+func (c *Chunk) SetNumVirtualRows(numVirtualRows int) {
+	c.numVirtualRows = numVirtualRows
+}
+
+// SetRequiredRows sets the number of required rows.
+func (c *Chunk) SetRequiredRows(requiredRows, maxChunkSize int) *Chunk {
+	if requiredRows <= 0 || requiredRows > maxChunkSize {
+		requiredRows = maxChunkSize
+	}
+	c.requiredRows = requiredRows
+	return c
+}
+
+// RequiredRows returns how many rows is considered full.
+func (c *Chunk) RequiredRows() int {
+	return c.requiredRows
+}
+
+// IsFull returns if this chunk is considered full.
+func (c *Chunk) IsFull() bool {
+	return c.NumRows() >= c.requiredRows
+}
+
+// Reset resets the chunk, so the memory it allocated can be reused.
+// Make sure all the data in the chunk is not used anymore before you reuse this chunk.
+func (c *Chunk) Reset() {
+	c.sel = nil
+	if c.columns == nil {
+		return
+	}
+	for _, col := range c.columns {
+		col.Reset()
+	}
+	c.numVirtualRows = 0
+}
+
+// MemoryUsage returns the total memory usage of a Chunk in bytes, including the unused capacity.
+func (c *Chunk) MemoryUsage() (sum int64) {
+	for _, col := range c.columns {
+		sum += int64(unsafe.Sizeof(*col)) + int64(cap(col.nullBitmap)) + int64(cap(col.offsets)*sizeInt64) +
+			int64(cap(col.data)) + int64(cap(col.elemBuf))
+	}
+	return
+}
+
+// CopyConstruct creates a new chunk and copies this chunk's data into it.
+func (c *Chunk) CopyConstruct() *Chunk {
+	newChk := &Chunk{numVirtualRows: c.numVirtualRows, capacity: c.capacity, columns: make([]*Column, len(c.columns))}
+	for i := range c.columns {
+		newChk.columns[i] = c.columns[i].CopyConstruct(nil)
+	}
+	if c.sel != nil {
+		newChk.sel = make([]int, len(c.sel))
+		copy(newChk.sel, c.sel)
+	}
+	return newChk
+}
+
+// GrowAndReset resets the Chunk and doubles the capacity of the Chunk.
+// The doubled capacity should not be larger than maxChunkSize.
+func (c *Chunk) GrowAndReset(maxChunkSize int) {
+	c.sel = nil
+	if c.columns == nil {
+		return
+	}
+	newCap := reCalcCapacity(c, maxChunkSize)
+	if newCap <= c.capacity {
+		c.Reset()
+		return
+	}
+	c.capacity = newCap
+	c.columns = renewColumns(c.columns, newCap)
+	c.numVirtualRows = 0
+	c.requiredRows = maxChunkSize
+}
+
+// SwapColumns swaps columns with another Chunk.
+func (c *Chunk) SwapColumns(other *Chunk) {
+	c.sel, other.sel = other.sel, c.sel
+	c.columns, other.columns = other.columns, c.columns
+	c.numVirtualRows, other.numVirtualRows = other.numVirtualRows, c.numVirtualRows
+}
+
+// MakeRef makes Column in "dstColIdx" reference to Column in "srcColIdx".
+func (c *Chunk) MakeRef(srcColIdx, dstColIdx int) {
+	c.columns[dstColIdx] = c.columns[srcColIdx]
+}
+
+// MakeRefTo copies columns `src.columns[srcColIdx]` to `c.columns[dstColIdx]`.
+func (c *Chunk) MakeRefTo(dstColIdx int, src *Chunk, srcColIdx int) {
+	c.columns[dstColIdx] = src.columns[srcColIdx]
+}
+
+// Prune creates a new Chunk according to `c` and prunes the columns
+// whose index is not in `usedColIdxs`
+func (c *Chunk) Prune(usedColIdxs []int) *Chunk {
+	chk := &Chunk{columns: make([]*Column, 0, len(usedColIdxs)), capacity: c.capacity, requiredRows: c.requiredRows}
+	for _, idx := range usedColIdxs {
+		chk.columns = append(chk.columns, c.columns[idx])
+	}
+	chk.sel = c.sel
+	return chk
+}
+
+// AppendRow appends a row to the chunk.
+func (c *Chunk) AppendRow(row Row) {
+	c.AppendPartialRow(0, row)
+	c.numVirtualRows++
+}
+
+// AppendPartialRow appends a row to the chunk, starting from the colOff column.
+func (c *Chunk) AppendPartialRow(colOff int, row Row) {
+	c.appendSel(colOff)
+	for i, rowCol := range row.c.columns {
+		c.columns[colOff+i].appendRaw(rowCol, row.idx)
+	}
+}
+
+// appendSel appends a selected row to the chunk, the columns on the left of colIdx
+// have been filled by the caller.
+func (c *Chunk) appendSel(colIdx int) {
+	if colIdx == 0 && c.sel != nil { // use column 0 as standard
+		c.sel = append(c.sel, c.columns[0].length)
+	}
+}
+
+// Append appends rows in [begin, end) in another Chunk to a Chunk.
+func (c *Chunk) Append(other *Chunk, begin, end int) {
+	for i := begin; i < end; i++ {
+		c.AppendRow(other.GetRow(i))
+	}
+}
+
+// AppendNull appends a null value to the chunk.
+func (c *Chunk) AppendNull(colIdx int) {
+	c.appendSel(colIdx)
+	c.columns[colIdx].AppendNull()
+}
+
+// AppendInt64 appends a int64 value to the chunk.
+func (c *Chunk) AppendInt64(colIdx int, i int64) {
+	c.appendSel(colIdx)
+	c.columns[colIdx].AppendInt64(i)
+}
+
+// AppendUint64 appends a uint64 value to the chunk.
+func (c *Chunk) AppendUint64(colIdx int, u uint64) {
+	c.appendSel(colIdx)
+	c.columns[colIdx].AppendUint64(u)
+}
+
+// AppendFloat32 appends a float32 value to the chunk.
+func (c *Chunk) AppendFloat32(colIdx int, f float32) {
+	c.appendSel(colIdx)
+	c.columns[colIdx].AppendFloat32(f)
+}
+
+// AppendFloat64 appends a float64 value to the chunk.
+func (c *Chunk) AppendFloat64(colIdx int, f float64) {
+	c.appendSel(colIdx)
+	c.columns[colIdx].AppendFloat64(f)
+}
+
+// AppendString appends a string value to the chunk.
+func (c *Chunk) AppendString(colIdx int, str string) {
+	c.appendSel(colIdx)
+	c.columns[colIdx].AppendString(str)
+}
+
+// AppendBytes appends a bytes value to the chunk.
+func (c *Chunk) AppendBytes(colIdx int, b []byte) {
+	c.appendSel(colIdx)
+	c.columns[colIdx].AppendBytes(b)
+}
+
+// AppendTime appends a Time value to the chunk.
+func (c *Chunk) AppendTime(colIdx int, t types.Time) {
+	c.appendSel(colIdx)
+	c.columns[colIdx].AppendTime(t)
+}
+
+// AppendMyDecimal appends a MyDecimal value to the chunk.
+func (c *Chunk) AppendMyDecimal(colIdx int, dec *types.MyDecimal) {
+	c.appendSel(colIdx)
+	c.columns[colIdx].AppendMyDecimal(dec)
+}
+
+// AppendDuration appends a Duration value to the chunk.
+func (c *Chunk) AppendDuration(colIdx int, dur types.Duration) {
+	c.appendSel(colIdx)
+	c.columns[colIdx].AppendDuration(dur)
+}
+
+// AppendJSON appends a JSON value to the chunk.
+func (c *Chunk) AppendJSON(colIdx int, j json.BinaryJSON) {
+	c.appendSel(colIdx)
+	c.columns[colIdx].AppendJSON(j)
+}
+
+// AppendDatum appends a datum into the chunk.
+func (c *Chunk) AppendDatum(colIdx int, d *types.Datum) {
+	switch d.Kind() {
+	case types.KindNull:
+		c.AppendNull(colIdx)
+	case types.KindInt64:
+		c.AppendInt64(colIdx, d.GetInt64())
+	case types.KindUint64:
+		c.AppendUint64(colIdx, d.GetUint64())
+	case types.KindFloat64:
+		c.appendFloat(colIdx, d.GetFloat64())
+	case types.KindString:
+		c.AppendString(colIdx, d.GetString())
+	case types.KindBytes:
+		c.AppendBytes(colIdx, d.GetBytes())
+	case types.KindMysqlDecimal:
+		c.AppendMyDecimal(colIdx, d.GetMysqlDecimal())
+	case types.KindMysqlTime:
+		c.AppendTime(colIdx, d.GetMysqlTime())
+	case types.KindMysqlDuration:
+		c.AppendDuration(colIdx, d.GetMysqlDuration())
+	case types.KindMysqlEnum:
+		c.AppendString(colIdx, d.GetMysqlEnum().Name)
+	case types.KindMysqlSet:
+		c.AppendString(colIdx, d.GetMysqlSet().Name)
+	case types.KindMysqlBit:
+		// The value of a BIT column fits uint64.
+		val, _ := d.GetMysqlBit().ToInt(nil)
+		c.AppendUint64(colIdx, val)
+	case types.KindBinaryLiteral:
+		c.AppendBytes(colIdx, d.GetBinaryLiteral())
+	case types.KindMysqlJSON:
+		c.AppendJSON(colIdx, d.GetMysqlJSON())
+	}
+}
+
+// appendFloat appends a float64 datum value, a float column holds float32 elements.
+func (c *Chunk) appendFloat(colIdx int, f float64) {
+	if c.columns[colIdx].typeSize() == sizeFloat32 {
+		c.AppendFloat32(colIdx, float32(f))
+		return
+	}
+	c.AppendFloat64(colIdx, f)
+}
+
+func mathMin(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,526 @@
+package chunk
+
+import (
+	"reflect"
+	"time"
+	"unsafe"
+
+	"github.com/pingcap/parser/mysql"
+	"grant-db/types"
+	"grant-db/types/json"
+)
+
+const (
+	sizeInt64   = int(unsafe.Sizeof(int64(0)))
+	sizeUint64  = int(unsafe.Sizeof(uint64(0)))
+	sizeFloat32 = int(unsafe.Sizeof(float32(0)))
+	sizeFloat64 = int(unsafe.Sizeof(float64(0)))
+	sizeTime    = int(unsafe.Sizeof(types.Time{}))
+)
+
+// getFixedLen returns the element length of the fixed-length field type, -1 is returned
+// for the variable-length types.
+func getFixedLen(colType *types.FieldType) int {
+	switch colType.Tp {
+	case mysql.TypeFloat:
+		return sizeFloat32
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong,
+		mysql.TypeLonglong, mysql.TypeDouble, mysql.TypeYear, mysql.TypeDuration:
+		return sizeInt64
+	case mysql.TypeBit:
+		// A BIT value is held in its uint64 form.
+		return sizeUint64
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		return sizeTime
+	}
+	return -1
+}
+
+// Column stores one column of data in Apache Arrow format.
+// See https://arrow.apache.org/docs/format/Columnar.html
+type Column struct {
+	length     int
+	nullBitmap []byte // bit 1 means not null.
+	offsets    []int64
+	data       []byte
+	elemBuf    []byte
+}
+
+// NewColumn creates a new column with the specific type and capacity.
+func NewColumn(ft *types.FieldType, cap int) *Column {
+	return newColumn(getFixedLen(ft), cap)
+}
+
+func newColumn(typeSize, cap int) *Column {
+	if typeSize == -1 {
+		return newVarLenColumn(cap)
+	}
+	return newFixedLenColumn(typeSize, cap)
+}
+
+func newFixedLenColumn(elemLen, cap int) *Column {
+	return &Column{
+		elemBuf:    make([]byte, elemLen),
+		data:       make([]byte, 0, cap*elemLen),
+		nullBitmap: make([]byte, 0, (cap+7)>>3),
+	}
+}
+
+func newVarLenColumn(cap int) *Column {
+	return &Column{
+		offsets:    make([]int64, 1, cap+1),
+		data:       make([]byte, 0, cap*8),
+		nullBitmap: make([]byte, 0, (cap+7)>>3),
+	}
+}
+
+// typeSize returns the size of the fixed length elements, or -1 for the var length ones.
+func (c *Column) typeSize() int {
+	if c.isFixed() {
+		return len(c.elemBuf)
+	}
+	return -1
+}
+
+func (c *Column) isFixed() bool {
+	return c.elemBuf != nil
+}
+
+// Reset resets this Column.
+func (c *Column) Reset() {
+	c.length = 0
+	c.nullBitmap = c.nullBitmap[:0]
+	if len(c.offsets) > 0 {
+		// The first offset is always 0, it makes slicing the data easier, we need to keep it.
+		c.offsets = c.offsets[:1]
+	}
+	c.data = c.data[:0]
+}
+
+// IsNull returns if this row is null.
+func (c *Column) IsNull(rowIdx int) bool {
+	nullByte := c.nullBitmap[rowIdx/8]
+	return nullByte&(1<<(uint(rowIdx)&7)) == 0
+}
+
+// CopyConstruct copies this Column to dst.
+// If dst is nil, it creates a new Column and returns it.
+func (c *Column) CopyConstruct(dst *Column) *Column {
+	if dst != nil {
+		dst.length = c.length
+		dst.nullBitmap = append(dst.nullBitmap[:0], c.nullBitmap...)
+		dst.offsets = append(dst.offsets[:0], c.offsets...)
+		dst.data = append(dst.data[:0], c.data...)
+		dst.elemBuf = append(dst.elemBuf[:0], c.elemBuf...)
+		return dst
+	}
+	newCol := &Column{length: c.length}
+	newCol.nullBitmap = append(newCol.nullBitmap, c.nullBitmap...)
+	newCol.offsets = append(newCol.offsets, c.offsets...)
+	newCol.data = append(newCol.data, c.data...)
+	newCol.elemBuf = append(newCol.elemBuf, c.elemBuf...)
+	return newCol
+}
+
+// CopyReconstruct copies this Column to dst and removes unselected rows.
+// If dst is nil, it creates a new Column and returns it.
+func (c *Column) CopyReconstruct(sel []int, dst *Column) *Column {
+	if sel == nil {
+		return c.CopyConstruct(dst)
+	}
+	if dst == nil {
+		if c.isFixed() {
+			dst = newFixedLenColumn(c.typeSize(), len(sel))
+		} else {
+			dst = newVarLenColumn(len(sel))
+		}
+	} else {
+		dst.Reset()
+	}
+	if c.isFixed() {
+		elemLen := len(c.elemBuf)
+		for _, i := range sel {
+			dst.appendNullBitmap(!c.IsNull(i))
+			dst.data = append(dst.data, c.data[i*elemLen:i*elemLen+elemLen]...)
+			dst.length++
+		}
+		dst.elemBuf = append(dst.elemBuf[:0], c.elemBuf...)
+		return dst
+	}
+	if len(dst.offsets) == 0 {
+		dst.offsets = append(dst.offsets, 0)
+	}
+	dst.elemBuf = nil
+	for _, i := range sel {
+		dst.appendNullBitmap(!c.IsNull(i))
+		start, end := c.offsets[i], c.offsets[i+1]
+		dst.data = append(dst.data, c.data[start:end]...)
+		dst.offsets = append(dst.offsets, int64(len(dst.data)))
+		dst.length++
+	}
+	return dst
+}
+
+func (c *Column) appendNullBitmap(notNull bool) {
+	idx := c.length >> 3
+	if idx >= len(c.nullBitmap) {
+		c.nullBitmap = append(c.nullBitmap, 0)
+	}
+	if notNull {
+		pos := uint(c.length) & 7
+		c.nullBitmap[idx] |= byte(1 << pos)
+	}
+}
+
+// appendMultiSameNullBitmap appends multiple same bit value to `nullBitMap`.
+// notNull means not null.
+// num means the number of bits that should be appended.
+func (c *Column) appendMultiSameNullBitmap(notNull bool, num int) {
+	numNewBytes := ((c.length + num + 7) >> 3) - len(c.nullBitmap)
+	b := byte(0)
+	if notNull {
+		b = 0xff
+	}
+	for i := 0; i < numNewBytes; i++ {
+		c.nullBitmap = append(c.nullBitmap, b)
+	}
+	if !notNull {
+		return
+	}
+	// 1. Set all the remaining bits in the last slot of old c.numBitMap to 1.
+	numRemainingBits := uint(c.length % 8)
+	bitMask := byte(^((1 << numRemainingBits) - 1))
+	c.nullBitmap[c.length/8] |= bitMask
+	// 2. Set all the redundant bits in the last slot of new c.numBitMap to 0.
+	numRedundantBits := uint(len(c.nullBitmap)*8 - c.length - num)
+	bitMask = byte(1<<(8-numRedundantBits)) - 1
+	c.nullBitmap[len(c.nullBitmap)-1] &= bitMask
+}
+
+// AppendNull appends a null value into this Column.
+func (c *Column) AppendNull() {
+	c.appendNullBitmap(false)
+	if c.isFixed() {
+		c.data = append(c.data, c.elemBuf...)
+	} else {
+		c.offsets = append(c.offsets, c.offsets[c.length])
+	}
+	c.length++
+}
+
+func (c *Column) finishAppendFixed() {
+	c.data = append(c.data, c.elemBuf...)
+	c.appendNullBitmap(true)
+	c.length++
+}
+
+// AppendInt64 appends an int64 value into this Column.
+func (c *Column) AppendInt64(i int64) {
+	*(*int64)(unsafe.Pointer(&c.elemBuf[0])) = i
+	c.finishAppendFixed()
+}
+
+// AppendUint64 appends a uint64 value into this Column.
+func (c *Column) AppendUint64(u uint64) {
+	*(*uint64)(unsafe.Pointer(&c.elemBuf[0])) = u
+	c.finishAppendFixed()
+}
+
+// AppendFloat32 appends a float32 value into this Column.
+func (c *Column) AppendFloat32(f float32) {
+	*(*float32)(unsafe.Pointer(&c.elemBuf[0])) = f
+	c.finishAppendFixed()
+}
+
+// AppendFloat64 appends a float64 value into this Column.
+func (c *Column) AppendFloat64(f float64) {
+	*(*float64)(unsafe.Pointer(&c.elemBuf[0])) = f
+	c.finishAppendFixed()
+}
+
+// AppendTime appends a time value into this Column.
+func (c *Column) AppendTime(t types.Time) {
+	*(*types.Time)(unsafe.Pointer(&c.elemBuf[0])) = t
+	c.finishAppendFixed()
+}
+
+// AppendDuration appends a duration value into this Column, the fsp is kept by the field type.
+func (c *Column) AppendDuration(dur types.Duration) {
+	c.AppendInt64(int64(dur.Duration))
+}
+
+func (c *Column) finishAppendVar() {
+	c.appendNullBitmap(true)
+	c.offsets = append(c.offsets, int64(len(c.data)))
+	c.length++
+}
+
+// AppendString appends a string value into this Column.
+func (c *Column) AppendString(str string) {
+	c.data = append(c.data, str...)
+	c.finishAppendVar()
+}
+
+// AppendBytes appends a bytes value into this Column.
+func (c *Column) AppendBytes(b []byte) {
+	c.data = append(c.data, b...)
+	c.finishAppendVar()
+}
+
+// AppendMyDecimal appends a MyDecimal value into this Column.
+func (c *Column) AppendMyDecimal(dec *types.MyDecimal) {
+	c.data = dec.AppendBinary(c.data)
+	c.finishAppendVar()
+}
+
+// AppendJSON appends a BinaryJSON value into this Column, the type code is followed by the value.
+func (c *Column) AppendJSON(j json.BinaryJSON) {
+	c.data = append(c.data, j.TypeCode)
+	c.data = append(c.data, j.Value...)
+	c.finishAppendVar()
+}
+
+// appendRaw appends the raw element of row rowIdx in src.
+func (c *Column) appendRaw(src *Column, rowIdx int) {
+	if src.IsNull(rowIdx) {
+		c.AppendNull()
+		return
+	}
+	if c.isFixed() {
+		elemLen := len(c.elemBuf)
+		offset := rowIdx * elemLen
+		c.data = append(c.data, src.data[offset:offset+elemLen]...)
+		c.appendNullBitmap(true)
+		c.length++
+		return
+	}
+	start, end := src.offsets[rowIdx], src.offsets[rowIdx+1]
+	c.data = append(c.data, src.data[start:end]...)
+	c.finishAppendVar()
+}
+
+// GetInt64 returns the int64 in the specific row.
+func (c *Column) GetInt64(rowID int) int64 {
+	return *(*int64)(unsafe.Pointer(&c.data[rowID*8]))
+}
+
+// GetUint64 returns the uint64 in the specific row.
+func (c *Column) GetUint64(rowID int) uint64 {
+	return *(*uint64)(unsafe.Pointer(&c.data[rowID*8]))
+}
+
+// GetFloat32 returns the float32 in the specific row.
+func (c *Column) GetFloat32(rowID int) float32 {
+	return *(*float32)(unsafe.Pointer(&c.data[rowID*4]))
+}
+
+// GetFloat64 returns the float64 in the specific row.
+func (c *Column) GetFloat64(rowID int) float64 {
+	return *(*float64)(unsafe.Pointer(&c.data[rowID*8]))
+}
+
+// GetTime returns the Time in the specific row.
+func (c *Column) GetTime(rowID int) types.Time {
+	return *(*types.Time)(unsafe.Pointer(&c.data[rowID*sizeTime]))
+}
+
+// GetDuration returns the Duration in the specific row.
+func (c *Column) GetDuration(rowID int, fillFsp int) types.Duration {
+	dur := *(*int64)(unsafe.Pointer(&c.data[rowID*8]))
+	return types.Duration{Duration: time.Duration(dur), Fsp: int8(fillFsp)}
+}
+
+// GetString returns the string in the specific row.
+func (c *Column) GetString(rowID int) string {
+	return string(c.data[c.offsets[rowID]:c.offsets[rowID+1]])
+}
+
+// GetBytes returns the byte slice in the specific row.
+func (c *Column) GetBytes(rowID int) []byte {
+	return c.data[c.offsets[rowID]:c.offsets[rowID+1]]
+}
+
+// GetDecimal returns the decimal in the specific row.
+func (c *Column) GetDecimal(rowID int) *types.MyDecimal {
+	dec := new(types.MyDecimal)
+	_ = dec.FromBinary(c.GetBytes(rowID))
+	return dec
+}
+
+// GetJSON returns the BinaryJSON in the specific row.
+func (c *Column) GetJSON(rowID int) json.BinaryJSON {
+	start := c.offsets[rowID]
+	return json.BinaryJSON{TypeCode: c.data[start], Value: c.data[start+1 : c.offsets[rowID+1]]}
+}
+
+func (c *Column) castSliceHeader(header *reflect.SliceHeader, typeSize int) {
+	header.Data = (*reflect.SliceHeader)(unsafe.Pointer(&c.data)).Data
+	header.Len = c.length
+	header.Cap = cap(c.data) / typeSize
+}
+
+// Int64s returns an int64 slice stored in this Column.
+func (c *Column) Int64s() []int64 {
+	var res []int64
+	c.castSliceHeader((*reflect.SliceHeader)(unsafe.Pointer(&res)), sizeInt64)
+	return res
+}
+
+// Uint64s returns a uint64 slice stored in this Column.
+func (c *Column) Uint64s() []uint64 {
+	var res []uint64
+	c.castSliceHeader((*reflect.SliceHeader)(unsafe.Pointer(&res)), sizeUint64)
+	return res
+}
+
+// Float32s returns a float32 slice stored in this Column.
+func (c *Column) Float32s() []float32 {
+	var res []float32
+	c.castSliceHeader((*reflect.SliceHeader)(unsafe.Pointer(&res)), sizeFloat32)
+	return res
+}
+
+// Float64s returns a float64 slice stored in this Column.
+func (c *Column) Float64s() []float64 {
+	var res []float64
+	c.castSliceHeader((*reflect.SliceHeader)(unsafe.Pointer(&res)), sizeFloat64)
+	return res
+}
+
+// Times returns a Time slice stored in this Column.
+func (c *Column) Times() []types.Time {
+	var res []types.Time
+	c.castSliceHeader((*reflect.SliceHeader)(unsafe.Pointer(&res)), sizeTime)
+	return res
+}
+
+// SetNull sets the rowIdx to null.
+func (c *Column) SetNull(rowIdx int, isNull bool) {
+	if isNull {
+		c.nullBitmap[rowIdx>>3] &= ^(1 << uint(rowIdx&7))
+	} else {
+		c.nullBitmap[rowIdx>>3] |= 1 << uint(rowIdx&7)
+	}
+}
+
+// SetNulls sets rows in [begin, end) to null.
+func (c *Column) SetNulls(begin, end int, isNull bool) {
+	for i := begin; i < end; i++ {
+		c.SetNull(i, isNull)
+	}
+}
+
+// NullCount returns the number of nulls in this Column.
+func (c *Column) NullCount() int {
+	cnt := 0
+	for i := 0; i < c.length; i++ {
+		if c.IsNull(i) {
+			cnt++
+		}
+	}
+	return cnt
+}
+
+// resize resizes the fixed-length Column to n rows, the values are undefined and the
+// null flags are set by isNull.
+func (c *Column) resize(n, typeSize int, isNull bool) {
+	sizeData := n * typeSize
+	if cap(c.data) >= sizeData {
+		c.data = c.data[:sizeData]
+	} else {
+		c.data = make([]byte, sizeData)
+	}
+
+	sizeNulls := (n + 7) >> 3
+	if cap(c.nullBitmap) >= sizeNulls {
+		c.nullBitmap = c.nullBitmap[:sizeNulls]
+	} else {
+		c.nullBitmap = make([]byte, sizeNulls)
+	}
+	b := byte(0xff)
+	if isNull {
+		b = 0
+	}
+	for i := range c.nullBitmap {
+		c.nullBitmap[i] = b
+	}
+	c.length = n
+	if cap(c.elemBuf) < typeSize {
+		c.elemBuf = make([]byte, typeSize)
+	}
+	c.elemBuf = c.elemBuf[:typeSize]
+}
+
+// ResizeInt64 resizes the column so that it contains n int64 elements.
+func (c *Column) ResizeInt64(n int, isNull bool) {
+	c.resize(n, sizeInt64, isNull)
+}
+
+// ResizeUint64 resizes the column so that it contains n uint64 elements.
+func (c *Column) ResizeUint64(n int, isNull bool) {
+	c.resize(n, sizeUint64, isNull)
+}
+
+// ResizeFloat32 resizes the column so that it contains n float32 elements.
+func (c *Column) ResizeFloat32(n int, isNull bool) {
+	c.resize(n, sizeFloat32, isNull)
+}
+
+// ResizeFloat64 resizes the column so that it contains n float64 elements.
+func (c *Column) ResizeFloat64(n int, isNull bool) {
+	c.resize(n, sizeFloat64, isNull)
+}
+
+// ResizeTime resizes the column so that it contains n Time elements.
+func (c *Column) ResizeTime(n int, isNull bool) {
+	c.resize(n, sizeTime, isNull)
+}
+
+// ReserveString changes the column capacity to store n string elements and set the length to zero.
+func (c *Column) ReserveString(n int) {
+	c.reserve(n, 8)
+}
+
+// ReserveBytes changes the column capacity to store n bytes elements and set the length to zero.
+func (c *Column) ReserveBytes(n int) {
+	c.reserve(n, 8)
+}
+
+// ReserveDecimal changes the column capacity to store n decimal elements and set the length to zero.
+func (c *Column) ReserveDecimal(n int) {
+	c.reserve(n, 8)
+}
+
+// ReserveJSON changes the column capacity to store n JSON elements and set the length to zero.
+func (c *Column) ReserveJSON(n int) {
+	c.reserve(n, 8)
+}
+
+func (c *Column) reserve(n, estElemSize int) {
+	nData := n * estElemSize
+	if cap(c.data) < nData {
+		c.data = make([]byte, 0, nData)
+	}
+	if cap(c.nullBitmap) < (n+7)>>3 {
+		c.nullBitmap = make([]byte, 0, (n+7)>>3)
+	}
+	if cap(c.offsets) < n+1 {
+		c.offsets = make([]int64, 1, n+1)
+	}
+	c.elemBuf = nil
+	c.Reset()
+}
+
+// MergeNulls merges these columns' null bitmaps.
+// For a row, if any column of it is null, the result is null.
+// It works like: if col1.IsNull || col2.IsNull || col3.IsNull.
+// The caller should ensure that all these columns have the same
+// length, and data stored in the result column is fixed-length type.
+func (c *Column) MergeNulls(cols ...*Column) {
+	for _, col := range cols {
+		for i := range c.nullBitmap {
+			// bit 0 is null, 1 is not null, so do AND operations here.
+			c.nullBitmap[i] &= col.nullBitmap[i]
+		}
+	}
+}
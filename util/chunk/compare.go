@@ -0,0 +1,158 @@
+package chunk
+
+import (
+	"github.com/pingcap/parser/mysql"
+	"grant-db/types"
+	"grant-db/types/json"
+	"grant-db/util/collate"
+)
+
+// CompareFunc is a function to compare the two values in Row, the two columns must have the same type.
+type CompareFunc = func(l Row, lCol int, r Row, rCol int) int
+
+// GetCompareFunc gets a compare function for the field type, the strings are compared by the collation
+// of the field type, and NULL is less than any value.
+func GetCompareFunc(tp *types.FieldType) CompareFunc {
+	switch tp.Tp {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong, mysql.TypeYear:
+		if mysql.HasUnsignedFlag(tp.Flag) {
+			return cmpUint64
+		}
+		return cmpInt64
+	case mysql.TypeFloat:
+		return cmpFloat32
+	case mysql.TypeDouble:
+		return cmpFloat64
+	case mysql.TypeString, mysql.TypeVarString, mysql.TypeVarchar,
+		mysql.TypeBlob, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
+		collator := collate.GetCollator(tp.Collate)
+		return func(l Row, lCol int, r Row, rCol int) int {
+			if cmp, ok := cmpNull(l.IsNull(lCol), r.IsNull(rCol)); ok {
+				return cmp
+			}
+			return collator.Compare(l.GetString(lCol), r.GetString(rCol))
+		}
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		return cmpTime
+	case mysql.TypeDuration:
+		return cmpDuration
+	case mysql.TypeNewDecimal:
+		return cmpMyDecimal
+	case mysql.TypeEnum:
+		// The items are compared by their indexes.
+		return func(l Row, lCol int, r Row, rCol int) int {
+			if cmp, ok := cmpNull(l.IsNull(lCol), r.IsNull(rCol)); ok {
+				return cmp
+			}
+			return compareUint64(l.GetEnum(lCol, tp.Elems).Value, r.GetEnum(rCol, tp.Elems).Value)
+		}
+	case mysql.TypeSet:
+		return func(l Row, lCol int, r Row, rCol int) int {
+			if cmp, ok := cmpNull(l.IsNull(lCol), r.IsNull(rCol)); ok {
+				return cmp
+			}
+			return compareUint64(l.GetSet(lCol, tp.Elems).Value, r.GetSet(rCol, tp.Elems).Value)
+		}
+	case mysql.TypeBit:
+		return cmpUint64
+	case mysql.TypeJSON:
+		return cmpJSON
+	}
+	return nil
+}
+
+// cmpNull compares the values if either of them is NULL, the second returned value is false if neither is.
+func cmpNull(lNull, rNull bool) (int, bool) {
+	switch {
+	case lNull && rNull:
+		return 0, true
+	case lNull:
+		return -1, true
+	case rNull:
+		return 1, true
+	}
+	return 0, false
+}
+
+func cmpInt64(l Row, lCol int, r Row, rCol int) int {
+	if cmp, ok := cmpNull(l.IsNull(lCol), r.IsNull(rCol)); ok {
+		return cmp
+	}
+	return compareInt64(l.GetInt64(lCol), r.GetInt64(rCol))
+}
+
+func cmpUint64(l Row, lCol int, r Row, rCol int) int {
+	if cmp, ok := cmpNull(l.IsNull(lCol), r.IsNull(rCol)); ok {
+		return cmp
+	}
+	return compareUint64(l.GetUint64(lCol), r.GetUint64(rCol))
+}
+
+func cmpFloat32(l Row, lCol int, r Row, rCol int) int {
+	if cmp, ok := cmpNull(l.IsNull(lCol), r.IsNull(rCol)); ok {
+		return cmp
+	}
+	return compareFloat64(float64(l.GetFloat32(lCol)), float64(r.GetFloat32(rCol)))
+}
+
+func cmpFloat64(l Row, lCol int, r Row, rCol int) int {
+	if cmp, ok := cmpNull(l.IsNull(lCol), r.IsNull(rCol)); ok {
+		return cmp
+	}
+	return compareFloat64(l.GetFloat64(lCol), r.GetFloat64(rCol))
+}
+
+func cmpMyDecimal(l Row, lCol int, r Row, rCol int) int {
+	if cmp, ok := cmpNull(l.IsNull(lCol), r.IsNull(rCol)); ok {
+		return cmp
+	}
+	return l.GetMyDecimal(lCol).Compare(r.GetMyDecimal(rCol))
+}
+
+func cmpTime(l Row, lCol int, r Row, rCol int) int {
+	if cmp, ok := cmpNull(l.IsNull(lCol), r.IsNull(rCol)); ok {
+		return cmp
+	}
+	return l.GetTime(lCol).Compare(r.GetTime(rCol))
+}
+
+func cmpDuration(l Row, lCol int, r Row, rCol int) int {
+	if cmp, ok := cmpNull(l.IsNull(lCol), r.IsNull(rCol)); ok {
+		return cmp
+	}
+	return compareInt64(l.GetInt64(lCol), r.GetInt64(rCol))
+}
+
+func cmpJSON(l Row, lCol int, r Row, rCol int) int {
+	if cmp, ok := cmpNull(l.IsNull(lCol), r.IsNull(rCol)); ok {
+		return cmp
+	}
+	return json.CompareBinary(l.GetJSON(lCol), r.GetJSON(rCol))
+}
+
+func compareInt64(l, r int64) int {
+	if l < r {
+		return -1
+	} else if l > r {
+		return 1
+	}
+	return 0
+}
+
+func compareUint64(l, r uint64) int {
+	if l < r {
+		return -1
+	} else if l > r {
+		return 1
+	}
+	return 0
+}
+
+func compareFloat64(l, r float64) int {
+	if l < r {
+		return -1
+	} else if l > r {
+		return 1
+	}
+	return 0
+}
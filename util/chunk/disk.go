@@ -0,0 +1,155 @@
+package chunk
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"os"
+
+	"grant-db/types"
+	"grant-db/util/memory"
+)
+
+// ListInDisk holds the rows of the chunks in a temporary file, the rows are read by the same RowPtrs as the
+// ones of a List which holds the same chunks.
+//
+// The rows are encoded column by column, a column is a byte of the NULL flag followed by the raw value, which
+// is prefixed by the length if the column isn't of a fixed length.
+type ListInDisk struct {
+	fieldTypes []*types.FieldType
+	disk       *os.File
+	// offsets are the offsets of the rows in the file, and rowIdxOfChunk are the indexes of the first rows
+	// of the chunks in offsets.
+	offsets       []int64
+	rowIdxOfChunk []int
+	offWrite      int64
+	buf           []byte
+
+	diskTracker *memory.Tracker
+}
+
+// NewListInDisk creates a new ListInDisk with field types, the file is created when the first chunk is added.
+func NewListInDisk(fieldTypes []*types.FieldType) *ListInDisk {
+	return &ListInDisk{
+		fieldTypes:  fieldTypes,
+		diskTracker: memory.NewTracker(memory.LabelForSQLText),
+	}
+}
+
+// GetDiskTracker returns the disk tracker of this ListInDisk.
+func (l *ListInDisk) GetDiskTracker() *memory.Tracker {
+	return l.diskTracker
+}
+
+// Len returns the number of rows in the ListInDisk.
+func (l *ListInDisk) Len() int {
+	return len(l.offsets)
+}
+
+// NumChunks returns the number of chunks in the ListInDisk.
+func (l *ListInDisk) NumChunks() int {
+	return len(l.rowIdxOfChunk)
+}
+
+// NumRowsOfChunk returns the number of rows of the chunk.
+func (l *ListInDisk) NumRowsOfChunk(chkIdx int) int {
+	if chkIdx == len(l.rowIdxOfChunk)-1 {
+		return len(l.offsets) - l.rowIdxOfChunk[chkIdx]
+	}
+	return l.rowIdxOfChunk[chkIdx+1] - l.rowIdxOfChunk[chkIdx]
+}
+
+// Add writes the rows of the chunk to the file, the empty chunks are ignored. The chunk can be reused by
+// the caller.
+func (l *ListInDisk) Add(chk *Chunk) (err error) {
+	if chk.NumRows() == 0 {
+		return nil
+	}
+	if l.disk == nil {
+		if l.disk, err = ioutil.TempFile("", "grant-db-spill"); err != nil {
+			return err
+		}
+	}
+	l.rowIdxOfChunk = append(l.rowIdxOfChunk, len(l.offsets))
+	l.buf = l.buf[:0]
+	for i := 0; i < chk.NumRows(); i++ {
+		l.offsets = append(l.offsets, l.offWrite+int64(len(l.buf)))
+		l.buf = appendRowToBuf(l.buf, chk.GetRow(i))
+	}
+	n, err := l.disk.WriteAt(l.buf, l.offWrite)
+	l.offWrite += int64(n)
+	l.diskTracker.Consume(int64(n))
+	return err
+}
+
+// appendRowToBuf appends the encoded columns of the row to the buffer.
+func appendRowToBuf(buf []byte, row Row) []byte {
+	for _, col := range row.c.columns {
+		if col.IsNull(row.idx) {
+			buf = append(buf, 0)
+			continue
+		}
+		buf = append(buf, 1)
+		if col.isFixed() {
+			elemLen := len(col.elemBuf)
+			buf = append(buf, col.data[row.idx*elemLen:(row.idx+1)*elemLen]...)
+			continue
+		}
+		start, end := col.offsets[row.idx], col.offsets[row.idx+1]
+		var lenBuf [binary.MaxVarintLen64]byte
+		buf = append(buf, lenBuf[:binary.PutUvarint(lenBuf[:], uint64(end-start))]...)
+		buf = append(buf, col.data[start:end]...)
+	}
+	return buf
+}
+
+// GetRow reads the row from the file, the row is in a new chunk so it's valid after the other rows are read.
+// It's safe to be called concurrently.
+func (l *ListInDisk) GetRow(ptr RowPtr) (Row, error) {
+	rowIdx := l.rowIdxOfChunk[ptr.ChkIdx] + int(ptr.RowIdx)
+	end := l.offWrite
+	if rowIdx+1 < len(l.offsets) {
+		end = l.offsets[rowIdx+1]
+	}
+	buf := make([]byte, end-l.offsets[rowIdx])
+	if _, err := l.disk.ReadAt(buf, l.offsets[rowIdx]); err != nil {
+		return Row{}, err
+	}
+	chk := NewChunkWithCapacity(l.fieldTypes, 1)
+	for _, col := range chk.columns {
+		notNull := buf[0] == 1
+		buf = buf[1:]
+		if !notNull {
+			col.AppendNull()
+			continue
+		}
+		if col.isFixed() {
+			elemLen := len(col.elemBuf)
+			col.data = append(col.data, buf[:elemLen]...)
+			buf = buf[elemLen:]
+			col.appendNullBitmap(true)
+			col.length++
+			continue
+		}
+		size, n := binary.Uvarint(buf)
+		col.data = append(col.data, buf[n:n+int(size)]...)
+		buf = buf[n+int(size):]
+		col.finishAppendVar()
+	}
+	chk.numVirtualRows = 1
+	return chk.GetRow(0), nil
+}
+
+// Close removes the file, the disk it used is released by the tracker.
+func (l *ListInDisk) Close() error {
+	l.diskTracker.Consume(-l.diskTracker.BytesConsumed())
+	if l.disk == nil {
+		return nil
+	}
+	name := l.disk.Name()
+	err := l.disk.Close()
+	if err1 := os.Remove(name); err == nil {
+		err = err1
+	}
+	l.disk = nil
+	return err
+}
@@ -0,0 +1,152 @@
+package chunk
+
+import (
+	"grant-db/types"
+	"grant-db/util/memory"
+)
+
+// List holds a slice of chunks, it's used to keep the rows of an executor in the memory.
+type List struct {
+	fieldTypes    []*types.FieldType
+	initChunkSize int
+	maxChunkSize  int
+	length        int
+	chunks        []*Chunk
+	freelist      []*Chunk
+
+	memTracker *memory.Tracker
+	// consumedIdx is the index of the last chunk whose memory is consumed by the tracker, the last chunk
+	// is consumed after it's full.
+	consumedIdx int
+}
+
+// RowPtr is the position of a row in the List, it's only valid for the List which returns it.
+type RowPtr struct {
+	ChkIdx uint32
+	RowIdx uint32
+}
+
+// NewList creates a new List with field types, init chunk size and max chunk size.
+func NewList(fieldTypes []*types.FieldType, initChunkSize, maxChunkSize int) *List {
+	l := &List{
+		fieldTypes:    fieldTypes,
+		initChunkSize: initChunkSize,
+		maxChunkSize:  maxChunkSize,
+		memTracker:    memory.NewTracker(memory.LabelForSQLText),
+		consumedIdx:   -1,
+	}
+	return l
+}
+
+// GetMemTracker returns the memory tracker of this List.
+func (l *List) GetMemTracker() *memory.Tracker {
+	return l.memTracker
+}
+
+// Len returns the length of the List.
+func (l *List) Len() int {
+	return l.length
+}
+
+// NumChunks returns the number of chunks in the List.
+func (l *List) NumChunks() int {
+	return len(l.chunks)
+}
+
+// GetChunk gets the Chunk by ChkIdx.
+func (l *List) GetChunk(chkIdx int) *Chunk {
+	return l.chunks[chkIdx]
+}
+
+// AppendRow appends a row to the List, the row is copied to the List.
+func (l *List) AppendRow(row Row) RowPtr {
+	chkIdx := len(l.chunks) - 1
+	if chkIdx == -1 || l.chunks[chkIdx].NumRows() >= l.chunks[chkIdx].Capacity() || chkIdx == l.consumedIdx {
+		newChk := l.allocChunk()
+		l.chunks = append(l.chunks, newChk)
+		if chkIdx != l.consumedIdx {
+			l.memTracker.Consume(l.chunks[chkIdx].MemoryUsage())
+			l.consumedIdx = chkIdx
+		}
+		chkIdx++
+	}
+	chk := l.chunks[chkIdx]
+	rowIdx := chk.NumRows()
+	chk.AppendRow(row)
+	l.length++
+	return RowPtr{ChkIdx: uint32(chkIdx), RowIdx: uint32(rowIdx)}
+}
+
+// Add adds a chunk to the List, the chunk is kept by the List and can't be reused by the caller. The empty
+// chunks are ignored.
+func (l *List) Add(chk *Chunk) {
+	if chk.NumRows() == 0 {
+		return
+	}
+	if chkIdx := len(l.chunks) - 1; l.consumedIdx != chkIdx {
+		l.memTracker.Consume(l.chunks[chkIdx].MemoryUsage())
+		l.consumedIdx = chkIdx
+	}
+	l.memTracker.Consume(chk.MemoryUsage())
+	l.consumedIdx++
+	l.chunks = append(l.chunks, chk)
+	l.length += chk.NumRows()
+}
+
+func (l *List) allocChunk() (chk *Chunk) {
+	if len(l.freelist) > 0 {
+		lastIdx := len(l.freelist) - 1
+		chk = l.freelist[lastIdx]
+		l.freelist = l.freelist[:lastIdx]
+		l.memTracker.Consume(-chk.MemoryUsage())
+		chk.Reset()
+		return
+	}
+	if len(l.chunks) > 0 {
+		return Renew(l.chunks[len(l.chunks)-1], l.maxChunkSize)
+	}
+	return New(l.fieldTypes, l.initChunkSize, l.maxChunkSize)
+}
+
+// GetRow gets a Row from the list by RowPtr.
+func (l *List) GetRow(ptr RowPtr) Row {
+	chk := l.chunks[ptr.ChkIdx]
+	return chk.GetRow(int(ptr.RowIdx))
+}
+
+// Reset resets the List, the chunks are kept in the free list to be reused.
+func (l *List) Reset() {
+	if lastIdx := len(l.chunks) - 1; lastIdx != l.consumedIdx {
+		l.memTracker.Consume(l.chunks[lastIdx].MemoryUsage())
+	}
+	l.freelist = append(l.freelist, l.chunks...)
+	l.chunks = l.chunks[:0]
+	l.length = 0
+	l.consumedIdx = -1
+}
+
+// Clear releases the chunks of the List, the memory they consumed is released by the tracker.
+func (l *List) Clear() {
+	l.memTracker.Consume(-l.memTracker.BytesConsumed())
+	l.freelist = nil
+	l.chunks = nil
+	l.length = 0
+	l.consumedIdx = -1
+}
+
+// ListWalkFunc is called on every row by Walk, the walk stops if an error is returned.
+type ListWalkFunc = func(row Row) error
+
+// Walk calls walkFunc on the rows of the List in order.
+func (l *List) Walk(walkFunc ListWalkFunc) error {
+	for i := 0; i < len(l.chunks); i++ {
+		chk := l.chunks[i]
+		for j := 0; j < chk.NumRows(); j++ {
+			err := walkFunc(chk.GetRow(j))
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package chunk
+
+import (
+	"github.com/pingcap/parser/mysql"
+	"grant-db/types"
+	"grant-db/types/json"
+)
+
+// Row represents a row of data, can be used to access values.
+type Row struct {
+	c   *Chunk
+	idx int
+}
+
+// Chunk returns the Chunk which the row belongs to.
+func (r Row) Chunk() *Chunk {
+	return r.c
+}
+
+// IsEmpty returns true if the Row is empty.
+func (r Row) IsEmpty() bool {
+	return r == Row{}
+}
+
+// Idx returns the row index of Chunk.
+func (r Row) Idx() int {
+	return r.idx
+}
+
+// Len returns the number of values in the row.
+func (r Row) Len() int {
+	return r.c.NumCols()
+}
+
+// GetInt64 returns the int64 value with the colIdx.
+func (r Row) GetInt64(colIdx int) int64 {
+	return r.c.columns[colIdx].GetInt64(r.idx)
+}
+
+// GetUint64 returns the uint64 value with the colIdx.
+func (r Row) GetUint64(colIdx int) uint64 {
+	return r.c.columns[colIdx].GetUint64(r.idx)
+}
+
+// GetFloat32 returns the float32 value with the colIdx.
+func (r Row) GetFloat32(colIdx int) float32 {
+	return r.c.columns[colIdx].GetFloat32(r.idx)
+}
+
+// GetFloat64 returns the float64 value with the colIdx.
+func (r Row) GetFloat64(colIdx int) float64 {
+	return r.c.columns[colIdx].GetFloat64(r.idx)
+}
+
+// GetString returns the string value with the colIdx.
+func (r Row) GetString(colIdx int) string {
+	return r.c.columns[colIdx].GetString(r.idx)
+}
+
+// GetBytes returns the bytes value with the colIdx.
+func (r Row) GetBytes(colIdx int) []byte {
+	return r.c.columns[colIdx].GetBytes(r.idx)
+}
+
+// GetTime returns the Time value with the colIdx.
+func (r Row) GetTime(colIdx int) types.Time {
+	return r.c.columns[colIdx].GetTime(r.idx)
+}
+
+// GetDuration returns the Duration value with the colIdx.
+func (r Row) GetDuration(colIdx int, fillFsp int) types.Duration {
+	return r.c.columns[colIdx].GetDuration(r.idx, fillFsp)
+}
+
+// GetEnum returns the Enum value with the colIdx, an ENUM column holds the names of the items.
+func (r Row) GetEnum(colIdx int, elems []string) types.Enum {
+	name := r.c.columns[colIdx].GetString(r.idx)
+	if name == "" {
+		return types.Enum{}
+	}
+	enum, _ := types.ParseEnumName(elems, name)
+	return enum
+}
+
+// GetSet returns the Set value with the colIdx, a SET column holds the names of the items.
+func (r Row) GetSet(colIdx int, elems []string) types.Set {
+	set, _ := types.ParseSetName(elems, r.c.columns[colIdx].GetString(r.idx))
+	return set
+}
+
+// GetMyDecimal returns the MyDecimal value with the colIdx.
+func (r Row) GetMyDecimal(colIdx int) *types.MyDecimal {
+	return r.c.columns[colIdx].GetDecimal(r.idx)
+}
+
+// GetJSON returns the BinaryJSON value with the colIdx.
+func (r Row) GetJSON(colIdx int) json.BinaryJSON {
+	return r.c.columns[colIdx].GetJSON(r.idx)
+}
+
+// IsNull returns if the datum in the chunk.Row is null.
+func (r Row) IsNull(colIdx int) bool {
+	return r.c.columns[colIdx].IsNull(r.idx)
+}
+
+// GetDatumRow converts chunk.Row to types.DatumRow.
+// Keep in mind that GetDatumRow has a reference to r.c, which is a chunk,
+// this function works only if the underlying chunk is valid or unchanged.
+func (r Row) GetDatumRow(fields []*types.FieldType) []types.Datum {
+	datumRow := make([]types.Datum, 0, r.c.NumCols())
+	for colIdx := 0; colIdx < r.c.NumCols(); colIdx++ {
+		datum := r.GetDatum(colIdx, fields[colIdx])
+		datumRow = append(datumRow, datum)
+	}
+	return datumRow
+}
+
+// GetDatum implements the chunk.Row interface.
+func (r Row) GetDatum(colIdx int, tp *types.FieldType) types.Datum {
+	var d types.Datum
+	if r.IsNull(colIdx) {
+		return d
+	}
+	switch tp.Tp {
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeInt24, mysql.TypeLong, mysql.TypeLonglong, mysql.TypeYear:
+		if mysql.HasUnsignedFlag(tp.Flag) {
+			d.SetUint64(r.GetUint64(colIdx))
+		} else {
+			d.SetInt64(r.GetInt64(colIdx))
+		}
+	case mysql.TypeFloat:
+		d.SetFloat64(float64(r.GetFloat32(colIdx)))
+	case mysql.TypeDouble:
+		d.SetFloat64(r.GetFloat64(colIdx))
+	case mysql.TypeVarchar, mysql.TypeVarString, mysql.TypeString,
+		mysql.TypeBlob, mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeLongBlob:
+		if tp.Charset == "binary" || !types.HasCharset(tp) {
+			d.SetBytes(r.GetBytes(colIdx))
+		} else {
+			d.SetString(r.GetString(colIdx))
+		}
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		d.SetMysqlTime(r.GetTime(colIdx))
+	case mysql.TypeDuration:
+		fsp := tp.Decimal
+		if fsp == types.UnspecifiedLength {
+			fsp = int(types.DefaultFsp)
+		}
+		d.SetMysqlDuration(r.GetDuration(colIdx, fsp))
+	case mysql.TypeNewDecimal:
+		d.SetMysqlDecimal(r.GetMyDecimal(colIdx))
+	case mysql.TypeEnum:
+		d.SetMysqlEnum(r.GetEnum(colIdx, tp.Elems))
+	case mysql.TypeSet:
+		d.SetMysqlSet(r.GetSet(colIdx, tp.Elems))
+	case mysql.TypeBit:
+		d.SetMysqlBit(types.NewBinaryLiteralFromUint(r.GetUint64(colIdx), (tp.Flen+7)>>3))
+	case mysql.TypeJSON:
+		d.SetMysqlJSON(r.GetJSON(colIdx))
+	default:
+		d.SetBytes(r.GetBytes(colIdx))
+	}
+	return d
+}
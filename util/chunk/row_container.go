@@ -0,0 +1,156 @@
+package chunk
+
+import (
+	"sync/atomic"
+
+	"grant-db/types"
+	"grant-db/util/memory"
+)
+
+// The states of the spilling of a RowContainer.
+const (
+	notSpilled uint32 = iota
+	spillRequested
+	spilled
+)
+
+// RowContainer holds the rows in a List, the rows are moved to a ListInDisk when the memory quota is
+// exceeded. The rows are read by the RowPtrs before and after they're spilled.
+//
+// The spilling is requested by the action of the memory tracker, which may be taken by another goroutine,
+// so the rows are spilled by the goroutine which adds the chunks.
+type RowContainer struct {
+	fieldTypes    []*types.FieldType
+	records       *List
+	recordsInDisk *ListInDisk
+	spillState    uint32
+
+	memTracker  *memory.Tracker
+	diskTracker *memory.Tracker
+	actionSpill *SpillDiskAction
+}
+
+// NewRowContainer creates a new RowContainer with field types and chunk size.
+func NewRowContainer(fieldTypes []*types.FieldType, chunkSize int) *RowContainer {
+	li := NewList(fieldTypes, chunkSize, chunkSize)
+	c := &RowContainer{
+		fieldTypes:  fieldTypes,
+		records:     li,
+		memTracker:  li.GetMemTracker(),
+		diskTracker: memory.NewTracker(memory.LabelForSQLText),
+	}
+	c.actionSpill = &SpillDiskAction{c: c}
+	return c
+}
+
+// GetMemTracker returns the memory tracker of the rows in the memory.
+func (c *RowContainer) GetMemTracker() *memory.Tracker {
+	return c.memTracker
+}
+
+// GetDiskTracker returns the disk tracker of the rows in the disk.
+func (c *RowContainer) GetDiskTracker() *memory.Tracker {
+	return c.diskTracker
+}
+
+// ActionSpill returns the action which requests the spilling of the rows.
+func (c *RowContainer) ActionSpill() *SpillDiskAction {
+	return c.actionSpill
+}
+
+// AlreadySpilled returns whether the rows are spilled to the disk.
+func (c *RowContainer) AlreadySpilled() bool {
+	return atomic.LoadUint32(&c.spillState) == spilled
+}
+
+// Len returns the number of rows.
+func (c *RowContainer) Len() int {
+	if c.AlreadySpilled() {
+		return c.recordsInDisk.Len()
+	}
+	return c.records.Len()
+}
+
+// NumChunks returns the number of chunks.
+func (c *RowContainer) NumChunks() int {
+	if c.AlreadySpilled() {
+		return c.recordsInDisk.NumChunks()
+	}
+	return c.records.NumChunks()
+}
+
+// NumRowsOfChunk returns the number of rows of the chunk.
+func (c *RowContainer) NumRowsOfChunk(chkIdx int) int {
+	if c.AlreadySpilled() {
+		return c.recordsInDisk.NumRowsOfChunk(chkIdx)
+	}
+	return c.records.GetChunk(chkIdx).NumRows()
+}
+
+// Add adds the chunk to the container, the chunk is kept by the container and can't be reused by the caller.
+// The rows are spilled to the disk if it's requested.
+func (c *RowContainer) Add(chk *Chunk) error {
+	if c.AlreadySpilled() {
+		return c.recordsInDisk.Add(chk)
+	}
+	c.records.Add(chk)
+	if atomic.LoadUint32(&c.spillState) == spillRequested {
+		return c.SpillToDisk()
+	}
+	return nil
+}
+
+// SpillToDisk moves the rows in the memory to the disk, the later chunks are added to the disk as well.
+func (c *RowContainer) SpillToDisk() error {
+	c.recordsInDisk = NewListInDisk(c.fieldTypes)
+	c.recordsInDisk.GetDiskTracker().AttachTo(c.diskTracker)
+	for i := 0; i < c.records.NumChunks(); i++ {
+		if err := c.recordsInDisk.Add(c.records.GetChunk(i)); err != nil {
+			return err
+		}
+	}
+	c.records.Clear()
+	atomic.StoreUint32(&c.spillState, spilled)
+	return nil
+}
+
+// GetRow returns the row of the RowPtr.
+func (c *RowContainer) GetRow(ptr RowPtr) (Row, error) {
+	if c.AlreadySpilled() {
+		return c.recordsInDisk.GetRow(ptr)
+	}
+	return c.records.GetRow(ptr), nil
+}
+
+// Close releases the rows in the memory and the disk.
+func (c *RowContainer) Close() error {
+	c.records.Clear()
+	if c.recordsInDisk != nil {
+		return c.recordsInDisk.Close()
+	}
+	return nil
+}
+
+// SpillDiskAction requests the spilling of the rows of a RowContainer when the memory quota is exceeded, the
+// fallback action is taken if they're spilled already.
+type SpillDiskAction struct {
+	c        *RowContainer
+	fallback memory.ActionOnExceed
+}
+
+// Action implements memory.ActionOnExceed Action interface. The quota may be exceeded again before the rows
+// are spilled by the next Add, which is ignored.
+func (a *SpillDiskAction) Action(t *memory.Tracker) {
+	if atomic.CompareAndSwapUint32(&a.c.spillState, notSpilled, spillRequested) ||
+		atomic.LoadUint32(&a.c.spillState) == spillRequested {
+		return
+	}
+	if a.fallback != nil {
+		a.fallback.Action(t)
+	}
+}
+
+// SetFallback implements memory.ActionOnExceed SetFallback interface.
+func (a *SpillDiskAction) SetFallback(fallback memory.ActionOnExceed) {
+	a.fallback = fallback
+}
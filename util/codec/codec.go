@@ -0,0 +1,449 @@
+package codec
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pingcap/parser/mysql"
+	"grant-db/types"
+	"grant-db/types/json"
+	"grant-db/util/chunk"
+	"grant-db/util/collate"
+)
+
+// First byte in the encoded value which specifies the encoding type.
+const (
+	NilFlag          byte = 0
+	bytesFlag        byte = 1
+	compactBytesFlag byte = 2
+	intFlag          byte = 3
+	uintFlag         byte = 4
+	floatFlag        byte = 5
+	decimalFlag      byte = 6
+	durationFlag     byte = 7
+	varintFlag       byte = 8
+	uvarintFlag      byte = 9
+	jsonFlag         byte = 10
+	maxFlag          byte = 250
+)
+
+func encode(b []byte, vals []types.Datum, comparable bool) ([]byte, error) {
+	for i, length := 0, len(vals); i < length; i++ {
+		switch vals[i].Kind() {
+		case types.KindInt64:
+			b = encodeSignedInt(b, vals[i].GetInt64(), comparable)
+		case types.KindUint64:
+			b = encodeUnsignedInt(b, vals[i].GetUint64(), comparable)
+		case types.KindFloat64:
+			b = append(b, floatFlag)
+			b = EncodeFloat(b, vals[i].GetFloat64())
+		case types.KindString, types.KindBytes:
+			b = encodeBytes(b, vals[i].GetBytes(), comparable)
+		case types.KindMysqlTime:
+			// datetime is stored in its packed form and restored by the column type.
+			b = encodeUnsignedInt(b, vals[i].GetMysqlTime().ToPackedUint(), comparable)
+		case types.KindMysqlDuration:
+			// duration may have negative value, so we cannot use String to encode directly.
+			b = append(b, durationFlag)
+			b = EncodeInt(b, int64(vals[i].GetMysqlDuration().Duration))
+		case types.KindMysqlDecimal:
+			b = append(b, decimalFlag)
+			b = EncodeDecimal(b, vals[i].GetMysqlDecimal())
+		case types.KindMysqlEnum:
+			b = encodeUnsignedInt(b, vals[i].GetMysqlEnum().Value, comparable)
+		case types.KindMysqlSet:
+			b = encodeUnsignedInt(b, vals[i].GetMysqlSet().Value, comparable)
+		case types.KindMysqlBit:
+			// The value of a BIT column is converted to fit uint64 already.
+			val, err := vals[i].GetMysqlBit().ToInt(nil)
+			if err != nil {
+				return b, err
+			}
+			b = encodeUnsignedInt(b, val, comparable)
+		case types.KindBinaryLiteral:
+			b = encodeBytes(b, vals[i].GetBinaryLiteral(), comparable)
+		case types.KindMysqlJSON:
+			j := vals[i].GetMysqlJSON()
+			b = append(b, jsonFlag, j.TypeCode)
+			b = append(b, j.Value...)
+		case types.KindNull:
+			b = append(b, NilFlag)
+		case types.KindMinNotNull:
+			b = append(b, bytesFlag)
+		case types.KindMaxValue:
+			b = append(b, maxFlag)
+		default:
+			return b, fmt.Errorf("unsupported encode type %d", vals[i].Kind())
+		}
+	}
+
+	return b, nil
+}
+
+func encodeBytes(b []byte, v []byte, comparable bool) []byte {
+	if comparable {
+		b = append(b, bytesFlag)
+		b = EncodeBytes(b, v)
+	} else {
+		b = append(b, compactBytesFlag)
+		b = EncodeCompactBytes(b, v)
+	}
+	return b
+}
+
+func encodeSignedInt(b []byte, v int64, comparable bool) []byte {
+	if comparable {
+		b = append(b, intFlag)
+		b = EncodeInt(b, v)
+	} else {
+		b = append(b, varintFlag)
+		b = EncodeVarint(b, v)
+	}
+	return b
+}
+
+func encodeUnsignedInt(b []byte, v uint64, comparable bool) []byte {
+	if comparable {
+		b = append(b, uintFlag)
+		b = EncodeUint(b, v)
+	} else {
+		b = append(b, uvarintFlag)
+		b = EncodeUvarint(b, v)
+	}
+	return b
+}
+
+// EncodeKey appends the encoded values to byte slice b, returns the appended
+// slice. It guarantees the encoded value is in ascending order for comparison.
+func EncodeKey(b []byte, v ...types.Datum) ([]byte, error) {
+	return encode(b, v, true)
+}
+
+// EncodeValue appends the encoded values to byte slice b, returning the appended
+// slice. It does not guarantee the order for comparison.
+func EncodeValue(b []byte, v ...types.Datum) ([]byte, error) {
+	return encode(b, v, false)
+}
+
+// Decode decodes values from a byte slice generated with EncodeKey or EncodeValue
+// before.
+// size is the size of decoded datum slice.
+func Decode(b []byte, size int) ([]types.Datum, error) {
+	if len(b) < 1 {
+		return nil, errors.New("invalid encoded key")
+	}
+
+	var (
+		err    error
+		values = make([]types.Datum, 0, size)
+	)
+
+	for len(b) > 0 {
+		var d types.Datum
+		b, d, err = DecodeOne(b)
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, d)
+	}
+
+	return values, nil
+}
+
+// DecodeOne decodes on datum from a byte slice generated with EncodeKey or EncodeValue.
+func DecodeOne(b []byte) (remain []byte, d types.Datum, err error) {
+	if len(b) < 1 {
+		return nil, d, errors.New("invalid encoded key")
+	}
+	flag := b[0]
+	b = b[1:]
+	switch flag {
+	case intFlag:
+		var v int64
+		b, v, err = DecodeInt(b)
+		d.SetInt64(v)
+	case uintFlag:
+		var v uint64
+		b, v, err = DecodeUint(b)
+		d.SetUint64(v)
+	case varintFlag:
+		var v int64
+		b, v, err = DecodeVarint(b)
+		d.SetInt64(v)
+	case uvarintFlag:
+		var v uint64
+		b, v, err = DecodeUvarint(b)
+		d.SetUint64(v)
+	case floatFlag:
+		var v float64
+		b, v, err = DecodeFloat(b)
+		d.SetFloat64(v)
+	case bytesFlag:
+		var v []byte
+		b, v, err = DecodeBytes(b, nil)
+		d.SetBytes(v)
+	case compactBytesFlag:
+		var v []byte
+		b, v, err = DecodeCompactBytes(b)
+		d.SetBytes(v)
+	case decimalFlag:
+		var dec *types.MyDecimal
+		b, dec, err = DecodeDecimal(b)
+		if err == nil {
+			d.SetMysqlDecimal(dec)
+		}
+	case durationFlag:
+		var r int64
+		b, r, err = DecodeInt(b)
+		if err == nil {
+			// use max fsp, let outer to do round manually.
+			d.SetMysqlDuration(types.Duration{Duration: time.Duration(r), Fsp: types.MaxFsp})
+		}
+	case jsonFlag:
+		var size int
+		size, err = json.PeekBytesAsJSON(b)
+		if err == nil {
+			d.SetMysqlJSON(json.BinaryJSON{TypeCode: b[0], Value: b[1:size]})
+			b = b[size:]
+		}
+	case NilFlag:
+	case maxFlag:
+		d = types.MaxValueDatum()
+	default:
+		return b, d, fmt.Errorf("invalid encoded key flag %v", flag)
+	}
+	if err != nil {
+		return b, d, err
+	}
+	return b, d, nil
+}
+
+// CutOne cuts the first encoded value from b.
+// It will return the first encoded item and the remains as byte slice.
+func CutOne(b []byte) (data []byte, remain []byte, err error) {
+	l, err := peek(b)
+	if err != nil {
+		return nil, nil, err
+	}
+	return b[:l], b[l:], nil
+}
+
+// peek peeks the first encoded value from b and returns its length.
+func peek(b []byte) (length int, err error) {
+	if len(b) < 1 {
+		return 0, errors.New("invalid encoded key")
+	}
+	flag := b[0]
+	length++
+	b = b[1:]
+	var l int
+	switch flag {
+	case NilFlag, maxFlag:
+	case intFlag, uintFlag, floatFlag, durationFlag:
+		l = 8
+	case bytesFlag:
+		l, err = peekBytes(b)
+	case compactBytesFlag:
+		l, err = peekCompactBytes(b)
+	case decimalFlag:
+		var remain []byte
+		remain, _, err = DecodeDecimal(b)
+		l = len(b) - len(remain)
+	case varintFlag:
+		l, err = peekVarint(b)
+	case uvarintFlag:
+		l, err = peekUvarint(b)
+	case jsonFlag:
+		l, err = json.PeekBytesAsJSON(b)
+	default:
+		return 0, fmt.Errorf("invalid encoded key flag %v", flag)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if len(b) < l {
+		return 0, errors.New("insufficient bytes to decode value")
+	}
+	length += l
+	return
+}
+
+func peekBytes(b []byte) (int, error) {
+	offset := 0
+	for {
+		if len(b) < offset+encGroupSize+1 {
+			return 0, errors.New("insufficient bytes to decode value")
+		}
+		// The byte slice is encoded into many groups.
+		// For each group, there are 8 bytes for data and 1 byte for marker.
+		marker := b[offset+encGroupSize]
+		padCount := encMarker - marker
+		offset += encGroupSize + 1
+		// When padCount is not zero, it means we get the end of the byte slice.
+		if padCount != 0 {
+			break
+		}
+	}
+	return offset, nil
+}
+
+func peekCompactBytes(b []byte) (int, error) {
+	remain, v, err := DecodeVarint(b)
+	if err != nil {
+		return 0, err
+	}
+	n := len(b) - len(remain)
+	return n + int(v), nil
+}
+
+func peekVarint(b []byte) (int, error) {
+	remain, _, err := DecodeVarint(b)
+	if err != nil {
+		return 0, err
+	}
+	return len(b) - len(remain), nil
+}
+
+func peekUvarint(b []byte) (int, error) {
+	remain, _, err := DecodeUvarint(b)
+	if err != nil {
+		return 0, err
+	}
+	return len(b) - len(remain), nil
+}
+
+// Unflatten converts a raw datum decoded from storage to the datum of field type ft.
+func Unflatten(datum types.Datum, ft *types.FieldType) (types.Datum, error) {
+	if datum.IsNull() {
+		return datum, nil
+	}
+	switch ft.Tp {
+	case mysql.TypeFloat, mysql.TypeDouble:
+		return datum, nil
+	case mysql.TypeTiny, mysql.TypeShort, mysql.TypeYear, mysql.TypeInt24,
+		mysql.TypeLong, mysql.TypeLonglong:
+		return datum, nil
+	case mysql.TypeVarchar, mysql.TypeString, mysql.TypeVarString,
+		mysql.TypeTinyBlob, mysql.TypeMediumBlob, mysql.TypeBlob, mysql.TypeLongBlob:
+		if ft.Charset == "" || ft.Charset == "binary" {
+			return datum, nil
+		}
+		datum.SetString(string(datum.GetBytes()))
+		return datum, nil
+	case mysql.TypeDate, mysql.TypeDatetime, mysql.TypeTimestamp:
+		fsp := types.DefaultFsp
+		if ft.Decimal > 0 {
+			fsp = int8(ft.Decimal)
+		}
+		datum.SetMysqlTime(types.FromPackedUint(datum.GetUint64(), ft.Tp, fsp))
+		return datum, nil
+	case mysql.TypeDuration:
+		dur := datum.GetMysqlDuration()
+		dur.Fsp = types.DefaultFsp
+		if ft.Decimal > 0 {
+			dur.Fsp = int8(ft.Decimal)
+		}
+		datum.SetMysqlDuration(dur)
+		return datum, nil
+	case mysql.TypeEnum:
+		// An invalid value is stored as 0, it's the empty string.
+		var enum types.Enum
+		if v := datum.GetUint64(); v != 0 {
+			var err error
+			if enum, err = types.ParseEnumValue(ft.Elems, v); err != nil {
+				return datum, err
+			}
+		}
+		datum.SetMysqlEnum(enum)
+		return datum, nil
+	case mysql.TypeSet:
+		set, err := types.ParseSetValue(ft.Elems, datum.GetUint64())
+		if err != nil {
+			return datum, err
+		}
+		datum.SetMysqlSet(set)
+		return datum, nil
+	case mysql.TypeBit:
+		val := datum.GetUint64()
+		byteSize := (ft.Flen + 7) >> 3
+		datum.SetMysqlBit(types.NewBinaryLiteralFromUint(val, byteSize))
+		return datum, nil
+	case mysql.TypeNewDecimal, mysql.TypeJSON:
+		return datum, nil
+	}
+	return datum, fmt.Errorf("unsupported unflatten type %d", ft.Tp)
+}
+
+// HashChunkRow encodes the values of the columns of the row into a key, the values which are equal by their
+// evaluation types and collations have the same keys. The columns are of the same evaluation types as the
+// ones they are compared with, like the join keys. hasNull is true if any of the values is NULL, which is
+// equal to none of the values.
+func HashChunkRow(b []byte, row chunk.Row, allTypes []*types.FieldType, colIdx []int) (_ []byte, hasNull bool) {
+	for _, idx := range colIdx {
+		if row.IsNull(idx) {
+			return b, true
+		}
+		b = hashChunkValue(b, row, allTypes[idx], idx)
+	}
+	return b, false
+}
+
+// HashGroupKey encodes the values of the columns of the row into a key like HashChunkRow, but a NULL is
+// equal to the other NULLs, like the group by items.
+func HashGroupKey(b []byte, row chunk.Row, allTypes []*types.FieldType, colIdx []int) []byte {
+	for _, idx := range colIdx {
+		if row.IsNull(idx) {
+			b = append(b, NilFlag)
+			continue
+		}
+		b = append(b, bytesFlag)
+		b = hashChunkValue(b, row, allTypes[idx], idx)
+	}
+	return b
+}
+
+func hashChunkValue(b []byte, row chunk.Row, tp *types.FieldType, idx int) []byte {
+	switch tp.EvalType() {
+	case types.ETInt:
+		if tp.Tp == mysql.TypeBit {
+			return EncodeUint(b, row.GetUint64(idx))
+		}
+		return EncodeInt(b, row.GetInt64(idx))
+	case types.ETReal:
+		var f float64
+		if tp.Tp == mysql.TypeFloat {
+			f = float64(row.GetFloat32(idx))
+		} else {
+			f = row.GetFloat64(idx)
+		}
+		if f == 0 {
+			// -0 is equal to 0.
+			f = 0
+		}
+		return EncodeFloat(b, f)
+	case types.ETDecimal:
+		// The number of fraction digits is the last byte, which is cut so 1.0 is equal to 1.00.
+		b = EncodeDecimal(b, row.GetMyDecimal(idx))
+		return b[:len(b)-1]
+	case types.ETDatetime, types.ETTimestamp:
+		return EncodeUint(b, row.GetTime(idx).ToPackedUint())
+	case types.ETDuration:
+		return EncodeInt(b, int64(row.GetDuration(idx, 0).Duration))
+	case types.ETJson:
+		j := row.GetJSON(idx)
+		b = append(b, j.TypeCode)
+		return EncodeCompactBytes(b, j.Value)
+	}
+	var str string
+	switch tp.Tp {
+	case mysql.TypeEnum:
+		str = row.GetEnum(idx, tp.Elems).Name
+	case mysql.TypeSet:
+		str = row.GetSet(idx, tp.Elems).Name
+	default:
+		str = row.GetString(idx)
+	}
+	return EncodeCompactBytes(b, collate.GetCollator(tp.Collate).Key(str))
+}
@@ -0,0 +1,375 @@
+package codec
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/pingcap/parser/mysql"
+	"grant-db/types"
+)
+
+// quickConfig is the config of the round-trip and order checks, which run on the seeds and then on random values.
+var quickConfig = &quick.Config{MaxCount: 1000}
+
+// checkOrder checks that the order of the encoded values a and b is cmp, which is the order of the values.
+func checkOrder(t *testing.T, cmp int, a, b []byte) {
+	t.Helper()
+	if got := bytes.Compare(a, b); got != cmp {
+		t.Fatalf("the encoded values %x and %x are in order %d, expected %d", a, b, got, cmp)
+	}
+}
+
+func compareInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// decodeKey decodes the single datum encoded by EncodeKey or EncodeValue and restores it by ft.
+func decodeKey(t *testing.T, b []byte, ft *types.FieldType) types.Datum {
+	t.Helper()
+	remain, d, err := DecodeOne(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(remain) != 0 {
+		t.Fatalf("%d bytes remain after decoding %x", len(remain), b)
+	}
+	if d, err = Unflatten(d, ft); err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestInt(t *testing.T) {
+	check := func(a, b int64) bool {
+		encA, encB := EncodeInt(nil, a), EncodeInt(nil, b)
+		remain, v, err := DecodeInt(append(encA, 'x'))
+		if err != nil || v != a || string(remain) != "x" {
+			t.Fatalf("DecodeInt(%x) = %d, %q, %v, expected %d", encA, v, remain, err, a)
+		}
+		checkOrder(t, compareInt(a, b), encA, encB)
+
+		descA, descB := EncodeIntDesc(nil, a), EncodeIntDesc(nil, b)
+		if _, v, err = DecodeIntDesc(descA); err != nil || v != a {
+			t.Fatalf("DecodeIntDesc(%x) = %d, %v, expected %d", descA, v, err, a)
+		}
+		checkOrder(t, -compareInt(a, b), descA, descB)
+
+		varA := EncodeVarint(nil, a)
+		if _, v, err = DecodeVarint(varA); err != nil || v != a {
+			t.Fatalf("DecodeVarint(%x) = %d, %v, expected %d", varA, v, err, a)
+		}
+
+		keyA, err := EncodeKey(nil, types.NewIntDatum(a))
+		if err != nil {
+			t.Fatal(err)
+		}
+		keyB, err := EncodeKey(nil, types.NewIntDatum(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := decodeKey(t, keyA, types.NewFieldType(mysql.TypeLonglong)); d.GetInt64() != a {
+			t.Fatalf("the key %x is decoded to %d, expected %d", keyA, d.GetInt64(), a)
+		}
+		checkOrder(t, compareInt(a, b), keyA, keyB)
+		return true
+	}
+	for _, v := range []int64{math.MinInt64, -1, 0, 1, math.MaxInt64} {
+		check(v, -v)
+	}
+	if err := quick.Check(check, quickConfig); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestUint(t *testing.T) {
+	check := func(a, b uint64) bool {
+		encA, encB := EncodeUint(nil, a), EncodeUint(nil, b)
+		remain, v, err := DecodeUint(append(encA, 'x'))
+		if err != nil || v != a || string(remain) != "x" {
+			t.Fatalf("DecodeUint(%x) = %d, %q, %v, expected %d", encA, v, remain, err, a)
+		}
+		checkOrder(t, compareUint(a, b), encA, encB)
+
+		descA, descB := EncodeUintDesc(nil, a), EncodeUintDesc(nil, b)
+		if _, v, err = DecodeUintDesc(descA); err != nil || v != a {
+			t.Fatalf("DecodeUintDesc(%x) = %d, %v, expected %d", descA, v, err, a)
+		}
+		checkOrder(t, -compareUint(a, b), descA, descB)
+
+		varA := EncodeUvarint(nil, a)
+		if _, v, err = DecodeUvarint(varA); err != nil || v != a {
+			t.Fatalf("DecodeUvarint(%x) = %d, %v, expected %d", varA, v, err, a)
+		}
+
+		keyA, err := EncodeKey(nil, types.NewUintDatum(a))
+		if err != nil {
+			t.Fatal(err)
+		}
+		keyB, err := EncodeKey(nil, types.NewUintDatum(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := decodeKey(t, keyA, types.NewFieldType(mysql.TypeLonglong)); d.GetUint64() != a {
+			t.Fatalf("the key %x is decoded to %d, expected %d", keyA, d.GetUint64(), a)
+		}
+		checkOrder(t, compareUint(a, b), keyA, keyB)
+		return true
+	}
+	for _, v := range []uint64{0, 1, math.MaxInt64, math.MaxInt64 + 1, math.MaxUint64} {
+		check(v, math.MaxUint64-v)
+	}
+	if err := quick.Check(check, quickConfig); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFloat(t *testing.T) {
+	check := func(a, b float64) bool {
+		if math.IsNaN(a) || math.IsNaN(b) {
+			return true
+		}
+		// The negative zero is stored as the zero.
+		cmp := 0
+		switch {
+		case a < b:
+			cmp = -1
+		case a > b:
+			cmp = 1
+		}
+		encA, encB := EncodeFloat(nil, a), EncodeFloat(nil, b)
+		_, v, err := DecodeFloat(encA)
+		if err != nil || v != a {
+			t.Fatalf("DecodeFloat(%x) = %v, %v, expected %v", encA, v, err, a)
+		}
+		checkOrder(t, cmp, encA, encB)
+
+		descA, descB := EncodeFloatDesc(nil, a), EncodeFloatDesc(nil, b)
+		if _, v, err = DecodeFloatDesc(descA); err != nil || v != a {
+			t.Fatalf("DecodeFloatDesc(%x) = %v, %v, expected %v", descA, v, err, a)
+		}
+		checkOrder(t, -cmp, descA, descB)
+
+		keyA, err := EncodeKey(nil, types.NewFloat64Datum(a))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := decodeKey(t, keyA, types.NewFieldType(mysql.TypeDouble)); d.GetFloat64() != a {
+			t.Fatalf("the key %x is decoded to %v, expected %v", keyA, d.GetFloat64(), a)
+		}
+		return true
+	}
+	for _, v := range []float64{math.Inf(-1), -math.MaxFloat64, -1.5, -math.SmallestNonzeroFloat64, 0, 1e-300, 2.5,
+		math.MaxFloat64, math.Inf(1)} {
+		check(v, -v)
+	}
+	if err := quick.Check(check, quickConfig); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBytes(t *testing.T) {
+	check := func(a, b []byte) bool {
+		encA, encB := EncodeBytes(nil, a), EncodeBytes(nil, b)
+		remain, v, err := DecodeBytes(append(encA, 'x'), nil)
+		if err != nil || !bytes.Equal(v, a) || string(remain) != "x" {
+			t.Fatalf("DecodeBytes(%x) = %x, %q, %v, expected %x", encA, v, remain, err, a)
+		}
+		checkOrder(t, bytes.Compare(a, b), encA, encB)
+
+		descA, descB := EncodeBytesDesc(nil, a), EncodeBytesDesc(nil, b)
+		if _, v, err = DecodeBytesDesc(descA, nil); err != nil || !bytes.Equal(v, a) {
+			t.Fatalf("DecodeBytesDesc(%x) = %x, %v, expected %x", descA, v, err, a)
+		}
+		checkOrder(t, -bytes.Compare(a, b), descA, descB)
+
+		compactA := EncodeCompactBytes(nil, a)
+		if _, v, err = DecodeCompactBytes(compactA); err != nil || !bytes.Equal(v, a) {
+			t.Fatalf("DecodeCompactBytes(%x) = %x, %v, expected %x", compactA, v, err, a)
+		}
+
+		// The bytes in a key are followed by the other columns, a cut must stop at the end of them.
+		keyA, err := EncodeKey(nil, types.NewBytesDatum(a), types.NewIntDatum(1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		keyB, err := EncodeKey(nil, types.NewBytesDatum(b), types.NewIntDatum(0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, remain, err := CutOne(keyA)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := decodeKey(t, data, types.NewFieldType(mysql.TypeBlob)); !bytes.Equal(d.GetBytes(), a) {
+			t.Fatalf("the key %x is decoded to %x, expected %x", keyA, d.GetBytes(), a)
+		}
+		if _, d, err := DecodeOne(remain); err != nil || d.GetInt64() != 1 {
+			t.Fatalf("the rest of the key %x is decoded to %v, %v", keyA, d, err)
+		}
+		cmp := bytes.Compare(a, b)
+		if cmp == 0 {
+			cmp = 1
+		}
+		checkOrder(t, cmp, keyA, keyB)
+		return true
+	}
+	check([]byte{}, []byte{0})
+	check([]byte("abcdefgh"), []byte("abcdefgh\x00"))
+	check([]byte("abcdefg"), []byte("abcdefgh"))
+	check([]byte{0xff, 0xff}, []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+	if err := quick.Check(check, quickConfig); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newDecimal returns the decimal unscaled / 10^frac, the frac is at most 30 like DECIMAL.
+func newDecimal(t *testing.T, unscaled int64, frac uint8) *types.MyDecimal {
+	t.Helper()
+	dec := new(types.MyDecimal)
+	if err := dec.FromString([]byte(fmt.Sprintf("%de-%d", unscaled, frac%31))); err != nil {
+		t.Fatal(err)
+	}
+	return dec
+}
+
+func TestDecimal(t *testing.T) {
+	check := func(unscaledA int64, fracA uint8, unscaledB int64, fracB uint8) bool {
+		a, b := newDecimal(t, unscaledA, fracA), newDecimal(t, unscaledB, fracB)
+		encA, encB := EncodeDecimal(nil, a), EncodeDecimal(nil, b)
+		remain, v, err := DecodeDecimal(append(encA, 'x'))
+		if err != nil || v.String() != a.String() || string(remain) != "x" {
+			t.Fatalf("DecodeDecimal(%x) = %v, %q, %v, expected %v", encA, v, remain, err, a)
+		}
+		// The equal decimals of different fraction digits, like 1.0 and 1.00, are stored apart.
+		if cmp := a.Compare(b); cmp != 0 || a.GetDigitsFrac() == b.GetDigitsFrac() {
+			checkOrder(t, cmp, encA, encB)
+		}
+
+		keyA, err := EncodeKey(nil, types.NewDecimalDatum(a))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if d := decodeKey(t, keyA, types.NewFieldType(mysql.TypeNewDecimal)); d.GetMysqlDecimal().String() != a.String() {
+			t.Fatalf("the key %x is decoded to %v, expected %v", keyA, d.GetMysqlDecimal(), a)
+		}
+		return true
+	}
+	check(0, 0, 0, 2)
+	check(-1, 0, 1, 0)
+	check(10, 1, 1, 0)
+	check(-125, 2, -1250, 3)
+	check(99999, 30, 1, 25)
+	check(math.MinInt64, 4, math.MaxInt64, 4)
+	if err := quick.Check(check, quickConfig); err != nil {
+		t.Fatal(err)
+	}
+
+	enc := EncodeDecimal(nil, newDecimal(t, 1, 30))
+	enc[len(enc)-1] = types.MaxDecimalScale + 1
+	if _, _, err := DecodeDecimal(enc); err == nil {
+		t.Fatalf("DecodeDecimal(%x) of too many fraction digits succeeds", enc)
+	}
+}
+
+// newDatetime returns the datetime of the parts, which are wrapped into their ranges.
+func newDatetime(year uint16, month, day, hour, minute, second uint8, micro uint32) types.Time {
+	return types.NewTime(int(year%10000), int(month%13), int(day%32), int(hour%24), int(minute%60), int(second%60),
+		int(micro%1000000), mysql.TypeDatetime, types.MaxFsp)
+}
+
+func TestDatetime(t *testing.T) {
+	check := func(year uint16, month, day, hour, minute, second uint8, micro uint32, delta int64) bool {
+		a := newDatetime(year, month, day, hour, minute, second, micro)
+		b := a
+		// The dates with zero parts aren't Go times, they are only compared with themselves.
+		if delta != 0 && a.Month() != 0 && a.Day() != 0 {
+			goTime := time.Date(a.Year(), time.Month(a.Month()), a.Day(), a.Hour(), a.Minute(), a.Second(),
+				a.Microsecond()*1000, time.UTC)
+			b = types.FromGoTime(goTime.Add(time.Duration(delta)*time.Microsecond), mysql.TypeDatetime, types.MaxFsp)
+		}
+		ft := types.NewFieldType(mysql.TypeDatetime)
+		ft.Decimal = int(types.MaxFsp)
+		for _, comparable := range []bool{true, false} {
+			encA, err := encode(nil, []types.Datum{types.NewTimeDatum(a)}, comparable)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := decodeKey(t, encA, ft); d.GetMysqlTime().Compare(a) != 0 || d.GetMysqlTime().String() != a.String() {
+				t.Fatalf("%x is decoded to %v, expected %v", encA, d.GetMysqlTime(), a)
+			}
+		}
+		keyA, err := EncodeKey(nil, types.NewTimeDatum(a))
+		if err != nil {
+			t.Fatal(err)
+		}
+		keyB, err := EncodeKey(nil, types.NewTimeDatum(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkOrder(t, a.Compare(b), keyA, keyB)
+		return true
+	}
+	check(0, 0, 0, 0, 0, 0, 0, 1)
+	check(1970, 1, 1, 0, 0, 0, 0, -1)
+	check(2020, 2, 29, 23, 59, 59, 999999, 1)
+	check(9999, 12, 31, 23, 59, 59, 999999, -1000000)
+	if err := quick.Check(check, quickConfig); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDuration(t *testing.T) {
+	maxDur := int64(838*time.Hour + 59*time.Minute + 59*time.Second)
+	check := func(a, b int64) bool {
+		durA := types.Duration{Duration: time.Duration(a % (maxDur + 1)), Fsp: types.MaxFsp}
+		durB := types.Duration{Duration: time.Duration(b % (maxDur + 1)), Fsp: types.MaxFsp}
+		ft := types.NewFieldType(mysql.TypeDuration)
+		ft.Decimal = int(types.MaxFsp)
+		for _, comparable := range []bool{true, false} {
+			encA, err := encode(nil, []types.Datum{types.NewDurationDatum(durA)}, comparable)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := decodeKey(t, encA, ft); d.GetMysqlDuration().Compare(durA) != 0 {
+				t.Fatalf("%x is decoded to %v, expected %v", encA, d.GetMysqlDuration(), durA)
+			}
+		}
+		keyA, err := EncodeKey(nil, types.NewDurationDatum(durA))
+		if err != nil {
+			t.Fatal(err)
+		}
+		keyB, err := EncodeKey(nil, types.NewDurationDatum(durB))
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkOrder(t, durA.Compare(durB), keyA, keyB)
+		return true
+	}
+	check(0, 1)
+	check(-maxDur, maxDur)
+	check(int64(-time.Microsecond), int64(time.Microsecond))
+	check(int64(time.Hour), int64(time.Hour))
+	if err := quick.Check(check, quickConfig); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,94 @@
+package codec
+
+import (
+	"errors"
+	"strconv"
+
+	"grant-db/types"
+)
+
+const (
+	decimalNeg  byte = 0
+	decimalZero byte = 1
+	decimalPos  byte = 2
+)
+
+// EncodeDecimal encodes a decimal into a byte slice which can be sorted lexicographically later.
+// The value is normalized to 0.digits * 10^exp, a larger exponent or larger digits mean a larger
+// absolute value, negative values have the exponent and digits bitwise reversed.
+// The number of fraction digits is appended so decoding restores the exact value, it never exceeds
+// types.MaxDecimalScale and fits in a byte.
+func EncodeDecimal(b []byte, dec *types.MyDecimal) []byte {
+	neg, digits, exp := dec.Scientific()
+	switch {
+	case digits == "":
+		b = append(b, decimalZero)
+	case neg:
+		b = append(b, decimalNeg)
+		b = EncodeIntDesc(b, int64(exp))
+		b = EncodeBytesDesc(b, []byte(digits))
+	default:
+		b = append(b, decimalPos)
+		b = EncodeInt(b, int64(exp))
+		b = EncodeBytes(b, []byte(digits))
+	}
+	return append(b, byte(dec.GetDigitsFrac()))
+}
+
+// DecodeDecimal decodes bytes to decimal.
+func DecodeDecimal(b []byte) ([]byte, *types.MyDecimal, error) {
+	if len(b) < 1 {
+		return nil, nil, errors.New("insufficient bytes to decode value")
+	}
+	sign := b[0]
+	b = b[1:]
+	var (
+		exp    int64
+		digits []byte
+		err    error
+	)
+	switch sign {
+	case decimalZero:
+	case decimalNeg:
+		if b, exp, err = DecodeIntDesc(b); err != nil {
+			return nil, nil, err
+		}
+		if b, digits, err = DecodeBytesDesc(b, nil); err != nil {
+			return nil, nil, err
+		}
+	case decimalPos:
+		if b, exp, err = DecodeInt(b); err != nil {
+			return nil, nil, err
+		}
+		if b, digits, err = DecodeBytes(b, nil); err != nil {
+			return nil, nil, err
+		}
+	default:
+		return nil, nil, errors.New("invalid decimal sign")
+	}
+	if len(b) < 1 {
+		return nil, nil, errors.New("insufficient bytes to decode value")
+	}
+	frac := int(b[0])
+	if frac > types.MaxDecimalScale {
+		return nil, nil, errors.New("invalid decimal fraction digits")
+	}
+	b = b[1:]
+	dec := new(types.MyDecimal)
+	if len(digits) == 0 {
+		dec.FromInt(0)
+	} else {
+		str := make([]byte, 0, len(digits)+24)
+		if sign == decimalNeg {
+			str = append(str, '-')
+		}
+		str = append(str, "0."...)
+		str = append(str, digits...)
+		str = append(str, 'e')
+		str = strconv.AppendInt(str, exp, 10)
+		if err = dec.FromString(str); err != nil {
+			return nil, nil, err
+		}
+	}
+	return b, dec.Rescale(frac), nil
+}
@@ -0,0 +1,122 @@
+package collate
+
+import (
+	"strings"
+
+	"grant-db/util/stringutil"
+)
+
+// Collator compares the strings of a collation.
+type Collator interface {
+	// Compare returns an integer comparing the two strings, the result is 0 if a == b, -1 if a < b and +1 if a > b.
+	Compare(a, b string) int
+	// Key returns the sort key of the string, the keys of two strings are compared by bytes as the strings
+	// are compared by the collation.
+	Key(str string) []byte
+	// Pattern returns a WildcardPattern to match the strings by LIKE.
+	Pattern() WildcardPattern
+}
+
+// WildcardPattern is the pattern of LIKE, which is compiled once and matches the strings.
+type WildcardPattern interface {
+	// Compile compiles the pattern with the escape character.
+	Compile(pattern string, escape byte)
+	// DoMatch returns whether the string matches the pattern.
+	DoMatch(str string) bool
+}
+
+var collators = map[string]Collator{
+	"binary":             &binCollator{},
+	"utf8_bin":           &binPaddingCollator{},
+	"utf8mb4_bin":        &binPaddingCollator{},
+	"ascii_bin":          &binPaddingCollator{},
+	"latin1_bin":         &binPaddingCollator{},
+	"utf8_general_ci":    &generalCICollator{},
+	"utf8mb4_general_ci": &generalCICollator{},
+	"latin1_swedish_ci":  &generalCICollator{},
+	"utf8_unicode_ci":    newUnicodeCICollator(true),
+	"utf8mb4_unicode_ci": newUnicodeCICollator(true),
+	"utf8mb4_0900_ai_ci": newUnicodeCICollator(false),
+	"gbk_bin":            &gbkCollator{},
+	"gbk_chinese_ci":     &gbkCollator{ci: true},
+}
+
+// GetCollator returns the collator of the collation, the strings of an unknown collation are compared by bytes.
+func GetCollator(collate string) Collator {
+	if c, ok := collators[strings.ToLower(collate)]; ok {
+		return c
+	}
+	return collators["binary"]
+}
+
+// IsBinCollation returns whether the strings of the collation are compared by bytes, the trailing spaces aside.
+func IsBinCollation(collate string) bool {
+	switch GetCollator(collate).(type) {
+	case *binCollator, *binPaddingCollator:
+		return true
+	}
+	return false
+}
+
+// truncateTailingSpace removes the trailing spaces, which are ignored by the PAD SPACE collations.
+func truncateTailingSpace(str string) string {
+	return strings.TrimRight(str, " ")
+}
+
+type binCollator struct{}
+
+func (c *binCollator) Compare(a, b string) int {
+	return strings.Compare(a, b)
+}
+
+func (c *binCollator) Key(str string) []byte {
+	return []byte(str)
+}
+
+func (c *binCollator) Pattern() WildcardPattern {
+	return &binPattern{}
+}
+
+// binPaddingCollator compares the strings by bytes, the trailing spaces are ignored.
+type binPaddingCollator struct{}
+
+func (c *binPaddingCollator) Compare(a, b string) int {
+	return strings.Compare(truncateTailingSpace(a), truncateTailingSpace(b))
+}
+
+func (c *binPaddingCollator) Key(str string) []byte {
+	return []byte(truncateTailingSpace(str))
+}
+
+func (c *binPaddingCollator) Pattern() WildcardPattern {
+	return &binPattern{}
+}
+
+type binPattern struct {
+	patChars []byte
+	patTypes []byte
+}
+
+func (p *binPattern) Compile(pattern string, escape byte) {
+	p.patChars, p.patTypes = stringutil.CompilePattern(pattern, escape)
+}
+
+func (p *binPattern) DoMatch(str string) bool {
+	return stringutil.DoMatch(str, p.patChars, p.patTypes)
+}
+
+// runePattern matches the strings character by character, equal reports whether two characters are equal
+// in the collation.
+type runePattern struct {
+	patChars []rune
+	patTypes []byte
+	equal    func(a, b rune) bool
+}
+
+func (p *runePattern) Compile(pattern string, escape byte) {
+	p.patChars, p.patTypes = stringutil.CompilePatternRunes(pattern, escape)
+}
+
+func (p *runePattern) DoMatch(str string) bool {
+	return stringutil.DoMatchRunes(str, p.patChars, p.patTypes, p.equal)
+}
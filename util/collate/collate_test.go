@@ -0,0 +1,95 @@
+package collate
+
+import (
+	"bytes"
+	"sync"
+	"testing"
+)
+
+func TestCollators(t *testing.T) {
+	tests := []struct {
+		collation string
+		a, b      string
+		expected  int
+	}{
+		{"binary", "a", "a ", -1},
+		{"utf8mb4_bin", "a", "a ", 0},
+		{"utf8mb4_bin", "a", "A", 1},
+		{"utf8mb4_general_ci", "a", "A ", 0},
+		{"utf8mb4_general_ci", "ä", "A", 0},
+		{"utf8mb4_general_ci", "a", "b", -1},
+		{"utf8mb4_general_ci", "😀", "😃", 0},
+		{"latin1_swedish_ci", "abc", "ABC", 0},
+		{"utf8mb4_unicode_ci", "ß", "ss", 0},
+		{"utf8mb4_unicode_ci", "a", "A ", 0},
+		{"utf8mb4_0900_ai_ci", "a", "A ", -1},
+		{"utf8mb4_0900_ai_ci", "é", "E", 0},
+		{"gbk_bin", "a", "A", 1},
+		{"gbk_chinese_ci", "a", "A", 0},
+		// The Chinese characters are ordered by pinyin: a, bu, zhong.
+		{"gbk_chinese_ci", "啊", "不", -1},
+		{"gbk_chinese_ci", "不", "中", -1},
+		{"unknown", "a", "A", 1},
+	}
+	for _, tt := range tests {
+		c := GetCollator(tt.collation)
+		if got := c.Compare(tt.a, tt.b); got != tt.expected {
+			t.Fatalf("%s compares %q with %q: %d, expected %d", tt.collation, tt.a, tt.b, got, tt.expected)
+		}
+		// The keys are ordered as the strings.
+		if got := bytes.Compare(c.Key(tt.a), c.Key(tt.b)); got != tt.expected {
+			t.Fatalf("%s compares the keys of %q and %q: %d, expected %d", tt.collation, tt.a, tt.b, got, tt.expected)
+		}
+	}
+	if !IsBinCollation("utf8mb4_bin") || IsBinCollation("utf8mb4_general_ci") {
+		t.Fatal("IsBinCollation is wrong")
+	}
+}
+
+func TestPattern(t *testing.T) {
+	tests := []struct {
+		collation string
+		pattern   string
+		str       string
+		expected  bool
+	}{
+		{"utf8mb4_bin", "a%c", "abbc", true},
+		{"utf8mb4_bin", "a_c", "ABC", false},
+		{"utf8mb4_general_ci", "a_c", "ABC", true},
+		{"utf8mb4_unicode_ci", "%É%", "cafe", true},
+		{"gbk_chinese_ci", "中_", "中文", true},
+		{"utf8mb4_general_ci", `a\%`, "a%", true},
+		{"utf8mb4_general_ci", `a\%`, "ab", false},
+	}
+	for _, tt := range tests {
+		p := GetCollator(tt.collation).Pattern()
+		p.Compile(tt.pattern, '\\')
+		if got := p.DoMatch(tt.str); got != tt.expected {
+			t.Fatalf("%q like %q in %s: %v, expected %v", tt.str, tt.pattern, tt.collation, got, tt.expected)
+		}
+	}
+}
+
+func TestUnicodeCIConcurrent(t *testing.T) {
+	c := GetCollator("utf8mb4_unicode_ci")
+	expected := c.Key("Straße")
+	var wg sync.WaitGroup
+	errs := make(chan string, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				if !bytes.Equal(c.Key("Straße"), expected) || c.Compare("strasse", "STRASSE") != 0 {
+					errs <- "the concurrent collations differ"
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatal(err)
+	}
+}
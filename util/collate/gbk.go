@@ -0,0 +1,55 @@
+package collate
+
+import (
+	"bytes"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+)
+
+// gbkEncoder is shared since the GBK encoder keeps no state.
+var gbkEncoder = simplifiedchinese.GBK.NewEncoder()
+
+// gbkCollator is the collator of gbk_bin and gbk_chinese_ci, the strings are compared by their GBK codes,
+// which sort the common Chinese characters by pinyin. gbk_chinese_ci ignores the case of the Latin letters.
+// The characters which are not in GBK are '?', and the trailing spaces are ignored.
+type gbkCollator struct {
+	ci bool
+}
+
+func (c *gbkCollator) Compare(a, b string) int {
+	return bytes.Compare(c.Key(a), c.Key(b))
+}
+
+func (c *gbkCollator) Key(str string) []byte {
+	str = truncateTailingSpace(str)
+	key := make([]byte, 0, len(str))
+	for _, r := range str {
+		key = c.appendWeight(key, r)
+	}
+	return key
+}
+
+func (c *gbkCollator) Pattern() WildcardPattern {
+	return &runePattern{equal: func(a, b rune) bool {
+		var bufA, bufB [2]byte
+		return bytes.Equal(c.appendWeight(bufA[:0], a), c.appendWeight(bufB[:0], b))
+	}}
+}
+
+func (c *gbkCollator) appendWeight(key []byte, r rune) []byte {
+	if r < utf8.RuneSelf {
+		if c.ci && 'a' <= r && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+		return append(key, byte(r))
+	}
+	var src [utf8.UTFMax]byte
+	var dst [2]byte
+	n := utf8.EncodeRune(src[:], r)
+	nDst, _, err := gbkEncoder.Transform(dst[:], src[:n], true)
+	if err != nil {
+		return append(key, '?')
+	}
+	return append(key, dst[:nDst]...)
+}
@@ -0,0 +1,68 @@
+package collate
+
+import (
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// generalCICollator is the collator of utf8mb4_general_ci, every character is compared by its weight,
+// which is the upper case of the character without the accents. The characters out of the BMP are
+// all equal, and the trailing spaces are ignored.
+type generalCICollator struct{}
+
+func (c *generalCICollator) Compare(a, b string) int {
+	a, b = truncateTailingSpace(a), truncateTailingSpace(b)
+	for len(a) > 0 && len(b) > 0 {
+		ra, sizeA := utf8.DecodeRuneInString(a)
+		rb, sizeB := utf8.DecodeRuneInString(b)
+		if wa, wb := generalCIWeight(ra), generalCIWeight(rb); wa != wb {
+			if wa < wb {
+				return -1
+			}
+			return 1
+		}
+		a, b = a[sizeA:], b[sizeB:]
+	}
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+	return 0
+}
+
+func (c *generalCICollator) Key(str string) []byte {
+	str = truncateTailingSpace(str)
+	key := make([]byte, 0, len(str)*2)
+	for _, r := range str {
+		w := generalCIWeight(r)
+		key = append(key, byte(w>>8), byte(w))
+	}
+	return key
+}
+
+func (c *generalCICollator) Pattern() WildcardPattern {
+	return &runePattern{equal: func(a, b rune) bool {
+		return generalCIWeight(a) == generalCIWeight(b)
+	}}
+}
+
+func generalCIWeight(r rune) uint16 {
+	switch {
+	case r < utf8.RuneSelf:
+		if 'a' <= r && r <= 'z' {
+			r -= 'a' - 'A'
+		}
+		return uint16(r)
+	case r > 0xFFFF:
+		return 0xFFFD
+	}
+	// The base character is the first one of the canonical decomposition.
+	if d := norm.NFD.PropertiesString(string(r)).Decomposition(); len(d) > 0 {
+		r, _ = utf8.DecodeRune(d)
+	}
+	return uint16(unicode.ToUpper(r))
+}
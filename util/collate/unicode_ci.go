@@ -0,0 +1,56 @@
+package collate
+
+import (
+	"sync"
+
+	"golang.org/x/text/collate"
+	"golang.org/x/text/language"
+)
+
+// unicodeCICollator is the collator of utf8mb4_unicode_ci and utf8mb4_0900_ai_ci, the strings are compared by the
+// Unicode Collation Algorithm with the case and the accents ignored. utf8mb4_unicode_ci is PAD SPACE and
+// utf8mb4_0900_ai_ci is NO PAD.
+type unicodeCICollator struct {
+	padding bool
+}
+
+// unicodeCIState is a collator of x/text with its key buffer. The collator of x/text is not safe for concurrent
+// use, so each goroutine takes one from unicodeCIStates.
+type unicodeCIState struct {
+	c   *collate.Collator
+	buf collate.Buffer
+}
+
+var unicodeCIStates = sync.Pool{New: func() interface{} {
+	return &unicodeCIState{c: collate.New(language.Und, collate.Loose)}
+}}
+
+func newUnicodeCICollator(padding bool) *unicodeCICollator {
+	return &unicodeCICollator{padding: padding}
+}
+
+func (c *unicodeCICollator) Compare(a, b string) int {
+	if c.padding {
+		a, b = truncateTailingSpace(a), truncateTailingSpace(b)
+	}
+	s := unicodeCIStates.Get().(*unicodeCIState)
+	defer unicodeCIStates.Put(s)
+	return s.c.CompareString(a, b)
+}
+
+func (c *unicodeCICollator) Key(str string) []byte {
+	if c.padding {
+		str = truncateTailingSpace(str)
+	}
+	s := unicodeCIStates.Get().(*unicodeCIState)
+	defer unicodeCIStates.Put(s)
+	key := append([]byte(nil), s.c.KeyFromString(&s.buf, str)...)
+	s.buf.Reset()
+	return key
+}
+
+func (c *unicodeCICollator) Pattern() WildcardPattern {
+	return &runePattern{equal: func(a, b rune) bool {
+		return a == b || c.Compare(string(a), string(b)) == 0
+	}}
+}
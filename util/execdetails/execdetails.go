@@ -0,0 +1,66 @@
+package execdetails
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RuntimeStats is the runtime statistics of an executor, which is recorded by every call of Next.
+type RuntimeStats struct {
+	// loop is the number of the calls of Next.
+	loop int32
+	// consume is the total time of the calls in nanoseconds, including the time of the children.
+	consume int64
+	// rows is the number of the returned rows.
+	rows int64
+}
+
+// Record records a call of Next which takes d and returns rowNum rows.
+func (e *RuntimeStats) Record(d time.Duration, rowNum int) {
+	atomic.AddInt32(&e.loop, 1)
+	atomic.AddInt64(&e.consume, int64(d))
+	atomic.AddInt64(&e.rows, int64(rowNum))
+}
+
+// GetActRows returns the number of the returned rows.
+func (e *RuntimeStats) GetActRows() int64 {
+	return atomic.LoadInt64(&e.rows)
+}
+
+// String implements fmt.Stringer interface.
+func (e *RuntimeStats) String() string {
+	return fmt.Sprintf("time:%v, loops:%d", time.Duration(atomic.LoadInt64(&e.consume)), atomic.LoadInt32(&e.loop))
+}
+
+// RuntimeStatsColl collects the runtime statistics of the executors of a statement by the IDs of the plans.
+type RuntimeStatsColl struct {
+	mu    sync.Mutex
+	stats map[int]*RuntimeStats
+}
+
+// NewRuntimeStatsColl creates a RuntimeStatsColl.
+func NewRuntimeStatsColl() *RuntimeStatsColl {
+	return &RuntimeStatsColl{stats: make(map[int]*RuntimeStats)}
+}
+
+// GetRootStats returns the runtime statistics of the plan, which are created if they don't exist.
+func (e *RuntimeStatsColl) GetRootStats(planID int) *RuntimeStats {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	stats, ok := e.stats[planID]
+	if !ok {
+		stats = &RuntimeStats{}
+		e.stats[planID] = stats
+	}
+	return stats
+}
+
+// ExistsRootStats checks whether the runtime statistics of the plan exist.
+func (e *RuntimeStatsColl) ExistsRootStats(planID int) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, ok := e.stats[planID]
+	return ok
+}
@@ -0,0 +1,31 @@
+package memory
+
+import (
+	"github.com/pingcap/parser/terror"
+)
+
+// ActionOnExceed is the action taken when a tracker consumes more bytes than its limit.
+type ActionOnExceed interface {
+	// Action is called with the tracker whose limit is exceeded, it may be called concurrently.
+	Action(t *Tracker)
+	// SetFallback sets the action which is taken if this one can't release the bytes.
+	SetFallback(a ActionOnExceed)
+}
+
+// codeMemoryExceedForQuery is the error code of ErrMemoryExceedForQuery.
+const codeMemoryExceedForQuery = 8175
+
+// ErrMemoryExceedForQuery is the error of a query which uses more memory than tidb_mem_quota_query.
+var ErrMemoryExceedForQuery = terror.ClassUtil.New(codeMemoryExceedForQuery, "Out Of Memory Quota! quota: %s, consumed: %s")
+
+// PanicOnExceed panics with ErrMemoryExceedForQuery, the panic is recovered by the executors and returned as
+// the error of the statement.
+type PanicOnExceed struct{}
+
+// Action implements ActionOnExceed Action interface.
+func (a *PanicOnExceed) Action(t *Tracker) {
+	panic(ErrMemoryExceedForQuery.GenWithStackByArgs(BytesToString(t.GetBytesLimit()), BytesToString(t.BytesConsumed())))
+}
+
+// SetFallback implements ActionOnExceed SetFallback interface, it's the last action so it has no fallback.
+func (a *PanicOnExceed) SetFallback(ActionOnExceed) {}
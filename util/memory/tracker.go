@@ -0,0 +1,180 @@
+package memory
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"sync/atomic"
+)
+
+// LabelForSQLText is the label of the tracker of a statement, the trackers of the executors are labeled by
+// the IDs of their plans.
+const LabelForSQLText = -1
+
+// Tracker tracks the bytes used by a statement or an executor. The trackers are arranged into a tree, the
+// bytes consumed by a tracker are consumed by its ancestors too. It's used for both the memory and the disk.
+//
+// A tracker may have a limit of the bytes, the action of the tracker is taken when its ancestors and it consume
+// more bytes than their limits.
+//
+// Only Consume, BytesConsumed, MaxConsumed, AttachTo and FallbackOldAndSetNewAction are thread-safe.
+type Tracker struct {
+	mu struct {
+		sync.Mutex
+		children []*Tracker
+	}
+	actionMu struct {
+		sync.Mutex
+		actionOnExceed ActionOnExceed
+	}
+
+	label         int
+	bytesConsumed int64
+	bytesLimit    int64
+	maxConsumed   int64
+	parent        *Tracker
+}
+
+// NewTracker creates a tracker of the label.
+func NewTracker(label int) *Tracker {
+	return &Tracker{label: label}
+}
+
+// SetBytesLimit sets the limit of the bytes, there is no limit if it's not positive.
+func (t *Tracker) SetBytesLimit(bytesLimit int64) {
+	t.bytesLimit = bytesLimit
+}
+
+// GetBytesLimit returns the limit of the bytes.
+func (t *Tracker) GetBytesLimit() int64 {
+	return t.bytesLimit
+}
+
+// SetActionOnExceed sets the action taken when the limit is exceeded.
+func (t *Tracker) SetActionOnExceed(a ActionOnExceed) {
+	t.actionMu.Lock()
+	t.actionMu.actionOnExceed = a
+	t.actionMu.Unlock()
+}
+
+// FallbackOldAndSetNewAction sets the action taken when the limit is exceeded, the old action is taken if the
+// new one can't release the bytes.
+func (t *Tracker) FallbackOldAndSetNewAction(a ActionOnExceed) {
+	t.actionMu.Lock()
+	a.SetFallback(t.actionMu.actionOnExceed)
+	t.actionMu.actionOnExceed = a
+	t.actionMu.Unlock()
+}
+
+// Label returns the label of the tracker.
+func (t *Tracker) Label() int {
+	return t.label
+}
+
+// AttachTo attaches the tracker to the parent, the bytes it has consumed are consumed by the parent.
+func (t *Tracker) AttachTo(parent *Tracker) {
+	if t.parent != nil {
+		t.parent.remove(t)
+	}
+	parent.mu.Lock()
+	parent.mu.children = append(parent.mu.children, t)
+	parent.mu.Unlock()
+	t.parent = parent
+	parent.Consume(t.BytesConsumed())
+}
+
+// Detach detaches the tracker from its parent, the bytes it has consumed are released by the parent.
+func (t *Tracker) Detach() {
+	if t.parent != nil {
+		t.parent.remove(t)
+	}
+}
+
+func (t *Tracker) remove(child *Tracker) {
+	t.mu.Lock()
+	for i, c := range t.mu.children {
+		if c == child {
+			t.mu.children = append(t.mu.children[:i], t.mu.children[i+1:]...)
+			break
+		}
+	}
+	t.mu.Unlock()
+	t.Consume(-child.BytesConsumed())
+	child.parent = nil
+}
+
+// Consume consumes the bytes, which are released if bytes is negative. The action of the root-most tracker
+// whose limit is exceeded is taken.
+func (t *Tracker) Consume(bytes int64) {
+	var rootExceed *Tracker
+	for tracker := t; tracker != nil; tracker = tracker.parent {
+		consumed := atomic.AddInt64(&tracker.bytesConsumed, bytes)
+		if bytes > 0 && tracker.bytesLimit > 0 && consumed > tracker.bytesLimit {
+			rootExceed = tracker
+		}
+		for {
+			maxNow := atomic.LoadInt64(&tracker.maxConsumed)
+			if consumed <= maxNow || atomic.CompareAndSwapInt64(&tracker.maxConsumed, maxNow, consumed) {
+				break
+			}
+		}
+	}
+	if rootExceed != nil {
+		rootExceed.actionMu.Lock()
+		action := rootExceed.actionMu.actionOnExceed
+		rootExceed.actionMu.Unlock()
+		if action != nil {
+			action.Action(rootExceed)
+		}
+	}
+}
+
+// BytesConsumed returns the bytes which are consumed now.
+func (t *Tracker) BytesConsumed() int64 {
+	return atomic.LoadInt64(&t.bytesConsumed)
+}
+
+// MaxConsumed returns the most bytes which are consumed at the same time.
+func (t *Tracker) MaxConsumed() int64 {
+	return atomic.LoadInt64(&t.maxConsumed)
+}
+
+// SearchTracker returns the tracker of the label in the tree of the tracker, it's nil if there is none.
+func (t *Tracker) SearchTracker(label int) *Tracker {
+	if t.label == label {
+		return t
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, child := range t.mu.children {
+		if found := child.SearchTracker(label); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// The units of the bytes.
+const (
+	byteSizeKB = int64(1 << 10)
+	byteSizeMB = int64(1 << 20)
+	byteSizeGB = int64(1 << 30)
+)
+
+// BytesToString formats the bytes by the largest unit which isn't larger than them, the number is rounded
+// to two decimal places.
+func BytesToString(numBytes int64) string {
+	switch {
+	case numBytes >= byteSizeGB:
+		return formatBytes(numBytes, byteSizeGB) + " GB"
+	case numBytes >= byteSizeMB:
+		return formatBytes(numBytes, byteSizeMB) + " MB"
+	case numBytes >= byteSizeKB:
+		return formatBytes(numBytes, byteSizeKB) + " KB"
+	}
+	return strconv.FormatInt(numBytes, 10) + " Bytes"
+}
+
+func formatBytes(numBytes, unit int64) string {
+	return strconv.FormatFloat(math.Round(float64(numBytes)/float64(unit)*100)/100, 'f', -1, 64)
+}
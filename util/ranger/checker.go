@@ -0,0 +1,91 @@
+package ranger
+
+import (
+	"github.com/pingcap/parser/ast"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/expression"
+	"grant-db/sessionctx"
+	"grant-db/types"
+)
+
+// conditionChecker checks if this condition can be pushed to index planner.
+type conditionChecker struct {
+	ctx    sessionctx.Context
+	col    *expression.Column
+	length int
+}
+
+func (c *conditionChecker) check(condition expression.Expression) bool {
+	if sf, ok := condition.(*expression.ScalarFunction); ok {
+		return c.checkScalarFunction(sf)
+	}
+	return false
+}
+
+func (c *conditionChecker) checkScalarFunction(sf *expression.ScalarFunction) bool {
+	args := sf.GetArgs()
+	switch sf.FuncName.L {
+	case ast.LogicOr, ast.LogicAnd:
+		return c.check(args[0]) && c.check(args[1])
+	case ast.EQ, ast.NE, ast.GE, ast.GT, ast.LE, ast.LT, ast.NullEQ:
+		if con, ok := args[0].(*expression.Constant); ok {
+			return c.checkColumn(args[1]) && c.checkConstant(con)
+		}
+		if con, ok := args[1].(*expression.Constant); ok {
+			return c.checkColumn(args[0]) && c.checkConstant(con)
+		}
+	case ast.IsNull:
+		return c.checkColumn(args[0])
+	case ast.UnaryNot:
+		// Only `not isnull(col)` is supported.
+		if inner, ok := args[0].(*expression.ScalarFunction); ok && inner.FuncName.L == ast.IsNull {
+			return c.checkColumn(inner.GetArgs()[0])
+		}
+	case ast.In:
+		if !c.checkColumn(args[0]) {
+			return false
+		}
+		for _, arg := range args[1:] {
+			con, ok := arg.(*expression.Constant)
+			if !ok || !c.checkConstant(con) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func (c *conditionChecker) checkColumn(expr expression.Expression) bool {
+	col, ok := expr.(*expression.Column)
+	return ok && col.UniqueID == c.col.UniqueID
+}
+
+// checkConstant checks whether the column is compared with the constant in the way the values are ordered in
+// the column, the constant is converted to the column type when the ranges are built.
+func (c *conditionChecker) checkConstant(con *expression.Constant) bool {
+	if con.Value.IsNull() {
+		return true
+	}
+	colTp := c.col.GetType()
+	switch colTp.Tp {
+	case mysql.TypeEnum, mysql.TypeSet, mysql.TypeBit, mysql.TypeJSON:
+		return false
+	}
+	conEt := con.GetType().EvalType()
+	switch colTp.EvalType() {
+	case types.ETInt, types.ETReal, types.ETDecimal:
+		return conEt == types.ETInt || conEt == types.ETReal || conEt == types.ETDecimal
+	case types.ETString:
+		if conEt != types.ETString {
+			return false
+		}
+		_, collation := expression.DeriveCollationFromExprs(c.ctx, c.col, con)
+		return collation == colTp.Collate
+	case types.ETDatetime, types.ETTimestamp:
+		return conEt == types.ETDatetime || conEt == types.ETTimestamp || conEt == types.ETString
+	case types.ETDuration:
+		return conEt == types.ETDuration || conEt == types.ETString
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package ranger
+
+import (
+	"grant-db/expression"
+	"grant-db/sessionctx"
+	"grant-db/types"
+)
+
+// DetachRangeResult wraps up results when detaching conditions and builing ranges.
+type DetachRangeResult struct {
+	// Ranges is the ranges extracted and built from conditions.
+	Ranges []*Range
+	// AccessConds is the extracted conditions for access.
+	AccessConds []expression.Expression
+	// RemainedConds is the filter conditions which should be kept after access.
+	RemainedConds []expression.Expression
+	// EqCondCount is the number of leading index columns which are equal to a single value, the rows are
+	// ordered by the following columns.
+	EqCondCount int
+	// EqOrInCount is the number of leading index columns which are equal to the constants, like `a = 1` or
+	// `a in (1, 2)`.
+	EqOrInCount int
+}
+
+// DetachCondAndBuildRangeForIndex detaches the index filters from table filters and uses them to build the
+// ranges of the index. The leading columns which are equal to the constants are used first, and then the
+// range conditions of the next column, like `a = 1 and b in (2, 3) and c > 4` over the index (a, b, c, d).
+// The lengths are the prefix lengths of the index columns, the conditions of the prefix columns are kept
+// as the filters since the ranges are cut.
+func DetachCondAndBuildRangeForIndex(sctx sessionctx.Context, conditions []expression.Expression, cols []*expression.Column,
+	lengths []int) (*DetachRangeResult, error) {
+	res := &DetachRangeResult{}
+	sc := sctx.GetSessionVars().StmtCtx
+	used := make([]bool, len(conditions))
+	ranges := []*Range{{}}
+	hasPrefix := false
+	for i, col := range cols {
+		checker := &conditionChecker{ctx: sctx, col: col, length: lengths[i]}
+		rb := newBuilder(sc, col)
+		// The condition of the single values are used first, the rows of each value are ordered by the
+		// following columns.
+		eqIdx := -1
+		var eqPoints []point
+		for j, cond := range conditions {
+			if used[j] || !checker.check(cond) {
+				continue
+			}
+			points := rb.build(cond)
+			if rb.err == nil && rb.isSinglePoints(points) {
+				eqIdx, eqPoints = j, points
+				break
+			}
+			rb.err = nil
+		}
+		if eqIdx >= 0 {
+			newRanges, err := rb.appendPoints2Ranges(ranges, eqPoints, col.RetType)
+			if err != nil {
+				// The values can't be converted to the column type, the conditions are used as filters.
+				break
+			}
+			used[eqIdx] = true
+			ranges = newRanges
+			hasPrefix = hasPrefix || lengths[i] != types.UnspecifiedLength
+			res.AccessConds = append(res.AccessConds, conditions[eqIdx])
+			if len(eqPoints) == 2 && res.EqCondCount == i {
+				res.EqCondCount++
+			}
+			res.EqOrInCount++
+			continue
+		}
+		var accessIdxs []int
+		points := fullRange
+		for j, cond := range conditions {
+			if used[j] || !checker.check(cond) {
+				continue
+			}
+			accessIdxs = append(accessIdxs, j)
+			points = rb.intersection(points, rb.build(cond))
+		}
+		if len(accessIdxs) > 0 && rb.err == nil {
+			if newRanges, err := rb.appendPoints2Ranges(ranges, points, col.RetType); err == nil {
+				for _, j := range accessIdxs {
+					used[j] = true
+					res.AccessConds = append(res.AccessConds, conditions[j])
+				}
+				ranges = newRanges
+				hasPrefix = hasPrefix || lengths[i] != types.UnspecifiedLength
+			}
+		}
+		break
+	}
+	for j, cond := range conditions {
+		if !used[j] {
+			res.RemainedConds = append(res.RemainedConds, cond)
+		}
+	}
+	if len(res.AccessConds) == 0 {
+		res.Ranges = FullRange()
+		return res, nil
+	}
+	if hasPrefix {
+		tps := make([]*types.FieldType, 0, len(cols))
+		for _, col := range cols {
+			tps = append(tps, col.RetType)
+		}
+		fixPrefixColRange(ranges, lengths, tps)
+		// The values are cut, so the conditions of the prefix columns are checked again.
+		for i, cond := range res.AccessConds {
+			if accessOnPrefixColumn(sctx, cond, cols, lengths) {
+				res.RemainedConds = append(res.RemainedConds, res.AccessConds[i])
+			}
+		}
+	}
+	res.Ranges = ranges
+	return res, nil
+}
+
+// accessOnPrefixColumn checks whether the condition is on a prefix column of the index.
+func accessOnPrefixColumn(sctx sessionctx.Context, cond expression.Expression, cols []*expression.Column, lengths []int) bool {
+	for i, col := range cols {
+		if lengths[i] == types.UnspecifiedLength {
+			continue
+		}
+		checker := &conditionChecker{ctx: sctx, col: col, length: lengths[i]}
+		if checker.check(cond) {
+			return true
+		}
+	}
+	return false
+}
+
+// DetachCondsForColumn detaches the conditions which can be used to build the ranges of the column from the
+// other conditions.
+func DetachCondsForColumn(sctx sessionctx.Context, conds []expression.Expression, col *expression.Column) (accessConditions, otherConditions []expression.Expression) {
+	checker := &conditionChecker{ctx: sctx, col: col, length: types.UnspecifiedLength}
+	for _, cond := range conds {
+		if checker.check(cond) {
+			accessConditions = append(accessConditions, cond)
+		} else {
+			otherConditions = append(otherConditions, cond)
+		}
+	}
+	return accessConditions, otherConditions
+}
@@ -0,0 +1,303 @@
+package ranger
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pingcap/parser/ast"
+	"grant-db/expression"
+	"grant-db/sessionctx/stmtctx"
+	"grant-db/types"
+	"grant-db/util/collate"
+)
+
+// Point is the end point of range interval.
+type point struct {
+	value types.Datum
+	excl  bool // exclude
+	start bool
+}
+
+func (rp point) String() string {
+	val := rp.value.GetValue()
+	if rp.value.Kind() == types.KindMinNotNull {
+		val = "-inf"
+	} else if rp.value.Kind() == types.KindMaxValue {
+		val = "+inf"
+	}
+	if rp.start {
+		symbol := "["
+		if rp.excl {
+			symbol = "("
+		}
+		return fmt.Sprintf("%s%v", symbol, val)
+	}
+	symbol := "]"
+	if rp.excl {
+		symbol = ")"
+	}
+	return fmt.Sprintf("%v%s", val, symbol)
+}
+
+type pointSorter struct {
+	points []point
+	err    error
+	r      *builder
+}
+
+func (r *pointSorter) Len() int {
+	return len(r.points)
+}
+
+func (r *pointSorter) Less(i, j int) bool {
+	a := r.points[i]
+	b := r.points[j]
+	less, err := r.r.rangePointLess(a, b)
+	if err != nil {
+		r.err = err
+	}
+	return less
+}
+
+func (r *pointSorter) Swap(i, j int) {
+	r.points[i], r.points[j] = r.points[j], r.points[i]
+}
+
+func (r *builder) rangePointLess(a, b point) (bool, error) {
+	cmp, err := r.compare(&a.value, &b.value)
+	if cmp != 0 {
+		return cmp < 0, nil
+	}
+	return rangePointEqualValueLess(a, b), err
+}
+
+func rangePointEqualValueLess(a, b point) bool {
+	if a.start && b.start {
+		return !a.excl && b.excl
+	} else if a.start {
+		return !a.excl && !b.excl
+	} else if b.start {
+		return a.excl || b.excl
+	}
+	return a.excl && !b.excl
+}
+
+// fullRange is (-∞, +∞).
+var fullRange = []point{
+	{start: true},
+	{value: types.MaxValueDatum()},
+}
+
+// builder is the range builder struct.
+type builder struct {
+	err error
+	sc  *stmtctx.StatementContext
+	// collator compares the strings of the column.
+	collator collate.Collator
+}
+
+func newBuilder(sc *stmtctx.StatementContext, col *expression.Column) *builder {
+	r := &builder{sc: sc}
+	if col.GetType().EvalType() == types.ETString {
+		r.collator = collate.GetCollator(col.GetType().Collate)
+	}
+	return r
+}
+
+// compare compares the values of the points, the strings are compared by the collation of the column.
+func (r *builder) compare(a, b *types.Datum) (int, error) {
+	if r.collator != nil && isStringKind(a.Kind()) && isStringKind(b.Kind()) {
+		return r.collator.Compare(a.GetString(), b.GetString()), nil
+	}
+	return a.CompareDatum(r.sc, b)
+}
+
+func isStringKind(k byte) bool {
+	return k == types.KindString || k == types.KindBytes
+}
+
+func (r *builder) build(expr expression.Expression) []point {
+	if sf, ok := expr.(*expression.ScalarFunction); ok {
+		return r.buildFromScalarFunc(sf)
+	}
+	return fullRange
+}
+
+func (r *builder) buildFromBinOp(expr *expression.ScalarFunction) []point {
+	// This has been checked that the binary operation is comparison operation, and one of
+	// the operand is column name expression.
+	var (
+		op    string
+		value types.Datum
+	)
+	if v, ok := expr.GetArgs()[1].(*expression.Constant); ok {
+		value, op = v.Value, expr.FuncName.L
+	} else {
+		value = expr.GetArgs()[0].(*expression.Constant).Value
+		switch expr.FuncName.L {
+		case ast.GE:
+			op = ast.LE
+		case ast.GT:
+			op = ast.LT
+		case ast.LT:
+			op = ast.GT
+		case ast.LE:
+			op = ast.GE
+		default:
+			op = expr.FuncName.L
+		}
+	}
+	if value.IsNull() {
+		if op == ast.NullEQ {
+			return []point{{start: true}, {}}
+		}
+		return nil
+	}
+	switch op {
+	case ast.EQ, ast.NullEQ:
+		startPoint := point{value: value, start: true}
+		endPoint := point{value: value}
+		return []point{startPoint, endPoint}
+	case ast.NE:
+		startPoint1 := point{value: types.MinNotNullDatum(), start: true}
+		endPoint1 := point{value: value, excl: true}
+		startPoint2 := point{value: value, start: true, excl: true}
+		endPoint2 := point{value: types.MaxValueDatum()}
+		return []point{startPoint1, endPoint1, startPoint2, endPoint2}
+	case ast.LT:
+		startPoint := point{value: types.MinNotNullDatum(), start: true}
+		endPoint := point{value: value, excl: true}
+		return []point{startPoint, endPoint}
+	case ast.LE:
+		startPoint := point{value: types.MinNotNullDatum(), start: true}
+		endPoint := point{value: value}
+		return []point{startPoint, endPoint}
+	case ast.GT:
+		startPoint := point{value: value, start: true, excl: true}
+		endPoint := point{value: types.MaxValueDatum()}
+		return []point{startPoint, endPoint}
+	case ast.GE:
+		startPoint := point{value: value, start: true}
+		endPoint := point{value: types.MaxValueDatum()}
+		return []point{startPoint, endPoint}
+	}
+	return nil
+}
+
+func (r *builder) buildFromIn(expr *expression.ScalarFunction) []point {
+	list := expr.GetArgs()[1:]
+	rangePoints := make([]point, 0, len(list)*2)
+	for _, e := range list {
+		v := e.(*expression.Constant).Value
+		if v.IsNull() {
+			// `x in (null)` is NULL, it's never true.
+			continue
+		}
+		rangePoints = append(rangePoints, point{value: v, start: true}, point{value: v})
+	}
+	sorter := pointSorter{points: rangePoints, r: r}
+	sort.Sort(&sorter)
+	if sorter.err != nil {
+		r.err = sorter.err
+	}
+	// check and remove duplicates
+	curPos, frontPos := 0, 0
+	for frontPos < len(rangePoints) {
+		if rangePoints[curPos].start == rangePoints[frontPos].start {
+			frontPos++
+		} else {
+			curPos++
+			rangePoints[curPos] = rangePoints[frontPos]
+			frontPos++
+		}
+	}
+	if curPos > 0 {
+		curPos++
+	}
+	return rangePoints[:curPos]
+}
+
+func (r *builder) buildFromScalarFunc(expr *expression.ScalarFunction) []point {
+	switch op := expr.FuncName.L; op {
+	case ast.GE, ast.GT, ast.LT, ast.LE, ast.EQ, ast.NE, ast.NullEQ:
+		return r.buildFromBinOp(expr)
+	case ast.LogicAnd:
+		return r.intersection(r.build(expr.GetArgs()[0]), r.build(expr.GetArgs()[1]))
+	case ast.LogicOr:
+		return r.union(r.build(expr.GetArgs()[0]), r.build(expr.GetArgs()[1]))
+	case ast.IsNull:
+		startPoint := point{start: true}
+		endPoint := point{}
+		return []point{startPoint, endPoint}
+	case ast.In:
+		return r.buildFromIn(expr)
+	case ast.UnaryNot:
+		// It's `not isnull(col)`, which is checked by the conditionChecker.
+		startPoint := point{value: types.MinNotNullDatum(), start: true}
+		endPoint := point{value: types.MaxValueDatum()}
+		return []point{startPoint, endPoint}
+	}
+	return nil
+}
+
+func (r *builder) intersection(a, b []point) []point {
+	return r.merge(a, b, false)
+}
+
+func (r *builder) union(a, b []point) []point {
+	return r.merge(a, b, true)
+}
+
+func (r *builder) merge(a, b []point, union bool) []point {
+	sorter := pointSorter{points: append(append([]point(nil), a...), b...), r: r}
+	sort.Sort(&sorter)
+	if sorter.err != nil {
+		r.err = sorter.err
+		return nil
+	}
+	var (
+		inRangeCount         int
+		requiredInRangeCount int
+	)
+	if union {
+		requiredInRangeCount = 1
+	} else {
+		requiredInRangeCount = 2
+	}
+	merged := make([]point, 0, len(sorter.points))
+	for _, val := range sorter.points {
+		if val.start {
+			inRangeCount++
+			if inRangeCount == requiredInRangeCount {
+				// just reached the required in range count, a new range started.
+				merged = append(merged, val)
+			}
+		} else {
+			if inRangeCount == requiredInRangeCount {
+				// just about to leave the required in range count, the range is ended.
+				merged = append(merged, val)
+			}
+			inRangeCount--
+		}
+	}
+	return merged
+}
+
+// isSinglePoints checks whether every interval of the points is a single value, like the points of `a = 1`
+// or `a in (1, 2)`.
+func (r *builder) isSinglePoints(points []point) bool {
+	for i := 0; i < len(points); i += 2 {
+		if points[i].excl || points[i+1].excl {
+			return false
+		}
+		switch points[i].value.Kind() {
+		case types.KindMinNotNull, types.KindMaxValue:
+			return false
+		}
+		cmp, err := r.compare(&points[i].value, &points[i+1].value)
+		if err != nil || cmp != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,288 @@
+package ranger
+
+import (
+	"math"
+	"unicode/utf8"
+
+	"github.com/pingcap/parser/charset"
+	"github.com/pingcap/parser/mysql"
+	"grant-db/expression"
+	"grant-db/sessionctx/stmtctx"
+	"grant-db/types"
+)
+
+// validInterval checks whether the interval of the start point and the end point contains any value.
+func (r *builder) validInterval(low, high point) (bool, error) {
+	if low.value.Kind() == types.KindNull && high.value.Kind() == types.KindNull {
+		return !low.excl && !high.excl, nil
+	}
+	cmp, err := r.compare(&low.value, &high.value)
+	if err != nil {
+		return false, err
+	}
+	if cmp == 0 {
+		return !low.excl && !high.excl, nil
+	}
+	return cmp < 0, nil
+}
+
+// points2Ranges build index ranges from range points.
+// Only one column is built there. If there're multiple columns, use appendPoints2Ranges.
+func (r *builder) points2Ranges(rangePoints []point, tp *types.FieldType) ([]*Range, error) {
+	return r.appendPoints2IndexRange(&Range{}, rangePoints, tp)
+}
+
+// points2TableRanges builds the ranges of the integer handle column, which is never NULL.
+func (r *builder) points2TableRanges(rangePoints []point, tp *types.FieldType) ([]*Range, error) {
+	ranges := make([]*Range, 0, len(rangePoints)/2)
+	var minValueDatum, maxValueDatum types.Datum
+	// Currently, table's kv range cannot accept encoded value of MaxValueDatum. we need to convert it.
+	if mysql.HasUnsignedFlag(tp.Flag) {
+		minValueDatum.SetUint64(0)
+		maxValueDatum.SetUint64(math.MaxUint64)
+	} else {
+		minValueDatum.SetInt64(math.MinInt64)
+		maxValueDatum.SetInt64(math.MaxInt64)
+	}
+	for i := 0; i < len(rangePoints); i += 2 {
+		startPoint, err := convertPoint(rangePoints[i], tp)
+		if err != nil {
+			return nil, err
+		}
+		endPoint, err := convertPoint(rangePoints[i+1], tp)
+		if err != nil {
+			return nil, err
+		}
+		if endPoint.value.Kind() == types.KindNull {
+			continue
+		}
+		if startPoint.value.Kind() == types.KindNull || startPoint.value.Kind() == types.KindMinNotNull {
+			startPoint.value, startPoint.excl = minValueDatum, false
+		}
+		if endPoint.value.Kind() == types.KindMaxValue {
+			endPoint.value, endPoint.excl = maxValueDatum, false
+		}
+		less, err := r.validInterval(startPoint, endPoint)
+		if err != nil {
+			return nil, err
+		}
+		if !less {
+			continue
+		}
+		ranges = append(ranges, &Range{
+			LowVal:      []types.Datum{startPoint.value},
+			LowExclude:  startPoint.excl,
+			HighVal:     []types.Datum{endPoint.value},
+			HighExclude: endPoint.excl,
+		})
+	}
+	return ranges, nil
+}
+
+// convertPoint converts the value of the point to the column type, the exclusiveness is adjusted if the value
+// is changed, like `a > 1.9` is `a >= 2` for an integer column. The strings are compared by the collation of
+// the column, they are kept as they are.
+func convertPoint(point point, tp *types.FieldType) (point, error) {
+	switch point.value.Kind() {
+	case types.KindMaxValue, types.KindMinNotNull, types.KindNull:
+		return point, nil
+	}
+	if tp.EvalType() == types.ETString {
+		return point, nil
+	}
+	// The conversion errors are returned instead of being turned into warnings, the condition is used as a
+	// filter then.
+	sc := &stmtctx.StatementContext{}
+	casted, err := point.value.ConvertTo(sc, tp)
+	if err != nil {
+		return point, err
+	}
+	valCmpCasted, err := point.value.CompareDatum(sc, &casted)
+	if err != nil {
+		return point, err
+	}
+	point.value = casted
+	if valCmpCasted == 0 {
+		return point, nil
+	}
+	if point.start {
+		if point.excl {
+			if valCmpCasted < 0 {
+				// e.g. "a > 1.9" convert to "a >= 2".
+				point.excl = false
+			}
+		} else {
+			if valCmpCasted > 0 {
+				// e.g. "a >= 1.1 convert to "a > 1"
+				point.excl = true
+			}
+		}
+	} else {
+		if point.excl {
+			if valCmpCasted > 0 {
+				// e.g. "a < 1.1" convert to "a <= 1"
+				point.excl = false
+			}
+		} else {
+			if valCmpCasted < 0 {
+				// e.g. "a <= 1.9" convert to "a < 2"
+				point.excl = true
+			}
+		}
+	}
+	return point, nil
+}
+
+// appendPoints2Ranges appends additional column ranges for multi-column index.
+// The additional column ranges can only be appended to point ranges.
+// for example we have an index (a, b), if the condition is (a > 1 and b = 2)
+// then we can not build a conjunctive ranges for this index.
+func (r *builder) appendPoints2Ranges(origin []*Range, rangePoints []point, ft *types.FieldType) ([]*Range, error) {
+	var newIndexRanges []*Range
+	for i := 0; i < len(origin); i++ {
+		newRanges, err := r.appendPoints2IndexRange(origin[i], rangePoints, ft)
+		if err != nil {
+			return nil, err
+		}
+		newIndexRanges = append(newIndexRanges, newRanges...)
+	}
+	return newIndexRanges, nil
+}
+
+func (r *builder) appendPoints2IndexRange(origin *Range, rangePoints []point, ft *types.FieldType) ([]*Range, error) {
+	newRanges := make([]*Range, 0, len(rangePoints)/2)
+	for i := 0; i < len(rangePoints); i += 2 {
+		startPoint, err := convertPoint(rangePoints[i], ft)
+		if err != nil {
+			return nil, err
+		}
+		endPoint, err := convertPoint(rangePoints[i+1], ft)
+		if err != nil {
+			return nil, err
+		}
+		less, err := r.validInterval(startPoint, endPoint)
+		if err != nil {
+			return nil, err
+		}
+		if !less {
+			continue
+		}
+		// The column which isn't NULL has no [NULL, NULL] interval.
+		if mysql.HasNotNullFlag(ft.Flag) && endPoint.value.Kind() == types.KindNull {
+			continue
+		}
+
+		lowVal := make([]types.Datum, len(origin.LowVal)+1)
+		copy(lowVal, origin.LowVal)
+		lowVal[len(origin.LowVal)] = startPoint.value
+
+		highVal := make([]types.Datum, len(origin.HighVal)+1)
+		copy(highVal, origin.HighVal)
+		highVal[len(origin.HighVal)] = endPoint.value
+
+		ir := &Range{
+			LowVal:      lowVal,
+			LowExclude:  startPoint.excl,
+			HighVal:     highVal,
+			HighExclude: endPoint.excl,
+		}
+		newRanges = append(newRanges, ir)
+	}
+	return newRanges, nil
+}
+
+// BuildTableRange builds range of PK column for PhysicalTableScan.
+func BuildTableRange(accessConditions []expression.Expression, sc *stmtctx.StatementContext, tp *types.FieldType) ([]*Range, error) {
+	rb := &builder{sc: sc}
+	rangePoints := fullRange
+	for _, cond := range accessConditions {
+		rangePoints = rb.intersection(rangePoints, rb.build(cond))
+		if rb.err != nil {
+			return nil, rb.err
+		}
+	}
+	return rb.points2TableRanges(rangePoints, tp)
+}
+
+// BuildColumnRange builds range from CNF conditions. The colLen is the prefix length of the column in an
+// index, which is types.UnspecifiedLength for the whole column.
+func BuildColumnRange(conds []expression.Expression, sc *stmtctx.StatementContext, col *expression.Column, colLen int) ([]*Range, error) {
+	if len(conds) == 0 {
+		return FullRange(), nil
+	}
+	rb := newBuilder(sc, col)
+	rangePoints := fullRange
+	for _, cond := range conds {
+		rangePoints = rb.intersection(rangePoints, rb.build(cond))
+		if rb.err != nil {
+			return nil, rb.err
+		}
+	}
+	ranges, err := rb.points2Ranges(rangePoints, col.RetType)
+	if err != nil {
+		return nil, err
+	}
+	if colLen != types.UnspecifiedLength {
+		for _, ran := range ranges {
+			if fixRangeDatum(&ran.LowVal[0], colLen, col.RetType) {
+				ran.LowExclude = false
+			}
+			if fixRangeDatum(&ran.HighVal[0], colLen, col.RetType) {
+				ran.HighExclude = false
+			}
+		}
+	}
+	return ranges, nil
+}
+
+// fixPrefixColRange cuts the values of the prefix columns of an index, the bounds which are cut are inclusive.
+func fixPrefixColRange(ranges []*Range, lengths []int, tp []*types.FieldType) bool {
+	hasCut := false
+	for _, ran := range ranges {
+		lowTail := len(ran.LowVal) - 1
+		for i := 0; i < lowTail; i++ {
+			hasCut = fixRangeDatum(&ran.LowVal[i], lengths[i], tp[i]) || hasCut
+		}
+		lowCut := fixRangeDatum(&ran.LowVal[lowTail], lengths[lowTail], tp[lowTail])
+		if lowCut {
+			ran.LowExclude = false
+		}
+		highTail := len(ran.HighVal) - 1
+		for i := 0; i < highTail; i++ {
+			hasCut = fixRangeDatum(&ran.HighVal[i], lengths[i], tp[i]) || hasCut
+		}
+		highCut := fixRangeDatum(&ran.HighVal[highTail], lengths[highTail], tp[highTail])
+		if highCut {
+			ran.HighExclude = false
+		}
+		hasCut = hasCut || lowCut || highCut
+	}
+	return hasCut
+}
+
+// CutDatumByPrefixLen cuts the string to the prefix length of the index column, it's used for the values
+// which aren't constants, like the join keys of an index join.
+func CutDatumByPrefixLen(v *types.Datum, length int, tp *types.FieldType) {
+	fixRangeDatum(v, length, tp)
+}
+
+// fixRangeDatum cuts the string to the prefix length of the index column, the binary strings are cut by
+// bytes and the others are cut by characters.
+func fixRangeDatum(v *types.Datum, length int, tp *types.FieldType) bool {
+	if length == types.UnspecifiedLength || !isStringKind(v.Kind()) {
+		return false
+	}
+	if tp.Charset == charset.CharsetBin {
+		if b := v.GetBytes(); len(b) > length {
+			v.SetBytes(b[:length])
+			return true
+		}
+		return false
+	}
+	if s := v.GetString(); utf8.RuneCountInString(s) > length {
+		rs := []rune(s)
+		v.SetString(string(rs[:length]))
+		return true
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package ranger
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"grant-db/sessionctx/stmtctx"
+	"grant-db/types"
+)
+
+// Range represents a range of the column values or the index keys, the values of the index columns are
+// compared one by one. The values of the leading columns of a range over an index are equal, only the
+// last column has an interval.
+type Range struct {
+	LowVal  []types.Datum
+	HighVal []types.Datum
+
+	LowExclude  bool // Low value is exclusive.
+	HighExclude bool // High value is exclusive.
+}
+
+// Clone clones a Range.
+func (ran *Range) Clone() *Range {
+	newRange := &Range{
+		LowVal:      make([]types.Datum, 0, len(ran.LowVal)),
+		HighVal:     make([]types.Datum, 0, len(ran.HighVal)),
+		LowExclude:  ran.LowExclude,
+		HighExclude: ran.HighExclude,
+	}
+	for i, length := 0, len(ran.LowVal); i < length; i++ {
+		newRange.LowVal = append(newRange.LowVal, ran.LowVal[i])
+	}
+	for i, length := 0, len(ran.HighVal); i < length; i++ {
+		newRange.HighVal = append(newRange.HighVal, ran.HighVal[i])
+	}
+	return newRange
+}
+
+// IsPoint returns if the range is a point.
+func (ran *Range) IsPoint(sc *stmtctx.StatementContext) bool {
+	if len(ran.LowVal) != len(ran.HighVal) {
+		return false
+	}
+	for i := range ran.LowVal {
+		a := ran.LowVal[i]
+		b := ran.HighVal[i]
+		if a.Kind() == types.KindMinNotNull || b.Kind() == types.KindMaxValue {
+			return false
+		}
+		cmp, err := a.CompareDatum(sc, &b)
+		if err != nil {
+			return false
+		}
+		if cmp != 0 {
+			return false
+		}
+		if a.IsNull() {
+			return false
+		}
+	}
+	return !ran.LowExclude && !ran.HighExclude
+}
+
+// PrefixEqualLen tells you how long the prefix of the range is a point.
+// e.g. If this range is (1 2 3, 1 2 +inf), then the return value is 2.
+func (ran *Range) PrefixEqualLen(sc *stmtctx.StatementContext) (int, error) {
+	// Here, len(ran.LowVal) always equal to len(ran.HighVal)
+	for i := 0; i < len(ran.LowVal); i++ {
+		cmp, err := ran.LowVal[i].CompareDatum(sc, &ran.HighVal[i])
+		if err != nil {
+			return 0, err
+		}
+		if cmp != 0 {
+			return i, nil
+		}
+	}
+	return len(ran.LowVal), nil
+}
+
+func (ran *Range) String() string {
+	lowStrs := make([]string, 0, len(ran.LowVal))
+	for _, d := range ran.LowVal {
+		lowStrs = append(lowStrs, formatDatum(d, true))
+	}
+	highStrs := make([]string, 0, len(ran.LowVal))
+	for _, d := range ran.HighVal {
+		highStrs = append(highStrs, formatDatum(d, false))
+	}
+	l, r := "[", "]"
+	if ran.LowExclude {
+		l = "("
+	}
+	if ran.HighExclude {
+		r = ")"
+	}
+	return l + strings.Join(lowStrs, " ") + "," + strings.Join(highStrs, " ") + r
+}
+
+// formatDatum formats a datum of the range, the bounds are shown as infinities.
+func formatDatum(d types.Datum, isLeftSide bool) string {
+	switch d.Kind() {
+	case types.KindNull:
+		return "NULL"
+	case types.KindMinNotNull:
+		return "-inf"
+	case types.KindMaxValue:
+		return "+inf"
+	case types.KindInt64:
+		switch d.GetInt64() {
+		case math.MinInt64:
+			if isLeftSide {
+				return "-inf"
+			}
+		case math.MaxInt64:
+			if !isLeftSide {
+				return "+inf"
+			}
+		}
+	case types.KindUint64:
+		if d.GetUint64() == math.MaxUint64 && !isLeftSide {
+			return "+inf"
+		}
+	case types.KindString, types.KindBytes:
+		return fmt.Sprintf("%q", d.GetString())
+	}
+	return fmt.Sprintf("%v", d.GetValue())
+}
+
+// FullIntRange is used for table range. Since table range cannot accept MaxValueDatum as the max value.
+// So we need to set it to MaxInt64.
+func FullIntRange(isUnsigned bool) []*Range {
+	if isUnsigned {
+		return []*Range{{LowVal: []types.Datum{types.NewUintDatum(0)}, HighVal: []types.Datum{types.NewUintDatum(math.MaxUint64)}}}
+	}
+	return []*Range{{LowVal: []types.Datum{types.NewIntDatum(math.MinInt64)}, HighVal: []types.Datum{types.NewIntDatum(math.MaxInt64)}}}
+}
+
+// FullRange is [null, +∞) for Range.
+func FullRange() []*Range {
+	return []*Range{{LowVal: []types.Datum{{}}, HighVal: []types.Datum{types.MaxValueDatum()}}}
+}
+
+// FullNotNullRange is (-∞, +∞) for Range.
+func FullNotNullRange() []*Range {
+	return []*Range{{LowVal: []types.Datum{types.MinNotNullDatum()}, HighVal: []types.Datum{types.MaxValueDatum()}}}
+}
+
+// NullRange is [null, null] for Range.
+func NullRange() []*Range {
+	return []*Range{{LowVal: []types.Datum{{}}, HighVal: []types.Datum{{}}}}
+}
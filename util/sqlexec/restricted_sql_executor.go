@@ -0,0 +1,24 @@
+package sqlexec
+
+import (
+	"context"
+
+	"github.com/pingcap/parser/ast"
+	"grant-db/util/chunk"
+)
+
+// RecordSet is an abstract result set interface to help get data from Plan.
+type RecordSet interface {
+	// Fields gets result fields.
+	Fields() []*ast.ResultField
+
+	// Next reads records into chunk.
+	Next(ctx context.Context, req *chunk.Chunk) error
+
+	// NewChunk create a chunk.
+	NewChunk() *chunk.Chunk
+
+	// Close closes the underlying iterator, call Next after Close will
+	// restart the iteration.
+	Close() error
+}
@@ -0,0 +1,177 @@
+package stringutil
+
+import (
+	"unicode/utf8"
+)
+
+const (
+	// PatMatch is the enumeration value for per-character match.
+	PatMatch = iota + 1
+	// PatOne is the enumeration value for '_' match.
+	PatOne
+	// PatAny is the enumeration value for '%' match.
+	PatAny
+)
+
+// CompilePattern handles escapes and wild cards convert pattern characters and
+// pattern types.
+func CompilePattern(pattern string, escape byte) (patChars, patTypes []byte) {
+	patChars = make([]byte, len(pattern))
+	patTypes = make([]byte, len(pattern))
+	patLen := 0
+	for i := 0; i < len(pattern); i++ {
+		var tp byte
+		var c = pattern[i]
+		switch c {
+		case escape:
+			tp = PatMatch
+			if i < len(pattern)-1 {
+				i++
+				c = pattern[i]
+			}
+		case '_':
+			// %_ => _%
+			if patLen > 0 && patTypes[patLen-1] == PatAny {
+				tp = PatAny
+				c = '%'
+				patChars[patLen-1], patTypes[patLen-1] = '_', PatOne
+			} else {
+				tp = PatOne
+			}
+		case '%':
+			// %% => %
+			if patLen > 0 && patTypes[patLen-1] == PatAny {
+				continue
+			}
+			tp = PatAny
+		default:
+			tp = PatMatch
+		}
+		patChars[patLen] = c
+		patTypes[patLen] = tp
+		patLen++
+	}
+	patChars = patChars[:patLen]
+	patTypes = patTypes[:patLen]
+	return
+}
+
+// DoMatch matches the string with patChars and patTypes, '_' matches a whole utf8 character.
+// The algorithm has linear time complexity.
+// https://research.swtch.com/glob
+func DoMatch(str string, patChars, patTypes []byte) bool {
+	var sIdx, pIdx, nextSIdx, nextPIdx int
+	for pIdx < len(patChars) || sIdx < len(str) {
+		if pIdx < len(patChars) {
+			switch patTypes[pIdx] {
+			case PatMatch:
+				if sIdx < len(str) && str[sIdx] == patChars[pIdx] {
+					pIdx++
+					sIdx++
+					continue
+				}
+			case PatOne:
+				if sIdx < len(str) {
+					_, size := utf8.DecodeRuneInString(str[sIdx:])
+					pIdx++
+					sIdx += size
+					continue
+				}
+			case PatAny:
+				// Try to match at sIdx.
+				// If that doesn't work out,
+				// restart at sIdx+1 next.
+				nextPIdx = pIdx
+				nextSIdx = sIdx + 1
+				pIdx++
+				continue
+			}
+		}
+		// Mismatch. Maybe restart.
+		if 0 < nextSIdx && nextSIdx <= len(str) {
+			pIdx = nextPIdx
+			sIdx = nextSIdx
+			continue
+		}
+		return false
+	}
+	// Matched all of pattern to all of name. Success.
+	return true
+}
+
+// CompilePatternRunes is like CompilePattern, but the pattern is compiled to characters rather than bytes,
+// which is used to match the strings character by character.
+func CompilePatternRunes(pattern string, escape byte) (patChars []rune, patTypes []byte) {
+	runes := []rune(pattern)
+	patChars = make([]rune, 0, len(runes))
+	patTypes = make([]byte, 0, len(runes))
+	for i := 0; i < len(runes); i++ {
+		var tp byte
+		c := runes[i]
+		switch c {
+		case rune(escape):
+			tp = PatMatch
+			if i < len(runes)-1 {
+				i++
+				c = runes[i]
+			}
+		case '_':
+			// %_ => _%
+			if n := len(patTypes); n > 0 && patTypes[n-1] == PatAny {
+				tp = PatAny
+				c = '%'
+				patChars[n-1], patTypes[n-1] = '_', PatOne
+			} else {
+				tp = PatOne
+			}
+		case '%':
+			// %% => %
+			if n := len(patTypes); n > 0 && patTypes[n-1] == PatAny {
+				continue
+			}
+			tp = PatAny
+		default:
+			tp = PatMatch
+		}
+		patChars = append(patChars, c)
+		patTypes = append(patTypes, tp)
+	}
+	return
+}
+
+// DoMatchRunes is like DoMatch, but the string is matched character by character with the pattern
+// compiled by CompilePatternRunes, equal reports whether a character of the string matches a pattern character.
+func DoMatchRunes(str string, patChars []rune, patTypes []byte, equal func(a, b rune) bool) bool {
+	runes := []rune(str)
+	var sIdx, pIdx, nextSIdx, nextPIdx int
+	for pIdx < len(patChars) || sIdx < len(runes) {
+		if pIdx < len(patChars) {
+			switch patTypes[pIdx] {
+			case PatMatch:
+				if sIdx < len(runes) && equal(runes[sIdx], patChars[pIdx]) {
+					pIdx++
+					sIdx++
+					continue
+				}
+			case PatOne:
+				if sIdx < len(runes) {
+					pIdx++
+					sIdx++
+					continue
+				}
+			case PatAny:
+				nextPIdx = pIdx
+				nextSIdx = sIdx + 1
+				pIdx++
+				continue
+			}
+		}
+		if 0 < nextSIdx && nextSIdx <= len(runes) {
+			pIdx = nextPIdx
+			sIdx = nextSIdx
+			continue
+		}
+		return false
+	}
+	return true
+}
@@ -0,0 +1,200 @@
+// Package testkit runs SQL statements on sessions in tests.
+package testkit
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/pingcap/errors"
+	"github.com/pingcap/parser/mysql"
+	"github.com/pingcap/parser/terror"
+	"grant-db/domain"
+	"grant-db/kv"
+	"grant-db/session"
+	"grant-db/types"
+	"grant-db/util/sqlexec"
+)
+
+// NewMockStore creates an in-memory store and bootstraps its domain, both are closed when the test finishes.
+func NewMockStore(t *testing.T) (kv.Storage, *domain.Domain) {
+	t.Helper()
+	store, err := kv.NewStorage("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dom, err := session.BootstrapSession(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		dom.Close()
+		_ = store.Close()
+	})
+	return store, dom
+}
+
+// TestKit executes the statements on a session, the statements are formatted with args like fmt.Sprintf.
+type TestKit struct {
+	t     *testing.T
+	store kv.Storage
+	Se    session.Session
+}
+
+// NewTestKit creates a TestKit on a new session of store.
+func NewTestKit(t *testing.T, store kv.Storage) *TestKit {
+	t.Helper()
+	se, err := session.NewSession(store)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(se.Close)
+	return &TestKit{t: t, store: store, Se: se}
+}
+
+// Exec executes the statements of sql and returns the record set of the last one, the record sets of the
+// others are drained and closed.
+func (tk *TestKit) Exec(sql string, args ...interface{}) (sqlexec.RecordSet, error) {
+	if len(args) > 0 {
+		sql = fmt.Sprintf(sql, args...)
+	}
+	ctx := context.Background()
+	stmts, err := tk.Se.Parse(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+	var rs sqlexec.RecordSet
+	for i, stmt := range stmts {
+		if rs, err = tk.Se.ExecuteStmt(ctx, stmt); err != nil {
+			return nil, err
+		}
+		if rs != nil && i < len(stmts)-1 {
+			if _, err = drain(rs); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return rs, nil
+}
+
+// ExecToErr executes sql and returns its error.
+func (tk *TestKit) ExecToErr(sql string, args ...interface{}) error {
+	rs, err := tk.Exec(sql, args...)
+	if err == nil && rs != nil {
+		_, err = drain(rs)
+	}
+	return err
+}
+
+// MustExec executes sql and fails the test on an error.
+func (tk *TestKit) MustExec(sql string, args ...interface{}) {
+	tk.t.Helper()
+	if err := tk.ExecToErr(sql, args...); err != nil {
+		tk.t.Fatalf("%s: %v", sql, err)
+	}
+}
+
+// MustGetErrCode executes sql and checks it fails with the MySQL error code.
+func (tk *TestKit) MustGetErrCode(sql string, code int) {
+	tk.t.Helper()
+	err := tk.ExecToErr(sql)
+	if err == nil {
+		tk.t.Fatalf("%s: expected error %d, got nothing", sql, code)
+	}
+	if got := ErrCode(err); got != code {
+		tk.t.Fatalf("%s: expected error %d, got %d: %v", sql, code, got, err)
+	}
+}
+
+// MustQuery executes sql and returns its rows.
+func (tk *TestKit) MustQuery(sql string, args ...interface{}) *Result {
+	tk.t.Helper()
+	rs, err := tk.Exec(sql, args...)
+	if err == nil && rs == nil {
+		err = errors.New("the statement returns no result set")
+	}
+	var rows []string
+	if err == nil {
+		rows, err = drain(rs)
+	}
+	if err != nil {
+		tk.t.Fatalf("%s: %v", sql, err)
+	}
+	return &Result{t: tk.t, sql: sql, rows: rows}
+}
+
+// ErrCode returns the MySQL error code of err, it's ErrUnknown if err isn't a SQL error.
+func ErrCode(err error) int {
+	switch x := errors.Cause(err).(type) {
+	case *terror.Error:
+		return int(x.ToSQLError().Code)
+	case *mysql.SQLError:
+		return int(x.Code)
+	}
+	return mysql.ErrUnknown
+}
+
+// drain reads all the rows of rs and closes it, the columns of a row are joined by spaces and NULL is "<nil>".
+func drain(rs sqlexec.RecordSet) ([]string, error) {
+	fields := rs.Fields()
+	fieldTypes := make([]*types.FieldType, 0, len(fields))
+	for _, f := range fields {
+		fieldTypes = append(fieldTypes, &f.Column.FieldType)
+	}
+	var rows []string
+	req := rs.NewChunk()
+	for {
+		err := rs.Next(context.Background(), req)
+		if err != nil || req.NumRows() == 0 {
+			if closeErr := rs.Close(); err == nil {
+				err = closeErr
+			}
+			return rows, err
+		}
+		for i := 0; i < req.NumRows(); i++ {
+			row := req.GetRow(i).GetDatumRow(fieldTypes)
+			cols := make([]string, 0, len(row))
+			for _, d := range row {
+				if d.IsNull() {
+					cols = append(cols, "<nil>")
+					continue
+				}
+				s, err := d.ToString()
+				if err != nil {
+					_ = rs.Close()
+					return nil, err
+				}
+				cols = append(cols, s)
+			}
+			rows = append(rows, strings.Join(cols, " "))
+		}
+	}
+}
+
+// Result is the rows of a query.
+type Result struct {
+	t    *testing.T
+	sql  string
+	rows []string
+}
+
+// Rows returns the rows, the columns of a row are joined by spaces and NULL is "<nil>".
+func (res *Result) Rows() []string {
+	return res.rows
+}
+
+// Check checks the rows are the expected ones in order.
+func (res *Result) Check(expected ...string) {
+	res.t.Helper()
+	if fmt.Sprintf("%q", res.rows) != fmt.Sprintf("%q", expected) {
+		res.t.Fatalf("%s: the rows are\n%s\nexpected\n%s", res.sql, strings.Join(res.rows, "\n"), strings.Join(expected, "\n"))
+	}
+}
+
+// Sort sorts the rows, it's used to check the rows whose order is undefined.
+func (res *Result) Sort() *Result {
+	sort.Strings(res.rows)
+	return res
+}
@@ -0,0 +1,52 @@
+package texttree
+
+// The runes which draw the branches of an operator tree.
+const (
+	// TreeBody is the branch of a sub-tree which has more children to be attached.
+	TreeBody = '│'
+	// TreeMiddleNode attaches a child which isn't the last one of its parent.
+	TreeMiddleNode = '├'
+	// TreeLastNode attaches the last child of its parent.
+	TreeLastNode = '└'
+	// TreeGap is the gap between the branches.
+	TreeGap = ' '
+	// TreeNodeIdentifier connects a branch and the node attached to it.
+	TreeNodeIdentifier = '─'
+)
+
+// Indent4Child returns the indent of the children of a node whose indent is indent.
+func Indent4Child(indent string, isLastChild bool) string {
+	if !isLastChild {
+		return string(append([]rune(indent), TreeBody, TreeGap))
+	}
+	// The sub-tree of the last child ends, so the closest branch is replaced by a gap.
+	runes := []rune(indent)
+	for i := len(runes) - 1; i >= 0; i-- {
+		if runes[i] == TreeBody {
+			runes[i] = TreeGap
+			break
+		}
+	}
+	return string(append(runes, TreeBody, TreeGap))
+}
+
+// PrettyIdentifier returns the id prefixed by the indent, with the branch which attaches the node.
+func PrettyIdentifier(id, indent string, isLastChild bool) string {
+	if len(indent) == 0 {
+		return id
+	}
+	runes := []rune(indent)
+	for i := len(runes) - 1; i >= 0; i-- {
+		if runes[i] != TreeBody {
+			continue
+		}
+		if isLastChild {
+			runes[i] = TreeLastNode
+		} else {
+			runes[i] = TreeMiddleNode
+		}
+		break
+	}
+	runes[len(runes)-1] = TreeNodeIdentifier
+	return string(runes) + id
+}